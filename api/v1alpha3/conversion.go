@@ -368,3 +368,13 @@ func Convert_v1alpha6_Router_To_v1alpha3_Router(in *infrav1.Router, out *Router,
 func Convert_v1alpha6_LoadBalancer_To_v1alpha3_LoadBalancer(in *infrav1.LoadBalancer, out *LoadBalancer, s conversion.Scope) error {
 	return autoConvert_v1alpha6_LoadBalancer_To_v1alpha3_LoadBalancer(in, out, s)
 }
+
+func Convert_v1alpha6_Bastion_To_v1alpha3_Bastion(in *infrav1.Bastion, out *Bastion, s conversion.Scope) error {
+	// User data has no equivalent in v1alpha3
+	return autoConvert_v1alpha6_Bastion_To_v1alpha3_Bastion(in, out, s)
+}
+
+func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in *infrav1.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
+	// The server create options and the instance actions audit have no equivalent in v1alpha3
+	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in, out, s)
+}
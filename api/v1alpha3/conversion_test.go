@@ -234,11 +234,81 @@ func TestFuzzyConversion(t *testing.T) {
 				if v1alpha6Cluster.Spec.Bastion != nil {
 					v1alpha6Cluster.Spec.Bastion.Instance.ImageUUID = ""
 					v1alpha6Cluster.Spec.Bastion.Instance.Ports = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ServerGroup = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageChecksum = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.BootstrapFormat = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.Ignition = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SSHPublicKeySecretRef = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ServerPassword = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ManagedSubnet = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.AddressSortOrder = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SchedulerHintAdditionalProperties = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.FlavorID = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageFilter = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Traits = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageRef = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ConfigureSecondaryInterfaces = false
+					v1alpha6Cluster.Spec.Bastion.Instance.Region = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.AdditionalBlockDevices = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ControlPlaneStorage = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Remediation = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.AdoptExisting = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SnapshotBeforeDelete = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Reservation = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.DeleteRemovedBlockDevices = false
+					v1alpha6Cluster.Spec.Bastion.Instance.Hostname = ""
+					v1alpha6Cluster.Spec.Bastion.UserData = ""
+					v1alpha6Cluster.Spec.Bastion.DNS = nil
 				}
 				v1alpha6Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha6Cluster.Spec.ResourceNaming = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.Provider = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.FlavorID = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.FlavorName = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.HealthMonitor = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.ConnectionLimit = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutClientData = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutMemberData = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutMemberConnect = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.AvailabilityZone = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.Shared = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TLS = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor = nil
+				v1alpha6Cluster.Spec.FloatingIPPoolRef = nil
+				v1alpha6Cluster.Spec.AdditionalFloatingIPs = nil
+				v1alpha6Cluster.Spec.ControlPlaneEndpointDNS = nil
+				v1alpha6Cluster.Spec.BlueprintExport = nil
+				v1alpha6Cluster.Spec.GarbageCollection = nil
+				v1alpha6Cluster.Spec.ExternallyManagedNetwork = false
+				v1alpha6Cluster.Spec.ManagedAPIServerVIP = false
+				v1alpha6Cluster.Spec.ServerMetadata = nil
+				v1alpha6Cluster.Spec.ManagedSecurityGroupRulesPolicy = ""
+				v1alpha6Cluster.Spec.ManagedSecurityGroupRules = nil
+				v1alpha6Cluster.Spec.NodeIPv6Subnet = nil
+				v1alpha6Cluster.Spec.NetworkMTU = 0
+				v1alpha6Cluster.Spec.ProviderNetwork = nil
+				v1alpha6Cluster.Spec.RouterExternalGateway = nil
+				v1alpha6Cluster.Spec.ComputeAvailabilityZone = ""
+				v1alpha6Cluster.Spec.RootVolumeAvailabilityZone = ""
+				v1alpha6Cluster.Spec.NetworkAvailabilityZone = ""
+				v1alpha6Cluster.Spec.MachineMetadataPropagation = nil
+				v1alpha6Cluster.Spec.SnapshotBeforeDelete = nil
+				v1alpha6Cluster.Spec.ManagedSubnets = nil
+				v1alpha6Cluster.Spec.Router = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.IPVersion = 0
+				v1alpha6Cluster.Status.APIServerAddress = ""
+				v1alpha6Cluster.Status.BastionDNSRecord = nil
+				v1alpha6Cluster.Status.ExternalAddresses = nil
+				v1alpha6Cluster.Status.FloatingIPPoolClaims = nil
+				v1alpha6Cluster.Status.AdditionalFloatingIPs = nil
 
 				v1alpha6Cluster.Status.FailureMessage = nil
 				v1alpha6Cluster.Status.FailureReason = nil
+				v1alpha6Cluster.Status.Preflight = nil
+				v1alpha6Cluster.Status.GarbageCollection = nil
+				v1alpha6Cluster.Status.Plan = nil
+				v1alpha6Cluster.Status.Conditions = nil
 
 				if v1alpha6Cluster.Status.Bastion != nil {
 					v1alpha6Cluster.Status.Bastion.ImageUUID = ""
@@ -246,6 +316,11 @@ func TestFuzzyConversion(t *testing.T) {
 				}
 
 				if v1alpha6Cluster.Status.Network != nil {
+					v1alpha6Cluster.Status.Network.IPv6Subnet = nil
+					v1alpha6Cluster.Status.Network.ManagedSubnets = nil
+					v1alpha6Cluster.Status.Network.MTU = 0
+					v1alpha6Cluster.Status.Network.AdditionalPortsLoadBalancer = nil
+					v1alpha6Cluster.Status.Network.APIServerVIP = nil
 					if v1alpha6Cluster.Status.Network.APIServerLoadBalancer != nil {
 						v1alpha6Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
@@ -255,6 +330,11 @@ func TestFuzzyConversion(t *testing.T) {
 				}
 
 				if v1alpha6Cluster.Status.ExternalNetwork != nil {
+					v1alpha6Cluster.Status.ExternalNetwork.IPv6Subnet = nil
+					v1alpha6Cluster.Status.ExternalNetwork.ManagedSubnets = nil
+					v1alpha6Cluster.Status.ExternalNetwork.MTU = 0
+					v1alpha6Cluster.Status.ExternalNetwork.AdditionalPortsLoadBalancer = nil
+					v1alpha6Cluster.Status.ExternalNetwork.APIServerVIP = nil
 					if v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
 						v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
@@ -269,6 +349,37 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
 				v1alpha6Machine.Spec.Ports = nil
 				v1alpha6Machine.Spec.ImageUUID = ""
+				v1alpha6Machine.Spec.ServerGroup = nil
+				v1alpha6Machine.Spec.ImageChecksum = ""
+				v1alpha6Machine.Spec.BootstrapFormat = ""
+				v1alpha6Machine.Spec.Ignition = nil
+				v1alpha6Machine.Spec.SSHPublicKeySecretRef = nil
+				v1alpha6Machine.Spec.ServerPassword = nil
+				v1alpha6Machine.Spec.ManagedSubnet = nil
+				v1alpha6Machine.Spec.AddressSortOrder = nil
+				v1alpha6Machine.Spec.SchedulerHintAdditionalProperties = nil
+				v1alpha6Machine.Spec.FlavorID = ""
+				v1alpha6Machine.Spec.ImageFilter = nil
+				v1alpha6Machine.Spec.Traits = nil
+				v1alpha6Machine.Spec.ImageRef = nil
+				v1alpha6Machine.Spec.ConfigureSecondaryInterfaces = false
+				v1alpha6Machine.Spec.Region = ""
+				v1alpha6Machine.Spec.AdditionalBlockDevices = nil
+				v1alpha6Machine.Spec.ControlPlaneStorage = nil
+				v1alpha6Machine.Spec.Remediation = nil
+				v1alpha6Machine.Spec.AdoptExisting = nil
+				v1alpha6Machine.Spec.SnapshotBeforeDelete = nil
+				v1alpha6Machine.Spec.Reservation = nil
+				v1alpha6Machine.Spec.DeleteRemovedBlockDevices = false
+				v1alpha6Machine.Spec.Hostname = ""
+				v1alpha6Machine.Status.ServerCreateOpts = nil
+				v1alpha6Machine.Status.InstanceActionsAudit = nil
+				v1alpha6Machine.Status.Remediation = nil
+				v1alpha6Machine.Status.SnapshotImageID = ""
+				v1alpha6Machine.Status.Placement = nil
+				v1alpha6Machine.Status.Plan = nil
+				v1alpha6Machine.Status.ImageID = ""
+				v1alpha6Machine.Status.AttachedBlockDevices = nil
 			},
 			func(v1alpha6MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6MachineTemplate)
@@ -278,6 +389,29 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha6MachineTemplate.Spec.Template.Spec.Image = ""
 				v1alpha6MachineTemplate.Spec.Template.Spec.ImageUUID = ""
 				v1alpha6MachineTemplate.Spec.Template.Spec.Ports = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ServerGroup = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageChecksum = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.BootstrapFormat = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.Ignition = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SSHPublicKeySecretRef = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ServerPassword = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ManagedSubnet = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.AddressSortOrder = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SchedulerHintAdditionalProperties = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.FlavorID = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageFilter = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Traits = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageRef = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ConfigureSecondaryInterfaces = false
+				v1alpha6MachineTemplate.Spec.Template.Spec.Region = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.AdditionalBlockDevices = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ControlPlaneStorage = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Remediation = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.AdoptExisting = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SnapshotBeforeDelete = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Reservation = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.DeleteRemovedBlockDevices = false
+				v1alpha6MachineTemplate.Spec.Template.Spec.Hostname = ""
 			},
 			func(v1alpha6Network *infrav1.Network, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6Network)
@@ -295,6 +429,8 @@ func TestFuzzyConversion(t *testing.T) {
 
 				v1alpha6RootVolume.VolumeType = ""
 				v1alpha6RootVolume.AvailabilityZone = ""
+				v1alpha6RootVolume.CrossAZAttach = false
+				v1alpha6RootVolume.RetentionPolicy = ""
 			},
 		}
 	}
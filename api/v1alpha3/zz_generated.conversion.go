@@ -45,11 +45,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_Bastion_To_v1alpha3_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ExternalRouterIPParam)(nil), (*v1alpha6.ExternalRouterIPParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(a.(*ExternalRouterIPParam), b.(*v1alpha6.ExternalRouterIPParam), scope)
 	}); err != nil {
@@ -130,11 +125,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineTemplate)(nil), (*v1alpha6.OpenStackMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(a.(*OpenStackMachineTemplate), b.(*v1alpha6.OpenStackMachineTemplate), scope)
 	}); err != nil {
@@ -285,6 +275,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_Bastion_To_v1alpha3_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.Instance)(nil), (*Instance)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_Instance_To_v1alpha3_Instance(a.(*v1alpha6.Instance), b.(*Instance), scope)
 	}); err != nil {
@@ -320,6 +315,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.RootVolume)(nil), (*RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_RootVolume_To_v1alpha3_RootVolume(a.(*v1alpha6.RootVolume), b.(*RootVolume), scope)
 	}); err != nil {
@@ -363,14 +363,11 @@ func autoConvert_v1alpha6_Bastion_To_v1alpha3_Bastion(in *v1alpha6.Bastion, out
 		return err
 	}
 	out.AvailabilityZone = in.AvailabilityZone
+	// WARNING: in.UserData requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNS requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_Bastion_To_v1alpha3_Bastion is an autogenerated conversion function.
-func Convert_v1alpha6_Bastion_To_v1alpha3_Bastion(in *v1alpha6.Bastion, out *Bastion, s conversion.Scope) error {
-	return autoConvert_v1alpha6_Bastion_To_v1alpha3_Bastion(in, out, s)
-}
-
 func autoConvert_v1alpha3_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(in *ExternalRouterIPParam, out *v1alpha6.ExternalRouterIPParam, s conversion.Scope) error {
 	out.FixedIP = in.FixedIP
 	if err := Convert_v1alpha3_SubnetParam_To_v1alpha6_SubnetParam(&in.Subnet, &out.Subnet, s); err != nil {
@@ -534,6 +531,9 @@ func autoConvert_v1alpha6_Network_To_v1alpha3_Network(in *v1alpha6.Network, out
 	out.ID = in.ID
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Subnet = (*Subnet)(unsafe.Pointer(in.Subnet))
+	// WARNING: in.IPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
+	// WARNING: in.MTU requires manual conversion: does not exist in peer-type
 	// WARNING: in.PortOpts requires manual conversion: does not exist in peer-type
 	if in.Router != nil {
 		in, out := &in.Router, &out.Router
@@ -553,6 +553,8 @@ func autoConvert_v1alpha6_Network_To_v1alpha3_Network(in *v1alpha6.Network, out
 	} else {
 		out.APIServerLoadBalancer = nil
 	}
+	// WARNING: in.AdditionalPortsLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerVIP requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -730,13 +732,18 @@ func autoConvert_v1alpha3_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(
 func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(in *v1alpha6.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	out.CloudName = in.CloudName
 	out.NodeCIDR = in.NodeCIDR
+	// WARNING: in.NodeIPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha6_NetworkFilter_To_v1alpha3_Filter(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha6_SubnetFilter_To_v1alpha3_SubnetFilter(&in.Subnet, &out.Subnet, s); err != nil {
 		return err
 	}
+	// WARNING: in.ExternallyManagedNetwork requires manual conversion: does not exist in peer-type
 	out.DNSNameservers = *(*[]string)(unsafe.Pointer(&in.DNSNameservers))
+	// WARNING: in.Router requires manual conversion: does not exist in peer-type
+	// WARNING: in.RouterExternalGateway requires manual conversion: does not exist in peer-type
 	if in.ExternalRouterIPs != nil {
 		in, out := &in.ExternalRouterIPs, &out.ExternalRouterIPs
 		*out = make([]ExternalRouterIPParam, len(*in))
@@ -752,17 +759,31 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(
 	// WARNING: in.APIServerLoadBalancer requires manual conversion: does not exist in peer-type
 	// WARNING: in.DisableAPIServerFloatingIP requires manual conversion: does not exist in peer-type
 	out.APIServerFloatingIP = in.APIServerFloatingIP
+	// WARNING: in.FloatingIPPoolRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
 	// WARNING: in.APIServerFixedIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedAPIServerVIP requires manual conversion: does not exist in peer-type
 	out.APIServerPort = in.APIServerPort
 	out.ManagedSecurityGroups = in.ManagedSecurityGroups
 	// WARNING: in.AllowAllInClusterTraffic requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSecurityGroupRulesPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSecurityGroupRules requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = in.DisablePortSecurity
+	// WARNING: in.NetworkMTU requires manual conversion: does not exist in peer-type
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.ServerMetadata requires manual conversion: does not exist in peer-type
+	// WARNING: in.MachineMetadataPropagation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceNaming requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta1_APIEndpoint_To_v1alpha3_APIEndpoint(&in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint, s); err != nil {
 		return err
 	}
+	// WARNING: in.ControlPlaneEndpointDNS requires manual conversion: does not exist in peer-type
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
 	// WARNING: in.ControlPlaneOmitAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.ComputeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.RootVolumeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkAvailabilityZone requires manual conversion: does not exist in peer-type
 	if in.Bastion != nil {
 		in, out := &in.Bastion, &out.Bastion
 		*out = new(Bastion)
@@ -772,6 +793,8 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(
 	} else {
 		out.Bastion = nil
 	}
+	// WARNING: in.BlueprintExport requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
 	// WARNING: in.IdentityRef requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -850,8 +873,17 @@ func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterSta
 	} else {
 		out.Bastion = nil
 	}
+	// WARNING: in.BastionDNSRecord requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExternalAddresses requires manual conversion: does not exist in peer-type
+	// WARNING: in.FloatingIPPoolClaims requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
+	// WARNING: in.Preflight requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -972,11 +1004,18 @@ func autoConvert_v1alpha3_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(
 func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(in *v1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
+	// WARNING: in.AdoptExisting requires manual conversion: does not exist in peer-type
 	out.CloudName = in.CloudName
 	out.Flavor = in.Flavor
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
 	out.Image = in.Image
 	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageFilter requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageChecksum requires manual conversion: does not exist in peer-type
 	out.SSHKeyName = in.SSHKeyName
+	// WARNING: in.SSHPublicKeySecretRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerPassword requires manual conversion: does not exist in peer-type
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
 		*out = make([]NetworkParam, len(*in))
@@ -989,13 +1028,19 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(
 		out.Networks = nil
 	}
 	// WARNING: in.Ports requires manual conversion: does not exist in peer-type
+	// WARNING: in.AddressSortOrder requires manual conversion: does not exist in peer-type
 	out.Subnet = in.Subnet
+	// WARNING: in.ManagedSubnet requires manual conversion: does not exist in peer-type
 	out.FloatingIP = in.FloatingIP
 	out.SecurityGroups = *(*[]SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
 	out.Trunk = in.Trunk
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
+	// WARNING: in.Hostname requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigureSecondaryInterfaces requires manual conversion: does not exist in peer-type
 	if in.RootVolume != nil {
 		in, out := &in.RootVolume, &out.RootVolume
 		*out = new(RootVolume)
@@ -1005,8 +1050,18 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(
 	} else {
 		out.RootVolume = nil
 	}
+	// WARNING: in.AdditionalBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneStorage requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeleteRemovedBlockDevices requires manual conversion: does not exist in peer-type
 	out.ServerGroupID = in.ServerGroupID
+	// WARNING: in.ServerGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.SchedulerHintAdditionalProperties requires manual conversion: does not exist in peer-type
+	// WARNING: in.Traits requires manual conversion: does not exist in peer-type
+	// WARNING: in.Reservation requires manual conversion: does not exist in peer-type
 	// WARNING: in.IdentityRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1029,17 +1084,20 @@ func autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineSta
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.ImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerCreateOpts requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceActionsAudit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*v1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in *v1alpha6.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(in *OpenStackMachineTemplate, out *v1alpha6.OpenStackMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_OpenStackMachineTemplateSpec_To_v1alpha6_OpenStackMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1168,6 +1226,8 @@ func autoConvert_v1alpha6_RootVolume_To_v1alpha3_RootVolume(in *v1alpha6.RootVol
 	out.Size = in.Size
 	// WARNING: in.VolumeType requires manual conversion: does not exist in peer-type
 	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.CrossAZAttach requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetentionPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
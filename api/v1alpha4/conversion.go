@@ -304,6 +304,11 @@ func Convert_Slice_v1alpha4_Network_To_Slice_v1alpha6_Network(in *[]Network, out
 	return nil
 }
 
+func Convert_v1alpha6_Network_To_v1alpha4_Network(in *infrav1.Network, out *Network, s conversion.Scope) error {
+	// IPv6Subnet, the managed subnets, the MTU and the additional ports load balancer have no equivalent in v1alpha4
+	return autoConvert_v1alpha6_Network_To_v1alpha4_Network(in, out, s)
+}
+
 func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha4_Network(in *[]infrav1.Network, out *[]Network, s conversion.Scope) error {
 	*out = make([]Network, len(*in))
 	for i := range *in {
@@ -403,3 +408,18 @@ func Convert_v1alpha6_Router_To_v1alpha4_Router(in *infrav1.Router, out *Router,
 func Convert_v1alpha6_LoadBalancer_To_v1alpha4_LoadBalancer(in *infrav1.LoadBalancer, out *LoadBalancer, s conversion.Scope) error {
 	return autoConvert_v1alpha6_LoadBalancer_To_v1alpha4_LoadBalancer(in, out, s)
 }
+
+func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in *infrav1.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	// The preflight report, floating IP pool claims and API server address have no equivalent in v1alpha4
+	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in, out, s)
+}
+
+func Convert_v1alpha6_Bastion_To_v1alpha4_Bastion(in *infrav1.Bastion, out *Bastion, s conversion.Scope) error {
+	// User data has no equivalent in v1alpha4
+	return autoConvert_v1alpha6_Bastion_To_v1alpha4_Bastion(in, out, s)
+}
+
+func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(in *infrav1.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
+	// The server create options and the instance actions audit have no equivalent in v1alpha4
+	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(in, out, s)
+}
@@ -150,7 +150,7 @@ func TestConvertFrom(t *testing.T) {
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"cloudName\":\"\",\"flavor\":\"\",\"ports\":[{\"fixedIPs\":[{\"subnet\":{\"id\":\"986f5848-127f-4357-944e-5dd75472def8\"}}]}]},\"status\":{\"ready\":false}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"cloudName\":\"\",\"ports\":[{\"fixedIPs\":[{\"subnet\":{\"id\":\"986f5848-127f-4357-944e-5dd75472def8\"}}]}]},\"status\":{\"ready\":false}}",
 					},
 				},
 			},
@@ -308,6 +308,12 @@ func TestFuzzyConversion(t *testing.T) {
 					}
 				}
 				v1alpha6PortOpts.SecurityGroupFilters = nil
+				v1alpha6PortOpts.Subports = nil
+				v1alpha6PortOpts.Hints = nil
+				v1alpha6PortOpts.QoSPolicy = ""
+				v1alpha6PortOpts.DeviceProfile = ""
+				v1alpha6PortOpts.Order = 0
+				v1alpha6PortOpts.BindingProfile = nil
 			},
 			func(v1alpha6FixedIP *infrav1.FixedIP, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6FixedIP)
@@ -323,6 +329,7 @@ func TestFuzzyConversion(t *testing.T) {
 						v1alpha6FixedIP.Subnet = nil
 					}
 				}
+				v1alpha6FixedIP.IPAddressPoolRef = nil
 			},
 			func(v1alpha6Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6Cluster)
@@ -332,11 +339,82 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha6Cluster.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
 
 				v1alpha6Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha6Cluster.Spec.ResourceNaming = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.Provider = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.FlavorID = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.FlavorName = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.HealthMonitor = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.ConnectionLimit = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutClientData = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutMemberData = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TimeoutMemberConnect = 0
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.AvailabilityZone = ""
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.Shared = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.TLS = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor = nil
+				v1alpha6Cluster.Spec.FloatingIPPoolRef = nil
+				v1alpha6Cluster.Spec.AdditionalFloatingIPs = nil
+				v1alpha6Cluster.Spec.ControlPlaneEndpointDNS = nil
+				v1alpha6Cluster.Spec.BlueprintExport = nil
+				v1alpha6Cluster.Spec.GarbageCollection = nil
+				v1alpha6Cluster.Spec.ExternallyManagedNetwork = false
+				v1alpha6Cluster.Spec.ManagedAPIServerVIP = false
+				v1alpha6Cluster.Spec.ServerMetadata = nil
+				v1alpha6Cluster.Spec.ManagedSecurityGroupRulesPolicy = ""
+				v1alpha6Cluster.Spec.ManagedSecurityGroupRules = nil
+				v1alpha6Cluster.Spec.NodeIPv6Subnet = nil
+				v1alpha6Cluster.Spec.NetworkMTU = 0
+				v1alpha6Cluster.Spec.ProviderNetwork = nil
+				v1alpha6Cluster.Spec.RouterExternalGateway = nil
+				v1alpha6Cluster.Spec.ComputeAvailabilityZone = ""
+				v1alpha6Cluster.Spec.RootVolumeAvailabilityZone = ""
+				v1alpha6Cluster.Spec.NetworkAvailabilityZone = ""
+				v1alpha6Cluster.Spec.MachineMetadataPropagation = nil
+				v1alpha6Cluster.Spec.SnapshotBeforeDelete = nil
+				v1alpha6Cluster.Spec.ManagedSubnets = nil
+				v1alpha6Cluster.Spec.Router = nil
+				v1alpha6Cluster.Spec.APIServerLoadBalancer.IPVersion = 0
+				v1alpha6Cluster.Status.APIServerAddress = ""
+				v1alpha6Cluster.Status.BastionDNSRecord = nil
+				v1alpha6Cluster.Status.ExternalAddresses = nil
+				v1alpha6Cluster.Status.FloatingIPPoolClaims = nil
+				v1alpha6Cluster.Status.AdditionalFloatingIPs = nil
 
 				if v1alpha6Cluster.Spec.Bastion != nil {
 					v1alpha6Cluster.Spec.Bastion.Instance.Image = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.ServerGroup = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageChecksum = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.BootstrapFormat = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.Ignition = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SSHPublicKeySecretRef = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ServerPassword = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ManagedSubnet = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.AddressSortOrder = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SchedulerHintAdditionalProperties = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.FlavorID = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageFilter = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Traits = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ImageRef = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ConfigureSecondaryInterfaces = false
+					v1alpha6Cluster.Spec.Bastion.Instance.Region = ""
+					v1alpha6Cluster.Spec.Bastion.Instance.AdditionalBlockDevices = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.ControlPlaneStorage = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Remediation = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.AdoptExisting = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.SnapshotBeforeDelete = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.Reservation = nil
+					v1alpha6Cluster.Spec.Bastion.Instance.DeleteRemovedBlockDevices = false
+					v1alpha6Cluster.Spec.Bastion.Instance.Hostname = ""
+					v1alpha6Cluster.Spec.Bastion.UserData = ""
+					v1alpha6Cluster.Spec.Bastion.DNS = nil
 				}
 
+				v1alpha6Cluster.Status.Preflight = nil
+				v1alpha6Cluster.Status.GarbageCollection = nil
+				v1alpha6Cluster.Status.Plan = nil
+				v1alpha6Cluster.Status.Conditions = nil
+
 				if v1alpha6Cluster.Status.Bastion != nil {
 					v1alpha6Cluster.Status.Bastion.ImageUUID = ""
 					v1alpha6Cluster.Status.Bastion.Image = ""
@@ -344,6 +422,11 @@ func TestFuzzyConversion(t *testing.T) {
 				}
 
 				if v1alpha6Cluster.Status.Network != nil {
+					v1alpha6Cluster.Status.Network.IPv6Subnet = nil
+					v1alpha6Cluster.Status.Network.ManagedSubnets = nil
+					v1alpha6Cluster.Status.Network.MTU = 0
+					v1alpha6Cluster.Status.Network.AdditionalPortsLoadBalancer = nil
+					v1alpha6Cluster.Status.Network.APIServerVIP = nil
 					if v1alpha6Cluster.Status.Network.APIServerLoadBalancer != nil {
 						v1alpha6Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
@@ -353,6 +436,11 @@ func TestFuzzyConversion(t *testing.T) {
 				}
 
 				if v1alpha6Cluster.Status.ExternalNetwork != nil {
+					v1alpha6Cluster.Status.ExternalNetwork.IPv6Subnet = nil
+					v1alpha6Cluster.Status.ExternalNetwork.ManagedSubnets = nil
+					v1alpha6Cluster.Status.ExternalNetwork.MTU = 0
+					v1alpha6Cluster.Status.ExternalNetwork.AdditionalPortsLoadBalancer = nil
+					v1alpha6Cluster.Status.ExternalNetwork.APIServerVIP = nil
 					if v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
 						v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
@@ -366,6 +454,41 @@ func TestFuzzyConversion(t *testing.T) {
 
 				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
 
+				v1alpha6Machine.Spec.ServerGroup = nil
+
+				v1alpha6Machine.Spec.ImageChecksum = ""
+
+				v1alpha6Machine.Spec.BootstrapFormat = ""
+
+				v1alpha6Machine.Spec.Ignition = nil
+				v1alpha6Machine.Spec.SSHPublicKeySecretRef = nil
+				v1alpha6Machine.Spec.ServerPassword = nil
+				v1alpha6Machine.Spec.ManagedSubnet = nil
+				v1alpha6Machine.Spec.AddressSortOrder = nil
+				v1alpha6Machine.Spec.SchedulerHintAdditionalProperties = nil
+				v1alpha6Machine.Spec.FlavorID = ""
+				v1alpha6Machine.Spec.ImageFilter = nil
+				v1alpha6Machine.Spec.Traits = nil
+				v1alpha6Machine.Spec.ImageRef = nil
+				v1alpha6Machine.Spec.ConfigureSecondaryInterfaces = false
+				v1alpha6Machine.Spec.Region = ""
+				v1alpha6Machine.Spec.AdditionalBlockDevices = nil
+				v1alpha6Machine.Spec.ControlPlaneStorage = nil
+				v1alpha6Machine.Spec.Remediation = nil
+				v1alpha6Machine.Spec.AdoptExisting = nil
+				v1alpha6Machine.Spec.SnapshotBeforeDelete = nil
+				v1alpha6Machine.Spec.Reservation = nil
+				v1alpha6Machine.Spec.DeleteRemovedBlockDevices = false
+				v1alpha6Machine.Spec.Hostname = ""
+				v1alpha6Machine.Status.ServerCreateOpts = nil
+				v1alpha6Machine.Status.InstanceActionsAudit = nil
+				v1alpha6Machine.Status.Remediation = nil
+				v1alpha6Machine.Status.SnapshotImageID = ""
+				v1alpha6Machine.Status.Placement = nil
+				v1alpha6Machine.Status.Plan = nil
+				v1alpha6Machine.Status.ImageID = ""
+				v1alpha6Machine.Status.AttachedBlockDevices = nil
+
 				// In v1alpha4 boot from volume only supports
 				// image by UUID, and boot from local only
 				// suppots image by name
@@ -381,6 +504,29 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha6MachineTemplate.ObjectMeta.Annotations = map[string]string{}
 
 				v1alpha6MachineTemplate.Spec.Template.Spec.Image = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.ServerGroup = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageChecksum = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.BootstrapFormat = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.Ignition = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SSHPublicKeySecretRef = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ServerPassword = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ManagedSubnet = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.AddressSortOrder = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SchedulerHintAdditionalProperties = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.FlavorID = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageFilter = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Traits = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ImageRef = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ConfigureSecondaryInterfaces = false
+				v1alpha6MachineTemplate.Spec.Template.Spec.Region = ""
+				v1alpha6MachineTemplate.Spec.Template.Spec.AdditionalBlockDevices = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.ControlPlaneStorage = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Remediation = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.AdoptExisting = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.SnapshotBeforeDelete = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.Reservation = nil
+				v1alpha6MachineTemplate.Spec.Template.Spec.DeleteRemovedBlockDevices = false
+				v1alpha6MachineTemplate.Spec.Template.Spec.Hostname = ""
 			},
 			func(v1alpha6Instance *infrav1.Instance, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6Instance)
@@ -399,6 +545,8 @@ func TestFuzzyConversion(t *testing.T) {
 
 				v1alpha6RootVolume.VolumeType = ""
 				v1alpha6RootVolume.AvailabilityZone = ""
+				v1alpha6RootVolume.CrossAZAttach = false
+				v1alpha6RootVolume.RetentionPolicy = ""
 			},
 			func(v1alpha6ClusterTemplate *infrav1.OpenStackClusterTemplate, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha6ClusterTemplate)
@@ -408,9 +556,71 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
 
 				v1alpha6ClusterTemplate.Spec.Template.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ResourceNaming = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.Provider = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.FlavorID = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.FlavorName = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.HealthMonitor = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.ConnectionLimit = 0
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.TimeoutClientData = 0
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.TimeoutMemberData = 0
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.TimeoutMemberConnect = 0
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.AvailabilityZone = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.ExistingLoadBalancer = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.Shared = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.TLS = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.AdditionalPortsFlavor = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.FloatingIPPoolRef = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.AdditionalFloatingIPs = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ControlPlaneEndpointDNS = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.BlueprintExport = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.GarbageCollection = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ExternallyManagedNetwork = false
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ManagedAPIServerVIP = false
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ServerMetadata = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ManagedSecurityGroupRulesPolicy = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ManagedSecurityGroupRules = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.NodeIPv6Subnet = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.NetworkMTU = 0
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ProviderNetwork = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.RouterExternalGateway = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ComputeAvailabilityZone = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.RootVolumeAvailabilityZone = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.NetworkAvailabilityZone = ""
+				v1alpha6ClusterTemplate.Spec.Template.Spec.MachineMetadataPropagation = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.SnapshotBeforeDelete = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.SnapshotBeforeDelete = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.ManagedSubnets = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.Router = nil
+				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.IPVersion = 0
 
 				if v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion != nil {
 					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Image = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ServerGroup = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ImageChecksum = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.BootstrapFormat = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Ignition = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.SSHPublicKeySecretRef = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ServerPassword = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ManagedSubnet = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.AddressSortOrder = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.SchedulerHintAdditionalProperties = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.FlavorID = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ImageFilter = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Traits = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ImageRef = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ConfigureSecondaryInterfaces = false
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Region = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.AdditionalBlockDevices = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.ControlPlaneStorage = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Remediation = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.AdoptExisting = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.SnapshotBeforeDelete = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Reservation = nil
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.DeleteRemovedBlockDevices = false
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Hostname = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.UserData = ""
+					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.DNS = nil
 				}
 			},
 		}
@@ -55,11 +55,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_Bastion_To_v1alpha4_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ExternalRouterIPParam)(nil), (*v1alpha6.ExternalRouterIPParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(a.(*ExternalRouterIPParam), b.(*v1alpha6.ExternalRouterIPParam), scope)
 	}); err != nil {
@@ -80,11 +75,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_Network_To_v1alpha4_Network(a.(*v1alpha6.Network), b.(*Network), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkParam)(nil), (*v1alpha6.NetworkParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_NetworkParam_To_v1alpha6_NetworkParam(a.(*NetworkParam), b.(*v1alpha6.NetworkParam), scope)
 	}); err != nil {
@@ -120,11 +110,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackClusterTemplate)(nil), (*v1alpha6.OpenStackClusterTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(a.(*OpenStackClusterTemplate), b.(*v1alpha6.OpenStackClusterTemplate), scope)
 	}); err != nil {
@@ -200,11 +185,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineTemplate)(nil), (*v1alpha6.OpenStackMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(a.(*OpenStackMachineTemplate), b.(*v1alpha6.OpenStackMachineTemplate), scope)
 	}); err != nil {
@@ -365,6 +345,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_Bastion_To_v1alpha4_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.FixedIP)(nil), (*FixedIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(a.(*v1alpha6.FixedIP), b.(*FixedIP), scope)
 	}); err != nil {
@@ -385,16 +370,31 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_Network_To_v1alpha4_Network(a.(*v1alpha6.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterSpec)(nil), (*OpenStackClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(a.(*v1alpha6.OpenStackClusterSpec), b.(*OpenStackClusterSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.PortOpts)(nil), (*PortOpts)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(a.(*v1alpha6.PortOpts), b.(*PortOpts), scope)
 	}); err != nil {
@@ -465,14 +465,11 @@ func autoConvert_v1alpha6_Bastion_To_v1alpha4_Bastion(in *v1alpha6.Bastion, out
 		return err
 	}
 	out.AvailabilityZone = in.AvailabilityZone
+	// WARNING: in.UserData requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNS requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_Bastion_To_v1alpha4_Bastion is an autogenerated conversion function.
-func Convert_v1alpha6_Bastion_To_v1alpha4_Bastion(in *v1alpha6.Bastion, out *Bastion, s conversion.Scope) error {
-	return autoConvert_v1alpha6_Bastion_To_v1alpha4_Bastion(in, out, s)
-}
-
 func autoConvert_v1alpha4_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(in *ExternalRouterIPParam, out *v1alpha6.ExternalRouterIPParam, s conversion.Scope) error {
 	out.FixedIP = in.FixedIP
 	if err := Convert_v1alpha4_SubnetParam_To_v1alpha6_SubnetParam(&in.Subnet, &out.Subnet, s); err != nil {
@@ -508,6 +505,7 @@ func autoConvert_v1alpha4_FixedIP_To_v1alpha6_FixedIP(in *FixedIP, out *v1alpha6
 func autoConvert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(in *v1alpha6.FixedIP, out *FixedIP, s conversion.Scope) error {
 	// WARNING: in.Subnet requires manual conversion: does not exist in peer-type
 	out.IPAddress = in.IPAddress
+	// WARNING: in.IPAddressPoolRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -657,6 +655,9 @@ func autoConvert_v1alpha6_Network_To_v1alpha4_Network(in *v1alpha6.Network, out
 	out.ID = in.ID
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Subnet = (*Subnet)(unsafe.Pointer(in.Subnet))
+	// WARNING: in.IPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
+	// WARNING: in.MTU requires manual conversion: does not exist in peer-type
 	if in.PortOpts != nil {
 		in, out := &in.PortOpts, &out.PortOpts
 		*out = new(PortOpts)
@@ -684,14 +685,11 @@ func autoConvert_v1alpha6_Network_To_v1alpha4_Network(in *v1alpha6.Network, out
 	} else {
 		out.APIServerLoadBalancer = nil
 	}
+	// WARNING: in.AdditionalPortsLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerVIP requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_Network_To_v1alpha4_Network is an autogenerated conversion function.
-func Convert_v1alpha6_Network_To_v1alpha4_Network(in *v1alpha6.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1alpha6_Network_To_v1alpha4_Network(in, out, s)
-}
-
 func autoConvert_v1alpha4_NetworkParam_To_v1alpha6_NetworkParam(in *NetworkParam, out *v1alpha6.NetworkParam, s conversion.Scope) error {
 	out.UUID = in.UUID
 	out.FixedIP = in.FixedIP
@@ -866,13 +864,18 @@ func autoConvert_v1alpha4_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(
 func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(in *v1alpha6.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	out.CloudName = in.CloudName
 	out.NodeCIDR = in.NodeCIDR
+	// WARNING: in.NodeIPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha6_NetworkFilter_To_v1alpha4_Filter(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha6_SubnetFilter_To_v1alpha4_SubnetFilter(&in.Subnet, &out.Subnet, s); err != nil {
 		return err
 	}
+	// WARNING: in.ExternallyManagedNetwork requires manual conversion: does not exist in peer-type
 	out.DNSNameservers = *(*[]string)(unsafe.Pointer(&in.DNSNameservers))
+	// WARNING: in.Router requires manual conversion: does not exist in peer-type
+	// WARNING: in.RouterExternalGateway requires manual conversion: does not exist in peer-type
 	if in.ExternalRouterIPs != nil {
 		in, out := &in.ExternalRouterIPs, &out.ExternalRouterIPs
 		*out = make([]ExternalRouterIPParam, len(*in))
@@ -888,15 +891,29 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(
 	// WARNING: in.APIServerLoadBalancer requires manual conversion: does not exist in peer-type
 	out.DisableAPIServerFloatingIP = in.DisableAPIServerFloatingIP
 	out.APIServerFloatingIP = in.APIServerFloatingIP
+	// WARNING: in.FloatingIPPoolRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
 	out.APIServerFixedIP = in.APIServerFixedIP
+	// WARNING: in.ManagedAPIServerVIP requires manual conversion: does not exist in peer-type
 	out.APIServerPort = in.APIServerPort
 	out.ManagedSecurityGroups = in.ManagedSecurityGroups
 	out.AllowAllInClusterTraffic = in.AllowAllInClusterTraffic
+	// WARNING: in.ManagedSecurityGroupRulesPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSecurityGroupRules requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = in.DisablePortSecurity
+	// WARNING: in.NetworkMTU requires manual conversion: does not exist in peer-type
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.ServerMetadata requires manual conversion: does not exist in peer-type
+	// WARNING: in.MachineMetadataPropagation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceNaming requires manual conversion: does not exist in peer-type
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	// WARNING: in.ControlPlaneEndpointDNS requires manual conversion: does not exist in peer-type
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
 	// WARNING: in.ControlPlaneOmitAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.ComputeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.RootVolumeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkAvailabilityZone requires manual conversion: does not exist in peer-type
 	if in.Bastion != nil {
 		in, out := &in.Bastion, &out.Bastion
 		*out = new(Bastion)
@@ -906,6 +923,8 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(
 	} else {
 		out.Bastion = nil
 	}
+	// WARNING: in.BlueprintExport requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
 	out.IdentityRef = (*OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
 }
@@ -986,16 +1005,20 @@ func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterSta
 	} else {
 		out.Bastion = nil
 	}
+	// WARNING: in.BastionDNSRecord requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExternalAddresses requires manual conversion: does not exist in peer-type
+	// WARNING: in.FloatingIPPoolClaims requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
+	// WARNING: in.Preflight requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in *v1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(in *OpenStackClusterTemplate, out *v1alpha6.OpenStackClusterTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha4_OpenStackClusterTemplateSpec_To_v1alpha6_OpenStackClusterTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1261,11 +1284,18 @@ func autoConvert_v1alpha4_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(
 func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(in *v1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
+	// WARNING: in.AdoptExisting requires manual conversion: does not exist in peer-type
 	out.CloudName = in.CloudName
 	out.Flavor = in.Flavor
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
 	out.Image = in.Image
 	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageFilter requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageChecksum requires manual conversion: does not exist in peer-type
 	out.SSHKeyName = in.SSHKeyName
+	// WARNING: in.SSHPublicKeySecretRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerPassword requires manual conversion: does not exist in peer-type
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
 		*out = make([]NetworkParam, len(*in))
@@ -1288,13 +1318,19 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(
 	} else {
 		out.Ports = nil
 	}
+	// WARNING: in.AddressSortOrder requires manual conversion: does not exist in peer-type
 	out.Subnet = in.Subnet
+	// WARNING: in.ManagedSubnet requires manual conversion: does not exist in peer-type
 	out.FloatingIP = in.FloatingIP
 	out.SecurityGroups = *(*[]SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
 	out.Trunk = in.Trunk
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
+	// WARNING: in.Hostname requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigureSecondaryInterfaces requires manual conversion: does not exist in peer-type
 	if in.RootVolume != nil {
 		in, out := &in.RootVolume, &out.RootVolume
 		*out = new(RootVolume)
@@ -1304,8 +1340,18 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(
 	} else {
 		out.RootVolume = nil
 	}
+	// WARNING: in.AdditionalBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneStorage requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeleteRemovedBlockDevices requires manual conversion: does not exist in peer-type
 	out.ServerGroupID = in.ServerGroupID
+	// WARNING: in.ServerGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.SchedulerHintAdditionalProperties requires manual conversion: does not exist in peer-type
+	// WARNING: in.Traits requires manual conversion: does not exist in peer-type
+	// WARNING: in.Reservation requires manual conversion: does not exist in peer-type
 	out.IdentityRef = (*OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1328,17 +1374,20 @@ func autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineSta
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.ImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerCreateOpts requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceActionsAudit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*v1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(in *v1alpha6.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(in *OpenStackMachineTemplate, out *v1alpha6.OpenStackMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha4_OpenStackMachineTemplateSpec_To_v1alpha6_OpenStackMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1491,6 +1540,7 @@ func autoConvert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in *v1alpha6.PortOpts, o
 	out.Description = in.Description
 	out.AdminStateUp = (*bool)(unsafe.Pointer(in.AdminStateUp))
 	out.MACAddress = in.MACAddress
+	// WARNING: in.Order requires manual conversion: does not exist in peer-type
 	if in.FixedIPs != nil {
 		in, out := &in.FixedIPs, &out.FixedIPs
 		*out = make([]FixedIP, len(*in))
@@ -1508,11 +1558,16 @@ func autoConvert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in *v1alpha6.PortOpts, o
 	// WARNING: in.SecurityGroupFilters requires manual conversion: does not exist in peer-type
 	out.AllowedAddressPairs = *(*[]AddressPair)(unsafe.Pointer(&in.AllowedAddressPairs))
 	out.Trunk = (*bool)(unsafe.Pointer(in.Trunk))
+	// WARNING: in.Subports requires manual conversion: does not exist in peer-type
 	out.HostID = in.HostID
 	out.VNICType = in.VNICType
 	out.Profile = *(*map[string]string)(unsafe.Pointer(&in.Profile))
+	// WARNING: in.BindingProfile requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = (*bool)(unsafe.Pointer(in.DisablePortSecurity))
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.Hints requires manual conversion: does not exist in peer-type
+	// WARNING: in.QoSPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeviceProfile requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1528,6 +1583,8 @@ func autoConvert_v1alpha6_RootVolume_To_v1alpha4_RootVolume(in *v1alpha6.RootVol
 	out.Size = in.Size
 	// WARNING: in.VolumeType requires manual conversion: does not exist in peer-type
 	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.CrossAZAttach requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetentionPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha5
 
 import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
@@ -39,6 +42,22 @@ func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
 		return err
 	}
 
+	// Round-trip the restored object to find out which of its fields v1alpha5 can represent
+	spoke := &OpenStackCluster{}
+	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha5_OpenStackCluster(restored, spoke, nil); err != nil {
+		return err
+	}
+	unchanged := &infrav1.OpenStackCluster{}
+	if err := Convert_v1alpha5_OpenStackCluster_To_v1alpha6_OpenStackCluster(spoke, unchanged, nil); err != nil {
+		return err
+	}
+	merged := &infrav1.OpenStackCluster{}
+	if err := restoreHubData(restored, unchanged, dst, merged); err != nil {
+		return err
+	}
+	dst.Spec = merged.Spec
+	dst.Status = merged.Status
+
 	return nil
 }
 
@@ -86,6 +105,21 @@ func (r *OpenStackClusterTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 		return err
 	}
 
+	// Round-trip the restored object to find out which of its fields v1alpha5 can represent
+	spoke := &OpenStackClusterTemplate{}
+	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha5_OpenStackClusterTemplate(restored, spoke, nil); err != nil {
+		return err
+	}
+	unchanged := &infrav1.OpenStackClusterTemplate{}
+	if err := Convert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(spoke, unchanged, nil); err != nil {
+		return err
+	}
+	merged := &infrav1.OpenStackClusterTemplate{}
+	if err := restoreHubData(restored, unchanged, dst, merged); err != nil {
+		return err
+	}
+	dst.Spec = merged.Spec
+
 	return nil
 }
 
@@ -119,6 +153,22 @@ func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
 		return err
 	}
 
+	// Round-trip the restored object to find out which of its fields v1alpha5 can represent
+	spoke := &OpenStackMachine{}
+	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(restored, spoke, nil); err != nil {
+		return err
+	}
+	unchanged := &infrav1.OpenStackMachine{}
+	if err := Convert_v1alpha5_OpenStackMachine_To_v1alpha6_OpenStackMachine(spoke, unchanged, nil); err != nil {
+		return err
+	}
+	merged := &infrav1.OpenStackMachine{}
+	if err := restoreHubData(restored, unchanged, dst, merged); err != nil {
+		return err
+	}
+	dst.Spec = merged.Spec
+	dst.Status = merged.Status
+
 	return nil
 }
 
@@ -166,6 +216,21 @@ func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 		return err
 	}
 
+	// Round-trip the restored object to find out which of its fields v1alpha5 can represent
+	spoke := &OpenStackMachineTemplate{}
+	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTemplate(restored, spoke, nil); err != nil {
+		return err
+	}
+	unchanged := &infrav1.OpenStackMachineTemplate{}
+	if err := Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(spoke, unchanged, nil); err != nil {
+		return err
+	}
+	merged := &infrav1.OpenStackMachineTemplate{}
+	if err := restoreHubData(restored, unchanged, dst, merged); err != nil {
+		return err
+	}
+	dst.Spec = merged.Spec
+
 	return nil
 }
 
@@ -198,7 +263,134 @@ func (r *OpenStackMachineTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) er
 	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha5_OpenStackMachineTemplateList(src, r, nil)
 }
 
+// restoreHubData applies the fields of restored, the hub object saved on the last down-conversion, which
+// were lost converting it to v1alpha5. unchanged is restored after a round-trip through v1alpha5, so the
+// difference between unchanged and dst holds the changes made through v1alpha5 since then: these are
+// applied on top of restored and the result is written to merged.
+func restoreHubData(restored, unchanged, dst, merged interface{}) error {
+	restoredJSON, err := json.Marshal(restored)
+	if err != nil {
+		return err
+	}
+	unchangedJSON, err := normalizedJSON(unchanged)
+	if err != nil {
+		return err
+	}
+	dstJSON, err := normalizedJSON(dst)
+	if err != nil {
+		return err
+	}
+
+	patch, err := jsonpatch.CreateMergePatch(unchangedJSON, dstJSON)
+	if err != nil {
+		return err
+	}
+	mergedJSON, err := jsonpatch.MergePatch(restoredJSON, patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(mergedJSON, merged)
+}
+
+// normalizedJSON marshals obj without null values and empty lists, so that a nil and an empty slice do
+// not show up as a change. A changed item replaces the whole list in a merge patch, which would drop the
+// restored fields of all the other items.
+func normalizedJSON(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(dropEmpty(value))
+}
+
+func dropEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if list, ok := item.([]interface{}); item == nil || ok && len(list) == 0 {
+				delete(v, key)
+				continue
+			}
+			v[key] = dropEmpty(item)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = dropEmpty(v[i])
+		}
+	}
+	return value
+}
+
 func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in *infrav1.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	// Our new flag has no equivalent in v1alpha5
 	return autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in, out, s)
 }
+
+func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in *infrav1.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
+	// New fields have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in, out, s)
+}
+
+func Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(in *infrav1.PortOpts, out *PortOpts, s conversion.Scope) error {
+	// Subports, hints, QoS policies, device profiles and binding profiles have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(in, out, s)
+}
+
+func Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(in *infrav1.RootVolume, out *RootVolume, s conversion.Scope) error {
+	// CrossAZAttach and RetentionPolicy have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(in, out, s)
+}
+
+func Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(in *infrav1.FixedIP, out *FixedIP, s conversion.Scope) error {
+	// IPAddressPoolRef has no equivalent in v1alpha5
+	return autoConvert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(in, out, s)
+}
+
+func Convert_Slice_v1alpha5_Network_To_Slice_v1alpha6_Network(in *[]Network, out *[]infrav1.Network, s conversion.Scope) error {
+	*out = make([]infrav1.Network, len(*in))
+	for i := range *in {
+		if err := Convert_v1alpha5_Network_To_v1alpha6_Network(&(*in)[i], &(*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Convert_v1alpha6_Network_To_v1alpha5_Network(in *infrav1.Network, out *Network, s conversion.Scope) error {
+	// IPv6Subnet, the managed subnets, the MTU and the additional ports load balancer have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_Network_To_v1alpha5_Network(in, out, s)
+}
+
+func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha5_Network(in *[]infrav1.Network, out *[]Network, s conversion.Scope) error {
+	*out = make([]Network, len(*in))
+	for i := range *in {
+		if err := Convert_v1alpha6_Network_To_v1alpha5_Network(&(*in)[i], &(*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(in *infrav1.APIServerLoadBalancer, out *APIServerLoadBalancer, s conversion.Scope) error {
+	// Provider, flavors, IP version, health monitor, existing and shared load balancers and TLS have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(in, out, s)
+}
+
+func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in *infrav1.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	// The preflight report, floating IP pool claims and API server address have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in, out, s)
+}
+
+func Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(in *infrav1.Bastion, out *Bastion, s conversion.Scope) error {
+	// User data has no equivalent in v1alpha5
+	return autoConvert_v1alpha6_Bastion_To_v1alpha5_Bastion(in, out, s)
+}
+
+func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(in *infrav1.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
+	// The server create options and the instance actions audit have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(in, out, s)
+}
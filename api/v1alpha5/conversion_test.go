@@ -17,11 +17,17 @@ limitations under the License.
 package v1alpha5
 
 import (
+	"fmt"
 	"testing"
 
+	fuzz "github.com/google/gofuzz"
 	"github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
@@ -79,7 +85,7 @@ func TestConvertFrom(t *testing.T) {
 				Spec: OpenStackMachineSpec{},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"cloudName\":\"\",\"flavor\":\"\"},\"status\":{\"ready\":false}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"cloudName\":\"\"},\"status\":{\"ready\":false}}",
 					},
 				},
 			},
@@ -94,7 +100,7 @@ func TestConvertFrom(t *testing.T) {
 				Spec: OpenStackMachineTemplateSpec{},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"template\":{\"spec\":{\"cloudName\":\"\",\"flavor\":\"\"}}}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"template\":{\"spec\":{\"cloudName\":\"\"}}}}",
 					},
 				},
 			},
@@ -109,3 +115,123 @@ func TestConvertFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestFuzzyConversion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	fuzzerFuncs := func(_ runtimeserializer.CodecFactory) []interface{} {
+		return []interface{}{
+			// The conversion-data annotation of a fuzzed spoke is not valid hub data
+			func(v1alpha5Cluster *OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha5Cluster)
+				v1alpha5Cluster.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha5ClusterTemplate *OpenStackClusterTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha5ClusterTemplate)
+				v1alpha5ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha5Machine *OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha5Machine)
+				v1alpha5Machine.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha5MachineTemplate *OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha5MachineTemplate)
+				v1alpha5MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+
+			// The conversion-data annotation is removed from the annotations of the restored hub
+			func(v1alpha6Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6Cluster)
+				v1alpha6Cluster.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha6ClusterTemplate *infrav1.OpenStackClusterTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6ClusterTemplate)
+				v1alpha6ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha6Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6Machine)
+				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha6MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6MachineTemplate)
+				v1alpha6MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+
+			// Binding profile values must be valid JSON to be stored in the conversion-data annotation
+			func(v1alpha6JSON *apiextensionsv1.JSON, c fuzz.Continue) {
+				v1alpha6JSON.Raw = []byte(fmt.Sprintf("%d", c.Int()))
+			},
+
+			// A pointer to a nil slice is stored as null in the conversion-data annotation
+			func(v1alpha6SecurityGroups **[]string, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6SecurityGroups)
+				if *v1alpha6SecurityGroups != nil && **v1alpha6SecurityGroups == nil {
+					*v1alpha6SecurityGroups = nil
+				}
+			},
+			func(v1alpha6Networks **[]infrav1.Network, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6Networks)
+				if *v1alpha6Networks != nil && **v1alpha6Networks == nil {
+					*v1alpha6Networks = nil
+				}
+			},
+		}
+	}
+
+	t.Run("for OpenStackCluster", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackCluster{},
+		Spoke:       &OpenStackCluster{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackClusterTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackClusterTemplate{},
+		Spoke:       &OpenStackClusterTemplate{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackMachine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackMachine{},
+		Spoke:       &OpenStackMachine{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackMachineTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackMachineTemplate{},
+		Spoke:       &OpenStackMachineTemplate{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+}
+
+func TestConvertToRestoresHubData(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	hub := &infrav1.OpenStackMachine{
+		Spec: infrav1.OpenStackMachineSpec{
+			Flavor: "m1.small",
+			Ports:  []infrav1.PortOpts{{Description: "primary", QoSPolicy: "gold"}},
+		},
+		Status: infrav1.OpenStackMachineStatus{
+			InstanceActionsAudit: &infrav1.InstanceActionsAudit{LastCheckTime: metav1.Unix(1660000000, 0)},
+		},
+	}
+	spoke := &OpenStackMachine{}
+	g.Expect(spoke.ConvertFrom(hub)).To(gomega.Succeed())
+
+	// Change a field through v1alpha5 after the down-conversion
+	spoke.Spec.Flavor = "m1.large"
+
+	got := &infrav1.OpenStackMachine{}
+	g.Expect(spoke.ConvertTo(got)).To(gomega.Succeed())
+	g.Expect(got.Spec.Flavor).To(gomega.Equal("m1.large"))
+	g.Expect(got.Spec.Ports).To(gomega.Equal(hub.Spec.Ports))
+	g.Expect(got.Status.InstanceActionsAudit).NotTo(gomega.BeNil())
+	g.Expect(got.Status.InstanceActionsAudit.LastCheckTime.Unix()).To(gomega.Equal(int64(1660000000)))
+}
@@ -44,11 +44,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.APIServerLoadBalancer)(nil), (*APIServerLoadBalancer)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(a.(*v1alpha6.APIServerLoadBalancer), b.(*APIServerLoadBalancer), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AddressPair)(nil), (*v1alpha6.AddressPair)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_AddressPair_To_v1alpha6_AddressPair(a.(*AddressPair), b.(*v1alpha6.AddressPair), scope)
 	}); err != nil {
@@ -64,11 +59,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ExternalRouterIPParam)(nil), (*v1alpha6.ExternalRouterIPParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(a.(*ExternalRouterIPParam), b.(*v1alpha6.ExternalRouterIPParam), scope)
 	}); err != nil {
@@ -84,11 +74,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.FixedIP)(nil), (*FixedIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(a.(*v1alpha6.FixedIP), b.(*FixedIP), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Instance)(nil), (*v1alpha6.Instance)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_Instance_To_v1alpha6_Instance(a.(*Instance), b.(*v1alpha6.Instance), scope)
 	}); err != nil {
@@ -114,11 +99,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_Network_To_v1alpha5_Network(a.(*v1alpha6.Network), b.(*Network), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkFilter)(nil), (*v1alpha6.NetworkFilter)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_NetworkFilter_To_v1alpha6_NetworkFilter(a.(*NetworkFilter), b.(*v1alpha6.NetworkFilter), scope)
 	}); err != nil {
@@ -169,11 +149,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackClusterTemplate)(nil), (*v1alpha6.OpenStackClusterTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(a.(*OpenStackClusterTemplate), b.(*v1alpha6.OpenStackClusterTemplate), scope)
 	}); err != nil {
@@ -249,21 +224,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineStatus)(nil), (*v1alpha6.OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(a.(*OpenStackMachineStatus), b.(*v1alpha6.OpenStackMachineStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineTemplate)(nil), (*v1alpha6.OpenStackMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(a.(*OpenStackMachineTemplate), b.(*v1alpha6.OpenStackMachineTemplate), scope)
 	}); err != nil {
@@ -309,21 +274,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.PortOpts)(nil), (*PortOpts)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(a.(*v1alpha6.PortOpts), b.(*PortOpts), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*RootVolume)(nil), (*v1alpha6.RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_RootVolume_To_v1alpha6_RootVolume(a.(*RootVolume), b.(*v1alpha6.RootVolume), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.RootVolume)(nil), (*RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(a.(*v1alpha6.RootVolume), b.(*RootVolume), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Router)(nil), (*v1alpha6.Router)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_Router_To_v1alpha6_Router(a.(*Router), b.(*v1alpha6.Router), scope)
 	}); err != nil {
@@ -404,11 +359,66 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*[]Network)(nil), (*[]v1alpha6.Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_Slice_v1alpha5_Network_To_Slice_v1alpha6_Network(a.(*[]Network), b.(*[]v1alpha6.Network), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*[]v1alpha6.Network)(nil), (*[]Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_Slice_v1alpha6_Network_To_Slice_v1alpha5_Network(a.(*[]v1alpha6.Network), b.(*[]Network), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.APIServerLoadBalancer)(nil), (*APIServerLoadBalancer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(a.(*v1alpha6.APIServerLoadBalancer), b.(*APIServerLoadBalancer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(a.(*v1alpha6.Bastion), b.(*Bastion), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.FixedIP)(nil), (*FixedIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(a.(*v1alpha6.FixedIP), b.(*FixedIP), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_Network_To_v1alpha5_Network(a.(*v1alpha6.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterSpec)(nil), (*OpenStackClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(a.(*v1alpha6.OpenStackClusterSpec), b.(*OpenStackClusterSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(a.(*v1alpha6.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.PortOpts)(nil), (*PortOpts)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(a.(*v1alpha6.PortOpts), b.(*PortOpts), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.RootVolume)(nil), (*RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(a.(*v1alpha6.RootVolume), b.(*RootVolume), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -427,15 +437,24 @@ func Convert_v1alpha5_APIServerLoadBalancer_To_v1alpha6_APIServerLoadBalancer(in
 func autoConvert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(in *v1alpha6.APIServerLoadBalancer, out *APIServerLoadBalancer, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.AdditionalPorts = *(*[]int)(unsafe.Pointer(&in.AdditionalPorts))
+	// WARNING: in.IPVersion requires manual conversion: does not exist in peer-type
 	out.AllowedCIDRs = *(*[]string)(unsafe.Pointer(&in.AllowedCIDRs))
+	// WARNING: in.Provider requires manual conversion: does not exist in peer-type
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
+	// WARNING: in.FlavorName requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalPortsFlavor requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthMonitor requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConnectionLimit requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeoutClientData requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeoutMemberData requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeoutMemberConnect requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExistingLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.Shared requires manual conversion: does not exist in peer-type
+	// WARNING: in.TLS requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer is an autogenerated conversion function.
-func Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(in *v1alpha6.APIServerLoadBalancer, out *APIServerLoadBalancer, s conversion.Scope) error {
-	return autoConvert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(in, out, s)
-}
-
 func autoConvert_v1alpha5_AddressPair_To_v1alpha6_AddressPair(in *AddressPair, out *v1alpha6.AddressPair, s conversion.Scope) error {
 	out.IPAddress = in.IPAddress
 	out.MACAddress = in.MACAddress
@@ -478,14 +497,11 @@ func autoConvert_v1alpha6_Bastion_To_v1alpha5_Bastion(in *v1alpha6.Bastion, out
 		return err
 	}
 	out.AvailabilityZone = in.AvailabilityZone
+	// WARNING: in.UserData requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNS requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_Bastion_To_v1alpha5_Bastion is an autogenerated conversion function.
-func Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(in *v1alpha6.Bastion, out *Bastion, s conversion.Scope) error {
-	return autoConvert_v1alpha6_Bastion_To_v1alpha5_Bastion(in, out, s)
-}
-
 func autoConvert_v1alpha5_ExternalRouterIPParam_To_v1alpha6_ExternalRouterIPParam(in *ExternalRouterIPParam, out *v1alpha6.ExternalRouterIPParam, s conversion.Scope) error {
 	out.FixedIP = in.FixedIP
 	if err := Convert_v1alpha5_SubnetParam_To_v1alpha6_SubnetParam(&in.Subnet, &out.Subnet, s); err != nil {
@@ -526,21 +542,25 @@ func Convert_v1alpha5_FixedIP_To_v1alpha6_FixedIP(in *FixedIP, out *v1alpha6.Fix
 func autoConvert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(in *v1alpha6.FixedIP, out *FixedIP, s conversion.Scope) error {
 	out.Subnet = (*SubnetFilter)(unsafe.Pointer(in.Subnet))
 	out.IPAddress = in.IPAddress
+	// WARNING: in.IPAddressPoolRef requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP is an autogenerated conversion function.
-func Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(in *v1alpha6.FixedIP, out *FixedIP, s conversion.Scope) error {
-	return autoConvert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(in, out, s)
-}
-
 func autoConvert_v1alpha5_Instance_To_v1alpha6_Instance(in *Instance, out *v1alpha6.Instance, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
 	out.Trunk = in.Trunk
 	out.FailureDomain = in.FailureDomain
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
-	out.Networks = (*[]v1alpha6.Network)(unsafe.Pointer(in.Networks))
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = new([]v1alpha6.Network)
+		if err := Convert_Slice_v1alpha5_Network_To_Slice_v1alpha6_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Networks = nil
+	}
 	out.Subnet = in.Subnet
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Image = in.Image
@@ -550,7 +570,15 @@ func autoConvert_v1alpha5_Instance_To_v1alpha6_Instance(in *Instance, out *v1alp
 	out.UserData = in.UserData
 	out.Metadata = *(*map[string]string)(unsafe.Pointer(&in.Metadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*v1alpha6.RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(v1alpha6.RootVolume)
+		if err := Convert_v1alpha5_RootVolume_To_v1alpha6_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	out.State = v1alpha6.InstanceState(in.State)
 	out.IP = in.IP
@@ -569,7 +597,15 @@ func autoConvert_v1alpha6_Instance_To_v1alpha5_Instance(in *v1alpha6.Instance, o
 	out.Trunk = in.Trunk
 	out.FailureDomain = in.FailureDomain
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
-	out.Networks = (*[]Network)(unsafe.Pointer(in.Networks))
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = new([]Network)
+		if err := Convert_Slice_v1alpha6_Network_To_Slice_v1alpha5_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Networks = nil
+	}
 	out.Subnet = in.Subnet
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Image = in.Image
@@ -579,7 +615,15 @@ func autoConvert_v1alpha6_Instance_To_v1alpha5_Instance(in *v1alpha6.Instance, o
 	out.UserData = in.UserData
 	out.Metadata = *(*map[string]string)(unsafe.Pointer(&in.Metadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolume)
+		if err := Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	out.State = InstanceState(in.State)
 	out.IP = in.IP
@@ -625,7 +669,15 @@ func autoConvert_v1alpha5_Network_To_v1alpha6_Network(in *Network, out *v1alpha6
 	out.ID = in.ID
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Subnet = (*v1alpha6.Subnet)(unsafe.Pointer(in.Subnet))
-	out.PortOpts = (*v1alpha6.PortOpts)(unsafe.Pointer(in.PortOpts))
+	if in.PortOpts != nil {
+		in, out := &in.PortOpts, &out.PortOpts
+		*out = new(v1alpha6.PortOpts)
+		if err := Convert_v1alpha5_PortOpts_To_v1alpha6_PortOpts(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PortOpts = nil
+	}
 	out.Router = (*v1alpha6.Router)(unsafe.Pointer(in.Router))
 	out.APIServerLoadBalancer = (*v1alpha6.LoadBalancer)(unsafe.Pointer(in.APIServerLoadBalancer))
 	return nil
@@ -641,17 +693,25 @@ func autoConvert_v1alpha6_Network_To_v1alpha5_Network(in *v1alpha6.Network, out
 	out.ID = in.ID
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Subnet = (*Subnet)(unsafe.Pointer(in.Subnet))
-	out.PortOpts = (*PortOpts)(unsafe.Pointer(in.PortOpts))
+	// WARNING: in.IPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
+	// WARNING: in.MTU requires manual conversion: does not exist in peer-type
+	if in.PortOpts != nil {
+		in, out := &in.PortOpts, &out.PortOpts
+		*out = new(PortOpts)
+		if err := Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PortOpts = nil
+	}
 	out.Router = (*Router)(unsafe.Pointer(in.Router))
 	out.APIServerLoadBalancer = (*LoadBalancer)(unsafe.Pointer(in.APIServerLoadBalancer))
+	// WARNING: in.AdditionalPortsLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerVIP requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_Network_To_v1alpha5_Network is an autogenerated conversion function.
-func Convert_v1alpha6_Network_To_v1alpha5_Network(in *v1alpha6.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1alpha6_Network_To_v1alpha5_Network(in, out, s)
-}
-
 func autoConvert_v1alpha5_NetworkFilter_To_v1alpha6_NetworkFilter(in *NetworkFilter, out *v1alpha6.NetworkFilter, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Description = in.Description
@@ -815,7 +875,15 @@ func autoConvert_v1alpha5_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
-	out.Bastion = (*v1alpha6.Bastion)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(v1alpha6.Bastion)
+		if err := Convert_v1alpha5_Bastion_To_v1alpha6_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	out.IdentityRef = (*v1alpha6.OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
 }
@@ -828,13 +896,18 @@ func Convert_v1alpha5_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in *
 func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in *v1alpha6.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	out.CloudName = in.CloudName
 	out.NodeCIDR = in.NodeCIDR
+	// WARNING: in.NodeIPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSubnets requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha6_NetworkFilter_To_v1alpha5_NetworkFilter(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha6_SubnetFilter_To_v1alpha5_SubnetFilter(&in.Subnet, &out.Subnet, s); err != nil {
 		return err
 	}
+	// WARNING: in.ExternallyManagedNetwork requires manual conversion: does not exist in peer-type
 	out.DNSNameservers = *(*[]string)(unsafe.Pointer(&in.DNSNameservers))
+	// WARNING: in.Router requires manual conversion: does not exist in peer-type
+	// WARNING: in.RouterExternalGateway requires manual conversion: does not exist in peer-type
 	out.ExternalRouterIPs = *(*[]ExternalRouterIPParam)(unsafe.Pointer(&in.ExternalRouterIPs))
 	out.ExternalNetworkID = in.ExternalNetworkID
 	if err := Convert_v1alpha6_APIServerLoadBalancer_To_v1alpha5_APIServerLoadBalancer(&in.APIServerLoadBalancer, &out.APIServerLoadBalancer, s); err != nil {
@@ -842,29 +915,78 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(
 	}
 	out.DisableAPIServerFloatingIP = in.DisableAPIServerFloatingIP
 	out.APIServerFloatingIP = in.APIServerFloatingIP
+	// WARNING: in.FloatingIPPoolRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
 	out.APIServerFixedIP = in.APIServerFixedIP
+	// WARNING: in.ManagedAPIServerVIP requires manual conversion: does not exist in peer-type
 	out.APIServerPort = in.APIServerPort
 	out.ManagedSecurityGroups = in.ManagedSecurityGroups
 	out.AllowAllInClusterTraffic = in.AllowAllInClusterTraffic
+	// WARNING: in.ManagedSecurityGroupRulesPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedSecurityGroupRules requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = in.DisablePortSecurity
+	// WARNING: in.NetworkMTU requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProviderNetwork requires manual conversion: does not exist in peer-type
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.ServerMetadata requires manual conversion: does not exist in peer-type
+	// WARNING: in.MachineMetadataPropagation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceNaming requires manual conversion: does not exist in peer-type
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	// WARNING: in.ControlPlaneEndpointDNS requires manual conversion: does not exist in peer-type
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
 	// WARNING: in.ControlPlaneOmitAvailabilityZone requires manual conversion: does not exist in peer-type
-	out.Bastion = (*Bastion)(unsafe.Pointer(in.Bastion))
+	// WARNING: in.ComputeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.RootVolumeAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkAvailabilityZone requires manual conversion: does not exist in peer-type
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Bastion)
+		if err := Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
+	// WARNING: in.BlueprintExport requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
 	out.IdentityRef = (*OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
 }
 
 func autoConvert_v1alpha5_OpenStackClusterStatus_To_v1alpha6_OpenStackClusterStatus(in *OpenStackClusterStatus, out *v1alpha6.OpenStackClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
-	out.Network = (*v1alpha6.Network)(unsafe.Pointer(in.Network))
-	out.ExternalNetwork = (*v1alpha6.Network)(unsafe.Pointer(in.ExternalNetwork))
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(v1alpha6.Network)
+		if err := Convert_v1alpha5_Network_To_v1alpha6_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Network = nil
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(v1alpha6.Network)
+		if err := Convert_v1alpha5_Network_To_v1alpha6_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalNetwork = nil
+	}
 	out.FailureDomains = *(*v1beta1.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.ControlPlaneSecurityGroup = (*v1alpha6.SecurityGroup)(unsafe.Pointer(in.ControlPlaneSecurityGroup))
 	out.WorkerSecurityGroup = (*v1alpha6.SecurityGroup)(unsafe.Pointer(in.WorkerSecurityGroup))
 	out.BastionSecurityGroup = (*v1alpha6.SecurityGroup)(unsafe.Pointer(in.BastionSecurityGroup))
-	out.Bastion = (*v1alpha6.Instance)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(v1alpha6.Instance)
+		if err := Convert_v1alpha5_Instance_To_v1alpha6_Instance(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	return nil
@@ -877,23 +999,51 @@ func Convert_v1alpha5_OpenStackClusterStatus_To_v1alpha6_OpenStackClusterStatus(
 
 func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in *v1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
-	out.Network = (*Network)(unsafe.Pointer(in.Network))
-	out.ExternalNetwork = (*Network)(unsafe.Pointer(in.ExternalNetwork))
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(Network)
+		if err := Convert_v1alpha6_Network_To_v1alpha5_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Network = nil
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(Network)
+		if err := Convert_v1alpha6_Network_To_v1alpha5_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalNetwork = nil
+	}
 	out.FailureDomains = *(*v1beta1.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.ControlPlaneSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.ControlPlaneSecurityGroup))
 	out.WorkerSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.WorkerSecurityGroup))
 	out.BastionSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.BastionSecurityGroup))
-	out.Bastion = (*Instance)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Instance)
+		if err := Convert_v1alpha6_Instance_To_v1alpha5_Instance(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
+	// WARNING: in.BastionDNSRecord requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExternalAddresses requires manual conversion: does not exist in peer-type
+	// WARNING: in.FloatingIPPoolClaims requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalFloatingIPs requires manual conversion: does not exist in peer-type
+	// WARNING: in.Preflight requires manual conversion: does not exist in peer-type
+	// WARNING: in.GarbageCollection requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in *v1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(in *OpenStackClusterTemplate, out *v1alpha6.OpenStackClusterTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha5_OpenStackClusterTemplateSpec_To_v1alpha6_OpenStackClusterTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1066,7 +1216,17 @@ func Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(in *v1alpha6
 
 func autoConvert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(in *OpenStackMachineList, out *v1alpha6.OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha6.OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha6.OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_OpenStackMachine_To_v1alpha6_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1077,7 +1237,17 @@ func Convert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(in *
 
 func autoConvert_v1alpha6_OpenStackMachineList_To_v1alpha5_OpenStackMachineList(in *v1alpha6.OpenStackMachineList, out *OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1095,7 +1265,17 @@ func autoConvert_v1alpha5_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(
 	out.ImageUUID = in.ImageUUID
 	out.SSHKeyName = in.SSHKeyName
 	out.Networks = *(*[]v1alpha6.NetworkParam)(unsafe.Pointer(&in.Networks))
-	out.Ports = *(*[]v1alpha6.PortOpts)(unsafe.Pointer(&in.Ports))
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]v1alpha6.PortOpts, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_PortOpts_To_v1alpha6_PortOpts(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Ports = nil
+	}
 	out.Subnet = in.Subnet
 	out.FloatingIP = in.FloatingIP
 	out.SecurityGroups = *(*[]v1alpha6.SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
@@ -1103,7 +1283,15 @@ func autoConvert_v1alpha5_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*v1alpha6.RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(v1alpha6.RootVolume)
+		if err := Convert_v1alpha5_RootVolume_To_v1alpha6_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	out.IdentityRef = (*v1alpha6.OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
@@ -1117,31 +1305,67 @@ func Convert_v1alpha5_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in *
 func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in *v1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
+	// WARNING: in.AdoptExisting requires manual conversion: does not exist in peer-type
 	out.CloudName = in.CloudName
 	out.Flavor = in.Flavor
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
 	out.Image = in.Image
 	out.ImageUUID = in.ImageUUID
+	// WARNING: in.ImageFilter requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageChecksum requires manual conversion: does not exist in peer-type
 	out.SSHKeyName = in.SSHKeyName
+	// WARNING: in.SSHPublicKeySecretRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerPassword requires manual conversion: does not exist in peer-type
 	out.Networks = *(*[]NetworkParam)(unsafe.Pointer(&in.Networks))
-	out.Ports = *(*[]PortOpts)(unsafe.Pointer(&in.Ports))
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]PortOpts, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Ports = nil
+	}
+	// WARNING: in.AddressSortOrder requires manual conversion: does not exist in peer-type
 	out.Subnet = in.Subnet
+	// WARNING: in.ManagedSubnet requires manual conversion: does not exist in peer-type
 	out.FloatingIP = in.FloatingIP
 	out.SecurityGroups = *(*[]SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
 	out.Trunk = in.Trunk
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*RootVolume)(unsafe.Pointer(in.RootVolume))
+	// WARNING: in.Hostname requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigureSecondaryInterfaces requires manual conversion: does not exist in peer-type
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolume)
+		if err := Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
+	// WARNING: in.AdditionalBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneStorage requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeleteRemovedBlockDevices requires manual conversion: does not exist in peer-type
 	out.ServerGroupID = in.ServerGroupID
+	// WARNING: in.ServerGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.SchedulerHintAdditionalProperties requires manual conversion: does not exist in peer-type
+	// WARNING: in.Traits requires manual conversion: does not exist in peer-type
+	// WARNING: in.Reservation requires manual conversion: does not exist in peer-type
 	out.IdentityRef = (*OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotBeforeDelete requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in *v1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in, out, s)
-}
-
 func autoConvert_v1alpha5_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(in *OpenStackMachineStatus, out *v1alpha6.OpenStackMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
@@ -1161,17 +1385,20 @@ func autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineSta
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.ImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerCreateOpts requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceActionsAudit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Remediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedBlockDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.SnapshotImageID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Plan requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*v1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(in *v1alpha6.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackMachineStatus_To_v1alpha5_OpenStackMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(in *OpenStackMachineTemplate, out *v1alpha6.OpenStackMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha5_OpenStackMachineTemplateSpec_To_v1alpha6_OpenStackMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1200,7 +1427,17 @@ func Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTempl
 
 func autoConvert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(in *OpenStackMachineTemplateList, out *v1alpha6.OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha6.OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha6.OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1211,7 +1448,17 @@ func Convert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineT
 
 func autoConvert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha5_OpenStackMachineTemplateList(in *v1alpha6.OpenStackMachineTemplateList, out *OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1274,7 +1521,17 @@ func autoConvert_v1alpha5_PortOpts_To_v1alpha6_PortOpts(in *PortOpts, out *v1alp
 	out.Description = in.Description
 	out.AdminStateUp = (*bool)(unsafe.Pointer(in.AdminStateUp))
 	out.MACAddress = in.MACAddress
-	out.FixedIPs = *(*[]v1alpha6.FixedIP)(unsafe.Pointer(&in.FixedIPs))
+	if in.FixedIPs != nil {
+		in, out := &in.FixedIPs, &out.FixedIPs
+		*out = make([]v1alpha6.FixedIP, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_FixedIP_To_v1alpha6_FixedIP(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.FixedIPs = nil
+	}
 	out.TenantID = in.TenantID
 	out.ProjectID = in.ProjectID
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
@@ -1300,26 +1557,37 @@ func autoConvert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(in *v1alpha6.PortOpts, o
 	out.Description = in.Description
 	out.AdminStateUp = (*bool)(unsafe.Pointer(in.AdminStateUp))
 	out.MACAddress = in.MACAddress
-	out.FixedIPs = *(*[]FixedIP)(unsafe.Pointer(&in.FixedIPs))
+	// WARNING: in.Order requires manual conversion: does not exist in peer-type
+	if in.FixedIPs != nil {
+		in, out := &in.FixedIPs, &out.FixedIPs
+		*out = make([]FixedIP, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_FixedIP_To_v1alpha5_FixedIP(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.FixedIPs = nil
+	}
 	out.TenantID = in.TenantID
 	out.ProjectID = in.ProjectID
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
 	out.SecurityGroupFilters = *(*[]SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroupFilters))
 	out.AllowedAddressPairs = *(*[]AddressPair)(unsafe.Pointer(&in.AllowedAddressPairs))
 	out.Trunk = (*bool)(unsafe.Pointer(in.Trunk))
+	// WARNING: in.Subports requires manual conversion: does not exist in peer-type
 	out.HostID = in.HostID
 	out.VNICType = in.VNICType
 	out.Profile = *(*map[string]string)(unsafe.Pointer(&in.Profile))
+	// WARNING: in.BindingProfile requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = (*bool)(unsafe.Pointer(in.DisablePortSecurity))
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.Hints requires manual conversion: does not exist in peer-type
+	// WARNING: in.QoSPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeviceProfile requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts is an autogenerated conversion function.
-func Convert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(in *v1alpha6.PortOpts, out *PortOpts, s conversion.Scope) error {
-	return autoConvert_v1alpha6_PortOpts_To_v1alpha5_PortOpts(in, out, s)
-}
-
 func autoConvert_v1alpha5_RootVolume_To_v1alpha6_RootVolume(in *RootVolume, out *v1alpha6.RootVolume, s conversion.Scope) error {
 	out.Size = in.Size
 	out.VolumeType = in.VolumeType
@@ -1336,14 +1604,11 @@ func autoConvert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(in *v1alpha6.RootVol
 	out.Size = in.Size
 	out.VolumeType = in.VolumeType
 	out.AvailabilityZone = in.AvailabilityZone
+	// WARNING: in.CrossAZAttach requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetentionPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume is an autogenerated conversion function.
-func Convert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(in *v1alpha6.RootVolume, out *RootVolume, s conversion.Scope) error {
-	return autoConvert_v1alpha6_RootVolume_To_v1alpha5_RootVolume(in, out, s)
-}
-
 func autoConvert_v1alpha5_Router_To_v1alpha6_Router(in *Router, out *v1alpha6.Router, s conversion.Scope) error {
 	out.Name = in.Name
 	out.ID = in.ID
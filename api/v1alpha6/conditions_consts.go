@@ -26,10 +26,24 @@ const (
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// WaitingForIPAddressReason used when machine is waiting for the IP addresses of its ports to be allocated by an IPAM provider.
+	WaitingForIPAddressReason = "WaitingForIPAddress"
+	// WaitingForImageReason used when machine is waiting for the OpenStackImage it references to be ready.
+	WaitingForImageReason = "WaitingForImage"
 	// InvalidMachineSpecReason used when the machine spec is invalid.
 	InvalidMachineSpecReason = "InvalidMachineSpec"
 	// InstanceCreateFailedReason used when creating the instance failed.
 	InstanceCreateFailedReason = "InstanceCreateFailed"
+	// ImageChecksumMismatchReason used when the image of the instance does not have the checksum it is pinned to.
+	ImageChecksumMismatchReason = "ImageChecksumMismatch"
+	// VolumeAvailabilityZoneMismatchReason used when the root volume of the instance is in another availability zone than the instance.
+	VolumeAvailabilityZoneMismatchReason = "VolumeAvailabilityZoneMismatch"
+	// NoValidResourceProviderReason used when no resource provider in Placement satisfies the traits of the instance.
+	NoValidResourceProviderReason = "NoValidResourceProvider"
+	// ReservationNotActiveReason used when the Blazar lease or reservation of the instance does not exist or is not active.
+	ReservationNotActiveReason = "ReservationNotActive"
+	// InstanceAdoptionFailedReason used when the existing server to adopt was not found or does not match the spec of the machine.
+	InstanceAdoptionFailedReason = "InstanceAdoptionFailed"
 	// InstanceNotFoundReason used when the instance couldn't be retrieved.
 	InstanceNotFoundReason = "InstanceNotFound"
 	// InstanceStateErrorReason used when the instance is in error state.
@@ -40,6 +54,14 @@ const (
 	InstanceNotReadyReason = "InstanceNotReady"
 	// InstanceDeleteFailedReason used when deleting the instance failed.
 	InstanceDeleteFailedReason = "InstanceDeleteFailed"
+	// InstanceExpiredReason used on the OwnerRemediated condition of a Machine which is older than the
+	// MaxInstanceAgeAnnotation of its OpenStackMachineTemplate and is replaced by its MachineSet.
+	InstanceExpiredReason = "InstanceExpired"
+	// InPlaceRemediationFailedReason used on the OwnerRemediated condition of a Machine whose node is still not
+	// healthy after the in-place remediation of its OpenStackMachine and is replaced by its MachineSet.
+	InPlaceRemediationFailedReason = "InPlaceRemediationFailed"
+	// InstanceEvacuatingReason used when the instance is evacuated from its hypervisor, which is down.
+	InstanceEvacuatingReason = "InstanceEvacuating"
 )
 
 const (
@@ -50,4 +72,138 @@ const (
 	LoadBalancerMemberErrorReason = "LoadBalancerMemberError"
 	// FloatingIPErrorReason used when the floating ip could not be created or attached.
 	FloatingIPErrorReason = "FloatingIPError"
+	// WaitingForLoadBalancerRemovalReason used when the floating ip is still held by a load balancer which is being removed.
+	WaitingForLoadBalancerRemovalReason = "WaitingForLoadBalancerRemoval"
+)
+
+const (
+	// KeyPairReadyCondition reports on the availability of the Nova keypair referenced by the machine.
+	KeyPairReadyCondition clusterv1.ConditionType = "KeyPairReady"
+
+	// KeyPairNotFoundReason used when the keypair does not exist and cannot be re-imported.
+	KeyPairNotFoundReason = "KeyPairNotFound"
+	// KeyPairImportFailedReason used when re-importing the keypair failed.
+	KeyPairImportFailedReason = "KeyPairImportFailed"
+)
+
+const (
+	// ServerCreateOptsUpToDateCondition reports whether the server of the machine would be created with the same
+	// options from the current spec. It is not part of the Ready summary, as a changed option only takes effect
+	// when the machine is replaced.
+	ServerCreateOptsUpToDateCondition clusterv1.ConditionType = "ServerCreateOptsUpToDate"
+
+	// ServerCreateOptsChangedReason used when the current spec produces options which differ from the options the
+	// server was created with.
+	ServerCreateOptsChangedReason = "ServerCreateOptsChanged"
+)
+
+const (
+	// ServerGroupReadyCondition reports on the membership of the instance in the server group of the machine and on
+	// the policy of the server group being honoured. It is not part of the Ready summary, as the instance keeps working.
+	ServerGroupReadyCondition clusterv1.ConditionType = "ServerGroupReady"
+
+	// NotServerGroupMemberReason used when the instance is no longer a member of its server group.
+	NotServerGroupMemberReason = "NotServerGroupMember"
+	// ServerGroupPolicyViolatedReason used when the instance shares a host with another member of an anti-affinity
+	// server group, or does not share a host with the other members of an affinity server group.
+	ServerGroupPolicyViolatedReason = "ServerGroupPolicyViolated"
+)
+
+const (
+	// InstancesReadyCondition reports on the instances of an OpenStackMachinePool. Ready indicates that the desired
+	// number of instances of the current template is active.
+	InstancesReadyCondition clusterv1.ConditionType = "InstancesReady"
+
+	// InstancesScalingReason used while instances of an OpenStackMachinePool are created, replaced or deleted.
+	InstancesScalingReason = "InstancesScaling"
+)
+
+const (
+	// ImageReadyCondition reports on the Glance image of an OpenStackImage. Ready indicates that the image is active.
+	ImageReadyCondition clusterv1.ConditionType = "ImageReady"
+
+	// ImageImportingReason used while the data of the image is imported.
+	ImageImportingReason = "ImageImporting"
+	// ImageCreateFailedReason used when creating the image or importing its data failed.
+	ImageCreateFailedReason = "ImageCreateFailed"
+	// ImageDeleteFailedReason used when deleting the image failed.
+	ImageDeleteFailedReason = "ImageDeleteFailed"
+)
+
+const (
+	// PortsReadyCondition reports on the ports of the instance of a machine, which are created before the instance.
+	PortsReadyCondition clusterv1.ConditionType = "PortsReady"
+	// ServerCreatedCondition reports on the creation of the server of a machine. Unlike InstanceReady it is true
+	// as soon as the server exists, whatever its state.
+	ServerCreatedCondition clusterv1.ConditionType = "ServerCreated"
+	// FloatingIPReadyCondition reports on the floating IP of a control plane machine which holds the control plane
+	// endpoint. It is only set if the endpoint is a floating IP.
+	FloatingIPReadyCondition clusterv1.ConditionType = "FloatingIPReady"
+
+	// PortCreateFailedReason used when creating the ports of the instance failed.
+	PortCreateFailedReason = "PortCreateFailed"
+)
+
+const (
+	// NetworkReadyCondition reports on the external network and the network of a cluster.
+	NetworkReadyCondition clusterv1.ConditionType = "NetworkReady"
+	// SubnetsReadyCondition reports on the subnets of the network of a cluster.
+	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
+	// RouterReadyCondition reports on the router of a cluster. It is only set if the network of the cluster is managed.
+	RouterReadyCondition clusterv1.ConditionType = "RouterReady"
+	// SecurityGroupsReadyCondition reports on the managed security groups of a cluster. It is only set if
+	// ManagedSecurityGroups is enabled.
+	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
+	// LoadBalancerReadyCondition reports on the API server load balancer of a cluster. It is only set if the load
+	// balancer is enabled, or while it is removed.
+	LoadBalancerReadyCondition clusterv1.ConditionType = "LoadBalancerReady"
+	// APIServerVIPReadyCondition reports on the managed VIP port of the API server of a cluster. It is only set if
+	// ManagedAPIServerVIP is enabled.
+	APIServerVIPReadyCondition clusterv1.ConditionType = "APIServerVIPReady"
+	// BastionReadyCondition reports on the bastion of a cluster. It is only set if the bastion is enabled.
+	BastionReadyCondition clusterv1.ConditionType = "BastionReady"
+	// OpenStackAPIUpToDateCondition reports whether the OpenStack services of the cloud of a cluster returned
+	// deprecation warnings to CAPO since it started. It is only set once warnings were returned, and is not part of
+	// the Ready summary.
+	OpenStackAPIUpToDateCondition clusterv1.ConditionType = "OpenStackAPIUpToDate"
+	// OpenStackResourcesUpToDateCondition reports whether the controller would change OpenStack resources of a cluster
+	// or machine in plan mode. It is only set while the PlanAnnotation is set on the cluster, and is not part of the
+	// Ready summary.
+	OpenStackResourcesUpToDateCondition clusterv1.ConditionType = "OpenStackResourcesUpToDate"
+
+	// NetworkReconcileFailedReason used when reconciling or looking up the network failed.
+	NetworkReconcileFailedReason = "NetworkReconcileFailed"
+	// SubnetsReconcileFailedReason used when reconciling or looking up the subnets failed.
+	SubnetsReconcileFailedReason = "SubnetsReconcileFailed"
+	// RouterReconcileFailedReason used when reconciling the router failed.
+	RouterReconcileFailedReason = "RouterReconcileFailed"
+	// SecurityGroupsReconcileFailedReason used when reconciling the security groups failed.
+	SecurityGroupsReconcileFailedReason = "SecurityGroupsReconcileFailed"
+	// LoadBalancerReconcileFailedReason used when reconciling or removing the load balancer failed.
+	LoadBalancerReconcileFailedReason = "LoadBalancerReconcileFailed"
+	// APIServerVIPReconcileFailedReason used when reconciling the VIP port of the API server failed.
+	APIServerVIPReconcileFailedReason = "APIServerVIPReconcileFailed"
+	// OpenStackAPIDeprecatedReason used when an OpenStack service returned a Warning, Deprecation or Sunset header.
+	OpenStackAPIDeprecatedReason = "OpenStackAPIDeprecated"
+	// BastionReconcileFailedReason used when reconciling or deleting the bastion failed.
+	BastionReconcileFailedReason = "BastionReconcileFailed"
+	// ChangesPlannedReason used in plan mode when the controller would change OpenStack resources.
+	ChangesPlannedReason = "ChangesPlanned"
+)
+
+// The reasons of the conditions of clusters and machines when OpenStack rejected a request. They take precedence
+// over the reasons of the individual conditions, and the message of the condition is the error returned by OpenStack.
+const (
+	// ResourceNotFoundReason used when a resource referenced by the spec does not exist.
+	ResourceNotFoundReason = "ResourceNotFound"
+	// ForbiddenReason used when the policy of the cloud does not allow a request.
+	ForbiddenReason = "Forbidden"
+	// InvalidRequestReason used when OpenStack rejected a request as invalid.
+	InvalidRequestReason = "InvalidRequest"
+	// QuotaExceededReason used when a request would exceed the quota of the project.
+	QuotaExceededReason = "QuotaExceeded"
+	// ResourceBusyReason used when a resource is in use, or in a transitional state which does not allow changes.
+	ResourceBusyReason = "ResourceBusy"
+	// OpenStackUnavailableReason used when an OpenStack service failed to handle a request.
+	OpenStackUnavailableReason = "OpenStackUnavailable"
 )
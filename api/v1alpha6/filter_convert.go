@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha6
 
 import (
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 )
@@ -51,3 +52,16 @@ func (networkFilter NetworkFilter) ToListOpt() networks.ListOpts {
 		NotTagsAny:  networkFilter.NotTagsAny,
 	}
 }
+
+func (routerFilter RouterFilter) ToListOpt() routers.ListOpts {
+	return routers.ListOpts{
+		Name:        routerFilter.Name,
+		Description: routerFilter.Description,
+		ProjectID:   routerFilter.ProjectID,
+		ID:          routerFilter.ID,
+		Tags:        routerFilter.Tags,
+		TagsAny:     routerFilter.TagsAny,
+		NotTags:     routerFilter.NotTags,
+		NotTagsAny:  routerFilter.NotTagsAny,
+	}
+}
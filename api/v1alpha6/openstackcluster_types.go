@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha6
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -26,6 +27,24 @@ const (
 	// ClusterFinalizer allows ReconcileOpenStackCluster to clean up OpenStack resources associated with OpenStackCluster before
 	// removing it from the apiserver.
 	ClusterFinalizer = "openstackcluster.infrastructure.cluster.x-k8s.io"
+
+	// PreflightAnnotation requests the preflight checks to be run against the cloud of the OpenStackCluster.
+	// The report is written to the status and the annotation is removed once the checks have run.
+	PreflightAnnotation = "infrastructure.cluster.x-k8s.io/preflight"
+
+	// PlanAnnotation puts the OpenStackCluster and its machines in plan mode. Instead of changing OpenStack
+	// resources, the controllers record the operations they would perform in the plan of their status. The
+	// resources are reconciled again once the annotation is removed.
+	PlanAnnotation = "infrastructure.cluster.x-k8s.io/plan"
+
+	// StorageVersionAnnotation records the API version an OpenStackCluster or OpenStackMachine was last written in.
+	// Objects without it may still be stored in an older API version and are rewritten through conversion
+	// before they are reconciled.
+	StorageVersionAnnotation = "infrastructure.cluster.x-k8s.io/storage-version"
+
+	// OpenStackAPIDebugAnnotation set to "true" logs the OpenStack API requests and responses made for the
+	// OpenStackCluster and its machines, with credentials and tokens redacted.
+	OpenStackAPIDebugAnnotation = "infrastructure.cluster.x-k8s.io/openstack-api-debug"
 )
 
 // OpenStackClusterSpec defines the desired state of OpenStackCluster.
@@ -39,19 +58,61 @@ type OpenStackClusterSpec struct {
 	// If you leave this empty, no network will be created.
 	NodeCIDR string `json:"nodeCidr,omitempty"`
 
+	// NodeIPv6Subnet is an IPv6 subnet to be created in the cluster network in addition
+	// to the IPv4 subnet with NodeCIDR, which makes the cluster network dual-stack.
+	// It can only be set together with NodeCIDR.
+	// +optional
+	NodeIPv6Subnet *IPv6SubnetOptions `json:"nodeIPv6Subnet,omitempty"`
+
+	// ManagedSubnets are additional subnets to be created in the cluster network and
+	// connected to its router. Machines select one of them with managedSubnet.
+	// They can only be set together with NodeCIDR. Subnets can be added, but not
+	// removed, and only their host routes and service types can be changed.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ManagedSubnets []ManagedSubnet `json:"managedSubnets,omitempty"`
+
 	// If NodeCIDR cannot be set this can be used to detect an existing network.
 	Network NetworkFilter `json:"network,omitempty"`
 
 	// If NodeCIDR cannot be set this can be used to detect an existing subnet.
 	Subnet SubnetFilter `json:"subnet,omitempty"`
 
+	// ExternallyManagedNetwork treats the network, subnet, router, security groups and
+	// API server load balancer of the cluster as pre-existing and read-only, e.g. if
+	// Neutron is owned by another team. CAPO looks them up with Network, Subnet, Router
+	// and ExistingLoadBalancer and attaches the machines to them, but never creates,
+	// changes or deletes them, and fails if any of them cannot be found. It requires
+	// Network and Subnet, cannot be set together with NodeCIDR or ManagedSecurityGroups,
+	// and cannot be changed.
+	// +optional
+	ExternallyManagedNetwork bool `json:"externallyManagedNetwork,omitempty"`
+
 	// DNSNameservers is the list of nameservers for OpenStack Subnet being created.
 	// Set this value when you need create a new network/subnet while the access
 	// through DNS is required.
 	// +listType=set
 	DNSNameservers []string `json:"dnsNameservers,omitempty"`
+	// Router is an existing router the subnets of the cluster network are connected
+	// to, instead of a router created by CAPO. The router must match exactly one
+	// router. CAPO removes the interfaces of the subnets from the router when the
+	// cluster is deleted, but never changes the gateway of the router or deletes it.
+	// It can only be set together with NodeCIDR or ExternallyManagedNetwork, which
+	// only looks the router up.
+	// +optional
+	Router *RouterFilter `json:"router,omitempty"`
+
+	// RouterExternalGateway configures the external gateway of the router created by
+	// CAPO. It is applied to the existing router when it is changed. It cannot be set
+	// together with Router.
+	// +optional
+	RouterExternalGateway *RouterExternalGateway `json:"routerExternalGateway,omitempty"`
+
 	// ExternalRouterIPs is an array of externalIPs on the respective subnets.
-	// This is necessary if the router needs a fixed ip in a specific subnet.
+	// This is necessary if the router needs a fixed ip in a specific subnet,
+	// e.g. to allow-list the SNAT addresses of the cluster. It cannot be set
+	// together with Router.
 	ExternalRouterIPs []ExternalRouterIPParam `json:"externalRouterIPs,omitempty"`
 	// ExternalNetworkID is the ID of an external OpenStack Network. This is necessary
 	// to get public internet to the VMs.
@@ -84,6 +145,22 @@ type OpenStackClusterSpec struct {
 	// This field is not used if DisableAPIServerFloatingIP is set to true.
 	APIServerFloatingIP string `json:"apiServerFloatingIP,omitempty"`
 
+	// FloatingIPPoolRef references an OpenStackFloatingIPPool in the namespace of the
+	// cluster. If the API server or bastion floating IP is not specified, an address is
+	// claimed from the pool before falling back to allocating a new floating IP.
+	// Claimed addresses are returned to the pool when the cluster is deleted.
+	// +optional
+	FloatingIPPoolRef *corev1.LocalObjectReference `json:"floatingIPPoolRef,omitempty"`
+
+	// AdditionalFloatingIPs are floating IPs which CAPO allocates on the external
+	// network for the cluster, e.g. for an ingress controller or a VPN gateway
+	// in the cluster. They are not associated with any port, are published in
+	// the status and are deleted when they are removed or the cluster is deleted.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalFloatingIPs []AdditionalFloatingIP `json:"additionalFloatingIPs,omitempty"`
+
 	// APIServerFixedIP is the fixed IP which will be associated with the API server.
 	// In the case where the API server has a floating IP but not a managed load balancer,
 	// this field is not used.
@@ -94,6 +171,18 @@ type OpenStackClusterSpec struct {
 	// holds the fixed IP to be used as a VIP.
 	APIServerFixedIP string `json:"apiServerFixedIP,omitempty"`
 
+	// ManagedAPIServerVIP makes CAPO manage the VIP of the API server without
+	// a load balancer, e.g. on clouds without Octavia. CAPO creates a port on
+	// the cluster network whose fixed IP is the VIP, which is APIServerFixedIP
+	// if set, and adds the VIP to the allowed address pairs of the ports of
+	// the control plane machines on the cluster network. The floating IP of
+	// the API server is associated with the port unless
+	// DisableAPIServerFloatingIP is set. The VIP must be announced by software
+	// on the control plane machines, e.g. kube-vip or keepalived. It requires
+	// APIServerLoadBalancer to be disabled.
+	// +optional
+	ManagedAPIServerVIP bool `json:"managedAPIServerVIP,omitempty"`
+
 	// APIServerPort is the port on which the listener on the APIServer
 	// will be created
 	APIServerPort int `json:"apiServerPort,omitempty"`
@@ -113,18 +202,75 @@ type OpenStackClusterSpec struct {
 	// +optional
 	AllowAllInClusterTraffic bool `json:"allowAllInClusterTraffic"`
 
+	// ManagedSecurityGroupRulesPolicy determines how the rules of the managed security
+	// groups are reconciled. With Replace, the default, rules which are not managed by
+	// the OpenStack provider are deleted. With Merge, only rules created by the OpenStack
+	// provider are deleted or replaced, and rules added by operators are left intact.
+	// +optional
+	ManagedSecurityGroupRulesPolicy SecurityGroupRulesPolicy `json:"managedSecurityGroupRulesPolicy,omitempty"`
+
+	// ManagedSecurityGroupRules are rules which are added to the managed security groups,
+	// for example for a CNI plugin other than Calico. They are only used when managed
+	// security groups are in use.
+	// +optional
+	ManagedSecurityGroupRules *ManagedSecurityGroupRules `json:"managedSecurityGroupRules,omitempty"`
+
 	// DisablePortSecurity disables the port security of the network created for the
 	// Kubernetes cluster, which also disables SecurityGroups
 	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
 
+	// NetworkMTU is the MTU of the network created for the Kubernetes cluster. If it
+	// is not set, the network gets the default MTU of Neutron. Changes are applied to
+	// the existing network. It can only be set together with NodeCIDR.
+	// +kubebuilder:validation:Minimum=68
+	// +optional
+	NetworkMTU int `json:"networkMtu,omitempty"`
+
+	// ProviderNetwork creates the network of the Kubernetes cluster as a flat or VLAN
+	// provider network, which requires admin rights. The nodes are directly routable
+	// on the provider network, so no router is created for the cluster and no floating
+	// IPs are used. It can only be set together with NodeCIDR and
+	// DisableAPIServerFloatingIP, and cannot be changed.
+	// +optional
+	ProviderNetwork *ProviderNetwork `json:"providerNetwork,omitempty"`
+
 	// Tags for all resources in cluster
 	// +listType=set
 	Tags []string `json:"tags,omitempty"`
 
+	// ServerMetadata is added to the metadata of all servers of the cluster,
+	// including the bastion. Keys set in the serverMetadata of a machine take
+	// precedence. Changes are applied to existing servers.
+	// +optional
+	ServerMetadata map[string]string `json:"serverMetadata,omitempty"`
+
+	// MachineMetadataPropagation selects labels and annotations of the Machines of
+	// the cluster which are mirrored into the metadata of their servers and kept in
+	// sync. Changes are applied to existing servers.
+	// +optional
+	MachineMetadataPropagation *MachineMetadataPropagation `json:"machineMetadataPropagation,omitempty"`
+
+	// SnapshotBeforeDelete is the default snapshotBeforeDelete of the
+	// machines of the cluster, excluding the bastion. Changes apply to the
+	// machines which are deleted afterwards.
+	// +optional
+	SnapshotBeforeDelete *SnapshotBeforeDelete `json:"snapshotBeforeDelete,omitempty"`
+
+	// ResourceNaming overrides the naming pattern of OpenStack resources
+	// created for the cluster. It cannot be changed after creation.
+	// +optional
+	ResourceNaming *ResourceNaming `json:"resourceNaming,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
 
+	// ControlPlaneEndpointDNS configures a record in Designate pointing at the API server
+	// floating IP or load balancer VIP. If set, the host of the control plane endpoint is
+	// the name of the record.
+	// +optional
+	ControlPlaneEndpointDNS *ControlPlaneEndpointDNS `json:"controlPlaneEndpointDNS,omitempty"`
+
 	// ControlPlaneAvailabilityZones is the az to deploy control plane to
 	// +listType=set
 	ControlPlaneAvailabilityZones []string `json:"controlPlaneAvailabilityZones,omitempty"`
@@ -133,6 +279,25 @@ type OpenStackClusterSpec struct {
 	// to make a decision on which az to use based on other scheduling constraints
 	ControlPlaneOmitAvailabilityZone bool `json:"controlPlaneOmitAvailabilityZone,omitempty"`
 
+	// ComputeAvailabilityZone is the Nova availability zone of the machines which have no
+	// failure domain, and of the bastion if it has no availability zone. If not set, Nova
+	// chooses the availability zone of these machines.
+	// +optional
+	ComputeAvailabilityZone string `json:"computeAvailabilityZone,omitempty"`
+
+	// RootVolumeAvailabilityZone is the Cinder availability zone of the root volumes which
+	// do not set their own availability zone. If not set, a root volume is created in the
+	// availability zone of its instance, so that only the availability zones which also
+	// exist in Cinder are reported as failure domains.
+	// +optional
+	RootVolumeAvailabilityZone string `json:"rootVolumeAvailabilityZone,omitempty"`
+
+	// NetworkAvailabilityZone is the Neutron availability zone hint of the cluster network
+	// and router. It requires NodeCIDR to be set. If not set, Neutron chooses the
+	// availability zone.
+	// +optional
+	NetworkAvailabilityZone string `json:"networkAvailabilityZone,omitempty"`
+
 	// Bastion is the OpenStack instance to login the nodes
 	//
 	// As a rolling update is not ideal during a bastion host session, we
@@ -141,6 +306,18 @@ type OpenStackClusterSpec struct {
 	//+optional
 	Bastion *Bastion `json:"bastion,omitempty"`
 
+	// BlueprintExport exports the blueprint of the cluster, from which disaster recovery tooling can re-create
+	// equivalent infrastructure in another region. The blueprint is updated whenever the resources of the cluster
+	// change.
+	// +optional
+	BlueprintExport *BlueprintExport `json:"blueprintExport,omitempty"`
+
+	// GarbageCollection periodically deletes OpenStack resources of the cluster which are
+	// not referenced by any object, e.g. ports or volumes left behind by a controller which
+	// crashed while creating a machine.
+	// +optional
+	GarbageCollection *GarbageCollection `json:"garbageCollection,omitempty"`
+
 	// IdentityRef is a reference to a identity to be used when reconciling this cluster
 	// +optional
 	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
@@ -173,6 +350,50 @@ type OpenStackClusterStatus struct {
 
 	Bastion *Instance `json:"bastion,omitempty"`
 
+	// BastionDNSRecord is the record in Designate pointing at the floating IP of the
+	// bastion. It is only set if spec.bastion.dns is set.
+	// +optional
+	BastionDNSRecord *DNSRecordReference `json:"bastionDNSRecord,omitempty"`
+
+	// APIServerAddress is the IP address the DNS record of the control plane endpoint
+	// points at. It is only set if spec.controlPlaneEndpointDNS is set.
+	// +optional
+	APIServerAddress string `json:"apiServerAddress,omitempty"`
+
+	// ExternalAddresses lists the addresses of the cluster which are reachable from
+	// outside of the cluster network: the VIP and floating IP of the API server, the
+	// floating IP of the bastion, the external IPs of the router and the additional
+	// floating IPs.
+	// +optional
+	ExternalAddresses []ClusterAddress `json:"externalAddresses,omitempty"`
+
+	// FloatingIPPoolClaims are the addresses claimed from the floating IP pool of the
+	// cluster. They are disassociated instead of deleted when no longer used.
+	// +optional
+	FloatingIPPoolClaims []FloatingIPClaim `json:"floatingIPPoolClaims,omitempty"`
+
+	// AdditionalFloatingIPs are the floating IPs allocated for the additional
+	// floating IPs in the spec of the cluster.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalFloatingIPs []AdditionalFloatingIPStatus `json:"additionalFloatingIPs,omitempty"`
+
+	// Preflight contains the report of the last preflight check of the cluster.
+	// The checks are run when the PreflightAnnotation is set on the OpenStackCluster.
+	// +optional
+	Preflight *PreflightReport `json:"preflight,omitempty"`
+
+	// GarbageCollection reports the last run of the garbage collection of orphaned
+	// resources and the orphans it found. It is only set if spec.garbageCollection is set.
+	// +optional
+	GarbageCollection *GarbageCollectionStatus `json:"garbageCollection,omitempty"`
+
+	// Plan contains the operations on the OpenStack resources of the cluster which
+	// the controller would perform. It is only set while the PlanAnnotation is set.
+	// +optional
+	Plan *Plan `json:"plan,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the OpenStackCluster and will contain a succinct value suitable
 	// for machine interpretation.
@@ -210,6 +431,64 @@ type OpenStackClusterStatus struct {
 	// and/or logged in the controller's output.
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions report on the network, subnets, router, security groups, load balancer and bastion of the cluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ClusterAddressType is the kind of an address of the cluster.
+type ClusterAddressType string
+
+const (
+	APIServerVIPAddress              ClusterAddressType = "APIServerVIP"
+	APIServerFloatingIPAddress       ClusterAddressType = "APIServerFloatingIP"
+	AdditionalPortsVIPAddress        ClusterAddressType = "AdditionalPortsVIP"
+	AdditionalPortsFloatingIPAddress ClusterAddressType = "AdditionalPortsFloatingIP"
+	BastionFloatingIPAddress         ClusterAddressType = "BastionFloatingIP"
+	RouterExternalIPAddress          ClusterAddressType = "RouterExternalIP"
+	AdditionalFloatingIPAddress      ClusterAddressType = "AdditionalFloatingIP"
+)
+
+// ClusterAddress is an address of the cluster.
+type ClusterAddress struct {
+	// Type is the kind of the address, e.g. APIServerFloatingIP.
+	Type ClusterAddressType `json:"type"`
+	// Address is the IP address.
+	Address string `json:"address"`
+}
+
+// PreflightCheckResult is the result of a single preflight check.
+type PreflightCheckResult string
+
+const (
+	PreflightCheckPassed  PreflightCheckResult = "Passed"
+	PreflightCheckWarning PreflightCheckResult = "Warning"
+	PreflightCheckFailed  PreflightCheckResult = "Failed"
+	PreflightCheckSkipped PreflightCheckResult = "Skipped"
+)
+
+// PreflightReport is the structured result of checking an OpenStack cloud
+// against the spec of an OpenStackCluster.
+type PreflightReport struct {
+	// Time is when the checks were run.
+	Time metav1.Time `json:"time"`
+	// Passed is true if none of the checks failed.
+	Passed bool `json:"passed"`
+	// Checks contains the result of each check.
+	// +optional
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// PreflightCheck is the result of a single preflight check.
+type PreflightCheck struct {
+	// Name identifies the check, e.g. "ComputeQuota".
+	Name string `json:"name"`
+	// Result is the result of the check.
+	Result PreflightCheckResult `json:"result"`
+	// Message describes the result of the check.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -242,6 +521,16 @@ type OpenStackClusterList struct {
 	Items           []OpenStackCluster `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the OpenStackCluster resource.
+func (r *OpenStackCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackCluster to the predescribed clusterv1.Conditions.
+func (r *OpenStackCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenStackCluster{}, &OpenStackClusterList{})
 }
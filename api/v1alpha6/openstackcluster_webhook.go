@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha6
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -24,10 +25,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/topology"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // log is for logging in this package.
@@ -36,6 +39,7 @@ var _ = logf.Log.WithName("openstackcluster-resource")
 func (r *OpenStackCluster) SetupWebhookWithManager(mgr manager.Manager) error {
 	return builder.WebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&openStackClusterValidator{}).
 		Complete()
 }
 
@@ -43,15 +47,59 @@ func (r *OpenStackCluster) SetupWebhookWithManager(mgr manager.Manager) error {
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackcluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,versions=v1alpha6,name=default.openstackcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Defaulter = &OpenStackCluster{}
-	_ webhook.Validator = &OpenStackCluster{}
+	_ webhook.Defaulter       = &OpenStackCluster{}
+	_ webhook.Validator       = &OpenStackCluster{}
+	_ webhook.CustomValidator = &openStackClusterValidator{}
 )
 
+// openStackClusterValidator validates OpenStackClusters with the admission request at hand, which is needed
+// to recognise the dry-run requests of the Cluster topology controller.
+// +kubebuilder:object:generate=false
+type openStackClusterValidator struct{}
+
 // Default satisfies the defaulting webhook interface.
 func (r *OpenStackCluster) Default() {
-	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
-		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
+	defaultOpenStackClusterSpec(&r.Spec)
+	setStorageVersion(&r.ObjectMeta)
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *openStackClusterValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	openStackCluster, ok := obj.(*OpenStackCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", obj))
+	}
+	return openStackCluster.ValidateCreate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+// The topology controller computes its changes with a dry-run of the full intent of the ClusterClass,
+// which must not be rejected for immutable fields, so the immutability checks are skipped for it.
+func (v *openStackClusterValidator) ValidateUpdate(ctx context.Context, oldRaw runtime.Object, newRaw runtime.Object) error {
+	old, ok := oldRaw.(*OpenStackCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", oldRaw))
+	}
+	newObj, ok := newRaw.(*OpenStackCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", newRaw))
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
 	}
+
+	return newObj.validateUpdate(old, topology.ShouldSkipImmutabilityChecks(req, newObj))
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *openStackClusterValidator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	openStackCluster, ok := obj.(*OpenStackCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", obj))
+	}
+	return openStackCluster.ValidateDelete()
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -62,16 +110,70 @@ func (r *OpenStackCluster) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
+	if r.Spec.APIServerLoadBalancer.FlavorID != "" && r.Spec.APIServerLoadBalancer.FlavorName != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "apiServerLoadBalancer", "flavorName"), "cannot be set together with flavorID"))
+	}
+
+	allErrs = append(allErrs, validateResourceNaming(r.Spec.ResourceNaming, field.NewPath("spec", "resourceNaming"))...)
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(r.Spec.SnapshotBeforeDelete, field.NewPath("spec", "snapshotBeforeDelete"))...)
+	allErrs = append(allErrs, validateHealthMonitor(r.Spec.APIServerLoadBalancer.HealthMonitor, field.NewPath("spec", "apiServerLoadBalancer", "healthMonitor"))...)
+	allErrs = append(allErrs, validateExistingLoadBalancer(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateSharedLoadBalancer(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateListenerSettings(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateLoadBalancerTLS(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateAdditionalPortsFlavor(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateAdditionalPorts(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateControlPlaneEndpointDNS(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateIPv6(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateManagedSubnets(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRouter(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRouterExternalGateway(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateExternallyManagedNetwork(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateManagedAPIServerVIP(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateNetworkMTU(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateProviderNetwork(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateManagedSecurityGroupRules(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateAvailabilityZones(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateMachineMetadataPropagation(r.Spec.MachineMetadataPropagation, field.NewPath("spec", "machineMetadataPropagation"))...)
+	allErrs = append(allErrs, validateBlueprintExport(r.Spec.BlueprintExport, field.NewPath("spec", "blueprintExport"))...)
+	allErrs = append(allErrs, validateGarbageCollection(r.Spec.GarbageCollection, field.NewPath("spec", "garbageCollection"))...)
+	if r.Spec.Bastion != nil {
+		allErrs = append(allErrs, validateIPAddressPoolRefs(r.Spec.Bastion.Instance.Ports, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateServerPassword(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateAdoptExisting(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortBindingProfiles(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortSecurity(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortFixedIPs(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateSchedulerHints(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateAdditionalBlockDevices(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateImageFilter(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateImageRef(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateConfigureSecondaryInterfaces(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateRegion(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateTraits(r.Spec.Bastion.Instance.Traits, field.NewPath("spec", "bastion", "instance", "traits"))...)
+		allErrs = append(allErrs, validateSecurityGroupParams(r.Spec.Bastion.Instance.SecurityGroups, field.NewPath("spec", "bastion", "instance", "securityGroups"))...)
+		allErrs = append(allErrs, validateBastionDNS(r.Spec.Bastion.DNS, field.NewPath("spec", "bastion", "dns"))...)
+		if r.Spec.Bastion.Enabled {
+			allErrs = append(allErrs, validateFlavor(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		}
+	}
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (r *OpenStackCluster) ValidateUpdate(oldRaw runtime.Object) error {
-	var allErrs field.ErrorList
 	old, ok := oldRaw.(*OpenStackCluster)
 	if !ok {
 		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", oldRaw))
 	}
+	return r.validateUpdate(old, false)
+}
+
+// validateUpdate validates an update of the OpenStackCluster. With skipImmutabilityChecks, changes of
+// immutable fields are accepted, while the new values are still validated.
+func (r *OpenStackCluster) validateUpdate(old *OpenStackCluster, skipImmutabilityChecks bool) error {
+	var allErrs field.ErrorList
 
 	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
 		allErrs = append(allErrs,
@@ -92,7 +194,7 @@ func (r *OpenStackCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		r.Spec.IdentityRef = &OpenStackIdentityReference{}
 	}
 
-	if old.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
+	if !skipImmutabilityChecks && old.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "identityRef"),
 				r.Spec.IdentityRef, "field cannot be set to nil"),
@@ -105,7 +207,43 @@ func (r *OpenStackCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		r.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{}
 	}
 
+	// The load balancer, managed subnets and external gateway can be changed, but must stay
+	// read-only for an externally managed network.
+	allErrs = append(allErrs, validateExternallyManagedNetwork(&r.Spec, field.NewPath("spec"))...)
+	// The load balancer cannot be enabled for a managed VIP.
+	allErrs = append(allErrs, validateManagedAPIServerVIP(&r.Spec, field.NewPath("spec"))...)
+	// The router and floating IPs cannot be added to a cluster on a provider network.
+	allErrs = append(allErrs, validateProviderNetwork(&r.Spec, field.NewPath("spec"))...)
+
+	// Allow changes to the compute and root volume availability zones, which apply to new machines.
+	// The availability zone of the network cannot be changed once the network is created.
+	allErrs = append(allErrs, validateAvailabilityZones(&r.Spec, field.NewPath("spec"))...)
+	old.Spec.ComputeAvailabilityZone = ""
+	r.Spec.ComputeAvailabilityZone = ""
+	old.Spec.RootVolumeAvailabilityZone = ""
+	r.Spec.RootVolumeAvailabilityZone = ""
+
 	// Allow changes to the bastion spec.
+	if r.Spec.Bastion != nil {
+		allErrs = append(allErrs, validateIPAddressPoolRefs(r.Spec.Bastion.Instance.Ports, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateServerPassword(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateAdoptExisting(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortBindingProfiles(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortSecurity(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validatePortFixedIPs(r.Spec.Bastion.Instance.Ports, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateSchedulerHints(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateAdditionalBlockDevices(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateImageFilter(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateImageRef(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateConfigureSecondaryInterfaces(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateRegion(&r.Spec.Bastion.Instance, false, field.NewPath("spec", "bastion", "instance"))...)
+		allErrs = append(allErrs, validateTraits(r.Spec.Bastion.Instance.Traits, field.NewPath("spec", "bastion", "instance", "traits"))...)
+		allErrs = append(allErrs, validateSecurityGroupParams(r.Spec.Bastion.Instance.SecurityGroups, field.NewPath("spec", "bastion", "instance", "securityGroups"))...)
+		allErrs = append(allErrs, validateBastionDNS(r.Spec.Bastion.DNS, field.NewPath("spec", "bastion", "dns"))...)
+		if r.Spec.Bastion.Enabled {
+			allErrs = append(allErrs, validateFlavor(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+		}
+	}
 	old.Spec.Bastion = &Bastion{}
 	r.Spec.Bastion = &Bastion{}
 
@@ -115,7 +253,81 @@ func (r *OpenStackCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		r.Spec.APIServerLoadBalancer.AllowedCIDRs = []string{}
 	}
 
-	if !reflect.DeepEqual(old.Spec, r.Spec) {
+	// Allow switching between a load balancer and a fixed endpoint. The controller creates or
+	// removes the load balancer and moves the endpoint address.
+	if old.Spec.APIServerLoadBalancer.Enabled != r.Spec.APIServerLoadBalancer.Enabled {
+		allErrs = append(allErrs, validateLoadBalancerSwitch(&old.Spec, &r.Spec, field.NewPath("spec"))...)
+		old.Spec.APIServerLoadBalancer = APIServerLoadBalancer{}
+		r.Spec.APIServerLoadBalancer = APIServerLoadBalancer{}
+	}
+
+	// Allow adding managed subnets, which are created and connected to the router.
+	allErrs = append(allErrs, validateManagedSubnets(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateManagedSubnetsUpdate(&old.Spec, &r.Spec, field.NewPath("spec"))...)
+	old.Spec.ManagedSubnets = nil
+	r.Spec.ManagedSubnets = nil
+
+	// Allow adding and removing additional floating IPs, which are allocated and deleted.
+	allErrs = append(allErrs, validateAdditionalFloatingIPsUpdate(&old.Spec, &r.Spec, field.NewPath("spec"))...)
+	old.Spec.AdditionalFloatingIPs = nil
+	r.Spec.AdditionalFloatingIPs = nil
+
+	// Allow changes to the external gateway, which are applied to the existing router.
+	allErrs = append(allErrs, validateRouterExternalGateway(&r.Spec, field.NewPath("spec"))...)
+	old.Spec.RouterExternalGateway = nil
+	r.Spec.RouterExternalGateway = nil
+
+	// Allow changes to the MTU, which are applied to the existing network.
+	allErrs = append(allErrs, validateNetworkMTU(&r.Spec, field.NewPath("spec"))...)
+	old.Spec.NetworkMTU = 0
+	r.Spec.NetworkMTU = 0
+
+	// Allow changes to the user-defined security group rules, which are applied to the existing groups.
+	allErrs = append(allErrs, validateManagedSecurityGroupRules(&r.Spec, field.NewPath("spec"))...)
+	old.Spec.ManagedSecurityGroupRules = nil
+	r.Spec.ManagedSecurityGroupRules = nil
+
+	// Allow changes to the server metadata, which are applied to existing servers.
+	old.Spec.ServerMetadata = nil
+	r.Spec.ServerMetadata = nil
+
+	// Allow changes to the mirrored labels and annotations, which are applied to existing servers.
+	allErrs = append(allErrs, validateMachineMetadataPropagation(r.Spec.MachineMetadataPropagation, field.NewPath("spec", "machineMetadataPropagation"))...)
+	old.Spec.MachineMetadataPropagation = nil
+	r.Spec.MachineMetadataPropagation = nil
+
+	// Allow changes to the snapshot before delete, which applies to machines deleted afterwards.
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(r.Spec.SnapshotBeforeDelete, field.NewPath("spec", "snapshotBeforeDelete"))...)
+	old.Spec.SnapshotBeforeDelete = nil
+	r.Spec.SnapshotBeforeDelete = nil
+
+	// Allow changes to the blueprint export, which is exported again.
+	allErrs = append(allErrs, validateBlueprintExport(r.Spec.BlueprintExport, field.NewPath("spec", "blueprintExport"))...)
+	old.Spec.BlueprintExport = nil
+	r.Spec.BlueprintExport = nil
+
+	// Allow changes to the garbage collection, which apply to its next run.
+	allErrs = append(allErrs, validateGarbageCollection(r.Spec.GarbageCollection, field.NewPath("spec", "garbageCollection"))...)
+	old.Spec.GarbageCollection = nil
+	r.Spec.GarbageCollection = nil
+
+	// Allow changes to the health monitor, which are applied to the existing monitors.
+	allErrs = append(allErrs, validateHealthMonitor(r.Spec.APIServerLoadBalancer.HealthMonitor, field.NewPath("spec", "apiServerLoadBalancer", "healthMonitor"))...)
+	old.Spec.APIServerLoadBalancer.HealthMonitor = nil
+	r.Spec.APIServerLoadBalancer.HealthMonitor = nil
+
+	// Allow changes to the connection limit and timeouts, which are applied to the existing listeners.
+	allErrs = append(allErrs, validateListenerSettings(&r.Spec.APIServerLoadBalancer, field.NewPath("spec", "apiServerLoadBalancer"))...)
+	old.Spec.APIServerLoadBalancer.ConnectionLimit = 0
+	r.Spec.APIServerLoadBalancer.ConnectionLimit = 0
+	old.Spec.APIServerLoadBalancer.TimeoutClientData = 0
+	r.Spec.APIServerLoadBalancer.TimeoutClientData = 0
+	old.Spec.APIServerLoadBalancer.TimeoutMemberData = 0
+	r.Spec.APIServerLoadBalancer.TimeoutMemberData = 0
+	old.Spec.APIServerLoadBalancer.TimeoutMemberConnect = 0
+	r.Spec.APIServerLoadBalancer.TimeoutMemberConnect = 0
+
+	if !skipImmutabilityChecks && !reflect.DeepEqual(old.Spec, r.Spec) {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
 
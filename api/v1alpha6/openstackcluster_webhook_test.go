@@ -17,9 +17,17 @@ limitations under the License.
 package v1alpha6
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestOpenStackCluster_ValidateUpdate(t *testing.T) {
@@ -175,6 +183,402 @@ func TestOpenStackCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Adding OpenStackCluster.Spec.ManagedSubnets is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage},
+						{Name: "workers", CIDR: "10.8.0.0/24", Role: SubnetRoleWorkers},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.NetworkMTU is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:  "foobar",
+					NodeCIDR:   "10.6.0.0/24",
+					NetworkMTU: 8950,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ComputeAvailabilityZone and RootVolumeAvailabilityZone is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					ComputeAvailabilityZone:    "az1",
+					RootVolumeAvailabilityZone: "nova",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.NetworkAvailabilityZone is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:               "foobar",
+					NodeCIDR:                "10.6.0.0/24",
+					NetworkAvailabilityZone: "az1",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:               "foobar",
+					NodeCIDR:                "10.6.0.0/24",
+					NetworkAvailabilityZone: "az2",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.RouterExternalGateway is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterExternalGateway: &RouterExternalGateway{
+						EnableSNAT: pointer.Bool(false),
+						QoSPolicy:  "egress-limit",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.MachineMetadataPropagation is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					MachineMetadataPropagation: &MachineMetadataPropagation{
+						Labels: []string{"node-role.kubernetes.io/worker"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ServerMetadata is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					ServerMetadata: map[string]string{"team": "a"},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					ServerMetadata: map[string]string{"team": "b", "cost-center": "1234"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Adding and removing OpenStackCluster.Spec.AdditionalFloatingIPs is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					AdditionalFloatingIPs: []AdditionalFloatingIP{
+						{Name: "vpn", Description: "VPN gateway"},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					AdditionalFloatingIPs: []AdditionalFloatingIP{
+						{Name: "ingress", Description: "Ingress controller"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.AdditionalFloatingIPs is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					AdditionalFloatingIPs: []AdditionalFloatingIP{
+						{Name: "ingress"},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					AdditionalFloatingIPs: []AdditionalFloatingIP{
+						{Name: "ingress", FloatingIP: "192.0.2.10"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing the host routes and service types of OpenStackCluster.Spec.ManagedSubnets is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{
+							Name:         "storage",
+							CIDR:         "10.7.0.0/24",
+							Role:         SubnetRoleStorage,
+							HostRoutes:   []HostRoute{{Destination: "192.168.0.0/16", NextHop: "10.7.0.254"}},
+							ServiceTypes: []string{"compute:nova"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ManagedSubnets is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage, DNSNameservers: []string{"10.0.0.53"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Removing OpenStackCluster.Spec.ManagedSubnets is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", Role: SubnetRoleStorage},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.APIServerLoadBalancer.HealthMonitor is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						HealthMonitor: &HealthMonitor{
+							Type:    HealthMonitorTypeHTTPS,
+							Delay:   10,
+							URLPath: "/readyz",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.APIServerLoadBalancer listener timeouts is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:           true,
+						TimeoutClientData: 50000,
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:           true,
+						ConnectionLimit:   1000,
+						TimeoutClientData: 600000,
+						TimeoutMemberData: 600000,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Setting OpenStackCluster.Spec.APIServerLoadBalancer listener timeouts with an existing load balancer is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{ID: "lb-id"},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{ID: "lb-id"},
+						TimeoutClientData:    600000,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Enabling OpenStackCluster.Spec.APIServerLoadBalancer with a floating IP is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:            "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "203.0.113.10", Port: 6443},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:            "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "203.0.113.10", Port: 6443},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:  true,
+						Provider: "amphora",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Disabling OpenStackCluster.Spec.APIServerLoadBalancer with a fixed IP is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.10",
+					ControlPlaneEndpoint:       clusterv1.APIEndpoint{Host: "10.6.0.10", Port: 6443},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.10",
+					ControlPlaneEndpoint:       clusterv1.APIEndpoint{Host: "10.6.0.10", Port: 6443},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Disabling OpenStackCluster.Spec.APIServerLoadBalancer without a floating or fixed IP is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					DisableAPIServerFloatingIP: true,
+					ControlPlaneEndpoint:       clusterv1.APIEndpoint{Host: "10.6.0.10", Port: 6443},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					DisableAPIServerFloatingIP: true,
+					ControlPlaneEndpoint:       clusterv1.APIEndpoint{Host: "10.6.0.10", Port: 6443},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Disabling a shared OpenStackCluster.Spec.APIServerLoadBalancer is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:            "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "cluster.example.com", Port: 443},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						Shared: &SharedLoadBalancer{
+							LoadBalancer: LoadBalancerReference{Name: "shared"},
+							Hostname:     "cluster.example.com",
+							Port:         443,
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:            "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "cluster.example.com", Port: 443},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -188,6 +592,97 @@ func TestOpenStackCluster_ValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestOpenStackClusterValidator_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldCluster := &OpenStackCluster{
+		Spec: OpenStackClusterSpec{
+			CloudName: "foobar",
+			NodeCIDR:  "10.6.0.0/24",
+		},
+	}
+	newCluster := func(annotations map[string]string, managedSecurityGroupRules *ManagedSecurityGroupRules) *OpenStackCluster {
+		return &OpenStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec: OpenStackClusterSpec{
+				CloudName:                 "foobar",
+				NodeCIDR:                  "10.7.0.0/24",
+				ManagedSecurityGroupRules: managedSecurityGroupRules,
+			},
+		}
+	}
+	dryRunAnnotations := map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+
+	tests := []struct {
+		name       string
+		newCluster *OpenStackCluster
+		req        *admission.Request
+		wantErr    bool
+	}{
+		{
+			name:       "Changing an immutable field is not allowed",
+			newCluster: newCluster(nil, nil),
+			req:        &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(false)}},
+			wantErr:    true,
+		},
+		{
+			name:       "Changing an immutable field in a dry-run is not allowed",
+			newCluster: newCluster(nil, nil),
+			req:        &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+			wantErr:    true,
+		},
+		{
+			name:       "Changing an immutable field in a dry-run of the topology controller is allowed",
+			newCluster: newCluster(dryRunAnnotations, nil),
+			req:        &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+		},
+		{
+			name:       "Dry-runs of the topology controller still validate the new values",
+			newCluster: newCluster(dryRunAnnotations, &ManagedSecurityGroupRules{}),
+			req:        &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &openStackClusterValidator{}
+			ctx := admission.NewContextWithRequest(context.Background(), *tt.req)
+
+			err := validator.ValidateUpdate(ctx, oldCluster.DeepCopy(), tt.newCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestOpenStackCluster_Default(t *testing.T) {
+	g := NewWithT(t)
+
+	template := &OpenStackClusterTemplate{
+		Spec: OpenStackClusterTemplateSpec{
+			Template: OpenStackClusterTemplateResource{
+				Spec: OpenStackClusterSpec{
+					CloudName:   "foobar",
+					IdentityRef: &OpenStackIdentityReference{Name: "foobar"},
+				},
+			},
+		},
+	}
+	template.Default()
+
+	// The topology controller computes the cluster from the defaulted template
+	cluster := &OpenStackCluster{Spec: *template.Spec.Template.Spec.DeepCopy()}
+	cluster.Default()
+	g.Expect(cluster.Spec).To(Equal(template.Spec.Template.Spec))
+
+	defaulted := cluster.DeepCopy()
+	cluster.Default()
+	g.Expect(cluster).To(Equal(defaulted))
+}
+
 func TestOpenStackCluster_ValidateCreate(t *testing.T) {
 	g := NewWithT(t)
 
@@ -197,26 +692,1166 @@ func TestOpenStackCluster_ValidateCreate(t *testing.T) {
 		wantErr  bool
 	}{
 		{
-			name: "OpenStackCluster.Spec.IdentityRef with correct spec on create",
+			name: "OpenStackCluster.Spec.IdentityRef with correct spec on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.IdentityRef with faulty spec on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "foobar",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with valid templates on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						Network:  "{{ .Namespace }}-{{ .ClusterName }}-net",
+						Listener: "{{ .ClusterName }}-listener-{{ .Port }}",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with invalid template on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						LoadBalancer: "{{ .ClusterName",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with an unknown field on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						Network: "{{ .Cluster }}-net",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with a template which renders an empty name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						Router: "{{ if false }}router{{ end }}",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with a listener template without the port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						Listener: "{{ .ClusterName }}-listener",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ResourceNaming with a pool template without the port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ResourceNaming: &ResourceNaming{
+						Pool: "{{ .ClusterName }}-pool",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer with flavorID and flavorName on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:    true,
+						FlavorID:   "foobar",
+						FlavorName: "foobar",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.HealthMonitor with timeout not less than delay on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						HealthMonitor: &HealthMonitor{
+							Delay:   5,
+							Timeout: 5,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.HealthMonitor with urlPath on a TCP monitor on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						HealthMonitor: &HealthMonitor{
+							URLPath: "/healthz",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with ID on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{ID: "foobar"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with ID and name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{ID: "foobar", Name: "foobar"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with load balancer disabled on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "foobar"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with listener timeouts on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "foobar"},
+						TimeoutClientData:    3600000,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer listener timeouts with the ovn provider on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:           true,
+						Provider:          "ovn",
+						TimeoutClientData: 3600000,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer connection limit with the ovn provider on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						Provider:        "ovn",
+						ConnectionLimit: 1000,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with availabilityZone on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "foobar"},
+						AvailabilityZone:     "az1",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.Shared on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						Shared: &SharedLoadBalancer{
+							LoadBalancer: LoadBalancerReference{Name: "foobar"},
+							Port:         443,
+							Hostname:     "api.foobar.example.com",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.Shared without hostname on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						Shared: &SharedLoadBalancer{
+							LoadBalancer: LoadBalancerReference{Name: "foobar"},
+							Port:         443,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.Shared with additional ports on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						AdditionalPorts: []int{8443},
+						Shared: &SharedLoadBalancer{
+							LoadBalancer: LoadBalancerReference{Name: "foobar"},
+							Port:         443,
+							Hostname:     "api.foobar.example.com",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer with provider on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						Provider:             "amphora",
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "foobar"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.TLS with certificate name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						TLS:     &LoadBalancerTLS{CertificateName: "foobar"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.TLS with certificate ref and name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						TLS: &LoadBalancerTLS{
+							CertificateRef:  "https://barbican.example.com/v1/containers/foobar",
+							CertificateName: "foobar",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.TLS with existing load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "foobar"},
+						TLS:                  &LoadBalancerTLS{CertificateName: "foobar"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						AdditionalPorts: []int{22623, 8132},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts with a duplicate port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						AdditionalPorts: []int{22623, 22623},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts with the API server port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:     "foobar",
+					APIServerPort: 8443,
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						AdditionalPorts: []int{22623, 8443},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts with an invalid port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:         true,
+						AdditionalPorts: []int{65536},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:               true,
+						AdditionalPorts:       []int{443},
+						AdditionalPortsFlavor: &LoadBalancerFlavor{Name: "single"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor without additional ports on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:               true,
+						AdditionalPortsFlavor: &LoadBalancerFlavor{Name: "single"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor with ID and name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:               true,
+						AdditionalPorts:       []int{443},
+						AdditionalPortsFlavor: &LoadBalancerFlavor{ID: "foobar", Name: "single"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor with existing load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:               true,
+						AdditionalPorts:       []int{443},
+						ExistingLoadBalancer:  &LoadBalancerReference{Name: "foobar"},
+						AdditionalPortsFlavor: &LoadBalancerFlavor{Name: "single"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ControlPlaneEndpointDNS on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNS{
+						Zone:       "example.com.",
+						RecordName: "api.foobar.example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ControlPlaneEndpointDNS with record outside of the zone on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNS{
+						Zone:       "example.com",
+						RecordName: "api.foobar.example.org",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ControlPlaneEndpointDNS with control plane endpoint on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{
+						Host: "api.foobar.example.com",
+						Port: 6443,
+					},
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNS{
+						Zone:       "example.com",
+						RecordName: "api.foobar.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion.DNS with record name template on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						DNS: &BastionDNS{
+							Zone:       "example.com",
+							RecordName: "{{ .ClusterName }}.bastion.example.com.",
+							TTL:        60,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion.DNS without zone on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						DNS: &BastionDNS{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion.DNS with record outside of the zone on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						DNS: &BastionDNS{
+							Zone:       "example.com",
+							RecordName: "{{ .ClusterName }}.example.org",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion.DNS with invalid record name template on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						DNS: &BastionDNS{
+							Zone:       "example.com",
+							RecordName: "{{ .ClusterName }.example.com",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.NodeIPv6Subnet with an IPv6 VIP on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					NodeIPv6Subnet:             &IPv6SubnetOptions{CIDR: "2001:db8:6::/64"},
+					DisableAPIServerFloatingIP: true,
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:   true,
+						IPVersion: 6,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.NodeIPv6Subnet with an IPv4 CIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					NodeCIDR:       "10.6.0.0/24",
+					NodeIPv6Subnet: &IPv6SubnetOptions{CIDR: "10.7.0.0/24"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.NodeIPv6Subnet without NodeCIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					NodeIPv6Subnet: &IPv6SubnetOptions{CIDR: "2001:db8:6::/64"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSubnets on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{
+							Name:            "storage",
+							CIDR:            "10.7.0.0/24",
+							Role:            SubnetRoleStorage,
+							GatewayIP:       "10.7.0.254",
+							AllocationPools: []AllocationPool{{Start: "10.7.0.10", End: "10.7.0.200"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSubnets with an invalid host route on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{
+							Name:       "storage",
+							CIDR:       "10.7.0.0/24",
+							HostRoutes: []HostRoute{{Destination: "192.168.0.0", NextHop: "10.7.0.254"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSubnets without NodeCIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSubnets with an allocation pool outside of the CIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "storage", CIDR: "10.7.0.0/24", AllocationPools: []AllocationPool{{Start: "10.7.0.10", End: "10.8.0.10"}}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSubnets with a reserved name on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ManagedSubnets: []ManagedSubnet{
+						{Name: "ipv6", CIDR: "10.7.0.0/24"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Router on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					Router:    &RouterFilter{Name: "shared-router"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.Router without a filter on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					Router:    &RouterFilter{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Router with external router IPs on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					Router:    &RouterFilter{Name: "shared-router"},
+					ExternalRouterIPs: []ExternalRouterIPParam{
+						{FixedIP: "203.0.113.10", Subnet: SubnetParam{UUID: "external-subnet"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternallyManagedNetwork on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                "foobar",
+					ExternallyManagedNetwork: true,
+					Network:                  NetworkFilter{Name: "shared-network"},
+					Subnet:                   SubnetFilter{Name: "shared-subnet"},
+					Router:                   &RouterFilter{Name: "shared-router"},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &LoadBalancerReference{Name: "shared-lb"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternallyManagedNetwork without a network filter on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                "foobar",
+					ExternallyManagedNetwork: true,
+					Subnet:                   SubnetFilter{Name: "shared-subnet"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternallyManagedNetwork with managed security groups on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                "foobar",
+					ExternallyManagedNetwork: true,
+					Network:                  NetworkFilter{Name: "shared-network"},
+					Subnet:                   SubnetFilter{Name: "shared-subnet"},
+					ManagedSecurityGroups:    true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternallyManagedNetwork with a managed load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                "foobar",
+					ExternallyManagedNetwork: true,
+					Network:                  NetworkFilter{Name: "shared-network"},
+					Subnet:                   SubnetFilter{Name: "shared-subnet"},
+					APIServerLoadBalancer:    APIServerLoadBalancer{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedAPIServerVIP on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					ManagedAPIServerVIP:        true,
+					APIServerFixedIP:           "10.6.0.10",
+					DisableAPIServerFloatingIP: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedAPIServerVIP with a load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedAPIServerVIP:   true,
+					APIServerLoadBalancer: APIServerLoadBalancer{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedAPIServerVIP with an invalid fixed IP on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:           "foobar",
+					ManagedAPIServerVIP: true,
+					APIServerFixedIP:    "10.6.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.NetworkMTU on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:  "foobar",
+					NodeCIDR:   "10.6.0.0/24",
+					NetworkMTU: 8950,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.NetworkMTU without NodeCIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:  "foobar",
+					NetworkMTU: 8950,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ProviderNetwork on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.10",
+					ProviderNetwork: &ProviderNetwork{
+						PhysicalNetwork: "physnet1",
+						NetworkType:     ProviderNetworkTypeVLAN,
+						SegmentationID:  100,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ProviderNetwork with a segmentation ID for a flat network on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.10",
+					ProviderNetwork: &ProviderNetwork{
+						PhysicalNetwork: "physnet1",
+						NetworkType:     ProviderNetworkTypeFlat,
+						SegmentationID:  100,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ProviderNetwork with the API server floating IP on create",
 			template: &OpenStackCluster{
 				Spec: OpenStackClusterSpec{
 					CloudName: "foobar",
-					IdentityRef: &OpenStackIdentityReference{
-						Kind: "Secret",
-						Name: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					ProviderNetwork: &ProviderNetwork{
+						PhysicalNetwork: "physnet1",
+						NetworkType:     ProviderNetworkTypeFlat,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ProviderNetwork with a router on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.10",
+					Router:                     &RouterFilter{Name: "foobar"},
+					ProviderNetwork: &ProviderNetwork{
+						PhysicalNetwork: "physnet1",
+						NetworkType:     ProviderNetworkTypeFlat,
 					},
 				},
 			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec availability zones on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					NodeCIDR:                   "10.6.0.0/24",
+					ComputeAvailabilityZone:    "az1",
+					RootVolumeAvailabilityZone: "nova",
+					NetworkAvailabilityZone:    "az1",
+				},
+			},
 			wantErr: false,
 		},
 		{
-			name: "OpenStackCluster.Spec.IdentityRef with faulty spec on create",
+			name: "OpenStackCluster.Spec.NetworkAvailabilityZone without NodeCIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:               "foobar",
+					NetworkAvailabilityZone: "az1",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RootVolumeAvailabilityZone differs from the bastion availability zone on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					ComputeAvailabilityZone:    "az1",
+					RootVolumeAvailabilityZone: "nova",
+					Bastion: &Bastion{
+						Enabled: true,
+						Instance: OpenStackMachineSpec{
+							Flavor:     "m1.small",
+							RootVolume: &RootVolume{Size: 20},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RootVolumeAvailabilityZone differs from the bastion availability zone with crossAZAttach on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					ComputeAvailabilityZone:    "az1",
+					RootVolumeAvailabilityZone: "nova",
+					Bastion: &Bastion{
+						Enabled: true,
+						Instance: OpenStackMachineSpec{
+							Flavor:     "m1.small",
+							RootVolume: &RootVolume{Size: 20, CrossAZAttach: true},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterExternalGateway on create",
 			template: &OpenStackCluster{
 				Spec: OpenStackClusterSpec{
 					CloudName: "foobar",
-					IdentityRef: &OpenStackIdentityReference{
-						Kind: "foobar",
-						Name: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterExternalGateway: &RouterExternalGateway{
+						EnableSNAT: pointer.Bool(false),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterExternalGateway with Router on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					Router:    &RouterFilter{Name: "shared-router"},
+					RouterExternalGateway: &RouterExternalGateway{
+						EnableSNAT: pointer.Bool(false),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.MachineMetadataPropagation on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					MachineMetadataPropagation: &MachineMetadataPropagation{
+						Labels:      []string{"node-role.kubernetes.io/worker"},
+						Annotations: []string{"example.com/owner"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.MachineMetadataPropagation with an invalid key on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					MachineMetadataPropagation: &MachineMetadataPropagation{
+						Labels: []string{"example.com/rack/row"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerLoadBalancer with an IPv6 VIP and a floating IP on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					NodeCIDR:       "10.6.0.0/24",
+					NodeIPv6Subnet: &IPv6SubnetOptions{CIDR: "2001:db8:6::/64"},
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled:   true,
+						IPVersion: 6,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion with a fixed IP claimed from an IPAM pool on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Enabled: true,
+						Instance: OpenStackMachineSpec{
+							Ports: []PortOpts{
+								{
+									FixedIPs: []FixedIP{
+										{
+											IPAddressPoolRef: &corev1.TypedLocalObjectReference{
+												APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+												Kind:     "InClusterIPPool",
+												Name:     "pool",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: true,
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						AllNodesSecurityGroupRules: []SecurityGroupRuleSpec{
+							{
+								Direction:           "ingress",
+								Protocol:            "udp",
+								PortRangeMin:        8472,
+								RemoteManagedGroups: []ManagedSecurityGroupName{ManagedSecurityGroupControlPlane, ManagedSecurityGroupWorker},
+							},
+						},
+						Worker: []SecurityGroupRuleSpec{
+							{
+								Direction:      "ingress",
+								Protocol:       "tcp",
+								PortRangeMin:   30000,
+								PortRangeMax:   32767,
+								RemoteIPPrefix: "2001:db8::/32",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules without ManagedSecurityGroups on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						ControlPlane: []SecurityGroupRuleSpec{{Direction: "ingress"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules with a port range but no protocol on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: true,
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						ControlPlane: []SecurityGroupRuleSpec{{Direction: "ingress", PortRangeMin: 179}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules with remoteIPPrefix and remoteManagedGroups on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: true,
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						Worker: []SecurityGroupRuleSpec{
+							{
+								Direction:           "ingress",
+								RemoteIPPrefix:      "10.0.0.0/8",
+								RemoteManagedGroups: []ManagedSecurityGroupName{ManagedSecurityGroupWorker},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules with an empty remote security group on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: true,
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						Worker: []SecurityGroupRuleSpec{
+							{
+								Direction:            "ingress",
+								RemoteSecurityGroups: []SecurityGroupParam{{}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ManagedSecurityGroupRules with an etherType not matching remoteIPPrefix on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: true,
+					ManagedSecurityGroupRules: &ManagedSecurityGroupRules{
+						Worker: []SecurityGroupRuleSpec{
+							{
+								Direction:      "ingress",
+								EtherType:      "IPv6",
+								RemoteIPPrefix: "10.0.0.0/8",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.GarbageCollection on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					GarbageCollection: &GarbageCollection{
+						DryRun:      true,
+						GracePeriod: &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.GarbageCollection with a zero interval on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					GarbageCollection: &GarbageCollection{
+						Interval: &metav1.Duration{},
 					},
 				},
 			},
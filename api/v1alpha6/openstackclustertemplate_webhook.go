@@ -17,14 +17,17 @@ limitations under the License.
 package v1alpha6
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 const openStackClusterTemplateImmutableMsg = "OpenStackClusterTemplate spec.template.spec field is immutable. Please create new resource instead."
@@ -32,6 +35,7 @@ const openStackClusterTemplateImmutableMsg = "OpenStackClusterTemplate spec.temp
 func (r *OpenStackClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&openStackClusterTemplateValidator{}).
 		Complete()
 }
 
@@ -39,15 +43,58 @@ func (r *OpenStackClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) err
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclustertemplates,versions=v1alpha6,name=validation.openstackclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Defaulter = &OpenStackClusterTemplate{}
-	_ webhook.Validator = &OpenStackClusterTemplate{}
+	_ webhook.Defaulter       = &OpenStackClusterTemplate{}
+	_ webhook.Validator       = &OpenStackClusterTemplate{}
+	_ webhook.CustomValidator = &openStackClusterTemplateValidator{}
 )
 
+// openStackClusterTemplateValidator validates OpenStackClusterTemplates with the admission request at hand,
+// which is needed to recognise the dry-run requests of the Cluster topology controller.
+// +kubebuilder:object:generate=false
+type openStackClusterTemplateValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *openStackClusterTemplateValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	openStackClusterTemplate, ok := obj.(*OpenStackClusterTemplate)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", obj))
+	}
+	return openStackClusterTemplate.ValidateCreate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+// Templates referenced by a ClusterClass are rotated rather than changed, but the topology controller
+// still computes its changes with dry-runs which must not be rejected for the immutable spec.
+func (v *openStackClusterTemplateValidator) ValidateUpdate(ctx context.Context, oldRaw runtime.Object, newRaw runtime.Object) error {
+	old, ok := oldRaw.(*OpenStackClusterTemplate)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", oldRaw))
+	}
+	newObj, ok := newRaw.(*OpenStackClusterTemplate)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", newRaw))
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+	}
+
+	return newObj.validateUpdate(old, topology.ShouldSkipImmutabilityChecks(req, newObj))
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *openStackClusterTemplateValidator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	openStackClusterTemplate, ok := obj.(*OpenStackClusterTemplate)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", obj))
+	}
+	return openStackClusterTemplate.ValidateDelete()
+}
+
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
 func (r *OpenStackClusterTemplate) Default() {
-	if r.Spec.Template.Spec.IdentityRef != nil && r.Spec.Template.Spec.IdentityRef.Kind == "" {
-		r.Spec.Template.Spec.IdentityRef.Kind = defaultIdentityRefKind
-	}
+	defaultOpenStackClusterSpec(&r.Spec.Template.Spec)
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -58,18 +105,43 @@ func (r *OpenStackClusterTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
+	if r.Spec.Template.Spec.APIServerLoadBalancer.FlavorID != "" && r.Spec.Template.Spec.APIServerLoadBalancer.FlavorName != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "apiServerLoadBalancer", "flavorName"), "cannot be set together with flavorID"))
+	}
+
+	allErrs = append(allErrs, validateResourceNaming(r.Spec.Template.Spec.ResourceNaming, field.NewPath("spec", "template", "spec", "resourceNaming"))...)
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(r.Spec.Template.Spec.SnapshotBeforeDelete, field.NewPath("spec", "template", "spec", "snapshotBeforeDelete"))...)
+	allErrs = append(allErrs, validateBlueprintExport(r.Spec.Template.Spec.BlueprintExport, field.NewPath("spec", "template", "spec", "blueprintExport"))...)
+	allErrs = append(allErrs, validateGarbageCollection(r.Spec.Template.Spec.GarbageCollection, field.NewPath("spec", "template", "spec", "garbageCollection"))...)
+	allErrs = append(allErrs, validateHealthMonitor(r.Spec.Template.Spec.APIServerLoadBalancer.HealthMonitor, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer", "healthMonitor"))...)
+	allErrs = append(allErrs, validateExistingLoadBalancer(&r.Spec.Template.Spec.APIServerLoadBalancer, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateSharedLoadBalancer(&r.Spec.Template.Spec.APIServerLoadBalancer, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateListenerSettings(&r.Spec.Template.Spec.APIServerLoadBalancer, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateLoadBalancerTLS(&r.Spec.Template.Spec.APIServerLoadBalancer, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateAdditionalPortsFlavor(&r.Spec.Template.Spec.APIServerLoadBalancer, field.NewPath("spec", "template", "spec", "apiServerLoadBalancer"))...)
+	allErrs = append(allErrs, validateAdditionalPorts(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateControlPlaneEndpointDNS(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateManagedAPIServerVIP(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateProviderNetwork(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (r *OpenStackClusterTemplate) ValidateUpdate(oldRaw runtime.Object) error {
-	var allErrs field.ErrorList
 	old, ok := oldRaw.(*OpenStackClusterTemplate)
 	if !ok {
 		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", oldRaw))
 	}
+	return r.validateUpdate(old, false)
+}
+
+// validateUpdate validates an update of the OpenStackClusterTemplate. With skipImmutabilityChecks, changes
+// of the template are accepted.
+func (r *OpenStackClusterTemplate) validateUpdate(old *OpenStackClusterTemplate, skipImmutabilityChecks bool) error {
+	var allErrs field.ErrorList
 
-	if !reflect.DeepEqual(r.Spec.Template.Spec, old.Spec.Template.Spec) {
+	if !skipImmutabilityChecks && !reflect.DeepEqual(r.Spec.Template.Spec, old.Spec.Template.Spec) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("OpenStackClusterTemplate", "spec", "template", "spec"), r, openStackClusterTemplateImmutableMsg),
 		)
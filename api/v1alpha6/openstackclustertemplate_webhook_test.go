@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestOpenStackClusterTemplateValidator_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := &OpenStackClusterTemplate{
+		Spec: OpenStackClusterTemplateSpec{
+			Template: OpenStackClusterTemplateResource{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+		},
+	}
+	newTemplate := func(annotations map[string]string, nodeCIDR string) *OpenStackClusterTemplate {
+		template := oldTemplate.DeepCopy()
+		template.Annotations = annotations
+		template.Spec.Template.Spec.NodeCIDR = nodeCIDR
+		return template
+	}
+	dryRunAnnotations := map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+
+	tests := []struct {
+		name        string
+		newTemplate *OpenStackClusterTemplate
+		req         *admission.Request
+		wantErr     bool
+	}{
+		{
+			name:        "Changing only the metadata is allowed",
+			newTemplate: newTemplate(map[string]string{"foo": "bar"}, "10.6.0.0/24"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(false)}},
+		},
+		{
+			name:        "Changing the template is not allowed",
+			newTemplate: newTemplate(nil, "10.7.0.0/24"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(false)}},
+			wantErr:     true,
+		},
+		{
+			name:        "Changing the template in a dry-run is not allowed",
+			newTemplate: newTemplate(nil, "10.7.0.0/24"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+			wantErr:     true,
+		},
+		{
+			name:        "Changing the template in a dry-run of the topology controller is allowed",
+			newTemplate: newTemplate(dryRunAnnotations, "10.7.0.0/24"),
+			req:         &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &openStackClusterTemplateValidator{}
+			ctx := admission.NewContextWithRequest(context.Background(), *tt.req)
+
+			err := validator.ValidateUpdate(ctx, oldTemplate.DeepCopy(), tt.newTemplate)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestOpenStackClusterTemplate_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := &OpenStackClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "foobar"},
+		Spec: OpenStackClusterTemplateSpec{
+			Template: OpenStackClusterTemplateResource{
+				Spec: OpenStackClusterSpec{CloudName: "foobar"},
+			},
+		},
+	}
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.Spec.Template.Spec.CloudName = "barfoo"
+
+	g.Expect(newTemplate.ValidateUpdate(oldTemplate)).NotTo(Succeed())
+	g.Expect(oldTemplate.DeepCopy().ValidateUpdate(oldTemplate)).To(Succeed())
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenStackFloatingIPPoolSpec defines the desired state of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolSpec struct {
+	// Addresses are pre-allocated floating IPs in the project of the clusters
+	// using the pool. They are associated with the API servers and bastions of
+	// the clusters instead of allocating new floating IPs.
+	// +kubebuilder:validation:MinItems=1
+	Addresses []string `json:"addresses"`
+}
+
+// OpenStackFloatingIPPoolStatus defines the observed state of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolStatus struct {
+	// Claims are the addresses of the pool which are in use.
+	// +optional
+	Claims []FloatingIPClaim `json:"claims,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:storageversion
+//+kubebuilder:resource:path=openstackfloatingippools,scope=Namespaced,categories=cluster-api,shortName=osfip
+//+kubebuilder:subresource:status
+
+// OpenStackFloatingIPPool is the Schema for the openstackfloatingippools API.
+type OpenStackFloatingIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackFloatingIPPoolSpec   `json:"spec,omitempty"`
+	Status OpenStackFloatingIPPoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpenStackFloatingIPPoolList contains a list of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackFloatingIPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackFloatingIPPool{}, &OpenStackFloatingIPPoolList{})
+}
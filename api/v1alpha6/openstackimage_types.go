@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ImageFinalizer allows ReconcileOpenStackImage to delete the Glance image before removing the
+	// OpenStackImage from the apiserver.
+	ImageFinalizer = "openstackimage.infrastructure.cluster.x-k8s.io"
+)
+
+// ImageImportMethod is how the data of an OpenStackImage is imported into Glance.
+// +kubebuilder:validation:Enum=web-download;upload
+type ImageImportMethod string
+
+const (
+	// ImageImportMethodWebDownload lets Glance download the data from the URL with the
+	// web-download import method.
+	ImageImportMethodWebDownload ImageImportMethod = "web-download"
+	// ImageImportMethodUpload downloads the data from the URL in the controller and
+	// streams it to Glance, for clouds without the web-download import method or
+	// which cannot reach the URL.
+	ImageImportMethodUpload ImageImportMethod = "upload"
+)
+
+// OpenStackImageSpec defines the desired state of OpenStackImage.
+type OpenStackImageSpec struct {
+	// CloudName is the name of the cloud in the clouds.yaml of the IdentityRef.
+	CloudName string `json:"cloudName"`
+
+	// IdentityRef is a reference to the identity used to manage the image.
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+
+	// Name is the name of the Glance image. Defaults to the name of the OpenStackImage.
+	// An image with the name in the project is adopted instead of creating a new one.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// URL is the HTTP(S) URL the data of the image is imported from.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// ImportMethod is how the data is imported. Defaults to web-download.
+	// +kubebuilder:default=web-download
+	// +optional
+	ImportMethod ImageImportMethod `json:"importMethod,omitempty"`
+
+	// DiskFormat is the disk format of the image, e.g. qcow2 or raw.
+	DiskFormat string `json:"diskFormat"`
+
+	// ContainerFormat is the container format of the image. Defaults to bare.
+	// +kubebuilder:default=bare
+	// +optional
+	ContainerFormat string `json:"containerFormat,omitempty"`
+
+	// Visibility is the visibility of the image. Defaults to private.
+	// +kubebuilder:validation:Enum=public;private;shared;community
+	// +kubebuilder:default=private
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// Tags are the tags of the image.
+	// +listType=set
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Properties are the properties of the image, e.g. hw_disk_bus. They are
+	// kept up to date, but properties removed from the spec are not removed
+	// from the image.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// Prewarm caches the image on the compute hosts of availability zones once it is
+	// active, so that the first machine created from it in each zone does not wait for
+	// the image to be downloaded.
+	// +optional
+	Prewarm *ImagePrewarm `json:"prewarm,omitempty"`
+}
+
+// ImagePrewarm boots a throwaway server without network from the image in each
+// availability zone, and deletes it as soon as it is active.
+type ImagePrewarm struct {
+	// AvailabilityZones are the availability zones the image is cached in.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	AvailabilityZones []string `json:"availabilityZones"`
+
+	// Flavor is the name of the flavor of the throwaway servers. The smallest flavor
+	// whose root disk fits the image keeps the servers cheap.
+	Flavor string `json:"flavor"`
+}
+
+// OpenStackImageStatus defines the observed state of OpenStackImage.
+type OpenStackImageStatus struct {
+	// Ready is true when the image is active.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// ImageID is the ID of the Glance image.
+	// +optional
+	ImageID string `json:"imageID,omitempty"`
+
+	// Status is the status of the Glance image.
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// PrewarmedAvailabilityZones are the availability zones of spec.prewarm the image
+	// has been cached in.
+	// +optional
+	PrewarmedAvailabilityZones []string `json:"prewarmedAvailabilityZones,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackimages,scope=Namespaced,categories=cluster-api,shortName=osimg
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Image ready status"
+// +kubebuilder:printcolumn:name="ImageID",type="string",JSONPath=".status.imageID",description="Glance image ID"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.status",description="Glance image status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackImage"
+
+// OpenStackImage is the Schema for the openstackimages API.
+type OpenStackImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackImageSpec   `json:"spec,omitempty"`
+	Status OpenStackImageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackImageList contains a list of OpenStackImage.
+type OpenStackImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackImage `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackImage resource.
+func (r *OpenStackImage) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackImage to the predescribed clusterv1.Conditions.
+func (r *OpenStackImage) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackImage{}, &OpenStackImageList{})
+}
@@ -37,12 +37,27 @@ type OpenStackMachineSpec struct {
 	// InstanceID is the OpenStack instance ID for this machine.
 	InstanceID *string `json:"instanceID,omitempty"`
 
+	// AdoptExisting selects an existing server which is adopted by the machine
+	// instead of creating a new server, e.g. to migrate a cluster which was
+	// built by hand. Exactly one server must match. The server is renamed after
+	// the machine and its ports are taken over. An existing server can also be
+	// adopted by its ID by setting providerID.
+	// +optional
+	AdoptExisting *ServerFilter `json:"adoptExisting,omitempty"`
+
 	// The name of the cloud to use from the clouds secret
 	// +optional
 	CloudName string `json:"cloudName"`
 
 	// The flavor reference for the flavor for your server instance.
-	Flavor string `json:"flavor"`
+	// Either Flavor or FlavorID must be set.
+	// +optional
+	Flavor string `json:"flavor,omitempty"`
+
+	// FlavorID is the ID of the flavor for your server instance. It avoids looking up the
+	// flavor by name. Either Flavor or FlavorID must be set.
+	// +optional
+	FlavorID string `json:"flavorID,omitempty"`
 
 	// The name of the image to use for your server instance.
 	// If the RootVolume is specified, this will be ignored and use rootVolume directly.
@@ -52,9 +67,41 @@ type OpenStackMachineSpec struct {
 	// if it's empty, Image name will be used
 	ImageUUID string `json:"imageUUID,omitempty"`
 
+	// ImageFilter selects the image by its tags and properties instead of
+	// Image or ImageUUID. The image of a machine is resolved once, when its
+	// instance is created, and recorded in status.imageID.
+	// +optional
+	ImageFilter *ImageFilter `json:"imageFilter,omitempty"`
+
+	// ImageRef references an OpenStackImage in the namespace of the machine
+	// whose image is used instead of Image or ImageUUID. The instance is created
+	// once the image is ready, and the image is recorded in status.imageID.
+	// +optional
+	ImageRef *corev1.LocalObjectReference `json:"imageRef,omitempty"`
+
+	// ImageChecksum pins the image to its content. It is compared with the
+	// checksum and the os_hash_value of the image resolved from Image or
+	// ImageUUID, and the instance is not created if neither matches, e.g.
+	// because the image was replaced in Glance.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]+$`
+	// +optional
+	ImageChecksum string `json:"imageChecksum,omitempty"`
+
 	// The ssh key to inject in the instance
 	SSHKeyName string `json:"sshKeyName,omitempty"`
 
+	// SSHPublicKeySecretRef references a secret holding the public key of
+	// SSHKeyName. If set and the keypair no longer exists in the cloud, it is
+	// re-imported from this secret before the instance is created.
+	// +optional
+	SSHPublicKeySecretRef *SSHPublicKeySecretReference `json:"sshPublicKeySecretRef,omitempty"`
+
+	// ServerPassword, if set, publishes the admin password which the image
+	// posted to the metadata service, e.g. cloudbase-init on Windows, to the
+	// secret <machine name>-password once the instance is active.
+	// +optional
+	ServerPassword *ServerPasswordOptions `json:"serverPassword,omitempty"`
+
 	// A networks object. Required parameter when there are multiple networks defined for the tenant.
 	// When you do not specify both networks and ports parameters, the server attaches to the only network created for the current tenant.
 	Networks []NetworkParam `json:"networks,omitempty"`
@@ -63,9 +110,23 @@ type OpenStackMachineSpec struct {
 	// When you do not specify both networks and ports parameters, the server attaches to the only network created for the current tenant.
 	Ports []PortOpts `json:"ports,omitempty"`
 
+	// AddressSortOrder lists the names of the networks whose addresses are
+	// reported first in the addresses of the machine, in this order. The
+	// addresses of other networks follow in lexical order of the network names.
+	// The first listed network the machine has an address on is also the network
+	// of its API server load balancer member, instead of the cluster network.
+	// +optional
+	AddressSortOrder []string `json:"addressSortOrder,omitempty"`
+
 	// UUID, IP address of a port from this subnet will be marked as AccessIPv4 on the created compute instance
 	Subnet string `json:"subnet,omitempty"`
 
+	// ManagedSubnet selects the managed subnet of the cluster network the ports of the
+	// instance on the cluster network get their IPv4 address from, instead of the subnet
+	// with the NodeCIDR of the cluster.
+	// +optional
+	ManagedSubnet *ManagedSubnetSelector `json:"managedSubnet,omitempty"`
+
 	// The floatingIP which will be associated to the machine, only used for master.
 	// The floatingIP should have been created and haven't been associated.
 	FloatingIP string `json:"floatingIP,omitempty"`
@@ -87,15 +148,119 @@ type OpenStackMachineSpec struct {
 	// Config Drive support
 	ConfigDrive *bool `json:"configDrive,omitempty"`
 
+	// Hostname is the hostname of the server, which Nova exposes to the
+	// server through the metadata service and the config drive instead of
+	// the name of the server. It allows the server name to follow naming
+	// conventions which are not valid hostnames, while cloud-init sets the
+	// hostname the node registers with. It is a Go template which can refer
+	// to {{ .MachineName }}, {{ .ClusterName }} and {{ .Namespace }}, and
+	// must render a DNS-1123 label. It requires Nova microversion 2.90
+	// (Xena), and is ignored by machine pools and the bastion.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// BootstrapFormat is the format of the bootstrap data of the machine. If
+	// it is not set, the format is read from the bootstrap data secret and
+	// defaults to cloud-config.
+	// +optional
+	BootstrapFormat BootstrapFormat `json:"bootstrapFormat,omitempty"`
+
+	// Ignition configures how Ignition bootstrap data is passed to the instance.
+	// +optional
+	Ignition *IgnitionOptions `json:"ignition,omitempty"`
+
+	// ConfigureSecondaryInterfaces adds a cloud-init network config v2 for the
+	// ports after the first one to the cloud-config bootstrap data, which
+	// statically assigns the fixed IPs of the ports to the interfaces with
+	// their MAC addresses. It is for images which only configure the first
+	// interface, e.g. with DHCP, and is written as a netplan configuration.
+	// +optional
+	ConfigureSecondaryInterfaces bool `json:"configureSecondaryInterfaces,omitempty"`
+
 	// The volume metadata to boot from
 	RootVolume *RootVolume `json:"rootVolume,omitempty"`
 
+	// AdditionalBlockDevices are volumes which are created with the machine
+	// and attached to it in addition to its root disk, e.g. to give etcd a
+	// dedicated disk. They are deleted together with the machine. Devices
+	// which are added to an existing machine are attached to its server, and
+	// devices which are removed are detached from it.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalBlockDevices []AdditionalBlockDevice `json:"additionalBlockDevices,omitempty"`
+
+	// ControlPlaneStorage creates dedicated volumes for the data of the
+	// control plane, e.g. a volume for etcd which is attached after the
+	// additional block devices. It is meant for control plane machines.
+	// +optional
+	ControlPlaneStorage *ControlPlaneStorage `json:"controlPlaneStorage,omitempty"`
+
+	// DeleteRemovedBlockDevices deletes the volumes of additional block
+	// devices which are removed from an existing machine once they are
+	// detached from its server. By default the volumes are kept.
+	// +optional
+	DeleteRemovedBlockDevices bool `json:"deleteRemovedBlockDevices,omitempty"`
+
 	// The server group to assign the machine to
 	ServerGroupID string `json:"serverGroupID,omitempty"`
 
-	// IdentityRef is a reference to a identity to be used when reconciling this cluster
+	// ServerGroup, if set, makes CAPO manage the server group the machine is
+	// assigned to. The server group is created with the first machine which
+	// needs it and deleted together with the last one. Mutually exclusive
+	// with ServerGroupID.
+	// +optional
+	ServerGroup *ServerGroup `json:"serverGroup,omitempty"`
+
+	// SchedulerHintAdditionalProperties are scheduler hints which are passed to
+	// Nova when the server is created, e.g. to target host aggregates or custom
+	// scheduler filters. The group hint cannot be set together with
+	// ServerGroupID or ServerGroup.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SchedulerHintAdditionalProperties []SchedulerHintAdditionalProperty `json:"schedulerHintAdditionalProperties,omitempty"`
+
+	// Traits are the traits of the hypervisor the instance requires or must
+	// not have. Before the instance is created, they are checked against the
+	// resource providers in Placement. Nova only schedules on traits which are
+	// requested by the flavor or the image, e.g. trait:CUSTOM_GPU=required.
+	// +optional
+	Traits *Traits `json:"traits,omitempty"`
+
+	// Reservation makes the server use capacity reserved by a Blazar lease,
+	// which must be active when the server is created. A host reservation is
+	// passed to Nova as the reservation scheduler hint, and an instance
+	// reservation replaces the flavor with the flavor Blazar created for it.
+	// +optional
+	Reservation *Reservation `json:"reservation,omitempty"`
+
+	// IdentityRef is a reference to a identity to be used when reconciling this machine.
+	// If not specified, the identity of the cluster is used. Resources owned by the
+	// cluster, such as the API server load balancer, always use the cluster identity.
 	// +optional
 	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+
+	// Region is the OpenStack region the machine is created in. It overrides the
+	// region of the cloud in clouds.yaml, so that the worker nodes of a cluster can
+	// be spread across regions which share an identity endpoint. The cluster network
+	// and the managed security groups only exist in the region of the cluster, so
+	// machines in another region must set networks or ports.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Remediation configures how the machine is remediated when its node is
+	// not healthy. By default the machine is only replaced, e.g. by a
+	// MachineHealthCheck.
+	// +optional
+	Remediation *MachineRemediation `json:"remediation,omitempty"`
+
+	// SnapshotBeforeDelete makes CAPO create a snapshot image of the server
+	// before it is deleted, e.g. for the post-mortem analysis of a crashed
+	// node. It overrides the snapshotBeforeDelete of the cluster and can be
+	// changed until the machine is deleted.
+	// +optional
+	SnapshotBeforeDelete *SnapshotBeforeDelete `json:"snapshotBeforeDelete,omitempty"`
 }
 
 // OpenStackMachineStatus defines the observed state of OpenStackMachine.
@@ -111,6 +276,52 @@ type OpenStackMachineStatus struct {
 	// +optional
 	InstanceState *InstanceState `json:"instanceState,omitempty"`
 
+	// ImageID is the ID of the image resolved from ImageFilter or ImageRef.
+	// It is kept for the lifetime of the machine, so that the instance is
+	// recreated from the same image even if a newer image matches the filter.
+	// +optional
+	ImageID string `json:"imageID,omitempty"`
+
+	// ServerCreateOpts records hashes of the effective options the server of
+	// the machine was created with. They are compared with the options the
+	// current spec would produce to report whether a new server would differ.
+	// +optional
+	ServerCreateOpts *ServerCreateOptsHashes `json:"serverCreateOpts,omitempty"`
+
+	// InstanceActionsAudit records which instance actions of the server have
+	// been checked for actions performed outside of CAPO.
+	// +optional
+	InstanceActionsAudit *InstanceActionsAudit `json:"instanceActionsAudit,omitempty"`
+
+	// Remediation records the progress of the in-place remediation of the
+	// machine while its node is not healthy.
+	// +optional
+	Remediation *MachineRemediationStatus `json:"remediation,omitempty"`
+
+	// AttachedBlockDevices are the additional block devices whose volumes are
+	// attached to the server of the machine. Only the volumes of these devices
+	// are detached when devices are removed from the spec.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AttachedBlockDevices []AttachedBlockDevice `json:"attachedBlockDevices,omitempty"`
+
+	// SnapshotImageID is the ID of the snapshot image of the server which is
+	// created before the server is deleted.
+	// +optional
+	SnapshotImageID string `json:"snapshotImageID,omitempty"`
+
+	// Placement is where the server of the machine was scheduled. It is set
+	// once the server is ACTIVE and follows the server when it is migrated.
+	// +optional
+	Placement *InstancePlacement `json:"placement,omitempty"`
+
+	// Plan contains the operations on the OpenStack resources of the machine which
+	// the controller would perform. It is only set while the PlanAnnotation is set
+	// on the OpenStackCluster.
+	// +optional
+	Plan *Plan `json:"plan,omitempty"`
+
 	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
 
 	// FailureMessage will be set in the event that there is a terminal problem
@@ -135,6 +346,64 @@ type OpenStackMachineStatus struct {
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// InstanceActionsAudit records which instance actions of a server have been checked.
+type InstanceActionsAudit struct {
+	// LastCheckTime is the time the instance actions of the server were last listed.
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+
+	// LastActionTime is the start time of the latest instance action of the server
+	// which has been checked.
+	// +optional
+	LastActionTime *metav1.Time `json:"lastActionTime,omitempty"`
+}
+
+// AttachedBlockDevice is an additional block device whose volume is attached to the server of a machine.
+type AttachedBlockDevice struct {
+	// Name is the name of the device.
+	Name string `json:"name"`
+
+	// VolumeID is the ID of the volume of the device.
+	VolumeID string `json:"volumeID"`
+}
+
+// MachineRemediationStatus records the progress of the in-place remediation of a machine.
+type MachineRemediationStatus struct {
+	// Step is the last remediation step which was taken.
+	Step RemediationStep `json:"step"`
+
+	// LastStepTime is the time the last remediation step was taken.
+	LastStepTime metav1.Time `json:"lastStepTime"`
+}
+
+// InstancePlacement is where the server of a machine was scheduled by Nova.
+type InstancePlacement struct {
+	// AvailabilityZone is the availability zone of the server.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// HostID identifies the compute host of the server. Nova obfuscates the
+	// host per project, so two servers of a project have the same HostID if
+	// and only if they run on the same host.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// Host is the name of the compute host of the server. It is only set if
+	// the credentials of the machine have admin rights.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// HypervisorHostname is the hostname of the hypervisor of the server. It
+	// is only set if the credentials of the machine have admin rights.
+	// +optional
+	HypervisorHostname string `json:"hypervisorHostname,omitempty"`
+
+	// ServerGroups are the IDs of the server groups the server is a member
+	// of. They require Nova microversion 2.71 (Stein) and are not set on
+	// older clouds.
+	// +optional
+	ServerGroups []string `json:"serverGroups,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=openstackmachines,scope=Namespaced,categories=cluster-api,shortName=osm
@@ -51,6 +51,7 @@ func (r *OpenStackMachine) Default() {
 	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
 		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
 	}
+	setStorageVersion(&r.ObjectMeta)
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -61,6 +62,31 @@ func (r *OpenStackMachine) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
+	if r.Spec.ServerGroup != nil && r.Spec.ServerGroupID != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "serverGroup"), "cannot be set together with serverGroupID"))
+	}
+
+	allErrs = append(allErrs, validateFlavor(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateImageFilter(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateImageRef(&r.Spec, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateConfigureSecondaryInterfaces(&r.Spec, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRegion(&r.Spec, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateTraits(r.Spec.Traits, field.NewPath("spec", "traits"))...)
+	allErrs = append(allErrs, validateSecurityGroupParams(r.Spec.SecurityGroups, field.NewPath("spec", "securityGroups"))...)
+	allErrs = append(allErrs, validateSubports(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateIPAddressPoolRefs(r.Spec.Ports, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateServerPassword(&r.Spec, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateAdoptExisting(&r.Spec, true, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePortBindingProfiles(r.Spec.Ports, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePortSecurity(r.Spec.Ports, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePortFixedIPs(r.Spec.Ports, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateSchedulerHints(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateAdditionalBlockDevices(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateManagedSubnetSelector(r.Spec.ManagedSubnet, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRemediation(r.Spec.Remediation, field.NewPath("spec", "remediation"))...)
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(r.Spec.SnapshotBeforeDelete, field.NewPath("spec", "snapshotBeforeDelete"))...)
+	allErrs = append(allErrs, validateHostname(&r.Spec, field.NewPath("spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -100,6 +126,43 @@ func (r *OpenStackMachine) ValidateUpdate(old runtime.Object) error {
 		delete(newOpenStackMachineSpec, "instanceID")
 	}
 
+	// allow changes to the allowed address pairs of the ports, which are reconciled onto the existing ports
+	oldPorts, _ := oldOpenStackMachineSpec["ports"].([]interface{})
+	newPorts, _ := newOpenStackMachineSpec["ports"].([]interface{})
+	if len(oldPorts) == len(newPorts) {
+		for _, ports := range [][]interface{}{oldPorts, newPorts} {
+			for _, port := range ports {
+				if port, ok := port.(map[string]interface{}); ok {
+					delete(port, "allowedAddressPairs")
+				}
+			}
+		}
+	}
+
+	// allow changes to the remediation, which does not affect the server
+	delete(oldOpenStackMachineSpec, "remediation")
+	delete(newOpenStackMachineSpec, "remediation")
+	allErrs = append(allErrs, validateRemediation(r.Spec.Remediation, field.NewPath("spec", "remediation"))...)
+
+	// allow changes to the snapshot before delete, which is only taken when the server is deleted
+	delete(oldOpenStackMachineSpec, "snapshotBeforeDelete")
+	delete(newOpenStackMachineSpec, "snapshotBeforeDelete")
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(r.Spec.SnapshotBeforeDelete, field.NewPath("spec", "snapshotBeforeDelete"))...)
+
+	// allow changes to the address sort order, which only affects the reported addresses and the load balancer member
+	delete(oldOpenStackMachineSpec, "addressSortOrder")
+	delete(newOpenStackMachineSpec, "addressSortOrder")
+
+	// allow adding and removing additional block devices, which are attached to and detached from the server
+	delete(oldOpenStackMachineSpec, "additionalBlockDevices")
+	delete(newOpenStackMachineSpec, "additionalBlockDevices")
+	delete(oldOpenStackMachineSpec, "deleteRemovedBlockDevices")
+	delete(newOpenStackMachineSpec, "deleteRemovedBlockDevices")
+	allErrs = append(allErrs, validateAdditionalBlockDevices(&r.Spec, field.NewPath("spec"))...)
+	if oldMachine, ok := old.(*OpenStackMachine); ok {
+		allErrs = append(allErrs, validateAdditionalBlockDevicesUpdate(&oldMachine.Spec, &r.Spec, field.NewPath("spec"))...)
+	}
+
 	if !reflect.DeepEqual(oldOpenStackMachineSpec, newOpenStackMachineSpec) {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
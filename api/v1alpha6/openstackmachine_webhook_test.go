@@ -0,0 +1,274 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestOpenStackMachine_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	vipPorts := func(pairs ...AddressPair) []PortOpts {
+		return []PortOpts{
+			{Description: "primary", AllowedAddressPairs: pairs},
+			{Description: "secondary"},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		oldMachine *OpenStackMachine
+		newMachine *OpenStackMachine
+		wantErr    bool
+	}{
+		{
+			name: "OpenStackMachine with immutable spec",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Image: "bar"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Image: "NewImage"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine allows adding allowed address pairs to a port",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: vipPorts()},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: vipPorts(AddressPair{IPAddress: "10.0.0.100"})},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine allows removing allowed address pairs from a port",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: vipPorts(AddressPair{IPAddress: "10.0.0.100"})},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: vipPorts()},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine does not allow other changes to the ports",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: vipPorts()},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", Ports: []PortOpts{
+					{Description: "primary", AllowedAddressPairs: []AddressPair{{IPAddress: "10.0.0.100"}}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine allows adding an additional block device",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "data", Size: 10}}},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "data", Size: 10}, {Name: "logs", Size: 5}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine allows removing an additional block device",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "data", Size: 10}, {Name: "logs", Size: 5}}},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "logs", Size: 5}}, DeleteRemovedBlockDevices: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine does not allow changing an additional block device",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "data", Size: 10}}},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: "data", Size: 20}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine does not allow adding an additional block device with a reserved name",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{{Name: RootVolumeBlockDeviceName, Size: 10}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine allows changing the address sort order",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AddressSortOrder: []string{"storage"}},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AddressSortOrder: []string{"k8s", "storage"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine allows enabling the snapshot before delete",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", SnapshotBeforeDelete: &SnapshotBeforeDelete{Enabled: true, Name: "{{ .MachineName }}-crashed"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine does not allow an invalid snapshot name template",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", SnapshotBeforeDelete: &SnapshotBeforeDelete{Enabled: true, Name: "{{ .MachineName"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine does not allow a negative snapshot expiry",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", SnapshotBeforeDelete: &SnapshotBeforeDelete{Enabled: true, Expiry: &metav1.Duration{Duration: -time.Hour}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine allows adding a retained additional block device",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{
+					{Name: "cache", Size: 10, RetentionPolicy: VolumeRetentionPolicyRetain, RetainedVolumeName: "runner-cache"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine does not allow a retained volume name without the Retain retention policy",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{
+					{Name: "cache", Size: 10, RetainedVolumeName: "runner-cache"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine does not allow retained block devices with the same volume name",
+			oldMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo"},
+			},
+			newMachine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdditionalBlockDevices: []AdditionalBlockDevice{
+					{Name: "cache", Size: 10, RetentionPolicy: VolumeRetentionPolicyRetain, RetainedVolumeName: "runner-cache"},
+					{Name: "data", Size: 10, RetentionPolicy: VolumeRetentionPolicyRetain, RetainedVolumeName: "runner-cache"},
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.newMachine.ValidateUpdate(tt.oldMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestOpenStackMachine_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		machine *OpenStackMachine
+		wantErr bool
+	}{
+		{
+			name: "OpenStackMachine adopting a server by name",
+			machine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdoptExisting: &ServerFilter{Name: "node-1"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine adopting a server without name or tags",
+			machine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", AdoptExisting: &ServerFilter{}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine adopting a server by filter and provider ID",
+			machine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{
+					Flavor:        "foo",
+					ProviderID:    pointer.String("openstack:///8c5a1e2b-0f6f-4b32-9c2d-1d2c3b4a5e6f"),
+					AdoptExisting: &ServerFilter{Tags: []string{"legacy"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackMachine adopting a server by provider ID",
+			machine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", ProviderID: pointer.String("openstack:///8c5a1e2b-0f6f-4b32-9c2d-1d2c3b4a5e6f")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackMachine adopting a server by an invalid provider ID",
+			machine: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{Flavor: "foo", ProviderID: pointer.String("8c5a1e2b-0f6f-4b32-9c2d-1d2c3b4a5e6f")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileOpenStackMachinePool to clean up OpenStack resources associated with
+	// OpenStackMachinePool before removing it from the apiserver.
+	MachinePoolFinalizer = "openstackmachinepool.infrastructure.cluster.x-k8s.io"
+)
+
+// OpenStackMachinePoolSpec defines the desired state of OpenStackMachinePool.
+type OpenStackMachinePoolSpec struct {
+	// ProviderIDList are the provider IDs of the instances of the pool.
+	// It is set by the controller.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Template is the spec of the instances of the pool. Instances created from a
+	// previous template are replaced when it changes. ProviderID and InstanceID are ignored.
+	Template OpenStackMachineSpec `json:"template"`
+
+	// MaxSurge is the number of instances which can be created above the number of
+	// replicas of the MachinePool while instances are replaced. If it is 0, an outdated
+	// instance is deleted before its replacement is created. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSurge *int32 `json:"maxSurge,omitempty"`
+}
+
+// OpenStackMachinePoolStatus defines the observed state of OpenStackMachinePool.
+type OpenStackMachinePoolStatus struct {
+	// Ready is true when the desired number of instances of the current template is active.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the number of active instances of the pool.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Instances are the instances of the pool.
+	// +optional
+	Instances []OpenStackMachinePoolInstance `json:"instances,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the pool and will contain a succinct value suitable
+	// for machine interpretation.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the pool and will contain a more verbose string suitable
+	// for logging and human consumption.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// OpenStackMachinePoolInstance is an instance of an OpenStackMachinePool.
+type OpenStackMachinePoolInstance struct {
+	Name       string        `json:"name"`
+	ID         string        `json:"id"`
+	ProviderID string        `json:"providerID"`
+	State      InstanceState `json:"state,omitempty"`
+	// UpToDate is true if the instance was created from the current template.
+	UpToDate bool `json:"upToDate"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackmachinepools,scope=Namespaced,categories=cluster-api,shortName=osmp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this OpenStackMachinePool belongs"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of active instances"
+// +kubebuilder:printcolumn:name="MachinePool",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"MachinePool\")].name",description="MachinePool object which owns with this OpenStackMachinePool"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackMachinePool"
+
+// OpenStackMachinePool is the Schema for the openstackmachinepools API.
+type OpenStackMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackMachinePoolSpec   `json:"spec,omitempty"`
+	Status OpenStackMachinePoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackMachinePoolList contains a list of OpenStackMachinePool.
+type OpenStackMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackMachinePool `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackMachinePool resource.
+func (r *OpenStackMachinePool) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackMachinePool to the predescribed clusterv1.Conditions.
+func (r *OpenStackMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackMachinePool{}, &OpenStackMachinePoolList{})
+}
@@ -20,6 +20,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// MaxInstanceAgeAnnotation is set on an OpenStackMachineTemplate to the maximum age, as a duration such as "720h",
+	// of the machines of the MachineDeployments using the template. Older machines are replaced one at a time.
+	MaxInstanceAgeAnnotation = "infrastructure.cluster.x-k8s.io/max-instance-age"
+)
+
 // OpenStackMachineTemplateSpec defines the desired state of OpenStackMachineTemplate.
 type OpenStackMachineTemplateSpec struct {
 	Template OpenStackMachineTemplateResource `json:"template"`
@@ -61,6 +61,32 @@ func (r *OpenStackMachineTemplateWebhook) ValidateCreate(ctx context.Context, ob
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"), "cannot be set in templates"))
 	}
 
+	if openStackMachineTemplate.Spec.Template.Spec.ServerGroup != nil && openStackMachineTemplate.Spec.Template.Spec.ServerGroupID != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "serverGroup"), "cannot be set together with serverGroupID"))
+	}
+
+	allErrs = append(allErrs, validateFlavor(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateImageFilter(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateImageRef(&openStackMachineTemplate.Spec.Template.Spec, true, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateConfigureSecondaryInterfaces(&openStackMachineTemplate.Spec.Template.Spec, true, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateRegion(&openStackMachineTemplate.Spec.Template.Spec, true, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateTraits(openStackMachineTemplate.Spec.Template.Spec.Traits, field.NewPath("spec", "template", "spec", "traits"))...)
+	allErrs = append(allErrs, validateSecurityGroupParams(openStackMachineTemplate.Spec.Template.Spec.SecurityGroups, field.NewPath("spec", "template", "spec", "securityGroups"))...)
+	allErrs = append(allErrs, validateSubports(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateIPAddressPoolRefs(openStackMachineTemplate.Spec.Template.Spec.Ports, true, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateServerPassword(&openStackMachineTemplate.Spec.Template.Spec, true, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateAdoptExisting(&openStackMachineTemplate.Spec.Template.Spec, false, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validatePortBindingProfiles(openStackMachineTemplate.Spec.Template.Spec.Ports, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validatePortSecurity(openStackMachineTemplate.Spec.Template.Spec.Ports, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validatePortFixedIPs(openStackMachineTemplate.Spec.Template.Spec.Ports, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateSchedulerHints(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateAdditionalBlockDevices(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateManagedSubnetSelector(openStackMachineTemplate.Spec.Template.Spec.ManagedSubnet, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateRemediation(openStackMachineTemplate.Spec.Template.Spec.Remediation, field.NewPath("spec", "template", "spec", "remediation"))...)
+	allErrs = append(allErrs, validateSnapshotBeforeDelete(openStackMachineTemplate.Spec.Template.Spec.SnapshotBeforeDelete, field.NewPath("spec", "template", "spec", "snapshotBeforeDelete"))...)
+	allErrs = append(allErrs, validateHostname(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateMaxInstanceAge(openStackMachineTemplate.Annotations, field.NewPath("metadata", "annotations"))...)
+
 	return aggregateObjErrors(openStackMachineTemplate.GroupVersionKind().GroupKind(), openStackMachineTemplate.Name, allErrs)
 }
 
@@ -89,6 +115,8 @@ func (r *OpenStackMachineTemplateWebhook) ValidateUpdate(ctx context.Context, ol
 		)
 	}
 
+	allErrs = append(allErrs, validateMaxInstanceAge(newObj.Annotations, field.NewPath("metadata", "annotations"))...)
+
 	return aggregateObjErrors(newObj.GroupVersionKind().GroupKind(), newObj.Name, allErrs)
 }
 
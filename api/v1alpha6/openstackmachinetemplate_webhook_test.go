@@ -19,9 +19,12 @@ package v1alpha6
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -165,3 +168,558 @@ func TestOpenStackMachineTemplate_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenStackMachineTemplate_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	poolRef := &corev1.TypedLocalObjectReference{
+		APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+		Kind:     "InClusterIPPool",
+		Name:     "pool",
+	}
+	templateWithFixedIPs := func(fixedIPs ...FixedIP) *OpenStackMachineTemplate {
+		return &OpenStackMachineTemplate{
+			Spec: OpenStackMachineTemplateSpec{
+				Template: OpenStackMachineTemplateResource{
+					Spec: OpenStackMachineSpec{
+						Flavor: "foo",
+						Image:  "bar",
+						Ports: []PortOpts{
+							{FixedIPs: fixedIPs},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	templateWithServerPassword := func(sshKeyName string) *OpenStackMachineTemplate {
+		return &OpenStackMachineTemplate{
+			Spec: OpenStackMachineTemplateSpec{
+				Template: OpenStackMachineTemplateResource{
+					Spec: OpenStackMachineSpec{
+						Flavor:     "foo",
+						Image:      "bar",
+						SSHKeyName: sshKeyName,
+						ServerPassword: &ServerPasswordOptions{
+							PrivateKeySecretRef: SSHPrivateKeySecretReference{Name: "keypair"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	templateWithFlavor := func(flavor, flavorID string) *OpenStackMachineTemplate {
+		return &OpenStackMachineTemplate{
+			Spec: OpenStackMachineTemplateSpec{
+				Template: OpenStackMachineTemplateResource{
+					Spec: OpenStackMachineSpec{
+						Flavor:   flavor,
+						FlavorID: flavorID,
+						Image:    "bar",
+					},
+				},
+			},
+		}
+	}
+
+	templateWithBlockDevices := func(rootVolume *RootVolume, devices ...AdditionalBlockDevice) *OpenStackMachineTemplate {
+		t := templateWithFlavor("foo", "")
+		t.Spec.Template.Spec.RootVolume = rootVolume
+		t.Spec.Template.Spec.AdditionalBlockDevices = devices
+		return t
+	}
+
+	tests := []struct {
+		name     string
+		template *OpenStackMachineTemplate
+		wantErr  bool
+	}{
+		{
+			name:     "Fixed IP claimed from an IPAM pool",
+			template: templateWithFixedIPs(FixedIP{IPAddressPoolRef: poolRef}),
+		},
+		{
+			name:     "Fixed IP claimed from an IPAM pool with an address",
+			template: templateWithFixedIPs(FixedIP{IPAddressPoolRef: poolRef, IPAddress: "10.0.0.10"}),
+			wantErr:  true,
+		},
+		{
+			name:     "Fixed IP claimed from an IPAM pool without a kind",
+			template: templateWithFixedIPs(FixedIP{IPAddressPoolRef: &corev1.TypedLocalObjectReference{APIGroup: poolRef.APIGroup, Name: "pool"}}),
+			wantErr:  true,
+		},
+		{
+			name:     "Fixed IPs given by address only",
+			template: templateWithFixedIPs(FixedIP{IPAddress: "10.0.0.10"}, FixedIP{IPAddress: "10.0.0.11"}),
+		},
+		{
+			name:     "Fixed IP without subnet, address or pool",
+			template: templateWithFixedIPs(FixedIP{}),
+			wantErr:  true,
+		},
+		{
+			name:     "Same fixed IP address twice on one port",
+			template: templateWithFixedIPs(FixedIP{IPAddress: "10.0.0.10"}, FixedIP{Subnet: &SubnetFilter{Name: "nodes"}, IPAddress: "10.0.0.10"}),
+			wantErr:  true,
+		},
+		{
+			name: "Binding profile key also set in profile",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							Ports: []PortOpts{
+								{
+									Profile:        map[string]string{"trusted": "true"},
+									BindingProfile: map[string]apiextensionsv1.JSON{"trusted": {Raw: []byte(`true`)}},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Port without port security and without security groups",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							Ports: []PortOpts{
+								{Description: "primary"},
+								{Description: "data plane", DisablePortSecurity: pointer.Bool(true), SecurityGroups: &[]string{}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Port without port security with security groups",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							Ports: []PortOpts{
+								{
+									DisablePortSecurity:  pointer.Bool(true),
+									SecurityGroupFilters: []SecurityGroupParam{{Name: "default"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Port without port security with allowed address pairs",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							Ports: []PortOpts{
+								{
+									DisablePortSecurity: pointer.Bool(true),
+									AllowedAddressPairs: []AddressPair{{IPAddress: "10.0.0.100"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Scheduler hints",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							SchedulerHintAdditionalProperties: []SchedulerHintAdditionalProperty{
+								{Name: "same_host", Value: SchedulerHintAdditionalValue{Type: SchedulerHintValueTypeStringList, StringList: []string{"a1b2c3d4-0000-4000-8000-000000000001"}}},
+								{Name: "aggregate", Value: SchedulerHintAdditionalValue{Type: SchedulerHintValueTypeString, String: pointer.String("gpu")}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Scheduler hint without a value of its type",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+							SchedulerHintAdditionalProperties: []SchedulerHintAdditionalProperty{
+								{Name: "aggregate", Value: SchedulerHintAdditionalValue{Type: SchedulerHintValueTypeBool, String: pointer.String("gpu")}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Group scheduler hint together with a server group",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:      "foo",
+							Image:       "bar",
+							ServerGroup: &ServerGroup{Policy: ServerGroupPolicyAntiAffinity},
+							SchedulerHintAdditionalProperties: []SchedulerHintAdditionalProperty{
+								{Name: "group", Value: SchedulerHintAdditionalValue{Type: SchedulerHintValueTypeString, String: pointer.String("7b940d62-68ef-4e42-a76a-1a62e290509c")}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reservation scheduler hint together with a reservation",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:      "foo",
+							Image:       "bar",
+							Reservation: &Reservation{LeaseID: "0b0e3c5a-1d7e-4d6f-9f5e-8a3c2b1d0e9f"},
+							SchedulerHintAdditionalProperties: []SchedulerHintAdditionalProperty{
+								{Name: "reservation", Value: SchedulerHintAdditionalValue{Type: SchedulerHintValueTypeString, String: pointer.String("5c9e8a7b-3f2d-4e1c-b0a9-8d7e6f5a4b3c")}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Managed subnet selected by role",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:        "foo",
+							Image:         "bar",
+							ManagedSubnet: &ManagedSubnetSelector{Role: SubnetRoleWorkers},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Managed subnet selected by name and role",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:        "foo",
+							Image:         "bar",
+							ManagedSubnet: &ManagedSubnetSelector{Name: "workers", Role: SubnetRoleWorkers},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Managed subnet selected by neither name nor role",
+			template: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:        "foo",
+							Image:         "bar",
+							ManagedSubnet: &ManagedSubnetSelector{},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Flavor selected by ID",
+			template: templateWithFlavor("", "3f9a7b41-2c6d-4e5f-8a90-1b2c3d4e5f60"),
+		},
+		{
+			name:     "Flavor selected by name and ID",
+			template: templateWithFlavor("foo", "3f9a7b41-2c6d-4e5f-8a90-1b2c3d4e5f60"),
+			wantErr:  true,
+		},
+		{
+			name:     "Flavor selected by neither name nor ID",
+			template: templateWithFlavor("", ""),
+			wantErr:  true,
+		},
+		{
+			name: "Image selected by a filter",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Image = ""
+				t.Spec.Template.Spec.ImageFilter = &ImageFilter{Tags: []string{"kubernetes"}, MostRecent: true}
+				return t
+			}(),
+		},
+		{
+			name: "Image selected by a filter and a name",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.ImageFilter = &ImageFilter{Tags: []string{"kubernetes"}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Image filter without criteria",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Image = ""
+				t.Spec.Template.Spec.ImageFilter = &ImageFilter{MostRecent: true}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Image referenced by an OpenStackImage",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Image = ""
+				t.Spec.Template.Spec.ImageRef = &corev1.LocalObjectReference{Name: "ubuntu"}
+				return t
+			}(),
+		},
+		{
+			name: "Image referenced by an OpenStackImage and a name",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.ImageRef = &corev1.LocalObjectReference{Name: "ubuntu"}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Secondary interfaces configured with cloud-config",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.ConfigureSecondaryInterfaces = true
+				return t
+			}(),
+		},
+		{
+			name: "Secondary interfaces configured with Ignition",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.ConfigureSecondaryInterfaces = true
+				t.Spec.Template.Spec.BootstrapFormat = BootstrapFormatIgnition
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Region with networks",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Region = "region-two"
+				t.Spec.Template.Spec.Networks = []NetworkParam{{Filter: NetworkFilter{Name: "region-two-net"}}}
+				return t
+			}(),
+		},
+		{
+			name: "Region without networks or ports",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Region = "region-two"
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Traits",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Traits = &Traits{Required: []string{"CUSTOM_GPU"}, Forbidden: []string{"HW_CPU_X86_AVX512F"}}
+				return t
+			}(),
+		},
+		{
+			name: "Trait which is not a trait name",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Traits = &Traits{Required: []string{"custom-gpu"}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Trait both required and forbidden",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Traits = &Traits{Required: []string{"CUSTOM_GPU"}, Forbidden: []string{"CUSTOM_GPU"}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Max instance age",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Annotations = map[string]string{MaxInstanceAgeAnnotation: "720h"}
+				return t
+			}(),
+		},
+		{
+			name: "Max instance age which is not a duration",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Annotations = map[string]string{MaxInstanceAgeAnnotation: "30d"}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name:     "Server password",
+			template: templateWithServerPassword("keypair"),
+		},
+		{
+			name:     "Server password without a keypair",
+			template: templateWithServerPassword(""),
+			wantErr:  true,
+		},
+		{
+			name: "Additional block devices with scheduler hints",
+			template: templateWithBlockDevices(&RootVolume{Size: 50},
+				AdditionalBlockDevice{Name: "etcd", Size: 10, VolumeType: "ssd", QoSSpecs: "high-iops", SchedulerHints: &VolumeSchedulerHints{DifferentHostAs: []string{"root"}}},
+				AdditionalBlockDevice{Name: "data", Size: 100, SchedulerHints: &VolumeSchedulerHints{SameHostAs: []string{"etcd"}}}),
+		},
+		{
+			name: "Etcd volume of the control plane storage",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithBlockDevices(nil, AdditionalBlockDevice{Name: "data", Size: 100, Tag: "data"})
+				t.Spec.Template.Spec.ControlPlaneStorage = &ControlPlaneStorage{Etcd: &EtcdVolume{Size: 10, VolumeType: "ssd", QoSSpecs: "high-iops"}}
+				return t
+			}(),
+		},
+		{
+			name: "Etcd volume with QoS specs but no volume type",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithBlockDevices(nil)
+				t.Spec.Template.Spec.ControlPlaneStorage = &ControlPlaneStorage{Etcd: &EtcdVolume{Size: 10, QoSSpecs: "high-iops"}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Additional block device named like the etcd volume",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithBlockDevices(nil, AdditionalBlockDevice{Name: "etcd", Size: 10})
+				t.Spec.Template.Spec.ControlPlaneStorage = &ControlPlaneStorage{Etcd: &EtcdVolume{Size: 10}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name:     "Additional block device named root",
+			template: templateWithBlockDevices(nil, AdditionalBlockDevice{Name: "root", Size: 10}),
+			wantErr:  true,
+		},
+		{
+			name:     "Additional block device with QoS specs but no volume type",
+			template: templateWithBlockDevices(nil, AdditionalBlockDevice{Name: "etcd", Size: 10, QoSSpecs: "high-iops"}),
+			wantErr:  true,
+		},
+		{
+			name:     "Additional block device referencing the root volume without one",
+			template: templateWithBlockDevices(nil, AdditionalBlockDevice{Name: "etcd", Size: 10, SchedulerHints: &VolumeSchedulerHints{SameHostAs: []string{"root"}}}),
+			wantErr:  true,
+		},
+		{
+			name: "Additional block device referencing a later device",
+			template: templateWithBlockDevices(nil,
+				AdditionalBlockDevice{Name: "etcd", Size: 10, SchedulerHints: &VolumeSchedulerHints{SameHostAs: []string{"data"}}},
+				AdditionalBlockDevice{Name: "data", Size: 100}),
+			wantErr: true,
+		},
+		{
+			name: "In-place remediation with a timeout",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Remediation = &MachineRemediation{Strategy: RemediationStrategyInPlace, Timeout: &metav1.Duration{Duration: 10 * time.Minute}}
+				return t
+			}(),
+		},
+		{
+			name: "In-place remediation with a negative timeout",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Remediation = &MachineRemediation{Strategy: RemediationStrategyInPlace, Timeout: &metav1.Duration{Duration: -time.Minute}}
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Hostname template",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Hostname = "{{ .ClusterName }}-{{ .MachineName }}"
+				return t
+			}(),
+		},
+		{
+			name: "Hostname template which does not parse",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Hostname = "{{ .MachineName }"
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Hostname which is not a DNS-1123 label",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.Hostname = "Corp_Node"
+				return t
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "Adopting an existing server",
+			template: func() *OpenStackMachineTemplate {
+				t := templateWithFlavor("foo", "")
+				t.Spec.Template.Spec.AdoptExisting = &ServerFilter{Name: "node-1"}
+				return t
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &OpenStackMachineTemplateWebhook{}
+			err := webhook.ValidateCreate(context.Background(), tt.template)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
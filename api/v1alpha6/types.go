@@ -16,6 +16,12 @@ limitations under the License.
 
 package v1alpha6
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // OpenStackMachineTemplateResource describes the data needed to create a OpenStackMachine from a template.
 type OpenStackMachineTemplateResource struct {
 	// Spec is the specification of the desired behavior of the machine.
@@ -78,6 +84,61 @@ type NetworkFilter struct {
 	NotTagsAny  string `json:"notTagsAny,omitempty"`
 }
 
+type RouterFilter struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Tags        string `json:"tags,omitempty"`
+	TagsAny     string `json:"tagsAny,omitempty"`
+	NotTags     string `json:"notTags,omitempty"`
+	NotTagsAny  string `json:"notTagsAny,omitempty"`
+}
+
+// RouterExternalGateway configures the external gateway of a router.
+type RouterExternalGateway struct {
+	// EnableSNAT enables source NAT of the traffic leaving the cluster network through the
+	// external gateway. If not set, the Neutron default is used, which enables it. Disable it
+	// if the node addresses are routed in the external network, e.g. for provider network
+	// egress. Changing it requires the ext-gw-mode extension and, by default Neutron policy,
+	// admin credentials.
+	// +optional
+	EnableSNAT *bool `json:"enableSNAT,omitempty"`
+
+	// QoSPolicy is the name or ID of the Neutron QoS policy applied to the external gateway,
+	// e.g. to limit the egress bandwidth of the cluster. Requires the qos-gateway-ip extension.
+	// +optional
+	QoSPolicy string `json:"qosPolicy,omitempty"`
+}
+
+// ProviderNetworkType is the type of a provider network.
+// +kubebuilder:validation:Enum=flat;vlan
+type ProviderNetworkType string
+
+const (
+	ProviderNetworkTypeFlat ProviderNetworkType = "flat"
+	ProviderNetworkTypeVLAN ProviderNetworkType = "vlan"
+)
+
+// ProviderNetwork describes the physical network a provider network is mapped to.
+type ProviderNetwork struct {
+	// PhysicalNetwork is the name of the physical network, as configured in the
+	// Neutron bridge or interface mappings, e.g. physnet1.
+	// +kubebuilder:validation:MinLength=1
+	PhysicalNetwork string `json:"physicalNetwork"`
+
+	// NetworkType is the type of the network, flat or vlan.
+	NetworkType ProviderNetworkType `json:"networkType"`
+
+	// SegmentationID is the VLAN ID of a vlan network. If not set, Neutron allocates
+	// a VLAN ID from the ranges of the physical network. It cannot be set for a flat
+	// network.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	// +optional
+	SegmentationID int `json:"segmentationID,omitempty"`
+}
+
 type SubnetParam struct {
 	// Optional UUID of the subnet.
 	// If specified this will not be validated prior to server creation.
@@ -113,11 +174,20 @@ type PortOpts struct {
 	Description  string `json:"description,omitempty"`
 	AdminStateUp *bool  `json:"adminStateUp,omitempty"`
 	MACAddress   string `json:"macAddress,omitempty"`
+	// Order is the position of the network interface of the port on the server.
+	// Interfaces are attached in ascending order, and ports of the same order
+	// in the order of the list, so that the interface names are the same on
+	// all machines. The index of the port in the list is still used for its name.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Order int32 `json:"order,omitempty"`
 	// Specify pairs of subnet and/or IP address. These should be subnets of the network with the given NetworkID.
 	FixedIPs  []FixedIP `json:"fixedIPs,omitempty"`
 	TenantID  string    `json:"tenantId,omitempty"`
 	ProjectID string    `json:"projectId,omitempty"`
-	// The uuids of the security groups to assign to the instance
+	// The uuids of the security groups to assign to the instance. If neither
+	// securityGroups nor securityGroupFilters is set, the security groups of
+	// the instance are used. An empty list creates the port without security groups.
 	// +listType=set
 	SecurityGroups *[]string `json:"securityGroups,omitempty"`
 	// The names, uuids, filters or any combination these of the security groups to assign to the instance
@@ -126,6 +196,10 @@ type PortOpts struct {
 	// Enables and disables trunk at port level. If not provided, openStackMachine.Spec.Trunk is inherited.
 	Trunk *bool `json:"trunk,omitempty"`
 
+	// Subports to create and attach to the trunk of this port. Requires the trunk
+	// to be enabled for the port.
+	Subports []SubportOpts `json:"subports,omitempty"`
+
 	// The ID of the host where the port is allocated
 	HostID string `json:"hostId,omitempty"`
 
@@ -137,21 +211,109 @@ type PortOpts struct {
 	// information to the plug-in.
 	Profile map[string]string `json:"profile,omitempty"`
 
+	// BindingProfile is passed to Neutron as the binding profile of the port
+	// together with Profile. Unlike Profile, its values can be any JSON value,
+	// e.g. `capabilities: [switchdev]` or `trusted: true` for SR-IOV ports.
+	// A key cannot be set in both Profile and BindingProfile.
+	// +optional
+	BindingProfile map[string]apiextensionsv1.JSON `json:"bindingProfile,omitempty"`
+
 	// DisablePortSecurity enables or disables the port security when set.
-	// When not set, it takes the value of the corresponding field at the network level.
+	// When not set, it takes the value of the corresponding field at the network level,
+	// i.e. ports on a cluster network created with disablePortSecurity have no port security.
+	// Security groups and allowed address pairs cannot be set when port security is disabled.
 	DisablePortSecurity *bool `json:"disablePortSecurity,omitempty"`
 
 	// Tags applied to the port (and corresponding trunk, if a trunk is configured.)
 	// These tags are applied in addition to the instance's tags, which will also be applied to the port.
 	// +listType=set
 	Tags []string `json:"tags,omitempty"`
+
+	// Hints passed to the Neutron backend to tune the datapath of the port.
+	// Requires the port-hints extension.
+	// +optional
+	Hints *PortHints `json:"hints,omitempty"`
+
+	// QoSPolicy is the name or ID of the Neutron QoS policy applied to the port,
+	// e.g. to limit its bandwidth. Requires the qos extension.
+	// +optional
+	QoSPolicy string `json:"qosPolicy,omitempty"`
+
+	// DeviceProfile is the name of the Cyborg device profile of the port, e.g.
+	// of a SmartNIC or an FPGA. Nova requests the accelerator from Cyborg when
+	// the server is created and binds it to the port. Requires the
+	// port-device-profile extension.
+	// +optional
+	DeviceProfile string `json:"deviceProfile,omitempty"`
+}
+
+// PortHints are backend specific hints which Neutron passes to the mechanism driver of the port.
+type PortHints struct {
+	// OpenVSwitch hints are used by the OVS and OVN mechanism drivers.
+	// +optional
+	OpenVSwitch *OpenVSwitchPortHints `json:"openvswitch,omitempty"`
+}
+
+// TxSteering is the transmit steering policy of an Open vSwitch port.
+// +kubebuilder:validation:Enum=thru;hash
+type TxSteering string
+
+const (
+	// TxSteeringThru sends the packets of the port to the transmit queue of the CPU
+	// which processes them, which gives the lowest latency.
+	TxSteeringThru TxSteering = "thru"
+	// TxSteeringHash spreads the packets of the port over the transmit queues by flow hash,
+	// which gives the highest throughput.
+	TxSteeringHash TxSteering = "hash"
+)
+
+type OpenVSwitchPortHints struct {
+	// TxSteering sets the transmit steering policy of the port.
+	// Requires the port-hint-ovs-tx-steering extension.
+	// +optional
+	TxSteering TxSteering `json:"txSteering,omitempty"`
+}
+
+// SubportSegmentationType is the segmentation type of a trunk subport.
+// +kubebuilder:validation:Enum=vlan
+type SubportSegmentationType string
+
+const (
+	SubportSegmentationTypeVLAN SubportSegmentationType = "vlan"
+)
+
+type SubportOpts struct {
+	// Network is a query for an openstack network that the subport will be created on.
+	// This will fail if the query returns more than one network.
+	Network *NetworkFilter `json:"network"`
+	// Subnet is an openstack subnet query that will return the id of the subnet to
+	// create the subport in. This query must not return more than one subnet.
+	// If unspecified, Neutron chooses the subnet.
+	Subnet *SubnetFilter `json:"subnet,omitempty"`
+	// SegmentationType is the segmentation type of the subport on the trunk.
+	SegmentationType SubportSegmentationType `json:"segmentationType"`
+	// SegmentationID is the segmentation ID of the subport on the trunk, e.g. the VLAN ID.
+	// It must be unique within the trunk.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	SegmentationID int `json:"segmentationID"`
 }
 
 type FixedIP struct {
 	// Subnet is an openstack subnet query that will return the id of a subnet to create
 	// the fixed IP of a port in. This query must not return more than one subnet.
-	Subnet    *SubnetFilter `json:"subnet"`
-	IPAddress string        `json:"ipAddress,omitempty"`
+	// If it is not set, the subnet is determined by Neutron from the IP address.
+	// +optional
+	Subnet *SubnetFilter `json:"subnet,omitempty"`
+	// IPAddress is the address of the fixed IP. If it is not set, Neutron allocates
+	// an address from the subnet.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+	// IPAddressPoolRef is a reference to a Cluster API IPAM pool, e.g. an InClusterIPPool,
+	// from which the address is claimed. The claim is created for the machine and
+	// released when the machine is deleted. It cannot be set together with ipAddress.
+	// +optional
+	IPAddressPoolRef *corev1.TypedLocalObjectReference `json:"ipAddressPoolRef,omitempty"`
 }
 
 type AddressPair struct {
@@ -182,10 +344,628 @@ type Instance struct {
 	FloatingIP     string            `json:"floatingIP,omitempty"`
 }
 
+// ServerFilter selects an existing server in Nova by its name and tags.
+type ServerFilter struct {
+	// Name of the server.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Tags which the server must all have.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ImageFilter selects an image in Glance by its name, tags and properties.
+type ImageFilter struct {
+	// Name of the image.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Tags which the image must all have.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Properties which the image must have with the given values, e.g.
+	// os_distro: ubuntu.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// MostRecent selects the most recently created image if several images
+	// match. Otherwise exactly one image must match.
+	// +optional
+	MostRecent bool `json:"mostRecent,omitempty"`
+}
+
 type RootVolume struct {
 	Size             int    `json:"diskSize,omitempty"`
 	VolumeType       string `json:"volumeType,omitempty"`
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// CrossAZAttach allows the volume to be in a different availability zone
+	// than the instance. Nova rejects such volumes unless cross_az_attach is
+	// enabled in its cinder configuration, so by default the instance is not
+	// created if the availability zones differ.
+	// +optional
+	CrossAZAttach bool `json:"crossAZAttach,omitempty"`
+
+	// RetentionPolicy is what happens to the volume when the machine is
+	// deleted. By default it is deleted together with the server.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	RetentionPolicy VolumeRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// VolumeRetentionPolicy is what happens to the volume of a machine when the machine is deleted.
+type VolumeRetentionPolicy string
+
+const (
+	// VolumeRetentionPolicyDelete deletes the volume together with the server of the machine.
+	VolumeRetentionPolicyDelete VolumeRetentionPolicy = "Delete"
+	// VolumeRetentionPolicyRetain keeps the volume when the machine is deleted.
+	VolumeRetentionPolicyRetain VolumeRetentionPolicy = "Retain"
+)
+
+// RootVolumeBlockDeviceName is the name by which the scheduler hints of an additional block
+// device reference the root volume.
+const RootVolumeBlockDeviceName = "root"
+
+// AdditionalBlockDevice is a Cinder volume which is created with the instance
+// and attached to it in addition to its root disk.
+type AdditionalBlockDevice struct {
+	// Name is the name of the device. It is appended to the name of the
+	// instance to name the volume, and references the device in the
+	// scheduler hints of other devices. The name root is reserved for the
+	// root volume.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Size is the size of the volume in GiB.
+	// +kubebuilder:validation:Minimum=1
+	Size int `json:"size"`
+
+	// VolumeType is the Cinder volume type of the volume.
+	// +optional
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// QoSSpecs is the name of the Cinder QoS specs which must be associated
+	// with VolumeType. QoS specs are associated with volume types by the cloud
+	// administrator, so the volume is not created if the volume type has
+	// different or no QoS specs. Requires VolumeType.
+	// +optional
+	QoSSpecs string `json:"qosSpecs,omitempty"`
+
+	// AvailabilityZone is the availability zone of the volume. It defaults to
+	// the availability zone of the instance.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// SchedulerHints are passed to Cinder when the volume is created.
+	// +optional
+	SchedulerHints *VolumeSchedulerHints `json:"schedulerHints,omitempty"`
+
+	// Tag is the device tag with which the volume is attached to the
+	// instance. Nova exposes the tags of the devices of an instance in its
+	// metadata, which lets the instance find the disk of the volume.
+	// +kubebuilder:validation:MaxLength=60
+	// +kubebuilder:validation:Pattern=`^[^,/]*$`
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// RetentionPolicy is what happens to the volume when the machine is
+	// deleted. By default it is deleted together with the server. A retained
+	// volume is kept, e.g. to be attached to the successor of the machine.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	RetentionPolicy VolumeRetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// RetainedVolumeName is the name of the volume instead of the name of the
+	// instance followed by the name of the device. It does not have to be
+	// unique: an available volume of the name, e.g. one retained from a
+	// deleted machine, is attached instead of creating a new volume, so that
+	// the machines of a MachineDeployment can reuse a pool of volumes, e.g.
+	// with the cache of a CI runner. Requires the Retain retention policy.
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	RetainedVolumeName string `json:"retainedVolumeName,omitempty"`
+}
+
+// EtcdBlockDeviceName is the name of the additional block device of the etcd volume of
+// ControlPlaneStorage, which is also the tag the volume is attached with.
+const EtcdBlockDeviceName = "etcd"
+
+// ControlPlaneStorage configures dedicated volumes for the data of the control plane.
+type ControlPlaneStorage struct {
+	// Etcd is a dedicated volume for the data of etcd. It is attached as an
+	// additional block device named etcd with the device tag etcd, with
+	// which the bootstrap data finds the disk to mount at /var/lib/etcd.
+	Etcd *EtcdVolume `json:"etcd,omitempty"`
+}
+
+// EtcdVolume is the dedicated etcd volume of a control plane machine.
+type EtcdVolume struct {
+	// Size is the size of the volume in GiB.
+	// +kubebuilder:validation:Minimum=1
+	Size int `json:"size"`
+
+	// VolumeType is the Cinder volume type of the volume, which should be
+	// backed by low-latency, high-IOPS storage.
+	// +optional
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// QoSSpecs is the name of the Cinder QoS specs which must be associated
+	// with VolumeType. Requires VolumeType.
+	// +optional
+	QoSSpecs string `json:"qosSpecs,omitempty"`
+
+	// AvailabilityZone is the availability zone of the volume. It defaults to
+	// the availability zone of the instance.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+// VolumeSchedulerHints are the Cinder scheduler hints of an additional block
+// device. Volumes are referenced by the name of a device listed before the
+// device, or root for the root volume.
+type VolumeSchedulerHints struct {
+	// SameHostAs places the volume on the same backend host as the
+	// referenced volumes.
+	// +listType=set
+	// +optional
+	SameHostAs []string `json:"sameHostAs,omitempty"`
+
+	// DifferentHostAs places the volume on a different backend host from the
+	// referenced volumes.
+	// +listType=set
+	// +optional
+	DifferentHostAs []string `json:"differentHostAs,omitempty"`
+}
+
+// ServerCreateOptsHashes are hashes of the effective options a server was created with.
+type ServerCreateOptsHashes struct {
+	// Hash is the hash of all options.
+	Hash string `json:"hash"`
+
+	// Fields are the hashes of the individual options by name, which are
+	// compared to report the options which changed.
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ServerGroupPolicy is the scheduling policy applied to a Nova server group.
+// +kubebuilder:validation:Enum=anti-affinity;soft-anti-affinity;affinity
+type ServerGroupPolicy string
+
+const (
+	ServerGroupPolicyAntiAffinity     ServerGroupPolicy = "anti-affinity"
+	ServerGroupPolicySoftAntiAffinity ServerGroupPolicy = "soft-anti-affinity"
+	ServerGroupPolicyAffinity         ServerGroupPolicy = "affinity"
+)
+
+// ServerGroup describes a Nova server group created and owned by CAPO.
+// A single server group is shared by all machines of the same
+// MachineDeployment, or by all control plane machines of the cluster.
+type ServerGroup struct {
+	// Policy is the scheduling policy of the server group.
+	Policy ServerGroupPolicy `json:"policy"`
+}
+
+// SchedulerHintValueType is the type of the value of a scheduler hint.
+// +kubebuilder:validation:Enum=Bool;String;StringList;Number
+type SchedulerHintValueType string
+
+const (
+	SchedulerHintValueTypeBool       SchedulerHintValueType = "Bool"
+	SchedulerHintValueTypeString     SchedulerHintValueType = "String"
+	SchedulerHintValueTypeStringList SchedulerHintValueType = "StringList"
+	SchedulerHintValueTypeNumber     SchedulerHintValueType = "Number"
+)
+
+// Traits are traits of resource providers in Placement, e.g. HW_CPU_X86_AVX2
+// or CUSTOM_GPU.
+type Traits struct {
+	// Required are the traits the hypervisor must have.
+	// +optional
+	Required []string `json:"required,omitempty"`
+
+	// Forbidden are the traits the hypervisor must not have.
+	// +optional
+	Forbidden []string `json:"forbidden,omitempty"`
+}
+
+// Reservation references a Blazar lease in whose reserved capacity a server is
+// created.
+type Reservation struct {
+	// LeaseID is the ID of the Blazar lease.
+	// +kubebuilder:validation:Format=uuid
+	LeaseID string `json:"leaseID"`
+
+	// ReservationID is the ID of the reservation of the lease to use. It must be
+	// set if the lease has more than one reservation.
+	// +kubebuilder:validation:Format=uuid
+	// +optional
+	ReservationID string `json:"reservationID,omitempty"`
+}
+
+// SchedulerHintAdditionalProperty is a scheduler hint which is passed to Nova
+// when the server is created.
+type SchedulerHintAdditionalProperty struct {
+	// Name of the scheduler hint, e.g. same_host, different_host, query or the
+	// hint of a custom scheduler filter.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value of the scheduler hint.
+	Value SchedulerHintAdditionalValue `json:"value"`
+}
+
+// SchedulerHintAdditionalValue is the value of a scheduler hint. Exactly the
+// field matching the type must be set.
+type SchedulerHintAdditionalValue struct {
+	// Type of the value.
+	Type SchedulerHintValueType `json:"type"`
+
+	// Bool is the value of a hint of type Bool.
+	// +optional
+	Bool *bool `json:"bool,omitempty"`
+
+	// String is the value of a hint of type String.
+	// +optional
+	String *string `json:"string,omitempty"`
+
+	// StringList is the value of a hint of type StringList, e.g. the server
+	// IDs of same_host or different_host.
+	// +optional
+	StringList []string `json:"stringList,omitempty"`
+
+	// Number is the value of a hint of type Number.
+	// +optional
+	Number *int `json:"number,omitempty"`
+}
+
+// BootstrapFormat is the format of the bootstrap data of a machine.
+// +kubebuilder:validation:Enum=cloud-config;ignition
+type BootstrapFormat string
+
+const (
+	BootstrapFormatCloudConfig BootstrapFormat = "cloud-config"
+	BootstrapFormatIgnition    BootstrapFormat = "ignition"
+)
+
+// IPv6AddressMode is how IPv6 addresses are assigned to ports, or how router
+// advertisements are sent, in an IPv6 subnet.
+// +kubebuilder:validation:Enum=slaac;dhcpv6-stateful;dhcpv6-stateless
+type IPv6AddressMode string
+
+const (
+	IPv6AddressModeSLAAC           IPv6AddressMode = "slaac"
+	IPv6AddressModeDHCPv6Stateful  IPv6AddressMode = "dhcpv6-stateful"
+	IPv6AddressModeDHCPv6Stateless IPv6AddressMode = "dhcpv6-stateless"
+)
+
+// IPv6SubnetOptions describes an IPv6 subnet to be created.
+type IPv6SubnetOptions struct {
+	// CIDR is the IPv6 CIDR of the subnet.
+	CIDR string `json:"cidr"`
+
+	// AddressMode is how the ports of the subnet get their addresses. Defaults to slaac.
+	// +optional
+	AddressMode IPv6AddressMode `json:"addressMode,omitempty"`
+
+	// RAMode is how router advertisements are sent in the subnet. Defaults to the address mode.
+	// +optional
+	RAMode IPv6AddressMode `json:"raMode,omitempty"`
+
+	// DNSNameservers is the list of IPv6 nameservers of the subnet.
+	// +listType=set
+	// +optional
+	DNSNameservers []string `json:"dnsNameservers,omitempty"`
+}
+
+// SubnetRole is the role of a managed subnet of the cluster network.
+// +kubebuilder:validation:Enum=control-plane;workers;storage
+type SubnetRole string
+
+const (
+	SubnetRoleControlPlane SubnetRole = "control-plane"
+	SubnetRoleWorkers      SubnetRole = "workers"
+	SubnetRoleStorage      SubnetRole = "storage"
+)
+
+// ManagedSubnet describes an additional IPv4 subnet to be created in the cluster network.
+type ManagedSubnet struct {
+	// Name identifies the subnet in the cluster. The subnet is named after the
+	// subnet with NodeCIDR, suffixed with the name.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// CIDR is the IPv4 CIDR of the subnet.
+	CIDR string `json:"cidr"`
+
+	// Role is the role of the subnet, by which machines can select it.
+	// +optional
+	Role SubnetRole `json:"role,omitempty"`
+
+	// AllocationPools are the ranges of addresses which are allocated to ports.
+	// Defaults to all addresses of the CIDR except the gateway.
+	// +optional
+	AllocationPools []AllocationPool `json:"allocationPools,omitempty"`
+
+	// GatewayIP is the address of the gateway of the subnet. Defaults to the
+	// first address of the CIDR.
+	// +optional
+	GatewayIP string `json:"gatewayIP,omitempty"`
+
+	// DNSNameservers is the list of nameservers of the subnet.
+	// +listType=set
+	// +optional
+	DNSNameservers []string `json:"dnsNameservers,omitempty"`
+
+	// HostRoutes are the static routes which DHCP announces to the ports of the
+	// subnet, e.g. to reach on-premises networks through a VPN gateway. Changes
+	// are applied to the existing subnet.
+	// +optional
+	HostRoutes []HostRoute `json:"hostRoutes,omitempty"`
+
+	// DisableDHCP creates the subnet without DHCP, for environments which forbid it.
+	// Machines on the subnet are always created with a config drive, from whose
+	// network data the operating system configures the fixed IPs of the ports
+	// statically. The fixed IPs can be set per machine in the ports of the machine.
+	// +optional
+	DisableDHCP bool `json:"disableDHCP,omitempty"`
+
+	// ServiceTypes restricts the subnet to ports with these device owners, e.g.
+	// network:floatingip_agent_gateway for the gateways of DVR routers or
+	// network:router_gateway. Machine ports cannot get addresses on a subnet
+	// which is restricted to other device owners. It requires the
+	// subnet-service-types extension of Neutron. Changes are applied to the
+	// existing subnet.
+	// +listType=set
+	// +optional
+	ServiceTypes []string `json:"serviceTypes,omitempty"`
+}
+
+// AllocationPool is a range of addresses of a subnet.
+type AllocationPool struct {
+	// Start is the first address of the range.
+	Start string `json:"start"`
+
+	// End is the last address of the range.
+	End string `json:"end"`
+}
+
+// HostRoute is a static route of a subnet.
+type HostRoute struct {
+	// Destination is the CIDR of the destination network.
+	Destination string `json:"destination"`
+
+	// NextHop is the address of the gateway to the destination network.
+	NextHop string `json:"nextHop"`
+}
+
+// ManagedSubnetSelector selects a managed subnet of the cluster network by its name or role.
+// Exactly one of them must be set.
+type ManagedSubnetSelector struct {
+	// Name is the name of the managed subnet.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Role is the role of the managed subnet. It must be unique among the
+	// managed subnets of the cluster.
+	// +optional
+	Role SubnetRole `json:"role,omitempty"`
+}
+
+// ManagedSubnetStatus represents a managed subnet of the cluster network.
+type ManagedSubnetStatus struct {
+	// Name is the name of the managed subnet in the spec of the cluster.
+	Name string `json:"name"`
+
+	// Role is the role of the managed subnet.
+	// +optional
+	Role SubnetRole `json:"role,omitempty"`
+
+	// Subnet is the OpenStack subnet.
+	Subnet Subnet `json:"subnet"`
+
+	// DHCPDisabled is true if DHCP is disabled on the subnet.
+	// +optional
+	DHCPDisabled bool `json:"dhcpDisabled,omitempty"`
+
+	// ServiceTypes are the service types which were set on the subnet.
+	// +optional
+	ServiceTypes []string `json:"serviceTypes,omitempty"`
+}
+
+// SecurityGroupRulesPolicy is how the rules of a managed security group are reconciled.
+// +kubebuilder:validation:Enum=Replace;Merge
+type SecurityGroupRulesPolicy string
+
+const (
+	SecurityGroupRulesPolicyReplace SecurityGroupRulesPolicy = "Replace"
+	SecurityGroupRulesPolicyMerge   SecurityGroupRulesPolicy = "Merge"
+)
+
+// ManagedSecurityGroupName is the name of a security group managed by the OpenStack provider.
+// +kubebuilder:validation:Enum=bastion;controlplane;worker
+type ManagedSecurityGroupName string
+
+const (
+	ManagedSecurityGroupBastion      ManagedSecurityGroupName = "bastion"
+	ManagedSecurityGroupControlPlane ManagedSecurityGroupName = "controlplane"
+	ManagedSecurityGroupWorker       ManagedSecurityGroupName = "worker"
+)
+
+// ManagedSecurityGroupRules are user-defined rules of the managed security groups.
+type ManagedSecurityGroupRules struct {
+	// AllNodesSecurityGroupRules are added to the security groups of both the control plane
+	// and the worker nodes.
+	// +optional
+	AllNodesSecurityGroupRules []SecurityGroupRuleSpec `json:"allNodesSecurityGroupRules,omitempty"`
+
+	// ControlPlane rules are added to the security group of the control plane nodes.
+	// +optional
+	ControlPlane []SecurityGroupRuleSpec `json:"controlPlane,omitempty"`
+
+	// Worker rules are added to the security group of the worker nodes.
+	// +optional
+	Worker []SecurityGroupRuleSpec `json:"worker,omitempty"`
+
+	// ReplaceDefaultRules replaces the default ingress rules for the Kubernetes API server,
+	// the Kubelet, etcd, NodePort services and Calico with the user-defined rules. The
+	// egress rules and the SSH rules of the bastion are kept.
+	// +optional
+	ReplaceDefaultRules bool `json:"replaceDefaultRules,omitempty"`
+}
+
+// SecurityGroupRuleSpec is a user-defined rule of a managed security group. The remote
+// of the rule is either RemoteIPPrefix or the groups in RemoteManagedGroups and
+// RemoteSecurityGroups. If none of them is set, the rule applies to any remote.
+type SecurityGroupRuleSpec struct {
+	// Description of the rule.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Direction of the rule.
+	// +kubebuilder:validation:Enum=ingress;egress
+	Direction string `json:"direction"`
+
+	// EtherType of the rule. It defaults to the address family of RemoteIPPrefix, or
+	// IPv4 if RemoteIPPrefix is not set.
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	// +optional
+	EtherType string `json:"etherType,omitempty"`
+
+	// Protocol of the rule, for example tcp, udp or icmp. If it is not set, the rule
+	// applies to all protocols.
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// PortRangeMin is the first port of the range matched by the rule.
+	// +optional
+	PortRangeMin int `json:"portRangeMin,omitempty"`
+
+	// PortRangeMax is the last port of the range matched by the rule. It defaults to
+	// PortRangeMin.
+	// +optional
+	PortRangeMax int `json:"portRangeMax,omitempty"`
+
+	// RemoteIPPrefix is the CIDR of the remote matched by the rule.
+	// +optional
+	RemoteIPPrefix string `json:"remoteIPPrefix,omitempty"`
+
+	// RemoteManagedGroups are the managed security groups matched by the rule. One rule
+	// is created for each group. References to the bastion group are ignored while the
+	// bastion is disabled.
+	// +optional
+	RemoteManagedGroups []ManagedSecurityGroupName `json:"remoteManagedGroups,omitempty"`
+
+	// RemoteSecurityGroups are existing security groups matched by the rule, given by UUID,
+	// name or filter. One rule is created for each group found.
+	// +optional
+	RemoteSecurityGroups []SecurityGroupParam `json:"remoteSecurityGroups,omitempty"`
+}
+
+// IgnitionOptions configures how Ignition bootstrap data is passed to an instance.
+type IgnitionOptions struct {
+	// SwiftContainer is the Swift container in which Ignition configs larger
+	// than the Nova user data limit of 64KiB are stored. The instance then
+	// receives a small config which replaces itself with the stored config,
+	// fetched with a temporary URL. The container or the account must have
+	// a temp URL key. Stored configs expire after an hour.
+	// +optional
+	SwiftContainer string `json:"swiftContainer,omitempty"`
+}
+
+// ResourceNaming holds Go templates for the names of OpenStack resources
+// created by CAPO. All templates can refer to {{ .ClusterName }} and
+// {{ .Namespace }}; the listener and pool templates can also refer to
+// {{ .Port }}. Resources whose template is empty keep their default name.
+type ResourceNaming struct {
+	// Network is the name template of the cluster network and subnet.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// Router is the name template of the cluster router.
+	// +optional
+	Router string `json:"router,omitempty"`
+
+	// LoadBalancer is the name template of the API server load balancer.
+	// +optional
+	LoadBalancer string `json:"loadBalancer,omitempty"`
+
+	// Listener is the name template of the API server load balancer listeners.
+	// +optional
+	Listener string `json:"listener,omitempty"`
+
+	// Pool is the name template of the API server load balancer pools and
+	// their health monitors. Pool members are named after their pool.
+	// +optional
+	Pool string `json:"pool,omitempty"`
+
+	// FloatingIPDescription is the description template of floating IPs.
+	// +optional
+	FloatingIPDescription string `json:"floatingIPDescription,omitempty"`
+}
+
+// SSHPublicKeySecretReference is a reference to a secret holding an SSH
+// public key in the namespace of the referencing object.
+type SSHPublicKeySecretReference struct {
+	// Name of the secret.
+	Name string `json:"name"`
+
+	// Key of the public key in the secret. Defaults to "ssh-publickey".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ServerPasswordOptions configures how the admin password of a server is published.
+type ServerPasswordOptions struct {
+	// PrivateKeySecretRef references a secret holding the private key of
+	// SSHKeyName, which is used to decrypt the password. Only RSA keys can
+	// decrypt server passwords.
+	PrivateKeySecretRef SSHPrivateKeySecretReference `json:"privateKeySecretRef"`
+
+	// ClearAfterRetrieval clears the password from the metadata service once
+	// it has been published.
+	// +optional
+	ClearAfterRetrieval bool `json:"clearAfterRetrieval,omitempty"`
+}
+
+// MachineMetadataPropagation is an allowlist of Machine labels and annotations
+// which are mirrored into server metadata. A label is mirrored with the metadata
+// key k8s-label:<key>, and an annotation with k8s-annotation:<key>, where a "/"
+// in the key is replaced by ":" as Nova does not allow it in metadata keys.
+type MachineMetadataPropagation struct {
+	// Labels are the keys of the labels to mirror.
+	// +listType=set
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations are the keys of the annotations to mirror. Annotations whose
+	// value is longer than 255 characters are not mirrored.
+	// +listType=set
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+const (
+	// MachineLabelMetadataPrefix is the prefix of the server metadata keys of mirrored Machine labels.
+	MachineLabelMetadataPrefix = "k8s-label:"
+	// MachineAnnotationMetadataPrefix is the prefix of the server metadata keys of mirrored Machine annotations.
+	MachineAnnotationMetadataPrefix = "k8s-annotation:"
+)
+
+// SSHPrivateKeySecretReference is a reference to a secret holding an SSH
+// private key.
+type SSHPrivateKeySecretReference struct {
+	// Name of the secret.
+	Name string `json:"name"`
+
+	// Key of the private key in the secret. Defaults to "ssh-privatekey".
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // Network represents basic information about an OpenStack Neutron Network associated with an instance's port.
@@ -196,13 +976,31 @@ type Network struct {
 	//+optional
 	Tags []string `json:"tags,omitempty"`
 
-	Subnet   *Subnet   `json:"subnet,omitempty"`
+	Subnet *Subnet `json:"subnet,omitempty"`
+	// IPv6Subnet is the IPv6 subnet of a dual-stack network.
+	// +optional
+	IPv6Subnet *Subnet `json:"ipv6Subnet,omitempty"`
+	// ManagedSubnets are the additional subnets of the cluster network.
+	// +optional
+	ManagedSubnets []ManagedSubnetStatus `json:"managedSubnets,omitempty"`
+	// MTU is the MTU which was set on the network with networkMtu.
+	// +optional
+	MTU      int       `json:"mtu,omitempty"`
 	PortOpts *PortOpts `json:"port,omitempty"`
 	Router   *Router   `json:"router,omitempty"`
 
 	// Be careful when using APIServerLoadBalancer, because this field is optional and therefore not
 	// set in all cases
 	APIServerLoadBalancer *LoadBalancer `json:"apiServerLoadBalancer,omitempty"`
+
+	// AdditionalPortsLoadBalancer is the load balancer serving the additional ports of the
+	// API server load balancer, if they use a different flavor.
+	// +optional
+	AdditionalPortsLoadBalancer *LoadBalancer `json:"additionalPortsLoadBalancer,omitempty"`
+
+	// APIServerVIP is the port holding the managed VIP of the API server.
+	// +optional
+	APIServerVIP *APIServerVIP `json:"apiServerVIP,omitempty"`
 }
 
 // Subnet represents basic information about the associated OpenStack Neutron Subnet.
@@ -236,6 +1034,17 @@ type LoadBalancer struct {
 	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
 }
 
+// APIServerVIP is the port holding the managed VIP of the API server.
+type APIServerVIP struct {
+	// PortID is the ID of the port.
+	PortID string `json:"portID"`
+	// IP is the fixed IP of the port, which is the VIP.
+	IP string `json:"ip"`
+	// FloatingIP is the floating IP associated with the port, if any.
+	// +optional
+	FloatingIP string `json:"floatingIP,omitempty"`
+}
+
 // SecurityGroup represents the basic information of the associated
 // OpenStack Neutron Security Group.
 type SecurityGroup struct {
@@ -292,6 +1101,9 @@ var (
 
 	// InstanceStateDeleted is the string representing an instance in a deleted state.
 	InstanceStateDeleted = InstanceState("DELETED")
+
+	// InstanceStateUnknown is the string representing an instance whose host cannot be reached.
+	InstanceStateUnknown = InstanceState("UNKNOWN")
 )
 
 // Bastion represents basic information about the bastion node.
@@ -304,6 +1116,41 @@ type Bastion struct {
 
 	//+optional
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// UserData is the cloud-init user data passed to the bastion instance.
+	// It is stored in plain text in the OpenStackCluster, so it must not contain secrets.
+	// The bastion is rebuilt when it changes.
+	//+optional
+	UserData string `json:"userData,omitempty"`
+
+	// DNS configures a record in Designate pointing at the floating IP of the bastion.
+	// The record is kept when the bastion is re-created and updated to its new address.
+	//+optional
+	DNS *BastionDNS `json:"dns,omitempty"`
+}
+
+// BastionDNS is a record set in Designate for the floating IP of the bastion.
+type BastionDNS struct {
+	// Zone is the name of the Designate zone, e.g. example.com.
+	Zone string `json:"zone"`
+	// RecordName is the name template of the fully qualified name of the record, e.g.
+	// "{{ .ClusterName }}.bastion.example.com". It can refer to {{ .ClusterName }} and
+	// {{ .Namespace }}, and the name must be in the zone. Defaults to
+	// "<cluster name>-bastion.<zone>".
+	// +optional
+	RecordName string `json:"recordName,omitempty"`
+	// TTL is the time to live of the record in seconds. The TTL of the zone is used if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTL int `json:"ttl,omitempty"`
+}
+
+// DNSRecordReference identifies a record set created in Designate.
+type DNSRecordReference struct {
+	// Zone is the name of the Designate zone of the record.
+	Zone string `json:"zone"`
+	// Name is the fully qualified name of the record.
+	Name string `json:"name"`
 }
 
 type APIServerLoadBalancer struct {
@@ -311,6 +1158,415 @@ type APIServerLoadBalancer struct {
 	Enabled bool `json:"enabled,omitempty"`
 	// AdditionalPorts adds additional tcp ports to the load balancer.
 	AdditionalPorts []int `json:"additionalPorts,omitempty"`
+	// IPVersion is the IP version of the VIP of the load balancer. With 6, the VIP is
+	// created in the IPv6 subnet of the cluster network and the control plane machines
+	// are added as members with their IPv6 addresses. This requires nodeIPv6Subnet and,
+	// as floating IPs are IPv4, disableAPIServerFloatingIP. Defaults to 4.
+	// +kubebuilder:validation:Enum=4;6
+	// +optional
+	IPVersion int `json:"ipVersion,omitempty"`
 	// AllowedCIDRs restrict access to all API-Server listeners to the given address CIDRs.
+	// The bastion, cluster subnet and router IPs are added automatically. The CIDRs are
+	// reconciled on every reconcile, so changes made to the listeners outside of the spec are reverted.
 	AllowedCIDRs []string `json:"allowedCidrs,omitempty"`
+	// Provider is the name of the Octavia provider to create the load balancer with, e.g. amphora or ovn.
+	// If unspecified, amphora is used if it is available, otherwise the Octavia default provider.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// FlavorID is the ID of the Octavia flavor to create the load balancer with.
+	// +optional
+	FlavorID string `json:"flavorID,omitempty"`
+	// FlavorName is the name of the Octavia flavor to create the load balancer with.
+	// It cannot be set together with FlavorID.
+	// +optional
+	FlavorName string `json:"flavorName,omitempty"`
+	// AvailabilityZone is the Octavia availability zone to create the load balancer in, e.g. the
+	// availability zone of the control plane machines. It must be an enabled Octavia availability
+	// zone, which requires a provider other than ovn.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// AdditionalPortsFlavor is the Octavia flavor of the listeners of AdditionalPorts, e.g. a
+	// single amphora for ingress while the API server uses an active-standby flavor. As a flavor
+	// applies to a whole load balancer, the additional ports are served by a separate load
+	// balancer with its own VIP and floating IP if the flavor differs from the one of the API
+	// server load balancer. If unset, the additional ports share the API server load balancer.
+	// +optional
+	AdditionalPortsFlavor *LoadBalancerFlavor `json:"additionalPortsFlavor,omitempty"`
+	// HealthMonitor configures the health monitors of the load balancer pools.
+	// Changes are applied to existing health monitors.
+	// +optional
+	HealthMonitor *HealthMonitor `json:"healthMonitor,omitempty"`
+	// ConnectionLimit is the maximum number of connections of each listener, or -1 for no limit.
+	// Defaults to the Octavia default. Changes are applied to existing listeners.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit int `json:"connectionLimit,omitempty"`
+	// TimeoutClientData is the time in milliseconds after which an inactive client connection of a
+	// listener is closed, e.g. 3600000 for long-running kubectl exec and watch connections.
+	// Defaults to the Octavia default of 50000. Changes are applied to existing listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutClientData int `json:"timeoutClientData,omitempty"`
+	// TimeoutMemberData is the time in milliseconds after which an inactive connection of a listener
+	// to a member is closed. Defaults to the Octavia default of 50000. Changes are applied to existing
+	// listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutMemberData int `json:"timeoutMemberData,omitempty"`
+	// TimeoutMemberConnect is the time in milliseconds a listener waits for a connection to a member
+	// to be established. Defaults to the Octavia default of 5000. Changes are applied to existing
+	// listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutMemberConnect int `json:"timeoutMemberConnect,omitempty"`
+	// ExistingLoadBalancer references a load balancer which is managed outside of CAPO.
+	// If set, CAPO only manages the members of the default pools of its listeners for the
+	// control plane machines, and never creates or deletes the load balancer, its listeners,
+	// pools, health monitors or floating IP.
+	// +optional
+	ExistingLoadBalancer *LoadBalancerReference `json:"existingLoadBalancer,omitempty"`
+	// Shared exposes the API server through a load balancer which is shared with other clusters.
+	// CAPO creates a pool for the control plane machines on the shared load balancer and an L7
+	// policy routing requests for Hostname to it, and never creates or deletes the load balancer,
+	// its listeners or floating IP.
+	// +optional
+	Shared *SharedLoadBalancer `json:"shared,omitempty"`
+	// TLS terminates TLS on the API server listener of the load balancer with a certificate
+	// stored in Barbican. The listener uses the TERMINATED_HTTPS protocol and re-encrypts the
+	// traffic to the API servers. Requires an Octavia provider which supports TLS termination.
+	// +optional
+	TLS *LoadBalancerTLS `json:"tls,omitempty"`
+}
+
+// LoadBalancerFlavor references an Octavia flavor by ID or name.
+type LoadBalancerFlavor struct {
+	// ID is the ID of the flavor.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Name is the name of the flavor. It cannot be set together with ID.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// LoadBalancerTLS references the certificate which the API server listener presents.
+type LoadBalancerTLS struct {
+	// CertificateRef is the URL of the Barbican certificate container, or of the Barbican secret
+	// holding a PKCS12 bundle, with the certificate, its private key and any intermediates.
+	// +optional
+	CertificateRef string `json:"certificateRef,omitempty"`
+	// CertificateName is the name of the Barbican certificate container, or of the Barbican secret
+	// holding a PKCS12 bundle, if no certificate container has the name. It must be unique in the
+	// project. It cannot be set together with CertificateRef.
+	// +optional
+	CertificateName string `json:"certificateName,omitempty"`
+}
+
+// SharedLoadBalancer describes how the API server is exposed through a load balancer shared
+// by several clusters.
+type SharedLoadBalancer struct {
+	// LoadBalancer references the shared load balancer.
+	LoadBalancer LoadBalancerReference `json:"loadBalancer"`
+	// Port is the port of the shared listener. The listener must use the TERMINATED_HTTPS
+	// protocol and present a certificate which is valid for Hostname.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+	// Hostname is the host name requests for the API server of the cluster are routed by.
+	// It is used as the host of the control plane endpoint.
+	Hostname string `json:"hostname"`
+}
+
+// LoadBalancerReference references an Octavia load balancer by ID or name.
+type LoadBalancerReference struct {
+	// ID is the ID of the load balancer.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Name is the name of the load balancer. It must be unique in the project.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ControlPlaneEndpointDNS is a record set in Designate for the control plane endpoint.
+type ControlPlaneEndpointDNS struct {
+	// Zone is the name of the Designate zone, e.g. example.com.
+	Zone string `json:"zone"`
+	// RecordName is the fully qualified name of the record, e.g. api.mycluster.example.com.
+	// It must be in the zone.
+	RecordName string `json:"recordName"`
+	// TTL is the time to live of the record in seconds. The TTL of the zone is used if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTL int `json:"ttl,omitempty"`
+}
+
+// BlueprintExportKind is the kind of object the blueprint of a cluster is exported to.
+// +kubebuilder:validation:Enum=ConfigMap;Secret
+type BlueprintExportKind string
+
+const (
+	BlueprintExportConfigMap BlueprintExportKind = "ConfigMap"
+	BlueprintExportSecret    BlueprintExportKind = "Secret"
+)
+
+// BlueprintExport configures the export of the blueprint of a cluster, i.e. the OpenStack resources of the cluster
+// with their IDs, names and security group rules, to an object in the namespace of the cluster.
+type BlueprintExport struct {
+	// Kind is the kind of object the blueprint is exported to. A Secret should be used if the names and
+	// addresses of the resources are confidential.
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind BlueprintExportKind `json:"kind,omitempty"`
+	// Name is the name of the object. It defaults to the name of the OpenStackCluster with the suffix -blueprint.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// GarbageCollection configures the garbage collection of the orphaned OpenStack resources of a cluster, i.e. the
+// ports, trunks, floating IPs and volumes CAPO created for the cluster which are not used by a server and not
+// referenced by any object of the cluster.
+type GarbageCollection struct {
+	// DryRun only reports orphaned resources with events and in the status of the
+	// cluster instead of deleting them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// GracePeriod is how long a resource must have been found orphaned before it is
+	// deleted, so that resources which are being created are not deleted. Defaults to 1h.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// Interval is the time between two runs of the garbage collection. Defaults to 10m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// GarbageCollectionStatus is the state of the garbage collection of a cluster.
+type GarbageCollectionStatus struct {
+	// LastRunTime is the time of the last run of the garbage collection.
+	LastRunTime metav1.Time `json:"lastRunTime"`
+
+	// Orphans are the orphaned resources found by the last run which have not been
+	// deleted, because their grace period has not passed yet or in dry-run mode.
+	// +optional
+	Orphans []OrphanedResource `json:"orphans,omitempty"`
+}
+
+// OrphanedResource is an OpenStack resource of a cluster which is not referenced by any object.
+type OrphanedResource struct {
+	// Type is the type of the resource, i.e. port, trunk, floatingip or volume.
+	Type string `json:"type"`
+
+	// ID is the ID of the resource.
+	ID string `json:"id"`
+
+	// Name is the name of the resource, or the address of a floating IP.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// FoundAt is the time the resource was first found orphaned.
+	FoundAt metav1.Time `json:"foundAt"`
+}
+
+// PlannedAction is the kind of change of a planned operation.
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type PlannedAction string
+
+const (
+	PlannedActionCreate PlannedAction = "Create"
+	PlannedActionUpdate PlannedAction = "Update"
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// Plan records the operations on OpenStack resources which the controller would
+// perform while the PlanAnnotation is set.
+type Plan struct {
+	// Time is when the plan was computed.
+	Time metav1.Time `json:"time"`
+
+	// Operations are the planned operations, in the order they would be performed.
+	// +optional
+	Operations []PlannedOperation `json:"operations,omitempty"`
+}
+
+// PlannedOperation is an operation on an OpenStack resource which the controller would perform.
+type PlannedOperation struct {
+	// Action is the kind of change.
+	Action PlannedAction `json:"action"`
+
+	// Resource is the type of the resource, e.g. network or server.
+	Resource string `json:"resource"`
+
+	// Name is the name of the resource, or the address of a floating IP.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ID is the ID of the resource if it exists.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Message describes the operation.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FloatingIPClaim records the use of an address of an OpenStackFloatingIPPool.
+type FloatingIPClaim struct {
+	// Address is the claimed floating IP.
+	Address string `json:"address"`
+	// ClusterName is the name of the OpenStackCluster which claimed the address.
+	// It is only set in the status of the pool.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// Use is what the address is used for, either apiserver or bastion.
+	Use string `json:"use"`
+}
+
+// AdditionalFloatingIP is a floating IP which is allocated for the cluster.
+type AdditionalFloatingIP struct {
+	// Name identifies the floating IP in the cluster, e.g. ingress.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Description is the purpose of the floating IP, which is added to the
+	// description of the floating IP in OpenStack.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// FloatingIP is the address to allocate, which requires admin rights by
+	// default. If not set, Neutron allocates any free address.
+	// +optional
+	FloatingIP string `json:"floatingIP,omitempty"`
+}
+
+// AdditionalFloatingIPStatus is a floating IP which was allocated for the cluster.
+type AdditionalFloatingIPStatus struct {
+	// Name is the name of the additional floating IP in the spec of the cluster.
+	Name string `json:"name"`
+
+	// ID is the ID of the floating IP.
+	ID string `json:"id"`
+
+	// FloatingIP is the address of the floating IP.
+	FloatingIP string `json:"floatingIP"`
+}
+
+// HealthMonitorType is the type of an Octavia health monitor.
+// +kubebuilder:validation:Enum=TCP;HTTP;HTTPS;PING;TLS-HELLO
+type HealthMonitorType string
+
+const (
+	HealthMonitorTypeTCP      HealthMonitorType = "TCP"
+	HealthMonitorTypeHTTP     HealthMonitorType = "HTTP"
+	HealthMonitorTypeHTTPS    HealthMonitorType = "HTTPS"
+	HealthMonitorTypePING     HealthMonitorType = "PING"
+	HealthMonitorTypeTLSHello HealthMonitorType = "TLS-HELLO"
+)
+
+// HealthMonitor configures an Octavia health monitor.
+type HealthMonitor struct {
+	// Type is the type of the health monitor. Defaults to TCP.
+	// +optional
+	Type HealthMonitorType `json:"type,omitempty"`
+	// Delay is the time in seconds between probes of the pool members. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Delay int `json:"delay,omitempty"`
+	// Timeout is the time in seconds a probe waits for a reply. It must be less than Delay. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+	// MaxRetries is the number of successful probes before a member is marked online. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// MaxRetriesDown is the number of failed probes before a member is marked offline.
+	// Defaults to the Octavia default.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetriesDown int `json:"maxRetriesDown,omitempty"`
+	// URLPath is the path probed by HTTP and HTTPS health monitors.
+	// Defaults to the Octavia default.
+	// +optional
+	URLPath string `json:"urlPath,omitempty"`
+}
+
+// RemediationStrategy is the strategy used to remediate a machine whose node is not healthy.
+type RemediationStrategy string
+
+const (
+	// RemediationStrategyReplace replaces the machine.
+	RemediationStrategyReplace = RemediationStrategy("Replace")
+
+	// RemediationStrategyInPlace first hard reboots the server of the machine, then
+	// rebuilds it with the same image, and replaces the machine only if its node is
+	// still not healthy.
+	RemediationStrategyInPlace = RemediationStrategy("InPlace")
+)
+
+// RemediationStep is a step of the in-place remediation of a machine.
+type RemediationStep string
+
+const (
+	// RemediationStepReboot is a hard reboot of the server.
+	RemediationStepReboot = RemediationStep("Reboot")
+
+	// RemediationStepRebuild is a rebuild of the server with its image.
+	RemediationStepRebuild = RemediationStep("Rebuild")
+
+	// RemediationStepReplace marks the machine for replacement by its owner.
+	RemediationStepReplace = RemediationStep("Replace")
+)
+
+// SnapshotBeforeDelete configures the snapshot image of the server of a machine
+// which is created before the server is deleted.
+type SnapshotBeforeDelete struct {
+	// Enabled creates the snapshot. A machine can set it to false to disable
+	// the snapshot configured for the cluster.
+	Enabled bool `json:"enabled"`
+
+	// Name is the Go template of the name of the snapshot image, which can
+	// refer to {{ .MachineName }}, {{ .ClusterName }} and {{ .Namespace }}.
+	// Defaults to "<machine name>-snapshot".
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Expiry is how long the snapshot is needed. The time it expires is
+	// recorded in the capo-expires-at property of the image as an RFC 3339
+	// timestamp, so that it can be cleaned up. CAPO does not delete snapshots.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+
+	// Timeout is how long the deletion of the server waits for the snapshot
+	// to become active. The server is deleted without a complete snapshot
+	// once it has passed or if the snapshot fails. Defaults to 30m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// MachineRemediation configures the remediation of a machine whose node is not healthy.
+type MachineRemediation struct {
+	// Strategy is the remediation strategy. With InPlace, a machine whose node has
+	// not been healthy for Timeout is hard rebooted, then rebuilt with the same image
+	// and only marked for replacement if its node is still not healthy Timeout after
+	// each step. Servers booted from volume and control plane machines are not rebuilt.
+	// +kubebuilder:validation:Enum=Replace;InPlace
+	// +kubebuilder:default=Replace
+	// +optional
+	Strategy RemediationStrategy `json:"strategy,omitempty"`
+
+	// Timeout is how long the node must be unhealthy before the first step is taken,
+	// and how long each step is given to make it healthy again. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Evacuate makes CAPO evacuate the server to another hypervisor when its
+	// hypervisor is reported down, which keeps its ports, volumes and IP
+	// addresses. This requires the server to be booted from volume or the
+	// hypervisors to use shared storage, and the identity to be allowed to list
+	// hypervisors and evacuate servers, which are admin actions by default.
+	// +optional
+	Evacuate bool `json:"evacuate,omitempty"`
 }
@@ -17,11 +17,29 @@ limitations under the License.
 package v1alpha6
 
 import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
+// traitNameRegexp matches the names of standard and custom traits in Placement.
+var traitNameRegexp = regexp.MustCompile(`^[A-Z0-9_]{1,255}$`)
+
+// maxServerMetadataKeyLength is the maximum length of a server metadata key accepted by Nova.
+const maxServerMetadataKeyLength = 255
+
 func aggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
@@ -33,3 +51,1221 @@ func aggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorLis
 		allErrs,
 	)
 }
+
+// setStorageVersion marks an object as written in this API version. Every create and update is
+// persisted in the storage version, so objects passing through the webhook need no rewrite.
+func setStorageVersion(meta *metav1.ObjectMeta) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[StorageVersionAnnotation] = GroupVersion.Version
+}
+
+// defaultOpenStackClusterSpec defaults the spec of an OpenStackCluster or OpenStackClusterTemplate. The Cluster
+// topology controller computes OpenStackClusters from the defaulted templates of a ClusterClass, so both must be
+// defaulted alike, and only unset fields may be defaulted: defaulting must be idempotent, or each dry-run of the
+// topology controller would see a change.
+func defaultOpenStackClusterSpec(spec *OpenStackClusterSpec) {
+	if spec.IdentityRef != nil && spec.IdentityRef.Kind == "" {
+		spec.IdentityRef.Kind = defaultIdentityRefKind
+	}
+}
+
+func validateResourceNaming(naming *ResourceNaming, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if naming == nil {
+		return allErrs
+	}
+
+	// The templates are rendered like by the controller, so that unknown fields and empty names are rejected
+	// instead of failing every reconcile.
+	data := names.TemplateData{ClusterName: "cluster", Namespace: "default", Port: 6443}
+	templates := []struct {
+		name    string
+		tmpl    string
+		perPort bool
+	}{
+		{"network", naming.Network, false},
+		{"router", naming.Router, false},
+		{"loadBalancer", naming.LoadBalancer, false},
+		{"listener", naming.Listener, true},
+		{"pool", naming.Pool, true},
+		{"floatingIPDescription", naming.FloatingIPDescription, false},
+	}
+	for _, t := range templates {
+		if t.tmpl == "" {
+			continue
+		}
+		name, err := names.Render(t.tmpl, "", data)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(t.name), t.tmpl, err.Error()))
+			continue
+		}
+		// The listeners and pools of the API server port and the additional ports are found by their names
+		if t.perPort {
+			otherPortData := data
+			otherPortData.Port = 8443
+			if otherPortName, err := names.Render(t.tmpl, "", otherPortData); err == nil && otherPortName == name {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(t.name), t.tmpl, "must include {{ .Port }}, as the name must differ for each port"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateHealthMonitor(monitor *HealthMonitor, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if monitor == nil {
+		return allErrs
+	}
+
+	// Compare against the defaults applied by the load balancer service.
+	delay, timeout := monitor.Delay, monitor.Timeout
+	if delay == 0 {
+		delay = 30
+	}
+	if timeout == 0 {
+		timeout = 5
+	}
+	if timeout >= delay {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), timeout, fmt.Sprintf("must be less than delay (%d)", delay)))
+	}
+
+	if monitor.URLPath != "" && monitor.Type != HealthMonitorTypeHTTP && monitor.Type != HealthMonitorTypeHTTPS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("urlPath"), "can only be set for HTTP and HTTPS health monitors"))
+	}
+	return allErrs
+}
+
+func validateExistingLoadBalancer(lb *APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	ref := lb.ExistingLoadBalancer
+	if ref == nil {
+		return allErrs
+	}
+	refPath := fldPath.Child("existingLoadBalancer")
+
+	if !lb.Enabled {
+		allErrs = append(allErrs, field.Forbidden(refPath, "requires the API server load balancer to be enabled"))
+	}
+	if (ref.ID == "") == (ref.Name == "") {
+		allErrs = append(allErrs, field.Invalid(refPath, ref, "exactly one of id or name must be set"))
+	}
+
+	// These fields configure resources which are not managed for an existing load balancer.
+	if lb.Provider != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("provider"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.FlavorID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("flavorID"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.FlavorName != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("flavorName"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.AvailabilityZone != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("availabilityZone"), "cannot be set with existingLoadBalancer"))
+	}
+	if len(lb.AllowedCIDRs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("allowedCidrs"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.HealthMonitor != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthMonitor"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.TLS != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("tls"), "cannot be set with existingLoadBalancer"))
+	}
+	if lb.AdditionalPortsFlavor != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalPortsFlavor"), "cannot be set with existingLoadBalancer"))
+	}
+	return allErrs
+}
+
+func validateSharedLoadBalancer(lb *APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	shared := lb.Shared
+	if shared == nil {
+		return allErrs
+	}
+	sharedPath := fldPath.Child("shared")
+
+	if !lb.Enabled {
+		allErrs = append(allErrs, field.Forbidden(sharedPath, "requires the API server load balancer to be enabled"))
+	}
+	if lb.ExistingLoadBalancer != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("existingLoadBalancer"), "cannot be set together with shared"))
+	}
+	if (shared.LoadBalancer.ID == "") == (shared.LoadBalancer.Name == "") {
+		allErrs = append(allErrs, field.Invalid(sharedPath.Child("loadBalancer"), shared.LoadBalancer, "exactly one of id or name must be set"))
+	}
+	if shared.Hostname == "" {
+		allErrs = append(allErrs, field.Required(sharedPath.Child("hostname"), "hostname is required"))
+	}
+
+	// These fields configure resources which are not managed for a shared load balancer.
+	if lb.Provider != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("provider"), "cannot be set with shared"))
+	}
+	if lb.FlavorID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("flavorID"), "cannot be set with shared"))
+	}
+	if lb.FlavorName != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("flavorName"), "cannot be set with shared"))
+	}
+	if lb.AvailabilityZone != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("availabilityZone"), "cannot be set with shared"))
+	}
+	if len(lb.AllowedCIDRs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("allowedCidrs"), "cannot be set with shared"))
+	}
+	if len(lb.AdditionalPorts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalPorts"), "cannot be set with shared"))
+	}
+	if lb.TLS != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("tls"), "cannot be set with shared"))
+	}
+	if lb.AdditionalPortsFlavor != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalPortsFlavor"), "cannot be set with shared"))
+	}
+	return allErrs
+}
+
+// validateListenerSettings validates that the connection limit and timeouts of the listeners are only set for a
+// load balancer whose listeners are created by CAPO, and that the timeouts are not set for the ovn provider, which
+// does not support them.
+func validateListenerSettings(lb *APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	timeouts := lb.TimeoutClientData != 0 || lb.TimeoutMemberData != 0 || lb.TimeoutMemberConnect != 0
+	if lb.ConnectionLimit == 0 && !timeouts {
+		return allErrs
+	}
+	switch {
+	case lb.ExistingLoadBalancer != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "connectionLimit and listener timeouts cannot be set with existingLoadBalancer"))
+	case lb.Shared != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "connectionLimit and listener timeouts cannot be set with shared"))
+	case timeouts && lb.Provider == "ovn":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "listener timeouts are not supported by the ovn provider"))
+	}
+	return allErrs
+}
+
+// validateAdditionalPorts validates that the additional ports of the load balancer are valid ports which are
+// different from each other and from the API server port, as each gets its own listener on the VIP.
+func validateAdditionalPorts(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	portsPath := fldPath.Child("apiServerLoadBalancer", "additionalPorts")
+
+	apiServerPort := 6443
+	switch {
+	case spec.ControlPlaneEndpoint.Port != 0:
+		apiServerPort = int(spec.ControlPlaneEndpoint.Port)
+	case spec.APIServerPort != 0:
+		apiServerPort = spec.APIServerPort
+	}
+
+	seen := map[int]bool{}
+	for i, port := range spec.APIServerLoadBalancer.AdditionalPorts {
+		switch {
+		case port < 1 || port > 65535:
+			allErrs = append(allErrs, field.Invalid(portsPath.Index(i), port, "must be between 1 and 65535"))
+		case port == apiServerPort:
+			allErrs = append(allErrs, field.Invalid(portsPath.Index(i), port, "must be different from the API server port"))
+		case seen[port]:
+			allErrs = append(allErrs, field.Duplicate(portsPath.Index(i), port))
+		}
+		seen[port] = true
+	}
+	return allErrs
+}
+
+func validateAdditionalPortsFlavor(lb *APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	flavor := lb.AdditionalPortsFlavor
+	if flavor == nil {
+		return allErrs
+	}
+	flavorPath := fldPath.Child("additionalPortsFlavor")
+
+	if len(lb.AdditionalPorts) == 0 {
+		allErrs = append(allErrs, field.Forbidden(flavorPath, "requires additionalPorts"))
+	}
+	if (flavor.ID == "") == (flavor.Name == "") {
+		allErrs = append(allErrs, field.Invalid(flavorPath, flavor, "exactly one of id or name must be set"))
+	}
+	return allErrs
+}
+
+func validateLoadBalancerTLS(lb *APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	tls := lb.TLS
+	if tls == nil {
+		return allErrs
+	}
+	tlsPath := fldPath.Child("tls")
+
+	if !lb.Enabled {
+		allErrs = append(allErrs, field.Forbidden(tlsPath, "requires the API server load balancer to be enabled"))
+	}
+	if (tls.CertificateRef == "") == (tls.CertificateName == "") {
+		allErrs = append(allErrs, field.Invalid(tlsPath, tls, "exactly one of certificateRef or certificateName must be set"))
+	}
+	return allErrs
+}
+
+// validateLoadBalancerSwitch validates switching the API server of an existing cluster between a
+// load balancer and a fixed endpoint. The address of the control plane endpoint cannot change, so it
+// must be either a floating IP or a fixed IP which is moved between the load balancer and the machines.
+func validateLoadBalancerSwitch(old, spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	lbPath := fldPath.Child("apiServerLoadBalancer")
+
+	if old.APIServerLoadBalancer.Shared != nil || spec.APIServerLoadBalancer.Shared != nil {
+		allErrs = append(allErrs, field.Forbidden(lbPath.Child("enabled"), "cannot be changed for a shared load balancer"))
+	}
+	if old.APIServerLoadBalancer.ExistingLoadBalancer != nil || spec.APIServerLoadBalancer.ExistingLoadBalancer != nil {
+		allErrs = append(allErrs, field.Forbidden(lbPath.Child("enabled"), "cannot be changed for an existing load balancer"))
+	}
+	if spec.DisableAPIServerFloatingIP && spec.APIServerFixedIP == "" {
+		allErrs = append(allErrs, field.Forbidden(lbPath.Child("enabled"), "cannot be changed without a floating IP or apiServerFixedIP"))
+	}
+
+	allErrs = append(allErrs, validateHealthMonitor(spec.APIServerLoadBalancer.HealthMonitor, lbPath.Child("healthMonitor"))...)
+	allErrs = append(allErrs, validateLoadBalancerTLS(&spec.APIServerLoadBalancer, lbPath)...)
+	allErrs = append(allErrs, validateAdditionalPortsFlavor(&spec.APIServerLoadBalancer, lbPath)...)
+	allErrs = append(allErrs, validateAdditionalPorts(spec, fldPath)...)
+	allErrs = append(allErrs, validateIPv6(spec, fldPath)...)
+	if spec.APIServerLoadBalancer.FlavorID != "" && spec.APIServerLoadBalancer.FlavorName != "" {
+		allErrs = append(allErrs, field.Forbidden(lbPath.Child("flavorName"), "cannot be set together with flavorID"))
+	}
+	return allErrs
+}
+
+func validateControlPlaneEndpointDNS(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	dns := spec.ControlPlaneEndpointDNS
+	if dns == nil {
+		return allErrs
+	}
+	dnsPath := fldPath.Child("controlPlaneEndpointDNS")
+
+	zone := strings.TrimSuffix(dns.Zone, ".")
+	recordName := strings.TrimSuffix(dns.RecordName, ".")
+	if zone == "" {
+		allErrs = append(allErrs, field.Required(dnsPath.Child("zone"), "zone is required"))
+	}
+	if recordName == "" {
+		allErrs = append(allErrs, field.Required(dnsPath.Child("recordName"), "recordName is required"))
+	} else if zone != "" && !strings.HasSuffix(recordName, "."+zone) {
+		allErrs = append(allErrs, field.Invalid(dnsPath.Child("recordName"), dns.RecordName, fmt.Sprintf("must be in zone %s", dns.Zone)))
+	}
+
+	// The host of the control plane endpoint is the name of the record.
+	if spec.ControlPlaneEndpoint.Host != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("controlPlaneEndpoint"), "cannot be set together with controlPlaneEndpointDNS"))
+	}
+	if spec.APIServerLoadBalancer.Shared != nil {
+		allErrs = append(allErrs, field.Forbidden(dnsPath, "cannot be set together with a shared load balancer"))
+	}
+	return allErrs
+}
+
+// validateBastionDNS validates the DNS record of the bastion. The record name is a naming
+// template, so it can only be checked to end with the zone.
+func validateBastionDNS(dns *BastionDNS, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if dns == nil {
+		return allErrs
+	}
+
+	zone := strings.TrimSuffix(dns.Zone, ".")
+	if zone == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("zone"), "zone is required"))
+	}
+	if dns.RecordName == "" {
+		return allErrs
+	}
+	if _, err := template.New("recordName").Parse(dns.RecordName); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), dns.RecordName, err.Error()))
+	} else if zone != "" && !strings.HasSuffix(strings.TrimSuffix(dns.RecordName, "."), "."+zone) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), dns.RecordName, fmt.Sprintf("must be in zone %s", dns.Zone)))
+	}
+	return allErrs
+}
+
+// validateIPv6 validates the IPv6 subnet of the cluster network and the IP version of the
+// API server load balancer.
+func validateIPv6(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if subnet := spec.NodeIPv6Subnet; subnet != nil {
+		subnetPath := fldPath.Child("nodeIPv6Subnet")
+		if spec.NodeCIDR == "" {
+			allErrs = append(allErrs, field.Forbidden(subnetPath, "can only be set together with nodeCidr"))
+		}
+		if ip, _, err := net.ParseCIDR(subnet.CIDR); err != nil || ip.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidr"), subnet.CIDR, "must be an IPv6 CIDR"))
+		}
+	}
+
+	if spec.APIServerLoadBalancer.IPVersion == 6 {
+		ipVersionPath := fldPath.Child("apiServerLoadBalancer", "ipVersion")
+		if spec.NodeIPv6Subnet == nil {
+			allErrs = append(allErrs, field.Forbidden(ipVersionPath, "an IPv6 VIP requires nodeIPv6Subnet"))
+		}
+		if !spec.DisableAPIServerFloatingIP {
+			allErrs = append(allErrs, field.Forbidden(ipVersionPath, "an IPv6 VIP requires disableAPIServerFloatingIP"))
+		}
+	}
+	return allErrs
+}
+
+// validateManagedSubnets validates the managed subnets of the cluster network.
+func validateManagedSubnets(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(spec.ManagedSubnets) > 0 && spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedSubnets"), "can only be set together with nodeCidr"))
+	}
+
+	names := make(map[string]bool)
+	for i, subnet := range spec.ManagedSubnets {
+		subnetPath := fldPath.Child("managedSubnets").Index(i)
+		switch {
+		case names[subnet.Name]:
+			allErrs = append(allErrs, field.Duplicate(subnetPath.Child("name"), subnet.Name))
+		case subnet.Name == "ipv6":
+			// The subnet would be named like the IPv6 subnet of the cluster network
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("name"), subnet.Name, "is reserved"))
+		}
+		names[subnet.Name] = true
+
+		ip, cidr, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidr"), subnet.CIDR, "must be an IPv4 CIDR"))
+			continue
+		}
+		inCIDR := func(address string) bool {
+			ip := net.ParseIP(address)
+			return ip != nil && cidr.Contains(ip)
+		}
+		if subnet.GatewayIP != "" && !inCIDR(subnet.GatewayIP) {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("gatewayIP"), subnet.GatewayIP, "must be an address of the CIDR"))
+		}
+		for j, pool := range subnet.AllocationPools {
+			poolPath := subnetPath.Child("allocationPools").Index(j)
+			if !inCIDR(pool.Start) {
+				allErrs = append(allErrs, field.Invalid(poolPath.Child("start"), pool.Start, "must be an address of the CIDR"))
+			}
+			if !inCIDR(pool.End) {
+				allErrs = append(allErrs, field.Invalid(poolPath.Child("end"), pool.End, "must be an address of the CIDR"))
+			}
+		}
+		for j, route := range subnet.HostRoutes {
+			routePath := subnetPath.Child("hostRoutes").Index(j)
+			if ip, _, err := net.ParseCIDR(route.Destination); err != nil || ip.To4() == nil {
+				allErrs = append(allErrs, field.Invalid(routePath.Child("destination"), route.Destination, "must be an IPv4 CIDR"))
+			}
+			if ip := net.ParseIP(route.NextHop); ip == nil || ip.To4() == nil {
+				allErrs = append(allErrs, field.Invalid(routePath.Child("nextHop"), route.NextHop, "must be an IPv4 address"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateRouter validates the existing router of the cluster network.
+func validateRouter(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.Router == nil {
+		return allErrs
+	}
+	routerPath := fldPath.Child("router")
+	if spec.NodeCIDR == "" && !spec.ExternallyManagedNetwork {
+		allErrs = append(allErrs, field.Forbidden(routerPath, "can only be set together with nodeCidr or externallyManagedNetwork"))
+	}
+	if *spec.Router == (RouterFilter{}) {
+		allErrs = append(allErrs, field.Required(routerPath, "must select a router"))
+	}
+	if len(spec.ExternalRouterIPs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalRouterIPs"), "cannot be set together with router"))
+	}
+	return allErrs
+}
+
+// validateRouterExternalGateway validates that the external gateway is only configured for a
+// router created by CAPO.
+func validateRouterExternalGateway(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.RouterExternalGateway != nil && spec.Router != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("routerExternalGateway"), "cannot be set together with router"))
+	}
+	return allErrs
+}
+
+// validateExternallyManagedNetwork validates that an externally managed network only selects existing
+// resources, and does not configure any resource which CAPO would create or change.
+func validateExternallyManagedNetwork(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !spec.ExternallyManagedNetwork {
+		return allErrs
+	}
+
+	if spec.Network == (NetworkFilter{}) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("network"), "must select the network with externallyManagedNetwork"))
+	}
+	if spec.Subnet == (SubnetFilter{}) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("subnet"), "must select the subnet with externallyManagedNetwork"))
+	}
+	if spec.APIServerLoadBalancer.Enabled && spec.APIServerLoadBalancer.ExistingLoadBalancer == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiServerLoadBalancer", "existingLoadBalancer"), "must be set with externallyManagedNetwork if the load balancer is enabled"))
+	}
+
+	// These fields configure resources which are created or changed by CAPO.
+	if spec.NodeCIDR != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeCidr"), "cannot be set with externallyManagedNetwork"))
+	}
+	if spec.NodeIPv6Subnet != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeIPv6Subnet"), "cannot be set with externallyManagedNetwork"))
+	}
+	if len(spec.ManagedSubnets) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedSubnets"), "cannot be set with externallyManagedNetwork"))
+	}
+	if spec.ManagedSecurityGroups {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedSecurityGroups"), "cannot be set with externallyManagedNetwork"))
+	}
+	if spec.RouterExternalGateway != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("routerExternalGateway"), "cannot be set with externallyManagedNetwork"))
+	}
+	if len(spec.ExternalRouterIPs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalRouterIPs"), "cannot be set with externallyManagedNetwork"))
+	}
+	if spec.NetworkMTU != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("networkMtu"), "cannot be set with externallyManagedNetwork"))
+	}
+	if spec.DisablePortSecurity {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disablePortSecurity"), "cannot be set with externallyManagedNetwork"))
+	}
+	return allErrs
+}
+
+// validateManagedAPIServerVIP validates that the managed VIP of the API server is not combined with a load balancer,
+// which holds the VIP itself.
+func validateManagedAPIServerVIP(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !spec.ManagedAPIServerVIP {
+		return allErrs
+	}
+
+	if spec.APIServerLoadBalancer.Enabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("apiServerLoadBalancer", "enabled"), "cannot be set with managedAPIServerVIP"))
+	}
+	if spec.APIServerFixedIP != "" && net.ParseIP(spec.APIServerFixedIP) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiServerFixedIP"), spec.APIServerFixedIP, "must be an IP address"))
+	}
+	return allErrs
+}
+
+// validateMachineMetadataPropagation validates the keys of the labels and annotations mirrored
+// into server metadata.
+func validateMachineMetadataPropagation(propagation *MachineMetadataPropagation, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if propagation == nil {
+		return allErrs
+	}
+	validateKeys := func(keys []string, prefix string, keysPath *field.Path) {
+		for i, key := range keys {
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(keysPath.Index(i), key, msg))
+			}
+			if len(prefix)+len(key) > maxServerMetadataKeyLength {
+				allErrs = append(allErrs, field.TooLong(keysPath.Index(i), key, maxServerMetadataKeyLength-len(prefix)))
+			}
+		}
+	}
+	validateKeys(propagation.Labels, MachineLabelMetadataPrefix, fldPath.Child("labels"))
+	validateKeys(propagation.Annotations, MachineAnnotationMetadataPrefix, fldPath.Child("annotations"))
+	return allErrs
+}
+
+// validateNetworkMTU validates the MTU of the cluster network.
+func validateNetworkMTU(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.NetworkMTU != 0 && spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("networkMtu"), "can only be set together with nodeCidr"))
+	}
+	return allErrs
+}
+
+// validateProviderNetwork validates that a provider network is created by CAPO, and that the cluster
+// does not use the router or floating IPs, as its nodes are directly routable.
+func validateProviderNetwork(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	providerNetwork := spec.ProviderNetwork
+	if providerNetwork == nil {
+		return allErrs
+	}
+	providerNetworkPath := fldPath.Child("providerNetwork")
+
+	if spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(providerNetworkPath, "can only be set together with nodeCidr"))
+	}
+	if providerNetwork.NetworkType == ProviderNetworkTypeFlat && providerNetwork.SegmentationID != 0 {
+		allErrs = append(allErrs, field.Forbidden(providerNetworkPath.Child("segmentationID"), "cannot be set for a flat network"))
+	}
+	if !spec.DisableAPIServerFloatingIP {
+		allErrs = append(allErrs, field.Required(fldPath.Child("disableAPIServerFloatingIP"), "must be set with providerNetwork"))
+	}
+
+	// These fields configure the router or floating IPs, which are not used on a provider network.
+	if spec.Router != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("router"), "cannot be set with providerNetwork"))
+	}
+	if spec.RouterExternalGateway != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("routerExternalGateway"), "cannot be set with providerNetwork"))
+	}
+	if len(spec.ExternalRouterIPs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalRouterIPs"), "cannot be set with providerNetwork"))
+	}
+	if spec.APIServerFloatingIP != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("apiServerFloatingIP"), "cannot be set with providerNetwork"))
+	}
+	if spec.FloatingIPPoolRef != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("floatingIPPoolRef"), "cannot be set with providerNetwork"))
+	}
+	if len(spec.AdditionalFloatingIPs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalFloatingIPs"), "cannot be set with providerNetwork"))
+	}
+	if spec.Bastion != nil {
+		if spec.Bastion.Instance.FloatingIP != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("bastion", "instance", "floatingIP"), "cannot be set with providerNetwork"))
+		}
+		if spec.Bastion.DNS != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("bastion", "dns"), "cannot be set with providerNetwork, as it points at the floating IP of the bastion"))
+		}
+	}
+	return allErrs
+}
+
+// validateManagedSecurityGroupRules validates the user-defined rules of the managed security groups.
+func validateManagedSecurityGroupRules(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	groupRules := spec.ManagedSecurityGroupRules
+	if groupRules == nil {
+		return allErrs
+	}
+	fldPath = fldPath.Child("managedSecurityGroupRules")
+	if !spec.ManagedSecurityGroups {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "can only be set together with managedSecurityGroups"))
+		return allErrs
+	}
+
+	for i := range groupRules.AllNodesSecurityGroupRules {
+		allErrs = append(allErrs, validateSecurityGroupRule(&groupRules.AllNodesSecurityGroupRules[i], fldPath.Child("allNodesSecurityGroupRules").Index(i))...)
+	}
+	for i := range groupRules.ControlPlane {
+		allErrs = append(allErrs, validateSecurityGroupRule(&groupRules.ControlPlane[i], fldPath.Child("controlPlane").Index(i))...)
+	}
+	for i := range groupRules.Worker {
+		allErrs = append(allErrs, validateSecurityGroupRule(&groupRules.Worker[i], fldPath.Child("worker").Index(i))...)
+	}
+	return allErrs
+}
+
+func validateSecurityGroupRule(rule *SecurityGroupRuleSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateSecurityGroupParams(rule.RemoteSecurityGroups, fldPath.Child("remoteSecurityGroups"))...)
+	if rule.RemoteIPPrefix != "" {
+		if len(rule.RemoteManagedGroups) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("remoteManagedGroups"), "cannot be set together with remoteIPPrefix"))
+		}
+		if len(rule.RemoteSecurityGroups) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("remoteSecurityGroups"), "cannot be set together with remoteIPPrefix"))
+		}
+		ip, _, err := net.ParseCIDR(rule.RemoteIPPrefix)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("remoteIPPrefix"), rule.RemoteIPPrefix, "must be a CIDR"))
+		case rule.EtherType == "IPv4" && ip.To4() == nil, rule.EtherType == "IPv6" && ip.To4() != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("etherType"), rule.EtherType, "must match the address family of remoteIPPrefix"))
+		}
+	}
+	if rule.PortRangeMin < 0 || rule.PortRangeMin > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("portRangeMin"), rule.PortRangeMin, "must be a port number"))
+	}
+	if rule.PortRangeMax < 0 || rule.PortRangeMax > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("portRangeMax"), rule.PortRangeMax, "must be a port number"))
+	}
+	if rule.PortRangeMax != 0 && rule.PortRangeMax < rule.PortRangeMin {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("portRangeMax"), rule.PortRangeMax, "must not be lower than portRangeMin"))
+	}
+	if (rule.PortRangeMin != 0 || rule.PortRangeMax != 0) && rule.Protocol == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("protocol"), "must be set together with a port range"))
+	}
+	return allErrs
+}
+
+// validateSecurityGroupParams validates that every security group reference selects groups by UUID, name or
+// filter. A reference without any of them would match all security groups of the project.
+func validateSecurityGroupParams(params []SecurityGroupParam, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range params {
+		if params[i].UUID == "" && params[i].Name == "" && params[i].Filter == (SecurityGroupFilter{}) {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "one of uuid, name or filter must be set"))
+		}
+	}
+	return allErrs
+}
+
+// validateFlavor validates that the flavor of an instance is given either by name or by ID.
+func validateFlavor(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case spec.Flavor == "" && spec.FlavorID == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("flavor"), "either flavor or flavorID must be set"))
+	case spec.Flavor != "" && spec.FlavorID != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("flavorID"), "cannot be set together with flavor"))
+	}
+	return allErrs
+}
+
+// validateImageFilter checks that the image of the machine is selected either by ImageFilter or by Image or ImageUUID,
+// and that the filter selects images by at least one criterion.
+func validateImageFilter(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	filter := spec.ImageFilter
+	if filter == nil {
+		return allErrs
+	}
+	if spec.Image != "" || spec.ImageUUID != "" || spec.ImageRef != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageFilter"), "cannot be set together with image, imageUUID or imageRef"))
+	}
+	if filter.Name == "" && len(filter.Tags) == 0 && len(filter.Properties) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("imageFilter"), "at least one of name, tags or properties must be set"))
+	}
+	return allErrs
+}
+
+// validateImageRef checks that the image of the machine is selected either by ImageRef or by Image or ImageUUID.
+func validateImageRef(spec *OpenStackMachineSpec, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.ImageRef == nil {
+		return allErrs
+	}
+	if !allowed {
+		return append(allErrs, field.Forbidden(fldPath.Child("imageRef"), "an OpenStackImage can only be referenced by machines"))
+	}
+	if spec.Image != "" || spec.ImageUUID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageRef"), "cannot be set together with image or imageUUID"))
+	}
+	if spec.ImageRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("imageRef", "name"), "name of an OpenStackImage must be set"))
+	}
+	return allErrs
+}
+
+// validateConfigureSecondaryInterfaces checks that the network config of the secondary interfaces is only added to
+// cloud-config bootstrap data of machines.
+func validateConfigureSecondaryInterfaces(spec *OpenStackMachineSpec, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !spec.ConfigureSecondaryInterfaces {
+		return allErrs
+	}
+	if !allowed {
+		return append(allErrs, field.Forbidden(fldPath.Child("configureSecondaryInterfaces"), "secondary interfaces can only be configured for machines"))
+	}
+	if spec.BootstrapFormat == BootstrapFormatIgnition {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("configureSecondaryInterfaces"), "cannot be set with Ignition bootstrap data"))
+	}
+	return allErrs
+}
+
+// validateRegion checks that only machines override the region, and that they do not use the cluster network, which
+// only exists in the region of the cluster.
+func validateRegion(spec *OpenStackMachineSpec, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.Region == "" {
+		return allErrs
+	}
+	if !allowed {
+		return append(allErrs, field.Forbidden(fldPath.Child("region"), "the region can only be set for machines"))
+	}
+	if len(spec.Networks) == 0 && len(spec.Ports) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("networks"), "networks or ports are required with region"))
+	}
+	if spec.ManagedSubnet != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedSubnet"), "cannot be set with region"))
+	}
+	return allErrs
+}
+
+// validateTraits checks that the traits are valid trait names and that no trait is both required and forbidden.
+func validateTraits(traits *Traits, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if traits == nil {
+		return allErrs
+	}
+
+	required := make(map[string]bool)
+	for i, trait := range traits.Required {
+		if !traitNameRegexp.MatchString(trait) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("required").Index(i), trait, "must consist of upper case letters, digits and underscores"))
+		}
+		required[trait] = true
+	}
+	for i, trait := range traits.Forbidden {
+		if !traitNameRegexp.MatchString(trait) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("forbidden").Index(i), trait, "must consist of upper case letters, digits and underscores"))
+		}
+		if required[trait] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("forbidden").Index(i), trait, "cannot be both required and forbidden"))
+		}
+	}
+	return allErrs
+}
+
+// validateAvailabilityZones validates the availability zones of the cluster against each other and
+// against the bastion, whose root volume must be in the availability zone of its instance unless
+// it allows cross-AZ attachment.
+func validateAvailabilityZones(spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.NetworkAvailabilityZone != "" && spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("networkAvailabilityZone"), "can only be set together with nodeCidr"))
+	}
+
+	if spec.Bastion == nil || !spec.Bastion.Enabled {
+		return allErrs
+	}
+	rootVolume := spec.Bastion.Instance.RootVolume
+	if rootVolume == nil || rootVolume.Size <= 0 || rootVolume.CrossAZAttach {
+		return allErrs
+	}
+	instanceAZ := spec.Bastion.AvailabilityZone
+	if instanceAZ == "" {
+		instanceAZ = spec.ComputeAvailabilityZone
+	}
+	volumeAZ, volumeAZPath := rootVolume.AvailabilityZone, fldPath.Child("bastion", "instance", "rootVolume", "availabilityZone")
+	if volumeAZ == "" {
+		volumeAZ, volumeAZPath = spec.RootVolumeAvailabilityZone, fldPath.Child("rootVolumeAvailabilityZone")
+	}
+	if instanceAZ != "" && volumeAZ != "" && instanceAZ != volumeAZ {
+		allErrs = append(allErrs, field.Invalid(volumeAZPath, volumeAZ,
+			fmt.Sprintf("the bastion root volume must be in availability zone %s of the bastion unless crossAZAttach is set", instanceAZ)))
+	}
+	return allErrs
+}
+
+// validateManagedSubnetsUpdate validates that existing managed subnets are not removed, and that only their host
+// routes and service types are changed.
+func validateManagedSubnetsUpdate(old, spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, oldSubnet := range old.ManagedSubnets {
+		found := false
+		for _, subnet := range spec.ManagedSubnets {
+			if subnet.Name == oldSubnet.Name {
+				// The host routes and service types are applied to the existing subnet
+				oldSubnet.HostRoutes, subnet.HostRoutes = nil, nil
+				oldSubnet.ServiceTypes, subnet.ServiceTypes = nil, nil
+				found = reflect.DeepEqual(subnet, oldSubnet)
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedSubnets"), fmt.Sprintf("subnet %s cannot be removed, and only its host routes and service types can be changed", oldSubnet.Name)))
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalFloatingIPsUpdate validates that existing additional floating IPs are not changed. They
+// can be added and removed.
+func validateAdditionalFloatingIPsUpdate(old, spec *OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, oldFloatingIP := range old.AdditionalFloatingIPs {
+		for _, floatingIP := range spec.AdditionalFloatingIPs {
+			if floatingIP.Name == oldFloatingIP.Name && !reflect.DeepEqual(floatingIP, oldFloatingIP) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalFloatingIPs"), fmt.Sprintf("floating IP %s cannot be changed", oldFloatingIP.Name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateManagedSubnetSelector validates that a managed subnet is selected either by name or by role.
+func validateManagedSubnetSelector(selector *ManagedSubnetSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if selector == nil {
+		return allErrs
+	}
+	selectorPath := fldPath.Child("managedSubnet")
+	if selector.Name == "" && selector.Role == "" {
+		allErrs = append(allErrs, field.Required(selectorPath, "one of name and role must be set"))
+	}
+	if selector.Name != "" && selector.Role != "" {
+		allErrs = append(allErrs, field.Forbidden(selectorPath.Child("role"), "cannot be set together with name"))
+	}
+	return allErrs
+}
+
+// validateIPAddressPoolRefs validates the IPAM pool references of the fixed IPs of the ports.
+// If allowed is false, the addresses cannot be claimed for the server, e.g. for the bastion.
+func validateIPAddressPoolRefs(ports []PortOpts, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, port := range ports {
+		for j, fixedIP := range port.FixedIPs {
+			ref := fixedIP.IPAddressPoolRef
+			if ref == nil {
+				continue
+			}
+			refPath := fldPath.Child("ports").Index(i).Child("fixedIPs").Index(j).Child("ipAddressPoolRef")
+
+			if !allowed {
+				allErrs = append(allErrs, field.Forbidden(refPath, "addresses can only be claimed for machines"))
+				continue
+			}
+			if fixedIP.IPAddress != "" {
+				allErrs = append(allErrs, field.Forbidden(refPath, "cannot be set together with ipAddress"))
+			}
+			if ref.APIGroup == nil || *ref.APIGroup == "" {
+				allErrs = append(allErrs, field.Required(refPath.Child("apiGroup"), "apiGroup is required"))
+			}
+			if ref.Kind == "" {
+				allErrs = append(allErrs, field.Required(refPath.Child("kind"), "kind is required"))
+			}
+			if ref.Name == "" {
+				allErrs = append(allErrs, field.Required(refPath.Child("name"), "name is required"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validatePortBindingProfiles validates that no key of the binding profile of a port is also set in its profile.
+func validatePortBindingProfiles(ports []PortOpts, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, port := range ports {
+		for k := range port.BindingProfile {
+			if _, ok := port.Profile[k]; ok {
+				allErrs = append(allErrs, field.Duplicate(fldPath.Child("ports").Index(i).Child("bindingProfile").Key(k), k))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateSchedulerHints validates that the value of each scheduler hint matches its type, and that
+// the group hint is not set together with a server group nor the reservation hint together with a reservation.
+func validateSchedulerHints(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, hint := range spec.SchedulerHintAdditionalProperties {
+		hintPath := fldPath.Child("schedulerHintAdditionalProperties").Index(i)
+		if hint.Name == "group" && (spec.ServerGroupID != "" || spec.ServerGroup != nil) {
+			allErrs = append(allErrs, field.Forbidden(hintPath.Child("name"), "the group hint cannot be set together with serverGroupID or serverGroup"))
+		}
+		if hint.Name == "reservation" && spec.Reservation != nil {
+			allErrs = append(allErrs, field.Forbidden(hintPath.Child("name"), "the reservation hint cannot be set together with reservation"))
+		}
+
+		value := hint.Value
+		values := []struct {
+			valueType SchedulerHintValueType
+			name      string
+			set       bool
+		}{
+			{SchedulerHintValueTypeBool, "bool", value.Bool != nil},
+			{SchedulerHintValueTypeString, "string", value.String != nil},
+			{SchedulerHintValueTypeStringList, "stringList", value.StringList != nil},
+			{SchedulerHintValueTypeNumber, "number", value.Number != nil},
+		}
+		for _, v := range values {
+			valuePath := hintPath.Child("value", v.name)
+			if v.valueType == value.Type && !v.set {
+				allErrs = append(allErrs, field.Required(valuePath, fmt.Sprintf("must be set for type %s", value.Type)))
+			}
+			if v.valueType != value.Type && v.set {
+				allErrs = append(allErrs, field.Forbidden(valuePath, fmt.Sprintf("cannot be set for type %s", value.Type)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalBlockDevices validates that the additional block devices do not use the name of the root
+// volume or of the etcd volume, that QoS specs are only required together with a volume type, and that scheduler
+// hints only reference the root volume or devices listed before the device, whose volumes have been created when
+// it is created.
+func validateAdditionalBlockDevices(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	etcd := spec.ControlPlaneStorage != nil && spec.ControlPlaneStorage.Etcd != nil
+	if etcd && spec.ControlPlaneStorage.Etcd.QoSSpecs != "" && spec.ControlPlaneStorage.Etcd.VolumeType == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("controlPlaneStorage", "etcd", "volumeType"), "must be set together with qosSpecs"))
+	}
+
+	known := make(map[string]bool)
+	retainedVolumeNames := make(map[string]bool)
+	if spec.RootVolume != nil && spec.RootVolume.Size > 0 {
+		known[RootVolumeBlockDeviceName] = true
+	}
+	for i, device := range spec.AdditionalBlockDevices {
+		devicePath := fldPath.Child("additionalBlockDevices").Index(i)
+		if device.Name == RootVolumeBlockDeviceName {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("name"), device.Name, "is reserved for the root volume"))
+		}
+		if etcd && device.Name == EtcdBlockDeviceName {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("name"), device.Name, "is reserved for the etcd volume of controlPlaneStorage"))
+		}
+		if etcd && device.Tag == EtcdBlockDeviceName {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("tag"), device.Tag, "is reserved for the etcd volume of controlPlaneStorage"))
+		}
+		if device.QoSSpecs != "" && device.VolumeType == "" {
+			allErrs = append(allErrs, field.Required(devicePath.Child("volumeType"), "must be set together with qosSpecs"))
+		}
+		if device.RetainedVolumeName != "" {
+			if device.RetentionPolicy != VolumeRetentionPolicyRetain {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("retentionPolicy"), device.RetentionPolicy, "must be Retain if retainedVolumeName is set"))
+			}
+			if retainedVolumeNames[device.RetainedVolumeName] {
+				allErrs = append(allErrs, field.Duplicate(devicePath.Child("retainedVolumeName"), device.RetainedVolumeName))
+			}
+			retainedVolumeNames[device.RetainedVolumeName] = true
+		}
+		if hints := device.SchedulerHints; hints != nil {
+			for _, h := range []struct {
+				name  string
+				names []string
+			}{
+				{"sameHostAs", hints.SameHostAs},
+				{"differentHostAs", hints.DifferentHostAs},
+			} {
+				for j, name := range h.names {
+					if !known[name] {
+						allErrs = append(allErrs, field.Invalid(devicePath.Child("schedulerHints", h.name).Index(j), name,
+							"must be the root volume or an additional block device listed before this one"))
+					}
+				}
+			}
+		}
+		known[device.Name] = true
+	}
+	return allErrs
+}
+
+// validateAdditionalBlockDevicesUpdate validates that the additional block devices of an existing machine are only
+// added or removed. The volume of a device is not changed once it has been created.
+func validateAdditionalBlockDevicesUpdate(old, spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, oldDevice := range old.AdditionalBlockDevices {
+		for i, device := range spec.AdditionalBlockDevices {
+			if device.Name == oldDevice.Name && !reflect.DeepEqual(device, oldDevice) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalBlockDevices").Index(i), fmt.Sprintf("block device %s cannot be changed", oldDevice.Name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validatePortFixedIPs validates that each fixed IP of a port selects a subnet or an address, which Neutron
+// requires, and that no address is requested twice for the same port.
+func validatePortFixedIPs(ports []PortOpts, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, port := range ports {
+		addresses := make(map[string]bool)
+		for j, fixedIP := range port.FixedIPs {
+			fixedIPPath := fldPath.Child("ports").Index(i).Child("fixedIPs").Index(j)
+			if fixedIP.Subnet == nil && fixedIP.IPAddress == "" && fixedIP.IPAddressPoolRef == nil {
+				allErrs = append(allErrs, field.Required(fixedIPPath, "one of subnet, ipAddress and ipAddressPoolRef must be set"))
+			}
+			if fixedIP.IPAddress == "" {
+				continue
+			}
+			if addresses[fixedIP.IPAddress] {
+				allErrs = append(allErrs, field.Duplicate(fixedIPPath.Child("ipAddress"), fixedIP.IPAddress))
+			}
+			addresses[fixedIP.IPAddress] = true
+		}
+	}
+	return allErrs
+}
+
+// validatePortSecurity validates that ports with port security disabled do not request security groups or
+// allowed address pairs, which Neutron rejects for such ports.
+func validatePortSecurity(ports []PortOpts, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, port := range ports {
+		if port.DisablePortSecurity == nil || !*port.DisablePortSecurity {
+			continue
+		}
+		portPath := fldPath.Child("ports").Index(i)
+		if port.SecurityGroups != nil && len(*port.SecurityGroups) > 0 {
+			allErrs = append(allErrs, field.Forbidden(portPath.Child("securityGroups"), "cannot be set when port security is disabled"))
+		}
+		if len(port.SecurityGroupFilters) > 0 {
+			allErrs = append(allErrs, field.Forbidden(portPath.Child("securityGroupFilters"), "cannot be set when port security is disabled"))
+		}
+		if len(port.AllowedAddressPairs) > 0 {
+			allErrs = append(allErrs, field.Forbidden(portPath.Child("allowedAddressPairs"), "cannot be set when port security is disabled"))
+		}
+	}
+	return allErrs
+}
+
+// validateServerPassword validates the publishing of the server password. If allowed is false,
+// the password cannot be published for the server, e.g. for the bastion.
+func validateServerPassword(spec *OpenStackMachineSpec, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.ServerPassword == nil {
+		return allErrs
+	}
+	passwordPath := fldPath.Child("serverPassword")
+
+	if !allowed {
+		return append(allErrs, field.Forbidden(passwordPath, "the password can only be published for machines"))
+	}
+	if spec.SSHKeyName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sshKeyName"), "sshKeyName is required to decrypt the password"))
+	}
+	if spec.ServerPassword.PrivateKeySecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(passwordPath.Child("privateKeySecretRef", "name"), "name is required"))
+	}
+	return allErrs
+}
+
+// validateAdoptExisting validates the selection of an existing server to adopt. If allowed is false, no server can
+// be adopted, e.g. for templates, as a server can only be adopted by a single machine.
+func validateAdoptExisting(spec *OpenStackMachineSpec, allowed bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	adoptPath := fldPath.Child("adoptExisting")
+
+	if spec.AdoptExisting != nil {
+		if !allowed {
+			return append(allErrs, field.Forbidden(adoptPath, "existing servers can only be adopted by an OpenStackMachine"))
+		}
+		if spec.AdoptExisting.Name == "" && len(spec.AdoptExisting.Tags) == 0 {
+			allErrs = append(allErrs, field.Required(adoptPath, "name or tags are required to select the server"))
+		}
+		if spec.ProviderID != nil {
+			allErrs = append(allErrs, field.Forbidden(adoptPath, "cannot be set together with providerID"))
+		}
+	}
+	// A server adopted by its provider ID is looked up by the ID following the prefix
+	if allowed && spec.ProviderID != nil && spec.InstanceID == nil {
+		if id := strings.TrimPrefix(*spec.ProviderID, "openstack:///"); id == *spec.ProviderID || id == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("providerID"), *spec.ProviderID, "must have the format openstack:///<server ID>"))
+		}
+	}
+	return allErrs
+}
+
+func validateSubports(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, port := range spec.Ports {
+		if len(port.Subports) == 0 {
+			continue
+		}
+		portPath := fldPath.Child("ports").Index(i)
+
+		trunk := spec.Trunk
+		if port.Trunk != nil {
+			trunk = *port.Trunk
+		}
+		if !trunk {
+			allErrs = append(allErrs, field.Forbidden(portPath.Child("subports"), "requires trunk to be enabled for the port"))
+		}
+
+		segmentationIDs := map[int]bool{}
+		for j, subport := range port.Subports {
+			subportPath := portPath.Child("subports").Index(j)
+			if subport.Network == nil {
+				allErrs = append(allErrs, field.Required(subportPath.Child("network"), "network is required"))
+			}
+			if segmentationIDs[subport.SegmentationID] {
+				allErrs = append(allErrs, field.Duplicate(subportPath.Child("segmentationID"), subport.SegmentationID))
+			}
+			segmentationIDs[subport.SegmentationID] = true
+		}
+	}
+	return allErrs
+}
+
+// validateMaxInstanceAge checks that the MaxInstanceAgeAnnotation, if set, is a positive duration.
+func validateMaxInstanceAge(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	value, ok := annotations[MaxInstanceAgeAnnotation]
+	if !ok {
+		return nil
+	}
+	if maxAge, err := time.ParseDuration(value); err != nil || maxAge <= 0 {
+		return field.ErrorList{field.Invalid(fldPath.Key(MaxInstanceAgeAnnotation), value, "must be a positive duration such as 720h")}
+	}
+	return nil
+}
+
+// validateSnapshotBeforeDelete checks that the name template of the snapshot parses and that its durations, if set,
+// are positive.
+func validateSnapshotBeforeDelete(snapshot *SnapshotBeforeDelete, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if snapshot == nil {
+		return allErrs
+	}
+	if _, err := template.New("name").Parse(snapshot.Name); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), snapshot.Name, err.Error()))
+	}
+	if snapshot.Expiry != nil && snapshot.Expiry.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("expiry"), snapshot.Expiry.Duration.String(), "must be a positive duration"))
+	}
+	if snapshot.Timeout != nil && snapshot.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), snapshot.Timeout.Duration.String(), "must be a positive duration"))
+	}
+	return allErrs
+}
+
+// validateHostname checks that the hostname template of the machine parses, and that the hostname is a DNS-1123
+// label if it is not a template.
+func validateHostname(spec *OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	if spec.Hostname == "" {
+		return nil
+	}
+	if _, err := template.New("hostname").Parse(spec.Hostname); err != nil {
+		return field.ErrorList{field.Invalid(fldPath.Child("hostname"), spec.Hostname, err.Error())}
+	}
+	if !strings.Contains(spec.Hostname, "{{") {
+		if errs := validation.IsDNS1123Label(spec.Hostname); len(errs) > 0 {
+			return field.ErrorList{field.Invalid(fldPath.Child("hostname"), spec.Hostname, strings.Join(errs, ", "))}
+		}
+	}
+	return nil
+}
+
+// validateRemediation checks that the timeout of the remediation, if set, is positive.
+func validateRemediation(remediation *MachineRemediation, fldPath *field.Path) field.ErrorList {
+	if remediation == nil || remediation.Timeout == nil {
+		return nil
+	}
+	if remediation.Timeout.Duration <= 0 {
+		return field.ErrorList{field.Invalid(fldPath.Child("timeout"), remediation.Timeout.Duration.String(), "must be a positive duration")}
+	}
+	return nil
+}
+
+// validateGarbageCollection checks that the grace period and the interval of the garbage collection are positive.
+func validateGarbageCollection(gc *GarbageCollection, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if gc == nil {
+		return allErrs
+	}
+	if gc.GracePeriod != nil && gc.GracePeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gracePeriod"), gc.GracePeriod.Duration.String(), "must be a positive duration"))
+	}
+	if gc.Interval != nil && gc.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), gc.Interval.Duration.String(), "must be a positive duration"))
+	}
+	return allErrs
+}
+
+// validateBlueprintExport checks that the name of the object the blueprint is exported to, if set, is valid.
+func validateBlueprintExport(export *BlueprintExport, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if export == nil || export.Name == "" {
+		return allErrs
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(export.Name) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), export.Name, msg))
+	}
+	return allErrs
+}
@@ -23,6 +23,8 @@ package v1alpha6
 
 import (
 	"k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
@@ -41,6 +43,31 @@ func (in *APIServerLoadBalancer) DeepCopyInto(out *APIServerLoadBalancer) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdditionalPortsFlavor != nil {
+		in, out := &in.AdditionalPortsFlavor, &out.AdditionalPortsFlavor
+		*out = new(LoadBalancerFlavor)
+		**out = **in
+	}
+	if in.HealthMonitor != nil {
+		in, out := &in.HealthMonitor, &out.HealthMonitor
+		*out = new(HealthMonitor)
+		**out = **in
+	}
+	if in.ExistingLoadBalancer != nil {
+		in, out := &in.ExistingLoadBalancer, &out.ExistingLoadBalancer
+		*out = new(LoadBalancerReference)
+		**out = **in
+	}
+	if in.Shared != nil {
+		in, out := &in.Shared, &out.Shared
+		*out = new(SharedLoadBalancer)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(LoadBalancerTLS)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerLoadBalancer.
@@ -54,598 +81,492 @@ func (in *APIServerLoadBalancer) DeepCopy() *APIServerLoadBalancer {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AddressPair) DeepCopyInto(out *AddressPair) {
+func (in *APIServerVIP) DeepCopyInto(out *APIServerVIP) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressPair.
-func (in *AddressPair) DeepCopy() *AddressPair {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerVIP.
+func (in *APIServerVIP) DeepCopy() *APIServerVIP {
 	if in == nil {
 		return nil
 	}
-	out := new(AddressPair)
+	out := new(APIServerVIP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Bastion) DeepCopyInto(out *Bastion) {
+func (in *AdditionalBlockDevice) DeepCopyInto(out *AdditionalBlockDevice) {
 	*out = *in
-	in.Instance.DeepCopyInto(&out.Instance)
+	if in.SchedulerHints != nil {
+		in, out := &in.SchedulerHints, &out.SchedulerHints
+		*out = new(VolumeSchedulerHints)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bastion.
-func (in *Bastion) DeepCopy() *Bastion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalBlockDevice.
+func (in *AdditionalBlockDevice) DeepCopy() *AdditionalBlockDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(Bastion)
+	out := new(AdditionalBlockDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalRouterIPParam) DeepCopyInto(out *ExternalRouterIPParam) {
+func (in *AdditionalFloatingIP) DeepCopyInto(out *AdditionalFloatingIP) {
 	*out = *in
-	out.Subnet = in.Subnet
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalRouterIPParam.
-func (in *ExternalRouterIPParam) DeepCopy() *ExternalRouterIPParam {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalFloatingIP.
+func (in *AdditionalFloatingIP) DeepCopy() *AdditionalFloatingIP {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalRouterIPParam)
+	out := new(AdditionalFloatingIP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FixedIP) DeepCopyInto(out *FixedIP) {
+func (in *AdditionalFloatingIPStatus) DeepCopyInto(out *AdditionalFloatingIPStatus) {
 	*out = *in
-	if in.Subnet != nil {
-		in, out := &in.Subnet, &out.Subnet
-		*out = new(SubnetFilter)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixedIP.
-func (in *FixedIP) DeepCopy() *FixedIP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalFloatingIPStatus.
+func (in *AdditionalFloatingIPStatus) DeepCopy() *AdditionalFloatingIPStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FixedIP)
+	out := new(AdditionalFloatingIPStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Instance) DeepCopyInto(out *Instance) {
+func (in *AddressPair) DeepCopyInto(out *AddressPair) {
 	*out = *in
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
-		*out = new([]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]string, len(*in))
-			copy(*out, *in)
-		}
-	}
-	if in.Networks != nil {
-		in, out := &in.Networks, &out.Networks
-		*out = new([]Network)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]Network, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
-	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ConfigDrive != nil {
-		in, out := &in.ConfigDrive, &out.ConfigDrive
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RootVolume != nil {
-		in, out := &in.RootVolume, &out.RootVolume
-		*out = new(RootVolume)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
-func (in *Instance) DeepCopy() *Instance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressPair.
+func (in *AddressPair) DeepCopy() *AddressPair {
 	if in == nil {
 		return nil
 	}
-	out := new(Instance)
+	out := new(AddressPair)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
+func (in *AllocationPool) DeepCopyInto(out *AllocationPool) {
 	*out = *in
-	if in.AllowedCIDRs != nil {
-		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancer.
-func (in *LoadBalancer) DeepCopy() *LoadBalancer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllocationPool.
+func (in *AllocationPool) DeepCopy() *AllocationPool {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancer)
+	out := new(AllocationPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Network) DeepCopyInto(out *Network) {
+func (in *AttachedBlockDevice) DeepCopyInto(out *AttachedBlockDevice) {
 	*out = *in
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Subnet != nil {
-		in, out := &in.Subnet, &out.Subnet
-		*out = new(Subnet)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PortOpts != nil {
-		in, out := &in.PortOpts, &out.PortOpts
-		*out = new(PortOpts)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Router != nil {
-		in, out := &in.Router, &out.Router
-		*out = new(Router)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachedBlockDevice.
+func (in *AttachedBlockDevice) DeepCopy() *AttachedBlockDevice {
+	if in == nil {
+		return nil
 	}
-	if in.APIServerLoadBalancer != nil {
-		in, out := &in.APIServerLoadBalancer, &out.APIServerLoadBalancer
-		*out = new(LoadBalancer)
-		(*in).DeepCopyInto(*out)
+	out := new(AttachedBlockDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bastion) DeepCopyInto(out *Bastion) {
+	*out = *in
+	in.Instance.DeepCopyInto(&out.Instance)
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(BastionDNS)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
-func (in *Network) DeepCopy() *Network {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bastion.
+func (in *Bastion) DeepCopy() *Bastion {
 	if in == nil {
 		return nil
 	}
-	out := new(Network)
+	out := new(Bastion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkFilter) DeepCopyInto(out *NetworkFilter) {
+func (in *BastionDNS) DeepCopyInto(out *BastionDNS) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkFilter.
-func (in *NetworkFilter) DeepCopy() *NetworkFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionDNS.
+func (in *BastionDNS) DeepCopy() *BastionDNS {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkFilter)
+	out := new(BastionDNS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkParam) DeepCopyInto(out *NetworkParam) {
+func (in *BlueprintExport) DeepCopyInto(out *BlueprintExport) {
 	*out = *in
-	out.Filter = in.Filter
-	if in.Subnets != nil {
-		in, out := &in.Subnets, &out.Subnets
-		*out = make([]SubnetParam, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkParam.
-func (in *NetworkParam) DeepCopy() *NetworkParam {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintExport.
+func (in *BlueprintExport) DeepCopy() *BlueprintExport {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkParam)
+	out := new(BlueprintExport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackCluster) DeepCopyInto(out *OpenStackCluster) {
+func (in *ClusterAddress) DeepCopyInto(out *ClusterAddress) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackCluster.
-func (in *OpenStackCluster) DeepCopy() *OpenStackCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAddress.
+func (in *ClusterAddress) DeepCopy() *ClusterAddress {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackCluster)
+	out := new(ClusterAddress)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackCluster) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneEndpointDNS) DeepCopyInto(out *ControlPlaneEndpointDNS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneEndpointDNS.
+func (in *ControlPlaneEndpointDNS) DeepCopy() *ControlPlaneEndpointDNS {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ControlPlaneEndpointDNS)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterList) DeepCopyInto(out *OpenStackClusterList) {
+func (in *ControlPlaneStorage) DeepCopyInto(out *ControlPlaneStorage) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OpenStackCluster, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdVolume)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterList.
-func (in *OpenStackClusterList) DeepCopy() *OpenStackClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneStorage.
+func (in *ControlPlaneStorage) DeepCopy() *ControlPlaneStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterList)
+	out := new(ControlPlaneStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackClusterList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordReference) DeepCopyInto(out *DNSRecordReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordReference.
+func (in *DNSRecordReference) DeepCopy() *DNSRecordReference {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(DNSRecordReference)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterSpec) DeepCopyInto(out *OpenStackClusterSpec) {
+func (in *EtcdVolume) DeepCopyInto(out *EtcdVolume) {
 	*out = *in
-	out.Network = in.Network
-	out.Subnet = in.Subnet
-	if in.DNSNameservers != nil {
-		in, out := &in.DNSNameservers, &out.DNSNameservers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ExternalRouterIPs != nil {
-		in, out := &in.ExternalRouterIPs, &out.ExternalRouterIPs
-		*out = make([]ExternalRouterIPParam, len(*in))
-		copy(*out, *in)
-	}
-	in.APIServerLoadBalancer.DeepCopyInto(&out.APIServerLoadBalancer)
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
-	if in.ControlPlaneAvailabilityZones != nil {
-		in, out := &in.ControlPlaneAvailabilityZones, &out.ControlPlaneAvailabilityZones
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Bastion != nil {
-		in, out := &in.Bastion, &out.Bastion
-		*out = new(Bastion)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.IdentityRef != nil {
-		in, out := &in.IdentityRef, &out.IdentityRef
-		*out = new(OpenStackIdentityReference)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterSpec.
-func (in *OpenStackClusterSpec) DeepCopy() *OpenStackClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdVolume.
+func (in *EtcdVolume) DeepCopy() *EtcdVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterSpec)
+	out := new(EtcdVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterStatus) DeepCopyInto(out *OpenStackClusterStatus) {
+func (in *ExternalRouterIPParam) DeepCopyInto(out *ExternalRouterIPParam) {
 	*out = *in
-	if in.Network != nil {
-		in, out := &in.Network, &out.Network
-		*out = new(Network)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExternalNetwork != nil {
-		in, out := &in.ExternalNetwork, &out.ExternalNetwork
-		*out = new(Network)
-		(*in).DeepCopyInto(*out)
+	out.Subnet = in.Subnet
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalRouterIPParam.
+func (in *ExternalRouterIPParam) DeepCopy() *ExternalRouterIPParam {
+	if in == nil {
+		return nil
 	}
-	if in.FailureDomains != nil {
-		in, out := &in.FailureDomains, &out.FailureDomains
-		*out = make(v1beta1.FailureDomains, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.ControlPlaneSecurityGroup != nil {
-		in, out := &in.ControlPlaneSecurityGroup, &out.ControlPlaneSecurityGroup
-		*out = new(SecurityGroup)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.WorkerSecurityGroup != nil {
-		in, out := &in.WorkerSecurityGroup, &out.WorkerSecurityGroup
-		*out = new(SecurityGroup)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.BastionSecurityGroup != nil {
-		in, out := &in.BastionSecurityGroup, &out.BastionSecurityGroup
-		*out = new(SecurityGroup)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Bastion != nil {
-		in, out := &in.Bastion, &out.Bastion
-		*out = new(Instance)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.ClusterStatusError)
+	out := new(ExternalRouterIPParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixedIP) DeepCopyInto(out *FixedIP) {
+	*out = *in
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(SubnetFilter)
 		**out = **in
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
-		**out = **in
+	if in.IPAddressPoolRef != nil {
+		in, out := &in.IPAddressPoolRef, &out.IPAddressPoolRef
+		*out = new(v1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterStatus.
-func (in *OpenStackClusterStatus) DeepCopy() *OpenStackClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixedIP.
+func (in *FixedIP) DeepCopy() *FixedIP {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterStatus)
+	out := new(FixedIP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterTemplate) DeepCopyInto(out *OpenStackClusterTemplate) {
+func (in *FloatingIPClaim) DeepCopyInto(out *FloatingIPClaim) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplate.
-func (in *OpenStackClusterTemplate) DeepCopy() *OpenStackClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FloatingIPClaim.
+func (in *FloatingIPClaim) DeepCopy() *FloatingIPClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterTemplate)
+	out := new(FloatingIPClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackClusterTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollection) DeepCopyInto(out *GarbageCollection) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	return nil
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollection.
+func (in *GarbageCollection) DeepCopy() *GarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollection)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterTemplateList) DeepCopyInto(out *OpenStackClusterTemplateList) {
+func (in *GarbageCollectionStatus) DeepCopyInto(out *GarbageCollectionStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OpenStackClusterTemplate, len(*in))
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+	if in.Orphans != nil {
+		in, out := &in.Orphans, &out.Orphans
+		*out = make([]OrphanedResource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateList.
-func (in *OpenStackClusterTemplateList) DeepCopy() *OpenStackClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionStatus.
+func (in *GarbageCollectionStatus) DeepCopy() *GarbageCollectionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterTemplateList)
+	out := new(GarbageCollectionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackClusterTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterTemplateResource) DeepCopyInto(out *OpenStackClusterTemplateResource) {
+func (in *HealthMonitor) DeepCopyInto(out *HealthMonitor) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateResource.
-func (in *OpenStackClusterTemplateResource) DeepCopy() *OpenStackClusterTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthMonitor.
+func (in *HealthMonitor) DeepCopy() *HealthMonitor {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterTemplateResource)
+	out := new(HealthMonitor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackClusterTemplateSpec) DeepCopyInto(out *OpenStackClusterTemplateSpec) {
+func (in *HostRoute) DeepCopyInto(out *HostRoute) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateSpec.
-func (in *OpenStackClusterTemplateSpec) DeepCopy() *OpenStackClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRoute.
+func (in *HostRoute) DeepCopy() *HostRoute {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackClusterTemplateSpec)
+	out := new(HostRoute)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackIdentityReference) DeepCopyInto(out *OpenStackIdentityReference) {
+func (in *IPv6SubnetOptions) DeepCopyInto(out *IPv6SubnetOptions) {
 	*out = *in
+	if in.DNSNameservers != nil {
+		in, out := &in.DNSNameservers, &out.DNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackIdentityReference.
-func (in *OpenStackIdentityReference) DeepCopy() *OpenStackIdentityReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPv6SubnetOptions.
+func (in *IPv6SubnetOptions) DeepCopy() *IPv6SubnetOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackIdentityReference)
+	out := new(IPv6SubnetOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachine) DeepCopyInto(out *OpenStackMachine) {
+func (in *IgnitionOptions) DeepCopyInto(out *IgnitionOptions) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachine.
-func (in *OpenStackMachine) DeepCopy() *OpenStackMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnitionOptions.
+func (in *IgnitionOptions) DeepCopy() *IgnitionOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachine)
+	out := new(IgnitionOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackMachine) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineList) DeepCopyInto(out *OpenStackMachineList) {
+func (in *ImageFilter) DeepCopyInto(out *ImageFilter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OpenStackMachine, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineList.
-func (in *OpenStackMachineList) DeepCopy() *OpenStackMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFilter.
+func (in *ImageFilter) DeepCopy() *ImageFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineList)
+	out := new(ImageFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackMachineList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrewarm) DeepCopyInto(out *ImagePrewarm) {
+	*out = *in
+	if in.AvailabilityZones != nil {
+		in, out := &in.AvailabilityZones, &out.AvailabilityZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrewarm.
+func (in *ImagePrewarm) DeepCopy() *ImagePrewarm {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrewarm)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
+func (in *Instance) DeepCopyInto(out *Instance) {
 	*out = *in
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
-		**out = **in
-	}
-	if in.InstanceID != nil {
-		in, out := &in.InstanceID, &out.InstanceID
-		*out = new(string)
-		**out = **in
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
 	}
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
-		*out = make([]NetworkParam, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]PortOpts, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+		*out = new([]Network)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]Network, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
 		}
 	}
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
-		*out = make([]SecurityGroupParam, len(*in))
-		copy(*out, *in)
-	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ServerMetadata != nil {
-		in, out := &in.ServerMetadata, &out.ServerMetadata
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
@@ -661,330 +582,2137 @@ func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
 		*out = new(RootVolume)
 		**out = **in
 	}
-	if in.IdentityRef != nil {
-		in, out := &in.IdentityRef, &out.IdentityRef
-		*out = new(OpenStackIdentityReference)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
+func (in *Instance) DeepCopy() *Instance {
+	if in == nil {
+		return nil
 	}
+	out := new(Instance)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineSpec.
-func (in *OpenStackMachineSpec) DeepCopy() *OpenStackMachineSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceActionsAudit) DeepCopyInto(out *InstanceActionsAudit) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	if in.LastActionTime != nil {
+		in, out := &in.LastActionTime, &out.LastActionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceActionsAudit.
+func (in *InstanceActionsAudit) DeepCopy() *InstanceActionsAudit {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineSpec)
+	out := new(InstanceActionsAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancePlacement) DeepCopyInto(out *InstancePlacement) {
+	*out = *in
+	if in.ServerGroups != nil {
+		in, out := &in.ServerGroups, &out.ServerGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancePlacement.
+func (in *InstancePlacement) DeepCopy() *InstancePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancer.
+func (in *LoadBalancer) DeepCopy() *LoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerFlavor) DeepCopyInto(out *LoadBalancerFlavor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerFlavor.
+func (in *LoadBalancerFlavor) DeepCopy() *LoadBalancerFlavor {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerFlavor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerReference) DeepCopyInto(out *LoadBalancerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerReference.
+func (in *LoadBalancerReference) DeepCopy() *LoadBalancerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerTLS) DeepCopyInto(out *LoadBalancerTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerTLS.
+func (in *LoadBalancerTLS) DeepCopy() *LoadBalancerTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineMetadataPropagation) DeepCopyInto(out *MachineMetadataPropagation) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineMetadataPropagation.
+func (in *MachineMetadataPropagation) DeepCopy() *MachineMetadataPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineMetadataPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRemediation) DeepCopyInto(out *MachineRemediation) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRemediation.
+func (in *MachineRemediation) DeepCopy() *MachineRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRemediationStatus) DeepCopyInto(out *MachineRemediationStatus) {
+	*out = *in
+	in.LastStepTime.DeepCopyInto(&out.LastStepTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRemediationStatus.
+func (in *MachineRemediationStatus) DeepCopy() *MachineRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSecurityGroupRules) DeepCopyInto(out *ManagedSecurityGroupRules) {
+	*out = *in
+	if in.AllNodesSecurityGroupRules != nil {
+		in, out := &in.AllNodesSecurityGroupRules, &out.AllNodesSecurityGroupRules
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Worker != nil {
+		in, out := &in.Worker, &out.Worker
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSecurityGroupRules.
+func (in *ManagedSecurityGroupRules) DeepCopy() *ManagedSecurityGroupRules {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSecurityGroupRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSubnet) DeepCopyInto(out *ManagedSubnet) {
+	*out = *in
+	if in.AllocationPools != nil {
+		in, out := &in.AllocationPools, &out.AllocationPools
+		*out = make([]AllocationPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.DNSNameservers != nil {
+		in, out := &in.DNSNameservers, &out.DNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostRoutes != nil {
+		in, out := &in.HostRoutes, &out.HostRoutes
+		*out = make([]HostRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceTypes != nil {
+		in, out := &in.ServiceTypes, &out.ServiceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSubnet.
+func (in *ManagedSubnet) DeepCopy() *ManagedSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSubnetSelector) DeepCopyInto(out *ManagedSubnetSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSubnetSelector.
+func (in *ManagedSubnetSelector) DeepCopy() *ManagedSubnetSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSubnetSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSubnetStatus) DeepCopyInto(out *ManagedSubnetStatus) {
+	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+	if in.ServiceTypes != nil {
+		in, out := &in.ServiceTypes, &out.ServiceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSubnetStatus.
+func (in *ManagedSubnetStatus) DeepCopy() *ManagedSubnetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSubnetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Network) DeepCopyInto(out *Network) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(Subnet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPv6Subnet != nil {
+		in, out := &in.IPv6Subnet, &out.IPv6Subnet
+		*out = new(Subnet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedSubnets != nil {
+		in, out := &in.ManagedSubnets, &out.ManagedSubnets
+		*out = make([]ManagedSubnetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PortOpts != nil {
+		in, out := &in.PortOpts, &out.PortOpts
+		*out = new(PortOpts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Router != nil {
+		in, out := &in.Router, &out.Router
+		*out = new(Router)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerLoadBalancer != nil {
+		in, out := &in.APIServerLoadBalancer, &out.APIServerLoadBalancer
+		*out = new(LoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalPortsLoadBalancer != nil {
+		in, out := &in.AdditionalPortsLoadBalancer, &out.AdditionalPortsLoadBalancer
+		*out = new(LoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerVIP != nil {
+		in, out := &in.APIServerVIP, &out.APIServerVIP
+		*out = new(APIServerVIP)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
+func (in *Network) DeepCopy() *Network {
+	if in == nil {
+		return nil
+	}
+	out := new(Network)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkFilter) DeepCopyInto(out *NetworkFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkFilter.
+func (in *NetworkFilter) DeepCopy() *NetworkFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkParam) DeepCopyInto(out *NetworkParam) {
+	*out = *in
+	out.Filter = in.Filter
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetParam, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkParam.
+func (in *NetworkParam) DeepCopy() *NetworkParam {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackCluster) DeepCopyInto(out *OpenStackCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackCluster.
+func (in *OpenStackCluster) DeepCopy() *OpenStackCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterList) DeepCopyInto(out *OpenStackClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterList.
+func (in *OpenStackClusterList) DeepCopy() *OpenStackClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterSpec) DeepCopyInto(out *OpenStackClusterSpec) {
+	*out = *in
+	if in.NodeIPv6Subnet != nil {
+		in, out := &in.NodeIPv6Subnet, &out.NodeIPv6Subnet
+		*out = new(IPv6SubnetOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedSubnets != nil {
+		in, out := &in.ManagedSubnets, &out.ManagedSubnets
+		*out = make([]ManagedSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Network = in.Network
+	out.Subnet = in.Subnet
+	if in.DNSNameservers != nil {
+		in, out := &in.DNSNameservers, &out.DNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Router != nil {
+		in, out := &in.Router, &out.Router
+		*out = new(RouterFilter)
+		**out = **in
+	}
+	if in.RouterExternalGateway != nil {
+		in, out := &in.RouterExternalGateway, &out.RouterExternalGateway
+		*out = new(RouterExternalGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalRouterIPs != nil {
+		in, out := &in.ExternalRouterIPs, &out.ExternalRouterIPs
+		*out = make([]ExternalRouterIPParam, len(*in))
+		copy(*out, *in)
+	}
+	in.APIServerLoadBalancer.DeepCopyInto(&out.APIServerLoadBalancer)
+	if in.FloatingIPPoolRef != nil {
+		in, out := &in.FloatingIPPoolRef, &out.FloatingIPPoolRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AdditionalFloatingIPs != nil {
+		in, out := &in.AdditionalFloatingIPs, &out.AdditionalFloatingIPs
+		*out = make([]AdditionalFloatingIP, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedSecurityGroupRules != nil {
+		in, out := &in.ManagedSecurityGroupRules, &out.ManagedSecurityGroupRules
+		*out = new(ManagedSecurityGroupRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderNetwork != nil {
+		in, out := &in.ProviderNetwork, &out.ProviderNetwork
+		*out = new(ProviderNetwork)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServerMetadata != nil {
+		in, out := &in.ServerMetadata, &out.ServerMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MachineMetadataPropagation != nil {
+		in, out := &in.MachineMetadataPropagation, &out.MachineMetadataPropagation
+		*out = new(MachineMetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotBeforeDelete != nil {
+		in, out := &in.SnapshotBeforeDelete, &out.SnapshotBeforeDelete
+		*out = new(SnapshotBeforeDelete)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceNaming != nil {
+		in, out := &in.ResourceNaming, &out.ResourceNaming
+		*out = new(ResourceNaming)
+		**out = **in
+	}
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlaneEndpointDNS != nil {
+		in, out := &in.ControlPlaneEndpointDNS, &out.ControlPlaneEndpointDNS
+		*out = new(ControlPlaneEndpointDNS)
+		**out = **in
+	}
+	if in.ControlPlaneAvailabilityZones != nil {
+		in, out := &in.ControlPlaneAvailabilityZones, &out.ControlPlaneAvailabilityZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Bastion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlueprintExport != nil {
+		in, out := &in.BlueprintExport, &out.BlueprintExport
+		*out = new(BlueprintExport)
+		**out = **in
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(OpenStackIdentityReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterSpec.
+func (in *OpenStackClusterSpec) DeepCopy() *OpenStackClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterStatus) DeepCopyInto(out *OpenStackClusterStatus) {
+	*out = *in
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(Network)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(Network)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make(v1beta1.FailureDomains, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ControlPlaneSecurityGroup != nil {
+		in, out := &in.ControlPlaneSecurityGroup, &out.ControlPlaneSecurityGroup
+		*out = new(SecurityGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerSecurityGroup != nil {
+		in, out := &in.WorkerSecurityGroup, &out.WorkerSecurityGroup
+		*out = new(SecurityGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BastionSecurityGroup != nil {
+		in, out := &in.BastionSecurityGroup, &out.BastionSecurityGroup
+		*out = new(SecurityGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Instance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BastionDNSRecord != nil {
+		in, out := &in.BastionDNSRecord, &out.BastionDNSRecord
+		*out = new(DNSRecordReference)
+		**out = **in
+	}
+	if in.ExternalAddresses != nil {
+		in, out := &in.ExternalAddresses, &out.ExternalAddresses
+		*out = make([]ClusterAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.FloatingIPPoolClaims != nil {
+		in, out := &in.FloatingIPPoolClaims, &out.FloatingIPPoolClaims
+		*out = make([]FloatingIPClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalFloatingIPs != nil {
+		in, out := &in.AdditionalFloatingIPs, &out.AdditionalFloatingIPs
+		*out = make([]AdditionalFloatingIPStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(Plan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.ClusterStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterStatus.
+func (in *OpenStackClusterStatus) DeepCopy() *OpenStackClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterTemplate) DeepCopyInto(out *OpenStackClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplate.
+func (in *OpenStackClusterTemplate) DeepCopy() *OpenStackClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterTemplateList) DeepCopyInto(out *OpenStackClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateList.
+func (in *OpenStackClusterTemplateList) DeepCopy() *OpenStackClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterTemplateResource) DeepCopyInto(out *OpenStackClusterTemplateResource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateResource.
+func (in *OpenStackClusterTemplateResource) DeepCopy() *OpenStackClusterTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterTemplateSpec) DeepCopyInto(out *OpenStackClusterTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterTemplateSpec.
+func (in *OpenStackClusterTemplateSpec) DeepCopy() *OpenStackClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackFloatingIPPool) DeepCopyInto(out *OpenStackFloatingIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackFloatingIPPool.
+func (in *OpenStackFloatingIPPool) DeepCopy() *OpenStackFloatingIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackFloatingIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackFloatingIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackFloatingIPPoolList) DeepCopyInto(out *OpenStackFloatingIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackFloatingIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackFloatingIPPoolList.
+func (in *OpenStackFloatingIPPoolList) DeepCopy() *OpenStackFloatingIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackFloatingIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackFloatingIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackFloatingIPPoolSpec) DeepCopyInto(out *OpenStackFloatingIPPoolSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackFloatingIPPoolSpec.
+func (in *OpenStackFloatingIPPoolSpec) DeepCopy() *OpenStackFloatingIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackFloatingIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackFloatingIPPoolStatus) DeepCopyInto(out *OpenStackFloatingIPPoolStatus) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]FloatingIPClaim, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackFloatingIPPoolStatus.
+func (in *OpenStackFloatingIPPoolStatus) DeepCopy() *OpenStackFloatingIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackFloatingIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackIdentityReference) DeepCopyInto(out *OpenStackIdentityReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackIdentityReference.
+func (in *OpenStackIdentityReference) DeepCopy() *OpenStackIdentityReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackIdentityReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackImage) DeepCopyInto(out *OpenStackImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackImage.
+func (in *OpenStackImage) DeepCopy() *OpenStackImage {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackImageList) DeepCopyInto(out *OpenStackImageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackImageList.
+func (in *OpenStackImageList) DeepCopy() *OpenStackImageList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackImageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackImageSpec) DeepCopyInto(out *OpenStackImageSpec) {
+	*out = *in
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(OpenStackIdentityReference)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Prewarm != nil {
+		in, out := &in.Prewarm, &out.Prewarm
+		*out = new(ImagePrewarm)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackImageSpec.
+func (in *OpenStackImageSpec) DeepCopy() *OpenStackImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackImageStatus) DeepCopyInto(out *OpenStackImageStatus) {
+	*out = *in
+	if in.PrewarmedAvailabilityZones != nil {
+		in, out := &in.PrewarmedAvailabilityZones, &out.PrewarmedAvailabilityZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackImageStatus.
+func (in *OpenStackImageStatus) DeepCopy() *OpenStackImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachine) DeepCopyInto(out *OpenStackMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachine.
+func (in *OpenStackMachine) DeepCopy() *OpenStackMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineList) DeepCopyInto(out *OpenStackMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineList.
+func (in *OpenStackMachineList) DeepCopy() *OpenStackMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachinePool) DeepCopyInto(out *OpenStackMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachinePool.
+func (in *OpenStackMachinePool) DeepCopy() *OpenStackMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachinePoolInstance) DeepCopyInto(out *OpenStackMachinePoolInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachinePoolInstance.
+func (in *OpenStackMachinePoolInstance) DeepCopy() *OpenStackMachinePoolInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachinePoolInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachinePoolList) DeepCopyInto(out *OpenStackMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachinePoolList.
+func (in *OpenStackMachinePoolList) DeepCopy() *OpenStackMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachinePoolSpec) DeepCopyInto(out *OpenStackMachinePoolSpec) {
+	*out = *in
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachinePoolSpec.
+func (in *OpenStackMachinePoolSpec) DeepCopy() *OpenStackMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachinePoolStatus) DeepCopyInto(out *OpenStackMachinePoolStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]OpenStackMachinePoolInstance, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachinePoolStatus.
+func (in *OpenStackMachinePoolStatus) DeepCopy() *OpenStackMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
+	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.InstanceID != nil {
+		in, out := &in.InstanceID, &out.InstanceID
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdoptExisting != nil {
+		in, out := &in.AdoptExisting, &out.AdoptExisting
+		*out = new(ServerFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageFilter != nil {
+		in, out := &in.ImageFilter, &out.ImageFilter
+		*out = new(ImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SSHPublicKeySecretRef != nil {
+		in, out := &in.SSHPublicKeySecretRef, &out.SSHPublicKeySecretRef
+		*out = new(SSHPublicKeySecretReference)
+		**out = **in
+	}
+	if in.ServerPassword != nil {
+		in, out := &in.ServerPassword, &out.ServerPassword
+		*out = new(ServerPasswordOptions)
+		**out = **in
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]NetworkParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]PortOpts, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddressSortOrder != nil {
+		in, out := &in.AddressSortOrder, &out.AddressSortOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedSubnet != nil {
+		in, out := &in.ManagedSubnet, &out.ManagedSubnet
+		*out = new(ManagedSubnetSelector)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]SecurityGroupParam, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServerMetadata != nil {
+		in, out := &in.ServerMetadata, &out.ServerMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigDrive != nil {
+		in, out := &in.ConfigDrive, &out.ConfigDrive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Ignition != nil {
+		in, out := &in.Ignition, &out.Ignition
+		*out = new(IgnitionOptions)
+		**out = **in
+	}
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolume)
+		**out = **in
+	}
+	if in.AdditionalBlockDevices != nil {
+		in, out := &in.AdditionalBlockDevices, &out.AdditionalBlockDevices
+		*out = make([]AdditionalBlockDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlaneStorage != nil {
+		in, out := &in.ControlPlaneStorage, &out.ControlPlaneStorage
+		*out = new(ControlPlaneStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerGroup != nil {
+		in, out := &in.ServerGroup, &out.ServerGroup
+		*out = new(ServerGroup)
+		**out = **in
+	}
+	if in.SchedulerHintAdditionalProperties != nil {
+		in, out := &in.SchedulerHintAdditionalProperties, &out.SchedulerHintAdditionalProperties
+		*out = make([]SchedulerHintAdditionalProperty, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Traits != nil {
+		in, out := &in.Traits, &out.Traits
+		*out = new(Traits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reservation != nil {
+		in, out := &in.Reservation, &out.Reservation
+		*out = new(Reservation)
+		**out = **in
+	}
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(OpenStackIdentityReference)
+		**out = **in
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(MachineRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotBeforeDelete != nil {
+		in, out := &in.SnapshotBeforeDelete, &out.SnapshotBeforeDelete
+		*out = new(SnapshotBeforeDelete)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineSpec.
+func (in *OpenStackMachineSpec) DeepCopy() *OpenStackMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineStatus) DeepCopyInto(out *OpenStackMachineStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]v1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceState != nil {
+		in, out := &in.InstanceState, &out.InstanceState
+		*out = new(InstanceState)
+		**out = **in
+	}
+	if in.ServerCreateOpts != nil {
+		in, out := &in.ServerCreateOpts, &out.ServerCreateOpts
+		*out = new(ServerCreateOptsHashes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceActionsAudit != nil {
+		in, out := &in.InstanceActionsAudit, &out.InstanceActionsAudit
+		*out = new(InstanceActionsAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(MachineRemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AttachedBlockDevices != nil {
+		in, out := &in.AttachedBlockDevices, &out.AttachedBlockDevices
+		*out = make([]AttachedBlockDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(InstancePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(Plan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineStatus.
+func (in *OpenStackMachineStatus) DeepCopy() *OpenStackMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineTemplate) DeepCopyInto(out *OpenStackMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplate.
+func (in *OpenStackMachineTemplate) DeepCopy() *OpenStackMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineTemplateList) DeepCopyInto(out *OpenStackMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateList.
+func (in *OpenStackMachineTemplateList) DeepCopy() *OpenStackMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineTemplateResource) DeepCopyInto(out *OpenStackMachineTemplateResource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateResource.
+func (in *OpenStackMachineTemplateResource) DeepCopy() *OpenStackMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackMachineTemplateSpec) DeepCopyInto(out *OpenStackMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateSpec.
+func (in *OpenStackMachineTemplateSpec) DeepCopy() *OpenStackMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenVSwitchPortHints) DeepCopyInto(out *OpenVSwitchPortHints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenVSwitchPortHints.
+func (in *OpenVSwitchPortHints) DeepCopy() *OpenVSwitchPortHints {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenVSwitchPortHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedResource) DeepCopyInto(out *OrphanedResource) {
+	*out = *in
+	in.FoundAt.DeepCopyInto(&out.FoundAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedResource.
+func (in *OrphanedResource) DeepCopy() *OrphanedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plan) DeepCopyInto(out *Plan) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]PlannedOperation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plan.
+func (in *Plan) DeepCopy() *Plan {
+	if in == nil {
+		return nil
+	}
+	out := new(Plan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedOperation) DeepCopyInto(out *PlannedOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedOperation.
+func (in *PlannedOperation) DeepCopy() *PlannedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortHints) DeepCopyInto(out *PortHints) {
+	*out = *in
+	if in.OpenVSwitch != nil {
+		in, out := &in.OpenVSwitch, &out.OpenVSwitch
+		*out = new(OpenVSwitchPortHints)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortHints.
+func (in *PortHints) DeepCopy() *PortHints {
+	if in == nil {
+		return nil
+	}
+	out := new(PortHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortOpts) DeepCopyInto(out *PortOpts) {
+	*out = *in
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkFilter)
+		**out = **in
+	}
+	if in.AdminStateUp != nil {
+		in, out := &in.AdminStateUp, &out.AdminStateUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FixedIPs != nil {
+		in, out := &in.FixedIPs, &out.FixedIPs
+		*out = make([]FixedIP, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.SecurityGroupFilters != nil {
+		in, out := &in.SecurityGroupFilters, &out.SecurityGroupFilters
+		*out = make([]SecurityGroupParam, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAddressPairs != nil {
+		in, out := &in.AllowedAddressPairs, &out.AllowedAddressPairs
+		*out = make([]AddressPair, len(*in))
+		copy(*out, *in)
+	}
+	if in.Trunk != nil {
+		in, out := &in.Trunk, &out.Trunk
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Subports != nil {
+		in, out := &in.Subports, &out.Subports
+		*out = make([]SubportOpts, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BindingProfile != nil {
+		in, out := &in.BindingProfile, &out.BindingProfile
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.DisablePortSecurity != nil {
+		in, out := &in.DisablePortSecurity, &out.DisablePortSecurity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hints != nil {
+		in, out := &in.Hints, &out.Hints
+		*out = new(PortHints)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortOpts.
+func (in *PortOpts) DeepCopy() *PortOpts {
+	if in == nil {
+		return nil
+	}
+	out := new(PortOpts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightCheck) DeepCopyInto(out *PreflightCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightCheck.
+func (in *PreflightCheck) DeepCopy() *PreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightReport) DeepCopyInto(out *PreflightReport) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightReport.
+func (in *PreflightReport) DeepCopy() *PreflightReport {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderNetwork) DeepCopyInto(out *ProviderNetwork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderNetwork.
+func (in *ProviderNetwork) DeepCopy() *ProviderNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Reservation) DeepCopyInto(out *Reservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reservation.
+func (in *Reservation) DeepCopy() *Reservation {
+	if in == nil {
+		return nil
+	}
+	out := new(Reservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceNaming) DeepCopyInto(out *ResourceNaming) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceNaming.
+func (in *ResourceNaming) DeepCopy() *ResourceNaming {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceNaming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RootVolume) DeepCopyInto(out *RootVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootVolume.
+func (in *RootVolume) DeepCopy() *RootVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(RootVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Router) DeepCopyInto(out *Router) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Router.
+func (in *Router) DeepCopy() *Router {
+	if in == nil {
+		return nil
+	}
+	out := new(Router)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterExternalGateway) DeepCopyInto(out *RouterExternalGateway) {
+	*out = *in
+	if in.EnableSNAT != nil {
+		in, out := &in.EnableSNAT, &out.EnableSNAT
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterExternalGateway.
+func (in *RouterExternalGateway) DeepCopy() *RouterExternalGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterExternalGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterFilter) DeepCopyInto(out *RouterFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterFilter.
+func (in *RouterFilter) DeepCopy() *RouterFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHPrivateKeySecretReference) DeepCopyInto(out *SSHPrivateKeySecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHPrivateKeySecretReference.
+func (in *SSHPrivateKeySecretReference) DeepCopy() *SSHPrivateKeySecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHPrivateKeySecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHPublicKeySecretReference) DeepCopyInto(out *SSHPublicKeySecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHPublicKeySecretReference.
+func (in *SSHPublicKeySecretReference) DeepCopy() *SSHPublicKeySecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHPublicKeySecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerHintAdditionalProperty) DeepCopyInto(out *SchedulerHintAdditionalProperty) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerHintAdditionalProperty.
+func (in *SchedulerHintAdditionalProperty) DeepCopy() *SchedulerHintAdditionalProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerHintAdditionalProperty)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineStatus) DeepCopyInto(out *OpenStackMachineStatus) {
+func (in *SchedulerHintAdditionalValue) DeepCopyInto(out *SchedulerHintAdditionalValue) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]v1.NodeAddress, len(*in))
-		copy(*out, *in)
-	}
-	if in.InstanceState != nil {
-		in, out := &in.InstanceState, &out.InstanceState
-		*out = new(InstanceState)
-		**out = **in
-	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.MachineStatusError)
+	if in.Bool != nil {
+		in, out := &in.Bool, &out.Bool
+		*out = new(bool)
 		**out = **in
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
+	if in.String != nil {
+		in, out := &in.String, &out.String
 		*out = new(string)
 		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1beta1.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.StringList != nil {
+		in, out := &in.StringList, &out.StringList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Number != nil {
+		in, out := &in.Number, &out.Number
+		*out = new(int)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineStatus.
-func (in *OpenStackMachineStatus) DeepCopy() *OpenStackMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerHintAdditionalValue.
+func (in *SchedulerHintAdditionalValue) DeepCopy() *SchedulerHintAdditionalValue {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineStatus)
+	out := new(SchedulerHintAdditionalValue)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineTemplate) DeepCopyInto(out *OpenStackMachineTemplate) {
+func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityGroupRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplate.
-func (in *OpenStackMachineTemplate) DeepCopy() *OpenStackMachineTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroup.
+func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineTemplate)
+	out := new(SecurityGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackMachineTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineTemplateList) DeepCopyInto(out *OpenStackMachineTemplateList) {
+func (in *SecurityGroupFilter) DeepCopyInto(out *SecurityGroupFilter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OpenStackMachineTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateList.
-func (in *OpenStackMachineTemplateList) DeepCopy() *OpenStackMachineTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupFilter.
+func (in *SecurityGroupFilter) DeepCopy() *SecurityGroupFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineTemplateList)
+	out := new(SecurityGroupFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenStackMachineTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineTemplateResource) DeepCopyInto(out *OpenStackMachineTemplateResource) {
+func (in *SecurityGroupParam) DeepCopyInto(out *SecurityGroupParam) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Filter = in.Filter
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateResource.
-func (in *OpenStackMachineTemplateResource) DeepCopy() *OpenStackMachineTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupParam.
+func (in *SecurityGroupParam) DeepCopy() *SecurityGroupParam {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineTemplateResource)
+	out := new(SecurityGroupParam)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenStackMachineTemplateSpec) DeepCopyInto(out *OpenStackMachineTemplateSpec) {
+func (in *SecurityGroupRule) DeepCopyInto(out *SecurityGroupRule) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineTemplateSpec.
-func (in *OpenStackMachineTemplateSpec) DeepCopy() *OpenStackMachineTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRule.
+func (in *SecurityGroupRule) DeepCopy() *SecurityGroupRule {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenStackMachineTemplateSpec)
+	out := new(SecurityGroupRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PortOpts) DeepCopyInto(out *PortOpts) {
+func (in *SecurityGroupRuleSpec) DeepCopyInto(out *SecurityGroupRuleSpec) {
 	*out = *in
-	if in.Network != nil {
-		in, out := &in.Network, &out.Network
-		*out = new(NetworkFilter)
-		**out = **in
-	}
-	if in.AdminStateUp != nil {
-		in, out := &in.AdminStateUp, &out.AdminStateUp
-		*out = new(bool)
-		**out = **in
-	}
-	if in.FixedIPs != nil {
-		in, out := &in.FixedIPs, &out.FixedIPs
-		*out = make([]FixedIP, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
-		*out = new([]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]string, len(*in))
-			copy(*out, *in)
-		}
-	}
-	if in.SecurityGroupFilters != nil {
-		in, out := &in.SecurityGroupFilters, &out.SecurityGroupFilters
-		*out = make([]SecurityGroupParam, len(*in))
+	if in.RemoteManagedGroups != nil {
+		in, out := &in.RemoteManagedGroups, &out.RemoteManagedGroups
+		*out = make([]ManagedSecurityGroupName, len(*in))
 		copy(*out, *in)
 	}
-	if in.AllowedAddressPairs != nil {
-		in, out := &in.AllowedAddressPairs, &out.AllowedAddressPairs
-		*out = make([]AddressPair, len(*in))
-		copy(*out, *in)
-	}
-	if in.Trunk != nil {
-		in, out := &in.Trunk, &out.Trunk
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Profile != nil {
-		in, out := &in.Profile, &out.Profile
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.DisablePortSecurity != nil {
-		in, out := &in.DisablePortSecurity, &out.DisablePortSecurity
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]string, len(*in))
+	if in.RemoteSecurityGroups != nil {
+		in, out := &in.RemoteSecurityGroups, &out.RemoteSecurityGroups
+		*out = make([]SecurityGroupParam, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortOpts.
-func (in *PortOpts) DeepCopy() *PortOpts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRuleSpec.
+func (in *SecurityGroupRuleSpec) DeepCopy() *SecurityGroupRuleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PortOpts)
+	out := new(SecurityGroupRuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RootVolume) DeepCopyInto(out *RootVolume) {
+func (in *ServerCreateOptsHashes) DeepCopyInto(out *ServerCreateOptsHashes) {
 	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootVolume.
-func (in *RootVolume) DeepCopy() *RootVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerCreateOptsHashes.
+func (in *ServerCreateOptsHashes) DeepCopy() *ServerCreateOptsHashes {
 	if in == nil {
 		return nil
 	}
-	out := new(RootVolume)
+	out := new(ServerCreateOptsHashes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Router) DeepCopyInto(out *Router) {
+func (in *ServerFilter) DeepCopyInto(out *ServerFilter) {
 	*out = *in
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.IPs != nil {
-		in, out := &in.IPs, &out.IPs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Router.
-func (in *Router) DeepCopy() *Router {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerFilter.
+func (in *ServerFilter) DeepCopy() *ServerFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(Router)
+	out := new(ServerFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
+func (in *ServerGroup) DeepCopyInto(out *ServerGroup) {
 	*out = *in
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]SecurityGroupRule, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroup.
-func (in *SecurityGroup) DeepCopy() *SecurityGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerGroup.
+func (in *ServerGroup) DeepCopy() *ServerGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityGroup)
+	out := new(ServerGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityGroupFilter) DeepCopyInto(out *SecurityGroupFilter) {
+func (in *ServerPasswordOptions) DeepCopyInto(out *ServerPasswordOptions) {
 	*out = *in
+	out.PrivateKeySecretRef = in.PrivateKeySecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupFilter.
-func (in *SecurityGroupFilter) DeepCopy() *SecurityGroupFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPasswordOptions.
+func (in *ServerPasswordOptions) DeepCopy() *ServerPasswordOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityGroupFilter)
+	out := new(ServerPasswordOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityGroupParam) DeepCopyInto(out *SecurityGroupParam) {
+func (in *SharedLoadBalancer) DeepCopyInto(out *SharedLoadBalancer) {
 	*out = *in
-	out.Filter = in.Filter
+	out.LoadBalancer = in.LoadBalancer
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupParam.
-func (in *SecurityGroupParam) DeepCopy() *SecurityGroupParam {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedLoadBalancer.
+func (in *SharedLoadBalancer) DeepCopy() *SharedLoadBalancer {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityGroupParam)
+	out := new(SharedLoadBalancer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityGroupRule) DeepCopyInto(out *SecurityGroupRule) {
+func (in *SnapshotBeforeDelete) DeepCopyInto(out *SnapshotBeforeDelete) {
 	*out = *in
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRule.
-func (in *SecurityGroupRule) DeepCopy() *SecurityGroupRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBeforeDelete.
+func (in *SnapshotBeforeDelete) DeepCopy() *SnapshotBeforeDelete {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityGroupRule)
+	out := new(SnapshotBeforeDelete)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1039,3 +2767,78 @@ func (in *SubnetParam) DeepCopy() *SubnetParam {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubportOpts) DeepCopyInto(out *SubportOpts) {
+	*out = *in
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkFilter)
+		**out = **in
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(SubnetFilter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubportOpts.
+func (in *SubportOpts) DeepCopy() *SubportOpts {
+	if in == nil {
+		return nil
+	}
+	out := new(SubportOpts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Traits) DeepCopyInto(out *Traits) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Forbidden != nil {
+		in, out := &in.Forbidden, &out.Forbidden
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Traits.
+func (in *Traits) DeepCopy() *Traits {
+	if in == nil {
+		return nil
+	}
+	out := new(Traits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSchedulerHints) DeepCopyInto(out *VolumeSchedulerHints) {
+	*out = *in
+	if in.SameHostAs != nil {
+		in, out := &in.SameHostAs, &out.SameHostAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DifferentHostAs != nil {
+		in, out := &in.DifferentHostAs, &out.DifferentHostAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSchedulerHints.
+func (in *VolumeSchedulerHints) DeepCopy() *VolumeSchedulerHints {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSchedulerHints)
+	in.DeepCopyInto(out)
+	return out
+}
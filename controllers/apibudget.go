@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/apievents"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/apilog"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/budget"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/deprecation"
+)
+
+// maxDeprecationWarningsInCondition bounds the warnings listed in the message of the condition.
+const maxDeprecationWarningsInCondition = 5
+
+// apiBudgetExceeded returns whether the cluster has used up its OpenStack API budget. In that case the
+// reconciliation of obj is delayed until the budget is renewed, and the returned result requeues it then.
+func apiBudgetExceeded(log logr.Logger, cluster *clusterv1.Cluster, obj runtime.Object) (bool, ctrl.Result) {
+	exceeded, wait := budget.Exceeded(cluster.Namespace, cluster.Name)
+	if !exceeded {
+		return false, ctrl.Result{}
+	}
+
+	limit, period := budget.Limit()
+	log.Info("OpenStack API budget of the cluster is used up, delaying reconciliation", "limit", limit, "period", period, "requeueAfter", wait)
+	record.Warnf(obj, "APIBudgetExceeded", "Cluster %s used up its budget of %d OpenStack API calls per %s, delaying reconciliation by %s", cluster.Name, limit, period, wait)
+	return true, ctrl.Result{RequeueAfter: wait}
+}
+
+// trackAPIRequests counts the OpenStack API calls of the provider client against the budget of the cluster.
+func trackAPIRequests(providerClient *gophercloud.ProviderClient, cluster *clusterv1.Cluster) {
+	providerClient.HTTPClient.Transport = budget.Transport(providerClient.HTTPClient.Transport, cluster.Namespace, cluster.Name)
+}
+
+// logAPIRequests logs the OpenStack API requests of the provider client and their responses if debug logging is
+// enabled for all clusters or for the cluster by its annotation.
+func logAPIRequests(providerClient *gophercloud.ProviderClient, log logr.Logger, openStackCluster *infrav1.OpenStackCluster) {
+	if !apilog.Enabled() && openStackCluster.Annotations[infrav1.OpenStackAPIDebugAnnotation] != "true" {
+		return
+	}
+	providerClient.HTTPClient.Transport = apilog.Transport(providerClient.HTTPClient.Transport, log.WithName("openstack-api"))
+}
+
+// recordAPIRequests emits an event on obj for every OpenStack API call of the provider client which creates, updates
+// or deletes a resource.
+func recordAPIRequests(providerClient *gophercloud.ProviderClient, obj runtime.Object) {
+	if !apievents.Enabled() {
+		return
+	}
+	providerClient.HTTPClient.Transport = apievents.Transport(providerClient.HTTPClient.Transport, obj)
+}
+
+// trackAPIDeprecations records the deprecation warnings returned by the OpenStack services to the provider client for
+// its cloud, which is identified by its identity endpoint.
+func trackAPIDeprecations(providerClient *gophercloud.ProviderClient, log logr.Logger) {
+	providerClient.HTTPClient.Transport = deprecation.Transport(providerClient.HTTPClient.Transport, providerClient.IdentityBase, log.WithName("openstack-api"))
+}
+
+// markAPIDeprecations sets the OpenStackAPIUpToDateCondition of the cluster from the deprecation warnings recorded for
+// its cloud, by any of the controllers.
+func markAPIDeprecations(providerClient *gophercloud.ProviderClient, openStackCluster *infrav1.OpenStackCluster) {
+	warnings := deprecation.Warnings(providerClient.IdentityBase)
+	if len(warnings) == 0 {
+		conditions.Delete(openStackCluster, infrav1.OpenStackAPIUpToDateCondition)
+		return
+	}
+
+	messages := make([]string, 0, maxDeprecationWarningsInCondition)
+	for i, w := range warnings {
+		if i == maxDeprecationWarningsInCondition {
+			messages = append(messages, fmt.Sprintf("and %d more", len(warnings)-i))
+			break
+		}
+		messages = append(messages, w.String())
+	}
+	conditions.MarkFalse(openStackCluster, infrav1.OpenStackAPIUpToDateCondition, infrav1.OpenStackAPIDeprecatedReason,
+		clusterv1.ConditionSeverityWarning, "%s", strings.Join(messages, "; "))
+}
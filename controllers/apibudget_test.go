@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/deprecation"
+)
+
+func Test_markAPIDeprecations(t *testing.T) {
+	g := NewWithT(t)
+	defer deprecation.Reset()
+
+	providerClient := &gophercloud.ProviderClient{IdentityBase: "https://keystone.example.com/"}
+	openStackCluster := &infrav1.OpenStackCluster{}
+
+	markAPIDeprecations(providerClient, openStackCluster)
+	g.Expect(conditions.Has(openStackCluster, infrav1.OpenStackAPIUpToDateCondition)).To(BeFalse(), "condition is only set once warnings were returned")
+
+	for i := 0; i < maxDeprecationWarningsInCondition+2; i++ {
+		deprecation.Record(providerClient.IdentityBase, deprecation.Warning{Endpoint: "nova.example.com:8774", Header: "Warning", Value: fmt.Sprintf("299 - %d", i)})
+	}
+	markAPIDeprecations(providerClient, openStackCluster)
+	condition := conditions.Get(openStackCluster, infrav1.OpenStackAPIUpToDateCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(BeEquivalentTo("False"))
+	g.Expect(condition.Reason).To(Equal(infrav1.OpenStackAPIDeprecatedReason))
+	g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	g.Expect(condition.Message).To(ContainSubstring("nova.example.com:8774 returned Warning: 299 - 0"))
+	g.Expect(condition.Message).To(HaveSuffix("and 2 more"))
+}
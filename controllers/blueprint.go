@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/blueprint"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// blueprintObjectName returns the name of the object the blueprint of the cluster is exported to.
+func blueprintObjectName(openStackCluster *infrav1.OpenStackCluster) string {
+	if name := openStackCluster.Spec.BlueprintExport.Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s-blueprint", openStackCluster.Name)
+}
+
+// reconcileBlueprint exports the blueprint of the cluster to a ConfigMap or Secret if requested by the cluster. The
+// object is owned by the cluster, and is kept if the export is disabled.
+func reconcileBlueprint(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	if openStackCluster.Spec.BlueprintExport == nil {
+		return nil
+	}
+
+	data, err := blueprint.New(openStackCluster).Marshal()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal blueprint")
+	}
+
+	name := blueprintObjectName(openStackCluster)
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: openStackCluster.Namespace,
+		Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "OpenStackCluster",
+				Name:       openStackCluster.Name,
+				UID:        openStackCluster.UID,
+				Controller: pointer.Bool(true),
+			},
+		},
+	}
+
+	var obj client.Object
+	var current []byte
+	key := types.NamespacedName{Namespace: openStackCluster.Namespace, Name: name}
+	if openStackCluster.Spec.BlueprintExport.Kind == infrav1.BlueprintExportSecret {
+		secret := &corev1.Secret{}
+		err = c.Get(ctx, key, secret)
+		if apierrors.IsNotFound(err) {
+			secret = &corev1.Secret{ObjectMeta: meta, Type: corev1.SecretTypeOpaque}
+		}
+		current = secret.Data[blueprint.DataKey]
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[blueprint.DataKey] = data
+		obj = secret
+	} else {
+		configMap := &corev1.ConfigMap{}
+		err = c.Get(ctx, key, configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{ObjectMeta: meta}
+		}
+		if value, ok := configMap.Data[blueprint.DataKey]; ok {
+			current = []byte(value)
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[blueprint.DataKey] = string(data)
+		obj = configMap
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		err = c.Create(ctx, obj)
+	case err != nil:
+		return errors.Wrapf(err, "failed to get blueprint %s", name)
+	case bytes.Equal(current, data):
+		return nil
+	default:
+		err = c.Update(ctx, obj)
+	}
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedExportBlueprint", "Failed to export blueprint to %s: %v", name, err)
+		return errors.Wrapf(err, "failed to export blueprint to %s", name)
+	}
+	record.Eventf(openStackCluster, "SuccessfulExportBlueprint", "Exported blueprint to %s", name)
+	return nil
+}
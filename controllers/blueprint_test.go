@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/blueprint"
+)
+
+func Test_reconcileBlueprint(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	openStackCluster := &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{ID: "net", Name: "network"},
+		},
+	}
+	c := fake.NewClientBuilder().Build()
+
+	// Nothing is exported unless requested
+	g.Expect(reconcileBlueprint(ctx, c, cluster, openStackCluster)).To(Succeed())
+	configMaps := &corev1.ConfigMapList{}
+	g.Expect(c.List(ctx, configMaps)).To(Succeed())
+	g.Expect(configMaps.Items).To(BeEmpty())
+
+	openStackCluster.Spec.BlueprintExport = &infrav1.BlueprintExport{}
+	g.Expect(reconcileBlueprint(ctx, c, cluster, openStackCluster)).To(Succeed())
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "cluster-blueprint"}, configMap)).To(Succeed())
+	g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "cluster"))
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	exported, err := blueprint.Unmarshal([]byte(configMap.Data[blueprint.DataKey]))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exported.Network.ID).To(Equal("net"))
+
+	// The blueprint is updated when the resources change
+	openStackCluster.Status.Network.ID = "other"
+	g.Expect(reconcileBlueprint(ctx, c, cluster, openStackCluster)).To(Succeed())
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "cluster-blueprint"}, configMap)).To(Succeed())
+	exported, err = blueprint.Unmarshal([]byte(configMap.Data[blueprint.DataKey]))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exported.Network.ID).To(Equal("other"))
+
+	openStackCluster.Spec.BlueprintExport = &infrav1.BlueprintExport{Kind: infrav1.BlueprintExportSecret, Name: "dr"}
+	g.Expect(reconcileBlueprint(ctx, c, cluster, openStackCluster)).To(Succeed())
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "dr"}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(HaveKey(blueprint.DataKey))
+}
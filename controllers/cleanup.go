@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// CleanupVerification is how the controllers verify that no OpenStack resources remain after the deletion of a
+// cluster or machine.
+type CleanupVerification string
+
+const (
+	// CleanupVerificationNone does not verify deletions.
+	CleanupVerificationNone CleanupVerification = "none"
+	// CleanupVerificationReport reports the resources which remain after a deletion with an event and a metric, and
+	// removes the finalizer anyway.
+	CleanupVerificationReport CleanupVerification = "report"
+	// CleanupVerificationStrict reports the resources which remain after a deletion like CleanupVerificationReport,
+	// but keeps the finalizer until none remain.
+	CleanupVerificationStrict CleanupVerification = "strict"
+)
+
+// waitForCleanupToVerify is the delay before a deletion is verified again in strict mode.
+const waitForCleanupToVerify = 30 * time.Second
+
+// verifyCleanup lists the OpenStack resources of obj, an object of the kind, which remain after its deletion and
+// reports them. It returns whether the finalizer of obj may be removed, which in strict mode requires that no
+// resources remain and that they could be listed.
+func verifyCleanup(log logr.Logger, verification CleanupVerification, obj runtime.Object, kind string, list func() (map[string][]string, error)) (bool, error) {
+	if verification != CleanupVerificationReport && verification != CleanupVerificationStrict {
+		return true, nil
+	}
+
+	resources, err := list()
+	if err != nil {
+		if verification == CleanupVerificationStrict {
+			return false, fmt.Errorf("failed to verify the deletion: %w", err)
+		}
+		log.Error(err, "Failed to verify the deletion")
+		return true, nil
+	}
+
+	types := make([]string, 0, len(resources))
+	for resource, ids := range resources {
+		if len(ids) > 0 {
+			types = append(types, resource)
+		}
+	}
+	if len(types) == 0 {
+		return true, nil
+	}
+	sort.Strings(types)
+
+	leftovers := make([]string, 0, len(types))
+	for _, resource := range types {
+		ids := resources[resource]
+		metrics.LeftoverResourcesFound(kind, resource, len(ids))
+		leftovers = append(leftovers, fmt.Sprintf("%s %s", resource, strings.Join(ids, ", ")))
+	}
+	log.Info("OpenStack resources remain after deletion", "resources", leftovers, "verification", verification)
+	record.Warnf(obj, "LeftoverResources", "OpenStack resources remain after deletion: %s", strings.Join(leftovers, "; "))
+	return verification != CleanupVerificationStrict, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_verifyCleanup(t *testing.T) {
+	leftovers := func() (map[string][]string, error) {
+		return map[string][]string{"port": {"port-id"}, "network": nil}, nil
+	}
+	nothingLeft := func() (map[string][]string, error) {
+		return map[string][]string{"network": nil}, nil
+	}
+	listFails := func() (map[string][]string, error) {
+		return nil, errors.New("neutron unavailable")
+	}
+
+	tests := []struct {
+		name         string
+		verification CleanupVerification
+		list         func() (map[string][]string, error)
+		wantClean    bool
+		wantErr      bool
+	}{
+		{
+			name:         "deletion is not verified by default",
+			verification: CleanupVerificationNone,
+			list:         listFails,
+			wantClean:    true,
+		},
+		{
+			name:         "leftovers are only reported",
+			verification: CleanupVerificationReport,
+			list:         leftovers,
+			wantClean:    true,
+		},
+		{
+			name:         "failed verification is only logged",
+			verification: CleanupVerificationReport,
+			list:         listFails,
+			wantClean:    true,
+		},
+		{
+			name:         "leftovers keep the finalizer in strict mode",
+			verification: CleanupVerificationStrict,
+			list:         leftovers,
+			wantClean:    false,
+		},
+		{
+			name:         "failed verification keeps the finalizer in strict mode",
+			verification: CleanupVerificationStrict,
+			list:         listFails,
+			wantClean:    false,
+			wantErr:      true,
+		},
+		{
+			name:         "finalizer is removed once nothing is left in strict mode",
+			verification: CleanupVerificationStrict,
+			list:         nothingLeft,
+			wantClean:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clean, err := verifyCleanup(logr.Discard(), tt.verification, &infrav1.OpenStackMachine{}, "OpenStackMachine", tt.list)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(clean).To(Equal(tt.wantClean))
+		})
+	}
+}
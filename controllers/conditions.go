@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// openStackErrorReason returns the condition reason for an error returned by OpenStack, or fallback if the error
+// does not tell why OpenStack rejected the request.
+func openStackErrorReason(err error, fallback string) string {
+	if reason, ok := capoerrors.ClassifyConflict(err); ok {
+		switch reason {
+		case capoerrors.ConflictQuota:
+			return infrav1.QuotaExceededReason
+		case capoerrors.ConflictPending, capoerrors.ConflictInUse:
+			return infrav1.ResourceBusyReason
+		}
+		return fallback
+	}
+
+	switch {
+	case capoerrors.IsNotFound(err):
+		return infrav1.ResourceNotFoundReason
+	case capoerrors.IsForbidden(err):
+		return infrav1.ForbiddenReason
+	case capoerrors.IsInvalidError(err):
+		return infrav1.InvalidRequestReason
+	case capoerrors.IsRetryable(err):
+		return infrav1.OpenStackUnavailableReason
+	}
+	return fallback
+}
+
+// markOpenStackError marks the condition as false with the reason of the OpenStack error, or fallback, and the error
+// as message. Conflicts and service outages are retried, so they are only warnings.
+func markOpenStackError(obj conditions.Setter, conditionType clusterv1.ConditionType, fallback string, err error) {
+	severity := clusterv1.ConditionSeverityError
+	if capoerrors.IsConflict(err) || capoerrors.IsRetryable(err) {
+		severity = clusterv1.ConditionSeverityWarning
+	}
+	conditions.MarkFalse(obj, conditionType, openStackErrorReason(err, fallback), severity, "%s", err.Error())
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_markOpenStackError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantReason   string
+		wantSeverity clusterv1.ConditionSeverity
+	}{
+		{
+			name:         "Other errors use the fallback reason",
+			err:          errors.New("foo"),
+			wantReason:   infrav1.NetworkReconcileFailedReason,
+			wantSeverity: clusterv1.ConditionSeverityError,
+		},
+		{
+			name:         "Not found",
+			err:          errors.Wrap(gophercloud.ErrDefault404{}, "failed to get network"),
+			wantReason:   infrav1.ResourceNotFoundReason,
+			wantSeverity: clusterv1.ConditionSeverityError,
+		},
+		{
+			name: "Forbidden",
+			err: gophercloud.ErrDefault403{
+				ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusForbidden},
+			},
+			wantReason:   infrav1.ForbiddenReason,
+			wantSeverity: clusterv1.ConditionSeverityError,
+		},
+		{
+			name:         "Quota",
+			err:          conflictError(`{"NeutronError": {"type": "OverQuota", "message": "Quota exceeded for resources: ['network']."}}`),
+			wantReason:   infrav1.QuotaExceededReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:         "Pending load balancer",
+			err:          conflictError(`{"faultcode": "Client", "faultstring": "Load Balancer 123 is immutable and cannot be updated."}`),
+			wantReason:   infrav1.ResourceBusyReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:         "Other conflicts use the fallback reason",
+			err:          conflictError(`{}`),
+			wantReason:   infrav1.NetworkReconcileFailedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:         "Service unavailable",
+			err:          gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusServiceUnavailable},
+			wantReason:   infrav1.OpenStackUnavailableReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{}
+			markOpenStackError(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, tt.err)
+
+			condition := conditions.Get(openStackCluster, infrav1.NetworkReadyCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(conditions.IsFalse(openStackCluster, infrav1.NetworkReadyCondition)).To(BeTrue())
+			g.Expect(condition.Reason).To(Equal(tt.wantReason))
+			g.Expect(condition.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(condition.Message).To(Equal(tt.err.Error()))
+		})
+	}
+}
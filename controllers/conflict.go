@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// conflictRequeueDelays are the delays before retrying a reconciliation which failed with a 409 Conflict.
+// Load balancers in a PENDING_* state stay immutable until Octavia completes the operation, which takes
+// much longer than a port to be released.
+var conflictRequeueDelays = map[capoerrors.ConflictReason]time.Duration{
+	capoerrors.ConflictPending: 1 * time.Minute,
+	capoerrors.ConflictInUse:   5 * time.Second,
+	capoerrors.ConflictQuota:   5 * time.Minute,
+	capoerrors.ConflictOther:   15 * time.Second,
+}
+
+// requeueOnConflict replaces a reconciliation error caused by a 409 Conflict with a requeue after a delay
+// which depends on the kind of the conflict, instead of the exponential backoff of the controller.
+func requeueOnConflict(log logr.Logger, result ctrl.Result, err error) (ctrl.Result, error) {
+	reason, ok := capoerrors.ClassifyConflict(err)
+	if !ok {
+		return result, err
+	}
+
+	delay := conflictRequeueDelays[reason]
+	log.Info("OpenStack rejected a request with a conflict, requeuing", "reason", reason, "requeueAfter", delay, "error", err.Error())
+	return ctrl.Result{RequeueAfter: delay}, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func conflictError(body string) error {
+	return gophercloud.ErrDefault409{
+		ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{
+			Method:   http.MethodPut,
+			Expected: []int{http.StatusOK},
+			Actual:   http.StatusConflict,
+			Body:     []byte(body),
+		},
+	}
+}
+
+func Test_requeueOnConflict(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     ctrl.Result
+		err        error
+		wantResult ctrl.Result
+		wantErr    bool
+	}{
+		{
+			name:       "No error",
+			result:     ctrl.Result{RequeueAfter: time.Second},
+			wantResult: ctrl.Result{RequeueAfter: time.Second},
+		},
+		{
+			name:       "Other errors are returned",
+			err:        errors.New("foo"),
+			wantResult: ctrl.Result{},
+			wantErr:    true,
+		},
+		{
+			name:       "Load balancer in a PENDING_UPDATE state",
+			err:        errors.Wrap(conflictError(`{"faultcode": "Client", "faultstring": "Load Balancer 5d2a6b4c is immutable and cannot be updated.", "debuginfo": null}`), "failed to reconcile load balancer"),
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name:       "IP address in use",
+			err:        fmt.Errorf("error creating Openstack instance: %w", conflictError(`{"NeutronError": {"type": "IpAddressInUse", "message": "Unable to complete operation for network 1d2c. The IP address 10.6.0.10 is in use.", "detail": ""}}`)),
+			wantResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+		},
+		{
+			name:       "Port in use",
+			err:        conflictError(`{"NeutronError": {"type": "PortInUse", "message": "Unable to complete operation on port 7a3b for network 1d2c. Port already has an attached device.", "detail": ""}}`),
+			wantResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+		},
+		{
+			name:       "Quota exceeded",
+			err:        conflictError(`{"NeutronError": {"type": "OverQuota", "message": "Quota exceeded for resources: ['port'].", "detail": ""}}`),
+			wantResult: ctrl.Result{RequeueAfter: 5 * time.Minute},
+		},
+		{
+			name:       "Unknown conflict",
+			err:        conflictError(`conflict`),
+			wantResult: ctrl.Result{RequeueAfter: 15 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := requeueOnConflict(logr.Discard(), tt.result, tt.err)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(result).To(Equal(tt.wantResult))
+		})
+	}
+}
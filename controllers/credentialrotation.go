@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+)
+
+// CredentialRotationReconciler re-authenticates when an identity secret is replaced, e.g. with a new application
+// credential or trust, so that the cached clients of the previous credentials are not used any more. Machines
+// keep their servers, only the credentials the controller uses to manage them change.
+type CredentialRotationReconciler struct {
+	Client client.Client
+}
+
+func (r *CredentialRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err := provider.RotateCredentials(ctx, r.Client, req.NamespacedName); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to rotate the credentials of secret %s", req.NamespacedName)
+	}
+	log.V(4).Info("Reconciled identity secret")
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager watches only the metadata of secrets, as secrets are not cached by the manager. Only the
+// secrets whose credentials were used to create a cached client are reconciled.
+func (r *CredentialRotationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("credentialrotation").
+		WithOptions(options).
+		For(&corev1.Secret{}, builder.OnlyMetadata, builder.WithPredicates(
+			predicate.ResourceVersionChangedPredicate{},
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return provider.IsIdentitySecret(client.ObjectKeyFromObject(obj))
+			}),
+		)).
+		Complete(r)
+}
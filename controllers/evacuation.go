@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+// instanceEvacuator evacuates an instance whose hypervisor is down. It is implemented by compute.Service.
+type instanceEvacuator interface {
+	IsHypervisorDown(instanceStatus *compute.InstanceStatus) (bool, error)
+	EvacuateInstance(eventObject runtime.Object, instanceStatus *compute.InstanceStatus) error
+}
+
+// reconcileEvacuation evacuates the server of a machine which enables evacuation when its hypervisor is reported down,
+// instead of leaving it to be deleted and replaced. The hypervisor is only checked when the node of the machine is not
+// healthy or Nova cannot tell the state of the server, as listing hypervisors is an admin call. It returns whether the
+// server is being evacuated.
+func reconcileEvacuation(logger logr.Logger, evacuator instanceEvacuator, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus) (bool, error) {
+	remediation := openStackMachine.Spec.Remediation
+	if remediation == nil || !remediation.Evacuate {
+		return false, nil
+	}
+
+	// Nova only evacuates servers in these states
+	state := instanceStatus.State()
+	switch state {
+	case infrav1.InstanceStateActive, infrav1.InstanceStateShutoff, infrav1.InstanceStateError, infrav1.InstanceStateUnknown:
+	default:
+		return false, nil
+	}
+	if state != infrav1.InstanceStateUnknown && !conditions.IsFalse(machine, clusterv1.MachineNodeHealthyCondition) {
+		return false, nil
+	}
+
+	down, err := evacuator.IsHypervisorDown(instanceStatus)
+	if err != nil {
+		return false, errors.Wrapf(err, "error checking the hypervisor of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+	}
+	if !down {
+		return false, nil
+	}
+
+	logger.Info("Hypervisor is down, evacuating instance", "instance-id", instanceStatus.ID(), "hypervisor", instanceStatus.HypervisorHostname())
+	if err := evacuator.EvacuateInstance(openStackMachine, instanceStatus); err != nil {
+		return false, errors.Wrapf(err, "error evacuating OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+	}
+	conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceEvacuatingReason, clusterv1.ConditionSeverityWarning, "Hypervisor %s is down", instanceStatus.HypervisorHostname())
+	return true, nil
+}
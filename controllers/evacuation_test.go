@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+type fakeEvacuator struct {
+	hypervisorDown bool
+	err            error
+	checked        bool
+	evacuated      bool
+}
+
+func (f *fakeEvacuator) IsHypervisorDown(_ *compute.InstanceStatus) (bool, error) {
+	f.checked = true
+	return f.hypervisorDown, f.err
+}
+
+func (f *fakeEvacuator) EvacuateInstance(_ runtime.Object, _ *compute.InstanceStatus) error {
+	f.evacuated = true
+	return nil
+}
+
+func Test_reconcileEvacuation(t *testing.T) {
+	newMachine := func(nodeHealthy bool) *clusterv1.Machine {
+		machine := &clusterv1.Machine{}
+		if nodeHealthy {
+			conditions.MarkTrue(machine, clusterv1.MachineNodeHealthyCondition)
+		} else {
+			conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeConditionsFailedReason, clusterv1.ConditionSeverityWarning, "Node condition Ready is Unknown")
+		}
+		return machine
+	}
+	newOpenStackMachine := func(evacuate bool) *infrav1.OpenStackMachine {
+		return &infrav1.OpenStackMachine{
+			Spec: infrav1.OpenStackMachineSpec{
+				Remediation: &infrav1.MachineRemediation{Evacuate: evacuate},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		machine          *clusterv1.Machine
+		openStackMachine *infrav1.OpenStackMachine
+		state            infrav1.InstanceState
+		evacuator        fakeEvacuator
+		wantChecked      bool
+		wantEvacuated    bool
+		wantErr          bool
+	}{
+		{
+			name:             "Evacuation is not enabled",
+			machine:          newMachine(false),
+			openStackMachine: newOpenStackMachine(false),
+			state:            infrav1.InstanceStateActive,
+			evacuator:        fakeEvacuator{hypervisorDown: true},
+		},
+		{
+			name:             "Node is healthy",
+			machine:          newMachine(true),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceStateActive,
+			evacuator:        fakeEvacuator{hypervisorDown: true},
+		},
+		{
+			name:             "Server is being rebuilt",
+			machine:          newMachine(false),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceState("REBUILD"),
+			evacuator:        fakeEvacuator{hypervisorDown: true},
+		},
+		{
+			name:             "Node is unhealthy and hypervisor is up",
+			machine:          newMachine(false),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceStateActive,
+			evacuator:        fakeEvacuator{},
+			wantChecked:      true,
+		},
+		{
+			name:             "Node is unhealthy and hypervisor is down",
+			machine:          newMachine(false),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceStateActive,
+			evacuator:        fakeEvacuator{hypervisorDown: true},
+			wantChecked:      true,
+			wantEvacuated:    true,
+		},
+		{
+			name:             "Server state is unknown",
+			machine:          newMachine(true),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceStateUnknown,
+			evacuator:        fakeEvacuator{hypervisorDown: true},
+			wantChecked:      true,
+			wantEvacuated:    true,
+		},
+		{
+			name:             "Checking the hypervisor fails",
+			machine:          newMachine(false),
+			openStackMachine: newOpenStackMachine(true),
+			state:            infrav1.InstanceStateError,
+			evacuator:        fakeEvacuator{err: errors.New("Forbidden")},
+			wantChecked:      true,
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			instanceStatus := compute.NewInstanceStatusFromServer(&clients.ServerExt{Server: servers.Server{ID: "server-id", Name: "machine", Status: string(tt.state)}}, logr.Discard())
+
+			evacuating, err := reconcileEvacuation(logr.Discard(), &tt.evacuator, tt.machine, tt.openStackMachine, instanceStatus)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(evacuating).To(Equal(tt.wantEvacuated))
+			g.Expect(tt.evacuator.checked).To(Equal(tt.wantChecked))
+			g.Expect(tt.evacuator.evacuated).To(Equal(tt.wantEvacuated))
+			if tt.wantEvacuated {
+				g.Expect(conditions.GetReason(tt.openStackMachine, infrav1.InstanceReadyCondition)).To(Equal(infrav1.InstanceEvacuatingReason))
+			}
+		})
+	}
+}
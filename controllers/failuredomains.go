@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// reconcileFailureDomains reports the availability zones which exist in all the services which
+// follow the failure domain of a machine as failure domains of the cluster. These are Nova, and
+// Cinder unless the root volumes are in a fixed availability zone. It also checks that the fixed
+// compute and root volume availability zones exist.
+func reconcileFailureDomains(computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster) error {
+	availabilityZones, err := computeService.GetAvailabilityZones()
+	if err != nil {
+		return err
+	}
+	computeZones := make([]string, 0, len(availabilityZones))
+	for _, az := range availabilityZones {
+		computeZones = append(computeZones, az.ZoneName)
+	}
+
+	volumeAvailabilityZones, err := computeService.GetVolumeAvailabilityZones()
+	if err != nil {
+		return err
+	}
+	var volumeZones []string
+	if volumeAvailabilityZones != nil {
+		volumeZones = make([]string, 0, len(volumeAvailabilityZones))
+		for _, az := range volumeAvailabilityZones {
+			volumeZones = append(volumeZones, az.ZoneName)
+		}
+	}
+
+	if az := openStackCluster.Spec.ComputeAvailabilityZone; az != "" && !contains(computeZones, az) {
+		record.Warnf(openStackCluster, "InvalidAvailabilityZone", "Compute availability zone %s does not exist", az)
+		return fmt.Errorf("compute availability zone %s does not exist", az)
+	}
+	if az := openStackCluster.Spec.RootVolumeAvailabilityZone; az != "" && !contains(volumeZones, az) {
+		record.Warnf(openStackCluster, "InvalidAvailabilityZone", "Root volume availability zone %s does not exist", az)
+		return fmt.Errorf("root volume availability zone %s does not exist", az)
+	}
+
+	openStackCluster.Status.FailureDomains = clusterFailureDomains(openStackCluster, computeZones, volumeZones)
+	return nil
+}
+
+// clusterFailureDomains returns the failure domains of the cluster from the availability zones
+// of Nova and Cinder. volumeZones is nil if the cloud has no block storage service.
+func clusterFailureDomains(openStackCluster *infrav1.OpenStackCluster, computeZones, volumeZones []string) clusterv1.FailureDomains {
+	// Create a new list in case any AZs have been removed from OpenStack
+	failureDomains := make(clusterv1.FailureDomains)
+	for _, zone := range computeZones {
+		// Root volumes which follow the failure domain need the AZ in Cinder as well
+		if openStackCluster.Spec.RootVolumeAvailabilityZone == "" && volumeZones != nil && !contains(volumeZones, zone) {
+			continue
+		}
+
+		// By default, the AZ is used or not used for control plane nodes depending on the flag
+		found := !openStackCluster.Spec.ControlPlaneOmitAvailabilityZone
+		// If explicit AZs for control plane nodes are given, they override the value
+		if len(openStackCluster.Spec.ControlPlaneAvailabilityZones) > 0 {
+			found = contains(openStackCluster.Spec.ControlPlaneAvailabilityZones, zone)
+		}
+		// Add the AZ object to the failure domains for the cluster
+		failureDomains[zone] = clusterv1.FailureDomainSpec{
+			ControlPlane: found,
+		}
+	}
+	return failureDomains
+}
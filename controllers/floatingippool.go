@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// reconcileFloatingIPPoolClaims claims the addresses for the API server and the bastion of the
+// cluster from its floating IP pool. The claims are recorded in the status of the pool first, so
+// a conflicting update of the pool never leaves the same address claimed by two clusters. If the
+// pool has no free address left, a new floating IP is allocated as if there was no pool.
+func reconcileFloatingIPPoolClaims(ctx context.Context, c client.Client, openStackCluster *infrav1.OpenStackCluster) error {
+	if openStackCluster.Spec.FloatingIPPoolRef == nil {
+		return nil
+	}
+
+	var uses []string
+	if needsAPIServerFloatingIP(openStackCluster) {
+		uses = append(uses, networking.FloatingIPUseAPIServer)
+	}
+	if needsBastionFloatingIP(openStackCluster) {
+		uses = append(uses, networking.FloatingIPUseBastion)
+	}
+	if len(uses) == 0 {
+		return nil
+	}
+
+	pool := &infrav1.OpenStackFloatingIPPool{}
+	key := client.ObjectKey{Namespace: openStackCluster.Namespace, Name: openStackCluster.Spec.FloatingIPPoolRef.Name}
+	if err := c.Get(ctx, key, pool); err != nil {
+		return errors.Wrapf(err, "failed to get floating IP pool %s", key.Name)
+	}
+
+	var claims []infrav1.FloatingIPClaim
+	poolChanged := false
+	for _, use := range uses {
+		address, claimed := claimFloatingIP(pool, openStackCluster.Name, use)
+		if address == "" {
+			record.Warnf(openStackCluster, "FloatingIPPoolExhausted", "Floating IP pool %s has no free address for %s, allocating a new floating IP", pool.Name, use)
+			continue
+		}
+		poolChanged = poolChanged || claimed
+		claims = append(claims, infrav1.FloatingIPClaim{Address: address, Use: use})
+	}
+
+	if poolChanged {
+		if err := c.Status().Update(ctx, pool); err != nil {
+			return errors.Wrapf(err, "failed to claim floating IPs from pool %s", pool.Name)
+		}
+	}
+
+	for _, claim := range claims {
+		record.Eventf(openStackCluster, "SuccessfulClaimFloatingIP", "Claimed floating IP %s from pool %s for %s", claim.Address, pool.Name, claim.Use)
+	}
+	openStackCluster.Status.FloatingIPPoolClaims = append(openStackCluster.Status.FloatingIPPoolClaims, claims...)
+	return nil
+}
+
+// needsAPIServerFloatingIP returns whether the cluster should claim the floating IP of the API
+// server from its pool.
+func needsAPIServerFloatingIP(openStackCluster *infrav1.OpenStackCluster) bool {
+	if networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseAPIServer) != "" {
+		return false
+	}
+	// The floating IP of the API server cannot change once the control plane endpoint is set
+	if openStackCluster.Spec.DisableAPIServerFloatingIP || openStackCluster.Spec.APIServerFloatingIP != "" || openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+		return false
+	}
+	lb := openStackCluster.Spec.APIServerLoadBalancer
+	return !lb.Enabled || (lb.ExistingLoadBalancer == nil && lb.Shared == nil)
+}
+
+// needsBastionFloatingIP returns whether the cluster should claim the floating IP of the bastion
+// from its pool.
+func needsBastionFloatingIP(openStackCluster *infrav1.OpenStackCluster) bool {
+	if networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseBastion) != "" {
+		return false
+	}
+	bastion := openStackCluster.Spec.Bastion
+	return bastion != nil && bastion.Enabled && bastion.Instance.FloatingIP == ""
+}
+
+// claimFloatingIP returns the address of the pool claimed by the cluster for the given use,
+// claiming the first free address if there is none. It returns whether the status of the pool
+// was changed.
+func claimFloatingIP(pool *infrav1.OpenStackFloatingIPPool, clusterName, use string) (string, bool) {
+	claimed := make(map[string]bool, len(pool.Status.Claims))
+	for _, claim := range pool.Status.Claims {
+		if claim.ClusterName == clusterName && claim.Use == use {
+			return claim.Address, false
+		}
+		claimed[claim.Address] = true
+	}
+
+	for _, address := range pool.Spec.Addresses {
+		if !claimed[address] {
+			pool.Status.Claims = append(pool.Status.Claims, infrav1.FloatingIPClaim{
+				Address:     address,
+				ClusterName: clusterName,
+				Use:         use,
+			})
+			return address, true
+		}
+	}
+	return "", false
+}
+
+// releaseFloatingIPPoolClaims returns the addresses claimed by the cluster to its floating IP
+// pool. It must only be called after the addresses were disassociated.
+func releaseFloatingIPPoolClaims(ctx context.Context, c client.Client, openStackCluster *infrav1.OpenStackCluster) error {
+	if openStackCluster.Spec.FloatingIPPoolRef == nil {
+		return nil
+	}
+
+	pool := &infrav1.OpenStackFloatingIPPool{}
+	key := client.ObjectKey{Namespace: openStackCluster.Namespace, Name: openStackCluster.Spec.FloatingIPPoolRef.Name}
+	if err := c.Get(ctx, key, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			openStackCluster.Status.FloatingIPPoolClaims = nil
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get floating IP pool %s", key.Name)
+	}
+
+	claims := make([]infrav1.FloatingIPClaim, 0, len(pool.Status.Claims))
+	var released []string
+	for _, claim := range pool.Status.Claims {
+		if claim.ClusterName == openStackCluster.Name {
+			released = append(released, claim.Address)
+			continue
+		}
+		claims = append(claims, claim)
+	}
+
+	if len(released) > 0 {
+		pool.Status.Claims = claims
+		if err := c.Status().Update(ctx, pool); err != nil {
+			return errors.Wrapf(err, "failed to release floating IPs to pool %s", pool.Name)
+		}
+		for _, address := range released {
+			record.Eventf(openStackCluster, "SuccessfulReleaseFloatingIP", "Released floating IP %s to pool %s", address, pool.Name)
+		}
+	}
+
+	openStackCluster.Status.FloatingIPPoolClaims = nil
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func floatingIPPoolTestClient(g *WithT, pool *infrav1.OpenStackFloatingIPPool) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()
+}
+
+func floatingIPPoolTestCluster(name string) *infrav1.OpenStackCluster {
+	return &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: infrav1.OpenStackClusterSpec{
+			FloatingIPPoolRef: &corev1.LocalObjectReference{Name: "pool"},
+			Bastion:           &infrav1.Bastion{Enabled: true},
+		},
+	}
+}
+
+func Test_reconcileFloatingIPPoolClaims(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	pool := &infrav1.OpenStackFloatingIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "test"},
+		Spec: infrav1.OpenStackFloatingIPPoolSpec{
+			Addresses: []string{"172.24.4.10", "172.24.4.11", "172.24.4.12"},
+		},
+	}
+	c := floatingIPPoolTestClient(g, pool)
+
+	first := floatingIPPoolTestCluster("first")
+	g.Expect(reconcileFloatingIPPoolClaims(ctx, c, first)).To(Succeed())
+	g.Expect(first.Status.FloatingIPPoolClaims).To(Equal([]infrav1.FloatingIPClaim{
+		{Address: "172.24.4.10", Use: "apiserver"},
+		{Address: "172.24.4.11", Use: "bastion"},
+	}))
+
+	// Claims are kept on subsequent reconciles
+	g.Expect(reconcileFloatingIPPoolClaims(ctx, c, first)).To(Succeed())
+	g.Expect(first.Status.FloatingIPPoolClaims).To(HaveLen(2))
+
+	// The pool runs out of addresses for the bastion of the second cluster
+	second := floatingIPPoolTestCluster("second")
+	g.Expect(reconcileFloatingIPPoolClaims(ctx, c, second)).To(Succeed())
+	g.Expect(second.Status.FloatingIPPoolClaims).To(Equal([]infrav1.FloatingIPClaim{
+		{Address: "172.24.4.12", Use: "apiserver"},
+	}))
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+	g.Expect(pool.Status.Claims).To(Equal([]infrav1.FloatingIPClaim{
+		{Address: "172.24.4.10", ClusterName: "first", Use: "apiserver"},
+		{Address: "172.24.4.11", ClusterName: "first", Use: "bastion"},
+		{Address: "172.24.4.12", ClusterName: "second", Use: "apiserver"},
+	}))
+
+	g.Expect(releaseFloatingIPPoolClaims(ctx, c, first)).To(Succeed())
+	g.Expect(first.Status.FloatingIPPoolClaims).To(BeEmpty())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+	g.Expect(pool.Status.Claims).To(Equal([]infrav1.FloatingIPClaim{
+		{Address: "172.24.4.12", ClusterName: "second", Use: "apiserver"},
+	}))
+}
+
+func Test_needsAPIServerFloatingIP(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*infrav1.OpenStackCluster)
+		want   bool
+	}{
+		{
+			name:   "no floating IP yet",
+			modify: func(*infrav1.OpenStackCluster) {},
+			want:   true,
+		},
+		{
+			name: "floating IP is specified",
+			modify: func(c *infrav1.OpenStackCluster) {
+				c.Spec.APIServerFloatingIP = "172.24.4.20"
+			},
+			want: false,
+		},
+		{
+			name: "floating IP is disabled",
+			modify: func(c *infrav1.OpenStackCluster) {
+				c.Spec.DisableAPIServerFloatingIP = true
+			},
+			want: false,
+		},
+		{
+			name: "control plane endpoint is already set",
+			modify: func(c *infrav1.OpenStackCluster) {
+				c.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "172.24.4.20", Port: 6443}
+			},
+			want: false,
+		},
+		{
+			name: "shared load balancer",
+			modify: func(c *infrav1.OpenStackCluster) {
+				c.Spec.APIServerLoadBalancer = infrav1.APIServerLoadBalancer{
+					Enabled: true,
+					Shared:  &infrav1.SharedLoadBalancer{LoadBalancer: infrav1.LoadBalancerReference{Name: "shared"}},
+				}
+			},
+			want: false,
+		},
+		{
+			name: "managed load balancer",
+			modify: func(c *infrav1.OpenStackCluster) {
+				c.Spec.APIServerLoadBalancer = infrav1.APIServerLoadBalancer{Enabled: true}
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := floatingIPPoolTestCluster("cluster")
+			tt.modify(openStackCluster)
+			g.Expect(needsAPIServerFloatingIP(openStackCluster)).To(Equal(tt.want))
+		})
+	}
+}
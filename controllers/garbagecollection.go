@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	defaultGarbageCollectionGracePeriod = time.Hour
+	defaultGarbageCollectionInterval    = 10 * time.Minute
+)
+
+// orphanCollector lists and deletes the orphaned resources of a cluster.
+type orphanCollector interface {
+	list() ([]infrav1.OrphanedResource, error)
+	delete(orphan *infrav1.OrphanedResource) error
+}
+
+// reconcileGarbageCollection runs the garbage collection of the orphaned resources of the cluster if it is enabled
+// and its interval has passed since the last run. It returns when it should run next.
+func reconcileGarbageCollection(ctx context.Context, c client.Client, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, now time.Time) (time.Duration, error) {
+	gc := openStackCluster.Spec.GarbageCollection
+	if gc == nil {
+		openStackCluster.Status.GarbageCollection = nil
+		return 0, nil
+	}
+
+	interval := defaultGarbageCollectionInterval
+	if gc.Interval != nil {
+		interval = gc.Interval.Duration
+	}
+	if status := openStackCluster.Status.GarbageCollection; status != nil {
+		if next := status.LastRunTime.Add(interval); next.After(now) {
+			return next.Sub(now), nil
+		}
+	}
+
+	collector, err := newOrphanCollector(ctx, c, scope, cluster, openStackCluster)
+	if err != nil {
+		return 0, err
+	}
+	if err := collectGarbage(scope, collector, openStackCluster, now); err != nil {
+		return 0, err
+	}
+	return interval, nil
+}
+
+// collectGarbage deletes the orphans which have been found in previous runs for longer than the grace period, and
+// records the others in the status of the cluster with the time they were first found. Newly found orphans are
+// reported by an event.
+func collectGarbage(scope *scope.Scope, collector orphanCollector, openStackCluster *infrav1.OpenStackCluster, now time.Time) error {
+	gc := openStackCluster.Spec.GarbageCollection
+	gracePeriod := defaultGarbageCollectionGracePeriod
+	if gc.GracePeriod != nil {
+		gracePeriod = gc.GracePeriod.Duration
+	}
+
+	orphans, err := collector.list()
+	if err != nil {
+		return errors.Wrap(err, "failed to list orphaned resources")
+	}
+
+	foundAt := make(map[string]metav1.Time)
+	if status := openStackCluster.Status.GarbageCollection; status != nil {
+		for _, orphan := range status.Orphans {
+			foundAt[orphan.Type+"/"+orphan.ID] = orphan.FoundAt
+		}
+	}
+
+	var remaining []infrav1.OrphanedResource
+	var errs []error
+	for i := range orphans {
+		orphan := &orphans[i]
+		if t, ok := foundAt[orphan.Type+"/"+orphan.ID]; ok {
+			orphan.FoundAt = t
+		} else {
+			orphan.FoundAt = metav1.NewTime(now)
+			record.Warnf(openStackCluster, "FoundOrphanedResource", "Found orphaned %s %s with id %s", orphan.Type, orphan.Name, orphan.ID)
+		}
+
+		if gc.DryRun || now.Sub(orphan.FoundAt.Time) < gracePeriod {
+			remaining = append(remaining, *orphan)
+			continue
+		}
+		scope.Logger.Info("Deleting orphaned resource", "type", orphan.Type, "id", orphan.ID, "name", orphan.Name)
+		if err := collector.delete(orphan); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete orphaned %s %s: %w", orphan.Type, orphan.ID, err))
+			remaining = append(remaining, *orphan)
+		}
+	}
+
+	openStackCluster.Status.GarbageCollection = &infrav1.GarbageCollectionStatus{
+		LastRunTime: metav1.NewTime(now),
+		Orphans:     remaining,
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// clusterOrphanCollector collects the orphaned Neutron resources and volumes of a cluster.
+type clusterOrphanCollector struct {
+	eventObject       *infrav1.OpenStackCluster
+	clusterName       string
+	instanceNames     []string
+	addresses         []string
+	networkingService *networking.Service
+	computeService    *compute.Service
+}
+
+func newOrphanCollector(ctx context.Context, c client.Client, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (*clusterOrphanCollector, error) {
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceNames, machineAddresses, err := listInstances(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterOrphanCollector{
+		eventObject:       openStackCluster,
+		clusterName:       fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name),
+		instanceNames:     instanceNames,
+		addresses:         append(clusterFloatingIPs(openStackCluster), machineAddresses...),
+		networkingService: networkingService,
+		computeService:    computeService,
+	}, nil
+}
+
+// listInstances returns the names of the instances whose resources are not orphaned, i.e. the bastion of the cluster
+// and the machines and machine pools in its namespace, and the floating IPs of the machines.
+func listInstances(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) ([]string, []string, error) {
+	instanceNames := []string{fmt.Sprintf("%s-bastion", cluster.Name)}
+	var addresses []string
+
+	// The machines and machine pools of all clusters in the namespace are considered, as a resource which
+	// belongs to an instance by its name must not be deleted, even if the instance is not labelled yet.
+	machineList := &infrav1.OpenStackMachineList{}
+	if err := c.List(ctx, machineList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list OpenStackMachines")
+	}
+	for i := range machineList.Items {
+		instanceNames = append(instanceNames, machineList.Items[i].Name)
+		if address := machineList.Items[i].Spec.FloatingIP; address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	poolList := &infrav1.OpenStackMachinePoolList{}
+	if err := c.List(ctx, poolList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list OpenStackMachinePools")
+	}
+	for i := range poolList.Items {
+		instanceNames = append(instanceNames, poolList.Items[i].Name)
+	}
+	return instanceNames, addresses, nil
+}
+
+func (c *clusterOrphanCollector) list() ([]infrav1.OrphanedResource, error) {
+	orphans, err := c.networkingService.ListOrphanedResources(c.eventObject, c.clusterName, c.instanceNames, c.addresses)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := c.computeService.ListOrphanedVolumes(instanceClusterName(c.eventObject), c.instanceNames)
+	if err != nil {
+		return nil, err
+	}
+	return append(orphans, volumes...), nil
+}
+
+func (c *clusterOrphanCollector) delete(orphan *infrav1.OrphanedResource) error {
+	if orphan.Type == compute.VolumeResource {
+		return c.computeService.DeleteOrphanedVolume(c.eventObject, orphan.ID)
+	}
+	return c.networkingService.DeleteOrphanedResource(c.eventObject, orphan)
+}
+
+// clusterFloatingIPs returns the floating IPs referenced by the spec and the status of the cluster, which are not
+// orphaned even if they are not associated with a port, e.g. an API server floating IP reserved in advance.
+func clusterFloatingIPs(openStackCluster *infrav1.OpenStackCluster) []string {
+	var addresses []string
+	add := func(address string) {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	add(openStackCluster.Spec.APIServerFloatingIP)
+	add(openStackCluster.Spec.ControlPlaneEndpoint.Host)
+	add(openStackCluster.Status.APIServerAddress)
+	if bastion := openStackCluster.Spec.Bastion; bastion != nil {
+		add(bastion.Instance.FloatingIP)
+	}
+	for _, fip := range openStackCluster.Spec.AdditionalFloatingIPs {
+		add(fip.FloatingIP)
+	}
+	for _, address := range openStackCluster.Status.ExternalAddresses {
+		add(address.Address)
+	}
+	for _, claim := range openStackCluster.Status.FloatingIPPoolClaims {
+		add(claim.Address)
+	}
+	return addresses
+}
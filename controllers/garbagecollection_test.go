@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+type fakeOrphanCollector struct {
+	orphans   []infrav1.OrphanedResource
+	deleteErr error
+	deleted   []string
+}
+
+func (f *fakeOrphanCollector) list() ([]infrav1.OrphanedResource, error) {
+	return f.orphans, nil
+}
+
+func (f *fakeOrphanCollector) delete(orphan *infrav1.OrphanedResource) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, orphan.ID)
+	return nil
+}
+
+func Test_collectGarbage(t *testing.T) {
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	longAgo := metav1.NewTime(now.Add(-2 * time.Hour))
+	recently := metav1.NewTime(now.Add(-10 * time.Minute))
+	orphans := func() []infrav1.OrphanedResource {
+		return []infrav1.OrphanedResource{
+			{Type: "port", ID: "old-port"},
+			{Type: "port", ID: "new-port"},
+			{Type: "floatingip", ID: "recent-fip"},
+		}
+	}
+	previousStatus := &infrav1.GarbageCollectionStatus{
+		LastRunTime: metav1.NewTime(now.Add(-10 * time.Minute)),
+		Orphans: []infrav1.OrphanedResource{
+			{Type: "port", ID: "old-port", FoundAt: longAgo},
+			{Type: "floatingip", ID: "recent-fip", FoundAt: recently},
+			{Type: "port", ID: "gone-port", FoundAt: longAgo},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		gc            infrav1.GarbageCollection
+		deleteErr     error
+		wantDeleted   []string
+		wantRemaining map[string]metav1.Time
+		wantErr       bool
+	}{
+		{
+			name:        "orphans found longer than the grace period ago are deleted",
+			wantDeleted: []string{"old-port"},
+			wantRemaining: map[string]metav1.Time{
+				"new-port":   metav1.NewTime(now),
+				"recent-fip": recently,
+			},
+		},
+		{
+			name:        "grace period can be shortened",
+			gc:          infrav1.GarbageCollection{GracePeriod: &metav1.Duration{Duration: 5 * time.Minute}},
+			wantDeleted: []string{"old-port", "recent-fip"},
+			wantRemaining: map[string]metav1.Time{
+				"new-port": metav1.NewTime(now),
+			},
+		},
+		{
+			name: "orphans are only reported in dry run",
+			gc:   infrav1.GarbageCollection{DryRun: true},
+			wantRemaining: map[string]metav1.Time{
+				"old-port":   longAgo,
+				"new-port":   metav1.NewTime(now),
+				"recent-fip": recently,
+			},
+		},
+		{
+			name:      "orphans which fail to be deleted are kept",
+			deleteErr: errors.New("port is in use"),
+			wantRemaining: map[string]metav1.Time{
+				"old-port":   longAgo,
+				"new-port":   metav1.NewTime(now),
+				"recent-fip": recently,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   infrav1.OpenStackClusterSpec{GarbageCollection: &tt.gc},
+				Status: infrav1.OpenStackClusterStatus{GarbageCollection: previousStatus.DeepCopy()},
+			}
+			collector := &fakeOrphanCollector{orphans: orphans(), deleteErr: tt.deleteErr}
+
+			err := collectGarbage(&scope.Scope{Logger: logr.Discard()}, collector, openStackCluster, now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(collector.deleted).To(Equal(tt.wantDeleted))
+
+			status := openStackCluster.Status.GarbageCollection
+			g.Expect(status).NotTo(BeNil())
+			g.Expect(status.LastRunTime).To(Equal(metav1.NewTime(now)))
+			remaining := make(map[string]metav1.Time)
+			for _, orphan := range status.Orphans {
+				remaining[orphan.ID] = orphan.FoundAt
+			}
+			g.Expect(remaining).To(Equal(tt.wantRemaining))
+		})
+	}
+}
+
+func Test_listInstances(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a-md-0-abcde", Namespace: "default"},
+			Spec:       infrav1.OpenStackMachineSpec{FloatingIP: "203.0.113.10"},
+		},
+		&infrav1.OpenStackMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a-pool-0", Namespace: "default"}},
+		&infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Name: "cluster-b-md-0-fghij", Namespace: "other"}},
+	).Build()
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "default"}}
+
+	instanceNames, addresses, err := listInstances(context.Background(), c, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instanceNames).To(ConsistOf("cluster-a-bastion", "cluster-a-md-0-abcde", "cluster-a-pool-0"))
+	g.Expect(addresses).To(ConsistOf("203.0.113.10"))
+
+	// The resources of the bastion are named after its instance, which does not include the namespace
+	g.Expect(names.BelongsToInstance("cluster-a-bastion-0", instanceNames)).To(BeTrue())
+	g.Expect(names.BelongsToInstance("cluster-a-bastion-root", instanceNames)).To(BeTrue())
+	g.Expect(names.BelongsToInstance("cluster-b-md-0-fghij-0", instanceNames)).To(BeFalse())
+}
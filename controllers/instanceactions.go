@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// reconcileInstanceActions reports actions performed on the instance of the machine outside of CAPO, e.g. a stop
+// or a migration by an admin of the cloud, with an event. The actions are listed at most once per interval, and
+// an interval of 0 disables the audit. Actions which started before the first check are not reported. Failures
+// to check are only logged, as the audit does not affect the instance.
+func reconcileInstanceActions(logger logr.Logger, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus, interval time.Duration) {
+	if interval == 0 {
+		return
+	}
+
+	now := metav1.Now()
+	audit := openStackMachine.Status.InstanceActionsAudit
+	if audit != nil && now.Sub(audit.LastCheckTime.Time) < interval {
+		return
+	}
+
+	var since *time.Time
+	if audit != nil && audit.LastActionTime != nil {
+		since = &audit.LastActionTime.Time
+	}
+	actions, latest, err := computeService.GetOutOfBandInstanceActions(instanceStatus.ID(), since)
+	if err != nil {
+		logger.Error(err, "Failed to check instance actions", "instance-id", instanceStatus.ID())
+		return
+	}
+
+	if audit != nil {
+		for _, action := range actions {
+			record.Warnf(openStackMachine, "InstanceActionDetected", "Action %s was performed on instance %s by user %s of project %s at %s",
+				action.Action, instanceStatus.ID(), action.UserID, action.ProjectID, action.StartTime.UTC().Format(time.RFC3339))
+		}
+	}
+
+	newAudit := &infrav1.InstanceActionsAudit{LastCheckTime: now}
+	switch {
+	case latest != nil:
+		newAudit.LastActionTime = &metav1.Time{Time: *latest}
+	case audit != nil:
+		newAudit.LastActionTime = audit.LastActionTime
+	}
+	openStackMachine.Status.InstanceActionsAudit = newAudit
+}
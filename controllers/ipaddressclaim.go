@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// ipAddressClaimName returns the name of the claim for a fixed IP of a port of the machine.
+func ipAddressClaimName(openStackMachine *infrav1.OpenStackMachine, portIndex, fixedIPIndex int) string {
+	return fmt.Sprintf("%s-%d-%d", openStackMachine.Name, portIndex, fixedIPIndex)
+}
+
+// reconcileIPAddressClaims claims the fixed IPs of the ports of the machine which reference an
+// IPAM pool and returns the ports with the claimed addresses. It returns false if an address has
+// not been allocated by the IPAM provider yet.
+func reconcileIPAddressClaims(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, openStackMachine *infrav1.OpenStackMachine) ([]infrav1.PortOpts, bool, error) {
+	if !hasIPAddressPoolRefs(openStackMachine.Spec.Ports) {
+		return openStackMachine.Spec.Ports, true, nil
+	}
+
+	ports := make([]infrav1.PortOpts, len(openStackMachine.Spec.Ports))
+	allocated := true
+	for i := range openStackMachine.Spec.Ports {
+		port := *openStackMachine.Spec.Ports[i].DeepCopy()
+		for j := range port.FixedIPs {
+			poolRef := port.FixedIPs[j].IPAddressPoolRef
+			if poolRef == nil {
+				continue
+			}
+
+			claim, err := getOrCreateIPAddressClaim(ctx, c, cluster, openStackMachine, ipAddressClaimName(openStackMachine, i, j), poolRef)
+			if err != nil {
+				return nil, false, err
+			}
+			if claim.Status.AddressRef.Name == "" {
+				allocated = false
+				continue
+			}
+
+			address := &ipamv1.IPAddress{}
+			key := client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}
+			if err := c.Get(ctx, key, address); err != nil {
+				if apierrors.IsNotFound(err) {
+					allocated = false
+					continue
+				}
+				return nil, false, errors.Wrapf(err, "failed to get IP address %s", key.Name)
+			}
+			port.FixedIPs[j].IPAddress = address.Spec.Address
+		}
+		ports[i] = port
+	}
+
+	if !allocated {
+		return nil, false, nil
+	}
+	return ports, true, nil
+}
+
+func hasIPAddressPoolRefs(ports []infrav1.PortOpts) bool {
+	for _, port := range ports {
+		for _, fixedIP := range port.FixedIPs {
+			if fixedIP.IPAddressPoolRef != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getOrCreateIPAddressClaim(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, openStackMachine *infrav1.OpenStackMachine, name string, poolRef *corev1.TypedLocalObjectReference) (*ipamv1.IPAddressClaim, error) {
+	claim := &ipamv1.IPAddressClaim{}
+	key := client.ObjectKey{Namespace: openStackMachine.Namespace, Name: name}
+	err := c.Get(ctx, key, claim)
+	if err == nil {
+		return claim, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get IP address claim %s", name)
+	}
+
+	claim = &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: openStackMachine.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       "OpenStackMachine",
+					Name:       openStackMachine.Name,
+					UID:        openStackMachine.UID,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: *poolRef,
+		},
+	}
+	if err := c.Create(ctx, claim); err != nil {
+		record.Warnf(openStackMachine, "FailedCreateIPAddressClaim", "Failed to create IP address claim %s: %v", name, err)
+		return nil, errors.Wrapf(err, "failed to create IP address claim %s", name)
+	}
+	record.Eventf(openStackMachine, "SuccessfulCreateIPAddressClaim", "Created IP address claim %s from pool %s", name, poolRef.Name)
+	return claim, nil
+}
+
+// deleteIPAddressClaims releases the addresses claimed for the ports of the machine.
+func deleteIPAddressClaims(ctx context.Context, c client.Client, openStackMachine *infrav1.OpenStackMachine) error {
+	for i, port := range openStackMachine.Spec.Ports {
+		for j, fixedIP := range port.FixedIPs {
+			if fixedIP.IPAddressPoolRef == nil {
+				continue
+			}
+
+			claim := &ipamv1.IPAddressClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: openStackMachine.Namespace,
+					Name:      ipAddressClaimName(openStackMachine, i, j),
+				},
+			}
+			if err := c.Delete(ctx, claim); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				record.Warnf(openStackMachine, "FailedDeleteIPAddressClaim", "Failed to delete IP address claim %s: %v", claim.Name, err)
+				return errors.Wrapf(err, "failed to delete IP address claim %s", claim.Name)
+			}
+			record.Eventf(openStackMachine, "SuccessfulDeleteIPAddressClaim", "Deleted IP address claim %s", claim.Name)
+		}
+	}
+	return nil
+}
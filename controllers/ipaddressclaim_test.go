@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_reconcileIPAddressClaims(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	poolRef := corev1.TypedLocalObjectReference{
+		APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+		Kind:     "InClusterIPPool",
+		Name:     "pool",
+	}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test"}}
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "test"},
+		Spec: infrav1.OpenStackMachineSpec{
+			Ports: []infrav1.PortOpts{
+				{Description: "DHCP"},
+				{
+					FixedIPs: []infrav1.FixedIP{
+						{Subnet: &infrav1.SubnetFilter{Name: "subnet"}, IPAddressPoolRef: &poolRef},
+					},
+				},
+			},
+		},
+	}
+
+	// The claim is created, but the address is not allocated yet
+	ports, allocated, err := reconcileIPAddressClaims(ctx, c, cluster, openStackMachine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeFalse())
+	g.Expect(ports).To(BeNil())
+
+	claim := &ipamv1.IPAddressClaim{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-machine-1-0"}, claim)).To(Succeed())
+	g.Expect(claim.Spec.PoolRef).To(Equal(poolRef))
+	g.Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "test-cluster"))
+	g.Expect(claim.OwnerReferences).To(HaveLen(1))
+	g.Expect(claim.OwnerReferences[0].Name).To(Equal("test-machine"))
+
+	// The IPAM provider allocates the address
+	g.Expect(c.Create(ctx, &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine-1-0", Namespace: "test"},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef:  poolRef,
+			Address:  "10.0.0.10",
+			Prefix:   24,
+		},
+	})).To(Succeed())
+	claim.Status.AddressRef.Name = "test-machine-1-0"
+	g.Expect(c.Status().Update(ctx, claim)).To(Succeed())
+
+	ports, allocated, err = reconcileIPAddressClaims(ctx, c, cluster, openStackMachine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeTrue())
+	g.Expect(ports).To(HaveLen(2))
+	g.Expect(ports[0]).To(Equal(openStackMachine.Spec.Ports[0]))
+	g.Expect(ports[1].FixedIPs[0].IPAddress).To(Equal("10.0.0.10"))
+	g.Expect(ports[1].FixedIPs[0].Subnet).To(Equal(&infrav1.SubnetFilter{Name: "subnet"}))
+	// The spec of the machine is not modified
+	g.Expect(openStackMachine.Spec.Ports[1].FixedIPs[0].IPAddress).To(BeEmpty())
+
+	// The claim is released on delete
+	g.Expect(deleteIPAddressClaims(ctx, c, openStackMachine)).To(Succeed())
+	err = c.Get(ctx, client.ObjectKeyFromObject(claim), claim)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(deleteIPAddressClaims(ctx, c, openStackMachine)).To(Succeed())
+}
+
+func Test_reconcileIPAddressClaimsWithoutPools(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackMachine := &infrav1.OpenStackMachine{
+		Spec: infrav1.OpenStackMachineSpec{
+			Ports: []infrav1.PortOpts{{Description: "DHCP"}},
+		},
+	}
+
+	// No client calls are made if no port references a pool
+	ports, allocated, err := reconcileIPAddressClaims(context.TODO(), nil, &clusterv1.Cluster{}, openStackMachine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeTrue())
+	g.Expect(ports).To(Equal(openStackMachine.Spec.Ports))
+}
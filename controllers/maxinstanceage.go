@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// waitForInstanceReplacementDuration is the interval at which an expired machine checks whether it is its turn
+// to be replaced.
+const waitForInstanceReplacementDuration = time.Minute
+
+// maxInstanceAge returns the maximum age set on the OpenStackMachineTemplate the machine was cloned from, or zero
+// if the machine has no maximum age.
+func maxInstanceAge(ctx context.Context, c client.Client, openStackMachine *infrav1.OpenStackMachine) (time.Duration, error) {
+	templateName, ok := openStackMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	template := &infrav1.OpenStackMachineTemplate{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: openStackMachine.Namespace, Name: templateName}, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The template was removed by a rollout, which replaces the machine anyway
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "failed to get OpenStackMachineTemplate %s", templateName)
+	}
+
+	value, ok := template.Annotations[infrav1.MaxInstanceAgeAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil || maxAge <= 0 {
+		return 0, errors.Errorf("invalid %s annotation %q on OpenStackMachineTemplate %s", infrav1.MaxInstanceAgeAnnotation, value, templateName)
+	}
+	return maxAge, nil
+}
+
+// reconcileMaxInstanceAge marks a machine of a MachineDeployment for replacement by its MachineSet once it is
+// older than the maximum age of its template. To keep the capacity of the MachineDeployment, machines are marked
+// one at a time: the oldest expired machine of a MachineSet first, and only while no other machine of the
+// MachineDeployment is being replaced or provisioned. It returns when the machine should be checked again.
+func reconcileMaxInstanceAge(ctx context.Context, c client.Client, logger logr.Logger, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, now time.Time) (time.Duration, error) {
+	deploymentName, ok := machine.Labels[clusterv1.MachineDeploymentLabelName]
+	if !ok || conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return 0, nil
+	}
+
+	maxAge, err := maxInstanceAge(ctx, c, openStackMachine)
+	if err != nil || maxAge == 0 {
+		return 0, err
+	}
+	expiry := machine.CreationTimestamp.Add(maxAge)
+	if expiry.After(now) {
+		return expiry.Sub(now), nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(machine.Namespace), client.MatchingLabels{clusterv1.MachineDeploymentLabelName: deploymentName}); err != nil {
+		return 0, errors.Wrapf(err, "failed to list machines of MachineDeployment %s", deploymentName)
+	}
+	owner := metav1.GetControllerOf(machine)
+	for i := range machines.Items {
+		m := &machines.Items[i]
+		if m.Name == machine.Name {
+			continue
+		}
+		if !m.DeletionTimestamp.IsZero() || conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition) || m.Status.NodeRef == nil {
+			logger.Info("Waiting for the other machines of the MachineDeployment to be running before replacing the expired machine", "otherMachine", m.Name)
+			return waitForInstanceReplacementDuration, nil
+		}
+		if otherOwner := metav1.GetControllerOf(m); owner != nil && otherOwner != nil && otherOwner.UID == owner.UID && olderMachine(m, machine) {
+			logger.Info("Waiting for an older machine of the MachineSet to be replaced first", "otherMachine", m.Name)
+			return waitForInstanceReplacementDuration, nil
+		}
+	}
+
+	if err := markMachineForReplacement(ctx, c, machine, infrav1.InstanceExpiredReason, "Machine is older than %s", maxAge); err != nil {
+		return 0, err
+	}
+	logger.Info("Marked expired machine for replacement", "maxInstanceAge", maxAge)
+	record.Eventf(openStackMachine, "InstanceExpired", "Machine %s is older than %s and was marked for replacement", machine.Name, maxAge)
+	return 0, nil
+}
+
+// olderMachine returns true if a was created before b. Machines created in the same second are ordered by name.
+func olderMachine(a, b *clusterv1.Machine) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_reconcileMaxInstanceAge(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	newMachine := func(name, machineSet string, age time.Duration) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            map[string]string{clusterv1.MachineDeploymentLabelName: "workers"},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: machineSet, UID: types.UID(machineSet), Controller: pointer.Bool(true)},
+				},
+			},
+			Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: name}},
+		}
+	}
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "machine",
+			Namespace:   "test",
+			Annotations: map[string]string{clusterv1.TemplateClonedFromNameAnnotation: "workers"},
+		},
+	}
+	newTemplate := func(maxAge string) *infrav1.OpenStackMachineTemplate {
+		template := &infrav1.OpenStackMachineTemplate{ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "test"}}
+		if maxAge != "" {
+			template.Annotations = map[string]string{infrav1.MaxInstanceAgeAnnotation: maxAge}
+		}
+		return template
+	}
+
+	tests := []struct {
+		name             string
+		template         *infrav1.OpenStackMachineTemplate
+		others           []*clusterv1.Machine
+		machineAge       time.Duration
+		wantRequeueAfter time.Duration
+		wantMarked       bool
+		wantErr          bool
+	}{
+		{
+			name:       "No maximum age",
+			template:   newTemplate(""),
+			machineAge: 100 * day,
+		},
+		{
+			name:       "Template removed",
+			machineAge: 100 * day,
+		},
+		{
+			name:             "Machine not expired",
+			template:         newTemplate("720h"),
+			machineAge:       20 * day,
+			wantRequeueAfter: 10 * day,
+		},
+		{
+			name:       "Machine expired",
+			template:   newTemplate("720h"),
+			others:     []*clusterv1.Machine{newMachine("newer", "ms", 40*day)},
+			machineAge: 50 * day,
+			wantMarked: true,
+		},
+		{
+			name:             "Older machine of the MachineSet expired",
+			template:         newTemplate("720h"),
+			others:           []*clusterv1.Machine{newMachine("older", "ms", 60*day)},
+			machineAge:       50 * day,
+			wantRequeueAfter: waitForInstanceReplacementDuration,
+		},
+		{
+			name:       "Older machine of another MachineSet",
+			template:   newTemplate("720h"),
+			others:     []*clusterv1.Machine{newMachine("older", "old-ms", 60*day)},
+			machineAge: 50 * day,
+			wantMarked: true,
+		},
+		{
+			name:     "Other machine being replaced",
+			template: newTemplate("720h"),
+			others: func() []*clusterv1.Machine {
+				m := newMachine("replaced", "ms", 40*day)
+				conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, infrav1.InstanceExpiredReason, clusterv1.ConditionSeverityWarning, "")
+				return []*clusterv1.Machine{m}
+			}(),
+			machineAge:       50 * day,
+			wantRequeueAfter: waitForInstanceReplacementDuration,
+		},
+		{
+			name:     "Other machine being provisioned",
+			template: newTemplate("720h"),
+			others: func() []*clusterv1.Machine {
+				m := newMachine("provisioning", "ms", time.Minute)
+				m.Status.NodeRef = nil
+				return []*clusterv1.Machine{m}
+			}(),
+			machineAge:       50 * day,
+			wantRequeueAfter: waitForInstanceReplacementDuration,
+		},
+		{
+			name:       "Invalid maximum age",
+			template:   newTemplate("30d"),
+			machineAge: 50 * day,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+			machine := newMachine("machine", "ms", tt.machineAge)
+			objects := []client.Object{machine}
+			if tt.template != nil {
+				objects = append(objects, tt.template)
+			}
+			for _, m := range tt.others {
+				objects = append(objects, m)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			requeueAfter, err := reconcileMaxInstanceAge(context.TODO(), c, logr.Discard(), machine, openStackMachine.DeepCopy(), now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(requeueAfter).To(Equal(tt.wantRequeueAfter))
+
+			got := &clusterv1.Machine{}
+			g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(machine), got)).To(Succeed())
+			g.Expect(conditions.IsFalse(got, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(tt.wantMarked))
+		})
+	}
+}
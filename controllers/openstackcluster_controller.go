@@ -19,7 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +33,7 @@ import (
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,14 +49,21 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/dns"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/preflight"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/budget"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 )
 
 const (
 	BastionInstanceHashAnnotation = "infrastructure.cluster.x-k8s.io/bastion-hash"
+
+	waitForEndpointHandoverDuration = 15 * time.Second
 )
 
 // OpenStackClusterReconciler reconciles a OpenStackCluster object.
@@ -60,11 +71,17 @@ type OpenStackClusterReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// CleanupVerification is how the deletion of clusters is verified.
+	CleanupVerification CleanupVerification
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
 
 func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -97,6 +114,13 @@ func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return reconcile.Result{}, nil
 	}
 
+	// Make sure the spec is not read from an object stored in an older API version before mutating cloud state.
+	if openStackCluster.DeletionTimestamp.IsZero() {
+		if rewritten, err := ensureStorageVersion(ctx, log, r.Client, openStackCluster); err != nil || rewritten {
+			return reconcile.Result{Requeue: rewritten}, err
+		}
+	}
+
 	patchHelper, err := patch.NewHelper(openStackCluster, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -104,35 +128,162 @@ func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// Always patch the openStackCluster when exiting this function so we can persist any OpenStackCluster changes.
 	defer func() {
-		if err := patchHelper.Patch(ctx, openStackCluster); err != nil {
+		if err := patchCluster(ctx, patchHelper, openStackCluster); err != nil {
 			if reterr == nil {
 				reterr = errors.Wrapf(err, "error patching OpenStackCluster %s/%s", openStackCluster.Namespace, openStackCluster.Name)
 			}
 		}
 	}()
 
+	// Deletions are never delayed, so that the OpenStack resources are released.
+	if openStackCluster.DeletionTimestamp.IsZero() {
+		if exceeded, result := apiBudgetExceeded(log, cluster, openStackCluster); exceeded {
+			return result, nil
+		}
+	}
+
 	osProviderClient, clientOpts, projectID, err := provider.NewClientFromCluster(ctx, r.Client, openStackCluster)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	trackAPIRequests(osProviderClient, cluster)
+	trackAPIDeprecations(osProviderClient, log)
+	logAPIRequests(osProviderClient, log, openStackCluster)
+	recordAPIRequests(osProviderClient, openStackCluster)
 
 	scope := &scope.Scope{
 		ProviderClient:     osProviderClient,
 		ProviderClientOpts: clientOpts,
 		ProjectID:          projectID,
 		Logger:             log,
+		ReconcileContext:   ctx,
+	}
+
+	if isPlanMode(openStackCluster) {
+		return reconcile.Result{}, reconcileClusterPlan(scope, cluster, openStackCluster, time.Now())
 	}
+	clearPlan(openStackCluster, &openStackCluster.Status.Plan)
 
 	// Handle deleted clusters
 	if !openStackCluster.DeletionTimestamp.IsZero() {
-		return reconcileDelete(ctx, scope, patchHelper, cluster, openStackCluster)
+		result, err := reconcileDelete(ctx, r.Client, scope, patchHelper, cluster, openStackCluster, r.CleanupVerification)
+		return requeueOnConflict(log, result, err)
 	}
 
 	// Handle non-deleted clusters
-	return reconcileNormal(ctx, scope, patchHelper, cluster, openStackCluster)
+	if err := r.reconcileInventory(ctx, cluster, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+	result, err := reconcileNormal(ctx, r.Client, scope, patchHelper, cluster, openStackCluster)
+	markAPIDeprecations(osProviderClient, openStackCluster)
+	return requeueOnConflict(log, result, err)
+}
+
+// reconcileInventory exports the inventory of the OpenStack resources of the cluster as metrics.
+func (r *OpenStackClusterReconciler) reconcileInventory(ctx context.Context, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	machineList := &infrav1.OpenStackMachineList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(cluster.Namespace), client.MatchingLabels(labels)); err != nil {
+		return errors.Wrap(err, "failed to list OpenStackMachines")
+	}
+
+	metrics.SetClusterInventory(cluster.Namespace, cluster.Name, clusterInventory(openStackCluster, machineList.Items))
+	return nil
+}
+
+// clusterInventory counts the instances, volumes and floating IPs of the machines and the
+// bastion of the cluster, and the floating IP of the API server load balancer or VIP.
+func clusterInventory(openStackCluster *infrav1.OpenStackCluster, machines []infrav1.OpenStackMachine) metrics.ClusterInventory {
+	inventory := metrics.ClusterInventory{
+		Instances:       map[string]int{},
+		VolumeGigabytes: map[string]int{},
+	}
+
+	addInstance := func(flavor string, rootVolume *infrav1.RootVolume, additionalBlockDevices []infrav1.AdditionalBlockDevice) {
+		inventory.Instances[flavor]++
+		if hasRootVolume(rootVolume) {
+			inventory.VolumeGigabytes[rootVolume.VolumeType] += rootVolume.Size
+		}
+		for _, device := range additionalBlockDevices {
+			inventory.VolumeGigabytes[device.VolumeType] += device.Size
+		}
+	}
+
+	for i := range machines {
+		machine := &machines[i]
+		if machine.Spec.InstanceID == nil {
+			continue
+		}
+		flavor := machine.Spec.Flavor
+		if flavor == "" {
+			flavor = machine.Spec.FlavorID
+		}
+		addInstance(flavor, machine.Spec.RootVolume, machineBlockDevices(&machine.Spec))
+		for _, address := range machine.Status.Addresses {
+			if address.Type == corev1.NodeExternalIP {
+				inventory.FloatingIPs++
+			}
+		}
+	}
+
+	if bastion := openStackCluster.Status.Bastion; bastion != nil && bastion.ID != "" {
+		var additionalBlockDevices []infrav1.AdditionalBlockDevice
+		if openStackCluster.Spec.Bastion != nil {
+			additionalBlockDevices = machineBlockDevices(&openStackCluster.Spec.Bastion.Instance)
+		}
+		addInstance(bastion.Flavor, bastion.RootVolume, additionalBlockDevices)
+		if bastion.FloatingIP != "" {
+			inventory.FloatingIPs++
+		}
+	}
+
+	if network := openStackCluster.Status.Network; network != nil && network.APIServerLoadBalancer != nil && network.APIServerLoadBalancer.IP != "" {
+		inventory.FloatingIPs++
+	}
+	if network := openStackCluster.Status.Network; network != nil && network.APIServerVIP != nil && network.APIServerVIP.FloatingIP != "" {
+		inventory.FloatingIPs++
+	}
+	inventory.FloatingIPs += len(openStackCluster.Status.AdditionalFloatingIPs)
+
+	return inventory
+}
+
+func hasRootVolume(rootVolume *infrav1.RootVolume) bool {
+	return rootVolume != nil && rootVolume.Size > 0
+}
+
+// rootVolumeWithAvailabilityZone returns the root volume with the root volume availability zone
+// of the cluster if it does not set its own availability zone.
+func rootVolumeWithAvailabilityZone(openStackCluster *infrav1.OpenStackCluster, rootVolume *infrav1.RootVolume) *infrav1.RootVolume {
+	if rootVolume == nil || rootVolume.AvailabilityZone != "" || openStackCluster.Spec.RootVolumeAvailabilityZone == "" {
+		return rootVolume
+	}
+	withAvailabilityZone := *rootVolume
+	withAvailabilityZone.AvailabilityZone = openStackCluster.Spec.RootVolumeAvailabilityZone
+	return &withAvailabilityZone
+}
+
+// machineBlockDevices returns the additional block devices of a machine, followed by the
+// volumes of its control plane storage.
+func machineBlockDevices(spec *infrav1.OpenStackMachineSpec) []infrav1.AdditionalBlockDevice {
+	if spec.ControlPlaneStorage == nil || spec.ControlPlaneStorage.Etcd == nil {
+		return spec.AdditionalBlockDevices
+	}
+
+	etcd := spec.ControlPlaneStorage.Etcd
+	devices := make([]infrav1.AdditionalBlockDevice, 0, len(spec.AdditionalBlockDevices)+1)
+	devices = append(devices, spec.AdditionalBlockDevices...)
+	return append(devices, infrav1.AdditionalBlockDevice{
+		Name:             infrav1.EtcdBlockDeviceName,
+		Size:             etcd.Size,
+		VolumeType:       etcd.VolumeType,
+		QoSSpecs:         etcd.QoSSpecs,
+		AvailabilityZone: etcd.AvailabilityZone,
+		Tag:              infrav1.EtcdBlockDeviceName,
+	})
 }
 
-func reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+func reconcileDelete(ctx context.Context, c client.Client, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, cleanupVerification CleanupVerification) (ctrl.Result, error) {
 	scope.Logger.Info("Reconciling Cluster delete")
 
 	if err := deleteBastion(scope, cluster, openStackCluster); err != nil {
@@ -147,40 +298,94 @@ func reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch
 	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
 
 	if err = networkingService.DeletePorts(openStackCluster); err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete ports: %v", err))
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete ports"))
 		return reconcile.Result{}, errors.Wrap(err, "failed to delete ports")
 	}
 
-	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+	if err = networkingService.DeleteAdditionalFloatingIPs(openStackCluster, clusterName); err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete additional floating IPs"))
+		return reconcile.Result{}, errors.Wrap(err, "failed to delete additional floating IPs")
+	}
+
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		if err = networkingService.DeleteAPIServerVIP(openStackCluster, clusterName); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete API server VIP"))
+			return reconcile.Result{}, errors.Wrap(err, "failed to delete API server VIP")
+		}
+	}
+
+	if openStackCluster.Spec.ControlPlaneEndpointDNS != nil || openStackCluster.Status.BastionDNSRecord != nil {
+		dnsService, err := dns.NewService(scope)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if openStackCluster.Spec.ControlPlaneEndpointDNS != nil {
+			if err = dnsService.DeleteControlPlaneEndpointRecord(openStackCluster); err != nil {
+				handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete DNS record"))
+				return reconcile.Result{}, errors.Wrap(err, "failed to delete DNS record")
+			}
+		}
+
+		if err = dnsService.DeleteBastionRecord(openStackCluster); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete DNS record of bastion"))
+			return reconcile.Result{}, errors.Wrap(err, "failed to delete DNS record of bastion")
+		}
+	}
+
+	// The load balancer may still exist if the cluster was switched away from it
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled || hasAPIServerLoadBalancerStatus(openStackCluster) {
 		loadBalancerService, err := loadbalancer.NewService(scope)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
 		if err = loadBalancerService.DeleteLoadBalancer(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete load balancer: %v", err))
-			return reconcile.Result{}, errors.Errorf("failed to delete load balancer: %v", err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete load balancer"))
+			return reconcile.Result{}, errors.Wrap(err, "failed to delete load balancer")
 		}
 	}
 
-	if err = networkingService.DeleteSecurityGroups(openStackCluster, clusterName); err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete security groups: %v", err))
-		return reconcile.Result{}, errors.Errorf("failed to delete security groups: %v", err)
+	// The security groups of an externally managed network are never deleted, even if they are
+	// named like the managed ones
+	if !openStackCluster.Spec.ExternallyManagedNetwork {
+		if err = networkingService.DeleteSecurityGroups(openStackCluster, clusterName); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete security groups"))
+			return reconcile.Result{}, errors.Wrap(err, "failed to delete security groups")
+		}
 	}
 
 	// if NodeCIDR was not set, no network was created.
 	if openStackCluster.Spec.NodeCIDR != "" {
 		if err = networkingService.DeleteRouter(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete router: %v", err))
-			return ctrl.Result{}, errors.Errorf("failed to delete router: %v", err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete router"))
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete router")
 		}
 
 		if err = networkingService.DeleteNetwork(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete network: %v", err))
-			return ctrl.Result{}, errors.Errorf("failed to delete network: %v", err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete network"))
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete network")
 		}
 	}
 
+	if err = releaseFloatingIPPoolClaims(ctx, c, openStackCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	clean, err := verifyCleanup(scope.Logger, cleanupVerification, openStackCluster, "OpenStackCluster", func() (map[string][]string, error) {
+		return listClusterResources(scope, networkingService, openStackCluster, clusterName)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !clean {
+		return ctrl.Result{RequeueAfter: waitForCleanupToVerify}, nil
+	}
+
+	metrics.DeleteClusterInventory(cluster.Namespace, cluster.Name)
+	metrics.DeleteClusterReconcileDurations(cluster.Namespace, cluster.Name)
+	budget.Forget(cluster.Namespace, cluster.Name)
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(openStackCluster, infrav1.ClusterFinalizer)
 	scope.Logger.Info("Reconciled Cluster delete successfully")
@@ -190,6 +395,29 @@ func reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch
 	return ctrl.Result{}, nil
 }
 
+// listClusterResources returns the OpenStack resources created for the cluster which still exist, by resource type.
+func listClusterResources(scope *scope.Scope, networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster, clusterName string) (map[string][]string, error) {
+	resources, err := networkingService.ListClusterResources(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled || hasAPIServerLoadBalancerStatus(openStackCluster) {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := loadBalancerService.ListClusterLoadBalancers(openStackCluster, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			resources[loadbalancer.LoadBalancerResource] = ids
+		}
+	}
+	return resources, nil
+}
+
 func contains(arr []string, target string) bool {
 	for _, a := range arr {
 		if a == target {
@@ -223,26 +451,37 @@ func deleteBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackClus
 		addresses := instanceNS.Addresses()
 
 		for _, address := range addresses {
-			if address.Type == corev1.NodeExternalIP {
-				if err = networkingService.DeleteFloatingIP(openStackCluster, address.Address); err != nil {
-					handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete floating IP: %v", err))
-					return errors.Errorf("failed to delete floating IP: %v", err)
+			if address.Type != corev1.NodeExternalIP {
+				continue
+			}
+			// Addresses claimed from a floating IP pool are kept for the next bastion
+			if networking.IsClaimedFloatingIP(openStackCluster, address.Address) {
+				if err = networkingService.DisassociateFloatingIP(openStackCluster, address.Address); err != nil {
+					handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to disassociate floating IP"))
+					return errors.Wrap(err, "failed to disassociate floating IP")
 				}
+				continue
+			}
+			if err = networkingService.DeleteFloatingIP(openStackCluster, address.Address); err != nil {
+				handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete floating IP"))
+				return errors.Wrap(err, "failed to delete floating IP")
 			}
 		}
 
-		rootVolume := openStackCluster.Spec.Bastion.Instance.RootVolume
-		if err = computeService.DeleteInstance(openStackCluster, instanceStatus, instanceName, rootVolume); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete bastion: %v", err))
-			return errors.Errorf("failed to delete bastion: %v", err)
+		bastionSpec := &openStackCluster.Spec.Bastion.Instance
+		if err = computeService.DeleteInstance(openStackCluster, instanceStatus, instanceName, bastionSpec.RootVolume, machineBlockDevices(bastionSpec), bastionSpec.Ports); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete bastion"))
+			return errors.Wrap(err, "failed to delete bastion")
 		}
 	}
 
 	openStackCluster.Status.Bastion = nil
 
-	if err = networkingService.DeleteBastionSecurityGroup(openStackCluster, fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)); err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete bastion security group: %v", err))
-		return errors.Errorf("failed to delete bastion security group: %v", err)
+	if !openStackCluster.Spec.ExternallyManagedNetwork {
+		if err = networkingService.DeleteBastionSecurityGroup(openStackCluster, fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete bastion security group"))
+			return errors.Wrap(err, "failed to delete bastion security group")
+		}
 	}
 	openStackCluster.Status.BastionSecurityGroup = nil
 
@@ -251,7 +490,7 @@ func deleteBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackClus
 	return nil
 }
 
-func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+func reconcileNormal(ctx context.Context, c client.Client, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
 	scope.Logger.Info("Reconciling Cluster")
 
 	// If the OpenStackCluster doesn't have our finalizer, add it.
@@ -261,6 +500,14 @@ func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch
 		return reconcile.Result{}, err
 	}
 
+	if err := reconcilePreflight(scope, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := reconcileFloatingIPPoolClaims(ctx, c, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	computeService, err := compute.NewService(scope)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -271,35 +518,140 @@ func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch
 		return reconcile.Result{}, err
 	}
 
-	if err = reconcileBastion(scope, cluster, openStackCluster); err != nil {
+	done := metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceBastion)
+	err = reconcileBastion(scope, cluster, openStackCluster)
+	done()
+	if err != nil {
+		markOpenStackError(openStackCluster, infrav1.BastionReadyCondition, infrav1.BastionReconcileFailedReason, err)
 		return reconcile.Result{}, err
 	}
+	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
+		conditions.MarkTrue(openStackCluster, infrav1.BastionReadyCondition)
+	} else {
+		conditions.Delete(openStackCluster, infrav1.BastionReadyCondition)
+	}
 
-	availabilityZones, err := computeService.GetAvailabilityZones()
-	if err != nil {
-		return ctrl.Result{}, err
+	if err = reconcileBastionDNSRecord(scope, cluster, openStackCluster); err != nil {
+		return reconcile.Result{}, err
 	}
 
-	// Create a new list in case any AZs have been removed from OpenStack
-	openStackCluster.Status.FailureDomains = make(clusterv1.FailureDomains)
-	for _, az := range availabilityZones {
-		// By default, the AZ is used or not used for control plane nodes depending on the flag
-		found := !openStackCluster.Spec.ControlPlaneOmitAvailabilityZone
-		// If explicit AZs for control plane nodes are given, they override the value
-		if len(openStackCluster.Spec.ControlPlaneAvailabilityZones) > 0 {
-			found = contains(openStackCluster.Spec.ControlPlaneAvailabilityZones, az.ZoneName)
-		}
-		// Add the AZ object to the failure domains for the cluster
-		openStackCluster.Status.FailureDomains[az.ZoneName] = clusterv1.FailureDomainSpec{
-			ControlPlane: found,
-		}
+	if err = reconcileFailureDomains(computeService, openStackCluster); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile failure domains")
+	}
+
+	openStackCluster.Status.ExternalAddresses = clusterExternalAddresses(openStackCluster)
+
+	if err = reconcileBlueprint(ctx, c, cluster, openStackCluster); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	openStackCluster.Status.Ready = true
 	openStackCluster.Status.FailureMessage = nil
 	openStackCluster.Status.FailureReason = nil
+
+	nextGarbageCollection, err := reconcileGarbageCollection(ctx, c, scope, cluster, openStackCluster, time.Now())
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to collect orphaned resources")
+	}
+
+	if isAPIServerFloatingIPMovePending(openStackCluster) {
+		scope.Logger.Info("Waiting for the load balancer to take over the control plane endpoint")
+		return reconcile.Result{RequeueAfter: waitForEndpointHandoverDuration}, nil
+	}
+
 	scope.Logger.Info("Reconciled Cluster create successfully")
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: nextGarbageCollection}, nil
+}
+
+// clusterExternalAddresses collects the addresses of the cluster which are reachable from outside of the
+// cluster network from the status of the cluster.
+func clusterExternalAddresses(openStackCluster *infrav1.OpenStackCluster) []infrav1.ClusterAddress {
+	var addresses []infrav1.ClusterAddress
+	add := func(addressType infrav1.ClusterAddressType, address string) {
+		if address != "" {
+			addresses = append(addresses, infrav1.ClusterAddress{Type: addressType, Address: address})
+		}
+	}
+
+	network := openStackCluster.Status.Network
+	switch {
+	case hasAPIServerLoadBalancerStatus(openStackCluster):
+		add(infrav1.APIServerVIPAddress, network.APIServerLoadBalancer.InternalIP)
+		add(infrav1.APIServerFloatingIPAddress, network.APIServerLoadBalancer.IP)
+	case openStackCluster.Spec.APIServerLoadBalancer.Enabled:
+		// The load balancer has not been created yet
+	case network != nil && network.APIServerVIP != nil:
+		add(infrav1.APIServerVIPAddress, network.APIServerVIP.IP)
+		add(infrav1.APIServerFloatingIPAddress, network.APIServerVIP.FloatingIP)
+	case openStackCluster.Spec.ManagedAPIServerVIP:
+		// The VIP port has not been created yet
+	case !openStackCluster.Spec.DisableAPIServerFloatingIP:
+		// The floating IP is only recorded as the control plane endpoint, or the address of its DNS record
+		address := openStackCluster.Status.APIServerAddress
+		if address == "" && net.ParseIP(openStackCluster.Spec.ControlPlaneEndpoint.Host) != nil {
+			address = openStackCluster.Spec.ControlPlaneEndpoint.Host
+		}
+		add(infrav1.APIServerFloatingIPAddress, address)
+	default:
+		add(infrav1.APIServerVIPAddress, openStackCluster.Spec.APIServerFixedIP)
+	}
+
+	if network != nil && network.AdditionalPortsLoadBalancer != nil {
+		add(infrav1.AdditionalPortsVIPAddress, network.AdditionalPortsLoadBalancer.InternalIP)
+		add(infrav1.AdditionalPortsFloatingIPAddress, network.AdditionalPortsLoadBalancer.IP)
+	}
+
+	if bastion := openStackCluster.Status.Bastion; bastion != nil {
+		add(infrav1.BastionFloatingIPAddress, bastion.FloatingIP)
+	}
+
+	if network != nil && network.Router != nil {
+		for _, ip := range network.Router.IPs {
+			add(infrav1.RouterExternalIPAddress, ip)
+		}
+	}
+
+	for _, fip := range openStackCluster.Status.AdditionalFloatingIPs {
+		add(infrav1.AdditionalFloatingIPAddress, fip.FloatingIP)
+	}
+
+	return addresses
+}
+
+// hasAPIServerLoadBalancerStatus returns true if an API server load balancer was created for the cluster.
+func hasAPIServerLoadBalancerStatus(openStackCluster *infrav1.OpenStackCluster) bool {
+	return openStackCluster.Status.Network != nil && openStackCluster.Status.Network.APIServerLoadBalancer != nil
+}
+
+// isAPIServerFloatingIPMovePending returns true if the floating IP of a cluster which was switched
+// to a load balancer is still held by a control plane machine.
+func isAPIServerFloatingIPMovePending(openStackCluster *infrav1.OpenStackCluster) bool {
+	lb := openStackCluster.Spec.APIServerLoadBalancer
+	if !lb.Enabled || lb.Shared != nil || lb.ExistingLoadBalancer != nil || openStackCluster.Spec.DisableAPIServerFloatingIP {
+		return false
+	}
+	return hasAPIServerLoadBalancerStatus(openStackCluster) && openStackCluster.Status.Network.APIServerLoadBalancer.IP == ""
+}
+
+// reconcilePreflight runs the preflight checks if they were requested with the
+// preflight annotation, and records the report in the status.
+func reconcilePreflight(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster) error {
+	if _, ok := openStackCluster.ObjectMeta.Annotations[infrav1.PreflightAnnotation]; !ok {
+		return nil
+	}
+
+	preflightService, err := preflight.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	scope.Logger.Info("Running preflight checks")
+	report := preflightService.Run(openStackCluster)
+	openStackCluster.Status.Preflight = report
+	delete(openStackCluster.ObjectMeta.Annotations, infrav1.PreflightAnnotation)
+	scope.Logger.Info("Ran preflight checks", "passed", report.Passed)
+
+	return nil
 }
 
 func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
@@ -313,6 +665,7 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 	if err != nil {
 		return err
 	}
+	computeService.ObserveReconcileDurations(cluster.Namespace, cluster.Name)
 
 	instanceSpec := bastionToInstanceSpec(openStackCluster, cluster.Name)
 	bastionHash, err := compute.HashInstanceSpec(instanceSpec)
@@ -320,12 +673,22 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 		return errors.Wrap(err, "failed computing bastion hash from instance spec")
 	}
 
+	// The server metadata and tags of the cluster are updated in place, so they are not part of the hash
+	instanceSpec.Metadata = serverMetadata(openStackCluster, instanceSpec.Metadata)
+	instanceSpec.Tags = instanceTags(openStackCluster, openStackCluster.Spec.Bastion.Instance.Tags)
+
 	instanceStatus, err := computeService.GetInstanceStatusByName(openStackCluster, fmt.Sprintf("%s-bastion", cluster.Name))
 	if err != nil {
 		return err
 	}
 	if instanceStatus != nil {
 		if !bastionHashHasChanged(bastionHash, openStackCluster.ObjectMeta.Annotations) {
+			if err := computeService.ReconcileServerMetadata(openStackCluster, instanceStatus, instanceSpec.Metadata); err != nil {
+				return errors.Wrap(err, "failed to update metadata of bastion")
+			}
+			if err := computeService.ReconcileInstanceTags(openStackCluster, instanceSpec, instanceStatus); err != nil {
+				return errors.Wrap(err, "failed to update tags of bastion")
+			}
 			bastion, err := instanceStatus.APIInstance(openStackCluster)
 			if err != nil {
 				return err
@@ -345,7 +708,18 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 
 	instanceStatus, err = computeService.CreateInstance(openStackCluster, openStackCluster, instanceSpec, cluster.Name)
 	if err != nil {
-		return errors.Errorf("failed to reconcile bastion: %v", err)
+		return errors.Wrap(err, "failed to reconcile bastion")
+	}
+
+	// The bastion on a provider network is reached at its fixed IP
+	if openStackCluster.Spec.ProviderNetwork != nil {
+		bastion, err := instanceStatus.APIInstance(openStackCluster)
+		if err != nil {
+			return err
+		}
+		openStackCluster.Status.Bastion = bastion
+		annotations.AddAnnotations(openStackCluster, map[string]string{BastionInstanceHashAnnotation: bastionHash})
+		return nil
 	}
 
 	networkingService, err := networking.NewService(scope)
@@ -353,10 +727,14 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 		return err
 	}
 	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
-	fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, openStackCluster.Spec.Bastion.Instance.FloatingIP)
+	floatingIP := openStackCluster.Spec.Bastion.Instance.FloatingIP
+	if floatingIP == "" {
+		floatingIP = networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseBastion)
+	}
+	fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIP)
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to get or create floating IP for bastion: %v", err))
-		return errors.Errorf("failed to get or create floating IP for bastion: %v", err)
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to get or create floating IP for bastion"))
+		return errors.Wrap(err, "failed to get or create floating IP for bastion")
 	}
 	port, err := computeService.GetManagementPort(openStackCluster, instanceStatus)
 	if err != nil {
@@ -366,8 +744,8 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 	}
 	err = networkingService.AssociateFloatingIP(openStackCluster, fp, port.ID)
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to associate floating IP with bastion: %v", err))
-		return errors.Errorf("failed to associate floating IP with bastion: %v", err)
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to associate floating IP with bastion"))
+		return errors.Wrap(err, "failed to associate floating IP with bastion")
 	}
 
 	bastion, err := instanceStatus.APIInstance(openStackCluster)
@@ -380,16 +758,67 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 	return nil
 }
 
+// reconcileBastionDNSRecord points the DNS record of the bastion at its floating IP. The record
+// is deleted if the bastion is disabled or its record is removed from the spec. While the bastion
+// is re-created, the record keeps pointing at its previous address.
+func reconcileBastionDNSRecord(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	bastion := openStackCluster.Spec.Bastion
+	hasRecord := bastion != nil && bastion.Enabled && bastion.DNS != nil
+	if !hasRecord && openStackCluster.Status.BastionDNSRecord == nil {
+		return nil
+	}
+	if hasRecord && (openStackCluster.Status.Bastion == nil || openStackCluster.Status.Bastion.FloatingIP == "") {
+		return nil
+	}
+
+	dnsService, err := dns.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	if !hasRecord {
+		if err := dnsService.DeleteBastionRecord(openStackCluster); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to delete DNS record of bastion"))
+			return errors.Wrap(err, "failed to delete DNS record of bastion")
+		}
+		return nil
+	}
+
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	if err := dnsService.ReconcileBastionRecord(openStackCluster, clusterName, openStackCluster.Status.Bastion.FloatingIP); err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile DNS record of bastion"))
+		return errors.Wrap(err, "failed to reconcile DNS record of bastion")
+	}
+	return nil
+}
+
 func bastionToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, clusterName string) *compute.InstanceSpec {
 	name := fmt.Sprintf("%s-bastion", clusterName)
 	instanceSpec := &compute.InstanceSpec{
-		Name:          name,
-		Flavor:        openStackCluster.Spec.Bastion.Instance.Flavor,
-		SSHKeyName:    openStackCluster.Spec.Bastion.Instance.SSHKeyName,
-		Image:         openStackCluster.Spec.Bastion.Instance.Image,
-		ImageUUID:     openStackCluster.Spec.Bastion.Instance.ImageUUID,
-		FailureDomain: openStackCluster.Spec.Bastion.AvailabilityZone,
-		RootVolume:    openStackCluster.Spec.Bastion.Instance.RootVolume,
+		Name:                   name,
+		Flavor:                 openStackCluster.Spec.Bastion.Instance.Flavor,
+		FlavorID:               openStackCluster.Spec.Bastion.Instance.FlavorID,
+		SSHKeyName:             openStackCluster.Spec.Bastion.Instance.SSHKeyName,
+		Image:                  openStackCluster.Spec.Bastion.Instance.Image,
+		ImageUUID:              openStackCluster.Spec.Bastion.Instance.ImageUUID,
+		ImageChecksum:          openStackCluster.Spec.Bastion.Instance.ImageChecksum,
+		ImageFilter:            openStackCluster.Spec.Bastion.Instance.ImageFilter,
+		UserData:               openStackCluster.Spec.Bastion.UserData,
+		Metadata:               openStackCluster.Spec.Bastion.Instance.ServerMetadata,
+		ConfigDrive:            openStackCluster.Spec.Bastion.Instance.ConfigDrive != nil && *openStackCluster.Spec.Bastion.Instance.ConfigDrive,
+		FailureDomain:          openStackCluster.Spec.Bastion.AvailabilityZone,
+		RootVolume:             rootVolumeWithAvailabilityZone(openStackCluster, openStackCluster.Spec.Bastion.Instance.RootVolume),
+		AdditionalBlockDevices: machineBlockDevices(&openStackCluster.Spec.Bastion.Instance),
+		Trunk:                  openStackCluster.Spec.Bastion.Instance.Trunk,
+		ManagedSubnet:          openStackCluster.Spec.Bastion.Instance.ManagedSubnet,
+		SchedulerHints:         openStackCluster.Spec.Bastion.Instance.SchedulerHintAdditionalProperties,
+		Traits:                 openStackCluster.Spec.Bastion.Instance.Traits,
+		Reservation:            openStackCluster.Spec.Bastion.Instance.Reservation,
+		ClusterName:            instanceClusterName(openStackCluster),
+	}
+
+	if instanceSpec.FailureDomain == "" {
+		instanceSpec.FailureDomain = openStackCluster.Spec.ComputeAvailabilityZone
 	}
 
 	instanceSpec.SecurityGroups = openStackCluster.Spec.Bastion.Instance.SecurityGroups
@@ -426,10 +855,174 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 
 	scope.Logger.Info("Reconciling network components")
 
-	err = networkingService.ReconcileExternalNetwork(openStackCluster)
+	done := metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceNetwork)
+	err = reconcileNetwork(scope, networkingService, openStackCluster, clusterName)
+	done()
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile external network: %v", err))
-		return errors.Errorf("failed to reconcile external network: %v", err)
+		return err
+	}
+
+	done = metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceSecurityGroup)
+	err = networkingService.ReconcileSecurityGroups(openStackCluster, clusterName)
+	done()
+	if err != nil {
+		markOpenStackError(openStackCluster, infrav1.SecurityGroupsReadyCondition, infrav1.SecurityGroupsReconcileFailedReason, err)
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile security groups"))
+		return errors.Wrap(err, "failed to reconcile security groups")
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		conditions.MarkTrue(openStackCluster, infrav1.SecurityGroupsReadyCondition)
+	} else {
+		conditions.Delete(openStackCluster, infrav1.SecurityGroupsReadyCondition)
+	}
+
+	if err := networkingService.ReconcileAdditionalFloatingIPs(openStackCluster, clusterName); err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile additional floating IPs"))
+		return errors.Wrap(err, "failed to reconcile additional floating IPs")
+	}
+
+	apiServerPort := getAPIServerPort(openStackCluster)
+
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			return err
+		}
+
+		done := metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceLoadBalancer)
+		err = loadBalancerService.ReconcileLoadBalancer(openStackCluster, clusterName, apiServerPort)
+		done()
+		if err != nil {
+			markOpenStackError(openStackCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile load balancer"))
+			return errors.Wrap(err, "failed to reconcile load balancer")
+		}
+		conditions.MarkTrue(openStackCluster, infrav1.LoadBalancerReadyCondition)
+	} else if hasAPIServerLoadBalancerStatus(openStackCluster) {
+		// The cluster was switched to a fixed endpoint. The control plane machines take over
+		// the endpoint address once the load balancer is gone.
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			return err
+		}
+
+		done := metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceLoadBalancer)
+		err = loadBalancerService.RemoveLoadBalancer(openStackCluster, clusterName)
+		done()
+		if err != nil {
+			markOpenStackError(openStackCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to remove load balancer"))
+			return errors.Wrap(err, "failed to remove load balancer")
+		}
+		openStackCluster.Status.Network.APIServerLoadBalancer = nil
+	}
+	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		conditions.Delete(openStackCluster, infrav1.LoadBalancerReadyCondition)
+	}
+
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		if err := networkingService.ReconcileAPIServerVIP(openStackCluster, clusterName); err != nil {
+			markOpenStackError(openStackCluster, infrav1.APIServerVIPReadyCondition, infrav1.APIServerVIPReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile API server VIP"))
+			return errors.Wrap(err, "failed to reconcile API server VIP")
+		}
+		conditions.MarkTrue(openStackCluster, infrav1.APIServerVIPReadyCondition)
+	} else {
+		conditions.Delete(openStackCluster, infrav1.APIServerVIPReadyCondition)
+	}
+
+	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+		var host string
+		// If there is a load balancer use the floating IP for it if set, falling back to the internal IP
+		switch {
+		case openStackCluster.Spec.APIServerLoadBalancer.Enabled && openStackCluster.Spec.APIServerLoadBalancer.Shared != nil:
+			// Requests are routed to the cluster by their host name on the shared listener
+			host = openStackCluster.Spec.APIServerLoadBalancer.Shared.Hostname
+			apiServerPort = openStackCluster.Spec.APIServerLoadBalancer.Shared.Port
+		case openStackCluster.Spec.APIServerLoadBalancer.Enabled:
+			if openStackCluster.Status.Network.APIServerLoadBalancer.IP != "" {
+				host = openStackCluster.Status.Network.APIServerLoadBalancer.IP
+			} else {
+				host = openStackCluster.Status.Network.APIServerLoadBalancer.InternalIP
+			}
+		case openStackCluster.Spec.ManagedAPIServerVIP:
+			// The floating IP of the VIP port if there is one, falling back to the VIP itself
+			vip := openStackCluster.Status.Network.APIServerVIP
+			if vip.FloatingIP != "" {
+				host = vip.FloatingIP
+			} else {
+				host = vip.IP
+			}
+		case !openStackCluster.Spec.DisableAPIServerFloatingIP:
+			// If floating IPs are not disabled, get one to use as the VIP for the control plane
+			floatingIP := openStackCluster.Spec.APIServerFloatingIP
+			if floatingIP == "" {
+				floatingIP = networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseAPIServer)
+			}
+			fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIP)
+			if err != nil {
+				handleUpdateOSCError(openStackCluster, errors.Wrap(err, "Floating IP cannot be got or created"))
+				return errors.Wrap(err, "Floating IP cannot be got or created")
+			}
+			host = fp.FloatingIP
+		case openStackCluster.Spec.APIServerFixedIP != "":
+			// If a fixed IP was specified, assume that the user is providing the extra configuration
+			// to use that IP as the VIP for the API server, e.g. using keepalived or kube-vip
+			host = openStackCluster.Spec.APIServerFixedIP
+		default:
+			// Without a load balancer, a floating IP or a fixed IP, the VIP must be managed by
+			// setting managedAPIServerVIP
+			return errors.New("unable to determine VIP for API server")
+		}
+
+		// The endpoint is the name of the DNS record pointing at the address
+		if openStackCluster.Spec.ControlPlaneEndpointDNS != nil {
+			openStackCluster.Status.APIServerAddress = host
+			host = strings.TrimSuffix(openStackCluster.Spec.ControlPlaneEndpointDNS.RecordName, ".")
+		}
+
+		// Set APIEndpoints so the Cluster API Cluster Controller can pull them
+		openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+			Host: host,
+			Port: int32(apiServerPort),
+		}
+	}
+
+	if openStackCluster.Spec.ControlPlaneEndpointDNS != nil && openStackCluster.Status.APIServerAddress != "" {
+		dnsService, err := dns.NewService(scope)
+		if err != nil {
+			return err
+		}
+
+		err = dnsService.ReconcileControlPlaneEndpointRecord(openStackCluster, clusterName, openStackCluster.Status.APIServerAddress)
+		if err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile DNS record"))
+			return errors.Wrap(err, "failed to reconcile DNS record")
+		}
+	}
+
+	return nil
+}
+
+// getAPIServerPort returns the port that we will use for the API server.
+func getAPIServerPort(openStackCluster *infrav1.OpenStackCluster) int {
+	switch {
+	case openStackCluster.Spec.ControlPlaneEndpoint.IsValid():
+		return int(openStackCluster.Spec.ControlPlaneEndpoint.Port)
+	case openStackCluster.Spec.APIServerPort != 0:
+		return openStackCluster.Spec.APIServerPort
+	default:
+		return 6443
+	}
+}
+
+// reconcileNetwork reconciles the external network and the network, subnet and router of the cluster, or looks up
+// the network and subnet of the cluster if they are not managed.
+func reconcileNetwork(scope *scope.Scope, networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	if err := networkingService.ReconcileExternalNetwork(openStackCluster); err != nil {
+		markOpenStackError(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, err)
+		handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile external network"))
+		return errors.Wrap(err, "failed to reconcile external network")
 	}
 
 	if openStackCluster.Spec.NodeCIDR == "" {
@@ -438,14 +1031,17 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 		netOpts := openStackCluster.Spec.Network.ToListOpt()
 		networkList, err := networkingService.GetNetworksByFilter(&netOpts)
 		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find network: %v", err))
-			return errors.Errorf("failed to find network: %v", err)
+			markOpenStackError(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to find network"))
+			return errors.Wrap(err, "failed to find network")
 		}
 		if len(networkList) == 0 {
+			conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.ResourceNotFoundReason, clusterv1.ConditionSeverityError, "No network matches the filter")
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find any network: %v", err))
 			return errors.Errorf("failed to find any network: %v", err)
 		}
 		if len(networkList) > 1 {
+			conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "%d networks match the filter", len(networkList))
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find only one network (result: %v): %v", networkList, err))
 			return errors.Errorf("failed to find only one network (result: %v): %v", networkList, err)
 		}
@@ -455,15 +1051,21 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 		openStackCluster.Status.Network.ID = networkList[0].ID
 		openStackCluster.Status.Network.Name = networkList[0].Name
 		openStackCluster.Status.Network.Tags = networkList[0].Tags
+		conditions.MarkTrue(openStackCluster, infrav1.NetworkReadyCondition)
 
 		subnetOpts := openStackCluster.Spec.Subnet.ToListOpt()
-		subnetOpts.NetworkID = networkList[0].ID
-		subnetList, err := networkingService.GetSubnetsByFilter(&subnetOpts)
+		subnetList, err := networkingService.GetNetworkSubnetsByFilter(networkList[0].ID, &subnetOpts)
+		if err != nil {
+			markOpenStackError(openStackCluster, infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+		} else if len(subnetList) == 0 {
+			conditions.MarkFalse(openStackCluster, infrav1.SubnetsReadyCondition, infrav1.ResourceNotFoundReason, clusterv1.ConditionSeverityError, "No subnet matches the filter")
+		}
 		if err != nil || len(subnetList) == 0 {
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find subnet: %v", err))
 			return errors.Errorf("failed to find subnet: %v", err)
 		}
 		if len(subnetList) > 1 {
+			conditions.MarkFalse(openStackCluster, infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, clusterv1.ConditionSeverityError, "%d subnets match the filter", len(subnetList))
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find only one subnet (result: %v): %v", subnetList, err))
 			return errors.Errorf("failed to find only one subnet (result: %v): %v", subnetList, err)
 		}
@@ -473,90 +1075,47 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 			CIDR: subnetList[0].CIDR,
 			Tags: subnetList[0].Tags,
 		}
+		conditions.MarkTrue(openStackCluster, infrav1.SubnetsReadyCondition)
+
+		// The router of a network which is not managed is not managed either, but the router of an
+		// externally managed network is looked up to fail early if the subnet is not routed
+		if openStackCluster.Spec.ExternallyManagedNetwork && openStackCluster.Spec.Router != nil {
+			if err := networkingService.LookupRouter(openStackCluster); err != nil {
+				markOpenStackError(openStackCluster, infrav1.RouterReadyCondition, infrav1.RouterReconcileFailedReason, err)
+				handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to find router"))
+				return errors.Wrap(err, "failed to find router")
+			}
+			conditions.MarkTrue(openStackCluster, infrav1.RouterReadyCondition)
+		} else {
+			conditions.Delete(openStackCluster, infrav1.RouterReadyCondition)
+		}
 	} else {
 		err := networkingService.ReconcileNetwork(openStackCluster, clusterName)
 		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile network: %v", err))
-			return errors.Errorf("failed to reconcile network: %v", err)
+			markOpenStackError(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile network"))
+			return errors.Wrap(err, "failed to reconcile network")
 		}
+		conditions.MarkTrue(openStackCluster, infrav1.NetworkReadyCondition)
 		err = networkingService.ReconcileSubnet(openStackCluster, clusterName)
 		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile subnets: %v", err))
-			return errors.Errorf("failed to reconcile subnets: %v", err)
-		}
-		err = networkingService.ReconcileRouter(openStackCluster, clusterName)
-		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile router: %v", err))
-			return errors.Errorf("failed to reconcile router: %v", err)
+			markOpenStackError(openStackCluster, infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile subnets"))
+			return errors.Wrap(err, "failed to reconcile subnets")
 		}
-	}
-
-	err = networkingService.ReconcileSecurityGroups(openStackCluster, clusterName)
-	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile security groups: %v", err))
-		return errors.Errorf("failed to reconcile security groups: %v", err)
-	}
-
-	// Calculate the port that we will use for the API server
-	var apiServerPort int
-	switch {
-	case openStackCluster.Spec.ControlPlaneEndpoint.IsValid():
-		apiServerPort = int(openStackCluster.Spec.ControlPlaneEndpoint.Port)
-	case openStackCluster.Spec.APIServerPort != 0:
-		apiServerPort = openStackCluster.Spec.APIServerPort
-	default:
-		apiServerPort = 6443
-	}
-
-	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
-		loadBalancerService, err := loadbalancer.NewService(scope)
-		if err != nil {
-			return err
+		conditions.MarkTrue(openStackCluster, infrav1.SubnetsReadyCondition)
+		// The nodes on a provider network are routed by the physical network
+		if openStackCluster.Spec.ProviderNetwork != nil {
+			conditions.Delete(openStackCluster, infrav1.RouterReadyCondition)
+			return nil
 		}
-
-		err = loadBalancerService.ReconcileLoadBalancer(openStackCluster, clusterName, apiServerPort)
+		err = networkingService.ReconcileRouter(openStackCluster, clusterName)
 		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile load balancer: %v", err))
-			return errors.Errorf("failed to reconcile load balancer: %v", err)
-		}
-	}
-
-	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
-		var host string
-		// If there is a load balancer use the floating IP for it if set, falling back to the internal IP
-		switch {
-		case openStackCluster.Spec.APIServerLoadBalancer.Enabled:
-			if openStackCluster.Status.Network.APIServerLoadBalancer.IP != "" {
-				host = openStackCluster.Status.Network.APIServerLoadBalancer.IP
-			} else {
-				host = openStackCluster.Status.Network.APIServerLoadBalancer.InternalIP
-			}
-		case !openStackCluster.Spec.DisableAPIServerFloatingIP:
-			// If floating IPs are not disabled, get one to use as the VIP for the control plane
-			fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, openStackCluster.Spec.APIServerFloatingIP)
-			if err != nil {
-				handleUpdateOSCError(openStackCluster, errors.Errorf("Floating IP cannot be got or created: %v", err))
-				return errors.Errorf("Floating IP cannot be got or created: %v", err)
-			}
-			host = fp.FloatingIP
-		case openStackCluster.Spec.APIServerFixedIP != "":
-			// If a fixed IP was specified, assume that the user is providing the extra configuration
-			// to use that IP as the VIP for the API server, e.g. using keepalived or kube-vip
-			host = openStackCluster.Spec.APIServerFixedIP
-		default:
-			// For now, we do not provide a managed VIP without either a load balancer or a floating IP
-			// In the future, we could manage a VIP port on the cluster network and set allowedAddressPairs
-			// accordingly when creating control plane machines
-			// However this would require us to deploy software on the control plane hosts to manage the
-			// VIP (e.g. keepalived/kube-vip)
-			return errors.New("unable to determine VIP for API server")
-		}
-
-		// Set APIEndpoints so the Cluster API Cluster Controller can pull them
-		openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-			Host: host,
-			Port: int32(apiServerPort),
+			markOpenStackError(openStackCluster, infrav1.RouterReadyCondition, infrav1.RouterReconcileFailedReason, err)
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to reconcile router"))
+			return errors.Wrap(err, "failed to reconcile router")
 		}
+		conditions.MarkTrue(openStackCluster, infrav1.RouterReadyCondition)
 	}
 
 	return nil
@@ -611,7 +1170,36 @@ func (r *OpenStackClusterReconciler) SetupWithManager(ctx context.Context, mgr c
 		Complete(r)
 }
 
+// clusterConditions are the conditions summarised by the Ready condition of an OpenStackCluster, in the order in
+// which the resources are reconciled, so that the summary reports the first resource which blocks provisioning.
+var clusterConditions = []clusterv1.ConditionType{
+	infrav1.NetworkReadyCondition,
+	infrav1.SubnetsReadyCondition,
+	infrav1.RouterReadyCondition,
+	infrav1.SecurityGroupsReadyCondition,
+	infrav1.LoadBalancerReadyCondition,
+	infrav1.APIServerVIPReadyCondition,
+	infrav1.BastionReadyCondition,
+}
+
+func patchCluster(ctx context.Context, patchHelper *patch.Helper, openStackCluster *infrav1.OpenStackCluster, options ...patch.Option) error {
+	// Always update the readyCondition by summarizing the state of other conditions.
+	conditions.SetSummary(openStackCluster,
+		conditions.WithConditions(clusterConditions...),
+	)
+
+	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	options = append(options,
+		patch.WithOwnedConditions{Conditions: append([]clusterv1.ConditionType{clusterv1.ReadyCondition, infrav1.OpenStackResourcesUpToDateCondition}, clusterConditions...)},
+	)
+	return patchHelper.Patch(ctx, openStackCluster, options...)
+}
+
 func handleUpdateOSCError(openstackCluster *infrav1.OpenStackCluster, message error) {
+	// Conflicts are transient, the reconciliation is retried once OpenStack has completed the pending operation
+	if capoerrors.IsConflict(message) {
+		return
+	}
 	err := capierrors.UpdateClusterError
 	openstackCluster.Status.FailureReason = &err
 	openstackCluster.Status.FailureMessage = pointer.StringPtr(message.Error())
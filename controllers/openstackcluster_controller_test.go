@@ -19,11 +19,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"testing"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -34,6 +36,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
@@ -222,3 +226,289 @@ func createRequestFromOSCluster(openStackCluster *infrav1.OpenStackCluster) reco
 		},
 	}
 }
+
+func Test_clusterInventory(t *testing.T) {
+	g := NewWithT(t)
+
+	instanceID := "aaaaaaaa-bbbb-cccc-dddd-111111111111"
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			Bastion: &infrav1.Instance{
+				ID:         "aaaaaaaa-bbbb-cccc-dddd-222222222222",
+				Flavor:     "m1.small",
+				FloatingIP: "172.24.4.10",
+			},
+			Network: &infrav1.Network{
+				APIServerLoadBalancer: &infrav1.LoadBalancer{IP: "172.24.4.11"},
+			},
+		},
+	}
+	machines := []infrav1.OpenStackMachine{
+		{
+			Spec: infrav1.OpenStackMachineSpec{
+				InstanceID: &instanceID,
+				Flavor:     "m1.large",
+				RootVolume: &infrav1.RootVolume{Size: 50, VolumeType: "ssd"},
+			},
+			Status: infrav1.OpenStackMachineStatus{
+				Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.6.0.10"},
+					{Type: corev1.NodeExternalIP, Address: "172.24.4.12"},
+				},
+			},
+		},
+		{
+			Spec: infrav1.OpenStackMachineSpec{
+				InstanceID: &instanceID,
+				Flavor:     "m1.large",
+				RootVolume: &infrav1.RootVolume{Size: 20},
+			},
+		},
+		{
+			// The instance has not been created yet.
+			Spec: infrav1.OpenStackMachineSpec{
+				Flavor: "m1.large",
+			},
+		},
+	}
+
+	g.Expect(clusterInventory(openStackCluster, machines)).To(Equal(metrics.ClusterInventory{
+		Instances:       map[string]int{"m1.large": 2, "m1.small": 1},
+		VolumeGigabytes: map[string]int{"ssd": 50, "": 20},
+		FloatingIPs:     3,
+	}))
+}
+
+func Test_clusterExternalAddresses(t *testing.T) {
+	tests := []struct {
+		name             string
+		openStackCluster *infrav1.OpenStackCluster
+		want             []infrav1.ClusterAddress
+	}{
+		{
+			name: "Load balancer, bastion and router",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{
+						APIServerLoadBalancer:       &infrav1.LoadBalancer{InternalIP: "10.6.0.5", IP: "172.24.4.11"},
+						AdditionalPortsLoadBalancer: &infrav1.LoadBalancer{InternalIP: "10.6.0.6"},
+						Router:                      &infrav1.Router{IPs: []string{"172.24.4.2", "2001:db8::2"}},
+					},
+					Bastion: &infrav1.Instance{FloatingIP: "172.24.4.10"},
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerVIPAddress, Address: "10.6.0.5"},
+				{Type: infrav1.APIServerFloatingIPAddress, Address: "172.24.4.11"},
+				{Type: infrav1.AdditionalPortsVIPAddress, Address: "10.6.0.6"},
+				{Type: infrav1.BastionFloatingIPAddress, Address: "172.24.4.10"},
+				{Type: infrav1.RouterExternalIPAddress, Address: "172.24.4.2"},
+				{Type: infrav1.RouterExternalIPAddress, Address: "2001:db8::2"},
+			},
+		},
+		{
+			name: "Load balancer not created yet",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+					ControlPlaneEndpoint:  clusterv1.APIEndpoint{Host: "172.24.4.11", Port: 6443},
+				},
+			},
+		},
+		{
+			name: "Floating IP",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "172.24.4.11", Port: 6443},
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerFloatingIPAddress, Address: "172.24.4.11"},
+			},
+		},
+		{
+			name: "Floating IP behind a DNS record",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "api.example.com", Port: 6443},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					APIServerAddress: "172.24.4.11",
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerFloatingIPAddress, Address: "172.24.4.11"},
+			},
+		},
+		{
+			name: "Fixed IP",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					DisableAPIServerFloatingIP: true,
+					APIServerFixedIP:           "10.6.0.5",
+					ControlPlaneEndpoint:       clusterv1.APIEndpoint{Host: "10.6.0.5", Port: 6443},
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerVIPAddress, Address: "10.6.0.5"},
+			},
+		},
+		{
+			name: "Managed VIP",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedAPIServerVIP:  true,
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "172.24.4.11", Port: 6443},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{
+						APIServerVIP: &infrav1.APIServerVIP{PortID: "vip-port-id", IP: "10.6.0.5", FloatingIP: "172.24.4.11"},
+					},
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerVIPAddress, Address: "10.6.0.5"},
+				{Type: infrav1.APIServerFloatingIPAddress, Address: "172.24.4.11"},
+			},
+		},
+		{
+			name: "Managed VIP not created yet",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedAPIServerVIP: true,
+				},
+			},
+		},
+		{
+			name: "Additional floating IPs",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "172.24.4.11", Port: 6443},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					AdditionalFloatingIPs: []infrav1.AdditionalFloatingIPStatus{
+						{Name: "ingress", ID: "ingress-id", FloatingIP: "172.24.4.20"},
+						{Name: "vpn", ID: "vpn-id", FloatingIP: "172.24.4.21"},
+					},
+				},
+			},
+			want: []infrav1.ClusterAddress{
+				{Type: infrav1.APIServerFloatingIPAddress, Address: "172.24.4.11"},
+				{Type: infrav1.AdditionalFloatingIPAddress, Address: "172.24.4.20"},
+				{Type: infrav1.AdditionalFloatingIPAddress, Address: "172.24.4.21"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterExternalAddresses(tt.openStackCluster)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_clusterFailureDomains(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         infrav1.OpenStackClusterSpec
+		computeZones []string
+		volumeZones  []string
+		want         clusterv1.FailureDomains
+	}{
+		{
+			name:         "No block storage service",
+			computeZones: []string{"az1", "az2"},
+			want: clusterv1.FailureDomains{
+				"az1": {ControlPlane: true},
+				"az2": {ControlPlane: true},
+			},
+		},
+		{
+			name:         "Root volumes follow the failure domain",
+			computeZones: []string{"az1", "az2", "az3"},
+			volumeZones:  []string{"az1", "az3", "az4"},
+			want: clusterv1.FailureDomains{
+				"az1": {ControlPlane: true},
+				"az3": {ControlPlane: true},
+			},
+		},
+		{
+			name:         "Root volumes in a fixed availability zone",
+			spec:         infrav1.OpenStackClusterSpec{RootVolumeAvailabilityZone: "nova"},
+			computeZones: []string{"az1", "az2"},
+			volumeZones:  []string{"nova"},
+			want: clusterv1.FailureDomains{
+				"az1": {ControlPlane: true},
+				"az2": {ControlPlane: true},
+			},
+		},
+		{
+			name:         "Control plane availability zones",
+			spec:         infrav1.OpenStackClusterSpec{ControlPlaneAvailabilityZones: []string{"az2"}},
+			computeZones: []string{"az1", "az2"},
+			volumeZones:  []string{"az1", "az2"},
+			want: clusterv1.FailureDomains{
+				"az1": {ControlPlane: false},
+				"az2": {ControlPlane: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := &infrav1.OpenStackCluster{Spec: tt.spec}
+			g.Expect(clusterFailureDomains(openStackCluster, tt.computeZones, tt.volumeZones)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_bastionToInstanceSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	configDrive := true
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			ManagedSecurityGroups: true,
+			Bastion: &infrav1.Bastion{
+				Enabled: true,
+				Instance: infrav1.OpenStackMachineSpec{
+					Flavor:         "m1.small",
+					Image:          "ubuntu",
+					ServerMetadata: map[string]string{"role": "bastion"},
+					ConfigDrive:    &configDrive,
+					RootVolume:     &infrav1.RootVolume{Size: 20},
+					SecurityGroups: []infrav1.SecurityGroupParam{{Name: "ssh"}},
+					Ports: []infrav1.PortOpts{
+						{FixedIPs: []infrav1.FixedIP{{IPAddress: "10.6.0.5"}}},
+					},
+				},
+				AvailabilityZone: "az1",
+				UserData:         "#cloud-config\npackages: [tmux]\n",
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			BastionSecurityGroup: &infrav1.SecurityGroup{ID: "bastion-sg-id"},
+		},
+	}
+
+	instanceSpec := bastionToInstanceSpec(openStackCluster, "test")
+	g.Expect(instanceSpec.Name).To(Equal("test-bastion"))
+	g.Expect(instanceSpec.UserData).To(Equal("#cloud-config\npackages: [tmux]\n"))
+	g.Expect(instanceSpec.Metadata).To(Equal(map[string]string{"role": "bastion"}))
+	g.Expect(instanceSpec.ConfigDrive).To(BeTrue())
+	g.Expect(instanceSpec.FailureDomain).To(Equal("az1"))
+	g.Expect(instanceSpec.RootVolume).To(Equal(&infrav1.RootVolume{Size: 20}))
+	g.Expect(instanceSpec.Ports).To(Equal(openStackCluster.Spec.Bastion.Instance.Ports))
+	g.Expect(instanceSpec.SecurityGroups).To(Equal([]infrav1.SecurityGroupParam{{Name: "ssh"}, {UUID: "bastion-sg-id"}}))
+
+	// Changing the user data changes the hash, so the bastion is rebuilt.
+	hash, err := compute.HashInstanceSpec(instanceSpec)
+	g.Expect(err).NotTo(HaveOccurred())
+	openStackCluster.Spec.Bastion.UserData = "#cloud-config\npackages: [tmux, htop]\n"
+	newHash, err := compute.HashInstanceSpec(bastionToInstanceSpec(openStackCluster, "test"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newHash).NotTo(Equal(hash))
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/image"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// OpenStackImageReconciler reconciles a OpenStackImage object.
+type OpenStackImageReconciler struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	WatchFilterValue string
+}
+
+const waitForImageToReconcile = 30 * time.Second
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackimages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackimages/status,verbs=get;update;patch
+
+func (r *OpenStackImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackImage instance.
+	openStackImage := &infrav1.OpenStackImage{}
+	err := r.Client.Get(ctx, req.NamespacedName, openStackImage)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackImage", openStackImage.Name)
+
+	if annotations.HasPaused(openStackImage) {
+		log.Info("OpenStackImage is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(openStackImage, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the openStackImage when exiting this function so we can persist any OpenStackImage changes.
+	defer func() {
+		conditions.SetSummary(openStackImage, conditions.WithConditions(infrav1.ImageReadyCondition))
+		if err := patchHelper.Patch(ctx, openStackImage, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.ImageReadyCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromImage(ctx, r.Client, openStackImage)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	recordAPIRequests(osProviderClient, openStackImage)
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+		ReconcileContext:   ctx,
+	}
+
+	imageService, err := image.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Handle deleted images
+	if !openStackImage.DeletionTimestamp.IsZero() {
+		return reconcileImageDelete(scope, imageService, openStackImage)
+	}
+
+	// Handle non-deleted images
+	return reconcileImageNormal(ctx, scope, patchHelper, imageService, openStackImage)
+}
+
+func (r *OpenStackImageReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.OpenStackImage{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
+
+func reconcileImageDelete(scope *scope.Scope, imageService *image.Service, openStackImage *infrav1.OpenStackImage) (ctrl.Result, error) {
+	scope.Logger.Info("Reconciling Image delete")
+
+	if err := deleteImagePrewarmServers(scope, openStackImage); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error deleting prewarm servers")
+	}
+
+	if err := imageService.DeleteImage(openStackImage); err != nil {
+		conditions.MarkFalse(openStackImage, infrav1.ImageReadyCondition, infrav1.ImageDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting image failed: %v", err)
+		return ctrl.Result{}, errors.Wrapf(err, "error deleting image %s", openStackImage.Status.ImageID)
+	}
+
+	controllerutil.RemoveFinalizer(openStackImage, infrav1.ImageFinalizer)
+	scope.Logger.Info("Reconciled Image delete successfully")
+	return ctrl.Result{}, nil
+}
+
+func reconcileImageNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, imageService *image.Service, openStackImage *infrav1.OpenStackImage) (ctrl.Result, error) {
+	// If the OpenStackImage doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(openStackImage, infrav1.ImageFinalizer)
+	// Register the finalizer immediately to avoid orphaning the image on delete
+	if err := patchHelper.Patch(ctx, openStackImage); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope.Logger.Info("Reconciling Image")
+
+	if err := imageService.ReconcileImage(openStackImage); err != nil {
+		conditions.MarkFalse(openStackImage, infrav1.ImageReadyCondition, infrav1.ImageCreateFailedReason, clusterv1.ConditionSeverityError, "Reconciling image failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile image")
+	}
+
+	if !openStackImage.Status.Ready {
+		scope.Logger.Info("Waiting for image to become active", "imageID", openStackImage.Status.ImageID, "status", openStackImage.Status.Status)
+		conditions.MarkFalse(openStackImage, infrav1.ImageReadyCondition, infrav1.ImageImportingReason, clusterv1.ConditionSeverityInfo, "Image is %s", openStackImage.Status.Status)
+		return ctrl.Result{RequeueAfter: waitForImageToReconcile}, nil
+	}
+
+	conditions.MarkTrue(openStackImage, infrav1.ImageReadyCondition)
+
+	prewarmed, err := reconcileImagePrewarm(scope, openStackImage)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to prewarm image")
+	}
+	if !prewarmed {
+		scope.Logger.Info("Waiting for image to be cached", "availabilityZones", openStackImage.Spec.Prewarm.AvailabilityZones)
+		return ctrl.Result{RequeueAfter: waitForImageToReconcile}, nil
+	}
+
+	scope.Logger.Info("Reconciled Image successfully")
+	return ctrl.Result{}, nil
+}
+
+// reconcileImagePrewarm caches the image in the availability zones of spec.prewarm which it has not been
+// cached in yet. It returns true once the image is cached in all of them.
+func reconcileImagePrewarm(scope *scope.Scope, openStackImage *infrav1.OpenStackImage) (bool, error) {
+	prewarm := openStackImage.Spec.Prewarm
+	if prewarm == nil {
+		return true, nil
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return false, err
+	}
+
+	prewarmed := sets.NewString(openStackImage.Status.PrewarmedAvailabilityZones...)
+	for _, availabilityZone := range prewarm.AvailabilityZones {
+		if prewarmed.Has(availabilityZone) {
+			continue
+		}
+		name := compute.PrewarmServerName(openStackImage.Name, availabilityZone)
+		done, err := computeService.PrewarmImage(openStackImage, name, openStackImage.Status.ImageID, prewarm.Flavor, availabilityZone)
+		if err != nil {
+			return false, err
+		}
+		if done {
+			openStackImage.Status.PrewarmedAvailabilityZones = append(openStackImage.Status.PrewarmedAvailabilityZones, availabilityZone)
+			prewarmed.Insert(availabilityZone)
+		}
+	}
+	return prewarmed.HasAll(prewarm.AvailabilityZones...), nil
+}
+
+// deleteImagePrewarmServers deletes the throwaway servers of the availability zones the image is still
+// being cached in.
+func deleteImagePrewarmServers(scope *scope.Scope, openStackImage *infrav1.OpenStackImage) error {
+	prewarm := openStackImage.Spec.Prewarm
+	if prewarm == nil {
+		return nil
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	prewarmed := sets.NewString(openStackImage.Status.PrewarmedAvailabilityZones...)
+	for _, availabilityZone := range prewarm.AvailabilityZones {
+		if prewarmed.Has(availabilityZone) {
+			continue
+		}
+		if err := computeService.DeletePrewarmServer(openStackImage, compute.PrewarmServerName(openStackImage.Name, availabilityZone)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referencedImageID returns the ID of the image of the OpenStackImage referenced by the machine, or an empty string
+// if the OpenStackImage does not exist or is not ready yet.
+func referencedImageID(ctx context.Context, c client.Client, openStackMachine *infrav1.OpenStackMachine) (string, error) {
+	openStackImage := &infrav1.OpenStackImage{}
+	key := client.ObjectKey{Namespace: openStackMachine.Namespace, Name: openStackMachine.Spec.ImageRef.Name}
+	if err := c.Get(ctx, key, openStackImage); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get OpenStackImage %s", key.Name)
+	}
+	if !openStackImage.Status.Ready || !openStackImage.DeletionTimestamp.IsZero() {
+		return "", nil
+	}
+	return openStackImage.Status.ImageID, nil
+}
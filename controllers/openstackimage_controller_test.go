@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_referencedImageID(t *testing.T) {
+	const imageID = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test"},
+		Spec: infrav1.OpenStackMachineSpec{
+			ImageRef: &corev1.LocalObjectReference{Name: "ubuntu"},
+		},
+	}
+	newImage := func(ready bool) *infrav1.OpenStackImage {
+		return &infrav1.OpenStackImage{
+			ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: "test"},
+			Status:     infrav1.OpenStackImageStatus{Ready: ready, ImageID: imageID},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		image *infrav1.OpenStackImage
+		want  string
+	}{
+		{
+			name: "OpenStackImage does not exist",
+		},
+		{
+			name:  "OpenStackImage is not ready",
+			image: newImage(false),
+		},
+		{
+			name:  "OpenStackImage is ready",
+			image: newImage(true),
+			want:  imageID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+			var objects []client.Object
+			if tt.image != nil {
+				objects = append(objects, tt.image)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			got, err := referencedImageID(context.TODO(), c, openStackMachine)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -21,14 +21,18 @@ import (
 	"encoding/base64"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -54,7 +58,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
 // OpenStackMachineReconciler reconciles a OpenStackMachine object.
@@ -62,17 +69,31 @@ type OpenStackMachineReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// InstanceActionsAuditInterval is the minimum interval between checks of the instance actions of a machine.
+	// The audit is disabled if it is 0.
+	InstanceActionsAuditInterval time.Duration
+	// CleanupVerification is how the deletion of machines is verified.
+	CleanupVerification CleanupVerification
 }
 
 const (
 	waitForClusterInfrastructureReadyDuration = 15 * time.Second
 	waitForInstanceBecomeActiveToReconcile    = 60 * time.Second
+	waitForKeyPairToReconcile                 = 30 * time.Second
+	waitForIPAddressToReconcile               = 10 * time.Second
+	waitForServerPasswordToReconcile          = 30 * time.Second
+	waitForSnapshotToReconcile                = 30 * time.Second
+
+	defaultSSHPublicKeySecretKey = "ssh-publickey"
 )
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines/status,verbs=patch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
 func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -116,6 +137,13 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, nil
 	}
 
+	// Make sure the spec is not read from an object stored in an older API version before mutating cloud state.
+	if openStackMachine.DeletionTimestamp.IsZero() {
+		if rewritten, err := ensureStorageVersion(ctx, log, r.Client, openStackMachine); err != nil || rewritten {
+			return ctrl.Result{Requeue: rewritten}, err
+		}
+	}
+
 	infraCluster, err := r.getInfraCluster(ctx, cluster, openStackMachine)
 	if err != nil {
 		return ctrl.Result{}, errors.New("error getting infra provider cluster")
@@ -140,35 +168,86 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}()
 
-	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, r.Client, openStackMachine)
+	// Deletions are never delayed, so that the OpenStack resources are released.
+	if openStackMachine.DeletionTimestamp.IsZero() {
+		if exceeded, result := apiBudgetExceeded(log, cluster, openStackMachine); exceeded {
+			return result, nil
+		}
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, r.Client, infraCluster, openStackMachine)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	trackAPIRequests(osProviderClient, cluster)
+	trackAPIDeprecations(osProviderClient, log)
+	logAPIRequests(osProviderClient, log, infraCluster)
+	recordAPIRequests(osProviderClient, openStackMachine)
 
 	scope := &scope.Scope{
 		ProviderClient:     osProviderClient,
 		ProviderClientOpts: clientOpts,
 		ProjectID:          projectID,
 		Logger:             log,
+		ReconcileContext:   ctx,
+	}
+
+	if isPlanMode(infraCluster) {
+		return reconcile.Result{}, reconcileMachinePlan(scope, infraCluster, machine, openStackMachine, time.Now())
+	}
+	clearPlan(openStackMachine, &openStackMachine.Status.Plan)
+
+	clusterScope, err := r.clusterScope(ctx, scope, cluster, infraCluster, openStackMachine)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// Handle deleted machines
 	if !openStackMachine.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, scope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+		result, err := r.reconcileDelete(ctx, scope, clusterScope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+		return requeueOnConflict(log, result, err)
 	}
 
 	// Handle non-deleted clusters
-	return r.reconcileNormal(ctx, scope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+	result, err := r.reconcileNormal(ctx, scope, clusterScope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+	return requeueOnConflict(log, result, err)
+}
+
+// clusterScope returns the scope used for the resources owned by the cluster, such as the API server load balancer
+// and floating IP. It is the machine scope unless the machine uses its own identity or region.
+func (r *OpenStackMachineReconciler) clusterScope(ctx context.Context, machineScope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (*scope.Scope, error) {
+	if provider.MachineHasClusterIdentity(openStackCluster, openStackMachine) && openStackMachine.Spec.Region == "" {
+		return machineScope, nil
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromCluster(ctx, r.Client, openStackCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating client with the cluster identity")
+	}
+	trackAPIRequests(osProviderClient, cluster)
+	trackAPIDeprecations(osProviderClient, machineScope.Logger)
+	logAPIRequests(osProviderClient, machineScope.Logger, openStackCluster)
+	recordAPIRequests(osProviderClient, openStackMachine)
+
+	return &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             machineScope.Logger,
+		ReconcileContext:   ctx,
+	}, nil
 }
 
 func patchMachine(ctx context.Context, patchHelper *patch.Helper, openStackMachine *infrav1.OpenStackMachine, machine *clusterv1.Machine, options ...patch.Option) error {
 	// Always update the readyCondition by summarizing the state of other conditions.
 	applicableConditions := []clusterv1.ConditionType{
+		infrav1.PortsReadyCondition,
+		infrav1.ServerCreatedCondition,
 		infrav1.InstanceReadyCondition,
 	}
 
 	if util.IsControlPlaneMachine(machine) {
-		applicableConditions = append(applicableConditions, infrav1.APIServerIngressReadyCondition)
+		applicableConditions = append(applicableConditions, infrav1.FloatingIPReadyCondition, infrav1.APIServerIngressReadyCondition)
 	}
 
 	conditions.SetSummary(openStackMachine,
@@ -181,8 +260,15 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, openStackMachi
 	options = append(options,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
+			infrav1.PortsReadyCondition,
+			infrav1.ServerCreatedCondition,
 			infrav1.InstanceReadyCondition,
+			infrav1.FloatingIPReadyCondition,
 			infrav1.APIServerIngressReadyCondition,
+			infrav1.KeyPairReadyCondition,
+			infrav1.ServerGroupReadyCondition,
+			infrav1.ServerCreateOptsUpToDateCondition,
+			infrav1.OpenStackResourcesUpToDateCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, openStackMachine, options...)
@@ -225,7 +311,7 @@ func (r *OpenStackMachineReconciler) SetupWithManager(ctx context.Context, mgr c
 		Complete(r)
 }
 
-func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (ctrl.Result, error) {
+func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope *scope.Scope, clusterScope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (ctrl.Result, error) {
 	scope.Logger.Info("Reconciling Machine delete")
 
 	clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
@@ -235,13 +321,13 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 		return ctrl.Result{}, err
 	}
 
-	networkingService, err := networking.NewService(scope)
+	networkingService, err := networking.NewService(clusterScope)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
-		loadBalancerService, err := loadbalancer.NewService(scope)
+		loadBalancerService, err := loadbalancer.NewService(clusterScope)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -257,7 +343,23 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled && util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" {
+
+	if snapshot := snapshotBeforeDelete(openStackCluster, openStackMachine); snapshot != nil && instanceStatus != nil {
+		data := names.NewTemplateData(cluster.Namespace, clusterName)
+		data.MachineName = openStackMachine.Name
+		imageID, done, err := computeService.SnapshotInstance(openStackMachine, instanceStatus, snapshot, data, openStackMachine.Status.SnapshotImageID, time.Now())
+		openStackMachine.Status.SnapshotImageID = imageID
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error creating snapshot of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		if !done {
+			scope.Logger.Info("Waiting for snapshot before deleting instance", "imageID", imageID)
+			return ctrl.Result{RequeueAfter: waitForSnapshotToReconcile}, nil
+		}
+	}
+
+	// The floating IP of a managed VIP is associated with the VIP port, not with the machines
+	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled && !openStackCluster.Spec.ManagedAPIServerVIP && util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" {
 		if instanceStatus != nil {
 			instanceNS, err := instanceStatus.NetworkStatus()
 			if err != nil {
@@ -267,7 +369,8 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 
 			addresses := instanceNS.Addresses()
 			for _, address := range addresses {
-				if address.Type == corev1.NodeExternalIP {
+				// Addresses claimed from a floating IP pool are disassociated when the instance is deleted
+				if address.Type == corev1.NodeExternalIP && !networking.IsClaimedFloatingIP(openStackCluster, address.Address) {
 					if err = networkingService.DeleteFloatingIP(openStackMachine, address.Address); err != nil {
 						handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("error deleting Openstack floating IP: %v", err))
 						conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Deleting floating IP failed: %v", err)
@@ -278,12 +381,41 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 		}
 	}
 
-	if err := computeService.DeleteInstance(openStackMachine, instanceStatus, openStackMachine.Name, openStackMachine.Spec.RootVolume); err != nil {
+	if err := computeService.DeleteInstance(openStackMachine, instanceStatus, openStackMachine.Name, openStackMachine.Spec.RootVolume, machineBlockDevices(&openStackMachine.Spec), openStackMachine.Spec.Ports); err != nil {
 		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("error deleting OpenStack instance %s with ID %s: %v", instanceStatus.Name(), instanceStatus.ID(), err))
 		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting instance failed: %v", err)
 		return ctrl.Result{}, nil
 	}
 
+	if err := deleteIPAddressClaims(ctx, r.Client, openStackMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if openStackMachine.Spec.ServerGroup != nil {
+		serverGroupName := compute.ServerGroupName(clusterName, serverGroupOwner(machine))
+		inUse, err := serverGroupInUse(ctx, r.Client, cluster, machine, openStackMachine)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error checking whether server group %s is in use", serverGroupName)
+		}
+		if !inUse {
+			if err := computeService.DeleteServerGroupIfUnused(openStackMachine, serverGroupName); err != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "error deleting server group %s", serverGroupName)
+			}
+		}
+	}
+
+	clean, err := verifyCleanup(scope.Logger, r.CleanupVerification, openStackMachine, "OpenStackMachine", func() (map[string][]string, error) {
+		return computeService.ListInstanceResources(clusterName, openStackMachine.Name, instanceTags(openStackCluster, openStackMachine.Spec.Tags), openStackMachine.Spec.RootVolume, openStackMachine.Spec.Ports)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !clean {
+		return ctrl.Result{RequeueAfter: waitForCleanupToVerify}, nil
+	}
+
+	metrics.DeleteServerGroupViolation(openStackMachine.Namespace, openStackMachine.Name)
+
 	controllerutil.RemoveFinalizer(openStackMachine, infrav1.MachineFinalizer)
 	scope.Logger.Info("Reconciled Machine delete successfully")
 	if err := patchHelper.Patch(ctx, openStackMachine); err != nil {
@@ -292,7 +424,7 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 	return ctrl.Result{}, nil
 }
 
-func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (_ ctrl.Result, reterr error) {
+func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, clusterScope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (_ ctrl.Result, reterr error) {
 	// If the OpenStackMachine is in an error state, return early.
 	if openStackMachine.Status.FailureReason != nil || openStackMachine.Status.FailureMessage != nil {
 		scope.Logger.Info("Not reconciling machine in failed state. See openStackMachine.status.failureReason, openStackMachine.status.failureMessage, or previously logged error for details")
@@ -318,7 +450,7 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
 	}
-	userData, err := r.getBootstrapData(ctx, machine, openStackMachine)
+	userData, bootstrapFormat, err := r.getBootstrapData(ctx, machine, openStackMachine)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -330,15 +462,71 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	computeService.ObserveReconcileDurations(cluster.Namespace, cluster.Name)
 
-	networkingService, err := networking.NewService(scope)
+	networkingService, err := networking.NewService(clusterScope)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	instanceStatus, err := r.getOrCreate(scope.Logger, cluster, openStackCluster, machine, openStackMachine, computeService, userData)
+	if err := r.reconcileKeyPair(ctx, scope.Logger, computeService, openStackMachine); err != nil {
+		if openStackMachine.Spec.InstanceID == nil {
+			// Don't let Nova reject the server create request with an unclear error
+			scope.Logger.Info("Keypair is not available, requeuing machine", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: waitForKeyPairToReconcile}, nil
+		}
+		scope.Logger.Info("Keypair is not available", "reason", err.Error())
+	}
+
+	ports := openStackMachine.Spec.Ports
+	if openStackMachine.Spec.InstanceID == nil {
+		var allocated bool
+		ports, allocated, err = reconcileIPAddressClaims(ctx, r.Client, cluster, openStackMachine)
+		if err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, "Claiming IP addresses failed: %v", err)
+			return ctrl.Result{}, err
+		}
+		if !allocated {
+			scope.Logger.Info("IP addresses of the ports are not allocated yet, requeuing machine")
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForIPAddressReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: waitForIPAddressToReconcile}, nil
+		}
+	}
+
+	// The image is resolved once, so that the instance is always recreated from the same image
+	if openStackMachine.Spec.InstanceID == nil && openStackMachine.Spec.ImageFilter != nil && openStackMachine.Status.ImageID == "" {
+		imageID, err := computeService.ResolveImageFilter(openStackMachine.Spec.ImageFilter)
+		if err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, "Resolving image filter failed: %v", err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to resolve image filter")
+		}
+		scope.Logger.Info("Resolved image filter", "imageID", imageID)
+		openStackMachine.Status.ImageID = imageID
+	}
+
+	if openStackMachine.Spec.InstanceID == nil && openStackMachine.Spec.ImageRef != nil && openStackMachine.Status.ImageID == "" {
+		imageID, err := referencedImageID(ctx, r.Client, openStackMachine)
+		if err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, "Getting image failed: %v", err)
+			return ctrl.Result{}, err
+		}
+		if imageID == "" {
+			scope.Logger.Info("OpenStackImage is not ready yet, requeuing machine", "openStackImage", openStackMachine.Spec.ImageRef.Name)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForImageReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: waitForImageToReconcile}, nil
+		}
+		scope.Logger.Info("Resolved image reference", "imageID", imageID)
+		openStackMachine.Status.ImageID = imageID
+	}
+
+	done := metrics.ReconcileTimer(cluster.Namespace, cluster.Name, metrics.ReconcileResourceServer)
+	instanceStatus, err := r.getOrCreate(scope.Logger, cluster, openStackCluster, machine, openStackMachine, computeService, userData, bootstrapFormat, ports)
+	done()
 	if err != nil {
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance cannot be created: %v", err))
+		// A conflict, e.g. a fixed IP which is still in use by a deleted port, is retried
+		if !capoerrors.IsConflict(err) {
+			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance cannot be created: %v", err))
+		}
 		// Conditions set in getOrCreate
 		return ctrl.Result{}, err
 	}
@@ -358,24 +546,75 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 	state := instanceStatus.State()
 	openStackMachine.Status.InstanceState = &state
 
+	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, userData)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instanceSpec.BootstrapFormat = bootstrapFormat
+	if err := reconcileServerCreateOpts(openStackMachine, instanceSpec); err != nil {
+		return ctrl.Result{}, err
+	}
+	if state != infrav1.InstanceStateDeleted {
+		reconcileInstanceActions(scope.Logger, computeService, openStackMachine, instanceStatus, r.InstanceActionsAuditInterval)
+	}
+
 	instanceNS, err := instanceStatus.NetworkStatus()
 	if err != nil {
 		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Unable to get network status for OpenStack instance %s with ID %s: %v", instanceStatus.Name(), instanceStatus.ID(), err))
 		return ctrl.Result{}, nil
 	}
 
-	addresses := instanceNS.Addresses()
+	addresses := instanceNS.Addresses(openStackMachine.Spec.AddressSortOrder...)
 	openStackMachine.Status.Addresses = addresses
 
+	evacuating, err := reconcileEvacuation(scope.Logger, computeService, machine, openStackMachine, instanceStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if evacuating {
+		openStackMachine.Status.Ready = false
+		return ctrl.Result{RequeueAfter: waitForInstanceBecomeActiveToReconcile}, nil
+	}
+
+	result := ctrl.Result{}
 	switch instanceStatus.State() {
 	case infrav1.InstanceStateActive:
 		scope.Logger.Info("Machine instance is ACTIVE", "instance-id", instanceStatus.ID())
 		conditions.MarkTrue(openStackMachine, infrav1.InstanceReadyCondition)
 		openStackMachine.Status.Ready = true
+		if err := computeService.ReconcileServerMetadata(openStackMachine, instanceStatus, machineServerMetadata(openStackCluster, machine, openStackMachine)); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error updating metadata of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		if err := computeService.ReconcileInstanceTags(openStackMachine, instanceSpec, instanceStatus); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error updating tags of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		if err := computeService.ReconcilePortAllowedAddressPairs(openStackMachine, openStackCluster, instanceSpec, instanceStatus); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error updating allowed address pairs of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		attachedBlockDevices, err := computeService.ReconcileBlockDevices(openStackMachine, instanceSpec, instanceStatus, openStackMachine.Status.AttachedBlockDevices, openStackMachine.Spec.DeleteRemovedBlockDevices)
+		openStackMachine.Status.AttachedBlockDevices = attachedBlockDevices
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error updating block devices of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		published, err := reconcileServerPassword(ctx, r.Client, computeService, cluster, openStackMachine, instanceStatus)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error publishing password of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		if !published {
+			// The password is posted by the image once it has booted
+			scope.Logger.Info("Server password is not available yet, requeuing machine", "instance-id", instanceStatus.ID())
+			result = ctrl.Result{RequeueAfter: waitForServerPasswordToReconcile}
+		}
+		reconcilePlacement(scope.Logger, computeService, openStackMachine, instanceStatus)
+		reconcileServerGroupMembership(scope.Logger, computeService, clusterName, machine, openStackMachine, instanceStatus)
 	case infrav1.InstanceStateError:
 		// Error is unexpected, thus we report error and never retry
 		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance state %q is unexpected", instanceStatus.State()))
-		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStateErrorReason, clusterv1.ConditionSeverityError, "")
+		var message string
+		if consoleLog := computeService.ReportConsoleLog(openStackMachine, instanceStatus.ID()); consoleLog != "" {
+			message = "Console log:\n" + consoleLog
+		}
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStateErrorReason, clusterv1.ConditionSeverityError, "%s", message)
 		return ctrl.Result{}, nil
 	case infrav1.InstanceStateDeleted:
 		// we should avoid further actions for DELETED VM
@@ -390,25 +629,47 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 		return ctrl.Result{RequeueAfter: waitForInstanceBecomeActiveToReconcile}, nil
 	}
 
+	requeueAfter, err := reconcileMaxInstanceAge(ctx, r.Client, scope.Logger, machine, openStackMachine, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if requeueAfter > 0 && (result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = requeueAfter
+	}
+
+	requeueAfter, err = reconcileRemediation(ctx, r.Client, scope.Logger, computeService, machine, openStackMachine, instanceStatus, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if requeueAfter > 0 && (result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = requeueAfter
+	}
+
 	if !util.IsControlPlaneMachine(machine) {
 		scope.Logger.Info("Not a Control plane machine, no floating ip reconcile needed, Reconciled Machine create successfully")
-		return ctrl.Result{}, nil
+		return result, nil
 	}
 
 	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
-		err = r.reconcileLoadBalancerMember(scope, openStackCluster, machine, openStackMachine, instanceNS, clusterName)
+		err = r.reconcileLoadBalancerMember(clusterScope, openStackCluster, machine, openStackMachine, instanceNS, clusterName)
 		if err != nil {
+			// The load balancer is immutable while it is updated for another member
+			if capoerrors.IsConflict(err) {
+				conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.LoadBalancerMemberErrorReason, clusterv1.ConditionSeverityWarning, "Reconciling load balancer member failed: %v", err)
+				return ctrl.Result{}, errors.Wrap(err, "LoadBalancerMember cannot be reconciled")
+			}
 			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("LoadBalancerMember cannot be reconciled: %v", err))
 			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.LoadBalancerMemberErrorReason, clusterv1.ConditionSeverityError, "Reconciling load balancer member failed: %v", err)
 			return ctrl.Result{}, nil
 		}
-	} else if !openStackCluster.Spec.DisableAPIServerFloatingIP {
-		floatingIPAddress := openStackCluster.Spec.ControlPlaneEndpoint.Host
+	} else if !openStackCluster.Spec.DisableAPIServerFloatingIP && !openStackCluster.Spec.ManagedAPIServerVIP {
+		floatingIPAddress := networking.GetControlPlaneEndpointAddress(openStackCluster)
 		if openStackCluster.Spec.APIServerFloatingIP != "" {
 			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
 		}
 		fp, err := networkingService.GetOrCreateFloatingIP(openStackMachine, openStackCluster, clusterName, floatingIPAddress)
 		if err != nil {
+			markOpenStackError(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPErrorReason, err)
 			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Floating IP cannot be got or created: %v", err))
 			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Floating IP cannot be obtained or created: %v", err)
 			return ctrl.Result{}, nil
@@ -416,34 +677,132 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 		port, err := computeService.GetManagementPort(openStackCluster, instanceStatus)
 		if err != nil {
 			err = errors.Errorf("getting management port for control plane machine %s: %v", machine.Name, err)
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "%s", err.Error())
 			handleUpdateMachineError(scope.Logger, openStackMachine, err)
 			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Obtaining management port for control plane machine failed: %v", err)
 			return ctrl.Result{}, nil
 		}
 
-		if fp.PortID != "" {
+		switch {
+		case fp.PortID != "" && fp.PortID != port.ID && hasAPIServerLoadBalancerStatus(openStackCluster):
+			// The cluster was switched away from its load balancer, which still holds the floating IP
+			scope.Logger.Info("Waiting for the load balancer to release the floating IP", "id", fp.ID, "portID", fp.PortID)
+			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.WaitingForLoadBalancerRemovalReason, clusterv1.ConditionSeverityInfo, "")
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.WaitingForLoadBalancerRemovalReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: waitForEndpointHandoverDuration}, nil
+		case fp.PortID != "":
 			scope.Logger.Info("Floating IP already associated to a port:", "id", fp.ID, "fixed ip", fp.FixedIP, "portID", port.ID)
-		} else {
+		default:
 			err = networkingService.AssociateFloatingIP(openStackMachine, fp, port.ID)
 			if err != nil {
+				markOpenStackError(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPErrorReason, err)
 				handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Floating IP cannot be associated: %v", err))
 				conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Associating floating IP failed: %v", err)
 				return ctrl.Result{}, nil
 			}
 		}
+		conditions.MarkTrue(openStackMachine, infrav1.FloatingIPReadyCondition)
+	} else {
+		conditions.Delete(openStackMachine, infrav1.FloatingIPReadyCondition)
 	}
 	conditions.MarkTrue(openStackMachine, infrav1.APIServerIngressReadyCondition)
 
 	scope.Logger.Info("Reconciled Machine create successfully")
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service, userData string) (*compute.InstanceStatus, error) {
+// reconcileKeyPair checks that the keypair referenced by the machine exists,
+// re-importing it from SSHPublicKeySecretRef if it was deleted from the cloud.
+func (r *OpenStackMachineReconciler) reconcileKeyPair(ctx context.Context, logger logr.Logger, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine) error {
+	keyPairName := openStackMachine.Spec.SSHKeyName
+	if keyPairName == "" {
+		return nil
+	}
+
+	exists, err := computeService.KeyPairExists(keyPairName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		conditions.MarkTrue(openStackMachine, infrav1.KeyPairReadyCondition)
+		return nil
+	}
+
+	if openStackMachine.Spec.SSHPublicKeySecretRef == nil {
+		err = errors.Errorf("keypair %s does not exist", keyPairName)
+		conditions.MarkFalse(openStackMachine, infrav1.KeyPairReadyCondition, infrav1.KeyPairNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	logger.Info("Keypair does not exist, re-importing it", "keypair", keyPairName)
+	publicKey, err := r.getSSHPublicKey(ctx, openStackMachine)
+	if err == nil {
+		err = computeService.ImportKeyPair(openStackMachine, keyPairName, publicKey)
+	}
+	if err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.KeyPairReadyCondition, infrav1.KeyPairImportFailedReason, clusterv1.ConditionSeverityError, "Importing keypair %s failed: %v", keyPairName, err)
+		return err
+	}
+
+	conditions.MarkTrue(openStackMachine, infrav1.KeyPairReadyCondition)
+	return nil
+}
+
+// reconcileServerCreateOpts records the hashes of the options the server of the machine was created with, and
+// reports the options which would differ if the server was created from the current spec. Machines whose server
+// was created before the hashes were recorded assume the current spec.
+func reconcileServerCreateOpts(openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) error {
+	specHash, fields, err := compute.HashServerCreateOpts(instanceSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash server create options")
+	}
+
+	recorded := openStackMachine.Status.ServerCreateOpts
+	if recorded != nil && recorded.Hash != specHash {
+		// Options which were not recorded, e.g. because they were added by a newer version, are not compared
+		var changed []string
+		for name, fieldHash := range fields {
+			if recordedHash, ok := recorded.Fields[name]; ok && recordedHash != fieldHash {
+				changed = append(changed, name)
+			}
+		}
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			conditions.MarkFalse(openStackMachine, infrav1.ServerCreateOptsUpToDateCondition, infrav1.ServerCreateOptsChangedReason, clusterv1.ConditionSeverityInfo, "Options changed since the server was created: %s", strings.Join(changed, ", "))
+			return nil
+		}
+	}
+
+	openStackMachine.Status.ServerCreateOpts = &infrav1.ServerCreateOptsHashes{Hash: specHash, Fields: fields}
+	conditions.MarkTrue(openStackMachine, infrav1.ServerCreateOptsUpToDateCondition)
+	return nil
+}
+
+func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service, userData string, bootstrapFormat infrav1.BootstrapFormat, ports []infrav1.PortOpts) (*compute.InstanceStatus, error) {
 	instanceStatus, err := computeService.GetInstanceStatusByName(openStackMachine, openStackMachine.Name)
 	if err != nil {
 		return nil, err
 	}
 
+	if instanceID, adopt := serverToAdopt(openStackMachine); instanceStatus == nil && adopt {
+		logger.Info("Adopting existing server", "instanceID", instanceID, "filter", openStackMachine.Spec.AdoptExisting)
+		instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, userData)
+		if err != nil {
+			err = errors.Errorf("machine spec is invalid: %v", err)
+			handleUpdateMachineError(logger, openStackMachine, err)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InvalidMachineSpecReason, clusterv1.ConditionSeverityError, err.Error())
+			return nil, err
+		}
+		instanceSpec.Ports = ports
+
+		clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
+		instanceStatus, err = computeService.AdoptInstance(openStackMachine, instanceSpec, clusterName, instanceID, openStackMachine.Spec.AdoptExisting)
+		if err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, instanceCreateFailedReason(err), clusterv1.ConditionSeverityError, err.Error())
+			return nil, errors.Wrap(err, "error adopting OpenStack instance")
+		}
+	}
+
 	if instanceStatus == nil {
 		logger.Info("Machine not exist, Creating Machine", "Machine", openStackMachine.Name)
 		instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, userData)
@@ -453,68 +812,186 @@ func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *cl
 			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InvalidMachineSpecReason, clusterv1.ConditionSeverityError, err.Error())
 			return nil, err
 		}
+		instanceSpec.BootstrapFormat = bootstrapFormat
+		// Fixed IPs claimed from IPAM pools are resolved to their addresses
+		instanceSpec.Ports = ports
+
+		if openStackMachine.Spec.ServerGroup != nil {
+			clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
+			serverGroupName := compute.ServerGroupName(clusterName, serverGroupOwner(machine))
+			instanceSpec.ServerGroupID, err = computeService.ReconcileServerGroup(openStackMachine, serverGroupName, openStackMachine.Spec.ServerGroup.Policy)
+			if err != nil {
+				conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return nil, errors.Wrapf(err, "error reconciling server group %s", serverGroupName)
+			}
+		}
 
 		instanceStatus, err = computeService.CreateInstance(openStackMachine, openStackCluster, instanceSpec, cluster.Name)
 		if err != nil {
-			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
-			return nil, errors.Errorf("error creating Openstack instance: %v", err)
+			var portErr *compute.PortError
+			if errors.As(err, &portErr) {
+				markOpenStackError(openStackMachine, infrav1.PortsReadyCondition, infrav1.PortCreateFailedReason, portErr.Err)
+			} else {
+				markOpenStackError(openStackMachine, infrav1.ServerCreatedCondition, instanceCreateFailedReason(err), err)
+			}
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, instanceCreateFailedReason(err), clusterv1.ConditionSeverityError, err.Error())
+			return nil, errors.Wrap(err, "error creating Openstack instance")
 		}
 	}
 
+	if instanceStatus != nil {
+		conditions.MarkTrue(openStackMachine, infrav1.PortsReadyCondition)
+		conditions.MarkTrue(openStackMachine, infrav1.ServerCreatedCondition)
+	}
+
 	return instanceStatus, nil
 }
 
-func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, userData string) (*compute.InstanceSpec, error) {
-	if openStackMachine == nil {
-		return nil, fmt.Errorf("create Options need be specified to create instace")
+// serverToAdopt returns whether the machine adopts an existing server instead of creating one, and the ID of the
+// server if it is selected by the provider ID. Once the server was adopted, the instance ID of the machine is set.
+func serverToAdopt(openStackMachine *infrav1.OpenStackMachine) (string, bool) {
+	if openStackMachine.Spec.InstanceID != nil {
+		return "", false
+	}
+	if openStackMachine.Spec.ProviderID != nil {
+		return strings.TrimPrefix(*openStackMachine.Spec.ProviderID, "openstack:///"), true
 	}
+	return "", openStackMachine.Spec.AdoptExisting != nil
+}
 
-	instanceSpec := compute.InstanceSpec{
-		Name:          openStackMachine.Name,
-		Image:         openStackMachine.Spec.Image,
-		ImageUUID:     openStackMachine.Spec.ImageUUID,
-		Flavor:        openStackMachine.Spec.Flavor,
-		SSHKeyName:    openStackMachine.Spec.SSHKeyName,
-		UserData:      userData,
-		Metadata:      openStackMachine.Spec.ServerMetadata,
-		ConfigDrive:   openStackMachine.Spec.ConfigDrive != nil && *openStackMachine.Spec.ConfigDrive,
-		RootVolume:    openStackMachine.Spec.RootVolume,
-		Subnet:        openStackMachine.Spec.Subnet,
-		ServerGroupID: openStackMachine.Spec.ServerGroupID,
-		Trunk:         openStackMachine.Spec.Trunk,
-	}
-
-	// Add the failure domain only if specified
-	if machine.Spec.FailureDomain != nil {
-		instanceSpec.FailureDomain = *machine.Spec.FailureDomain
+// instanceCreateFailedReason returns the condition reason for an error creating an instance.
+func instanceCreateFailedReason(err error) string {
+	switch {
+	case errors.Is(err, compute.ErrImageChecksumMismatch):
+		return infrav1.ImageChecksumMismatchReason
+	case errors.Is(err, compute.ErrVolumeAvailabilityZoneMismatch):
+		return infrav1.VolumeAvailabilityZoneMismatchReason
+	case errors.Is(err, compute.ErrNoValidResourceProvider):
+		return infrav1.NoValidResourceProviderReason
+	case errors.Is(err, compute.ErrReservationNotActive):
+		return infrav1.ReservationNotActiveReason
+	case errors.Is(err, compute.ErrServerNotAdoptable):
+		return infrav1.InstanceAdoptionFailedReason
+	default:
+		return infrav1.InstanceCreateFailedReason
 	}
+}
+
+// serverMetadata merges the server metadata of the cluster with the metadata of a server, which
+// takes precedence.
+func serverMetadata(openStackCluster *infrav1.OpenStackCluster, metadata map[string]string) map[string]string {
+	if len(openStackCluster.Spec.ServerMetadata) == 0 {
+		return metadata
+	}
+
+	merged := make(map[string]string, len(openStackCluster.Spec.ServerMetadata)+len(metadata))
+	for k, v := range openStackCluster.Spec.ServerMetadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return merged
+}
 
-	machineTags := []string{}
+// machineServerMetadata returns the metadata of the server of a machine, which includes the
+// mirrored labels and annotations of the Machine. The server metadata of the machine takes
+// precedence over them.
+func machineServerMetadata(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) map[string]string {
+	metadata := compute.PropagatedMetadata(openStackCluster.Spec.MachineMetadataPropagation, machine.Labels, machine.Annotations)
+	if len(metadata) == 0 {
+		return serverMetadata(openStackCluster, openStackMachine.Spec.ServerMetadata)
+	}
+	for k, v := range openStackMachine.Spec.ServerMetadata {
+		metadata[k] = v
+	}
+	return serverMetadata(openStackCluster, metadata)
+}
+
+// instanceTags returns the tags of an instance, which are the tags of the machine followed by the tags of the
+// cluster.
+func instanceTags(openStackCluster *infrav1.OpenStackCluster, machineTags []string) []string {
+	tags := []string{}
 
 	// Append machine specific tags
-	machineTags = append(machineTags, openStackMachine.Spec.Tags...)
+	tags = append(tags, machineTags...)
 
 	// Append cluster scope tags
-	machineTags = append(machineTags, openStackCluster.Spec.Tags...)
+	tags = append(tags, openStackCluster.Spec.Tags...)
 
 	// tags need to be unique or the "apply tags" call will fail.
-	deduplicate := func(tags []string) []string {
-		seen := make(map[string]struct{}, len(machineTags))
-		unique := make([]string, 0, len(machineTags))
-		for _, tag := range tags {
-			if _, ok := seen[tag]; !ok {
-				seen[tag] = struct{}{}
-				unique = append(unique, tag)
-			}
+	seen := make(map[string]struct{}, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; !ok {
+			seen[tag] = struct{}{}
+			unique = append(unique, tag)
 		}
-		return unique
 	}
-	machineTags = deduplicate(machineTags)
+	return unique
+}
+
+// instanceClusterName returns the name which identifies the cluster of an instance in the metadata of its volumes. The
+// namespace and name are separated by a slash, which is not allowed in either, so that it is unique.
+func instanceClusterName(openStackCluster *infrav1.OpenStackCluster) string {
+	return openStackCluster.Namespace + "/" + openStackCluster.Name
+}
+
+func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, userData string) (*compute.InstanceSpec, error) {
+	if openStackMachine == nil {
+		return nil, fmt.Errorf("create Options need be specified to create instace")
+	}
 
-	instanceSpec.Tags = machineTags
+	instanceSpec := compute.InstanceSpec{
+		Name:                   openStackMachine.Name,
+		Image:                  openStackMachine.Spec.Image,
+		ImageUUID:              openStackMachine.Spec.ImageUUID,
+		ImageChecksum:          openStackMachine.Spec.ImageChecksum,
+		ImageFilter:            openStackMachine.Spec.ImageFilter,
+		Flavor:                 openStackMachine.Spec.Flavor,
+		FlavorID:               openStackMachine.Spec.FlavorID,
+		SSHKeyName:             openStackMachine.Spec.SSHKeyName,
+		UserData:               userData,
+		Metadata:               machineServerMetadata(openStackCluster, machine, openStackMachine),
+		ConfigDrive:            openStackMachine.Spec.ConfigDrive != nil && *openStackMachine.Spec.ConfigDrive,
+		RootVolume:             rootVolumeWithAvailabilityZone(openStackCluster, openStackMachine.Spec.RootVolume),
+		AdditionalBlockDevices: machineBlockDevices(&openStackMachine.Spec),
+		Subnet:                 openStackMachine.Spec.Subnet,
+		ManagedSubnet:          openStackMachine.Spec.ManagedSubnet,
+		ServerGroupID:          openStackMachine.Spec.ServerGroupID,
+		SchedulerHints:         openStackMachine.Spec.SchedulerHintAdditionalProperties,
+		Traits:                 openStackMachine.Spec.Traits,
+		Reservation:            openStackMachine.Spec.Reservation,
+		Trunk:                  openStackMachine.Spec.Trunk,
+		ClusterName:            instanceClusterName(openStackCluster),
+	}
+
+	hostname, err := machineHostname(machine, openStackMachine)
+	if err != nil {
+		return nil, err
+	}
+	instanceSpec.Hostname = hostname
+
+	if openStackMachine.Status.ImageID != "" {
+		instanceSpec.ImageUUID = openStackMachine.Status.ImageID
+	}
+
+	if openStackMachine.Spec.Ignition != nil {
+		instanceSpec.IgnitionSwiftContainer = openStackMachine.Spec.Ignition.SwiftContainer
+	}
+	instanceSpec.ConfigureSecondaryNICs = openStackMachine.Spec.ConfigureSecondaryInterfaces
+
+	// Use the failure domain if specified, otherwise the compute availability zone of the cluster
+	instanceSpec.FailureDomain = openStackCluster.Spec.ComputeAvailabilityZone
+	if machine.Spec.FailureDomain != nil {
+		instanceSpec.FailureDomain = *machine.Spec.FailureDomain
+	}
+
+	instanceSpec.Tags = instanceTags(openStackCluster, openStackMachine.Spec.Tags)
 
 	instanceSpec.SecurityGroups = openStackMachine.Spec.SecurityGroups
-	if openStackCluster.Spec.ManagedSecurityGroups {
+	// The managed security groups only exist in the region of the cluster
+	if openStackCluster.Spec.ManagedSecurityGroups && openStackMachine.Spec.Region == "" {
 		var managedSecurityGroup string
 		if util.IsControlPlaneMachine(machine) {
 			managedSecurityGroup = openStackCluster.Status.ControlPlaneSecurityGroup.ID
@@ -530,9 +1007,68 @@ func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *
 	instanceSpec.Networks = openStackMachine.Spec.Networks
 	instanceSpec.Ports = openStackMachine.Spec.Ports
 
+	// Any control plane machine may hold the managed VIP of the API server
+	if openStackCluster.Spec.ManagedAPIServerVIP && util.IsControlPlaneMachine(machine) &&
+		openStackCluster.Status.Network != nil && openStackCluster.Status.Network.APIServerVIP != nil {
+		instanceSpec.APIServerVIP = openStackCluster.Status.Network.APIServerVIP.IP
+	}
+
 	return &instanceSpec, nil
 }
 
+// machineHostname renders the hostname template of the machine. It returns an empty hostname if the template is not
+// set, in which case Nova derives the hostname from the name of the server.
+func machineHostname(machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (string, error) {
+	data := names.TemplateData{
+		ClusterName: machine.Spec.ClusterName,
+		Namespace:   openStackMachine.Namespace,
+		MachineName: openStackMachine.Name,
+	}
+	hostname, err := names.Render(openStackMachine.Spec.Hostname, "", data)
+	if err != nil {
+		return "", err
+	}
+	if hostname == "" {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Label(hostname); len(errs) > 0 {
+		return "", fmt.Errorf("invalid hostname %q: %s", hostname, strings.Join(errs, ", "))
+	}
+	return hostname, nil
+}
+
+// serverGroupOwner returns the name of the group of machines sharing a
+// managed server group: the control plane, the machine's MachineDeployment or
+// MachineSet, or the machine itself if it is not part of any of these. The
+// labels of a Machine are copied to its OpenStackMachine, which therefore has
+// the same owner unless the machine is not part of any of these.
+func serverGroupOwner(machine metav1.Object) string {
+	labels := machine.GetLabels()
+	if _, ok := labels[clusterv1.MachineControlPlaneLabelName]; ok {
+		return "control-plane"
+	}
+	if name, ok := labels[clusterv1.MachineDeploymentLabelName]; ok && name != "" {
+		return name
+	}
+	if name, ok := labels[clusterv1.MachineSetLabelName]; ok && name != "" {
+		return name
+	}
+	return machine.GetName()
+}
+
+// snapshotBeforeDelete returns the snapshot to create before the server of the machine is deleted, or nil if the
+// snapshot is not enabled. The snapshotBeforeDelete of the machine takes precedence over that of the cluster.
+func snapshotBeforeDelete(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) *infrav1.SnapshotBeforeDelete {
+	snapshot := openStackCluster.Spec.SnapshotBeforeDelete
+	if openStackMachine.Spec.SnapshotBeforeDelete != nil {
+		snapshot = openStackMachine.Spec.SnapshotBeforeDelete
+	}
+	if snapshot == nil || !snapshot.Enabled {
+		return nil
+	}
+	return snapshot
+}
+
 func handleUpdateMachineError(logger logr.Logger, openstackMachine *infrav1.OpenStackMachine, message error) {
 	err := capierrors.UpdateMachineError
 	openstackMachine.Status.FailureReason = &err
@@ -542,7 +1078,15 @@ func handleUpdateMachineError(logger logr.Logger, openstackMachine *infrav1.Open
 }
 
 func (r *OpenStackMachineReconciler) reconcileLoadBalancerMember(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceNS *compute.InstanceNetworkStatus, clusterName string) error {
-	ip := instanceNS.IP(openStackCluster.Status.Network.Name)
+	network := openStackCluster.Status.Network.Name
+	if preferred := instanceNS.PreferredNetwork(openStackMachine.Spec.AddressSortOrder); preferred != "" {
+		network = preferred
+	}
+	ip := instanceNS.IP(network)
+	// Members of an IPv6 VIP are added with their IPv6 address
+	if openStackCluster.Spec.APIServerLoadBalancer.IPVersion == 6 {
+		ip = instanceNS.IPv6(network)
+	}
 	loadbalancerService, err := loadbalancer.NewService(scope)
 	if err != nil {
 		return err
@@ -583,23 +1127,56 @@ func (r *OpenStackMachineReconciler) OpenStackClusterToOpenStackMachines(ctx con
 	}
 }
 
-func (r *OpenStackMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (string, error) {
+func (r *OpenStackMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (string, infrav1.BootstrapFormat, error) {
 	if machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+		return "", "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
 	}
 
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{Namespace: machine.Namespace, Name: *machine.Spec.Bootstrap.DataSecretName}
 	if err := r.Client.Get(ctx, key, secret); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for Openstack Machine %s/%s", machine.Namespace, openStackMachine.Name)
+		return "", "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for Openstack Machine %s/%s", machine.Namespace, openStackMachine.Name)
 	}
 
 	value, ok := secret.Data["value"]
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return "", "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	return base64.StdEncoding.EncodeToString(value), bootstrapDataFormat(openStackMachine.Spec.BootstrapFormat, secret), nil
+}
+
+// bootstrapDataFormat returns format if it is set, and otherwise the format of the
+// bootstrap data in secret as set by the bootstrap provider.
+func bootstrapDataFormat(format infrav1.BootstrapFormat, secret *corev1.Secret) infrav1.BootstrapFormat {
+	if format != "" {
+		return format
+	}
+	if secretFormat := secret.Data["format"]; len(secretFormat) > 0 {
+		return infrav1.BootstrapFormat(secretFormat)
+	}
+	return infrav1.BootstrapFormatCloudConfig
+}
+
+func (r *OpenStackMachineReconciler) getSSHPublicKey(ctx context.Context, openStackMachine *infrav1.OpenStackMachine) (string, error) {
+	ref := openStackMachine.Spec.SSHPublicKeySecretRef
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: openStackMachine.Namespace, Name: ref.Name}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve SSH public key secret for Openstack Machine %s/%s", openStackMachine.Namespace, openStackMachine.Name)
+	}
+
+	dataKey := ref.Key
+	if dataKey == "" {
+		dataKey = defaultSSHPublicKeySecretKey
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return "", errors.Errorf("error retrieving SSH public key: secret %s has no key %s", ref.Name, dataKey)
 	}
 
-	return base64.StdEncoding.EncodeToString(value), nil
+	return string(value), nil
 }
 
 func (r *OpenStackMachineReconciler) requeueOpenStackMachinesForUnpausedCluster(ctx context.Context) handler.MapFunc {
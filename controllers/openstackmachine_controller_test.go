@@ -17,15 +17,28 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/utils/openstack/clientconfig"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 const (
@@ -36,6 +49,7 @@ const (
 	workerSecurityGroupUUID       = "9c6c0d28-03c9-436c-815d-58440ac2c1c8"
 	serverGroupUUID               = "7b940d62-68ef-4e42-a76a-1a62e290509c"
 
+	openStackClusterName = "test-openstack-cluster"
 	openStackMachineName = "test-openstack-machine"
 	namespace            = "test-namespace"
 	imageName            = "test-image"
@@ -46,6 +60,10 @@ const (
 
 func getDefaultOpenStackCluster() *infrav1.OpenStackCluster {
 	return &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openStackClusterName,
+			Namespace: namespace,
+		},
 		Spec: infrav1.OpenStackClusterSpec{},
 		Status: infrav1.OpenStackClusterStatus{
 			Network: &infrav1.Network{
@@ -107,6 +125,7 @@ func getDefaultInstanceSpec() *compute.InstanceSpec {
 		FailureDomain: *pointer.StringPtr(failureDomain),
 		ServerGroupID: serverGroupUUID,
 		Tags:          []string{"test-tag"},
+		ClusterName:   namespace + "/" + openStackClusterName,
 	}
 }
 
@@ -151,6 +170,42 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Managed API server VIP",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ManagedAPIServerVIP = true
+				c.Status.Network.APIServerVIP = &infrav1.APIServerVIP{PortID: "vip-port-id", IP: "10.6.0.10"}
+				return c
+			},
+			machine: func() *clusterv1.Machine {
+				m := getDefaultMachine()
+				m.Labels = map[string]string{
+					clusterv1.MachineControlPlaneLabelName: "true",
+				}
+				return m
+			},
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.APIServerVIP = "10.6.0.10"
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Managed API server VIP is not held by workers",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ManagedAPIServerVIP = true
+				c.Status.Network.APIServerVIP = &infrav1.APIServerVIP{PortID: "vip-port-id", IP: "10.6.0.10"}
+				return c
+			},
+			machine:          getDefaultMachine,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: getDefaultInstanceSpec,
+			wantErr:          false,
+		},
 		{
 			name: "Worker security group",
 			openStackCluster: func() *infrav1.OpenStackCluster {
@@ -190,6 +245,27 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Managed security groups are not added in another region",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ManagedSecurityGroups = true
+				return c
+			},
+			machine: getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Region = "region-two"
+				m.Spec.SecurityGroups = []infrav1.SecurityGroupParam{{UUID: extraSecurityGroupUUID}}
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.SecurityGroups = []infrav1.SecurityGroupParam{{UUID: extraSecurityGroupUUID}}
+				return i
+			},
+			wantErr: false,
+		},
 		{
 			name: "Tags",
 			openStackCluster: func() *infrav1.OpenStackCluster {
@@ -210,6 +286,197 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Server metadata",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ServerMetadata = map[string]string{
+					"cost-center":   "1234",
+					"test-metadata": "cluster-value",
+				}
+				return c
+			},
+			machine:          getDefaultMachine,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Metadata = map[string]string{
+					"cost-center":   "1234",
+					"test-metadata": "test-value",
+				}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Mirrored machine labels and annotations",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.MachineMetadataPropagation = &infrav1.MachineMetadataPropagation{
+					Labels:      []string{"node-role.kubernetes.io/worker"},
+					Annotations: []string{"example.com/owner"},
+				}
+				return c
+			},
+			machine: func() *clusterv1.Machine {
+				m := getDefaultMachine()
+				m.Labels = map[string]string{"node-role.kubernetes.io/worker": "", "unselected": "value"}
+				m.Annotations = map[string]string{"example.com/owner": "team-a"}
+				return m
+			},
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Metadata = map[string]string{
+					"k8s-label:node-role.kubernetes.io:worker": "",
+					"k8s-annotation:example.com:owner":         "team-a",
+					"test-metadata":                            "test-value",
+				}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Flavor selected by ID",
+			openStackCluster: getDefaultOpenStackCluster,
+			machine:          getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Flavor = ""
+				m.Spec.FlavorID = "3f9a7b41-2c6d-4e5f-8a90-1b2c3d4e5f60"
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Flavor = ""
+				i.FlavorID = "3f9a7b41-2c6d-4e5f-8a90-1b2c3d4e5f60"
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Image resolved from a filter",
+			openStackCluster: getDefaultOpenStackCluster,
+			machine:          getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Image = ""
+				m.Spec.ImageFilter = &infrav1.ImageFilter{Tags: []string{"kubernetes"}, MostRecent: true}
+				m.Status.ImageID = "ce96e584-7ebc-46d6-9e55-987d72e3806c"
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Image = ""
+				i.ImageFilter = &infrav1.ImageFilter{Tags: []string{"kubernetes"}, MostRecent: true}
+				i.ImageUUID = "ce96e584-7ebc-46d6-9e55-987d72e3806c"
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cluster availability zones",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ComputeAvailabilityZone = "compute-az"
+				c.Spec.RootVolumeAvailabilityZone = "volume-az"
+				return c
+			},
+			machine: func() *clusterv1.Machine {
+				m := getDefaultMachine()
+				m.Spec.FailureDomain = nil
+				return m
+			},
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.RootVolume = &infrav1.RootVolume{Size: 50, CrossAZAttach: true}
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.FailureDomain = "compute-az"
+				i.RootVolume = &infrav1.RootVolume{Size: 50, AvailabilityZone: "volume-az", CrossAZAttach: true}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failure domain and root volume availability zone take precedence",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.ComputeAvailabilityZone = "compute-az"
+				c.Spec.RootVolumeAvailabilityZone = "volume-az"
+				return c
+			},
+			machine: getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.RootVolume = &infrav1.RootVolume{Size: 50, AvailabilityZone: failureDomain}
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.RootVolume = &infrav1.RootVolume{Size: 50, AvailabilityZone: failureDomain}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Etcd volume of the control plane storage",
+			openStackCluster: getDefaultOpenStackCluster,
+			machine:          getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.AdditionalBlockDevices = []infrav1.AdditionalBlockDevice{{Name: "data", Size: 100}}
+				m.Spec.ControlPlaneStorage = &infrav1.ControlPlaneStorage{
+					Etcd: &infrav1.EtcdVolume{Size: 10, VolumeType: "ssd", QoSSpecs: "high-iops"},
+				}
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.AdditionalBlockDevices = []infrav1.AdditionalBlockDevice{
+					{Name: "data", Size: 100},
+					{Name: "etcd", Size: 10, VolumeType: "ssd", QoSSpecs: "high-iops", Tag: "etcd"},
+				}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Hostname",
+			openStackCluster: getDefaultOpenStackCluster,
+			machine: func() *clusterv1.Machine {
+				m := getDefaultMachine()
+				m.Spec.ClusterName = "mycluster"
+				return m
+			},
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Hostname = "{{ .ClusterName }}-{{ .MachineName }}"
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Hostname = "mycluster-" + openStackMachineName
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Invalid hostname",
+			openStackCluster: getDefaultOpenStackCluster,
+			machine:          getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Hostname = "{{ .Namespace }}.{{ .MachineName }}"
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				return nil
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -224,3 +491,246 @@ func Test_machineToInstanceSpec(t *testing.T) {
 		})
 	}
 }
+
+func Test_serverGroupOwner(t *testing.T) {
+	RegisterTestingT(t)
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name: "Control plane machine",
+			labels: map[string]string{
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+			want: "control-plane",
+		},
+		{
+			name: "MachineDeployment machine",
+			labels: map[string]string{
+				clusterv1.MachineDeploymentLabelName: "md-0",
+				clusterv1.MachineSetLabelName:        "md-0-7f4d5b",
+			},
+			want: "md-0",
+		},
+		{
+			name: "MachineSet machine",
+			labels: map[string]string{
+				clusterv1.MachineSetLabelName: "ms-0",
+			},
+			want: "ms-0",
+		},
+		{
+			name: "Standalone machine",
+			want: "test-machine",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := getDefaultMachine()
+			machine.Name = "test-machine"
+			machine.Labels = tt.labels
+
+			Expect(serverGroupOwner(machine)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_snapshotBeforeDelete(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := &infrav1.SnapshotBeforeDelete{Enabled: true, Name: "{{ .MachineName }}-crashed"}
+	disabled := &infrav1.SnapshotBeforeDelete{Enabled: false}
+
+	tests := []struct {
+		name    string
+		cluster *infrav1.SnapshotBeforeDelete
+		machine *infrav1.SnapshotBeforeDelete
+		want    *infrav1.SnapshotBeforeDelete
+	}{
+		{
+			name: "Not configured",
+		},
+		{
+			name:    "Cluster default",
+			cluster: enabled,
+			want:    enabled,
+		},
+		{
+			name:    "Machine overrides the cluster default",
+			cluster: &infrav1.SnapshotBeforeDelete{Enabled: true},
+			machine: enabled,
+			want:    enabled,
+		},
+		{
+			name:    "Machine disables the cluster default",
+			cluster: enabled,
+			machine: disabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openStackCluster := getDefaultOpenStackCluster()
+			openStackCluster.Spec.SnapshotBeforeDelete = tt.cluster
+			openStackMachine := getDefaultOpenStackMachine()
+			openStackMachine.Spec.SnapshotBeforeDelete = tt.machine
+
+			Expect(snapshotBeforeDelete(openStackCluster, openStackMachine)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_reconcileServerCreateOpts(t *testing.T) {
+	RegisterTestingT(t)
+
+	openStackMachine := getDefaultOpenStackMachine()
+	instanceSpec := &compute.InstanceSpec{Name: "test-instance", Flavor: "m1.small", Image: "ubuntu"}
+
+	// The options of a machine without recorded hashes are recorded
+	Expect(reconcileServerCreateOpts(openStackMachine, instanceSpec)).To(Succeed())
+	Expect(openStackMachine.Status.ServerCreateOpts).NotTo(BeNil())
+	recorded := openStackMachine.Status.ServerCreateOpts.DeepCopy()
+	Expect(conditions.IsTrue(openStackMachine, infrav1.ServerCreateOptsUpToDateCondition)).To(BeTrue())
+
+	// Changed options are reported, and the recorded hashes are kept
+	instanceSpec.Flavor = "m1.large"
+	instanceSpec.Image = "flatcar"
+	Expect(reconcileServerCreateOpts(openStackMachine, instanceSpec)).To(Succeed())
+	Expect(openStackMachine.Status.ServerCreateOpts).To(Equal(recorded))
+	condition := conditions.Get(openStackMachine, infrav1.ServerCreateOptsUpToDateCondition)
+	Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	Expect(condition.Reason).To(Equal(infrav1.ServerCreateOptsChangedReason))
+	Expect(condition.Message).To(Equal("Options changed since the server was created: Flavor, Image"))
+
+	// Options which were not recorded are not compared
+	instanceSpec.Flavor = "m1.small"
+	instanceSpec.Image = "ubuntu"
+	instanceSpec.ConfigDrive = true
+	delete(openStackMachine.Status.ServerCreateOpts.Fields, "ConfigDrive")
+	Expect(reconcileServerCreateOpts(openStackMachine, instanceSpec)).To(Succeed())
+	Expect(conditions.IsTrue(openStackMachine, infrav1.ServerCreateOptsUpToDateCondition)).To(BeTrue())
+	Expect(openStackMachine.Status.ServerCreateOpts.Fields).To(HaveKey("ConfigDrive"))
+}
+
+// newKeyPairComputeService returns a compute service of a Nova endpoint which knows the given keypairs, and which
+// adds imported keypairs to them. The requests made to the endpoint are appended to requests.
+func newKeyPairComputeService(t *testing.T, keyPairs map[string]bool, requests *[]string) *compute.Service {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && keyPairs[strings.TrimPrefix(r.URL.Path, "/os-keypairs/")]:
+			fmt.Fprintf(w, `{"keypair": {"name": %q}}`, strings.TrimPrefix(r.URL.Path, "/os-keypairs/"))
+		case r.Method == http.MethodPost && r.URL.Path == "/os-keypairs":
+			keyPairs[sshKeyName] = true
+			fmt.Fprintf(w, `{"keypair": {"name": %q}}`, sshKeyName)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &gophercloud.ProviderClient{}
+	provider.UseTokenLock()
+	provider.EndpointLocator = func(gophercloud.EndpointOpts) (string, error) { return server.URL + "/", nil }
+	computeService, err := compute.NewService(&scope.Scope{
+		ProviderClient:     provider,
+		ProviderClientOpts: &clientconfig.ClientOpts{AuthInfo: &clientconfig.AuthInfo{}},
+		Logger:             logr.Discard(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return computeService
+}
+
+func Test_reconcileKeyPair(t *testing.T) {
+	const getKeyPair = "GET /os-keypairs/" + sshKeyName
+	publicKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-public-key", Namespace: namespace},
+		Data:       map[string][]byte{"ssh-publickey": []byte("ssh-ed25519 AAAA")},
+	}
+
+	tests := []struct {
+		name          string
+		modify        func(*infrav1.OpenStackMachine)
+		keyPairExists bool
+		wantRequests  []string
+		wantErr       bool
+		wantReason    string
+	}{
+		{
+			name:   "No keypair",
+			modify: func(m *infrav1.OpenStackMachine) { m.Spec.SSHKeyName = "" },
+		},
+		{
+			name:          "Keypair exists",
+			keyPairExists: true,
+			wantRequests:  []string{getKeyPair},
+		},
+		{
+			name:         "Missing keypair without public key secret",
+			wantRequests: []string{getKeyPair},
+			wantErr:      true,
+			wantReason:   infrav1.KeyPairNotFoundReason,
+		},
+		{
+			name:         "Missing keypair of a provisioned machine",
+			modify:       func(m *infrav1.OpenStackMachine) { m.Spec.InstanceID = pointer.String("instance-id") },
+			wantRequests: []string{getKeyPair},
+			wantErr:      true,
+			wantReason:   infrav1.KeyPairNotFoundReason,
+		},
+		{
+			name: "Missing keypair is re-imported",
+			modify: func(m *infrav1.OpenStackMachine) {
+				m.Spec.SSHPublicKeySecretRef = &infrav1.SSHPublicKeySecretReference{Name: publicKeySecret.Name}
+			},
+			wantRequests: []string{getKeyPair, "POST /os-keypairs"},
+		},
+		{
+			name: "Missing public key secret",
+			modify: func(m *infrav1.OpenStackMachine) {
+				m.Spec.SSHPublicKeySecretRef = &infrav1.SSHPublicKeySecretReference{Name: "missing"}
+			},
+			wantRequests: []string{getKeyPair},
+			wantErr:      true,
+			wantReason:   infrav1.KeyPairImportFailedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			r := &OpenStackMachineReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(publicKeySecret.DeepCopy()).Build()}
+
+			openStackMachine := getDefaultOpenStackMachine()
+			if tt.modify != nil {
+				tt.modify(openStackMachine)
+			}
+			var requests []string
+			computeService := newKeyPairComputeService(t, map[string]bool{sshKeyName: tt.keyPairExists}, &requests)
+
+			err := r.reconcileKeyPair(context.TODO(), logr.Discard(), computeService, openStackMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(requests).To(Equal(tt.wantRequests))
+
+			switch {
+			case openStackMachine.Spec.SSHKeyName == "":
+				g.Expect(conditions.Has(openStackMachine, infrav1.KeyPairReadyCondition)).To(BeFalse())
+			case tt.wantReason != "":
+				g.Expect(conditions.GetReason(openStackMachine, infrav1.KeyPairReadyCondition)).To(Equal(tt.wantReason))
+			default:
+				g.Expect(conditions.IsTrue(openStackMachine, infrav1.KeyPairReadyCondition)).To(BeTrue())
+			}
+		})
+	}
+}
@@ -0,0 +1,651 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// OpenStackMachinePoolReconciler reconciles a OpenStackMachinePool object.
+type OpenStackMachinePoolReconciler struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	WatchFilterValue string
+}
+
+const (
+	// machinePoolKey is the server metadata key identifying the pool an instance belongs to.
+	machinePoolKey = "capo-machine-pool"
+	// machinePoolTemplateHashKey is the server metadata key of the hash of the template an
+	// instance was created from.
+	machinePoolTemplateHashKey = "capo-machine-pool-template-hash"
+
+	defaultMachinePoolMaxSurge = 1
+
+	waitForMachinePoolInstancesToReconcile = 30 * time.Second
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+
+func (r *OpenStackMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackMachinePool instance.
+	openStackMachinePool := &infrav1.OpenStackMachinePool{}
+	err := r.Client.Get(ctx, req.NamespacedName, openStackMachinePool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackMachinePool", openStackMachinePool.Name)
+
+	// Fetch the MachinePool.
+	machinePool, err := exputil.GetOwnerMachinePool(ctx, r.Client, openStackMachinePool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		log.Info("MachinePool Controller has not yet set OwnerRef")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("machinePool", machinePool.Name)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Info("MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("cluster", cluster.Name)
+
+	if annotations.IsPaused(cluster, openStackMachinePool) {
+		log.Info("OpenStackMachinePool or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	openStackClusterName := client.ObjectKey{
+		Namespace: openStackMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, openStackClusterName, openStackCluster); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error getting infra provider cluster")
+	}
+
+	log = log.WithValues("openStackCluster", openStackCluster.Name)
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(openStackMachinePool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the openStackMachinePool when exiting this function so we can persist any OpenStackMachinePool changes.
+	defer func() {
+		conditions.SetSummary(openStackMachinePool, conditions.WithConditions(infrav1.InstancesReadyCondition))
+		if err := patchHelper.Patch(ctx, openStackMachinePool, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.InstancesReadyCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Deletions are never delayed, so that the OpenStack resources are released.
+	if openStackMachinePool.DeletionTimestamp.IsZero() {
+		if exceeded, result := apiBudgetExceeded(log, cluster, openStackMachinePool); exceeded {
+			return result, nil
+		}
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachinePool(ctx, r.Client, openStackMachinePool)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	trackAPIRequests(osProviderClient, cluster)
+	trackAPIDeprecations(osProviderClient, log)
+	logAPIRequests(osProviderClient, log, openStackCluster)
+	recordAPIRequests(osProviderClient, openStackMachinePool)
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+		ReconcileContext:   ctx,
+	}
+
+	// Handle deleted machine pools
+	if !openStackMachinePool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(scope, cluster, openStackMachinePool)
+	}
+
+	// Handle non-deleted machine pools
+	return r.reconcileNormal(ctx, scope, patchHelper, cluster, openStackCluster, machinePool, openStackMachinePool)
+}
+
+func (r *OpenStackMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.OpenStackMachinePool{}).
+		Watches(
+			&source.Kind{Type: &expclusterv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(exputil.MachinePoolToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("OpenStackMachinePool"), ctrl.LoggerFrom(ctx))),
+		).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
+
+func (r *OpenStackMachinePoolReconciler) reconcileDelete(scope *scope.Scope, cluster *clusterv1.Cluster, openStackMachinePool *infrav1.OpenStackMachinePool) (ctrl.Result, error) {
+	scope.Logger.Info("Reconciling MachinePool delete")
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instances, err := getMachinePoolInstances(computeService, openStackMachinePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, instance := range instances {
+		if err := computeService.DeleteInstance(openStackMachinePool, instance, instance.Name(), openStackMachinePool.Spec.Template.RootVolume, machineBlockDevices(&openStackMachinePool.Spec.Template), openStackMachinePool.Spec.Template.Ports); err != nil {
+			conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, infrav1.InstanceDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting instance %s failed: %v", instance.Name(), err)
+			return ctrl.Result{}, errors.Errorf("error deleting OpenStack instance %s with ID %s: %v", instance.Name(), instance.ID(), err)
+		}
+	}
+
+	if openStackMachinePool.Spec.Template.ServerGroup != nil {
+		clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
+		serverGroupName := compute.ServerGroupName(clusterName, openStackMachinePool.Name)
+		if err := computeService.DeleteServerGroupIfUnused(openStackMachinePool, serverGroupName); err != nil {
+			return ctrl.Result{}, errors.Errorf("error deleting server group %s: %v", serverGroupName, err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(openStackMachinePool, infrav1.MachinePoolFinalizer)
+	scope.Logger.Info("Reconciled MachinePool delete successfully")
+	return ctrl.Result{}, nil
+}
+
+func (r *OpenStackMachinePoolReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machinePool *expclusterv1.MachinePool, openStackMachinePool *infrav1.OpenStackMachinePool) (ctrl.Result, error) {
+	// If the OpenStackMachinePool is in an error state, return early.
+	if openStackMachinePool.Status.FailureReason != nil || openStackMachinePool.Status.FailureMessage != nil {
+		scope.Logger.Info("Not reconciling machine pool in failed state. See openStackMachinePool.status.failureReason, openStackMachinePool.status.failureMessage, or previously logged error for details")
+		return ctrl.Result{}, nil
+	}
+
+	// If the OpenStackMachinePool doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(openStackMachinePool, infrav1.MachinePoolFinalizer)
+	// Register the finalizer immediately to avoid orphaning OpenStack resources on delete
+	if err := patchHelper.Patch(ctx, openStackMachinePool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !cluster.Status.InfrastructureReady {
+		scope.Logger.Info("Cluster infrastructure is not ready yet, requeuing machine pool")
+		conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: waitForClusterInfrastructureReadyDuration}, nil
+	}
+
+	// Make sure bootstrap data is available and populated.
+	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		scope.Logger.Info("Bootstrap data secret reference is not yet available")
+		conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+	userData, bootstrapFormat, err := r.getBootstrapData(ctx, machinePool, openStackMachinePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	scope.Logger.Info("Reconciling MachinePool")
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	templateHash, err := machinePoolTemplateHash(machinePool, openStackMachinePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instanceStatuses, err := getMachinePoolInstances(computeService, openStackMachinePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instances := make([]poolInstance, 0, len(instanceStatuses))
+	for _, instanceStatus := range instanceStatuses {
+		instances = append(instances, poolInstance{
+			InstanceStatus: instanceStatus,
+			upToDate:       instanceStatus.Metadata()[machinePoolTemplateHashKey] == templateHash,
+		})
+	}
+
+	desired := 1
+	if machinePool.Spec.Replicas != nil {
+		desired = int(*machinePool.Spec.Replicas)
+	}
+	maxSurge := defaultMachinePoolMaxSurge
+	if openStackMachinePool.Spec.MaxSurge != nil {
+		maxSurge = int(*openStackMachinePool.Spec.MaxSurge)
+	}
+	create, toDelete := planMachinePoolInstances(instances, desired, maxSurge)
+
+	deleted := make(map[string]bool, len(toDelete))
+	for _, instance := range toDelete {
+		scope.Logger.Info("Deleting instance of MachinePool", "name", instance.Name(), "up-to-date", instance.upToDate, "state", instance.State())
+		if err := computeService.DeleteInstance(openStackMachinePool, instance.InstanceStatus, instance.Name(), openStackMachinePool.Spec.Template.RootVolume, machineBlockDevices(&openStackMachinePool.Spec.Template), openStackMachinePool.Spec.Template.Ports); err != nil {
+			conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, infrav1.InstanceDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting instance %s failed: %v", instance.Name(), err)
+			return ctrl.Result{}, errors.Errorf("error deleting OpenStack instance %s with ID %s: %v", instance.Name(), instance.ID(), err)
+		}
+		deleted[instance.ID()] = true
+	}
+	remaining := make([]poolInstance, 0, len(instances))
+	metadata := serverMetadata(openStackCluster, openStackMachinePool.Spec.Template.ServerMetadata)
+	tagsSpec := &compute.InstanceSpec{
+		Tags:  instanceTags(openStackCluster, openStackMachinePool.Spec.Template.Tags),
+		Trunk: openStackMachinePool.Spec.Template.Trunk,
+		Ports: openStackMachinePool.Spec.Template.Ports,
+	}
+	for _, instance := range instances {
+		if deleted[instance.ID()] {
+			continue
+		}
+		remaining = append(remaining, instance)
+		if instance.State() != infrav1.InstanceStateActive {
+			continue
+		}
+		if err := computeService.ReconcileServerMetadata(openStackMachinePool, instance.InstanceStatus, metadata); err != nil {
+			return ctrl.Result{}, errors.Errorf("error updating metadata of OpenStack instance %s with ID %s: %v", instance.Name(), instance.ID(), err)
+		}
+		if err := computeService.ReconcileInstanceTags(openStackMachinePool, tagsSpec, instance.InstanceStatus); err != nil {
+			return ctrl.Result{}, errors.Errorf("error updating tags of OpenStack instance %s with ID %s: %v", instance.Name(), instance.ID(), err)
+		}
+	}
+
+	if create > 0 {
+		scope.Logger.Info("Creating instances of MachinePool", "count", create)
+		if err := r.createInstances(cluster, openStackCluster, machinePool, openStackMachinePool, computeService, remaining, create, templateHash, userData, bootstrapFormat); err != nil {
+			conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, instanceCreateFailedReason(err), clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	setMachinePoolInstances(openStackMachinePool, remaining)
+	openStackMachinePool.Status.Ready = true
+
+	activeUpToDate := 0
+	for _, instance := range remaining {
+		if instance.upToDate && instance.State() == infrav1.InstanceStateActive {
+			activeUpToDate++
+		}
+	}
+	if create > 0 || len(toDelete) > 0 || activeUpToDate != desired || len(remaining) != desired {
+		conditions.MarkFalse(openStackMachinePool, infrav1.InstancesReadyCondition, infrav1.InstancesScalingReason, clusterv1.ConditionSeverityInfo, "%d of %d instances are up to date and active", activeUpToDate, desired)
+		return ctrl.Result{RequeueAfter: waitForMachinePoolInstancesToReconcile}, nil
+	}
+
+	conditions.MarkTrue(openStackMachinePool, infrav1.InstancesReadyCondition)
+	scope.Logger.Info("Reconciled MachinePool successfully")
+	return ctrl.Result{}, nil
+}
+
+// createInstances creates count instances of the pool, spread over the failure domains of the
+// MachinePool. Instances are created with a single request per failure domain if the template
+// allows it.
+func (r *OpenStackMachinePoolReconciler) createInstances(cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machinePool *expclusterv1.MachinePool, openStackMachinePool *infrav1.OpenStackMachinePool, computeService *compute.Service, instances []poolInstance, count int, templateHash, userData string, bootstrapFormat infrav1.BootstrapFormat) error {
+	var serverGroupID string
+	if openStackMachinePool.Spec.Template.ServerGroup != nil {
+		clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
+		serverGroupName := compute.ServerGroupName(clusterName, openStackMachinePool.Name)
+		var err error
+		serverGroupID, err = computeService.ReconcileServerGroup(openStackMachinePool, serverGroupName, openStackMachinePool.Spec.Template.ServerGroup.Policy)
+		if err != nil {
+			return errors.Errorf("error reconciling server group %s: %v", serverGroupName, err)
+		}
+	}
+
+	failureDomains := machinePool.Spec.FailureDomains
+	counts := spreadOverFailureDomains(failureDomains, instances, count)
+	if len(failureDomains) == 0 {
+		failureDomains = []string{""}
+	}
+	for _, failureDomain := range failureDomains {
+		n := counts[failureDomain]
+		if n == 0 {
+			continue
+		}
+
+		instanceSpec, err := machinePoolToInstanceSpec(openStackCluster, openStackMachinePool, machinePoolInstanceName(openStackMachinePool), failureDomain, templateHash, userData)
+		if err != nil {
+			return errors.Errorf("machine pool spec is invalid: %v", err)
+		}
+		instanceSpec.BootstrapFormat = bootstrapFormat
+		if serverGroupID != "" {
+			instanceSpec.ServerGroupID = serverGroupID
+		}
+
+		if compute.SupportsBatchCreate(instanceSpec) {
+			if _, err := computeService.CreateInstanceBatch(openStackMachinePool, openStackCluster, instanceSpec, n, n); err != nil {
+				return errors.Wrap(err, "error creating OpenStack instances")
+			}
+			continue
+		}
+
+		for i := 0; i < n; i++ {
+			instanceSpec.Name = machinePoolInstanceName(openStackMachinePool)
+			if _, err := computeService.CreateInstance(openStackMachinePool, openStackCluster, instanceSpec, cluster.Name); err != nil {
+				return errors.Wrap(err, "error creating OpenStack instance")
+			}
+		}
+	}
+	return nil
+}
+
+func (r *OpenStackMachinePoolReconciler) getBootstrapData(ctx context.Context, machinePool *expclusterv1.MachinePool, openStackMachinePool *infrav1.OpenStackMachinePool) (string, infrav1.BootstrapFormat, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: machinePool.Namespace, Name: *machinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		return "", "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for MachinePool %s/%s", machinePool.Namespace, machinePool.Name)
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return "", "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	return base64.StdEncoding.EncodeToString(value), bootstrapDataFormat(openStackMachinePool.Spec.Template.BootstrapFormat, secret), nil
+}
+
+// poolInstance is an instance of an OpenStackMachinePool.
+type poolInstance struct {
+	*compute.InstanceStatus
+	// upToDate is true if the instance was created from the current template.
+	upToDate bool
+}
+
+func (i poolInstance) active() bool {
+	return i.State() == infrav1.InstanceStateActive
+}
+
+// planMachinePoolInstances returns the number of instances to create and the instances to
+// delete to move the pool towards desired up to date instances. Failed instances are always
+// replaced. Up to maxSurge instances are created above desired to replace outdated instances,
+// which are only deleted once enough instances are active. If maxSurge is 0, an outdated
+// instance is deleted before its replacement is created.
+func planMachinePoolInstances(instances []poolInstance, desired, maxSurge int) (int, []poolInstance) {
+	var toDelete, upToDate, outdated []poolInstance
+	active := 0
+	for _, instance := range instances {
+		switch {
+		case instance.State() == infrav1.InstanceStateError:
+			toDelete = append(toDelete, instance)
+			continue
+		case instance.upToDate:
+			upToDate = append(upToDate, instance)
+		default:
+			outdated = append(outdated, instance)
+		}
+		if instance.active() {
+			active++
+		}
+	}
+
+	// Delete instances which are not active yet first, then the newest ones
+	byDeletionPriority := func(list []poolInstance) {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].active() != list[j].active() {
+				return !list[i].active()
+			}
+			return list[i].Name() > list[j].Name()
+		})
+	}
+	byDeletionPriority(upToDate)
+	byDeletionPriority(outdated)
+
+	// Outdated instances are replaced first, as long as enough instances stay active
+	excess := len(upToDate) + len(outdated) - desired
+	for len(outdated) > 0 && excess > 0 {
+		if outdated[0].active() {
+			if active-1 < desired {
+				break
+			}
+			active--
+		}
+		toDelete = append(toDelete, outdated[0])
+		outdated = outdated[1:]
+		excess--
+	}
+	// Up to date instances are only deleted when scaling down
+	for len(upToDate) > 0 && excess > len(outdated) {
+		toDelete = append(toDelete, upToDate[0])
+		upToDate = upToDate[1:]
+		excess--
+	}
+
+	missing := desired - len(upToDate)
+	room := desired + maxSurge - len(upToDate) - len(outdated)
+	create := missing
+	if room < create {
+		create = room
+	}
+	if create < 0 {
+		create = 0
+	}
+
+	// Without surge an outdated instance has to make room for its replacement, once the
+	// previous replacements are active
+	if create == 0 && missing > 0 && len(outdated) > 0 {
+		allActive := true
+		for _, instance := range upToDate {
+			allActive = allActive && instance.active()
+		}
+		if allActive {
+			toDelete = append(toDelete, outdated[0])
+			create = 1
+		}
+	}
+
+	return create, toDelete
+}
+
+// spreadOverFailureDomains returns the number of instances to create in each failure domain,
+// filling up the failure domains with the fewest instances first.
+func spreadOverFailureDomains(failureDomains []string, instances []poolInstance, count int) map[string]int {
+	if len(failureDomains) == 0 {
+		return map[string]int{"": count}
+	}
+
+	current := make(map[string]int, len(failureDomains))
+	for _, failureDomain := range failureDomains {
+		current[failureDomain] = 0
+	}
+	for _, instance := range instances {
+		if _, ok := current[instance.AvailabilityZone()]; ok {
+			current[instance.AvailabilityZone()]++
+		}
+	}
+
+	counts := make(map[string]int, len(failureDomains))
+	for i := 0; i < count; i++ {
+		next := failureDomains[0]
+		for _, failureDomain := range failureDomains[1:] {
+			if current[failureDomain] < current[next] {
+				next = failureDomain
+			}
+		}
+		current[next]++
+		counts[next]++
+	}
+	return counts
+}
+
+// getMachinePoolInstances returns the instances of the pool which have not been deleted.
+func getMachinePoolInstances(computeService *compute.Service, openStackMachinePool *infrav1.OpenStackMachinePool) ([]*compute.InstanceStatus, error) {
+	instanceStatuses, err := computeService.GetInstanceStatusesByNamePrefix(openStackMachinePool.Name + "-")
+	if err != nil {
+		return nil, err
+	}
+
+	// Pools in other namespaces or with a name starting with the name of this pool share the prefix
+	owner := machinePoolOwner(openStackMachinePool)
+	instances := make([]*compute.InstanceStatus, 0, len(instanceStatuses))
+	for _, instanceStatus := range instanceStatuses {
+		if instanceStatus.Metadata()[machinePoolKey] == owner && instanceStatus.State() != infrav1.InstanceStateDeleted {
+			instances = append(instances, instanceStatus)
+		}
+	}
+	return instances, nil
+}
+
+func machinePoolOwner(openStackMachinePool *infrav1.OpenStackMachinePool) string {
+	return fmt.Sprintf("%s/%s", openStackMachinePool.Namespace, openStackMachinePool.Name)
+}
+
+func machinePoolInstanceName(openStackMachinePool *infrav1.OpenStackMachinePool) string {
+	return fmt.Sprintf("%s-%s", openStackMachinePool.Name, utilrand.String(5))
+}
+
+// machinePoolTemplateHash returns a hash of everything which requires the instances of the pool
+// to be replaced when it changes. The server metadata and tags are updated in place by the
+// reconcile, so they are not part of it.
+func machinePoolTemplateHash(machinePool *expclusterv1.MachinePool, openStackMachinePool *infrav1.OpenStackMachinePool) (string, error) {
+	template := openStackMachinePool.Spec.Template.DeepCopy()
+	template.ProviderID = nil
+	template.InstanceID = nil
+	template.ServerMetadata = nil
+	template.Tags = nil
+
+	b, err := json.Marshal(struct {
+		Template       *infrav1.OpenStackMachineSpec
+		Version        *string
+		DataSecretName *string
+	}{
+		Template:       template,
+		Version:        machinePool.Spec.Template.Spec.Version,
+		DataSecretName: machinePool.Spec.Template.Spec.Bootstrap.DataSecretName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write(b)
+	return fmt.Sprintf("%x", hash.Sum32()), nil
+}
+
+func machinePoolToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, openStackMachinePool *infrav1.OpenStackMachinePool, name, failureDomain, templateHash, userData string) (*compute.InstanceSpec, error) {
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       openStackMachinePool.Spec.Template,
+	}
+	machine := &clusterv1.Machine{}
+	if failureDomain != "" {
+		machine.Spec.FailureDomain = &failureDomain
+	}
+
+	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, userData)
+	if err != nil {
+		return nil, err
+	}
+
+	// The instances of a pool are named like hostnames, and Nova does not allow a hostname for several instances
+	instanceSpec.Hostname = ""
+
+	metadata := make(map[string]string, len(instanceSpec.Metadata)+2)
+	for k, v := range instanceSpec.Metadata {
+		metadata[k] = v
+	}
+	metadata[machinePoolKey] = machinePoolOwner(openStackMachinePool)
+	metadata[machinePoolTemplateHashKey] = templateHash
+	instanceSpec.Metadata = metadata
+
+	return instanceSpec, nil
+}
+
+// setMachinePoolInstances reports the instances of the pool and their provider IDs.
+func setMachinePoolInstances(openStackMachinePool *infrav1.OpenStackMachinePool, instances []poolInstance) {
+	statuses := make([]infrav1.OpenStackMachinePoolInstance, 0, len(instances))
+	var replicas int32
+	for _, instance := range instances {
+		statuses = append(statuses, infrav1.OpenStackMachinePoolInstance{
+			Name:       instance.Name(),
+			ID:         instance.ID(),
+			ProviderID: fmt.Sprintf("openstack:///%s", instance.ID()),
+			State:      instance.State(),
+			UpToDate:   instance.upToDate,
+		})
+		if instance.active() {
+			replicas++
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	providerIDList := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		providerIDList = append(providerIDList, status.ProviderID)
+	}
+
+	openStackMachinePool.Spec.ProviderIDList = providerIDList
+	openStackMachinePool.Status.Instances = statuses
+	openStackMachinePool.Status.Replicas = replicas
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+func newPoolInstance(name string, state infrav1.InstanceState, upToDate bool, availabilityZone string) poolInstance {
+	server := &clients.ServerExt{Server: servers.Server{ID: name, Name: name, Status: string(state)}}
+	server.AvailabilityZone = availabilityZone
+	return poolInstance{
+		InstanceStatus: compute.NewInstanceStatusFromServer(server, logr.Discard()),
+		upToDate:       upToDate,
+	}
+}
+
+func Test_planMachinePoolInstances(t *testing.T) {
+	active := infrav1.InstanceStateActive
+	build := infrav1.InstanceStateBuilding
+
+	tests := []struct {
+		name       string
+		instances  []poolInstance
+		desired    int
+		maxSurge   int
+		wantCreate int
+		wantDelete []string
+	}{
+		{
+			name:       "scale up from zero",
+			desired:    3,
+			maxSurge:   1,
+			wantCreate: 3,
+		},
+		{
+			name: "scale down deletes the newest instances",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, true, ""),
+				newPoolInstance("pool-b", active, true, ""),
+				newPoolInstance("pool-c", active, true, ""),
+			},
+			desired:    1,
+			maxSurge:   1,
+			wantDelete: []string{"pool-c", "pool-b"},
+		},
+		{
+			name: "failed instance is replaced",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, true, ""),
+				newPoolInstance("pool-b", infrav1.InstanceStateError, true, ""),
+			},
+			desired:    2,
+			maxSurge:   1,
+			wantCreate: 1,
+			wantDelete: []string{"pool-b"},
+		},
+		{
+			name: "outdated instances are replaced with surge",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, false, ""),
+				newPoolInstance("pool-b", active, false, ""),
+			},
+			desired:    2,
+			maxSurge:   1,
+			wantCreate: 1,
+		},
+		{
+			name: "outdated instance is deleted once its replacement is active",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, false, ""),
+				newPoolInstance("pool-b", active, false, ""),
+				newPoolInstance("pool-c", active, true, ""),
+			},
+			desired:    2,
+			maxSurge:   1,
+			wantCreate: 1,
+			wantDelete: []string{"pool-b"},
+		},
+		{
+			name: "outdated instance is kept while its replacement is building",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, false, ""),
+				newPoolInstance("pool-b", active, false, ""),
+				newPoolInstance("pool-c", build, true, ""),
+			},
+			desired:  2,
+			maxSurge: 1,
+		},
+		{
+			name: "outdated instance is deleted first without surge",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, false, ""),
+				newPoolInstance("pool-b", active, false, ""),
+			},
+			desired:    2,
+			maxSurge:   0,
+			wantCreate: 1,
+			wantDelete: []string{"pool-b"},
+		},
+		{
+			name: "no surge waits for the replacement to be active",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, false, ""),
+				newPoolInstance("pool-c", build, true, ""),
+			},
+			desired:  2,
+			maxSurge: 0,
+		},
+		{
+			name: "up to date pool is unchanged",
+			instances: []poolInstance{
+				newPoolInstance("pool-a", active, true, ""),
+				newPoolInstance("pool-b", active, true, ""),
+			},
+			desired:  2,
+			maxSurge: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			create, toDelete := planMachinePoolInstances(tt.instances, tt.desired, tt.maxSurge)
+			g.Expect(create).To(Equal(tt.wantCreate))
+			var names []string
+			for _, instance := range toDelete {
+				names = append(names, instance.Name())
+			}
+			g.Expect(names).To(Equal(tt.wantDelete))
+		})
+	}
+}
+
+func Test_spreadOverFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(spreadOverFailureDomains(nil, nil, 3)).To(Equal(map[string]int{"": 3}))
+
+	instances := []poolInstance{
+		newPoolInstance("pool-a", infrav1.InstanceStateActive, true, "az1"),
+		newPoolInstance("pool-b", infrav1.InstanceStateActive, true, "az1"),
+		newPoolInstance("pool-c", infrav1.InstanceStateActive, true, "az3"),
+	}
+	g.Expect(spreadOverFailureDomains([]string{"az1", "az2", "az3"}, instances, 3)).To(Equal(map[string]int{"az2": 2, "az3": 1}))
+}
+
+func Test_machinePoolTemplateHash(t *testing.T) {
+	g := NewWithT(t)
+
+	machinePool := &expclusterv1.MachinePool{}
+	machinePool.Spec.Template.Spec.Version = pointer.String("v1.24.0")
+	machinePool.Spec.Template.Spec.Bootstrap.DataSecretName = pointer.String("bootstrap")
+	openStackMachinePool := &infrav1.OpenStackMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: infrav1.OpenStackMachinePoolSpec{
+			Template: infrav1.OpenStackMachineSpec{Flavor: flavorName, Image: imageName},
+		},
+	}
+
+	hash, err := machinePoolTemplateHash(machinePool, openStackMachinePool)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Scaling and the provider IDs do not replace instances
+	machinePool.Spec.Replicas = pointer.Int32(5)
+	openStackMachinePool.Spec.MaxSurge = pointer.Int32(2)
+	openStackMachinePool.Spec.ProviderIDList = []string{"openstack:///id"}
+	g.Expect(machinePoolTemplateHash(machinePool, openStackMachinePool)).To(Equal(hash))
+
+	// Server metadata and tags are updated in place
+	openStackMachinePool.Spec.Template.Tags = []string{"new-tag"}
+	openStackMachinePool.Spec.Template.ServerMetadata = map[string]string{"new-key": "new-value"}
+	g.Expect(machinePoolTemplateHash(machinePool, openStackMachinePool)).To(Equal(hash))
+
+	openStackMachinePool.Spec.Template.Flavor = "other-flavor"
+	g.Expect(machinePoolTemplateHash(machinePool, openStackMachinePool)).NotTo(Equal(hash))
+
+	openStackMachinePool.Spec.Template.Flavor = flavorName
+	machinePool.Spec.Template.Spec.Version = pointer.String("v1.25.0")
+	g.Expect(machinePoolTemplateHash(machinePool, openStackMachinePool)).NotTo(Equal(hash))
+}
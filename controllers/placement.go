@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+// reconcilePlacement records where the active instance of the machine was scheduled in its status, so that the
+// spread of the machines over hosts, availability zones and server groups can be verified. Failures to get the
+// placement are only logged and keep the previous placement, as it does not affect the instance.
+func reconcilePlacement(logger logr.Logger, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus) {
+	placement, err := computeService.GetInstancePlacement(instanceStatus)
+	if err != nil {
+		logger.Error(err, "Failed to get placement of instance", "instance-id", instanceStatus.ID())
+		return
+	}
+	openStackMachine.Status.Placement = placement
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// isPlanMode returns true if the PlanAnnotation is set on the cluster, in which case the OpenStack resources of the
+// cluster and its machines are not changed.
+func isPlanMode(openStackCluster *infrav1.OpenStackCluster) bool {
+	_, ok := openStackCluster.ObjectMeta.Annotations[infrav1.PlanAnnotation]
+	return ok
+}
+
+// reconcileClusterPlan records the operations the reconcile of the cluster would perform in its plan.
+func reconcileClusterPlan(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, now time.Time) error {
+	scope.Logger.Info("Planning Cluster")
+
+	operations, err := planCluster(scope, cluster, openStackCluster)
+	if err != nil {
+		return err
+	}
+	recordPlan(openStackCluster, &openStackCluster.Status.Plan, operations, now)
+	return nil
+}
+
+func planCluster(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) ([]infrav1.PlannedOperation, error) {
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	bastionName := fmt.Sprintf("%s-bastion", cluster.Name)
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var bastionSpec *infrav1.OpenStackMachineSpec
+	if openStackCluster.Spec.Bastion != nil {
+		bastionSpec = &openStackCluster.Spec.Bastion.Instance
+	}
+
+	if !openStackCluster.DeletionTimestamp.IsZero() {
+		var operations []infrav1.PlannedOperation
+		if bastionSpec != nil {
+			bastionOperations, err := computeService.PlanInstanceDelete(openStackCluster, bastionName, bastionSpec.RootVolume, machineBlockDevices(bastionSpec))
+			if err != nil {
+				return nil, err
+			}
+			operations = append(operations, bastionOperations...)
+		}
+
+		resources, err := listClusterResources(scope, networkingService, openStackCluster, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		resourceTypes := make([]string, 0, len(resources))
+		for resourceType := range resources {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+		sort.Strings(resourceTypes)
+		for _, resourceType := range resourceTypes {
+			for _, id := range resources[resourceType] {
+				operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionDelete, Resource: resourceType, ID: id})
+			}
+		}
+		return operations, nil
+	}
+
+	operations, err := networkingService.PlanNetworkComponents(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	lbSpec := openStackCluster.Spec.APIServerLoadBalancer
+	if lbSpec.Enabled || hasAPIServerLoadBalancerStatus(openStackCluster) {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			return nil, err
+		}
+		lbOperations, err := loadBalancerService.PlanLoadBalancer(openStackCluster, clusterName, getAPIServerPort(openStackCluster))
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, lbOperations...)
+	}
+
+	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() && !lbSpec.Enabled && !openStackCluster.Spec.ManagedAPIServerVIP && !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		floatingIP := openStackCluster.Spec.APIServerFloatingIP
+		if floatingIP == "" {
+			floatingIP = networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseAPIServer)
+		}
+		operation, err := networkingService.PlanFloatingIP(floatingIP, "floating IP of the API server")
+		if err != nil {
+			return nil, err
+		}
+		if operation != nil {
+			operations = append(operations, *operation)
+		}
+	}
+
+	if bastionSpec != nil && openStackCluster.Spec.Bastion.Enabled {
+		instanceSpec := bastionToInstanceSpec(openStackCluster, cluster.Name)
+		bastionHash, err := compute.HashInstanceSpec(instanceSpec)
+		if err != nil {
+			return nil, err
+		}
+		bastionOperations, err := computeService.PlanInstance(openStackCluster, instanceSpec)
+		if err != nil {
+			return nil, err
+		}
+		if len(bastionOperations) == 0 && bastionHashHasChanged(bastionHash, openStackCluster.ObjectMeta.Annotations) {
+			// The bastion is recreated with the changed spec
+			bastionOperations, err = computeService.PlanInstanceDelete(openStackCluster, bastionName, bastionSpec.RootVolume, machineBlockDevices(bastionSpec))
+			if err != nil {
+				return nil, err
+			}
+			bastionOperations = append(bastionOperations, infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: compute.ServerResource, Name: bastionName, Message: "bastion spec changed"})
+		}
+		operations = append(operations, bastionOperations...)
+	} else if bastionSpec != nil {
+		bastionOperations, err := computeService.PlanInstanceDelete(openStackCluster, bastionName, bastionSpec.RootVolume, machineBlockDevices(bastionSpec))
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, bastionOperations...)
+	}
+
+	return operations, nil
+}
+
+// reconcileMachinePlan records the operations the reconcile of the machine would perform in its plan.
+func reconcileMachinePlan(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, now time.Time) error {
+	scope.Logger.Info("Planning Machine")
+
+	operations, err := planMachine(scope, openStackCluster, machine, openStackMachine)
+	if err != nil {
+		return err
+	}
+	recordPlan(openStackMachine, &openStackMachine.Status.Plan, operations, now)
+	return nil
+}
+
+func planMachine(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) ([]infrav1.PlannedOperation, error) {
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if !openStackMachine.DeletionTimestamp.IsZero() {
+		return computeService.PlanInstanceDelete(openStackMachine, openStackMachine.Name, openStackMachine.Spec.RootVolume, machineBlockDevices(&openStackMachine.Spec))
+	}
+
+	if instanceID, adopt := serverToAdopt(openStackMachine); adopt {
+		instanceStatus, err := computeService.GetInstanceStatusByName(openStackMachine, openStackMachine.Name)
+		if err != nil || instanceStatus != nil {
+			return nil, err
+		}
+		return []infrav1.PlannedOperation{{Action: infrav1.PlannedActionUpdate, Resource: compute.ServerResource, Name: openStackMachine.Name, ID: instanceID, Message: "existing server is adopted"}}, nil
+	}
+
+	// The bootstrap data is not needed to plan the instance
+	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, "")
+	if err != nil {
+		return nil, err
+	}
+	return computeService.PlanInstance(openStackMachine, instanceSpec)
+}
+
+// recordPlan records the planned operations in the plan and the OpenStackResourcesUpToDate condition of obj. The
+// operations are reported by events when they change.
+func recordPlan(obj conditions.Setter, plan **infrav1.Plan, operations []infrav1.PlannedOperation, now time.Time) {
+	if *plan != nil && equality.Semantic.DeepEqual((*plan).Operations, operations) {
+		return
+	}
+
+	*plan = &infrav1.Plan{
+		Time:       metav1.NewTime(now),
+		Operations: operations,
+	}
+	if len(operations) == 0 {
+		conditions.MarkTrue(obj, infrav1.OpenStackResourcesUpToDateCondition)
+		return
+	}
+
+	descriptions := make([]string, 0, len(operations))
+	for i := range operations {
+		description := describeOperation(&operations[i])
+		record.Eventf(obj, "PlannedOperation", "Would %s", description)
+		descriptions = append(descriptions, description)
+	}
+	conditions.MarkFalse(obj, infrav1.OpenStackResourcesUpToDateCondition, infrav1.ChangesPlannedReason, clusterv1.ConditionSeverityInfo, "Planned operations: %s", strings.Join(descriptions, "; "))
+}
+
+// clearPlan removes the plan and the OpenStackResourcesUpToDate condition of obj once it is no longer in plan mode.
+func clearPlan(obj conditions.Setter, plan **infrav1.Plan) {
+	*plan = nil
+	conditions.Delete(obj, infrav1.OpenStackResourcesUpToDateCondition)
+}
+
+// describeOperation returns a short description of a planned operation, e.g. "create network k8s-clusterapi-cluster-default-test".
+func describeOperation(operation *infrav1.PlannedOperation) string {
+	description := fmt.Sprintf("%s %s", strings.ToLower(string(operation.Action)), operation.Resource)
+	if operation.Name != "" {
+		description += " " + operation.Name
+	}
+	if operation.ID != "" {
+		description += fmt.Sprintf(" (id %s)", operation.ID)
+	}
+	if operation.Message != "" {
+		description += ": " + operation.Message
+	}
+	return description
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_recordPlan(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	createNetwork := infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: "network", Name: "k8s-clusterapi-cluster-default-test"}
+	deleteServer := infrav1.PlannedOperation{Action: infrav1.PlannedActionDelete, Resource: "server", Name: "test-bastion", ID: "server-id"}
+
+	tests := []struct {
+		name          string
+		plan          *infrav1.Plan
+		operations    []infrav1.PlannedOperation
+		wantTime      metav1.Time
+		wantStatus    corev1.ConditionStatus
+		wantMessage   string
+		wantCondition bool
+	}{
+		{
+			name:          "No operations mark the resources up to date",
+			wantTime:      metav1.NewTime(now),
+			wantStatus:    corev1.ConditionTrue,
+			wantCondition: true,
+		},
+		{
+			name:          "Operations are listed in the condition",
+			operations:    []infrav1.PlannedOperation{createNetwork, deleteServer},
+			wantTime:      metav1.NewTime(now),
+			wantStatus:    corev1.ConditionFalse,
+			wantMessage:   "Planned operations: create network k8s-clusterapi-cluster-default-test; delete server test-bastion (id server-id)",
+			wantCondition: true,
+		},
+		{
+			name:       "Unchanged operations keep the time of the plan",
+			plan:       &infrav1.Plan{Time: earlier, Operations: []infrav1.PlannedOperation{createNetwork}},
+			operations: []infrav1.PlannedOperation{createNetwork},
+			wantTime:   earlier,
+		},
+		{
+			name:          "Changed operations update the time of the plan",
+			plan:          &infrav1.Plan{Time: earlier, Operations: []infrav1.PlannedOperation{createNetwork}},
+			operations:    []infrav1.PlannedOperation{deleteServer},
+			wantTime:      metav1.NewTime(now),
+			wantStatus:    corev1.ConditionFalse,
+			wantMessage:   "Planned operations: delete server test-bastion (id server-id)",
+			wantCondition: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := &infrav1.OpenStackCluster{}
+			openStackCluster.Status.Plan = tt.plan
+
+			recordPlan(openStackCluster, &openStackCluster.Status.Plan, tt.operations, now)
+
+			g.Expect(openStackCluster.Status.Plan).NotTo(BeNil())
+			g.Expect(openStackCluster.Status.Plan.Time).To(Equal(tt.wantTime))
+			g.Expect(openStackCluster.Status.Plan.Operations).To(Equal(tt.operations))
+
+			condition := conditions.Get(openStackCluster, infrav1.OpenStackResourcesUpToDateCondition)
+			if !tt.wantCondition {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tt.wantStatus))
+			g.Expect(condition.Message).To(Equal(tt.wantMessage))
+			if tt.wantStatus == corev1.ConditionFalse {
+				g.Expect(condition.Reason).To(Equal(infrav1.ChangesPlannedReason))
+				g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+			}
+		})
+	}
+}
+
+func Test_clearPlan(t *testing.T) {
+	g := NewWithT(t)
+	openStackCluster := &infrav1.OpenStackCluster{}
+	recordPlan(openStackCluster, &openStackCluster.Status.Plan, nil, time.Now())
+
+	clearPlan(openStackCluster, &openStackCluster.Status.Plan)
+
+	g.Expect(openStackCluster.Status.Plan).To(BeNil())
+	g.Expect(conditions.Has(openStackCluster, infrav1.OpenStackResourcesUpToDateCondition)).To(BeFalse())
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// defaultRemediationTimeout is how long the node of a machine must be unhealthy before it is remediated in place,
+// and how long each remediation step is given, if the machine does not set a timeout.
+const defaultRemediationTimeout = 5 * time.Minute
+
+// instanceRemediator takes the steps of the in-place remediation of an instance. It is implemented by compute.Service.
+type instanceRemediator interface {
+	RebootInstance(eventObject runtime.Object, instanceStatus *compute.InstanceStatus) error
+	RebuildInstance(eventObject runtime.Object, instanceStatus *compute.InstanceStatus, imageID string) error
+}
+
+// reconcileRemediation remediates a machine with the InPlace remediation strategy whose node is not healthy. Once the
+// node has been unhealthy for the remediation timeout, the server is hard rebooted. If the node is still unhealthy
+// after another timeout, the server is rebuilt with its image, and after a third timeout the machine is marked for
+// replacement by its owner. Servers booted from volume and control plane machines, whose etcd member would be lost,
+// are not rebuilt. It returns when the machine should be checked again.
+func reconcileRemediation(ctx context.Context, c client.Client, logger logr.Logger, remediator instanceRemediator, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus, now time.Time) (time.Duration, error) {
+	remediation := openStackMachine.Spec.Remediation
+	if remediation == nil || remediation.Strategy != infrav1.RemediationStrategyInPlace || conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return 0, nil
+	}
+	timeout := defaultRemediationTimeout
+	if remediation.Timeout != nil {
+		timeout = remediation.Timeout.Duration
+	}
+
+	status := openStackMachine.Status.Remediation
+	nodeHealthy := conditions.Get(machine, clusterv1.MachineNodeHealthyCondition)
+	if machine.Status.NodeRef == nil || nodeHealthy == nil || nodeHealthy.Status != corev1.ConditionFalse || nodeHealthy.Reason != clusterv1.NodeConditionsFailedReason {
+		if status != nil && conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition) {
+			logger.Info("Node of the machine is healthy again", "remediationStep", status.Step)
+			record.Eventf(openStackMachine, "MachineRemediated", "Node of machine %s is healthy after %s", machine.Name, status.Step)
+			openStackMachine.Status.Remediation = nil
+		}
+		return 0, nil
+	}
+
+	// Each step is taken once the node has been unhealthy for the timeout since the previous one
+	since := nodeHealthy.LastTransitionTime.Time
+	if status != nil && status.LastStepTime.After(since) {
+		since = status.LastStepTime.Time
+	}
+	if wait := since.Add(timeout).Sub(now); wait > 0 {
+		return wait, nil
+	}
+
+	step := nextRemediationStep(status, machine, openStackMachine)
+	logger.Info("Remediating machine whose node is not healthy", "remediationStep", step, "reason", nodeHealthy.Message)
+	switch step {
+	case infrav1.RemediationStepReboot:
+		if err := remediator.RebootInstance(openStackMachine, instanceStatus); err != nil {
+			return 0, errors.Wrapf(err, "failed to reboot OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+	case infrav1.RemediationStepRebuild:
+		if err := remediator.RebuildInstance(openStackMachine, instanceStatus, openStackMachine.Status.ImageID); err != nil {
+			return 0, errors.Wrapf(err, "failed to rebuild OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+	case infrav1.RemediationStepReplace:
+		if err := markMachineForReplacement(ctx, c, machine, infrav1.InPlaceRemediationFailedReason, "Node is not healthy after in-place remediation"); err != nil {
+			return 0, err
+		}
+		record.Warnf(openStackMachine, "InPlaceRemediationFailed", "Node of machine %s is not healthy after in-place remediation, machine was marked for replacement", machine.Name)
+	}
+	openStackMachine.Status.Remediation = &infrav1.MachineRemediationStatus{
+		Step:         step,
+		LastStepTime: metav1.NewTime(now),
+	}
+	if step == infrav1.RemediationStepReplace {
+		return 0, nil
+	}
+	return timeout, nil
+}
+
+// nextRemediationStep returns the step which follows the last remediation step of a machine.
+func nextRemediationStep(status *infrav1.MachineRemediationStatus, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) infrav1.RemediationStep {
+	if status == nil {
+		return infrav1.RemediationStepReboot
+	}
+	rootVolume := openStackMachine.Spec.RootVolume
+	if status.Step == infrav1.RemediationStepReboot && openStackMachine.Status.ImageID != "" && (rootVolume == nil || rootVolume.Size == 0) && !util.IsControlPlaneMachine(machine) {
+		return infrav1.RemediationStepRebuild
+	}
+	return infrav1.RemediationStepReplace
+}
+
+// markMachineForReplacement sets the OwnerRemediated condition of a machine to false, which makes the MachineSet
+// of the machine replace it.
+func markMachineForReplacement(ctx context.Context, c client.Client, machine *clusterv1.Machine, reason string, messageFormat string, messageArgs ...interface{}) error {
+	machinePatchHelper, err := patch.NewHelper(machine, c)
+	if err != nil {
+		return err
+	}
+	conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, reason, clusterv1.ConditionSeverityWarning, messageFormat, messageArgs...)
+	if err := machinePatchHelper.Patch(ctx, machine, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{clusterv1.MachineOwnerRemediatedCondition}}); err != nil {
+		return errors.Wrapf(err, "failed to mark machine %s for replacement", machine.Name)
+	}
+	return nil
+}
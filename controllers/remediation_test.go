@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+type fakeRemediator struct {
+	steps []infrav1.RemediationStep
+	err   error
+}
+
+func (f *fakeRemediator) RebootInstance(_ runtime.Object, _ *compute.InstanceStatus) error {
+	f.steps = append(f.steps, infrav1.RemediationStepReboot)
+	return f.err
+}
+
+func (f *fakeRemediator) RebuildInstance(_ runtime.Object, _ *compute.InstanceStatus, imageID string) error {
+	f.steps = append(f.steps, infrav1.RemediationStepRebuild)
+	return f.err
+}
+
+func Test_reconcileRemediation(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	newMachine := func(unhealthyFor time.Duration) *clusterv1.Machine {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test"},
+			Status:     clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "machine"}},
+		}
+		if unhealthyFor == 0 {
+			conditions.MarkTrue(machine, clusterv1.MachineNodeHealthyCondition)
+		} else {
+			conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeConditionsFailedReason, clusterv1.ConditionSeverityWarning, "Node condition Ready is Unknown")
+		}
+		machine.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-unhealthyFor))
+		return machine
+	}
+	controlPlane := func(m *clusterv1.Machine) *clusterv1.Machine {
+		m.Labels = map[string]string{clusterv1.MachineControlPlaneLabelName: ""}
+		return m
+	}
+	newOpenStackMachine := func(strategy infrav1.RemediationStrategy, status *infrav1.MachineRemediationStatus) *infrav1.OpenStackMachine {
+		return &infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test"},
+			Spec: infrav1.OpenStackMachineSpec{
+				Remediation: &infrav1.MachineRemediation{Strategy: strategy, Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			},
+			Status: infrav1.OpenStackMachineStatus{ImageID: "image-id", Remediation: status},
+		}
+	}
+	stepAgo := func(step infrav1.RemediationStep, ago time.Duration) *infrav1.MachineRemediationStatus {
+		return &infrav1.MachineRemediationStatus{Step: step, LastStepTime: metav1.NewTime(now.Add(-ago))}
+	}
+
+	tests := []struct {
+		name             string
+		machine          *clusterv1.Machine
+		openStackMachine *infrav1.OpenStackMachine
+		remediatorErr    error
+		wantRequeueAfter time.Duration
+		wantSteps        []infrav1.RemediationStep
+		wantStatus       *infrav1.RemediationStep
+		wantMarked       bool
+		wantErr          bool
+	}{
+		{
+			name:             "Replace strategy",
+			machine:          newMachine(time.Hour),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyReplace, nil),
+		},
+		{
+			name:             "Node healthy",
+			machine:          newMachine(0),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, nil),
+		},
+		{
+			name:             "Node healthy after reboot",
+			machine:          newMachine(0),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepReboot, 5*time.Minute)),
+		},
+		{
+			name:             "Node unhealthy for less than the timeout",
+			machine:          newMachine(4 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, nil),
+			wantRequeueAfter: 6 * time.Minute,
+		},
+		{
+			name:             "Node unhealthy for longer than the timeout",
+			machine:          newMachine(15 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, nil),
+			wantRequeueAfter: 10 * time.Minute,
+			wantSteps:        []infrav1.RemediationStep{infrav1.RemediationStepReboot},
+			wantStatus:       stepPtr(infrav1.RemediationStepReboot),
+		},
+		{
+			name:             "Reboot fails",
+			machine:          newMachine(15 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, nil),
+			remediatorErr:    errors.New("Conflict"),
+			wantSteps:        []infrav1.RemediationStep{infrav1.RemediationStepReboot},
+			wantErr:          true,
+		},
+		{
+			name:             "Waiting after reboot",
+			machine:          newMachine(20 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepReboot, 8*time.Minute)),
+			wantRequeueAfter: 2 * time.Minute,
+			wantStatus:       stepPtr(infrav1.RemediationStepReboot),
+		},
+		{
+			name:             "Node unhealthy after reboot",
+			machine:          newMachine(30 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepReboot, 15*time.Minute)),
+			wantRequeueAfter: 10 * time.Minute,
+			wantSteps:        []infrav1.RemediationStep{infrav1.RemediationStepRebuild},
+			wantStatus:       stepPtr(infrav1.RemediationStepRebuild),
+		},
+		{
+			name:    "Server booted from volume is not rebuilt",
+			machine: newMachine(30 * time.Minute),
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepReboot, 15*time.Minute))
+				m.Spec.RootVolume = &infrav1.RootVolume{Size: 50}
+				return m
+			}(),
+			wantStatus: stepPtr(infrav1.RemediationStepReplace),
+			wantMarked: true,
+		},
+		{
+			name:             "Control plane machine is not rebuilt",
+			machine:          controlPlane(newMachine(30 * time.Minute)),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepReboot, 15*time.Minute)),
+			wantStatus:       stepPtr(infrav1.RemediationStepReplace),
+			wantMarked:       true,
+		},
+		{
+			name:             "Node unhealthy after rebuild",
+			machine:          newMachine(45 * time.Minute),
+			openStackMachine: newOpenStackMachine(infrav1.RemediationStrategyInPlace, stepAgo(infrav1.RemediationStepRebuild, 15*time.Minute)),
+			wantStatus:       stepPtr(infrav1.RemediationStepReplace),
+			wantMarked:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.machine).Build()
+
+			remediator := &fakeRemediator{err: tt.remediatorErr}
+			instanceStatus := compute.NewInstanceStatusFromServer(&clients.ServerExt{Server: servers.Server{ID: "server-id", Name: "machine"}}, logr.Discard())
+
+			requeueAfter, err := reconcileRemediation(context.TODO(), c, logr.Discard(), remediator, tt.machine, tt.openStackMachine, instanceStatus, now)
+			g.Expect(remediator.steps).To(Equal(tt.wantSteps))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(requeueAfter).To(Equal(tt.wantRequeueAfter))
+
+			if tt.wantStatus == nil {
+				g.Expect(tt.openStackMachine.Status.Remediation).To(BeNil())
+			} else {
+				g.Expect(tt.openStackMachine.Status.Remediation).NotTo(BeNil())
+				g.Expect(tt.openStackMachine.Status.Remediation.Step).To(Equal(*tt.wantStatus))
+			}
+
+			got := &clusterv1.Machine{}
+			g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(tt.machine), got)).To(Succeed())
+			g.Expect(conditions.IsFalse(got, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(tt.wantMarked))
+		})
+	}
+}
+
+func stepPtr(step infrav1.RemediationStep) *infrav1.RemediationStep {
+	return &step
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// reconcileServerGroupMembership reports whether the instance of the machine is still a member of its server
+// group and honours its policy. It is run on every reconcile of an active machine, so a violation caused by an
+// admin operation like an evacuation is detected within the sync period. Failures to check are only logged,
+// as the check does not affect the instance.
+func reconcileServerGroupMembership(logger logr.Logger, computeService *compute.Service, clusterName string, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus) {
+	serverGroupID := openStackMachine.Spec.ServerGroupID
+	serverGroupName := ""
+	if openStackMachine.Spec.ServerGroup != nil {
+		serverGroupName = compute.ServerGroupName(clusterName, serverGroupOwner(machine))
+	}
+	if serverGroupID == "" && serverGroupName == "" {
+		return
+	}
+
+	membership, err := computeService.CheckServerGroupMembership(instanceStatus, serverGroupID, serverGroupName)
+	if err != nil {
+		logger.Error(err, "Failed to check server group membership", "instance-id", instanceStatus.ID())
+		return
+	}
+	if membership == nil {
+		logger.Info("Server group of machine does not exist, not checking membership", "id", serverGroupID, "name", serverGroupName)
+		return
+	}
+
+	metrics.SetServerGroupViolation(openStackMachine.Namespace, openStackMachine.Name, membership.Violated())
+
+	// Only a new violation is reported by an event, the condition records it until it is resolved
+	previousReason := conditions.GetReason(openStackMachine, infrav1.ServerGroupReadyCondition)
+
+	var reason string
+	switch {
+	case !membership.Member:
+		reason = infrav1.NotServerGroupMemberReason
+		conditions.MarkFalse(openStackMachine, infrav1.ServerGroupReadyCondition, reason, clusterv1.ConditionSeverityWarning, "Instance %s is not a member of server group %s", instanceStatus.ID(), membership.ServerGroupID)
+	case len(membership.ViolatingMembers) > 0:
+		reason = infrav1.ServerGroupPolicyViolatedReason
+		conditions.MarkFalse(openStackMachine, infrav1.ServerGroupReadyCondition, reason, clusterv1.ConditionSeverityWarning, "Policy %s of server group %s is violated together with instances %v", membership.Policy, membership.ServerGroupID, membership.ViolatingMembers)
+	default:
+		conditions.MarkTrue(openStackMachine, infrav1.ServerGroupReadyCondition)
+		return
+	}
+
+	if previousReason == reason {
+		return
+	}
+	record.Warnf(openStackMachine, "ServerGroupViolated", "%s", conditions.GetMessage(openStackMachine, infrav1.ServerGroupReadyCondition))
+}
+
+// serverGroupInUse returns whether another OpenStackMachine of the cluster which is not being deleted shares the
+// managed server group of the given machine. Such a machine may have resolved the server group without having
+// created its server yet, in which case it is not yet a member of the group.
+func serverGroupInUse(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (bool, error) {
+	openStackMachines := &infrav1.OpenStackMachineList{}
+	if err := c.List(ctx, openStackMachines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return false, err
+	}
+
+	owner := serverGroupOwner(machine)
+	for i := range openStackMachines.Items {
+		other := &openStackMachines.Items[i]
+		if other.UID == openStackMachine.UID || !other.DeletionTimestamp.IsZero() || other.Spec.ServerGroup == nil {
+			continue
+		}
+		if serverGroupOwner(other) == owner {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_serverGroupInUse(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "default"}}
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{
+		Name:      "md-0-abcde",
+		Namespace: "default",
+		Labels:    map[string]string{clusterv1.MachineDeploymentLabelName: "md-0"},
+	}}
+	openStackMachine := func(name, cluster, deployment string, serverGroup bool) *infrav1.OpenStackMachine {
+		m := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Labels:    map[string]string{clusterv1.ClusterLabelName: cluster, clusterv1.MachineDeploymentLabelName: deployment},
+		}}
+		if serverGroup {
+			m.Spec.ServerGroup = &infrav1.ServerGroup{Policy: infrav1.ServerGroupPolicyAntiAffinity}
+		}
+		return m
+	}
+	deleting := func(m *infrav1.OpenStackMachine) *infrav1.OpenStackMachine {
+		now := metav1.Now()
+		m.DeletionTimestamp = &now
+		m.Finalizers = []string{infrav1.MachineFinalizer}
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		want    bool
+	}{
+		{
+			name:    "Last machine of the group",
+			objects: []client.Object{deleting(openStackMachine("md-0-abcde", "cluster-a", "md-0", true))},
+		},
+		{
+			name: "Sibling without server",
+			objects: []client.Object{
+				deleting(openStackMachine("md-0-abcde", "cluster-a", "md-0", true)),
+				openStackMachine("md-0-fghij", "cluster-a", "md-0", true),
+			},
+			want: true,
+		},
+		{
+			name: "Sibling being deleted",
+			objects: []client.Object{
+				deleting(openStackMachine("md-0-abcde", "cluster-a", "md-0", true)),
+				deleting(openStackMachine("md-0-fghij", "cluster-a", "md-0", true)),
+			},
+		},
+		{
+			name: "Machines of other groups",
+			objects: []client.Object{
+				deleting(openStackMachine("md-0-abcde", "cluster-a", "md-0", true)),
+				openStackMachine("md-1-fghij", "cluster-a", "md-1", true),
+				openStackMachine("md-0-klmno", "cluster-b", "md-0", true),
+				openStackMachine("md-0-pqrst", "cluster-a", "md-0", false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			got, err := serverGroupInUse(context.TODO(), c, cluster, machine, tt.objects[0].(*infrav1.OpenStackMachine))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+const (
+	defaultSSHPrivateKeySecretKey = "ssh-privatekey"
+	serverPasswordSecretKey       = "password"
+)
+
+// serverPasswordSecretName returns the name of the secret the password of the machine is published to.
+func serverPasswordSecretName(openStackMachine *infrav1.OpenStackMachine) string {
+	return fmt.Sprintf("%s-password", openStackMachine.Name)
+}
+
+// reconcileServerPassword publishes the admin password of the server to a secret if requested
+// by the machine. It returns false if the server has not posted a password yet.
+func reconcileServerPassword(ctx context.Context, c client.Client, computeService *compute.Service, cluster *clusterv1.Cluster, openStackMachine *infrav1.OpenStackMachine, instanceStatus *compute.InstanceStatus) (bool, error) {
+	opts := openStackMachine.Spec.ServerPassword
+	if opts == nil {
+		return true, nil
+	}
+
+	secretName := serverPasswordSecretName(openStackMachine)
+	err := c.Get(ctx, types.NamespacedName{Namespace: openStackMachine.Namespace, Name: secretName}, &corev1.Secret{})
+	if err == nil {
+		// The password has already been published
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to get server password secret %s", secretName)
+	}
+
+	privateKey, err := getSSHPrivateKey(ctx, c, openStackMachine)
+	if err != nil {
+		return false, err
+	}
+	password, err := computeService.GetServerPassword(instanceStatus, privateKey)
+	if err != nil {
+		return false, err
+	}
+	if password == "" {
+		return false, nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: openStackMachine.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       "OpenStackMachine",
+					Name:       openStackMachine.Name,
+					UID:        openStackMachine.UID,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{serverPasswordSecretKey: []byte(password)},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		record.Warnf(openStackMachine, "FailedPublishServerPassword", "Failed to publish password of server %s to secret %s: %v", instanceStatus.Name(), secretName, err)
+		return false, errors.Wrapf(err, "failed to create server password secret %s", secretName)
+	}
+	record.Eventf(openStackMachine, "SuccessfulPublishServerPassword", "Published password of server %s to secret %s", instanceStatus.Name(), secretName)
+
+	if opts.ClearAfterRetrieval {
+		if err := computeService.ClearServerPassword(openStackMachine, instanceStatus); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// getSSHPrivateKey returns the RSA private key referenced by the server password options of the machine.
+func getSSHPrivateKey(ctx context.Context, c client.Client, openStackMachine *infrav1.OpenStackMachine) (*rsa.PrivateKey, error) {
+	ref := openStackMachine.Spec.ServerPassword.PrivateKeySecretRef
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: openStackMachine.Namespace, Name: ref.Name}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve SSH private key secret for Openstack Machine %s/%s", openStackMachine.Namespace, openStackMachine.Name)
+	}
+
+	dataKey := ref.Key
+	if dataKey == "" {
+		dataKey = defaultSSHPrivateKeySecretKey
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return nil, errors.Errorf("error retrieving SSH private key: secret %s has no key %s", ref.Name, dataKey)
+	}
+
+	parsed, err := ssh.ParseRawPrivateKey(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing SSH private key in secret %s", ref.Name)
+	}
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("SSH private key in secret %s is a %T, but server passwords can only be decrypted with RSA keys", ref.Name, parsed)
+	}
+	return privateKey, nil
+}
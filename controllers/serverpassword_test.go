@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_getSSHPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519DER, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ed25519DER})
+
+	tests := []struct {
+		name    string
+		ref     infrav1.SSHPrivateKeySecretReference
+		data    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "RSA key with default key",
+			ref:  infrav1.SSHPrivateKeySecretReference{Name: "keypair"},
+			data: map[string][]byte{"ssh-privatekey": rsaPEM},
+		},
+		{
+			name: "RSA key with custom key",
+			ref:  infrav1.SSHPrivateKeySecretReference{Name: "keypair", Key: "id_rsa"},
+			data: map[string][]byte{"id_rsa": rsaPEM},
+		},
+		{
+			name:    "Missing key",
+			ref:     infrav1.SSHPrivateKeySecretReference{Name: "keypair"},
+			data:    map[string][]byte{"id_rsa": rsaPEM},
+			wantErr: true,
+		},
+		{
+			name:    "Not an RSA key",
+			ref:     infrav1.SSHPrivateKeySecretReference{Name: "keypair"},
+			data:    map[string][]byte{"ssh-privatekey": ed25519PEM},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "keypair", Namespace: "test"},
+				Data:       tt.data,
+			}).Build()
+			openStackMachine := &infrav1.OpenStackMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "test"},
+				Spec: infrav1.OpenStackMachineSpec{
+					ServerPassword: &infrav1.ServerPasswordOptions{PrivateKeySecretRef: tt.ref},
+				},
+			}
+
+			got, err := getSSHPrivateKey(context.TODO(), c, openStackMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.Equal(rsaKey)).To(BeTrue())
+		})
+	}
+}
+
+func Test_reconcileServerPasswordPublished(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "test"},
+		Spec: infrav1.OpenStackMachineSpec{
+			ServerPassword: &infrav1.ServerPasswordOptions{
+				PrivateKeySecretRef: infrav1.SSHPrivateKeySecretReference{Name: "keypair"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: serverPasswordSecretName(openStackMachine), Namespace: "test"},
+	}).Build()
+
+	// OpenStack is not queried again once the password has been published
+	published, err := reconcileServerPassword(context.TODO(), c, nil, &clusterv1.Cluster{}, openStackMachine, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(published).To(BeTrue())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// ensureStorageVersion rewrites an object which has not been written in the current API version since
+// the upgrade of CAPO. Objects stored in an older API version are only converted when read. The rewrite
+// is a patch of the StorageVersionAnnotation: the API server converts the object and persists it in the
+// storage version. Fields which the older version cannot represent are restored by the conversion from
+// the conversion-data annotation, if the object has one. It returns true if the object was rewritten, in
+// which case the caller should requeue instead of acting on the object it read.
+func ensureStorageVersion(ctx context.Context, log logr.Logger, c client.Client, obj client.Object) (bool, error) {
+	if obj.GetAnnotations()[infrav1.StorageVersionAnnotation] == infrav1.GroupVersion.Version {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	previous := annotations[infrav1.StorageVersionAnnotation]
+	annotations[infrav1.StorageVersionAnnotation] = infrav1.GroupVersion.Version
+	obj.SetAnnotations(annotations)
+
+	log.Info("Rewriting object in the current API version before reconciling it", "previousVersion", previous, "version", infrav1.GroupVersion.Version)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
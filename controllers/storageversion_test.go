@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_ensureStorageVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantRewritten bool
+	}{
+		{
+			name:          "object without annotations is rewritten",
+			wantRewritten: true,
+		},
+		{
+			name:          "object written in an older version is rewritten",
+			annotations:   map[string]string{infrav1.StorageVersionAnnotation: "v1alpha5", "foo": "bar"},
+			wantRewritten: true,
+		},
+		{
+			name:          "object written in the current version is left alone",
+			annotations:   map[string]string{infrav1.StorageVersionAnnotation: infrav1.GroupVersion.Version},
+			wantRewritten: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.TODO()
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			openStackCluster := &infrav1.OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "test", Annotations: tt.annotations},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(openStackCluster).Build()
+
+			rewritten, err := ensureStorageVersion(ctx, logr.Discard(), c, openStackCluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rewritten).To(Equal(tt.wantRewritten))
+
+			stored := &infrav1.OpenStackCluster{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(openStackCluster), stored)).To(Succeed())
+			g.Expect(stored.Annotations).To(HaveKeyWithValue(infrav1.StorageVersionAnnotation, infrav1.GroupVersion.Version))
+			for k, v := range tt.annotations {
+				if k != infrav1.StorageVersionAnnotation {
+					g.Expect(stored.Annotations).To(HaveKeyWithValue(k, v))
+				}
+			}
+		})
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -33,18 +35,33 @@ import (
 	_ "k8s.io/component-base/logs/json/register"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	infrav1alpha5 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha5"
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/controllers"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/apievents"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/apilog"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/batch"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/budget"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/egress"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/ratelimit"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/tlsconfig"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/webhooks"
 	"sigs.k8s.io/cluster-api-provider-openstack/version"
 )
 
@@ -63,17 +80,43 @@ var (
 	profilerAddress             string
 	openStackClusterConcurrency int
 	openStackMachineConcurrency int
+	enableMachinePools          bool
 	syncPeriod                  time.Duration
 	webhookPort                 int
 	webhookCertDir              string
 	healthAddr                  string
 	lbProvider                  string
+	deleteConcurrency           int
+	deleteQPS                   float32
+	deleteBurst                 int
+	retryConfig                 string
+	flavorAliasesConfig         string
+	tlsMinVersion               string
+	tlsCipherSuites             []string
+	egressIPProbeURL            string
+	egressIPRefreshInterval     time.Duration
+	instanceActionsInterval     time.Duration
+	apiBudget                   int
+	apiBudgetPeriod             time.Duration
+	openStackQPS                float32
+	openStackBurst              int
+	openStackServiceQPS         map[string]string
+	lookupCacheTTL              time.Duration
+	openStackAPIDebug           bool
+	openStackAPIEvents          bool
+	openStackRequestTimeout     time.Duration
+	validateOpenStackResources  bool
+	cleanupVerification         string
+	namespacePolicyConfig       string
+	namespacePolicies           []webhooks.NamespacePolicy
 	logOptions                  = logs.NewOptions()
 )
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = expclusterv1.AddToScheme(scheme)
+	_ = ipamv1.AddToScheme(scheme)
 	_ = infrav1.AddToScheme(scheme)
 	_ = infrav1alpha3.AddToScheme(scheme)
 	_ = infrav1alpha4.AddToScheme(scheme)
@@ -81,6 +124,14 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 
 	metrics.RegisterAPIPrometheusMetrics()
+	metrics.RegisterDeletionPrometheusMetrics()
+	metrics.RegisterInventoryPrometheusMetrics()
+	metrics.RegisterServerGroupPrometheusMetrics()
+	metrics.RegisterClusterAPIPrometheusMetrics()
+	metrics.RegisterAPIRetryPrometheusMetrics()
+	metrics.RegisterReauthPrometheusMetrics()
+	metrics.RegisterReconcilePrometheusMetrics()
+	metrics.RegisterLeftoverPrometheusMetrics()
 }
 
 // InitFlags initializes the flags.
@@ -118,6 +169,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&openStackMachineConcurrency, "openstackmachine-concurrency", 10,
 		"Number of OpenStackMachines to process simultaneously")
 
+	fs.BoolVar(&enableMachinePools, "enable-machine-pools", false,
+		"Enable the experimental OpenStackMachinePool controller")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -132,6 +186,88 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&lbProvider, "lb-provider", "amphora",
 		"The name of the load balancer provider (amphora or ovn) to use (defaults to amphora).")
+
+	fs.IntVar(&deleteConcurrency, "delete-concurrency", batch.DefaultDeleteConcurrency,
+		"Number of OpenStack resources of a cluster or machine to delete in parallel")
+
+	fs.Float32Var(&deleteQPS, "delete-qps", batch.DefaultDeleteQPS,
+		"Maximum number of OpenStack delete calls per second, shared by all reconciles")
+
+	fs.IntVar(&deleteBurst, "delete-burst", batch.DefaultDeleteBurst,
+		"Maximum burst of OpenStack delete calls, shared by all reconciles")
+
+	fs.StringVar(&retryConfig, "retry-config", "",
+		"Path to a YAML file with retry counts, backoff parameters and timeouts for waiting on resources of each OpenStack service")
+
+	fs.StringVar(&flavorAliasesConfig, "flavor-aliases-config", "",
+		"Path to a YAML file which maps flavor names used in templates to the flavors of each cloud")
+
+	fs.StringVar(&namespacePolicyConfig, "namespace-policy-config", "",
+		"Path to a YAML file which restricts the external networks, flavors and images that clusters and machines "+
+			"in given namespaces may use")
+
+	fs.StringVar(&tlsMinVersion, "tls-min-version", "VersionTLS12",
+		"Minimum TLS version of connections to OpenStack endpoints and of the webhook server. "+
+			"Possible values: "+strings.Join(cliflag.TLSPossibleVersions(), ", "))
+
+	fs.StringSliceVar(&tlsCipherSuites, "tls-cipher-suites", []string{},
+		"Comma-separated list of cipher suites for connections to OpenStack endpoints and the webhook server. "+
+			"If omitted, the default Go cipher suites will be used. Cipher suites cannot be configured for TLS 1.3. "+
+			"Possible values: "+strings.Join(cliflag.TLSCipherPossibleValues(), ", "))
+
+	fs.StringVar(&egressIPProbeURL, "egress-ip-probe-url", "",
+		"URL of a service which returns the public egress IP of the controller as plain text (e.g. https://api.ipify.org). "+
+			"If set, the address is always allowed to access API server load balancers with allowed CIDRs.")
+
+	fs.DurationVar(&egressIPRefreshInterval, "egress-ip-refresh-interval", egress.DefaultRefreshInterval,
+		"How often the egress IP of the controller is probed again (e.g. 10m)")
+
+	fs.DurationVar(&instanceActionsInterval, "instance-actions-audit-interval", 30*time.Minute,
+		"Minimum interval between checks of the Nova instance actions of a machine for actions performed outside of CAPO, "+
+			"which are reported with an event. Machines are checked when they are reconciled, so at least once per sync period. "+
+			"Set to 0 to disable the audit.")
+
+	fs.IntVar(&apiBudget, "openstack-api-budget", 0,
+		"Maximum number of OpenStack API calls per cluster within each --openstack-api-budget-period. "+
+			"The reconciliation of a cluster which used up its budget is delayed until the period ends, except for deletions. "+
+			"Set to 0 to disable the budget.")
+
+	fs.DurationVar(&apiBudgetPeriod, "openstack-api-budget-period", budget.DefaultPeriod,
+		"Period of the OpenStack API budget of a cluster (e.g. 1h)")
+
+	fs.Float32Var(&openStackQPS, "openstack-qps", 0,
+		"Maximum number of OpenStack API calls per second, shared by all reconciles. Set to 0 to disable the limit.")
+
+	fs.IntVar(&openStackBurst, "openstack-burst", 20,
+		"Maximum burst of OpenStack API calls of --openstack-qps and of each limit of --openstack-service-qps")
+
+	fs.StringToStringVar(&openStackServiceQPS, "openstack-service-qps", map[string]string{},
+		"Maximum number of OpenStack API calls per second to individual services by their type in the service catalog, "+
+			"in addition to --openstack-qps (e.g. network=10,compute=5)")
+
+	fs.DurationVar(&lookupCacheTTL, "lookup-cache-ttl", 0,
+		"How long the resolution of networks, subnets, security groups and images by name or filter is reused "+
+			"before OpenStack is asked again (e.g. 5m). Set to 0 to disable the cache.")
+
+	fs.BoolVar(&openStackAPIDebug, "openstack-api-debug", false,
+		"Log the OpenStack API requests and responses of all clusters, with credentials and tokens redacted. "+
+			"It can be enabled for a single cluster with the "+infrav1.OpenStackAPIDebugAnnotation+" annotation.")
+
+	fs.BoolVar(&openStackAPIEvents, "openstack-api-events", false,
+		"Emit an event on the reconciled object for every OpenStack API call which creates, updates or deletes a resource. The events come in addition to the events of the controllers, so enabling them multiplies the number of events.")
+
+	fs.DurationVar(&openStackRequestTimeout, "openstack-request-timeout", 5*time.Minute,
+		"Maximum duration of an OpenStack API call, after which it is cancelled and the reconcile fails with an error. "+
+			"A list is limited as a whole, uploads of image data are not limited. Set to 0 to disable the timeout.")
+
+	fs.BoolVar(&validateOpenStackResources, "validate-openstack-resources", false,
+		"Reject OpenStackMachines and OpenStackMachineTemplates on creation if their flavor, image, networks, subnets "+
+			"or keypair do not exist. The webhook calls OpenStack with the credentials of the machine or its cluster.")
+
+	fs.StringVar(&cleanupVerification, "cleanup-verification", string(controllers.CleanupVerificationNone),
+		"Whether the OpenStack resources of clusters and machines are listed again after their deletion. "+
+			"One of none, report (emit an event and a metric for the resources which remain) "+
+			"or strict (also keep the finalizer until no resources remain).")
 }
 
 func main() {
@@ -155,6 +291,46 @@ func main() {
 		}()
 	}
 
+	minVersion, err := cliflag.TLSVersion(tlsMinVersion)
+	if err != nil {
+		setupLog.Error(err, "invalid TLS min version")
+		os.Exit(1)
+	}
+	cipherSuites, err := cliflag.TLSCipherSuites(tlsCipherSuites)
+	if err != nil {
+		setupLog.Error(err, "invalid TLS cipher suites")
+		os.Exit(1)
+	}
+	tlsconfig.Configure(minVersion, cipherSuites)
+
+	if retryConfig != "" {
+		if err := retry.LoadConfig(retryConfig); err != nil {
+			setupLog.Error(err, "unable to load retry configuration")
+			os.Exit(1)
+		}
+	}
+
+	switch controllers.CleanupVerification(cleanupVerification) {
+	case controllers.CleanupVerificationNone, controllers.CleanupVerificationReport, controllers.CleanupVerificationStrict:
+	default:
+		setupLog.Error(fmt.Errorf("unknown cleanup verification %q", cleanupVerification), "invalid cleanup verification")
+		os.Exit(1)
+	}
+
+	if flavorAliasesConfig != "" {
+		if err := flavoralias.LoadConfig(flavorAliasesConfig); err != nil {
+			setupLog.Error(err, "unable to load flavor alias configuration")
+			os.Exit(1)
+		}
+	}
+
+	if namespacePolicyConfig != "" {
+		if namespacePolicies, err = webhooks.LoadNamespacePolicies(namespacePolicyConfig); err != nil {
+			setupLog.Error(err, "unable to load namespace policy configuration")
+			os.Exit(1)
+		}
+	}
+
 	cfg, err := config.GetConfigWithContext(os.Getenv("KUBECONTEXT"))
 	if err != nil {
 		setupLog.Error(err, "unable to get kubeconfig")
@@ -183,12 +359,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	mgr.GetWebhookServer().TLSOpts = append(mgr.GetWebhookServer().TLSOpts, tlsconfig.Apply)
+
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
 	// Initialize event recorder.
 	record.InitFromRecorder(mgr.GetEventRecorderFor("openstack-controller"))
 
+	batch.Configure(deleteConcurrency, deleteQPS, deleteBurst)
+	egress.Configure(egressIPProbeURL, egressIPRefreshInterval)
+	budget.Configure(apiBudget, apiBudgetPeriod)
+
+	serviceQPS := map[string]float32{}
+	for serviceType, qps := range openStackServiceQPS {
+		v, err := strconv.ParseFloat(qps, 32)
+		if err != nil {
+			setupLog.Error(err, "invalid OpenStack API rate limit", "service", serviceType)
+			os.Exit(1)
+		}
+		serviceQPS[serviceType] = float32(v)
+	}
+	ratelimit.Configure(openStackQPS, openStackBurst, serviceQPS)
+	lookupcache.Configure(lookupCacheTTL)
+	apilog.Configure(openStackAPIDebug)
+	apievents.Configure(openStackAPIEvents)
+	clients.ConfigureRequestTimeout(openStackRequestTimeout)
+
 	setupChecks(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
@@ -215,21 +412,48 @@ func setupChecks(mgr ctrl.Manager) {
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&controllers.OpenStackClusterReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackcluster-controller"),
-		WatchFilterValue: watchFilterValue,
+		Client:              mgr.GetClient(),
+		Recorder:            mgr.GetEventRecorderFor("openstackcluster-controller"),
+		WatchFilterValue:    watchFilterValue,
+		CleanupVerification: controllers.CleanupVerification(cleanupVerification),
 	}).SetupWithManager(ctx, mgr, concurrency(openStackClusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenStackCluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.OpenStackMachineReconciler{
+		Client:                       mgr.GetClient(),
+		Recorder:                     mgr.GetEventRecorderFor("openstackmachine-controller"),
+		WatchFilterValue:             watchFilterValue,
+		InstanceActionsAuditInterval: instanceActionsInterval,
+		CleanupVerification:          controllers.CleanupVerification(cleanupVerification),
+	}).SetupWithManager(ctx, mgr, concurrency(openStackMachineConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachine")
+		os.Exit(1)
+	}
+	if err := (&controllers.OpenStackImageReconciler{
 		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackmachine-controller"),
+		Recorder:         mgr.GetEventRecorderFor("openstackimage-controller"),
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(openStackMachineConcurrency)); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachine")
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackImage")
+		os.Exit(1)
+	}
+	if err := (&controllers.CredentialRotationReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(ctx, mgr, concurrency(1)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CredentialRotation")
 		os.Exit(1)
 	}
+	if enableMachinePools {
+		if err := (&controllers.OpenStackMachinePoolReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("openstackmachinepool-controller"),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(openStackMachineConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachinePool")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -261,6 +485,21 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterList")
 		os.Exit(1)
 	}
+
+	// The webhook is always served as it is part of the webhook configuration, it admits everything unless enabled
+	mgr.GetWebhookServer().Register(webhooks.ResourceValidatorPath, &webhook.Admission{
+		Handler: &webhooks.ResourceValidator{
+			Client:  mgr.GetClient(),
+			Enabled: validateOpenStackResources,
+		},
+	})
+
+	// Likewise, the webhook admits everything if no namespace policies are configured
+	mgr.GetWebhookServer().Register(webhooks.NamespacePolicyValidatorPath, &webhook.Admission{
+		Handler: &webhooks.NamespacePolicyValidator{
+			Policies: namespacePolicies,
+		},
+	})
 }
 
 func concurrency(c int) controller.Options {
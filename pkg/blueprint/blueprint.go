@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package blueprint defines the blueprint of an OpenStackCluster, i.e. the OpenStack resources of the cluster with
+// their IDs, names and security group rules, which CAPO exports for disaster recovery tooling. The schema of the
+// blueprint is versioned independently of the API of CAPO, and fields are only added to a schema version.
+package blueprint
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+const (
+	// SchemaVersion is the version of the schema of the blueprint.
+	SchemaVersion = "v1"
+	// DataKey is the key of the blueprint in the data of the ConfigMap or Secret it is exported to.
+	DataKey = "blueprint.yaml"
+)
+
+// Security group roles.
+const (
+	SecurityGroupRoleControlPlane = "controlplane"
+	SecurityGroupRoleWorker       = "worker"
+	SecurityGroupRoleBastion      = "bastion"
+)
+
+// Load balancer roles.
+const (
+	LoadBalancerRoleAPIServer       = "apiserver"
+	LoadBalancerRoleAdditionalPorts = "additionalports"
+)
+
+// Blueprint is the effective specification of the OpenStack resources of a cluster.
+type Blueprint struct {
+	// SchemaVersion is the version of the schema of the blueprint.
+	SchemaVersion string `json:"schemaVersion"`
+	// Cluster is the name of the OpenStackCluster.
+	Cluster string `json:"cluster"`
+	// Namespace is the namespace of the OpenStackCluster.
+	Namespace string `json:"namespace"`
+	// ControlPlaneEndpoint is the endpoint of the API server.
+	ControlPlaneEndpoint Endpoint `json:"controlPlaneEndpoint"`
+	// ExternalNetwork is the external network of the cluster.
+	ExternalNetwork *Network `json:"externalNetwork,omitempty"`
+	// Network is the network of the cluster.
+	Network *Network `json:"network,omitempty"`
+	// Subnets are the subnets of the network of the cluster.
+	Subnets []Subnet `json:"subnets,omitempty"`
+	// Router is the router of the cluster.
+	Router *Router `json:"router,omitempty"`
+	// SecurityGroups are the security groups managed for the cluster.
+	SecurityGroups []SecurityGroup `json:"securityGroups,omitempty"`
+	// LoadBalancers are the load balancers of the API server.
+	LoadBalancers []LoadBalancer `json:"loadBalancers,omitempty"`
+	// Bastion is the bastion of the cluster.
+	Bastion *Bastion `json:"bastion,omitempty"`
+	// FailureDomains are the availability zones the control plane machines are spread across.
+	FailureDomains []string `json:"failureDomains,omitempty"`
+}
+
+// Endpoint is the host and port of an endpoint.
+type Endpoint struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+}
+
+// Network is a Neutron network.
+type Network struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	MTU  int      `json:"mtu,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Subnet is a Neutron subnet.
+type Subnet struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	CIDR string   `json:"cidr"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Router is a Neutron router.
+type Router struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+	// ExternalIPs are the addresses of the router on the external network.
+	ExternalIPs []string `json:"externalIPs,omitempty"`
+}
+
+// SecurityGroup is a Neutron security group.
+type SecurityGroup struct {
+	// Role is what the security group is used for: controlplane, worker or bastion.
+	Role  string              `json:"role"`
+	ID    string              `json:"id"`
+	Name  string              `json:"name"`
+	Rules []SecurityGroupRule `json:"rules,omitempty"`
+}
+
+// SecurityGroupRule is a rule of a security group.
+type SecurityGroupRule struct {
+	Description    string `json:"description,omitempty"`
+	Direction      string `json:"direction"`
+	EtherType      string `json:"etherType"`
+	Protocol       string `json:"protocol,omitempty"`
+	PortRangeMin   int    `json:"portRangeMin,omitempty"`
+	PortRangeMax   int    `json:"portRangeMax,omitempty"`
+	RemoteIPPrefix string `json:"remoteIPPrefix,omitempty"`
+	// RemoteGroup is the role of the remote security group if it is managed for the cluster, so that the rule can
+	// be re-created in another region, and its ID otherwise.
+	RemoteGroup string `json:"remoteGroup,omitempty"`
+}
+
+// LoadBalancer is an Octavia load balancer.
+type LoadBalancer struct {
+	// Role is what the load balancer is used for: apiserver or additionalports.
+	Role         string   `json:"role"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	VIP          string   `json:"vip,omitempty"`
+	FloatingIP   string   `json:"floatingIP,omitempty"`
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// Bastion is the bastion server.
+type Bastion struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Flavor           string `json:"flavor,omitempty"`
+	Image            string `json:"image,omitempty"`
+	ImageUUID        string `json:"imageUUID,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	FloatingIP       string `json:"floatingIP,omitempty"`
+}
+
+// New returns the blueprint of the cluster from its spec and status.
+func New(openStackCluster *infrav1.OpenStackCluster) *Blueprint {
+	spec := &openStackCluster.Spec
+	status := &openStackCluster.Status
+
+	b := &Blueprint{
+		SchemaVersion: SchemaVersion,
+		Cluster:       openStackCluster.Name,
+		Namespace:     openStackCluster.Namespace,
+		ControlPlaneEndpoint: Endpoint{
+			Host: spec.ControlPlaneEndpoint.Host,
+			Port: spec.ControlPlaneEndpoint.Port,
+		},
+	}
+
+	if status.ExternalNetwork != nil && status.ExternalNetwork.ID != "" {
+		b.ExternalNetwork = newNetwork(status.ExternalNetwork)
+	}
+
+	if network := status.Network; network != nil {
+		if network.ID != "" {
+			b.Network = newNetwork(network)
+		}
+		for _, subnet := range []*infrav1.Subnet{network.Subnet, network.IPv6Subnet} {
+			if subnet != nil {
+				b.Subnets = append(b.Subnets, newSubnet(subnet))
+			}
+		}
+		for i := range network.ManagedSubnets {
+			b.Subnets = append(b.Subnets, newSubnet(&network.ManagedSubnets[i].Subnet))
+		}
+		if router := network.Router; router != nil {
+			b.Router = &Router{ID: router.ID, Name: router.Name, Tags: router.Tags, ExternalIPs: router.IPs}
+		}
+		if lb := network.APIServerLoadBalancer; lb != nil {
+			b.LoadBalancers = append(b.LoadBalancers, newLoadBalancer(LoadBalancerRoleAPIServer, lb))
+		}
+		if lb := network.AdditionalPortsLoadBalancer; lb != nil {
+			b.LoadBalancers = append(b.LoadBalancers, newLoadBalancer(LoadBalancerRoleAdditionalPorts, lb))
+		}
+	}
+
+	groupRoles := map[string]string{}
+	groups := []struct {
+		role  string
+		group *infrav1.SecurityGroup
+	}{
+		{SecurityGroupRoleControlPlane, status.ControlPlaneSecurityGroup},
+		{SecurityGroupRoleWorker, status.WorkerSecurityGroup},
+		{SecurityGroupRoleBastion, status.BastionSecurityGroup},
+	}
+	for _, g := range groups {
+		if g.group != nil {
+			groupRoles[g.group.ID] = g.role
+		}
+	}
+	for _, g := range groups {
+		if g.group != nil {
+			b.SecurityGroups = append(b.SecurityGroups, newSecurityGroup(g.role, g.group, groupRoles))
+		}
+	}
+
+	if bastion := status.Bastion; bastion != nil {
+		b.Bastion = &Bastion{
+			ID:               bastion.ID,
+			Name:             bastion.Name,
+			Flavor:           bastion.Flavor,
+			Image:            bastion.Image,
+			ImageUUID:        bastion.ImageUUID,
+			AvailabilityZone: bastion.FailureDomain,
+			FloatingIP:       bastion.FloatingIP,
+		}
+	}
+
+	for name, failureDomain := range status.FailureDomains {
+		if failureDomain.ControlPlane {
+			b.FailureDomains = append(b.FailureDomains, name)
+		}
+	}
+	sort.Strings(b.FailureDomains)
+
+	return b
+}
+
+// Marshal returns the blueprint as YAML.
+func (b *Blueprint) Marshal() ([]byte, error) {
+	return yaml.Marshal(b)
+}
+
+// Unmarshal parses a blueprint of the current schema version.
+func Unmarshal(data []byte) (*Blueprint, error) {
+	b := &Blueprint{}
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	if b.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported blueprint schema version %q", b.SchemaVersion)
+	}
+	return b, nil
+}
+
+func newNetwork(network *infrav1.Network) *Network {
+	return &Network{ID: network.ID, Name: network.Name, MTU: network.MTU, Tags: network.Tags}
+}
+
+func newSubnet(subnet *infrav1.Subnet) Subnet {
+	return Subnet{ID: subnet.ID, Name: subnet.Name, CIDR: subnet.CIDR, Tags: subnet.Tags}
+}
+
+func newLoadBalancer(role string, lb *infrav1.LoadBalancer) LoadBalancer {
+	return LoadBalancer{Role: role, ID: lb.ID, Name: lb.Name, VIP: lb.InternalIP, FloatingIP: lb.IP, AllowedCIDRs: lb.AllowedCIDRs}
+}
+
+// newSecurityGroup converts the security group, replacing the IDs of remote groups by their roles. The rules are
+// sorted, as Neutron does not list them in a stable order.
+func newSecurityGroup(role string, group *infrav1.SecurityGroup, groupRoles map[string]string) SecurityGroup {
+	sg := SecurityGroup{Role: role, ID: group.ID, Name: group.Name}
+	for _, rule := range group.Rules {
+		remoteGroup := rule.RemoteGroupID
+		if remoteRole, ok := groupRoles[remoteGroup]; ok {
+			remoteGroup = remoteRole
+		}
+		sg.Rules = append(sg.Rules, SecurityGroupRule{
+			Description:    rule.Description,
+			Direction:      rule.Direction,
+			EtherType:      rule.EtherType,
+			Protocol:       rule.Protocol,
+			PortRangeMin:   rule.PortRangeMin,
+			PortRangeMax:   rule.PortRangeMax,
+			RemoteIPPrefix: rule.RemoteIPPrefix,
+			RemoteGroup:    remoteGroup,
+		})
+	}
+	sort.Slice(sg.Rules, func(i, j int) bool {
+		return fmt.Sprintf("%+v", sg.Rules[i]) < fmt.Sprintf("%+v", sg.Rules[j])
+	})
+	return sg
+}
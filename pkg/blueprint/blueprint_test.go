@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blueprint
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: infrav1.OpenStackClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "203.0.113.10", Port: 6443},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			ExternalNetwork: &infrav1.Network{ID: "ext", Name: "public"},
+			Network: &infrav1.Network{
+				ID:                    "net",
+				Name:                  "k8s-clusterapi-cluster-default-cluster",
+				MTU:                   1450,
+				Subnet:                &infrav1.Subnet{ID: "subnet", Name: "k8s-clusterapi-cluster-default-cluster", CIDR: "10.6.0.0/24"},
+				Router:                &infrav1.Router{ID: "router", Name: "k8s-clusterapi-cluster-default-cluster", IPs: []string{"203.0.113.2"}},
+				APIServerLoadBalancer: &infrav1.LoadBalancer{ID: "lb", Name: "k8s-clusterapi-cluster-default-cluster-kubeapi", IP: "203.0.113.10", InternalIP: "10.6.0.10"},
+			},
+			ControlPlaneSecurityGroup: &infrav1.SecurityGroup{
+				ID:   "cp",
+				Name: "k8s-cluster-default-cluster-secgroup-controlplane",
+				Rules: []infrav1.SecurityGroupRule{
+					{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 6443, PortRangeMax: 6443},
+					{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 2379, PortRangeMax: 2380, RemoteGroupID: "cp"},
+					{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 10250, PortRangeMax: 10250, RemoteGroupID: "worker"},
+					{Direction: "ingress", EtherType: "IPv4", RemoteGroupID: "other"},
+				},
+			},
+			WorkerSecurityGroup: &infrav1.SecurityGroup{ID: "worker", Name: "k8s-cluster-default-cluster-secgroup-worker"},
+			Bastion:             &infrav1.Instance{ID: "bastion", Name: "cluster-bastion", Flavor: "m1.small", Image: "ubuntu", FailureDomain: "az1", FloatingIP: "203.0.113.20"},
+			FailureDomains: clusterv1.FailureDomains{
+				"az2": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"az1": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"az3": clusterv1.FailureDomainSpec{},
+			},
+		},
+	}
+
+	b := New(openStackCluster)
+	g.Expect(b.SchemaVersion).To(Equal(SchemaVersion))
+	g.Expect(b.ControlPlaneEndpoint).To(Equal(Endpoint{Host: "203.0.113.10", Port: 6443}))
+	g.Expect(b.ExternalNetwork).To(Equal(&Network{ID: "ext", Name: "public"}))
+	g.Expect(b.Network.MTU).To(Equal(1450))
+	g.Expect(b.Subnets).To(ConsistOf(Subnet{ID: "subnet", Name: "k8s-clusterapi-cluster-default-cluster", CIDR: "10.6.0.0/24"}))
+	g.Expect(b.Router.ExternalIPs).To(ConsistOf("203.0.113.2"))
+	g.Expect(b.LoadBalancers).To(ConsistOf(LoadBalancer{Role: LoadBalancerRoleAPIServer, ID: "lb", Name: "k8s-clusterapi-cluster-default-cluster-kubeapi", VIP: "10.6.0.10", FloatingIP: "203.0.113.10"}))
+	g.Expect(b.Bastion.AvailabilityZone).To(Equal("az1"))
+	g.Expect(b.FailureDomains).To(Equal([]string{"az1", "az2"}))
+
+	g.Expect(b.SecurityGroups).To(HaveLen(2))
+	g.Expect(b.SecurityGroups[0].Role).To(Equal(SecurityGroupRoleControlPlane))
+	var remoteGroups []string
+	for _, rule := range b.SecurityGroups[0].Rules {
+		remoteGroups = append(remoteGroups, rule.RemoteGroup)
+	}
+	// Managed remote groups are replaced by their roles
+	g.Expect(remoteGroups).To(ConsistOf("", SecurityGroupRoleControlPlane, SecurityGroupRoleWorker, "other"))
+
+	// The rules are sorted, so that the blueprint is stable
+	openStackCluster.Status.ControlPlaneSecurityGroup.Rules[0], openStackCluster.Status.ControlPlaneSecurityGroup.Rules[3] =
+		openStackCluster.Status.ControlPlaneSecurityGroup.Rules[3], openStackCluster.Status.ControlPlaneSecurityGroup.Rules[0]
+	g.Expect(New(openStackCluster)).To(Equal(b))
+}
+
+func TestMarshal(t *testing.T) {
+	g := NewWithT(t)
+
+	b := New(&infrav1.OpenStackCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}})
+	data, err := b.Marshal()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	parsed, err := Unmarshal(data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(parsed).To(Equal(b))
+
+	_, err = Unmarshal([]byte("schemaVersion: v0\ncluster: cluster\n"))
+	g.Expect(err).To(HaveOccurred())
+}
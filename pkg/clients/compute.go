@@ -17,14 +17,28 @@ limitations under the License.
 package clients
 
 import (
+	"context"
+	"crypto/rsa"
 	"fmt"
+	"time"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/apiversions"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/evacuate"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/limits"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	servertags "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tags"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/utils/openstack/compute/v2/flavors"
+	"k8s.io/apimachinery/pkg/util/cache"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
@@ -41,26 +55,101 @@ CAPO uses server tags, which were added in microversion 2.52.
 */
 const NovaMinimumMicroversion = "2.53"
 
+// NovaVolumeAttachMicroversion is the Nova microversion volumes are attached to existing servers with. Volumes
+// attached with delete_on_termination, like the volumes a server is created with, require 2.79 (Train), which is
+// also required to list the delete_on_termination of attachments.
+const NovaVolumeAttachMicroversion = "2.79"
+
+// NovaHostnameMicroversion is the Nova microversion servers with a hostname different from their name are created
+// with. The hostname of servers requires 2.90 (Xena).
+const NovaHostnameMicroversion = "2.90"
+
+// NovaServerGroupsMicroversion is the Nova microversion the server groups of a server are shown with. The server
+// groups of servers require 2.71 (Stein).
+const NovaServerGroupsMicroversion = "2.71"
+
+const (
+	// flavorIDCacheSize is the maximum number of flavor name to ID
+	// resolutions kept in memory.
+	flavorIDCacheSize = 256
+	// flavorIDCacheTTL is how long a flavor name to ID resolution is reused
+	// before Nova is asked again.
+	flavorIDCacheTTL = 10 * time.Minute
+)
+
+// flavorIDCache memoizes flavor name to ID resolutions across reconciles, as
+// resolving a name requires listing every flavor visible to the project.
+var flavorIDCache = cache.NewLRUExpireCache(flavorIDCacheSize)
+
+const (
+	// maxMicroversionCacheSize is the maximum number of compute endpoints
+	// whose maximum microversion is kept in memory.
+	maxMicroversionCacheSize = 64
+	// maxMicroversionCacheTTL is how long the maximum microversion of a
+	// compute endpoint is reused before Nova is asked again.
+	maxMicroversionCacheTTL = time.Hour
+)
+
+// maxMicroversionCache memoizes the maximum microversion of compute endpoints
+// across reconciles, as it only changes when Nova is upgraded.
+var maxMicroversionCache = cache.NewLRUExpireCache(maxMicroversionCacheSize)
+
 // ServerExt is the base gophercloud Server with extensions used by InstanceStatus.
 type ServerExt struct {
 	servers.Server
 	availabilityzones.ServerAvailabilityZoneExt
+	// ServerAttributesExt is only returned to admins.
+	extendedserverattributes.ServerAttributesExt
 }
 
 type ComputeClient interface {
-	ListAvailabilityZones() ([]availabilityzones.AvailabilityZone, error)
-
-	GetFlavorIDFromName(flavor string) (string, error)
-	CreateServer(createOpts servers.CreateOptsBuilder) (*ServerExt, error)
-	DeleteServer(serverID string) error
-	GetServer(serverID string) (*ServerExt, error)
-	ListServers(listOpts servers.ListOptsBuilder) ([]ServerExt, error)
-
-	ListAttachedInterfaces(serverID string) ([]attachinterfaces.Interface, error)
-	DeleteAttachedInterface(serverID, portID string) error
+	ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error)
+
+	GetFlavorIDFromName(ctx context.Context, flavor string) (string, error)
+	CreateServer(ctx context.Context, createOpts servers.CreateOptsBuilder) (*ServerExt, error)
+	CreateServers(ctx context.Context, createOpts servers.CreateOptsBuilder) (string, error)
+	DeleteServer(ctx context.Context, serverID string) error
+	GetServer(ctx context.Context, serverID string) (*ServerExt, error)
+	ListServerGroupsOfServer(ctx context.Context, serverID string) ([]string, error)
+	ListServers(ctx context.Context, listOpts servers.ListOptsBuilder) ([]ServerExt, error)
+	EvacuateServer(ctx context.Context, serverID string, opts evacuate.EvacuateOptsBuilder) error
+	RebootServer(ctx context.Context, serverID string, opts servers.RebootOptsBuilder) error
+	RebuildServer(ctx context.Context, serverID string, opts servers.RebuildOptsBuilder) (*ServerExt, error)
+	UpdateServer(ctx context.Context, serverID string, opts servers.UpdateOptsBuilder) (*ServerExt, error)
+	CreateServerImage(ctx context.Context, serverID string, opts servers.CreateImageOptsBuilder) (string, error)
+	UpdateServerMetadata(ctx context.Context, serverID string, opts servers.UpdateMetadataOptsBuilder) (map[string]string, error)
+	DeleteServerMetadatum(ctx context.Context, serverID, key string) error
+	ReplaceAllServerTags(ctx context.Context, serverID string, tags []string) ([]string, error)
+	GetServerPassword(ctx context.Context, serverID string, privateKey *rsa.PrivateKey) (string, error)
+	ClearServerPassword(ctx context.Context, serverID string) error
+	ListInstanceActions(ctx context.Context, serverID string) ([]instanceactions.InstanceAction, error)
+	GetConsoleOutput(ctx context.Context, serverID string, length int) (string, error)
+
+	ListHypervisors(ctx context.Context, listOpts hypervisors.ListOptsBuilder) ([]hypervisors.Hypervisor, error)
+
+	ListAttachedInterfaces(ctx context.Context, serverID string) ([]attachinterfaces.Interface, error)
+	DeleteAttachedInterface(ctx context.Context, serverID, portID string) error
+
+	AttachVolume(ctx context.Context, serverID string, createOpts volumeattach.CreateOptsBuilder) (*volumeattach.VolumeAttachment, error)
+	DetachVolume(ctx context.Context, serverID, volumeID string) error
+	ListVolumeAttachments(ctx context.Context, serverID string) ([]volumeattach.VolumeAttachment, error)
+
+	CreateServerGroup(ctx context.Context, createOpts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error)
+	DeleteServerGroup(ctx context.Context, serverGroupID string) error
+	GetServerGroup(ctx context.Context, serverGroupID string) (*servergroups.ServerGroup, error)
+	ListServerGroups(ctx context.Context, listOpts servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error)
+
+	CreateKeyPair(ctx context.Context, createOpts keypairs.CreateOptsBuilder) (*keypairs.KeyPair, error)
+	GetKeyPair(ctx context.Context, name string) (*keypairs.KeyPair, error)
+
+	GetLimits(ctx context.Context) (*limits.Limits, error)
+	GetMaxMicroversion(ctx context.Context) (string, error)
 }
 
-type computeClient struct{ client *gophercloud.ServiceClient }
+type computeClient struct {
+	client    *gophercloud.ServiceClient
+	projectID string
+}
 
 // NewComputeClient returns a new compute client.
 func NewComputeClient(scope *scope.Scope) (ComputeClient, error) {
@@ -72,54 +161,124 @@ func NewComputeClient(scope *scope.Scope) (ComputeClient, error) {
 	}
 	compute.Microversion = NovaMinimumMicroversion
 
-	return &computeClient{compute}, nil
+	return &computeClient{client: compute, projectID: scope.ProjectID}, nil
 }
 
-func (c computeClient) ListAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
+func (c computeClient) ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("availability_zone", "list")
-	allPages, err := availabilityzones.List(c.client).AllPages()
+	allPages, err := availabilityzones.List(client).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return availabilityzones.ExtractAvailabilityZones(allPages)
 }
 
-func (c computeClient) GetFlavorIDFromName(flavor string) (string, error) {
+func (c computeClient) GetFlavorIDFromName(ctx context.Context, flavor string) (string, error) {
+	// Flavors may be private to a project, so the project is part of the key.
+	key := c.client.Endpoint + "|" + c.projectID + "|" + flavor
+	if flavorID, ok := flavorIDCache.Get(key); ok {
+		return flavorID.(string), nil
+	}
+
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("flavor", "get")
-	flavorID, err := flavors.IDFromName(c.client, flavor)
-	return flavorID, mc.ObserveRequest(err)
+	flavorID, err := flavors.IDFromName(client, flavor)
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	flavorIDCache.Add(key, flavorID, flavorIDCacheTTL)
+	return flavorID, nil
 }
 
-func (c computeClient) CreateServer(createOpts servers.CreateOptsBuilder) (*ServerExt, error) {
+func (c computeClient) CreateServer(ctx context.Context, createOpts servers.CreateOptsBuilder) (*ServerExt, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	var server ServerExt
+	if hasHostname(createOpts) {
+		client.Microversion = NovaHostnameMicroversion
+	}
 	mc := metrics.NewMetricPrometheusContext("server", "create")
-	err := servers.Create(c.client, createOpts).ExtractInto(&server)
+	err := servers.Create(client, createOpts).ExtractInto(&server)
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return &server, nil
 }
 
-func (c computeClient) DeleteServer(serverID string) error {
+// hasHostname returns whether the server created with the options has a hostname. Invalid options are left to
+// servers.Create to reject.
+func hasHostname(createOpts servers.CreateOptsBuilder) bool {
+	b, err := createOpts.ToServerCreateMap()
+	if err != nil {
+		return false
+	}
+	server, _ := b["server"].(map[string]interface{})
+	_, ok := server["hostname"]
+	return ok
+}
+
+// CreateServers creates several servers with a single request, which must set
+// return_reservation_id. It returns the reservation ID of the servers.
+func (c computeClient) CreateServers(ctx context.Context, createOpts servers.CreateOptsBuilder) (string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	var reservation struct {
+		ReservationID string `json:"reservation_id"`
+	}
+	mc := metrics.NewMetricPrometheusContext("server", "create")
+	err := servers.Create(client, createOpts).ExtractInto(&reservation)
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return reservation.ReservationID, nil
+}
+
+func (c computeClient) DeleteServer(ctx context.Context, serverID string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("server", "delete")
-	err := servers.Delete(c.client, serverID).ExtractErr()
+	err := servers.Delete(client, serverID).ExtractErr()
 	return mc.ObserveRequestIgnoreNotFound(err)
 }
 
-func (c computeClient) GetServer(serverID string) (*ServerExt, error) {
+func (c computeClient) GetServer(ctx context.Context, serverID string) (*ServerExt, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	var server ServerExt
 	mc := metrics.NewMetricPrometheusContext("server", "get")
-	err := servers.Get(c.client, serverID).ExtractInto(&server)
+	err := servers.Get(client, serverID).ExtractInto(&server)
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return &server, nil
 }
 
-func (c computeClient) ListServers(listOpts servers.ListOptsBuilder) ([]ServerExt, error) {
+// ListServerGroupsOfServer returns the IDs of the server groups the server is a member of. It requires
+// NovaServerGroupsMicroversion.
+func (c computeClient) ListServerGroupsOfServer(ctx context.Context, serverID string) ([]string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	var server struct {
+		ServerGroups []string `json:"server_groups"`
+	}
+	client.Microversion = NovaServerGroupsMicroversion
+	mc := metrics.NewMetricPrometheusContext("server", "get")
+	err := servers.Get(client, serverID).ExtractInto(&server)
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	return server.ServerGroups, nil
+}
+
+func (c computeClient) ListServers(ctx context.Context, listOpts servers.ListOptsBuilder) ([]ServerExt, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	var serverList []ServerExt
 	mc := metrics.NewMetricPrometheusContext("server", "list")
-	allPages, err := servers.List(c.client, listOpts).AllPages()
+	allPages, err := servers.List(client, listOpts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
@@ -127,21 +286,303 @@ func (c computeClient) ListServers(listOpts servers.ListOptsBuilder) ([]ServerEx
 	return serverList, err
 }
 
-func (c computeClient) ListAttachedInterfaces(serverID string) ([]attachinterfaces.Interface, error) {
+// EvacuateServer rebuilds the server on another host after its host failed. Since microversion 2.14 the
+// response has no body, which evacuate.Evacuate fails to decode, so the action is posted directly.
+func (c computeClient) EvacuateServer(ctx context.Context, serverID string, opts evacuate.EvacuateOptsBuilder) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	body, err := opts.ToEvacuateMap()
+	if err != nil {
+		return err
+	}
+	mc := metrics.NewMetricPrometheusContext("server", "evacuate")
+	_, err = client.Post(client.ServiceURL("servers", serverID, "action"), body, nil, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	return mc.ObserveRequest(err)
+}
+
+func (c computeClient) RebootServer(ctx context.Context, serverID string, opts servers.RebootOptsBuilder) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server", "reboot")
+	err := servers.Reboot(client, serverID, opts).ExtractErr()
+	return mc.ObserveRequest(err)
+}
+
+func (c computeClient) RebuildServer(ctx context.Context, serverID string, opts servers.RebuildOptsBuilder) (*ServerExt, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	var server ServerExt
+	mc := metrics.NewMetricPrometheusContext("server", "rebuild")
+	err := servers.Rebuild(client, serverID, opts).ExtractInto(&server)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (c computeClient) UpdateServer(ctx context.Context, serverID string, opts servers.UpdateOptsBuilder) (*ServerExt, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	var server ServerExt
+	mc := metrics.NewMetricPrometheusContext("server", "update")
+	err := servers.Update(client, serverID, opts).ExtractInto(&server)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// CreateServerImage creates a snapshot image of the server and returns its ID. Since microversion 2.45 the ID is
+// returned in the body instead of the Location header, which servers.CreateImage expects, so the action is posted
+// directly.
+func (c computeClient) CreateServerImage(ctx context.Context, serverID string, opts servers.CreateImageOptsBuilder) (string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	body, err := opts.ToServerCreateImageMap()
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		ImageID string `json:"image_id"`
+	}
+	mc := metrics.NewMetricPrometheusContext("server_image", "create")
+	_, err = client.Post(client.ServiceURL("servers", serverID, "action"), body, &result, &gophercloud.RequestOpts{
+		OkCodes: []int{202},
+	})
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return result.ImageID, nil
+}
+
+func (c computeClient) UpdateServerMetadata(ctx context.Context, serverID string, opts servers.UpdateMetadataOptsBuilder) (map[string]string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_metadata", "update")
+	metadata, err := servers.UpdateMetadata(client, serverID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (c computeClient) DeleteServerMetadatum(ctx context.Context, serverID, key string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_metadata", "delete")
+	err := servers.DeleteMetadatum(client, serverID, key).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (c computeClient) ReplaceAllServerTags(ctx context.Context, serverID string, tags []string) ([]string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_tags", "update")
+	replaced, err := servertags.ReplaceAll(client, serverID, servertags.ReplaceAllOpts{Tags: tags}).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return replaced, nil
+}
+
+// GetServerPassword returns the admin password of the server decrypted with the
+// given private key, or an empty string if the server has not posted a password.
+func (c computeClient) GetServerPassword(ctx context.Context, serverID string, privateKey *rsa.PrivateKey) (string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_password", "get")
+	password, err := servers.GetPassword(client, serverID).ExtractPassword(privateKey)
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// ClearServerPassword removes the admin password of the server from the metadata service.
+func (c computeClient) ClearServerPassword(ctx context.Context, serverID string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_password", "delete")
+	_, err := client.Delete(client.ServiceURL("servers", serverID, "os-server-password"), nil)
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (c computeClient) ListInstanceActions(ctx context.Context, serverID string) ([]instanceactions.InstanceAction, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_instance_action", "list")
+	allPages, err := instanceactions.List(client, serverID, nil).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return instanceactions.ExtractInstanceActions(allPages)
+}
+
+// GetConsoleOutput returns the last length lines of the console log of the server.
+func (c computeClient) GetConsoleOutput(ctx context.Context, serverID string, length int) (string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_console_output", "get")
+	output, err := servers.ShowConsoleOutput(client, serverID, servers.ShowConsoleOutputOpts{Length: length}).Extract()
+	return output, mc.ObserveRequest(err)
+}
+
+func (c computeClient) ListHypervisors(ctx context.Context, listOpts hypervisors.ListOptsBuilder) ([]hypervisors.Hypervisor, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("hypervisor", "list")
+	allPages, err := hypervisors.List(client, listOpts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return hypervisors.ExtractHypervisors(allPages)
+}
+
+func (c computeClient) ListAttachedInterfaces(ctx context.Context, serverID string) ([]attachinterfaces.Interface, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "list")
-	interfaces, err := attachinterfaces.List(c.client, serverID).AllPages()
+	interfaces, err := attachinterfaces.List(client, serverID).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return attachinterfaces.ExtractInterfaces(interfaces)
 }
 
-func (c computeClient) DeleteAttachedInterface(serverID, portID string) error {
+func (c computeClient) DeleteAttachedInterface(ctx context.Context, serverID, portID string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "delete")
-	err := attachinterfaces.Delete(c.client, serverID, portID).ExtractErr()
+	err := attachinterfaces.Delete(client, serverID, portID).ExtractErr()
 	return mc.ObserveRequestIgnoreNotFoundorConflict(err)
 }
 
+func (c computeClient) AttachVolume(ctx context.Context, serverID string, createOpts volumeattach.CreateOptsBuilder) (*volumeattach.VolumeAttachment, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	client.Microversion = NovaVolumeAttachMicroversion
+	mc := metrics.NewMetricPrometheusContext("server_os_volume_attachment", "create")
+	attachment, err := volumeattach.Create(client, serverID, createOpts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (c computeClient) DetachVolume(ctx context.Context, serverID, volumeID string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_os_volume_attachment", "delete")
+	err := volumeattach.Delete(client, serverID, volumeID).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (c computeClient) ListVolumeAttachments(ctx context.Context, serverID string) ([]volumeattach.VolumeAttachment, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	client.Microversion = NovaVolumeAttachMicroversion
+	mc := metrics.NewMetricPrometheusContext("server_os_volume_attachment", "list")
+	allPages, err := volumeattach.List(client, serverID).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return volumeattach.ExtractVolumeAttachments(allPages)
+}
+
+func (c computeClient) CreateServerGroup(ctx context.Context, createOpts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_group", "create")
+	serverGroup, err := servergroups.Create(client, createOpts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return serverGroup, nil
+}
+
+func (c computeClient) DeleteServerGroup(ctx context.Context, serverGroupID string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_group", "delete")
+	err := servergroups.Delete(client, serverGroupID).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (c computeClient) GetServerGroup(ctx context.Context, serverGroupID string) (*servergroups.ServerGroup, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_group", "get")
+	serverGroup, err := servergroups.Get(client, serverGroupID).Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	return serverGroup, nil
+}
+
+func (c computeClient) ListServerGroups(ctx context.Context, listOpts servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("server_group", "list")
+	allPages, err := servergroups.List(client, listOpts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return servergroups.ExtractServerGroups(allPages)
+}
+
+func (c computeClient) CreateKeyPair(ctx context.Context, createOpts keypairs.CreateOptsBuilder) (*keypairs.KeyPair, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keypair", "create")
+	keyPair, err := keypairs.Create(client, createOpts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return keyPair, nil
+}
+
+func (c computeClient) GetKeyPair(ctx context.Context, name string) (*keypairs.KeyPair, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keypair", "get")
+	keyPair, err := keypairs.Get(client, name, nil).Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	return keyPair, nil
+}
+
+func (c computeClient) GetLimits(ctx context.Context) (*limits.Limits, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("limits", "get")
+	l, err := limits.Get(client, nil).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// GetMaxMicroversion returns the maximum microversion supported by Nova.
+func (c computeClient) GetMaxMicroversion(ctx context.Context) (string, error) {
+	key := c.client.Endpoint
+	if microversion, ok := maxMicroversionCache.Get(key); ok {
+		return microversion.(string), nil
+	}
+
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("version", "get")
+	version, err := apiversions.Get(client, "v2.1").Extract()
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	maxMicroversionCache.Add(key, version.Version, maxMicroversionCacheTTL)
+	return version.Version, nil
+}
+
 type computeErrorClient struct{ error }
 
 // NewComputeErrorClient returns a ComputeClient in which every method returns the given error.
@@ -149,34 +590,138 @@ func NewComputeErrorClient(e error) ComputeClient {
 	return computeErrorClient{e}
 }
 
-func (e computeErrorClient) ListAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
+func (e computeErrorClient) ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) GetFlavorIDFromName(ctx context.Context, flavor string) (string, error) {
+	return "", e.error
+}
+
+func (e computeErrorClient) CreateServer(ctx context.Context, createOpts servers.CreateOptsBuilder) (*ServerExt, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) CreateServers(ctx context.Context, createOpts servers.CreateOptsBuilder) (string, error) {
+	return "", e.error
+}
+
+func (e computeErrorClient) DeleteServer(ctx context.Context, serverID string) error {
+	return e.error
+}
+
+func (e computeErrorClient) GetServer(ctx context.Context, serverID string) (*ServerExt, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) ListServerGroupsOfServer(ctx context.Context, serverID string) ([]string, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) ListServers(ctx context.Context, listOpts servers.ListOptsBuilder) ([]ServerExt, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) EvacuateServer(ctx context.Context, serverID string, opts evacuate.EvacuateOptsBuilder) error {
+	return e.error
+}
+
+func (e computeErrorClient) RebootServer(ctx context.Context, serverID string, opts servers.RebootOptsBuilder) error {
+	return e.error
+}
+
+func (e computeErrorClient) RebuildServer(ctx context.Context, serverID string, opts servers.RebuildOptsBuilder) (*ServerExt, error) {
 	return nil, e.error
 }
 
-func (e computeErrorClient) GetFlavorIDFromName(flavor string) (string, error) {
+func (e computeErrorClient) UpdateServer(ctx context.Context, serverID string, opts servers.UpdateOptsBuilder) (*ServerExt, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) CreateServerImage(ctx context.Context, serverID string, opts servers.CreateImageOptsBuilder) (string, error) {
 	return "", e.error
 }
 
-func (e computeErrorClient) CreateServer(createOpts servers.CreateOptsBuilder) (*ServerExt, error) {
+func (e computeErrorClient) UpdateServerMetadata(ctx context.Context, serverID string, opts servers.UpdateMetadataOptsBuilder) (map[string]string, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) DeleteServerMetadatum(ctx context.Context, serverID, key string) error {
+	return e.error
+}
+
+func (e computeErrorClient) ReplaceAllServerTags(ctx context.Context, serverID string, tags []string) ([]string, error) {
 	return nil, e.error
 }
 
-func (e computeErrorClient) DeleteServer(serverID string) error {
+func (e computeErrorClient) GetServerPassword(ctx context.Context, serverID string, privateKey *rsa.PrivateKey) (string, error) {
+	return "", e.error
+}
+
+func (e computeErrorClient) ClearServerPassword(ctx context.Context, serverID string) error {
 	return e.error
 }
 
-func (e computeErrorClient) GetServer(serverID string) (*ServerExt, error) {
+func (e computeErrorClient) ListInstanceActions(ctx context.Context, serverID string) ([]instanceactions.InstanceAction, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) GetConsoleOutput(ctx context.Context, serverID string, length int) (string, error) {
+	return "", e.error
+}
+
+func (e computeErrorClient) ListHypervisors(ctx context.Context, listOpts hypervisors.ListOptsBuilder) ([]hypervisors.Hypervisor, error) {
 	return nil, e.error
 }
 
-func (e computeErrorClient) ListServers(listOpts servers.ListOptsBuilder) ([]ServerExt, error) {
+func (e computeErrorClient) ListAttachedInterfaces(ctx context.Context, serverID string) ([]attachinterfaces.Interface, error) {
 	return nil, e.error
 }
 
-func (e computeErrorClient) ListAttachedInterfaces(serverID string) ([]attachinterfaces.Interface, error) {
+func (e computeErrorClient) DeleteAttachedInterface(ctx context.Context, serverID, portID string) error {
+	return e.error
+}
+
+func (e computeErrorClient) AttachVolume(ctx context.Context, serverID string, createOpts volumeattach.CreateOptsBuilder) (*volumeattach.VolumeAttachment, error) {
 	return nil, e.error
 }
 
-func (e computeErrorClient) DeleteAttachedInterface(serverID, portID string) error {
+func (e computeErrorClient) DetachVolume(ctx context.Context, serverID, volumeID string) error {
 	return e.error
 }
+
+func (e computeErrorClient) ListVolumeAttachments(ctx context.Context, serverID string) ([]volumeattach.VolumeAttachment, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) CreateServerGroup(ctx context.Context, createOpts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) DeleteServerGroup(ctx context.Context, serverGroupID string) error {
+	return e.error
+}
+
+func (e computeErrorClient) GetServerGroup(ctx context.Context, serverGroupID string) (*servergroups.ServerGroup, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) ListServerGroups(ctx context.Context, listOpts servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) CreateKeyPair(ctx context.Context, createOpts keypairs.CreateOptsBuilder) (*keypairs.KeyPair, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) GetKeyPair(ctx context.Context, name string) (*keypairs.KeyPair, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) GetLimits(ctx context.Context) (*limits.Limits, error) {
+	return nil, e.error
+}
+
+func (e computeErrorClient) GetMaxMicroversion(ctx context.Context) (string, error) {
+	return "", e.error
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/fixture"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+func newFixtureComputeClient(t *testing.T, name string) ComputeClient {
+	client := fixture.NewServiceClient(t, name, openstack.NewComputeV2)
+	client.Microversion = NovaMinimumMicroversion
+	return &computeClient{client: client, projectID: fixture.ProjectID}
+}
+
+func TestComputeClient_GetServer(t *testing.T) {
+	t.Run("Server which failed to boot from volume", func(t *testing.T) {
+		g := NewWithT(t)
+		c := newFixtureComputeClient(t, "compute-get-server-error")
+
+		server, err := c.GetServer(context.Background(), "1b8ef0d4-0c5e-4b0a-9e2b-6a0b1c0c7d01")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(server.Status).To(Equal("ERROR"))
+		g.Expect(server.Fault.Message).To(Equal("No valid host was found. There are not enough hosts available."))
+		// Servers booted from volume have an empty string instead of an image
+		g.Expect(server.Image).To(BeEmpty())
+		g.Expect(server.AvailabilityZone).To(BeEmpty())
+		g.Expect(server.AttachedVolumes).To(HaveLen(1))
+	})
+
+	t.Run("Server not found", func(t *testing.T) {
+		g := NewWithT(t)
+		c := newFixtureComputeClient(t, "compute-get-server-not-found")
+
+		_, err := c.GetServer(context.Background(), "6f3c1c4e-3b1f-4d59-8f0e-2b4a0e5c9a77")
+		g.Expect(capoerrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func TestComputeClient_ListServers(t *testing.T) {
+	g := NewWithT(t)
+	c := newFixtureComputeClient(t, "compute-list-servers-paged")
+
+	serverList, err := c.ListServers(context.Background(), servers.ListOpts{Name: "^cluster-md-0", Limit: 1})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(serverList).To(HaveLen(2))
+	g.Expect(serverList[0].Name).To(Equal("cluster-md-0-abcde"))
+	g.Expect(serverList[1].Name).To(Equal("cluster-md-0-fghij"))
+	g.Expect(serverList[1].AvailabilityZone).To(Equal("az2"))
+}
+
+func TestComputeClient_GetConsoleOutput(t *testing.T) {
+	g := NewWithT(t)
+	c := newFixtureComputeClient(t, "compute-get-console-output")
+
+	output, err := c.GetConsoleOutput(context.Background(), "1b8ef0d4-0c5e-4b0a-9e2b-6a0b1c0c7d01", 30)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output).To(HaveSuffix("Kernel panic - not syncing: VFS: Unable to mount root fs on unknown-block(0,0)\n"))
+}
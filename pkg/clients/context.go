@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+var (
+	requestTimeoutMu sync.RWMutex
+	requestTimeout   time.Duration
+)
+
+// ConfigureRequestTimeout sets the timeout of each call of a client. A call which
+// pages through a list is limited as a whole. Uploads of image data are not
+// limited. A timeout of 0 disables the limit.
+func ConfigureRequestTimeout(timeout time.Duration) {
+	requestTimeoutMu.Lock()
+	defer requestTimeoutMu.Unlock()
+	requestTimeout = timeout
+}
+
+func getRequestTimeout() time.Duration {
+	requestTimeoutMu.RLock()
+	defer requestTimeoutMu.RUnlock()
+	return requestTimeout
+}
+
+// withContext returns a copy of client whose requests are made with ctx, limited
+// by the request timeout. The returned function must be called once the call of
+// the client has completed.
+func withContext(ctx context.Context, client *gophercloud.ServiceClient) (*gophercloud.ServiceClient, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if timeout := getRequestTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	return contextClient(ctx, client), cancel
+}
+
+// contextClient returns a copy of client whose requests are made with ctx.
+// gophercloud only takes the context of requests from the provider client, so
+// the copy has its own provider client, which shares the token, the endpoints
+// and the transport of the provider client of client. A 401 response
+// re-authenticates the shared provider client, like the sessions of the
+// provider client cache, and the request is retried with its new token.
+func contextClient(ctx context.Context, client *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	shared := client.ProviderClient
+	provider := &gophercloud.ProviderClient{
+		IdentityBase:      shared.IdentityBase,
+		IdentityEndpoint:  shared.IdentityEndpoint,
+		EndpointLocator:   shared.EndpointLocator,
+		HTTPClient:        shared.HTTPClient,
+		UserAgent:         shared.UserAgent,
+		Throwaway:         shared.Throwaway,
+		Context:           ctx,
+		RetryBackoffFunc:  shared.RetryBackoffFunc,
+		MaxBackoffRetries: shared.MaxBackoffRetries,
+		RetryFunc:         shared.RetryFunc,
+	}
+	provider.UseTokenLock()
+	provider.CopyTokenFrom(shared)
+	if shared.ReauthFunc != nil {
+		provider.ReauthFunc = func() error {
+			if err := shared.Reauthenticate(provider.Token()); err != nil {
+				return err
+			}
+			provider.CopyTokenFrom(shared)
+			return nil
+		}
+	}
+
+	serviceClient := *client
+	serviceClient.ProviderClient = provider
+	return &serviceClient
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+// newHangingComputeClient returns a compute client of a server which only responds once the request is cancelled.
+func newHangingComputeClient(t *testing.T) ComputeClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &gophercloud.ProviderClient{}
+	provider.UseTokenLock()
+	return &computeClient{client: &gophercloud.ServiceClient{ProviderClient: provider, Endpoint: server.URL + "/"}}
+}
+
+func TestComputeClient_RequestTimeout(t *testing.T) {
+	g := NewWithT(t)
+	ConfigureRequestTimeout(100 * time.Millisecond)
+	defer ConfigureRequestTimeout(0)
+	c := newHangingComputeClient(t)
+
+	start := time.Now()
+	_, err := c.GetServer(context.Background(), "server-id")
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue(), "unexpected error %v", err)
+	g.Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+}
+
+func TestComputeClient_Cancel(t *testing.T) {
+	g := NewWithT(t)
+	c := newHangingComputeClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := c.GetServer(ctx, "server-id")
+	g.Expect(errors.Is(err, context.Canceled)).To(BeTrue(), "unexpected error %v", err)
+}
+
+func TestContextClient_Reauthenticate(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reauths := 0
+	shared := &gophercloud.ProviderClient{}
+	shared.UseTokenLock()
+	shared.SetToken("old-token")
+	shared.ReauthFunc = func() error {
+		reauths++
+		shared.SetToken("new-token")
+		return nil
+	}
+	serviceClient := &gophercloud.ServiceClient{ProviderClient: shared, Endpoint: server.URL + "/"}
+
+	for i := 0; i < 2; i++ {
+		client := contextClient(context.Background(), serviceClient)
+		_, err := client.Get(client.ServiceURL("servers"), nil, &gophercloud.RequestOpts{OkCodes: []int{http.StatusNoContent}})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	// The token of the first reauthentication is reused by later calls
+	g.Expect(reauths).To(Equal(1))
+	g.Expect(shared.Token()).To(Equal("new-token"))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+type DNSClient interface {
+	ListZones(ctx context.Context, opts zones.ListOptsBuilder) ([]zones.Zone, error)
+	ListRecordSets(ctx context.Context, zoneID string, opts recordsets.ListOptsBuilder) ([]recordsets.RecordSet, error)
+	CreateRecordSet(ctx context.Context, zoneID string, opts recordsets.CreateOptsBuilder) (*recordsets.RecordSet, error)
+	UpdateRecordSet(ctx context.Context, zoneID, id string, opts recordsets.UpdateOptsBuilder) (*recordsets.RecordSet, error)
+	DeleteRecordSet(ctx context.Context, zoneID, id string) error
+}
+
+type dnsClient struct{ client *gophercloud.ServiceClient }
+
+// NewDNSClient returns a new designate client.
+func NewDNSClient(scope *scope.Scope) (DNSClient, error) {
+	dns, err := openstack.NewDNSV2(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dns service client: %v", err)
+	}
+
+	return &dnsClient{dns}, nil
+}
+
+func (c dnsClient) ListZones(ctx context.Context, opts zones.ListOptsBuilder) ([]zones.Zone, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("dns_zone", "list")
+	pages, err := zones.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return zones.ExtractZones(pages)
+}
+
+func (c dnsClient) ListRecordSets(ctx context.Context, zoneID string, opts recordsets.ListOptsBuilder) ([]recordsets.RecordSet, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("dns_recordset", "list")
+	pages, err := recordsets.ListByZone(client, zoneID, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return recordsets.ExtractRecordSets(pages)
+}
+
+func (c dnsClient) CreateRecordSet(ctx context.Context, zoneID string, opts recordsets.CreateOptsBuilder) (*recordsets.RecordSet, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("dns_recordset", "create")
+	recordSet, err := recordsets.Create(client, zoneID, opts).Extract()
+	return recordSet, mc.ObserveRequest(err)
+}
+
+func (c dnsClient) UpdateRecordSet(ctx context.Context, zoneID, id string, opts recordsets.UpdateOptsBuilder) (*recordsets.RecordSet, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("dns_recordset", "update")
+	recordSet, err := recordsets.Update(client, zoneID, id, opts).Extract()
+	return recordSet, mc.ObserveRequest(err)
+}
+
+func (c dnsClient) DeleteRecordSet(ctx context.Context, zoneID, id string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("dns_recordset", "delete")
+	err := recordsets.Delete(client, zoneID, id).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixture records the requests of the OpenStack clients to a cloud and
+// replays them in unit tests, so that tests can cover the responses of real
+// clouds without access to one.
+//
+// Fixtures are stored as YAML in the testdata/fixtures directory of the package
+// under test. Tests replay them by default. Setting CAPO_RECORD_FIXTURES=true
+// records them instead against the cloud named by OS_CLOUD in clouds.yaml.
+// Tokens, passwords and other secrets, the project ID and the host names of
+// the cloud are scrubbed before fixtures are written.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RecordEnv is the environment variable which enables recording of fixtures.
+	RecordEnv = "CAPO_RECORD_FIXTURES"
+
+	// Endpoint is the endpoint of all services when fixtures are replayed.
+	Endpoint = "http://openstack.fixture/"
+	// ProjectID replaces the ID of the project of the cloud in recorded fixtures.
+	ProjectID = "fixture-project-id"
+
+	redacted = "REDACTED"
+)
+
+// responseHeaders are the response headers which are recorded. Other headers are
+// dropped, as they may contain tokens or cookies.
+var responseHeaders = []string{
+	"Content-Type",
+	"Location",
+	"Retry-After",
+	"Openstack-Api-Version",
+	"X-Openstack-Nova-Api-Version",
+}
+
+// secretFields matches the JSON fields of request and response bodies which contain secrets.
+var secretFields = regexp.MustCompile(`"(adminPass|password|secret|private_key|token|id_token|access_token|payload)"(\s*):(\s*)"(?:[^"\\]|\\.)*"`)
+
+// NewServiceClientFunc creates a service client, e.g. openstack.NewComputeV2.
+type NewServiceClientFunc func(*gophercloud.ProviderClient, gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// Path is the path and query of the request, relative to the endpoint of the service.
+	Path string `json:"path"`
+	// RequestBody is the body of the request. If it is set, replayed requests must have
+	// an equivalent JSON body.
+	RequestBody string `json:"requestBody,omitempty"`
+
+	// StatusCode is the status code of the response.
+	StatusCode int `json:"statusCode"`
+	// Headers are the headers of the response.
+	Headers map[string]string `json:"headers,omitempty"`
+	// ResponseBody is the body of the response.
+	ResponseBody string `json:"responseBody,omitempty"`
+}
+
+// Fixture is a recorded sequence of interactions with a service.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewServiceClient returns a service client which replays the fixture testdata/fixtures/<name>.yaml,
+// or records it if CAPO_RECORD_FIXTURES is set.
+func NewServiceClient(t testing.TB, name string, newClient NewServiceClientFunc) *gophercloud.ServiceClient {
+	t.Helper()
+
+	path := filepath.Join("testdata", "fixtures", name+".yaml")
+	if record, _ := strconv.ParseBool(os.Getenv(RecordEnv)); record {
+		return newRecordingServiceClient(t, path, newClient)
+	}
+	return newReplayingServiceClient(t, path, newClient)
+}
+
+func newReplayingServiceClient(t testing.TB, path string, newClient NewServiceClientFunc) *gophercloud.ServiceClient {
+	t.Helper()
+
+	fixture, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	r := &replayer{fixture: fixture}
+	providerClient := &gophercloud.ProviderClient{
+		HTTPClient: http.Client{Transport: r},
+		EndpointLocator: func(gophercloud.EndpointOpts) (string, error) {
+			return Endpoint, nil
+		},
+	}
+	serviceClient, err := newClient(providerClient, gophercloud.EndpointOpts{})
+	if err != nil {
+		t.Fatalf("failed to create service client: %v", err)
+	}
+	// Paths are recorded relative to the endpoint of the service client
+	r.endpoint = serviceClient.Endpoint
+	return serviceClient
+}
+
+func newRecordingServiceClient(t testing.TB, path string, newClient NewServiceClientFunc) *gophercloud.ServiceClient {
+	t.Helper()
+
+	clientOpts := &clientconfig.ClientOpts{Cloud: os.Getenv("OS_CLOUD")}
+	cloud, err := clientconfig.GetCloudFromYAML(clientOpts)
+	if err != nil {
+		t.Fatalf("failed to read clouds.yaml: %v", err)
+	}
+	authOpts, err := clientconfig.AuthOptions(clientOpts)
+	if err != nil {
+		t.Fatalf("failed to get auth options: %v", err)
+	}
+	// The authentication request is not recorded.
+	providerClient, err := openstack.AuthenticatedClient(*authOpts)
+	if err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	serviceClient, err := newClient(providerClient, gophercloud.EndpointOpts{Region: cloud.RegionName})
+	if err != nil {
+		t.Fatalf("failed to create service client: %v", err)
+	}
+
+	projectID := authOpts.TenantID
+	if authResult, ok := providerClient.GetAuthResult().(tokens.CreateResult); ok {
+		if project, err := authResult.ExtractProject(); err == nil && project != nil {
+			projectID = project.ID
+		}
+	}
+
+	r := &recorder{
+		endpoint:  serviceClient.Endpoint,
+		transport: http.DefaultTransport,
+		scrubber:  newScrubber(serviceClient.Endpoint, projectID),
+	}
+	providerClient.HTTPClient.Transport = r
+	t.Cleanup(func() {
+		if err := r.fixture.Save(path); err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+	return serviceClient
+}
+
+// Load reads a fixture from a file.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err := yaml.UnmarshalStrict(data, fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// Save writes a fixture to a file, creating its directory if necessary.
+func (f *Fixture) Save(path string) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// replayer is an http.RoundTripper which responds to requests with the recorded responses
+// of a fixture. Each interaction is replayed once, in the order in which it was recorded.
+type replayer struct {
+	endpoint string
+
+	mu      sync.Mutex
+	fixture *Fixture
+	used    []bool
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.String(), r.endpoint)
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.used == nil {
+		r.used = make([]bool, len(r.fixture.Interactions))
+	}
+	for i := range r.fixture.Interactions {
+		interaction := &r.fixture.Interactions[i]
+		if r.used[i] || interaction.Method != req.Method || interaction.Path != path {
+			continue
+		}
+		if interaction.RequestBody != "" && !equivalentBodies(interaction.RequestBody, secretFields.ReplaceAllString(body, `"$1"$2:$3"`+redacted+`"`)) {
+			continue
+		}
+		r.used[i] = true
+		return interaction.response(req), nil
+	}
+	return nil, fmt.Errorf("fixture has no interaction for %s %s", req.Method, path)
+}
+
+func (i *Interaction) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range i.Headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(i.ResponseBody)),
+		ContentLength: int64(len(i.ResponseBody)),
+		Request:       req,
+	}
+}
+
+// recorder is an http.RoundTripper which records the requests sent through it and their
+// responses in a fixture.
+type recorder struct {
+	endpoint  string
+	transport http.RoundTripper
+	scrubber  *strings.Replacer
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Method:       req.Method,
+		Path:         r.scrub(strings.TrimPrefix(req.URL.String(), r.endpoint)),
+		RequestBody:  r.scrub(requestBody),
+		StatusCode:   resp.StatusCode,
+		ResponseBody: r.scrub(string(responseBody)),
+	}
+	for _, k := range responseHeaders {
+		if v := resp.Header.Get(k); v != "" {
+			if interaction.Headers == nil {
+				interaction.Headers = map[string]string{}
+			}
+			interaction.Headers[k] = r.scrub(v)
+		}
+	}
+
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *recorder) scrub(s string) string {
+	s = secretFields.ReplaceAllString(s, `"$1"$2:$3"`+redacted+`"`)
+	if r.scrubber != nil {
+		s = r.scrubber.Replace(s)
+	}
+	return s
+}
+
+// newScrubber returns a replacer which replaces the endpoint of the service and the
+// project ID of the cloud with the values used when fixtures are replayed.
+func newScrubber(endpoint, projectID string) *strings.Replacer {
+	oldnew := []string{endpoint, Endpoint}
+	if host := hostOf(endpoint); host != "" {
+		oldnew = append(oldnew, host, strings.TrimSuffix(strings.TrimPrefix(Endpoint, "http://"), "/"))
+	}
+	if projectID != "" {
+		oldnew = append(oldnew, projectID, ProjectID)
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+func hostOf(endpoint string) string {
+	s := endpoint
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// equivalentBodies returns true if both bodies are equal JSON documents, or equal strings if
+// either is not JSON.
+func equivalentBodies(a, b string) bool {
+	var ja, jb interface{}
+	if json.Unmarshal([]byte(a), &ja) != nil || json.Unmarshal([]byte(b), &jb) != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(ja, jb)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixture
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	g := NewWithT(t)
+
+	const projectID = "0123456789abcdef"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "gAAAAAsecret")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2.1/servers":
+			body, _ := io.ReadAll(r.Body)
+			g.Expect(string(body)).To(ContainSubstring(`"adminPass":"hunter2"`))
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = io.WriteString(w, `{"server": {"id": "server-id", "adminPass": "hunter2", "links": [{"rel": "self", "href": "`+server.URL+`/v2.1/`+projectID+`/servers/server-id"}]}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2.1/servers/server-id":
+			_, _ = io.WriteString(w, `{"server": {"id": "server-id", "name": "test", "status": "ERROR", "tenant_id": "`+projectID+`", "fault": {"code": 500, "message": "No valid host was found."}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"itemNotFound": {"code": 404, "message": "Instance could not be found."}}`)
+		}
+	}))
+	defer server.Close()
+
+	endpoint := server.URL + "/v2.1/"
+	r := &recorder{
+		endpoint:  endpoint,
+		transport: http.DefaultTransport,
+		scrubber:  newScrubber(endpoint, projectID),
+	}
+	providerClient := &gophercloud.ProviderClient{HTTPClient: http.Client{Transport: r}}
+	serviceClient := &gophercloud.ServiceClient{ProviderClient: providerClient, Endpoint: endpoint}
+
+	exercise := func(serviceClient *gophercloud.ServiceClient) {
+		created, err := servers.Create(serviceClient, servers.CreateOpts{Name: "test", FlavorRef: "flavor", AdminPass: "hunter2"}).Extract()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(created.ID).To(Equal("server-id"))
+
+		got, err := servers.Get(serviceClient, "server-id").Extract()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(got.Status).To(Equal("ERROR"))
+		g.Expect(got.Fault.Message).To(Equal("No valid host was found."))
+
+		_, err = servers.Get(serviceClient, "missing").Extract()
+		g.Expect(err).To(BeAssignableToTypeOf(gophercloud.ErrDefault404{}))
+	}
+	exercise(serviceClient)
+
+	path := filepath.Join(t.TempDir(), "fixtures", "servers.yaml")
+	g.Expect(r.fixture.Save(path)).To(Succeed())
+
+	data, err := os.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, secret := range []string{"hunter2", "gAAAAAsecret", projectID, strings.TrimPrefix(server.URL, "http://")} {
+		g.Expect(string(data)).NotTo(ContainSubstring(secret))
+	}
+	g.Expect(string(data)).To(ContainSubstring(ProjectID))
+
+	fixture, err := Load(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fixture.Interactions).To(HaveLen(3))
+	g.Expect(fixture.Interactions[0].Path).To(Equal("servers"))
+	g.Expect(fixture.Interactions[0].Headers).To(Equal(map[string]string{"Content-Type": "application/json"}))
+
+	// The fixture is replayed without the server
+	server.Close()
+	exercise(newReplayingServiceClient(t, path, func(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error) {
+		serviceClient, err := openstack.NewComputeV2(providerClient, eo)
+		if err != nil {
+			return nil, err
+		}
+		serviceClient.Endpoint += "v2.1/"
+		return serviceClient, nil
+	}))
+}
+
+func TestReplayUnexpectedRequest(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &replayer{
+		endpoint: Endpoint,
+		fixture: &Fixture{Interactions: []Interaction{
+			{Method: http.MethodPost, Path: "servers", RequestBody: `{"server": {"name": "a"}}`, StatusCode: http.StatusAccepted},
+		}},
+	}
+	providerClient := &gophercloud.ProviderClient{HTTPClient: http.Client{Transport: r}}
+	serviceClient := &gophercloud.ServiceClient{ProviderClient: providerClient, Endpoint: Endpoint}
+
+	// Requests must match the method, path and body of an interaction
+	_, err := servers.Get(serviceClient, "server-id").Extract()
+	g.Expect(err).To(MatchError(ContainSubstring("fixture has no interaction for GET servers/server-id")))
+	err = servers.Create(serviceClient, servers.CreateOpts{Name: "b", FlavorRef: "flavor"}).Err
+	g.Expect(err).To(MatchError(ContainSubstring("fixture has no interaction for POST servers")))
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imagedata"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imageimport"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
@@ -28,7 +32,13 @@ import (
 )
 
 type ImageClient interface {
-	ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error)
+	ListImages(ctx context.Context, listOpts images.ListOptsBuilder) ([]images.Image, error)
+	GetImage(ctx context.Context, id string) (*images.Image, error)
+	CreateImage(ctx context.Context, opts images.CreateOptsBuilder) (*images.Image, error)
+	UpdateImage(ctx context.Context, id string, opts images.UpdateOptsBuilder) (*images.Image, error)
+	DeleteImage(ctx context.Context, id string) error
+	ImportImage(ctx context.Context, id string, opts imageimport.CreateOptsBuilder) error
+	UploadImageData(ctx context.Context, id string, data io.Reader) error
 }
 
 type imageClient struct{ client *gophercloud.ServiceClient }
@@ -45,15 +55,75 @@ func NewImageClient(scope *scope.Scope) (ImageClient, error) {
 	return imageClient{images}, nil
 }
 
-func (c imageClient) ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error) {
+func (c imageClient) ListImages(ctx context.Context, listOpts images.ListOptsBuilder) ([]images.Image, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("image", "list")
-	pages, err := images.List(c.client, listOpts).AllPages()
+	pages, err := images.List(client, listOpts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return images.ExtractImages(pages)
 }
 
+func (c imageClient) GetImage(ctx context.Context, id string) (*images.Image, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("image", "get")
+	image, err := images.Get(client, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (c imageClient) CreateImage(ctx context.Context, opts images.CreateOptsBuilder) (*images.Image, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("image", "create")
+	image, err := images.Create(client, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (c imageClient) UpdateImage(ctx context.Context, id string, opts images.UpdateOptsBuilder) (*images.Image, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("image", "update")
+	image, err := images.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (c imageClient) DeleteImage(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("image", "delete")
+	err := images.Delete(client, id).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (c imageClient) ImportImage(ctx context.Context, id string, opts imageimport.CreateOptsBuilder) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("image_import", "create")
+	err := imageimport.Create(client, id, opts).ExtractErr()
+	return mc.ObserveRequest(err)
+}
+
+// UploadImageData uploads the data of the image. The upload is not limited by the request timeout, as large images
+// take long to upload.
+func (c imageClient) UploadImageData(ctx context.Context, id string, data io.Reader) error {
+	client := contextClient(ctx, c.client)
+	mc := metrics.NewMetricPrometheusContext("image_data", "upload")
+	err := imagedata.Upload(client, id, data).ExtractErr()
+	return mc.ObserveRequest(err)
+}
+
 type imageErrorClient struct{ error }
 
 // NewImageErrorClient returns an ImageClient in which every method returns the given error.
@@ -61,6 +131,30 @@ func NewImageErrorClient(e error) ImageClient {
 	return imageErrorClient{e}
 }
 
-func (e imageErrorClient) ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error) {
+func (e imageErrorClient) ListImages(ctx context.Context, listOpts images.ListOptsBuilder) ([]images.Image, error) {
+	return nil, e.error
+}
+
+func (e imageErrorClient) GetImage(ctx context.Context, id string) (*images.Image, error) {
+	return nil, e.error
+}
+
+func (e imageErrorClient) CreateImage(ctx context.Context, opts images.CreateOptsBuilder) (*images.Image, error) {
 	return nil, e.error
 }
+
+func (e imageErrorClient) UpdateImage(ctx context.Context, id string, opts images.UpdateOptsBuilder) (*images.Image, error) {
+	return nil, e.error
+}
+
+func (e imageErrorClient) DeleteImage(ctx context.Context, id string) error {
+	return e.error
+}
+
+func (e imageErrorClient) ImportImage(ctx context.Context, id string, opts imageimport.CreateOptsBuilder) error {
+	return e.error
+}
+
+func (e imageErrorClient) UploadImageData(ctx context.Context, id string, data io.Reader) error {
+	return e.error
+}
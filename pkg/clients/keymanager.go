@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/secrets"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+type KeyManagerClient interface {
+	ListContainers(ctx context.Context, opts containers.ListOptsBuilder) ([]containers.Container, error)
+	GetContainer(ctx context.Context, id string) (*containers.Container, error)
+	ListSecrets(ctx context.Context, opts secrets.ListOptsBuilder) ([]secrets.Secret, error)
+	GetSecret(ctx context.Context, id string) (*secrets.Secret, error)
+}
+
+type keyManagerClient struct{ client *gophercloud.ServiceClient }
+
+// NewKeyManagerClient returns a new barbican client.
+func NewKeyManagerClient(scope *scope.Scope) (KeyManagerClient, error) {
+	keyManager, err := openstack.NewKeyManagerV1(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key manager service client: %v", err)
+	}
+
+	return &keyManagerClient{keyManager}, nil
+}
+
+func (c keyManagerClient) ListContainers(ctx context.Context, opts containers.ListOptsBuilder) ([]containers.Container, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keymanager_container", "list")
+	pages, err := containers.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return containers.ExtractContainers(pages)
+}
+
+func (c keyManagerClient) GetContainer(ctx context.Context, id string) (*containers.Container, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keymanager_container", "get")
+	container, err := containers.Get(client, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+func (c keyManagerClient) ListSecrets(ctx context.Context, opts secrets.ListOptsBuilder) ([]secrets.Secret, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keymanager_secret", "list")
+	pages, err := secrets.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return secrets.ExtractSecrets(pages)
+}
+
+func (c keyManagerClient) GetSecret(ctx context.Context, id string) (*secrets.Secret, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("keymanager_secret", "get")
+	secret, err := secrets.Get(client, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return secret, nil
+}
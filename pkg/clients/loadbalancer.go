@@ -17,11 +17,13 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
 	"github.com/gophercloud/gophercloud/openstack/compute/apiversions"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/l7policies"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
@@ -34,27 +36,51 @@ import (
 )
 
 type LbClient interface {
-	CreateLoadBalancer(opts loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error)
-	ListLoadBalancers(opts loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error)
-	GetLoadBalancer(id string) (*loadbalancers.LoadBalancer, error)
-	DeleteLoadBalancer(id string, opts loadbalancers.DeleteOptsBuilder) error
-	CreateListener(opts listeners.CreateOptsBuilder) (*listeners.Listener, error)
-	ListListeners(opts listeners.ListOptsBuilder) ([]listeners.Listener, error)
-	UpdateListener(id string, opts listeners.UpdateOpts) (*listeners.Listener, error)
-	GetListener(id string) (*listeners.Listener, error)
-	DeleteListener(id string) error
-	CreatePool(opts pools.CreateOptsBuilder) (*pools.Pool, error)
-	ListPools(opts pools.ListOptsBuilder) ([]pools.Pool, error)
-	GetPool(id string) (*pools.Pool, error)
-	DeletePool(id string) error
-	CreatePoolMember(poolID string, opts pools.CreateMemberOptsBuilder) (*pools.Member, error)
-	ListPoolMember(poolID string, opts pools.ListMembersOptsBuilder) ([]pools.Member, error)
-	DeletePoolMember(poolID string, lbMemberID string) error
-	CreateMonitor(opts monitors.CreateOptsBuilder) (*monitors.Monitor, error)
-	ListMonitors(opts monitors.ListOptsBuilder) ([]monitors.Monitor, error)
-	DeleteMonitor(id string) error
-	ListLoadBalancerProviders() ([]providers.Provider, error)
-	ListOctaviaVersions() ([]apiversions.APIVersion, error)
+	CreateLoadBalancer(ctx context.Context, opts loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error)
+	ListLoadBalancers(ctx context.Context, opts loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error)
+	GetLoadBalancer(ctx context.Context, id string) (*loadbalancers.LoadBalancer, error)
+	UpdateLoadBalancer(ctx context.Context, id string, opts loadbalancers.UpdateOpts) (*loadbalancers.LoadBalancer, error)
+	DeleteLoadBalancer(ctx context.Context, id string, opts loadbalancers.DeleteOptsBuilder) error
+	CreateListener(ctx context.Context, opts listeners.CreateOptsBuilder) (*listeners.Listener, error)
+	ListListeners(ctx context.Context, opts listeners.ListOptsBuilder) ([]listeners.Listener, error)
+	UpdateListener(ctx context.Context, id string, opts listeners.UpdateOpts) (*listeners.Listener, error)
+	GetListener(ctx context.Context, id string) (*listeners.Listener, error)
+	DeleteListener(ctx context.Context, id string) error
+	CreatePool(ctx context.Context, opts pools.CreateOptsBuilder) (*pools.Pool, error)
+	ListPools(ctx context.Context, opts pools.ListOptsBuilder) ([]pools.Pool, error)
+	GetPool(ctx context.Context, id string) (*pools.Pool, error)
+	UpdatePool(ctx context.Context, id string, opts pools.UpdateOpts) (*pools.Pool, error)
+	DeletePool(ctx context.Context, id string) error
+	CreatePoolMember(ctx context.Context, poolID string, opts pools.CreateMemberOptsBuilder) (*pools.Member, error)
+	ListPoolMember(ctx context.Context, poolID string, opts pools.ListMembersOptsBuilder) ([]pools.Member, error)
+	DeletePoolMember(ctx context.Context, poolID string, lbMemberID string) error
+	CreateMonitor(ctx context.Context, opts monitors.CreateOptsBuilder) (*monitors.Monitor, error)
+	ListMonitors(ctx context.Context, opts monitors.ListOptsBuilder) ([]monitors.Monitor, error)
+	UpdateMonitor(ctx context.Context, id string, opts monitors.UpdateOptsBuilder) (*monitors.Monitor, error)
+	DeleteMonitor(ctx context.Context, id string) error
+	CreateL7Policy(ctx context.Context, opts l7policies.CreateOptsBuilder) (*l7policies.L7Policy, error)
+	ListL7Policies(ctx context.Context, opts l7policies.ListOptsBuilder) ([]l7policies.L7Policy, error)
+	DeleteL7Policy(ctx context.Context, id string) error
+	ListLoadBalancerProviders(ctx context.Context) ([]providers.Provider, error)
+	ListLoadBalancerFlavors(ctx context.Context) ([]LoadBalancerFlavor, error)
+	ListLoadBalancerAvailabilityZones(ctx context.Context) ([]LoadBalancerAvailabilityZone, error)
+	ListOctaviaVersions(ctx context.Context) ([]apiversions.APIVersion, error)
+}
+
+// LoadBalancerFlavor is an Octavia flavor. gophercloud has no bindings for the
+// Octavia flavors API yet.
+type LoadBalancerFlavor struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// LoadBalancerAvailabilityZone is an Octavia availability zone. gophercloud has no
+// bindings for the Octavia availability zones API yet.
+type LoadBalancerAvailabilityZone struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
 }
 
 type lbClient struct {
@@ -73,179 +99,285 @@ func NewLbClient(scope *scope.Scope) (LbClient, error) {
 	return &lbClient{loadbalancerClient}, nil
 }
 
-func (l lbClient) CreateLoadBalancer(opts loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error) {
+func (l lbClient) CreateLoadBalancer(ctx context.Context, opts loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer", "create")
-	lb, err := loadbalancers.Create(l.serviceClient, opts).Extract()
+	lb, err := loadbalancers.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return lb, nil
 }
 
-func (l lbClient) ListLoadBalancers(opts loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error) {
+func (l lbClient) ListLoadBalancers(ctx context.Context, opts loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer", "list")
-	allPages, err := loadbalancers.List(l.serviceClient, opts).AllPages()
+	allPages, err := loadbalancers.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return loadbalancers.ExtractLoadBalancers(allPages)
 }
 
-func (l lbClient) GetLoadBalancer(id string) (*loadbalancers.LoadBalancer, error) {
+func (l lbClient) GetLoadBalancer(ctx context.Context, id string) (*loadbalancers.LoadBalancer, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer", "get")
-	lb, err := loadbalancers.Get(l.serviceClient, id).Extract()
+	lb, err := loadbalancers.Get(client, id).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return lb, nil
 }
 
-func (l lbClient) DeleteLoadBalancer(id string, opts loadbalancers.DeleteOptsBuilder) error {
+func (l lbClient) DeleteLoadBalancer(ctx context.Context, id string, opts loadbalancers.DeleteOptsBuilder) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer", "delete")
-	err := loadbalancers.Delete(l.serviceClient, id, opts).ExtractErr()
+	err := loadbalancers.Delete(client, id, opts).ExtractErr()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
 		return err
 	}
 	return nil
 }
 
-func (l lbClient) CreateListener(opts listeners.CreateOptsBuilder) (*listeners.Listener, error) {
+func (l lbClient) CreateListener(ctx context.Context, opts listeners.CreateOptsBuilder) (*listeners.Listener, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "create")
-	listener, err := listeners.Create(l.serviceClient, opts).Extract()
+	listener, err := listeners.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return listener, nil
 }
 
-func (l lbClient) UpdateListener(id string, opts listeners.UpdateOpts) (*listeners.Listener, error) {
+func (l lbClient) UpdateLoadBalancer(ctx context.Context, id string, opts loadbalancers.UpdateOpts) (*loadbalancers.LoadBalancer, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer", "update")
+	lb, err := loadbalancers.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+func (l lbClient) UpdateListener(ctx context.Context, id string, opts listeners.UpdateOpts) (*listeners.Listener, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "update")
-	listener, err := listeners.Update(l.serviceClient, id, opts).Extract()
+	listener, err := listeners.Update(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return listener, nil
 }
 
-func (l lbClient) ListListeners(opts listeners.ListOptsBuilder) ([]listeners.Listener, error) {
+func (l lbClient) ListListeners(ctx context.Context, opts listeners.ListOptsBuilder) ([]listeners.Listener, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "list")
-	allPages, err := listeners.List(l.serviceClient, opts).AllPages()
+	allPages, err := listeners.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return listeners.ExtractListeners(allPages)
 }
 
-func (l lbClient) GetListener(id string) (*listeners.Listener, error) {
+func (l lbClient) GetListener(ctx context.Context, id string) (*listeners.Listener, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "get")
-	listener, err := listeners.Get(l.serviceClient, id).Extract()
+	listener, err := listeners.Get(client, id).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return listener, nil
 }
 
-func (l lbClient) DeleteListener(id string) error {
+func (l lbClient) DeleteListener(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "delete")
-	err := listeners.Delete(l.serviceClient, id).ExtractErr()
+	err := listeners.Delete(client, id).ExtractErr()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
 		return fmt.Errorf("error deleting lbaas listener %s: %v", id, err)
 	}
 	return nil
 }
 
-func (l lbClient) CreatePool(opts pools.CreateOptsBuilder) (*pools.Pool, error) {
+func (l lbClient) CreatePool(ctx context.Context, opts pools.CreateOptsBuilder) (*pools.Pool, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "create")
-	pool, err := pools.Create(l.serviceClient, opts).Extract()
+	pool, err := pools.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return pool, nil
 }
 
-func (l lbClient) ListPools(opts pools.ListOptsBuilder) ([]pools.Pool, error) {
+func (l lbClient) ListPools(ctx context.Context, opts pools.ListOptsBuilder) ([]pools.Pool, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "list")
-	allPages, err := pools.List(l.serviceClient, opts).AllPages()
+	allPages, err := pools.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return pools.ExtractPools(allPages)
 }
 
-func (l lbClient) GetPool(id string) (*pools.Pool, error) {
+func (l lbClient) GetPool(ctx context.Context, id string) (*pools.Pool, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "get")
-	pool, err := pools.Get(l.serviceClient, id).Extract()
+	pool, err := pools.Get(client, id).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return pool, nil
 }
 
-func (l lbClient) DeletePool(id string) error {
+func (l lbClient) UpdatePool(ctx context.Context, id string, opts pools.UpdateOpts) (*pools.Pool, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "update")
+	pool, err := pools.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func (l lbClient) DeletePool(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "delete")
-	err := pools.Delete(l.serviceClient, id).ExtractErr()
+	err := pools.Delete(client, id).ExtractErr()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
 		return fmt.Errorf("error deleting lbaas pool %s: %v", id, err)
 	}
 	return nil
 }
 
-func (l lbClient) CreatePoolMember(poolID string, lbMemberOpts pools.CreateMemberOptsBuilder) (*pools.Member, error) {
+func (l lbClient) CreatePoolMember(ctx context.Context, poolID string, lbMemberOpts pools.CreateMemberOptsBuilder) (*pools.Member, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_member", "create")
-	member, err := pools.CreateMember(l.serviceClient, poolID, lbMemberOpts).Extract()
+	member, err := pools.CreateMember(client, poolID, lbMemberOpts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, fmt.Errorf("error create lbmember: %s", err)
 	}
 	return member, nil
 }
 
-func (l lbClient) ListPoolMember(poolID string, opts pools.ListMembersOptsBuilder) ([]pools.Member, error) {
+func (l lbClient) ListPoolMember(ctx context.Context, poolID string, opts pools.ListMembersOptsBuilder) ([]pools.Member, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_pool", "list")
-	allPages, err := pools.ListMembers(l.serviceClient, poolID, opts).AllPages()
+	allPages, err := pools.ListMembers(client, poolID, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return pools.ExtractMembers(allPages)
 }
 
-func (l lbClient) DeletePoolMember(poolID string, lbMemberID string) error {
+func (l lbClient) DeletePoolMember(ctx context.Context, poolID string, lbMemberID string) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_member", "delete")
-	err := pools.DeleteMember(l.serviceClient, poolID, lbMemberID).ExtractErr()
+	err := pools.DeleteMember(client, poolID, lbMemberID).ExtractErr()
 	if mc.ObserveRequest(err) != nil {
 		return fmt.Errorf("error deleting lbmember: %s", err)
 	}
 	return nil
 }
 
-func (l lbClient) CreateMonitor(opts monitors.CreateOptsBuilder) (*monitors.Monitor, error) {
+func (l lbClient) CreateMonitor(ctx context.Context, opts monitors.CreateOptsBuilder) (*monitors.Monitor, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_healthmonitor", "create")
-	monitor, err := monitors.Create(l.serviceClient, opts).Extract()
+	monitor, err := monitors.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return monitor, nil
 }
 
-func (l lbClient) ListMonitors(opts monitors.ListOptsBuilder) ([]monitors.Monitor, error) {
+func (l lbClient) ListMonitors(ctx context.Context, opts monitors.ListOptsBuilder) ([]monitors.Monitor, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_healthmonitor", "list")
-	allPages, err := monitors.List(l.serviceClient, opts).AllPages()
+	allPages, err := monitors.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return monitors.ExtractMonitors(allPages)
 }
 
-func (l lbClient) DeleteMonitor(id string) error {
+func (l lbClient) UpdateMonitor(ctx context.Context, id string, opts monitors.UpdateOptsBuilder) (*monitors.Monitor, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_healthmonitor", "update")
+	monitor, err := monitors.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return monitor, nil
+}
+
+func (l lbClient) DeleteMonitor(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_healthmonitor", "delete")
-	err := monitors.Delete(l.serviceClient, id).ExtractErr()
+	err := monitors.Delete(client, id).ExtractErr()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
 		return fmt.Errorf("error deleting lbaas monitor %s: %v", id, err)
 	}
 	return nil
 }
 
-func (l lbClient) ListLoadBalancerProviders() ([]providers.Provider, error) {
-	allPages, err := providers.List(l.serviceClient, providers.ListOpts{}).AllPages()
+func (l lbClient) CreateL7Policy(ctx context.Context, opts l7policies.CreateOptsBuilder) (*l7policies.L7Policy, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_l7policy", "create")
+	policy, err := l7policies.Create(client, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (l lbClient) ListL7Policies(ctx context.Context, opts l7policies.ListOptsBuilder) ([]l7policies.L7Policy, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_l7policy", "list")
+	allPages, err := l7policies.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return l7policies.ExtractL7Policies(allPages)
+}
+
+func (l lbClient) DeleteL7Policy(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_l7policy", "delete")
+	err := l7policies.Delete(client, id).ExtractErr()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting lbaas l7 policy %s: %v", id, err)
+	}
+	return nil
+}
+
+func (l lbClient) ListLoadBalancerProviders(ctx context.Context) ([]providers.Provider, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	allPages, err := providers.List(client, providers.ListOpts{}).AllPages()
 	if err != nil {
 		return nil, fmt.Errorf("listing providers: %v", err)
 	}
@@ -256,9 +388,39 @@ func (l lbClient) ListLoadBalancerProviders() ([]providers.Provider, error) {
 	return providersList, nil
 }
 
-func (l lbClient) ListOctaviaVersions() ([]apiversions.APIVersion, error) {
+func (l lbClient) ListLoadBalancerFlavors(ctx context.Context) ([]LoadBalancerFlavor, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_flavor", "list")
+	var body struct {
+		Flavors []LoadBalancerFlavor `json:"flavors"`
+	}
+	_, err := client.Get(client.ServiceURL("lbaas", "flavors"), &body, nil)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return body.Flavors, nil
+}
+
+func (l lbClient) ListLoadBalancerAvailabilityZones(ctx context.Context) ([]LoadBalancerAvailabilityZone, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_availability_zone", "list")
+	var body struct {
+		AvailabilityZones []LoadBalancerAvailabilityZone `json:"availability_zones"`
+	}
+	_, err := client.Get(client.ServiceURL("lbaas", "availabilityzones"), &body, nil)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return body.AvailabilityZones, nil
+}
+
+func (l lbClient) ListOctaviaVersions(ctx context.Context) ([]apiversions.APIVersion, error) {
+	client, cancel := withContext(ctx, l.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("version", "list")
-	allPages, err := apiversions.List(l.serviceClient).AllPages()
+	allPages, err := apiversions.List(client).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
@@ -21,11 +21,20 @@ limitations under the License.
 package mock
 
 import (
+	context "context"
+	rsa "crypto/rsa"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	attachinterfaces "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	availabilityzones "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	evacuate "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/evacuate"
+	hypervisors "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	instanceactions "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	keypairs "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	limits "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/limits"
+	servergroups "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	volumeattach "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	servers "github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	clients "sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 )
@@ -53,120 +62,504 @@ func (m *MockComputeClient) EXPECT() *MockComputeClientMockRecorder {
 	return m.recorder
 }
 
+// AttachVolume mocks base method.
+func (m *MockComputeClient) AttachVolume(arg0 context.Context, arg1 string, arg2 volumeattach.CreateOptsBuilder) (*volumeattach.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachVolume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*volumeattach.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachVolume indicates an expected call of AttachVolume.
+func (mr *MockComputeClientMockRecorder) AttachVolume(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachVolume", reflect.TypeOf((*MockComputeClient)(nil).AttachVolume), arg0, arg1, arg2)
+}
+
+// ClearServerPassword mocks base method.
+func (m *MockComputeClient) ClearServerPassword(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearServerPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearServerPassword indicates an expected call of ClearServerPassword.
+func (mr *MockComputeClientMockRecorder) ClearServerPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearServerPassword", reflect.TypeOf((*MockComputeClient)(nil).ClearServerPassword), arg0, arg1)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockComputeClient) CreateKeyPair(arg0 context.Context, arg1 keypairs.CreateOptsBuilder) (*keypairs.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", arg0, arg1)
+	ret0, _ := ret[0].(*keypairs.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockComputeClientMockRecorder) CreateKeyPair(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockComputeClient)(nil).CreateKeyPair), arg0, arg1)
+}
+
 // CreateServer mocks base method.
-func (m *MockComputeClient) CreateServer(arg0 servers.CreateOptsBuilder) (*clients.ServerExt, error) {
+func (m *MockComputeClient) CreateServer(arg0 context.Context, arg1 servers.CreateOptsBuilder) (*clients.ServerExt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateServer", arg0)
+	ret := m.ctrl.Call(m, "CreateServer", arg0, arg1)
 	ret0, _ := ret[0].(*clients.ServerExt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateServer indicates an expected call of CreateServer.
-func (mr *MockComputeClientMockRecorder) CreateServer(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) CreateServer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServer", reflect.TypeOf((*MockComputeClient)(nil).CreateServer), arg0, arg1)
+}
+
+// CreateServerGroup mocks base method.
+func (m *MockComputeClient) CreateServerGroup(arg0 context.Context, arg1 servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServerGroup", arg0, arg1)
+	ret0, _ := ret[0].(*servergroups.ServerGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServerGroup indicates an expected call of CreateServerGroup.
+func (mr *MockComputeClientMockRecorder) CreateServerGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServerGroup", reflect.TypeOf((*MockComputeClient)(nil).CreateServerGroup), arg0, arg1)
+}
+
+// CreateServerImage mocks base method.
+func (m *MockComputeClient) CreateServerImage(arg0 context.Context, arg1 string, arg2 servers.CreateImageOptsBuilder) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServerImage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServerImage indicates an expected call of CreateServerImage.
+func (mr *MockComputeClientMockRecorder) CreateServerImage(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServer", reflect.TypeOf((*MockComputeClient)(nil).CreateServer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServerImage", reflect.TypeOf((*MockComputeClient)(nil).CreateServerImage), arg0, arg1, arg2)
+}
+
+// CreateServers mocks base method.
+func (m *MockComputeClient) CreateServers(arg0 context.Context, arg1 servers.CreateOptsBuilder) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServers", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServers indicates an expected call of CreateServers.
+func (mr *MockComputeClientMockRecorder) CreateServers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServers", reflect.TypeOf((*MockComputeClient)(nil).CreateServers), arg0, arg1)
 }
 
 // DeleteAttachedInterface mocks base method.
-func (m *MockComputeClient) DeleteAttachedInterface(arg0, arg1 string) error {
+func (m *MockComputeClient) DeleteAttachedInterface(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAttachedInterface", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteAttachedInterface", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAttachedInterface indicates an expected call of DeleteAttachedInterface.
-func (mr *MockComputeClientMockRecorder) DeleteAttachedInterface(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) DeleteAttachedInterface(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachedInterface", reflect.TypeOf((*MockComputeClient)(nil).DeleteAttachedInterface), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachedInterface", reflect.TypeOf((*MockComputeClient)(nil).DeleteAttachedInterface), arg0, arg1, arg2)
 }
 
 // DeleteServer mocks base method.
-func (m *MockComputeClient) DeleteServer(arg0 string) error {
+func (m *MockComputeClient) DeleteServer(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteServer", arg0)
+	ret := m.ctrl.Call(m, "DeleteServer", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteServer indicates an expected call of DeleteServer.
-func (mr *MockComputeClientMockRecorder) DeleteServer(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) DeleteServer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServer", reflect.TypeOf((*MockComputeClient)(nil).DeleteServer), arg0, arg1)
+}
+
+// DeleteServerGroup mocks base method.
+func (m *MockComputeClient) DeleteServerGroup(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServerGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServerGroup indicates an expected call of DeleteServerGroup.
+func (mr *MockComputeClientMockRecorder) DeleteServerGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServerGroup", reflect.TypeOf((*MockComputeClient)(nil).DeleteServerGroup), arg0, arg1)
+}
+
+// DeleteServerMetadatum mocks base method.
+func (m *MockComputeClient) DeleteServerMetadatum(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServerMetadatum", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServerMetadatum indicates an expected call of DeleteServerMetadatum.
+func (mr *MockComputeClientMockRecorder) DeleteServerMetadatum(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServerMetadatum", reflect.TypeOf((*MockComputeClient)(nil).DeleteServerMetadatum), arg0, arg1, arg2)
+}
+
+// DetachVolume mocks base method.
+func (m *MockComputeClient) DetachVolume(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachVolume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachVolume indicates an expected call of DetachVolume.
+func (mr *MockComputeClientMockRecorder) DetachVolume(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachVolume", reflect.TypeOf((*MockComputeClient)(nil).DetachVolume), arg0, arg1, arg2)
+}
+
+// EvacuateServer mocks base method.
+func (m *MockComputeClient) EvacuateServer(arg0 context.Context, arg1 string, arg2 evacuate.EvacuateOptsBuilder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvacuateServer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvacuateServer indicates an expected call of EvacuateServer.
+func (mr *MockComputeClientMockRecorder) EvacuateServer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvacuateServer", reflect.TypeOf((*MockComputeClient)(nil).EvacuateServer), arg0, arg1, arg2)
+}
+
+// GetConsoleOutput mocks base method.
+func (m *MockComputeClient) GetConsoleOutput(arg0 context.Context, arg1 string, arg2 int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsoleOutput", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsoleOutput indicates an expected call of GetConsoleOutput.
+func (mr *MockComputeClientMockRecorder) GetConsoleOutput(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServer", reflect.TypeOf((*MockComputeClient)(nil).DeleteServer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsoleOutput", reflect.TypeOf((*MockComputeClient)(nil).GetConsoleOutput), arg0, arg1, arg2)
 }
 
 // GetFlavorIDFromName mocks base method.
-func (m *MockComputeClient) GetFlavorIDFromName(arg0 string) (string, error) {
+func (m *MockComputeClient) GetFlavorIDFromName(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFlavorIDFromName", arg0)
+	ret := m.ctrl.Call(m, "GetFlavorIDFromName", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFlavorIDFromName indicates an expected call of GetFlavorIDFromName.
-func (mr *MockComputeClientMockRecorder) GetFlavorIDFromName(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) GetFlavorIDFromName(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlavorIDFromName", reflect.TypeOf((*MockComputeClient)(nil).GetFlavorIDFromName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlavorIDFromName", reflect.TypeOf((*MockComputeClient)(nil).GetFlavorIDFromName), arg0, arg1)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockComputeClient) GetKeyPair(arg0 context.Context, arg1 string) (*keypairs.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", arg0, arg1)
+	ret0, _ := ret[0].(*keypairs.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockComputeClientMockRecorder) GetKeyPair(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockComputeClient)(nil).GetKeyPair), arg0, arg1)
+}
+
+// GetLimits mocks base method.
+func (m *MockComputeClient) GetLimits(arg0 context.Context) (*limits.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", arg0)
+	ret0, _ := ret[0].(*limits.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockComputeClientMockRecorder) GetLimits(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockComputeClient)(nil).GetLimits), arg0)
+}
+
+// GetMaxMicroversion mocks base method.
+func (m *MockComputeClient) GetMaxMicroversion(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxMicroversion", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxMicroversion indicates an expected call of GetMaxMicroversion.
+func (mr *MockComputeClientMockRecorder) GetMaxMicroversion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxMicroversion", reflect.TypeOf((*MockComputeClient)(nil).GetMaxMicroversion), arg0)
 }
 
 // GetServer mocks base method.
-func (m *MockComputeClient) GetServer(arg0 string) (*clients.ServerExt, error) {
+func (m *MockComputeClient) GetServer(arg0 context.Context, arg1 string) (*clients.ServerExt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServer", arg0)
+	ret := m.ctrl.Call(m, "GetServer", arg0, arg1)
 	ret0, _ := ret[0].(*clients.ServerExt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServer indicates an expected call of GetServer.
-func (mr *MockComputeClientMockRecorder) GetServer(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) GetServer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServer", reflect.TypeOf((*MockComputeClient)(nil).GetServer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServer", reflect.TypeOf((*MockComputeClient)(nil).GetServer), arg0, arg1)
+}
+
+// GetServerGroup mocks base method.
+func (m *MockComputeClient) GetServerGroup(arg0 context.Context, arg1 string) (*servergroups.ServerGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerGroup", arg0, arg1)
+	ret0, _ := ret[0].(*servergroups.ServerGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerGroup indicates an expected call of GetServerGroup.
+func (mr *MockComputeClientMockRecorder) GetServerGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerGroup", reflect.TypeOf((*MockComputeClient)(nil).GetServerGroup), arg0, arg1)
+}
+
+// GetServerPassword mocks base method.
+func (m *MockComputeClient) GetServerPassword(arg0 context.Context, arg1 string, arg2 *rsa.PrivateKey) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerPassword indicates an expected call of GetServerPassword.
+func (mr *MockComputeClientMockRecorder) GetServerPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerPassword", reflect.TypeOf((*MockComputeClient)(nil).GetServerPassword), arg0, arg1, arg2)
 }
 
 // ListAttachedInterfaces mocks base method.
-func (m *MockComputeClient) ListAttachedInterfaces(arg0 string) ([]attachinterfaces.Interface, error) {
+func (m *MockComputeClient) ListAttachedInterfaces(arg0 context.Context, arg1 string) ([]attachinterfaces.Interface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAttachedInterfaces", arg0)
+	ret := m.ctrl.Call(m, "ListAttachedInterfaces", arg0, arg1)
 	ret0, _ := ret[0].([]attachinterfaces.Interface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAttachedInterfaces indicates an expected call of ListAttachedInterfaces.
-func (mr *MockComputeClientMockRecorder) ListAttachedInterfaces(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) ListAttachedInterfaces(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachedInterfaces", reflect.TypeOf((*MockComputeClient)(nil).ListAttachedInterfaces), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachedInterfaces", reflect.TypeOf((*MockComputeClient)(nil).ListAttachedInterfaces), arg0, arg1)
 }
 
 // ListAvailabilityZones mocks base method.
-func (m *MockComputeClient) ListAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
+func (m *MockComputeClient) ListAvailabilityZones(arg0 context.Context) ([]availabilityzones.AvailabilityZone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAvailabilityZones")
+	ret := m.ctrl.Call(m, "ListAvailabilityZones", arg0)
 	ret0, _ := ret[0].([]availabilityzones.AvailabilityZone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAvailabilityZones indicates an expected call of ListAvailabilityZones.
-func (mr *MockComputeClientMockRecorder) ListAvailabilityZones() *gomock.Call {
+func (mr *MockComputeClientMockRecorder) ListAvailabilityZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockComputeClient)(nil).ListAvailabilityZones), arg0)
+}
+
+// ListHypervisors mocks base method.
+func (m *MockComputeClient) ListHypervisors(arg0 context.Context, arg1 hypervisors.ListOptsBuilder) ([]hypervisors.Hypervisor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHypervisors", arg0, arg1)
+	ret0, _ := ret[0].([]hypervisors.Hypervisor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHypervisors indicates an expected call of ListHypervisors.
+func (mr *MockComputeClientMockRecorder) ListHypervisors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHypervisors", reflect.TypeOf((*MockComputeClient)(nil).ListHypervisors), arg0, arg1)
+}
+
+// ListInstanceActions mocks base method.
+func (m *MockComputeClient) ListInstanceActions(arg0 context.Context, arg1 string) ([]instanceactions.InstanceAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstanceActions", arg0, arg1)
+	ret0, _ := ret[0].([]instanceactions.InstanceAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstanceActions indicates an expected call of ListInstanceActions.
+func (mr *MockComputeClientMockRecorder) ListInstanceActions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockComputeClient)(nil).ListAvailabilityZones))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceActions", reflect.TypeOf((*MockComputeClient)(nil).ListInstanceActions), arg0, arg1)
+}
+
+// ListServerGroups mocks base method.
+func (m *MockComputeClient) ListServerGroups(arg0 context.Context, arg1 servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServerGroups", arg0, arg1)
+	ret0, _ := ret[0].([]servergroups.ServerGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServerGroups indicates an expected call of ListServerGroups.
+func (mr *MockComputeClientMockRecorder) ListServerGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServerGroups", reflect.TypeOf((*MockComputeClient)(nil).ListServerGroups), arg0, arg1)
+}
+
+// ListServerGroupsOfServer mocks base method.
+func (m *MockComputeClient) ListServerGroupsOfServer(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServerGroupsOfServer", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServerGroupsOfServer indicates an expected call of ListServerGroupsOfServer.
+func (mr *MockComputeClientMockRecorder) ListServerGroupsOfServer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServerGroupsOfServer", reflect.TypeOf((*MockComputeClient)(nil).ListServerGroupsOfServer), arg0, arg1)
 }
 
 // ListServers mocks base method.
-func (m *MockComputeClient) ListServers(arg0 servers.ListOptsBuilder) ([]clients.ServerExt, error) {
+func (m *MockComputeClient) ListServers(arg0 context.Context, arg1 servers.ListOptsBuilder) ([]clients.ServerExt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListServers", arg0)
+	ret := m.ctrl.Call(m, "ListServers", arg0, arg1)
 	ret0, _ := ret[0].([]clients.ServerExt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListServers indicates an expected call of ListServers.
-func (mr *MockComputeClientMockRecorder) ListServers(arg0 interface{}) *gomock.Call {
+func (mr *MockComputeClientMockRecorder) ListServers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServers", reflect.TypeOf((*MockComputeClient)(nil).ListServers), arg0, arg1)
+}
+
+// ListVolumeAttachments mocks base method.
+func (m *MockComputeClient) ListVolumeAttachments(arg0 context.Context, arg1 string) ([]volumeattach.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeAttachments", arg0, arg1)
+	ret0, _ := ret[0].([]volumeattach.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeAttachments indicates an expected call of ListVolumeAttachments.
+func (mr *MockComputeClientMockRecorder) ListVolumeAttachments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeAttachments", reflect.TypeOf((*MockComputeClient)(nil).ListVolumeAttachments), arg0, arg1)
+}
+
+// RebootServer mocks base method.
+func (m *MockComputeClient) RebootServer(arg0 context.Context, arg1 string, arg2 servers.RebootOptsBuilder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebootServer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebootServer indicates an expected call of RebootServer.
+func (mr *MockComputeClientMockRecorder) RebootServer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebootServer", reflect.TypeOf((*MockComputeClient)(nil).RebootServer), arg0, arg1, arg2)
+}
+
+// RebuildServer mocks base method.
+func (m *MockComputeClient) RebuildServer(arg0 context.Context, arg1 string, arg2 servers.RebuildOptsBuilder) (*clients.ServerExt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildServer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*clients.ServerExt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildServer indicates an expected call of RebuildServer.
+func (mr *MockComputeClientMockRecorder) RebuildServer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildServer", reflect.TypeOf((*MockComputeClient)(nil).RebuildServer), arg0, arg1, arg2)
+}
+
+// ReplaceAllServerTags mocks base method.
+func (m *MockComputeClient) ReplaceAllServerTags(arg0 context.Context, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceAllServerTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceAllServerTags indicates an expected call of ReplaceAllServerTags.
+func (mr *MockComputeClientMockRecorder) ReplaceAllServerTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllServerTags", reflect.TypeOf((*MockComputeClient)(nil).ReplaceAllServerTags), arg0, arg1, arg2)
+}
+
+// UpdateServer mocks base method.
+func (m *MockComputeClient) UpdateServer(arg0 context.Context, arg1 string, arg2 servers.UpdateOptsBuilder) (*clients.ServerExt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*clients.ServerExt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateServer indicates an expected call of UpdateServer.
+func (mr *MockComputeClientMockRecorder) UpdateServer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServer", reflect.TypeOf((*MockComputeClient)(nil).UpdateServer), arg0, arg1, arg2)
+}
+
+// UpdateServerMetadata mocks base method.
+func (m *MockComputeClient) UpdateServerMetadata(arg0 context.Context, arg1 string, arg2 servers.UpdateMetadataOptsBuilder) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServerMetadata", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateServerMetadata indicates an expected call of UpdateServerMetadata.
+func (mr *MockComputeClientMockRecorder) UpdateServerMetadata(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServers", reflect.TypeOf((*MockComputeClient)(nil).ListServers), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServerMetadata", reflect.TypeOf((*MockComputeClient)(nil).UpdateServerMetadata), arg0, arg1, arg2)
 }
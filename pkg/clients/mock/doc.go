@@ -19,14 +19,27 @@ package mock
 //go:generate mockgen -package mock -destination=compute.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients ComputeClient
 //go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt compute.go > _compute.go && mv _compute.go compute.go"
 
+//go:generate mockgen -package mock -destination=dns.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients DNSClient
+//go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt dns.go > _dns.go && mv _dns.go dns.go"
+
 //go:generate mockgen -package mock -destination=image.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients ImageClient
 //go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt image.go > _image.go && mv _image.go image.go"
 
+//go:generate mockgen -package mock -destination=keymanager.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients KeyManagerClient
+//go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt keymanager.go > _keymanager.go && mv _keymanager.go keymanager.go"
 //go:generate mockgen -package mock -destination=loadbalancer.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients LbClient
 //go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt loadbalancer.go > _loadbalancer.go && mv _loadbalancer.go loadbalancer.go"
 
 //go:generate mockgen -package mock -destination=network.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients NetworkClient
 //go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt network.go > _network.go && mv _network.go network.go"
 
+//go:generate mockgen -package mock -destination=objectstorage.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients ObjectStorageClient
+//go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt objectstorage.go > _objectstorage.go && mv _objectstorage.go objectstorage.go"
+//go:generate mockgen -package mock -destination=placement.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients PlacementClient
+//go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt placement.go > _placement.go && mv _placement.go placement.go"
+
+//go:generate mockgen -package mock -destination=reservation.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients ReservationClient
+//go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt reservation.go > _reservation.go && mv _reservation.go reservation.go"
+
 //go:generate mockgen -package mock -destination=volume.go sigs.k8s.io/cluster-api-provider-openstack/pkg/clients VolumeClient
 //go:generate /usr/bin/env bash -c "cat ../../../hack/boilerplate/boilerplate.generatego.txt volume.go > _volume.go && mv _volume.go volume.go"
@@ -21,9 +21,12 @@ limitations under the License.
 package mock
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	imageimport "github.com/gophercloud/gophercloud/openstack/imageservice/v2/imageimport"
 	images "github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 )
 
@@ -50,17 +53,104 @@ func (m *MockImageClient) EXPECT() *MockImageClientMockRecorder {
 	return m.recorder
 }
 
+// CreateImage mocks base method.
+func (m *MockImageClient) CreateImage(arg0 context.Context, arg1 images.CreateOptsBuilder) (*images.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImage", arg0, arg1)
+	ret0, _ := ret[0].(*images.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImage indicates an expected call of CreateImage.
+func (mr *MockImageClientMockRecorder) CreateImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImage", reflect.TypeOf((*MockImageClient)(nil).CreateImage), arg0, arg1)
+}
+
+// DeleteImage mocks base method.
+func (m *MockImageClient) DeleteImage(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImage indicates an expected call of DeleteImage.
+func (mr *MockImageClientMockRecorder) DeleteImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImage", reflect.TypeOf((*MockImageClient)(nil).DeleteImage), arg0, arg1)
+}
+
+// GetImage mocks base method.
+func (m *MockImageClient) GetImage(arg0 context.Context, arg1 string) (*images.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImage", arg0, arg1)
+	ret0, _ := ret[0].(*images.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImage indicates an expected call of GetImage.
+func (mr *MockImageClientMockRecorder) GetImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockImageClient)(nil).GetImage), arg0, arg1)
+}
+
+// ImportImage mocks base method.
+func (m *MockImageClient) ImportImage(arg0 context.Context, arg1 string, arg2 imageimport.CreateOptsBuilder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportImage indicates an expected call of ImportImage.
+func (mr *MockImageClientMockRecorder) ImportImage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImage", reflect.TypeOf((*MockImageClient)(nil).ImportImage), arg0, arg1, arg2)
+}
+
 // ListImages mocks base method.
-func (m *MockImageClient) ListImages(arg0 images.ListOptsBuilder) ([]images.Image, error) {
+func (m *MockImageClient) ListImages(arg0 context.Context, arg1 images.ListOptsBuilder) ([]images.Image, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListImages", arg0)
+	ret := m.ctrl.Call(m, "ListImages", arg0, arg1)
 	ret0, _ := ret[0].([]images.Image)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListImages indicates an expected call of ListImages.
-func (mr *MockImageClientMockRecorder) ListImages(arg0 interface{}) *gomock.Call {
+func (mr *MockImageClientMockRecorder) ListImages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockImageClient)(nil).ListImages), arg0, arg1)
+}
+
+// UpdateImage mocks base method.
+func (m *MockImageClient) UpdateImage(arg0 context.Context, arg1 string, arg2 images.UpdateOptsBuilder) (*images.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*images.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImage indicates an expected call of UpdateImage.
+func (mr *MockImageClientMockRecorder) UpdateImage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImage", reflect.TypeOf((*MockImageClient)(nil).UpdateImage), arg0, arg1, arg2)
+}
+
+// UploadImageData mocks base method.
+func (m *MockImageClient) UploadImageData(arg0 context.Context, arg1 string, arg2 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadImageData", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadImageData indicates an expected call of UploadImageData.
+func (mr *MockImageClientMockRecorder) UploadImageData(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockImageClient)(nil).ListImages), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImageData", reflect.TypeOf((*MockImageClient)(nil).UploadImageData), arg0, arg1, arg2)
 }
@@ -21,15 +21,18 @@ limitations under the License.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	apiversions "github.com/gophercloud/gophercloud/openstack/compute/apiversions"
+	l7policies "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/l7policies"
 	listeners "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
 	loadbalancers "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	monitors "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	providers "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	clients "sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 )
 
 // MockLbClient is a mock of LbClient interface.
@@ -55,312 +58,431 @@ func (m *MockLbClient) EXPECT() *MockLbClientMockRecorder {
 	return m.recorder
 }
 
+// CreateL7Policy mocks base method.
+func (m *MockLbClient) CreateL7Policy(arg0 context.Context, arg1 l7policies.CreateOptsBuilder) (*l7policies.L7Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateL7Policy", arg0, arg1)
+	ret0, _ := ret[0].(*l7policies.L7Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateL7Policy indicates an expected call of CreateL7Policy.
+func (mr *MockLbClientMockRecorder) CreateL7Policy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateL7Policy", reflect.TypeOf((*MockLbClient)(nil).CreateL7Policy), arg0, arg1)
+}
+
 // CreateListener mocks base method.
-func (m *MockLbClient) CreateListener(arg0 listeners.CreateOptsBuilder) (*listeners.Listener, error) {
+func (m *MockLbClient) CreateListener(arg0 context.Context, arg1 listeners.CreateOptsBuilder) (*listeners.Listener, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateListener", arg0)
+	ret := m.ctrl.Call(m, "CreateListener", arg0, arg1)
 	ret0, _ := ret[0].(*listeners.Listener)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateListener indicates an expected call of CreateListener.
-func (mr *MockLbClientMockRecorder) CreateListener(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) CreateListener(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListener", reflect.TypeOf((*MockLbClient)(nil).CreateListener), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListener", reflect.TypeOf((*MockLbClient)(nil).CreateListener), arg0, arg1)
 }
 
 // CreateLoadBalancer mocks base method.
-func (m *MockLbClient) CreateLoadBalancer(arg0 loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error) {
+func (m *MockLbClient) CreateLoadBalancer(arg0 context.Context, arg1 loadbalancers.CreateOptsBuilder) (*loadbalancers.LoadBalancer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLoadBalancer", arg0)
+	ret := m.ctrl.Call(m, "CreateLoadBalancer", arg0, arg1)
 	ret0, _ := ret[0].(*loadbalancers.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateLoadBalancer indicates an expected call of CreateLoadBalancer.
-func (mr *MockLbClientMockRecorder) CreateLoadBalancer(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) CreateLoadBalancer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).CreateLoadBalancer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).CreateLoadBalancer), arg0, arg1)
 }
 
 // CreateMonitor mocks base method.
-func (m *MockLbClient) CreateMonitor(arg0 monitors.CreateOptsBuilder) (*monitors.Monitor, error) {
+func (m *MockLbClient) CreateMonitor(arg0 context.Context, arg1 monitors.CreateOptsBuilder) (*monitors.Monitor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMonitor", arg0)
+	ret := m.ctrl.Call(m, "CreateMonitor", arg0, arg1)
 	ret0, _ := ret[0].(*monitors.Monitor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMonitor indicates an expected call of CreateMonitor.
-func (mr *MockLbClientMockRecorder) CreateMonitor(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) CreateMonitor(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMonitor", reflect.TypeOf((*MockLbClient)(nil).CreateMonitor), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMonitor", reflect.TypeOf((*MockLbClient)(nil).CreateMonitor), arg0, arg1)
 }
 
 // CreatePool mocks base method.
-func (m *MockLbClient) CreatePool(arg0 pools.CreateOptsBuilder) (*pools.Pool, error) {
+func (m *MockLbClient) CreatePool(arg0 context.Context, arg1 pools.CreateOptsBuilder) (*pools.Pool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePool", arg0)
+	ret := m.ctrl.Call(m, "CreatePool", arg0, arg1)
 	ret0, _ := ret[0].(*pools.Pool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePool indicates an expected call of CreatePool.
-func (mr *MockLbClientMockRecorder) CreatePool(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) CreatePool(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePool", reflect.TypeOf((*MockLbClient)(nil).CreatePool), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePool", reflect.TypeOf((*MockLbClient)(nil).CreatePool), arg0, arg1)
 }
 
 // CreatePoolMember mocks base method.
-func (m *MockLbClient) CreatePoolMember(arg0 string, arg1 pools.CreateMemberOptsBuilder) (*pools.Member, error) {
+func (m *MockLbClient) CreatePoolMember(arg0 context.Context, arg1 string, arg2 pools.CreateMemberOptsBuilder) (*pools.Member, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePoolMember", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreatePoolMember", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*pools.Member)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePoolMember indicates an expected call of CreatePoolMember.
-func (mr *MockLbClientMockRecorder) CreatePoolMember(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) CreatePoolMember(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoolMember", reflect.TypeOf((*MockLbClient)(nil).CreatePoolMember), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoolMember", reflect.TypeOf((*MockLbClient)(nil).CreatePoolMember), arg0, arg1, arg2)
+}
+
+// DeleteL7Policy mocks base method.
+func (m *MockLbClient) DeleteL7Policy(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteL7Policy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteL7Policy indicates an expected call of DeleteL7Policy.
+func (mr *MockLbClientMockRecorder) DeleteL7Policy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteL7Policy", reflect.TypeOf((*MockLbClient)(nil).DeleteL7Policy), arg0, arg1)
 }
 
 // DeleteListener mocks base method.
-func (m *MockLbClient) DeleteListener(arg0 string) error {
+func (m *MockLbClient) DeleteListener(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteListener", arg0)
+	ret := m.ctrl.Call(m, "DeleteListener", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteListener indicates an expected call of DeleteListener.
-func (mr *MockLbClientMockRecorder) DeleteListener(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) DeleteListener(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListener", reflect.TypeOf((*MockLbClient)(nil).DeleteListener), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListener", reflect.TypeOf((*MockLbClient)(nil).DeleteListener), arg0, arg1)
 }
 
 // DeleteLoadBalancer mocks base method.
-func (m *MockLbClient) DeleteLoadBalancer(arg0 string, arg1 loadbalancers.DeleteOptsBuilder) error {
+func (m *MockLbClient) DeleteLoadBalancer(arg0 context.Context, arg1 string, arg2 loadbalancers.DeleteOptsBuilder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLoadBalancer", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteLoadBalancer", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteLoadBalancer indicates an expected call of DeleteLoadBalancer.
-func (mr *MockLbClientMockRecorder) DeleteLoadBalancer(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) DeleteLoadBalancer(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).DeleteLoadBalancer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).DeleteLoadBalancer), arg0, arg1, arg2)
 }
 
 // DeleteMonitor mocks base method.
-func (m *MockLbClient) DeleteMonitor(arg0 string) error {
+func (m *MockLbClient) DeleteMonitor(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMonitor", arg0)
+	ret := m.ctrl.Call(m, "DeleteMonitor", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMonitor indicates an expected call of DeleteMonitor.
-func (mr *MockLbClientMockRecorder) DeleteMonitor(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) DeleteMonitor(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMonitor", reflect.TypeOf((*MockLbClient)(nil).DeleteMonitor), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMonitor", reflect.TypeOf((*MockLbClient)(nil).DeleteMonitor), arg0, arg1)
 }
 
 // DeletePool mocks base method.
-func (m *MockLbClient) DeletePool(arg0 string) error {
+func (m *MockLbClient) DeletePool(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePool", arg0)
+	ret := m.ctrl.Call(m, "DeletePool", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePool indicates an expected call of DeletePool.
-func (mr *MockLbClientMockRecorder) DeletePool(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) DeletePool(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePool", reflect.TypeOf((*MockLbClient)(nil).DeletePool), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePool", reflect.TypeOf((*MockLbClient)(nil).DeletePool), arg0, arg1)
 }
 
 // DeletePoolMember mocks base method.
-func (m *MockLbClient) DeletePoolMember(arg0, arg1 string) error {
+func (m *MockLbClient) DeletePoolMember(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePoolMember", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeletePoolMember", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePoolMember indicates an expected call of DeletePoolMember.
-func (mr *MockLbClientMockRecorder) DeletePoolMember(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) DeletePoolMember(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePoolMember", reflect.TypeOf((*MockLbClient)(nil).DeletePoolMember), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePoolMember", reflect.TypeOf((*MockLbClient)(nil).DeletePoolMember), arg0, arg1, arg2)
 }
 
 // GetListener mocks base method.
-func (m *MockLbClient) GetListener(arg0 string) (*listeners.Listener, error) {
+func (m *MockLbClient) GetListener(arg0 context.Context, arg1 string) (*listeners.Listener, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListener", arg0)
+	ret := m.ctrl.Call(m, "GetListener", arg0, arg1)
 	ret0, _ := ret[0].(*listeners.Listener)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListener indicates an expected call of GetListener.
-func (mr *MockLbClientMockRecorder) GetListener(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) GetListener(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListener", reflect.TypeOf((*MockLbClient)(nil).GetListener), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListener", reflect.TypeOf((*MockLbClient)(nil).GetListener), arg0, arg1)
 }
 
 // GetLoadBalancer mocks base method.
-func (m *MockLbClient) GetLoadBalancer(arg0 string) (*loadbalancers.LoadBalancer, error) {
+func (m *MockLbClient) GetLoadBalancer(arg0 context.Context, arg1 string) (*loadbalancers.LoadBalancer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLoadBalancer", arg0)
+	ret := m.ctrl.Call(m, "GetLoadBalancer", arg0, arg1)
 	ret0, _ := ret[0].(*loadbalancers.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLoadBalancer indicates an expected call of GetLoadBalancer.
-func (mr *MockLbClientMockRecorder) GetLoadBalancer(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) GetLoadBalancer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).GetLoadBalancer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).GetLoadBalancer), arg0, arg1)
 }
 
 // GetPool mocks base method.
-func (m *MockLbClient) GetPool(arg0 string) (*pools.Pool, error) {
+func (m *MockLbClient) GetPool(arg0 context.Context, arg1 string) (*pools.Pool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPool", arg0)
+	ret := m.ctrl.Call(m, "GetPool", arg0, arg1)
 	ret0, _ := ret[0].(*pools.Pool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPool indicates an expected call of GetPool.
-func (mr *MockLbClientMockRecorder) GetPool(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) GetPool(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockLbClient)(nil).GetPool), arg0, arg1)
+}
+
+// ListL7Policies mocks base method.
+func (m *MockLbClient) ListL7Policies(arg0 context.Context, arg1 l7policies.ListOptsBuilder) ([]l7policies.L7Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListL7Policies", arg0, arg1)
+	ret0, _ := ret[0].([]l7policies.L7Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListL7Policies indicates an expected call of ListL7Policies.
+func (mr *MockLbClientMockRecorder) ListL7Policies(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockLbClient)(nil).GetPool), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListL7Policies", reflect.TypeOf((*MockLbClient)(nil).ListL7Policies), arg0, arg1)
 }
 
 // ListListeners mocks base method.
-func (m *MockLbClient) ListListeners(arg0 listeners.ListOptsBuilder) ([]listeners.Listener, error) {
+func (m *MockLbClient) ListListeners(arg0 context.Context, arg1 listeners.ListOptsBuilder) ([]listeners.Listener, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListListeners", arg0)
+	ret := m.ctrl.Call(m, "ListListeners", arg0, arg1)
 	ret0, _ := ret[0].([]listeners.Listener)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListListeners indicates an expected call of ListListeners.
-func (mr *MockLbClientMockRecorder) ListListeners(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListListeners(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListListeners", reflect.TypeOf((*MockLbClient)(nil).ListListeners), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListListeners", reflect.TypeOf((*MockLbClient)(nil).ListListeners), arg0, arg1)
+}
+
+// ListLoadBalancerAvailabilityZones mocks base method.
+func (m *MockLbClient) ListLoadBalancerAvailabilityZones(arg0 context.Context) ([]clients.LoadBalancerAvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoadBalancerAvailabilityZones", arg0)
+	ret0, _ := ret[0].([]clients.LoadBalancerAvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoadBalancerAvailabilityZones indicates an expected call of ListLoadBalancerAvailabilityZones.
+func (mr *MockLbClientMockRecorder) ListLoadBalancerAvailabilityZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancerAvailabilityZones", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancerAvailabilityZones), arg0)
+}
+
+// ListLoadBalancerFlavors mocks base method.
+func (m *MockLbClient) ListLoadBalancerFlavors(arg0 context.Context) ([]clients.LoadBalancerFlavor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoadBalancerFlavors", arg0)
+	ret0, _ := ret[0].([]clients.LoadBalancerFlavor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoadBalancerFlavors indicates an expected call of ListLoadBalancerFlavors.
+func (mr *MockLbClientMockRecorder) ListLoadBalancerFlavors(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancerFlavors", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancerFlavors), arg0)
 }
 
 // ListLoadBalancerProviders mocks base method.
-func (m *MockLbClient) ListLoadBalancerProviders() ([]providers.Provider, error) {
+func (m *MockLbClient) ListLoadBalancerProviders(arg0 context.Context) ([]providers.Provider, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListLoadBalancerProviders")
+	ret := m.ctrl.Call(m, "ListLoadBalancerProviders", arg0)
 	ret0, _ := ret[0].([]providers.Provider)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListLoadBalancerProviders indicates an expected call of ListLoadBalancerProviders.
-func (mr *MockLbClientMockRecorder) ListLoadBalancerProviders() *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListLoadBalancerProviders(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancerProviders", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancerProviders))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancerProviders", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancerProviders), arg0)
 }
 
 // ListLoadBalancers mocks base method.
-func (m *MockLbClient) ListLoadBalancers(arg0 loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error) {
+func (m *MockLbClient) ListLoadBalancers(arg0 context.Context, arg1 loadbalancers.ListOptsBuilder) ([]loadbalancers.LoadBalancer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListLoadBalancers", arg0)
+	ret := m.ctrl.Call(m, "ListLoadBalancers", arg0, arg1)
 	ret0, _ := ret[0].([]loadbalancers.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListLoadBalancers indicates an expected call of ListLoadBalancers.
-func (mr *MockLbClientMockRecorder) ListLoadBalancers(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListLoadBalancers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancers", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancers), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancers", reflect.TypeOf((*MockLbClient)(nil).ListLoadBalancers), arg0, arg1)
 }
 
 // ListMonitors mocks base method.
-func (m *MockLbClient) ListMonitors(arg0 monitors.ListOptsBuilder) ([]monitors.Monitor, error) {
+func (m *MockLbClient) ListMonitors(arg0 context.Context, arg1 monitors.ListOptsBuilder) ([]monitors.Monitor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListMonitors", arg0)
+	ret := m.ctrl.Call(m, "ListMonitors", arg0, arg1)
 	ret0, _ := ret[0].([]monitors.Monitor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListMonitors indicates an expected call of ListMonitors.
-func (mr *MockLbClientMockRecorder) ListMonitors(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListMonitors(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMonitors", reflect.TypeOf((*MockLbClient)(nil).ListMonitors), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMonitors", reflect.TypeOf((*MockLbClient)(nil).ListMonitors), arg0, arg1)
 }
 
 // ListOctaviaVersions mocks base method.
-func (m *MockLbClient) ListOctaviaVersions() ([]apiversions.APIVersion, error) {
+func (m *MockLbClient) ListOctaviaVersions(arg0 context.Context) ([]apiversions.APIVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListOctaviaVersions")
+	ret := m.ctrl.Call(m, "ListOctaviaVersions", arg0)
 	ret0, _ := ret[0].([]apiversions.APIVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListOctaviaVersions indicates an expected call of ListOctaviaVersions.
-func (mr *MockLbClientMockRecorder) ListOctaviaVersions() *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListOctaviaVersions(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOctaviaVersions", reflect.TypeOf((*MockLbClient)(nil).ListOctaviaVersions))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOctaviaVersions", reflect.TypeOf((*MockLbClient)(nil).ListOctaviaVersions), arg0)
 }
 
 // ListPoolMember mocks base method.
-func (m *MockLbClient) ListPoolMember(arg0 string, arg1 pools.ListMembersOptsBuilder) ([]pools.Member, error) {
+func (m *MockLbClient) ListPoolMember(arg0 context.Context, arg1 string, arg2 pools.ListMembersOptsBuilder) ([]pools.Member, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPoolMember", arg0, arg1)
+	ret := m.ctrl.Call(m, "ListPoolMember", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]pools.Member)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPoolMember indicates an expected call of ListPoolMember.
-func (mr *MockLbClientMockRecorder) ListPoolMember(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListPoolMember(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPoolMember", reflect.TypeOf((*MockLbClient)(nil).ListPoolMember), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPoolMember", reflect.TypeOf((*MockLbClient)(nil).ListPoolMember), arg0, arg1, arg2)
 }
 
 // ListPools mocks base method.
-func (m *MockLbClient) ListPools(arg0 pools.ListOptsBuilder) ([]pools.Pool, error) {
+func (m *MockLbClient) ListPools(arg0 context.Context, arg1 pools.ListOptsBuilder) ([]pools.Pool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPools", arg0)
+	ret := m.ctrl.Call(m, "ListPools", arg0, arg1)
 	ret0, _ := ret[0].([]pools.Pool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPools indicates an expected call of ListPools.
-func (mr *MockLbClientMockRecorder) ListPools(arg0 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) ListPools(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPools", reflect.TypeOf((*MockLbClient)(nil).ListPools), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPools", reflect.TypeOf((*MockLbClient)(nil).ListPools), arg0, arg1)
 }
 
 // UpdateListener mocks base method.
-func (m *MockLbClient) UpdateListener(arg0 string, arg1 listeners.UpdateOpts) (*listeners.Listener, error) {
+func (m *MockLbClient) UpdateListener(arg0 context.Context, arg1 string, arg2 listeners.UpdateOpts) (*listeners.Listener, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateListener", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateListener", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*listeners.Listener)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateListener indicates an expected call of UpdateListener.
-func (mr *MockLbClientMockRecorder) UpdateListener(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLbClientMockRecorder) UpdateListener(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListener", reflect.TypeOf((*MockLbClient)(nil).UpdateListener), arg0, arg1, arg2)
+}
+
+// UpdateLoadBalancer mocks base method.
+func (m *MockLbClient) UpdateLoadBalancer(arg0 context.Context, arg1 string, arg2 loadbalancers.UpdateOpts) (*loadbalancers.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadBalancer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*loadbalancers.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLoadBalancer indicates an expected call of UpdateLoadBalancer.
+func (mr *MockLbClientMockRecorder) UpdateLoadBalancer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancer", reflect.TypeOf((*MockLbClient)(nil).UpdateLoadBalancer), arg0, arg1, arg2)
+}
+
+// UpdateMonitor mocks base method.
+func (m *MockLbClient) UpdateMonitor(arg0 context.Context, arg1 string, arg2 monitors.UpdateOptsBuilder) (*monitors.Monitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMonitor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*monitors.Monitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMonitor indicates an expected call of UpdateMonitor.
+func (mr *MockLbClientMockRecorder) UpdateMonitor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMonitor", reflect.TypeOf((*MockLbClient)(nil).UpdateMonitor), arg0, arg1, arg2)
+}
+
+// UpdatePool mocks base method.
+func (m *MockLbClient) UpdatePool(arg0 context.Context, arg1 string, arg2 pools.UpdateOpts) (*pools.Pool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePool", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pools.Pool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePool indicates an expected call of UpdatePool.
+func (mr *MockLbClientMockRecorder) UpdatePool(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListener", reflect.TypeOf((*MockLbClient)(nil).UpdateListener), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePool", reflect.TypeOf((*MockLbClient)(nil).UpdatePool), arg0, arg1, arg2)
 }
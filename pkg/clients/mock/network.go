@@ -21,6 +21,7 @@ limitations under the License.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -28,12 +29,15 @@ import (
 	attributestags "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	floatingips "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	routers "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	policies "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
+	quotas "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
 	groups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	rules "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 	trunks "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	networks "github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	ports "github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	subnets "github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	clients "sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 )
 
 // MockNetworkClient is a mock of NetworkClient interface.
@@ -60,608 +64,730 @@ func (m *MockNetworkClient) EXPECT() *MockNetworkClientMockRecorder {
 }
 
 // AddRouterInterface mocks base method.
-func (m *MockNetworkClient) AddRouterInterface(arg0 string, arg1 routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+func (m *MockNetworkClient) AddRouterInterface(arg0 context.Context, arg1 string, arg2 routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddRouterInterface", arg0, arg1)
+	ret := m.ctrl.Call(m, "AddRouterInterface", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*routers.InterfaceInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddRouterInterface indicates an expected call of AddRouterInterface.
-func (mr *MockNetworkClientMockRecorder) AddRouterInterface(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) AddRouterInterface(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).AddRouterInterface), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).AddRouterInterface), arg0, arg1, arg2)
+}
+
+// AddSubports mocks base method.
+func (m *MockNetworkClient) AddSubports(arg0 context.Context, arg1 string, arg2 trunks.AddSubportsOptsBuilder) (*trunks.Trunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSubports", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*trunks.Trunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSubports indicates an expected call of AddSubports.
+func (mr *MockNetworkClientMockRecorder) AddSubports(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSubports", reflect.TypeOf((*MockNetworkClient)(nil).AddSubports), arg0, arg1, arg2)
 }
 
 // CreateFloatingIP mocks base method.
-func (m *MockNetworkClient) CreateFloatingIP(arg0 floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
+func (m *MockNetworkClient) CreateFloatingIP(arg0 context.Context, arg1 floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFloatingIP", arg0)
+	ret := m.ctrl.Call(m, "CreateFloatingIP", arg0, arg1)
 	ret0, _ := ret[0].(*floatingips.FloatingIP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateFloatingIP indicates an expected call of CreateFloatingIP.
-func (mr *MockNetworkClientMockRecorder) CreateFloatingIP(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateFloatingIP(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).CreateFloatingIP), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).CreateFloatingIP), arg0, arg1)
 }
 
 // CreateNetwork mocks base method.
-func (m *MockNetworkClient) CreateNetwork(arg0 networks.CreateOptsBuilder) (*networks.Network, error) {
+func (m *MockNetworkClient) CreateNetwork(arg0 context.Context, arg1 networks.CreateOptsBuilder) (*networks.Network, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNetwork", arg0)
+	ret := m.ctrl.Call(m, "CreateNetwork", arg0, arg1)
 	ret0, _ := ret[0].(*networks.Network)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateNetwork indicates an expected call of CreateNetwork.
-func (mr *MockNetworkClientMockRecorder) CreateNetwork(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateNetwork(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetwork", reflect.TypeOf((*MockNetworkClient)(nil).CreateNetwork), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetwork", reflect.TypeOf((*MockNetworkClient)(nil).CreateNetwork), arg0, arg1)
 }
 
 // CreatePort mocks base method.
-func (m *MockNetworkClient) CreatePort(arg0 ports.CreateOptsBuilder) (*ports.Port, error) {
+func (m *MockNetworkClient) CreatePort(arg0 context.Context, arg1 ports.CreateOptsBuilder) (*ports.Port, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePort", arg0)
+	ret := m.ctrl.Call(m, "CreatePort", arg0, arg1)
 	ret0, _ := ret[0].(*ports.Port)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePort indicates an expected call of CreatePort.
-func (mr *MockNetworkClientMockRecorder) CreatePort(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreatePort(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePort", reflect.TypeOf((*MockNetworkClient)(nil).CreatePort), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePort", reflect.TypeOf((*MockNetworkClient)(nil).CreatePort), arg0, arg1)
 }
 
 // CreateRouter mocks base method.
-func (m *MockNetworkClient) CreateRouter(arg0 routers.CreateOptsBuilder) (*routers.Router, error) {
+func (m *MockNetworkClient) CreateRouter(arg0 context.Context, arg1 routers.CreateOptsBuilder) (*routers.Router, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRouter", arg0)
+	ret := m.ctrl.Call(m, "CreateRouter", arg0, arg1)
 	ret0, _ := ret[0].(*routers.Router)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateRouter indicates an expected call of CreateRouter.
-func (mr *MockNetworkClientMockRecorder) CreateRouter(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateRouter(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouter", reflect.TypeOf((*MockNetworkClient)(nil).CreateRouter), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouter", reflect.TypeOf((*MockNetworkClient)(nil).CreateRouter), arg0, arg1)
 }
 
 // CreateSecGroup mocks base method.
-func (m *MockNetworkClient) CreateSecGroup(arg0 groups.CreateOptsBuilder) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) CreateSecGroup(arg0 context.Context, arg1 groups.CreateOptsBuilder) (*groups.SecGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSecGroup", arg0)
+	ret := m.ctrl.Call(m, "CreateSecGroup", arg0, arg1)
 	ret0, _ := ret[0].(*groups.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSecGroup indicates an expected call of CreateSecGroup.
-func (mr *MockNetworkClientMockRecorder) CreateSecGroup(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateSecGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).CreateSecGroup), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).CreateSecGroup), arg0, arg1)
 }
 
 // CreateSecGroupRule mocks base method.
-func (m *MockNetworkClient) CreateSecGroupRule(arg0 rules.CreateOptsBuilder) (*rules.SecGroupRule, error) {
+func (m *MockNetworkClient) CreateSecGroupRule(arg0 context.Context, arg1 rules.CreateOptsBuilder) (*rules.SecGroupRule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSecGroupRule", arg0)
+	ret := m.ctrl.Call(m, "CreateSecGroupRule", arg0, arg1)
 	ret0, _ := ret[0].(*rules.SecGroupRule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSecGroupRule indicates an expected call of CreateSecGroupRule.
-func (mr *MockNetworkClientMockRecorder) CreateSecGroupRule(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateSecGroupRule(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).CreateSecGroupRule), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).CreateSecGroupRule), arg0, arg1)
 }
 
 // CreateSubnet mocks base method.
-func (m *MockNetworkClient) CreateSubnet(arg0 subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
+func (m *MockNetworkClient) CreateSubnet(arg0 context.Context, arg1 subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSubnet", arg0)
+	ret := m.ctrl.Call(m, "CreateSubnet", arg0, arg1)
 	ret0, _ := ret[0].(*subnets.Subnet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSubnet indicates an expected call of CreateSubnet.
-func (mr *MockNetworkClientMockRecorder) CreateSubnet(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateSubnet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubnet", reflect.TypeOf((*MockNetworkClient)(nil).CreateSubnet), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubnet", reflect.TypeOf((*MockNetworkClient)(nil).CreateSubnet), arg0, arg1)
 }
 
 // CreateTrunk mocks base method.
-func (m *MockNetworkClient) CreateTrunk(arg0 trunks.CreateOptsBuilder) (*trunks.Trunk, error) {
+func (m *MockNetworkClient) CreateTrunk(arg0 context.Context, arg1 trunks.CreateOptsBuilder) (*trunks.Trunk, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTrunk", arg0)
+	ret := m.ctrl.Call(m, "CreateTrunk", arg0, arg1)
 	ret0, _ := ret[0].(*trunks.Trunk)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTrunk indicates an expected call of CreateTrunk.
-func (mr *MockNetworkClientMockRecorder) CreateTrunk(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) CreateTrunk(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrunk", reflect.TypeOf((*MockNetworkClient)(nil).CreateTrunk), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrunk", reflect.TypeOf((*MockNetworkClient)(nil).CreateTrunk), arg0, arg1)
 }
 
 // DeleteFloatingIP mocks base method.
-func (m *MockNetworkClient) DeleteFloatingIP(arg0 string) error {
+func (m *MockNetworkClient) DeleteFloatingIP(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFloatingIP", arg0)
+	ret := m.ctrl.Call(m, "DeleteFloatingIP", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteFloatingIP indicates an expected call of DeleteFloatingIP.
-func (mr *MockNetworkClientMockRecorder) DeleteFloatingIP(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteFloatingIP(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).DeleteFloatingIP), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).DeleteFloatingIP), arg0, arg1)
 }
 
 // DeleteNetwork mocks base method.
-func (m *MockNetworkClient) DeleteNetwork(arg0 string) error {
+func (m *MockNetworkClient) DeleteNetwork(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteNetwork", arg0)
+	ret := m.ctrl.Call(m, "DeleteNetwork", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteNetwork indicates an expected call of DeleteNetwork.
-func (mr *MockNetworkClientMockRecorder) DeleteNetwork(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteNetwork(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetwork", reflect.TypeOf((*MockNetworkClient)(nil).DeleteNetwork), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetwork", reflect.TypeOf((*MockNetworkClient)(nil).DeleteNetwork), arg0, arg1)
 }
 
 // DeletePort mocks base method.
-func (m *MockNetworkClient) DeletePort(arg0 string) error {
+func (m *MockNetworkClient) DeletePort(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePort", arg0)
+	ret := m.ctrl.Call(m, "DeletePort", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePort indicates an expected call of DeletePort.
-func (mr *MockNetworkClientMockRecorder) DeletePort(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeletePort(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePort", reflect.TypeOf((*MockNetworkClient)(nil).DeletePort), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePort", reflect.TypeOf((*MockNetworkClient)(nil).DeletePort), arg0, arg1)
 }
 
 // DeleteRouter mocks base method.
-func (m *MockNetworkClient) DeleteRouter(arg0 string) error {
+func (m *MockNetworkClient) DeleteRouter(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRouter", arg0)
+	ret := m.ctrl.Call(m, "DeleteRouter", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteRouter indicates an expected call of DeleteRouter.
-func (mr *MockNetworkClientMockRecorder) DeleteRouter(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteRouter(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouter", reflect.TypeOf((*MockNetworkClient)(nil).DeleteRouter), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouter", reflect.TypeOf((*MockNetworkClient)(nil).DeleteRouter), arg0, arg1)
 }
 
 // DeleteSecGroup mocks base method.
-func (m *MockNetworkClient) DeleteSecGroup(arg0 string) error {
+func (m *MockNetworkClient) DeleteSecGroup(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSecGroup", arg0)
+	ret := m.ctrl.Call(m, "DeleteSecGroup", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSecGroup indicates an expected call of DeleteSecGroup.
-func (mr *MockNetworkClientMockRecorder) DeleteSecGroup(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteSecGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSecGroup), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSecGroup), arg0, arg1)
 }
 
 // DeleteSecGroupRule mocks base method.
-func (m *MockNetworkClient) DeleteSecGroupRule(arg0 string) error {
+func (m *MockNetworkClient) DeleteSecGroupRule(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSecGroupRule", arg0)
+	ret := m.ctrl.Call(m, "DeleteSecGroupRule", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSecGroupRule indicates an expected call of DeleteSecGroupRule.
-func (mr *MockNetworkClientMockRecorder) DeleteSecGroupRule(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteSecGroupRule(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSecGroupRule), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSecGroupRule), arg0, arg1)
 }
 
 // DeleteSubnet mocks base method.
-func (m *MockNetworkClient) DeleteSubnet(arg0 string) error {
+func (m *MockNetworkClient) DeleteSubnet(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSubnet", arg0)
+	ret := m.ctrl.Call(m, "DeleteSubnet", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSubnet indicates an expected call of DeleteSubnet.
-func (mr *MockNetworkClientMockRecorder) DeleteSubnet(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteSubnet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubnet", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSubnet), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubnet", reflect.TypeOf((*MockNetworkClient)(nil).DeleteSubnet), arg0, arg1)
 }
 
 // DeleteTrunk mocks base method.
-func (m *MockNetworkClient) DeleteTrunk(arg0 string) error {
+func (m *MockNetworkClient) DeleteTrunk(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTrunk", arg0)
+	ret := m.ctrl.Call(m, "DeleteTrunk", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTrunk indicates an expected call of DeleteTrunk.
-func (mr *MockNetworkClientMockRecorder) DeleteTrunk(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) DeleteTrunk(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrunk", reflect.TypeOf((*MockNetworkClient)(nil).DeleteTrunk), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrunk", reflect.TypeOf((*MockNetworkClient)(nil).DeleteTrunk), arg0, arg1)
 }
 
 // GetFloatingIP mocks base method.
-func (m *MockNetworkClient) GetFloatingIP(arg0 string) (*floatingips.FloatingIP, error) {
+func (m *MockNetworkClient) GetFloatingIP(arg0 context.Context, arg1 string) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFloatingIP", arg0)
+	ret := m.ctrl.Call(m, "GetFloatingIP", arg0, arg1)
 	ret0, _ := ret[0].(*floatingips.FloatingIP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFloatingIP indicates an expected call of GetFloatingIP.
-func (mr *MockNetworkClientMockRecorder) GetFloatingIP(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetFloatingIP(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).GetFloatingIP), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).GetFloatingIP), arg0, arg1)
+}
+
+// GetFloatingIPWithRevision mocks base method.
+func (m *MockNetworkClient) GetFloatingIPWithRevision(arg0 context.Context, arg1 string) (*floatingips.FloatingIP, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFloatingIPWithRevision", arg0, arg1)
+	ret0, _ := ret[0].(*floatingips.FloatingIP)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFloatingIPWithRevision indicates an expected call of GetFloatingIPWithRevision.
+func (mr *MockNetworkClientMockRecorder) GetFloatingIPWithRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloatingIPWithRevision", reflect.TypeOf((*MockNetworkClient)(nil).GetFloatingIPWithRevision), arg0, arg1)
 }
 
 // GetNetwork mocks base method.
-func (m *MockNetworkClient) GetNetwork(arg0 string) (*networks.Network, error) {
+func (m *MockNetworkClient) GetNetwork(arg0 context.Context, arg1 string) (*networks.Network, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNetwork", arg0)
+	ret := m.ctrl.Call(m, "GetNetwork", arg0, arg1)
 	ret0, _ := ret[0].(*networks.Network)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNetwork indicates an expected call of GetNetwork.
-func (mr *MockNetworkClientMockRecorder) GetNetwork(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetNetwork(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetwork", reflect.TypeOf((*MockNetworkClient)(nil).GetNetwork), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetwork", reflect.TypeOf((*MockNetworkClient)(nil).GetNetwork), arg0, arg1)
 }
 
 // GetPort mocks base method.
-func (m *MockNetworkClient) GetPort(arg0 string) (*ports.Port, error) {
+func (m *MockNetworkClient) GetPort(arg0 context.Context, arg1 string) (*ports.Port, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPort", arg0)
+	ret := m.ctrl.Call(m, "GetPort", arg0, arg1)
 	ret0, _ := ret[0].(*ports.Port)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPort indicates an expected call of GetPort.
-func (mr *MockNetworkClientMockRecorder) GetPort(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetPort(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPort", reflect.TypeOf((*MockNetworkClient)(nil).GetPort), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPort", reflect.TypeOf((*MockNetworkClient)(nil).GetPort), arg0, arg1)
+}
+
+// GetPortWithRevision mocks base method.
+func (m *MockNetworkClient) GetPortWithRevision(arg0 context.Context, arg1 string) (*ports.Port, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortWithRevision", arg0, arg1)
+	ret0, _ := ret[0].(*ports.Port)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPortWithRevision indicates an expected call of GetPortWithRevision.
+func (mr *MockNetworkClientMockRecorder) GetPortWithRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortWithRevision", reflect.TypeOf((*MockNetworkClient)(nil).GetPortWithRevision), arg0, arg1)
+}
+
+// GetQuotaDetail mocks base method.
+func (m *MockNetworkClient) GetQuotaDetail(arg0 context.Context, arg1 string) (*quotas.QuotaDetailSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDetail", arg0, arg1)
+	ret0, _ := ret[0].(*quotas.QuotaDetailSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDetail indicates an expected call of GetQuotaDetail.
+func (mr *MockNetworkClientMockRecorder) GetQuotaDetail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDetail", reflect.TypeOf((*MockNetworkClient)(nil).GetQuotaDetail), arg0, arg1)
 }
 
 // GetRouter mocks base method.
-func (m *MockNetworkClient) GetRouter(arg0 string) (*routers.Router, error) {
+func (m *MockNetworkClient) GetRouter(arg0 context.Context, arg1 string) (*routers.Router, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRouter", arg0)
+	ret := m.ctrl.Call(m, "GetRouter", arg0, arg1)
 	ret0, _ := ret[0].(*routers.Router)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRouter indicates an expected call of GetRouter.
-func (mr *MockNetworkClientMockRecorder) GetRouter(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetRouter(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouter", reflect.TypeOf((*MockNetworkClient)(nil).GetRouter), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouter", reflect.TypeOf((*MockNetworkClient)(nil).GetRouter), arg0, arg1)
+}
+
+// GetRouterGatewayInfo mocks base method.
+func (m *MockNetworkClient) GetRouterGatewayInfo(arg0 context.Context, arg1 string) (*clients.RouterGatewayInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRouterGatewayInfo", arg0, arg1)
+	ret0, _ := ret[0].(*clients.RouterGatewayInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRouterGatewayInfo indicates an expected call of GetRouterGatewayInfo.
+func (mr *MockNetworkClientMockRecorder) GetRouterGatewayInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouterGatewayInfo", reflect.TypeOf((*MockNetworkClient)(nil).GetRouterGatewayInfo), arg0, arg1)
 }
 
 // GetSecGroup mocks base method.
-func (m *MockNetworkClient) GetSecGroup(arg0 string) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) GetSecGroup(arg0 context.Context, arg1 string) (*groups.SecGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSecGroup", arg0)
+	ret := m.ctrl.Call(m, "GetSecGroup", arg0, arg1)
 	ret0, _ := ret[0].(*groups.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSecGroup indicates an expected call of GetSecGroup.
-func (mr *MockNetworkClientMockRecorder) GetSecGroup(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetSecGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).GetSecGroup), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).GetSecGroup), arg0, arg1)
 }
 
 // GetSecGroupRule mocks base method.
-func (m *MockNetworkClient) GetSecGroupRule(arg0 string) (*rules.SecGroupRule, error) {
+func (m *MockNetworkClient) GetSecGroupRule(arg0 context.Context, arg1 string) (*rules.SecGroupRule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSecGroupRule", arg0)
+	ret := m.ctrl.Call(m, "GetSecGroupRule", arg0, arg1)
 	ret0, _ := ret[0].(*rules.SecGroupRule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSecGroupRule indicates an expected call of GetSecGroupRule.
-func (mr *MockNetworkClientMockRecorder) GetSecGroupRule(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetSecGroupRule(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).GetSecGroupRule), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).GetSecGroupRule), arg0, arg1)
 }
 
 // GetSubnet mocks base method.
-func (m *MockNetworkClient) GetSubnet(arg0 string) (*subnets.Subnet, error) {
+func (m *MockNetworkClient) GetSubnet(arg0 context.Context, arg1 string) (*subnets.Subnet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSubnet", arg0)
+	ret := m.ctrl.Call(m, "GetSubnet", arg0, arg1)
 	ret0, _ := ret[0].(*subnets.Subnet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSubnet indicates an expected call of GetSubnet.
-func (mr *MockNetworkClientMockRecorder) GetSubnet(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) GetSubnet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnet", reflect.TypeOf((*MockNetworkClient)(nil).GetSubnet), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnet", reflect.TypeOf((*MockNetworkClient)(nil).GetSubnet), arg0, arg1)
 }
 
 // ListExtensions mocks base method.
-func (m *MockNetworkClient) ListExtensions() ([]extensions.Extension, error) {
+func (m *MockNetworkClient) ListExtensions(arg0 context.Context) ([]extensions.Extension, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListExtensions")
+	ret := m.ctrl.Call(m, "ListExtensions", arg0)
 	ret0, _ := ret[0].([]extensions.Extension)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListExtensions indicates an expected call of ListExtensions.
-func (mr *MockNetworkClientMockRecorder) ListExtensions() *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListExtensions(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExtensions", reflect.TypeOf((*MockNetworkClient)(nil).ListExtensions))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExtensions", reflect.TypeOf((*MockNetworkClient)(nil).ListExtensions), arg0)
 }
 
 // ListFloatingIP mocks base method.
-func (m *MockNetworkClient) ListFloatingIP(arg0 floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
+func (m *MockNetworkClient) ListFloatingIP(arg0 context.Context, arg1 floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListFloatingIP", arg0)
+	ret := m.ctrl.Call(m, "ListFloatingIP", arg0, arg1)
 	ret0, _ := ret[0].([]floatingips.FloatingIP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListFloatingIP indicates an expected call of ListFloatingIP.
-func (mr *MockNetworkClientMockRecorder) ListFloatingIP(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListFloatingIP(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).ListFloatingIP), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).ListFloatingIP), arg0, arg1)
 }
 
 // ListNetwork mocks base method.
-func (m *MockNetworkClient) ListNetwork(arg0 networks.ListOptsBuilder) ([]networks.Network, error) {
+func (m *MockNetworkClient) ListNetwork(arg0 context.Context, arg1 networks.ListOptsBuilder) ([]networks.Network, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListNetwork", arg0)
+	ret := m.ctrl.Call(m, "ListNetwork", arg0, arg1)
 	ret0, _ := ret[0].([]networks.Network)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListNetwork indicates an expected call of ListNetwork.
-func (mr *MockNetworkClientMockRecorder) ListNetwork(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListNetwork(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetwork", reflect.TypeOf((*MockNetworkClient)(nil).ListNetwork), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetwork", reflect.TypeOf((*MockNetworkClient)(nil).ListNetwork), arg0, arg1)
 }
 
 // ListPort mocks base method.
-func (m *MockNetworkClient) ListPort(arg0 ports.ListOptsBuilder) ([]ports.Port, error) {
+func (m *MockNetworkClient) ListPort(arg0 context.Context, arg1 ports.ListOptsBuilder) ([]ports.Port, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPort", arg0)
+	ret := m.ctrl.Call(m, "ListPort", arg0, arg1)
 	ret0, _ := ret[0].([]ports.Port)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPort indicates an expected call of ListPort.
-func (mr *MockNetworkClientMockRecorder) ListPort(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListPort(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPort", reflect.TypeOf((*MockNetworkClient)(nil).ListPort), arg0, arg1)
+}
+
+// ListQoSPolicy mocks base method.
+func (m *MockNetworkClient) ListQoSPolicy(arg0 context.Context, arg1 policies.PolicyListOptsBuilder) ([]policies.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQoSPolicy", arg0, arg1)
+	ret0, _ := ret[0].([]policies.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQoSPolicy indicates an expected call of ListQoSPolicy.
+func (mr *MockNetworkClientMockRecorder) ListQoSPolicy(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPort", reflect.TypeOf((*MockNetworkClient)(nil).ListPort), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQoSPolicy", reflect.TypeOf((*MockNetworkClient)(nil).ListQoSPolicy), arg0, arg1)
 }
 
 // ListRouter mocks base method.
-func (m *MockNetworkClient) ListRouter(arg0 routers.ListOpts) ([]routers.Router, error) {
+func (m *MockNetworkClient) ListRouter(arg0 context.Context, arg1 routers.ListOpts) ([]routers.Router, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRouter", arg0)
+	ret := m.ctrl.Call(m, "ListRouter", arg0, arg1)
 	ret0, _ := ret[0].([]routers.Router)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListRouter indicates an expected call of ListRouter.
-func (mr *MockNetworkClientMockRecorder) ListRouter(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListRouter(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouter", reflect.TypeOf((*MockNetworkClient)(nil).ListRouter), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouter", reflect.TypeOf((*MockNetworkClient)(nil).ListRouter), arg0, arg1)
 }
 
 // ListSecGroup mocks base method.
-func (m *MockNetworkClient) ListSecGroup(arg0 groups.ListOpts) ([]groups.SecGroup, error) {
+func (m *MockNetworkClient) ListSecGroup(arg0 context.Context, arg1 groups.ListOpts) ([]groups.SecGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSecGroup", arg0)
+	ret := m.ctrl.Call(m, "ListSecGroup", arg0, arg1)
 	ret0, _ := ret[0].([]groups.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListSecGroup indicates an expected call of ListSecGroup.
-func (mr *MockNetworkClientMockRecorder) ListSecGroup(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListSecGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).ListSecGroup), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).ListSecGroup), arg0, arg1)
 }
 
 // ListSecGroupRule mocks base method.
-func (m *MockNetworkClient) ListSecGroupRule(arg0 rules.ListOpts) ([]rules.SecGroupRule, error) {
+func (m *MockNetworkClient) ListSecGroupRule(arg0 context.Context, arg1 rules.ListOpts) ([]rules.SecGroupRule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSecGroupRule", arg0)
+	ret := m.ctrl.Call(m, "ListSecGroupRule", arg0, arg1)
 	ret0, _ := ret[0].([]rules.SecGroupRule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListSecGroupRule indicates an expected call of ListSecGroupRule.
-func (mr *MockNetworkClientMockRecorder) ListSecGroupRule(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListSecGroupRule(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).ListSecGroupRule), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecGroupRule", reflect.TypeOf((*MockNetworkClient)(nil).ListSecGroupRule), arg0, arg1)
 }
 
 // ListSubnet mocks base method.
-func (m *MockNetworkClient) ListSubnet(arg0 subnets.ListOptsBuilder) ([]subnets.Subnet, error) {
+func (m *MockNetworkClient) ListSubnet(arg0 context.Context, arg1 subnets.ListOptsBuilder) ([]subnets.Subnet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSubnet", arg0)
+	ret := m.ctrl.Call(m, "ListSubnet", arg0, arg1)
 	ret0, _ := ret[0].([]subnets.Subnet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListSubnet indicates an expected call of ListSubnet.
-func (mr *MockNetworkClientMockRecorder) ListSubnet(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListSubnet(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubnet", reflect.TypeOf((*MockNetworkClient)(nil).ListSubnet), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubnet", reflect.TypeOf((*MockNetworkClient)(nil).ListSubnet), arg0, arg1)
 }
 
 // ListTrunk mocks base method.
-func (m *MockNetworkClient) ListTrunk(arg0 trunks.ListOptsBuilder) ([]trunks.Trunk, error) {
+func (m *MockNetworkClient) ListTrunk(arg0 context.Context, arg1 trunks.ListOptsBuilder) ([]trunks.Trunk, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTrunk", arg0)
+	ret := m.ctrl.Call(m, "ListTrunk", arg0, arg1)
 	ret0, _ := ret[0].([]trunks.Trunk)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTrunk indicates an expected call of ListTrunk.
-func (mr *MockNetworkClientMockRecorder) ListTrunk(arg0 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ListTrunk(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrunk", reflect.TypeOf((*MockNetworkClient)(nil).ListTrunk), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrunk", reflect.TypeOf((*MockNetworkClient)(nil).ListTrunk), arg0, arg1)
 }
 
 // RemoveRouterInterface mocks base method.
-func (m *MockNetworkClient) RemoveRouterInterface(arg0 string, arg1 routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+func (m *MockNetworkClient) RemoveRouterInterface(arg0 context.Context, arg1 string, arg2 routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveRouterInterface", arg0, arg1)
+	ret := m.ctrl.Call(m, "RemoveRouterInterface", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*routers.InterfaceInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RemoveRouterInterface indicates an expected call of RemoveRouterInterface.
-func (mr *MockNetworkClientMockRecorder) RemoveRouterInterface(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) RemoveRouterInterface(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).RemoveRouterInterface), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).RemoveRouterInterface), arg0, arg1, arg2)
 }
 
 // ReplaceAllAttributesTags mocks base method.
-func (m *MockNetworkClient) ReplaceAllAttributesTags(arg0, arg1 string, arg2 attributestags.ReplaceAllOptsBuilder) ([]string, error) {
+func (m *MockNetworkClient) ReplaceAllAttributesTags(arg0 context.Context, arg1, arg2 string, arg3 attributestags.ReplaceAllOptsBuilder) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReplaceAllAttributesTags", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ReplaceAllAttributesTags", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ReplaceAllAttributesTags indicates an expected call of ReplaceAllAttributesTags.
-func (mr *MockNetworkClientMockRecorder) ReplaceAllAttributesTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) ReplaceAllAttributesTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllAttributesTags", reflect.TypeOf((*MockNetworkClient)(nil).ReplaceAllAttributesTags), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllAttributesTags", reflect.TypeOf((*MockNetworkClient)(nil).ReplaceAllAttributesTags), arg0, arg1, arg2, arg3)
 }
 
 // UpdateFloatingIP mocks base method.
-func (m *MockNetworkClient) UpdateFloatingIP(arg0 string, arg1 floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
+func (m *MockNetworkClient) UpdateFloatingIP(arg0 context.Context, arg1 string, arg2 floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFloatingIP", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateFloatingIP", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*floatingips.FloatingIP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateFloatingIP indicates an expected call of UpdateFloatingIP.
-func (mr *MockNetworkClientMockRecorder) UpdateFloatingIP(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdateFloatingIP(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).UpdateFloatingIP), arg0, arg1, arg2)
+}
+
+// UpdateFloatingIPWithRevision mocks base method.
+func (m *MockNetworkClient) UpdateFloatingIPWithRevision(arg0 context.Context, arg1 string, arg2 int, arg3 floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFloatingIPWithRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*floatingips.FloatingIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFloatingIPWithRevision indicates an expected call of UpdateFloatingIPWithRevision.
+func (mr *MockNetworkClientMockRecorder) UpdateFloatingIPWithRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFloatingIP", reflect.TypeOf((*MockNetworkClient)(nil).UpdateFloatingIP), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFloatingIPWithRevision", reflect.TypeOf((*MockNetworkClient)(nil).UpdateFloatingIPWithRevision), arg0, arg1, arg2, arg3)
 }
 
 // UpdateNetwork mocks base method.
-func (m *MockNetworkClient) UpdateNetwork(arg0 string, arg1 networks.UpdateOptsBuilder) (*networks.Network, error) {
+func (m *MockNetworkClient) UpdateNetwork(arg0 context.Context, arg1 string, arg2 networks.UpdateOptsBuilder) (*networks.Network, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNetwork", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateNetwork", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*networks.Network)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateNetwork indicates an expected call of UpdateNetwork.
-func (mr *MockNetworkClientMockRecorder) UpdateNetwork(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdateNetwork(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNetwork", reflect.TypeOf((*MockNetworkClient)(nil).UpdateNetwork), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNetwork", reflect.TypeOf((*MockNetworkClient)(nil).UpdateNetwork), arg0, arg1, arg2)
 }
 
 // UpdatePort mocks base method.
-func (m *MockNetworkClient) UpdatePort(arg0 string, arg1 ports.UpdateOptsBuilder) (*ports.Port, error) {
+func (m *MockNetworkClient) UpdatePort(arg0 context.Context, arg1 string, arg2 ports.UpdateOptsBuilder) (*ports.Port, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePort", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdatePort", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*ports.Port)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdatePort indicates an expected call of UpdatePort.
-func (mr *MockNetworkClientMockRecorder) UpdatePort(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdatePort(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePort", reflect.TypeOf((*MockNetworkClient)(nil).UpdatePort), arg0, arg1, arg2)
+}
+
+// UpdatePortWithRevision mocks base method.
+func (m *MockNetworkClient) UpdatePortWithRevision(arg0 context.Context, arg1 string, arg2 int, arg3 ports.UpdateOptsBuilder) (*ports.Port, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePortWithRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*ports.Port)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePortWithRevision indicates an expected call of UpdatePortWithRevision.
+func (mr *MockNetworkClientMockRecorder) UpdatePortWithRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePort", reflect.TypeOf((*MockNetworkClient)(nil).UpdatePort), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePortWithRevision", reflect.TypeOf((*MockNetworkClient)(nil).UpdatePortWithRevision), arg0, arg1, arg2, arg3)
 }
 
 // UpdateRouter mocks base method.
-func (m *MockNetworkClient) UpdateRouter(arg0 string, arg1 routers.UpdateOptsBuilder) (*routers.Router, error) {
+func (m *MockNetworkClient) UpdateRouter(arg0 context.Context, arg1 string, arg2 routers.UpdateOptsBuilder) (*routers.Router, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRouter", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateRouter", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*routers.Router)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateRouter indicates an expected call of UpdateRouter.
-func (mr *MockNetworkClientMockRecorder) UpdateRouter(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdateRouter(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRouter", reflect.TypeOf((*MockNetworkClient)(nil).UpdateRouter), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRouter", reflect.TypeOf((*MockNetworkClient)(nil).UpdateRouter), arg0, arg1, arg2)
 }
 
 // UpdateSecGroup mocks base method.
-func (m *MockNetworkClient) UpdateSecGroup(arg0 string, arg1 groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) UpdateSecGroup(arg0 context.Context, arg1 string, arg2 groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSecGroup", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateSecGroup", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*groups.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSecGroup indicates an expected call of UpdateSecGroup.
-func (mr *MockNetworkClientMockRecorder) UpdateSecGroup(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdateSecGroup(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).UpdateSecGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecGroup", reflect.TypeOf((*MockNetworkClient)(nil).UpdateSecGroup), arg0, arg1, arg2)
 }
 
 // UpdateSubnet mocks base method.
-func (m *MockNetworkClient) UpdateSubnet(arg0 string, arg1 subnets.UpdateOptsBuilder) (*subnets.Subnet, error) {
+func (m *MockNetworkClient) UpdateSubnet(arg0 context.Context, arg1 string, arg2 subnets.UpdateOptsBuilder) (*subnets.Subnet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSubnet", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateSubnet", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*subnets.Subnet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSubnet indicates an expected call of UpdateSubnet.
-func (mr *MockNetworkClientMockRecorder) UpdateSubnet(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNetworkClientMockRecorder) UpdateSubnet(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubnet", reflect.TypeOf((*MockNetworkClient)(nil).UpdateSubnet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubnet", reflect.TypeOf((*MockNetworkClient)(nil).UpdateSubnet), arg0, arg1, arg2)
 }
@@ -21,10 +21,14 @@ limitations under the License.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	availabilityzones "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/availabilityzones"
+	qos "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/qos"
 	volumes "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	volumetypes "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumetypes"
 )
 
 // MockVolumeClient is a mock of VolumeClient interface.
@@ -51,60 +55,105 @@ func (m *MockVolumeClient) EXPECT() *MockVolumeClientMockRecorder {
 }
 
 // CreateVolume mocks base method.
-func (m *MockVolumeClient) CreateVolume(arg0 volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+func (m *MockVolumeClient) CreateVolume(arg0 context.Context, arg1 volumes.CreateOptsBuilder) (*volumes.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVolume", arg0)
+	ret := m.ctrl.Call(m, "CreateVolume", arg0, arg1)
 	ret0, _ := ret[0].(*volumes.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateVolume indicates an expected call of CreateVolume.
-func (mr *MockVolumeClientMockRecorder) CreateVolume(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeClientMockRecorder) CreateVolume(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockVolumeClient)(nil).CreateVolume), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockVolumeClient)(nil).CreateVolume), arg0, arg1)
 }
 
 // DeleteVolume mocks base method.
-func (m *MockVolumeClient) DeleteVolume(arg0 string, arg1 volumes.DeleteOptsBuilder) error {
+func (m *MockVolumeClient) DeleteVolume(arg0 context.Context, arg1 string, arg2 volumes.DeleteOptsBuilder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteVolume", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteVolume", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteVolume indicates an expected call of DeleteVolume.
-func (mr *MockVolumeClientMockRecorder) DeleteVolume(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockVolumeClientMockRecorder) DeleteVolume(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockVolumeClient)(nil).DeleteVolume), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockVolumeClient)(nil).DeleteVolume), arg0, arg1, arg2)
 }
 
 // GetVolume mocks base method.
-func (m *MockVolumeClient) GetVolume(arg0 string) (*volumes.Volume, error) {
+func (m *MockVolumeClient) GetVolume(arg0 context.Context, arg1 string) (*volumes.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVolume", arg0)
+	ret := m.ctrl.Call(m, "GetVolume", arg0, arg1)
 	ret0, _ := ret[0].(*volumes.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetVolume indicates an expected call of GetVolume.
-func (mr *MockVolumeClientMockRecorder) GetVolume(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeClientMockRecorder) GetVolume(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockVolumeClient)(nil).GetVolume), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockVolumeClient)(nil).GetVolume), arg0, arg1)
+}
+
+// ListAvailabilityZones mocks base method.
+func (m *MockVolumeClient) ListAvailabilityZones(arg0 context.Context) ([]availabilityzones.AvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailabilityZones", arg0)
+	ret0, _ := ret[0].([]availabilityzones.AvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailabilityZones indicates an expected call of ListAvailabilityZones.
+func (mr *MockVolumeClientMockRecorder) ListAvailabilityZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockVolumeClient)(nil).ListAvailabilityZones), arg0)
+}
+
+// ListQoSSpecs mocks base method.
+func (m *MockVolumeClient) ListQoSSpecs(arg0 context.Context) ([]qos.QoS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQoSSpecs", arg0)
+	ret0, _ := ret[0].([]qos.QoS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQoSSpecs indicates an expected call of ListQoSSpecs.
+func (mr *MockVolumeClientMockRecorder) ListQoSSpecs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQoSSpecs", reflect.TypeOf((*MockVolumeClient)(nil).ListQoSSpecs), arg0)
+}
+
+// ListVolumeTypes mocks base method.
+func (m *MockVolumeClient) ListVolumeTypes(arg0 context.Context) ([]volumetypes.VolumeType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeTypes", arg0)
+	ret0, _ := ret[0].([]volumetypes.VolumeType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeTypes indicates an expected call of ListVolumeTypes.
+func (mr *MockVolumeClientMockRecorder) ListVolumeTypes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeTypes", reflect.TypeOf((*MockVolumeClient)(nil).ListVolumeTypes), arg0)
 }
 
 // ListVolumes mocks base method.
-func (m *MockVolumeClient) ListVolumes(arg0 volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+func (m *MockVolumeClient) ListVolumes(arg0 context.Context, arg1 volumes.ListOptsBuilder) ([]volumes.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVolumes", arg0)
+	ret := m.ctrl.Call(m, "ListVolumes", arg0, arg1)
 	ret0, _ := ret[0].([]volumes.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListVolumes indicates an expected call of ListVolumes.
-func (mr *MockVolumeClientMockRecorder) ListVolumes(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeClientMockRecorder) ListVolumes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockVolumeClient)(nil).ListVolumes), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockVolumeClient)(nil).ListVolumes), arg0, arg1)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gophercloud/gophercloud"
@@ -25,6 +26,8 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
@@ -36,57 +39,88 @@ import (
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
+// neutronRevision is the revision of a Neutron resource, which Neutron increments with every
+// change of the resource.
+type neutronRevision struct {
+	RevisionNumber int `json:"revision_number"`
+}
+
+// ifMatchRevision returns the header which makes Neutron reject an update of a resource which
+// no longer has the given revision number.
+func ifMatchRevision(revisionNumber int) map[string]string {
+	return map[string]string{"If-Match": fmt.Sprintf("revision_number=%d", revisionNumber)}
+}
+
+// RouterGatewayInfo is the external gateway of a router. Unlike routers.GatewayInfo it includes
+// the QoS policy of the gateway, so it can also be used to update the gateway.
+type RouterGatewayInfo struct {
+	NetworkID        string                    `json:"network_id,omitempty"`
+	EnableSNAT       *bool                     `json:"enable_snat,omitempty"`
+	ExternalFixedIPs []routers.ExternalFixedIP `json:"external_fixed_ips,omitempty"`
+	QoSPolicyID      string                    `json:"qos_policy_id,omitempty"`
+}
+
 type NetworkClient interface {
-	ListFloatingIP(opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error)
-	CreateFloatingIP(opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error)
-	DeleteFloatingIP(id string) error
-	GetFloatingIP(id string) (*floatingips.FloatingIP, error)
-	UpdateFloatingIP(id string, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error)
-
-	ListPort(opts ports.ListOptsBuilder) ([]ports.Port, error)
-	CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error)
-	DeletePort(id string) error
-	GetPort(id string) (*ports.Port, error)
-	UpdatePort(id string, opts ports.UpdateOptsBuilder) (*ports.Port, error)
-
-	ListTrunk(opts trunks.ListOptsBuilder) ([]trunks.Trunk, error)
-	CreateTrunk(opts trunks.CreateOptsBuilder) (*trunks.Trunk, error)
-	DeleteTrunk(id string) error
-
-	ListRouter(opts routers.ListOpts) ([]routers.Router, error)
-	CreateRouter(opts routers.CreateOptsBuilder) (*routers.Router, error)
-	DeleteRouter(id string) error
-	GetRouter(id string) (*routers.Router, error)
-	UpdateRouter(id string, opts routers.UpdateOptsBuilder) (*routers.Router, error)
-	AddRouterInterface(id string, opts routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
-	RemoveRouterInterface(id string, opts routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
-
-	ListSecGroup(opts groups.ListOpts) ([]groups.SecGroup, error)
-	CreateSecGroup(opts groups.CreateOptsBuilder) (*groups.SecGroup, error)
-	DeleteSecGroup(id string) error
-	GetSecGroup(id string) (*groups.SecGroup, error)
-	UpdateSecGroup(id string, opts groups.UpdateOptsBuilder) (*groups.SecGroup, error)
-
-	ListSecGroupRule(opts rules.ListOpts) ([]rules.SecGroupRule, error)
-	CreateSecGroupRule(opts rules.CreateOptsBuilder) (*rules.SecGroupRule, error)
-	DeleteSecGroupRule(id string) error
-	GetSecGroupRule(id string) (*rules.SecGroupRule, error)
-
-	ListNetwork(opts networks.ListOptsBuilder) ([]networks.Network, error)
-	CreateNetwork(opts networks.CreateOptsBuilder) (*networks.Network, error)
-	DeleteNetwork(id string) error
-	GetNetwork(id string) (*networks.Network, error)
-	UpdateNetwork(id string, opts networks.UpdateOptsBuilder) (*networks.Network, error)
-
-	ListSubnet(opts subnets.ListOptsBuilder) ([]subnets.Subnet, error)
-	CreateSubnet(opts subnets.CreateOptsBuilder) (*subnets.Subnet, error)
-	DeleteSubnet(id string) error
-	GetSubnet(id string) (*subnets.Subnet, error)
-	UpdateSubnet(id string, opts subnets.UpdateOptsBuilder) (*subnets.Subnet, error)
-
-	ListExtensions() ([]extensions.Extension, error)
-
-	ReplaceAllAttributesTags(resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error)
+	ListFloatingIP(ctx context.Context, opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error)
+	CreateFloatingIP(ctx context.Context, opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error)
+	DeleteFloatingIP(ctx context.Context, id string) error
+	GetFloatingIP(ctx context.Context, id string) (*floatingips.FloatingIP, error)
+	UpdateFloatingIP(ctx context.Context, id string, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error)
+	GetFloatingIPWithRevision(ctx context.Context, id string) (*floatingips.FloatingIP, int, error)
+	UpdateFloatingIPWithRevision(ctx context.Context, id string, revisionNumber int, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error)
+
+	ListPort(ctx context.Context, opts ports.ListOptsBuilder) ([]ports.Port, error)
+	CreatePort(ctx context.Context, opts ports.CreateOptsBuilder) (*ports.Port, error)
+	DeletePort(ctx context.Context, id string) error
+	GetPort(ctx context.Context, id string) (*ports.Port, error)
+	UpdatePort(ctx context.Context, id string, opts ports.UpdateOptsBuilder) (*ports.Port, error)
+	GetPortWithRevision(ctx context.Context, id string) (*ports.Port, int, error)
+	UpdatePortWithRevision(ctx context.Context, id string, revisionNumber int, opts ports.UpdateOptsBuilder) (*ports.Port, error)
+
+	ListTrunk(ctx context.Context, opts trunks.ListOptsBuilder) ([]trunks.Trunk, error)
+	CreateTrunk(ctx context.Context, opts trunks.CreateOptsBuilder) (*trunks.Trunk, error)
+	DeleteTrunk(ctx context.Context, id string) error
+	AddSubports(ctx context.Context, id string, opts trunks.AddSubportsOptsBuilder) (*trunks.Trunk, error)
+
+	ListRouter(ctx context.Context, opts routers.ListOpts) ([]routers.Router, error)
+	CreateRouter(ctx context.Context, opts routers.CreateOptsBuilder) (*routers.Router, error)
+	DeleteRouter(ctx context.Context, id string) error
+	GetRouter(ctx context.Context, id string) (*routers.Router, error)
+	UpdateRouter(ctx context.Context, id string, opts routers.UpdateOptsBuilder) (*routers.Router, error)
+	GetRouterGatewayInfo(ctx context.Context, id string) (*RouterGatewayInfo, error)
+	AddRouterInterface(ctx context.Context, id string, opts routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
+	RemoveRouterInterface(ctx context.Context, id string, opts routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
+
+	ListSecGroup(ctx context.Context, opts groups.ListOpts) ([]groups.SecGroup, error)
+	CreateSecGroup(ctx context.Context, opts groups.CreateOptsBuilder) (*groups.SecGroup, error)
+	DeleteSecGroup(ctx context.Context, id string) error
+	GetSecGroup(ctx context.Context, id string) (*groups.SecGroup, error)
+	UpdateSecGroup(ctx context.Context, id string, opts groups.UpdateOptsBuilder) (*groups.SecGroup, error)
+
+	ListSecGroupRule(ctx context.Context, opts rules.ListOpts) ([]rules.SecGroupRule, error)
+	CreateSecGroupRule(ctx context.Context, opts rules.CreateOptsBuilder) (*rules.SecGroupRule, error)
+	DeleteSecGroupRule(ctx context.Context, id string) error
+	GetSecGroupRule(ctx context.Context, id string) (*rules.SecGroupRule, error)
+
+	ListNetwork(ctx context.Context, opts networks.ListOptsBuilder) ([]networks.Network, error)
+	CreateNetwork(ctx context.Context, opts networks.CreateOptsBuilder) (*networks.Network, error)
+	DeleteNetwork(ctx context.Context, id string) error
+	GetNetwork(ctx context.Context, id string) (*networks.Network, error)
+	UpdateNetwork(ctx context.Context, id string, opts networks.UpdateOptsBuilder) (*networks.Network, error)
+
+	ListSubnet(ctx context.Context, opts subnets.ListOptsBuilder) ([]subnets.Subnet, error)
+	CreateSubnet(ctx context.Context, opts subnets.CreateOptsBuilder) (*subnets.Subnet, error)
+	DeleteSubnet(ctx context.Context, id string) error
+	GetSubnet(ctx context.Context, id string) (*subnets.Subnet, error)
+	UpdateSubnet(ctx context.Context, id string, opts subnets.UpdateOptsBuilder) (*subnets.Subnet, error)
+
+	ListExtensions(ctx context.Context) ([]extensions.Extension, error)
+
+	ListQoSPolicy(ctx context.Context, opts policies.PolicyListOptsBuilder) ([]policies.Policy, error)
+
+	GetQuotaDetail(ctx context.Context, projectID string) (*quotas.QuotaDetailSet, error)
+
+	ReplaceAllAttributesTags(ctx context.Context, resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error)
 }
 
 type networkClient struct {
@@ -105,339 +139,556 @@ func NewNetworkClient(scope *scope.Scope) (NetworkClient, error) {
 	return networkClient{serviceClient}, nil
 }
 
-func (c networkClient) AddRouterInterface(id string, opts routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+func (c networkClient) AddRouterInterface(ctx context.Context, id string, opts routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "create")
-	interfaceInfo, err := routers.AddInterface(c.serviceClient, id, opts).Extract()
+	interfaceInfo, err := routers.AddInterface(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return interfaceInfo, nil
 }
 
-func (c networkClient) RemoveRouterInterface(id string, opts routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+func (c networkClient) RemoveRouterInterface(ctx context.Context, id string, opts routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "delete")
-	interfaceInfo, err := routers.RemoveInterface(c.serviceClient, id, opts).Extract()
+	interfaceInfo, err := routers.RemoveInterface(client, id, opts).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return interfaceInfo, nil
 }
 
-func (c networkClient) ReplaceAllAttributesTags(resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error) {
+func (c networkClient) ReplaceAllAttributesTags(ctx context.Context, resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("attributes_tags", "replace_all")
-	tags, err := attributestags.ReplaceAll(c.serviceClient, resourceType, resourceID, opts).Extract()
+	tags, err := attributestags.ReplaceAll(client, resourceType, resourceID, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return tags, nil
 }
 
-func (c networkClient) ListRouter(opts routers.ListOpts) ([]routers.Router, error) {
+func (c networkClient) ListRouter(ctx context.Context, opts routers.ListOpts) ([]routers.Router, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("router", "list")
-	allPages, err := routers.List(c.serviceClient, opts).AllPages()
+	allPages, err := routers.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return routers.ExtractRouters(allPages)
 }
 
-func (c networkClient) ListFloatingIP(opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
+func (c networkClient) ListFloatingIP(ctx context.Context, opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("floating_ip", "list")
-	allPages, err := floatingips.List(c.serviceClient, opts).AllPages()
+	allPages, err := floatingips.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return floatingips.ExtractFloatingIPs(allPages)
 }
 
-func (c networkClient) CreateFloatingIP(opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
+func (c networkClient) CreateFloatingIP(ctx context.Context, opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("floating_ip", "create")
-	fip, err := floatingips.Create(c.serviceClient, opts).Extract()
+	fip, err := floatingips.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return fip, nil
 }
 
-func (c networkClient) DeleteFloatingIP(id string) error {
+func (c networkClient) DeleteFloatingIP(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("floating_ip", "delete")
-	return mc.ObserveRequestIgnoreNotFound(floatingips.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(floatingips.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetFloatingIP(id string) (*floatingips.FloatingIP, error) {
+func (c networkClient) GetFloatingIP(ctx context.Context, id string) (*floatingips.FloatingIP, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("floating_ip", "list")
-	fip, err := floatingips.Get(c.serviceClient, id).Extract()
+	fip, err := floatingips.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return fip, nil
 }
 
-func (c networkClient) UpdateFloatingIP(id string, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
+func (c networkClient) UpdateFloatingIP(ctx context.Context, id string, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("floating_ip", "update")
+	fip, err := floatingips.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return fip, nil
+}
+
+// GetFloatingIPWithRevision returns the floating IP together with its revision number, which
+// can be passed to UpdateFloatingIPWithRevision.
+func (c networkClient) GetFloatingIPWithRevision(ctx context.Context, id string) (*floatingips.FloatingIP, int, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("floating_ip", "get")
+	r := floatingips.Get(client, id)
+	fip, err := r.Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, 0, err
+	}
+	var rev struct {
+		FloatingIP neutronRevision `json:"floatingip"`
+	}
+	if err := r.ExtractInto(&rev); err != nil {
+		return nil, 0, err
+	}
+	return fip, rev.FloatingIP.RevisionNumber, nil
+}
+
+// UpdateFloatingIPWithRevision updates the floating IP only if it still has the given revision
+// number, and fails with 412 Precondition Failed if it was modified in the meantime.
+func (c networkClient) UpdateFloatingIPWithRevision(ctx context.Context, id string, revisionNumber int, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	b, err := opts.ToFloatingIPUpdateMap()
+	if err != nil {
+		return nil, err
+	}
 	mc := metrics.NewMetricPrometheusContext("floating_ip", "update")
-	fip, err := floatingips.Update(c.serviceClient, id, opts).Extract()
+	var r floatingips.UpdateResult
+	resp, err := client.Put(client.ServiceURL("floatingips", id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes:     []int{200},
+		MoreHeaders: ifMatchRevision(revisionNumber),
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	fip, err := r.Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return fip, nil
 }
 
-func (c networkClient) ListPort(opts ports.ListOptsBuilder) ([]ports.Port, error) {
+func (c networkClient) ListPort(ctx context.Context, opts ports.ListOptsBuilder) ([]ports.Port, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("port", "list")
-	allPages, err := ports.List(c.serviceClient, opts).AllPages()
+	allPages, err := ports.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return ports.ExtractPorts(allPages)
 }
 
-func (c networkClient) CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error) {
+func (c networkClient) CreatePort(ctx context.Context, opts ports.CreateOptsBuilder) (*ports.Port, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("port", "create")
-	port, err := ports.Create(c.serviceClient, opts).Extract()
+	port, err := ports.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return port, nil
 }
 
-func (c networkClient) DeletePort(id string) error {
+func (c networkClient) DeletePort(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("port", "delete")
-	return mc.ObserveRequestIgnoreNotFound(ports.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(ports.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetPort(id string) (*ports.Port, error) {
+func (c networkClient) GetPort(ctx context.Context, id string) (*ports.Port, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("port", "get")
-	port, err := ports.Get(c.serviceClient, id).Extract()
+	port, err := ports.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return port, nil
 }
 
-func (c networkClient) UpdatePort(id string, opts ports.UpdateOptsBuilder) (*ports.Port, error) {
+func (c networkClient) UpdatePort(ctx context.Context, id string, opts ports.UpdateOptsBuilder) (*ports.Port, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("port", "update")
+	port, err := ports.Update(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return port, nil
+}
+
+// GetPortWithRevision returns the port together with its revision number, which can be passed
+// to UpdatePortWithRevision.
+func (c networkClient) GetPortWithRevision(ctx context.Context, id string) (*ports.Port, int, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("port", "get")
+	r := ports.Get(client, id)
+	port, err := r.Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, 0, err
+	}
+	var rev struct {
+		Port neutronRevision `json:"port"`
+	}
+	if err := r.ExtractInto(&rev); err != nil {
+		return nil, 0, err
+	}
+	return port, rev.Port.RevisionNumber, nil
+}
+
+// UpdatePortWithRevision updates the port only if it still has the given revision number, and
+// fails with 412 Precondition Failed if it was modified in the meantime.
+func (c networkClient) UpdatePortWithRevision(ctx context.Context, id string, revisionNumber int, opts ports.UpdateOptsBuilder) (*ports.Port, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	b, err := opts.ToPortUpdateMap()
+	if err != nil {
+		return nil, err
+	}
 	mc := metrics.NewMetricPrometheusContext("port", "update")
-	port, err := ports.Update(c.serviceClient, id, opts).Extract()
+	var r ports.UpdateResult
+	resp, err := client.Put(client.ServiceURL("ports", id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes:     []int{200, 201},
+		MoreHeaders: ifMatchRevision(revisionNumber),
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	port, err := r.Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return port, nil
 }
 
-func (c networkClient) CreateTrunk(opts trunks.CreateOptsBuilder) (*trunks.Trunk, error) {
+func (c networkClient) CreateTrunk(ctx context.Context, opts trunks.CreateOptsBuilder) (*trunks.Trunk, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("trunk", "create")
-	trunk, err := trunks.Create(c.serviceClient, opts).Extract()
+	trunk, err := trunks.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return trunk, nil
 }
 
-func (c networkClient) DeleteTrunk(id string) error {
+func (c networkClient) DeleteTrunk(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("trunk", "delete")
-	return mc.ObserveRequestIgnoreNotFound(trunks.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(trunks.Delete(client, id).ExtractErr())
+}
+
+func (c networkClient) AddSubports(ctx context.Context, id string, opts trunks.AddSubportsOptsBuilder) (*trunks.Trunk, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("trunk_subport", "create")
+	trunk, err := trunks.AddSubports(client, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return trunk, nil
 }
 
-func (c networkClient) ListTrunk(opts trunks.ListOptsBuilder) ([]trunks.Trunk, error) {
+func (c networkClient) ListTrunk(ctx context.Context, opts trunks.ListOptsBuilder) ([]trunks.Trunk, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("trunk", "list")
-	allPages, err := trunks.List(c.serviceClient, opts).AllPages()
+	allPages, err := trunks.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return trunks.ExtractTrunks(allPages)
 }
 
-func (c networkClient) CreateRouter(opts routers.CreateOptsBuilder) (*routers.Router, error) {
+func (c networkClient) CreateRouter(ctx context.Context, opts routers.CreateOptsBuilder) (*routers.Router, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("router", "create")
-	router, err := routers.Create(c.serviceClient, opts).Extract()
+	router, err := routers.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return router, nil
 }
 
-func (c networkClient) DeleteRouter(id string) error {
+func (c networkClient) DeleteRouter(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("router", "delete")
-	return mc.ObserveRequestIgnoreNotFound(routers.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(routers.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetRouter(id string) (*routers.Router, error) {
+func (c networkClient) GetRouter(ctx context.Context, id string) (*routers.Router, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("router", "get")
-	router, err := routers.Get(c.serviceClient, id).Extract()
+	router, err := routers.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return router, nil
 }
 
-func (c networkClient) UpdateRouter(id string, opts routers.UpdateOptsBuilder) (*routers.Router, error) {
+func (c networkClient) UpdateRouter(ctx context.Context, id string, opts routers.UpdateOptsBuilder) (*routers.Router, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("router", "update")
-	router, err := routers.Update(c.serviceClient, id, opts).Extract()
+	router, err := routers.Update(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return router, nil
 }
 
-func (c networkClient) ListSecGroup(opts groups.ListOpts) ([]groups.SecGroup, error) {
+func (c networkClient) GetRouterGatewayInfo(ctx context.Context, id string) (*RouterGatewayInfo, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("router", "get")
+	var s struct {
+		Router struct {
+			GatewayInfo RouterGatewayInfo `json:"external_gateway_info"`
+		} `json:"router"`
+	}
+	err := routers.Get(client, id).ExtractInto(&s)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &s.Router.GatewayInfo, nil
+}
+
+func (c networkClient) ListSecGroup(ctx context.Context, opts groups.ListOpts) ([]groups.SecGroup, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("group", "list")
-	allPages, err := groups.List(c.serviceClient, opts).AllPages()
+	allPages, err := groups.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return groups.ExtractGroups(allPages)
 }
 
-func (c networkClient) CreateSecGroup(opts groups.CreateOptsBuilder) (*groups.SecGroup, error) {
+func (c networkClient) CreateSecGroup(ctx context.Context, opts groups.CreateOptsBuilder) (*groups.SecGroup, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group", "create")
-	group, err := groups.Create(c.serviceClient, opts).Extract()
+	group, err := groups.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return group, nil
 }
 
-func (c networkClient) DeleteSecGroup(id string) error {
+func (c networkClient) DeleteSecGroup(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group", "delete")
-	return mc.ObserveRequestIgnoreNotFound(groups.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(groups.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetSecGroup(id string) (*groups.SecGroup, error) {
+func (c networkClient) GetSecGroup(ctx context.Context, id string) (*groups.SecGroup, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group", "get")
-	group, err := groups.Get(c.serviceClient, id).Extract()
+	group, err := groups.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return group, nil
 }
 
-func (c networkClient) UpdateSecGroup(id string, opts groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
+func (c networkClient) UpdateSecGroup(ctx context.Context, id string, opts groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group", "update")
-	group, err := groups.Update(c.serviceClient, id, opts).Extract()
+	group, err := groups.Update(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return group, nil
 }
 
-func (c networkClient) ListSecGroupRule(opts rules.ListOpts) ([]rules.SecGroupRule, error) {
+func (c networkClient) ListSecGroupRule(ctx context.Context, opts rules.ListOpts) ([]rules.SecGroupRule, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group_rule", "list")
-	allPages, err := rules.List(c.serviceClient, opts).AllPages()
+	allPages, err := rules.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return rules.ExtractRules(allPages)
 }
 
-func (c networkClient) CreateSecGroupRule(opts rules.CreateOptsBuilder) (*rules.SecGroupRule, error) {
+func (c networkClient) CreateSecGroupRule(ctx context.Context, opts rules.CreateOptsBuilder) (*rules.SecGroupRule, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group_rule", "create")
-	rule, err := rules.Create(c.serviceClient, opts).Extract()
+	rule, err := rules.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return rule, nil
 }
 
-func (c networkClient) DeleteSecGroupRule(id string) error {
+func (c networkClient) DeleteSecGroupRule(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group_rule", "delete")
-	return mc.ObserveRequestIgnoreNotFound(rules.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(rules.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetSecGroupRule(id string) (*rules.SecGroupRule, error) {
+func (c networkClient) GetSecGroupRule(ctx context.Context, id string) (*rules.SecGroupRule, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("security_group_rule", "get")
-	rule, err := rules.Get(c.serviceClient, id).Extract()
+	rule, err := rules.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return rule, nil
 }
 
-func (c networkClient) ListNetwork(opts networks.ListOptsBuilder) ([]networks.Network, error) {
+func (c networkClient) ListNetwork(ctx context.Context, opts networks.ListOptsBuilder) ([]networks.Network, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network", "list")
-	allPages, err := networks.List(c.serviceClient, opts).AllPages()
+	allPages, err := networks.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return networks.ExtractNetworks(allPages)
 }
 
-func (c networkClient) CreateNetwork(opts networks.CreateOptsBuilder) (*networks.Network, error) {
+func (c networkClient) CreateNetwork(ctx context.Context, opts networks.CreateOptsBuilder) (*networks.Network, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network", "create")
-	net, err := networks.Create(c.serviceClient, opts).Extract()
+	net, err := networks.Create(client, opts).Extract()
 	if (mc.ObserveRequest(err)) != nil {
 		return nil, err
 	}
 	return net, nil
 }
 
-func (c networkClient) DeleteNetwork(id string) error {
+func (c networkClient) DeleteNetwork(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network", "delete")
-	return mc.ObserveRequestIgnoreNotFound(networks.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(networks.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetNetwork(id string) (*networks.Network, error) {
+func (c networkClient) GetNetwork(ctx context.Context, id string) (*networks.Network, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network", "get")
-	net, err := networks.Get(c.serviceClient, id).Extract()
+	net, err := networks.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return net, nil
 }
 
-func (c networkClient) UpdateNetwork(id string, opts networks.UpdateOptsBuilder) (*networks.Network, error) {
+func (c networkClient) UpdateNetwork(ctx context.Context, id string, opts networks.UpdateOptsBuilder) (*networks.Network, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network", "update")
-	net, err := networks.Update(c.serviceClient, id, opts).Extract()
+	net, err := networks.Update(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return net, nil
 }
 
-func (c networkClient) ListSubnet(opts subnets.ListOptsBuilder) ([]subnets.Subnet, error) {
+func (c networkClient) ListSubnet(ctx context.Context, opts subnets.ListOptsBuilder) ([]subnets.Subnet, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("subnet", "list")
-	allPages, err := subnets.List(c.serviceClient, opts).AllPages()
+	allPages, err := subnets.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return subnets.ExtractSubnets(allPages)
 }
 
-func (c networkClient) CreateSubnet(opts subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
+func (c networkClient) CreateSubnet(ctx context.Context, opts subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("subnet", "create")
-	subnet, err := subnets.Create(c.serviceClient, opts).Extract()
+	subnet, err := subnets.Create(client, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return subnet, nil
 }
 
-func (c networkClient) DeleteSubnet(id string) error {
+func (c networkClient) DeleteSubnet(ctx context.Context, id string) error {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("subnet", "delete")
-	return mc.ObserveRequestIgnoreNotFound(subnets.Delete(c.serviceClient, id).ExtractErr())
+	return mc.ObserveRequestIgnoreNotFound(subnets.Delete(client, id).ExtractErr())
 }
 
-func (c networkClient) GetSubnet(id string) (*subnets.Subnet, error) {
+func (c networkClient) GetSubnet(ctx context.Context, id string) (*subnets.Subnet, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("subnet", "get")
-	subnet, err := subnets.Get(c.serviceClient, id).Extract()
+	subnet, err := subnets.Get(client, id).Extract()
 	if mc.ObserveRequestIgnoreNotFound(err) != nil {
 		return nil, err
 	}
 	return subnet, nil
 }
 
-func (c networkClient) UpdateSubnet(id string, opts subnets.UpdateOptsBuilder) (*subnets.Subnet, error) {
+func (c networkClient) UpdateSubnet(ctx context.Context, id string, opts subnets.UpdateOptsBuilder) (*subnets.Subnet, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("subnet", "update")
-	subnet, err := subnets.Update(c.serviceClient, id, opts).Extract()
+	subnet, err := subnets.Update(client, id, opts).Extract()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return subnet, nil
 }
 
-func (c networkClient) ListExtensions() ([]extensions.Extension, error) {
+func (c networkClient) ListExtensions(ctx context.Context) ([]extensions.Extension, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("network_extension", "list")
-	allPages, err := extensions.List(c.serviceClient).AllPages()
+	allPages, err := extensions.List(client).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return extensions.ExtractExtensions(allPages)
 }
+
+func (c networkClient) ListQoSPolicy(ctx context.Context, opts policies.PolicyListOptsBuilder) ([]policies.Policy, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("qos_policy", "list")
+	allPages, err := policies.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return policies.ExtractPolicies(allPages)
+}
+
+func (c networkClient) GetQuotaDetail(ctx context.Context, projectID string) (*quotas.QuotaDetailSet, error) {
+	client, cancel := withContext(ctx, c.serviceClient)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("network_quota", "get")
+	quota, err := quotas.GetDetail(client, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return quota, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+type ObjectStorageClient interface {
+	CreateObject(ctx context.Context, container, name string, opts objects.CreateOptsBuilder) error
+	CreateTempURL(ctx context.Context, container, name string, opts objects.CreateTempURLOpts) (string, error)
+}
+
+type objectStorageClient struct{ client *gophercloud.ServiceClient }
+
+// NewObjectStorageClient returns a new swift client.
+func NewObjectStorageClient(scope *scope.Scope) (ObjectStorageClient, error) {
+	objectStorage, err := openstack.NewObjectStorageV1(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object storage service client: %v", err)
+	}
+
+	return &objectStorageClient{objectStorage}, nil
+}
+
+func (c objectStorageClient) CreateObject(ctx context.Context, container, name string, opts objects.CreateOptsBuilder) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("object", "create")
+	_, err := objects.Create(client, container, name, opts).Extract()
+	return mc.ObserveRequest(err)
+}
+
+func (c objectStorageClient) CreateTempURL(ctx context.Context, container, name string, opts objects.CreateTempURLOpts) (string, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	// Creating the temporary URL reads the temp URL key of the container or account
+	mc := metrics.NewMetricPrometheusContext("object_temp_url", "create")
+	url, err := objects.CreateTempURL(client, container, name, opts)
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return url, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// PlacementMinimumMicroversion is the minimum Placement microversion used by CAPO.
+// 1.22 corresponds to OpenStack Rocky and adds forbidden traits, i.e. !TRAIT, to the
+// required filter of resource providers.
+const PlacementMinimumMicroversion = "1.22"
+
+type PlacementClient interface {
+	ListResourceProviders(ctx context.Context, opts resourceproviders.ListOptsBuilder) ([]resourceproviders.ResourceProvider, error)
+}
+
+type placementClient struct{ client *gophercloud.ServiceClient }
+
+// NewPlacementClient returns a new placement client.
+func NewPlacementClient(scope *scope.Scope) (PlacementClient, error) {
+	placement, err := openstack.NewPlacementV1(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create placement service client: %w", err)
+	}
+	placement.Microversion = PlacementMinimumMicroversion
+
+	return &placementClient{placement}, nil
+}
+
+func (c placementClient) ListResourceProviders(ctx context.Context, opts resourceproviders.ListOptsBuilder) ([]resourceproviders.ResourceProvider, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("resource_provider", "list")
+	allPages, err := resourceproviders.List(client, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return resourceproviders.ExtractResourceProviders(allPages)
+}
+
+type placementErrorClient struct{ error }
+
+// NewPlacementErrorClient returns a PlacementClient in which every method returns the given error.
+func NewPlacementErrorClient(e error) PlacementClient {
+	return placementErrorClient{e}
+}
+
+func (e placementErrorClient) ListResourceProviders(ctx context.Context, opts resourceproviders.ListOptsBuilder) ([]resourceproviders.ResourceProvider, error) {
+	return nil, e.error
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	// LeaseStatusActive is the status of a Blazar lease between its start and end date.
+	LeaseStatusActive = "ACTIVE"
+	// ReservationStatusActive is the status of a reservation of an active lease.
+	ReservationStatusActive = "active"
+
+	// ReservationResourceTypeHost is the resource type of a reservation of physical hosts.
+	ReservationResourceTypeHost = "physical:host"
+	// ReservationResourceTypeInstance is the resource type of a reservation of instances.
+	ReservationResourceTypeInstance = "virtual:instance"
+)
+
+// Lease is a Blazar lease. gophercloud has no support for Blazar, so only the
+// fields used by CAPO are decoded.
+type Lease struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Status       string             `json:"status"`
+	StartDate    string             `json:"start_date"`
+	EndDate      string             `json:"end_date"`
+	Reservations []LeaseReservation `json:"reservations"`
+}
+
+// LeaseReservation is a reservation of a Blazar lease.
+type LeaseReservation struct {
+	ID           string `json:"id"`
+	ResourceType string `json:"resource_type"`
+	Status       string `json:"status"`
+}
+
+type ReservationClient interface {
+	GetLease(ctx context.Context, leaseID string) (*Lease, error)
+}
+
+type reservationClient struct{ client *gophercloud.ServiceClient }
+
+// NewReservationClient returns a new Blazar client.
+func NewReservationClient(scope *scope.Scope) (ReservationClient, error) {
+	eo := gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	}
+	eo.ApplyDefaults("reservation")
+	url, err := scope.ProviderClient.EndpointLocator(eo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reservation service client: %w", err)
+	}
+
+	return &reservationClient{&gophercloud.ServiceClient{
+		ProviderClient: scope.ProviderClient,
+		Endpoint:       url,
+		Type:           "reservation",
+	}}, nil
+}
+
+func (c reservationClient) GetLease(ctx context.Context, leaseID string) (*Lease, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	var body struct {
+		Lease Lease `json:"lease"`
+	}
+	mc := metrics.NewMetricPrometheusContext("lease", "get")
+	_, err := client.Get(client.ServiceURL("leases", leaseID), &body, nil)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &body.Lease, nil
+}
+
+type reservationErrorClient struct{ error }
+
+// NewReservationErrorClient returns a ReservationClient in which every method returns the given error.
+func NewReservationErrorClient(e error) ReservationClient {
+	return reservationErrorClient{e}
+}
+
+func (e reservationErrorClient) GetLease(ctx context.Context, leaseID string) (*Lease, error) {
+	return nil, e.error
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	// maxRetries is the maximum number of retries of a request.
+	maxRetries = 5
+	// retryBaseDelay is the delay before the first retry of a request without Retry-After header,
+	// which doubles with each retry.
+	retryBaseDelay = time.Second
+	// retryMaxDelay is the maximum delay before a retry. A request whose Retry-After header asks
+	// for a longer delay is not retried, so that it fails the reconcile instead of blocking it.
+	retryMaxDelay = 30 * time.Second
+	// retryJitter adds a random duration of up to retryJitter times the delay to each delay.
+	retryJitter = 0.5
+)
+
+// idempotentMethods are the methods whose requests are retried.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// RetryFunc retries idempotent requests rejected with 429 Too Many Requests or 503 Service
+// Unavailable. It waits for the delay of the Retry-After header of the response if it is set, and
+// a jittered exponential backoff otherwise. It is used as the gophercloud.RetryFunc of the
+// provider clients.
+func RetryFunc(ctx context.Context, method, url string, options *gophercloud.RequestOpts, err error, failCount uint) error {
+	delay, ok := retryDelay(method, err, failCount)
+	if !ok {
+		return err
+	}
+	metrics.APIRequestRetried(method, capoerrors.StatusCode(err))
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return err
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryDelay returns the delay before retrying a request which failed with err for the
+// failCount time, and false if it must not be retried.
+func retryDelay(method string, err error, failCount uint) (time.Duration, bool) {
+	if !idempotentMethods[method] || failCount > maxRetries {
+		return 0, false
+	}
+
+	var respErr gophercloud.ErrUnexpectedResponseCode
+	var err429 gophercloud.ErrDefault429
+	var err503 gophercloud.ErrDefault503
+	switch {
+	case errors.As(err, &err429):
+		respErr = err429.ErrUnexpectedResponseCode
+	case errors.As(err, &err503):
+		respErr = err503.ErrUnexpectedResponseCode
+	default:
+		return 0, false
+	}
+
+	if delay, ok := retryAfter(respErr.ResponseHeader); ok {
+		return delay, delay <= retryMaxDelay
+	}
+
+	delay := retryBaseDelay << (failCount - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return wait.Jitter(delay, retryJitter), true
+}
+
+// retryAfter returns the delay of the Retry-After header, which is either a number of seconds or
+// an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
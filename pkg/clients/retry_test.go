@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		responses []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "Too many requests are retried",
+			method:    http.MethodGet,
+			responses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			wantCalls: 3,
+		},
+		{
+			name:      "Unavailable service is retried",
+			method:    http.MethodDelete,
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls: 2,
+		},
+		{
+			name:      "Non-idempotent requests are not retried",
+			method:    http.MethodPost,
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Other errors are not retried",
+			method:    http.MethodGet,
+			responses: []int{http.StatusInternalServerError, http.StatusOK},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Retries are limited",
+			method:    http.MethodGet,
+			responses: []int{503, 503, 503, 503, 503, 503, 503},
+			wantCalls: maxRetries + 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.responses[calls])
+				calls++
+			}))
+			defer server.Close()
+
+			providerClient := &gophercloud.ProviderClient{RetryFunc: RetryFunc}
+			_, err := providerClient.Request(tt.method, server.URL, &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK}})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(calls).To(Equal(tt.wantCalls))
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	response := func(code int, retryAfter string) error {
+		header := http.Header{}
+		if retryAfter != "" {
+			header.Set("Retry-After", retryAfter)
+		}
+		respErr := gophercloud.ErrUnexpectedResponseCode{Actual: code, ResponseHeader: header}
+		if code == http.StatusTooManyRequests {
+			return gophercloud.ErrDefault429{ErrUnexpectedResponseCode: respErr}
+		}
+		return gophercloud.ErrDefault503{ErrUnexpectedResponseCode: respErr}
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		failCount uint
+		wantMin   time.Duration
+		wantMax   time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "Retry-After in seconds",
+			err:       response(http.StatusTooManyRequests, "7"),
+			failCount: 1,
+			wantMin:   7 * time.Second,
+			wantMax:   7 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "Retry-After as a date",
+			err:       response(http.StatusServiceUnavailable, time.Now().Add(20*time.Second).UTC().Format(http.TimeFormat)),
+			failCount: 1,
+			wantMin:   18 * time.Second,
+			wantMax:   20 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "Retry-After beyond the maximum delay",
+			err:       response(http.StatusServiceUnavailable, "3600"),
+			failCount: 1,
+		},
+		{
+			name:      "Exponential backoff",
+			err:       response(http.StatusServiceUnavailable, ""),
+			failCount: 3,
+			wantMin:   4 * time.Second,
+			wantMax:   6 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "Last retry",
+			err:       response(http.StatusTooManyRequests, ""),
+			failCount: maxRetries,
+			wantMin:   16 * time.Second,
+			wantMax:   24 * time.Second,
+			wantRetry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			delay, retry := retryDelay(http.MethodGet, tt.err, tt.failCount)
+			g.Expect(retry).To(Equal(tt.wantRetry))
+			if tt.wantRetry {
+				g.Expect(delay).To(BeNumerically(">=", tt.wantMin))
+				g.Expect(delay).To(BeNumerically("<=", tt.wantMax))
+			}
+		})
+	}
+}
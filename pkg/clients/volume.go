@@ -17,21 +17,28 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/availabilityzones"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/qos"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumetypes"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 type VolumeClient interface {
-	ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error)
-	CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error)
-	DeleteVolume(volumeID string, opts volumes.DeleteOptsBuilder) error
-	GetVolume(volumeID string) (*volumes.Volume, error)
+	ListVolumes(ctx context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error)
+	CreateVolume(ctx context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error)
+	DeleteVolume(ctx context.Context, volumeID string, opts volumes.DeleteOptsBuilder) error
+	GetVolume(ctx context.Context, volumeID string) (*volumes.Volume, error)
+	ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error)
+	ListVolumeTypes(ctx context.Context) ([]volumetypes.VolumeType, error)
+	ListQoSSpecs(ctx context.Context) ([]qos.QoS, error)
 }
 
 type volumeClient struct{ client *gophercloud.ServiceClient }
@@ -42,39 +49,80 @@ func NewVolumeClient(scope *scope.Scope) (VolumeClient, error) {
 		Region: scope.ProviderClientOpts.RegionName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create volume service client: %v", err)
+		return nil, fmt.Errorf("failed to create volume service client: %w", err)
 	}
 
 	return &volumeClient{volume}, nil
 }
 
-func (c volumeClient) ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+func (c volumeClient) ListVolumes(ctx context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("volume", "list")
-	pages, err := volumes.List(c.client, opts).AllPages()
+	pages, err := volumes.List(client, opts).AllPages()
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
 	return volumes.ExtractVolumes(pages)
 }
 
-func (c volumeClient) CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+func (c volumeClient) CreateVolume(ctx context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("volume", "create")
-	volume, err := volumes.Create(c.client, opts).Extract()
+	volume, err := volumes.Create(client, opts).Extract()
 	return volume, mc.ObserveRequest(err)
 }
 
-func (c volumeClient) DeleteVolume(volumeID string, opts volumes.DeleteOptsBuilder) error {
+func (c volumeClient) DeleteVolume(ctx context.Context, volumeID string, opts volumes.DeleteOptsBuilder) error {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("volume", "delete")
-	err := volumes.Delete(c.client, volumeID, opts).ExtractErr()
+	err := volumes.Delete(client, volumeID, opts).ExtractErr()
 	return mc.ObserveRequestIgnoreNotFound(err)
 }
 
-func (c volumeClient) GetVolume(volumeID string) (*volumes.Volume, error) {
+func (c volumeClient) GetVolume(ctx context.Context, volumeID string) (*volumes.Volume, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
 	mc := metrics.NewMetricPrometheusContext("volume", "get")
-	volume, err := volumes.Get(c.client, volumeID).Extract()
+	volume, err := volumes.Get(client, volumeID).Extract()
 	return volume, mc.ObserveRequestIgnoreNotFound(err)
 }
 
+func (c volumeClient) ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("volume_availability_zone", "list")
+	allPages, err := availabilityzones.List(client).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return availabilityzones.ExtractAvailabilityZones(allPages)
+}
+
+func (c volumeClient) ListVolumeTypes(ctx context.Context) ([]volumetypes.VolumeType, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("volume_type", "list")
+	allPages, err := volumetypes.List(client, volumetypes.ListOpts{}).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return volumetypes.ExtractVolumeTypes(allPages)
+}
+
+func (c volumeClient) ListQoSSpecs(ctx context.Context) ([]qos.QoS, error) {
+	client, cancel := withContext(ctx, c.client)
+	defer cancel()
+	mc := metrics.NewMetricPrometheusContext("volume_qos", "list")
+	allPages, err := qos.List(client, qos.ListOpts{}).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return qos.ExtractQoS(allPages)
+}
+
 type volumeErrorClient struct{ error }
 
 // NewVolumeErrorClient returns a VolumeClient in which every method returns the given error.
@@ -82,18 +130,30 @@ func NewVolumeErrorClient(e error) VolumeClient {
 	return volumeErrorClient{e}
 }
 
-func (e volumeErrorClient) ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+func (e volumeErrorClient) ListVolumes(ctx context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
 	return nil, e.error
 }
 
-func (e volumeErrorClient) CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+func (e volumeErrorClient) CreateVolume(ctx context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
 	return nil, e.error
 }
 
-func (e volumeErrorClient) DeleteVolume(volumeID string, opts volumes.DeleteOptsBuilder) error {
+func (e volumeErrorClient) DeleteVolume(ctx context.Context, volumeID string, opts volumes.DeleteOptsBuilder) error {
 	return e.error
 }
 
-func (e volumeErrorClient) GetVolume(volumeID string) (*volumes.Volume, error) {
+func (e volumeErrorClient) GetVolume(ctx context.Context, volumeID string) (*volumes.Volume, error) {
+	return nil, e.error
+}
+
+func (e volumeErrorClient) ListAvailabilityZones(ctx context.Context) ([]availabilityzones.AvailabilityZone, error) {
+	return nil, e.error
+}
+
+func (e volumeErrorClient) ListVolumeTypes(ctx context.Context) ([]volumetypes.VolumeType, error) {
+	return nil, e.error
+}
+
+func (e volumeErrorClient) ListQoSSpecs(ctx context.Context) ([]qos.QoS, error) {
 	return nil, e.error
 }
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
+)
+
+// ErrServerNotAdoptable is returned when the existing server to adopt was not found or does not match the spec
+// of the machine.
+var ErrServerNotAdoptable = errors.New("server cannot be adopted")
+
+// AdoptInstance takes over an existing server instead of creating a new one, e.g. to migrate a cluster which was
+// built by hand. The server is selected by its ID if instanceID is set, otherwise by the filter, which must match
+// exactly one server. Its ports are taken over and named like the ports created by CAPO, in the order they are
+// attached to the server. The server is renamed after the instance last, so that an adoption which failed half
+// way is retried, as the instance is looked up by its name.
+func (s *Service) AdoptInstance(eventObject runtime.Object, instanceSpec *InstanceSpec, clusterName string, instanceID string, filter *infrav1.ServerFilter) (*InstanceStatus, error) {
+	server, err := s.findServerToAdopt(instanceID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkServerToAdopt(s.scope.CloudName(), server, instanceSpec); err != nil {
+		record.Warnf(eventObject, "FailedAdoptServer", "Failed to adopt server %s with id %s: %v", server.Name, server.ID, err)
+		return nil, err
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := s.getComputeClient().ListAttachedInterfaces(s.scope.Context(), server.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list interfaces of server %s: %v", server.ID, err)
+	}
+	for i := range interfaces {
+		var portOpts *infrav1.PortOpts
+		if i < len(instanceSpec.Ports) {
+			portOpts = &instanceSpec.Ports[i]
+		}
+		portName := getPortName(instanceSpec.Name, portOpts, i)
+		if err := networkingService.AdoptPort(eventObject, clusterName, interfaces[i].PortID, portName, portOpts, instanceSpec.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if server.Name != instanceSpec.Name {
+		if _, err := s.getComputeClient().UpdateServer(s.scope.Context(), server.ID, servers.UpdateOpts{Name: instanceSpec.Name}); err != nil {
+			record.Warnf(eventObject, "FailedAdoptServer", "Failed to rename server %s with id %s to %s: %v", server.Name, server.ID, instanceSpec.Name, err)
+			return nil, err
+		}
+	}
+	record.Eventf(eventObject, "SuccessfulAdoptServer", "Adopted server %s with id %s", server.Name, server.ID)
+
+	return s.GetInstanceStatus(server.ID)
+}
+
+func (s *Service) findServerToAdopt(instanceID string, filter *infrav1.ServerFilter) (*clients.ServerExt, error) {
+	if instanceID != "" {
+		instanceStatus, err := s.GetInstanceStatus(instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if instanceStatus == nil {
+			return nil, fmt.Errorf("%w: server %s was not found", ErrServerNotAdoptable, instanceID)
+		}
+		return instanceStatus.server, nil
+	}
+
+	if filter == nil {
+		return nil, fmt.Errorf("%w: no server was selected", ErrServerNotAdoptable)
+	}
+	var listOpts servers.ListOpts
+	if filter.Name != "" {
+		// The name is a regular expression, which is anchored for a whole string match
+		listOpts.Name = fmt.Sprintf("^%s$", filter.Name)
+	}
+	listOpts.Tags = strings.Join(filter.Tags, ",")
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("get server list: %v", err)
+	}
+	if len(serverList) != 1 {
+		return nil, fmt.Errorf("%w: %d servers match name %q and tags %v, expected exactly one", ErrServerNotAdoptable, len(serverList), filter.Name, filter.Tags)
+	}
+	return &serverList[0], nil
+}
+
+// checkServerToAdopt returns an error if the server does not match the spec of the instance. Only the properties
+// which Nova returns and which cannot be changed without replacing the server are compared.
+func checkServerToAdopt(cloudName string, server *clients.ServerExt, instanceSpec *InstanceSpec) error {
+	if server.Status == string(infrav1.InstanceStateError) || server.Status == string(infrav1.InstanceStateDeleted) {
+		return fmt.Errorf("%w: server is in state %s", ErrServerNotAdoptable, server.Status)
+	}
+
+	// The flavor of a server is only returned by name since microversion 2.47
+	if instanceSpec.FlavorID == "" && instanceSpec.Flavor != "" {
+		flavorName := flavoralias.Resolve(cloudName, instanceSpec.Flavor)
+		if originalName, _ := server.Flavor["original_name"].(string); originalName != flavorName {
+			return fmt.Errorf("%w: server has flavor %q, expected %q", ErrServerNotAdoptable, originalName, flavorName)
+		}
+	}
+
+	if instanceSpec.FailureDomain != "" && server.AvailabilityZone != instanceSpec.FailureDomain {
+		return fmt.Errorf("%w: server is in availability zone %q, expected %q", ErrServerNotAdoptable, server.AvailabilityZone, instanceSpec.FailureDomain)
+	}
+	return nil
+}
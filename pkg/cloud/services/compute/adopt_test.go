@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_AdoptInstance(t *testing.T) {
+	legacyServer := func(status, flavor string) clients.ServerExt {
+		server := clients.ServerExt{Server: servers.Server{
+			ID:     "server-id",
+			Name:   "legacy-node",
+			Status: status,
+			Flavor: map[string]interface{}{"original_name": flavor},
+		}}
+		server.AvailabilityZone = "az1"
+		return server
+	}
+	description := "Created by cluster-api-provider-openstack cluster test-cluster"
+	portName := openStackMachineName + "-0"
+
+	tests := []struct {
+		name          string
+		instanceID    string
+		filter        *infrav1.ServerFilter
+		expectCompute func(m *mock.MockComputeClientMockRecorder)
+		expectNetwork func(m *mock.MockNetworkClientMockRecorder)
+		wantErr       error
+	}{
+		{
+			name:   "Server selected by name is adopted",
+			filter: &infrav1.ServerFilter{Name: "legacy-node", Tags: []string{"legacy"}},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), servers.ListOpts{Name: "^legacy-node$", Tags: "legacy"}).Return([]clients.ServerExt{legacyServer("ACTIVE", flavorName)}, nil)
+				m.ListAttachedInterfaces(gomock.Any(), "server-id").Return([]attachinterfaces.Interface{{PortID: "port-id"}}, nil)
+				m.UpdateServer(gomock.Any(), "server-id", servers.UpdateOpts{Name: openStackMachineName}).Return(nil, nil)
+				server := legacyServer("ACTIVE", flavorName)
+				server.Name = openStackMachineName
+				m.GetServer(gomock.Any(), "server-id").Return(&server, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetPort(gomock.Any(), "port-id").Return(&ports.Port{ID: "port-id", Name: "legacy-port"}, nil)
+				m.UpdatePort(gomock.Any(), "port-id", ports.UpdateOpts{Name: &portName, Description: &description}).Return(&ports.Port{}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", "port-id", attributestags.ReplaceAllOpts{Tags: []string{"test-tag"}}).Return(nil, nil)
+			},
+		},
+		{
+			name:       "Server selected by ID is adopted",
+			instanceID: "server-id",
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				server := legacyServer("SHUTOFF", flavorName)
+				server.Name = openStackMachineName
+				m.GetServer(gomock.Any(), "server-id").Return(&server, nil).Times(2)
+				m.ListAttachedInterfaces(gomock.Any(), "server-id").Return(nil, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+		},
+		{
+			name:   "Several matching servers are not adopted",
+			filter: &infrav1.ServerFilter{Tags: []string{"legacy"}},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), servers.ListOpts{Tags: "legacy"}).Return([]clients.ServerExt{legacyServer("ACTIVE", flavorName), legacyServer("ACTIVE", flavorName)}, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			wantErr:       ErrServerNotAdoptable,
+		},
+		{
+			name:   "Server with another flavor is not adopted",
+			filter: &infrav1.ServerFilter{Name: "legacy-node"},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), servers.ListOpts{Name: "^legacy-node$"}).Return([]clients.ServerExt{legacyServer("ACTIVE", "m1.large")}, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			wantErr:       ErrServerNotAdoptable,
+		},
+		{
+			name:   "Server in error state is not adopted",
+			filter: &infrav1.ServerFilter{Name: "legacy-node"},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), servers.ListOpts{Name: "^legacy-node$"}).Return([]clients.ServerExt{legacyServer("ERROR", flavorName)}, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			wantErr:       ErrServerNotAdoptable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expectCompute(mockComputeClient.EXPECT())
+			tt.expectNetwork(mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope:              &scope.Scope{Logger: logr.Discard()},
+				_computeClient:     mockComputeClient,
+				_networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+			}
+
+			instanceSpec := getDefaultInstanceSpec()
+			instanceSpec.FailureDomain = "az1"
+			instanceStatus, err := s.AdoptInstance(&infrav1.OpenStackMachine{}, instanceSpec, "test-cluster", tt.instanceID, tt.filter)
+			if tt.wantErr != nil {
+				g.Expect(errors.Is(err, tt.wantErr)).To(BeTrue(), "unexpected error: %v", err)
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(instanceStatus.Name()).To(Equal(openStackMachineName))
+		})
+	}
+}
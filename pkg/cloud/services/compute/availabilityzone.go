@@ -17,16 +17,34 @@ limitations under the License.
 package compute
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/gophercloud/gophercloud"
+	volumeavailabilityzones "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 )
 
 func (s *Service) GetAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
-	availabilityZoneList, err := s.getComputeClient().ListAvailabilityZones()
+	availabilityZoneList, err := s.getComputeClient().ListAvailabilityZones(s.scope.Context())
 	if err != nil {
 		return nil, fmt.Errorf("error extracting availability zone list: %v", err)
 	}
 
 	return availabilityZoneList, nil
 }
+
+// GetVolumeAvailabilityZones returns the availability zones of Cinder. It returns nil
+// without an error if the cloud has no block storage service.
+func (s *Service) GetVolumeAvailabilityZones() ([]volumeavailabilityzones.AvailabilityZone, error) {
+	availabilityZoneList, err := s.getVolumeClient().ListAvailabilityZones(s.scope.Context())
+	if err != nil {
+		var endpointNotFound *gophercloud.ErrEndpointNotFound
+		if errors.As(err, &endpointNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error extracting volume availability zone list: %v", err)
+	}
+
+	return availabilityZoneList, nil
+}
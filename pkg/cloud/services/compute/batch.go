@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// batchCreateOpts creates between Min and Max servers with a single request. Nova
+// returns the reservation ID of the servers instead of the first server.
+type batchCreateOpts struct {
+	servers.CreateOptsBuilder
+	Min int
+	Max int
+}
+
+func (opts batchCreateOpts) ToServerCreateMap() (map[string]interface{}, error) {
+	b, err := opts.CreateOptsBuilder.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	server := b["server"].(map[string]interface{})
+	server["min_count"] = opts.Min
+	server["max_count"] = opts.Max
+	server["return_reservation_id"] = true
+	return b, nil
+}
+
+// reservationListOpts lists the servers created by a single batch request, which is not
+// supported by servers.ListOpts.
+type reservationListOpts struct {
+	ReservationID string
+}
+
+func (opts reservationListOpts) ToServerListQuery() (string, error) {
+	q := url.Values{}
+	q.Set("reservation_id", opts.ReservationID)
+	return "?" + q.Encode(), nil
+}
+
+// SupportsBatchCreate returns true if instances of the spec can be created with CreateInstanceBatch.
+func SupportsBatchCreate(instanceSpec *InstanceSpec) bool {
+	return !instanceSpec.Trunk && !hasRootVolume(instanceSpec.RootVolume) && len(instanceSpec.AdditionalBlockDevices) == 0 && len(instanceSpec.Ports) == 0
+}
+
+// CreateInstanceBatch creates between min and max instances of the spec with a single
+// request using Nova multi-create, which schedules them together instead of racing for the
+// same hosts one by one. Nova appends the index of each instance to the name of the spec.
+// It returns the reservation ID of the batch, which is used to find its instances with
+// GetInstanceStatusesByReservationID.
+//
+// Ports cannot be shared between the instances of a batch, so they are attached to the
+// networks of the spec directly and Nova creates their ports. Port options, trunks and
+// root volumes are therefore not supported.
+func (s *Service) CreateInstanceBatch(eventObject runtime.Object, openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec, min, max int) (string, error) {
+	if min < 1 || max < min {
+		return "", fmt.Errorf("invalid instance count: min %d, max %d", min, max)
+	}
+	if !SupportsBatchCreate(instanceSpec) {
+		return "", fmt.Errorf("ports, trunks and root volumes are not supported when creating instances in a batch")
+	}
+
+	imageID, err := s.getInstanceImageID(instanceSpec)
+	if err != nil {
+		return "", fmt.Errorf("error getting image ID: %w", err)
+	}
+
+	reservation, err := s.getReservation(instanceSpec.Reservation)
+	if err != nil {
+		return "", err
+	}
+
+	flavorID := reservationFlavorID(reservation)
+	if flavorID == "" {
+		flavorID, err = s.getFlavorID(instanceSpec.FlavorID, instanceSpec.Flavor)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.checkTraits(instanceSpec.Traits); err != nil {
+		return "", err
+	}
+
+	userData, err := s.getUserData(eventObject, instanceSpec)
+	if err != nil {
+		return "", fmt.Errorf("error getting user data: %v", err)
+	}
+
+	nets, err := s.constructNetworks(openStackCluster, instanceSpec)
+	if err != nil {
+		return "", err
+	}
+	networks := make([]servers.Network, 0, len(nets))
+	for _, network := range nets {
+		if network.ID == "" {
+			return "", fmt.Errorf("no network was found or provided. Please check your machine configuration and try again")
+		}
+		networks = append(networks, servers.Network{UUID: network.ID})
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return "", err
+	}
+	securityGroups, err := networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
+	if err != nil {
+		return "", fmt.Errorf("error getting security groups: %v", err)
+	}
+
+	var serverCreateOpts servers.CreateOptsBuilder = servers.CreateOpts{
+		Name:             instanceSpec.Name,
+		ImageRef:         imageID,
+		FlavorRef:        flavorID,
+		AvailabilityZone: instanceSpec.FailureDomain,
+		Networks:         networks,
+		SecurityGroups:   securityGroups,
+		UserData:         []byte(userData),
+		Tags:             instanceSpec.Tags,
+		Metadata:         instanceSpec.Metadata,
+		ConfigDrive:      pointer.Bool(instanceConfigDrive(openStackCluster, instanceSpec)),
+	}
+	serverCreateOpts = applySchedulerHints(serverCreateOpts, instanceSpec.ServerGroupID, reservationSchedulerHints(reservation, instanceSpec.SchedulerHints))
+
+	reservationID, err := s.getComputeClient().CreateServers(s.scope.Context(), batchCreateOpts{
+		CreateOptsBuilder: keypairs.CreateOptsExt{
+			CreateOptsBuilder: serverCreateOpts,
+			KeyName:           instanceSpec.SSHKeyName,
+		},
+		Min: min,
+		Max: max,
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateServer", "Failed to create %d to %d servers %s: %v", min, max, instanceSpec.Name, err)
+		return "", fmt.Errorf("error creating Openstack instances: %v", err)
+	}
+
+	record.Eventf(eventObject, "SuccessfulCreateServer", "Created %d to %d servers %s with reservation id %s", min, max, instanceSpec.Name, reservationID)
+	return reservationID, nil
+}
+
+// GetInstanceStatusesByReservationID returns the instances created by CreateInstanceBatch.
+func (s *Service) GetInstanceStatusesByReservationID(reservationID string) ([]*InstanceStatus, error) {
+	if reservationID == "" {
+		return nil, fmt.Errorf("reservation ID must not be empty")
+	}
+
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), reservationListOpts{ReservationID: reservationID})
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers with reservation id %s: %v", reservationID, err)
+	}
+
+	instances := make([]*InstanceStatus, 0, len(serverList))
+	for i := range serverList {
+		instances = append(instances, &InstanceStatus{&serverList[i], s.scope.Logger})
+	}
+	return instances, nil
+}
+
+// GetInstanceStatusesByNamePrefix returns the instances whose name starts with prefix.
+func (s *Service) GetInstanceStatusesByNamePrefix(prefix string) ([]*InstanceStatus, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("name prefix must not be empty")
+	}
+
+	// The name parameter to /servers is a regular expression
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), servers.ListOpts{Name: "^" + regexp.QuoteMeta(prefix)})
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers with name prefix %s: %v", prefix, err)
+	}
+
+	instances := make([]*InstanceStatus, 0, len(serverList))
+	for i := range serverList {
+		// Not every cloud supports regular expressions in the name filter
+		if !strings.HasPrefix(serverList[i].Name, prefix) {
+			continue
+		}
+		instances = append(instances, &InstanceStatus{&serverList[i], s.scope.Logger})
+	}
+	return instances, nil
+}
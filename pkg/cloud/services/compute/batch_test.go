@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const reservationID = "r-abcdefgh"
+
+func TestService_CreateInstanceBatch(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+	mockImageClient := mock.NewMockImageClient(mockCtrl)
+	mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+
+	mockImageClient.EXPECT().ListImages(gomock.Any(), images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
+	mockComputeClient.EXPECT().GetFlavorIDFromName(gomock.Any(), flavorName).Return(flavorUUID, nil)
+	mockComputeClient.EXPECT().CreateServers(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts servers.CreateOptsBuilder) (string, error) {
+		optsMap, err := createOpts.ToServerCreateMap()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(optsMap["server"]).To(MatchKeys(IgnoreExtras, Keys{
+			"name":                  Equal(openStackMachineName),
+			"imageRef":              Equal(imageUUID),
+			"flavorRef":             Equal(flavorUUID),
+			"networks":              Equal([]map[string]interface{}{{"uuid": networkUUID}}),
+			"security_groups":       Equal([]map[string]interface{}{{"name": workerSecurityGroupUUID}}),
+			"key_name":              Equal(sshKeyName),
+			"min_count":             Equal(2),
+			"max_count":             Equal(3),
+			"return_reservation_id": BeTrue(),
+		}))
+		g.Expect(optsMap).To(HaveKeyWithValue("os:scheduler_hints", map[string]interface{}{"group": serverGroupUUID}))
+		return reservationID, nil
+	})
+
+	s := Service{
+		scope:              &scope.Scope{Logger: logr.Discard()},
+		_computeClient:     mockComputeClient,
+		_imageClient:       mockImageClient,
+		_networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+	}
+
+	got, err := s.CreateInstanceBatch(&infrav1.OpenStackMachine{}, getDefaultOpenStackCluster(), getDefaultInstanceSpec(), 2, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(reservationID))
+
+	// Ports cannot be shared by the instances of a batch
+	instanceSpec := getDefaultInstanceSpec()
+	instanceSpec.Ports = []infrav1.PortOpts{{}}
+	_, err = s.CreateInstanceBatch(&infrav1.OpenStackMachine{}, getDefaultOpenStackCluster(), instanceSpec, 2, 3)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = s.CreateInstanceBatch(&infrav1.OpenStackMachine{}, getDefaultOpenStackCluster(), getDefaultInstanceSpec(), 3, 2)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestService_GetInstanceStatusesByReservationID(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+	mockComputeClient.EXPECT().ListServers(gomock.Any(), reservationListOpts{ReservationID: reservationID}).Return([]clients.ServerExt{
+		{Server: servers.Server{ID: "server-1", Name: "machine-1"}},
+		{Server: servers.Server{ID: "server-2", Name: "machine-2"}},
+	}, nil)
+
+	s := Service{
+		scope:          &scope.Scope{Logger: logr.Discard()},
+		_computeClient: mockComputeClient,
+	}
+
+	instances, err := s.GetInstanceStatusesByReservationID(reservationID)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instances).To(HaveLen(2))
+	g.Expect(instances[0].ID()).To(Equal("server-1"))
+	g.Expect(instances[1].Name()).To(Equal("machine-2"))
+
+	query, err := reservationListOpts{ReservationID: reservationID}.ToServerListQuery()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(query).To(Equal("?reservation_id=r-abcdefgh"))
+}
@@ -0,0 +1,535 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/schedulerhints"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
+)
+
+// ErrVolumeQoSSpecsMismatch is returned when the volume type of an additional block device
+// is not associated with the QoS specs required by the device.
+var ErrVolumeQoSSpecsMismatch = errors.New("volume QoS specs mismatch")
+
+func additionalVolumeName(instanceName string, device *infrav1.AdditionalBlockDevice) string {
+	if device.RetainedVolumeName != "" {
+		return device.RetainedVolumeName
+	}
+	return fmt.Sprintf("%s-%s", instanceName, device.Name)
+}
+
+// isRetained returns true if the volume is kept when the machine is deleted.
+func isRetained(policy infrav1.VolumeRetentionPolicy) bool {
+	return policy == infrav1.VolumeRetentionPolicyRetain
+}
+
+// getOrCreateAdditionalVolumes returns the volumes of the additional block devices of the
+// instance in the order of the spec, creating those which do not exist yet.
+func (s *Service) getOrCreateAdditionalVolumes(eventObject runtime.Object, instanceSpec *InstanceSpec, rootVolume *volumes.Volume) ([]*volumes.Volume, error) {
+	if len(instanceSpec.AdditionalBlockDevices) == 0 {
+		return nil, nil
+	}
+
+	// Volumes by the name used to reference them in scheduler hints
+	references := map[string]*volumes.Volume{}
+	if rootVolume != nil {
+		references[infrav1.RootVolumeBlockDeviceName] = rootVolume
+	}
+
+	additionalVolumes := make([]*volumes.Volume, 0, len(instanceSpec.AdditionalBlockDevices))
+	for i := range instanceSpec.AdditionalBlockDevices {
+		device := &instanceSpec.AdditionalBlockDevices[i]
+		volume, err := s.getOrCreateAdditionalVolume(eventObject, instanceSpec, "", device, references)
+		if err != nil {
+			return nil, err
+		}
+		references[device.Name] = volume
+		additionalVolumes = append(additionalVolumes, volume)
+	}
+	return additionalVolumes, nil
+}
+
+// getOrCreateAdditionalVolume returns the volume of the additional block device, creating it if it does not exist.
+// serverID is the ID of the server of the instance once it has been created.
+func (s *Service) getOrCreateAdditionalVolume(eventObject runtime.Object, instanceSpec *InstanceSpec, serverID string, device *infrav1.AdditionalBlockDevice, references map[string]*volumes.Volume) (*volumes.Volume, error) {
+	name := additionalVolumeName(instanceSpec.Name, device)
+
+	var volume *volumes.Volume
+	var err error
+	if device.RetainedVolumeName != "" {
+		volume, err = s.getRetainedVolume(name, serverID, device.Size)
+	} else {
+		volume, err = s.getVolumeByName(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if volume != nil {
+		if volume.Size != device.Size {
+			return nil, fmt.Errorf("expected to find volume %s with size %d; found size %d", name, device.Size, volume.Size)
+		}
+		s.scope.Logger.Info("using existing volume", "name", name, "availabilityZone", volume.AvailabilityZone)
+		return volume, nil
+	}
+
+	if err := s.checkVolumeTypeQoSSpecs(device.VolumeType, device.QoSSpecs); err != nil {
+		return nil, err
+	}
+
+	availabilityZone := instanceSpec.FailureDomain
+	if device.AvailabilityZone != "" {
+		availabilityZone = device.AvailabilityZone
+	}
+
+	var createOpts volumes.CreateOptsBuilder = volumes.CreateOpts{
+		Size:             device.Size,
+		Description:      fmt.Sprintf("Volume %s for %s", device.Name, instanceSpec.Name),
+		Name:             name,
+		Multiattach:      false,
+		AvailabilityZone: availabilityZone,
+		VolumeType:       device.VolumeType,
+		Metadata:         volumeMetadata(instanceSpec.Tags, instanceSpec.ClusterName, isRetained(device.RetentionPolicy)),
+	}
+	if hints := device.SchedulerHints; hints != nil {
+		schedulerHints := schedulerhints.SchedulerHints{}
+		if schedulerHints.SameHost, err = volumeReferenceIDs(hints.SameHostAs, references); err != nil {
+			return nil, fmt.Errorf("volume %s: %w", name, err)
+		}
+		if schedulerHints.DifferentHost, err = volumeReferenceIDs(hints.DifferentHostAs, references); err != nil {
+			return nil, fmt.Errorf("volume %s: %w", name, err)
+		}
+		createOpts = schedulerhints.CreateOptsExt{
+			VolumeCreateOptsBuilder: createOpts,
+			SchedulerHints:          schedulerHints,
+		}
+	}
+
+	volume, err = s.getVolumeClient().CreateVolume(s.scope.Context(), createOpts)
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateVolume", "Failed to create volume %s; size=%d err=%v", name, device.Size, err)
+		return nil, err
+	}
+	record.Eventf(eventObject, "SuccessfulCreateVolume", "Created volume %s; id=%s availabilityZone=%s", name, volume.ID, volume.AvailabilityZone)
+	return volume, nil
+}
+
+// getRetainedVolume returns the volume of the given name to use for a retained block device of the server: the one
+// attached to the server, if any, or else an available one of the given size. Volumes of other sizes are left alone.
+func (s *Service) getRetainedVolume(name, serverID string, size int) (*volumes.Volume, error) {
+	volumeList, err := s.getVolumeClient().ListVolumes(s.scope.Context(), volumes.ListOpts{
+		AllTenants: false,
+		Name:       name,
+		TenantID:   s.scope.ProjectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %w", err)
+	}
+
+	var available *volumes.Volume
+	for i := range volumeList {
+		volume := &volumeList[i]
+		if serverID != "" {
+			for _, attachment := range volume.Attachments {
+				if attachment.ServerID == serverID {
+					return volume, nil
+				}
+			}
+		}
+		if available == nil && volume.Status == "available" && volume.Size == size {
+			available = volume
+		}
+	}
+	if available != nil {
+		s.scope.Logger.Info("reusing retained volume", "name", name, "id", available.ID)
+	}
+	return available, nil
+}
+
+// volumeReferenceIDs returns the IDs of the volumes referenced by name in scheduler hints.
+func volumeReferenceIDs(names []string, references map[string]*volumes.Volume) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		volume, ok := references[name]
+		if !ok {
+			return nil, fmt.Errorf("scheduler hints reference unknown volume %q", name)
+		}
+		ids = append(ids, volume.ID)
+	}
+	return ids, nil
+}
+
+// checkVolumeTypeQoSSpecs returns an error if qosSpecs is set and the volume type is not
+// associated with the QoS specs of that name. Cinder applies the QoS specs of the volume
+// type to the volumes of the type, so they cannot be set on the volume itself.
+func (s *Service) checkVolumeTypeQoSSpecs(volumeType, qosSpecs string) error {
+	if qosSpecs == "" {
+		return nil
+	}
+
+	volumeTypes, err := s.getVolumeClient().ListVolumeTypes(s.scope.Context())
+	if err != nil {
+		return fmt.Errorf("error listing volume types: %w", err)
+	}
+	var qosSpecsID string
+	found := false
+	for _, vt := range volumeTypes {
+		if vt.Name == volumeType || vt.ID == volumeType {
+			qosSpecsID = vt.QosSpecID
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("volume type %q not found", volumeType)
+	}
+
+	allQoSSpecs, err := s.getVolumeClient().ListQoSSpecs(s.scope.Context())
+	if err != nil {
+		return fmt.Errorf("error listing QoS specs: %w", err)
+	}
+	for _, q := range allQoSSpecs {
+		if q.Name == qosSpecs || q.ID == qosSpecs {
+			if q.ID == qosSpecsID {
+				return nil
+			}
+			break
+		}
+	}
+	return fmt.Errorf("%w: volume type %q is not associated with QoS specs %q", ErrVolumeQoSSpecsMismatch, volumeType, qosSpecs)
+}
+
+// applyBlockDevices sets the block device mapping of the instance if it boots from a
+// root volume or has additional volumes. If the instance has additional volumes but no
+// root volume it boots from the image on local disk. The additional volumes are attached
+// with the tags of the additional block devices of the spec, in the same order. Volumes
+// are deleted together with the server unless their retention policy is Retain.
+func applyBlockDevices(opts servers.CreateOptsBuilder, imageID string, rootVolume *volumes.Volume, rootVolumeSpec *infrav1.RootVolume, additionalVolumes []*volumes.Volume, devices []infrav1.AdditionalBlockDevice) servers.CreateOptsBuilder {
+	if rootVolume == nil && len(additionalVolumes) == 0 {
+		return opts
+	}
+
+	blocks := make([]bootfromvolume.BlockDevice, 0, len(additionalVolumes)+1)
+	if rootVolume != nil {
+		blocks = append(blocks, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceVolume,
+			BootIndex:           0,
+			UUID:                rootVolume.ID,
+			DeleteOnTermination: rootVolumeSpec == nil || !isRetained(rootVolumeSpec.RetentionPolicy),
+			DestinationType:     bootfromvolume.DestinationVolume,
+		})
+	} else {
+		blocks = append(blocks, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceImage,
+			BootIndex:           0,
+			UUID:                imageID,
+			DeleteOnTermination: true,
+			DestinationType:     bootfromvolume.DestinationLocal,
+		})
+	}
+	for i, volume := range additionalVolumes {
+		blocks = append(blocks, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceVolume,
+			BootIndex:           -1,
+			UUID:                volume.ID,
+			DeleteOnTermination: i >= len(devices) || !isRetained(devices[i].RetentionPolicy),
+			DestinationType:     bootfromvolume.DestinationVolume,
+		})
+	}
+	var createOpts servers.CreateOptsBuilder = bootfromvolume.CreateOptsExt{
+		CreateOptsBuilder: opts,
+		BlockDevice:       blocks,
+	}
+
+	tags := make(map[int]string)
+	for i := range devices {
+		if devices[i].Tag != "" {
+			// The first block device is the root disk
+			tags[i+1] = devices[i].Tag
+		}
+	}
+	if len(tags) > 0 {
+		createOpts = blockDeviceTagsExt{CreateOptsBuilder: createOpts, tags: tags}
+	}
+	return createOpts
+}
+
+// blockDeviceTagsExt adds device tags to the block device mapping of the server, which
+// gophercloud does not support. Tags require Nova microversion 2.42.
+type blockDeviceTagsExt struct {
+	servers.CreateOptsBuilder
+	// tags are the tags of the block devices by their index in the mapping.
+	tags map[int]string
+}
+
+func (opts blockDeviceTagsExt) ToServerCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+
+	blockDevices, _ := base["server"].(map[string]interface{})["block_device_mapping_v2"].([]map[string]interface{})
+	for i, tag := range opts.tags {
+		if i >= len(blockDevices) {
+			return nil, fmt.Errorf("no block device %d to tag with %s", i, tag)
+		}
+		blockDevices[i]["tag"] = tag
+	}
+	return base, nil
+}
+
+// deleteDanglingVolume deletes the volume of the given name, which is left behind if the
+// instance it was created for was not created.
+func (s *Service) deleteDanglingVolume(name string) error {
+	volume, err := s.getVolumeByName(name)
+	if err != nil {
+		return err
+	}
+	if volume == nil {
+		return nil
+	}
+
+	s.scope.Logger.Info("deleting dangling volume", "name", volume.Name, "id", volume.ID)
+	return s.getVolumeClient().DeleteVolume(s.scope.Context(), volume.ID, volumes.DeleteOpts{})
+}
+
+// ReconcileBlockDevices converges the volumes attached to the server of an instance with its additional block
+// devices. The volumes of devices which were added to the spec are created and attached, and the volumes of the
+// attached devices which were removed from the spec are detached and, if deleteRemoved is set, deleted. Volumes
+// which are attached to the server by others are left alone. It returns the devices whose volumes are attached,
+// also if it fails, so that devices which are not detached yet are kept.
+func (s *Service) ReconcileBlockDevices(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus, attached []infrav1.AttachedBlockDevice, deleteRemoved bool) ([]infrav1.AttachedBlockDevice, error) {
+	devices := instanceSpec.AdditionalBlockDevices
+	if len(devices) == 0 && len(attached) == 0 {
+		return nil, nil
+	}
+
+	attachments, err := s.getComputeClient().ListVolumeAttachments(s.scope.Context(), instanceStatus.ID())
+	if err != nil {
+		return attached, fmt.Errorf("error listing volume attachments: %w", err)
+	}
+	attachedVolumes := make(map[string]bool, len(attachments))
+	for _, attachment := range attachments {
+		attachedVolumes[attachment.VolumeID] = true
+	}
+
+	// Volume IDs of the attached devices by the device name
+	volumeIDs := make(map[string]string, len(attached))
+	for _, device := range attached {
+		volumeIDs[device.Name] = device.VolumeID
+	}
+	err = s.attachBlockDevices(eventObject, instanceSpec, instanceStatus, attachedVolumes, volumeIDs)
+	if err == nil {
+		err = s.detachRemovedBlockDevices(eventObject, instanceStatus, devices, attachedVolumes, volumeIDs, deleteRemoved)
+	}
+	return attachedBlockDevices(devices, volumeIDs), err
+}
+
+// attachBlockDevices attaches the volumes of the additional block devices which are not attached to the server,
+// creating them if they do not exist, and records them in volumeIDs.
+func (s *Service) attachBlockDevices(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus, attachedVolumes map[string]bool, volumeIDs map[string]string) error {
+	// Volumes by the name used to reference them in scheduler hints
+	var references map[string]*volumes.Volume
+	for i := range instanceSpec.AdditionalBlockDevices {
+		device := &instanceSpec.AdditionalBlockDevices[i]
+		if volumeID, ok := volumeIDs[device.Name]; ok && attachedVolumes[volumeID] {
+			if references != nil {
+				references[device.Name] = &volumes.Volume{ID: volumeID}
+			}
+			continue
+		}
+
+		if references == nil {
+			var err error
+			if references, err = s.blockDeviceReferences(instanceSpec, volumeIDs); err != nil {
+				return err
+			}
+		}
+		volume, err := s.getOrCreateAdditionalVolume(eventObject, instanceSpec, instanceStatus.ID(), device, references)
+		if err != nil {
+			return fmt.Errorf("error in get or create additional volume %s: %w", device.Name, err)
+		}
+		references[device.Name] = volume
+
+		// The volumes the server was created with are already attached
+		if !attachedVolumes[volume.ID] {
+			if err := s.attachVolume(eventObject, instanceStatus, volume, device); err != nil {
+				return err
+			}
+		}
+		volumeIDs[device.Name] = volume.ID
+	}
+	return nil
+}
+
+// blockDeviceReferences returns the volumes which the scheduler hints of additional block devices can reference,
+// i.e. the root volume and the volumes of the attached devices.
+func (s *Service) blockDeviceReferences(instanceSpec *InstanceSpec, volumeIDs map[string]string) (map[string]*volumes.Volume, error) {
+	references := make(map[string]*volumes.Volume, len(volumeIDs)+1)
+	for name, volumeID := range volumeIDs {
+		references[name] = &volumes.Volume{ID: volumeID}
+	}
+	if hasRootVolume(instanceSpec.RootVolume) {
+		rootVolume, err := s.getVolumeByName(rootVolumeName(instanceSpec.Name))
+		if err != nil {
+			return nil, err
+		}
+		if rootVolume != nil {
+			references[infrav1.RootVolumeBlockDeviceName] = rootVolume
+		}
+	}
+	return references, nil
+}
+
+// attachVolume attaches the volume of the device to the server once it is available. The volume is deleted together
+// with the server unless the device is retained, like the volumes the server was created with.
+func (s *Service) attachVolume(eventObject runtime.Object, instanceStatus *InstanceStatus, volume *volumes.Volume, device *infrav1.AdditionalBlockDevice) error {
+	switch volume.Status {
+	case "available":
+	case "creating":
+		timeout := getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", timeoutInstanceCreate) * time.Minute
+		if err := s.waitForVolume(volume.ID, timeout); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("volume %s with id %s cannot be attached in status %s", volume.Name, volume.ID, volume.Status)
+	}
+
+	_, err := s.getComputeClient().AttachVolume(s.scope.Context(), instanceStatus.ID(), volumeattach.CreateOpts{
+		VolumeID:            volume.ID,
+		Tag:                 device.Tag,
+		DeleteOnTermination: !isRetained(device.RetentionPolicy),
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedAttachVolume", "Failed to attach volume %s with id %s to server %s: %v", volume.Name, volume.ID, instanceStatus.Name(), err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulAttachVolume", "Attached volume %s with id %s to server %s", volume.Name, volume.ID, instanceStatus.Name())
+	return nil
+}
+
+// detachRemovedBlockDevices detaches the volumes of the attached devices which are not additional block devices
+// any more, deleting them if deleteRemoved is set, and removes them from volumeIDs.
+func (s *Service) detachRemovedBlockDevices(eventObject runtime.Object, instanceStatus *InstanceStatus, devices []infrav1.AdditionalBlockDevice, attachedVolumes map[string]bool, volumeIDs map[string]string, deleteRemoved bool) error {
+	declared := make(map[string]bool, len(devices))
+	for i := range devices {
+		declared[devices[i].Name] = true
+	}
+	removed := make([]string, 0, len(volumeIDs))
+	for name := range volumeIDs {
+		if !declared[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		volumeID := volumeIDs[name]
+		if attachedVolumes[volumeID] {
+			if err := s.getComputeClient().DetachVolume(s.scope.Context(), instanceStatus.ID(), volumeID); err != nil {
+				record.Warnf(eventObject, "FailedDetachVolume", "Failed to detach volume %s of block device %s from server %s: %v", volumeID, name, instanceStatus.Name(), err)
+				return err
+			}
+			record.Eventf(eventObject, "SuccessfulDetachVolume", "Detached volume %s of block device %s from server %s", volumeID, name, instanceStatus.Name())
+		}
+		if deleteRemoved {
+			if err := s.deleteDetachedVolume(eventObject, volumeID); err != nil {
+				return err
+			}
+		}
+		delete(volumeIDs, name)
+	}
+	return nil
+}
+
+// deleteDetachedVolume waits for the volume to be detached and deletes it. A volume which does not exist any more
+// is not deleted.
+func (s *Service) deleteDetachedVolume(eventObject runtime.Object, volumeID string) error {
+	var volume *volumes.Volume
+	err := retry.Wait(s.scope.Context(), retry.Volume, retry.Policy{Interval: retryIntervalInstanceStatus, Timeout: timeoutVolumeDetach}, func() (bool, error) {
+		var err error
+		volume, err = s.getVolumeClient().GetVolume(s.scope.Context(), volumeID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				volume = nil
+				return true, nil
+			}
+			if capoerrors.IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return volume.Status != "detaching" && volume.Status != "in-use", nil
+	})
+	if err != nil {
+		return fmt.Errorf("volume %s was not detached: %w", volumeID, err)
+	}
+	if volume == nil {
+		return nil
+	}
+
+	if err := s.getVolumeClient().DeleteVolume(s.scope.Context(), volumeID, volumes.DeleteOpts{}); err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(eventObject, "FailedDeleteVolume", "Failed to delete volume %s with id %s: %v", volume.Name, volumeID, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulDeleteVolume", "Deleted volume %s with id %s", volume.Name, volumeID)
+	return nil
+}
+
+// attachedBlockDevices returns the attached devices in volumeIDs, those in the spec in its order followed by the
+// removed ones by name.
+func attachedBlockDevices(devices []infrav1.AdditionalBlockDevice, volumeIDs map[string]string) []infrav1.AttachedBlockDevice {
+	if len(volumeIDs) == 0 {
+		return nil
+	}
+
+	attached := make([]infrav1.AttachedBlockDevice, 0, len(volumeIDs))
+	declared := make(map[string]bool, len(devices))
+	for i := range devices {
+		declared[devices[i].Name] = true
+		if volumeID, ok := volumeIDs[devices[i].Name]; ok {
+			attached = append(attached, infrav1.AttachedBlockDevice{Name: devices[i].Name, VolumeID: volumeID})
+		}
+	}
+	removed := make([]string, 0, len(volumeIDs))
+	for name := range volumeIDs {
+		if !declared[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		attached = append(attached, infrav1.AttachedBlockDevice{Name: name, VolumeID: volumeIDs[name]})
+	}
+	return attached
+}
@@ -0,0 +1,381 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/schedulerhints"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/qos"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumetypes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_getOrCreateAdditionalVolumes(t *testing.T) {
+	const (
+		rootVolumeID = "11111111-1111-1111-1111-111111111111"
+		etcdVolumeID = "22222222-2222-2222-2222-222222222222"
+		dataVolumeID = "33333333-3333-3333-3333-333333333333"
+		qosSpecsID   = "44444444-4444-4444-4444-444444444444"
+	)
+	etcd := infrav1.AdditionalBlockDevice{
+		Name:           "etcd",
+		Size:           10,
+		VolumeType:     "ssd",
+		QoSSpecs:       "high-iops",
+		SchedulerHints: &infrav1.VolumeSchedulerHints{DifferentHostAs: []string{"root"}},
+	}
+	data := infrav1.AdditionalBlockDevice{
+		Name:             "data",
+		Size:             100,
+		AvailabilityZone: "az2",
+		SchedulerHints:   &infrav1.VolumeSchedulerHints{SameHostAs: []string{"etcd"}},
+	}
+	volumeTypes := []volumetypes.VolumeType{
+		{ID: "ssd-id", Name: "ssd", QosSpecID: qosSpecsID},
+		{ID: "hdd-id", Name: "hdd"},
+	}
+	allQoSSpecs := []qos.QoS{{ID: qosSpecsID, Name: "high-iops"}}
+
+	tests := []struct {
+		name    string
+		devices []infrav1.AdditionalBlockDevice
+		expect  func(g Gomega, m *mock.MockVolumeClientMockRecorder)
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Volumes are created with scheduler hints",
+			devices: []infrav1.AdditionalBlockDevice{etcd, data},
+			expect: func(g Gomega, m *mock.MockVolumeClientMockRecorder) {
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-etcd"}).Return(nil, nil)
+				m.ListVolumeTypes(gomock.Any()).Return(volumeTypes, nil)
+				m.ListQoSSpecs(gomock.Any()).Return(allQoSSpecs, nil)
+				m.CreateVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+					opts := createOpts.(schedulerhints.CreateOptsExt)
+					g.Expect(opts.VolumeCreateOptsBuilder).To(Equal(volumes.CreateOpts{
+						Size:             10,
+						Description:      "Volume etcd for machine",
+						Name:             "machine-etcd",
+						AvailabilityZone: "az1",
+						VolumeType:       "ssd",
+						Metadata:         map[string]string{"capo-delete-on-termination": "true"},
+					}))
+					g.Expect(opts.SchedulerHints).To(Equal(schedulerhints.SchedulerHints{DifferentHost: []string{rootVolumeID}}))
+					return &volumes.Volume{ID: etcdVolumeID}, nil
+				})
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-data"}).Return(nil, nil)
+				m.CreateVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+					opts := createOpts.(schedulerhints.CreateOptsExt)
+					g.Expect(opts.VolumeCreateOptsBuilder.(volumes.CreateOpts).AvailabilityZone).To(Equal("az2"))
+					g.Expect(opts.SchedulerHints).To(Equal(schedulerhints.SchedulerHints{SameHost: []string{etcdVolumeID}}))
+					return &volumes.Volume{ID: dataVolumeID}, nil
+				})
+			},
+			want: []string{etcdVolumeID, dataVolumeID},
+		},
+		{
+			name:    "Existing volume is used",
+			devices: []infrav1.AdditionalBlockDevice{etcd},
+			expect: func(g Gomega, m *mock.MockVolumeClientMockRecorder) {
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-etcd"}).Return([]volumes.Volume{{ID: etcdVolumeID, Size: 10}}, nil)
+			},
+			want: []string{etcdVolumeID},
+		},
+		{
+			name: "Available retained volume is reused",
+			devices: []infrav1.AdditionalBlockDevice{{
+				Name:               "cache",
+				Size:               100,
+				RetentionPolicy:    infrav1.VolumeRetentionPolicyRetain,
+				RetainedVolumeName: "runner-cache",
+			}},
+			expect: func(g Gomega, m *mock.MockVolumeClientMockRecorder) {
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "runner-cache"}).Return([]volumes.Volume{
+					{ID: "in-use-id", Size: 100, Status: "in-use"},
+					{ID: "small-id", Size: 10, Status: "available"},
+					{ID: dataVolumeID, Size: 100, Status: "available"},
+				}, nil)
+			},
+			want: []string{dataVolumeID},
+		},
+		{
+			name: "Retained volume is created with its name if none is available",
+			devices: []infrav1.AdditionalBlockDevice{{
+				Name:               "cache",
+				Size:               100,
+				RetentionPolicy:    infrav1.VolumeRetentionPolicyRetain,
+				RetainedVolumeName: "runner-cache",
+			}},
+			expect: func(g Gomega, m *mock.MockVolumeClientMockRecorder) {
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "runner-cache"}).Return([]volumes.Volume{{ID: "in-use-id", Size: 100, Status: "in-use"}}, nil)
+				m.CreateVolume(gomock.Any(), volumes.CreateOpts{
+					Size:             100,
+					Description:      "Volume cache for machine",
+					Name:             "runner-cache",
+					AvailabilityZone: "az1",
+				}).Return(&volumes.Volume{ID: dataVolumeID}, nil)
+			},
+			want: []string{dataVolumeID},
+		},
+		{
+			name: "Volume type without the QoS specs",
+			devices: []infrav1.AdditionalBlockDevice{{
+				Name:       "etcd",
+				Size:       10,
+				VolumeType: "hdd",
+				QoSSpecs:   "high-iops",
+			}},
+			expect: func(g Gomega, m *mock.MockVolumeClientMockRecorder) {
+				m.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-etcd"}).Return(nil, nil)
+				m.ListVolumeTypes(gomock.Any()).Return(volumeTypes, nil)
+				m.ListQoSSpecs(gomock.Any()).Return(allQoSSpecs, nil)
+			},
+			wantErr: ErrVolumeQoSSpecsMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockVolumeClient := mock.NewMockVolumeClient(mockCtrl)
+			tt.expect(g, mockVolumeClient.EXPECT())
+			s := Service{
+				scope:         &scope.Scope{Logger: logr.Discard()},
+				_volumeClient: mockVolumeClient,
+			}
+
+			instanceSpec := &InstanceSpec{
+				Name:                   "machine",
+				FailureDomain:          "az1",
+				AdditionalBlockDevices: tt.devices,
+			}
+			got, err := s.getOrCreateAdditionalVolumes(nil, instanceSpec, &volumes.Volume{ID: rootVolumeID})
+			if tt.wantErr != nil {
+				g.Expect(errors.Is(err, tt.wantErr)).To(BeTrue(), "unexpected error %v", err)
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			ids := make([]string, 0, len(got))
+			for _, volume := range got {
+				ids = append(ids, volume.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_ReconcileBlockDevices(t *testing.T) {
+	const (
+		dataVolumeID = "33333333-3333-3333-3333-333333333333"
+		logsVolumeID = "55555555-5555-5555-5555-555555555555"
+	)
+	data := infrav1.AdditionalBlockDevice{Name: "data", Size: 100}
+	logs := infrav1.AdditionalBlockDevice{Name: "logs", Size: 10, Tag: "logs"}
+	cache := infrav1.AdditionalBlockDevice{Name: "cache", Size: 100, RetentionPolicy: infrav1.VolumeRetentionPolicyRetain, RetainedVolumeName: "runner-cache"}
+	attachedData := infrav1.AttachedBlockDevice{Name: "data", VolumeID: dataVolumeID}
+	attachedLogs := infrav1.AttachedBlockDevice{Name: "logs", VolumeID: logsVolumeID}
+	bothAttached := []volumeattach.VolumeAttachment{{VolumeID: dataVolumeID}, {VolumeID: logsVolumeID}}
+
+	tests := []struct {
+		name          string
+		devices       []infrav1.AdditionalBlockDevice
+		attached      []infrav1.AttachedBlockDevice
+		deleteRemoved bool
+		expect        func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder)
+		want          []infrav1.AttachedBlockDevice
+		wantErr       bool
+	}{
+		{
+			name:   "Machine without block devices",
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {},
+		},
+		{
+			name:    "Volumes the server was created with are recorded",
+			devices: []infrav1.AdditionalBlockDevice{data},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{{VolumeID: dataVolumeID}}, nil)
+				volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-data"}).Return([]volumes.Volume{{ID: dataVolumeID, Size: 100, Status: "in-use"}}, nil)
+			},
+			want: []infrav1.AttachedBlockDevice{attachedData},
+		},
+		{
+			name:     "Attached volumes are not looked up",
+			devices:  []infrav1.AdditionalBlockDevice{data},
+			attached: []infrav1.AttachedBlockDevice{attachedData},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{{VolumeID: dataVolumeID}, {VolumeID: "pvc-volume"}}, nil)
+			},
+			want: []infrav1.AttachedBlockDevice{attachedData},
+		},
+		{
+			name:     "Volume of an added device is created and attached",
+			devices:  []infrav1.AdditionalBlockDevice{data, logs},
+			attached: []infrav1.AttachedBlockDevice{attachedData},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{{VolumeID: dataVolumeID}}, nil)
+				volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "machine-logs"}).Return(nil, nil)
+				volume.CreateVolume(gomock.Any(), gomock.Any()).Return(&volumes.Volume{ID: logsVolumeID, Name: "machine-logs", Status: "creating"}, nil)
+				volume.GetVolume(gomock.Any(), logsVolumeID).Return(&volumes.Volume{ID: logsVolumeID, Status: "available"}, nil)
+				compute.AttachVolume(gomock.Any(), instanceUUID, volumeattach.CreateOpts{VolumeID: logsVolumeID, Tag: "logs", DeleteOnTermination: true}).Return(&volumeattach.VolumeAttachment{}, nil)
+			},
+			want: []infrav1.AttachedBlockDevice{attachedData, attachedLogs},
+		},
+		{
+			name:    "Retained volume attached to the server is recorded",
+			devices: []infrav1.AdditionalBlockDevice{cache},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{{VolumeID: dataVolumeID}}, nil)
+				volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "runner-cache"}).Return([]volumes.Volume{
+					{ID: logsVolumeID, Size: 100, Status: "available"},
+					{ID: dataVolumeID, Size: 100, Status: "in-use", Attachments: []volumes.Attachment{{ServerID: instanceUUID}}},
+				}, nil)
+			},
+			want: []infrav1.AttachedBlockDevice{{Name: "cache", VolumeID: dataVolumeID}},
+		},
+		{
+			name:    "Available retained volume is attached without deleting it on termination",
+			devices: []infrav1.AdditionalBlockDevice{cache},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return(nil, nil)
+				volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: "runner-cache"}).Return([]volumes.Volume{
+					{ID: logsVolumeID, Size: 100, Status: "in-use", Attachments: []volumes.Attachment{{ServerID: "other-server"}}},
+					{ID: dataVolumeID, Size: 100, Status: "available"},
+				}, nil)
+				compute.AttachVolume(gomock.Any(), instanceUUID, volumeattach.CreateOpts{VolumeID: dataVolumeID, DeleteOnTermination: false}).Return(&volumeattach.VolumeAttachment{}, nil)
+			},
+			want: []infrav1.AttachedBlockDevice{{Name: "cache", VolumeID: dataVolumeID}},
+		},
+		{
+			name:     "Volume of a removed device is detached",
+			devices:  []infrav1.AdditionalBlockDevice{data},
+			attached: []infrav1.AttachedBlockDevice{attachedData, attachedLogs},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return(bothAttached, nil)
+				compute.DetachVolume(gomock.Any(), instanceUUID, logsVolumeID).Return(nil)
+			},
+			want: []infrav1.AttachedBlockDevice{attachedData},
+		},
+		{
+			name:          "Volume of a removed device is detached and deleted",
+			devices:       []infrav1.AdditionalBlockDevice{data},
+			attached:      []infrav1.AttachedBlockDevice{attachedData, attachedLogs},
+			deleteRemoved: true,
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return(bothAttached, nil)
+				compute.DetachVolume(gomock.Any(), instanceUUID, logsVolumeID).Return(nil)
+				volume.GetVolume(gomock.Any(), logsVolumeID).Return(&volumes.Volume{ID: logsVolumeID, Name: "machine-logs", Status: "available"}, nil)
+				volume.DeleteVolume(gomock.Any(), logsVolumeID, volumes.DeleteOpts{}).Return(nil)
+			},
+			want: []infrav1.AttachedBlockDevice{attachedData},
+		},
+		{
+			name:     "Device which failed to detach is kept",
+			devices:  []infrav1.AdditionalBlockDevice{data},
+			attached: []infrav1.AttachedBlockDevice{attachedData, attachedLogs},
+			expect: func(compute *mock.MockComputeClientMockRecorder, volume *mock.MockVolumeClientMockRecorder) {
+				compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return(bothAttached, nil)
+				compute.DetachVolume(gomock.Any(), instanceUUID, logsVolumeID).Return(errors.New("test error"))
+			},
+			want:    []infrav1.AttachedBlockDevice{attachedData, attachedLogs},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockVolumeClient := mock.NewMockVolumeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT(), mockVolumeClient.EXPECT())
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: mockComputeClient,
+				_volumeClient:  mockVolumeClient,
+			}
+
+			instanceSpec := &InstanceSpec{
+				Name:                   "machine",
+				AdditionalBlockDevices: tt.devices,
+			}
+			instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{Server: servers.Server{ID: instanceUUID, Name: "machine"}}, logr.Discard())
+			got, err := s.ReconcileBlockDevices(&infrav1.OpenStackMachine{}, instanceSpec, instanceStatus, tt.attached, tt.deleteRemoved)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_applyBlockDevices(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := servers.CreateOpts{Name: "machine"}
+	g.Expect(applyBlockDevices(opts, imageUUID, nil, nil, nil, nil)).To(Equal(opts))
+
+	dataVolume := &volumes.Volume{ID: "data-id"}
+	got := applyBlockDevices(opts, imageUUID, nil, nil, []*volumes.Volume{dataVolume}, []infrav1.AdditionalBlockDevice{{Name: "data"}})
+	g.Expect(got).To(Equal(bootfromvolume.CreateOptsExt{
+		CreateOptsBuilder: opts,
+		BlockDevice: []bootfromvolume.BlockDevice{
+			{SourceType: bootfromvolume.SourceImage, BootIndex: 0, UUID: imageUUID, DeleteOnTermination: true, DestinationType: bootfromvolume.DestinationLocal},
+			{SourceType: bootfromvolume.SourceVolume, BootIndex: -1, UUID: "data-id", DeleteOnTermination: true, DestinationType: bootfromvolume.DestinationVolume},
+		},
+	}))
+
+	got = applyBlockDevices(opts, imageUUID, &volumes.Volume{ID: "root-id"}, &infrav1.RootVolume{Size: 50}, []*volumes.Volume{dataVolume}, nil)
+	g.Expect(got.(bootfromvolume.CreateOptsExt).BlockDevice[0]).To(Equal(bootfromvolume.BlockDevice{
+		SourceType: bootfromvolume.SourceVolume, BootIndex: 0, UUID: "root-id", DeleteOnTermination: true, DestinationType: bootfromvolume.DestinationVolume,
+	}))
+
+	retain := infrav1.VolumeRetentionPolicyRetain
+	got = applyBlockDevices(opts, imageUUID, &volumes.Volume{ID: "root-id"}, &infrav1.RootVolume{Size: 50, RetentionPolicy: retain},
+		[]*volumes.Volume{dataVolume, {ID: "cache-id"}}, []infrav1.AdditionalBlockDevice{{Name: "data"}, {Name: "cache", RetentionPolicy: retain}})
+	g.Expect(got.(bootfromvolume.CreateOptsExt).BlockDevice).To(Equal([]bootfromvolume.BlockDevice{
+		{SourceType: bootfromvolume.SourceVolume, BootIndex: 0, UUID: "root-id", DeleteOnTermination: false, DestinationType: bootfromvolume.DestinationVolume},
+		{SourceType: bootfromvolume.SourceVolume, BootIndex: -1, UUID: "data-id", DeleteOnTermination: true, DestinationType: bootfromvolume.DestinationVolume},
+		{SourceType: bootfromvolume.SourceVolume, BootIndex: -1, UUID: "cache-id", DeleteOnTermination: false, DestinationType: bootfromvolume.DestinationVolume},
+	}))
+
+	got = applyBlockDevices(opts, imageUUID, nil, nil, []*volumes.Volume{dataVolume}, []infrav1.AdditionalBlockDevice{{Name: "etcd", Tag: "etcd"}})
+	createMap, err := got.ToServerCreateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	blockDevices := createMap["server"].(map[string]interface{})["block_device_mapping_v2"].([]map[string]interface{})
+	g.Expect(blockDevices).To(HaveLen(2))
+	g.Expect(blockDevices[0]).NotTo(HaveKey("tag"))
+	g.Expect(blockDevices[1]).To(HaveKeyWithValue("tag", "etcd"))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+)
+
+// Types of the resources returned by ListInstanceResources, in addition to networking.PortResource.
+const (
+	ServerResource = "server"
+	VolumeResource = "volume"
+)
+
+// ListInstanceResources returns the IDs of the server, ports and root volume of the instance which still exist, by
+// resource type. A retained root volume is expected to remain and is not returned, nor is a root volume which is
+// being deleted. It is used to verify that the deletion of a machine left nothing behind.
+func (s *Service) ListInstanceResources(clusterName, instanceName string, tags []string, rootVolume *infrav1.RootVolume, portOpts []infrav1.PortOpts) (map[string][]string, error) {
+	resources := map[string][]string{}
+
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), servers.ListOpts{Name: fmt.Sprintf("^%s$", instanceName)})
+	if err != nil {
+		return nil, fmt.Errorf("get server list: %v", err)
+	}
+	for _, server := range serverList {
+		resources[ServerResource] = append(resources[ServerResource], server.ID)
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return nil, err
+	}
+	portIDs, err := networkingService.ListInstancePorts(clusterName, instanceName, tags, portOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(portIDs) > 0 {
+		resources[networking.PortResource] = portIDs
+	}
+
+	if hasRootVolume(rootVolume) && !isRetained(rootVolume.RetentionPolicy) {
+		volume, err := s.getVolumeByName(rootVolumeName(instanceName))
+		if err != nil {
+			return nil, err
+		}
+		// The root volume is deleted by Nova with the server, which may not have finished yet
+		if volume != nil && volume.Status != "deleting" {
+			resources[VolumeResource] = append(resources[VolumeResource], volume.ID)
+		}
+	}
+
+	return resources, nil
+}
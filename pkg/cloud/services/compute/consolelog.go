@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+const (
+	// consoleLogLines is the number of lines at the end of the console log of an instance
+	// which are reported when it fails to boot.
+	consoleLogLines = 30
+	// consoleLogMaxLength is the maximum length of the reported console log, which keeps it
+	// within the size of an event message.
+	consoleLogMaxLength = 800
+)
+
+// GetConsoleLogTail returns the end of the console log of the instance, or an empty string
+// if it is not available, e.g. because the instance was never scheduled to a host.
+func (s *Service) GetConsoleLogTail(instanceID string) string {
+	output, err := s.getComputeClient().GetConsoleOutput(s.scope.Context(), instanceID, consoleLogLines)
+	if err != nil {
+		s.scope.Logger.V(4).Info("Console log of instance is not available", "instance-id", instanceID, "error", err.Error())
+		return ""
+	}
+
+	output = strings.TrimRight(output, "\r\n")
+	if len(output) > consoleLogMaxLength {
+		output = output[len(output)-consoleLogMaxLength:]
+		// Drop the partial first line
+		if i := strings.IndexByte(output, '\n'); i >= 0 {
+			output = output[i+1:]
+		}
+	}
+	return output
+}
+
+// ReportConsoleLog reports the end of the console log of an instance which failed to boot
+// with a ServerConsoleLog event, and returns it.
+func (s *Service) ReportConsoleLog(eventObject runtime.Object, instanceID string) string {
+	consoleLog := s.GetConsoleLogTail(instanceID)
+	if consoleLog != "" {
+		record.Warnf(eventObject, "ServerConsoleLog", "Console log of server %s:\n%s", instanceID, consoleLog)
+	}
+	return consoleLog
+}
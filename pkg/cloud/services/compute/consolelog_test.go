@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_GetConsoleLogTail(t *testing.T) {
+	longLine := strings.Repeat("x", 100)
+	var longLog []string
+	for i := 0; i < 30; i++ {
+		longLog = append(longLog, longLine)
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   string
+	}{
+		{
+			name:   "Trailing newlines are trimmed",
+			output: "Booting\nKernel panic - not syncing\r\n\n",
+			want:   "Booting\nKernel panic - not syncing",
+		},
+		{
+			name:   "Long console log is truncated at a line boundary",
+			output: strings.Join(longLog, "\n") + "\n",
+			want:   strings.Join(longLog[:7], "\n"),
+		},
+		{
+			name: "Console log is not available",
+			err:  errors.New("Conflict: instance is not ready"),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			computeClient := mock.NewMockComputeClient(mockCtrl)
+			computeClient.EXPECT().GetConsoleOutput(gomock.Any(), instanceUUID, consoleLogLines).Return(tt.output, tt.err)
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: computeClient,
+			}
+			got := s.GetConsoleLogTail(instanceUUID)
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(len(got)).To(BeNumerically("<=", consoleLogMaxLength))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// hypervisorStateDown is the state of a hypervisor whose compute service is not reporting to Nova.
+const hypervisorStateDown = "down"
+
+// evacuateOpts are the options of an evacuation. Unlike evacuate.EvacuateOpts
+// they do not set onSharedStorage, which Nova rejects since microversion 2.14
+// and detects itself instead.
+type evacuateOpts struct {
+	// Host is the host to evacuate the server to. It is left to the scheduler if empty.
+	Host string `json:"host,omitempty"`
+}
+
+func (opts evacuateOpts) ToEvacuateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "evacuate")
+}
+
+// IsHypervisorDown returns whether the hypervisor of the instance is reported
+// down by Nova. It returns false if the hypervisor of the instance is not
+// known, which is the case unless the server was read with admin rights.
+func (s *Service) IsHypervisorDown(instanceStatus *InstanceStatus) (bool, error) {
+	hostname := instanceStatus.HypervisorHostname()
+	if hostname == "" {
+		return false, nil
+	}
+
+	// The hostname pattern is a substring match, so other hypervisors may be returned as well
+	hypervisorList, err := s.getComputeClient().ListHypervisors(s.scope.Context(), hypervisors.ListOpts{HypervisorHostnamePattern: &hostname})
+	if err != nil {
+		return false, fmt.Errorf("list hypervisors matching %s: %w", hostname, err)
+	}
+	for i := range hypervisorList {
+		if hypervisorList[i].HypervisorHostname == hostname {
+			return hypervisorList[i].State == hypervisorStateDown, nil
+		}
+	}
+	return false, nil
+}
+
+// EvacuateInstance rebuilds the server of an instance on another hypervisor
+// after its hypervisor failed. The ID, ports, IP addresses and attached volumes
+// of the server are kept, as is its root disk if it is on shared storage.
+func (s *Service) EvacuateInstance(eventObject runtime.Object, instanceStatus *InstanceStatus) error {
+	err := s.getComputeClient().EvacuateServer(s.scope.Context(), instanceStatus.ID(), evacuateOpts{})
+	if err != nil {
+		record.Warnf(eventObject, "FailedEvacuateServer", "Failed to evacuate server %s with id %s from hypervisor %s: %v", instanceStatus.Name(), instanceStatus.ID(), instanceStatus.HypervisorHostname(), err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulEvacuateServer", "Evacuated server %s with id %s from hypervisor %s", instanceStatus.Name(), instanceStatus.ID(), instanceStatus.HypervisorHostname())
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_IsHypervisorDown(t *testing.T) {
+	hostname := "compute-1"
+
+	tests := []struct {
+		name     string
+		hostname string
+		expect   func(m *mock.MockComputeClientMockRecorder)
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "Hypervisor is not known without admin rights",
+			hostname: "",
+			expect:   func(m *mock.MockComputeClientMockRecorder) {},
+		},
+		{
+			name:     "Hypervisor is down",
+			hostname: hostname,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListHypervisors(gomock.Any(), hypervisors.ListOpts{HypervisorHostnamePattern: &hostname}).Return([]hypervisors.Hypervisor{
+					{HypervisorHostname: "compute-10", State: "up"},
+					{HypervisorHostname: hostname, State: "down"},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name:     "Hypervisor is up",
+			hostname: hostname,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListHypervisors(gomock.Any(), hypervisors.ListOpts{HypervisorHostnamePattern: &hostname}).Return([]hypervisors.Hypervisor{
+					{HypervisorHostname: "compute-10", State: "down"},
+					{HypervisorHostname: hostname, State: "up"},
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name:     "Hypervisor is not listed",
+			hostname: hostname,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListHypervisors(gomock.Any(), hypervisors.ListOpts{HypervisorHostnamePattern: &hostname}).Return(nil, nil)
+			},
+			want: false,
+		},
+		{
+			name:     "Listing hypervisors is forbidden",
+			hostname: hostname,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListHypervisors(gomock.Any(), hypervisors.ListOpts{HypervisorHostnamePattern: &hostname}).Return(nil, errors.New("Forbidden"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			computeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(computeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: computeClient,
+			}
+			instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{
+				Server:              servers.Server{ID: instanceUUID},
+				ServerAttributesExt: extendedserverattributes.ServerAttributesExt{HypervisorHostname: tt.hostname},
+			}, logr.Discard())
+			got, err := s.IsHypervisorDown(instanceStatus)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_EvacuateInstance(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	computeClient := mock.NewMockComputeClient(mockCtrl)
+	computeClient.EXPECT().EvacuateServer(gomock.Any(), instanceUUID, evacuateOpts{}).Return(nil)
+
+	s := Service{
+		scope:          &scope.Scope{Logger: logr.Discard()},
+		_computeClient: computeClient,
+	}
+	instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{Server: servers.Server{ID: instanceUUID}}, logr.Discard())
+	g.Expect(s.EvacuateInstance(&infrav1.OpenStackMachine{}, instanceStatus)).To(Succeed())
+
+	body, err := evacuateOpts{}.ToEvacuateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(body).To(Equal(map[string]interface{}{"evacuate": map[string]interface{}{}}))
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ListOrphanedVolumes returns the volumes created for the instances of a cluster which are not attached to a server
+// and do not belong to one of instanceNames, e.g. the root volume of a server which was never created. Volumes are
+// listed by the metadata identifying the cluster, see InstanceSpec.ClusterName. Only volumes which would have been
+// deleted with their server are returned, so retained volumes are kept.
+func (s *Service) ListOrphanedVolumes(clusterName string, instanceNames []string) ([]infrav1.OrphanedResource, error) {
+	volumeList, err := s.getVolumeClient().ListVolumes(s.scope.Context(), volumes.ListOpts{Metadata: volumeMetadata(nil, clusterName, false)})
+	if err != nil {
+		return nil, err
+	}
+	var orphans []infrav1.OrphanedResource
+	for _, volume := range volumeList {
+		// The metadata is checked again, in case the filter is ignored by the cloud
+		if volume.Metadata[volumeClusterMetadataKey] != clusterName || volume.Metadata[volumeDeleteOnTerminationMetadataKey] != "true" {
+			continue
+		}
+		if volume.Status != "available" || names.BelongsToInstance(volume.Name, instanceNames) {
+			continue
+		}
+		orphans = append(orphans, infrav1.OrphanedResource{Type: VolumeResource, ID: volume.ID, Name: volume.Name})
+	}
+	return orphans, nil
+}
+
+// DeleteOrphanedVolume deletes a volume returned by ListOrphanedVolumes, unless it has been attached since.
+func (s *Service) DeleteOrphanedVolume(eventObject runtime.Object, volumeID string) error {
+	volume, err := s.getVolumeClient().GetVolume(s.scope.Context(), volumeID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if volume.Status != "available" {
+		return nil
+	}
+
+	if err := s.getVolumeClient().DeleteVolume(s.scope.Context(), volumeID, volumes.DeleteOpts{}); err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(eventObject, "FailedDeleteVolume", "Failed to delete volume %s with id %s: %v", volume.Name, volumeID, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulDeleteVolume", "Deleted volume %s with id %s", volume.Name, volumeID)
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_ListOrphanedVolumes(t *testing.T) {
+	const (
+		clusterName      = "default/cluster-a"
+		orphanID         = "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"
+		otherClusterID   = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		machineVolumeID  = "9e8d7c6b-5a49-4382-9170-6f5e4d3c2b1a"
+		attachedVolumeID = "2b3c4d5e-6f70-4819-a2b3-c4d5e6f70819"
+	)
+	metadata := func(cluster string) map[string]string {
+		return map[string]string{"tag:capi": "true", "capo-cluster": cluster, "capo-delete-on-termination": "true"}
+	}
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockVolumeClient := mock.NewMockVolumeClient(mockCtrl)
+	mockVolumeClient.EXPECT().ListVolumes(gomock.Any(), volumes.ListOpts{Metadata: map[string]string{
+		"capo-cluster":               clusterName,
+		"capo-delete-on-termination": "true",
+	}}).Return([]volumes.Volume{
+		{ID: orphanID, Name: "cluster-a-md-0-old-root", Status: "available", Metadata: metadata(clusterName)},
+		// A volume of another cluster with the same tags, in case the filter is ignored
+		{ID: otherClusterID, Name: "cluster-b-md-0-abcde-root", Status: "available", Metadata: metadata("default/cluster-b")},
+		{ID: machineVolumeID, Name: "cluster-a-md-0-abcde-root", Status: "available", Metadata: metadata(clusterName)},
+		{ID: attachedVolumeID, Name: "cluster-a-md-0-fghij-root", Status: "in-use", Metadata: metadata(clusterName)},
+	}, nil)
+
+	s := Service{
+		scope:         &scope.Scope{Logger: logr.Discard()},
+		_volumeClient: mockVolumeClient,
+	}
+	orphans, err := s.ListOrphanedVolumes(clusterName, []string{"cluster-a-md-0-abcde", "cluster-a-md-0-fghij"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(orphans).To(Equal([]infrav1.OrphanedResource{{Type: VolumeResource, ID: orphanID, Name: "cluster-a-md-0-old-root"}}))
+}
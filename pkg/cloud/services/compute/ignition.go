@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+const (
+	// maxUserDataSize is the maximum size of the base64 encoded user data of a server.
+	maxUserDataSize = 65535
+
+	// ignitionConfigTTL is how long Ignition configs stored in swift can be fetched.
+	ignitionConfigTTL = time.Hour
+)
+
+// getUserData returns the base64 encoded user data of the instance. Ignition configs
+// exceeding the user data limit of Nova are stored in swift and the instance gets a
+// config which replaces itself with the stored one.
+func (s *Service) getUserData(eventObject runtime.Object, instanceSpec *InstanceSpec) (string, error) {
+	if instanceSpec.BootstrapFormat != infrav1.BootstrapFormatIgnition || len(instanceSpec.UserData) <= maxUserDataSize {
+		return instanceSpec.UserData, nil
+	}
+
+	container := instanceSpec.IgnitionSwiftContainer
+	if container == "" {
+		return "", fmt.Errorf("ignition config of %d bytes exceeds the user data limit of %d bytes, set ignition.swiftContainer to store it in swift", len(instanceSpec.UserData), maxUserDataSize)
+	}
+
+	config, err := base64.StdEncoding.DecodeString(instanceSpec.UserData)
+	if err != nil {
+		return "", fmt.Errorf("error decoding ignition config: %v", err)
+	}
+	var header struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(config, &header); err != nil {
+		return "", fmt.Errorf("error parsing ignition config: %v", err)
+	}
+	if header.Ignition.Version == "" {
+		return "", fmt.Errorf("ignition config has no version")
+	}
+
+	objectStorageClient, err := s.getObjectStorageClient()
+	if err != nil {
+		return "", err
+	}
+
+	objectName := instanceSpec.Name + ".ign"
+	err = objectStorageClient.CreateObject(s.scope.Context(), container, objectName, objects.CreateOpts{
+		Content:     bytes.NewReader(config),
+		ContentType: "application/json",
+		DeleteAfter: int64(ignitionConfigTTL.Seconds()),
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateObject", "Failed to store ignition config %s/%s: %v", container, objectName, err)
+		return "", err
+	}
+	record.Eventf(eventObject, "SuccessfulCreateObject", "Stored ignition config %s/%s", container, objectName)
+
+	url, err := objectStorageClient.CreateTempURL(s.scope.Context(), container, objectName, objects.CreateTempURLOpts{
+		Method: objects.GET,
+		TTL:    int(ignitionConfigTTL.Seconds()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating temp URL for ignition config %s/%s: %v", container, objectName, err)
+	}
+
+	// The replace directive has the same structure in Ignition v2 and v3
+	pointer, err := json.Marshal(map[string]interface{}{
+		"ignition": map[string]interface{}{
+			"version": header.Ignition.Version,
+			"config": map[string]interface{}{
+				"replace": map[string]interface{}{
+					"source": url,
+					"verification": map[string]interface{}{
+						"hash": fmt.Sprintf("sha512-%x", sha512.Sum512(config)),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(pointer), nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_getUserData(t *testing.T) {
+	const (
+		container = "ignition"
+		tempURL   = "https://swift.example.com/v1/AUTH_project/ignition/test-machine.ign?temp_url_sig=abc&temp_url_expires=1"
+	)
+
+	smallConfig := `{"ignition":{"version":"3.3.0"}}`
+	largeConfig := fmt.Sprintf(`{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/large","contents":{"source":"data:,%s"}}]}}`, strings.Repeat("a", maxUserDataSize))
+	encode := func(config string) string {
+		return base64.StdEncoding.EncodeToString([]byte(config))
+	}
+
+	tests := []struct {
+		name         string
+		instanceSpec InstanceSpec
+		expect       func(m *mock.MockObjectStorageClientMockRecorder)
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "cloud-config is passed through",
+			instanceSpec: InstanceSpec{
+				Name:     openStackMachineName,
+				UserData: encode("#cloud-config\n" + strings.Repeat("a", maxUserDataSize)),
+			},
+			expect: func(m *mock.MockObjectStorageClientMockRecorder) {},
+			want:   encode("#cloud-config\n" + strings.Repeat("a", maxUserDataSize)),
+		},
+		{
+			name: "small ignition config is passed through",
+			instanceSpec: InstanceSpec{
+				Name:                   openStackMachineName,
+				UserData:               encode(smallConfig),
+				BootstrapFormat:        infrav1.BootstrapFormatIgnition,
+				IgnitionSwiftContainer: container,
+			},
+			expect: func(m *mock.MockObjectStorageClientMockRecorder) {},
+			want:   encode(smallConfig),
+		},
+		{
+			name: "large ignition config without container",
+			instanceSpec: InstanceSpec{
+				Name:            openStackMachineName,
+				UserData:        encode(largeConfig),
+				BootstrapFormat: infrav1.BootstrapFormatIgnition,
+			},
+			expect:  func(m *mock.MockObjectStorageClientMockRecorder) {},
+			wantErr: true,
+		},
+		{
+			name: "large ignition config is stored in swift",
+			instanceSpec: InstanceSpec{
+				Name:                   openStackMachineName,
+				UserData:               encode(largeConfig),
+				BootstrapFormat:        infrav1.BootstrapFormatIgnition,
+				IgnitionSwiftContainer: container,
+			},
+			expect: func(m *mock.MockObjectStorageClientMockRecorder) {
+				m.CreateObject(gomock.Any(), container, openStackMachineName+".ign", gomock.Any()).Return(nil)
+				m.CreateTempURL(gomock.Any(), container, openStackMachineName+".ign", objects.CreateTempURLOpts{Method: objects.GET, TTL: 3600}).Return(tempURL, nil)
+			},
+			want: encode(fmt.Sprintf(`{"ignition":{"config":{"replace":{"source":%q,"verification":{"hash":"sha512-%x"}}},"version":"3.3.0"}}`, tempURL, sha512.Sum512([]byte(largeConfig)))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockObjectStorageClient := mock.NewMockObjectStorageClient(mockCtrl)
+			tt.expect(mockObjectStorageClient.EXPECT())
+
+			s := Service{
+				scope:                &scope.Scope{Logger: logr.Discard()},
+				_objectStorageClient: mockObjectStorageClient,
+			}
+
+			got, err := s.getUserData(&infrav1.OpenStackMachine{}, &tt.instanceSpec)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			gotDecoded, err := base64.StdEncoding.DecodeString(got)
+			g.Expect(err).NotTo(HaveOccurred())
+			wantDecoded, _ := base64.StdEncoding.DecodeString(tt.want)
+			if json.Valid(wantDecoded) {
+				g.Expect(gotDecoded).To(MatchJSON(wantDecoded))
+			} else {
+				g.Expect(gotDecoded).To(Equal(wantDecoded))
+			}
+		})
+	}
+}
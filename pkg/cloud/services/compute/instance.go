@@ -17,34 +17,71 @@ limitations under the License.
 package compute
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/cluster-api/util"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/batch"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/hash"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
+	openstackutil "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/openstack"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
 )
 
 const (
 	retryIntervalInstanceStatus = 10 * time.Second
+	retryIntervalImageRequest   = 5 * time.Second
 	timeoutInstanceCreate       = 5
 	timeoutInstanceDelete       = 5 * time.Minute
+	timeoutVolumeDetach         = 5 * time.Minute
 )
 
+// ErrImageChecksumMismatch is returned when the image of an instance does not have the
+// checksum it is pinned to.
+var ErrImageChecksumMismatch = errors.New("image checksum mismatch")
+
+// ErrVolumeAvailabilityZoneMismatch is returned when the root volume of an instance is not in the
+// availability zone of the instance and cross-AZ attachment is not allowed.
+var ErrVolumeAvailabilityZoneMismatch = errors.New("volume availability zone mismatch")
+
+// PortError is returned when the ports of an instance could not be created, or the networks and security groups
+// of its ports could not be looked up.
+type PortError struct {
+	Err error
+}
+
+func (e *PortError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PortError) Unwrap() error {
+	return e.Err
+}
+
 // constructNetworks builds an array of networks from the network, subnet and ports items in the instance spec.
 // If no networks or ports are in the spec, returns a single network item for a network connection to the default cluster network.
 func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec) ([]infrav1.Network, error) {
@@ -89,26 +126,32 @@ func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster,
 				PortOpts: port,
 			})
 		} else {
+			subnet, err := clusterSubnet(openStackCluster, instanceSpec.ManagedSubnet)
+			if err != nil {
+				return nil, err
+			}
 			nets = append(nets, infrav1.Network{
-				ID: openStackCluster.Status.Network.ID,
-				Subnet: &infrav1.Subnet{
-					ID: openStackCluster.Status.Network.Subnet.ID,
-				},
-				PortOpts: port,
+				ID:         openStackCluster.Status.Network.ID,
+				Subnet:     subnet,
+				IPv6Subnet: clusterIPv6Subnet(openStackCluster),
+				PortOpts:   clusterNetworkPortOpts(openStackCluster, port, instanceSpec.APIServerVIP),
 			})
 		}
 	}
 
 	// no networks or ports found in the spec, so create a port on the cluster network
 	if len(nets) == 0 {
+		subnet, err := clusterSubnet(openStackCluster, instanceSpec.ManagedSubnet)
+		if err != nil {
+			return nil, err
+		}
 		nets = []infrav1.Network{{
-			ID: openStackCluster.Status.Network.ID,
-			Subnet: &infrav1.Subnet{
-				ID: openStackCluster.Status.Network.Subnet.ID,
-			},
-			PortOpts: &infrav1.PortOpts{
+			ID:         openStackCluster.Status.Network.ID,
+			Subnet:     subnet,
+			IPv6Subnet: clusterIPv6Subnet(openStackCluster),
+			PortOpts: clusterNetworkPortOpts(openStackCluster, &infrav1.PortOpts{
 				Trunk: &instanceSpec.Trunk,
-			},
+			}, instanceSpec.APIServerVIP),
 		}}
 		trunkRequired = instanceSpec.Trunk
 	}
@@ -126,6 +169,90 @@ func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster,
 	return nets, nil
 }
 
+// clusterSubnet returns the IPv4 subnet of the cluster network the ports of an instance get their address
+// from, which is the managed subnet selected by the instance or else the subnet with the NodeCIDR of the cluster.
+func clusterSubnet(openStackCluster *infrav1.OpenStackCluster, selector *infrav1.ManagedSubnetSelector) (*infrav1.Subnet, error) {
+	if selector == nil {
+		return &infrav1.Subnet{
+			ID: openStackCluster.Status.Network.Subnet.ID,
+		}, nil
+	}
+
+	selected, err := selectManagedSubnet(openStackCluster, selector)
+	if err != nil {
+		return nil, err
+	}
+	return &infrav1.Subnet{
+		ID: selected.Subnet.ID,
+	}, nil
+}
+
+// selectManagedSubnet returns the managed subnet of the cluster network matching the selector.
+func selectManagedSubnet(openStackCluster *infrav1.OpenStackCluster, selector *infrav1.ManagedSubnetSelector) (*infrav1.ManagedSubnetStatus, error) {
+	var selected *infrav1.ManagedSubnetStatus
+	for i := range openStackCluster.Status.Network.ManagedSubnets {
+		managedSubnet := &openStackCluster.Status.Network.ManagedSubnets[i]
+		if selector.Name != "" && managedSubnet.Name != selector.Name {
+			continue
+		}
+		if selector.Role != "" && managedSubnet.Role != selector.Role {
+			continue
+		}
+		if selected != nil {
+			return nil, fmt.Errorf("multiple managed subnets of the cluster network have role %s", selector.Role)
+		}
+		selected = managedSubnet
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("no managed subnet of the cluster network matches name %q and role %q", selector.Name, selector.Role)
+	}
+	return selected, nil
+}
+
+// instanceConfigDrive returns whether the instance is created with a config drive. Instances on a managed subnet
+// without DHCP always are, because their operating system configures the fixed IPs of the ports from the network
+// data of the config drive.
+func instanceConfigDrive(openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec) bool {
+	if instanceSpec.ConfigDrive || instanceSpec.ManagedSubnet == nil {
+		return instanceSpec.ConfigDrive
+	}
+	managedSubnet, err := selectManagedSubnet(openStackCluster, instanceSpec.ManagedSubnet)
+	return err == nil && managedSubnet.DHCPDisabled
+}
+
+// clusterIPv6Subnet returns the IPv6 subnet of a dual-stack cluster network, or nil.
+func clusterIPv6Subnet(openStackCluster *infrav1.OpenStackCluster) *infrav1.Subnet {
+	if openStackCluster.Status.Network.IPv6Subnet == nil {
+		return nil
+	}
+	return &infrav1.Subnet{
+		ID: openStackCluster.Status.Network.IPv6Subnet.ID,
+	}
+}
+
+// clusterNetworkPortOpts returns the options of a port on the cluster network. Unless the port
+// overrides it, the port security of a cluster network created by CAPO is inherited, so that no
+// security groups are requested for ports without port security. If the instance may hold the
+// managed VIP of the API server, the VIP is added to the allowed address pairs of a port with
+// port security.
+func clusterNetworkPortOpts(openStackCluster *infrav1.OpenStackCluster, port *infrav1.PortOpts, apiServerVIP string) *infrav1.PortOpts {
+	if openStackCluster.Spec.DisablePortSecurity && openStackCluster.Spec.NodeCIDR != "" && port.DisablePortSecurity == nil {
+		port = port.DeepCopy()
+		port.DisablePortSecurity = pointer.Bool(true)
+	}
+	if apiServerVIP == "" || (port.DisablePortSecurity != nil && *port.DisablePortSecurity) {
+		return port
+	}
+	for _, pair := range port.AllowedAddressPairs {
+		if pair.IPAddress == apiServerVIP {
+			return port
+		}
+	}
+	port = port.DeepCopy()
+	port.AllowedAddressPairs = append(port.AllowedAddressPairs, infrav1.AddressPair{IPAddress: apiServerVIP})
+	return port
+}
+
 func (s *Service) CreateInstance(eventObject runtime.Object, openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec, clusterName string) (*InstanceStatus, error) {
 	return s.createInstanceImpl(eventObject, openStackCluster, instanceSpec, clusterName, retryIntervalInstanceStatus)
 }
@@ -134,19 +261,37 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 	var server *clients.ServerExt
 	accessIPv4 := ""
 	portList := []servers.Network{}
+	var instancePorts []*ports.Port
 
 	if instanceSpec.Subnet != "" && accessIPv4 == "" {
 		return nil, fmt.Errorf("no ports with fixed IPs found on Subnet %q", instanceSpec.Subnet)
 	}
 
-	imageID, err := s.getImageID(instanceSpec.ImageUUID, instanceSpec.Image)
+	imageID, err := s.getInstanceImageID(instanceSpec)
 	if err != nil {
-		return nil, fmt.Errorf("error getting image ID: %v", err)
+		return nil, fmt.Errorf("error getting image ID: %w", err)
 	}
 
-	flavorID, err := s.getComputeClient().GetFlavorIDFromName(instanceSpec.Flavor)
+	reservation, err := s.getReservation(instanceSpec.Reservation)
 	if err != nil {
-		return nil, fmt.Errorf("error getting flavor id from flavor name %s: %v", instanceSpec.Flavor, err)
+		return nil, err
+	}
+
+	flavorID := reservationFlavorID(reservation)
+	if flavorID == "" {
+		flavorID, err = s.getFlavorID(instanceSpec.FlavorID, instanceSpec.Flavor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkTraits(instanceSpec.Traits); err != nil {
+		return nil, err
+	}
+
+	userData, err := s.getUserData(eventObject, instanceSpec)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user data: %v", err)
 	}
 
 	// Ensure we delete the ports we created if we haven't created the server.
@@ -155,14 +300,14 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 			return
 		}
 
-		if err := s.deletePorts(eventObject, portList); err != nil {
+		if err := s.deletePorts(eventObject, portList, declaredSubports(instanceSpec.Ports)); err != nil {
 			s.scope.Logger.V(4).Error(err, "Failed to clean up ports after failure")
 		}
 	}()
 
 	nets, err := s.constructNetworks(openStackCluster, instanceSpec)
 	if err != nil {
-		return nil, err
+		return nil, &PortError{Err: err}
 	}
 
 	networkingService, err := s.getNetworkingService()
@@ -170,14 +315,19 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		return nil, err
 	}
 
+	portsDone := s.reconcileTimer(metrics.ReconcileResourcePorts)
+	defer portsDone()
+
 	securityGroups, err := networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
 	if err != nil {
-		return nil, fmt.Errorf("error getting security groups: %v", err)
+		return nil, &PortError{Err: fmt.Errorf("error getting security groups: %w", err)}
 	}
 
-	for i, network := range nets {
+	// The ports are created in the order of the interfaces of the server, but named by their index in the spec
+	for _, i := range interfaceOrder(nets) {
+		network := nets[i]
 		if network.ID == "" {
-			return nil, fmt.Errorf("no network was found or provided. Please check your machine configuration and try again")
+			return nil, &PortError{Err: fmt.Errorf("no network was found or provided. Please check your machine configuration and try again")}
 		}
 		iTags := []string{}
 		if len(instanceSpec.Tags) > 0 {
@@ -186,7 +336,7 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		portName := getPortName(instanceSpec.Name, network.PortOpts, i)
 		port, err := networkingService.GetOrCreatePort(eventObject, clusterName, portName, network, &securityGroups, iTags)
 		if err != nil {
-			return nil, err
+			return nil, &PortError{Err: err}
 		}
 
 		for _, fip := range port.FixedIPs {
@@ -198,6 +348,19 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		portList = append(portList, servers.Network{
 			Port: port.ID,
 		})
+		instancePorts = append(instancePorts, port)
+	}
+	portsDone()
+
+	userData, err = s.addSecondaryInterfacesConfig(instanceSpec, userData, instancePorts)
+	if err != nil {
+		return nil, fmt.Errorf("error adding network config to user data: %w", err)
+	}
+
+	volumesDone := func() {}
+	if hasRootVolume(instanceSpec.RootVolume) || len(instanceSpec.AdditionalBlockDevices) > 0 {
+		volumesDone = s.reconcileTimer(metrics.ReconcileResourceVolumes)
+		defer volumesDone()
 	}
 
 	volume, err := s.getOrCreateRootVolume(eventObject, instanceSpec, imageID)
@@ -205,33 +368,26 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		return nil, fmt.Errorf("error in get or create root volume: %w", err)
 	}
 
+	additionalVolumes, err := s.getOrCreateAdditionalVolumes(eventObject, instanceSpec, volume)
+	if err != nil {
+		return nil, fmt.Errorf("error in get or create additional volumes: %w", err)
+	}
+
 	instanceCreateTimeout := getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", timeoutInstanceCreate)
 	instanceCreateTimeout *= time.Minute
 
-	// Wait for volume to become available
+	// Wait for volumes to become available
 	if volume != nil {
-		err = util.PollImmediate(retryIntervalInstanceStatus, instanceCreateTimeout, func() (bool, error) {
-			createdVolume, err := s.getVolumeClient().GetVolume(volume.ID)
-			if err != nil {
-				if capoerrors.IsRetryable(err) {
-					return false, nil
-				}
-				return false, err
-			}
-
-			switch createdVolume.Status {
-			case "available":
-				return true, nil
-			case "error":
-				return false, fmt.Errorf("volume %s is in error state", volume.ID)
-			default:
-				return false, nil
-			}
-		})
-		if err != nil {
-			return nil, fmt.Errorf("volume %s did not become available: %w", volume.ID, err)
+		if err := s.waitForVolume(volume.ID, instanceCreateTimeout); err != nil {
+			return nil, err
+		}
+	}
+	for _, additionalVolume := range additionalVolumes {
+		if err := s.waitForVolume(additionalVolume.ID, instanceCreateTimeout); err != nil {
+			return nil, err
 		}
 	}
+	volumesDone()
 
 	// Don't set ImageRef on the server if we're booting from volume
 	var serverImageRef string
@@ -245,27 +401,33 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		FlavorRef:        flavorID,
 		AvailabilityZone: instanceSpec.FailureDomain,
 		Networks:         portList,
-		UserData:         []byte(instanceSpec.UserData),
+		UserData:         []byte(userData),
 		Tags:             instanceSpec.Tags,
 		Metadata:         instanceSpec.Metadata,
-		ConfigDrive:      &instanceSpec.ConfigDrive,
+		ConfigDrive:      pointer.Bool(instanceConfigDrive(openStackCluster, instanceSpec)),
 		AccessIPv4:       accessIPv4,
 	}
 
-	serverCreateOpts = applyRootVolume(serverCreateOpts, volume)
+	serverCreateOpts = applyBlockDevices(serverCreateOpts, imageID, volume, instanceSpec.RootVolume, additionalVolumes, instanceSpec.AdditionalBlockDevices)
+
+	serverCreateOpts = applyHostname(serverCreateOpts, instanceSpec.Hostname)
 
-	serverCreateOpts = applyServerGroupID(serverCreateOpts, instanceSpec.ServerGroupID)
+	serverCreateOpts = applySchedulerHints(serverCreateOpts, instanceSpec.ServerGroupID, reservationSchedulerHints(reservation, instanceSpec.SchedulerHints))
 
-	server, err = s.getComputeClient().CreateServer(keypairs.CreateOptsExt{
+	server, err = s.getComputeClient().CreateServer(s.scope.Context(), keypairs.CreateOptsExt{
 		CreateOptsBuilder: serverCreateOpts,
 		KeyName:           instanceSpec.SSHKeyName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating Openstack instance: %v", err)
+		// Nova rejects an image which was not found, which may have been resolved from the lookup cache
+		if capoerrors.IsNotFound(err) || capoerrors.IsInvalidError(err) {
+			lookupcache.Invalidate(lookupcache.Image)
+		}
+		return nil, fmt.Errorf("error creating Openstack instance: %w", err)
 	}
 
 	var createdInstance *InstanceStatus
-	err = util.PollImmediate(retryInterval, instanceCreateTimeout, func() (bool, error) {
+	err = retry.Wait(s.scope.Context(), retry.Compute, retry.Policy{Interval: retryInterval, Timeout: instanceCreateTimeout}, func() (bool, error) {
 		createdInstance, err = s.GetInstanceStatus(server.ID)
 		if err != nil {
 			if capoerrors.IsRetryable(err) {
@@ -280,6 +442,9 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 	})
 	if err != nil {
 		record.Warnf(eventObject, "FailedCreateServer", "Failed to create server %s: %v", createdInstance.Name(), err)
+		if consoleLog := s.ReportConsoleLog(eventObject, server.ID); consoleLog != "" {
+			return nil, fmt.Errorf("%w, console log:\n%s", err, consoleLog)
+		}
 		return nil, err
 	}
 
@@ -288,6 +453,58 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 }
 
 // getPortName appends a suffix to an instance name in order to try and get a unique name per port.
+// ReconcilePortAllowedAddressPairs updates the allowed address pairs of the ports of the instance, which are the
+// only port options which can be changed once the instance has been created.
+func (s *Service) ReconcilePortAllowedAddressPairs(eventObject runtime.Object, openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus) error {
+	if len(instanceSpec.Ports) == 0 && instanceSpec.APIServerVIP == "" {
+		return nil
+	}
+
+	// constructNetworks defaults the trunk of the ports, which must not modify the spec of the caller
+	spec := *instanceSpec
+	spec.Ports = make([]infrav1.PortOpts, len(instanceSpec.Ports))
+	for i := range instanceSpec.Ports {
+		instanceSpec.Ports[i].DeepCopyInto(&spec.Ports[i])
+	}
+
+	nets, err := s.constructNetworks(openStackCluster, &spec)
+	if err != nil {
+		return err
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return err
+	}
+
+	for i, network := range nets {
+		portName := getPortName(spec.Name, network.PortOpts, i)
+		if err := networkingService.ReconcilePortAllowedAddressPairs(eventObject, instanceStatus.ID(), portName, network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interfaceOrder returns the indexes of the networks in the order of the network interfaces of the server, which is
+// the order of their ports and otherwise the order of the networks.
+func interfaceOrder(nets []infrav1.Network) []int {
+	order := make([]int, len(nets))
+	for i := range order {
+		order[i] = i
+	}
+	portOrder := func(network *infrav1.Network) int32 {
+		if network.PortOpts == nil {
+			return 0
+		}
+		return network.PortOpts.Order
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return portOrder(&nets[order[i]]) < portOrder(&nets[order[j]])
+	})
+	return order
+}
+
 func getPortName(instanceName string, opts *infrav1.PortOpts, netIndex int) string {
 	if opts != nil && opts.NameSuffix != "" {
 		return fmt.Sprintf("%s-%s", instanceName, opts.NameSuffix)
@@ -309,7 +526,7 @@ func (s *Service) getVolumeByName(name string) (*volumes.Volume, error) {
 		Name:       name,
 		TenantID:   s.scope.ProjectID,
 	}
-	volumeList, err := s.getVolumeClient().ListVolumes(listOpts)
+	volumeList, err := s.getVolumeClient().ListVolumes(s.scope.Context(), listOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error listing volumes: %w", err)
 	}
@@ -339,8 +556,11 @@ func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec
 		if volume.Size != size {
 			return nil, fmt.Errorf("exected to find volume %s with size %d; found size %d", name, size, volume.Size)
 		}
+		if err := checkVolumeAvailabilityZone(rootVolume, name, volume.AvailabilityZone, instanceSpec.FailureDomain); err != nil {
+			return nil, err
+		}
 
-		s.scope.Logger.Info("using existing root volume %s", name)
+		s.scope.Logger.Info("using existing root volume", "name", name, "availabilityZone", volume.AvailabilityZone)
 		return volume, nil
 	}
 
@@ -348,6 +568,9 @@ func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec
 	if rootVolume.AvailabilityZone != "" {
 		availabilityZone = rootVolume.AvailabilityZone
 	}
+	if err := checkVolumeAvailabilityZone(rootVolume, name, availabilityZone, instanceSpec.FailureDomain); err != nil {
+		return nil, err
+	}
 
 	createOpts := volumes.CreateOpts{
 		Size:             rootVolume.Size,
@@ -357,47 +580,121 @@ func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec
 		Multiattach:      false,
 		AvailabilityZone: availabilityZone,
 		VolumeType:       rootVolume.VolumeType,
+		Metadata:         volumeMetadata(instanceSpec.Tags, instanceSpec.ClusterName, isRetained(rootVolume.RetentionPolicy)),
 	}
-	volume, err = s.getVolumeClient().CreateVolume(createOpts)
+	volume, err = s.getVolumeClient().CreateVolume(s.scope.Context(), createOpts)
 	if err != nil {
 		record.Eventf(eventObject, "FailedCreateVolume", "Failed to create root volume; size=%d imageID=%s err=%v", size, imageID, err)
 		return nil, err
 	}
-	record.Eventf(eventObject, "SuccessfulCreateVolume", "Created root volume; id=%s", volume.ID)
+	record.Eventf(eventObject, "SuccessfulCreateVolume", "Created root volume; id=%s availabilityZone=%s", volume.ID, volume.AvailabilityZone)
 	return volume, err
 }
 
-// applyRootVolume sets a root volume if the root volume Size is not 0.
-func applyRootVolume(opts servers.CreateOptsBuilder, volume *volumes.Volume) servers.CreateOptsBuilder {
-	if volume == nil {
+// checkVolumeAvailabilityZone returns an error if the root volume in volumeAZ cannot be attached
+// to an instance in instanceAZ. If either is not set, the placement is left to OpenStack.
+func checkVolumeAvailabilityZone(rootVolume *infrav1.RootVolume, name, volumeAZ, instanceAZ string) error {
+	if rootVolume.CrossAZAttach || volumeAZ == "" || instanceAZ == "" || volumeAZ == instanceAZ {
+		return nil
+	}
+	return fmt.Errorf("%w: volume %s is in availability zone %s, but the instance is in %s; set crossAZAttach if Nova allows cross_az_attach",
+		ErrVolumeAvailabilityZoneMismatch, name, volumeAZ, instanceAZ)
+}
+
+// waitForVolume waits for a volume created for an instance to become available.
+func (s *Service) waitForVolume(volumeID string, timeout time.Duration) error {
+	volumePolicy := retry.Policy{Interval: retryIntervalInstanceStatus, Timeout: timeout}
+	err := retry.Wait(s.scope.Context(), retry.Volume, volumePolicy, func() (bool, error) {
+		createdVolume, err := s.getVolumeClient().GetVolume(s.scope.Context(), volumeID)
+		if err != nil {
+			if capoerrors.IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		switch createdVolume.Status {
+		case "available":
+			return true, nil
+		case "error":
+			return false, fmt.Errorf("volume %s is in error state", volumeID)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("volume %s did not become available: %w", volumeID, err)
+	}
+	return nil
+}
+
+// applyHostname sets the hostname of the server, if the spec contains one.
+func applyHostname(opts servers.CreateOptsBuilder, hostname string) servers.CreateOptsBuilder {
+	if hostname == "" {
+		return opts
+	}
+	return hostnameExt{CreateOptsBuilder: opts, hostname: hostname}
+}
+
+// hostnameExt sets the hostname of the server, which Nova exposes to the server through the metadata service and
+// the config drive instead of the name of the server. gophercloud does not support it, and it requires Nova
+// microversion 2.90.
+type hostnameExt struct {
+	servers.CreateOptsBuilder
+	hostname string
+}
+
+func (opts hostnameExt) ToServerCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+
+	base["server"].(map[string]interface{})["hostname"] = opts.hostname
+	return base, nil
+}
+
+// applySchedulerHints adds scheduler hints to the CreateOptsBuilder, if the
+// spec contains a server group ID or additional scheduler hints.
+func applySchedulerHints(opts servers.CreateOptsBuilder, serverGroupID string, hints []infrav1.SchedulerHintAdditionalProperty) servers.CreateOptsBuilder {
+	if serverGroupID == "" && len(hints) == 0 {
 		return opts
 	}
 
-	block := bootfromvolume.BlockDevice{
-		SourceType:          bootfromvolume.SourceVolume,
-		BootIndex:           0,
-		UUID:                volume.ID,
-		DeleteOnTermination: true,
-		DestinationType:     bootfromvolume.DestinationVolume,
+	schedulerHints := schedulerhints.SchedulerHints{
+		Group: serverGroupID,
+	}
+	if len(hints) > 0 {
+		schedulerHints.AdditionalProperties = make(map[string]interface{}, len(hints))
+		for _, hint := range hints {
+			schedulerHints.AdditionalProperties[hint.Name] = schedulerHintValue(hint.Value)
+		}
 	}
-	return bootfromvolume.CreateOptsExt{
+	return schedulerhints.CreateOptsExt{
 		CreateOptsBuilder: opts,
-		BlockDevice:       []bootfromvolume.BlockDevice{block},
+		SchedulerHints:    schedulerHints,
 	}
 }
 
-// applyServerGroupID adds a scheduler hint to the CreateOptsBuilder, if the
-// spec contains a server group ID.
-func applyServerGroupID(opts servers.CreateOptsBuilder, serverGroupID string) servers.CreateOptsBuilder {
-	if serverGroupID != "" {
-		return schedulerhints.CreateOptsExt{
-			CreateOptsBuilder: opts,
-			SchedulerHints: schedulerhints.SchedulerHints{
-				Group: serverGroupID,
-			},
+// schedulerHintValue returns the value of a scheduler hint as it is passed to Nova.
+func schedulerHintValue(value infrav1.SchedulerHintAdditionalValue) interface{} {
+	switch value.Type {
+	case infrav1.SchedulerHintValueTypeBool:
+		if value.Bool != nil {
+			return *value.Bool
+		}
+	case infrav1.SchedulerHintValueTypeString:
+		if value.String != nil {
+			return *value.String
+		}
+	case infrav1.SchedulerHintValueTypeStringList:
+		return value.StringList
+	case infrav1.SchedulerHintValueTypeNumber:
+		if value.Number != nil {
+			return *value.Number
 		}
 	}
-	return opts
+	return nil
 }
 
 func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]infrav1.Network, error) {
@@ -431,16 +728,18 @@ func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]inf
 				addSubnet(netID, subnet.UUID)
 			} else {
 				subnetOpts := subnet.Filter.ToListOpt()
-				if netID != "" {
-					subnetOpts.NetworkID = netID
-				}
 
 				networkingService, err := s.getNetworkingService()
 				if err != nil {
 					return err
 				}
 
-				subnetsByFilter, err := networkingService.GetSubnetsByFilter(&subnetOpts)
+				var subnetsByFilter []subnets.Subnet
+				if netID != "" {
+					subnetsByFilter, err = networkingService.GetNetworkSubnetsByFilter(netID, &subnetOpts)
+				} else {
+					subnetsByFilter, err = networkingService.GetSubnetsByFilter(&subnetOpts)
+				}
 				if err != nil {
 					return err
 				}
@@ -487,36 +786,202 @@ func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]inf
 
 // Helper function for getting image id from name.
 func (s *Service) getImageIDFromName(imageName string) (string, error) {
+	if imageID, ok := lookupcache.Get(lookupcache.Image, s.scope.ProjectID, s.scope.RegionName(), "name="+imageName); ok {
+		return imageID.(string), nil
+	}
+
+	image, err := s.getImageFromName(imageName)
+	if err != nil {
+		return "", err
+	}
+	lookupcache.Add(lookupcache.Image, s.scope.ProjectID, s.scope.RegionName(), "name="+imageName, image.ID)
+	return image.ID, nil
+}
+
+func (s *Service) getImageFromName(imageName string) (*images.Image, error) {
 	var opts images.ListOpts
 
 	opts.Name = imageName
 
-	allImages, err := s.getImageClient().ListImages(opts)
+	var allImages []images.Image
+	err := retryImageRequest(s.scope.Context(), func() (err error) {
+		allImages, err = s.getImageClient().ListImages(s.scope.Context(), opts)
+		return err
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	switch len(allImages) {
 	case 0:
-		return "", fmt.Errorf("no image with the Name %s could be found", imageName)
+		return nil, fmt.Errorf("no image with the Name %s could be found", imageName)
 	case 1:
-		return allImages[0].ID, nil
+		return &allImages[0], nil
 	default:
 		// this should never happen
-		return "", fmt.Errorf("too many images with the name, %s, were found", imageName)
+		return nil, fmt.Errorf("too many images with the name, %s, were found", imageName)
 	}
 }
 
+// getInstanceImageID returns the ID of the image of the instance. ImageFilter is only resolved
+// if the image is not given by ID.
+func (s *Service) getInstanceImageID(instanceSpec *InstanceSpec) (string, error) {
+	imageUUID := instanceSpec.ImageUUID
+	if imageUUID == "" && instanceSpec.ImageFilter != nil {
+		var err error
+		imageUUID, err = s.ResolveImageFilter(instanceSpec.ImageFilter)
+		if err != nil {
+			return "", err
+		}
+	}
+	return s.getImageID(imageUUID, instanceSpec.Image, instanceSpec.ImageChecksum)
+}
+
+// ResolveImageFilter returns the ID of the active image which matches the filter. If several
+// images match, the most recently created one is returned if the filter asks for it, and an
+// error otherwise.
+func (s *Service) ResolveImageFilter(filter *infrav1.ImageFilter) (string, error) {
+	// Properties and MostRecent are applied to the images listed by the other fields, so the
+	// whole filter is the key of the lookup.
+	query, queryErr := json.Marshal(filter)
+	if queryErr == nil {
+		if imageID, ok := lookupcache.Get(lookupcache.Image, s.scope.ProjectID, s.scope.RegionName(), string(query)); ok {
+			return imageID.(string), nil
+		}
+	}
+
+	imageID, err := s.resolveImageFilter(filter)
+	if err == nil && queryErr == nil {
+		lookupcache.Add(lookupcache.Image, s.scope.ProjectID, s.scope.RegionName(), string(query), imageID)
+	}
+	return imageID, err
+}
+
+func (s *Service) resolveImageFilter(filter *infrav1.ImageFilter) (string, error) {
+	opts := images.ListOpts{
+		Name:   filter.Name,
+		Tags:   filter.Tags,
+		Status: images.ImageStatusActive,
+	}
+
+	var allImages []images.Image
+	err := retryImageRequest(s.scope.Context(), func() (err error) {
+		allImages, err = s.getImageClient().ListImages(s.scope.Context(), opts)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var matching []images.Image
+	for i := range allImages {
+		if imageHasProperties(&allImages[i], filter.Properties) {
+			matching = append(matching, allImages[i])
+		}
+	}
+
+	switch {
+	case len(matching) == 0:
+		return "", fmt.Errorf("no image matching the filter could be found")
+	case len(matching) == 1:
+		return matching[0].ID, nil
+	case !filter.MostRecent:
+		return "", fmt.Errorf("found %d images matching the filter, set mostRecent to select the most recent one", len(matching))
+	}
+
+	mostRecent := &matching[0]
+	for i := range matching[1:] {
+		if image := &matching[i+1]; image.CreatedAt.After(mostRecent.CreatedAt) {
+			mostRecent = image
+		}
+	}
+	return mostRecent.ID, nil
+}
+
+// imageHasProperties returns true if the image has all the given properties with the given values.
+func imageHasProperties(image *images.Image, properties map[string]string) bool {
+	for k, v := range properties {
+		value, ok := image.Properties[k]
+		if !ok || fmt.Sprint(value) != v {
+			return false
+		}
+	}
+	return true
+}
+
 // Helper function for getting image ID from name or ID.
-func (s *Service) getImageID(imageUUID, imageName string) (string, error) {
+// If imageChecksum is set, the image must have this checksum.
+func (s *Service) getImageID(imageUUID, imageName, imageChecksum string) (string, error) {
+	if imageUUID == "" && imageName == "" {
+		return "", nil
+	}
+
+	if imageChecksum != "" {
+		return s.getPinnedImageID(imageUUID, imageName, imageChecksum)
+	}
+
 	if imageUUID != "" {
 		// we return imageUUID without check
 		return imageUUID, nil
-	} else if imageName != "" {
-		return s.getImageIDFromName(imageName)
+	}
+	return s.getImageIDFromName(imageName)
+}
+
+// getFlavorID returns flavorID if it is set, otherwise it resolves flavorName, which may be an alias of a
+// flavor of the cloud.
+func (s *Service) getFlavorID(flavorID, flavorName string) (string, error) {
+	if flavorID != "" {
+		return flavorID, nil
+	}
+
+	flavorName = flavoralias.Resolve(s.scope.CloudName(), flavorName)
+	flavorID, err := s.getComputeClient().GetFlavorIDFromName(s.scope.Context(), flavorName)
+	if err != nil {
+		return "", fmt.Errorf("error getting flavor id from flavor name %s: %v", flavorName, err)
+	}
+	return flavorID, nil
+}
+
+// getPinnedImageID returns the ID of the image after checking that its content has not
+// changed since it was pinned to imageChecksum.
+func (s *Service) getPinnedImageID(imageUUID, imageName, imageChecksum string) (string, error) {
+	var image *images.Image
+	var err error
+	if imageUUID != "" {
+		err = retryImageRequest(s.scope.Context(), func() (err error) {
+			image, err = s.getImageClient().GetImage(s.scope.Context(), imageUUID)
+			return err
+		})
+	} else {
+		image, err = s.getImageFromName(imageName)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return "", nil
+	// Glance reports the MD5 checksum and, since Rocky, a multihash of the image data
+	hashValue, _ := image.Properties["os_hash_value"].(string)
+	if !strings.EqualFold(image.Checksum, imageChecksum) && !strings.EqualFold(hashValue, imageChecksum) {
+		return "", fmt.Errorf("%w: image %s has checksum %s, expected %s", ErrImageChecksumMismatch, image.ID, image.Checksum, imageChecksum)
+	}
+	return image.ID, nil
+}
+
+// retryImageRequest retries a request to Glance which failed with a retryable error, if retries
+// are configured for the image service.
+func retryImageRequest(ctx context.Context, request func() error) error {
+	var requestErr error
+	err := retry.Wait(ctx, retry.Image, retry.Policy{Interval: retryIntervalImageRequest}, func() (bool, error) {
+		requestErr = request()
+		if requestErr != nil && !capoerrors.IsRetryable(requestErr) {
+			return false, requestErr
+		}
+		return requestErr == nil, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return requestErr
+	}
+	return err
 }
 
 // GetManagementPort returns the port which is used for management and external
@@ -542,7 +1007,32 @@ func (s *Service) GetManagementPort(openStackCluster *infrav1.OpenStackCluster,
 	return &allPorts[0], nil
 }
 
-func (s *Service) DeleteInstance(eventObject runtime.Object, instanceStatus *InstanceStatus, instanceName string, rootVolume *infrav1.RootVolume) error {
+// RebootInstance hard reboots the server of an instance.
+func (s *Service) RebootInstance(eventObject runtime.Object, instanceStatus *InstanceStatus) error {
+	err := s.getComputeClient().RebootServer(s.scope.Context(), instanceStatus.ID(), servers.RebootOpts{Type: servers.HardReboot})
+	if err != nil {
+		record.Warnf(eventObject, "FailedRebootServer", "Failed to reboot server %s with id %s: %v", instanceStatus.Name(), instanceStatus.ID(), err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulRebootServer", "Rebooted server %s with id %s", instanceStatus.Name(), instanceStatus.ID())
+	return nil
+}
+
+// RebuildInstance rebuilds the server of an instance with an image, which replaces the contents of its
+// root disk. The ID, ports and attached volumes of the server are kept.
+func (s *Service) RebuildInstance(eventObject runtime.Object, instanceStatus *InstanceStatus, imageID string) error {
+	_, err := s.getComputeClient().RebuildServer(s.scope.Context(), instanceStatus.ID(), servers.RebuildOpts{ImageRef: imageID})
+	if err != nil {
+		record.Warnf(eventObject, "FailedRebuildServer", "Failed to rebuild server %s with id %s: %v", instanceStatus.Name(), instanceStatus.ID(), err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulRebuildServer", "Rebuilt server %s with id %s from image %s", instanceStatus.Name(), instanceStatus.ID(), imageID)
+	return nil
+}
+
+// DeleteInstance deletes the given instance and the ports, trunks and volumes created for it. portOpts are the ports of
+// the instance spec, whose subports are used to find the ports of subports left behind by a previous attempt.
+func (s *Service) DeleteInstance(eventObject runtime.Object, instanceStatus *InstanceStatus, instanceName string, rootVolume *infrav1.RootVolume, additionalBlockDevices []infrav1.AdditionalBlockDevice, portOpts []infrav1.PortOpts) error {
 	if instanceStatus == nil {
 		/*
 			We create a boot-from-volume instance in 2 steps:
@@ -554,32 +1044,33 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceStatus *Ins
 			volume with no instance.
 
 			To handle this safely, we ensure that we never remove a machine finalizer until all resources
-			associated with the instance, including a root volume, have been deleted. To achieve this:
+			associated with the instance, including its volumes, have been deleted. To achieve this:
 			* We always call DeleteInstance when reconciling a delete, regardless of
 			  whether the instance exists or not.
-			* If the instance was already deleted we check that the volume is also gone.
+			* If the instance was already deleted we check that the volumes are also gone.
 
-			Note that we don't need to separately delete the root volume when deleting the instance because
-			DeleteOnTermination will ensure it is deleted in that case.
+			Note that we don't need to separately delete the volumes when deleting the instance because
+			DeleteOnTermination will ensure they are deleted in that case. Retained volumes are kept
+			in either case.
 		*/
-		if hasRootVolume(rootVolume) {
-			name := rootVolumeName(instanceName)
-			volume, err := s.getVolumeByName(name)
-			if err != nil {
+		if hasRootVolume(rootVolume) && !isRetained(rootVolume.RetentionPolicy) {
+			if err := s.deleteDanglingVolume(rootVolumeName(instanceName)); err != nil {
 				return err
 			}
-			if volume == nil {
-				return nil
+		}
+		for i := range additionalBlockDevices {
+			if isRetained(additionalBlockDevices[i].RetentionPolicy) {
+				continue
+			}
+			if err := s.deleteDanglingVolume(additionalVolumeName(instanceName, &additionalBlockDevices[i])); err != nil {
+				return err
 			}
-
-			s.scope.Logger.Info("deleting dangling root volume %s(%s)", volume.Name, volume.ID)
-			return s.getVolumeClient().DeleteVolume(volume.ID, volumes.DeleteOpts{})
 		}
 
 		return nil
 	}
 
-	instanceInterfaces, err := s.getComputeClient().ListAttachedInterfaces(instanceStatus.ID())
+	instanceInterfaces, err := s.getComputeClient().ListAttachedInterfaces(s.scope.Context(), instanceStatus.ID())
 	if err != nil {
 		return err
 	}
@@ -594,20 +1085,28 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceStatus *Ins
 		return err
 	}
 
-	// get and delete trunks
+	portIDs := make([]string, 0, len(instanceInterfaces))
 	for _, port := range instanceInterfaces {
-		if err = s.deleteAttachInterface(eventObject, instanceStatus.InstanceIdentifier(), port.PortID); err != nil {
+		portIDs = append(portIDs, port.PortID)
+	}
+
+	subports := declaredSubports(portOpts)
+
+	// get and delete trunks
+	err = batch.Delete("port", portIDs, func(portID string) error {
+		if err := s.deleteAttachInterface(eventObject, instanceStatus.InstanceIdentifier(), portID); err != nil {
 			return err
 		}
 
 		if trunkSupported {
-			if err = networkingService.DeleteTrunk(eventObject, port.PortID); err != nil {
+			if err := networkingService.DeleteTrunk(eventObject, portID, subports); err != nil {
 				return err
 			}
 		}
-		if err = networkingService.DeletePort(eventObject, port.PortID); err != nil {
-			return err
-		}
+		return networkingService.DeletePort(eventObject, portID)
+	})
+	if err != nil {
+		return err
 	}
 
 	// delete port of error instance
@@ -617,10 +1116,23 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceStatus *Ins
 		}
 	}
 
+	if err := s.detachVolumes(eventObject, instanceStatus, instanceName, rootVolume, retryIntervalInstanceStatus); err != nil {
+		return err
+	}
+
 	return s.deleteInstance(eventObject, instanceStatus.InstanceIdentifier())
 }
 
-func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network) error {
+// declaredSubports returns the subports of all the given ports.
+func declaredSubports(portOpts []infrav1.PortOpts) []infrav1.SubportOpts {
+	var subports []infrav1.SubportOpts
+	for i := range portOpts {
+		subports = append(subports, portOpts[i].Subports...)
+	}
+	return subports
+}
+
+func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network, subports []infrav1.SubportOpts) error {
 	trunkSupported, err := s.isTrunkExtSupported()
 	if err != nil {
 		return err
@@ -633,7 +1145,7 @@ func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network
 		}
 
 		if trunkSupported {
-			if err = networkingService.DeleteTrunk(eventObject, n.Port); err != nil {
+			if err = networkingService.DeleteTrunk(eventObject, n.Port, subports); err != nil {
 				return err
 			}
 		}
@@ -645,7 +1157,7 @@ func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network
 }
 
 func (s *Service) deleteAttachInterface(eventObject runtime.Object, instance *InstanceIdentifier, portID string) error {
-	err := s.getComputeClient().DeleteAttachedInterface(instance.ID, portID)
+	err := s.getComputeClient().DeleteAttachedInterface(s.scope.Context(), instance.ID, portID)
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
 			record.Eventf(eventObject, "SuccessfulDeleteAttachInterface", "Attach interface did not exist: instance %s, port %s", instance.ID, portID)
@@ -665,7 +1177,8 @@ func (s *Service) deleteAttachInterface(eventObject runtime.Object, instance *In
 }
 
 func (s *Service) deleteInstance(eventObject runtime.Object, instance *InstanceIdentifier) error {
-	err := s.getComputeClient().DeleteServer(instance.ID)
+	batch.Throttle()
+	err := s.getComputeClient().DeleteServer(s.scope.Context(), instance.ID)
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
 			record.Eventf(eventObject, "SuccessfulDeleteServer", "Server %s with id %s did not exist", instance.Name, instance.ID)
@@ -675,7 +1188,7 @@ func (s *Service) deleteInstance(eventObject runtime.Object, instance *InstanceI
 		return err
 	}
 
-	err = util.PollImmediate(retryIntervalInstanceStatus, timeoutInstanceDelete, func() (bool, error) {
+	err = retry.Wait(s.scope.Context(), retry.Compute, retry.Policy{Interval: retryIntervalInstanceStatus, Timeout: timeoutInstanceDelete}, func() (bool, error) {
 		i, err := s.GetInstanceStatus(instance.ID)
 		if err != nil {
 			return false, err
@@ -699,7 +1212,7 @@ func (s *Service) GetInstanceStatus(resourceID string) (instance *InstanceStatus
 		return nil, fmt.Errorf("resourceId should be specified to get detail")
 	}
 
-	server, err := s.getComputeClient().GetServer(resourceID)
+	server, err := s.getComputeClient().GetServer(s.scope.Context(), resourceID)
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
 			return nil, nil
@@ -723,7 +1236,7 @@ func (s *Service) GetInstanceStatusByName(eventObject runtime.Object, name strin
 		listOpts = servers.ListOpts{}
 	}
 
-	serverList, err := s.getComputeClient().ListServers(listOpts)
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), listOpts)
 	if err != nil {
 		return nil, fmt.Errorf("get server list: %v", err)
 	}
@@ -739,6 +1252,32 @@ func (s *Service) GetInstanceStatusByName(eventObject runtime.Object, name strin
 	return nil, nil
 }
 
+// GetInstancePlacement returns where the instance was scheduled. The server groups of the instance are only returned
+// if Nova supports NovaServerGroupsMicroversion.
+func (s *Service) GetInstancePlacement(instanceStatus *InstanceStatus) (*infrav1.InstancePlacement, error) {
+	placement := &infrav1.InstancePlacement{
+		AvailabilityZone:   instanceStatus.AvailabilityZone(),
+		HostID:             instanceStatus.HostID(),
+		Host:               instanceStatus.Host(),
+		HypervisorHostname: instanceStatus.HypervisorHostname(),
+	}
+
+	maxMicroversion, err := s.getComputeClient().GetMaxMicroversion(s.scope.Context())
+	if err != nil {
+		return nil, fmt.Errorf("get maximum microversion of compute service: %v", err)
+	}
+	if !openstackutil.IsMicroversionSupported(maxMicroversion, clients.NovaServerGroupsMicroversion) {
+		return placement, nil
+	}
+
+	serverGroups, err := s.getComputeClient().ListServerGroupsOfServer(s.scope.Context(), instanceStatus.ID())
+	if err != nil {
+		return nil, fmt.Errorf("get server groups of server %q: %v", instanceStatus.ID(), err)
+	}
+	placement.ServerGroups = serverGroups
+	return placement, nil
+}
+
 func getTimeout(name string, timeout int) time.Duration {
 	if v := os.Getenv(name); v != "" {
 		timeout, err := strconv.Atoi(v)
@@ -773,3 +1312,41 @@ func HashInstanceSpec(computeInstance *InstanceSpec) (string, error) {
 	}
 	return strconv.Itoa(int(instanceHash)), nil
 }
+
+// HashServerCreateOpts returns the hash of the options a server is created with from the instance
+// spec, together with the hashes of the individual fields of the spec. Options which are reconciled
+// on the existing server, i.e. its metadata, its tags, the allowed address pairs of its ports and
+// its additional block devices, are not part of the hashes.
+func HashServerCreateOpts(instanceSpec *InstanceSpec) (string, map[string]string, error) {
+	spec := *instanceSpec
+	spec.Metadata = nil
+	spec.Tags = nil
+	spec.AdditionalBlockDevices = nil
+	if instanceSpec.Ports != nil {
+		spec.Ports = make([]infrav1.PortOpts, len(instanceSpec.Ports))
+		for i := range instanceSpec.Ports {
+			instanceSpec.Ports[i].DeepCopyInto(&spec.Ports[i])
+			spec.Ports[i].AllowedAddressPairs = nil
+		}
+	}
+
+	specHash, err := HashInstanceSpec(&spec)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fields := make(map[string]string)
+	v := reflect.ValueOf(spec)
+	for i := 0; i < v.NumField(); i++ {
+		// The hashes recorded for servers created with additional block devices or tags are not compared
+		if name := v.Type().Field(i).Name; name == "AdditionalBlockDevices" || name == "Tags" {
+			continue
+		}
+		fieldHash, err := hash.ComputeSpewHash(v.Field(i).Interface())
+		if err != nil {
+			return "", nil, err
+		}
+		fields[v.Type().Field(i).Name] = strconv.Itoa(int(fieldHash))
+	}
+	return specHash, fields, nil
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package compute
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +31,8 @@ import (
 	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
@@ -37,6 +41,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/gophercloud/utils/openstack/clientconfig"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	gomegatypes "github.com/onsi/gomega/types"
@@ -48,6 +53,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
 )
 
 type gomegaMockMatcher struct {
@@ -116,6 +122,348 @@ func Test_getPortName(t *testing.T) {
 	}
 }
 
+func Test_interfaceOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		nets []infrav1.Network
+		want []int
+	}{
+		{
+			name: "without order the networks keep their order",
+			nets: []infrav1.Network{{}, {PortOpts: &infrav1.PortOpts{}}, {PortOpts: &infrav1.PortOpts{}}},
+			want: []int{0, 1, 2},
+		},
+		{
+			name: "ports are sorted by order, ports of the same order by index",
+			nets: []infrav1.Network{
+				{PortOpts: &infrav1.PortOpts{Order: 2}},
+				{PortOpts: &infrav1.PortOpts{Order: 1}},
+				{},
+				{PortOpts: &infrav1.PortOpts{Order: 1}},
+			},
+			want: []int{2, 1, 3, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(interfaceOrder(tt.nets)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_clusterNetworkPortOpts(t *testing.T) {
+	withoutPortSecurity := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.6.0.0/24", DisablePortSecurity: true},
+	}
+	tests := []struct {
+		name             string
+		openStackCluster *infrav1.OpenStackCluster
+		port             *infrav1.PortOpts
+		want             *bool
+	}{
+		{
+			name:             "cluster network with port security",
+			openStackCluster: &infrav1.OpenStackCluster{Spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.6.0.0/24"}},
+			port:             &infrav1.PortOpts{},
+			want:             nil,
+		},
+		{
+			name:             "cluster network without port security is inherited",
+			openStackCluster: withoutPortSecurity,
+			port:             &infrav1.PortOpts{},
+			want:             pointer.Bool(true),
+		},
+		{
+			name:             "port overrides cluster network without port security",
+			openStackCluster: withoutPortSecurity,
+			port:             &infrav1.PortOpts{DisablePortSecurity: pointer.Bool(false)},
+			want:             pointer.Bool(false),
+		},
+		{
+			name:             "existing cluster network is not changed by the cluster setting",
+			openStackCluster: &infrav1.OpenStackCluster{Spec: infrav1.OpenStackClusterSpec{DisablePortSecurity: true}},
+			port:             &infrav1.PortOpts{},
+			want:             nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := clusterNetworkPortOpts(tt.openStackCluster, tt.port, "")
+			g.Expect(got.DisablePortSecurity).To(Equal(tt.want))
+			// The options of the caller are not modified
+			g.Expect(tt.port.DisablePortSecurity == nil || got == tt.port).To(BeTrue())
+		})
+	}
+}
+
+func Test_clusterNetworkPortOptsAPIServerVIP(t *testing.T) {
+	openStackCluster := &infrav1.OpenStackCluster{Spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.6.0.0/24"}}
+	tests := []struct {
+		name             string
+		openStackCluster *infrav1.OpenStackCluster
+		port             *infrav1.PortOpts
+		want             []infrav1.AddressPair
+	}{
+		{
+			name:             "VIP is added to the allowed address pairs",
+			openStackCluster: openStackCluster,
+			port:             &infrav1.PortOpts{AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.6.0.20"}}},
+			want:             []infrav1.AddressPair{{IPAddress: "10.6.0.20"}, {IPAddress: "10.6.0.10"}},
+		},
+		{
+			name:             "VIP is not added twice",
+			openStackCluster: openStackCluster,
+			port:             &infrav1.PortOpts{AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.6.0.10"}}},
+			want:             []infrav1.AddressPair{{IPAddress: "10.6.0.10"}},
+		},
+		{
+			name:             "VIP is not added to a port without port security",
+			openStackCluster: &infrav1.OpenStackCluster{Spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.6.0.0/24", DisablePortSecurity: true}},
+			port:             &infrav1.PortOpts{},
+			want:             nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			pairs := tt.port.DeepCopy().AllowedAddressPairs
+			got := clusterNetworkPortOpts(tt.openStackCluster, tt.port, "10.6.0.10")
+			g.Expect(got.AllowedAddressPairs).To(Equal(tt.want))
+			// The options of the caller are not modified
+			g.Expect(tt.port.AllowedAddressPairs).To(Equal(pairs))
+		})
+	}
+}
+
+func Test_clusterSubnet(t *testing.T) {
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				Subnet: &infrav1.Subnet{ID: "node-subnet"},
+				ManagedSubnets: []infrav1.ManagedSubnetStatus{
+					{Name: "storage", Role: infrav1.SubnetRoleStorage, Subnet: infrav1.Subnet{ID: "storage-subnet"}},
+					{Name: "workers-a", Role: infrav1.SubnetRoleWorkers, Subnet: infrav1.Subnet{ID: "workers-a-subnet"}},
+					{Name: "workers-b", Role: infrav1.SubnetRoleWorkers, Subnet: infrav1.Subnet{ID: "workers-b-subnet"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		selector *infrav1.ManagedSubnetSelector
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "No managed subnet",
+			want: "node-subnet",
+		},
+		{
+			name:     "Managed subnet by name",
+			selector: &infrav1.ManagedSubnetSelector{Name: "workers-b"},
+			want:     "workers-b-subnet",
+		},
+		{
+			name:     "Managed subnet by role",
+			selector: &infrav1.ManagedSubnetSelector{Role: infrav1.SubnetRoleStorage},
+			want:     "storage-subnet",
+		},
+		{
+			name:     "Ambiguous role",
+			selector: &infrav1.ManagedSubnetSelector{Role: infrav1.SubnetRoleWorkers},
+			wantErr:  true,
+		},
+		{
+			name:     "Unknown managed subnet",
+			selector: &infrav1.ManagedSubnetSelector{Role: infrav1.SubnetRoleControlPlane},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := clusterSubnet(openStackCluster, tt.selector)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.ID).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_instanceConfigDrive(t *testing.T) {
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				Subnet: &infrav1.Subnet{ID: "node-subnet"},
+				ManagedSubnets: []infrav1.ManagedSubnetStatus{
+					{Name: "storage", Role: infrav1.SubnetRoleStorage, Subnet: infrav1.Subnet{ID: "storage-subnet"}},
+					{Name: "static", Subnet: infrav1.Subnet{ID: "static-subnet"}, DHCPDisabled: true},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		instanceSpec InstanceSpec
+		want         bool
+	}{
+		{
+			name: "No config drive",
+		},
+		{
+			name:         "Config drive",
+			instanceSpec: InstanceSpec{ConfigDrive: true},
+			want:         true,
+		},
+		{
+			name:         "Managed subnet with DHCP",
+			instanceSpec: InstanceSpec{ManagedSubnet: &infrav1.ManagedSubnetSelector{Name: "storage"}},
+		},
+		{
+			name:         "Managed subnet without DHCP",
+			instanceSpec: InstanceSpec{ManagedSubnet: &infrav1.ManagedSubnetSelector{Name: "static"}},
+			want:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(instanceConfigDrive(openStackCluster, &tt.instanceSpec)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_applySchedulerHints(t *testing.T) {
+	const serverGroupID = "7b940d62-68ef-4e42-a76a-1a62e290509c"
+
+	tests := []struct {
+		name          string
+		serverGroupID string
+		hints         []infrav1.SchedulerHintAdditionalProperty
+		want          map[string]interface{}
+	}{
+		{
+			name: "no hints",
+		},
+		{
+			name:          "server group",
+			serverGroupID: serverGroupID,
+			want:          map[string]interface{}{"group": serverGroupID},
+		},
+		{
+			name:          "server group and additional hints",
+			serverGroupID: serverGroupID,
+			hints: []infrav1.SchedulerHintAdditionalProperty{
+				{Name: "different_host", Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeStringList, StringList: []string{"a1b2c3d4-0000-4000-8000-000000000001"}}},
+				{Name: "aggregate", Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeString, String: pointer.String("gpu")}},
+				{Name: "dedicated", Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeBool, Bool: pointer.Bool(true)}},
+				{Name: "min_cores", Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeNumber, Number: pointer.Int(8)}},
+			},
+			want: map[string]interface{}{
+				"group":          serverGroupID,
+				"different_host": []string{"a1b2c3d4-0000-4000-8000-000000000001"},
+				"aggregate":      "gpu",
+				"dedicated":      true,
+				"min_cores":      8,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			opts := applySchedulerHints(servers.CreateOpts{Name: "test"}, tt.serverGroupID, tt.hints)
+			createMap, err := opts.ToServerCreateMap()
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.want == nil {
+				g.Expect(createMap).NotTo(HaveKey("os:scheduler_hints"))
+				return
+			}
+			g.Expect(createMap).To(HaveKeyWithValue("os:scheduler_hints", tt.want))
+		})
+	}
+}
+
+func Test_applyHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+	}{
+		{
+			name: "no hostname",
+		},
+		{
+			name:     "hostname",
+			hostname: "node-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			opts := applySchedulerHints(applyHostname(servers.CreateOpts{Name: "Corp Server 1"}, tt.hostname), "7b940d62-68ef-4e42-a76a-1a62e290509c", nil)
+			createMap, err := opts.ToServerCreateMap()
+			g.Expect(err).NotTo(HaveOccurred())
+			server := createMap["server"].(map[string]interface{})
+			g.Expect(server).To(HaveKeyWithValue("name", "Corp Server 1"))
+			if tt.hostname == "" {
+				g.Expect(server).NotTo(HaveKey("hostname"))
+				return
+			}
+			g.Expect(server).To(HaveKeyWithValue("hostname", tt.hostname))
+		})
+	}
+}
+
+func Test_HashServerCreateOpts(t *testing.T) {
+	g := NewWithT(t)
+
+	newSpec := func() *InstanceSpec {
+		return &InstanceSpec{
+			Name:     "test-instance",
+			Flavor:   "m1.small",
+			Metadata: map[string]string{"foo": "bar"},
+			Ports: []infrav1.PortOpts{
+				{AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.1"}}},
+			},
+		}
+	}
+
+	specHash, fields, err := HashServerCreateOpts(newSpec())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fields).To(HaveKey("Flavor"))
+	g.Expect(fields).To(HaveKey("Ports"))
+	g.Expect(fields).NotTo(HaveKey("AdditionalBlockDevices"))
+
+	// Options reconciled on the existing server do not change the hashes
+	spec := newSpec()
+	spec.Metadata = map[string]string{"foo": "baz"}
+	spec.Ports[0].AllowedAddressPairs = nil
+	spec.AdditionalBlockDevices = []infrav1.AdditionalBlockDevice{{Name: "data", Size: 10}}
+	gotHash, gotFields, err := HashServerCreateOpts(spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gotHash).To(Equal(specHash))
+	g.Expect(gotFields).To(Equal(fields))
+	g.Expect(spec.Metadata).To(HaveKeyWithValue("foo", "baz"), "the spec of the caller is not modified")
+
+	// Other options change the hash of their field only
+	spec = newSpec()
+	spec.Flavor = "m1.large"
+	gotHash, gotFields, err = HashServerCreateOpts(spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gotHash).NotTo(Equal(specHash))
+	for name, fieldHash := range fields {
+		if name == "Flavor" {
+			g.Expect(gotFields[name]).NotTo(Equal(fieldHash))
+		} else {
+			g.Expect(gotFields[name]).To(Equal(fieldHash), name)
+		}
+	}
+}
+
 func TestService_getServerNetworks(t *testing.T) {
 	const testClusterTag = "cluster=mycluster"
 
@@ -153,6 +501,15 @@ func TestService_getServerNetworks(t *testing.T) {
 		Tags:    []string{testClusterTag},
 	}
 
+	// Network C is owned by another project and shared as an external
+	// network, so its subnets are not visible.
+	testNetworkC := networks.Network{
+		ID:        networkCUUID,
+		Name:      "network-c",
+		ProjectID: "other-project",
+		Subnets:   []string{subnetC1UUID},
+	}
+
 	testSubnetA1 := subnets.Subnet{
 		ID:        subnetA1UUID,
 		Name:      "subnet-a1",
@@ -209,7 +566,7 @@ func TestService_getServerNetworks(t *testing.T) {
 			},
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				// List tagged networks (A & B)
-				m.ListNetwork(&testNetworkListOpts).
+				m.ListNetwork(gomock.Any(), &testNetworkListOpts).
 					Return([]networks.Network{testNetworkA, testNetworkB}, nil)
 			},
 			wantErr: false,
@@ -228,7 +585,7 @@ func TestService_getServerNetworks(t *testing.T) {
 			},
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				// List all tagged subnets in any network (A1, A2, and B1)
-				m.ListSubnet(&testSubnetListOpts).
+				m.ListSubnet(gomock.Any(), &testSubnetListOpts).
 					Return([]subnets.Subnet{testSubnetA1, testSubnetA2, testSubnetB1}, nil)
 			},
 			wantErr: false,
@@ -251,7 +608,7 @@ func TestService_getServerNetworks(t *testing.T) {
 				// List tagged subnets in network A (A1 & A2)
 				networkAFilter := testSubnetListOpts
 				networkAFilter.NetworkID = networkAUUID
-				m.ListSubnet(&networkAFilter).
+				m.ListSubnet(gomock.Any(), &networkAFilter).
 					Return([]subnets.Subnet{testSubnetA1, testSubnetA2}, nil)
 			},
 			wantErr: false,
@@ -293,7 +650,7 @@ func TestService_getServerNetworks(t *testing.T) {
 				// List tagged subnets in network A
 				networkAFilter := testSubnetListOpts
 				networkAFilter.NetworkID = networkAUUID
-				m.ListSubnet(&networkAFilter).
+				m.ListSubnet(gomock.Any(), &networkAFilter).
 					Return([]subnets.Subnet{testSubnetA1, testSubnetA2}, nil)
 			},
 			wantErr: false,
@@ -322,19 +679,19 @@ func TestService_getServerNetworks(t *testing.T) {
 			},
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				// List tagged networks (A & B)
-				m.ListNetwork(&testNetworkListOpts).
+				m.ListNetwork(gomock.Any(), &testNetworkListOpts).
 					Return([]networks.Network{testNetworkA, testNetworkB}, nil)
 
 				// List tagged subnets in network A (A1 & A2)
 				networkAFilter := testSubnetListOpts
 				networkAFilter.NetworkID = networkAUUID
-				m.ListSubnet(&networkAFilter).
+				m.ListSubnet(gomock.Any(), &networkAFilter).
 					Return([]subnets.Subnet{testSubnetA1, testSubnetA2}, nil)
 
 				// List tagged subnets in network B (B1)
 				networkBFilter := testSubnetListOpts
 				networkBFilter.NetworkID = networkBUUID
-				m.ListSubnet(&networkBFilter).
+				m.ListSubnet(gomock.Any(), &networkBFilter).
 					Return([]subnets.Subnet{testSubnetB1}, nil)
 			},
 			wantErr: false,
@@ -348,7 +705,7 @@ func TestService_getServerNetworks(t *testing.T) {
 			want: nil,
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				// List tagged networks (none for this test)
-				m.ListNetwork(&testNetworkListOpts).Return([]networks.Network{}, nil)
+				m.ListNetwork(gomock.Any(), &testNetworkListOpts).Return([]networks.Network{}, nil)
 			},
 			wantErr: true,
 		},
@@ -368,7 +725,49 @@ func TestService_getServerNetworks(t *testing.T) {
 				// List tagged subnets in network A
 				networkAFilter := testSubnetListOpts
 				networkAFilter.NetworkID = networkAUUID
-				m.ListSubnet(&networkAFilter).Return([]subnets.Subnet{}, nil)
+				m.ListSubnet(gomock.Any(), &networkAFilter).Return([]subnets.Subnet{}, nil)
+				// Network A is owned by the project, so its subnets are not hidden
+				m.GetNetwork(gomock.Any(), networkAUUID).Return(&testNetworkA, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Subnet ID filter in a network of another project with hidden subnets",
+			networkParams: []infrav1.NetworkParam{
+				{
+					UUID: networkCUUID,
+					Subnets: []infrav1.SubnetParam{
+						{Filter: infrav1.SubnetFilter{ID: subnetC1UUID}},
+					},
+				},
+			},
+			want: []infrav1.Network{
+				{ID: networkCUUID, Subnet: &infrav1.Subnet{ID: subnetC1UUID}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), &subnets.ListOpts{ID: subnetC1UUID, NetworkID: networkCUUID}).Return([]subnets.Subnet{}, nil)
+				m.GetNetwork(gomock.Any(), networkCUUID).Return(&testNetworkC, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: networkCUUID, Limit: 1}).Return([]subnets.Subnet{}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Subnet tag filter in a network of another project with hidden subnets",
+			networkParams: []infrav1.NetworkParam{
+				{
+					UUID: networkCUUID,
+					Subnets: []infrav1.SubnetParam{
+						{Filter: testSubnetFilter},
+					},
+				},
+			},
+			want: nil,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				networkCFilter := testSubnetListOpts
+				networkCFilter.NetworkID = networkCUUID
+				m.ListSubnet(gomock.Any(), &networkCFilter).Return([]subnets.Subnet{}, nil)
+				m.GetNetwork(gomock.Any(), networkCUUID).Return(&testNetworkC, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: networkCUUID, Limit: 1}).Return([]subnets.Subnet{}, nil)
 			},
 			wantErr: true,
 		},
@@ -416,13 +815,17 @@ func TestService_getImageID(t *testing.T) {
 	const imageIDB = "8f536889-5198-42d7-8314-cb78f4f4755c"
 	const imageIDC = "8f536889-5198-42d7-8314-cb78f4f4755d"
 
+	const checksum = "1c4a4eaf8d6d79c1a0bd7b7f3b3e4e5a"
+	const hashValue = "e0e5b2b6c5c0cb7c4d1c4b1d58d2a1d6c4f2b1e6f3a1e3c2d1b5a4f3e2d1c0b9"
+
 	tests := []struct {
-		testName  string
-		imageUUID string
-		imageName string
-		expect    func(m *mock.MockImageClientMockRecorder)
-		want      string
-		wantErr   bool
+		testName      string
+		imageUUID     string
+		imageName     string
+		imageChecksum string
+		expect        func(m *mock.MockImageClientMockRecorder)
+		want          string
+		wantErr       bool
 	}{
 		{
 			testName:  "Return image uuid if uuid given",
@@ -445,7 +848,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return image ID",
 			imageName: "test-image",
 			expect: func(m *mock.MockImageClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
 					[]images.Image{{ID: imageIDA, Name: "test-image"}},
 					nil)
 			},
@@ -456,7 +859,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return no results",
 			imageName: "test-image",
 			expect: func(m *mock.MockImageClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
 					[]images.Image{},
 					nil)
 			},
@@ -467,7 +870,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return multiple results",
 			imageName: "test-image",
 			expect: func(m *mock.MockImageClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
 					[]images.Image{
 						{ID: imageIDA, Name: "test-image"},
 						{ID: imageIDB, Name: "test-image"},
@@ -480,13 +883,47 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "OpenStack returns error",
 			imageName: "test-image",
 			expect: func(m *mock.MockImageClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
 					nil,
 					fmt.Errorf("test error"))
 			},
 			want:    "",
 			wantErr: true,
 		},
+		{
+			testName:      "Return pinned image uuid if checksum matches",
+			imageUUID:     imageIDC,
+			imageChecksum: checksum,
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.GetImage(gomock.Any(), imageIDC).Return(&images.Image{ID: imageIDC, Checksum: checksum}, nil)
+			},
+			want:    imageIDC,
+			wantErr: false,
+		},
+		{
+			testName:      "Return pinned image ID if multihash matches",
+			imageName:     "test-image",
+			imageChecksum: strings.ToUpper(hashValue),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
+					[]images.Image{{ID: imageIDA, Name: "test-image", Checksum: checksum, Properties: map[string]interface{}{"os_hash_value": hashValue}}},
+					nil)
+			},
+			want:    imageIDA,
+			wantErr: false,
+		},
+		{
+			testName:      "Return error if pinned image was replaced",
+			imageName:     "test-image",
+			imageChecksum: checksum,
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "test-image"}).Return(
+					[]images.Image{{ID: imageIDB, Name: "test-image", Checksum: "d41d8cd98f00b204e9800998ecf8427e"}},
+					nil)
+			},
+			want:    "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -503,7 +940,7 @@ func TestService_getImageID(t *testing.T) {
 				_networkingService: &networking.Service{},
 			}
 
-			got, err := s.getImageID(tt.imageUUID, tt.imageName)
+			got, err := s.getImageID(tt.imageUUID, tt.imageName, tt.imageChecksum)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.getImageID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -515,6 +952,154 @@ func TestService_getImageID(t *testing.T) {
 	}
 }
 
+func TestService_getFlavorID(t *testing.T) {
+	defer flavoralias.Configure(nil, nil)
+	flavoralias.Configure(nil, map[string]flavoralias.Aliases{"cloud-a": {"m1.large": "c4-m8"}})
+
+	tests := []struct {
+		testName   string
+		cloudName  string
+		flavorID   string
+		flavorName string
+		expect     func(m *mock.MockComputeClientMockRecorder)
+		want       string
+	}{
+		{
+			testName:   "Return flavor ID without lookup",
+			cloudName:  "cloud-a",
+			flavorID:   flavorUUID,
+			flavorName: "m1.large",
+			expect:     func(m *mock.MockComputeClientMockRecorder) {},
+			want:       flavorUUID,
+		},
+		{
+			testName:   "Resolve alias of the cloud",
+			cloudName:  "cloud-a",
+			flavorName: "m1.large",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetFlavorIDFromName(gomock.Any(), "c4-m8").Return(flavorUUID, nil)
+			},
+			want: flavorUUID,
+		},
+		{
+			testName:   "Resolve name without alias in other cloud",
+			cloudName:  "cloud-b",
+			flavorName: "m1.large",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetFlavorIDFromName(gomock.Any(), "m1.large").Return(flavorUUID, nil)
+			},
+			want: flavorUUID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					ProviderClientOpts: &clientconfig.ClientOpts{Cloud: tt.cloudName},
+					Logger:             logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.getFlavorID(tt.flavorID, tt.flavorName)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_ResolveImageFilter(t *testing.T) {
+	const imageIDA = "ce96e584-7ebc-46d6-9e55-987d72e3806c"
+	const imageIDB = "8f536889-5198-42d7-8314-cb78f4f4755c"
+
+	created := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	ubuntu := map[string]interface{}{"os_distro": "ubuntu", "os_version": "22.04"}
+	listOpts := images.ListOpts{Tags: []string{"kubernetes"}, Status: images.ImageStatusActive}
+
+	tests := []struct {
+		testName string
+		filter   *infrav1.ImageFilter
+		expect   func(m *mock.MockImageClientMockRecorder)
+		want     string
+		wantErr  bool
+	}{
+		{
+			testName: "Single image with tags",
+			filter:   &infrav1.ImageFilter{Tags: []string{"kubernetes"}},
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), listOpts).Return([]images.Image{{ID: imageIDA}}, nil)
+			},
+			want: imageIDA,
+		},
+		{
+			testName: "Images filtered by properties",
+			filter:   &infrav1.ImageFilter{Tags: []string{"kubernetes"}, Properties: map[string]string{"os_distro": "ubuntu"}},
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), listOpts).Return([]images.Image{
+					{ID: imageIDA, Properties: map[string]interface{}{"os_distro": "flatcar"}},
+					{ID: imageIDB, Properties: ubuntu},
+				}, nil)
+			},
+			want: imageIDB,
+		},
+		{
+			testName: "Most recent image",
+			filter:   &infrav1.ImageFilter{Tags: []string{"kubernetes"}, Properties: map[string]string{"os_distro": "ubuntu"}, MostRecent: true},
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), listOpts).Return([]images.Image{
+					{ID: imageIDA, Properties: ubuntu, CreatedAt: created.Add(24 * time.Hour)},
+					{ID: imageIDB, Properties: ubuntu, CreatedAt: created},
+				}, nil)
+			},
+			want: imageIDA,
+		},
+		{
+			testName: "Several images without most recent",
+			filter:   &infrav1.ImageFilter{Tags: []string{"kubernetes"}},
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), listOpts).Return([]images.Image{{ID: imageIDA}, {ID: imageIDB}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			testName: "No matching image",
+			filter:   &infrav1.ImageFilter{Tags: []string{"kubernetes"}, Properties: map[string]string{"os_version": "20.04"}},
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), listOpts).Return([]images.Image{{ID: imageIDA, Properties: ubuntu}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockImageClient := mock.NewMockImageClient(mockCtrl)
+			tt.expect(mockImageClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_imageClient: mockImageClient,
+			}
+
+			got, err := s.ResolveImageFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.ResolveImageFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Service.ResolveImageFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 const (
 	networkUUID                   = "d412171b-9fd7-41c1-95a6-c24e5953974d"
 	subnetUUID                    = "d2d8d98d-b234-477e-a547-868b7cb5d6a5"
@@ -615,7 +1200,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 	// Expected calls to create a server with a single default port
 	expectUseExistingDefaultPort := func(networkRecorder *mock.MockNetworkClientMockRecorder) {
 		// Returning a pre-existing port requires fewer mocks
-		networkRecorder.ListPort(ports.ListOpts{
+		networkRecorder.ListPort(gomock.Any(), ports.ListOpts{
 			Name:      portName,
 			NetworkID: networkUUID,
 		}).Return([]ports.Port{
@@ -627,13 +1212,13 @@ func TestService_ReconcileInstance(t *testing.T) {
 	}
 
 	expectCreatePort := func(networkRecorder *mock.MockNetworkClientMockRecorder, name string, networkID string) {
-		networkRecorder.ListPort(ports.ListOpts{
+		networkRecorder.ListPort(gomock.Any(), ports.ListOpts{
 			Name:      name,
 			NetworkID: networkID,
 		}).Return([]ports.Port{}, nil)
 
 		// gomock won't match a pointer to a nil slice in SecurityGroups, so we do this
-		networkRecorder.CreatePort(gomock.Any()).DoAndReturn(func(createOpts ports.CreateOptsBuilder) (*ports.Port, error) {
+		networkRecorder.CreatePort(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts ports.CreateOptsBuilder) (*ports.Port, error) {
 			createOptsMap, err := createOpts.ToPortCreateMap()
 			Expect(err).NotTo(HaveOccurred())
 
@@ -651,19 +1236,19 @@ func TestService_ReconcileInstance(t *testing.T) {
 				Description: portOpts["description"].(string),
 			}, nil
 		})
-		networkRecorder.ReplaceAllAttributesTags("ports", portUUID, attributestags.ReplaceAllOpts{Tags: []string{"test-tag"}}).Return(nil, nil)
+		networkRecorder.ReplaceAllAttributesTags(gomock.Any(), "ports", portUUID, attributestags.ReplaceAllOpts{Tags: []string{"test-tag"}}).Return(nil, nil)
 	}
 
 	// Expected calls if we delete the network port
 	expectCleanupDefaultPort := func(networkRecorder *mock.MockNetworkClientMockRecorder) {
-		networkRecorder.ListExtensions()
-		networkRecorder.DeletePort(portUUID).Return(nil)
+		networkRecorder.ListExtensions(gomock.Any())
+		networkRecorder.DeletePort(gomock.Any(), portUUID).Return(nil)
 	}
 
 	// Expected calls when using default image and flavor
 	expectDefaultImageAndFlavor := func(computeRecorder *mock.MockComputeClientMockRecorder, imageRecorder *mock.MockImageClientMockRecorder) {
-		imageRecorder.ListImages(images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
-		computeRecorder.GetFlavorIDFromName(flavorName).Return(flavorUUID, nil)
+		imageRecorder.ListImages(gomock.Any(), images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
+		computeRecorder.GetFlavorIDFromName(gomock.Any(), flavorName).Return(flavorUUID, nil)
 	}
 
 	// Expected calls and custom match function for creating a server
@@ -674,7 +1259,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 		// map[string]interface{} of the create options, and then use
 		// gomega to assert the contents of the map, which is more flexible.
 
-		computeRecorder.CreateServer(gomock.Any()).DoAndReturn(func(createOpts servers.CreateOptsBuilder) (*clients.ServerExt, error) {
+		computeRecorder.CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts servers.CreateOptsBuilder) (*clients.ServerExt, error) {
 			optsMap, err := createOpts.ToServerCreateMap()
 			Expect(err).NotTo(HaveOccurred())
 
@@ -690,7 +1275,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 	// Expected calls when polling for server creation
 	expectServerPoll := func(computeRecorder *mock.MockComputeClientMockRecorder, states []string) {
 		for _, state := range states {
-			computeRecorder.GetServer(instanceUUID).Return(returnedServer(state), nil)
+			computeRecorder.GetServer(gomock.Any(), instanceUUID).Return(returnedServer(state), nil)
 		}
 	}
 
@@ -708,7 +1293,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 	// Expected calls when polling for server creation
 	expectVolumePoll := func(volumeRecorder *mock.MockVolumeClientMockRecorder, states []string) {
 		for _, state := range states {
-			volumeRecorder.GetVolume(volumeUUID).Return(returnedVolume(state), nil)
+			volumeRecorder.GetVolume(gomock.Any(), volumeUUID).Return(returnedVolume(state), nil)
 		}
 	}
 
@@ -745,6 +1330,24 @@ func TestService_ReconcileInstance(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Flavor selected by ID",
+			getInstanceSpec: func() *InstanceSpec {
+				s := getDefaultInstanceSpec()
+				s.Flavor = ""
+				s.FlavorID = flavorUUID
+				return s
+			},
+			expect: func(r *recorders) {
+				expectUseExistingDefaultPort(r.network)
+				// The flavor is not looked up by name
+				r.image.ListImages(gomock.Any(), images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
+
+				expectCreateServer(r.compute, getDefaultServerMap(), false)
+				expectServerPollSuccess(r.compute)
+			},
+			wantErr: false,
+		},
 		{
 			name:            "Delete ports on server create error",
 			getInstanceSpec: getDefaultInstanceSpec,
@@ -774,7 +1377,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 				expectUseExistingDefaultPort(r.network)
 
 				// Looking up the second port fails
-				r.network.ListPort(ports.ListOpts{
+				r.network.ListPort(gomock.Any(), ports.ListOpts{
 					Name:      "test-openstack-machine-1",
 					NetworkID: networkUUID,
 				}).Return(nil, fmt.Errorf("test error"))
@@ -805,6 +1408,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 
 				expectCreateServer(r.compute, getDefaultServerMap(), false)
 				expectServerPoll(r.compute, []string{"BUILDING", "ERROR"})
+				r.compute.GetConsoleOutput(gomock.Any(), instanceUUID, consoleLogLines).Return("Kernel panic - not syncing: VFS: Unable to mount root fs\n", nil)
 
 				// Don't delete ports because the server is created: DeleteInstance will do it
 			},
@@ -823,15 +1427,16 @@ func TestService_ReconcileInstance(t *testing.T) {
 				expectUseExistingDefaultPort(r.network)
 				expectDefaultImageAndFlavor(r.compute, r.image)
 
-				r.volume.ListVolumes(volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
 					Return([]volumes.Volume{}, nil)
-				r.volume.CreateVolume(volumes.CreateOpts{
+				r.volume.CreateVolume(gomock.Any(), volumes.CreateOpts{
 					Size:             50,
 					AvailabilityZone: failureDomain,
 					Description:      fmt.Sprintf("Root volume for %s", openStackMachineName),
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
 					ImageID:          imageUUID,
 					Multiattach:      false,
+					Metadata:         map[string]string{"tag:test-tag": "true", "capo-delete-on-termination": "true"},
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePollSuccess(r.volume)
 
@@ -862,6 +1467,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 					Size:             50,
 					AvailabilityZone: "test-alternate-az",
 					VolumeType:       "test-volume-type",
+					CrossAZAttach:    true,
 				}
 				return s
 			},
@@ -869,9 +1475,9 @@ func TestService_ReconcileInstance(t *testing.T) {
 				expectUseExistingDefaultPort(r.network)
 				expectDefaultImageAndFlavor(r.compute, r.image)
 
-				r.volume.ListVolumes(volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
 					Return([]volumes.Volume{}, nil)
-				r.volume.CreateVolume(volumes.CreateOpts{
+				r.volume.CreateVolume(gomock.Any(), volumes.CreateOpts{
 					Size:             50,
 					AvailabilityZone: "test-alternate-az",
 					VolumeType:       "test-volume-type",
@@ -879,6 +1485,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
 					ImageID:          imageUUID,
 					Multiattach:      false,
+					Metadata:         map[string]string{"tag:test-tag": "true", "capo-delete-on-termination": "true"},
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePollSuccess(r.volume)
 
@@ -901,6 +1508,47 @@ func TestService_ReconcileInstance(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Boot from volume in another AZ without cross-AZ attach",
+			getInstanceSpec: func() *InstanceSpec {
+				s := getDefaultInstanceSpec()
+				s.RootVolume = &infrav1.RootVolume{
+					Size:             50,
+					AvailabilityZone: "test-alternate-az",
+				}
+				return s
+			},
+			expect: func(r *recorders) {
+				expectUseExistingDefaultPort(r.network)
+				expectDefaultImageAndFlavor(r.compute, r.image)
+
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
+					Return([]volumes.Volume{}, nil)
+
+				expectCleanupDefaultPort(r.network)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Boot from existing volume in another AZ without cross-AZ attach",
+			getInstanceSpec: func() *InstanceSpec {
+				s := getDefaultInstanceSpec()
+				s.RootVolume = &infrav1.RootVolume{
+					Size: 50,
+				}
+				return s
+			},
+			expect: func(r *recorders) {
+				expectUseExistingDefaultPort(r.network)
+				expectDefaultImageAndFlavor(r.compute, r.image)
+
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
+					Return([]volumes.Volume{{ID: volumeUUID, Size: 50, AvailabilityZone: "test-alternate-az"}}, nil)
+
+				expectCleanupDefaultPort(r.network)
+			},
+			wantErr: true,
+		},
 		{
 			name: "Boot from volume failure cleans up ports",
 			getInstanceSpec: func() *InstanceSpec {
@@ -914,15 +1562,16 @@ func TestService_ReconcileInstance(t *testing.T) {
 				expectUseExistingDefaultPort(r.network)
 				expectDefaultImageAndFlavor(r.compute, r.image)
 
-				r.volume.ListVolumes(volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: fmt.Sprintf("%s-root", openStackMachineName)}).
 					Return([]volumes.Volume{}, nil)
-				r.volume.CreateVolume(volumes.CreateOpts{
+				r.volume.CreateVolume(gomock.Any(), volumes.CreateOpts{
 					Size:             50,
 					AvailabilityZone: failureDomain,
 					Description:      fmt.Sprintf("Root volume for %s", openStackMachineName),
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
 					ImageID:          imageUUID,
 					Multiattach:      false,
+					Metadata:         map[string]string{"tag:test-tag": "true", "capo-delete-on-termination": "true"},
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePoll(r.volume, []string{"creating", "error"})
 
@@ -945,12 +1594,12 @@ func TestService_ReconcileInstance(t *testing.T) {
 				extensions := []extensions.Extension{
 					{Extension: common.Extension{Alias: "trunk"}},
 				}
-				r.network.ListExtensions().Return(extensions, nil)
+				r.network.ListExtensions(gomock.Any()).Return(extensions, nil)
 
 				expectCreatePort(r.network, portName, networkUUID)
 
 				// Check for existing trunk
-				r.network.ListTrunk(newGomegaMockMatcher(
+				r.network.ListTrunk(gomock.Any(), newGomegaMockMatcher(
 					MatchFields(IgnoreExtras, Fields{
 						"Name":   Equal(portName),
 						"PortID": Equal(portUUID),
@@ -958,32 +1607,32 @@ func TestService_ReconcileInstance(t *testing.T) {
 				)).Return([]trunks.Trunk{}, nil)
 
 				// Create new trunk
-				r.network.CreateTrunk(newGomegaMockMatcher(MatchFields(IgnoreExtras, Fields{
+				r.network.CreateTrunk(gomock.Any(), newGomegaMockMatcher(MatchFields(IgnoreExtras, Fields{
 					"Name":   Equal(portName),
 					"PortID": Equal(portUUID),
 				}))).Return(&trunks.Trunk{
 					PortID: portUUID,
 					ID:     trunkUUID,
 				}, nil)
-				r.network.ReplaceAllAttributesTags("trunks", trunkUUID, attributestags.ReplaceAllOpts{Tags: []string{"test-tag"}}).Return(nil, nil)
+				r.network.ReplaceAllAttributesTags(gomock.Any(), "trunks", trunkUUID, attributestags.ReplaceAllOpts{Tags: []string{"test-tag"}}).Return(nil, nil)
 
 				// Looking up the second port fails
-				r.network.ListPort(ports.ListOpts{
+				r.network.ListPort(gomock.Any(), ports.ListOpts{
 					Name:      "test-openstack-machine-1",
 					NetworkID: networkUUID,
 				}).Return(nil, fmt.Errorf("test error"))
 
-				r.network.ListExtensions().Return(extensions, nil)
+				r.network.ListExtensions(gomock.Any()).Return(extensions, nil)
 
-				r.network.ListTrunk(newGomegaMockMatcher(
+				r.network.ListTrunk(gomock.Any(), newGomegaMockMatcher(
 					MatchFields(IgnoreExtras, Fields{
 						"PortID": Equal(portUUID),
 					}),
 				)).Return([]trunks.Trunk{{ID: trunkUUID}}, nil)
 
 				// We should cleanup the first port and its trunk
-				r.network.DeleteTrunk(trunkUUID).Return(nil)
-				r.network.DeletePort(portUUID).Return(nil)
+				r.network.DeleteTrunk(gomock.Any(), trunkUUID).Return(nil)
+				r.network.DeletePort(gomock.Any(), portUUID).Return(nil)
 			},
 			wantErr: true,
 		},
@@ -1053,6 +1702,7 @@ func TestService_DeleteInstance(t *testing.T) {
 		eventObject    runtime.Object
 		instanceStatus func() *InstanceStatus
 		rootVolume     *infrav1.RootVolume
+		devices        []infrav1.AdditionalBlockDevice
 		expect         func(r *recorders)
 		wantErr        bool
 	}{
@@ -1061,23 +1711,24 @@ func TestService_DeleteInstance(t *testing.T) {
 			eventObject:    &infrav1.OpenStackMachine{},
 			instanceStatus: getDefaultInstanceStatus,
 			expect: func(r *recorders) {
-				r.compute.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{
+				r.compute.ListAttachedInterfaces(gomock.Any(), instanceUUID).Return([]attachinterfaces.Interface{
 					{
 						PortID: portUUID,
 					},
 				}, nil)
-				r.network.ListExtensions().Return([]extensions.Extension{{
+				r.network.ListExtensions(gomock.Any()).Return([]extensions.Extension{{
 					Extension: common.Extension{
 						Alias: "trunk",
 					},
 				}}, nil)
-				r.compute.DeleteAttachedInterface(instanceUUID, portUUID).Return(nil)
+				r.compute.DeleteAttachedInterface(gomock.Any(), instanceUUID, portUUID).Return(nil)
 				// FIXME: Why we are looking for a trunk when we know the port is not trunked?
-				r.network.ListTrunk(trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{}, nil)
-				r.network.DeletePort(portUUID).Return(nil)
+				r.network.ListTrunk(gomock.Any(), trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{}, nil)
+				r.network.DeletePort(gomock.Any(), portUUID).Return(nil)
 
-				r.compute.DeleteServer(instanceUUID).Return(nil)
-				r.compute.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{}, nil)
+				r.compute.DeleteServer(gomock.Any(), instanceUUID).Return(nil)
+				r.compute.GetServer(gomock.Any(), instanceUUID).Return(nil, gophercloud.ErrDefault404{})
 			},
 			wantErr: false,
 		},
@@ -1091,7 +1742,7 @@ func TestService_DeleteInstance(t *testing.T) {
 			expect: func(r *recorders) {
 				// Fetch volume by name
 				volumeName := fmt.Sprintf("%s-root", openStackMachineName)
-				r.volume.ListVolumes(volumes.ListOpts{
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{
 					AllTenants: false,
 					Name:       volumeName,
 					TenantID:   "",
@@ -1101,7 +1752,29 @@ func TestService_DeleteInstance(t *testing.T) {
 				}}, nil)
 
 				// Delete volume
-				r.volume.DeleteVolume(volumeUUID, volumes.DeleteOpts{}).Return(nil)
+				r.volume.DeleteVolume(gomock.Any(), volumeUUID, volumes.DeleteOpts{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "Retained volumes are not deleted",
+			eventObject:    &infrav1.OpenStackMachine{},
+			instanceStatus: func() *InstanceStatus { return nil },
+			rootVolume: &infrav1.RootVolume{
+				Size:            50,
+				RetentionPolicy: infrav1.VolumeRetentionPolicyRetain,
+			},
+			devices: []infrav1.AdditionalBlockDevice{
+				{Name: "cache", Size: 100, RetentionPolicy: infrav1.VolumeRetentionPolicyRetain, RetainedVolumeName: "runner-cache"},
+				{Name: "data", Size: 10},
+			},
+			expect: func(r *recorders) {
+				volumeName := fmt.Sprintf("%s-data", openStackMachineName)
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: volumeName}).Return([]volumes.Volume{{
+					ID:   volumeUUID,
+					Name: volumeName,
+				}}, nil)
+				r.volume.DeleteVolume(gomock.Any(), volumeUUID, volumes.DeleteOpts{}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -1130,9 +1803,91 @@ func TestService_DeleteInstance(t *testing.T) {
 				),
 				_volumeClient: mockVolumeClient,
 			}
-			if err := s.DeleteInstance(tt.eventObject, tt.instanceStatus(), openStackMachineName, tt.rootVolume); (err != nil) != tt.wantErr {
+			if err := s.DeleteInstance(tt.eventObject, tt.instanceStatus(), openStackMachineName, tt.rootVolume, tt.devices, nil); (err != nil) != tt.wantErr {
 				t.Errorf("Service.DeleteInstance() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestService_GetInstancePlacement(t *testing.T) {
+	instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{
+		Server:                    servers.Server{ID: instanceUUID, HostID: "host-id"},
+		ServerAvailabilityZoneExt: availabilityzones.ServerAvailabilityZoneExt{AvailabilityZone: "nova"},
+		ServerAttributesExt:       extendedserverattributes.ServerAttributesExt{Host: "compute-1", HypervisorHostname: "compute-1.example.com"},
+	}, logr.Discard())
+	placement := infrav1.InstancePlacement{
+		AvailabilityZone:   "nova",
+		HostID:             "host-id",
+		Host:               "compute-1",
+		HypervisorHostname: "compute-1.example.com",
+	}
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockComputeClientMockRecorder)
+		want    func() *infrav1.InstancePlacement
+		wantErr bool
+	}{
+		{
+			name: "Server groups are listed if Nova supports them",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetMaxMicroversion(gomock.Any()).Return("2.90", nil)
+				m.ListServerGroupsOfServer(gomock.Any(), instanceUUID).Return([]string{serverGroupUUID}, nil)
+			},
+			want: func() *infrav1.InstancePlacement {
+				p := placement
+				p.ServerGroups = []string{serverGroupUUID}
+				return &p
+			},
+		},
+		{
+			name: "Server groups are not listed by older Nova versions",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetMaxMicroversion(gomock.Any()).Return("2.60", nil)
+			},
+			want: func() *infrav1.InstancePlacement {
+				p := placement
+				return &p
+			},
+		},
+		{
+			name: "Listing server groups fails",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetMaxMicroversion(gomock.Any()).Return("2.71", nil)
+				m.ListServerGroupsOfServer(gomock.Any(), instanceUUID).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Getting the maximum microversion fails",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetMaxMicroversion(gomock.Any()).Return("", fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.GetInstancePlacement(instanceStatus)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want()))
+		})
+	}
+}
@@ -19,6 +19,7 @@ package compute
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"sort"
 
 	"github.com/go-logr/logr"
@@ -33,23 +34,40 @@ import (
 // InstanceSpec does not contain all of the fields of infrav1.Instance, as not
 // all of them can be set on a new instance.
 type InstanceSpec struct {
-	Name           string
-	Image          string
-	ImageUUID      string
-	Flavor         string
-	SSHKeyName     string
-	UserData       string
-	Metadata       map[string]string
-	ConfigDrive    bool
-	FailureDomain  string
-	RootVolume     *infrav1.RootVolume
-	Subnet         string
-	ServerGroupID  string
-	Trunk          bool
-	Tags           []string
-	SecurityGroups []infrav1.SecurityGroupParam
-	Networks       []infrav1.NetworkParam
-	Ports          []infrav1.PortOpts
+	Name                   string
+	Hostname               string
+	Image                  string
+	ImageUUID              string
+	ImageChecksum          string
+	ImageFilter            *infrav1.ImageFilter
+	Flavor                 string
+	FlavorID               string
+	SSHKeyName             string
+	UserData               string
+	BootstrapFormat        infrav1.BootstrapFormat
+	IgnitionSwiftContainer string
+	ConfigureSecondaryNICs bool
+	Metadata               map[string]string
+	ConfigDrive            bool
+	FailureDomain          string
+	RootVolume             *infrav1.RootVolume
+	AdditionalBlockDevices []infrav1.AdditionalBlockDevice
+	Subnet                 string
+	ManagedSubnet          *infrav1.ManagedSubnetSelector
+	ServerGroupID          string
+	SchedulerHints         []infrav1.SchedulerHintAdditionalProperty
+	Traits                 *infrav1.Traits
+	Reservation            *infrav1.Reservation
+	Trunk                  bool
+	Tags                   []string
+	SecurityGroups         []infrav1.SecurityGroupParam
+	Networks               []infrav1.NetworkParam
+	Ports                  []infrav1.PortOpts
+	// APIServerVIP is the managed VIP of the API server, which is added to the allowed address
+	// pairs of the ports of the instance on the cluster network.
+	APIServerVIP string
+	// ClusterName identifies the cluster of the instance in the metadata of its volumes.
+	ClusterName string
 }
 
 // InstanceIdentifier describes an instance which has not necessarily been fetched.
@@ -93,6 +111,10 @@ func (is *InstanceStatus) State() infrav1.InstanceState {
 	return infrav1.InstanceState(is.server.Status)
 }
 
+func (is *InstanceStatus) Metadata() map[string]string {
+	return is.server.Metadata
+}
+
 func (is *InstanceStatus) SSHKeyName() string {
 	return is.server.KeyName
 }
@@ -101,6 +123,24 @@ func (is *InstanceStatus) AvailabilityZone() string {
 	return is.server.AvailabilityZone
 }
 
+// HostID returns the obfuscated ID of the host of the instance, which is
+// unique per project.
+func (is *InstanceStatus) HostID() string {
+	return is.server.HostID
+}
+
+// Host returns the name of the compute host of the instance. It is empty
+// unless the server was read with admin rights.
+func (is *InstanceStatus) Host() string {
+	return is.server.Host
+}
+
+// HypervisorHostname returns the hostname of the hypervisor of the instance.
+// It is empty unless the server was read with admin rights.
+func (is *InstanceStatus) HypervisorHostname() string {
+	return is.server.HypervisorHostname
+}
+
 // APIInstance returns an infrav1.Instance object for use by the API.
 func (is *InstanceStatus) APIInstance(openStackCluster *infrav1.OpenStackCluster) (*infrav1.Instance, error) {
 	i := infrav1.Instance{
@@ -152,13 +192,20 @@ func (is *InstanceStatus) NetworkStatus() (*InstanceNetworkStatus, error) {
 			return nil, fmt.Errorf("error unmarshalling addresses for instance %s: %w", is.ID(), err)
 		}
 
-		var addresses []corev1.NodeAddress
+		var addresses, ipv6Addresses []corev1.NodeAddress
 		for i := range interfaceList {
 			address := &interfaceList[i]
 
-			// Only consider IPv4
-			if address.Version != 4 {
-				is.logger.V(6).Info("Ignoring IP address: only IPv4 is supported", "version", address.Version, "address", address.Address)
+			// Only consider IPv4 and IPv6 addresses which are reachable from other networks
+			switch address.Version {
+			case 4:
+			case 6:
+				if ip := net.ParseIP(address.Address); ip == nil || ip.IsLinkLocalUnicast() {
+					is.logger.V(6).Info("Ignoring link-local IPv6 address", "address", address.Address)
+					continue
+				}
+			default:
+				is.logger.V(6).Info("Ignoring IP address with unknown version", "version", address.Version, "address", address.Address)
 				continue
 			}
 
@@ -173,26 +220,33 @@ func (is *InstanceStatus) NetworkStatus() (*InstanceNetworkStatus, error) {
 				continue
 			}
 
-			addresses = append(addresses, corev1.NodeAddress{
+			nodeAddress := corev1.NodeAddress{
 				Type:    addressType,
 				Address: address.Address,
-			})
+			}
+			if address.Version == 6 {
+				ipv6Addresses = append(ipv6Addresses, nodeAddress)
+			} else {
+				addresses = append(addresses, nodeAddress)
+			}
 		}
 
-		addressesByNetwork[networkName] = addresses
+		// IPv4 addresses are listed first, so they are preferred in dual-stack networks
+		addressesByNetwork[networkName] = append(addresses, ipv6Addresses...)
 	}
 
 	return &InstanceNetworkStatus{addressesByNetwork}, nil
 }
 
 // Addresses returns a list of NodeAddresses containing all addresses which will
-// be reported on the OpenStackMachine object.
-func (ns *InstanceNetworkStatus) Addresses() []corev1.NodeAddress {
+// be reported on the OpenStackMachine object. The addresses of the networks in
+// networkOrder are listed first, in this order.
+func (ns *InstanceNetworkStatus) Addresses(networkOrder ...string) []corev1.NodeAddress {
 	// We want the returned order of addresses to be deterministic to make
 	// it easy to detect changes and avoid unnecessary updates. Iteration
 	// over maps is non-deterministic, so we explicitly iterate over the
 	// address map in lexical order of network names. This order is
-	// arbitrary.
+	// arbitrary unless it is given by networkOrder.
 	// Pull out addresses map keys (network names) and sort them lexically
 	networks := make([]string, 0, len(ns.addresses))
 	for network := range ns.addresses {
@@ -200,6 +254,21 @@ func (ns *InstanceNetworkStatus) Addresses() []corev1.NodeAddress {
 	}
 	sort.Strings(networks)
 
+	rank := make(map[string]int, len(networkOrder))
+	for i, network := range networkOrder {
+		if _, ok := rank[network]; !ok {
+			rank[network] = i
+		}
+	}
+	sort.SliceStable(networks, func(i, j int) bool {
+		ri, oki := rank[networks[i]]
+		rj, okj := rank[networks[j]]
+		if oki && okj {
+			return ri < rj
+		}
+		return oki && !okj
+	})
+
 	var addresses []corev1.NodeAddress
 	for _, network := range networks {
 		addressList := ns.addresses[network]
@@ -209,6 +278,16 @@ func (ns *InstanceNetworkStatus) Addresses() []corev1.NodeAddress {
 	return addresses
 }
 
+// PreferredNetwork returns the first network of networkOrder the instance has an address on, or an empty string.
+func (ns *InstanceNetworkStatus) PreferredNetwork(networkOrder []string) string {
+	for _, network := range networkOrder {
+		if len(ns.addresses[network]) > 0 {
+			return network
+		}
+	}
+	return ""
+}
+
 func (ns *InstanceNetworkStatus) firstAddressByNetworkAndType(networkName string, addressType corev1.NodeAddressType) string {
 	if addressList, ok := ns.addresses[networkName]; ok {
 		for i := range addressList {
@@ -226,6 +305,16 @@ func (ns *InstanceNetworkStatus) IP(networkName string) string {
 	return ns.firstAddressByNetworkAndType(networkName, corev1.NodeInternalIP)
 }
 
+// IPv6 returns the first listed IPv6 ip of an instance for the given network name.
+func (ns *InstanceNetworkStatus) IPv6(networkName string) string {
+	for _, address := range ns.addresses[networkName] {
+		if ip := net.ParseIP(address.Address); address.Type == corev1.NodeInternalIP && ip != nil && ip.To4() == nil {
+			return address.Address
+		}
+	}
+	return ""
+}
+
 // FloatingIP returns the first listed floating ip of an instance for the given
 // network name.
 func (ns *InstanceNetworkStatus) FloatingIP(networkName string) string {
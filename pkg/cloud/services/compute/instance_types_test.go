@@ -56,9 +56,10 @@ func serverWithAddresses(addresses map[string][]networkAddress) *clients.ServerE
 
 func TestNetworkStatus_Addresses(t *testing.T) {
 	tests := []struct {
-		name      string
-		addresses map[string][]networkAddress
-		want      []corev1.NodeAddress
+		name         string
+		addresses    map[string][]networkAddress
+		networkOrder []string
+		want         []corev1.NodeAddress
 	}{
 		{
 			name: "Single network single address",
@@ -135,6 +136,41 @@ func TestNetworkStatus_Addresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Dual-stack addresses",
+			addresses: map[string][]networkAddress{
+				"primary": {
+					{
+						Version: 6,
+						Addr:    "2001:db8::f816:3eff:fe56:3174",
+						Type:    "fixed",
+						MacAddr: macAddr1,
+					}, {
+						Version: 4,
+						Addr:    "192.168.0.1",
+						Type:    "fixed",
+						MacAddr: macAddr1,
+					}, {
+						Version: 4,
+						Addr:    "10.0.0.1",
+						Type:    "floating",
+						MacAddr: macAddr2,
+					},
+				},
+			},
+			want: []corev1.NodeAddress{
+				{
+					Type:    corev1.NodeInternalIP,
+					Address: "192.168.0.1",
+				}, {
+					Type:    corev1.NodeExternalIP,
+					Address: "10.0.0.1",
+				}, {
+					Type:    corev1.NodeInternalIP,
+					Address: "2001:db8::f816:3eff:fe56:3174",
+				},
+			},
+		},
 		{
 			name: "Multiple networks",
 			addresses: map[string][]networkAddress{
@@ -184,6 +220,56 @@ func TestNetworkStatus_Addresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Multiple networks in address sort order",
+			addresses: map[string][]networkAddress{
+				"primary": {
+					{
+						Version: 4,
+						Addr:    "192.168.0.1",
+						Type:    "fixed",
+						MacAddr: macAddr1,
+					}, {
+						Version: 4,
+						Addr:    "10.0.0.1",
+						Type:    "floating",
+						MacAddr: macAddr2,
+					},
+				},
+				"extraNet1": {
+					{
+						Version: 4,
+						Addr:    "192.168.1.1",
+						Type:    "fixed",
+						MacAddr: macAddr3,
+					},
+				},
+				"extraNet2": {
+					{
+						Version: 4,
+						Addr:    "192.168.2.1",
+						Type:    "fixed",
+						MacAddr: macAddr4,
+					},
+				},
+			},
+			networkOrder: []string{"primary", "missing", "extraNet2"},
+			want: []corev1.NodeAddress{
+				{
+					Type:    corev1.NodeInternalIP,
+					Address: "192.168.0.1",
+				}, {
+					Type:    corev1.NodeExternalIP,
+					Address: "10.0.0.1",
+				}, {
+					Type:    corev1.NodeInternalIP,
+					Address: "192.168.2.1",
+				}, {
+					Type:    corev1.NodeInternalIP,
+					Address: "192.168.1.1",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -196,7 +282,7 @@ func TestNetworkStatus_Addresses(t *testing.T) {
 			instanceNS, err := is.NetworkStatus()
 			g.Expect(err).NotTo(HaveOccurred())
 
-			got := instanceNS.Addresses()
+			got := instanceNS.Addresses(tt.networkOrder...)
 			g.Expect(got).To(Equal(tt.want))
 		})
 	}
@@ -208,6 +294,7 @@ func TestInstanceNetworkStatus(t *testing.T) {
 		addresses      map[string][]networkAddress
 		networkName    string
 		wantIP         string
+		wantIPv6       string
 		wantFloatingIP string
 	}{
 		{
@@ -377,6 +464,27 @@ func TestInstanceNetworkStatus(t *testing.T) {
 			wantIP:         "192.168.0.1",
 			wantFloatingIP: "10.0.0.1",
 		},
+		{
+			name: "Dual-stack addresses",
+			addresses: map[string][]networkAddress{
+				"primary": {
+					{
+						Version: 6,
+						Addr:    "2001:db8::f816:3eff:fe56:3174",
+						Type:    "fixed",
+						MacAddr: macAddr1,
+					}, {
+						Version: 4,
+						Addr:    "192.168.0.1",
+						Type:    "fixed",
+						MacAddr: macAddr1,
+					},
+				},
+			},
+			networkName: "primary",
+			wantIP:      "192.168.0.1",
+			wantIPv6:    "2001:db8::f816:3eff:fe56:3174",
+		},
 		{
 			name: "Network not found",
 			addresses: map[string][]networkAddress{
@@ -414,6 +522,9 @@ func TestInstanceNetworkStatus(t *testing.T) {
 			ip := ns.IP(tt.networkName)
 			g.Expect(ip).To(Equal(tt.wantIP))
 
+			ipv6 := ns.IPv6(tt.networkName)
+			g.Expect(ipv6).To(Equal(tt.wantIPv6))
+
 			floatingIP := ns.FloatingIP(tt.networkName)
 			g.Expect(floatingIP).To(Equal(tt.wantFloatingIP))
 		})
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+)
+
+// capoInstanceActions are the instance actions CAPO performs on the servers of machines.
+var capoInstanceActions = map[string]bool{
+	"create": true,
+	"delete": true,
+}
+
+// GetOutOfBandInstanceActions returns the actions on the instance which started after since and were not
+// performed by CAPO, ordered by their start time, together with the start time of the latest action which
+// started after since. All actions are considered if since is nil. Start times are truncated to seconds,
+// the precision they are recorded with in the status of a machine.
+func (s *Service) GetOutOfBandInstanceActions(instanceID string, since *time.Time) ([]instanceactions.InstanceAction, *time.Time, error) {
+	actions, err := s.getComputeClient().ListInstanceActions(s.scope.Context(), instanceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing actions of instance %s: %w", instanceID, err)
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].StartTime.Before(actions[j].StartTime)
+	})
+
+	var latest *time.Time
+	var outOfBand []instanceactions.InstanceAction
+	for i := range actions {
+		startTime := actions[i].StartTime.Truncate(time.Second)
+		if since != nil && !startTime.After(*since) {
+			continue
+		}
+		latest = &startTime
+		if capoInstanceActions[actions[i].Action] {
+			continue
+		}
+		outOfBand = append(outOfBand, actions[i])
+	}
+	return outOfBand, latest, nil
+}
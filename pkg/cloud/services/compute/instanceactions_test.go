@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_GetOutOfBandInstanceActions(t *testing.T) {
+	created := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+	stopped := created.Add(time.Hour + 500*time.Millisecond)
+	migrated := created.Add(2 * time.Hour)
+	actions := []instanceactions.InstanceAction{
+		{Action: "migrate", RequestID: "req-migrate", StartTime: migrated},
+		{Action: "create", RequestID: "req-create", StartTime: created},
+		{Action: "stop", RequestID: "req-stop", StartTime: stopped},
+	}
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name       string
+		since      *time.Time
+		listErr    error
+		want       []string
+		wantLatest *time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "All actions",
+			want:       []string{"req-stop", "req-migrate"},
+			wantLatest: timePtr(migrated),
+		},
+		{
+			name:       "Actions since the last check",
+			since:      timePtr(stopped.Truncate(time.Second)),
+			want:       []string{"req-migrate"},
+			wantLatest: timePtr(migrated),
+		},
+		{
+			name:  "No new actions",
+			since: timePtr(migrated),
+		},
+		{
+			name:    "Listing actions fails",
+			listErr: fmt.Errorf("test error"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			if tt.listErr != nil {
+				mockComputeClient.EXPECT().ListInstanceActions(gomock.Any(), instanceUUID).Return(nil, tt.listErr)
+			} else {
+				// The client returns a new slice on every call
+				mockComputeClient.EXPECT().ListInstanceActions(gomock.Any(), instanceUUID).Return(append([]instanceactions.InstanceAction{}, actions...), nil)
+			}
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, latest, err := s.GetOutOfBandInstanceActions(instanceUUID, tt.since)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			var gotIDs []string
+			for _, action := range got {
+				gotIDs = append(gotIDs, action.RequestID)
+			}
+			g.Expect(gotIDs).To(Equal(tt.want))
+			g.Expect(latest).To(Equal(tt.wantLatest))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// KeyPairExists returns whether a keypair with the given name exists.
+func (s *Service) KeyPairExists(name string) (bool, error) {
+	_, err := s.getComputeClient().GetKeyPair(s.scope.Context(), name)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error getting keypair %s: %v", name, err)
+	}
+	return true, nil
+}
+
+// ImportKeyPair imports the given public key as a keypair with the given name.
+func (s *Service) ImportKeyPair(eventObject runtime.Object, name, publicKey string) error {
+	createOpts := keypairs.CreateOpts{
+		Name:      name,
+		PublicKey: publicKey,
+	}
+	if _, err := s.getComputeClient().CreateKeyPair(s.scope.Context(), createOpts); err != nil {
+		record.Warnf(eventObject, "FailedImportKeyPair", "Failed to import keypair %s: %v", name, err)
+		return err
+	}
+
+	record.Eventf(eventObject, "SuccessfulImportKeyPair", "Imported keypair %s", name)
+	return nil
+}
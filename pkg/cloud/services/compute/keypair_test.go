@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_KeyPairExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockComputeClientMockRecorder)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Keypair exists",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetKeyPair(gomock.Any(), sshKeyName).Return(&keypairs.KeyPair{Name: sshKeyName}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "Keypair does not exist",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetKeyPair(gomock.Any(), sshKeyName).Return(nil, gophercloud.ErrDefault404{})
+			},
+			want: false,
+		},
+		{
+			name: "OpenStack returns error",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetKeyPair(gomock.Any(), sshKeyName).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.KeyPairExists(sshKeyName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_ImportKeyPair(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+	mockComputeClient.EXPECT().CreateKeyPair(gomock.Any(), keypairs.CreateOpts{
+		Name:      sshKeyName,
+		PublicKey: "ssh-ed25519 AAAA test",
+	}).Return(&keypairs.KeyPair{Name: sshKeyName}, nil)
+
+	s := Service{
+		scope: &scope.Scope{
+			Logger: logr.Discard(),
+		},
+		_computeClient: mockComputeClient,
+	}
+
+	g.Expect(s.ImportKeyPair(&infrav1.OpenStackMachine{}, sshKeyName, "ssh-ed25519 AAAA test")).To(Succeed())
+}
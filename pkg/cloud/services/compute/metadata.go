@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// maxMetadataValueLength is the maximum length of a server metadata value accepted by Nova.
+const maxMetadataValueLength = 255
+
+// PropagatedMetadata returns the server metadata which mirrors the labels and annotations
+// selected by propagation.
+func PropagatedMetadata(propagation *infrav1.MachineMetadataPropagation, labels, annotations map[string]string) map[string]string {
+	metadata := map[string]string{}
+	if propagation == nil {
+		return metadata
+	}
+	for _, key := range propagation.Labels {
+		if v, ok := labels[key]; ok {
+			metadata[PropagatedMetadataKey(infrav1.MachineLabelMetadataPrefix, key)] = v
+		}
+	}
+	for _, key := range propagation.Annotations {
+		if v, ok := annotations[key]; ok && len(v) <= maxMetadataValueLength {
+			metadata[PropagatedMetadataKey(infrav1.MachineAnnotationMetadataPrefix, key)] = v
+		}
+	}
+	return metadata
+}
+
+// PropagatedMetadataKey returns the server metadata key of a mirrored label or annotation. Nova
+// does not allow "/" in metadata keys, so it is replaced by ":".
+func PropagatedMetadataKey(prefix, key string) string {
+	return prefix + strings.ReplaceAll(key, "/", ":")
+}
+
+func isPropagatedMetadataKey(key string) bool {
+	return strings.HasPrefix(key, infrav1.MachineLabelMetadataPrefix) || strings.HasPrefix(key, infrav1.MachineAnnotationMetadataPrefix)
+}
+
+// ReconcileServerMetadata sets the given metadata on an existing server. Keys which are not given
+// are left untouched, as they may have been set by other tools, except for mirrored labels and
+// annotations, which are removed once they are no longer mirrored.
+func (s *Service) ReconcileServerMetadata(eventObject runtime.Object, instanceStatus *InstanceStatus, metadata map[string]string) error {
+	current := instanceStatus.Metadata()
+	changed := servers.MetadataOpts{}
+	for k, v := range metadata {
+		if cur, ok := current[k]; !ok || cur != v {
+			changed[k] = v
+		}
+	}
+	var stale []string
+	for k := range current {
+		if _, ok := metadata[k]; !ok && isPropagatedMetadataKey(k) {
+			stale = append(stale, k)
+		}
+	}
+	sort.Strings(stale)
+
+	if len(changed) > 0 {
+		if err := s.updateServerMetadata(eventObject, instanceStatus, changed); err != nil {
+			return err
+		}
+	}
+	for _, k := range stale {
+		if err := s.deleteServerMetadatum(eventObject, instanceStatus, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) updateServerMetadata(eventObject runtime.Object, instanceStatus *InstanceStatus, changed servers.MetadataOpts) error {
+
+	keys := make([]string, 0, len(changed))
+	for k := range changed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s.scope.Logger.Info("Updating server metadata", "id", instanceStatus.ID(), "keys", keys)
+
+	updated, err := s.getComputeClient().UpdateServerMetadata(s.scope.Context(), instanceStatus.ID(), changed)
+	if err != nil {
+		record.Warnf(eventObject, "FailedUpdateServerMetadata", "Failed to update metadata %v of server %s with id %s: %v", keys, instanceStatus.Name(), instanceStatus.ID(), err)
+		return err
+	}
+	instanceStatus.server.Metadata = updated
+
+	record.Eventf(eventObject, "SuccessfulUpdateServerMetadata", "Updated metadata %v of server %s with id %s", keys, instanceStatus.Name(), instanceStatus.ID())
+	return nil
+}
+
+func (s *Service) deleteServerMetadatum(eventObject runtime.Object, instanceStatus *InstanceStatus, key string) error {
+	s.scope.Logger.Info("Deleting server metadata", "id", instanceStatus.ID(), "key", key)
+
+	err := s.getComputeClient().DeleteServerMetadatum(s.scope.Context(), instanceStatus.ID(), key)
+	if err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(eventObject, "FailedDeleteServerMetadata", "Failed to delete metadata %s of server %s with id %s: %v", key, instanceStatus.Name(), instanceStatus.ID(), err)
+		return err
+	}
+	delete(instanceStatus.server.Metadata, key)
+
+	record.Eventf(eventObject, "SuccessfulDeleteServerMetadata", "Deleted metadata %s of server %s with id %s", key, instanceStatus.Name(), instanceStatus.ID())
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_ReconcileServerMetadata(t *testing.T) {
+	current := map[string]string{
+		"cost-center": "1234",
+		"owner":       "team-a",
+		"external":    "set-by-user",
+		"k8s-label:node-role.kubernetes.io:worker": "",
+	}
+
+	tests := []struct {
+		name       string
+		metadata   map[string]string
+		wantAbsent []string
+		expect     func(m *mock.MockComputeClientMockRecorder)
+	}{
+		{
+			name:     "metadata is up to date",
+			metadata: map[string]string{"cost-center": "1234", "owner": "team-a", "k8s-label:node-role.kubernetes.io:worker": ""},
+			expect:   func(m *mock.MockComputeClientMockRecorder) {},
+		},
+		{
+			name:     "changed and added keys are updated",
+			metadata: map[string]string{"cost-center": "5678", "owner": "team-a", "env": "prod", "k8s-label:node-role.kubernetes.io:worker": ""},
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.UpdateServerMetadata(gomock.Any(), instanceUUID, servers.MetadataOpts{"cost-center": "5678", "env": "prod"}).
+					Return(map[string]string{"cost-center": "5678", "owner": "team-a", "external": "set-by-user", "env": "prod", "k8s-label:node-role.kubernetes.io:worker": ""}, nil)
+			},
+		},
+		{
+			name:       "labels which are no longer mirrored are deleted",
+			metadata:   map[string]string{"cost-center": "1234", "owner": "team-a"},
+			wantAbsent: []string{"k8s-label:node-role.kubernetes.io:worker"},
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.DeleteServerMetadatum(gomock.Any(), instanceUUID, "k8s-label:node-role.kubernetes.io:worker").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: mockComputeClient,
+			}
+
+			server := &clients.ServerExt{}
+			server.ID = instanceUUID
+			server.Name = openStackMachineName
+			server.Metadata = make(map[string]string, len(current))
+			for k, v := range current {
+				server.Metadata[k] = v
+			}
+			instanceStatus := NewInstanceStatusFromServer(server, logr.Discard())
+
+			g.Expect(s.ReconcileServerMetadata(&infrav1.OpenStackMachine{}, instanceStatus, tt.metadata)).To(Succeed())
+			for k, v := range tt.metadata {
+				g.Expect(instanceStatus.Metadata()).To(HaveKeyWithValue(k, v))
+			}
+			for _, k := range tt.wantAbsent {
+				g.Expect(instanceStatus.Metadata()).NotTo(HaveKey(k))
+			}
+			g.Expect(instanceStatus.Metadata()).To(HaveKeyWithValue("external", "set-by-user"))
+		})
+	}
+}
+
+func TestPropagatedMetadata(t *testing.T) {
+	labels := map[string]string{
+		"node-role.kubernetes.io/worker": "",
+		"topology.example.com/rack":      "r12",
+		"unselected":                     "value",
+	}
+	annotations := map[string]string{
+		"example.com/owner": "team-a",
+		"example.com/long":  strings.Repeat("x", 256),
+	}
+
+	tests := []struct {
+		name        string
+		propagation *infrav1.MachineMetadataPropagation
+		want        map[string]string
+	}{
+		{
+			name: "nothing is mirrored by default",
+			want: map[string]string{},
+		},
+		{
+			name: "selected labels and annotations are mirrored",
+			propagation: &infrav1.MachineMetadataPropagation{
+				Labels:      []string{"node-role.kubernetes.io/worker", "topology.example.com/rack", "missing"},
+				Annotations: []string{"example.com/owner"},
+			},
+			want: map[string]string{
+				"k8s-label:node-role.kubernetes.io:worker": "",
+				"k8s-label:topology.example.com:rack":      "r12",
+				"k8s-annotation:example.com:owner":         "team-a",
+			},
+		},
+		{
+			name: "annotations with values too long for Nova are not mirrored",
+			propagation: &infrav1.MachineMetadataPropagation{
+				Annotations: []string{"example.com/long"},
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(PropagatedMetadata(tt.propagation, labels, annotations)).To(Equal(tt.want))
+		})
+	}
+}
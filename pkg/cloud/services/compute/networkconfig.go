@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/textproto"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+const (
+	// secondaryInterfacesNetplanPath is where the network config of the secondary interfaces is written.
+	secondaryInterfacesNetplanPath = "/etc/netplan/60-capo-secondary-interfaces.yaml"
+
+	// userDataBoundary separates the parts of multipart user data. It is fixed, so that the user data of an
+	// instance does not change between reconciles.
+	userDataBoundary = "==CAPO-USER-DATA-BOUNDARY=="
+
+	// networkConfigMergeHow makes cloud-init run the commands of the network config before those of the
+	// bootstrap data and keep the other keys of the bootstrap data.
+	networkConfigMergeHow = "dict(no_replace,recurse_list)+list(prepend)"
+)
+
+type netplanConfig struct {
+	Network netplanNetwork `json:"network"`
+}
+
+type netplanNetwork struct {
+	Version   int                        `json:"version"`
+	Ethernets map[string]netplanEthernet `json:"ethernets"`
+}
+
+type netplanEthernet struct {
+	Match     netplanMatch `json:"match"`
+	DHCP4     bool         `json:"dhcp4"`
+	DHCP6     bool         `json:"dhcp6"`
+	Addresses []string     `json:"addresses,omitempty"`
+}
+
+type netplanMatch struct {
+	MACAddress string `json:"macaddress"`
+}
+
+type cloudConfigFile struct {
+	Path        string `json:"path"`
+	Permissions string `json:"permissions"`
+	Content     string `json:"content"`
+}
+
+type networkCloudConfig struct {
+	MergeHow   string            `json:"merge_how"`
+	WriteFiles []cloudConfigFile `json:"write_files"`
+	RunCmd     []string          `json:"runcmd"`
+}
+
+// addSecondaryInterfacesConfig adds a cloud-config to the base64 encoded user data of an instance which configures
+// the interfaces of the ports after the first one with their fixed IPs. The first port is left to the image, which
+// usually configures it with DHCP.
+func (s *Service) addSecondaryInterfacesConfig(instanceSpec *InstanceSpec, userData string, instancePorts []*ports.Port) (string, error) {
+	if !instanceSpec.ConfigureSecondaryNICs || len(instancePorts) < 2 {
+		return userData, nil
+	}
+	if instanceSpec.BootstrapFormat == infrav1.BootstrapFormatIgnition {
+		s.scope.Logger.Info("Not configuring secondary interfaces of an instance with Ignition bootstrap data")
+		return userData, nil
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return "", err
+	}
+	cidrs := make(map[string]string)
+	for _, port := range instancePorts[1:] {
+		for _, fixedIP := range port.FixedIPs {
+			if _, ok := cidrs[fixedIP.SubnetID]; ok {
+				continue
+			}
+			subnetList, err := networkingService.GetSubnetsByFilter(subnets.ListOpts{ID: fixedIP.SubnetID})
+			if err != nil {
+				return "", fmt.Errorf("error getting subnet %s of port %s: %w", fixedIP.SubnetID, port.ID, err)
+			}
+			cidrs[fixedIP.SubnetID] = subnetList[0].CIDR
+		}
+	}
+
+	netplan, err := secondaryInterfacesNetplan(instancePorts[1:], cidrs)
+	if err != nil {
+		return "", err
+	}
+	cloudConfig, err := yaml.Marshal(networkCloudConfig{
+		MergeHow: networkConfigMergeHow,
+		WriteFiles: []cloudConfigFile{{
+			Path:        secondaryInterfacesNetplanPath,
+			Permissions: "0600",
+			Content:     string(netplan),
+		}},
+		RunCmd: []string{"netplan apply"},
+	})
+	if err != nil {
+		return "", err
+	}
+	userData, err = addCloudConfigPart(userData, append([]byte("#cloud-config\n"), cloudConfig...))
+	if err != nil {
+		return "", err
+	}
+	if len(userData) > maxUserDataSize {
+		return "", fmt.Errorf("user data with network config of %d bytes exceeds the user data limit of %d bytes", len(userData), maxUserDataSize)
+	}
+	return userData, nil
+}
+
+// secondaryInterfacesNetplan renders a netplan configuration which assigns the fixed IPs of the ports to the
+// interfaces with their MAC addresses, given the CIDRs of their subnets by ID.
+func secondaryInterfacesNetplan(instancePorts []*ports.Port, cidrs map[string]string) ([]byte, error) {
+	config := netplanConfig{
+		Network: netplanNetwork{
+			Version:   2,
+			Ethernets: make(map[string]netplanEthernet),
+		},
+	}
+	for i, port := range instancePorts {
+		ethernet := netplanEthernet{
+			Match: netplanMatch{MACAddress: port.MACAddress},
+		}
+		for _, fixedIP := range port.FixedIPs {
+			_, ipNet, err := net.ParseCIDR(cidrs[fixedIP.SubnetID])
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR of subnet %s: %w", fixedIP.SubnetID, err)
+			}
+			prefixLength, _ := ipNet.Mask.Size()
+			ethernet.Addresses = append(ethernet.Addresses, fmt.Sprintf("%s/%d", fixedIP.IPAddress, prefixLength))
+		}
+		config.Network.Ethernets[fmt.Sprintf("capo%d", i+1)] = ethernet
+	}
+	return yaml.Marshal(config)
+}
+
+// addCloudConfigPart combines the base64 encoded user data and a cloud-config into base64 encoded multipart user
+// data, which cloud-init processes part by part.
+func addCloudConfigPart(userData string, cloudConfig []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return "", fmt.Errorf("error decoding user data: %v", err)
+	}
+
+	var contentType string
+	switch {
+	case bytes.HasPrefix(data, []byte("#cloud-config")):
+		contentType = "text/cloud-config"
+	case bytes.HasPrefix(data, []byte("#!")):
+		contentType = "text/x-shellscript"
+	case bytes.HasPrefix(data, []byte("## template: jinja")):
+		contentType = "text/jinja2"
+	default:
+		return "", fmt.Errorf("user data of type %q cannot be combined with the network config", strings.SplitN(string(data), "\n", 2)[0])
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n", userDataBoundary)
+	w := multipart.NewWriter(buf)
+	if err := w.SetBoundary(userDataBoundary); err != nil {
+		return "", err
+	}
+	for _, part := range []struct {
+		contentType string
+		filename    string
+		data        []byte
+	}{
+		{contentType, "bootstrap", data},
+		{"text/cloud-config", "capo-network-config", cloudConfig},
+	} {
+		partWriter, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":        {part.contentType + `; charset="utf-8"`},
+			"Content-Disposition": {fmt.Sprintf("attachment; filename=%q", part.filename)},
+		})
+		if err != nil {
+			return "", err
+		}
+		if _, err := partWriter.Write(part.data); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
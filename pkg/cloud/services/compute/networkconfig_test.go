@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+)
+
+func Test_secondaryInterfacesNetplan(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := secondaryInterfacesNetplan([]*ports.Port{
+		{
+			MACAddress: "fa:16:3e:00:00:02",
+			FixedIPs:   []ports.IP{{SubnetID: "storage-subnet", IPAddress: "10.8.0.12"}},
+		},
+		{
+			MACAddress: "fa:16:3e:00:00:03",
+			FixedIPs: []ports.IP{
+				{SubnetID: "dual-stack-v4", IPAddress: "10.9.0.12"},
+				{SubnetID: "dual-stack-v6", IPAddress: "fd00::12"},
+			},
+		},
+	}, map[string]string{
+		"storage-subnet": "10.8.0.0/24",
+		"dual-stack-v4":  "10.9.0.0/22",
+		"dual-stack-v6":  "fd00::/64",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(got)).To(Equal(`network:
+  ethernets:
+    capo1:
+      addresses:
+      - 10.8.0.12/24
+      dhcp4: false
+      dhcp6: false
+      match:
+        macaddress: fa:16:3e:00:00:02
+    capo2:
+      addresses:
+      - 10.9.0.12/22
+      - fd00::12/64
+      dhcp4: false
+      dhcp6: false
+      match:
+        macaddress: fa:16:3e:00:00:03
+  version: 2
+`))
+}
+
+func Test_addCloudConfigPart(t *testing.T) {
+	cloudConfig := "#cloud-config\nruncmd:\n- netplan apply\n"
+
+	tests := []struct {
+		name            string
+		userData        string
+		wantContentType string
+		wantErr         bool
+	}{
+		{
+			name:            "Cloud-config",
+			userData:        "#cloud-config\nruncmd:\n- kubeadm join\n",
+			wantContentType: "text/cloud-config",
+		},
+		{
+			name:            "Shell script",
+			userData:        "#!/bin/bash\nkubeadm join\n",
+			wantContentType: "text/x-shellscript",
+		},
+		{
+			name:     "Multipart user data",
+			userData: "Content-Type: multipart/mixed; boundary=\"foo\"\n",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := addCloudConfigPart(base64.StdEncoding.EncodeToString([]byte(tt.userData)), []byte(cloudConfig))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			data, err := base64.StdEncoding.DecodeString(got)
+			g.Expect(err).NotTo(HaveOccurred())
+			msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+			g.Expect(err).NotTo(HaveOccurred())
+			mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(mediaType).To(Equal("multipart/mixed"))
+
+			var contentTypes, contents []string
+			r := multipart.NewReader(msg.Body, params["boundary"])
+			for {
+				part, err := r.NextPart()
+				if err == io.EOF {
+					break
+				}
+				g.Expect(err).NotTo(HaveOccurred())
+				content, err := io.ReadAll(part)
+				g.Expect(err).NotTo(HaveOccurred())
+				contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+				contentTypes = append(contentTypes, contentType)
+				contents = append(contents, string(content))
+			}
+			g.Expect(contentTypes).To(Equal([]string{tt.wantContentType, "text/cloud-config"}))
+			g.Expect(contents).To(Equal([]string{tt.userData, cloudConfig}))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"crypto/rsa"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// GetServerPassword returns the admin password which the server posted to the metadata service,
+// decrypted with the private key of its keypair. It returns an empty string if the server has
+// not posted a password yet.
+func (s *Service) GetServerPassword(instanceStatus *InstanceStatus, privateKey *rsa.PrivateKey) (string, error) {
+	password, err := s.getComputeClient().GetServerPassword(s.scope.Context(), instanceStatus.ID(), privateKey)
+	if err != nil {
+		return "", fmt.Errorf("error getting password of server %s with id %s: %v", instanceStatus.Name(), instanceStatus.ID(), err)
+	}
+	return password, nil
+}
+
+// ClearServerPassword removes the admin password of the server from the metadata service.
+func (s *Service) ClearServerPassword(eventObject runtime.Object, instanceStatus *InstanceStatus) error {
+	if err := s.getComputeClient().ClearServerPassword(s.scope.Context(), instanceStatus.ID()); err != nil {
+		record.Warnf(eventObject, "FailedClearServerPassword", "Failed to clear password of server %s with id %s: %v", instanceStatus.Name(), instanceStatus.ID(), err)
+		return err
+	}
+
+	record.Eventf(eventObject, "SuccessfulClearServerPassword", "Cleared password of server %s with id %s", instanceStatus.Name(), instanceStatus.ID())
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_GetServerPassword(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockComputeClientMockRecorder)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Password is posted",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerPassword(gomock.Any(), instanceUUID, privateKey).Return("secret", nil)
+			},
+			want: "secret",
+		},
+		{
+			name: "Password is not posted yet",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerPassword(gomock.Any(), instanceUUID, privateKey).Return("", nil)
+			},
+			want: "",
+		},
+		{
+			name: "OpenStack returns error",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerPassword(gomock.Any(), instanceUUID, privateKey).Return("", fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			server := &clients.ServerExt{}
+			server.ID = instanceUUID
+			instanceStatus := NewInstanceStatusFromServer(server, logr.Discard())
+
+			got, err := s.GetServerPassword(instanceStatus, privateKey)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_ClearServerPassword(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+	mockComputeClient.EXPECT().ClearServerPassword(gomock.Any(), instanceUUID).Return(nil)
+
+	s := Service{
+		scope: &scope.Scope{
+			Logger: logr.Discard(),
+		},
+		_computeClient: mockComputeClient,
+	}
+
+	server := &clients.ServerExt{}
+	server.ID = instanceUUID
+	instanceStatus := NewInstanceStatusFromServer(server, logr.Discard())
+
+	g.Expect(s.ClearServerPassword(&infrav1.OpenStackMachine{}, instanceStatus)).To(Succeed())
+}
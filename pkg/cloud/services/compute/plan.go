@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// PlanInstance returns the operations CreateInstance would perform to create the server of instanceSpec, and the
+// volumes of its root volume and additional block devices which do not exist yet, if no server of its name exists.
+// The ports of the server are not planned.
+func (s *Service) PlanInstance(eventObject runtime.Object, instanceSpec *InstanceSpec) ([]infrav1.PlannedOperation, error) {
+	instanceStatus, err := s.GetInstanceStatusByName(eventObject, instanceSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if instanceStatus != nil {
+		return nil, nil
+	}
+
+	var operations []infrav1.PlannedOperation
+	createVolume := func(name string, size int, volume *volumes.Volume) {
+		if volume == nil {
+			operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: VolumeResource, Name: name, Message: fmt.Sprintf("%d GiB", size)})
+		}
+	}
+
+	if hasRootVolume(instanceSpec.RootVolume) {
+		name := rootVolumeName(instanceSpec.Name)
+		volume, err := s.getVolumeByName(name)
+		if err != nil {
+			return nil, err
+		}
+		createVolume(name, instanceSpec.RootVolume.Size, volume)
+	}
+	for i := range instanceSpec.AdditionalBlockDevices {
+		device := &instanceSpec.AdditionalBlockDevices[i]
+		name := additionalVolumeName(instanceSpec.Name, device)
+		var volume *volumes.Volume
+		if device.RetainedVolumeName != "" {
+			volume, err = s.getRetainedVolume(name, "", device.Size)
+		} else {
+			volume, err = s.getVolumeByName(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		createVolume(name, device.Size, volume)
+	}
+
+	flavor := instanceSpec.Flavor
+	if flavor == "" {
+		flavor = instanceSpec.FlavorID
+	}
+	image := instanceSpec.Image
+	if instanceSpec.ImageUUID != "" {
+		image = instanceSpec.ImageUUID
+	}
+	operations = append(operations, infrav1.PlannedOperation{
+		Action:   infrav1.PlannedActionCreate,
+		Resource: ServerResource,
+		Name:     instanceSpec.Name,
+		Message:  fmt.Sprintf("with flavor %s and image %s", flavor, image),
+	})
+	return operations, nil
+}
+
+// PlanInstanceDelete returns the operations DeleteInstance would perform to delete the server instanceName, and the
+// volumes of its root volume and additional block devices which are not retained.
+func (s *Service) PlanInstanceDelete(eventObject runtime.Object, instanceName string, rootVolume *infrav1.RootVolume, additionalBlockDevices []infrav1.AdditionalBlockDevice) ([]infrav1.PlannedOperation, error) {
+	var operations []infrav1.PlannedOperation
+
+	instanceStatus, err := s.GetInstanceStatusByName(eventObject, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	if instanceStatus != nil {
+		operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionDelete, Resource: ServerResource, Name: instanceName, ID: instanceStatus.ID()})
+	}
+
+	deleteVolume := func(name string) error {
+		volume, err := s.getVolumeByName(name)
+		if err != nil {
+			return err
+		}
+		if volume != nil {
+			operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionDelete, Resource: VolumeResource, Name: name, ID: volume.ID})
+		}
+		return nil
+	}
+
+	if hasRootVolume(rootVolume) && !isRetained(rootVolume.RetentionPolicy) {
+		if err := deleteVolume(rootVolumeName(instanceName)); err != nil {
+			return nil, err
+		}
+	}
+	for i := range additionalBlockDevices {
+		device := &additionalBlockDevices[i]
+		if isRetained(device.RetentionPolicy) {
+			continue
+		}
+		if err := deleteVolume(additionalVolumeName(instanceName, device)); err != nil {
+			return nil, err
+		}
+	}
+	return operations, nil
+}
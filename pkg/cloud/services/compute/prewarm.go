@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// PrewarmServerName returns the name of the throwaway server which caches an image in an availability zone.
+func PrewarmServerName(imageName, availabilityZone string) string {
+	return fmt.Sprintf("%s-prewarm-%s", imageName, availabilityZone)
+}
+
+// PrewarmImage caches the image on a compute host of the availability zone by booting a throwaway server
+// without network from it, which is deleted as soon as it is active. It returns true once the server has
+// been deleted. A server which fails to boot is deleted as well and reported with an event, as the image
+// is cached when the first machine is created from it anyway.
+func (s *Service) PrewarmImage(eventObject runtime.Object, name, imageID, flavorName, availabilityZone string) (bool, error) {
+	server, err := s.getPrewarmServer(name)
+	if err != nil {
+		return false, err
+	}
+
+	if server == nil {
+		flavorID, err := s.getFlavorID("", flavorName)
+		if err != nil {
+			return false, err
+		}
+		_, err = s.getComputeClient().CreateServer(s.scope.Context(), servers.CreateOpts{
+			Name:             name,
+			ImageRef:         imageID,
+			FlavorRef:        flavorID,
+			AvailabilityZone: availabilityZone,
+			// The server only needs to download the image
+			Networks: "none",
+		})
+		if err != nil {
+			record.Warnf(eventObject, "FailedCreateServer", "Failed to create server %s to cache image %s in availability zone %s: %v", name, imageID, availabilityZone, err)
+			return false, err
+		}
+		record.Eventf(eventObject, "SuccessfulCreateServer", "Created server %s to cache image %s in availability zone %s", name, imageID, availabilityZone)
+		return false, nil
+	}
+
+	switch infrav1.InstanceState(server.Status) {
+	case infrav1.InstanceStateActive:
+	case infrav1.InstanceStateError:
+		record.Warnf(eventObject, "FailedPrewarmImage", "Server %s failed to boot from image %s in availability zone %s, the image is not cached", name, imageID, availabilityZone)
+	default:
+		return false, nil
+	}
+
+	if err := s.deletePrewarmServer(eventObject, server.ID, name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeletePrewarmServer deletes the throwaway server with the name, if it exists.
+func (s *Service) DeletePrewarmServer(eventObject runtime.Object, name string) error {
+	server, err := s.getPrewarmServer(name)
+	if err != nil || server == nil {
+		return err
+	}
+	return s.deletePrewarmServer(eventObject, server.ID, name)
+}
+
+func (s *Service) getPrewarmServer(name string) (*servers.Server, error) {
+	serverList, err := s.getComputeClient().ListServers(s.scope.Context(), servers.ListOpts{
+		// The name is a regular expression
+		Name: fmt.Sprintf("^%s$", name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get server list: %v", err)
+	}
+	if len(serverList) == 0 {
+		return nil, nil
+	}
+	return &serverList[0].Server, nil
+}
+
+func (s *Service) deletePrewarmServer(eventObject runtime.Object, id, name string) error {
+	if err := s.getComputeClient().DeleteServer(s.scope.Context(), id); err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(eventObject, "FailedDeleteServer", "Failed to delete server %s: %v", name, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulDeleteServer", "Deleted server %s", name)
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_PrewarmImage(t *testing.T) {
+	const (
+		serverName = "ubuntu-prewarm-az1"
+		serverID   = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	)
+	listOpts := servers.ListOpts{Name: "^" + serverName + "$"}
+
+	tests := []struct {
+		name   string
+		expect func(m *mock.MockComputeClientMockRecorder)
+		want   bool
+	}{
+		{
+			name: "Server is created",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), listOpts).Return(nil, nil)
+				m.GetFlavorIDFromName(gomock.Any(), "m1.tiny").Return(flavorUUID, nil)
+				m.CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createOpts servers.CreateOptsBuilder) (*clients.ServerExt, error) {
+					opts := createOpts.(servers.CreateOpts)
+					g := NewWithT(t)
+					g.Expect(opts.Name).To(Equal(serverName))
+					g.Expect(opts.ImageRef).To(Equal(imageUUID))
+					g.Expect(opts.FlavorRef).To(Equal(flavorUUID))
+					g.Expect(opts.AvailabilityZone).To(Equal("az1"))
+					g.Expect(opts.Networks).To(Equal("none"))
+					return &clients.ServerExt{Server: servers.Server{ID: serverID}}, nil
+				})
+			},
+		},
+		{
+			name: "Server is building",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), listOpts).Return([]clients.ServerExt{{Server: servers.Server{ID: serverID, Status: "BUILD"}}}, nil)
+			},
+		},
+		{
+			name: "Active server is deleted",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), listOpts).Return([]clients.ServerExt{{Server: servers.Server{ID: serverID, Status: "ACTIVE"}}}, nil)
+				m.DeleteServer(gomock.Any(), serverID).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "Failed server is deleted",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServers(gomock.Any(), listOpts).Return([]clients.ServerExt{{Server: servers.Server{ID: serverID, Status: "ERROR"}}}, nil)
+				m.DeleteServer(gomock.Any(), serverID).Return(nil)
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.PrewarmImage(&infrav1.OpenStackImage{}, serverName, imageUUID, "m1.tiny", "az1")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
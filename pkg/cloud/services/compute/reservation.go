@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+)
+
+// reservationSchedulerHint is the scheduler hint with which Nova schedules a server on a host
+// reserved by Blazar.
+const reservationSchedulerHint = "reservation"
+
+// ErrReservationNotActive is returned when the Blazar lease or reservation of an instance
+// does not exist or is not active.
+var ErrReservationNotActive = errors.New("reservation is not active")
+
+// getReservation returns the reservation of the instance, which must be active, or nil if the
+// instance has no reservation.
+func (s *Service) getReservation(reservation *infrav1.Reservation) (*clients.LeaseReservation, error) {
+	if reservation == nil {
+		return nil, nil
+	}
+
+	lease, err := s.getReservationClient().GetLease(s.scope.Context(), reservation.LeaseID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting lease %s: %v", ErrReservationNotActive, reservation.LeaseID, err)
+	}
+	if lease.Status != clients.LeaseStatusActive {
+		return nil, fmt.Errorf("%w: lease %s is %s, it starts at %s", ErrReservationNotActive, lease.ID, lease.Status, lease.StartDate)
+	}
+
+	var found *clients.LeaseReservation
+	for i := range lease.Reservations {
+		r := &lease.Reservations[i]
+		if r.ID == reservation.ReservationID || (reservation.ReservationID == "" && len(lease.Reservations) == 1) {
+			found = r
+			break
+		}
+	}
+	switch {
+	case found == nil && reservation.ReservationID == "":
+		return nil, fmt.Errorf("%w: lease %s has %d reservations, reservationID must select one", ErrReservationNotActive, lease.ID, len(lease.Reservations))
+	case found == nil:
+		return nil, fmt.Errorf("%w: lease %s has no reservation %s", ErrReservationNotActive, lease.ID, reservation.ReservationID)
+	case found.Status != clients.ReservationStatusActive:
+		return nil, fmt.Errorf("%w: reservation %s of lease %s is %s", ErrReservationNotActive, found.ID, lease.ID, found.Status)
+	}
+	return found, nil
+}
+
+// reservationFlavorID returns the ID of the flavor of an instance reservation, which Blazar
+// creates with the ID of the reservation, or an empty string for other reservations.
+func reservationFlavorID(reservation *clients.LeaseReservation) string {
+	if reservation == nil || reservation.ResourceType != clients.ReservationResourceTypeInstance {
+		return ""
+	}
+	return reservation.ID
+}
+
+// reservationSchedulerHints adds the reservation scheduler hint of a host reservation to the
+// scheduler hints of an instance.
+func reservationSchedulerHints(reservation *clients.LeaseReservation, hints []infrav1.SchedulerHintAdditionalProperty) []infrav1.SchedulerHintAdditionalProperty {
+	if reservation == nil || reservation.ResourceType != clients.ReservationResourceTypeHost {
+		return hints
+	}
+	id := reservation.ID
+	return append(append([]infrav1.SchedulerHintAdditionalProperty{}, hints...), infrav1.SchedulerHintAdditionalProperty{
+		Name:  reservationSchedulerHint,
+		Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeString, String: &id},
+	})
+}
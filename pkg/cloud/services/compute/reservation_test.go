@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_getReservation(t *testing.T) {
+	const (
+		leaseID           = "0b0e3c5a-1d7e-4d6f-9f5e-8a3c2b1d0e9f"
+		hostReservationID = "5c9e8a7b-3f2d-4e1c-b0a9-8d7e6f5a4b3c"
+		vmReservationID   = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+	)
+	hostReservation := clients.LeaseReservation{ID: hostReservationID, ResourceType: clients.ReservationResourceTypeHost, Status: clients.ReservationStatusActive}
+	vmReservation := clients.LeaseReservation{ID: vmReservationID, ResourceType: clients.ReservationResourceTypeInstance, Status: clients.ReservationStatusActive}
+
+	tests := []struct {
+		name        string
+		reservation *infrav1.Reservation
+		expect      func(m *mock.MockReservationClientMockRecorder)
+		want        *clients.LeaseReservation
+		wantErr     bool
+	}{
+		{
+			name:        "No reservation",
+			reservation: nil,
+			expect:      func(m *mock.MockReservationClientMockRecorder) {},
+		},
+		{
+			name:        "Single reservation of an active lease",
+			reservation: &infrav1.Reservation{LeaseID: leaseID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				m.GetLease(gomock.Any(), leaseID).Return(&clients.Lease{ID: leaseID, Status: clients.LeaseStatusActive, Reservations: []clients.LeaseReservation{hostReservation}}, nil)
+			},
+			want: &hostReservation,
+		},
+		{
+			name:        "Selected reservation of an active lease",
+			reservation: &infrav1.Reservation{LeaseID: leaseID, ReservationID: vmReservationID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				m.GetLease(gomock.Any(), leaseID).Return(&clients.Lease{ID: leaseID, Status: clients.LeaseStatusActive, Reservations: []clients.LeaseReservation{hostReservation, vmReservation}}, nil)
+			},
+			want: &vmReservation,
+		},
+		{
+			name:        "Lease with several reservations",
+			reservation: &infrav1.Reservation{LeaseID: leaseID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				m.GetLease(gomock.Any(), leaseID).Return(&clients.Lease{ID: leaseID, Status: clients.LeaseStatusActive, Reservations: []clients.LeaseReservation{hostReservation, vmReservation}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Lease has not started",
+			reservation: &infrav1.Reservation{LeaseID: leaseID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				m.GetLease(gomock.Any(), leaseID).Return(&clients.Lease{ID: leaseID, Status: "PENDING", StartDate: "2022-09-01T12:00:00.000000", Reservations: []clients.LeaseReservation{hostReservation}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Reservation is not active",
+			reservation: &infrav1.Reservation{LeaseID: leaseID, ReservationID: hostReservationID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				reservation := hostReservation
+				reservation.Status = "error"
+				m.GetLease(gomock.Any(), leaseID).Return(&clients.Lease{ID: leaseID, Status: clients.LeaseStatusActive, Reservations: []clients.LeaseReservation{reservation}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Lease does not exist",
+			reservation: &infrav1.Reservation{LeaseID: leaseID},
+			expect: func(m *mock.MockReservationClientMockRecorder) {
+				m.GetLease(gomock.Any(), leaseID).Return(nil, errors.New("Resource not found"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockReservationClient := mock.NewMockReservationClient(mockCtrl)
+			tt.expect(mockReservationClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_reservationClient: mockReservationClient,
+			}
+
+			got, err := s.getReservation(tt.reservation)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ErrReservationNotActive))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_reservationFlavorAndSchedulerHints(t *testing.T) {
+	g := NewWithT(t)
+	hints := []infrav1.SchedulerHintAdditionalProperty{
+		{Name: "aggregate", Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeString, String: pointer.String("gpu")}},
+	}
+
+	host := &clients.LeaseReservation{ID: "host-reservation-id", ResourceType: clients.ReservationResourceTypeHost}
+	g.Expect(reservationFlavorID(host)).To(BeEmpty())
+	g.Expect(reservationSchedulerHints(host, hints)).To(Equal(append(hints, infrav1.SchedulerHintAdditionalProperty{
+		Name:  "reservation",
+		Value: infrav1.SchedulerHintAdditionalValue{Type: infrav1.SchedulerHintValueTypeString, String: pointer.String("host-reservation-id")},
+	})))
+	g.Expect(hints).To(HaveLen(1))
+
+	instance := &clients.LeaseReservation{ID: "instance-reservation-id", ResourceType: clients.ReservationResourceTypeInstance}
+	g.Expect(reservationFlavorID(instance)).To(Equal("instance-reservation-id"))
+	g.Expect(reservationSchedulerHints(instance, hints)).To(Equal(hints))
+
+	g.Expect(reservationFlavorID(nil)).To(BeEmpty())
+	g.Expect(reservationSchedulerHints(nil, hints)).To(Equal(hints))
+}
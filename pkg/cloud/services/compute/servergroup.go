@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const serverGroupPrefix string = "k8s-cluster"
+
+// ServerGroupName returns the name of the server group managed by CAPO for
+// the machines of the given owner, e.g. a MachineDeployment.
+func ServerGroupName(clusterName, owner string) string {
+	return fmt.Sprintf("%s-%s-servergroup-%s", serverGroupPrefix, clusterName, owner)
+}
+
+// ReconcileServerGroup returns the ID of the server group with the given name,
+// creating it with the given policy if it does not exist yet.
+func (s *Service) ReconcileServerGroup(eventObject runtime.Object, name string, policy infrav1.ServerGroupPolicy) (string, error) {
+	serverGroup, err := s.getServerGroupByName(name)
+	if err != nil {
+		return "", err
+	}
+
+	if serverGroup != nil {
+		if len(serverGroup.Policies) != 1 || serverGroup.Policies[0] != string(policy) {
+			return "", fmt.Errorf("server group %s already exists with policies %v, expected %s", name, serverGroup.Policies, policy)
+		}
+		s.scope.Logger.V(6).Info("Reusing existing server group", "name", name, "id", serverGroup.ID)
+		return serverGroup.ID, nil
+	}
+
+	createOpts := servergroups.CreateOpts{
+		Name:     name,
+		Policies: []string{string(policy)},
+	}
+	serverGroup, err = s.getComputeClient().CreateServerGroup(s.scope.Context(), createOpts)
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateServerGroup", "Failed to create server group %s: %v", name, err)
+		return "", err
+	}
+
+	record.Eventf(eventObject, "SuccessfulCreateServerGroup", "Created server group %s with id %s", name, serverGroup.ID)
+	return serverGroup.ID, nil
+}
+
+// DeleteServerGroupIfUnused deletes the server group with the given name once
+// it no longer has any members. Machines which will use the group but have no
+// server yet are not members, so callers must check that there are none.
+func (s *Service) DeleteServerGroupIfUnused(eventObject runtime.Object, name string) error {
+	serverGroup, err := s.getServerGroupByName(name)
+	if err != nil {
+		return err
+	}
+
+	if serverGroup == nil {
+		return nil
+	}
+
+	if len(serverGroup.Members) > 0 {
+		s.scope.Logger.V(6).Info("Server group still has members, not deleting it", "name", name, "members", len(serverGroup.Members))
+		return nil
+	}
+
+	if err := s.getComputeClient().DeleteServerGroup(s.scope.Context(), serverGroup.ID); err != nil {
+		record.Warnf(eventObject, "FailedDeleteServerGroup", "Failed to delete server group %s with id %s: %v", name, serverGroup.ID, err)
+		return err
+	}
+
+	record.Eventf(eventObject, "SuccessfulDeleteServerGroup", "Deleted server group %s with id %s", name, serverGroup.ID)
+	return nil
+}
+
+func (s *Service) getServerGroupByName(name string) (*servergroups.ServerGroup, error) {
+	allServerGroups, err := s.getComputeClient().ListServerGroups(s.scope.Context(), servergroups.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing server groups: %v", err)
+	}
+
+	var serverGroupList []servergroups.ServerGroup
+	for _, serverGroup := range allServerGroups {
+		if serverGroup.Name == name {
+			serverGroupList = append(serverGroupList, serverGroup)
+		}
+	}
+
+	if len(serverGroupList) > 1 {
+		return nil, fmt.Errorf("expected to find a single server group called %s; found %d", name, len(serverGroupList))
+	}
+	if len(serverGroupList) == 0 {
+		return nil, nil
+	}
+	return &serverGroupList[0], nil
+}
+
+// ServerGroupMembership is the result of checking an instance against its server group.
+type ServerGroupMembership struct {
+	// ServerGroupID is the ID of the server group.
+	ServerGroupID string
+	// Policy is the policy of the server group.
+	Policy string
+	// Member is whether the instance is a member of the server group.
+	Member bool
+	// ViolatingMembers are the IDs of the other members of the server group whose
+	// placement breaks the policy of the server group together with the instance.
+	ViolatingMembers []string
+}
+
+// Violated returns true if the instance lost its membership or the policy of the server group is broken.
+func (m *ServerGroupMembership) Violated() bool {
+	return !m.Member || len(m.ViolatingMembers) > 0
+}
+
+// CheckServerGroupMembership checks that the instance is still a member of the server group with the given ID
+// or, if the ID is empty, of the server group with the given name, and that its placement honours the policy
+// of the server group. Membership and placement can be lost through admin operations like evacuation or a
+// forced live migration. Soft policies are best effort and are not checked. It returns nil if the server
+// group does not exist.
+func (s *Service) CheckServerGroupMembership(instanceStatus *InstanceStatus, serverGroupID, serverGroupName string) (*ServerGroupMembership, error) {
+	var serverGroup *servergroups.ServerGroup
+	var err error
+	if serverGroupID != "" {
+		serverGroup, err = s.getComputeClient().GetServerGroup(s.scope.Context(), serverGroupID)
+		if capoerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting server group %s: %v", serverGroupID, err)
+		}
+	} else {
+		serverGroup, err = s.getServerGroupByName(serverGroupName)
+		if err != nil {
+			return nil, err
+		}
+		if serverGroup == nil {
+			return nil, nil
+		}
+	}
+
+	membership := &ServerGroupMembership{ServerGroupID: serverGroup.ID}
+	if len(serverGroup.Policies) > 0 {
+		membership.Policy = serverGroup.Policies[0]
+	}
+	for _, member := range serverGroup.Members {
+		if member == instanceStatus.ID() {
+			membership.Member = true
+		}
+	}
+	if !membership.Member {
+		return membership, nil
+	}
+
+	policy := infrav1.ServerGroupPolicy(membership.Policy)
+	if policy != infrav1.ServerGroupPolicyAntiAffinity && policy != infrav1.ServerGroupPolicyAffinity {
+		return membership, nil
+	}
+	if instanceStatus.HostID() == "" {
+		// The host is not known until the instance has been scheduled
+		return membership, nil
+	}
+
+	for _, member := range serverGroup.Members {
+		if member == instanceStatus.ID() {
+			continue
+		}
+		server, err := s.getComputeClient().GetServer(s.scope.Context(), member)
+		if capoerrors.IsNotFound(err) {
+			// The member is being deleted
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting member %s of server group %s: %v", member, serverGroup.ID, err)
+		}
+		if server.HostID == "" {
+			continue
+		}
+
+		sameHost := server.HostID == instanceStatus.HostID()
+		if (policy == infrav1.ServerGroupPolicyAntiAffinity && sameHost) || (policy == infrav1.ServerGroupPolicyAffinity && !sameHost) {
+			membership.ViolatingMembers = append(membership.ViolatingMembers, member)
+		}
+	}
+	return membership, nil
+}
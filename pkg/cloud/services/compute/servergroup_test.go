@@ -0,0 +1,324 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const serverGroupName = "k8s-cluster-test-namespace-test-cluster-servergroup-md-0"
+
+func TestService_ReconcileServerGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockComputeClientMockRecorder)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Create server group if it does not exist",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: "other", Name: "other-server-group", Policies: []string{"affinity"}},
+				}, nil)
+				m.CreateServerGroup(gomock.Any(), servergroups.CreateOpts{
+					Name:     serverGroupName,
+					Policies: []string{"anti-affinity"},
+				}).Return(&servergroups.ServerGroup{ID: serverGroupUUID, Name: serverGroupName}, nil)
+			},
+			want: serverGroupUUID,
+		},
+		{
+			name: "Reuse existing server group",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Policies: []string{"anti-affinity"}},
+				}, nil)
+			},
+			want: serverGroupUUID,
+		},
+		{
+			name: "Existing server group with different policy",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Policies: []string{"affinity"}},
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Multiple server groups with the same name",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Policies: []string{"anti-affinity"}},
+					{ID: "duplicate", Name: serverGroupName, Policies: []string{"anti-affinity"}},
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Create server group fails",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return(nil, nil)
+				m.CreateServerGroup(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.ReconcileServerGroup(&infrav1.OpenStackMachine{}, serverGroupName, infrav1.ServerGroupPolicyAntiAffinity)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestService_DeleteServerGroupIfUnused(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockComputeClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "Delete server group without members",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Members: []string{}},
+				}, nil)
+				m.DeleteServerGroup(gomock.Any(), serverGroupUUID).Return(nil)
+			},
+		},
+		{
+			name: "Keep server group with members",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Members: []string{instanceUUID}},
+				}, nil)
+			},
+		},
+		{
+			name: "Server group does not exist",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return(nil, nil)
+			},
+		},
+		{
+			name: "Delete server group fails",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName},
+				}, nil)
+				m.DeleteServerGroup(gomock.Any(), serverGroupUUID).Return(fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			err := s.DeleteServerGroupIfUnused(&infrav1.OpenStackMachine{}, serverGroupName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestService_CheckServerGroupMembership(t *testing.T) {
+	const (
+		hostID        = "host-1"
+		otherHostID   = "host-2"
+		otherMemberID = "other-member"
+	)
+
+	instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{
+		Server: servers.Server{ID: instanceUUID, Name: "test-instance", HostID: hostID},
+	}, logr.Discard())
+
+	tests := []struct {
+		name          string
+		serverGroupID string
+		expect        func(m *mock.MockComputeClientMockRecorder)
+		want          *ServerGroupMembership
+		wantViolated  bool
+		wantErr       bool
+	}{
+		{
+			name:          "Member of anti-affinity server group on its own host",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"anti-affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+				m.GetServer(gomock.Any(), otherMemberID).Return(&clients.ServerExt{Server: servers.Server{ID: otherMemberID, HostID: otherHostID}}, nil)
+			},
+			want: &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "anti-affinity", Member: true},
+		},
+		{
+			name:          "Member of anti-affinity server group sharing a host",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"anti-affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+				m.GetServer(gomock.Any(), otherMemberID).Return(&clients.ServerExt{Server: servers.Server{ID: otherMemberID, HostID: hostID}}, nil)
+			},
+			want:         &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "anti-affinity", Member: true, ViolatingMembers: []string{otherMemberID}},
+			wantViolated: true,
+		},
+		{
+			name:          "Member of affinity server group on another host",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+				m.GetServer(gomock.Any(), otherMemberID).Return(&clients.ServerExt{Server: servers.Server{ID: otherMemberID, HostID: otherHostID}}, nil)
+			},
+			want:         &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "affinity", Member: true, ViolatingMembers: []string{otherMemberID}},
+			wantViolated: true,
+		},
+		{
+			name:          "Soft policies are not checked",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"soft-anti-affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+			},
+			want: &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "soft-anti-affinity", Member: true},
+		},
+		{
+			name:          "Deleted members are ignored",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"anti-affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+				m.GetServer(gomock.Any(), otherMemberID).Return(nil, gophercloud.ErrDefault404{})
+			},
+			want: &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "anti-affinity", Member: true},
+		},
+		{
+			name:          "Instance is no longer a member",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"anti-affinity"}, Members: []string{otherMemberID},
+				}, nil)
+			},
+			want:         &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "anti-affinity"},
+			wantViolated: true,
+		},
+		{
+			name: "Managed server group is looked up by name",
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.ListServerGroups(gomock.Any(), servergroups.ListOpts{}).Return([]servergroups.ServerGroup{
+					{ID: serverGroupUUID, Name: serverGroupName, Policies: []string{"anti-affinity"}, Members: []string{instanceUUID}},
+				}, nil)
+			},
+			want: &ServerGroupMembership{ServerGroupID: serverGroupUUID, Policy: "anti-affinity", Member: true},
+		},
+		{
+			name:          "Server group does not exist",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(nil, gophercloud.ErrDefault404{})
+			},
+		},
+		{
+			name:          "Get member fails",
+			serverGroupID: serverGroupUUID,
+			expect: func(m *mock.MockComputeClientMockRecorder) {
+				m.GetServerGroup(gomock.Any(), serverGroupUUID).Return(&servergroups.ServerGroup{
+					ID: serverGroupUUID, Policies: []string{"anti-affinity"}, Members: []string{instanceUUID, otherMemberID},
+				}, nil)
+				m.GetServer(gomock.Any(), otherMemberID).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+			}
+
+			got, err := s.CheckServerGroupMembership(instanceStatus, tt.serverGroupID, serverGroupName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+			if got != nil {
+				g.Expect(got.Violated()).To(Equal(tt.wantViolated))
+			}
+		})
+	}
+}
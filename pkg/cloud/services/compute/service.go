@@ -19,17 +19,27 @@ package compute
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 type Service struct {
-	scope              *scope.Scope
-	_computeClient     clients.ComputeClient
-	_volumeClient      clients.VolumeClient
-	_imageClient       clients.ImageClient
-	_networkingService *networking.Service
+	scope                *scope.Scope
+	_computeClient       clients.ComputeClient
+	_volumeClient        clients.VolumeClient
+	_imageClient         clients.ImageClient
+	_objectStorageClient clients.ObjectStorageClient
+	_placementClient     clients.PlacementClient
+	_reservationClient   clients.ReservationClient
+	_networkingService   *networking.Service
+
+	// reconcileDurationCluster is the cluster whose reconcile duration metrics include the time
+	// spent creating the ports and volumes of instances.
+	reconcileDurationCluster *types.NamespacedName
 }
 
 // NewService returns an instance of the compute service.
@@ -82,6 +92,47 @@ func (s Service) getImageClient() clients.ImageClient {
 	return s._imageClient
 }
 
+func (s Service) getPlacementClient() clients.PlacementClient {
+	if s._placementClient == nil {
+		placementClient, err := clients.NewPlacementClient(s.scope)
+		if err != nil {
+			return clients.NewPlacementErrorClient(err)
+		}
+
+		s._placementClient = placementClient
+	}
+
+	return s._placementClient
+}
+
+func (s Service) getReservationClient() clients.ReservationClient {
+	if s._reservationClient == nil {
+		reservationClient, err := clients.NewReservationClient(s.scope)
+		if err != nil {
+			return clients.NewReservationErrorClient(err)
+		}
+
+		s._reservationClient = reservationClient
+	}
+
+	return s._reservationClient
+}
+
+// getObjectStorageClient returns the swift client, which is only created when Ignition
+// configs have to be staged in swift as not every cloud has object storage.
+func (s *Service) getObjectStorageClient() (clients.ObjectStorageClient, error) {
+	if s._objectStorageClient == nil {
+		objectStorageClient, err := clients.NewObjectStorageClient(s.scope)
+		if err != nil {
+			return nil, err
+		}
+
+		s._objectStorageClient = objectStorageClient
+	}
+
+	return s._objectStorageClient, nil
+}
+
 func (s Service) getNetworkingService() (*networking.Service, error) {
 	if s._networkingService == nil {
 		networkingService, err := networking.NewService(s.scope)
@@ -94,3 +145,17 @@ func (s Service) getNetworkingService() (*networking.Service, error) {
 
 	return s._networkingService, nil
 }
+
+// ObserveReconcileDurations makes the service record the time it spends creating the ports and volumes
+// of instances in the reconcile duration metrics of a cluster.
+func (s *Service) ObserveReconcileDurations(namespace, cluster string) {
+	s.reconcileDurationCluster = &types.NamespacedName{Namespace: namespace, Name: cluster}
+}
+
+// reconcileTimer starts timing the reconciliation of a resource if the service records reconcile durations.
+func (s *Service) reconcileTimer(resource string) func() {
+	if s.reconcileDurationCluster == nil {
+		return func() {}
+	}
+	return metrics.ReconcileTimer(s.reconcileDurationCluster.Namespace, s.reconcileDurationCluster.Name, resource)
+}
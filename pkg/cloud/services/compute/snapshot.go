@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// Properties of the snapshot images of servers.
+const (
+	SnapshotMachineProperty   = "capo-machine"
+	SnapshotClusterProperty   = "capo-cluster"
+	SnapshotNamespaceProperty = "capo-namespace"
+	SnapshotExpiresAtProperty = "capo-expires-at"
+)
+
+// defaultSnapshotTimeout is how long the deletion of a server waits for its snapshot by default.
+const defaultSnapshotTimeout = 30 * time.Minute
+
+// SnapshotInstance creates a snapshot image of the server of an instance before it is deleted, unless imageID is
+// the ID of the snapshot which was already created. It returns the ID of the snapshot and whether the server can be
+// deleted, which is when the snapshot is active, when it failed or when it did not become active within the timeout
+// of the snapshot. Nova aborts the snapshot of a server which is deleted.
+func (s *Service) SnapshotInstance(eventObject runtime.Object, instanceStatus *InstanceStatus, snapshot *infrav1.SnapshotBeforeDelete, data names.TemplateData, imageID string, now time.Time) (string, bool, error) {
+	if imageID == "" {
+		name, err := names.Render(snapshot.Name, data.MachineName+"-snapshot", data)
+		if err != nil {
+			return "", false, err
+		}
+		metadata := map[string]string{
+			SnapshotMachineProperty:   data.MachineName,
+			SnapshotClusterProperty:   data.ClusterName,
+			SnapshotNamespaceProperty: data.Namespace,
+		}
+		if snapshot.Expiry != nil {
+			metadata[SnapshotExpiresAtProperty] = now.Add(snapshot.Expiry.Duration).UTC().Format(time.RFC3339)
+		}
+
+		imageID, err = s.getComputeClient().CreateServerImage(s.scope.Context(), instanceStatus.ID(), servers.CreateImageOpts{Name: name, Metadata: metadata})
+		if err != nil {
+			record.Warnf(eventObject, "FailedCreateSnapshot", "Failed to create snapshot %s of server %s with id %s: %v", name, instanceStatus.Name(), instanceStatus.ID(), err)
+			// Nova does not snapshot servers in some states, e.g. in error, which must not block their deletion
+			if capoerrors.IsConflict(err) {
+				return "", true, nil
+			}
+			return "", false, err
+		}
+		record.Eventf(eventObject, "SuccessfulCreateSnapshot", "Created snapshot %s of server %s with id %s; imageID=%s", name, instanceStatus.Name(), instanceStatus.ID(), imageID)
+	}
+
+	image, err := s.getImageClient().GetImage(s.scope.Context(), imageID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			record.Warnf(eventObject, "FailedSnapshot", "Snapshot %s of server %s was deleted before it became active", imageID, instanceStatus.Name())
+			return imageID, true, nil
+		}
+		return imageID, false, fmt.Errorf("error getting snapshot %s: %w", imageID, err)
+	}
+
+	switch image.Status {
+	case images.ImageStatusActive:
+		return imageID, true, nil
+	case images.ImageStatusKilled, images.ImageStatusDeleted, images.ImageStatusPendingDelete:
+		record.Warnf(eventObject, "FailedSnapshot", "Snapshot %s of server %s failed in status %s", imageID, instanceStatus.Name(), image.Status)
+		return imageID, true, nil
+	}
+
+	timeout := defaultSnapshotTimeout
+	if snapshot.Timeout != nil {
+		timeout = snapshot.Timeout.Duration
+	}
+	if now.Sub(image.CreatedAt) > timeout {
+		record.Warnf(eventObject, "FailedSnapshot", "Snapshot %s of server %s did not become active within %s", imageID, instanceStatus.Name(), timeout)
+		return imageID, true, nil
+	}
+	s.scope.Logger.Info("waiting for snapshot of server", "imageID", imageID, "status", image.Status)
+	return imageID, false, nil
+}
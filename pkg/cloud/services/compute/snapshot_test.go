@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+func TestService_SnapshotInstance(t *testing.T) {
+	const snapshotID = "66666666-6666-6666-6666-666666666666"
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		snapshot infrav1.SnapshotBeforeDelete
+		imageID  string
+		expect   func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder)
+		want     string
+		wantDone bool
+		wantErr  bool
+	}{
+		{
+			name:     "Snapshot is created with the default name",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				compute.CreateServerImage(gomock.Any(), instanceUUID, servers.CreateImageOpts{
+					Name: "machine-snapshot",
+					Metadata: map[string]string{
+						SnapshotMachineProperty:   "machine",
+						SnapshotClusterProperty:   "cluster",
+						SnapshotNamespaceProperty: "ns",
+					},
+				}).Return(snapshotID, nil)
+				image.GetImage(gomock.Any(), snapshotID).Return(&images.Image{ID: snapshotID, Status: images.ImageStatusQueued, CreatedAt: now}, nil)
+			},
+			want: snapshotID,
+		},
+		{
+			name: "Snapshot is created with the name template and expiry",
+			snapshot: infrav1.SnapshotBeforeDelete{
+				Enabled: true,
+				Name:    "{{ .Namespace }}-{{ .ClusterName }}-{{ .MachineName }}",
+				Expiry:  &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			},
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				compute.CreateServerImage(gomock.Any(), instanceUUID, servers.CreateImageOpts{
+					Name: "ns-cluster-machine",
+					Metadata: map[string]string{
+						SnapshotMachineProperty:   "machine",
+						SnapshotClusterProperty:   "cluster",
+						SnapshotNamespaceProperty: "ns",
+						SnapshotExpiresAtProperty: "2022-08-08T12:00:00Z",
+					},
+				}).Return(snapshotID, nil)
+				image.GetImage(gomock.Any(), snapshotID).Return(&images.Image{ID: snapshotID, Status: images.ImageStatusActive, CreatedAt: now}, nil)
+			},
+			want:     snapshotID,
+			wantDone: true,
+		},
+		{
+			name:     "Server which cannot be snapshotted is deleted",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				compute.CreateServerImage(gomock.Any(), instanceUUID, gomock.Any()).Return("", gophercloud.ErrDefault409{})
+			},
+			wantDone: true,
+		},
+		{
+			name:     "Failure to create the snapshot is retried",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				compute.CreateServerImage(gomock.Any(), instanceUUID, gomock.Any()).Return("", gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Created snapshot is waited for",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			imageID:  snapshotID,
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				image.GetImage(gomock.Any(), snapshotID).Return(&images.Image{ID: snapshotID, Status: images.ImageStatusSaving, CreatedAt: now.Add(-29 * time.Minute)}, nil)
+			},
+			want: snapshotID,
+		},
+		{
+			name:     "Snapshot which is not active within the timeout is abandoned",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true, Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			imageID:  snapshotID,
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				image.GetImage(gomock.Any(), snapshotID).Return(&images.Image{ID: snapshotID, Status: images.ImageStatusSaving, CreatedAt: now.Add(-11 * time.Minute)}, nil)
+			},
+			want:     snapshotID,
+			wantDone: true,
+		},
+		{
+			name:     "Failed snapshot is abandoned",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			imageID:  snapshotID,
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				image.GetImage(gomock.Any(), snapshotID).Return(&images.Image{ID: snapshotID, Status: images.ImageStatusKilled, CreatedAt: now}, nil)
+			},
+			want:     snapshotID,
+			wantDone: true,
+		},
+		{
+			name:     "Deleted snapshot is abandoned",
+			snapshot: infrav1.SnapshotBeforeDelete{Enabled: true},
+			imageID:  snapshotID,
+			expect: func(compute *mock.MockComputeClientMockRecorder, image *mock.MockImageClientMockRecorder) {
+				image.GetImage(gomock.Any(), snapshotID).Return(nil, gophercloud.ErrDefault404{})
+			},
+			want:     snapshotID,
+			wantDone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockImageClient := mock.NewMockImageClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT(), mockImageClient.EXPECT())
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				_computeClient: mockComputeClient,
+				_imageClient:   mockImageClient,
+			}
+
+			instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{Server: servers.Server{ID: instanceUUID, Name: "machine"}}, logr.Discard())
+			data := names.NewTemplateData("ns", "ns-cluster")
+			data.MachineName = "machine"
+			got, done, err := s.SnapshotInstance(&infrav1.OpenStackMachine{}, instanceStatus, &tt.snapshot, data, tt.imageID, now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(done).To(Equal(tt.wantDone))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// volumeTagMetadataPrefix prefixes the metadata keys of the volumes of an instance which carry its tags, as Cinder
+// does not support tags.
+const volumeTagMetadataPrefix = "tag:"
+
+// volumeDeleteOnTerminationMetadataKey marks the volumes of an instance which are deleted with its server, i.e. which
+// are not retained, so that they are garbage collected if the server is never created.
+const volumeDeleteOnTerminationMetadataKey = "capo-delete-on-termination"
+
+// volumeClusterMetadataKey identifies the cluster of the volumes of an instance, so that the garbage collection of a
+// cluster does not find the volumes of other clusters in the same project.
+const volumeClusterMetadataKey = "capo-cluster"
+
+// volumeMetadata returns the metadata of a volume of an instance of the given cluster with the given tags.
+func volumeMetadata(tags []string, clusterName string, retained bool) map[string]string {
+	if len(tags) == 0 && clusterName == "" && retained {
+		return nil
+	}
+	metadata := make(map[string]string, len(tags)+2)
+	for _, tag := range tags {
+		metadata[volumeTagMetadataPrefix+tag] = "true"
+	}
+	if clusterName != "" {
+		metadata[volumeClusterMetadataKey] = clusterName
+	}
+	if !retained {
+		metadata[volumeDeleteOnTerminationMetadataKey] = "true"
+	}
+	return metadata
+}
+
+// usesTrunk returns whether any port of the instance is a trunk.
+func usesTrunk(instanceSpec *InstanceSpec) bool {
+	if instanceSpec.Trunk {
+		return true
+	}
+	for _, port := range instanceSpec.Ports {
+		if port.Trunk != nil && *port.Trunk {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconcileInstanceTags adds the tags of the instance spec which the server, its ports or their trunks lack, e.g.
+// because they were added to the spec after the server was created. Tags which are not in the spec are kept, as
+// they may have been set by other tools.
+func (s *Service) ReconcileInstanceTags(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus) error {
+	if len(instanceSpec.Tags) == 0 {
+		return nil
+	}
+
+	var current []string
+	if instanceStatus.server.Tags != nil {
+		current = *instanceStatus.server.Tags
+	}
+	have := make(map[string]struct{}, len(current))
+	for _, tag := range current {
+		have[tag] = struct{}{}
+	}
+	var missing []string
+	for _, tag := range instanceSpec.Tags {
+		if _, ok := have[tag]; !ok {
+			have[tag] = struct{}{}
+			missing = append(missing, tag)
+		}
+	}
+
+	if len(missing) > 0 {
+		s.scope.Logger.Info("Adding missing server tags", "id", instanceStatus.ID(), "tags", missing)
+		tags, err := s.getComputeClient().ReplaceAllServerTags(s.scope.Context(), instanceStatus.ID(), append(append([]string{}, current...), missing...))
+		if err != nil {
+			record.Warnf(eventObject, "FailedUpdateServerTags", "Failed to add tags %v to server %s with id %s: %v", missing, instanceStatus.Name(), instanceStatus.ID(), err)
+			return err
+		}
+		instanceStatus.server.Tags = &tags
+		record.Eventf(eventObject, "SuccessfulUpdateServerTags", "Added tags %v to server %s with id %s", missing, instanceStatus.Name(), instanceStatus.ID())
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return err
+	}
+	return networkingService.ReconcileInstancePortTags(eventObject, instanceStatus.ID(), instanceSpec.Tags, usesTrunk(instanceSpec))
+}
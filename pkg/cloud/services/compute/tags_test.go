@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_volumeMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(volumeMetadata(nil, "", true)).To(BeNil())
+	g.Expect(volumeMetadata([]string{"team-a", "env-prod"}, "", true)).To(Equal(map[string]string{
+		"tag:team-a":   "true",
+		"tag:env-prod": "true",
+	}))
+	g.Expect(volumeMetadata([]string{"team-a"}, "default/cluster-a", false)).To(Equal(map[string]string{
+		"tag:team-a":                 "true",
+		"capo-cluster":               "default/cluster-a",
+		"capo-delete-on-termination": "true",
+	}))
+}
+
+func TestService_ReconcileInstanceTags(t *testing.T) {
+	const trunkUUID = "94ba1a31-6cf0-4a5c-a3e8-46e1c1c4e3a7"
+
+	tests := []struct {
+		name          string
+		instanceSpec  InstanceSpec
+		serverTags    []string
+		expectCompute func(m *mock.MockComputeClientMockRecorder)
+		expectNetwork func(m *mock.MockNetworkClientMockRecorder)
+		wantTags      []string
+	}{
+		{
+			name:          "no tags",
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+		},
+		{
+			name:          "tags are up to date",
+			instanceSpec:  InstanceSpec{Tags: []string{"team-a"}},
+			serverTags:    []string{"team-a", "external"},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: instanceUUID}).Return([]ports.Port{{ID: portUUID, Tags: []string{"team-a"}}}, nil)
+			},
+			wantTags: []string{"team-a", "external"},
+		},
+		{
+			name:         "missing tags are added to the server and its ports",
+			instanceSpec: InstanceSpec{Tags: []string{"team-a", "env-prod"}},
+			serverTags:   []string{"team-a", "external"},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {
+				m.ReplaceAllServerTags(gomock.Any(), instanceUUID, []string{"team-a", "external", "env-prod"}).Return([]string{"team-a", "external", "env-prod"}, nil)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: instanceUUID}).Return([]ports.Port{{ID: portUUID, Tags: []string{"team-a"}}}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", portUUID, attributestags.ReplaceAllOpts{Tags: []string{"env-prod", "team-a"}}).Return(nil, nil)
+			},
+			wantTags: []string{"team-a", "external", "env-prod"},
+		},
+		{
+			name: "missing tags are added to the trunks of the ports",
+			instanceSpec: InstanceSpec{
+				Tags:  []string{"team-a"},
+				Ports: []infrav1.PortOpts{{Trunk: pointer.Bool(true)}},
+			},
+			serverTags:    []string{"team-a"},
+			expectCompute: func(m *mock.MockComputeClientMockRecorder) {},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: instanceUUID}).Return([]ports.Port{{ID: portUUID, Tags: []string{"team-a"}}}, nil)
+				m.ListTrunk(gomock.Any(), trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{{ID: trunkUUID}}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "trunks", trunkUUID, attributestags.ReplaceAllOpts{Tags: []string{"team-a"}}).Return(nil, nil)
+			},
+			wantTags: []string{"team-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expectCompute(mockComputeClient.EXPECT())
+			tt.expectNetwork(mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope:              &scope.Scope{Logger: logr.Discard()},
+				_computeClient:     mockComputeClient,
+				_networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+			}
+			server := servers.Server{ID: instanceUUID, Name: "machine"}
+			if tt.serverTags != nil {
+				server.Tags = &tt.serverTags
+			}
+			instanceStatus := NewInstanceStatusFromServer(&clients.ServerExt{Server: server}, logr.Discard())
+
+			err := s.ReconcileInstanceTags(&infrav1.OpenStackMachine{}, &tt.instanceSpec, instanceStatus)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantTags != nil {
+				g.Expect(*instanceStatus.server.Tags).To(Equal(tt.wantTags))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// ErrNoValidResourceProvider is returned when no resource provider in Placement has the traits
+// required by an instance without any of its forbidden traits.
+var ErrNoValidResourceProvider = errors.New("no valid resource provider")
+
+// checkTraits checks that at least one resource provider in Placement satisfies the traits of
+// the instance, so that an instance which cannot be scheduled is reported with its traits
+// instead of failing with NoValidHost. The check is skipped if the cloud has no Placement
+// endpoint or its policy does not allow listing resource providers, which is admin-only by
+// default.
+func (s *Service) checkTraits(traits *infrav1.Traits) error {
+	if traits == nil || (len(traits.Required) == 0 && len(traits.Forbidden) == 0) {
+		return nil
+	}
+
+	filter := make([]string, 0, len(traits.Required)+len(traits.Forbidden))
+	filter = append(filter, traits.Required...)
+	for _, trait := range traits.Forbidden {
+		filter = append(filter, "!"+trait)
+	}
+
+	providers, err := s.getPlacementClient().ListResourceProviders(s.scope.Context(), resourceproviders.ListOpts{Required: strings.Join(filter, ",")})
+	if err != nil {
+		var endpointNotFound *gophercloud.ErrEndpointNotFound
+		if errors.As(err, &endpointNotFound) || capoerrors.IsForbidden(err) {
+			s.scope.Logger.V(4).Info("Skipping the check of the traits against Placement", "reason", err.Error())
+			return nil
+		}
+		return fmt.Errorf("error listing resource providers: %v", err)
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("%w: no resource provider has the traits %v without the traits %v", ErrNoValidResourceProvider, traits.Required, traits.Forbidden)
+	}
+	return nil
+}
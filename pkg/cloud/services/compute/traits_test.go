@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_checkTraits(t *testing.T) {
+	gpuTraits := &infrav1.Traits{Required: []string{"CUSTOM_GPU", "HW_CPU_X86_AVX2"}, Forbidden: []string{"CUSTOM_MAINTENANCE"}}
+	gpuListOpts := resourceproviders.ListOpts{Required: "CUSTOM_GPU,HW_CPU_X86_AVX2,!CUSTOM_MAINTENANCE"}
+
+	tests := []struct {
+		name    string
+		traits  *infrav1.Traits
+		expect  func(m *mock.MockPlacementClientMockRecorder)
+		wantErr error
+	}{
+		{
+			name:   "No traits",
+			expect: func(m *mock.MockPlacementClientMockRecorder) {},
+		},
+		{
+			name:   "Resource provider with the traits",
+			traits: gpuTraits,
+			expect: func(m *mock.MockPlacementClientMockRecorder) {
+				m.ListResourceProviders(gomock.Any(), gpuListOpts).Return([]resourceproviders.ResourceProvider{{UUID: "e3f3c1e4-5bd5-4d8b-a52e-77ad0f3a0c3b", Name: "compute-gpu-1"}}, nil)
+			},
+		},
+		{
+			name:   "No resource provider with the traits",
+			traits: gpuTraits,
+			expect: func(m *mock.MockPlacementClientMockRecorder) {
+				m.ListResourceProviders(gomock.Any(), gpuListOpts).Return([]resourceproviders.ResourceProvider{}, nil)
+			},
+			wantErr: ErrNoValidResourceProvider,
+		},
+		{
+			name:   "Listing resource providers is not allowed",
+			traits: gpuTraits,
+			expect: func(m *mock.MockPlacementClientMockRecorder) {
+				m.ListResourceProviders(gomock.Any(), gpuListOpts).Return(nil, gophercloud.ErrDefault403{})
+			},
+		},
+		{
+			name:   "Listing resource providers fails",
+			traits: gpuTraits,
+			expect: func(m *mock.MockPlacementClientMockRecorder) {
+				m.ListResourceProviders(gomock.Any(), gpuListOpts).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: errors.New("error listing resource providers: test error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockPlacementClient := mock.NewMockPlacementClient(mockCtrl)
+			tt.expect(mockPlacementClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_placementClient: mockPlacementClient,
+			}
+
+			err := s.checkTraits(tt.traits)
+			switch {
+			case tt.wantErr == nil:
+				g.Expect(err).NotTo(HaveOccurred())
+			case errors.Is(tt.wantErr, ErrNoValidResourceProvider):
+				g.Expect(err).To(MatchError(ErrNoValidResourceProvider))
+			default:
+				g.Expect(err).To(MatchError(tt.wantErr.Error()))
+			}
+		})
+	}
+}
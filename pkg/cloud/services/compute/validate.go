@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
+)
+
+// ValidateMachineResources checks that the flavor, image, networks, subnets and keypair referenced by
+// the machine spec exist. References which cannot be found are returned as field errors, while errors
+// of the OpenStack APIs are returned as error so that the caller can tell both apart.
+//
+// A flavor given by ID is not checked as the compute API offers no lookup by ID, and an image given by
+// ImageRef is checked by the OpenStackImage controller.
+func (s *Service) ValidateMachineResources(spec *infrav1.OpenStackMachineSpec, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	if spec.Flavor != "" {
+		flavorName := flavoralias.Resolve(s.scope.CloudName(), spec.Flavor)
+		if _, err := s.getComputeClient().GetFlavorIDFromName(s.scope.Context(), flavorName); err != nil {
+			if !capoerrors.IsNotFound(err) {
+				return nil, err
+			}
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("flavor"), flavorName))
+		}
+	}
+
+	imageErrs, err := s.validateImage(spec, fldPath)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, imageErrs...)
+
+	// A keypair which is imported from a secret is created by the controller
+	if spec.SSHKeyName != "" && spec.SSHPublicKeySecretRef == nil {
+		exists, err := s.KeyPairExists(spec.SSHKeyName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("sshKeyName"), spec.SSHKeyName))
+		}
+	}
+
+	networkingService, err := s.getNetworkingService()
+	if err != nil {
+		return nil, err
+	}
+	networkErrs, err := networkingService.ValidateNetworks(spec.Networks, spec.Ports, fldPath)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, networkErrs...)
+
+	return allErrs, nil
+}
+
+// validateImage checks that the image given by ImageUUID, Image or ImageFilter exists. Failed
+// requests are not retried as the validation runs within the timeout of an admission request.
+func (s *Service) validateImage(spec *infrav1.OpenStackMachineSpec, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	switch {
+	case spec.ImageUUID != "":
+		if _, err := s.getImageClient().GetImage(s.scope.Context(), spec.ImageUUID); err != nil {
+			if !capoerrors.IsNotFound(err) {
+				return nil, err
+			}
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("imageUUID"), spec.ImageUUID))
+		}
+	case spec.Image != "":
+		allImages, err := s.getImageClient().ListImages(s.scope.Context(), images.ListOpts{Name: spec.Image})
+		if err != nil {
+			return nil, err
+		}
+		if len(allImages) == 0 {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("image"), spec.Image))
+		}
+	case spec.ImageFilter != nil:
+		allImages, err := s.getImageClient().ListImages(s.scope.Context(), images.ListOpts{
+			Name:   spec.ImageFilter.Name,
+			Tags:   spec.ImageFilter.Tags,
+			Status: images.ImageStatusActive,
+		})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for i := range allImages {
+			if imageHasProperties(&allImages[i], spec.ImageFilter.Properties) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imageFilter"), spec.ImageFilter, "no active image matches the filter"))
+		}
+	}
+
+	return allErrs, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_ValidateMachineResources(t *testing.T) {
+	type recorders struct {
+		compute *mock.MockComputeClientMockRecorder
+		image   *mock.MockImageClientMockRecorder
+		network *mock.MockNetworkClientMockRecorder
+	}
+
+	tests := []struct {
+		name       string
+		spec       infrav1.OpenStackMachineSpec
+		expect     func(r *recorders)
+		wantFields []string
+		wantErr    bool
+	}{
+		{
+			name: "All resources exist",
+			spec: infrav1.OpenStackMachineSpec{
+				Flavor:     flavorName,
+				Image:      imageName,
+				SSHKeyName: sshKeyName,
+				Networks:   []infrav1.NetworkParam{{UUID: networkUUID}},
+			},
+			expect: func(r *recorders) {
+				r.compute.GetFlavorIDFromName(gomock.Any(), flavorName).Return(flavorUUID, nil)
+				r.image.ListImages(gomock.Any(), images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
+				r.compute.GetKeyPair(gomock.Any(), sshKeyName).Return(&keypairs.KeyPair{Name: sshKeyName}, nil)
+				r.network.GetNetwork(gomock.Any(), networkUUID).Return(&networks.Network{ID: networkUUID}, nil)
+			},
+		},
+		{
+			name: "Missing resources are reported",
+			spec: infrav1.OpenStackMachineSpec{
+				Flavor:     flavorName,
+				ImageUUID:  imageUUID,
+				SSHKeyName: sshKeyName,
+				Ports:      []infrav1.PortOpts{{Network: &infrav1.NetworkFilter{Name: "missing"}}},
+			},
+			expect: func(r *recorders) {
+				r.compute.GetFlavorIDFromName(gomock.Any(), flavorName).Return("", gophercloud.ErrResourceNotFound{})
+				r.image.GetImage(gomock.Any(), imageUUID).Return(nil, gophercloud.ErrDefault404{})
+				r.compute.GetKeyPair(gomock.Any(), sshKeyName).Return(nil, gophercloud.ErrDefault404{})
+				r.network.ListNetwork(gomock.Any(), networks.ListOpts{Name: "missing"}).Return([]networks.Network{}, nil)
+			},
+			wantFields: []string{"spec.flavor", "spec.imageUUID", "spec.sshKeyName", "spec.ports[0].network"},
+		},
+		{
+			name: "Image filter without matching properties",
+			spec: infrav1.OpenStackMachineSpec{
+				FlavorID: flavorUUID,
+				ImageFilter: &infrav1.ImageFilter{
+					Name:       imageName,
+					Properties: map[string]string{"os_distro": "ubuntu"},
+				},
+			},
+			expect: func(r *recorders) {
+				r.image.ListImages(gomock.Any(), images.ListOpts{Name: imageName, Status: images.ImageStatusActive}).
+					Return([]images.Image{{ID: imageUUID, Properties: map[string]interface{}{"os_distro": "flatcar"}}}, nil)
+			},
+			wantFields: []string{"spec.imageFilter"},
+		},
+		{
+			name: "Keypair imported from a secret is not checked",
+			spec: infrav1.OpenStackMachineSpec{
+				FlavorID:              flavorUUID,
+				SSHKeyName:            sshKeyName,
+				SSHPublicKeySecretRef: &infrav1.SSHPublicKeySecretReference{Name: "ssh-key"},
+			},
+			expect: func(r *recorders) {},
+		},
+		{
+			name: "OpenStack returns error",
+			spec: infrav1.OpenStackMachineSpec{
+				Flavor: flavorName,
+			},
+			expect: func(r *recorders) {
+				r.compute.GetFlavorIDFromName(gomock.Any(), flavorName).Return("", fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockImageClient := mock.NewMockImageClient(mockCtrl)
+			mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(&recorders{mockComputeClient.EXPECT(), mockImageClient.EXPECT(), mockNetworkClient.EXPECT()})
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient:     mockComputeClient,
+				_imageClient:       mockImageClient,
+				_networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+			}
+
+			allErrs, err := s.ValidateMachineResources(&tt.spec, field.NewPath("spec"))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			fields := []string{}
+			for _, err := range allErrs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tt.wantFields))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
+)
+
+// ErrVolumeDetachFailed is returned when Cinder failed to detach a volume from a server, which
+// requires an operator to reset the volume.
+var ErrVolumeDetachFailed = errors.New("volume detach failed")
+
+// detachVolumes detaches the volumes which survive the server, i.e. retained data volumes and volumes attached by
+// others like the Cinder CSI driver, before the server is deleted. Nova releases them when the server is deleted
+// as well, but does not wait for Cinder, which occasionally leaves them reserved or in-use. Volumes deleted together
+// with the server and the root volume are left to Nova.
+//
+// Detaching is retried as long as Nova rejects it with a conflict, which it does while the server or the volume is
+// in a transitional state, e.g. while a previous detach of a multipath device is still in progress. A volume counts
+// as detached once Cinder does not list an attachment to the server any more, as multiattach volumes stay in-use
+// while attached to other servers.
+func (s *Service) detachVolumes(eventObject runtime.Object, instanceStatus *InstanceStatus, instanceName string, rootVolume *infrav1.RootVolume, retryInterval time.Duration) error {
+	// Nova refuses to detach volumes from servers in error state
+	if instanceStatus.State() == infrav1.InstanceStateError {
+		return nil
+	}
+
+	attachments, err := s.getComputeClient().ListVolumeAttachments(s.scope.Context(), instanceStatus.ID())
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error listing volume attachments: %w", err)
+	}
+
+	var rootVolumeID string
+	for i := range attachments {
+		attachment := &attachments[i]
+		// DeleteOnTermination is unknown if Nova does not support the microversion it was introduced with
+		if attachment.DeleteOnTermination == nil || *attachment.DeleteOnTermination {
+			continue
+		}
+
+		// The root volume cannot be detached, and only needs to be looked up if it is retained
+		if rootVolumeID == "" && hasRootVolume(rootVolume) && isRetained(rootVolume.RetentionPolicy) {
+			volume, err := s.getVolumeByName(rootVolumeName(instanceName))
+			if err != nil {
+				return err
+			}
+			if volume != nil {
+				rootVolumeID = volume.ID
+			}
+		}
+		if attachment.VolumeID == rootVolumeID {
+			continue
+		}
+
+		if err := s.detachVolume(eventObject, instanceStatus, attachment, retryInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachVolume detaches the volume of the attachment from the server and waits for Cinder to release the attachment.
+func (s *Service) detachVolume(eventObject runtime.Object, instanceStatus *InstanceStatus, attachment *volumeattach.VolumeAttachment, retryInterval time.Duration) error {
+	policy := retry.Policy{Interval: retryInterval, Timeout: timeoutVolumeDetach}
+
+	err := retry.Wait(s.scope.Context(), retry.Compute, policy, func() (bool, error) {
+		err := s.getComputeClient().DetachVolume(s.scope.Context(), instanceStatus.ID(), attachment.VolumeID)
+		switch {
+		case err == nil, capoerrors.IsNotFound(err):
+			return true, nil
+		case capoerrors.IsConflict(err), capoerrors.IsRetryable(err):
+			s.scope.Logger.V(4).Info("Retrying to detach volume", "server", instanceStatus.Name(), "volume", attachment.VolumeID, "reason", err)
+			return false, nil
+		default:
+			return false, err
+		}
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedDetachVolume", "Failed to detach volume %s from server %s: %v", attachment.VolumeID, instanceStatus.Name(), err)
+		return fmt.Errorf("error detaching volume %s: %w", attachment.VolumeID, err)
+	}
+
+	err = retry.Wait(s.scope.Context(), retry.Volume, policy, func() (bool, error) {
+		volume, err := s.getVolumeClient().GetVolume(s.scope.Context(), attachment.VolumeID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return true, nil
+			}
+			if capoerrors.IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return isVolumeDetached(volume, instanceStatus.ID())
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedDetachVolume", "Failed to detach volume %s from server %s: %v", attachment.VolumeID, instanceStatus.Name(), err)
+		return fmt.Errorf("volume %s was not detached: %w", attachment.VolumeID, err)
+	}
+
+	record.Eventf(eventObject, "SuccessfulDetachVolume", "Detached volume %s from server %s", attachment.VolumeID, instanceStatus.Name())
+	return nil
+}
+
+// isVolumeDetached returns whether Cinder has released the attachment of the volume to the server, and an error if
+// Cinder failed to detach it.
+func isVolumeDetached(volume *volumes.Volume, serverID string) (bool, error) {
+	if volume.Status == "error_detaching" {
+		return false, fmt.Errorf("%w: volume %s is in status %s", ErrVolumeDetachFailed, volume.ID, volume.Status)
+	}
+	if volume.Status == "detaching" {
+		return false, nil
+	}
+	for _, attachment := range volume.Attachments {
+		if attachment.ServerID == serverID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
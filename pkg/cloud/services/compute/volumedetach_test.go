@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_detachVolumes(t *testing.T) {
+	const (
+		dataVolumeID  = "4d3a2c1b-0e9f-4a8b-9c7d-6e5f4a3b2c1d"
+		otherServerID = "7b6a5c4d-3e2f-4a1b-8c9d-0e1f2a3b4c5d"
+	)
+
+	type recorders struct {
+		compute *mock.MockComputeClientMockRecorder
+		volume  *mock.MockVolumeClientMockRecorder
+	}
+
+	retained := func(volumeID string) volumeattach.VolumeAttachment {
+		return volumeattach.VolumeAttachment{VolumeID: volumeID, ServerID: instanceUUID, DeleteOnTermination: pointer.Bool(false)}
+	}
+	attachment := func(serverID string) volumes.Attachment {
+		return volumes.Attachment{ServerID: serverID, VolumeID: dataVolumeID}
+	}
+
+	tests := []struct {
+		name       string
+		state      infrav1.InstanceState
+		rootVolume *infrav1.RootVolume
+		expect     func(r *recorders)
+		wantErr    error
+	}{
+		{
+			name:  "Volumes deleted with the server are not detached",
+			state: infrav1.InstanceStateActive,
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{
+					{VolumeID: volumeUUID, ServerID: instanceUUID, DeleteOnTermination: pointer.Bool(true)},
+					{VolumeID: dataVolumeID, ServerID: instanceUUID},
+				}, nil)
+			},
+		},
+		{
+			name:  "Volumes are not detached from servers in error state",
+			state: infrav1.InstanceStateError,
+		},
+		{
+			name:  "Volume is detached once its attachment is released",
+			state: infrav1.InstanceStateActive,
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{retained(dataVolumeID)}, nil)
+				r.compute.DetachVolume(gomock.Any(), instanceUUID, dataVolumeID).Return(nil)
+				gomock.InOrder(
+					r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(&volumes.Volume{ID: dataVolumeID, Status: "detaching", Attachments: []volumes.Attachment{attachment(instanceUUID)}}, nil),
+					r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(&volumes.Volume{ID: dataVolumeID, Status: "available"}, nil),
+				)
+			},
+		},
+		{
+			name:  "Detach is retried while Nova reports a conflict",
+			state: infrav1.InstanceStateActive,
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{retained(dataVolumeID)}, nil)
+				gomock.InOrder(
+					r.compute.DetachVolume(gomock.Any(), instanceUUID, dataVolumeID).Return(gophercloud.ErrDefault409{}),
+					r.compute.DetachVolume(gomock.Any(), instanceUUID, dataVolumeID).Return(nil),
+				)
+				r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(nil, gophercloud.ErrDefault404{})
+			},
+		},
+		{
+			name:  "Multiattach volume stays in use by other servers",
+			state: infrav1.InstanceStateActive,
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{retained(dataVolumeID)}, nil)
+				r.compute.DetachVolume(gomock.Any(), instanceUUID, dataVolumeID).Return(nil)
+				gomock.InOrder(
+					r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(&volumes.Volume{ID: dataVolumeID, Status: "in-use", Multiattach: true, Attachments: []volumes.Attachment{attachment(instanceUUID), attachment(otherServerID)}}, nil),
+					r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(&volumes.Volume{ID: dataVolumeID, Status: "in-use", Multiattach: true, Attachments: []volumes.Attachment{attachment(otherServerID)}}, nil),
+				)
+			},
+		},
+		{
+			name:  "Failed detach is terminal",
+			state: infrav1.InstanceStateActive,
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{retained(dataVolumeID)}, nil)
+				r.compute.DetachVolume(gomock.Any(), instanceUUID, dataVolumeID).Return(nil)
+				r.volume.GetVolume(gomock.Any(), dataVolumeID).Return(&volumes.Volume{ID: dataVolumeID, Status: "error_detaching", Attachments: []volumes.Attachment{attachment(instanceUUID)}}, nil)
+			},
+			wantErr: ErrVolumeDetachFailed,
+		},
+		{
+			name:  "Retained root volume is not detached",
+			state: infrav1.InstanceStateActive,
+			rootVolume: &infrav1.RootVolume{
+				Size:            50,
+				RetentionPolicy: infrav1.VolumeRetentionPolicyRetain,
+			},
+			expect: func(r *recorders) {
+				r.compute.ListVolumeAttachments(gomock.Any(), instanceUUID).Return([]volumeattach.VolumeAttachment{retained(volumeUUID)}, nil)
+				volumeName := fmt.Sprintf("%s-root", openStackMachineName)
+				r.volume.ListVolumes(gomock.Any(), volumes.ListOpts{Name: volumeName}).Return([]volumes.Volume{{ID: volumeUUID, Name: volumeName}}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := mock.NewMockComputeClient(mockCtrl)
+			mockVolumeClient := mock.NewMockVolumeClient(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(&recorders{mockComputeClient.EXPECT(), mockVolumeClient.EXPECT()})
+			}
+
+			s := Service{
+				scope: &scope.Scope{
+					Logger: logr.Discard(),
+				},
+				_computeClient: mockComputeClient,
+				_volumeClient:  mockVolumeClient,
+			}
+			instanceStatus := &InstanceStatus{
+				server: &clients.ServerExt{
+					Server: servers.Server{ID: instanceUUID, Name: openStackMachineName, Status: string(tt.state)},
+				},
+			}
+
+			err := s.detachVolumes(&infrav1.OpenStackMachine{}, instanceStatus, openStackMachineName, tt.rootVolume, time.Millisecond)
+			if tt.wantErr != nil {
+				g.Expect(errors.Is(err, tt.wantErr)).To(BeTrue(), "unexpected error: %v", err)
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
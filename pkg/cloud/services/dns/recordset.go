@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+const (
+	recordTypeA    = "A"
+	recordTypeAAAA = "AAAA"
+)
+
+// FQDN returns the name in the fully qualified form used by Designate, which ends with a dot.
+func FQDN(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ReconcileControlPlaneEndpointRecord creates or updates the A or AAAA record set of the
+// control plane endpoint, so that it points at the given address.
+func (s *Service) ReconcileControlPlaneEndpointRecord(openStackCluster *infrav1.OpenStackCluster, clusterName, address string) error {
+	spec := openStackCluster.Spec.ControlPlaneEndpointDNS
+	return s.reconcileRecord(openStackCluster, clusterName, spec.Zone, FQDN(spec.RecordName), spec.TTL, address)
+}
+
+// DeleteControlPlaneEndpointRecord deletes the A and AAAA record sets of the control plane endpoint.
+func (s *Service) DeleteControlPlaneEndpointRecord(openStackCluster *infrav1.OpenStackCluster) error {
+	spec := openStackCluster.Spec.ControlPlaneEndpointDNS
+	return s.deleteRecord(openStackCluster, spec.Zone, FQDN(spec.RecordName))
+}
+
+// ReconcileBastionRecord creates or updates the A or AAAA record set of the bastion, so that it
+// points at the given address. The record is recorded in the status of the cluster, and the
+// previous record is deleted if its name or zone changed.
+func (s *Service) ReconcileBastionRecord(openStackCluster *infrav1.OpenStackCluster, clusterName, address string) error {
+	spec := openStackCluster.Spec.Bastion.DNS
+	zone := FQDN(spec.Zone)
+
+	data := names.NewTemplateData(openStackCluster.Namespace, clusterName)
+	name, err := names.Render(spec.RecordName, fmt.Sprintf("%s-bastion.%s", data.ClusterName, zone), data)
+	if err != nil {
+		return err
+	}
+	name = FQDN(name)
+	if !strings.HasSuffix(name, "."+zone) {
+		return fmt.Errorf("DNS record %s is not in zone %s", name, spec.Zone)
+	}
+
+	if previous := openStackCluster.Status.BastionDNSRecord; previous != nil && (FQDN(previous.Zone) != zone || previous.Name != name) {
+		if err := s.DeleteBastionRecord(openStackCluster); err != nil {
+			return err
+		}
+	}
+
+	// The record is tracked before it is created so that it is deleted even if its creation
+	// is interrupted
+	openStackCluster.Status.BastionDNSRecord = &infrav1.DNSRecordReference{Zone: spec.Zone, Name: name}
+	return s.reconcileRecord(openStackCluster, clusterName, spec.Zone, name, spec.TTL, address)
+}
+
+// DeleteBastionRecord deletes the record set of the bastion recorded in the status of the cluster.
+func (s *Service) DeleteBastionRecord(openStackCluster *infrav1.OpenStackCluster) error {
+	dnsRecord := openStackCluster.Status.BastionDNSRecord
+	if dnsRecord == nil {
+		return nil
+	}
+	if err := s.deleteRecord(openStackCluster, dnsRecord.Zone, dnsRecord.Name); err != nil {
+		return err
+	}
+	openStackCluster.Status.BastionDNSRecord = nil
+	return nil
+}
+
+// reconcileRecord creates or updates the A or AAAA record set name in zone, so that it points at address.
+// The TTL of the zone is used if ttl is 0.
+func (s *Service) reconcileRecord(openStackCluster *infrav1.OpenStackCluster, clusterName, zoneName, name string, ttl int, address string) error {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return fmt.Errorf("cannot create DNS record %s for invalid address %q", name, address)
+	}
+	recordType := recordTypeA
+	if ip.To4() == nil {
+		recordType = recordTypeAAAA
+	}
+
+	zone, err := s.getZone(zoneName)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return fmt.Errorf("DNS zone %s does not exist", zoneName)
+	}
+
+	recordSets, err := s.client.ListRecordSets(s.scope.Context(), zone.ID, recordsets.ListOpts{Name: name, Type: recordType})
+	if err != nil {
+		return err
+	}
+
+	records := []string{address}
+	if len(recordSets) == 0 {
+		s.scope.Logger.Info("Creating DNS record", "name", name, "type", recordType, "address", address)
+		recordSet, err := s.client.CreateRecordSet(s.scope.Context(), zone.ID, recordsets.CreateOpts{
+			Name:        name,
+			Type:        recordType,
+			Records:     records,
+			TTL:         ttl,
+			Description: names.GetDescription(clusterName),
+		})
+		if err != nil {
+			record.Warnf(openStackCluster, "FailedCreateDNSRecord", "Failed to create DNS record %s: %v", name, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulCreateDNSRecord", "Created DNS record %s with id %s", name, recordSet.ID)
+		return nil
+	}
+
+	recordSet := recordSets[0]
+	if reflect.DeepEqual(recordSet.Records, records) && (ttl == 0 || recordSet.TTL == ttl) {
+		return nil
+	}
+
+	s.scope.Logger.Info("Updating DNS record", "name", name, "type", recordType, "address", address)
+	updateOpts := recordsets.UpdateOpts{Records: records}
+	if ttl != 0 {
+		updateOpts.TTL = &ttl
+	}
+	if _, err := s.client.UpdateRecordSet(s.scope.Context(), zone.ID, recordSet.ID, updateOpts); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateDNSRecord", "Failed to update DNS record %s with id %s: %v", name, recordSet.ID, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulUpdateDNSRecord", "Updated DNS record %s with id %s", name, recordSet.ID)
+	return nil
+}
+
+// deleteRecord deletes the A and AAAA record sets name in zone.
+func (s *Service) deleteRecord(openStackCluster *infrav1.OpenStackCluster, zoneName, name string) error {
+	zone, err := s.getZone(zoneName)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return nil
+	}
+
+	recordSets, err := s.client.ListRecordSets(s.scope.Context(), zone.ID, recordsets.ListOpts{Name: name})
+	if err != nil {
+		return err
+	}
+
+	for _, recordSet := range recordSets {
+		if recordSet.Type != recordTypeA && recordSet.Type != recordTypeAAAA {
+			continue
+		}
+		s.scope.Logger.Info("Deleting DNS record", "name", name, "type", recordSet.Type)
+		if err := s.client.DeleteRecordSet(s.scope.Context(), zone.ID, recordSet.ID); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(openStackCluster, "FailedDeleteDNSRecord", "Failed to delete DNS record %s with id %s: %v", name, recordSet.ID, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulDeleteDNSRecord", "Deleted DNS record %s with id %s", name, recordSet.ID)
+	}
+	return nil
+}
+
+func (s *Service) getZone(name string) (*zones.Zone, error) {
+	zoneList, err := s.client.ListZones(s.scope.Context(), zones.ListOpts{Name: FQDN(name)})
+	if err != nil {
+		return nil, err
+	}
+	switch len(zoneList) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &zoneList[0], nil
+	}
+	return nil, fmt.Errorf("found %d DNS zones with name %s", len(zoneList), name)
+}
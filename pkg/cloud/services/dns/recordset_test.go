@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+const (
+	zoneID      = "aaaaaaaa-bbbb-cccc-dddd-111111111111"
+	recordSetID = "aaaaaaaa-bbbb-cccc-dddd-222222222222"
+	recordName  = "api.cluster.example.com."
+)
+
+func dnsTestCluster(ttl int) *infrav1.OpenStackCluster {
+	return &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			ControlPlaneEndpointDNS: &infrav1.ControlPlaneEndpointDNS{
+				Zone:       "example.com",
+				RecordName: "api.cluster.example.com",
+				TTL:        ttl,
+			},
+		},
+	}
+}
+
+func Test_ReconcileControlPlaneEndpointRecord(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name    string
+		ttl     int
+		address string
+		expect  func(m *mock.MockDNSClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name:    "A record is created",
+			address: "172.24.4.10",
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: recordName, Type: "A"}).Return([]recordsets.RecordSet{}, nil)
+				m.CreateRecordSet(gomock.Any(), zoneID, recordsets.CreateOpts{
+					Name:        recordName,
+					Type:        "A",
+					Records:     []string{"172.24.4.10"},
+					Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+				}).Return(&recordsets.RecordSet{ID: recordSetID}, nil)
+			},
+		},
+		{
+			name:    "AAAA record is created with TTL",
+			ttl:     60,
+			address: "2001:db8::10",
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: recordName, Type: "AAAA"}).Return([]recordsets.RecordSet{}, nil)
+				m.CreateRecordSet(gomock.Any(), zoneID, recordsets.CreateOpts{
+					Name:        recordName,
+					Type:        "AAAA",
+					Records:     []string{"2001:db8::10"},
+					TTL:         60,
+					Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+				}).Return(&recordsets.RecordSet{ID: recordSetID}, nil)
+			},
+		},
+		{
+			name:    "up to date record is not changed",
+			address: "172.24.4.10",
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: recordName, Type: "A"}).Return([]recordsets.RecordSet{
+					{ID: recordSetID, Records: []string{"172.24.4.10"}, TTL: 3600},
+				}, nil)
+			},
+		},
+		{
+			name:    "record pointing at another address is updated",
+			address: "172.24.4.10",
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: recordName, Type: "A"}).Return([]recordsets.RecordSet{
+					{ID: recordSetID, Records: []string{"172.24.4.11"}, TTL: 3600},
+				}, nil)
+				m.UpdateRecordSet(gomock.Any(), zoneID, recordSetID, recordsets.UpdateOpts{Records: []string{"172.24.4.10"}}).Return(&recordsets.RecordSet{ID: recordSetID}, nil)
+			},
+		},
+		{
+			name:    "zone does not exist",
+			address: "172.24.4.10",
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock.NewMockDNSClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			err := s.ReconcileControlPlaneEndpointRecord(dnsTestCluster(tt.ttl), "test-cluster", tt.address)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_DeleteControlPlaneEndpointRecord(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	mockClient := mock.NewMockDNSClient(mockCtrl)
+	m := mockClient.EXPECT()
+	m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+	m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: recordName}).Return([]recordsets.RecordSet{
+		{ID: recordSetID, Type: "A"},
+		{ID: "txt-record", Type: "TXT"},
+	}, nil)
+	m.DeleteRecordSet(gomock.Any(), zoneID, recordSetID).Return(nil)
+
+	s := NewTestService(mockClient, logr.Discard())
+	g.Expect(s.DeleteControlPlaneEndpointRecord(dnsTestCluster(0))).To(Succeed())
+}
+
+func Test_ReconcileBastionRecord(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const bastionRecordName = "cluster-bastion.example.com."
+
+	tests := []struct {
+		name       string
+		dns        *infrav1.BastionDNS
+		previous   *infrav1.DNSRecordReference
+		expect     func(m *mock.MockDNSClientMockRecorder)
+		wantRecord *infrav1.DNSRecordReference
+		wantErr    bool
+	}{
+		{
+			name: "record with default name is created",
+			dns:  &infrav1.BastionDNS{Zone: "example.com", TTL: 60},
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: bastionRecordName, Type: "A"}).Return([]recordsets.RecordSet{}, nil)
+				m.CreateRecordSet(gomock.Any(), zoneID, recordsets.CreateOpts{
+					Name:        bastionRecordName,
+					Type:        "A",
+					Records:     []string{"172.24.4.20"},
+					TTL:         60,
+					Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+				}).Return(&recordsets.RecordSet{ID: recordSetID}, nil)
+			},
+			wantRecord: &infrav1.DNSRecordReference{Zone: "example.com", Name: bastionRecordName},
+		},
+		{
+			name:     "renamed record replaces the previous record",
+			dns:      &infrav1.BastionDNS{Zone: "example.com", RecordName: "jump.{{ .ClusterName }}.example.com"},
+			previous: &infrav1.DNSRecordReference{Zone: "example.com", Name: bastionRecordName},
+			expect: func(m *mock.MockDNSClientMockRecorder) {
+				m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil).Times(2)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: bastionRecordName}).Return([]recordsets.RecordSet{
+					{ID: recordSetID, Type: "A"},
+				}, nil)
+				m.DeleteRecordSet(gomock.Any(), zoneID, recordSetID).Return(nil)
+				m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: "jump.cluster.example.com.", Type: "A"}).Return([]recordsets.RecordSet{
+					{ID: recordSetID, Records: []string{"172.24.4.20"}},
+				}, nil)
+			},
+			wantRecord: &infrav1.DNSRecordReference{Zone: "example.com", Name: "jump.cluster.example.com."},
+		},
+		{
+			name:     "record outside of the zone",
+			dns:      &infrav1.BastionDNS{Zone: "example.com", RecordName: "{{ .ClusterName }}.example.org"},
+			previous: &infrav1.DNSRecordReference{Zone: "example.com", Name: bastionRecordName},
+			expect:   func(m *mock.MockDNSClientMockRecorder) {},
+			// The previous record is kept as the new one cannot be created
+			wantRecord: &infrav1.DNSRecordReference{Zone: "example.com", Name: bastionRecordName},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock.NewMockDNSClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					Bastion: &infrav1.Bastion{Enabled: true, DNS: tt.dns},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					BastionDNSRecord: tt.previous,
+				},
+			}
+			openStackCluster.Namespace = "test"
+
+			err := s.ReconcileBastionRecord(openStackCluster, "test-cluster", "172.24.4.20")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(openStackCluster.Status.BastionDNSRecord).To(Equal(tt.wantRecord))
+		})
+	}
+}
+
+func Test_DeleteBastionRecord(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := mock.NewMockDNSClient(mockCtrl)
+	m := mockClient.EXPECT()
+	m.ListZones(gomock.Any(), zones.ListOpts{Name: "example.com."}).Return([]zones.Zone{{ID: zoneID}}, nil)
+	m.ListRecordSets(gomock.Any(), zoneID, recordsets.ListOpts{Name: "cluster-bastion.example.com."}).Return([]recordsets.RecordSet{
+		{ID: recordSetID, Type: "A"},
+	}, nil)
+	m.DeleteRecordSet(gomock.Any(), zoneID, recordSetID).Return(nil)
+	s := NewTestService(mockClient, logr.Discard())
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			BastionDNSRecord: &infrav1.DNSRecordReference{Zone: "example.com", Name: "cluster-bastion.example.com."},
+		},
+	}
+	g.Expect(s.DeleteBastionRecord(openStackCluster)).To(Succeed())
+	g.Expect(openStackCluster.Status.BastionDNSRecord).To(BeNil())
+
+	// Without a record nothing is deleted
+	g.Expect(s.DeleteBastionRecord(openStackCluster)).To(Succeed())
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imageimport"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	defaultContainerFormat = "bare"
+	defaultVisibility      = images.ImageVisibilityPrivate
+)
+
+// ReconcileImage creates the Glance image of the OpenStackImage, or adopts an image with its name in the project,
+// imports its data and keeps its visibility, tags and properties up to date. It records the image in the status
+// of the OpenStackImage, which is ready once the image is active.
+func (s *Service) ReconcileImage(openStackImage *infrav1.OpenStackImage) error {
+	image, err := s.getOrCreateImage(openStackImage)
+	if err != nil {
+		return err
+	}
+	openStackImage.Status.ImageID = image.ID
+	openStackImage.Status.Status = string(image.Status)
+	openStackImage.Status.Ready = false
+
+	switch image.Status {
+	case images.ImageStatusQueued:
+		if err := s.importImageData(openStackImage, image); err != nil {
+			return err
+		}
+	case images.ImageStatusActive:
+		if err := s.updateImage(openStackImage, image); err != nil {
+			return err
+		}
+		openStackImage.Status.Ready = true
+	case images.ImageStatusKilled, images.ImageStatusDeactivated, images.ImageStatusDeleted, images.ImageStatusPendingDelete:
+		return fmt.Errorf("image %s is %s", image.ID, image.Status)
+	}
+	return nil
+}
+
+// DeleteImage deletes the Glance image of the OpenStackImage.
+func (s *Service) DeleteImage(openStackImage *infrav1.OpenStackImage) error {
+	imageID := openStackImage.Status.ImageID
+	if imageID == "" {
+		return nil
+	}
+
+	err := s.client.DeleteImage(s.scope.Context(), imageID)
+	if err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(openStackImage, "FailedDeleteImage", "Failed to delete image %s: %v", imageID, err)
+		return err
+	}
+	record.Eventf(openStackImage, "SuccessfulDeleteImage", "Deleted image %s", imageID)
+	openStackImage.Status.ImageID = ""
+	return nil
+}
+
+func imageName(openStackImage *infrav1.OpenStackImage) string {
+	if openStackImage.Spec.Name != "" {
+		return openStackImage.Spec.Name
+	}
+	return openStackImage.Name
+}
+
+func (s *Service) getOrCreateImage(openStackImage *infrav1.OpenStackImage) (*images.Image, error) {
+	if openStackImage.Status.ImageID != "" {
+		image, err := s.client.GetImage(s.scope.Context(), openStackImage.Status.ImageID)
+		if err == nil {
+			return image, nil
+		}
+		if !capoerrors.IsNotFound(err) {
+			return nil, err
+		}
+		s.scope.Logger.Info("Image was deleted, recreating it", "imageID", openStackImage.Status.ImageID)
+	}
+
+	name := imageName(openStackImage)
+	imageList, err := s.client.ListImages(s.scope.Context(), images.ListOpts{Name: name, Owner: s.scope.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	switch len(imageList) {
+	case 0:
+	case 1:
+		s.scope.Logger.Info("Adopting existing image", "name", name, "imageID", imageList[0].ID)
+		return &imageList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d images with the name %s", len(imageList), name)
+	}
+
+	containerFormat := openStackImage.Spec.ContainerFormat
+	if containerFormat == "" {
+		containerFormat = defaultContainerFormat
+	}
+	visibility := imageVisibility(openStackImage)
+	image, err := s.client.CreateImage(s.scope.Context(), images.CreateOpts{
+		Name:            name,
+		ContainerFormat: containerFormat,
+		DiskFormat:      openStackImage.Spec.DiskFormat,
+		Visibility:      &visibility,
+		Tags:            openStackImage.Spec.Tags,
+		Properties:      openStackImage.Spec.Properties,
+	})
+	if err != nil {
+		record.Warnf(openStackImage, "FailedCreateImage", "Failed to create image %s: %v", name, err)
+		return nil, err
+	}
+	record.Eventf(openStackImage, "SuccessfulCreateImage", "Created image %s with id %s", name, image.ID)
+	return image, nil
+}
+
+func imageVisibility(openStackImage *infrav1.OpenStackImage) images.ImageVisibility {
+	if openStackImage.Spec.Visibility == "" {
+		return defaultVisibility
+	}
+	return images.ImageVisibility(openStackImage.Spec.Visibility)
+}
+
+// importImageData imports the data of a queued image from the URL of the OpenStackImage. With the web-download
+// import method Glance downloads the data asynchronously, whereas with the upload method the data is streamed
+// through the controller before it returns.
+func (s *Service) importImageData(openStackImage *infrav1.OpenStackImage, image *images.Image) error {
+	url := openStackImage.Spec.URL
+	if openStackImage.Spec.ImportMethod == infrav1.ImageImportMethodUpload {
+		s.scope.Logger.Info("Uploading image data", "imageID", image.ID, "url", url)
+		if err := s.uploadImageData(image.ID, url); err != nil {
+			record.Warnf(openStackImage, "FailedUploadImage", "Failed to upload data of image %s from %s: %v", image.ID, url, err)
+			return err
+		}
+		record.Eventf(openStackImage, "SuccessfulUploadImage", "Uploaded data of image %s from %s", image.ID, url)
+		openStackImage.Status.Status = string(images.ImageStatusSaving)
+		return nil
+	}
+
+	s.scope.Logger.Info("Importing image data", "imageID", image.ID, "url", url)
+	if err := s.client.ImportImage(s.scope.Context(), image.ID, imageimport.CreateOpts{
+		Name: imageimport.WebDownloadMethod,
+		URI:  url,
+	}); err != nil {
+		record.Warnf(openStackImage, "FailedImportImage", "Failed to import data of image %s from %s: %v", image.ID, url, err)
+		return err
+	}
+	record.Eventf(openStackImage, "SuccessfulImportImage", "Started import of data of image %s from %s", image.ID, url)
+	openStackImage.Status.Status = string(images.ImageStatusImporting)
+	return nil
+}
+
+func (s *Service) uploadImageData(imageID, url string) error {
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download image data: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download image data: %s", resp.Status)
+	}
+
+	// The length of the data is not known in advance, so the body is sent to Glance in chunks
+	return s.client.UploadImageData(s.scope.Context(), imageID, resp.Body)
+}
+
+// updateImage updates the visibility, tags and properties of an active image which differ from the spec.
+func (s *Service) updateImage(openStackImage *infrav1.OpenStackImage, image *images.Image) error {
+	var opts images.UpdateOpts
+	if visibility := imageVisibility(openStackImage); image.Visibility != visibility {
+		opts = append(opts, images.UpdateVisibility{Visibility: visibility})
+	}
+	if openStackImage.Spec.Tags != nil && !equalTags(image.Tags, openStackImage.Spec.Tags) {
+		opts = append(opts, images.ReplaceImageTags{NewTags: openStackImage.Spec.Tags})
+	}
+	names := make([]string, 0, len(openStackImage.Spec.Properties))
+	for name := range openStackImage.Spec.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := openStackImage.Spec.Properties[name]
+		current, ok := image.Properties[name]
+		switch {
+		case !ok:
+			opts = append(opts, images.UpdateImageProperty{Op: images.AddOp, Name: name, Value: value})
+		case fmt.Sprint(current) != value:
+			opts = append(opts, images.UpdateImageProperty{Op: images.ReplaceOp, Name: name, Value: value})
+		}
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+
+	if _, err := s.client.UpdateImage(s.scope.Context(), image.ID, opts); err != nil {
+		record.Warnf(openStackImage, "FailedUpdateImage", "Failed to update image %s: %v", image.ID, err)
+		return err
+	}
+	record.Eventf(openStackImage, "SuccessfulUpdateImage", "Updated image %s", image.ID)
+	return nil
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	tags := make(map[string]bool, len(a))
+	for _, tag := range a {
+		tags[tag] = true
+	}
+	for _, tag := range b {
+		if !tags[tag] {
+			return false
+		}
+	}
+	return true
+}
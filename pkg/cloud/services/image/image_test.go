@@ -0,0 +1,273 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imageimport"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+const (
+	projectID = "8d1a9cb5-6c2f-4c4b-9d2e-5f0b0e6a1c11"
+	imageID   = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+)
+
+func Test_ReconcileImage(t *testing.T) {
+	imageData := "image data"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ubuntu.qcow2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(imageData))
+	}))
+	defer server.Close()
+
+	newImage := func(importMethod infrav1.ImageImportMethod, url string) *infrav1.OpenStackImage {
+		return &infrav1.OpenStackImage{
+			ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: "test"},
+			Spec: infrav1.OpenStackImageSpec{
+				URL:          url,
+				ImportMethod: importMethod,
+				DiskFormat:   "qcow2",
+				Properties:   map[string]string{"hw_disk_bus": "scsi"},
+			},
+		}
+	}
+	private := images.ImageVisibilityPrivate
+	createOpts := images.CreateOpts{
+		Name:            "ubuntu",
+		ContainerFormat: "bare",
+		DiskFormat:      "qcow2",
+		Visibility:      &private,
+		Properties:      map[string]string{"hw_disk_bus": "scsi"},
+	}
+
+	tests := []struct {
+		name           string
+		openStackImage *infrav1.OpenStackImage
+		expect         func(m *mock.MockImageClientMockRecorder)
+		wantStatus     string
+		wantReady      bool
+		wantErr        bool
+	}{
+		{
+			name:           "Image is created and imported with web-download",
+			openStackImage: newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2"),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{}, nil)
+				m.CreateImage(gomock.Any(), createOpts).Return(&images.Image{ID: imageID, Status: images.ImageStatusQueued}, nil)
+				m.ImportImage(gomock.Any(), imageID, imageimport.CreateOpts{
+					Name: imageimport.WebDownloadMethod,
+					URI:  "https://images.example.com/ubuntu.qcow2",
+				}).Return(nil)
+			},
+			wantStatus: "importing",
+		},
+		{
+			name:           "Image is created and uploaded",
+			openStackImage: newImage(infrav1.ImageImportMethodUpload, server.URL+"/ubuntu.qcow2"),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{}, nil)
+				m.CreateImage(gomock.Any(), createOpts).Return(&images.Image{ID: imageID, Status: images.ImageStatusQueued}, nil)
+				m.UploadImageData(gomock.Any(), imageID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data io.Reader) error {
+					b, err := io.ReadAll(data)
+					if err != nil || string(b) != imageData {
+						t.Errorf("unexpected image data %q: %v", b, err)
+					}
+					return nil
+				})
+			},
+			wantStatus: "saving",
+		},
+		{
+			name:           "Download of image data fails",
+			openStackImage: newImage(infrav1.ImageImportMethodUpload, server.URL+"/missing.qcow2"),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{}, nil)
+				m.CreateImage(gomock.Any(), createOpts).Return(&images.Image{ID: imageID, Status: images.ImageStatusQueued}, nil)
+			},
+			wantStatus: "queued",
+			wantErr:    true,
+		},
+		{
+			name: "Existing image is adopted and updated",
+			openStackImage: func() *infrav1.OpenStackImage {
+				openStackImage := newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2")
+				openStackImage.Spec.Visibility = "public"
+				openStackImage.Spec.Properties["os_distro"] = "ubuntu"
+				return openStackImage
+			}(),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{{
+					ID:         imageID,
+					Status:     images.ImageStatusActive,
+					Visibility: images.ImageVisibilityPrivate,
+					Properties: map[string]interface{}{"hw_disk_bus": "virtio"},
+				}}, nil)
+				m.UpdateImage(gomock.Any(), imageID, images.UpdateOpts{
+					images.UpdateVisibility{Visibility: images.ImageVisibilityPublic},
+					images.UpdateImageProperty{Op: images.ReplaceOp, Name: "hw_disk_bus", Value: "scsi"},
+					images.UpdateImageProperty{Op: images.AddOp, Name: "os_distro", Value: "ubuntu"},
+				}).Return(&images.Image{ID: imageID}, nil)
+			},
+			wantStatus: "active",
+			wantReady:  true,
+		},
+		{
+			name: "Active image is up to date",
+			openStackImage: func() *infrav1.OpenStackImage {
+				openStackImage := newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2")
+				openStackImage.Status.ImageID = imageID
+				return openStackImage
+			}(),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.GetImage(gomock.Any(), imageID).Return(&images.Image{
+					ID:         imageID,
+					Status:     images.ImageStatusActive,
+					Visibility: images.ImageVisibilityPrivate,
+					Properties: map[string]interface{}{"hw_disk_bus": "scsi"},
+				}, nil)
+			},
+			wantStatus: "active",
+			wantReady:  true,
+		},
+		{
+			name: "Deleted image is recreated",
+			openStackImage: func() *infrav1.OpenStackImage {
+				openStackImage := newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2")
+				openStackImage.Status.ImageID = "deleted-image"
+				return openStackImage
+			}(),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.GetImage(gomock.Any(), "deleted-image").Return(nil, gophercloud.ErrDefault404{})
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{}, nil)
+				m.CreateImage(gomock.Any(), createOpts).Return(&images.Image{ID: imageID, Status: images.ImageStatusQueued}, nil)
+				m.ImportImage(gomock.Any(), imageID, gomock.Any()).Return(nil)
+			},
+			wantStatus: "importing",
+		},
+		{
+			name: "Image import was killed",
+			openStackImage: func() *infrav1.OpenStackImage {
+				openStackImage := newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2")
+				openStackImage.Status.ImageID = imageID
+				return openStackImage
+			}(),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.GetImage(gomock.Any(), imageID).Return(&images.Image{ID: imageID, Status: images.ImageStatusKilled}, nil)
+			},
+			wantStatus: "killed",
+			wantErr:    true,
+		},
+		{
+			name:           "Several images with the name",
+			openStackImage: newImage(infrav1.ImageImportMethodWebDownload, "https://images.example.com/ubuntu.qcow2"),
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu", Owner: projectID}).Return([]images.Image{{ID: "image-1"}, {ID: "image-2"}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockImageClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+
+			s := NewTestService(projectID, mockClient, server.Client(), logr.Discard())
+			err := s.ReconcileImage(tt.openStackImage)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(tt.openStackImage.Status.Status).To(Equal(tt.wantStatus))
+			g.Expect(tt.openStackImage.Status.Ready).To(Equal(tt.wantReady))
+		})
+	}
+}
+
+func Test_DeleteImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		imageID string
+		expect  func(m *mock.MockImageClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name:   "No image",
+			expect: func(m *mock.MockImageClientMockRecorder) {},
+		},
+		{
+			name:    "Image is deleted",
+			imageID: imageID,
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.DeleteImage(gomock.Any(), imageID).Return(nil)
+			},
+		},
+		{
+			name:    "Image was already deleted",
+			imageID: imageID,
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.DeleteImage(gomock.Any(), imageID).Return(gophercloud.ErrDefault404{})
+			},
+		},
+		{
+			name:    "Deleting image fails",
+			imageID: imageID,
+			expect: func(m *mock.MockImageClientMockRecorder) {
+				m.DeleteImage(gomock.Any(), imageID).Return(gophercloud.ErrDefault409{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockImageClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+
+			openStackImage := &infrav1.OpenStackImage{Status: infrav1.OpenStackImageStatus{ImageID: tt.imageID}}
+			s := NewTestService(projectID, mockClient, http.DefaultClient, logr.Discard())
+			err := s.DeleteImage(openStackImage)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(openStackImage.Status.ImageID).To(Equal(tt.imageID))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackImage.Status.ImageID).To(BeEmpty())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"net/http"
+
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// Service interfaces with the OpenStack Glance API.
+type Service struct {
+	scope  *scope.Scope
+	client clients.ImageClient
+	// httpClient downloads the data of images which are uploaded by the controller.
+	httpClient *http.Client
+}
+
+// NewService returns an instance of the image service.
+func NewService(scope *scope.Scope) (*Service, error) {
+	client, err := clients.NewImageClient(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		scope:      scope,
+		client:     client,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// NewTestService returns a Service with no initialization. It should only be used by tests.
+func NewTestService(projectID string, client clients.ImageClient, httpClient *http.Client, logger logr.Logger) *Service {
+	return &Service{
+		scope: &scope.Scope{
+			ProjectID: projectID,
+			Logger:    logger,
+		},
+		client:     client,
+		httpClient: httpClient,
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+const additionalPortsLBSuffix = "additional"
+
+func getAdditionalPortsLoadBalancerName(openStackCluster *infrav1.OpenStackCluster, clusterName string) (string, error) {
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s", loadBalancerName, additionalPortsLBSuffix), nil
+}
+
+// isAdditionalPort returns true if the port is one of the additional ports of the API server load balancer.
+func isAdditionalPort(openStackCluster *infrav1.OpenStackCluster, port int) bool {
+	return port != int(openStackCluster.Spec.ControlPlaneEndpoint.Port)
+}
+
+// reconcileAdditionalPortsLoadBalancer reconciles the load balancer which serves the additional
+// ports with their own flavor, and records it in the status.
+func (s *Service) reconcileAdditionalPortsLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName, vipSubnetID, lbProvider, flavorID string, lbMethod pools.LBMethod, allowedCIDRsSupported, tagsSupported, timeoutsSupported bool) error {
+	loadBalancerName, err := getAdditionalPortsLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	s.scope.Logger.Info("Reconciling additional ports load balancer", "name", loadBalancerName)
+
+	lb, err := s.getOrCreateLoadBalancer(openStackCluster, loadBalancerName, vipSubnetID, clusterName, "", lbProvider, flavorID, getObjectTag(clusterName, "kubeapi-additional", tagsSupported))
+	if err != nil {
+		return err
+	}
+	if err := s.waitForLoadBalancerActive(lb.ID); err != nil {
+		return fmt.Errorf("load balancer %q with id %s is not active after timeout: %v", loadBalancerName, lb.ID, err)
+	}
+
+	_, allowedCIDRs, err := s.reconcileListeners(openStackCluster, clusterName, loadBalancerName, lb.ID, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts, 0, "", lbMethod, allowedCIDRsSupported, tagsSupported, timeoutsSupported)
+	if err != nil {
+		return err
+	}
+
+	var lbFloatingIP string
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		fp, err := s.networkingService.GetFloatingIPByPortID(lb.VipPortID)
+		if err != nil {
+			return err
+		}
+		if fp == nil {
+			var floatingIPAddress string
+			if status := openStackCluster.Status.Network.AdditionalPortsLoadBalancer; status != nil {
+				floatingIPAddress = status.IP
+			}
+			fp, err = s.networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIPAddress)
+			if err != nil {
+				return err
+			}
+			if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
+				return err
+			}
+		}
+		lbFloatingIP = fp.FloatingIP
+	}
+
+	openStackCluster.Status.Network.AdditionalPortsLoadBalancer = &infrav1.LoadBalancer{
+		Name:         lb.Name,
+		ID:           lb.ID,
+		InternalIP:   lb.VipAddress,
+		IP:           lbFloatingIP,
+		AllowedCIDRs: allowedCIDRs,
+	}
+	return nil
+}
+
+// getAdditionalPortsLoadBalancer returns the load balancer serving the additional ports, or nil if
+// the additional ports are served by the API server load balancer.
+func (s *Service) getAdditionalPortsLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) (*loadbalancers.LoadBalancer, error) {
+	// Avoid the lookup for clusters which never had a separate load balancer
+	if openStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor == nil &&
+		(openStackCluster.Status.Network == nil || openStackCluster.Status.Network.AdditionalPortsLoadBalancer == nil) {
+		return nil, nil
+	}
+
+	loadBalancerName, err := getAdditionalPortsLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return s.checkIfLbExists(loadBalancerName)
+}
+
+// deleteAdditionalPortsLoadBalancer deletes the load balancer serving the additional ports and its
+// floating IP, if it exists.
+func (s *Service) deleteAdditionalPortsLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	lb, err := s.getAdditionalPortsLoadBalancer(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	if lb != nil {
+		if err := s.deleteLoadBalancerAndFloatingIP(openStackCluster, lb, false); err != nil {
+			return err
+		}
+	}
+
+	if openStackCluster.Status.Network != nil {
+		openStackCluster.Status.Network.AdditionalPortsLoadBalancer = nil
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+)
+
+func Test_getAdditionalPortsFlavorID(t *testing.T) {
+	tests := []struct {
+		name       string
+		lbSpec     infrav1.APIServerLoadBalancer
+		lbProvider string
+		expect     func(m *mock.MockLbClientMockRecorder)
+		want       string
+	}{
+		{
+			name:       "no flavor requested",
+			lbSpec:     infrav1.APIServerLoadBalancer{AdditionalPorts: []int{443}},
+			lbProvider: "amphora",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "",
+		},
+		{
+			name: "flavor without additional ports",
+			lbSpec: infrav1.APIServerLoadBalancer{
+				AdditionalPortsFlavor: &infrav1.LoadBalancerFlavor{ID: "single-id"},
+			},
+			lbProvider: "amphora",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "",
+		},
+		{
+			name: "flavor by name",
+			lbSpec: infrav1.APIServerLoadBalancer{
+				AdditionalPorts:       []int{443},
+				FlavorName:            "ha",
+				AdditionalPortsFlavor: &infrav1.LoadBalancerFlavor{Name: "single"},
+			},
+			lbProvider: "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerFlavors(gomock.Any()).Return([]clients.LoadBalancerFlavor{
+					{ID: "ha-id", Name: "ha"},
+					{ID: "single-id", Name: "single"},
+				}, nil)
+			},
+			want: "single-id",
+		},
+		{
+			name: "flavors are ignored for the ovn provider",
+			lbSpec: infrav1.APIServerLoadBalancer{
+				AdditionalPorts:       []int{443},
+				AdditionalPortsFlavor: &infrav1.LoadBalancerFlavor{ID: "single-id"},
+			},
+			lbProvider: "ovn",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{APIServerLoadBalancer: tt.lbSpec},
+			}
+			got, err := lbs.getAdditionalPortsFlavorID(openStackCluster, "2.24", tt.lbProvider)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_deleteLoadBalancerWithAdditionalPorts(t *testing.T) {
+	const (
+		additionalLBID      = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		additionalVIPPortID = "aaaaaaaa-bbbb-cccc-dddd-777777777777"
+		fipID               = "aaaaaaaa-bbbb-cccc-dddd-888888888888"
+		floatingIP          = "203.0.113.20"
+	)
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	lbClient := mock.NewMockLbClient(mockCtrl)
+	networkClient := mock.NewMockNetworkClient(mockCtrl)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+				Enabled:               true,
+				AdditionalPorts:       []int{443},
+				AdditionalPortsFlavor: &infrav1.LoadBalancerFlavor{Name: "single"},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				AdditionalPortsLoadBalancer: &infrav1.LoadBalancer{ID: additionalLBID, IP: floatingIP},
+			},
+		},
+	}
+	additionalLB := loadbalancers.LoadBalancer{ID: additionalLBID, Name: "k8s-clusterapi-cluster-AAAAA-kubeapi-additional", VipPortID: additionalVIPPortID}
+	fip := floatingips.FloatingIP{ID: fipID, FloatingIP: floatingIP, PortID: additionalVIPPortID}
+
+	// The load balancer of the additional ports and its floating IP are deleted first
+	lbClient.EXPECT().ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: additionalLB.Name}).Return([]loadbalancers.LoadBalancer{additionalLB}, nil)
+	networkClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: additionalVIPPortID}).Return([]floatingips.FloatingIP{fip}, nil)
+	networkClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: floatingIP}).Return([]floatingips.FloatingIP{fip}, nil).Times(2)
+	networkClient.EXPECT().GetFloatingIPWithRevision(gomock.Any(), fipID).Return(&fip, 2, nil)
+	networkClient.EXPECT().UpdateFloatingIPWithRevision(gomock.Any(), fipID, 2, gomock.Any()).Return(&fip, nil)
+	networkClient.EXPECT().GetFloatingIP(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, Status: "DOWN"}, nil)
+	networkClient.EXPECT().DeleteFloatingIP(gomock.Any(), fipID).Return(nil)
+	lbClient.EXPECT().DeleteLoadBalancer(gomock.Any(), additionalLBID, loadbalancers.DeleteOpts{Cascade: true}).Return(nil)
+
+	// The API server load balancer is already gone
+	lbClient.EXPECT().ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: "k8s-clusterapi-cluster-AAAAA-kubeapi"}).Return([]loadbalancers.LoadBalancer{}, nil)
+
+	networkingService := networking.NewTestService("", networkClient, logr.Discard())
+	lbs := NewLoadBalancerTestService("", lbClient, networkingService, logr.Discard())
+	g.Expect(lbs.DeleteLoadBalancer(openStackCluster, "AAAAA")).To(Succeed())
+	g.Expect(openStackCluster.Status.Network.AdditionalPortsLoadBalancer).To(BeNil())
+}
+
+func Test_isAdditionalPort(t *testing.T) {
+	g := NewWithT(t)
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{AdditionalPorts: []int{443, 8443}},
+		},
+	}
+	openStackCluster.Spec.ControlPlaneEndpoint.Port = 6443
+
+	g.Expect(isAdditionalPort(openStackCluster, 6443)).To(BeFalse())
+	g.Expect(isAdditionalPort(openStackCluster, 443)).To(BeTrue())
+	g.Expect(isAdditionalPort(openStackCluster, 8443)).To(BeTrue())
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// LoadBalancerResource is the type of the resources returned by ListClusterLoadBalancers.
+const LoadBalancerResource = "loadbalancer"
+
+// ListClusterLoadBalancers returns the IDs of the load balancers created for the cluster which still exist. Existing
+// and shared load balancers are not created for the cluster and are never returned. It is used to verify that the
+// deletion of a cluster left nothing behind.
+func (s *Service) ListClusterLoadBalancers(openStackCluster *infrav1.OpenStackCluster, clusterName string) ([]string, error) {
+	if openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer != nil || openStackCluster.Spec.APIServerLoadBalancer.Shared != nil {
+		return nil, nil
+	}
+
+	var ids []string
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	lb, err := s.checkIfLbExists(loadBalancerName)
+	if err != nil {
+		return nil, err
+	}
+	if lb != nil {
+		ids = append(ids, lb.ID)
+	}
+
+	lb, err = s.getAdditionalPortsLoadBalancer(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if lb != nil {
+		ids = append(ids, lb.ID)
+	}
+	return ids, nil
+}
@@ -20,22 +20,26 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/net"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/egress"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 	openstackutil "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/openstack"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
 	capostrings "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/strings"
 )
 
@@ -43,12 +47,32 @@ const (
 	networkPrefix               string = "k8s-clusterapi"
 	kubeapiLBSuffix             string = "kubeapi"
 	defaultLoadBalancerProvider string = "amphora"
+	ovnLoadBalancerProvider     string = "ovn"
 )
 
+const (
+	defaultMonitorDelay      = 30
+	defaultMonitorTimeout    = 5
+	defaultMonitorMaxRetries = 3
+)
+
+// lbMethodSourceIPPort is the only load balancing algorithm supported by the OVN provider.
+const lbMethodSourceIPPort pools.LBMethod = "SOURCE_IP_PORT"
+
 const loadBalancerProvisioningStatusActive = "ACTIVE"
 
 func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string, apiServerPort int) error {
-	loadBalancerName := getLoadBalancerName(clusterName)
+	if openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer != nil {
+		return s.reconcileExistingLoadBalancer(openStackCluster, apiServerPort)
+	}
+	if openStackCluster.Spec.APIServerLoadBalancer.Shared != nil {
+		return s.reconcileSharedLoadBalancer(openStackCluster, clusterName)
+	}
+
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	s.scope.Logger.Info("Reconciling load balancer", "name", loadBalancerName)
 
 	var fixedIPAddress string
@@ -56,25 +80,59 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 	case openStackCluster.Spec.APIServerFixedIP != "":
 		fixedIPAddress = openStackCluster.Spec.APIServerFixedIP
 	case openStackCluster.Spec.DisableAPIServerFloatingIP && openStackCluster.Spec.ControlPlaneEndpoint.IsValid():
-		fixedIPAddress = openStackCluster.Spec.ControlPlaneEndpoint.Host
+		fixedIPAddress = networking.GetControlPlaneEndpointAddress(openStackCluster)
 	}
 
-	providers, err := s.loadbalancerClient.ListLoadBalancerProviders()
+	lbProvider, err := s.getLoadBalancerProvider(openStackCluster)
 	if err != nil {
 		return err
 	}
 
-	// As mostly all LoadBalancer features are only supported on "amphora" we explicitly set the provider
-	// in the LoadBalancer create call to make sure to get the desired features - even if multiple providers exist.
-	var lbProvider string
-	for _, v := range providers {
-		if v.Name == defaultLoadBalancerProvider {
-			lbProvider = v.Name
-			break
+	// To reduce API calls towards OpenStack API, let's handle the feature support verification for all Ports only once.
+	octaviaVersions, err := s.loadbalancerClient.ListOctaviaVersions(s.scope.Context())
+	if err != nil {
+		return err
+	}
+	// The current version is always the last one in the list.
+	octaviaVersion := octaviaVersions[len(octaviaVersions)-1].ID
+
+	flavorID, err := s.getLoadBalancerFlavorID(openStackCluster, octaviaVersion, lbProvider)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateLoadBalancerAvailabilityZone(openStackCluster, octaviaVersion, lbProvider); err != nil {
+		return err
+	}
+
+	var tlsContainerRef string
+	if tls := openStackCluster.Spec.APIServerLoadBalancer.TLS; tls != nil {
+		if !openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureTLSTermination, lbProvider) {
+			return fmt.Errorf("load balancer provider %q does not support TLS termination", lbProvider)
 		}
+		tlsContainerRef, err = s.getTLSContainerRef(tls)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The webhook rejects timeouts with the ovn provider, which can still be the default provider of the cloud
+	timeoutsSupported := openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureTimeout, lbProvider)
+	if lbSpec := openStackCluster.Spec.APIServerLoadBalancer; !timeoutsSupported && (lbSpec.TimeoutClientData != 0 || lbSpec.TimeoutMemberData != 0 || lbSpec.TimeoutMemberConnect != 0) {
+		record.Warnf(openStackCluster, "UnsupportedListenerTimeouts", "Load balancer provider %q does not support listener timeouts, not setting the timeouts of load balancer %s", lbProvider, loadBalancerName)
+	}
+
+	vipSubnetID := openStackCluster.Status.Network.Subnet.ID
+	if openStackCluster.Spec.APIServerLoadBalancer.IPVersion == 6 {
+		if openStackCluster.Status.Network.IPv6Subnet == nil {
+			return fmt.Errorf("an IPv6 VIP requires the IPv6 subnet of the cluster network")
+		}
+		vipSubnetID = openStackCluster.Status.Network.IPv6Subnet.ID
 	}
 
-	lb, err := s.getOrCreateLoadBalancer(openStackCluster, loadBalancerName, openStackCluster.Status.Network.Subnet.ID, clusterName, fixedIPAddress, lbProvider)
+	tagsSupported := openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureTags, lbProvider)
+
+	lb, err := s.getOrCreateLoadBalancer(openStackCluster, loadBalancerName, vipSubnetID, clusterName, fixedIPAddress, lbProvider, flavorID, getObjectTag(clusterName, "kubeapi", tagsSupported))
 	if err != nil {
 		return err
 	}
@@ -82,6 +140,46 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 		return fmt.Errorf("load balancer %q with id %s is not active after timeout: %v", loadBalancerName, lb.ID, err)
 	}
 
+	allowedCIDRsSupported := false
+	if openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureVIPACL, lbProvider) {
+		allowedCIDRsSupported = true
+	} else if len(openStackCluster.Spec.APIServerLoadBalancer.AllowedCIDRs) > 0 {
+		record.Warnf(openStackCluster, "UnsupportedAllowedCIDRs", "Load balancer provider %q does not support allowed CIDRs, not restricting access to load balancer %s", lbProvider, loadBalancerName)
+	}
+
+	lbMethod := pools.LBMethodRoundRobin
+	if lbProvider == ovnLoadBalancerProvider {
+		lbMethod = lbMethodSourceIPPort
+	}
+
+	// A flavor applies to a whole load balancer, so the additional ports are served by a separate
+	// load balancer if they use a different flavor.
+	additionalPortsFlavorID, err := s.getAdditionalPortsFlavorID(openStackCluster, octaviaVersion, lbProvider)
+	if err != nil {
+		return err
+	}
+	separateAdditionalPorts := additionalPortsFlavorID != "" && additionalPortsFlavorID != flavorID
+
+	portList := []int{apiServerPort}
+	if !separateAdditionalPorts {
+		portList = append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
+	}
+	apiServerPoolID, allowedCIDRs, err := s.reconcileListeners(openStackCluster, clusterName, loadBalancerName, lb.ID, portList, apiServerPort, tlsContainerRef, lbMethod, allowedCIDRsSupported, tagsSupported, timeoutsSupported)
+	if err != nil {
+		return err
+	}
+
+	if separateAdditionalPorts {
+		if err := s.reconcileAdditionalPortsLoadBalancer(openStackCluster, clusterName, vipSubnetID, lbProvider, additionalPortsFlavorID, lbMethod, allowedCIDRsSupported, tagsSupported, timeoutsSupported); err != nil {
+			return err
+		}
+	} else if openStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor != nil {
+		// The flavor of the additional ports resolves to the flavor of the API server load balancer
+		if err := s.deleteAdditionalPortsLoadBalancer(openStackCluster, clusterName); err != nil {
+			return err
+		}
+	}
+
 	var lbFloatingIP string
 	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
 		var floatingIPAddress string
@@ -89,60 +187,144 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 		case openStackCluster.Spec.APIServerFloatingIP != "":
 			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
 		case openStackCluster.Spec.ControlPlaneEndpoint.IsValid():
-			floatingIPAddress = openStackCluster.Spec.ControlPlaneEndpoint.Host
+			floatingIPAddress = networking.GetControlPlaneEndpointAddress(openStackCluster)
+		default:
+			floatingIPAddress = networking.GetClaimedFloatingIP(openStackCluster, networking.FloatingIPUseAPIServer)
 		}
 		fp, err := s.networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIPAddress)
 		if err != nil {
 			return err
 		}
-		if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
-			return err
+
+		// When a cluster is switched to a load balancer, its endpoint is still served by a
+		// control plane machine. The floating IP is only moved once the load balancer can
+		// serve the API server.
+		moveFloatingIP := true
+		if fp.PortID != "" && fp.PortID != lb.VipPortID && openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+			moveFloatingIP, err = s.hasOnlineMember(apiServerPoolID)
+			if err != nil {
+				return err
+			}
+		}
+		if moveFloatingIP {
+			if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
+				return err
+			}
+			lbFloatingIP = fp.FloatingIP
+		} else {
+			s.scope.Logger.Info("Waiting for load balancer members before moving the floating IP", "ip", fp.FloatingIP)
 		}
-		lbFloatingIP = fp.FloatingIP
 	}
 
-	allowedCIDRs := []string{}
-	// To reduce API calls towards OpenStack API, let's handle the CIDR support verification for all Ports only once.
-	allowedCIDRsSupported := false
-	octaviaVersions, err := s.loadbalancerClient.ListOctaviaVersions()
-	if err != nil {
-		return err
-	}
-	// The current version is always the last one in the list.
-	octaviaVersion := octaviaVersions[len(octaviaVersions)-1].ID
-	if openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureVIPACL, lbProvider) {
-		allowedCIDRsSupported = true
+	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
+		Name:         lb.Name,
+		ID:           lb.ID,
+		InternalIP:   lb.VipAddress,
+		IP:           lbFloatingIP,
+		AllowedCIDRs: allowedCIDRs,
 	}
+	return nil
+}
 
-	portList := []int{apiServerPort}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
+// reconcileListeners reconciles the listeners, pools and monitors of the given ports of a load
+// balancer. It returns the ID of the pool of the API server port, if it is one of the ports, and
+// the allowed CIDRs of the listeners. If tagsSupported is set, the listeners and pools are tagged.
+// If timeoutsSupported is not set, the timeouts of the spec are not applied to the listeners.
+func (s *Service) reconcileListeners(openStackCluster *infrav1.OpenStackCluster, clusterName, loadBalancerName, lbID string, portList []int, apiServerPort int, tlsContainerRef string, lbMethod pools.LBMethod, allowedCIDRsSupported, tagsSupported, timeoutsSupported bool) (string, []string, error) {
+	var apiServerPoolID string
+	allowedCIDRs := []string{}
 	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
+		listenerName, err := getListenerName(openStackCluster, clusterName, loadBalancerName, port)
+		if err != nil {
+			return "", nil, err
+		}
+		poolName, err := getPoolName(openStackCluster, clusterName, loadBalancerName, port)
+		if err != nil {
+			return "", nil, err
+		}
 
-		listener, err := s.getOrCreateListener(openStackCluster, lbPortObjectsName, lb.ID, port)
+		// TLS is only terminated for the API server, additional ports are passed through
+		var listenerTLSContainerRef string
+		if port == apiServerPort {
+			listenerTLSContainerRef = tlsContainerRef
+		}
+		listenerTag := getObjectTag(clusterName, fmt.Sprintf("listener-%d", port), tagsSupported)
+		listener, err := s.getOrCreateListener(openStackCluster, clusterName, listenerName, lbID, port, listenerTLSContainerRef, listenerTag, timeoutsSupported)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 
-		pool, err := s.getOrCreatePool(openStackCluster, lbPortObjectsName, listener.ID, lb.ID)
+		// The API servers only accept TLS, so terminated traffic is re-encrypted
+		tlsEnabled := listener.Protocol == string(listeners.ProtocolTerminatedHTTPS)
+		poolTag := getObjectTag(clusterName, fmt.Sprintf("pool-%d", port), tagsSupported)
+		pool, err := s.getOrCreatePool(openStackCluster, clusterName, poolName, listener.ID, lbID, lbMethod, tlsEnabled, poolTag)
 		if err != nil {
-			return err
+			return "", nil, err
+		}
+		if port == apiServerPort {
+			apiServerPoolID = pool.ID
 		}
 
-		if err := s.getOrCreateMonitor(openStackCluster, lbPortObjectsName, pool.ID, lb.ID); err != nil {
-			return err
+		if err := s.getOrCreateMonitor(openStackCluster, poolName, pool.ID, lbID); err != nil {
+			return "", nil, err
 		}
 
 		if allowedCIDRsSupported {
 			// Skip reconciliation if network status is nil (e.g. during clusterctl move)
 			if openStackCluster.Status.Network != nil {
 				if err := s.getOrUpdateAllowedCIDRS(openStackCluster, listener); err != nil {
-					return err
+					return "", nil, err
 				}
 				allowedCIDRs = listener.AllowedCIDRs
 			}
 		}
 	}
+	return apiServerPoolID, allowedCIDRs, nil
+}
+
+// reconcileExistingLoadBalancer verifies that the load balancer referenced by the spec is usable
+// and records it in the status. Nothing is created or modified.
+func (s *Service) reconcileExistingLoadBalancer(openStackCluster *infrav1.OpenStackCluster, apiServerPort int) error {
+	ref := openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer
+	s.scope.Logger.Info("Reconciling existing load balancer", "id", ref.ID, "name", ref.Name)
+
+	lb, err := s.getExistingLoadBalancer(ref)
+	if err != nil {
+		return err
+	}
+	if lb == nil {
+		return fmt.Errorf("existing load balancer %s does not exist", referenceString(ref))
+	}
+
+	var allowedCIDRs []string
+	portList := []int{apiServerPort}
+	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
+	for _, port := range portList {
+		listener, err := s.getListenerByPort(lb.ID, port)
+		if err != nil {
+			return err
+		}
+		if listener == nil {
+			return fmt.Errorf("existing load balancer %s has no listener on port %d", lb.ID, port)
+		}
+		if listener.DefaultPoolID == "" {
+			return fmt.Errorf("listener %s of existing load balancer %s has no default pool", listener.ID, lb.ID)
+		}
+		if port == apiServerPort {
+			allowedCIDRs = listener.AllowedCIDRs
+		}
+	}
+
+	var lbFloatingIP string
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP && lb.VipPortID != "" {
+		fp, err := s.networkingService.GetFloatingIPByPortID(lb.VipPortID)
+		if err != nil {
+			return err
+		}
+		if fp != nil {
+			lbFloatingIP = fp.FloatingIP
+		}
+	}
 
 	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
 		Name:         lb.Name,
@@ -154,26 +336,208 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 	return nil
 }
 
-func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackCluster, loadBalancerName, subnetID, clusterName, vipAddress, provider string) (*loadbalancers.LoadBalancer, error) {
-	lb, err := s.checkIfLbExists(loadBalancerName)
+// getExistingLoadBalancer returns the load balancer referenced by ref, or nil if it does not exist.
+func (s *Service) getExistingLoadBalancer(ref *infrav1.LoadBalancerReference) (*loadbalancers.LoadBalancer, error) {
+	if ref.ID != "" {
+		lb, err := s.loadbalancerClient.GetLoadBalancer(s.scope.Context(), ref.ID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return lb, nil
+	}
+
+	lbList, err := s.loadbalancerClient.ListLoadBalancers(s.scope.Context(), loadbalancers.ListOpts{Name: ref.Name})
+	if err != nil {
+		return nil, err
+	}
+	switch len(lbList) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &lbList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d load balancers with name %q, expected one", len(lbList), ref.Name)
+	}
+}
+
+func referenceString(ref *infrav1.LoadBalancerReference) string {
+	if ref.ID != "" {
+		return ref.ID
+	}
+	return fmt.Sprintf("%q", ref.Name)
+}
+
+func (s *Service) getListenerByPort(lbID string, port int) (*listeners.Listener, error) {
+	listenerList, err := s.loadbalancerClient.ListListeners(s.scope.Context(), listeners.ListOpts{LoadbalancerID: lbID, ProtocolPort: port})
+	if err != nil {
+		return nil, err
+	}
+	if len(listenerList) == 0 {
+		return nil, nil
+	}
+	return &listenerList[0], nil
+}
+
+// getMemberPool returns the pool which members for the given port are added to. For an existing
+// load balancer this is the default pool of the listener on the port, otherwise the pool is
+// looked up by name.
+func (s *Service) getMemberPool(openStackCluster *infrav1.OpenStackCluster, lbID, poolName string, port int) (*pools.Pool, error) {
+	if openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer == nil {
+		return s.checkIfPoolExists(poolName)
+	}
+
+	listener, err := s.getListenerByPort(lbID, port)
+	if err != nil {
+		return nil, err
+	}
+	if listener == nil || listener.DefaultPoolID == "" {
+		return nil, nil
+	}
+	pool, err := s.loadbalancerClient.GetPool(s.scope.Context(), listener.DefaultPoolID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pool, nil
+}
+
+// getLoadBalancerProvider returns the Octavia provider to create the load balancer with.
+func (s *Service) getLoadBalancerProvider(openStackCluster *infrav1.OpenStackCluster) (string, error) {
+	providers, err := s.loadbalancerClient.ListLoadBalancerProviders(s.scope.Context())
+	if err != nil {
+		return "", err
+	}
+
+	provider := openStackCluster.Spec.APIServerLoadBalancer.Provider
+	if provider == "" {
+		// As mostly all LoadBalancer features are only supported on "amphora" we explicitly set the provider
+		// in the LoadBalancer create call to make sure to get the desired features - even if multiple providers exist.
+		for _, v := range providers {
+			if v.Name == defaultLoadBalancerProvider {
+				return v.Name, nil
+			}
+		}
+		return "", nil
+	}
+
+	for _, v := range providers {
+		if v.Name == provider {
+			return v.Name, nil
+		}
+	}
+	return "", fmt.Errorf("load balancer provider %q is not available", provider)
+}
+
+// getLoadBalancerFlavorID returns the ID of the Octavia flavor to create the load balancer
+// with, or an empty string if no flavor is requested or flavors are not supported.
+func (s *Service) getLoadBalancerFlavorID(openStackCluster *infrav1.OpenStackCluster, octaviaVersion, lbProvider string) (string, error) {
+	lbSpec := &openStackCluster.Spec.APIServerLoadBalancer
+	return s.resolveLoadBalancerFlavorID(openStackCluster, octaviaVersion, lbProvider, lbSpec.FlavorID, lbSpec.FlavorName)
+}
+
+// getAdditionalPortsFlavorID returns the ID of the Octavia flavor of the listeners of the
+// additional ports, or an empty string if no flavor is requested or flavors are not supported.
+func (s *Service) getAdditionalPortsFlavorID(openStackCluster *infrav1.OpenStackCluster, octaviaVersion, lbProvider string) (string, error) {
+	flavor := openStackCluster.Spec.APIServerLoadBalancer.AdditionalPortsFlavor
+	if flavor == nil || len(openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts) == 0 {
+		return "", nil
+	}
+	return s.resolveLoadBalancerFlavorID(openStackCluster, octaviaVersion, lbProvider, flavor.ID, flavor.Name)
+}
+
+func (s *Service) resolveLoadBalancerFlavorID(openStackCluster *infrav1.OpenStackCluster, octaviaVersion, lbProvider, flavorID, flavorName string) (string, error) {
+	if flavorID == "" && flavorName == "" {
+		return "", nil
+	}
+
+	if !openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureFlavors, lbProvider) {
+		record.Warnf(openStackCluster, "UnsupportedLoadBalancerFlavor", "Load balancer provider %q does not support flavors, ignoring the requested flavor", lbProvider)
+		return "", nil
+	}
+
+	if flavorID != "" {
+		return flavorID, nil
+	}
+
+	flavors, err := s.loadbalancerClient.ListLoadBalancerFlavors(s.scope.Context())
+	if err != nil {
+		return "", fmt.Errorf("error listing load balancer flavors: %v", err)
+	}
+	var flavorIDs []string
+	for _, flavor := range flavors {
+		if flavor.Name == flavorName {
+			flavorIDs = append(flavorIDs, flavor.ID)
+		}
+	}
+	if len(flavorIDs) != 1 {
+		return "", fmt.Errorf("expected to find a single load balancer flavor called %s; found %d", flavorName, len(flavorIDs))
+	}
+	return flavorIDs[0], nil
+}
+
+// validateLoadBalancerAvailabilityZone validates that the availability zone to create the load balancer
+// in, if any, is an enabled Octavia availability zone.
+func (s *Service) validateLoadBalancerAvailabilityZone(openStackCluster *infrav1.OpenStackCluster, octaviaVersion, lbProvider string) error {
+	availabilityZone := openStackCluster.Spec.APIServerLoadBalancer.AvailabilityZone
+	if availabilityZone == "" {
+		return nil
+	}
+
+	if !openstackutil.IsOctaviaFeatureSupported(octaviaVersion, openstackutil.OctaviaFeatureAvailabilityZones, lbProvider) {
+		return fmt.Errorf("load balancer provider %q does not support availability zones", lbProvider)
+	}
+
+	availabilityZones, err := s.loadbalancerClient.ListLoadBalancerAvailabilityZones(s.scope.Context())
+	if err != nil {
+		return fmt.Errorf("error listing load balancer availability zones: %v", err)
+	}
+	for _, az := range availabilityZones {
+		if az.Name != availabilityZone {
+			continue
+		}
+		if !az.Enabled {
+			return fmt.Errorf("load balancer availability zone %s is disabled", availabilityZone)
+		}
+		return nil
+	}
+	return fmt.Errorf("load balancer availability zone %s does not exist", availabilityZone)
+}
+
+// getOrCreateLoadBalancer returns the load balancer with the given identifying tag or name, creating
+// it if it does not exist. If objectTag is empty, the load balancer is not tagged.
+func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackCluster, loadBalancerName, subnetID, clusterName, vipAddress, provider, flavorID, objectTag string) (*loadbalancers.LoadBalancer, error) {
+	tags := getObjectTags(openStackCluster, clusterName, objectTag)
+
+	lb, err := s.findLoadBalancer(loadBalancerName, objectTag)
 	if err != nil {
 		return nil, err
 	}
 
 	if lb != nil {
+		if len(tags) > 0 {
+			return s.tagLoadBalancer(openStackCluster, lb, tags)
+		}
 		return lb, nil
 	}
 
 	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer in subnet: %q", subnetID), "name", loadBalancerName)
 
 	lbCreateOpts := loadbalancers.CreateOpts{
-		Name:        loadBalancerName,
-		VipSubnetID: subnetID,
-		VipAddress:  vipAddress,
-		Description: names.GetDescription(clusterName),
-		Provider:    provider,
+		Name:             loadBalancerName,
+		VipSubnetID:      subnetID,
+		VipAddress:       vipAddress,
+		Description:      names.GetDescription(clusterName),
+		Provider:         provider,
+		FlavorID:         flavorID,
+		AvailabilityZone: openStackCluster.Spec.APIServerLoadBalancer.AvailabilityZone,
+		Tags:             tags,
 	}
-	lb, err = s.loadbalancerClient.CreateLoadBalancer(lbCreateOpts)
+	lb, err = s.loadbalancerClient.CreateLoadBalancer(s.scope.Context(), lbCreateOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateLoadBalancer", "Failed to create load balancer %s: %v", loadBalancerName, err)
 		return nil, err
@@ -183,25 +547,57 @@ func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackClu
 	return lb, nil
 }
 
-func (s *Service) getOrCreateListener(openStackCluster *infrav1.OpenStackCluster, listenerName, lbID string, port int) (*listeners.Listener, error) {
-	listener, err := s.checkIfListenerExists(listenerName)
+// getOrCreateListener returns the listener with the given identifying tag or name, creating it if it
+// does not exist. If objectTag is empty, the listener is not tagged. If tlsContainerRef is set, the listener terminates TLS with the certificate it references. The
+// certificate of an existing TLS terminating listener is updated when the reference changes, e.g.
+// when the certificate referenced by name is rotated. If timeoutsSupported is not set, the timeouts
+// of the spec are not applied to the listener.
+func (s *Service) getOrCreateListener(openStackCluster *infrav1.OpenStackCluster, clusterName, listenerName, lbID string, port int, tlsContainerRef, objectTag string, timeoutsSupported bool) (*listeners.Listener, error) {
+	tags := getObjectTags(openStackCluster, clusterName, objectTag)
+
+	listener, err := s.findListener(listenerName, objectTag, lbID)
 	if err != nil {
 		return nil, err
 	}
 
 	if listener != nil {
+		if len(tags) > 0 {
+			listener, err = s.tagListener(openStackCluster, listener, lbID, tags)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if settings, changed := listenerSettings(openStackCluster, listener, timeoutsSupported); changed {
+			listener, err = s.updateListenerSettings(openStackCluster, listener, lbID, settings)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if tlsContainerRef != "" && listener.Protocol == string(listeners.ProtocolTerminatedHTTPS) && listener.DefaultTlsContainerRef != tlsContainerRef {
+			return s.updateListenerCertificate(openStackCluster, listener, lbID, tlsContainerRef)
+		}
 		return listener, nil
 	}
 
 	s.scope.Logger.Info("Creating load balancer listener", "name", listenerName, "lb-id", lbID)
 
+	settings, _ := listenerSettings(openStackCluster, nil, timeoutsSupported)
 	listenerCreateOpts := listeners.CreateOpts{
-		Name:           listenerName,
-		Protocol:       "TCP",
-		ProtocolPort:   port,
-		LoadbalancerID: lbID,
+		Name:                 listenerName,
+		Protocol:             "TCP",
+		ProtocolPort:         port,
+		LoadbalancerID:       lbID,
+		Tags:                 tags,
+		ConnLimit:            settings.ConnLimit,
+		TimeoutClientData:    settings.TimeoutClientData,
+		TimeoutMemberData:    settings.TimeoutMemberData,
+		TimeoutMemberConnect: settings.TimeoutMemberConnect,
+	}
+	if tlsContainerRef != "" {
+		listenerCreateOpts.Protocol = listeners.ProtocolTerminatedHTTPS
+		listenerCreateOpts.DefaultTlsContainerRef = tlsContainerRef
 	}
-	listener, err = s.loadbalancerClient.CreateListener(listenerCreateOpts)
+	listener, err = s.loadbalancerClient.CreateListener(s.scope.Context(), listenerCreateOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateListener", "Failed to create listener %s: %v", listenerName, err)
 		return nil, err
@@ -221,50 +617,161 @@ func (s *Service) getOrCreateListener(openStackCluster *infrav1.OpenStackCluster
 	return listener, nil
 }
 
-func (s *Service) getOrUpdateAllowedCIDRS(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener) error {
+// listenerSettings returns the options to update the connection limit and timeouts of the listener
+// to the spec with, and whether any differs. Settings which are not set in the spec are left to
+// Octavia, as are the timeouts if timeoutsSupported is not set. If listener is nil, all settings
+// of the spec are returned.
+func listenerSettings(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener, timeoutsSupported bool) (listeners.UpdateOpts, bool) {
+	spec := openStackCluster.Spec.APIServerLoadBalancer
+	if !timeoutsSupported {
+		spec.TimeoutClientData = 0
+		spec.TimeoutMemberData = 0
+		spec.TimeoutMemberConnect = 0
+	}
+	current := listeners.Listener{}
+	if listener != nil {
+		current = *listener
+	}
+
+	changed := false
+	setting := func(desired, actual int) *int {
+		if desired == 0 || desired == actual {
+			return nil
+		}
+		changed = true
+		return pointer.Int(desired)
+	}
+	opts := listeners.UpdateOpts{
+		ConnLimit:            setting(spec.ConnectionLimit, current.ConnLimit),
+		TimeoutClientData:    setting(spec.TimeoutClientData, current.TimeoutClientData),
+		TimeoutMemberData:    setting(spec.TimeoutMemberData, current.TimeoutMemberData),
+		TimeoutMemberConnect: setting(spec.TimeoutMemberConnect, current.TimeoutMemberConnect),
+	}
+	return opts, changed
+}
+
+func (s *Service) updateListenerSettings(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener, lbID string, settings listeners.UpdateOpts) (*listeners.Listener, error) {
+	s.scope.Logger.Info("Updating connection limit and timeouts of load balancer listener", "name", listener.Name, "id", listener.ID)
+
+	updated, err := s.loadbalancerClient.UpdateListener(s.scope.Context(), listener.ID, settings)
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update connection limit and timeouts of listener %s: %v", listener.Name, err)
+		return nil, err
+	}
+
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		return nil, fmt.Errorf("load balancer %s is not active after updating listener %s: %v", lbID, listener.ID, err)
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulUpdateListener", "Updated connection limit and timeouts of listener %s with id %s", listener.Name, listener.ID)
+	return updated, nil
+}
+
+func (s *Service) updateListenerCertificate(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener, lbID, tlsContainerRef string) (*listeners.Listener, error) {
+	s.scope.Logger.Info("Updating load balancer listener certificate", "name", listener.Name, "lb-id", lbID)
+
+	updated, err := s.loadbalancerClient.UpdateListener(s.scope.Context(), listener.ID, listeners.UpdateOpts{
+		DefaultTlsContainerRef: &tlsContainerRef,
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update certificate of listener %s: %v", listener.Name, err)
+		return nil, err
+	}
+
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		return nil, fmt.Errorf("load balancer %s is not active after updating listener %s: %v", lbID, listener.ID, err)
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulUpdateListener", "Updated certificate of listener %s with id %s", listener.Name, listener.ID)
+	return updated, nil
+}
+
+// getCanonicalAllowedCIDRs returns the sorted CIDRs which are allowed to access the listeners of
+// the API server load balancer. When access is restricted, the bastion, the cluster subnet, the
+// router IPs and the egress IP of the controller, if known, are always allowed so that the control
+// plane keeps working and stays reachable by the controller.
+func getCanonicalAllowedCIDRs(openStackCluster *infrav1.OpenStackCluster, egressIP string) []string {
 	allowedCIDRs := []string{}
 
 	if len(openStackCluster.Spec.APIServerLoadBalancer.AllowedCIDRs) > 0 {
-		allowedCIDRs = append(allowedCIDRs, openStackCluster.Spec.APIServerLoadBalancer.AllowedCIDRs...)
+		// Validate CIDRs and convert any given IP into a CIDR.
+		allowedCIDRs = validateIPs(openStackCluster, openStackCluster.Spec.APIServerLoadBalancer.AllowedCIDRs)
+
+		clusterCIDRs := []string{}
+		if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled && openStackCluster.Status.Bastion != nil {
+			for _, ip := range []string{openStackCluster.Status.Bastion.FloatingIP, openStackCluster.Status.Bastion.IP} {
+				if ip != "" {
+					clusterCIDRs = append(clusterCIDRs, ip)
+				}
+			}
+		}
+
+		if network := openStackCluster.Status.Network; network != nil {
+			if network.Subnet != nil && network.Subnet.CIDR != "" {
+				clusterCIDRs = append(clusterCIDRs, network.Subnet.CIDR)
+			}
+			if network.IPv6Subnet != nil && network.IPv6Subnet.CIDR != "" {
+				clusterCIDRs = append(clusterCIDRs, network.IPv6Subnet.CIDR)
+			}
 
-		if openStackCluster.Spec.Bastion.Enabled {
-			allowedCIDRs = append(allowedCIDRs, openStackCluster.Status.Bastion.FloatingIP, openStackCluster.Status.Bastion.IP)
+			if network.Router != nil {
+				clusterCIDRs = append(clusterCIDRs, network.Router.IPs...)
+			}
 		}
 
-		if openStackCluster.Status.Network.Subnet.CIDR != "" {
-			allowedCIDRs = append(allowedCIDRs, openStackCluster.Status.Network.Subnet.CIDR)
+		if egressIP != "" {
+			clusterCIDRs = append(clusterCIDRs, egressIP)
 		}
 
-		if len(openStackCluster.Status.Network.Router.IPs) > 0 {
-			allowedCIDRs = append(allowedCIDRs, openStackCluster.Status.Network.Router.IPs...)
+		// The addresses of the cluster are only added if they match the IP version of the VIP.
+		for _, cidr := range clusterCIDRs {
+			if c, ok := toCIDR(openStackCluster, cidr); ok {
+				allowedCIDRs = append(allowedCIDRs, c)
+			}
 		}
 	}
 
-	// Validate CIDRs and convert any given IP into a CIDR.
-	allowedCIDRs = validateIPs(openStackCluster, allowedCIDRs)
-
 	// Remove duplicates.
 	allowedCIDRs = capostrings.Unique(allowedCIDRs)
-	listener.AllowedCIDRs = capostrings.Unique(listener.AllowedCIDRs)
+	sort.Strings(allowedCIDRs)
+	return allowedCIDRs
+}
 
-	if !reflect.DeepEqual(allowedCIDRs, listener.AllowedCIDRs) {
-		listenerUpdateOpts := listeners.UpdateOpts{
-			AllowedCIDRs: &allowedCIDRs,
-		}
+// getOrUpdateAllowedCIDRS reconciles the allowed CIDRs of the listener. CIDRs which were added to
+// or removed from the listener outside of the cluster spec are reverted.
+func (s *Service) getOrUpdateAllowedCIDRS(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener) error {
+	egressIP, err := egress.IP()
+	if err != nil {
+		// The last known address, if any, is still allowed
+		s.scope.Logger.Error(err, "Failed to discover the egress IP of the controller")
+	}
+	allowedCIDRs := getCanonicalAllowedCIDRs(openStackCluster, egressIP)
 
-		listener, err := s.loadbalancerClient.UpdateListener(listener.ID, listenerUpdateOpts)
-		if err != nil {
-			record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s: %v", listener.Name, err)
-			return err
-		}
+	listenerCIDRs := capostrings.Unique(listener.AllowedCIDRs)
+	sort.Strings(listenerCIDRs)
 
-		if err := s.waitForListener(listener.ID, "ACTIVE"); err != nil {
-			record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s with id %s: wait for listener active: %v", listener.Name, listener.ID, err)
-			return err
-		}
+	if reflect.DeepEqual(allowedCIDRs, listenerCIDRs) {
+		return nil
+	}
+
+	s.scope.Logger.Info("Updating allowed CIDRs of load balancer listener", "name", listener.Name, "id", listener.ID, "current", listenerCIDRs, "desired", allowedCIDRs)
+
+	listenerUpdateOpts := listeners.UpdateOpts{
+		AllowedCIDRs: &allowedCIDRs,
+	}
 
-		record.Eventf(openStackCluster, "SuccessfulUpdateListener", "Updated allowed_cidrs %s for listener %s with id %s", listener.AllowedCIDRs, listener.Name, listener.ID)
+	if _, err := s.loadbalancerClient.UpdateListener(s.scope.Context(), listener.ID, listenerUpdateOpts); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s: %v", listener.Name, err)
+		return err
+	}
+
+	if err := s.waitForListener(listener.ID, "ACTIVE"); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s with id %s: wait for listener active: %v", listener.Name, listener.ID, err)
+		return err
 	}
+
+	listener.AllowedCIDRs = allowedCIDRs
+	record.Eventf(openStackCluster, "SuccessfulUpdateListener", "Updated allowed_cidrs %s for listener %s with id %s", listener.AllowedCIDRs, listener.Name, listener.ID)
 	return nil
 }
 
@@ -273,38 +780,87 @@ func validateIPs(openStackCluster *infrav1.OpenStackCluster, definedCIDRs []stri
 	marshaledCIDRs := []string{}
 
 	for _, v := range definedCIDRs {
-		switch {
-		case net.IsIPv4String(v):
-			marshaledCIDRs = append(marshaledCIDRs, v+"/32")
-		case net.IsIPv4CIDRString(v):
-			marshaledCIDRs = append(marshaledCIDRs, v)
-		default:
-			record.Warnf(openStackCluster, "FailedIPAddressValidation", "%s is not a valid IPv4 nor CIDR address and will not get applied to allowed_cidrs", v)
+		cidr, ok := toCIDR(openStackCluster, v)
+		if !ok {
+			record.Warnf(openStackCluster, "FailedIPAddressValidation", "%s is not a valid IPv%d nor CIDR address and will not get applied to allowed_cidrs", v, getVIPIPVersion(openStackCluster))
+			continue
 		}
+		marshaledCIDRs = append(marshaledCIDRs, cidr)
 	}
 
 	return marshaledCIDRs
 }
 
-func (s *Service) getOrCreatePool(openStackCluster *infrav1.OpenStackCluster, poolName, listenerID, lbID string) (*pools.Pool, error) {
-	pool, err := s.checkIfPoolExists(poolName)
+// toCIDR converts an IP into a CIDR. It returns false if v is neither an IP nor a CIDR of the IP
+// version of the VIP.
+func toCIDR(openStackCluster *infrav1.OpenStackCluster, v string) (string, bool) {
+	if getVIPIPVersion(openStackCluster) == 6 {
+		switch {
+		case net.IsIPv6String(v):
+			return v + "/128", true
+		case net.IsIPv6CIDRString(v):
+			return v, true
+		}
+		return "", false
+	}
+
+	switch {
+	case net.IsIPv4String(v):
+		return v + "/32", true
+	case net.IsIPv4CIDRString(v):
+		return v, true
+	}
+	return "", false
+}
+
+// getVIPIPVersion returns the IP version of the VIP of the API server load balancer.
+func getVIPIPVersion(openStackCluster *infrav1.OpenStackCluster) int {
+	if openStackCluster.Spec.APIServerLoadBalancer.IPVersion == 6 {
+		return 6
+	}
+	return 4
+}
+
+// getOrCreatePool returns the pool with the given identifying tag or name, creating it if it does
+// not exist. If objectTag is empty, the pool is not tagged. If tlsEnabled is set, the pool
+// re-encrypts the HTTP traffic of a TLS terminating listener.
+func (s *Service) getOrCreatePool(openStackCluster *infrav1.OpenStackCluster, clusterName, poolName, listenerID, lbID string, lbMethod pools.LBMethod, tlsEnabled bool, objectTag string) (*pools.Pool, error) {
+	tags := getObjectTags(openStackCluster, clusterName, objectTag)
+
+	pool, err := s.findPool(poolName, objectTag, lbID)
 	if err != nil {
 		return nil, err
 	}
 
 	if pool != nil {
+		if len(tags) > 0 {
+			return s.tagPool(openStackCluster, pool, lbID, tags)
+		}
 		return pool, nil
 	}
 
 	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer pool for listener %q", listenerID), "name", poolName, "lb-id", lbID)
 
-	poolCreateOpts := pools.CreateOpts{
+	var createOpts pools.CreateOptsBuilder = pools.CreateOpts{
 		Name:       poolName,
 		Protocol:   "TCP",
-		LBMethod:   pools.LBMethodRoundRobin,
+		LBMethod:   lbMethod,
 		ListenerID: listenerID,
+		Tags:       tags,
+	}
+	if tlsEnabled {
+		createOpts = poolCreateOpts{
+			CreateOpts: pools.CreateOpts{
+				Name:       poolName,
+				Protocol:   pools.ProtocolHTTP,
+				LBMethod:   lbMethod,
+				ListenerID: listenerID,
+				Tags:       tags,
+			},
+			TLSEnabled: true,
+		}
 	}
-	pool, err = s.loadbalancerClient.CreatePool(poolCreateOpts)
+	pool, err = s.loadbalancerClient.CreatePool(s.scope.Context(), createOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreatePool", "Failed to create pool %s: %v", poolName, err)
 		return nil, err
@@ -319,27 +875,79 @@ func (s *Service) getOrCreatePool(openStackCluster *infrav1.OpenStackCluster, po
 	return pool, nil
 }
 
+// getMonitorSpec returns the desired health monitor settings, applying the defaults.
+func getMonitorSpec(openStackCluster *infrav1.OpenStackCluster) infrav1.HealthMonitor {
+	monitor := infrav1.HealthMonitor{}
+	if openStackCluster.Spec.APIServerLoadBalancer.HealthMonitor != nil {
+		monitor = *openStackCluster.Spec.APIServerLoadBalancer.HealthMonitor
+	}
+	if monitor.Type == "" {
+		monitor.Type = infrav1.HealthMonitorTypeTCP
+	}
+	if monitor.Delay == 0 {
+		monitor.Delay = defaultMonitorDelay
+	}
+	if monitor.Timeout == 0 {
+		monitor.Timeout = defaultMonitorTimeout
+	}
+	if monitor.MaxRetries == 0 {
+		monitor.MaxRetries = defaultMonitorMaxRetries
+	}
+	return monitor
+}
+
+// monitorNeedsUpdate returns whether the health monitor differs from the spec in
+// any of the settings which can be updated in place. Unset optional settings are
+// left to Octavia.
+func monitorNeedsUpdate(monitor *monitors.Monitor, spec infrav1.HealthMonitor) bool {
+	return monitor.Delay != spec.Delay ||
+		monitor.Timeout != spec.Timeout ||
+		monitor.MaxRetries != spec.MaxRetries ||
+		(spec.MaxRetriesDown != 0 && monitor.MaxRetriesDown != spec.MaxRetriesDown) ||
+		(spec.URLPath != "" && monitor.URLPath != spec.URLPath)
+}
+
 func (s *Service) getOrCreateMonitor(openStackCluster *infrav1.OpenStackCluster, monitorName, poolID, lbID string) error {
 	monitor, err := s.checkIfMonitorExists(monitorName)
 	if err != nil {
 		return err
 	}
 
+	spec := getMonitorSpec(openStackCluster)
+
 	if monitor != nil {
-		return nil
+		if monitor.Type != string(spec.Type) {
+			// The type of a health monitor cannot be changed, so it has to be recreated.
+			s.scope.Logger.Info("Recreating load balancer monitor with new type", "name", monitorName, "type", spec.Type)
+			if err := s.loadbalancerClient.DeleteMonitor(s.scope.Context(), monitor.ID); err != nil {
+				record.Warnf(openStackCluster, "FailedDeleteMonitor", "Failed to delete monitor %s with id %s: %v", monitorName, monitor.ID, err)
+				return err
+			}
+			if err := s.waitForLoadBalancerActive(lbID); err != nil {
+				return err
+			}
+			record.Eventf(openStackCluster, "SuccessfulDeleteMonitor", "Deleted monitor %s with id %s", monitorName, monitor.ID)
+		} else {
+			if monitorNeedsUpdate(monitor, spec) {
+				return s.updateMonitor(openStackCluster, monitor, spec, lbID)
+			}
+			return nil
+		}
 	}
 
 	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer monitor for pool %q", poolID), "name", monitorName, "lb-id", lbID)
 
 	monitorCreateOpts := monitors.CreateOpts{
-		Name:       monitorName,
-		PoolID:     poolID,
-		Type:       "TCP",
-		Delay:      30,
-		Timeout:    5,
-		MaxRetries: 3,
+		Name:           monitorName,
+		PoolID:         poolID,
+		Type:           string(spec.Type),
+		Delay:          spec.Delay,
+		Timeout:        spec.Timeout,
+		MaxRetries:     spec.MaxRetries,
+		MaxRetriesDown: spec.MaxRetriesDown,
+		URLPath:        spec.URLPath,
 	}
-	monitor, err = s.loadbalancerClient.CreateMonitor(monitorCreateOpts)
+	monitor, err = s.loadbalancerClient.CreateMonitor(s.scope.Context(), monitorCreateOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateMonitor", "Failed to create monitor %s: %v", monitorName, err)
 		return err
@@ -354,6 +962,30 @@ func (s *Service) getOrCreateMonitor(openStackCluster *infrav1.OpenStackCluster,
 	return nil
 }
 
+func (s *Service) updateMonitor(openStackCluster *infrav1.OpenStackCluster, monitor *monitors.Monitor, spec infrav1.HealthMonitor, lbID string) error {
+	s.scope.Logger.Info("Updating load balancer monitor", "name", monitor.Name, "id", monitor.ID)
+
+	monitorUpdateOpts := monitors.UpdateOpts{
+		Delay:          spec.Delay,
+		Timeout:        spec.Timeout,
+		MaxRetries:     spec.MaxRetries,
+		MaxRetriesDown: spec.MaxRetriesDown,
+		URLPath:        spec.URLPath,
+	}
+	if _, err := s.loadbalancerClient.UpdateMonitor(s.scope.Context(), monitor.ID, monitorUpdateOpts); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateMonitor", "Failed to update monitor %s with id %s: %v", monitor.Name, monitor.ID, err)
+		return err
+	}
+
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateMonitor", "Failed to update monitor %s with id %s: wait for load balancer active %s: %v", monitor.Name, monitor.ID, lbID, err)
+		return err
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulUpdateMonitor", "Updated monitor %s with id %s", monitor.Name, monitor.ID)
+	return nil
+}
+
 func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, clusterName, ip string) error {
 	if openStackCluster.Status.Network == nil {
 		return errors.New("network is not yet available in openStackCluster.Status")
@@ -365,17 +997,24 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 		return errors.New("network.APIServerLoadBalancer is not yet available in openStackCluster.Status")
 	}
 
-	loadBalancerName := getLoadBalancerName(clusterName)
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	s.scope.Logger.Info("Reconciling load balancer member", "name", loadBalancerName)
 
-	lbID := openStackCluster.Status.Network.APIServerLoadBalancer.ID
-	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
-	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
-		name := lbPortObjectsName + "-" + openStackMachine.Name
+	for _, port := range getMemberPorts(openStackCluster) {
+		lbName, lbID := loadBalancerName, openStackCluster.Status.Network.APIServerLoadBalancer.ID
+		if additionalLB := openStackCluster.Status.Network.AdditionalPortsLoadBalancer; additionalLB != nil && isAdditionalPort(openStackCluster, port) {
+			lbName, lbID = additionalLB.Name, additionalLB.ID
+		}
+		poolName, err := getPoolName(openStackCluster, clusterName, lbName, port)
+		if err != nil {
+			return err
+		}
+		name := poolName + "-" + openStackMachine.Name
 
-		pool, err := s.checkIfPoolExists(lbPortObjectsName)
+		pool, err := s.getMemberPool(openStackCluster, lbID, poolName, port)
 		if err != nil {
 			return err
 		}
@@ -402,7 +1041,7 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 			if err != nil {
 				return err
 			}
-			if err := s.loadbalancerClient.DeletePoolMember(pool.ID, lbMember.ID); err != nil {
+			if err := s.loadbalancerClient.DeletePoolMember(s.scope.Context(), pool.ID, lbMember.ID); err != nil {
 				return err
 			}
 			err = s.waitForLoadBalancerActive(lbID)
@@ -424,7 +1063,7 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 			return err
 		}
 
-		if _, err := s.loadbalancerClient.CreatePoolMember(pool.ID, lbMemberOpts); err != nil {
+		if _, err := s.loadbalancerClient.CreatePoolMember(s.scope.Context(), pool.ID, lbMemberOpts); err != nil {
 			return err
 		}
 
@@ -436,7 +1075,33 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 }
 
 func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
-	loadBalancerName := getLoadBalancerName(clusterName)
+	return s.deleteLoadBalancer(openStackCluster, clusterName, false)
+}
+
+// RemoveLoadBalancer deletes the API server load balancer of a cluster which no longer uses one. In
+// contrast to DeleteLoadBalancer the floating IP is only disassociated, as it still holds the
+// control plane endpoint.
+func (s *Service) RemoveLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	return s.deleteLoadBalancer(openStackCluster, clusterName, true)
+}
+
+func (s *Service) deleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string, keepFloatingIP bool) error {
+	if ref := openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer; ref != nil {
+		s.scope.Logger.Info("Not deleting existing load balancer", "id", ref.ID, "name", ref.Name)
+		return nil
+	}
+	if openStackCluster.Spec.APIServerLoadBalancer.Shared != nil {
+		return s.deleteSharedLoadBalancer(openStackCluster, clusterName)
+	}
+
+	if err := s.deleteAdditionalPortsLoadBalancer(openStackCluster, clusterName); err != nil {
+		return err
+	}
+
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	lb, err := s.checkIfLbExists(loadBalancerName)
 	if err != nil {
 		return err
@@ -445,7 +1110,12 @@ func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster,
 	if lb == nil {
 		return nil
 	}
+	return s.deleteLoadBalancerAndFloatingIP(openStackCluster, lb, keepFloatingIP)
+}
 
+// deleteLoadBalancerAndFloatingIP deletes a load balancer and, unless keepFloatingIP is set or it
+// was claimed from a floating IP pool, the floating IP of its VIP.
+func (s *Service) deleteLoadBalancerAndFloatingIP(openStackCluster *infrav1.OpenStackCluster, lb *loadbalancers.LoadBalancer, keepFloatingIP bool) error {
 	if lb.VipPortID != "" {
 		fip, err := s.networkingService.GetFloatingIPByPortID(lb.VipPortID)
 		if err != nil {
@@ -456,8 +1126,11 @@ func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster,
 			if err = s.networkingService.DisassociateFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
 				return err
 			}
-			if err = s.networkingService.DeleteFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
-				return err
+			// Addresses claimed from a floating IP pool are returned to the pool
+			if !keepFloatingIP && !networking.IsClaimedFloatingIP(openStackCluster, fip.FloatingIP) {
+				if err = s.networkingService.DeleteFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -465,8 +1138,8 @@ func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster,
 	deleteOpts := loadbalancers.DeleteOpts{
 		Cascade: true,
 	}
-	s.scope.Logger.Info("Deleting load balancer", "name", loadBalancerName, "cascade", deleteOpts.Cascade)
-	err = s.loadbalancerClient.DeleteLoadBalancer(lb.ID, deleteOpts)
+	s.scope.Logger.Info("Deleting load balancer", "name", lb.Name, "cascade", deleteOpts.Cascade)
+	err := s.loadbalancerClient.DeleteLoadBalancer(s.scope.Context(), lb.ID, deleteOpts)
 	if err != nil && !capoerrors.IsNotFound(err) {
 		record.Warnf(openStackCluster, "FailedDeleteLoadBalancer", "Failed to delete load balancer %s with id %s: %v", lb.Name, lb.ID, err)
 		return err
@@ -481,8 +1154,19 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 		return nil
 	}
 
-	loadBalancerName := getLoadBalancerName(clusterName)
-	lb, err := s.checkIfLbExists(loadBalancerName)
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	var lb *loadbalancers.LoadBalancer
+	switch {
+	case openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer != nil:
+		lb, err = s.getExistingLoadBalancer(openStackCluster.Spec.APIServerLoadBalancer.ExistingLoadBalancer)
+	case openStackCluster.Spec.APIServerLoadBalancer.Shared != nil:
+		lb, err = s.getExistingLoadBalancer(&openStackCluster.Spec.APIServerLoadBalancer.Shared.LoadBalancer)
+	default:
+		lb, err = s.checkIfLbExists(loadBalancerName)
+	}
 	if err != nil {
 		return err
 	}
@@ -491,20 +1175,28 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 		return nil
 	}
 
-	lbID := lb.ID
+	additionalLB, err := s.getAdditionalPortsLoadBalancer(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 
-	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
-	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
-		name := lbPortObjectsName + "-" + openStackMachine.Name
+	for _, port := range getMemberPorts(openStackCluster) {
+		lbName, lbID := loadBalancerName, lb.ID
+		if additionalLB != nil && isAdditionalPort(openStackCluster, port) {
+			lbName, lbID = additionalLB.Name, additionalLB.ID
+		}
+		poolName, err := getPoolName(openStackCluster, clusterName, lbName, port)
+		if err != nil {
+			return err
+		}
+		name := poolName + "-" + openStackMachine.Name
 
-		pool, err := s.checkIfPoolExists(lbPortObjectsName)
+		pool, err := s.getMemberPool(openStackCluster, lbID, poolName, port)
 		if err != nil {
 			return err
 		}
 		if pool == nil {
-			s.scope.Logger.Info("Load balancer pool does not exist", "name", lbPortObjectsName)
+			s.scope.Logger.Info("Load balancer pool does not exist", "name", poolName)
 			continue
 		}
 
@@ -519,7 +1211,7 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 			if err != nil {
 				return err
 			}
-			if err := s.loadbalancerClient.DeletePoolMember(pool.ID, lbMember.ID); err != nil {
+			if err := s.loadbalancerClient.DeletePoolMember(s.scope.Context(), pool.ID, lbMember.ID); err != nil {
 				return err
 			}
 			err = s.waitForLoadBalancerActive(lbID)
@@ -531,12 +1223,41 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 	return nil
 }
 
-func getLoadBalancerName(clusterName string) string {
-	return fmt.Sprintf("%s-cluster-%s-%s", networkPrefix, clusterName, kubeapiLBSuffix)
+// getMemberPorts returns the ports the members of the load balancer pools listen on.
+func getMemberPorts(openStackCluster *infrav1.OpenStackCluster) []int {
+	if openStackCluster.Spec.APIServerLoadBalancer.Shared != nil {
+		return []int{getSharedBackendPort(openStackCluster)}
+	}
+	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
+	return append(portList, openStackCluster.Spec.APIServerLoadBalancer.AdditionalPorts...)
+}
+
+func getResourceNaming(openStackCluster *infrav1.OpenStackCluster) infrav1.ResourceNaming {
+	if openStackCluster.Spec.ResourceNaming == nil {
+		return infrav1.ResourceNaming{}
+	}
+	return *openStackCluster.Spec.ResourceNaming
+}
+
+func getLoadBalancerName(openStackCluster *infrav1.OpenStackCluster, clusterName string) (string, error) {
+	defaultName := fmt.Sprintf("%s-cluster-%s-%s", networkPrefix, clusterName, kubeapiLBSuffix)
+	return names.Render(getResourceNaming(openStackCluster).LoadBalancer, defaultName, names.NewTemplateData(openStackCluster.Namespace, clusterName))
+}
+
+func getListenerName(openStackCluster *infrav1.OpenStackCluster, clusterName, loadBalancerName string, port int) (string, error) {
+	data := names.NewTemplateData(openStackCluster.Namespace, clusterName)
+	data.Port = port
+	return names.Render(getResourceNaming(openStackCluster).Listener, fmt.Sprintf("%s-%d", loadBalancerName, port), data)
+}
+
+func getPoolName(openStackCluster *infrav1.OpenStackCluster, clusterName, loadBalancerName string, port int) (string, error) {
+	data := names.NewTemplateData(openStackCluster.Namespace, clusterName)
+	data.Port = port
+	return names.Render(getResourceNaming(openStackCluster).Pool, fmt.Sprintf("%s-%d", loadBalancerName, port), data)
 }
 
 func (s *Service) checkIfLbExists(name string) (*loadbalancers.LoadBalancer, error) {
-	lbList, err := s.loadbalancerClient.ListLoadBalancers(loadbalancers.ListOpts{Name: name})
+	lbList, err := s.loadbalancerClient.ListLoadBalancers(s.scope.Context(), loadbalancers.ListOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
@@ -547,7 +1268,7 @@ func (s *Service) checkIfLbExists(name string) (*loadbalancers.LoadBalancer, err
 }
 
 func (s *Service) checkIfListenerExists(name string) (*listeners.Listener, error) {
-	listenerList, err := s.loadbalancerClient.ListListeners(listeners.ListOpts{Name: name})
+	listenerList, err := s.loadbalancerClient.ListListeners(s.scope.Context(), listeners.ListOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
@@ -558,7 +1279,7 @@ func (s *Service) checkIfListenerExists(name string) (*listeners.Listener, error
 }
 
 func (s *Service) checkIfPoolExists(name string) (*pools.Pool, error) {
-	poolList, err := s.loadbalancerClient.ListPools(pools.ListOpts{Name: name})
+	poolList, err := s.loadbalancerClient.ListPools(s.scope.Context(), pools.ListOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
@@ -569,7 +1290,7 @@ func (s *Service) checkIfPoolExists(name string) (*pools.Pool, error) {
 }
 
 func (s *Service) checkIfMonitorExists(name string) (*monitors.Monitor, error) {
-	monitorList, err := s.loadbalancerClient.ListMonitors(monitors.ListOpts{Name: name})
+	monitorList, err := s.loadbalancerClient.ListMonitors(s.scope.Context(), monitors.ListOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
@@ -580,7 +1301,7 @@ func (s *Service) checkIfMonitorExists(name string) (*monitors.Monitor, error) {
 }
 
 func (s *Service) checkIfLbMemberExists(poolID, name string) (*pools.Member, error) {
-	lbMemberList, err := s.loadbalancerClient.ListPoolMember(poolID, pools.ListMembersOpts{Name: name})
+	lbMemberList, err := s.loadbalancerClient.ListPoolMember(s.scope.Context(), poolID, pools.ListMembersOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
@@ -590,18 +1311,32 @@ func (s *Service) checkIfLbMemberExists(poolID, name string) (*pools.Member, err
 	return &lbMemberList[0], nil
 }
 
-var backoff = wait.Backoff{
-	Steps:    20,
-	Duration: time.Second,
+// hasOnlineMember returns true if any member of the pool can serve traffic.
+func (s *Service) hasOnlineMember(poolID string) (bool, error) {
+	lbMemberList, err := s.loadbalancerClient.ListPoolMember(s.scope.Context(), poolID, pools.ListMembersOpts{})
+	if err != nil {
+		return false, err
+	}
+	for _, member := range lbMemberList {
+		if member.OperatingStatus == "ONLINE" || member.OperatingStatus == "NO_MONITOR" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var loadBalancerPolicy = retry.Policy{
+	Interval: time.Second,
 	Factor:   1.25,
 	Jitter:   0.1,
+	Retries:  19,
 }
 
 // Possible LoadBalancer states are documented here: https://docs.openstack.org/api-ref/load-balancer/v2/index.html#prov-status
 func (s *Service) waitForLoadBalancerActive(id string) error {
 	s.scope.Logger.Info("Waiting for load balancer", "id", id, "targetStatus", "ACTIVE")
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		lb, err := s.loadbalancerClient.GetLoadBalancer(id)
+	return retry.Wait(s.scope.Context(), retry.LoadBalancer, loadBalancerPolicy, func() (bool, error) {
+		lb, err := s.loadbalancerClient.GetLoadBalancer(s.scope.Context(), id)
 		if err != nil {
 			return false, err
 		}
@@ -611,8 +1346,8 @@ func (s *Service) waitForLoadBalancerActive(id string) error {
 
 func (s *Service) waitForListener(id, target string) error {
 	s.scope.Logger.Info("Waiting for load balancer listener", "id", id, "targetStatus", target)
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		_, err := s.loadbalancerClient.GetListener(id)
+	return retry.Wait(s.scope.Context(), retry.LoadBalancer, loadBalancerPolicy, func() (bool, error) {
+		_, err := s.loadbalancerClient.GetListener(s.scope.Context(), id)
 		if err != nil {
 			return false, err
 		}
@@ -27,9 +27,14 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 )
@@ -61,6 +66,7 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 	lbtests := []struct {
 		name               string
 		fields             serviceFields
+		lbSpec             func(lbSpec *infrav1.APIServerLoadBalancer)
 		prepareServiceMock func(sf *serviceFields)
 		expectNetwork      func(m *mock.MockNetworkClientMockRecorder)
 		expectLoadBalancer func(m *mock.MockLbClientMockRecorder)
@@ -81,22 +87,23 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 					{Name: "amphora", Description: "The Octavia Amphora driver."},
 					{Name: "octavia", Description: "Deprecated alias of the Octavia Amphora driver."},
 				}
-				m.ListLoadBalancerProviders().Return(providers, nil)
+				m.ListLoadBalancerProviders(gomock.Any()).Return(providers, nil)
 
 				pendingLB := loadbalancers.LoadBalancer{
 					ID:                 "aaaaaaaa-bbbb-cccc-dddd-333333333333",
 					Name:               "k8s-clusterapi-cluster-AAAAA-kubeapi",
 					ProvisioningStatus: "PENDING_CREATE",
+					Tags:               []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-kubeapi"},
 				}
 				activeLB := pendingLB
 				activeLB.ProvisioningStatus = "ACTIVE"
 
 				// return existing loadbalancer in non-active state
 				lbList := []loadbalancers.LoadBalancer{pendingLB}
-				m.ListLoadBalancers(loadbalancers.ListOpts{Name: pendingLB.Name}).Return(lbList, nil)
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Tags: []string{"k8s-clusterapi-cluster-AAAAA-kubeapi"}}).Return(lbList, nil)
 
 				// wait for active loadbalancer by returning active loadbalancer on second call
-				m.GetLoadBalancer("aaaaaaaa-bbbb-cccc-dddd-333333333333").Return(&pendingLB, nil).Return(&activeLB, nil)
+				m.GetLoadBalancer(gomock.Any(), "aaaaaaaa-bbbb-cccc-dddd-333333333333").Return(&pendingLB, nil).Return(&activeLB, nil)
 
 				// return octavia versions
 				versions := []apiversions.APIVersion{
@@ -104,31 +111,101 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 					{ID: "2.23"},
 					{ID: "2.22"},
 				}
-				m.ListOctaviaVersions().Return(versions, nil)
+				m.ListOctaviaVersions(gomock.Any()).Return(versions, nil)
 
 				listenerList := []listeners.Listener{
 					{
 						ID:   "aaaaaaaa-bbbb-cccc-dddd-444444444444",
 						Name: "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+						Tags: []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-listener-0"},
 					},
 				}
-				m.ListListeners(listeners.ListOpts{Name: listenerList[0].Name}).Return(listenerList, nil)
+				m.ListListeners(gomock.Any(), listenerListOpts{
+					ListOpts: listeners.ListOpts{LoadbalancerID: pendingLB.ID},
+					Tags:     []string{"k8s-clusterapi-cluster-AAAAA-listener-0"},
+				}).Return(listenerList, nil)
 
 				poolList := []pools.Pool{
 					{
 						ID:   "aaaaaaaa-bbbb-cccc-dddd-555555555555",
 						Name: "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+						Tags: []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-pool-0"},
 					},
 				}
-				m.ListPools(pools.ListOpts{Name: poolList[0].Name}).Return(poolList, nil)
+				m.ListPools(gomock.Any(), poolListOpts{
+					ListOpts: pools.ListOpts{LoadbalancerID: pendingLB.ID},
+					Tags:     []string{"k8s-clusterapi-cluster-AAAAA-pool-0"},
+				}).Return(poolList, nil)
 
 				monitorList := []monitors.Monitor{
 					{
-						ID:   "aaaaaaaa-bbbb-cccc-dddd-666666666666",
-						Name: "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+						ID:         "aaaaaaaa-bbbb-cccc-dddd-666666666666",
+						Name:       "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+						Type:       "TCP",
+						Delay:      30,
+						Timeout:    5,
+						MaxRetries: 3,
 					},
 				}
-				m.ListMonitors(monitors.ListOpts{Name: monitorList[0].Name}).Return(monitorList, nil)
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorList[0].Name}).Return(monitorList, nil)
+			},
+			wantError: nil,
+		},
+		{
+			name: "reconcile loadbalancer with listener timeouts on the OVN provider skips the timeouts",
+			lbSpec: func(lbSpec *infrav1.APIServerLoadBalancer) {
+				lbSpec.Provider = "ovn"
+				lbSpec.TimeoutClientData = 60000
+			},
+			prepareServiceMock: func(sf *serviceFields) {
+				sf.networkingClient = mock.NewMockNetworkClient(mockCtrl)
+				sf.loadbalancerClient = mock.NewMockLbClient(mockCtrl)
+			},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerProviders(gomock.Any()).Return([]providers.Provider{
+					{Name: "amphora", Description: "The Octavia Amphora driver."},
+					{Name: "ovn", Description: "Octavia OVN driver."},
+				}, nil)
+				m.ListOctaviaVersions(gomock.Any()).Return([]apiversions.APIVersion{{ID: "2.24"}}, nil)
+
+				activeLB := loadbalancers.LoadBalancer{
+					ID:                 "aaaaaaaa-bbbb-cccc-dddd-333333333333",
+					Name:               "k8s-clusterapi-cluster-AAAAA-kubeapi",
+					ProvisioningStatus: "ACTIVE",
+					Tags:               []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-kubeapi"},
+				}
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Tags: []string{"k8s-clusterapi-cluster-AAAAA-kubeapi"}}).Return([]loadbalancers.LoadBalancer{activeLB}, nil)
+				m.GetLoadBalancer(gomock.Any(), activeLB.ID).Return(&activeLB, nil)
+
+				// The listener keeps the timeout chosen by Octavia, as no update is expected
+				m.ListListeners(gomock.Any(), listenerListOpts{
+					ListOpts: listeners.ListOpts{LoadbalancerID: activeLB.ID},
+					Tags:     []string{"k8s-clusterapi-cluster-AAAAA-listener-0"},
+				}).Return([]listeners.Listener{{
+					ID:                "aaaaaaaa-bbbb-cccc-dddd-444444444444",
+					Name:              "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+					Tags:              []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-listener-0"},
+					TimeoutClientData: 50000,
+				}}, nil)
+				m.ListPools(gomock.Any(), poolListOpts{
+					ListOpts: pools.ListOpts{LoadbalancerID: activeLB.ID},
+					Tags:     []string{"k8s-clusterapi-cluster-AAAAA-pool-0"},
+				}).Return([]pools.Pool{{
+					ID:       "aaaaaaaa-bbbb-cccc-dddd-555555555555",
+					Name:     "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+					LBMethod: string(lbMethodSourceIPPort),
+					Tags:     []string{"k8s-clusterapi-cluster-AAAAA", "k8s-clusterapi-cluster-AAAAA-pool-0"},
+				}}, nil)
+				monitorList := []monitors.Monitor{{
+					ID:         "aaaaaaaa-bbbb-cccc-dddd-666666666666",
+					Name:       "k8s-clusterapi-cluster-AAAAA-kubeapi-0",
+					Type:       "TCP",
+					Delay:      30,
+					Timeout:    5,
+					MaxRetries: 3,
+				}}
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorList[0].Name}).Return(monitorList, nil)
 			},
 			wantError: nil,
 		},
@@ -141,7 +218,11 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 			g := NewWithT(t)
 			tt.expectNetwork(tt.fields.networkingClient.EXPECT())
 			tt.expectLoadBalancer(tt.fields.loadbalancerClient.EXPECT())
-			err := lbs.ReconcileLoadBalancer(openStackCluster, "AAAAA", 0)
+			cluster := openStackCluster.DeepCopy()
+			if tt.lbSpec != nil {
+				tt.lbSpec(&cluster.Spec.APIServerLoadBalancer)
+			}
+			err := lbs.ReconcileLoadBalancer(cluster, "AAAAA", 0)
 			if tt.wantError != nil {
 				g.Expect(err).To(MatchError(tt.wantError))
 			} else {
@@ -150,3 +231,870 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 		})
 	}
 }
+
+func Test_getLoadBalancerFlavorID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name       string
+		lbSpec     infrav1.APIServerLoadBalancer
+		lbProvider string
+		expect     func(m *mock.MockLbClientMockRecorder)
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "no flavor requested",
+			lbProvider: "amphora",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "",
+		},
+		{
+			name:       "flavor by ID",
+			lbSpec:     infrav1.APIServerLoadBalancer{FlavorID: "flavor-id"},
+			lbProvider: "amphora",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "flavor-id",
+		},
+		{
+			name:       "flavor by name",
+			lbSpec:     infrav1.APIServerLoadBalancer{FlavorName: "small"},
+			lbProvider: "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerFlavors(gomock.Any()).Return([]clients.LoadBalancerFlavor{
+					{ID: "flavor-id", Name: "small"},
+					{ID: "other-flavor-id", Name: "large"},
+				}, nil)
+			},
+			want: "flavor-id",
+		},
+		{
+			name:       "flavor name not found",
+			lbSpec:     infrav1.APIServerLoadBalancer{FlavorName: "small"},
+			lbProvider: "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerFlavors(gomock.Any()).Return([]clients.LoadBalancerFlavor{}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:       "flavors are ignored for the ovn provider",
+			lbSpec:     infrav1.APIServerLoadBalancer{FlavorID: "flavor-id"},
+			lbProvider: "ovn",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{APIServerLoadBalancer: tt.lbSpec},
+			}
+			got, err := lbs.getLoadBalancerFlavorID(openStackCluster, "2.24", tt.lbProvider)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_validateLoadBalancerAvailabilityZone(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	availabilityZones := []clients.LoadBalancerAvailabilityZone{
+		{Name: "az1", Enabled: true},
+		{Name: "az2", Enabled: false},
+	}
+	tests := []struct {
+		name             string
+		availabilityZone string
+		lbProvider       string
+		expect           func(m *mock.MockLbClientMockRecorder)
+		wantErr          bool
+	}{
+		{
+			name:       "no availability zone requested",
+			lbProvider: "amphora",
+			expect:     func(m *mock.MockLbClientMockRecorder) {},
+		},
+		{
+			name:             "enabled availability zone",
+			availabilityZone: "az1",
+			lbProvider:       "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerAvailabilityZones(gomock.Any()).Return(availabilityZones, nil)
+			},
+		},
+		{
+			name:             "disabled availability zone",
+			availabilityZone: "az2",
+			lbProvider:       "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerAvailabilityZones(gomock.Any()).Return(availabilityZones, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:             "availability zone not found",
+			availabilityZone: "az3",
+			lbProvider:       "amphora",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancerAvailabilityZones(gomock.Any()).Return(availabilityZones, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:             "availability zones are not supported by the ovn provider",
+			availabilityZone: "az1",
+			lbProvider:       "ovn",
+			expect:           func(m *mock.MockLbClientMockRecorder) {},
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{AvailabilityZone: tt.availabilityZone},
+				},
+			}
+			err := lbs.validateLoadBalancerAvailabilityZone(openStackCluster, "2.24", tt.lbProvider)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_getLoadBalancerProvider(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	availableProviders := []providers.Provider{
+		{Name: "amphora", Description: "The Octavia Amphora driver."},
+		{Name: "ovn", Description: "Octavia OVN driver."},
+	}
+	tests := []struct {
+		name     string
+		provider string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "amphora is preferred by default",
+			want: "amphora",
+		},
+		{
+			name:     "requested provider",
+			provider: "ovn",
+			want:     "ovn",
+		},
+		{
+			name:     "requested provider is not available",
+			provider: "f5",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			mockLbClient.EXPECT().ListLoadBalancerProviders(gomock.Any()).Return(availableProviders, nil)
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Provider: tt.provider},
+				},
+			}
+			got, err := lbs.getLoadBalancerProvider(openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_getOrCreateMonitor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		monitorName = "k8s-clusterapi-cluster-AAAAA-kubeapi-0"
+		monitorID   = "aaaaaaaa-bbbb-cccc-dddd-666666666666"
+		poolID      = "aaaaaaaa-bbbb-cccc-dddd-555555555555"
+		lbID        = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+	)
+	activeLB := &loadbalancers.LoadBalancer{ID: lbID, ProvisioningStatus: "ACTIVE"}
+	defaultMonitor := monitors.Monitor{
+		ID:         monitorID,
+		Name:       monitorName,
+		Type:       "TCP",
+		Delay:      30,
+		Timeout:    5,
+		MaxRetries: 3,
+	}
+
+	tests := []struct {
+		name          string
+		healthMonitor *infrav1.HealthMonitor
+		expect        func(m *mock.MockLbClientMockRecorder)
+	}{
+		{
+			name: "create monitor with defaults",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorName}).Return([]monitors.Monitor{}, nil)
+				m.CreateMonitor(gomock.Any(), monitors.CreateOpts{
+					Name:       monitorName,
+					PoolID:     poolID,
+					Type:       "TCP",
+					Delay:      30,
+					Timeout:    5,
+					MaxRetries: 3,
+				}).Return(&defaultMonitor, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+			},
+		},
+		{
+			name: "create monitor from spec",
+			healthMonitor: &infrav1.HealthMonitor{
+				Type:           infrav1.HealthMonitorTypeHTTPS,
+				Delay:          10,
+				Timeout:        3,
+				MaxRetries:     5,
+				MaxRetriesDown: 2,
+				URLPath:        "/readyz",
+			},
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorName}).Return([]monitors.Monitor{}, nil)
+				m.CreateMonitor(gomock.Any(), monitors.CreateOpts{
+					Name:           monitorName,
+					PoolID:         poolID,
+					Type:           "HTTPS",
+					Delay:          10,
+					Timeout:        3,
+					MaxRetries:     5,
+					MaxRetriesDown: 2,
+					URLPath:        "/readyz",
+				}).Return(&defaultMonitor, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+			},
+		},
+		{
+			name: "existing monitor matches defaults",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorName}).Return([]monitors.Monitor{defaultMonitor}, nil)
+			},
+		},
+		{
+			name:          "existing monitor is updated",
+			healthMonitor: &infrav1.HealthMonitor{Delay: 10, MaxRetriesDown: 2},
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorName}).Return([]monitors.Monitor{defaultMonitor}, nil)
+				m.UpdateMonitor(gomock.Any(), monitorID, monitors.UpdateOpts{
+					Delay:          10,
+					Timeout:        5,
+					MaxRetries:     3,
+					MaxRetriesDown: 2,
+				}).Return(&defaultMonitor, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+			},
+		},
+		{
+			name:          "existing monitor is recreated when the type changes",
+			healthMonitor: &infrav1.HealthMonitor{Type: infrav1.HealthMonitorTypeHTTP, URLPath: "/healthz"},
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: monitorName}).Return([]monitors.Monitor{defaultMonitor}, nil)
+				m.DeleteMonitor(gomock.Any(), monitorID).Return(nil)
+				m.CreateMonitor(gomock.Any(), monitors.CreateOpts{
+					Name:       monitorName,
+					PoolID:     poolID,
+					Type:       "HTTP",
+					Delay:      30,
+					Timeout:    5,
+					MaxRetries: 3,
+					URLPath:    "/healthz",
+				}).Return(&defaultMonitor, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil).Times(2)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{HealthMonitor: tt.healthMonitor},
+				},
+			}
+			err := lbs.getOrCreateMonitor(openStackCluster, monitorName, poolID, lbID)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func Test_getCanonicalAllowedCIDRs(t *testing.T) {
+	tests := []struct {
+		name             string
+		openStackCluster *infrav1.OpenStackCluster
+		egressIP         string
+		want             []string
+	}{
+		{
+			name:             "no allowed CIDRs",
+			openStackCluster: &infrav1.OpenStackCluster{},
+			want:             nil,
+		},
+		{
+			name: "allowed CIDRs without network status",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						AllowedCIDRs: []string{"192.168.10.0/24", "10.0.0.1"},
+					},
+				},
+			},
+			want: []string{"10.0.0.1/32", "192.168.10.0/24"},
+		},
+		{
+			name: "bastion, subnet and router IPs are appended",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						AllowedCIDRs: []string{"192.168.10.0/24", "10.6.0.0/24"},
+					},
+					Bastion: &infrav1.Bastion{Enabled: true},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Bastion: &infrav1.Instance{IP: "10.6.0.10", FloatingIP: "172.24.4.10"},
+					Network: &infrav1.Network{
+						Subnet: &infrav1.Subnet{CIDR: "10.6.0.0/24"},
+						Router: &infrav1.Router{IPs: []string{"172.24.4.1"}},
+					},
+				},
+			},
+			want: []string{"10.6.0.0/24", "10.6.0.10/32", "172.24.4.1/32", "172.24.4.10/32", "192.168.10.0/24"},
+		},
+		{
+			name: "bastion without floating IP and no router",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						AllowedCIDRs: []string{"192.168.10.0/24"},
+					},
+					Bastion: &infrav1.Bastion{Enabled: true},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Bastion: &infrav1.Instance{IP: "10.6.0.10"},
+					Network: &infrav1.Network{},
+				},
+			},
+			want: []string{"10.6.0.10/32", "192.168.10.0/24"},
+		},
+		{
+			name: "IPv6 VIP only allows IPv6 CIDRs",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						IPVersion:    6,
+						AllowedCIDRs: []string{"2001:db8:10::/48", "2001:db8:20::1", "192.168.10.0/24"},
+					},
+					Bastion: &infrav1.Bastion{Enabled: true},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Bastion: &infrav1.Instance{IP: "10.6.0.10", FloatingIP: "172.24.4.10"},
+					Network: &infrav1.Network{
+						Subnet:     &infrav1.Subnet{CIDR: "10.6.0.0/24"},
+						IPv6Subnet: &infrav1.Subnet{CIDR: "2001:db8:6::/64"},
+						Router:     &infrav1.Router{IPs: []string{"172.24.4.1"}},
+					},
+				},
+			},
+			want: []string{"2001:db8:10::/48", "2001:db8:20::1/128", "2001:db8:6::/64"},
+		},
+		{
+			name: "egress IP of the controller is appended",
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						AllowedCIDRs: []string{"192.168.10.0/24"},
+					},
+				},
+			},
+			egressIP: "203.0.113.10",
+			want:     []string{"192.168.10.0/24", "203.0.113.10/32"},
+		},
+		{
+			name:             "egress IP of the controller does not restrict access",
+			openStackCluster: &infrav1.OpenStackCluster{},
+			egressIP:         "203.0.113.10",
+			want:             nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getCanonicalAllowedCIDRs(tt.openStackCluster, tt.egressIP)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_getOrUpdateAllowedCIDRS(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const listenerID = "aaaaaaaa-bbbb-cccc-dddd-444444444444"
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+				Enabled:      true,
+				AllowedCIDRs: []string{"192.168.10.0/24", "10.6.0.0/16"},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		listenerCIDRs []string
+		expect        func(m *mock.MockLbClientMockRecorder)
+	}{
+		{
+			name:          "listener is up to date in a different order",
+			listenerCIDRs: []string{"192.168.10.0/24", "10.6.0.0/16"},
+			expect:        func(m *mock.MockLbClientMockRecorder) {},
+		},
+		{
+			name:          "manually removed CIDR is restored",
+			listenerCIDRs: []string{"192.168.10.0/24"},
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				allowedCIDRs := []string{"10.6.0.0/16", "192.168.10.0/24"}
+				m.UpdateListener(gomock.Any(), listenerID, listeners.UpdateOpts{AllowedCIDRs: &allowedCIDRs}).Return(&listeners.Listener{ID: listenerID}, nil)
+				m.GetListener(gomock.Any(), listenerID).Return(&listeners.Listener{ID: listenerID}, nil)
+			},
+		},
+		{
+			name:          "manually added CIDR is removed",
+			listenerCIDRs: []string{"192.168.10.0/24", "10.6.0.0/16", "0.0.0.0/0"},
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				allowedCIDRs := []string{"10.6.0.0/16", "192.168.10.0/24"}
+				m.UpdateListener(gomock.Any(), listenerID, listeners.UpdateOpts{AllowedCIDRs: &allowedCIDRs}).Return(&listeners.Listener{ID: listenerID}, nil)
+				m.GetListener(gomock.Any(), listenerID).Return(&listeners.Listener{ID: listenerID}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+			listener := &listeners.Listener{ID: listenerID, AllowedCIDRs: tt.listenerCIDRs}
+			g.Expect(lbs.getOrUpdateAllowedCIDRS(openStackCluster, listener)).To(Succeed())
+			g.Expect(listener.AllowedCIDRs).To(ConsistOf("10.6.0.0/16", "192.168.10.0/24"))
+		})
+	}
+}
+
+func Test_getOrCreateListenerSettings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		listenerName = "k8s-clusterapi-cluster-AAAAA-kubeapi-6443"
+		listenerID   = "aaaaaaaa-bbbb-cccc-dddd-444444444444"
+		lbID         = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+	)
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+				Enabled:           true,
+				ConnectionLimit:   -1,
+				TimeoutClientData: 3600000,
+				TimeoutMemberData: 3600000,
+			},
+		},
+	}
+	activeLB := &loadbalancers.LoadBalancer{ID: lbID, ProvisioningStatus: "ACTIVE"}
+	listener := listeners.Listener{
+		ID:                   listenerID,
+		Name:                 listenerName,
+		Protocol:             "TCP",
+		ConnLimit:            -1,
+		TimeoutClientData:    3600000,
+		TimeoutMemberData:    3600000,
+		TimeoutMemberConnect: 5000,
+		ProvisioningStatus:   "ACTIVE",
+	}
+
+	tests := []struct {
+		name   string
+		expect func(m *mock.MockLbClientMockRecorder)
+	}{
+		{
+			name: "listener is created with the settings of the spec",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{}, nil)
+				m.CreateListener(gomock.Any(), listeners.CreateOpts{
+					Name:              listenerName,
+					Protocol:          "TCP",
+					ProtocolPort:      6443,
+					LoadbalancerID:    lbID,
+					ConnLimit:         pointer.Int(-1),
+					TimeoutClientData: pointer.Int(3600000),
+					TimeoutMemberData: pointer.Int(3600000),
+				}).Return(&listener, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+				m.GetListener(gomock.Any(), listenerID).Return(&listener, nil)
+			},
+		},
+		{
+			name: "up to date listener is not changed",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{listener}, nil)
+			},
+		},
+		{
+			name: "changed settings are updated",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				oldListener := listener
+				oldListener.ConnLimit = 1000
+				oldListener.TimeoutClientData = 50000
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{oldListener}, nil)
+				m.UpdateListener(gomock.Any(), listenerID, listeners.UpdateOpts{
+					ConnLimit:         pointer.Int(-1),
+					TimeoutClientData: pointer.Int(3600000),
+				}).Return(&listener, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			s := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+
+			got, err := s.getOrCreateListener(openStackCluster, "AAAAA", listenerName, lbID, 6443, "", "", true)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.TimeoutClientData).To(Equal(3600000))
+		})
+	}
+}
+
+func Test_ReconcileExistingLoadBalancer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		lbID       = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		lbName     = "shared-kubeapi"
+		vipPortID  = "aaaaaaaa-bbbb-cccc-dddd-777777777777"
+		listenerID = "aaaaaaaa-bbbb-cccc-dddd-444444444444"
+		poolID     = "aaaaaaaa-bbbb-cccc-dddd-555555555555"
+	)
+	existingLB := loadbalancers.LoadBalancer{
+		ID:                 lbID,
+		Name:               lbName,
+		VipAddress:         "10.0.0.10",
+		VipPortID:          vipPortID,
+		ProvisioningStatus: "ACTIVE",
+	}
+
+	tests := []struct {
+		name               string
+		ref                infrav1.LoadBalancerReference
+		expectNetwork      func(m *mock.MockNetworkClientMockRecorder)
+		expectLoadBalancer func(m *mock.MockLbClientMockRecorder)
+		want               *infrav1.LoadBalancer
+		wantErr            string
+	}{
+		{
+			name: "existing load balancer is referenced by ID",
+			ref:  infrav1.LoadBalancerReference{ID: lbID},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: vipPortID}).Return([]floatingips.FloatingIP{{FloatingIP: "172.24.4.10"}}, nil)
+			},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(&existingLB, nil)
+				m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: lbID, ProtocolPort: 6443}).Return([]listeners.Listener{
+					{ID: listenerID, DefaultPoolID: poolID, AllowedCIDRs: []string{"10.0.0.0/8"}},
+				}, nil)
+			},
+			want: &infrav1.LoadBalancer{
+				Name:         lbName,
+				ID:           lbID,
+				InternalIP:   "10.0.0.10",
+				IP:           "172.24.4.10",
+				AllowedCIDRs: []string{"10.0.0.0/8"},
+			},
+		},
+		{
+			name: "existing load balancer is referenced by name",
+			ref:  infrav1.LoadBalancerReference{Name: lbName},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: vipPortID}).Return([]floatingips.FloatingIP{}, nil)
+			},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{existingLB}, nil)
+				m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: lbID, ProtocolPort: 6443}).Return([]listeners.Listener{
+					{ID: listenerID, DefaultPoolID: poolID},
+				}, nil)
+			},
+			want: &infrav1.LoadBalancer{
+				Name:       lbName,
+				ID:         lbID,
+				InternalIP: "10.0.0.10",
+			},
+		},
+		{
+			name:          "existing load balancer does not exist",
+			ref:           infrav1.LoadBalancerReference{Name: lbName},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{}, nil)
+			},
+			wantErr: `existing load balancer "shared-kubeapi" does not exist`,
+		},
+		{
+			name:          "existing load balancer name is ambiguous",
+			ref:           infrav1.LoadBalancerReference{Name: lbName},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{existingLB, existingLB}, nil)
+			},
+			wantErr: `found 2 load balancers with name "shared-kubeapi", expected one`,
+		},
+		{
+			name:          "existing load balancer has no listener on the API server port",
+			ref:           infrav1.LoadBalancerReference{ID: lbID},
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(&existingLB, nil)
+				m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: lbID, ProtocolPort: 6443}).Return([]listeners.Listener{}, nil)
+			},
+			wantErr: "existing load balancer " + lbID + " has no listener on port 6443",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ref := tt.ref
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+						Enabled:              true,
+						ExistingLoadBalancer: &ref,
+					},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{},
+				},
+			}
+			mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expectNetwork(mockNetworkClient.EXPECT())
+			tt.expectLoadBalancer(mockLbClient.EXPECT())
+			networkingService := networking.NewTestService("", mockNetworkClient, logr.Discard())
+			lbs := NewLoadBalancerTestService("", mockLbClient, networkingService, logr.Discard())
+
+			err := lbs.ReconcileLoadBalancer(openStackCluster, "AAAAA", 6443)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.APIServerLoadBalancer).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_ExistingLoadBalancerMembers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		lbID     = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		poolID   = "aaaaaaaa-bbbb-cccc-dddd-555555555555"
+		memberID = "aaaaaaaa-bbbb-cccc-dddd-888888888888"
+		// Members are named after the pool CAPO would have created.
+		memberName = "k8s-clusterapi-cluster-AAAAA-kubeapi-6443-control-plane-0"
+	)
+	activeLB := &loadbalancers.LoadBalancer{ID: lbID, ProvisioningStatus: "ACTIVE"}
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+				Enabled:              true,
+				ExistingLoadBalancer: &infrav1.LoadBalancerReference{ID: lbID},
+			},
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.10", Port: 6443},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				Subnet:                &infrav1.Subnet{},
+				APIServerLoadBalancer: &infrav1.LoadBalancer{ID: lbID},
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+		},
+	}
+	openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Name: "control-plane-0"}}
+
+	expectDefaultPool := func(m *mock.MockLbClientMockRecorder) {
+		m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: lbID, ProtocolPort: 6443}).Return([]listeners.Listener{
+			{ID: "listener-id", DefaultPoolID: poolID},
+		}, nil)
+		m.GetPool(gomock.Any(), poolID).Return(&pools.Pool{ID: poolID}, nil)
+	}
+
+	t.Run("member is added to the default pool of the listener", func(t *testing.T) {
+		g := NewWithT(t)
+		mockLbClient := mock.NewMockLbClient(mockCtrl)
+		m := mockLbClient.EXPECT()
+		expectDefaultPool(m)
+		m.ListPoolMember(gomock.Any(), poolID, pools.ListMembersOpts{Name: memberName}).Return([]pools.Member{}, nil)
+		m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil).Times(2)
+		m.CreatePoolMember(gomock.Any(), poolID, pools.CreateMemberOpts{Name: memberName, ProtocolPort: 6443, Address: "10.0.0.20"}).Return(&pools.Member{ID: memberID}, nil)
+
+		lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+		g.Expect(lbs.ReconcileLoadBalancerMember(openStackCluster, machine, openStackMachine, "AAAAA", "10.0.0.20")).To(Succeed())
+	})
+
+	t.Run("member is removed from the default pool of the listener", func(t *testing.T) {
+		g := NewWithT(t)
+		mockLbClient := mock.NewMockLbClient(mockCtrl)
+		m := mockLbClient.EXPECT()
+		m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil).Times(3)
+		expectDefaultPool(m)
+		m.ListPoolMember(gomock.Any(), poolID, pools.ListMembersOpts{Name: memberName}).Return([]pools.Member{{ID: memberID}}, nil)
+		m.DeletePoolMember(gomock.Any(), poolID, memberID).Return(nil)
+
+		lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+		g.Expect(lbs.DeleteLoadBalancerMember(openStackCluster, machine, openStackMachine, "AAAAA")).To(Succeed())
+	})
+
+	t.Run("load balancer is not deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		mockLbClient := mock.NewMockLbClient(mockCtrl)
+
+		lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+		g.Expect(lbs.DeleteLoadBalancer(openStackCluster, "AAAAA")).To(Succeed())
+	})
+}
+
+func Test_hasOnlineMember(t *testing.T) {
+	const poolID = "aaaaaaaa-bbbb-cccc-dddd-555555555555"
+
+	tests := []struct {
+		name    string
+		members []pools.Member
+		want    bool
+	}{
+		{
+			name: "no members",
+			want: false,
+		},
+		{
+			name:    "members are offline",
+			members: []pools.Member{{Name: "a", OperatingStatus: "OFFLINE"}, {Name: "b", OperatingStatus: "ERROR"}},
+			want:    false,
+		},
+		{
+			name:    "one member is online",
+			members: []pools.Member{{Name: "a", OperatingStatus: "ERROR"}, {Name: "b", OperatingStatus: "ONLINE"}},
+			want:    true,
+		},
+		{
+			name:    "member without monitor",
+			members: []pools.Member{{Name: "a", OperatingStatus: "NO_MONITOR"}},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			lbClient := mock.NewMockLbClient(mockCtrl)
+			lbClient.EXPECT().ListPoolMember(gomock.Any(), poolID, pools.ListMembersOpts{}).Return(tt.members, nil)
+
+			networkingService := networking.NewTestService("", mock.NewMockNetworkClient(mockCtrl), logr.Discard())
+			lbs := NewLoadBalancerTestService("", lbClient, networkingService, logr.Discard())
+			g.Expect(lbs.hasOnlineMember(poolID)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_deleteLoadBalancer(t *testing.T) {
+	const (
+		lbID       = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		vipPortID  = "aaaaaaaa-bbbb-cccc-dddd-777777777777"
+		fipID      = "aaaaaaaa-bbbb-cccc-dddd-888888888888"
+		floatingIP = "203.0.113.10"
+	)
+	openStackCluster := &infrav1.OpenStackCluster{}
+	lb := loadbalancers.LoadBalancer{ID: lbID, Name: "k8s-clusterapi-cluster-AAAAA-kubeapi", VipPortID: vipPortID}
+	fip := floatingips.FloatingIP{ID: fipID, FloatingIP: floatingIP, PortID: vipPortID}
+
+	tests := []struct {
+		name           string
+		keepFloatingIP bool
+	}{
+		{
+			name: "floating IP is deleted with the load balancer",
+		},
+		{
+			name:           "floating IP is kept when the load balancer is removed",
+			keepFloatingIP: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			lbClient := mock.NewMockLbClient(mockCtrl)
+			networkClient := mock.NewMockNetworkClient(mockCtrl)
+
+			lbClient.EXPECT().ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lb.Name}).Return([]loadbalancers.LoadBalancer{lb}, nil)
+			networkClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: vipPortID}).Return([]floatingips.FloatingIP{fip}, nil)
+			networkClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: floatingIP}).Return([]floatingips.FloatingIP{fip}, nil)
+			networkClient.EXPECT().GetFloatingIPWithRevision(gomock.Any(), fipID).Return(&fip, 2, nil)
+			networkClient.EXPECT().UpdateFloatingIPWithRevision(gomock.Any(), fipID, 2, gomock.Any()).Return(&fip, nil)
+			networkClient.EXPECT().GetFloatingIP(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, Status: "DOWN"}, nil)
+			if !tt.keepFloatingIP {
+				networkClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: floatingIP}).Return([]floatingips.FloatingIP{fip}, nil)
+				networkClient.EXPECT().DeleteFloatingIP(gomock.Any(), fipID).Return(nil)
+			}
+			lbClient.EXPECT().DeleteLoadBalancer(gomock.Any(), lbID, loadbalancers.DeleteOpts{Cascade: true}).Return(nil)
+
+			networkingService := networking.NewTestService("", networkClient, logr.Discard())
+			lbs := NewLoadBalancerTestService("", lbClient, networkingService, logr.Discard())
+			g.Expect(lbs.deleteLoadBalancer(openStackCluster, "AAAAA", tt.keepFloatingIP)).To(Succeed())
+		})
+	}
+}
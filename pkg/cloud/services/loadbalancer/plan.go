@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// ListenerResource is the type of the listeners returned by PlanLoadBalancer.
+const ListenerResource = "listener"
+
+// PlanLoadBalancer returns the operations ReconcileLoadBalancer would perform to create the API server load balancer
+// of the cluster and its listener on apiServerPort, or RemoveLoadBalancer would perform to delete the load balancer
+// once it is disabled. Existing and shared load balancers are not created for the cluster and are not planned.
+func (s *Service) PlanLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string, apiServerPort int) ([]infrav1.PlannedOperation, error) {
+	lbSpec := openStackCluster.Spec.APIServerLoadBalancer
+	if lbSpec.ExistingLoadBalancer != nil || lbSpec.Shared != nil {
+		return nil, nil
+	}
+
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	lb, err := s.checkIfLbExists(loadBalancerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lbSpec.Enabled {
+		if lb == nil {
+			return nil, nil
+		}
+		return []infrav1.PlannedOperation{{Action: infrav1.PlannedActionDelete, Resource: LoadBalancerResource, Name: loadBalancerName, ID: lb.ID, Message: "load balancer is disabled"}}, nil
+	}
+
+	var operations []infrav1.PlannedOperation
+	if lb == nil {
+		operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: LoadBalancerResource, Name: loadBalancerName})
+	}
+	listenerName, err := getListenerName(openStackCluster, clusterName, loadBalancerName, apiServerPort)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := s.checkIfListenerExists(listenerName)
+	if err != nil {
+		return nil, err
+	}
+	if listener == nil {
+		operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: ListenerResource, Name: listenerName})
+	}
+	return operations, nil
+}
@@ -32,6 +32,9 @@ type Service struct {
 	scope              *scope.Scope
 	loadbalancerClient clients.LbClient
 	networkingService  *networking.Service
+	// The key manager client is only created when TLS termination is requested, as
+	// Barbican is not available in every cloud.
+	_keyManagerClient clients.KeyManagerClient
 }
 
 // NewService returns an instance of the loadbalancer service.
@@ -65,3 +68,16 @@ func NewLoadBalancerTestService(projectID string, lbClient clients.LbClient, cli
 		networkingService:  client,
 	}
 }
+
+func (s *Service) getKeyManagerClient() (clients.KeyManagerClient, error) {
+	if s._keyManagerClient == nil {
+		keyManagerClient, err := clients.NewKeyManagerClient(s.scope)
+		if err != nil {
+			return nil, err
+		}
+
+		s._keyManagerClient = keyManagerClient
+	}
+
+	return s._keyManagerClient, nil
+}
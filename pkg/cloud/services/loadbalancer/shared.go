@@ -0,0 +1,268 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/l7policies"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// defaultAPIServerPort is the port the API servers of a cluster behind a shared load
+// balancer listen on if spec.apiServerPort is not set.
+const defaultAPIServerPort = 6443
+
+// poolCreateOpts adds the tls_enabled field of Octavia pools, which is not supported by
+// gophercloud yet.
+type poolCreateOpts struct {
+	pools.CreateOpts
+	TLSEnabled bool
+}
+
+func (opts poolCreateOpts) ToPoolCreateMap() (map[string]interface{}, error) {
+	b, err := opts.CreateOpts.ToPoolCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	b["pool"].(map[string]interface{})["tls_enabled"] = opts.TLSEnabled
+	return b, nil
+}
+
+// getSharedBackendPort returns the port the API servers of a cluster behind a shared load
+// balancer listen on. It differs from the port of the control plane endpoint, which is the
+// port of the shared listener.
+func getSharedBackendPort(openStackCluster *infrav1.OpenStackCluster) int {
+	if openStackCluster.Spec.APIServerPort != 0 {
+		return openStackCluster.Spec.APIServerPort
+	}
+	return defaultAPIServerPort
+}
+
+// reconcileSharedLoadBalancer creates the pool of the cluster on the shared load balancer
+// and the L7 policy routing requests for the host name of the cluster to it.
+func (s *Service) reconcileSharedLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	shared := openStackCluster.Spec.APIServerLoadBalancer.Shared
+	s.scope.Logger.Info("Reconciling shared load balancer", "id", shared.LoadBalancer.ID, "name", shared.LoadBalancer.Name, "hostname", shared.Hostname)
+
+	lb, err := s.getExistingLoadBalancer(&shared.LoadBalancer)
+	if err != nil {
+		return err
+	}
+	if lb == nil {
+		return fmt.Errorf("shared load balancer %s does not exist", referenceString(&shared.LoadBalancer))
+	}
+
+	listener, err := s.getListenerByPort(lb.ID, shared.Port)
+	if err != nil {
+		return err
+	}
+	if listener == nil {
+		return fmt.Errorf("shared load balancer %s has no listener on port %d", lb.ID, shared.Port)
+	}
+	if listener.Protocol != string(listeners.ProtocolTerminatedHTTPS) {
+		return fmt.Errorf("listener %s of shared load balancer %s has protocol %s, expected %s", listener.ID, lb.ID, listener.Protocol, listeners.ProtocolTerminatedHTTPS)
+	}
+
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	poolName, err := getPoolName(openStackCluster, clusterName, loadBalancerName, getSharedBackendPort(openStackCluster))
+	if err != nil {
+		return err
+	}
+
+	pool, err := s.getOrCreateSharedPool(openStackCluster, poolName, lb.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.getOrCreateMonitor(openStackCluster, poolName, pool.ID, lb.ID); err != nil {
+		return err
+	}
+	if err := s.getOrCreateL7Policy(openStackCluster, poolName, listener.ID, pool.ID, lb.ID, shared.Hostname); err != nil {
+		return err
+	}
+
+	var lbFloatingIP string
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP && lb.VipPortID != "" {
+		fp, err := s.networkingService.GetFloatingIPByPortID(lb.VipPortID)
+		if err != nil {
+			return err
+		}
+		if fp != nil {
+			lbFloatingIP = fp.FloatingIP
+		}
+	}
+
+	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
+		Name:       lb.Name,
+		ID:         lb.ID,
+		InternalIP: lb.VipAddress,
+		IP:         lbFloatingIP,
+	}
+	return nil
+}
+
+// getOrCreateSharedPool creates the pool of the cluster on the shared load balancer. The pool
+// is not the default pool of a listener, requests are routed to it by an L7 policy. TLS is
+// terminated by the listener, so the connections to the API servers are re-encrypted.
+func (s *Service) getOrCreateSharedPool(openStackCluster *infrav1.OpenStackCluster, poolName, lbID string) (*pools.Pool, error) {
+	pool, err := s.checkIfPoolExists(poolName)
+	if err != nil {
+		return nil, err
+	}
+	if pool != nil {
+		return pool, nil
+	}
+
+	s.scope.Logger.Info("Creating load balancer pool on shared load balancer", "name", poolName, "lb-id", lbID)
+
+	opts := poolCreateOpts{
+		CreateOpts: pools.CreateOpts{
+			Name:           poolName,
+			Protocol:       pools.ProtocolHTTP,
+			LBMethod:       pools.LBMethodRoundRobin,
+			LoadbalancerID: lbID,
+		},
+		TLSEnabled: true,
+	}
+	pool, err = s.loadbalancerClient.CreatePool(s.scope.Context(), opts)
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedCreatePool", "Failed to create pool %s: %v", poolName, err)
+		return nil, err
+	}
+
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		record.Warnf(openStackCluster, "FailedCreatePool", "Failed to create pool %s with id %s: wait for load balancer active %s: %v", poolName, pool.ID, lbID, err)
+		return nil, err
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulCreatePool", "Created pool %s with id %s", poolName, pool.ID)
+	return pool, nil
+}
+
+func (s *Service) getOrCreateL7Policy(openStackCluster *infrav1.OpenStackCluster, policyName, listenerID, poolID, lbID, hostname string) error {
+	policy, err := s.checkIfL7PolicyExists(policyName)
+	if err != nil {
+		return err
+	}
+	if policy != nil {
+		return nil
+	}
+
+	s.scope.Logger.Info("Creating L7 policy", "name", policyName, "listener-id", listenerID, "hostname", hostname)
+
+	opts := l7policies.CreateOpts{
+		Name:           policyName,
+		ListenerID:     listenerID,
+		Action:         l7policies.ActionRedirectToPool,
+		RedirectPoolID: poolID,
+		Rules: []l7policies.CreateRuleOpts{
+			{
+				RuleType:    l7policies.TypeHostName,
+				CompareType: l7policies.CompareTypeEqual,
+				Value:       hostname,
+			},
+		},
+	}
+	policy, err = s.loadbalancerClient.CreateL7Policy(s.scope.Context(), opts)
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedCreateL7Policy", "Failed to create L7 policy %s: %v", policyName, err)
+		return err
+	}
+
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		record.Warnf(openStackCluster, "FailedCreateL7Policy", "Failed to create L7 policy %s with id %s: wait for load balancer active %s: %v", policyName, policy.ID, lbID, err)
+		return err
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulCreateL7Policy", "Created L7 policy %s with id %s", policyName, policy.ID)
+	return nil
+}
+
+// deleteSharedLoadBalancer deletes the L7 policy and the pool of the cluster from the shared
+// load balancer. Deleting the pool also deletes its members and health monitor.
+func (s *Service) deleteSharedLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	shared := openStackCluster.Spec.APIServerLoadBalancer.Shared
+
+	lb, err := s.getExistingLoadBalancer(&shared.LoadBalancer)
+	if err != nil {
+		return err
+	}
+	if lb == nil {
+		return nil
+	}
+
+	loadBalancerName, err := getLoadBalancerName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	poolName, err := getPoolName(openStackCluster, clusterName, loadBalancerName, getSharedBackendPort(openStackCluster))
+	if err != nil {
+		return err
+	}
+
+	policy, err := s.checkIfL7PolicyExists(poolName)
+	if err != nil {
+		return err
+	}
+	if policy != nil {
+		if err := s.waitForLoadBalancerActive(lb.ID); err != nil {
+			return err
+		}
+		s.scope.Logger.Info("Deleting L7 policy", "name", policy.Name)
+		if err := s.loadbalancerClient.DeleteL7Policy(s.scope.Context(), policy.ID); err != nil {
+			record.Warnf(openStackCluster, "FailedDeleteL7Policy", "Failed to delete L7 policy %s with id %s: %v", policy.Name, policy.ID, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulDeleteL7Policy", "Deleted L7 policy %s with id %s", policy.Name, policy.ID)
+	}
+
+	pool, err := s.checkIfPoolExists(poolName)
+	if err != nil {
+		return err
+	}
+	if pool != nil {
+		if err := s.waitForLoadBalancerActive(lb.ID); err != nil {
+			return err
+		}
+		s.scope.Logger.Info("Deleting load balancer pool", "name", pool.Name)
+		if err := s.loadbalancerClient.DeletePool(s.scope.Context(), pool.ID); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(openStackCluster, "FailedDeletePool", "Failed to delete pool %s with id %s: %v", pool.Name, pool.ID, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulDeletePool", "Deleted pool %s with id %s", pool.Name, pool.ID)
+	}
+	return nil
+}
+
+func (s *Service) checkIfL7PolicyExists(name string) (*l7policies.L7Policy, error) {
+	policyList, err := s.loadbalancerClient.ListL7Policies(s.scope.Context(), l7policies.ListOpts{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	if len(policyList) == 0 {
+		return nil, nil
+	}
+	return &policyList[0], nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/l7policies"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+)
+
+const (
+	sharedLBID       = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+	sharedListenerID = "aaaaaaaa-bbbb-cccc-dddd-444444444444"
+	sharedPoolID     = "aaaaaaaa-bbbb-cccc-dddd-555555555555"
+	sharedPolicyID   = "aaaaaaaa-bbbb-cccc-dddd-666666666666"
+	sharedVIPPortID  = "aaaaaaaa-bbbb-cccc-dddd-777777777777"
+	// The pool and L7 policy are named after the API server port of the cluster.
+	sharedPoolName = "k8s-clusterapi-cluster-AAAAA-kubeapi-6443"
+)
+
+func sharedLoadBalancerCluster() *infrav1.OpenStackCluster {
+	return &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{
+				Enabled: true,
+				Shared: &infrav1.SharedLoadBalancer{
+					LoadBalancer: infrav1.LoadBalancerReference{Name: "shared"},
+					Port:         443,
+					Hostname:     "api.aaaaa.example.com",
+				},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{},
+		},
+	}
+}
+
+func Test_ReconcileSharedLoadBalancer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sharedLB := loadbalancers.LoadBalancer{
+		ID:                 sharedLBID,
+		Name:               "shared",
+		VipAddress:         "10.0.0.10",
+		VipPortID:          sharedVIPPortID,
+		ProvisioningStatus: "ACTIVE",
+	}
+
+	tests := []struct {
+		name               string
+		expectNetwork      func(m *mock.MockNetworkClientMockRecorder)
+		expectLoadBalancer func(m *mock.MockLbClientMockRecorder)
+		want               *infrav1.LoadBalancer
+		wantErr            string
+	}{
+		{
+			name: "pool and L7 policy are created",
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: sharedVIPPortID}).Return([]floatingips.FloatingIP{{FloatingIP: "172.24.4.10"}}, nil)
+			},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: "shared"}).Return([]loadbalancers.LoadBalancer{sharedLB}, nil)
+				m.GetLoadBalancer(gomock.Any(), sharedLBID).Return(&sharedLB, nil).AnyTimes()
+				m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: sharedLBID, ProtocolPort: 443}).Return([]listeners.Listener{
+					{ID: sharedListenerID, Protocol: "TERMINATED_HTTPS"},
+				}, nil)
+
+				m.ListPools(gomock.Any(), pools.ListOpts{Name: sharedPoolName}).Return([]pools.Pool{}, nil)
+				m.CreatePool(gomock.Any(), poolCreateOpts{
+					CreateOpts: pools.CreateOpts{
+						Name:           sharedPoolName,
+						Protocol:       pools.ProtocolHTTP,
+						LBMethod:       pools.LBMethodRoundRobin,
+						LoadbalancerID: sharedLBID,
+					},
+					TLSEnabled: true,
+				}).Return(&pools.Pool{ID: sharedPoolID, Name: sharedPoolName}, nil)
+
+				m.ListMonitors(gomock.Any(), monitors.ListOpts{Name: sharedPoolName}).Return([]monitors.Monitor{}, nil)
+				m.CreateMonitor(gomock.Any(), monitors.CreateOpts{
+					Name:       sharedPoolName,
+					PoolID:     sharedPoolID,
+					Type:       "TCP",
+					Delay:      30,
+					Timeout:    5,
+					MaxRetries: 3,
+				}).Return(&monitors.Monitor{ID: "monitor-id"}, nil)
+
+				m.ListL7Policies(gomock.Any(), l7policies.ListOpts{Name: sharedPoolName}).Return([]l7policies.L7Policy{}, nil)
+				m.CreateL7Policy(gomock.Any(), l7policies.CreateOpts{
+					Name:           sharedPoolName,
+					ListenerID:     sharedListenerID,
+					Action:         l7policies.ActionRedirectToPool,
+					RedirectPoolID: sharedPoolID,
+					Rules: []l7policies.CreateRuleOpts{
+						{RuleType: l7policies.TypeHostName, CompareType: l7policies.CompareTypeEqual, Value: "api.aaaaa.example.com"},
+					},
+				}).Return(&l7policies.L7Policy{ID: sharedPolicyID}, nil)
+			},
+			want: &infrav1.LoadBalancer{
+				Name:       "shared",
+				ID:         sharedLBID,
+				InternalIP: "10.0.0.10",
+				IP:         "172.24.4.10",
+			},
+		},
+		{
+			name:          "listener does not terminate TLS",
+			expectNetwork: func(m *mock.MockNetworkClientMockRecorder) {},
+			expectLoadBalancer: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: "shared"}).Return([]loadbalancers.LoadBalancer{sharedLB}, nil)
+				m.ListListeners(gomock.Any(), listeners.ListOpts{LoadbalancerID: sharedLBID, ProtocolPort: 443}).Return([]listeners.Listener{
+					{ID: sharedListenerID, Protocol: "HTTPS"},
+				}, nil)
+			},
+			wantErr: "listener " + sharedListenerID + " of shared load balancer " + sharedLBID + " has protocol HTTPS, expected TERMINATED_HTTPS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := sharedLoadBalancerCluster()
+			mockNetworkClient := mock.NewMockNetworkClient(mockCtrl)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expectNetwork(mockNetworkClient.EXPECT())
+			tt.expectLoadBalancer(mockLbClient.EXPECT())
+			networkingService := networking.NewTestService("", mockNetworkClient, logr.Discard())
+			lbs := NewLoadBalancerTestService("", mockLbClient, networkingService, logr.Discard())
+
+			err := lbs.ReconcileLoadBalancer(openStackCluster, "AAAAA", 443)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.APIServerLoadBalancer).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_DeleteSharedLoadBalancer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	sharedLB := &loadbalancers.LoadBalancer{ID: sharedLBID, ProvisioningStatus: "ACTIVE"}
+	mockLbClient := mock.NewMockLbClient(mockCtrl)
+	m := mockLbClient.EXPECT()
+	m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: "shared"}).Return([]loadbalancers.LoadBalancer{*sharedLB}, nil)
+	m.GetLoadBalancer(gomock.Any(), sharedLBID).Return(sharedLB, nil).AnyTimes()
+	m.ListL7Policies(gomock.Any(), l7policies.ListOpts{Name: sharedPoolName}).Return([]l7policies.L7Policy{{ID: sharedPolicyID, Name: sharedPoolName}}, nil)
+	m.DeleteL7Policy(gomock.Any(), sharedPolicyID).Return(nil)
+	m.ListPools(gomock.Any(), pools.ListOpts{Name: sharedPoolName}).Return([]pools.Pool{{ID: sharedPoolID, Name: sharedPoolName}}, nil)
+	m.DeletePool(gomock.Any(), sharedPoolID).Return(nil)
+
+	lbs := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+	g.Expect(lbs.DeleteLoadBalancer(sharedLoadBalancerCluster(), "AAAAA")).To(Succeed())
+}
+
+func Test_getMemberPorts(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			ControlPlaneEndpoint:  clusterv1.APIEndpoint{Host: "10.0.0.10", Port: 6443},
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{AdditionalPorts: []int{8443}},
+		},
+	}
+	g.Expect(getMemberPorts(openStackCluster)).To(Equal([]int{6443, 8443}))
+
+	// The control plane endpoint of a cluster behind a shared load balancer
+	// uses the port of the shared listener.
+	openStackCluster = sharedLoadBalancerCluster()
+	openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "api.aaaaa.example.com", Port: 443}
+	g.Expect(getMemberPorts(openStackCluster)).To(Equal([]int{6443}))
+
+	openStackCluster.Spec.APIServerPort = 7443
+	g.Expect(getMemberPorts(openStackCluster)).To(Equal([]int{7443}))
+}
+
+func Test_poolCreateOpts(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := poolCreateOpts{
+		CreateOpts: pools.CreateOpts{
+			Name:     "pool",
+			Protocol: pools.ProtocolHTTP,
+			LBMethod: pools.LBMethodRoundRobin,
+		},
+		TLSEnabled: true,
+	}
+	b, err := opts.ToPoolCreateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(b["pool"]).To(HaveKeyWithValue("tls_enabled", true))
+	g.Expect(b["pool"]).To(HaveKeyWithValue("protocol", "HTTP"))
+}
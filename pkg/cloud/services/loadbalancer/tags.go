@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// If Octavia supports tags, the load balancers, listeners and pools of a cluster are tagged with the tags of the
+// cluster, a tag of the cluster and a tag identifying the object within the cluster. Objects are looked up by their
+// identifying tag before their name, so that they are found independently of their names, e.g. after clusterctl
+// move or when the status of the cluster was lost. Objects which are only found by name, e.g. because they were
+// created before Octavia supported tags, are tagged when they are adopted.
+
+// getClusterTag returns the tag of all Octavia objects of the cluster.
+func getClusterTag(clusterName string) string {
+	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+}
+
+// getObjectTag returns the tag identifying an Octavia object of the cluster, or an empty string if Octavia does
+// not support tags.
+func getObjectTag(clusterName, object string, tagsSupported bool) string {
+	if !tagsSupported {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s", getClusterTag(clusterName), object)
+}
+
+// getObjectTags returns the tags of the Octavia object of the cluster with the given identifying tag, or nil if
+// the object is not tagged.
+func getObjectTags(openStackCluster *infrav1.OpenStackCluster, clusterName, objectTag string) []string {
+	if objectTag == "" {
+		return nil
+	}
+	tags := make([]string, 0, len(openStackCluster.Spec.Tags)+2)
+	tags = append(tags, openStackCluster.Spec.Tags...)
+	return append(tags, getClusterTag(clusterName), objectTag)
+}
+
+// mergeTags returns the tags of an object with the desired tags it lacks appended, and whether any was missing.
+func mergeTags(tags, desired []string) ([]string, bool) {
+	merged := append([]string{}, tags...)
+	missing := false
+	for _, tag := range desired {
+		found := false
+		for _, t := range tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, tag)
+			missing = true
+		}
+	}
+	return merged, missing
+}
+
+// tagsQuery appends the tags filter to the query of a list request. gophercloud only supports filtering load
+// balancers by tags.
+func tagsQuery(query string, tags []string) string {
+	if len(tags) == 0 {
+		return query
+	}
+	filter := "tags=" + url.QueryEscape(strings.Join(tags, ","))
+	if query == "" {
+		return "?" + filter
+	}
+	return query + "&" + filter
+}
+
+// listenerListOpts lists the listeners with all of the given tags.
+type listenerListOpts struct {
+	listeners.ListOpts
+	Tags []string
+}
+
+func (opts listenerListOpts) ToListenerListQuery() (string, error) {
+	query, err := opts.ListOpts.ToListenerListQuery()
+	if err != nil {
+		return "", err
+	}
+	return tagsQuery(query, opts.Tags), nil
+}
+
+// poolListOpts lists the pools with all of the given tags.
+type poolListOpts struct {
+	pools.ListOpts
+	Tags []string
+}
+
+func (opts poolListOpts) ToPoolListQuery() (string, error) {
+	query, err := opts.ListOpts.ToPoolListQuery()
+	if err != nil {
+		return "", err
+	}
+	return tagsQuery(query, opts.Tags), nil
+}
+
+// findLoadBalancer returns the load balancer with the identifying tag or, if there is none, the name.
+func (s *Service) findLoadBalancer(name, objectTag string) (*loadbalancers.LoadBalancer, error) {
+	if objectTag != "" {
+		lbList, err := s.loadbalancerClient.ListLoadBalancers(s.scope.Context(), loadbalancers.ListOpts{Tags: []string{objectTag}})
+		if err != nil {
+			return nil, err
+		}
+		if len(lbList) > 0 {
+			return &lbList[0], nil
+		}
+	}
+	return s.checkIfLbExists(name)
+}
+
+// findListener returns the listener of the load balancer with the identifying tag or, if there is none, the name.
+func (s *Service) findListener(name, objectTag, lbID string) (*listeners.Listener, error) {
+	if objectTag != "" {
+		listenerList, err := s.loadbalancerClient.ListListeners(s.scope.Context(), listenerListOpts{ListOpts: listeners.ListOpts{LoadbalancerID: lbID}, Tags: []string{objectTag}})
+		if err != nil {
+			return nil, err
+		}
+		if len(listenerList) > 0 {
+			return &listenerList[0], nil
+		}
+	}
+	return s.checkIfListenerExists(name)
+}
+
+// findPool returns the pool of the load balancer with the identifying tag or, if there is none, the name.
+func (s *Service) findPool(name, objectTag, lbID string) (*pools.Pool, error) {
+	if objectTag != "" {
+		poolList, err := s.loadbalancerClient.ListPools(s.scope.Context(), poolListOpts{ListOpts: pools.ListOpts{LoadbalancerID: lbID}, Tags: []string{objectTag}})
+		if err != nil {
+			return nil, err
+		}
+		if len(poolList) > 0 {
+			return &poolList[0], nil
+		}
+	}
+	return s.checkIfPoolExists(name)
+}
+
+// tagLoadBalancer adds the tags the load balancer lacks.
+func (s *Service) tagLoadBalancer(openStackCluster *infrav1.OpenStackCluster, lb *loadbalancers.LoadBalancer, tags []string) (*loadbalancers.LoadBalancer, error) {
+	merged, missing := mergeTags(lb.Tags, tags)
+	if !missing {
+		return lb, nil
+	}
+
+	s.scope.Logger.Info("Tagging load balancer", "name", lb.Name, "id", lb.ID)
+	updated, err := s.loadbalancerClient.UpdateLoadBalancer(s.scope.Context(), lb.ID, loadbalancers.UpdateOpts{Tags: &merged})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedTagLoadBalancer", "Failed to tag load balancer %s with id %s: %v", lb.Name, lb.ID, err)
+		return nil, err
+	}
+	record.Eventf(openStackCluster, "SuccessfulTagLoadBalancer", "Tagged load balancer %s with id %s", lb.Name, lb.ID)
+	return updated, nil
+}
+
+// tagListener adds the tags the listener lacks.
+func (s *Service) tagListener(openStackCluster *infrav1.OpenStackCluster, listener *listeners.Listener, lbID string, tags []string) (*listeners.Listener, error) {
+	merged, missing := mergeTags(listener.Tags, tags)
+	if !missing {
+		return listener, nil
+	}
+
+	s.scope.Logger.Info("Tagging load balancer listener", "name", listener.Name, "id", listener.ID)
+	updated, err := s.loadbalancerClient.UpdateListener(s.scope.Context(), listener.ID, listeners.UpdateOpts{Tags: &merged})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedTagListener", "Failed to tag listener %s with id %s: %v", listener.Name, listener.ID, err)
+		return nil, err
+	}
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		return nil, fmt.Errorf("load balancer %s is not active after tagging listener %s: %v", lbID, listener.ID, err)
+	}
+	record.Eventf(openStackCluster, "SuccessfulTagListener", "Tagged listener %s with id %s", listener.Name, listener.ID)
+	return updated, nil
+}
+
+// tagPool adds the tags the pool lacks.
+func (s *Service) tagPool(openStackCluster *infrav1.OpenStackCluster, pool *pools.Pool, lbID string, tags []string) (*pools.Pool, error) {
+	merged, missing := mergeTags(pool.Tags, tags)
+	if !missing {
+		return pool, nil
+	}
+
+	s.scope.Logger.Info("Tagging load balancer pool", "name", pool.Name, "id", pool.ID)
+	updated, err := s.loadbalancerClient.UpdatePool(s.scope.Context(), pool.ID, pools.UpdateOpts{Tags: &merged})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedTagPool", "Failed to tag pool %s with id %s: %v", pool.Name, pool.ID, err)
+		return nil, err
+	}
+	if err := s.waitForLoadBalancerActive(lbID); err != nil {
+		return nil, fmt.Errorf("load balancer %s is not active after tagging pool %s: %v", lbID, pool.ID, err)
+	}
+	record.Eventf(openStackCluster, "SuccessfulTagPool", "Tagged pool %s with id %s", pool.Name, pool.ID)
+	return updated, nil
+}
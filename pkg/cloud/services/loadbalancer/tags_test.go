@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+func Test_tagListOpts(t *testing.T) {
+	g := NewWithT(t)
+
+	query, err := listenerListOpts{ListOpts: listeners.ListOpts{LoadbalancerID: "lb-id"}, Tags: []string{"a", "b"}}.ToListenerListQuery()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(query).To(Equal("?loadbalancer_id=lb-id&tags=a%2Cb"))
+
+	query, err = poolListOpts{Tags: []string{"a"}}.ToPoolListQuery()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(query).To(Equal("?tags=a"))
+
+	query, err = poolListOpts{ListOpts: pools.ListOpts{Name: "pool"}}.ToPoolListQuery()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(query).To(Equal("?name=pool"))
+}
+
+func Test_mergeTags(t *testing.T) {
+	g := NewWithT(t)
+
+	merged, missing := mergeTags([]string{"a", "b"}, []string{"b", "c"})
+	g.Expect(missing).To(BeTrue())
+	g.Expect(merged).To(Equal([]string{"a", "b", "c"}))
+
+	merged, missing = mergeTags([]string{"a", "b"}, []string{"b"})
+	g.Expect(missing).To(BeFalse())
+	g.Expect(merged).To(Equal([]string{"a", "b"}))
+}
+
+func Test_getOrCreateLoadBalancerTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		lbName = "k8s-clusterapi-cluster-AAAAA-kubeapi"
+		lbID   = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		subnet = "aaaaaaaa-bbbb-cccc-dddd-222222222222"
+	)
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			Tags: []string{"team-a"},
+		},
+	}
+	objectTag := "k8s-clusterapi-cluster-AAAAA-kubeapi"
+	tags := []string{"team-a", "k8s-clusterapi-cluster-AAAAA", objectTag}
+	taggedLB := loadbalancers.LoadBalancer{ID: lbID, Name: lbName, Tags: tags}
+
+	tests := []struct {
+		name      string
+		objectTag string
+		expect    func(m *mock.MockLbClientMockRecorder)
+	}{
+		{
+			name:      "load balancer is found by tag",
+			objectTag: objectTag,
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Tags: []string{objectTag}}).Return([]loadbalancers.LoadBalancer{taggedLB}, nil)
+			},
+		},
+		{
+			name:      "untagged load balancer is adopted by name",
+			objectTag: objectTag,
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Tags: []string{objectTag}}).Return([]loadbalancers.LoadBalancer{}, nil)
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{{ID: lbID, Name: lbName}}, nil)
+				m.UpdateLoadBalancer(gomock.Any(), lbID, loadbalancers.UpdateOpts{Tags: &tags}).Return(&taggedLB, nil)
+			},
+		},
+		{
+			name:      "load balancer is created with tags",
+			objectTag: objectTag,
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Tags: []string{objectTag}}).Return([]loadbalancers.LoadBalancer{}, nil)
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{}, nil)
+				m.CreateLoadBalancer(gomock.Any(), loadbalancers.CreateOpts{
+					Name:        lbName,
+					VipSubnetID: subnet,
+					Description: "Created by cluster-api-provider-openstack cluster AAAAA",
+					Provider:    "amphora",
+					Tags:        tags,
+				}).Return(&taggedLB, nil)
+			},
+		},
+		{
+			name: "load balancer is not tagged without tag support",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListLoadBalancers(gomock.Any(), loadbalancers.ListOpts{Name: lbName}).Return([]loadbalancers.LoadBalancer{{ID: lbID, Name: lbName}}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			s := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+
+			lb, err := s.getOrCreateLoadBalancer(openStackCluster, lbName, subnet, "AAAAA", "", "amphora", "", tt.objectTag)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lb.ID).To(Equal(lbID))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/secrets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// getTLSContainerRef returns the URL of the Barbican certificate container or secret which the
+// API server listener presents, after checking that it exists.
+func (s *Service) getTLSContainerRef(tls *infrav1.LoadBalancerTLS) (string, error) {
+	keyManagerClient, err := s.getKeyManagerClient()
+	if err != nil {
+		return "", err
+	}
+
+	if tls.CertificateRef != "" {
+		id := path.Base(strings.TrimSuffix(tls.CertificateRef, "/"))
+		if strings.Contains(tls.CertificateRef, "/containers/") {
+			_, err = keyManagerClient.GetContainer(s.scope.Context(), id)
+		} else {
+			_, err = keyManagerClient.GetSecret(s.scope.Context(), id)
+		}
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return "", fmt.Errorf("certificate %s does not exist", tls.CertificateRef)
+			}
+			return "", fmt.Errorf("error getting certificate %s: %v", tls.CertificateRef, err)
+		}
+		return tls.CertificateRef, nil
+	}
+
+	containerList, err := keyManagerClient.ListContainers(s.scope.Context(), containers.ListOpts{Name: tls.CertificateName})
+	if err != nil {
+		return "", fmt.Errorf("error listing certificate containers: %v", err)
+	}
+	var containerRefs []string
+	for _, container := range containerList {
+		if container.Type == string(containers.CertificateContainer) {
+			containerRefs = append(containerRefs, container.ContainerRef)
+		}
+	}
+	switch len(containerRefs) {
+	case 0:
+	case 1:
+		return containerRefs[0], nil
+	default:
+		return "", fmt.Errorf("found %d certificate containers with name %q, expected one", len(containerRefs), tls.CertificateName)
+	}
+
+	secretList, err := keyManagerClient.ListSecrets(s.scope.Context(), secrets.ListOpts{Name: tls.CertificateName})
+	if err != nil {
+		return "", fmt.Errorf("error listing secrets: %v", err)
+	}
+	switch len(secretList) {
+	case 0:
+		return "", fmt.Errorf("no certificate container or secret with name %q found", tls.CertificateName)
+	case 1:
+		return secretList[0].SecretRef, nil
+	default:
+		return "", fmt.Errorf("found %d secrets with name %q, expected one", len(secretList), tls.CertificateName)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/keymanager/v1/secrets"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+const (
+	containerRef = "https://barbican.example.com/v1/containers/aaaaaaaa-bbbb-cccc-dddd-777777777777"
+	secretRef    = "https://barbican.example.com/v1/secrets/aaaaaaaa-bbbb-cccc-dddd-888888888888"
+)
+
+func Test_getTLSContainerRef(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name    string
+		tls     infrav1.LoadBalancerTLS
+		expect  func(m *mock.MockKeyManagerClientMockRecorder)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "container by ref",
+			tls:  infrav1.LoadBalancerTLS{CertificateRef: containerRef},
+			expect: func(m *mock.MockKeyManagerClientMockRecorder) {
+				m.GetContainer(gomock.Any(), "aaaaaaaa-bbbb-cccc-dddd-777777777777").Return(&containers.Container{ContainerRef: containerRef}, nil)
+			},
+			want: containerRef,
+		},
+		{
+			name: "secret by ref does not exist",
+			tls:  infrav1.LoadBalancerTLS{CertificateRef: secretRef},
+			expect: func(m *mock.MockKeyManagerClientMockRecorder) {
+				m.GetSecret(gomock.Any(), "aaaaaaaa-bbbb-cccc-dddd-888888888888").Return(nil, gophercloud.ErrDefault404{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "certificate container by name",
+			tls:  infrav1.LoadBalancerTLS{CertificateName: "apiserver"},
+			expect: func(m *mock.MockKeyManagerClientMockRecorder) {
+				m.ListContainers(gomock.Any(), containers.ListOpts{Name: "apiserver"}).Return([]containers.Container{
+					{Name: "apiserver", Type: "generic", ContainerRef: "https://barbican.example.com/v1/containers/generic"},
+					{Name: "apiserver", Type: "certificate", ContainerRef: containerRef},
+				}, nil)
+			},
+			want: containerRef,
+		},
+		{
+			name: "secret by name",
+			tls:  infrav1.LoadBalancerTLS{CertificateName: "apiserver"},
+			expect: func(m *mock.MockKeyManagerClientMockRecorder) {
+				m.ListContainers(gomock.Any(), containers.ListOpts{Name: "apiserver"}).Return([]containers.Container{}, nil)
+				m.ListSecrets(gomock.Any(), secrets.ListOpts{Name: "apiserver"}).Return([]secrets.Secret{{Name: "apiserver", SecretRef: secretRef}}, nil)
+			},
+			want: secretRef,
+		},
+		{
+			name: "nothing by name",
+			tls:  infrav1.LoadBalancerTLS{CertificateName: "apiserver"},
+			expect: func(m *mock.MockKeyManagerClientMockRecorder) {
+				m.ListContainers(gomock.Any(), containers.ListOpts{Name: "apiserver"}).Return([]containers.Container{}, nil)
+				m.ListSecrets(gomock.Any(), secrets.ListOpts{Name: "apiserver"}).Return([]secrets.Secret{}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockKeyManagerClient := mock.NewMockKeyManagerClient(mockCtrl)
+			tt.expect(mockKeyManagerClient.EXPECT())
+			s := NewLoadBalancerTestService("", nil, nil, logr.Discard())
+			s._keyManagerClient = mockKeyManagerClient
+
+			got, err := s.getTLSContainerRef(&tt.tls)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(tt.want))
+			}
+		})
+	}
+}
+
+func Test_getOrCreateListenerTLS(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		listenerName = "k8s-clusterapi-cluster-AAAAA-kubeapi-6443"
+		listenerID   = "aaaaaaaa-bbbb-cccc-dddd-444444444444"
+		lbID         = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+	)
+	activeLB := &loadbalancers.LoadBalancer{ID: lbID, ProvisioningStatus: "ACTIVE"}
+	tlsListener := listeners.Listener{
+		ID:                     listenerID,
+		Name:                   listenerName,
+		Protocol:               "TERMINATED_HTTPS",
+		DefaultTlsContainerRef: containerRef,
+		ProvisioningStatus:     "ACTIVE",
+	}
+
+	tests := []struct {
+		name   string
+		expect func(m *mock.MockLbClientMockRecorder)
+	}{
+		{
+			name: "create TLS terminating listener",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{}, nil)
+				m.CreateListener(gomock.Any(), listeners.CreateOpts{
+					Name:                   listenerName,
+					Protocol:               "TERMINATED_HTTPS",
+					ProtocolPort:           6443,
+					LoadbalancerID:         lbID,
+					DefaultTlsContainerRef: containerRef,
+				}).Return(&tlsListener, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+				m.GetListener(gomock.Any(), listenerID).Return(&tlsListener, nil)
+			},
+		},
+		{
+			name: "up to date listener is not changed",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{tlsListener}, nil)
+			},
+		},
+		{
+			name: "rotated certificate is updated",
+			expect: func(m *mock.MockLbClientMockRecorder) {
+				oldListener := tlsListener
+				oldListener.DefaultTlsContainerRef = "https://barbican.example.com/v1/containers/old"
+				m.ListListeners(gomock.Any(), listeners.ListOpts{Name: listenerName}).Return([]listeners.Listener{oldListener}, nil)
+				ref := containerRef
+				m.UpdateListener(gomock.Any(), listenerID, listeners.UpdateOpts{DefaultTlsContainerRef: &ref}).Return(&tlsListener, nil)
+				m.GetLoadBalancer(gomock.Any(), lbID).Return(activeLB, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockLbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(mockLbClient.EXPECT())
+			s := NewLoadBalancerTestService("", mockLbClient, nil, logr.Discard())
+
+			listener, err := s.getOrCreateListener(&infrav1.OpenStackCluster{}, "cluster", listenerName, lbID, 6443, containerRef, "", true)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(listener.DefaultTlsContainerRef).To(Equal(containerRef))
+		})
+	}
+}
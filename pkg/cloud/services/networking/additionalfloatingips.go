@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ReconcileAdditionalFloatingIPs allocates the additional floating IPs of the cluster which do not
+// exist yet and deletes the floating IPs which were removed from the spec of the cluster. The
+// floating IPs are found by a tag with the name of the cluster and of the floating IP, so that they
+// are not allocated again if the status of the cluster is lost.
+func (s *Service) ReconcileAdditionalFloatingIPs(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	if len(openStackCluster.Spec.AdditionalFloatingIPs) > 0 && (openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "") {
+		return fmt.Errorf("additional floating IPs require an external network")
+	}
+
+	removed := make(map[string]bool, len(openStackCluster.Status.AdditionalFloatingIPs))
+	for _, status := range openStackCluster.Status.AdditionalFloatingIPs {
+		removed[status.Name] = true
+	}
+
+	var statuses []infrav1.AdditionalFloatingIPStatus
+	for _, additionalFloatingIP := range openStackCluster.Spec.AdditionalFloatingIPs {
+		delete(removed, additionalFloatingIP.Name)
+		fp, err := s.getOrCreateAdditionalFloatingIP(openStackCluster, clusterName, additionalFloatingIP)
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, infrav1.AdditionalFloatingIPStatus{
+			Name:       additionalFloatingIP.Name,
+			ID:         fp.ID,
+			FloatingIP: fp.FloatingIP,
+		})
+	}
+
+	for _, status := range openStackCluster.Status.AdditionalFloatingIPs {
+		if !removed[status.Name] {
+			continue
+		}
+		if err := s.deleteAdditionalFloatingIP(openStackCluster, clusterName, status.Name); err != nil {
+			return err
+		}
+	}
+
+	openStackCluster.Status.AdditionalFloatingIPs = statuses
+	return nil
+}
+
+// DeleteAdditionalFloatingIPs deletes the additional floating IPs of the cluster.
+func (s *Service) DeleteAdditionalFloatingIPs(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	// The status also has the floating IPs which were removed from the spec before they were deleted
+	var names []string
+	seen := make(map[string]bool)
+	for _, additionalFloatingIP := range openStackCluster.Spec.AdditionalFloatingIPs {
+		names = append(names, additionalFloatingIP.Name)
+		seen[additionalFloatingIP.Name] = true
+	}
+	for _, status := range openStackCluster.Status.AdditionalFloatingIPs {
+		if !seen[status.Name] {
+			names = append(names, status.Name)
+		}
+	}
+
+	for _, name := range names {
+		if err := s.deleteAdditionalFloatingIP(openStackCluster, clusterName, name); err != nil {
+			return err
+		}
+	}
+	openStackCluster.Status.AdditionalFloatingIPs = nil
+	return nil
+}
+
+func (s *Service) getOrCreateAdditionalFloatingIP(openStackCluster *infrav1.OpenStackCluster, clusterName string, additionalFloatingIP infrav1.AdditionalFloatingIP) (*floatingips.FloatingIP, error) {
+	tag := getAdditionalFloatingIPTag(clusterName, additionalFloatingIP.Name)
+	fpList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{Tags: tag})
+	if err != nil {
+		return nil, err
+	}
+	switch len(fpList) {
+	case 0:
+	case 1:
+		return &fpList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d floating IPs with the tag %s, which should not happen", len(fpList), tag)
+	}
+
+	description, err := names.Render(getResourceNaming(openStackCluster).FloatingIPDescription, names.GetDescription(clusterName), names.NewTemplateData(openStackCluster.Namespace, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	if additionalFloatingIP.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, additionalFloatingIP.Description)
+	}
+
+	fp, err := s.client.CreateFloatingIP(s.scope.Context(), floatingips.CreateOpts{
+		FloatingNetworkID: openStackCluster.Status.ExternalNetwork.ID,
+		FloatingIP:        additionalFloatingIP.FloatingIP,
+		Description:       description,
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedCreateFloatingIP", "Failed to create additional floating IP %s: %v", additionalFloatingIP.Name, err)
+		return nil, err
+	}
+
+	// The floating IP is tagged immediately, as it is found by its tag in the next reconcile
+	mc := metrics.NewMetricPrometheusContext("floating_ip", "update")
+	_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "floatingips", fp.ID, attributestags.ReplaceAllOpts{
+		Tags: append(append([]string{}, openStackCluster.Spec.Tags...), tag),
+	})
+	if mc.ObserveRequest(err) != nil {
+		if deleteErr := s.client.DeleteFloatingIP(s.scope.Context(), fp.ID); deleteErr != nil && !capoerrors.IsNotFound(deleteErr) {
+			return nil, fmt.Errorf("failed to tag floating IP %s: %v, and to delete it: %v", fp.FloatingIP, err, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to tag floating IP %s: %w", fp.FloatingIP, err)
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulCreateFloatingIP", "Created additional floating IP %s %s with id %s", additionalFloatingIP.Name, fp.FloatingIP, fp.ID)
+	return fp, nil
+}
+
+func (s *Service) deleteAdditionalFloatingIP(openStackCluster *infrav1.OpenStackCluster, clusterName, name string) error {
+	fpList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{Tags: getAdditionalFloatingIPTag(clusterName, name)})
+	if err != nil {
+		return err
+	}
+	for _, fp := range fpList {
+		if err := s.client.DeleteFloatingIP(s.scope.Context(), fp.ID); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(openStackCluster, "FailedDeleteFloatingIP", "Failed to delete additional floating IP %s %s: %v", name, fp.FloatingIP, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulDeleteFloatingIP", "Deleted additional floating IP %s %s", name, fp.FloatingIP)
+	}
+	return nil
+}
+
+// getAdditionalFloatingIPTag returns the tag by which an additional floating IP of the cluster is found.
+func getAdditionalFloatingIPTag(clusterName, name string) string {
+	return fmt.Sprintf("%s-cluster-%s-fip-%s", networkPrefix, clusterName, name)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ReconcileAdditionalFloatingIPs(t *testing.T) {
+	const (
+		externalNetworkID = "7d1f8e4a-5b2c-4c3d-9e8f-0a1b2c3d4e5f"
+		ingressTag        = "k8s-clusterapi-cluster-test-cluster-fip-ingress"
+		vpnTag            = "k8s-clusterapi-cluster-test-cluster-fip-vpn"
+	)
+	ingress := floatingips.FloatingIP{ID: "ingress-id", FloatingIP: "192.0.2.10"}
+	vpn := floatingips.FloatingIP{ID: "vpn-id", FloatingIP: "192.0.2.20"}
+
+	tests := []struct {
+		name            string
+		spec            []infrav1.AdditionalFloatingIP
+		status          []infrav1.AdditionalFloatingIPStatus
+		externalNetwork *infrav1.Network
+		expect          func(m *mock.MockNetworkClientMockRecorder)
+		want            []infrav1.AdditionalFloatingIPStatus
+		wantErr         bool
+	}{
+		{
+			name:            "floating IP is allocated and tagged",
+			spec:            []infrav1.AdditionalFloatingIP{{Name: "ingress", Description: "Ingress controller"}},
+			externalNetwork: &infrav1.Network{ID: externalNetworkID},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: ingressTag}).Return(nil, nil)
+				m.CreateFloatingIP(gomock.Any(), floatingips.CreateOpts{
+					FloatingNetworkID: externalNetworkID,
+					Description:       "Created by cluster-api-provider-openstack cluster test-cluster: Ingress controller",
+				}).Return(&ingress, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "floatingips", ingress.ID, attributestags.ReplaceAllOpts{Tags: []string{"cluster-tag", ingressTag}}).Return([]string{"cluster-tag", ingressTag}, nil)
+			},
+			want: []infrav1.AdditionalFloatingIPStatus{{Name: "ingress", ID: ingress.ID, FloatingIP: ingress.FloatingIP}},
+		},
+		{
+			name:            "existing floating IP is found by its tag",
+			spec:            []infrav1.AdditionalFloatingIP{{Name: "ingress"}},
+			externalNetwork: &infrav1.Network{ID: externalNetworkID},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: ingressTag}).Return([]floatingips.FloatingIP{ingress}, nil)
+			},
+			want: []infrav1.AdditionalFloatingIPStatus{{Name: "ingress", ID: ingress.ID, FloatingIP: ingress.FloatingIP}},
+		},
+		{
+			name:            "floating IP removed from the spec is deleted",
+			spec:            []infrav1.AdditionalFloatingIP{{Name: "ingress"}},
+			status:          []infrav1.AdditionalFloatingIPStatus{{Name: "ingress", ID: ingress.ID, FloatingIP: ingress.FloatingIP}, {Name: "vpn", ID: vpn.ID, FloatingIP: vpn.FloatingIP}},
+			externalNetwork: &infrav1.Network{ID: externalNetworkID},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: ingressTag}).Return([]floatingips.FloatingIP{ingress}, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: vpnTag}).Return([]floatingips.FloatingIP{vpn}, nil)
+				m.DeleteFloatingIP(gomock.Any(), vpn.ID).Return(nil)
+			},
+			want: []infrav1.AdditionalFloatingIPStatus{{Name: "ingress", ID: ingress.ID, FloatingIP: ingress.FloatingIP}},
+		},
+		{
+			name:            "floating IPs require an external network",
+			spec:            []infrav1.AdditionalFloatingIP{{Name: "ingress"}},
+			externalNetwork: &infrav1.Network{},
+			expect:          func(m *mock.MockNetworkClientMockRecorder) {},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					Tags:                  []string{"cluster-tag"},
+					AdditionalFloatingIPs: tt.spec,
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					ExternalNetwork:       tt.externalNetwork,
+					AdditionalFloatingIPs: tt.status,
+				},
+			}
+			err := s.ReconcileAdditionalFloatingIPs(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.AdditionalFloatingIPs).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_DeleteAdditionalFloatingIPs(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: "k8s-clusterapi-cluster-test-cluster-fip-ingress"}).Return([]floatingips.FloatingIP{{ID: "ingress-id"}}, nil)
+	mockClient.EXPECT().DeleteFloatingIP(gomock.Any(), "ingress-id").Return(nil)
+	mockClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{Tags: "k8s-clusterapi-cluster-test-cluster-fip-vpn"}).Return(nil, nil)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard()},
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			AdditionalFloatingIPs: []infrav1.AdditionalFloatingIP{{Name: "ingress"}},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			AdditionalFloatingIPs: []infrav1.AdditionalFloatingIPStatus{{Name: "ingress", ID: "ingress-id"}, {Name: "vpn", ID: "vpn-id"}},
+		},
+	}
+	g.Expect(s.DeleteAdditionalFloatingIPs(openStackCluster, "test-cluster")).To(Succeed())
+	g.Expect(openStackCluster.Status.AdditionalFloatingIPs).To(BeNil())
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ReconcileAPIServerVIP creates the port on the cluster network which holds the managed VIP of the API server, and
+// associates the floating IP of the API server with it unless floating IPs are disabled. The port is not attached
+// to any server. The control plane machines answer for its address, which is in the allowed address pairs of their
+// ports.
+func (s *Service) ReconcileAPIServerVIP(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	network := openStackCluster.Status.Network
+	if network == nil || network.ID == "" || network.Subnet == nil || network.Subnet.ID == "" {
+		return fmt.Errorf("the VIP of the API server requires the network and subnet of the cluster")
+	}
+
+	port, err := s.getOrCreateAPIServerVIPPort(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+
+	vip := &infrav1.APIServerVIP{PortID: port.ID}
+	for _, fixedIP := range port.FixedIPs {
+		if fixedIP.SubnetID == network.Subnet.ID {
+			vip.IP = fixedIP.IPAddress
+		}
+	}
+	if vip.IP == "" {
+		return fmt.Errorf("port %s of the VIP of the API server has no address on subnet %s", port.ID, network.Subnet.ID)
+	}
+
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		fp, err := s.GetFloatingIPByPortID(port.ID)
+		if err != nil {
+			return err
+		}
+		if fp == nil {
+			floatingIP := openStackCluster.Spec.APIServerFloatingIP
+			if floatingIP == "" {
+				floatingIP = GetClaimedFloatingIP(openStackCluster, FloatingIPUseAPIServer)
+			}
+			fp, err = s.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIP)
+			if err != nil {
+				return err
+			}
+			if err = s.AssociateFloatingIP(openStackCluster, fp, port.ID); err != nil {
+				return err
+			}
+		}
+		vip.FloatingIP = fp.FloatingIP
+	}
+
+	openStackCluster.Status.Network.APIServerVIP = vip
+	return nil
+}
+
+// DeleteAPIServerVIP deletes the port holding the managed VIP of the API server and the floating IP which was
+// created for it. A floating IP given by the spec or claimed from a floating IP pool is only released by the
+// deletion of the port.
+func (s *Service) DeleteAPIServerVIP(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	if network := openStackCluster.Status.Network; network != nil && network.APIServerVIP != nil {
+		floatingIP := network.APIServerVIP.FloatingIP
+		if floatingIP != "" && floatingIP != openStackCluster.Spec.APIServerFloatingIP && !IsClaimedFloatingIP(openStackCluster, floatingIP) {
+			if err := s.DeleteFloatingIP(openStackCluster, floatingIP); err != nil {
+				return err
+			}
+		}
+	}
+
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Name: apiServerVIPPortName(clusterName)})
+	if err != nil {
+		return err
+	}
+	for _, port := range portList {
+		if err := s.DeletePort(openStackCluster, port.ID); err != nil && !capoerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if openStackCluster.Status.Network != nil {
+		openStackCluster.Status.Network.APIServerVIP = nil
+	}
+	return nil
+}
+
+func (s *Service) getOrCreateAPIServerVIPPort(openStackCluster *infrav1.OpenStackCluster, clusterName string) (*ports.Port, error) {
+	network := openStackCluster.Status.Network
+	portName := apiServerVIPPortName(clusterName)
+
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Name: portName, NetworkID: network.ID})
+	if err != nil {
+		return nil, err
+	}
+	switch len(portList) {
+	case 0:
+	case 1:
+		return &portList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d ports with name %s, which should not happen", len(portList), portName)
+	}
+
+	port, err := s.client.CreatePort(s.scope.Context(), ports.CreateOpts{
+		Name:        portName,
+		NetworkID:   network.ID,
+		Description: names.GetDescription(clusterName),
+		FixedIPs: []ports.IP{
+			{SubnetID: network.Subnet.ID, IPAddress: openStackCluster.Spec.APIServerFixedIP},
+		},
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+		return nil, err
+	}
+
+	if err = s.replaceAllAttributesTags(openStackCluster, portResource, port.ID, openStackCluster.Spec.Tags); err != nil {
+		return nil, err
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulCreatePort", "Created port %s with id %s", port.Name, port.ID)
+	return port, nil
+}
+
+// apiServerVIPPortName returns the name of the port holding the managed VIP of the API server.
+func apiServerVIPPortName(clusterName string) string {
+	return fmt.Sprintf("%s-cluster-%s-apiserver-vip", networkPrefix, clusterName)
+}
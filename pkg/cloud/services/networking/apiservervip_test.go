@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ReconcileAPIServerVIP(t *testing.T) {
+	const (
+		networkID = "b4bd6b4e-5d45-4d8c-9d5e-3f3ac4a0b5c1"
+		subnetID  = "1d2c7c0e-9a0f-4b9c-8c4e-6f0a2e5c9d7b"
+		portName  = "k8s-clusterapi-cluster-test-cluster-apiserver-vip"
+	)
+	vipPort := ports.Port{
+		ID:       "vip-port-id",
+		Name:     portName,
+		FixedIPs: []ports.IP{{SubnetID: subnetID, IPAddress: "10.6.0.10"}},
+	}
+
+	tests := []struct {
+		name    string
+		spec    infrav1.OpenStackClusterSpec
+		network *infrav1.Network
+		expect  func(m *mock.MockNetworkClientMockRecorder)
+		want    *infrav1.APIServerVIP
+		wantErr bool
+	}{
+		{
+			name:    "port is created with the fixed IP and tagged",
+			spec:    infrav1.OpenStackClusterSpec{APIServerFixedIP: "10.6.0.10", DisableAPIServerFloatingIP: true, Tags: []string{"cluster-tag"}},
+			network: &infrav1.Network{ID: networkID, Subnet: &infrav1.Subnet{ID: subnetID}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Name: portName, NetworkID: networkID}).Return(nil, nil)
+				m.CreatePort(gomock.Any(), ports.CreateOpts{
+					Name:        portName,
+					NetworkID:   networkID,
+					Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+					FixedIPs:    []ports.IP{{SubnetID: subnetID, IPAddress: "10.6.0.10"}},
+				}).Return(&vipPort, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", vipPort.ID, attributestags.ReplaceAllOpts{Tags: []string{"cluster-tag"}}).Return([]string{"cluster-tag"}, nil)
+			},
+			want: &infrav1.APIServerVIP{PortID: vipPort.ID, IP: "10.6.0.10"},
+		},
+		{
+			name:    "existing port and floating IP are found",
+			network: &infrav1.Network{ID: networkID, Subnet: &infrav1.Subnet{ID: subnetID}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Name: portName, NetworkID: networkID}).Return([]ports.Port{vipPort}, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{PortID: vipPort.ID}).Return([]floatingips.FloatingIP{{ID: "fip-id", FloatingIP: "192.0.2.10"}}, nil)
+			},
+			want: &infrav1.APIServerVIP{PortID: vipPort.ID, IP: "10.6.0.10", FloatingIP: "192.0.2.10"},
+		},
+		{
+			name:    "VIP requires the subnet of the cluster",
+			network: &infrav1.Network{ID: networkID},
+			expect:  func(m *mock.MockNetworkClientMockRecorder) {},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   tt.spec,
+				Status: infrav1.OpenStackClusterStatus{Network: tt.network},
+			}
+			err := s.ReconcileAPIServerVIP(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.APIServerVIP).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_DeleteAPIServerVIP(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: "192.0.2.10"}).Return([]floatingips.FloatingIP{{ID: "fip-id"}}, nil)
+	mockClient.EXPECT().DeleteFloatingIP(gomock.Any(), "fip-id").Return(nil)
+	mockClient.EXPECT().ListPort(gomock.Any(), ports.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster-apiserver-vip"}).Return([]ports.Port{{ID: "vip-port-id"}}, nil)
+	mockClient.EXPECT().DeletePort(gomock.Any(), "vip-port-id").Return(nil)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard()},
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{ManagedAPIServerVIP: true},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				APIServerVIP: &infrav1.APIServerVIP{PortID: "vip-port-id", IP: "10.6.0.10", FloatingIP: "192.0.2.10"},
+			},
+		},
+	}
+	g.Expect(s.DeleteAPIServerVIP(openStackCluster, "test-cluster")).To(Succeed())
+	g.Expect(openStackCluster.Status.Network.APIServerVIP).To(BeNil())
+}
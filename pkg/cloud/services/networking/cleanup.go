@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// Types of the Neutron resources returned by ListClusterResources, ListInstancePorts, ListOrphanedResources and
+// PlanNetworkComponents.
+const (
+	NetworkResource       = "network"
+	SubnetResource        = "subnet"
+	RouterResource        = "router"
+	PortResource          = "port"
+	TrunkResource         = "trunk"
+	FloatingIPResource    = "floatingip"
+	SecurityGroupResource = "securitygroup"
+)
+
+// ListClusterResources returns the IDs of the Neutron resources created for the cluster which still exist, by
+// resource type. The resources are listed by the description CAPO gives them and the tags of the cluster, so that
+// resources which are no longer referenced by the status of the cluster are found as well. It is used to verify
+// that the deletion of a cluster left nothing behind.
+func (s *Service) ListClusterResources(openStackCluster *infrav1.OpenStackCluster, clusterName string) (map[string][]string, error) {
+	description := names.GetDescription(clusterName)
+	tags := strings.Join(openStackCluster.Spec.Tags, ",")
+	resources := map[string][]string{}
+
+	networkList, err := s.client.ListNetwork(s.scope.Context(), networks.ListOpts{Description: description, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networkList {
+		resources[NetworkResource] = append(resources[NetworkResource], network.ID)
+	}
+
+	routerList, err := s.client.ListRouter(s.scope.Context(), routers.ListOpts{Description: description, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	for _, router := range routerList {
+		resources[RouterResource] = append(resources[RouterResource], router.ID)
+	}
+
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Description: description, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range portList {
+		resources[PortResource] = append(resources[PortResource], port.ID)
+	}
+
+	fipDescription, err := names.Render(getResourceNaming(openStackCluster).FloatingIPDescription, description, names.NewTemplateData(openStackCluster.Namespace, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	fipList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{Description: fipDescription, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	for _, fip := range fipList {
+		resources[FloatingIPResource] = append(resources[FloatingIPResource], fip.ID)
+	}
+
+	// The security groups of an externally managed network are never deleted
+	if !openStackCluster.Spec.ExternallyManagedNetwork {
+		for _, name := range []string{getSecControlPlaneGroupName(clusterName), getSecWorkerGroupName(clusterName), getSecBastionGroupName(clusterName)} {
+			groupList, err := s.client.ListSecGroup(s.scope.Context(), groups.ListOpts{Name: name, Tags: tags})
+			if err != nil {
+				return nil, err
+			}
+			for _, group := range groupList {
+				resources[SecurityGroupResource] = append(resources[SecurityGroupResource], group.ID)
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// ListInstancePorts returns the IDs of the ports created for the instance which still exist. The ports are listed
+// by the description CAPO gives them and the tags of the instance, and are matched by the names which
+// GetOrCreatePort is called with, i.e. the name of the instance followed by the index of the port or the name
+// suffix of one of portOpts.
+func (s *Service) ListInstancePorts(clusterName, instanceName string, tags []string, portOpts []infrav1.PortOpts) ([]string, error) {
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Description: names.GetDescription(clusterName), Tags: strings.Join(tags, ",")})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, port := range portList {
+		if isInstancePortName(port.Name, instanceName, portOpts) {
+			ids = append(ids, port.ID)
+		}
+	}
+	return ids, nil
+}
+
+func isInstancePortName(portName, instanceName string, portOpts []infrav1.PortOpts) bool {
+	suffix := strings.TrimPrefix(portName, instanceName+"-")
+	if suffix == portName {
+		return false
+	}
+	if _, err := strconv.Atoi(suffix); err == nil {
+		return true
+	}
+	for _, opts := range portOpts {
+		if opts.NameSuffix != "" && opts.NameSuffix == suffix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ListClusterResources(t *testing.T) {
+	const description = "Created by cluster-api-provider-openstack cluster test-cluster"
+
+	tests := []struct {
+		name   string
+		spec   infrav1.OpenStackClusterSpec
+		expect func(m *mock.MockNetworkClientMockRecorder)
+		want   map[string][]string
+	}{
+		{
+			name: "remaining resources are listed by description and tags",
+			spec: infrav1.OpenStackClusterSpec{Tags: []string{"tag1", "tag2"}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Description: description, Tags: "tag1,tag2"}).Return([]networks.Network{{ID: "network-id"}}, nil)
+				m.ListRouter(gomock.Any(), routers.ListOpts{Description: description, Tags: "tag1,tag2"}).Return(nil, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{Description: description, Tags: "tag1,tag2"}).Return([]ports.Port{{ID: "port-1"}, {ID: "port-2"}}, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Description: description, Tags: "tag1,tag2"}).Return(nil, nil)
+				m.ListSecGroup(gomock.Any(), groups.ListOpts{Name: "k8s-cluster-test-cluster-secgroup-controlplane", Tags: "tag1,tag2"}).Return(nil, nil)
+				m.ListSecGroup(gomock.Any(), groups.ListOpts{Name: "k8s-cluster-test-cluster-secgroup-worker", Tags: "tag1,tag2"}).Return([]groups.SecGroup{{ID: "secgroup-id"}}, nil)
+				m.ListSecGroup(gomock.Any(), groups.ListOpts{Name: "k8s-cluster-test-cluster-secgroup-bastion", Tags: "tag1,tag2"}).Return(nil, nil)
+			},
+			want: map[string][]string{
+				NetworkResource:       {"network-id"},
+				PortResource:          {"port-1", "port-2"},
+				SecurityGroupResource: {"secgroup-id"},
+			},
+		},
+		{
+			name: "security groups of an externally managed network are not listed",
+			spec: infrav1.OpenStackClusterSpec{ExternallyManagedNetwork: true},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Description: description}).Return(nil, nil)
+				m.ListRouter(gomock.Any(), routers.ListOpts{Description: description}).Return(nil, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{Description: description}).Return(nil, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Description: description}).Return([]floatingips.FloatingIP{{ID: "fip-id"}}, nil)
+			},
+			want: map[string][]string{
+				FloatingIPResource: {"fip-id"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			resources, err := s.ListClusterResources(&infrav1.OpenStackCluster{Spec: tt.spec}, "test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resources).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_ListInstancePorts(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListPort(gomock.Any(), ports.ListOpts{Description: "Created by cluster-api-provider-openstack cluster test-cluster", Tags: "tag1"}).Return([]ports.Port{
+		{ID: "port-0", Name: "machine-1-0"},
+		{ID: "port-storage", Name: "machine-1-storage"},
+		{ID: "port-other", Name: "machine-1-other"},
+		{ID: "port-of-machine-1-0", Name: "machine-1-0-0"},
+		{ID: "port-of-machine-10", Name: "machine-10-0"},
+	}, nil)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard()},
+	}
+
+	ids, err := s.ListInstancePorts("test-cluster", "machine-1", []string{"tag1"}, []infrav1.PortOpts{{}, {NameSuffix: "storage"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ids).To(ConsistOf("port-0", "port-storage"))
+}
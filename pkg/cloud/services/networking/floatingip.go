@@ -22,14 +22,54 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
 )
 
+const (
+	// FloatingIPUseAPIServer is the use of an address claimed from a floating IP pool for the API server.
+	FloatingIPUseAPIServer = "apiserver"
+	// FloatingIPUseBastion is the use of an address claimed from a floating IP pool for the bastion.
+	FloatingIPUseBastion = "bastion"
+)
+
+// GetClaimedFloatingIP returns the address the cluster claimed from its floating IP pool
+// for the given use, or an empty string if it has not claimed one.
+func GetClaimedFloatingIP(openStackCluster *infrav1.OpenStackCluster, use string) string {
+	for _, claim := range openStackCluster.Status.FloatingIPPoolClaims {
+		if claim.Use == use {
+			return claim.Address
+		}
+	}
+	return ""
+}
+
+// GetControlPlaneEndpointAddress returns the IP address of the control plane endpoint of the
+// cluster. It is the host of the endpoint, unless the host is the name of a DNS record, in which
+// case it is the address the record points at.
+func GetControlPlaneEndpointAddress(openStackCluster *infrav1.OpenStackCluster) string {
+	if openStackCluster.Spec.ControlPlaneEndpointDNS != nil {
+		return openStackCluster.Status.APIServerAddress
+	}
+	return openStackCluster.Spec.ControlPlaneEndpoint.Host
+}
+
+// IsClaimedFloatingIP returns whether the address was claimed from the floating IP pool of
+// the cluster. Such addresses must be disassociated instead of deleted, so that they can be
+// returned to the pool.
+func IsClaimedFloatingIP(openStackCluster *infrav1.OpenStackCluster, ip string) bool {
+	for _, claim := range openStackCluster.Status.FloatingIPPoolClaims {
+		if claim.Address == ip {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackCluster *infrav1.OpenStackCluster, clusterName, ip string) (*floatingips.FloatingIP, error) {
 	var fp *floatingips.FloatingIP
 	var err error
@@ -48,9 +88,12 @@ func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackClu
 	}
 
 	fpCreateOpts.FloatingNetworkID = openStackCluster.Status.ExternalNetwork.ID
-	fpCreateOpts.Description = names.GetDescription(clusterName)
+	fpCreateOpts.Description, err = names.Render(getResourceNaming(openStackCluster).FloatingIPDescription, names.GetDescription(clusterName), names.NewTemplateData(openStackCluster.Namespace, clusterName))
+	if err != nil {
+		return nil, err
+	}
 
-	fp, err = s.client.CreateFloatingIP(fpCreateOpts)
+	fp, err = s.client.CreateFloatingIP(s.scope.Context(), fpCreateOpts)
 	if err != nil {
 		record.Warnf(eventObject, "FailedCreateFloatingIP", "Failed to create floating IP %s: %v", ip, err)
 		return nil, err
@@ -58,7 +101,7 @@ func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackClu
 
 	if len(openStackCluster.Spec.Tags) > 0 {
 		mc := metrics.NewMetricPrometheusContext("floating_ip", "update")
-		_, err = s.client.ReplaceAllAttributesTags("floatingips", fp.ID, attributestags.ReplaceAllOpts{
+		_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "floatingips", fp.ID, attributestags.ReplaceAllOpts{
 			Tags: openStackCluster.Spec.Tags,
 		})
 		if mc.ObserveRequest(err) != nil {
@@ -71,7 +114,7 @@ func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackClu
 }
 
 func (s *Service) GetFloatingIP(ip string) (*floatingips.FloatingIP, error) {
-	fpList, err := s.client.ListFloatingIP(floatingips.ListOpts{FloatingIP: ip})
+	fpList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{FloatingIP: ip})
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +125,7 @@ func (s *Service) GetFloatingIP(ip string) (*floatingips.FloatingIP, error) {
 }
 
 func (s *Service) GetFloatingIPByPortID(portID string) (*floatingips.FloatingIP, error) {
-	fpList, err := s.client.ListFloatingIP(floatingips.ListOpts{PortID: portID})
+	fpList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{PortID: portID})
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +145,7 @@ func (s *Service) DeleteFloatingIP(eventObject runtime.Object, ip string) error
 		return nil
 	}
 
-	err = s.client.DeleteFloatingIP(fip.ID)
+	err = s.client.DeleteFloatingIP(s.scope.Context(), fip.ID)
 	if err != nil {
 		record.Warnf(eventObject, "FailedDeleteFloatingIP", "Failed to delete floating IP %s: %v", ip, err)
 		return err
@@ -112,11 +155,10 @@ func (s *Service) DeleteFloatingIP(eventObject runtime.Object, ip string) error
 	return nil
 }
 
-var backoff = wait.Backoff{
-	Steps:    10,
-	Duration: 30 * time.Second,
-	Factor:   1.0,
+var floatingIPPolicy = retry.Policy{
+	Interval: 30 * time.Second,
 	Jitter:   0.1,
+	Retries:  9,
 }
 
 func (s *Service) AssociateFloatingIP(eventObject runtime.Object, fp *floatingips.FloatingIP, portID string) error {
@@ -131,7 +173,9 @@ func (s *Service) AssociateFloatingIP(eventObject runtime.Object, fp *floatingip
 		PortID: &portID,
 	}
 
-	_, err := s.client.UpdateFloatingIP(fp.ID, fpUpdateOpts)
+	err := s.updateFloatingIPWithRevision(fp.ID, fpUpdateOpts, func(current *floatingips.FloatingIP) bool {
+		return current.PortID != portID
+	})
 	if err != nil {
 		record.Warnf(eventObject, "FailedAssociateFloatingIP", "Failed to associate floating IP %s with port %s: %v", fp.FloatingIP, portID, err)
 		return err
@@ -162,7 +206,9 @@ func (s *Service) DisassociateFloatingIP(eventObject runtime.Object, ip string)
 		PortID: nil,
 	}
 
-	_, err = s.client.UpdateFloatingIP(fip.ID, fpUpdateOpts)
+	err = s.updateFloatingIPWithRevision(fip.ID, fpUpdateOpts, func(current *floatingips.FloatingIP) bool {
+		return current.PortID != ""
+	})
 	if err != nil {
 		record.Warnf(eventObject, "FailedDisassociateFloatingIP", "Failed to disassociate floating IP %s: %v", fip.FloatingIP, err)
 		return err
@@ -177,10 +223,26 @@ func (s *Service) DisassociateFloatingIP(eventObject runtime.Object, ip string)
 	return nil
 }
 
+// updateFloatingIPWithRevision updates a floating IP based on its current revision if needsUpdate
+// returns true for it, so that a concurrent association or disassociation is not overwritten.
+func (s *Service) updateFloatingIPWithRevision(id string, opts floatingips.UpdateOptsBuilder, needsUpdate func(*floatingips.FloatingIP) bool) error {
+	return s.retryOnRevisionConflict(fipResource, id, func() error {
+		current, revision, err := s.client.GetFloatingIPWithRevision(s.scope.Context(), id)
+		if err != nil {
+			return err
+		}
+		if !needsUpdate(current) {
+			return nil
+		}
+		_, err = s.client.UpdateFloatingIPWithRevision(s.scope.Context(), id, revision, opts)
+		return err
+	})
+}
+
 func (s *Service) waitForFloatingIP(id, target string) error {
 	s.scope.Logger.Info("Waiting for floating IP", "id", id, "targetStatus", target)
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		fip, err := s.client.GetFloatingIP(id)
+	return retry.Wait(s.scope.Context(), retry.Network, floatingIPPolicy, func() (bool, error) {
+		fip, err := s.client.GetFloatingIP(s.scope.Context(), id)
 		if err != nil {
 			return false, err
 		}
@@ -17,14 +17,18 @@ limitations under the License.
 package networking
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	. "github.com/onsi/gomega"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 func Test_GetOrCreateFloatingIP(t *testing.T) {
@@ -42,10 +46,10 @@ func Test_GetOrCreateFloatingIP(t *testing.T) {
 			ip:   "192.168.111.0",
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListFloatingIP(floatingips.ListOpts{FloatingIP: "192.168.111.0"}).
+					ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: "192.168.111.0"}).
 					Return([]floatingips.FloatingIP{}, nil)
 				m.
-					CreateFloatingIP(floatingips.CreateOpts{
+					CreateFloatingIP(gomock.Any(), floatingips.CreateOpts{
 						FloatingIP:  "192.168.111.0",
 						Description: "Created by cluster-api-provider-openstack cluster test-cluster",
 					}).
@@ -58,7 +62,7 @@ func Test_GetOrCreateFloatingIP(t *testing.T) {
 			ip:   "192.168.111.0",
 			expect: func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListFloatingIP(floatingips.ListOpts{FloatingIP: "192.168.111.0"}).
+					ListFloatingIP(gomock.Any(), floatingips.ListOpts{FloatingIP: "192.168.111.0"}).
 					Return([]floatingips.FloatingIP{{FloatingIP: "192.168.111.0"}}, nil)
 			},
 			want: &floatingips.FloatingIP{FloatingIP: "192.168.111.0"},
@@ -76,6 +80,7 @@ func Test_GetOrCreateFloatingIP(t *testing.T) {
 			tt.expect(mockClient.EXPECT())
 			s := Service{
 				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
 			}
 			eventObject := infrav1.OpenStackMachine{}
 			got, err := s.GetOrCreateFloatingIP(&eventObject, openStackCluster, "test-cluster", tt.ip)
@@ -84,3 +89,74 @@ func Test_GetOrCreateFloatingIP(t *testing.T) {
 		})
 	}
 }
+
+func Test_AssociateFloatingIP(t *testing.T) {
+	const (
+		fipID  = "9a3d4f3e-1c6b-4d5e-8f7a-0b1c2d3e4f50"
+		portID = "50214c48-c09e-4a54-914f-97b40fd22802"
+	)
+	fip := &floatingips.FloatingIP{ID: fipID, FloatingIP: "192.168.111.0"}
+	associatedPortID := portID
+	preconditionFailed := gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusPreconditionFailed}
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "associates the floating IP based on its revision",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetFloatingIPWithRevision(gomock.Any(), fipID).Return(fip, 3, nil)
+				m.UpdateFloatingIPWithRevision(gomock.Any(), fipID, 3, &floatingips.UpdateOpts{PortID: &associatedPortID}).Return(fip, nil)
+				m.GetFloatingIP(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, Status: "ACTIVE"}, nil)
+			},
+		},
+		{
+			name: "retries when the floating IP was modified concurrently",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				gomock.InOrder(
+					m.GetFloatingIPWithRevision(gomock.Any(), fipID).Return(fip, 3, nil),
+					m.UpdateFloatingIPWithRevision(gomock.Any(), fipID, 3, gomock.Any()).Return(nil, preconditionFailed),
+					m.GetFloatingIPWithRevision(gomock.Any(), fipID).Return(fip, 4, nil),
+					m.UpdateFloatingIPWithRevision(gomock.Any(), fipID, 4, gomock.Any()).Return(fip, nil),
+				)
+				m.GetFloatingIP(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, Status: "ACTIVE"}, nil)
+			},
+		},
+		{
+			name: "does not update the floating IP if it was associated concurrently",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetFloatingIPWithRevision(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, PortID: portID}, 4, nil)
+				m.GetFloatingIP(gomock.Any(), fipID).Return(&floatingips.FloatingIP{ID: fipID, Status: "ACTIVE"}, nil)
+			},
+		},
+		{
+			name: "fails when the floating IP is modified concurrently on every attempt",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetFloatingIPWithRevision(gomock.Any(), fipID).Return(fip, 3, nil).Times(revisionConflictRetries)
+				m.UpdateFloatingIPWithRevision(gomock.Any(), fipID, 3, gomock.Any()).Return(nil, preconditionFailed).Times(revisionConflictRetries)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			err := s.AssociateFloatingIP(&infrav1.OpenStackMachine{}, fip, portID)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
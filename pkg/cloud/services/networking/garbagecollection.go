@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ListOrphanedResources returns the ports, trunks and floating IPs created for the cluster which are neither used
+// nor referenced by any object, e.g. because the controller crashed before it recorded them. Like in
+// ListClusterResources, they are listed by the description CAPO gives them and the tags of the cluster. A port is
+// orphaned if it is not attached to a device and does not belong to one of instanceNames, a trunk if its parent
+// port is orphaned, and a floating IP if it is not associated with a port and its address is not one of addresses.
+// Trunks are returned first, as they must be deleted before their parent port.
+func (s *Service) ListOrphanedResources(openStackCluster *infrav1.OpenStackCluster, clusterName string, instanceNames []string, addresses []string) ([]infrav1.OrphanedResource, error) {
+	description := names.GetDescription(clusterName)
+	tags := strings.Join(openStackCluster.Spec.Tags, ",")
+
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Description: description, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	var orphanedPorts []infrav1.OrphanedResource
+	isOrphanedPort := make(map[string]bool)
+	for _, port := range portList {
+		if port.DeviceID != "" || port.DeviceOwner != "" || port.Name == apiServerVIPPortName(clusterName) || names.BelongsToInstance(port.Name, instanceNames) {
+			continue
+		}
+		isOrphanedPort[port.ID] = true
+		orphanedPorts = append(orphanedPorts, infrav1.OrphanedResource{Type: PortResource, ID: port.ID, Name: port.Name})
+	}
+
+	var orphans []infrav1.OrphanedResource
+	if len(orphanedPorts) > 0 {
+		trunkSupported, err := s.GetTrunkSupport()
+		if err != nil {
+			return nil, err
+		}
+		if trunkSupported {
+			trunkList, err := s.client.ListTrunk(s.scope.Context(), trunks.ListOpts{Description: description, Tags: tags})
+			if err != nil {
+				return nil, err
+			}
+			for _, trunk := range trunkList {
+				if isOrphanedPort[trunk.PortID] {
+					orphans = append(orphans, infrav1.OrphanedResource{Type: TrunkResource, ID: trunk.ID, Name: trunk.Name})
+				}
+			}
+		}
+	}
+	orphans = append(orphans, orphanedPorts...)
+
+	fipDescription, err := names.Render(getResourceNaming(openStackCluster).FloatingIPDescription, description, names.NewTemplateData(openStackCluster.Namespace, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	fipList, err := s.client.ListFloatingIP(s.scope.Context(), floatingips.ListOpts{Description: fipDescription, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	isReferenced := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		isReferenced[address] = true
+	}
+	for _, fip := range fipList {
+		if fip.PortID != "" || isReferenced[fip.FloatingIP] {
+			continue
+		}
+		orphans = append(orphans, infrav1.OrphanedResource{Type: FloatingIPResource, ID: fip.ID, Name: fip.FloatingIP})
+	}
+
+	return orphans, nil
+}
+
+// DeleteOrphanedResource deletes a port, trunk or floating IP returned by ListOrphanedResources. The ports CAPO
+// created for the subports of a trunk are deleted with it.
+func (s *Service) DeleteOrphanedResource(eventObject runtime.Object, orphan *infrav1.OrphanedResource) error {
+	switch orphan.Type {
+	case PortResource:
+		return s.DeletePort(eventObject, orphan.ID)
+	case TrunkResource:
+		trunkList, err := s.client.ListTrunk(s.scope.Context(), trunks.ListOpts{ID: orphan.ID})
+		if err != nil {
+			return err
+		}
+		if len(trunkList) == 0 {
+			return nil
+		}
+		// The trunk is listed, so its subports need not be declared to find their ports
+		return s.DeleteTrunk(eventObject, trunkList[0].PortID, nil)
+	case FloatingIPResource:
+		return s.DeleteFloatingIP(eventObject, orphan.Name)
+	default:
+		return fmt.Errorf("unknown type %q of orphaned resource %s", orphan.Type, orphan.ID)
+	}
+}
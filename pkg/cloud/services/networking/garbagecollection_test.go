@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ListOrphanedResources(t *testing.T) {
+	const description = "Created by cluster-api-provider-openstack cluster test-cluster"
+
+	tests := []struct {
+		name   string
+		expect func(m *mock.MockNetworkClientMockRecorder)
+		want   []infrav1.OrphanedResource
+	}{
+		{
+			name: "unused resources which belong to no instance are orphaned",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Description: description, Tags: "tag1"}).Return([]ports.Port{
+					{ID: "attached-port", Name: "other-0", DeviceID: "server-id", DeviceOwner: "compute:nova"},
+					{ID: "machine-port", Name: "machine-1-0"},
+					{ID: "vip-port", Name: "k8s-clusterapi-cluster-test-cluster-apiserver-vip"},
+					{ID: "orphaned-port", Name: "machine-2-0"},
+				}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{{Extension: common.Extension{Alias: "trunk"}}}, nil)
+				m.ListTrunk(gomock.Any(), trunks.ListOpts{Description: description, Tags: "tag1"}).Return([]trunks.Trunk{
+					{ID: "machine-trunk", Name: "machine-1", PortID: "machine-port"},
+					{ID: "orphaned-trunk", Name: "machine-2", PortID: "orphaned-port"},
+				}, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Description: description, Tags: "tag1"}).Return([]floatingips.FloatingIP{
+					{ID: "associated-fip", FloatingIP: "10.0.0.1", PortID: "attached-port"},
+					{ID: "reserved-fip", FloatingIP: "10.0.0.2"},
+					{ID: "orphaned-fip", FloatingIP: "10.0.0.3"},
+				}, nil)
+			},
+			want: []infrav1.OrphanedResource{
+				{Type: TrunkResource, ID: "orphaned-trunk", Name: "machine-2"},
+				{Type: PortResource, ID: "orphaned-port", Name: "machine-2-0"},
+				{Type: FloatingIPResource, ID: "orphaned-fip", Name: "10.0.0.3"},
+			},
+		},
+		{
+			name: "trunks are not listed without orphaned ports",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Description: description, Tags: "tag1"}).Return([]ports.Port{
+					{ID: "machine-port", Name: "machine-1-0"},
+				}, nil)
+				m.ListFloatingIP(gomock.Any(), floatingips.ListOpts{Description: description, Tags: "tag1"}).Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{Spec: infrav1.OpenStackClusterSpec{Tags: []string{"tag1"}}}
+			orphans, err := s.ListOrphanedResources(openStackCluster, "test-cluster", []string{"machine-1"}, []string{"10.0.0.2"})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(orphans).To(Equal(tt.want))
+		})
+	}
+}
@@ -22,19 +22,29 @@ import (
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/external"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/mtu"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
 type createOpts struct {
-	AdminStateUp        *bool  `json:"admin_state_up,omitempty"`
-	Name                string `json:"name,omitempty"`
-	PortSecurityEnabled *bool  `json:"port_security_enabled,omitempty"`
+	AdminStateUp          *bool    `json:"admin_state_up,omitempty"`
+	Name                  string   `json:"name,omitempty"`
+	PortSecurityEnabled   *bool    `json:"port_security_enabled,omitempty"`
+	MTU                   int      `json:"mtu,omitempty"`
+	AvailabilityZoneHints []string `json:"availability_zone_hints,omitempty"`
+	NetworkType           string   `json:"provider:network_type,omitempty"`
+	PhysicalNetwork       string   `json:"provider:physical_network,omitempty"`
+	SegmentationID        int      `json:"provider:segmentation_id,omitempty"`
 }
 
 func (c createOpts) ToNetworkCreateMap() (map[string]interface{}, error) {
@@ -65,7 +75,7 @@ func (s *Service) ReconcileExternalNetwork(openStackCluster *infrav1.OpenStackCl
 		External:        &iTrue,
 	}
 
-	networkList, err := s.client.ListNetwork(listOpts)
+	networkList, err := s.client.ListNetwork(s.scope.Context(), listOpts)
 	if err != nil {
 		return err
 	}
@@ -89,7 +99,10 @@ func (s *Service) ReconcileExternalNetwork(openStackCluster *infrav1.OpenStackCl
 }
 
 func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
-	networkName := getNetworkName(clusterName)
+	networkName, err := getNetworkName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	s.scope.Logger.Info("Reconciling network", "name", networkName)
 
 	res, err := s.getNetworkByName(networkName)
@@ -99,10 +112,19 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 
 	if res.ID != "" {
 		// Network exists
+		networkMTU, err := s.reconcileNetworkMTU(openStackCluster, &res)
+		if err != nil {
+			return err
+		}
+		tags, err := s.reconcileTags(openStackCluster, "networks", res.ID, res.Tags, openStackCluster.Spec.Tags)
+		if err != nil {
+			return err
+		}
 		openStackCluster.Status.Network = &infrav1.Network{
 			ID:   res.ID,
 			Name: res.Name,
-			Tags: res.Tags,
+			Tags: tags,
+			MTU:  networkMTU,
 		}
 		sInfo := fmt.Sprintf("Reuse Existing Network %s with id %s", res.Name, res.ID)
 		s.scope.Logger.V(6).Info(sInfo)
@@ -122,8 +144,17 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 			Name:         networkName,
 		}
 	}
+	opts.MTU = openStackCluster.Spec.NetworkMTU
+	if openStackCluster.Spec.NetworkAvailabilityZone != "" {
+		opts.AvailabilityZoneHints = []string{openStackCluster.Spec.NetworkAvailabilityZone}
+	}
+	if providerNetwork := openStackCluster.Spec.ProviderNetwork; providerNetwork != nil {
+		opts.NetworkType = string(providerNetwork.NetworkType)
+		opts.PhysicalNetwork = providerNetwork.PhysicalNetwork
+		opts.SegmentationID = providerNetwork.SegmentationID
+	}
 
-	network, err := s.client.CreateNetwork(opts)
+	network, err := s.client.CreateNetwork(s.scope.Context(), opts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateNetwork", "Failed to create network %s: %v", networkName, err)
 		return err
@@ -131,7 +162,7 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 	record.Eventf(openStackCluster, "SuccessfulCreateNetwork", "Created network %s with id %s", networkName, network.ID)
 
 	if len(openStackCluster.Spec.Tags) > 0 {
-		_, err = s.client.ReplaceAllAttributesTags("networks", network.ID, attributestags.ReplaceAllOpts{
+		_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "networks", network.ID, attributestags.ReplaceAllOpts{
 			Tags: openStackCluster.Spec.Tags,
 		})
 		if err != nil {
@@ -143,12 +174,40 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 		ID:   network.ID,
 		Name: network.Name,
 		Tags: openStackCluster.Spec.Tags,
+		MTU:  openStackCluster.Spec.NetworkMTU,
 	}
 	return nil
 }
 
+// reconcileNetworkMTU sets the MTU of an existing cluster network if it was changed in the
+// spec, and returns the MTU which is recorded in the status. Neutron does not return the MTU
+// with the network, so it is only compared with the MTU recorded in the status.
+func (s *Service) reconcileNetworkMTU(openStackCluster *infrav1.OpenStackCluster, network *networks.Network) (int, error) {
+	networkMTU := openStackCluster.Spec.NetworkMTU
+	if networkMTU == 0 {
+		return 0, nil
+	}
+	if openStackCluster.Status.Network != nil && openStackCluster.Status.Network.ID == network.ID && openStackCluster.Status.Network.MTU == networkMTU {
+		return networkMTU, nil
+	}
+
+	_, err := s.client.UpdateNetwork(s.scope.Context(), network.ID, mtu.UpdateOptsExt{
+		UpdateOptsBuilder: networks.UpdateOpts{},
+		MTU:               networkMTU,
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateNetwork", "Failed to set MTU %d of network %s with id %s: %v", networkMTU, network.Name, network.ID, err)
+		return 0, err
+	}
+	record.Eventf(openStackCluster, "SuccessfulUpdateNetwork", "Set MTU %d of network %s with id %s", networkMTU, network.Name, network.ID)
+	return networkMTU, nil
+}
+
 func (s *Service) DeleteNetwork(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
-	networkName := getNetworkName(clusterName)
+	networkName, err := getNetworkName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	network, err := s.getNetworkByName(networkName)
 	if err != nil {
 		return err
@@ -157,13 +216,14 @@ func (s *Service) DeleteNetwork(openStackCluster *infrav1.OpenStackCluster, clus
 		return nil
 	}
 
-	err = s.client.DeleteNetwork(network.ID)
+	err = s.client.DeleteNetwork(s.scope.Context(), network.ID)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedDeleteNetwork", "Failed to delete network %s with id %s: %v", network.Name, network.ID, err)
 		return err
 	}
 
 	record.Eventf(openStackCluster, "SuccessfulDeleteNetwork", "Deleted network %s with id %s", network.Name, network.ID)
+	lookupcache.Invalidate(lookupcache.Network, lookupcache.Subnet)
 	return nil
 }
 
@@ -173,10 +233,13 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 		return nil
 	}
 
-	subnetName := getSubnetName(clusterName)
+	subnetName, err := getSubnetName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
 	s.scope.Logger.Info("Reconciling subnet", "name", subnetName)
 
-	subnetList, err := s.client.ListSubnet(subnets.ListOpts{
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), subnets.ListOpts{
 		NetworkID: openStackCluster.Status.Network.ID,
 		CIDR:      openStackCluster.Spec.NodeCIDR,
 	})
@@ -191,13 +254,23 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 	var subnet *subnets.Subnet
 	if len(subnetList) == 0 {
 		var err error
-		subnet, err = s.createSubnet(openStackCluster, clusterName, subnetName)
+		subnet, err = s.createSubnet(openStackCluster, subnets.CreateOpts{
+			NetworkID:      openStackCluster.Status.Network.ID,
+			Name:           subnetName,
+			IPVersion:      4,
+			CIDR:           openStackCluster.Spec.NodeCIDR,
+			DNSNameservers: openStackCluster.Spec.DNSNameservers,
+			Description:    names.GetDescription(clusterName),
+		}, nil)
 		if err != nil {
 			return err
 		}
 	} else if len(subnetList) == 1 {
 		subnet = &subnetList[0]
 		s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing subnet %s with id %s", subnetName, subnet.ID))
+		if subnet.Tags, err = s.reconcileTags(openStackCluster, "subnets", subnet.ID, subnet.Tags, openStackCluster.Spec.Tags); err != nil {
+			return err
+		}
 	}
 
 	openStackCluster.Status.Network.Subnet = &infrav1.Subnet{
@@ -206,20 +279,217 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 		CIDR: subnet.CIDR,
 		Tags: subnet.Tags,
 	}
+	if err := s.reconcileIPv6Subnet(openStackCluster, clusterName, getIPv6SubnetName(subnetName)); err != nil {
+		return err
+	}
+	return s.reconcileManagedSubnets(openStackCluster, clusterName, subnetName)
+}
+
+// reconcileIPv6Subnet reconciles the IPv6 subnet of a dual-stack cluster network.
+func (s *Service) reconcileIPv6Subnet(openStackCluster *infrav1.OpenStackCluster, clusterName, subnetName string) error {
+	options := openStackCluster.Spec.NodeIPv6Subnet
+	if options == nil {
+		openStackCluster.Status.Network.IPv6Subnet = nil
+		return nil
+	}
+	s.scope.Logger.Info("Reconciling IPv6 subnet", "name", subnetName)
+
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), subnets.ListOpts{
+		NetworkID: openStackCluster.Status.Network.ID,
+		CIDR:      options.CIDR,
+	})
+	if err != nil {
+		return err
+	}
+
+	var subnet *subnets.Subnet
+	switch len(subnetList) {
+	case 0:
+		addressMode := options.AddressMode
+		if addressMode == "" {
+			addressMode = infrav1.IPv6AddressModeSLAAC
+		}
+		raMode := options.RAMode
+		if raMode == "" {
+			raMode = addressMode
+		}
+		subnet, err = s.createSubnet(openStackCluster, subnets.CreateOpts{
+			NetworkID:       openStackCluster.Status.Network.ID,
+			Name:            subnetName,
+			IPVersion:       6,
+			CIDR:            options.CIDR,
+			IPv6AddressMode: string(addressMode),
+			IPv6RAMode:      string(raMode),
+			DNSNameservers:  options.DNSNameservers,
+			Description:     names.GetDescription(clusterName),
+		}, nil)
+		if err != nil {
+			return err
+		}
+	case 1:
+		subnet = &subnetList[0]
+		s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing subnet %s with id %s", subnetName, subnet.ID))
+		if subnet.Tags, err = s.reconcileTags(openStackCluster, "subnets", subnet.ID, subnet.Tags, openStackCluster.Spec.Tags); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("found %d subnets with the name %s, which should not happen", len(subnetList), subnetName)
+	}
+
+	openStackCluster.Status.Network.IPv6Subnet = &infrav1.Subnet{
+		ID:   subnet.ID,
+		Name: subnet.Name,
+		CIDR: subnet.CIDR,
+		Tags: subnet.Tags,
+	}
 	return nil
 }
 
-func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, clusterName string, name string) (*subnets.Subnet, error) {
-	opts := subnets.CreateOpts{
-		NetworkID:      openStackCluster.Status.Network.ID,
-		Name:           name,
-		IPVersion:      4,
-		CIDR:           openStackCluster.Spec.NodeCIDR,
-		DNSNameservers: openStackCluster.Spec.DNSNameservers,
-		Description:    names.GetDescription(clusterName),
+// reconcileManagedSubnets reconciles the additional subnets of the cluster network. Existing subnets are found
+// by name, and only their host routes and service types are updated, as the other settings cannot be changed.
+func (s *Service) reconcileManagedSubnets(openStackCluster *infrav1.OpenStackCluster, clusterName, subnetName string) error {
+	appliedServiceTypes := make(map[string][]string)
+	for _, status := range openStackCluster.Status.Network.ManagedSubnets {
+		appliedServiceTypes[status.Name] = status.ServiceTypes
 	}
 
-	subnet, err := s.client.CreateSubnet(opts)
+	var managedSubnets []infrav1.ManagedSubnetStatus
+	for _, managedSubnet := range openStackCluster.Spec.ManagedSubnets {
+		name := getManagedSubnetName(subnetName, managedSubnet.Name)
+		s.scope.Logger.Info("Reconciling managed subnet", "name", name)
+
+		subnetList, err := s.client.ListSubnet(s.scope.Context(), subnets.ListOpts{
+			NetworkID: openStackCluster.Status.Network.ID,
+			Name:      name,
+		})
+		if err != nil {
+			return err
+		}
+
+		var subnet *subnets.Subnet
+		switch len(subnetList) {
+		case 0:
+			opts := subnets.CreateOpts{
+				NetworkID:      openStackCluster.Status.Network.ID,
+				Name:           name,
+				IPVersion:      4,
+				CIDR:           managedSubnet.CIDR,
+				DNSNameservers: managedSubnet.DNSNameservers,
+				Description:    names.GetDescription(clusterName),
+			}
+			if managedSubnet.DisableDHCP {
+				opts.EnableDHCP = pointer.Bool(false)
+			}
+			if managedSubnet.GatewayIP != "" {
+				opts.GatewayIP = &managedSubnet.GatewayIP
+			}
+			for _, pool := range managedSubnet.AllocationPools {
+				opts.AllocationPools = append(opts.AllocationPools, subnets.AllocationPool{
+					Start: pool.Start,
+					End:   pool.End,
+				})
+			}
+			opts.HostRoutes = getHostRoutes(managedSubnet.HostRoutes)
+			subnet, err = s.createSubnet(openStackCluster, opts, managedSubnet.ServiceTypes)
+			if err != nil {
+				return err
+			}
+		case 1:
+			subnet = &subnetList[0]
+			s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing subnet %s with id %s", name, subnet.ID))
+			if subnet.Tags, err = s.reconcileTags(openStackCluster, "subnets", subnet.ID, subnet.Tags, openStackCluster.Spec.Tags); err != nil {
+				return err
+			}
+			if err := s.updateManagedSubnet(openStackCluster, subnet, &managedSubnet, appliedServiceTypes[managedSubnet.Name]); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("found %d subnets with the name %s, which should not happen", len(subnetList), name)
+		}
+
+		managedSubnets = append(managedSubnets, infrav1.ManagedSubnetStatus{
+			Name: managedSubnet.Name,
+			Role: managedSubnet.Role,
+			Subnet: infrav1.Subnet{
+				ID:   subnet.ID,
+				Name: subnet.Name,
+				CIDR: subnet.CIDR,
+				Tags: subnet.Tags,
+			},
+			DHCPDisabled: !subnet.EnableDHCP,
+			ServiceTypes: managedSubnet.ServiceTypes,
+		})
+	}
+	openStackCluster.Status.Network.ManagedSubnets = managedSubnets
+	return nil
+}
+
+// updateManagedSubnet applies changed host routes and service types of a managed subnet to the existing subnet.
+// Neutron does not return the service types with the subnet, so they are only compared with the service types
+// recorded in the status.
+func (s *Service) updateManagedSubnet(openStackCluster *infrav1.OpenStackCluster, subnet *subnets.Subnet, managedSubnet *infrav1.ManagedSubnet, appliedServiceTypes []string) error {
+	hostRoutes := getHostRoutes(managedSubnet.HostRoutes)
+	hostRoutesChanged := !sets.NewString(hostRouteKeys(subnet.HostRoutes)...).Equal(sets.NewString(hostRouteKeys(hostRoutes)...))
+	serviceTypesChanged := !sets.NewString(appliedServiceTypes...).Equal(sets.NewString(managedSubnet.ServiceTypes...))
+	if !hostRoutesChanged && !serviceTypesChanged {
+		return nil
+	}
+
+	updateOpts := subnets.UpdateOpts{}
+	if hostRoutesChanged {
+		if hostRoutes == nil {
+			hostRoutes = []subnets.HostRoute{}
+		}
+		updateOpts.HostRoutes = &hostRoutes
+	}
+	var opts subnets.UpdateOptsBuilder = updateOpts
+	if serviceTypesChanged {
+		if err := s.checkSubnetServiceTypesSupport(); err != nil {
+			return err
+		}
+		opts = serviceTypesUpdateOpts{UpdateOptsBuilder: updateOpts, ServiceTypes: managedSubnet.ServiceTypes}
+	}
+
+	updated, err := s.client.UpdateSubnet(s.scope.Context(), subnet.ID, opts)
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateSubnet", "Failed to update host routes and service types of subnet %s with id %s: %v", subnet.Name, subnet.ID, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulUpdateSubnet", "Updated host routes and service types of subnet %s with id %s", subnet.Name, subnet.ID)
+	subnet.HostRoutes = updated.HostRoutes
+	return nil
+}
+
+func getHostRoutes(hostRoutes []infrav1.HostRoute) []subnets.HostRoute {
+	var routes []subnets.HostRoute
+	for _, route := range hostRoutes {
+		routes = append(routes, subnets.HostRoute{
+			DestinationCIDR: route.Destination,
+			NextHop:         route.NextHop,
+		})
+	}
+	return routes
+}
+
+// hostRouteKeys returns the host routes as strings, as Neutron does not preserve their order.
+func hostRouteKeys(hostRoutes []subnets.HostRoute) []string {
+	keys := make([]string, 0, len(hostRoutes))
+	for _, route := range hostRoutes {
+		keys = append(keys, route.DestinationCIDR+" via "+route.NextHop)
+	}
+	return keys
+}
+
+func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, opts subnets.CreateOpts, serviceTypes []string) (*subnets.Subnet, error) {
+	name := opts.Name
+	var createOpts subnets.CreateOptsBuilder = opts
+	if len(serviceTypes) > 0 {
+		if err := s.checkSubnetServiceTypesSupport(); err != nil {
+			return nil, err
+		}
+		createOpts = serviceTypesCreateOpts{CreateOptsBuilder: opts, ServiceTypes: serviceTypes}
+	}
+	subnet, err := s.client.CreateSubnet(s.scope.Context(), createOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateSubnet", "Failed to create subnet %s: %v", name, err)
 		return nil, err
@@ -228,7 +498,7 @@ func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, clust
 
 	if len(openStackCluster.Spec.Tags) > 0 {
 		mc := metrics.NewMetricPrometheusContext("subnet", "update")
-		_, err = s.client.ReplaceAllAttributesTags("subnets", subnet.ID, attributestags.ReplaceAllOpts{
+		_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "subnets", subnet.ID, attributestags.ReplaceAllOpts{
 			Tags: openStackCluster.Spec.Tags,
 		})
 		if mc.ObserveRequest(err) != nil {
@@ -244,7 +514,7 @@ func (s *Service) getNetworkByID(networkID string) (networks.Network, error) {
 		ID: networkID,
 	}
 
-	networkList, err := s.client.ListNetwork(opts)
+	networkList, err := s.client.ListNetwork(s.scope.Context(), opts)
 	if err != nil {
 		return networks.Network{}, err
 	}
@@ -263,7 +533,7 @@ func (s *Service) getNetworkByName(networkName string) (networks.Network, error)
 		Name: networkName,
 	}
 
-	networkList, err := s.client.ListNetwork(opts)
+	networkList, err := s.client.ListNetwork(s.scope.Context(), opts)
 	if err != nil {
 		return networks.Network{}, err
 	}
@@ -282,7 +552,7 @@ func (s *Service) GetNetworksByFilter(opts networks.ListOptsBuilder) ([]networks
 	if opts == nil {
 		return nil, fmt.Errorf("no Filters were passed")
 	}
-	networkList, err := s.client.ListNetwork(opts)
+	networkList, err := s.client.ListNetwork(s.scope.Context(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -293,7 +563,19 @@ func (s *Service) GetNetworksByFilter(opts networks.ListOptsBuilder) ([]networks
 }
 
 // GetNetworkIDsByFilter retrieves network ids by querying openstack with filters.
+// The ids are taken from the lookup cache if it is enabled.
 func (s *Service) GetNetworkIDsByFilter(opts networks.ListOptsBuilder) ([]string, error) {
+	var query string
+	var queryErr error
+	if opts != nil {
+		query, queryErr = opts.ToNetworkListQuery()
+		if queryErr == nil {
+			if ids, ok := lookupcache.Get(lookupcache.Network, s.scope.ProjectID, s.scope.RegionName(), query); ok {
+				return ids.([]string), nil
+			}
+		}
+	}
+
 	nets, err := s.GetNetworksByFilter(opts)
 	if err != nil {
 		return nil, err
@@ -302,6 +584,9 @@ func (s *Service) GetNetworkIDsByFilter(opts networks.ListOptsBuilder) ([]string
 	for _, network := range nets {
 		ids = append(ids, network.ID)
 	}
+	if queryErr == nil {
+		lookupcache.Add(lookupcache.Network, s.scope.ProjectID, s.scope.RegionName(), query, ids)
+	}
 	return ids, nil
 }
 
@@ -310,20 +595,113 @@ func (s *Service) GetSubnetsByFilter(opts subnets.ListOptsBuilder) ([]subnets.Su
 	if opts == nil {
 		return []subnets.Subnet{}, fmt.Errorf("no Filters were passed")
 	}
-	subnetList, err := s.client.ListSubnet(opts)
+	query, queryErr := opts.ToSubnetListQuery()
+	if queryErr == nil {
+		if subnetList, ok := lookupcache.Get(lookupcache.Subnet, s.scope.ProjectID, s.scope.RegionName(), query); ok {
+			return subnetList.([]subnets.Subnet), nil
+		}
+	}
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), opts)
 	if err != nil {
 		return []subnets.Subnet{}, err
 	}
 	if len(subnetList) == 0 {
 		return nil, fmt.Errorf("no subnets could be found with the filters provided")
 	}
+	if queryErr == nil {
+		lookupcache.Add(lookupcache.Subnet, s.scope.ProjectID, s.scope.RegionName(), query, subnetList)
+	}
 	return subnetList, nil
 }
 
-func getSubnetName(clusterName string) string {
-	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+// GetNetworkSubnetsByFilter retrieves the subnets of a network by querying openstack with filters.
+// Subnets of networks in other projects which are hidden from the project are returned without
+// details, see getHiddenSubnets.
+func (s *Service) GetNetworkSubnetsByFilter(networkID string, opts *subnets.ListOpts) ([]subnets.Subnet, error) {
+	opts.NetworkID = networkID
+	query, queryErr := opts.ToSubnetListQuery()
+	if queryErr == nil {
+		if subnetList, ok := lookupcache.Get(lookupcache.Subnet, s.scope.ProjectID, s.scope.RegionName(), query); ok {
+			return subnetList.([]subnets.Subnet), nil
+		}
+	}
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(subnetList) == 0 {
+		subnetList, err = s.getHiddenSubnets(networkID, *opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(subnetList) == 0 {
+		return nil, fmt.Errorf("no subnets could be found with the filters provided")
+	}
+	if queryErr == nil {
+		lookupcache.Add(lookupcache.Subnet, s.scope.ProjectID, s.scope.RegionName(), query, subnetList)
+	}
+	return subnetList, nil
+}
+
+// getHiddenSubnets returns the subnets of a network owned by another project which match opts,
+// when Neutron does not show them to the project. This is the case for networks shared with
+// the project as external networks: the network lists the IDs of its subnets, but the subnets
+// themselves can neither be listed nor fetched. As only their IDs are known, hidden subnets
+// can only be filtered by ID.
+func (s *Service) getHiddenSubnets(networkID string, opts subnets.ListOpts) ([]subnets.Subnet, error) {
+	network, err := s.client.GetNetwork(s.scope.Context(), networkID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if network.ProjectID == s.scope.ProjectID || len(network.Subnets) == 0 {
+		return nil, nil
+	}
+
+	// If any subnet of the network is visible, the filter simply did not match.
+	visible, err := s.client.ListSubnet(s.scope.Context(), subnets.ListOpts{NetworkID: networkID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(visible) > 0 {
+		return nil, nil
+	}
+
+	if opts != (subnets.ListOpts{ID: opts.ID, NetworkID: opts.NetworkID}) {
+		return nil, fmt.Errorf("subnets of network %s in project %s are not visible, they can only be selected by ID", networkID, network.ProjectID)
+	}
+
+	s.scope.Logger.V(4).Info("Using subnets hidden by Neutron", "networkID", networkID, "projectID", network.ProjectID)
+	var hidden []subnets.Subnet
+	for _, id := range network.Subnets {
+		if opts.ID == "" || opts.ID == id {
+			hidden = append(hidden, subnets.Subnet{ID: id, NetworkID: network.ID, ProjectID: network.ProjectID})
+		}
+	}
+	return hidden, nil
+}
+
+func getSubnetName(openStackCluster *infrav1.OpenStackCluster, clusterName string) (string, error) {
+	// The subnet is named after the network
+	return getNetworkName(openStackCluster, clusterName)
+}
+
+// getIPv6SubnetName returns the name of the IPv6 subnet of a dual-stack network, which must
+// differ from the name of its IPv4 subnet for the subnets to be found by name.
+func getIPv6SubnetName(subnetName string) string {
+	return subnetName + "-ipv6"
+}
+
+// getManagedSubnetName returns the name of a managed subnet of the cluster network, which is
+// named after the subnet with the NodeCIDR of the cluster.
+func getManagedSubnetName(subnetName, name string) string {
+	return subnetName + "-" + name
 }
 
-func getNetworkName(clusterName string) string {
-	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+func getNetworkName(openStackCluster *infrav1.OpenStackCluster, clusterName string) (string, error) {
+	defaultName := fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+	return names.Render(getResourceNaming(openStackCluster).Network, defaultName, names.NewTemplateData(openStackCluster.Namespace, clusterName))
 }
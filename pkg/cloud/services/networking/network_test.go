@@ -0,0 +1,365 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/mtu"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ReconcileNetwork(t *testing.T) {
+	const (
+		clusterNetworkID   = "c2d7ba3b-0bd1-4b0a-a4f5-8c6b2b2b6f0e"
+		clusterNetworkName = "k8s-clusterapi-cluster-test-cluster"
+	)
+
+	tests := []struct {
+		name             string
+		networkMTU       int
+		availabilityZone string
+		providerNetwork  *infrav1.ProviderNetwork
+		tags             []string
+		statusNetwork    *infrav1.Network
+		expect           func(m *mock.MockNetworkClientMockRecorder)
+		wantNetwork      *infrav1.Network
+		wantErr          bool
+	}{
+		{
+			name:       "network is created with the MTU",
+			networkMTU: 8950,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{}, nil)
+				m.CreateNetwork(gomock.Any(), createOpts{
+					AdminStateUp: gophercloud.Enabled,
+					Name:         clusterNetworkName,
+					MTU:          8950,
+				}).Return(&networks.Network{ID: clusterNetworkID, Name: clusterNetworkName}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, MTU: 8950},
+		},
+		{
+			name: "network is created without an MTU",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{}, nil)
+				m.CreateNetwork(gomock.Any(), createOpts{
+					AdminStateUp: gophercloud.Enabled,
+					Name:         clusterNetworkName,
+				}).Return(&networks.Network{ID: clusterNetworkID, Name: clusterNetworkName}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName},
+		},
+		{
+			name:             "network is created with the availability zone hint",
+			availabilityZone: "az1",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{}, nil)
+				m.CreateNetwork(gomock.Any(), createOpts{
+					AdminStateUp:          gophercloud.Enabled,
+					Name:                  clusterNetworkName,
+					AvailabilityZoneHints: []string{"az1"},
+				}).Return(&networks.Network{ID: clusterNetworkID, Name: clusterNetworkName}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName},
+		},
+		{
+			name: "network is created as a provider network",
+			providerNetwork: &infrav1.ProviderNetwork{
+				PhysicalNetwork: "physnet1",
+				NetworkType:     infrav1.ProviderNetworkTypeVLAN,
+				SegmentationID:  100,
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{}, nil)
+				m.CreateNetwork(gomock.Any(), createOpts{
+					AdminStateUp:    gophercloud.Enabled,
+					Name:            clusterNetworkName,
+					NetworkType:     "vlan",
+					PhysicalNetwork: "physnet1",
+					SegmentationID:  100,
+				}).Return(&networks.Network{ID: clusterNetworkID, Name: clusterNetworkName}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName},
+		},
+		{
+			name:          "changed MTU is set on the existing network",
+			networkMTU:    9000,
+			statusNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, MTU: 8950},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{{ID: clusterNetworkID, Name: clusterNetworkName}}, nil)
+				m.UpdateNetwork(gomock.Any(), clusterNetworkID, mtu.UpdateOptsExt{
+					UpdateOptsBuilder: networks.UpdateOpts{},
+					MTU:               9000,
+				}).Return(&networks.Network{ID: clusterNetworkID, Name: clusterNetworkName}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, MTU: 9000},
+		},
+		{
+			name:          "unchanged MTU is not set again",
+			networkMTU:    8950,
+			statusNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, MTU: 8950},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{{ID: clusterNetworkID, Name: clusterNetworkName}}, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, MTU: 8950},
+		},
+		{
+			name:          "missing tags are added to the existing network",
+			tags:          []string{"team-a", "env-prod"},
+			statusNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{{ID: clusterNetworkID, Name: clusterNetworkName, Tags: []string{"team-a", "external"}}}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "networks", clusterNetworkID, attributestags.ReplaceAllOpts{Tags: []string{"env-prod", "external", "team-a"}}).Return(nil, nil)
+			},
+			wantNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName, Tags: []string{"env-prod", "external", "team-a"}},
+		},
+		{
+			name:          "failure to set the MTU is returned",
+			networkMTU:    9000,
+			statusNetwork: &infrav1.Network{ID: clusterNetworkID, Name: clusterNetworkName},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: clusterNetworkName}).Return([]networks.Network{{ID: clusterNetworkID, Name: clusterNetworkName}}, nil)
+				m.UpdateNetwork(gomock.Any(), clusterNetworkID, gomock.Any()).Return(nil, gophercloud.ErrDefault400{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR:                "10.6.0.0/24",
+					NetworkMTU:              tt.networkMTU,
+					NetworkAvailabilityZone: tt.availabilityZone,
+					ProviderNetwork:         tt.providerNetwork,
+					Tags:                    tt.tags,
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: tt.statusNetwork,
+				},
+			}
+			err := s.ReconcileNetwork(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network).To(Equal(tt.wantNetwork))
+		})
+	}
+}
+
+func Test_reconcileManagedSubnets(t *testing.T) {
+	const (
+		clusterNetworkID = "c2d7ba3b-0bd1-4b0a-a4f5-8c6b2b2b6f0e"
+		subnetName       = "k8s-clusterapi-cluster-test-cluster"
+		subnetID         = "0d4f5b7c-64b9-4b0b-9a5e-8d4a0c6f4a1e"
+	)
+	managedSubnetName := subnetName + "-fip-gateway"
+	createOpts := subnets.CreateOpts{
+		NetworkID:   clusterNetworkID,
+		Name:        managedSubnetName,
+		IPVersion:   4,
+		CIDR:        "10.7.0.0/24",
+		Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+	}
+
+	serviceTypes := []string{"network:floatingip_agent_gateway"}
+	hostRoute := subnets.HostRoute{DestinationCIDR: "192.168.0.0/16", NextHop: "10.7.0.254"}
+
+	tests := []struct {
+		name                string
+		hostRoutes          []infrav1.HostRoute
+		appliedServiceTypes []string
+		expect              func(m *mock.MockNetworkClientMockRecorder)
+		wantErr             bool
+	}{
+		{
+			name: "subnet is created with the service types",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return(nil, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "subnet-service-types"}},
+				}, nil)
+				m.CreateSubnet(gomock.Any(), serviceTypesCreateOpts{
+					CreateOptsBuilder: createOpts,
+					ServiceTypes:      serviceTypes,
+				}).Return(&subnets.Subnet{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true}, nil)
+			},
+		},
+		{
+			name: "service types are not supported",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return(nil, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "port-hints"}},
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:       "subnet is created with the host routes",
+			hostRoutes: []infrav1.HostRoute{{Destination: "192.168.0.0/16", NextHop: "10.7.0.254"}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return(nil, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "subnet-service-types"}},
+				}, nil)
+				opts := createOpts
+				opts.HostRoutes = []subnets.HostRoute{hostRoute}
+				m.CreateSubnet(gomock.Any(), serviceTypesCreateOpts{
+					CreateOptsBuilder: opts,
+					ServiceTypes:      serviceTypes,
+				}).Return(&subnets.Subnet{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true}, nil)
+			},
+		},
+		{
+			name:                "existing subnet is reused",
+			appliedServiceTypes: serviceTypes,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return([]subnets.Subnet{
+					{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true},
+				}, nil)
+			},
+		},
+		{
+			name:                "changed host routes are set on the existing subnet",
+			hostRoutes:          []infrav1.HostRoute{{Destination: "192.168.0.0/16", NextHop: "10.7.0.254"}},
+			appliedServiceTypes: serviceTypes,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return([]subnets.Subnet{
+					{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true, HostRoutes: []subnets.HostRoute{{DestinationCIDR: "172.16.0.0/12", NextHop: "10.7.0.254"}}},
+				}, nil)
+				m.UpdateSubnet(gomock.Any(), subnetID, subnets.UpdateOpts{HostRoutes: &[]subnets.HostRoute{hostRoute}}).Return(&subnets.Subnet{ID: subnetID, HostRoutes: []subnets.HostRoute{hostRoute}}, nil)
+			},
+		},
+		{
+			name:                "unchanged host routes are not set again",
+			hostRoutes:          []infrav1.HostRoute{{Destination: "192.168.0.0/16", NextHop: "10.7.0.254"}},
+			appliedServiceTypes: serviceTypes,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return([]subnets.Subnet{
+					{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true, HostRoutes: []subnets.HostRoute{hostRoute}},
+				}, nil)
+			},
+		},
+		{
+			name:                "changed service types are set on the existing subnet",
+			appliedServiceTypes: []string{"network:router_gateway"},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: clusterNetworkID, Name: managedSubnetName}).Return([]subnets.Subnet{
+					{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24", EnableDHCP: true},
+				}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "subnet-service-types"}},
+				}, nil)
+				m.UpdateSubnet(gomock.Any(), subnetID, serviceTypesUpdateOpts{
+					UpdateOptsBuilder: subnets.UpdateOpts{},
+					ServiceTypes:      serviceTypes,
+				}).Return(&subnets.Subnet{ID: subnetID}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR: "10.6.0.0/24",
+					ManagedSubnets: []infrav1.ManagedSubnet{{
+						Name:         "fip-gateway",
+						CIDR:         "10.7.0.0/24",
+						HostRoutes:   tt.hostRoutes,
+						ServiceTypes: serviceTypes,
+					}},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{
+						ID: clusterNetworkID,
+						ManagedSubnets: []infrav1.ManagedSubnetStatus{{
+							Name:         "fip-gateway",
+							ServiceTypes: tt.appliedServiceTypes,
+						}},
+					},
+				},
+			}
+			err := s.reconcileManagedSubnets(openStackCluster, "test-cluster", subnetName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.ManagedSubnets).To(Equal([]infrav1.ManagedSubnetStatus{{
+				Name:         "fip-gateway",
+				Subnet:       infrav1.Subnet{ID: subnetID, Name: managedSubnetName, CIDR: "10.7.0.0/24"},
+				ServiceTypes: serviceTypes,
+			}}))
+		})
+	}
+}
+
+func Test_serviceTypesCreateOpts(t *testing.T) {
+	g := NewWithT(t)
+	b, err := serviceTypesCreateOpts{
+		CreateOptsBuilder: subnets.CreateOpts{NetworkID: "network-id", IPVersion: 4, CIDR: "10.7.0.0/24"},
+		ServiceTypes:      []string{"network:router_gateway"},
+	}.ToSubnetCreateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(b["subnet"]).To(HaveKeyWithValue("service_types", []string{"network:router_gateway"}))
+	g.Expect(b["subnet"]).To(HaveKeyWithValue("cidr", "10.7.0.0/24"))
+}
+
+func Test_serviceTypesUpdateOpts(t *testing.T) {
+	g := NewWithT(t)
+	hostRoutes := []subnets.HostRoute{}
+	b, err := serviceTypesUpdateOpts{
+		UpdateOptsBuilder: subnets.UpdateOpts{HostRoutes: &hostRoutes},
+	}.ToSubnetUpdateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(b["subnet"]).To(HaveKeyWithValue("service_types", []string{}))
+	g.Expect(b["subnet"]).To(HaveKey("host_routes"))
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// PlanNetworkComponents returns the operations ReconcileNetwork, ReconcileSubnet, ReconcileRouter,
+// ReconcileSecurityGroups and ReconcileAPIServerVIP would perform to create the network components of the cluster
+// which do not exist yet, and to delete the security group of a disabled bastion. Only the resources are looked up,
+// so changes to existing resources, e.g. to the rules of a security group, are not planned.
+func (s *Service) PlanNetworkComponents(openStackCluster *infrav1.OpenStackCluster, clusterName string) ([]infrav1.PlannedOperation, error) {
+	var operations []infrav1.PlannedOperation
+	create := func(resource, name, message string) {
+		operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: resource, Name: name, Message: message})
+	}
+
+	if openStackCluster.Spec.NodeCIDR != "" {
+		networkName, err := getNetworkName(openStackCluster, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		network, err := s.getNetworkByName(networkName)
+		if err != nil {
+			return nil, err
+		}
+		subnetName, err := getSubnetName(openStackCluster, clusterName)
+		if err != nil {
+			return nil, err
+		}
+
+		subnetExists := false
+		if network.ID == "" {
+			create(NetworkResource, networkName, "")
+		} else {
+			subnetList, err := s.client.ListSubnet(s.scope.Context(), subnets.ListOpts{NetworkID: network.ID, CIDR: openStackCluster.Spec.NodeCIDR})
+			if err != nil {
+				return nil, err
+			}
+			subnetExists = len(subnetList) > 0
+		}
+		if !subnetExists {
+			create(SubnetResource, subnetName, fmt.Sprintf("with CIDR %s", openStackCluster.Spec.NodeCIDR))
+		}
+
+		// The external network is looked up on a copy, as it is recorded in the status
+		cluster := openStackCluster.DeepCopy()
+		if err := s.ReconcileExternalNetwork(cluster); err != nil {
+			return nil, err
+		}
+		if openStackCluster.Spec.Router == nil && cluster.Status.ExternalNetwork.ID != "" {
+			routerName, err := getRouterName(openStackCluster, clusterName)
+			if err != nil {
+				return nil, err
+			}
+			router, err := s.getRouterByName(routerName)
+			if err != nil {
+				return nil, err
+			}
+			if router.ID == "" {
+				create(RouterResource, routerName, fmt.Sprintf("with gateway on external network %s", cluster.Status.ExternalNetwork.ID))
+			}
+		}
+	}
+
+	bastionEnabled := openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		groupNames := []string{getSecControlPlaneGroupName(clusterName), getSecWorkerGroupName(clusterName)}
+		if bastionEnabled {
+			groupNames = append(groupNames, getSecBastionGroupName(clusterName))
+		}
+		for _, name := range groupNames {
+			group, err := s.getSecGroupByName(name)
+			if err != nil {
+				return nil, err
+			}
+			if group == nil {
+				create(SecurityGroupResource, name, "")
+			}
+		}
+	}
+	if !bastionEnabled && !openStackCluster.Spec.ExternallyManagedNetwork {
+		name := getSecBastionGroupName(clusterName)
+		group, err := s.getSecGroupByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			operations = append(operations, infrav1.PlannedOperation{Action: infrav1.PlannedActionDelete, Resource: SecurityGroupResource, Name: name, ID: group.ID, Message: "bastion is disabled"})
+		}
+	}
+
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		portName := apiServerVIPPortName(clusterName)
+		portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Name: portName})
+		if err != nil {
+			return nil, err
+		}
+		if len(portList) == 0 {
+			create(PortResource, portName, "VIP of the API server")
+		}
+	}
+
+	return operations, nil
+}
+
+// PlanFloatingIP returns the operation GetOrCreateFloatingIP would perform to create the floating IP ip, which is
+// created with any address if ip is empty.
+func (s *Service) PlanFloatingIP(ip, message string) (*infrav1.PlannedOperation, error) {
+	if ip != "" {
+		fp, err := s.GetFloatingIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		if fp != nil {
+			return nil, nil
+		}
+	}
+	return &infrav1.PlannedOperation{Action: infrav1.PlannedActionCreate, Resource: FloatingIPResource, Name: ip, Message: message}, nil
+}
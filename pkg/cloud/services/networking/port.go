@@ -17,20 +17,25 @@ limitations under the License.
 package networking
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/cluster-api/util"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/batch"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
 )
 
 const (
@@ -50,11 +55,11 @@ func (s *Service) GetPortFromInstanceIP(instanceID string, ip string) ([]ports.P
 		},
 		Limit: 1,
 	}
-	return s.client.ListPort(portOpts)
+	return s.client.ListPort(s.scope.Context(), portOpts)
 }
 
 func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string, portName string, net infrav1.Network, instanceSecurityGroups *[]string, instanceTags []string) (*ports.Port, error) {
-	existingPorts, err := s.client.ListPort(ports.ListOpts{
+	existingPorts, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{
 		Name:      portName,
 		NetworkID: net.ID,
 	})
@@ -84,19 +89,16 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 	var securityGroups *[]string
 	addressPairs := []ports.AddressPair{}
 	if portOpts.DisablePortSecurity == nil || !*portOpts.DisablePortSecurity {
-		for _, ap := range portOpts.AllowedAddressPairs {
-			addressPairs = append(addressPairs, ports.AddressPair{
-				IPAddress:  ap.IPAddress,
-				MACAddress: ap.MACAddress,
-			})
-		}
-		securityGroups, err = s.CollectPortSecurityGroups(eventObject, portOpts.SecurityGroups, portOpts.SecurityGroupFilters)
-		if err != nil {
-			return nil, err
-		}
-		// inherit port security groups from the instance if not explicitly specified
-		if securityGroups == nil || len(*securityGroups) == 0 {
+		addressPairs = getAllowedAddressPairs(portOpts.AllowedAddressPairs)
+		// inherit port security groups from the instance if not explicitly specified. An
+		// explicitly empty list of security groups creates the port without security groups.
+		if portOpts.SecurityGroups == nil && len(portOpts.SecurityGroupFilters) == 0 {
 			securityGroups = instanceSecurityGroups
+		} else {
+			securityGroups, err = s.CollectPortSecurityGroups(eventObject, portOpts.SecurityGroups, portOpts.SecurityGroupFilters)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -104,6 +106,10 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 	if len(portOpts.FixedIPs) > 0 {
 		fips := make([]ports.IP, 0, len(portOpts.FixedIPs)+1)
 		for _, fixedIP := range portOpts.FixedIPs {
+			// The address of a fixed IP claimed from an IPAM pool is resolved by the machine controller
+			if fixedIP.IPAddressPoolRef != nil && fixedIP.IPAddress == "" {
+				return nil, fmt.Errorf("fixed IP of port %s has not been claimed from pool %s", portName, fixedIP.IPAddressPoolRef.Name)
+			}
 			subnetID, err := s.getSubnetIDForFixedIP(fixedIP.Subnet, net.ID)
 			if err != nil {
 				return nil, err
@@ -116,6 +122,9 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 		if net.Subnet.ID != "" {
 			fips = append(fips, ports.IP{SubnetID: net.Subnet.ID})
 		}
+		if net.IPv6Subnet != nil && net.IPv6Subnet.ID != "" {
+			fips = append(fips, ports.IP{SubnetID: net.IPv6Subnet.ID})
+		}
 		fixedIPs = fips
 	}
 
@@ -141,17 +150,57 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 		}
 	}
 
+	profile, err := getPortProfile(portOpts.Profile, portOpts.BindingProfile)
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+		return nil, err
+	}
 	createOpts = portsbinding.CreateOptsExt{
 		CreateOptsBuilder: createOpts,
 		HostID:            portOpts.HostID,
 		VNICType:          portOpts.VNICType,
-		Profile:           getPortProfile(portOpts.Profile),
+		Profile:           profile,
 	}
 
-	port, err := s.client.CreatePort(createOpts)
+	if hints := getPortHints(portOpts.Hints); hints != nil {
+		if err := s.checkPortHintsSupport(portOpts.Hints); err != nil {
+			record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+			return nil, err
+		}
+		createOpts = portHintsCreateOpts{
+			CreateOptsBuilder: createOpts,
+			Hints:             hints,
+		}
+	}
+
+	if portOpts.DeviceProfile != "" {
+		if err := s.checkPortDeviceProfileSupport(); err != nil {
+			record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+			return nil, err
+		}
+		createOpts = portDeviceProfileCreateOpts{
+			CreateOptsBuilder: createOpts,
+			DeviceProfile:     portOpts.DeviceProfile,
+		}
+	}
+
+	if portOpts.QoSPolicy != "" {
+		qosPolicyID, err := s.GetQoSPolicyID(portOpts.QoSPolicy)
+		if err != nil {
+			record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+			return nil, err
+		}
+		createOpts = policies.PortCreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			QoSPolicyID:       qosPolicyID,
+		}
+	}
+
+	port, err := s.client.CreatePort(s.scope.Context(), createOpts)
 	if err != nil {
 		record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
-		return nil, err
+		// The network, subnets or security groups of the port may have been resolved from the lookup cache
+		return nil, lookupcache.InvalidateOnNotFound(err, lookupcache.Network, lookupcache.Subnet, lookupcache.SecurityGroup)
 	}
 
 	var tags []string
@@ -174,11 +223,138 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 			record.Warnf(eventObject, "FailedReplaceTags", "Failed to replace trunk tags %s: %v", portName, err)
 			return nil, err
 		}
+		if err = s.reconcileSubports(eventObject, clusterName, trunk, portOpts.Subports, instanceSecurityGroups, tags); err != nil {
+			return nil, err
+		}
 	}
 
 	return port, nil
 }
 
+// AdoptPort takes over an existing port of a server which is adopted by a machine. The port is renamed like the ports
+// created by CAPO and gets the description of the cluster, so that it is found and deleted together with the machine,
+// and the tags of the instance and the port are added to its tags.
+func (s *Service) AdoptPort(eventObject runtime.Object, clusterName string, portID string, portName string, portOpts *infrav1.PortOpts, instanceTags []string) error {
+	port, err := s.client.GetPort(s.scope.Context(), portID)
+	if err != nil {
+		return fmt.Errorf("get port %s: %v", portID, err)
+	}
+
+	description := names.GetDescription(clusterName)
+	if portOpts != nil && portOpts.Description != "" {
+		description = portOpts.Description
+	}
+	if port.Name != portName || port.Description != description {
+		if _, err := s.client.UpdatePort(s.scope.Context(), port.ID, ports.UpdateOpts{Name: &portName, Description: &description}); err != nil {
+			record.Warnf(eventObject, "FailedAdoptPort", "Failed to adopt port %s with id %s: %v", port.Name, port.ID, err)
+			return err
+		}
+	}
+
+	tags := append([]string{}, port.Tags...)
+	tags = append(tags, instanceTags...)
+	if portOpts != nil {
+		tags = append(tags, portOpts.Tags...)
+	}
+	if len(tags) > len(port.Tags) {
+		if err := s.replaceAllAttributesTags(eventObject, portResource, port.ID, tags); err != nil {
+			record.Warnf(eventObject, "FailedReplaceTags", "Failed to replace port tags %s: %v", portName, err)
+			return err
+		}
+	}
+	record.Eventf(eventObject, "SuccessfulAdoptPort", "Adopted port %s with id %s as %s", port.Name, port.ID, portName)
+	return nil
+}
+
+// ReconcilePortAllowedAddressPairs updates the allowed address pairs of the port of an instance to match its port
+// options, so that e.g. a VIP can be added to the ports of existing machines. Pairs added to the port by other means
+// are removed. Ports without port security cannot have allowed address pairs and are ignored.
+func (s *Service) ReconcilePortAllowedAddressPairs(eventObject runtime.Object, instanceID string, portName string, net infrav1.Network) error {
+	portOpts := net.PortOpts
+	if portOpts == nil || (portOpts.DisablePortSecurity != nil && *portOpts.DisablePortSecurity) {
+		return nil
+	}
+
+	existingPorts, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{
+		Name:      portName,
+		NetworkID: net.ID,
+		DeviceID:  instanceID,
+	})
+	if err != nil {
+		return fmt.Errorf("searching for port %s of instance %s: %v", portName, instanceID, err)
+	}
+	if len(existingPorts) != 1 {
+		s.scope.Logger.V(4).Info("Port of instance not found, not reconciling allowed address pairs", "port", portName, "instance", instanceID, "found", len(existingPorts))
+		return nil
+	}
+	port := &existingPorts[0]
+
+	addressPairs := getAllowedAddressPairs(portOpts.AllowedAddressPairs)
+	if allowedAddressPairsEqual(port, addressPairs) {
+		return nil
+	}
+
+	// The port is read again with its revision, so that the update fails rather than overwriting
+	// a concurrent modification of the port.
+	updated := false
+	err = s.retryOnRevisionConflict(portResource, port.ID, func() error {
+		current, revision, err := s.client.GetPortWithRevision(s.scope.Context(), port.ID)
+		if err != nil {
+			return err
+		}
+		if allowedAddressPairsEqual(current, addressPairs) {
+			return nil
+		}
+		if _, err := s.client.UpdatePortWithRevision(s.scope.Context(), port.ID, revision, ports.UpdateOpts{AllowedAddressPairs: &addressPairs}); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	if err != nil {
+		record.Warnf(eventObject, "FailedUpdatePort", "Failed to update allowed address pairs of port %s with id %s: %v", portName, port.ID, err)
+		return err
+	}
+	if updated {
+		record.Eventf(eventObject, "SuccessfulUpdatePort", "Updated allowed address pairs of port %s with id %s", portName, port.ID)
+	}
+	return nil
+}
+
+func getAllowedAddressPairs(pairs []infrav1.AddressPair) []ports.AddressPair {
+	addressPairs := []ports.AddressPair{}
+	for _, ap := range pairs {
+		addressPairs = append(addressPairs, ports.AddressPair{
+			IPAddress:  ap.IPAddress,
+			MACAddress: ap.MACAddress,
+		})
+	}
+	return addressPairs
+}
+
+// allowedAddressPairsEqual returns true if the port has exactly the given allowed address pairs, in any order.
+// Neutron uses the MAC address of the port for pairs which do not specify one.
+func allowedAddressPairsEqual(port *ports.Port, addressPairs []ports.AddressPair) bool {
+	if len(port.AllowedAddressPairs) != len(addressPairs) {
+		return false
+	}
+
+	existing := make(map[ports.AddressPair]int, len(port.AllowedAddressPairs))
+	for _, ap := range port.AllowedAddressPairs {
+		existing[ap]++
+	}
+	for _, ap := range addressPairs {
+		if ap.MACAddress == "" {
+			ap.MACAddress = port.MACAddress
+		}
+		if existing[ap] == 0 {
+			return false
+		}
+		existing[ap]--
+	}
+	return true
+}
+
 func (s *Service) getSubnetIDForFixedIP(subnet *infrav1.SubnetFilter, networkID string) (string, error) {
 	if subnet == nil {
 		return "", nil
@@ -190,10 +366,16 @@ func (s *Service) getSubnetIDForFixedIP(subnet *infrav1.SubnetFilter, networkID
 
 	opts := subnet.ToListOpt()
 	opts.NetworkID = networkID
-	subnets, err := s.client.ListSubnet(opts)
+	subnets, err := s.client.ListSubnet(s.scope.Context(), opts)
 	if err != nil {
 		return "", err
 	}
+	if len(subnets) == 0 {
+		subnets, err = s.getHiddenSubnets(networkID, opts)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	switch len(subnets) {
 	case 0:
@@ -205,25 +387,35 @@ func (s *Service) getSubnetIDForFixedIP(subnet *infrav1.SubnetFilter, networkID
 	}
 }
 
-func getPortProfile(p map[string]string) map[string]interface{} {
+func getPortProfile(p map[string]string, bp map[string]apiextensionsv1.JSON) (map[string]interface{}, error) {
 	portProfile := make(map[string]interface{})
 	for k, v := range p {
 		portProfile[k] = v
 	}
+	for k, v := range bp {
+		if _, ok := portProfile[k]; ok {
+			return nil, fmt.Errorf("binding profile key %s is also set in profile", k)
+		}
+		var value interface{}
+		if err := json.Unmarshal(v.Raw, &value); err != nil {
+			return nil, fmt.Errorf("invalid value of binding profile key %s: %v", k, err)
+		}
+		portProfile[k] = value
+	}
 	// We need return nil if there is no profiles
 	// to have backward compatible defaults.
 	// To set profiles, your tenant needs this permission:
 	// rule:create_port and rule:create_port:binding:profile
 	if len(portProfile) == 0 {
-		return nil
+		return nil, nil
 	}
-	return portProfile
+	return portProfile, nil
 }
 
 func (s *Service) DeletePort(eventObject runtime.Object, portID string) error {
 	var err error
-	err = util.PollImmediate(retryIntervalPortDelete, timeoutPortDelete, func() (bool, error) {
-		err = s.client.DeletePort(portID)
+	err = retry.Wait(s.scope.Context(), retry.Network, retry.Policy{Interval: retryIntervalPortDelete, Timeout: timeoutPortDelete}, func() (bool, error) {
+		err = s.client.DeletePort(s.scope.Context(), portID)
 		if err != nil {
 			if capoerrors.IsNotFound(err) {
 				record.Eventf(eventObject, "SuccessfulDeletePort", "Port with id %d did not exist", portID)
@@ -246,7 +438,7 @@ func (s *Service) DeletePort(eventObject runtime.Object, portID string) error {
 
 func (s *Service) DeletePorts(openStackCluster *infrav1.OpenStackCluster) error {
 	networkID := openStackCluster.Spec.Network.ID
-	portList, err := s.client.ListPort(ports.ListOpts{
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{
 		NetworkID:   networkID,
 		DeviceOwner: "",
 	})
@@ -257,21 +449,24 @@ func (s *Service) DeletePorts(openStackCluster *infrav1.OpenStackCluster) error
 		return fmt.Errorf("list ports of network %q: %v", networkID, err)
 	}
 
+	var portIDs []string
 	for _, port := range portList {
 		if strings.HasPrefix(port.Name, openStackCluster.Name) {
-			err := s.DeletePort(openStackCluster, port.ID)
-			if capoerrors.IsNotFound(err) {
-				continue
-			}
-			return fmt.Errorf("delete port %s of network %q failed : %v", port.ID, networkID, err)
+			portIDs = append(portIDs, port.ID)
 		}
 	}
 
-	return nil
+	return batch.Delete("port", portIDs, func(portID string) error {
+		err := s.DeletePort(openStackCluster, portID)
+		if err != nil && !capoerrors.IsNotFound(err) {
+			return fmt.Errorf("delete port %s of network %q failed : %w", portID, networkID, err)
+		}
+		return nil
+	})
 }
 
 func (s *Service) GarbageCollectErrorInstancesPort(eventObject runtime.Object, instanceName string) error {
-	portList, err := s.client.ListPort(ports.ListOpts{
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{
 		Name: instanceName,
 	})
 	if err != nil {
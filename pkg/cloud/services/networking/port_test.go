@@ -17,19 +17,28 @@ limitations under the License.
 package networking
 
 import (
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 func Test_GetOrCreatePort(t *testing.T) {
@@ -46,6 +55,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 	tenantID := "62b523a7-f838-45fd-904f-d2db2bb58e04"
 	projectID := "063171b1-0595-4882-98cd-3ee79676ff87"
 	trunkID := "eb7541fa-5e2a-4cca-b2c3-dfa409b917ce"
+	qosPolicyID := "a1c4b5f0-9f7e-4d5b-8f0e-2f5c3b8d9e61"
 
 	// Other arbitrary variables passed in to the tests
 	instanceSecurityGroups := []string{"instance-secgroup"}
@@ -77,7 +87,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 			[]string{},
 			func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{{
@@ -100,7 +110,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 			[]string{},
 			func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{
@@ -131,12 +141,12 @@ func Test_GetOrCreatePort(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
 				m.
-					CreatePort(portsbinding.CreateOptsExt{
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
 							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -218,18 +228,18 @@ func Test_GetOrCreatePort(t *testing.T) {
 					Profile:           map[string]interface{}{"interface_name": "eno1"},
 				}
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-bar",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
 				m.
-					CreatePort(portbindingCreateOptsExt).
+					CreatePort(gomock.Any(), portbindingCreateOptsExt).
 					Return(&ports.Port{
 						ID: portID1,
 					}, nil)
-				m.ReplaceAllAttributesTags("ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-port-tag"}}).Return([]string{"my-port-tag"}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-port-tag"}}).Return([]string{"my-port-tag"}, nil)
 				m.
-					ListSubnet(subnets.ListOpts{
+					ListSubnet(gomock.Any(), subnets.ListOpts{
 						Name:      "subnetFoo",
 						NetworkID: netID,
 					}).Return([]subnets.Subnet{
@@ -245,6 +255,48 @@ func Test_GetOrCreatePort(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"creates port with multiple fixed IPs in the same subnet in addition to the cluster subnet",
+			"foo-port-1",
+			infrav1.Network{
+				ID:     netID,
+				Subnet: &infrav1.Subnet{ID: subnetID1},
+				PortOpts: &infrav1.PortOpts{
+					FixedIPs: []infrav1.FixedIP{
+						{Subnet: &infrav1.SubnetFilter{ID: subnetID2}},
+						{Subnet: &infrav1.SubnetFilter{ID: subnetID2}, IPAddress: "192.168.1.100"},
+						{IPAddress: "192.168.1.101"},
+					},
+				},
+			},
+			&instanceSecurityGroups,
+			[]string{},
+			func(m *mock.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
+						CreateOptsBuilder: ports.CreateOpts{
+							Name:        "foo-port-1",
+							Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+							NetworkID:   netID,
+							FixedIPs: []ports.IP{
+								{SubnetID: subnetID2},
+								{SubnetID: subnetID2, IPAddress: "192.168.1.100"},
+								{IPAddress: "192.168.1.101"},
+								{SubnetID: subnetID1},
+							},
+							SecurityGroups:      &instanceSecurityGroups,
+							AllowedAddressPairs: []ports.AddressPair{},
+						},
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
 		{
 			"fails to create port with specified portOpts if subnet query returns more than one subnet",
 			"foo-port-bar",
@@ -266,12 +318,12 @@ func Test_GetOrCreatePort(t *testing.T) {
 			nil,
 			func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-bar",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
 				m.
-					ListSubnet(subnets.ListOpts{
+					ListSubnet(gomock.Any(), subnets.ListOpts{
 						Tags:      "Foo",
 						NetworkID: netID,
 					}).Return([]subnets.Subnet{
@@ -304,12 +356,12 @@ func Test_GetOrCreatePort(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
 				m.
-					CreatePort(portsbinding.CreateOptsExt{
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
 							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -323,6 +375,39 @@ func Test_GetOrCreatePort(t *testing.T) {
 			&ports.Port{ID: portID1},
 			false,
 		},
+		{
+			"creates port without security groups if port security groups are explicitly empty",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					SecurityGroups: &[]string{},
+				},
+			},
+			&instanceSecurityGroups,
+			[]string{},
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
+						CreateOptsBuilder: ports.CreateOpts{
+							Name:                "foo-port-1",
+							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+							SecurityGroups:      &[]string{},
+							NetworkID:           netID,
+							AllowedAddressPairs: []ports.AddressPair{},
+						},
+					},
+					).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
 		{
 			"creates port with instance tags when port tags aren't specified",
 			"foo-port-1",
@@ -335,11 +420,11 @@ func Test_GetOrCreatePort(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
-				m.CreatePort(portsbinding.CreateOptsExt{
+				m.CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
 					CreateOptsBuilder: ports.CreateOpts{
 						Name:                "foo-port-1",
 						Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -347,7 +432,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 						AllowedAddressPairs: []ports.AddressPair{},
 					},
 				}).Return(&ports.Port{ID: portID1}, nil)
-				m.ReplaceAllAttributesTags("ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-instance-tag"}}).Return([]string{"my-instance-tag"}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-instance-tag"}}).Return([]string{"my-instance-tag"}, nil)
 			},
 			&ports.Port{ID: portID1},
 			false,
@@ -364,11 +449,11 @@ func Test_GetOrCreatePort(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
-				m.CreatePort(portsbinding.CreateOptsExt{
+				m.CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
 					CreateOptsBuilder: ports.CreateOpts{
 						Name:                "foo-port-1",
 						Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -377,7 +462,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 					},
 				}).Return(&ports.Port{ID: portID1}, nil)
 				m.
-					ReplaceAllAttributesTags("ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-instance-tag", "my-port-tag"}}).
+					ReplaceAllAttributesTags(gomock.Any(), "ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-instance-tag", "my-port-tag"}}).
 					Return([]string{"my-instance-tag", "my-port-tag"}, nil)
 			},
 			&ports.Port{ID: portID1},
@@ -397,12 +482,12 @@ func Test_GetOrCreatePort(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListPort(ports.ListOpts{
+					ListPort(gomock.Any(), ports.ListOpts{
 						Name:      "foo-port-1",
 						NetworkID: netID,
 					}).Return([]ports.Port{}, nil)
 				m.
-					CreatePort(portsbinding.CreateOptsExt{
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
 							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -411,23 +496,283 @@ func Test_GetOrCreatePort(t *testing.T) {
 						},
 					}).Return(&ports.Port{Name: "foo-port-1", ID: portID1}, nil)
 				m.
-					ListTrunk(trunks.ListOpts{
+					ListTrunk(gomock.Any(), trunks.ListOpts{
 						Name:   "foo-port-1",
 						PortID: portID1,
 					}).Return([]trunks.Trunk{}, nil)
 				m.
-					CreateTrunk(trunks.CreateOpts{
+					CreateTrunk(gomock.Any(), trunks.CreateOpts{
 						Name:        "foo-port-1",
 						PortID:      portID1,
 						Description: "Created by cluster-api-provider-openstack cluster test-cluster",
 					}).Return(&trunks.Trunk{ID: trunkID}, nil)
 
-				m.ReplaceAllAttributesTags("ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-tag"}}).Return([]string{"my-tag"}, nil)
-				m.ReplaceAllAttributesTags("trunks", trunkID, attributestags.ReplaceAllOpts{Tags: []string{"my-tag"}}).Return([]string{"my-tag"}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "ports", portID1, attributestags.ReplaceAllOpts{Tags: []string{"my-tag"}}).Return([]string{"my-tag"}, nil)
+				m.ReplaceAllAttributesTags(gomock.Any(), "trunks", trunkID, attributestags.ReplaceAllOpts{Tags: []string{"my-tag"}}).Return([]string{"my-tag"}, nil)
 			},
 			&ports.Port{Name: "foo-port-1", ID: portID1},
 			false,
 		},
+		{
+			"creates port with hints",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					Hints: &infrav1.PortHints{
+						OpenVSwitch: &infrav1.OpenVSwitchPortHints{TxSteering: infrav1.TxSteeringHash},
+					},
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "port-hints"}},
+					{Extension: common.Extension{Alias: "port-hint-ovs-tx-steering"}},
+				}, nil)
+				m.
+					CreatePort(gomock.Any(), portHintsCreateOpts{
+						CreateOptsBuilder: portsbinding.CreateOptsExt{
+							CreateOptsBuilder: ports.CreateOpts{
+								Name:                "foo-port-1",
+								Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+								NetworkID:           netID,
+								AllowedAddressPairs: []ports.AddressPair{},
+							},
+						},
+						Hints: map[string]interface{}{
+							"openvswitch": map[string]interface{}{
+								"other_config": map[string]interface{}{"tx-steering": "hash"},
+							},
+						},
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"fails to create port with hints if the extension is not supported",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					Hints: &infrav1.PortHints{
+						OpenVSwitch: &infrav1.OpenVSwitchPortHints{TxSteering: infrav1.TxSteeringThru},
+					},
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "port-hints"}},
+				}, nil)
+			},
+			nil,
+			true,
+		},
+		{
+			"creates port with a device profile",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					DeviceProfile: "smartnic",
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "port-device-profile"}},
+				}, nil)
+				m.
+					CreatePort(gomock.Any(), portDeviceProfileCreateOpts{
+						CreateOptsBuilder: portsbinding.CreateOptsExt{
+							CreateOptsBuilder: ports.CreateOpts{
+								Name:                "foo-port-1",
+								Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+								NetworkID:           netID,
+								AllowedAddressPairs: []ports.AddressPair{},
+							},
+						},
+						DeviceProfile: "smartnic",
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"fails to create port with a device profile if the extension is not supported",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					DeviceProfile: "smartnic",
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+					{Extension: common.Extension{Alias: "port-hints"}},
+				}, nil)
+			},
+			nil,
+			true,
+		},
+		{
+			"creates port with a binding profile",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					VNICType: "direct",
+					Profile:  map[string]string{"physical_network": "physnet1"},
+					BindingProfile: map[string]apiextensionsv1.JSON{
+						"capabilities": {Raw: []byte(`["switchdev"]`)},
+						"trusted":      {Raw: []byte(`true`)},
+					},
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					CreatePort(gomock.Any(), portsbinding.CreateOptsExt{
+						CreateOptsBuilder: ports.CreateOpts{
+							Name:                "foo-port-1",
+							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+							NetworkID:           netID,
+							AllowedAddressPairs: []ports.AddressPair{},
+						},
+						VNICType: "direct",
+						Profile: map[string]interface{}{
+							"physical_network": "physnet1",
+							"capabilities":     []interface{}{"switchdev"},
+							"trusted":          true,
+						},
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"fails to create port if a key is set in both profile and binding profile",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					Profile: map[string]string{"trusted": "true"},
+					BindingProfile: map[string]apiextensionsv1.JSON{
+						"trusted": {Raw: []byte(`true`)},
+					},
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+			},
+			nil,
+			true,
+		},
+		{
+			"creates port with a QoS policy by name",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					QoSPolicy: "bandwidth-limit",
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{ID: "bandwidth-limit"}).Return([]policies.Policy{}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{Name: "bandwidth-limit"}).Return([]policies.Policy{{ID: qosPolicyID, Name: "bandwidth-limit"}}, nil)
+				m.
+					CreatePort(gomock.Any(), policies.PortCreateOptsExt{
+						CreateOptsBuilder: portsbinding.CreateOptsExt{
+							CreateOptsBuilder: ports.CreateOpts{
+								Name:                "foo-port-1",
+								Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+								NetworkID:           netID,
+								AllowedAddressPairs: []ports.AddressPair{},
+							},
+						},
+						QoSPolicyID: qosPolicyID,
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"fails to create port if the QoS policy does not exist",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					QoSPolicy: qosPolicyID,
+				},
+			},
+			nil,
+			nil,
+			func(m *mock.MockNetworkClientMockRecorder) {
+				// No ports found
+				m.
+					ListPort(gomock.Any(), ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{ID: qosPolicyID}).Return([]policies.Policy{}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{Name: qosPolicyID}).Return([]policies.Policy{}, nil)
+			},
+			nil,
+			true,
+		},
 	}
 
 	eventObject := &infrav1.OpenStackMachine{}
@@ -438,6 +783,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 			tt.expect(mockClient.EXPECT())
 			s := Service{
 				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
 			}
 			got, err := s.GetOrCreatePort(
 				eventObject,
@@ -460,3 +806,155 @@ func Test_GetOrCreatePort(t *testing.T) {
 func pointerTo(b bool) *bool {
 	return &b
 }
+
+func Test_ReconcilePortAllowedAddressPairs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		netID      = "7fd24ceb-788a-441f-ad0a-d8e2f5d31a1d"
+		portID     = "50214c48-c09e-4a54-914f-97b40fd22802"
+		instanceID = "9a6f1a43-2e25-4c46-9b8f-6a7d8b2e7c11"
+		portName   = "test-machine-0"
+		macAddress = "fa:16:3e:12:34:56"
+	)
+	listOpts := ports.ListOpts{Name: portName, NetworkID: netID, DeviceID: instanceID}
+
+	tests := []struct {
+		name     string
+		portOpts *infrav1.PortOpts
+		expect   func(m *mock.MockNetworkClientMockRecorder)
+		wantErr  bool
+	}{
+		{
+			name: "Add allowed address pair",
+			portOpts: &infrav1.PortOpts{
+				AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.100"}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{ID: portID, MACAddress: macAddress}}, nil)
+				m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{ID: portID, MACAddress: macAddress}, 4, nil)
+				m.UpdatePortWithRevision(gomock.Any(), portID, 4, ports.UpdateOpts{
+					AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: "10.0.0.100"}},
+				}).Return(&ports.Port{ID: portID}, nil)
+			},
+		},
+		{
+			name: "Port modified concurrently is updated based on its new revision",
+			portOpts: &infrav1.PortOpts{
+				AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.100"}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{ID: portID, MACAddress: macAddress}}, nil)
+				gomock.InOrder(
+					m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{ID: portID, MACAddress: macAddress}, 4, nil),
+					m.UpdatePortWithRevision(gomock.Any(), portID, 4, gomock.Any()).Return(nil, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusPreconditionFailed}),
+					m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{ID: portID, MACAddress: macAddress}, 5, nil),
+					m.UpdatePortWithRevision(gomock.Any(), portID, 5, ports.UpdateOpts{
+						AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: "10.0.0.100"}},
+					}).Return(&ports.Port{ID: portID}, nil),
+				)
+			},
+		},
+		{
+			name: "Port updated concurrently to the same allowed address pairs",
+			portOpts: &infrav1.PortOpts{
+				AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.100"}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{ID: portID, MACAddress: macAddress}}, nil)
+				m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{
+					ID:                  portID,
+					MACAddress:          macAddress,
+					AllowedAddressPairs: []ports.AddressPair{{IPAddress: "10.0.0.100", MACAddress: macAddress}},
+				}, 5, nil)
+			},
+		},
+		{
+			name: "Port modified concurrently on every attempt",
+			portOpts: &infrav1.PortOpts{
+				AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.100"}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{ID: portID, MACAddress: macAddress}}, nil)
+				m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{ID: portID, MACAddress: macAddress}, 4, nil).Times(revisionConflictRetries)
+				m.UpdatePortWithRevision(gomock.Any(), portID, 4, gomock.Any()).Return(nil, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusPreconditionFailed}).Times(revisionConflictRetries)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Remove allowed address pairs",
+			portOpts: &infrav1.PortOpts{},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{
+					ID:                  portID,
+					MACAddress:          macAddress,
+					AllowedAddressPairs: []ports.AddressPair{{IPAddress: "10.0.0.100", MACAddress: macAddress}},
+				}}, nil)
+				m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{
+					ID:                  portID,
+					MACAddress:          macAddress,
+					AllowedAddressPairs: []ports.AddressPair{{IPAddress: "10.0.0.100", MACAddress: macAddress}},
+				}, 7, nil)
+				m.UpdatePortWithRevision(gomock.Any(), portID, 7, ports.UpdateOpts{
+					AllowedAddressPairs: &[]ports.AddressPair{},
+				}).Return(&ports.Port{ID: portID}, nil)
+			},
+		},
+		{
+			name: "Up to date allowed address pairs defaulted to the port MAC address",
+			portOpts: &infrav1.PortOpts{
+				AllowedAddressPairs: []infrav1.AddressPair{
+					{IPAddress: "10.0.0.100"},
+					{IPAddress: "10.0.0.0/24", MACAddress: "fa:16:3e:ab:cd:ef"},
+				},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{
+					ID:         portID,
+					MACAddress: macAddress,
+					AllowedAddressPairs: []ports.AddressPair{
+						{IPAddress: "10.0.0.0/24", MACAddress: "fa:16:3e:ab:cd:ef"},
+						{IPAddress: "10.0.0.100", MACAddress: macAddress},
+					},
+				}}, nil)
+			},
+		},
+		{
+			name: "Port security disabled",
+			portOpts: &infrav1.PortOpts{
+				DisablePortSecurity: pointerTo(true),
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {},
+		},
+		{
+			name:     "Update port fails",
+			portOpts: &infrav1.PortOpts{AllowedAddressPairs: []infrav1.AddressPair{{IPAddress: "10.0.0.100"}}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), listOpts).Return([]ports.Port{{ID: portID, MACAddress: macAddress}}, nil)
+				m.GetPortWithRevision(gomock.Any(), portID).Return(&ports.Port{ID: portID, MACAddress: macAddress}, 4, nil)
+				m.UpdatePortWithRevision(gomock.Any(), portID, 4, gomock.Any()).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			net := infrav1.Network{ID: netID, PortOpts: tt.portOpts}
+			err := s.ReconcilePortAllowedAddressPairs(&infrav1.OpenStackMachine{}, instanceID, portName, net)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
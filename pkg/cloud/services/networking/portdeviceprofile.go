@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+)
+
+const portDeviceProfileExtension = "port-device-profile"
+
+// portDeviceProfileCreateOpts adds the Cyborg device profile of the port-device-profile
+// extension, which is not supported by gophercloud, to the port create request.
+type portDeviceProfileCreateOpts struct {
+	ports.CreateOptsBuilder
+	DeviceProfile string
+}
+
+func (opts portDeviceProfileCreateOpts) ToPortCreateMap() (map[string]interface{}, error) {
+	b, err := opts.CreateOptsBuilder.ToPortCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	port := b["port"].(map[string]interface{})
+	port["device_profile"] = opts.DeviceProfile
+	return b, nil
+}
+
+// checkPortDeviceProfileSupport returns an error if Neutron does not support device profiles.
+// Without the extension Neutron would reject the request with an unrecognised attribute.
+func (s *Service) checkPortDeviceProfileSupport() error {
+	allExts, err := s.client.ListExtensions(s.scope.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range allExts {
+		if ext.Alias == portDeviceProfileExtension {
+			return nil
+		}
+	}
+	return fmt.Errorf("port device profiles require the %s extension, which is not supported by the networking service", portDeviceProfileExtension)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+const (
+	portHintsExtension             = "port-hints"
+	portHintOVSTxSteeringExtension = "port-hint-ovs-tx-steering"
+)
+
+// portHintsCreateOpts adds the hints of the port-hints extension, which are not
+// supported by gophercloud, to the port create request.
+type portHintsCreateOpts struct {
+	ports.CreateOptsBuilder
+	Hints map[string]interface{}
+}
+
+func (opts portHintsCreateOpts) ToPortCreateMap() (map[string]interface{}, error) {
+	b, err := opts.CreateOptsBuilder.ToPortCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	port := b["port"].(map[string]interface{})
+	port["hints"] = opts.Hints
+	return b, nil
+}
+
+// getPortHints returns the hints of the port in the format expected by Neutron, or nil
+// if no hints are set.
+func getPortHints(h *infrav1.PortHints) map[string]interface{} {
+	if h == nil || h.OpenVSwitch == nil || h.OpenVSwitch.TxSteering == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"openvswitch": map[string]interface{}{
+			"other_config": map[string]interface{}{
+				"tx-steering": string(h.OpenVSwitch.TxSteering),
+			},
+		},
+	}
+}
+
+// checkPortHintsSupport returns an error if Neutron does not support the given hints.
+// Without the extensions Neutron would reject the request with an unrecognised attribute.
+func (s *Service) checkPortHintsSupport(h *infrav1.PortHints) error {
+	allExts, err := s.client.ListExtensions(s.scope.Context())
+	if err != nil {
+		return err
+	}
+
+	supported := make(map[string]bool, len(allExts))
+	for _, ext := range allExts {
+		supported[ext.Alias] = true
+	}
+
+	required := []string{portHintsExtension}
+	if h.OpenVSwitch != nil && h.OpenVSwitch.TxSteering != "" {
+		required = append(required, portHintOVSTxSteeringExtension)
+	}
+	for _, alias := range required {
+		if !supported[alias] {
+			return fmt.Errorf("port hints require the %s extension, which is not supported by the networking service", alias)
+		}
+	}
+	return nil
+}
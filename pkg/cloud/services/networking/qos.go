@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
+)
+
+// GetQoSPolicyID returns the ID of the QoS policy with the given ID or name.
+func (s *Service) GetQoSPolicyID(nameOrID string) (string, error) {
+	policyList, err := s.client.ListQoSPolicy(s.scope.Context(), policies.ListOpts{ID: nameOrID})
+	if err != nil {
+		return "", fmt.Errorf("error listing QoS policies: %v", err)
+	}
+	if len(policyList) == 0 {
+		policyList, err = s.client.ListQoSPolicy(s.scope.Context(), policies.ListOpts{Name: nameOrID})
+		if err != nil {
+			return "", fmt.Errorf("error listing QoS policies: %v", err)
+		}
+	}
+
+	switch len(policyList) {
+	case 0:
+		return "", fmt.Errorf("no QoS policy with name or ID %s found", nameOrID)
+	case 1:
+		return policyList[0].ID, nil
+	default:
+		return "", fmt.Errorf("found %d QoS policies with name %s", len(policyList), nameOrID)
+	}
+}
@@ -19,12 +19,14 @@ package networking
 import (
 	"fmt"
 
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
@@ -39,35 +41,53 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		s.scope.Logger.V(4).Info("No need to reconcile router since no subnet exists.")
 		return nil
 	}
-	if openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "" {
-		s.scope.Logger.V(3).Info("No need to create router, due to missing ExternalNetworkID.")
-		return nil
-	}
-
-	routerName := getRouterName(clusterName)
-	s.scope.Logger.Info("Reconciling router", "name", routerName)
-
-	routerList, err := s.client.ListRouter(routers.ListOpts{
-		Name: routerName,
-	})
-	if err != nil {
-		return err
-	}
-
-	if len(routerList) > 1 {
-		return fmt.Errorf("found %d router with the name %s, which should not happen", len(routerList), routerName)
-	}
 
 	var router *routers.Router
-	if len(routerList) == 0 {
-		var err error
-		router, err = s.createRouter(openStackCluster, clusterName, routerName)
+	if openStackCluster.Spec.Router != nil {
+		existingRouter, err := s.getRouterByFilter(openStackCluster.Spec.Router)
 		if err != nil {
 			return err
 		}
+		if existingRouter.ID == "" {
+			return fmt.Errorf("no router could be found with the filters provided")
+		}
+		router = &existingRouter
+		s.scope.Logger.V(6).Info(fmt.Sprintf("Using existing Router %s with id %s", router.Name, router.ID))
 	} else {
-		router = &routerList[0]
-		s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing Router %s with id %s", routerName, router.ID))
+		if openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "" {
+			s.scope.Logger.V(3).Info("No need to create router, due to missing ExternalNetworkID.")
+			return nil
+		}
+
+		routerName, err := getRouterName(openStackCluster, clusterName)
+		if err != nil {
+			return err
+		}
+		s.scope.Logger.Info("Reconciling router", "name", routerName)
+
+		routerList, err := s.client.ListRouter(s.scope.Context(), routers.ListOpts{
+			Name: routerName,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(routerList) > 1 {
+			return fmt.Errorf("found %d router with the name %s, which should not happen", len(routerList), routerName)
+		}
+
+		if len(routerList) == 0 {
+			router, err = s.createRouter(openStackCluster, clusterName, routerName)
+			if err != nil {
+				return err
+			}
+		} else {
+			router = &routerList[0]
+			s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing Router %s with id %s", routerName, router.ID))
+			if router.Tags, err = s.reconcileTags(openStackCluster, "routers", router.ID, router.Tags, openStackCluster.Spec.Tags); err != nil {
+				return err
+			}
+		}
 	}
 
 	routerIPs := []string{}
@@ -88,33 +108,90 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		}
 	}
 
+	if openStackCluster.Spec.Router == nil && openStackCluster.Spec.RouterExternalGateway != nil {
+		if err := s.reconcileRouterExternalGateway(openStackCluster, router); err != nil {
+			return err
+		}
+	}
+
 	routerInterfaces, err := s.getRouterInterfaces(router.ID)
 	if err != nil {
 		return err
 	}
 
-	createInterface := true
-	// check all router interfaces for an existing port in our subnet.
-INTERFACE_LOOP:
+	subnetIDs := []string{openStackCluster.Status.Network.Subnet.ID}
+	if openStackCluster.Status.Network.IPv6Subnet != nil && openStackCluster.Status.Network.IPv6Subnet.ID != "" {
+		subnetIDs = append(subnetIDs, openStackCluster.Status.Network.IPv6Subnet.ID)
+	}
+	for _, managedSubnet := range openStackCluster.Status.Network.ManagedSubnets {
+		subnetIDs = append(subnetIDs, managedSubnet.Subnet.ID)
+	}
+
+	for _, subnetID := range subnetIDs {
+		createInterface := true
+		// check all router interfaces for an existing port in our subnet.
+	INTERFACE_LOOP:
+		for _, iface := range routerInterfaces {
+			for _, ip := range iface.FixedIPs {
+				if ip.SubnetID == subnetID {
+					createInterface = false
+					break INTERFACE_LOOP
+				}
+			}
+		}
+
+		// ... and create a router interface for our subnet.
+		if createInterface {
+			s.scope.Logger.V(4).Info("Creating RouterInterface", "routerID", router.ID, "subnetID", subnetID)
+			routerInterface, err := s.client.AddRouterInterface(s.scope.Context(), router.ID, routers.AddInterfaceOpts{
+				SubnetID: subnetID,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to create router interface: %v", err)
+			}
+			s.scope.Logger.V(4).Info("Created RouterInterface", "id", routerInterface.ID)
+		}
+	}
+	return nil
+}
+
+// LookupRouter looks up the existing router of an externally managed network and records it in the status of the
+// cluster without changing it. It fails if there is no router, or if the subnet of the cluster is not connected to it.
+func (s *Service) LookupRouter(openStackCluster *infrav1.OpenStackCluster) error {
+	router, err := s.getRouterByFilter(openStackCluster.Spec.Router)
+	if err != nil {
+		return err
+	}
+	if router.ID == "" {
+		return fmt.Errorf("no router could be found with the filters provided")
+	}
+
+	routerInterfaces, err := s.getRouterInterfaces(router.ID)
+	if err != nil {
+		return err
+	}
+	subnetID := openStackCluster.Status.Network.Subnet.ID
+	connected := false
 	for _, iface := range routerInterfaces {
 		for _, ip := range iface.FixedIPs {
-			if ip.SubnetID == openStackCluster.Status.Network.Subnet.ID {
-				createInterface = false
-				break INTERFACE_LOOP
+			if ip.SubnetID == subnetID {
+				connected = true
 			}
 		}
 	}
+	if !connected {
+		return fmt.Errorf("subnet %s is not connected to router %s", subnetID, router.ID)
+	}
 
-	// ... and create a router interface for our subnet.
-	if createInterface {
-		s.scope.Logger.V(4).Info("Creating RouterInterface", "routerID", router.ID, "subnetID", openStackCluster.Status.Network.Subnet.ID)
-		routerInterface, err := s.client.AddRouterInterface(router.ID, routers.AddInterfaceOpts{
-			SubnetID: openStackCluster.Status.Network.Subnet.ID,
-		})
-		if err != nil {
-			return fmt.Errorf("unable to create router interface: %v", err)
-		}
-		s.scope.Logger.V(4).Info("Created RouterInterface", "id", routerInterface.ID)
+	routerIPs := []string{}
+	for _, ip := range router.GatewayInfo.ExternalFixedIPs {
+		routerIPs = append(routerIPs, ip.IPAddress)
+	}
+	openStackCluster.Status.Network.Router = &infrav1.Router{
+		Name: router.Name,
+		ID:   router.ID,
+		Tags: router.Tags,
+		IPs:  routerIPs,
 	}
 	return nil
 }
@@ -124,6 +201,9 @@ func (s *Service) createRouter(openStackCluster *infrav1.OpenStackCluster, clust
 		Description: names.GetDescription(clusterName),
 		Name:        name,
 	}
+	if openStackCluster.Spec.NetworkAvailabilityZone != "" {
+		opts.AvailabilityZoneHints = []string{openStackCluster.Spec.NetworkAvailabilityZone}
+	}
 	// only set the GatewayInfo right now when no externalIPs
 	// should be configured because at least in our environment
 	// we can only set the routerIP via gateway update not during create
@@ -132,9 +212,12 @@ func (s *Service) createRouter(openStackCluster *infrav1.OpenStackCluster, clust
 		opts.GatewayInfo = &routers.GatewayInfo{
 			NetworkID: openStackCluster.Status.ExternalNetwork.ID,
 		}
+		if gateway := openStackCluster.Spec.RouterExternalGateway; gateway != nil {
+			opts.GatewayInfo.EnableSNAT = gateway.EnableSNAT
+		}
 	}
 
-	router, err := s.client.CreateRouter(opts)
+	router, err := s.client.CreateRouter(s.scope.Context(), opts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateRouter", "Failed to create router %s: %v", name, err)
 		return nil, err
@@ -142,7 +225,7 @@ func (s *Service) createRouter(openStackCluster *infrav1.OpenStackCluster, clust
 	record.Eventf(openStackCluster, "SuccessfulCreateRouter", "Created router %s with id %s", name, router.ID)
 
 	if len(openStackCluster.Spec.Tags) > 0 {
-		_, err = s.client.ReplaceAllAttributesTags("routers", router.ID, attributestags.ReplaceAllOpts{
+		_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "routers", router.ID, attributestags.ReplaceAllOpts{
 			Tags: openStackCluster.Spec.Tags,
 		})
 		if err != nil {
@@ -159,6 +242,9 @@ func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluste
 			NetworkID: openStackCluster.Status.ExternalNetwork.ID,
 		},
 	}
+	if gateway := openStackCluster.Spec.RouterExternalGateway; gateway != nil {
+		updateOpts.GatewayInfo.EnableSNAT = gateway.EnableSNAT
+	}
 
 	for _, externalRouterIP := range openStackCluster.Spec.ExternalRouterIPs {
 		subnetID := externalRouterIP.Subnet.UUID
@@ -179,7 +265,7 @@ func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluste
 		})
 	}
 
-	_, err := s.client.UpdateRouter(router.ID, updateOpts)
+	_, err := s.client.UpdateRouter(s.scope.Context(), router.ID, updateOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to update router %s with id %s: %v", router.Name, router.ID, err)
 		return err
@@ -189,8 +275,60 @@ func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluste
 	return nil
 }
 
+// routerGatewayUpdateOpts updates the external gateway of a router including its QoS policy,
+// which routers.UpdateOpts does not support.
+type routerGatewayUpdateOpts struct {
+	GatewayInfo *clients.RouterGatewayInfo `json:"external_gateway_info"`
+}
+
+func (opts routerGatewayUpdateOpts) ToRouterUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "router")
+}
+
+// reconcileRouterExternalGateway applies the SNAT setting and the QoS policy of the spec to the
+// external gateway of the router if they differ. Settings which are not set in the spec are left
+// as they are.
+func (s *Service) reconcileRouterExternalGateway(openStackCluster *infrav1.OpenStackCluster, router *routers.Router) error {
+	gateway := openStackCluster.Spec.RouterExternalGateway
+
+	gatewayInfo, err := s.client.GetRouterGatewayInfo(s.scope.Context(), router.ID)
+	if err != nil {
+		return err
+	}
+	if gatewayInfo.NetworkID == "" {
+		s.scope.Logger.V(4).Info("Router has no external gateway yet", "routerID", router.ID)
+		return nil
+	}
+
+	needsUpdate := false
+	if gateway.EnableSNAT != nil && (gatewayInfo.EnableSNAT == nil || *gatewayInfo.EnableSNAT != *gateway.EnableSNAT) {
+		gatewayInfo.EnableSNAT = gateway.EnableSNAT
+		needsUpdate = true
+	}
+	if gateway.QoSPolicy != "" {
+		qosPolicyID, err := s.GetQoSPolicyID(gateway.QoSPolicy)
+		if err != nil {
+			return err
+		}
+		if gatewayInfo.QoSPolicyID != qosPolicyID {
+			gatewayInfo.QoSPolicyID = qosPolicyID
+			needsUpdate = true
+		}
+	}
+	if !needsUpdate {
+		return nil
+	}
+
+	if _, err := s.client.UpdateRouter(s.scope.Context(), router.ID, routerGatewayUpdateOpts{GatewayInfo: gatewayInfo}); err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to update external gateway of router %s with id %s: %v", router.Name, router.ID, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulUpdateRouter", "Updated external gateway of router %s with id %s", router.Name, router.ID)
+	return nil
+}
+
 func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
-	router, subnet, err := s.getRouter(clusterName)
+	router, subnet, err := s.getRouter(openStackCluster, clusterName)
 	if err != nil {
 		return err
 	}
@@ -199,8 +337,32 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clust
 		return nil
 	}
 
-	if subnet.ID != "" {
-		_, err = s.client.RemoveRouterInterface(router.ID, routers.RemoveInterfaceOpts{
+	subnetList := []subnets.Subnet{subnet}
+	if subnet.Name != "" {
+		ipv6Subnet, err := s.getSubnetByName(getIPv6SubnetName(subnet.Name))
+		if err != nil {
+			return err
+		}
+		subnetList = append(subnetList, ipv6Subnet)
+	}
+
+	subnetName, err := getSubnetName(openStackCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	for _, managedSubnet := range openStackCluster.Spec.ManagedSubnets {
+		subnet, err := s.getSubnetByName(getManagedSubnetName(subnetName, managedSubnet.Name))
+		if err != nil {
+			return err
+		}
+		subnetList = append(subnetList, subnet)
+	}
+
+	for _, subnet := range subnetList {
+		if subnet.ID == "" {
+			continue
+		}
+		_, err = s.client.RemoveRouterInterface(s.scope.Context(), router.ID, routers.RemoveInterfaceOpts{
 			SubnetID: subnet.ID,
 		})
 		if err != nil {
@@ -213,7 +375,12 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clust
 		}
 	}
 
-	err = s.client.DeleteRouter(router.ID)
+	if openStackCluster.Spec.Router != nil {
+		s.scope.Logger.Info("Not deleting existing router", "id", router.ID)
+		return nil
+	}
+
+	err = s.client.DeleteRouter(s.scope.Context(), router.ID)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedDeleteRouter", "Failed to delete router %s with id %s: %v", router.Name, router.ID, err)
 		return err
@@ -224,19 +391,34 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clust
 }
 
 func (s *Service) getRouterInterfaces(routerID string) ([]ports.Port, error) {
-	return s.client.ListPort(ports.ListOpts{
+	return s.client.ListPort(s.scope.Context(), ports.ListOpts{
 		DeviceID: routerID,
 	})
 }
 
-func (s *Service) getRouter(clusterName string) (routers.Router, subnets.Subnet, error) {
-	routerName := getRouterName(clusterName)
-	router, err := s.getRouterByName(routerName)
-	if err != nil {
-		return routers.Router{}, subnets.Subnet{}, err
+func (s *Service) getRouter(openStackCluster *infrav1.OpenStackCluster, clusterName string) (routers.Router, subnets.Subnet, error) {
+	var router routers.Router
+	if openStackCluster.Spec.Router != nil {
+		var err error
+		router, err = s.getRouterByFilter(openStackCluster.Spec.Router)
+		if err != nil {
+			return routers.Router{}, subnets.Subnet{}, err
+		}
+	} else {
+		routerName, err := getRouterName(openStackCluster, clusterName)
+		if err != nil {
+			return routers.Router{}, subnets.Subnet{}, err
+		}
+		router, err = s.getRouterByName(routerName)
+		if err != nil {
+			return routers.Router{}, subnets.Subnet{}, err
+		}
 	}
 
-	subnetName := getSubnetName(clusterName)
+	subnetName, err := getSubnetName(openStackCluster, clusterName)
+	if err != nil {
+		return router, subnets.Subnet{}, err
+	}
 	subnet, err := s.getSubnetByName(subnetName)
 	if err != nil {
 		return router, subnets.Subnet{}, err
@@ -246,7 +428,7 @@ func (s *Service) getRouter(clusterName string) (routers.Router, subnets.Subnet,
 }
 
 func (s *Service) getRouterByName(routerName string) (routers.Router, error) {
-	routerList, err := s.client.ListRouter(routers.ListOpts{
+	routerList, err := s.client.ListRouter(s.scope.Context(), routers.ListOpts{
 		Name: routerName,
 	})
 	if err != nil {
@@ -262,12 +444,28 @@ func (s *Service) getRouterByName(routerName string) (routers.Router, error) {
 	return routers.Router{}, fmt.Errorf("found %d router with the name %s, which should not happen", len(routerList), routerName)
 }
 
+// getRouterByFilter returns the router matching the filter, or an empty router if there is none.
+func (s *Service) getRouterByFilter(filter *infrav1.RouterFilter) (routers.Router, error) {
+	routerList, err := s.client.ListRouter(s.scope.Context(), filter.ToListOpt())
+	if err != nil {
+		return routers.Router{}, err
+	}
+
+	switch len(routerList) {
+	case 0:
+		return routers.Router{}, nil
+	case 1:
+		return routerList[0], nil
+	}
+	return routers.Router{}, fmt.Errorf("found %d routers matching the filters provided, expected exactly one", len(routerList))
+}
+
 func (s *Service) getSubnetByName(subnetName string) (subnets.Subnet, error) {
 	opts := subnets.ListOpts{
 		Name: subnetName,
 	}
 
-	subnetList, err := s.client.ListSubnet(opts)
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), opts)
 	if err != nil {
 		return subnets.Subnet{}, err
 	}
@@ -281,6 +479,7 @@ func (s *Service) getSubnetByName(subnetName string) (subnets.Subnet, error) {
 	return subnets.Subnet{}, fmt.Errorf("found %d subnets with the name %s, which should not happen", len(subnetList), subnetName)
 }
 
-func getRouterName(clusterName string) string {
-	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+func getRouterName(openStackCluster *infrav1.OpenStackCluster, clusterName string) (string, error) {
+	defaultName := fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+	return names.Render(getResourceNaming(openStackCluster).Router, defaultName, names.NewTemplateData(openStackCluster.Namespace, clusterName))
 }
@@ -0,0 +1,359 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	routerID          = "a6e01d3b-b1d4-4a5e-9d1a-3a0c1e4a0f6e"
+	clusterSubnetID   = "5b0e4ef3-5d9b-4bba-bc3c-2f7d0c0a5c1a"
+	clusterSubnetName = "k8s-clusterapi-cluster-test-cluster"
+)
+
+func Test_ReconcileRouter(t *testing.T) {
+	existingRouter := &infrav1.RouterFilter{Name: "shared-router"}
+
+	tests := []struct {
+		name            string
+		router          *infrav1.RouterFilter
+		externalNetwork *infrav1.Network
+		expect          func(m *mock.MockNetworkClientMockRecorder)
+		wantRouter      *infrav1.Router
+		wantErr         bool
+	}{
+		{
+			name:   "existing router is connected to the subnet",
+			router: existingRouter,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{{
+					ID:   routerID,
+					Name: "shared-router",
+					GatewayInfo: routers.GatewayInfo{
+						ExternalFixedIPs: []routers.ExternalFixedIP{{IPAddress: "203.0.113.10"}},
+					},
+				}}, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: routerID}).Return([]ports.Port{}, nil)
+				m.AddRouterInterface(gomock.Any(), routerID, routers.AddInterfaceOpts{SubnetID: clusterSubnetID}).Return(&routers.InterfaceInfo{}, nil)
+			},
+			wantRouter: &infrav1.Router{ID: routerID, Name: "shared-router", IPs: []string{"203.0.113.10"}},
+		},
+		{
+			name:   "existing router already connected to the subnet",
+			router: existingRouter,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{{ID: routerID, Name: "shared-router"}}, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: routerID}).Return([]ports.Port{{
+					FixedIPs: []ports.IP{{SubnetID: clusterSubnetID}},
+				}}, nil)
+			},
+			wantRouter: &infrav1.Router{ID: routerID, Name: "shared-router", IPs: []string{}},
+		},
+		{
+			name:   "existing router not found",
+			router: existingRouter,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "multiple existing routers found",
+			router: existingRouter,
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{{ID: routerID}, {ID: "other"}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "no router is created without an external network",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR: "10.6.0.0/24",
+					Router:   tt.router,
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					ExternalNetwork: tt.externalNetwork,
+					Network: &infrav1.Network{
+						ID:     "network-id",
+						Subnet: &infrav1.Subnet{ID: clusterSubnetID},
+					},
+				},
+			}
+			err := s.ReconcileRouter(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.Router).To(Equal(tt.wantRouter))
+		})
+	}
+}
+
+func Test_LookupRouter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expect     func(m *mock.MockNetworkClientMockRecorder)
+		wantRouter *infrav1.Router
+		wantErr    bool
+	}{
+		{
+			name: "router connected to the subnet",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{{
+					ID:   routerID,
+					Name: "shared-router",
+					GatewayInfo: routers.GatewayInfo{
+						ExternalFixedIPs: []routers.ExternalFixedIP{{IPAddress: "203.0.113.10"}},
+					},
+				}}, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: routerID}).Return([]ports.Port{{
+					FixedIPs: []ports.IP{{SubnetID: clusterSubnetID}},
+				}}, nil)
+			},
+			wantRouter: &infrav1.Router{ID: routerID, Name: "shared-router", IPs: []string{"203.0.113.10"}},
+		},
+		{
+			name: "router not connected to the subnet",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{{ID: routerID, Name: "shared-router"}}, nil)
+				m.ListPort(gomock.Any(), ports.ListOpts{DeviceID: routerID}).Return([]ports.Port{}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "router not found",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: "shared-router"}).Return([]routers.Router{}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ExternallyManagedNetwork: true,
+					Router:                   &infrav1.RouterFilter{Name: "shared-router"},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{
+						ID:     "network-id",
+						Subnet: &infrav1.Subnet{ID: clusterSubnetID},
+					},
+				},
+			}
+			err := s.LookupRouter(openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.Router).To(Equal(tt.wantRouter))
+		})
+	}
+}
+
+func Test_reconcileRouterExternalGateway(t *testing.T) {
+	const (
+		externalNetworkID = "7e1b1b5e-1c3c-4bfe-9a0f-6a0f2d5d6e4a"
+		qosPolicyID       = "0b9d2a8c-3c2f-4c55-9d44-1f3a2e6c7b8d"
+	)
+	enabled, disabled := true, false
+	externalFixedIPs := []routers.ExternalFixedIP{{IPAddress: "203.0.113.10", SubnetID: "external-subnet"}}
+
+	tests := []struct {
+		name    string
+		gateway infrav1.RouterExternalGateway
+		expect  func(m *mock.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name:    "SNAT is disabled",
+			gateway: infrav1.RouterExternalGateway{EnableSNAT: &disabled},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetRouterGatewayInfo(gomock.Any(), routerID).Return(&clients.RouterGatewayInfo{
+					NetworkID:        externalNetworkID,
+					EnableSNAT:       &enabled,
+					ExternalFixedIPs: externalFixedIPs,
+				}, nil)
+				m.UpdateRouter(gomock.Any(), routerID, routerGatewayUpdateOpts{GatewayInfo: &clients.RouterGatewayInfo{
+					NetworkID:        externalNetworkID,
+					EnableSNAT:       &disabled,
+					ExternalFixedIPs: externalFixedIPs,
+				}}).Return(&routers.Router{}, nil)
+			},
+		},
+		{
+			name:    "QoS policy is set by name",
+			gateway: infrav1.RouterExternalGateway{QoSPolicy: "egress-limit"},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetRouterGatewayInfo(gomock.Any(), routerID).Return(&clients.RouterGatewayInfo{
+					NetworkID:  externalNetworkID,
+					EnableSNAT: &enabled,
+				}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{ID: "egress-limit"}).Return([]policies.Policy{}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{Name: "egress-limit"}).Return([]policies.Policy{{ID: qosPolicyID}}, nil)
+				m.UpdateRouter(gomock.Any(), routerID, routerGatewayUpdateOpts{GatewayInfo: &clients.RouterGatewayInfo{
+					NetworkID:   externalNetworkID,
+					EnableSNAT:  &enabled,
+					QoSPolicyID: qosPolicyID,
+				}}).Return(&routers.Router{}, nil)
+			},
+		},
+		{
+			name:    "unchanged gateway is not updated",
+			gateway: infrav1.RouterExternalGateway{EnableSNAT: &disabled, QoSPolicy: qosPolicyID},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetRouterGatewayInfo(gomock.Any(), routerID).Return(&clients.RouterGatewayInfo{
+					NetworkID:   externalNetworkID,
+					EnableSNAT:  &disabled,
+					QoSPolicyID: qosPolicyID,
+				}, nil)
+				m.ListQoSPolicy(gomock.Any(), policies.ListOpts{ID: qosPolicyID}).Return([]policies.Policy{{ID: qosPolicyID}}, nil)
+			},
+		},
+		{
+			name:    "router without external gateway is not updated",
+			gateway: infrav1.RouterExternalGateway{EnableSNAT: &disabled},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetRouterGatewayInfo(gomock.Any(), routerID).Return(&clients.RouterGatewayInfo{}, nil)
+			},
+		},
+		{
+			name:    "unknown QoS policy",
+			gateway: infrav1.RouterExternalGateway{QoSPolicy: "missing"},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetRouterGatewayInfo(gomock.Any(), routerID).Return(&clients.RouterGatewayInfo{NetworkID: externalNetworkID}, nil)
+				m.ListQoSPolicy(gomock.Any(), gomock.Any()).Return([]policies.Policy{}, nil).Times(2)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			gateway := tt.gateway
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					RouterExternalGateway: &gateway,
+				},
+			}
+			err := s.reconcileRouterExternalGateway(openStackCluster, &routers.Router{ID: routerID, Name: "router"})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func Test_DeleteRouter(t *testing.T) {
+	tests := []struct {
+		name   string
+		router *infrav1.RouterFilter
+		expect func(m *mock.MockNetworkClientMockRecorder)
+	}{
+		{
+			name:   "existing router is disconnected but not deleted",
+			router: &infrav1.RouterFilter{ID: routerID},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{ID: routerID}).Return([]routers.Router{{ID: routerID, Name: "shared-router"}}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{Name: clusterSubnetName}).Return([]subnets.Subnet{{ID: clusterSubnetID, Name: clusterSubnetName}}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{Name: clusterSubnetName + "-ipv6"}).Return([]subnets.Subnet{}, nil)
+				m.RemoveRouterInterface(gomock.Any(), routerID, routers.RemoveInterfaceOpts{SubnetID: clusterSubnetID}).Return(&routers.InterfaceInfo{}, nil)
+			},
+		},
+		{
+			name: "managed router is deleted",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListRouter(gomock.Any(), routers.ListOpts{Name: clusterSubnetName}).Return([]routers.Router{{ID: routerID, Name: clusterSubnetName}}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{Name: clusterSubnetName}).Return([]subnets.Subnet{{ID: clusterSubnetID, Name: clusterSubnetName}}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{Name: clusterSubnetName + "-ipv6"}).Return([]subnets.Subnet{}, nil)
+				m.RemoveRouterInterface(gomock.Any(), routerID, routers.RemoveInterfaceOpts{SubnetID: clusterSubnetID}).Return(&routers.InterfaceInfo{}, nil)
+				m.DeleteRouter(gomock.Any(), routerID).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR: "10.6.0.0/24",
+					Router:   tt.router,
+				},
+			}
+			g.Expect(s.DeleteRouter(openStackCluster, "test-cluster")).To(Succeed())
+		})
+	}
+}
@@ -19,12 +19,14 @@ package networking
 import (
 	"fmt"
 
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
 )
 
 const (
@@ -67,6 +69,14 @@ func (s *Service) ReconcileSecurityGroups(openStackCluster *infrav1.OpenStackClu
 		return err
 	}
 
+	// The rules recorded in the status are the ones created by the OpenStack provider
+	trackedSecGroups := map[string]*infrav1.SecurityGroup{
+		controlPlaneSuffix: openStackCluster.Status.ControlPlaneSecurityGroup,
+		workerSuffix:       openStackCluster.Status.WorkerSecurityGroup,
+		bastionSuffix:      openStackCluster.Status.BastionSecurityGroup,
+	}
+	mergeRules := openStackCluster.Spec.ManagedSecurityGroupRulesPolicy == infrav1.SecurityGroupRulesPolicyMerge
+
 	observedSecGroups := make(map[string]*infrav1.SecurityGroup)
 	for k, desiredSecGroup := range desiredSecGroups {
 		var err error
@@ -77,7 +87,11 @@ func (s *Service) ReconcileSecurityGroups(openStackCluster *infrav1.OpenStackClu
 		}
 
 		if observedSecGroups[k].ID != "" {
-			observedSecGroup, err := s.reconcileGroupRules(desiredSecGroup, *observedSecGroups[k])
+			var ownedRuleIDs map[string]struct{}
+			if mergeRules {
+				ownedRuleIDs = getRuleIDs(trackedSecGroups[k])
+			}
+			observedSecGroup, err := s.reconcileGroupRules(openStackCluster, desiredSecGroup, *observedSecGroups[k], trackedSecGroups[k], ownedRuleIDs)
 			if err != nil {
 				return err
 			}
@@ -118,16 +132,19 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 	controlPlaneRules := append([]infrav1.SecurityGroupRule{}, defaultRules...)
 	workerRules := append([]infrav1.SecurityGroupRule{}, defaultRules...)
 
-	controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneHTTPS()...)
-	workerRules = append(workerRules, GetSGWorkerNodePort()...)
-
-	if openStackCluster.Spec.AllowAllInClusterTraffic {
-		// Permit all ingress from the cluster security groups
-		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneAllowAll(remoteGroupIDSelf, secWorkerGroupID)...)
-		workerRules = append(workerRules, GetSGWorkerAllowAll(remoteGroupIDSelf, secControlPlaneGroupID)...)
-	} else {
-		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneGeneral(remoteGroupIDSelf, secWorkerGroupID)...)
-		workerRules = append(workerRules, GetSGWorkerGeneral(remoteGroupIDSelf, secControlPlaneGroupID)...)
+	userRules := openStackCluster.Spec.ManagedSecurityGroupRules
+	if userRules == nil || !userRules.ReplaceDefaultRules {
+		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneHTTPS()...)
+		workerRules = append(workerRules, GetSGWorkerNodePort()...)
+
+		if openStackCluster.Spec.AllowAllInClusterTraffic {
+			// Permit all ingress from the cluster security groups
+			controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneAllowAll(remoteGroupIDSelf, secWorkerGroupID)...)
+			workerRules = append(workerRules, GetSGWorkerAllowAll(remoteGroupIDSelf, secControlPlaneGroupID)...)
+		} else {
+			controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneGeneral(remoteGroupIDSelf, secWorkerGroupID)...)
+			workerRules = append(workerRules, GetSGWorkerGeneral(remoteGroupIDSelf, secControlPlaneGroupID)...)
+		}
 	}
 
 	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
@@ -152,6 +169,43 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 		}
 	}
 
+	// Permit the same ingress over IPv6 in a dual-stack cluster network
+	if openStackCluster.Spec.NodeIPv6Subnet != nil {
+		controlPlaneRules = withIPv6Rules(controlPlaneRules)
+		workerRules = withIPv6Rules(workerRules)
+		if bastionGroup, ok := desiredSecGroups[bastionSuffix]; ok {
+			bastionGroup.Rules = withIPv6Rules(bastionGroup.Rules)
+			desiredSecGroups[bastionSuffix] = bastionGroup
+		}
+	}
+
+	// The user-defined rules are added as they are, without IPv6 copies
+	if userRules != nil {
+		groupIDs := map[infrav1.ManagedSecurityGroupName]string{
+			infrav1.ManagedSecurityGroupControlPlane: secControlPlaneGroupID,
+			infrav1.ManagedSecurityGroupWorker:       secWorkerGroupID,
+			infrav1.ManagedSecurityGroupBastion:      secBastionGroupID,
+		}
+		for _, groupRules := range []struct {
+			rules *[]infrav1.SecurityGroupRule
+			self  infrav1.ManagedSecurityGroupName
+			specs []infrav1.SecurityGroupRuleSpec
+		}{
+			{&controlPlaneRules, infrav1.ManagedSecurityGroupControlPlane, userRules.AllNodesSecurityGroupRules},
+			{&controlPlaneRules, infrav1.ManagedSecurityGroupControlPlane, userRules.ControlPlane},
+			{&workerRules, infrav1.ManagedSecurityGroupWorker, userRules.AllNodesSecurityGroupRules},
+			{&workerRules, infrav1.ManagedSecurityGroupWorker, userRules.Worker},
+		} {
+			rules, err := s.getSGUserRules(groupRules.specs, groupRules.self, groupIDs)
+			if err != nil {
+				return desiredSecGroups, err
+			}
+			*groupRules.rules = append(*groupRules.rules, rules...)
+		}
+		controlPlaneRules = uniqueRules(controlPlaneRules)
+		workerRules = uniqueRules(workerRules)
+	}
+
 	desiredSecGroups[controlPlaneSuffix] = infrav1.SecurityGroup{
 		Name:  secGroupNames[controlPlaneSuffix],
 		Rules: controlPlaneRules,
@@ -177,31 +231,57 @@ func (s *Service) GetSecurityGroups(securityGroupParams []infrav1.SecurityGroupP
 			continue
 		}
 
+		// The filter is passed to Neutron, with the name taking precedence over the name of the filter
 		listOpts := groups.ListOpts(sg.Filter)
 		if listOpts.ProjectID == "" {
 			listOpts.ProjectID = s.scope.ProjectID
 		}
-		listOpts.Name = sg.Name
-		listOpts.ID = sg.UUID
-		SGList, err := s.client.ListSecGroup(listOpts)
+		if sg.Name != "" {
+			listOpts.Name = sg.Name
+		}
+		ids, err := s.getSecurityGroupIDs(listOpts)
 		if err != nil {
 			return nil, err
 		}
 
-		if len(SGList) == 0 {
-			return nil, fmt.Errorf("security group %s not found", sg.Name)
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("no security group found for name %q and filter %+v", sg.Name, sg.Filter)
 		}
 
-		for _, group := range SGList {
-			if isDuplicate(sgIDs, group.ID) {
+		for _, id := range ids {
+			if isDuplicate(sgIDs, id) {
 				continue
 			}
-			sgIDs = append(sgIDs, group.ID)
+			sgIDs = append(sgIDs, id)
 		}
 	}
 	return sgIDs, nil
 }
 
+// getSecurityGroupIDs returns the IDs of the security groups which match listOpts, from the
+// lookup cache if it is enabled.
+func (s *Service) getSecurityGroupIDs(listOpts groups.ListOpts) ([]string, error) {
+	query, queryErr := gophercloud.BuildQueryString(listOpts)
+	if queryErr == nil {
+		if ids, ok := lookupcache.Get(lookupcache.SecurityGroup, s.scope.ProjectID, s.scope.RegionName(), query.String()); ok {
+			return ids.([]string), nil
+		}
+	}
+
+	SGList, err := s.client.ListSecGroup(s.scope.Context(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(SGList))
+	for _, group := range SGList {
+		ids = append(ids, group.ID)
+	}
+	if queryErr == nil && len(ids) > 0 {
+		lookupcache.Add(lookupcache.SecurityGroup, s.scope.ProjectID, s.scope.RegionName(), query.String(), ids)
+	}
+	return ids, nil
+}
+
 func (s *Service) DeleteSecurityGroups(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	secGroupNames := []string{
 		getSecControlPlaneGroupName(clusterName),
@@ -230,22 +310,42 @@ func (s *Service) deleteSecurityGroup(openStackCluster *infrav1.OpenStackCluster
 		// nothing to do
 		return nil
 	}
-	err = s.client.DeleteSecGroup(group.ID)
+	err = s.client.DeleteSecGroup(s.scope.Context(), group.ID)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedDeleteSecurityGroup", "Failed to delete security group %s with id %s: %v", group.Name, group.ID, err)
 		return err
 	}
 
 	record.Eventf(openStackCluster, "SuccessfulDeleteSecurityGroup", "Deleted security group %s with id %s", group.Name, group.ID)
+	lookupcache.Invalidate(lookupcache.SecurityGroup)
 	return nil
 }
 
+// getRuleIDs returns the IDs of the rules of the group, which may be nil.
+func getRuleIDs(group *infrav1.SecurityGroup) map[string]struct{} {
+	ruleIDs := map[string]struct{}{}
+	if group == nil {
+		return ruleIDs
+	}
+	for _, rule := range group.Rules {
+		ruleIDs[rule.ID] = struct{}{}
+	}
+	return ruleIDs
+}
+
 // reconcileGroupRules reconciles an already existing observed group by deleting rules not needed anymore and
-// creating rules that are missing.
-func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (infrav1.SecurityGroup, error) {
+// creating rules that are missing. If ownedRuleIDs is not nil, only the rules with these IDs are deleted, so
+// rules added by others are left intact. Drift from the tracked group of the last reconcile is reported with
+// events: rules which were deleted out of band are re-created, and rules which are not desired are pruned.
+func (s *Service) reconcileGroupRules(openStackCluster *infrav1.OpenStackCluster, desired, observed infrav1.SecurityGroup, tracked *infrav1.SecurityGroup, ownedRuleIDs map[string]struct{}) (infrav1.SecurityGroup, error) {
 	rulesToDelete := []infrav1.SecurityGroupRule{}
 	// fills rulesToDelete by calculating observed - desired
 	for _, observedRule := range observed.Rules {
+		if ownedRuleIDs != nil {
+			if _, ok := ownedRuleIDs[observedRule.ID]; !ok {
+				continue
+			}
+		}
 		deleteRule := true
 		for _, desiredRule := range desired.Rules {
 			r := desiredRule
@@ -288,10 +388,12 @@ func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (
 	s.scope.Logger.V(4).Info("Deleting rules not needed anymore for group", "name", observed.Name, "amount", len(rulesToDelete))
 	for _, rule := range rulesToDelete {
 		s.scope.Logger.V(6).Info("Deleting rule", "ruleID", rule.ID, "groupName", observed.Name)
-		err := s.client.DeleteSecGroupRule(rule.ID)
+		err := s.client.DeleteSecGroupRule(s.scope.Context(), rule.ID)
 		if err != nil {
+			record.Warnf(openStackCluster, "FailedDeleteSecurityGroupRule", "Failed to prune rule %s with id %s of security group %s: %v", describeRule(rule), rule.ID, observed.Name, err)
 			return infrav1.SecurityGroup{}, err
 		}
+		record.Eventf(openStackCluster, "SuccessfulDeleteSecurityGroupRule", "Pruned rule %s with id %s of security group %s", describeRule(rule), rule.ID, observed.Name)
 	}
 
 	s.scope.Logger.V(4).Info("Creating new rules needed for group", "name", observed.Name, "amount", len(rulesToCreate))
@@ -303,8 +405,12 @@ func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (
 		}
 		newRule, err := s.createRule(r)
 		if err != nil {
+			record.Warnf(openStackCluster, "FailedCreateSecurityGroupRule", "Failed to create rule %s in security group %s: %v", describeRule(r), observed.Name, err)
 			return infrav1.SecurityGroup{}, err
 		}
+		if tracked != nil && containsRule(tracked.Rules, r) {
+			record.Eventf(openStackCluster, "SuccessfulCreateSecurityGroupRule", "Re-created deleted rule %s of security group %s with id %s", describeRule(r), observed.Name, newRule.ID)
+		}
 		reconciledRules = append(reconciledRules, newRule)
 	}
 	observed.Rules = reconciledRules
@@ -313,11 +419,11 @@ func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (
 }
 
 func (s *Service) createSecurityGroupIfNotExists(openStackCluster *infrav1.OpenStackCluster, groupName string) error {
-	secGroup, err := s.getSecurityGroupByName(groupName)
+	secGroup, err := s.getSecGroupByName(groupName)
 	if err != nil {
 		return err
 	}
-	if secGroup == nil || secGroup.ID == "" {
+	if secGroup == nil {
 		s.scope.Logger.V(6).Info("Group doesn't exist, creating it.", "name", groupName)
 
 		createOpts := groups.CreateOpts{
@@ -326,14 +432,14 @@ func (s *Service) createSecurityGroupIfNotExists(openStackCluster *infrav1.OpenS
 		}
 		s.scope.Logger.V(6).Info("Creating group", "name", groupName)
 
-		group, err := s.client.CreateSecGroup(createOpts)
+		group, err := s.client.CreateSecGroup(s.scope.Context(), createOpts)
 		if err != nil {
 			record.Warnf(openStackCluster, "FailedCreateSecurityGroup", "Failed to create security group %s: %v", groupName, err)
 			return err
 		}
 
 		if len(openStackCluster.Spec.Tags) > 0 {
-			_, err = s.client.ReplaceAllAttributesTags("security-groups", group.ID, attributestags.ReplaceAllOpts{
+			_, err = s.client.ReplaceAllAttributesTags(s.scope.Context(), "security-groups", group.ID, attributestags.ReplaceAllOpts{
 				Tags: openStackCluster.Spec.Tags,
 			})
 			if err != nil {
@@ -348,28 +454,41 @@ func (s *Service) createSecurityGroupIfNotExists(openStackCluster *infrav1.OpenS
 	sInfo := fmt.Sprintf("Reuse Existing SecurityGroup %s with %s", groupName, secGroup.ID)
 	s.scope.Logger.V(6).Info(sInfo)
 
-	return nil
+	_, err = s.reconcileTags(openStackCluster, "security-groups", secGroup.ID, secGroup.Tags, openStackCluster.Spec.Tags)
+	return err
 }
 
 func (s *Service) getSecurityGroupByName(name string) (*infrav1.SecurityGroup, error) {
+	group, err := s.getSecGroupByName(name)
+	if err != nil {
+		return &infrav1.SecurityGroup{}, err
+	}
+	if group == nil {
+		return &infrav1.SecurityGroup{}, nil
+	}
+	return convertOSSecGroupToConfigSecGroup(*group), nil
+}
+
+// getSecGroupByName returns the security group with the given name, or nil if there is none.
+func (s *Service) getSecGroupByName(name string) (*groups.SecGroup, error) {
 	opts := groups.ListOpts{
 		Name: name,
 	}
 
 	s.scope.Logger.V(6).Info("Attempting to fetch security group with", "name", name)
-	allGroups, err := s.client.ListSecGroup(opts)
+	allGroups, err := s.client.ListSecGroup(s.scope.Context(), opts)
 	if err != nil {
-		return &infrav1.SecurityGroup{}, err
+		return nil, err
 	}
 
 	switch len(allGroups) {
 	case 0:
-		return &infrav1.SecurityGroup{}, nil
+		return nil, nil
 	case 1:
-		return convertOSSecGroupToConfigSecGroup(allGroups[0]), nil
+		return &allGroups[0], nil
 	}
 
-	return &infrav1.SecurityGroup{}, fmt.Errorf("more than one security group found named: %s", name)
+	return nil, fmt.Errorf("more than one security group found named: %s", name)
 }
 
 func (s *Service) createRule(r infrav1.SecurityGroupRule) (infrav1.SecurityGroupRule, error) {
@@ -389,7 +508,7 @@ func (s *Service) createRule(r infrav1.SecurityGroupRule) (infrav1.SecurityGroup
 		SecGroupID:     r.SecurityGroupID,
 	}
 	s.scope.Logger.V(6).Info("Creating rule", "Description", r.Description, "Direction", dir, "PortRangeMin", r.PortRangeMin, "PortRangeMax", r.PortRangeMax, "Proto", proto, "etherType", etherType, "RemoteGroupID", r.RemoteGroupID, "RemoteIPPrefix", r.RemoteIPPrefix, "SecurityGroupID", r.SecurityGroupID)
-	rule, err := s.client.CreateSecGroupRule(createOpts)
+	rule, err := s.client.CreateSecGroupRule(s.scope.Context(), createOpts)
 	if err != nil {
 		return infrav1.SecurityGroupRule{}, err
 	}
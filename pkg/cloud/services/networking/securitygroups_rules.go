@@ -17,6 +17,9 @@ limitations under the License.
 package networking
 
 import (
+	"fmt"
+	"net"
+
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 )
 
@@ -41,6 +44,124 @@ var defaultRules = []infrav1.SecurityGroupRule{
 	},
 }
 
+// withIPv6Rules returns the rules with an IPv6 copy of each IPv4 ingress rule which does not
+// restrict the remote IP prefix. IP-in-IP encapsulation is only used over IPv4.
+func withIPv6Rules(rules []infrav1.SecurityGroupRule) []infrav1.SecurityGroupRule {
+	ipv6Rules := make([]infrav1.SecurityGroupRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Direction == "ingress" && rule.EtherType == "IPv4" && rule.RemoteIPPrefix == "" && rule.Protocol != "ipip" {
+			rule.EtherType = "IPv6"
+			ipv6Rules = append(ipv6Rules, rule)
+		}
+	}
+	return append(rules, ipv6Rules...)
+}
+
+// getSGUserRules returns the rules of a managed group for the user-defined rules. A remote managed group is
+// referenced by its ID, or by remoteGroupIDSelf if it is the group itself. References to a managed group which
+// does not exist, like the bastion group while the bastion is disabled, are skipped, while remote security
+// groups which are not found are an error.
+func (s *Service) getSGUserRules(specs []infrav1.SecurityGroupRuleSpec, self infrav1.ManagedSecurityGroupName, groupIDs map[infrav1.ManagedSecurityGroupName]string) ([]infrav1.SecurityGroupRule, error) {
+	var userRules []infrav1.SecurityGroupRule
+	for _, spec := range specs {
+		remoteGroupIDs, err := s.GetSecurityGroups(spec.RemoteSecurityGroups)
+		if err != nil {
+			return nil, err
+		}
+
+		rule := infrav1.SecurityGroupRule{
+			Description:    spec.Description,
+			Direction:      spec.Direction,
+			EtherType:      spec.EtherType,
+			PortRangeMin:   spec.PortRangeMin,
+			PortRangeMax:   spec.PortRangeMax,
+			Protocol:       spec.Protocol,
+			RemoteIPPrefix: spec.RemoteIPPrefix,
+		}
+		if rule.EtherType == "" {
+			rule.EtherType = "IPv4"
+			if ip, _, err := net.ParseCIDR(rule.RemoteIPPrefix); err == nil && ip.To4() == nil {
+				rule.EtherType = "IPv6"
+			}
+		}
+		if rule.PortRangeMax == 0 {
+			rule.PortRangeMax = rule.PortRangeMin
+		}
+
+		if len(spec.RemoteManagedGroups) == 0 && len(remoteGroupIDs) == 0 {
+			userRules = append(userRules, rule)
+			continue
+		}
+		for _, group := range spec.RemoteManagedGroups {
+			r := rule
+			switch {
+			case group == self:
+				r.RemoteGroupID = remoteGroupIDSelf
+			case groupIDs[group] != "":
+				r.RemoteGroupID = groupIDs[group]
+			default:
+				continue
+			}
+			userRules = append(userRules, r)
+		}
+		for _, id := range remoteGroupIDs {
+			r := rule
+			r.RemoteGroupID = id
+			userRules = append(userRules, r)
+		}
+	}
+	return userRules, nil
+}
+
+// containsRule returns true if rules contain a rule equal to rule.
+func containsRule(rules []infrav1.SecurityGroupRule, rule infrav1.SecurityGroupRule) bool {
+	for _, r := range rules {
+		if r.Equal(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRule returns a short description of a rule for events, e.g. "ingress IPv4 tcp 6443-6443 from any (Kubernetes API)".
+func describeRule(rule infrav1.SecurityGroupRule) string {
+	protocol := rule.Protocol
+	if protocol == "" {
+		protocol = "any"
+	}
+	remote := "any"
+	switch {
+	case rule.RemoteIPPrefix != "":
+		remote = rule.RemoteIPPrefix
+	case rule.RemoteGroupID != "":
+		remote = "group " + rule.RemoteGroupID
+	}
+	preposition := "from"
+	if rule.Direction == "egress" {
+		preposition = "to"
+	}
+	description := fmt.Sprintf("%s %s %s", rule.Direction, rule.EtherType, protocol)
+	if rule.PortRangeMin != 0 || rule.PortRangeMax != 0 {
+		description += fmt.Sprintf(" %d-%d", rule.PortRangeMin, rule.PortRangeMax)
+	}
+	description += fmt.Sprintf(" %s %s", preposition, remote)
+	if rule.Description != "" {
+		description += fmt.Sprintf(" (%s)", rule.Description)
+	}
+	return description
+}
+
+// uniqueRules returns the rules without duplicates, which Neutron refuses to create.
+func uniqueRules(rules []infrav1.SecurityGroupRule) []infrav1.SecurityGroupRule {
+	unique := make([]infrav1.SecurityGroupRule, 0, len(rules))
+	for _, rule := range rules {
+		if !containsRule(unique, rule) {
+			unique = append(unique, rule)
+		}
+	}
+	return unique
+}
+
 // Permit traffic for etcd, kubelet.
 func getSGControlPlaneCommon(remoteGroupIDSelf, secWorkerGroupID string) []infrav1.SecurityGroupRule {
 	return []infrav1.SecurityGroupRule{
@@ -0,0 +1,288 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/lookupcache"
+)
+
+func Test_reconcileGroupRules(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const groupID = "a9fb6be3-1b3d-4c8a-9b2f-8e34a6d2f2c1"
+
+	sshRule := infrav1.SecurityGroupRule{
+		Description:     "SSH",
+		Direction:       "ingress",
+		EtherType:       "IPv4",
+		PortRangeMin:    22,
+		PortRangeMax:    22,
+		Protocol:        "tcp",
+		SecurityGroupID: groupID,
+	}
+	apiServerRule := infrav1.SecurityGroupRule{
+		Description:     "Kubernetes API",
+		Direction:       "ingress",
+		EtherType:       "IPv4",
+		PortRangeMin:    6443,
+		PortRangeMax:    6443,
+		Protocol:        "tcp",
+		SecurityGroupID: groupID,
+	}
+	withID := func(rule infrav1.SecurityGroupRule, id string) infrav1.SecurityGroupRule {
+		rule.ID = id
+		return rule
+	}
+	// The previous desired rule was replaced by the API server rule, and an operator added a rule
+	previousRule := withID(sshRule, "previous-rule")
+	operatorRule := withID(infrav1.SecurityGroupRule{
+		Description:     "Monitoring",
+		Direction:       "ingress",
+		EtherType:       "IPv4",
+		PortRangeMin:    9100,
+		PortRangeMax:    9100,
+		Protocol:        "tcp",
+		SecurityGroupID: groupID,
+	}, "operator-rule")
+
+	desired := infrav1.SecurityGroup{
+		Name:  "k8s-cluster-test-secgroup-controlplane",
+		Rules: []infrav1.SecurityGroupRule{apiServerRule},
+	}
+	observed := infrav1.SecurityGroup{
+		Name:  "k8s-cluster-test-secgroup-controlplane",
+		ID:    groupID,
+		Rules: []infrav1.SecurityGroupRule{previousRule, operatorRule},
+	}
+
+	expectCreateAPIServerRule := func(m *mock.MockNetworkClientMockRecorder) {
+		m.CreateSecGroupRule(gomock.Any(), rules.CreateOpts{
+			Description:  "Kubernetes API",
+			Direction:    rules.DirIngress,
+			EtherType:    rules.EtherType4,
+			PortRangeMin: 6443,
+			PortRangeMax: 6443,
+			Protocol:     rules.ProtocolTCP,
+			SecGroupID:   groupID,
+		}).Return(&rules.SecGroupRule{
+			ID:           "api-server-rule",
+			Description:  "Kubernetes API",
+			Direction:    "ingress",
+			EtherType:    "IPv4",
+			PortRangeMin: 6443,
+			PortRangeMax: 6443,
+			Protocol:     "tcp",
+			SecGroupID:   groupID,
+		}, nil)
+	}
+
+	tests := []struct {
+		name         string
+		tracked      *infrav1.SecurityGroup
+		ownedRuleIDs map[string]struct{}
+		expect       func(m *mock.MockNetworkClientMockRecorder)
+	}{
+		{
+			name: "Replace deletes all rules which are not desired",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.DeleteSecGroupRule(gomock.Any(), "previous-rule").Return(nil)
+				m.DeleteSecGroupRule(gomock.Any(), "operator-rule").Return(nil)
+				expectCreateAPIServerRule(m)
+			},
+		},
+		{
+			name:         "Merge deletes only owned rules which are not desired",
+			ownedRuleIDs: map[string]struct{}{"previous-rule": {}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.DeleteSecGroupRule(gomock.Any(), "previous-rule").Return(nil)
+				expectCreateAPIServerRule(m)
+			},
+		},
+		{
+			name:         "Merge without owned rules deletes nothing",
+			ownedRuleIDs: map[string]struct{}{},
+			expect:       expectCreateAPIServerRule,
+		},
+		{
+			name: "A tracked rule which was deleted out of band is re-created",
+			tracked: &infrav1.SecurityGroup{
+				Name:  "k8s-cluster-test-secgroup-controlplane",
+				ID:    groupID,
+				Rules: []infrav1.SecurityGroupRule{withID(apiServerRule, "deleted-rule")},
+			},
+			ownedRuleIDs: map[string]struct{}{"deleted-rule": {}},
+			expect:       expectCreateAPIServerRule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+
+			got, err := s.reconcileGroupRules(&infrav1.OpenStackCluster{}, desired, observed, tt.tracked, tt.ownedRuleIDs)
+			g.Expect(err).NotTo(HaveOccurred())
+			// Only the rules of the provider are reported
+			g.Expect(got.Rules).To(Equal([]infrav1.SecurityGroupRule{withID(apiServerRule, "api-server-rule")}))
+		})
+	}
+}
+
+func TestService_GetSecurityGroups_LookupCache(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	lookupcache.Configure(time.Minute)
+	defer lookupcache.Configure(0)
+
+	const groupID = "a9fb6be3-1b3d-4c8a-9b2f-8e34a6d2f2c1"
+	listOpts := groups.ListOpts{Name: "default", ProjectID: "project"}
+
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListSecGroup(gomock.Any(), listOpts).Return([]groups.SecGroup{{ID: groupID, Name: "default"}}, nil).Times(2)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard(), ProjectID: "project"},
+	}
+
+	params := []infrav1.SecurityGroupParam{{Name: "default"}}
+	for i := 0; i < 3; i++ {
+		ids, err := s.GetSecurityGroups(params)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ids).To(Equal([]string{groupID}))
+	}
+
+	// A port which is not created because a resolved resource was not found invalidates the lookups
+	g.Expect(lookupcache.InvalidateOnNotFound(gophercloud.ErrDefault404{}, lookupcache.SecurityGroup)).To(HaveOccurred())
+	ids, err := s.GetSecurityGroups(params)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ids).To(Equal([]string{groupID}))
+}
+
+func Test_getSGUserRules(t *testing.T) {
+	groupIDs := map[infrav1.ManagedSecurityGroupName]string{
+		infrav1.ManagedSecurityGroupControlPlane: "controlplane-id",
+		infrav1.ManagedSecurityGroupWorker:       "worker-id",
+	}
+	specs := []infrav1.SecurityGroupRuleSpec{
+		{
+			Description:         "VXLAN",
+			Direction:           "ingress",
+			Protocol:            "udp",
+			PortRangeMin:        8472,
+			RemoteManagedGroups: []infrav1.ManagedSecurityGroupName{infrav1.ManagedSecurityGroupControlPlane, infrav1.ManagedSecurityGroupWorker, infrav1.ManagedSecurityGroupBastion},
+		},
+		{
+			Description:    "NodePort",
+			Direction:      "ingress",
+			Protocol:       "tcp",
+			PortRangeMin:   30000,
+			PortRangeMax:   32767,
+			RemoteIPPrefix: "2001:db8::/32",
+		},
+	}
+
+	g := NewWithT(t)
+	got, err := (&Service{}).getSGUserRules(specs, infrav1.ManagedSecurityGroupWorker, groupIDs)
+	g.Expect(err).NotTo(HaveOccurred())
+	// The bastion group does not exist, so no rule is created for it
+	g.Expect(got).To(Equal([]infrav1.SecurityGroupRule{
+		{
+			Description:   "VXLAN",
+			Direction:     "ingress",
+			EtherType:     "IPv4",
+			PortRangeMin:  8472,
+			PortRangeMax:  8472,
+			Protocol:      "udp",
+			RemoteGroupID: "controlplane-id",
+		},
+		{
+			Description:   "VXLAN",
+			Direction:     "ingress",
+			EtherType:     "IPv4",
+			PortRangeMin:  8472,
+			PortRangeMax:  8472,
+			Protocol:      "udp",
+			RemoteGroupID: remoteGroupIDSelf,
+		},
+		{
+			Description:    "NodePort",
+			Direction:      "ingress",
+			EtherType:      "IPv6",
+			PortRangeMin:   30000,
+			PortRangeMax:   32767,
+			Protocol:       "tcp",
+			RemoteIPPrefix: "2001:db8::/32",
+		},
+	}))
+	g.Expect(uniqueRules(append(got, got...))).To(Equal(got))
+}
+
+func Test_getSGUserRules_RemoteSecurityGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	specs := []infrav1.SecurityGroupRuleSpec{
+		{
+			Direction: "ingress",
+			Protocol:  "tcp",
+			RemoteSecurityGroups: []infrav1.SecurityGroupParam{
+				{Filter: infrav1.SecurityGroupFilter{Tags: "monitoring"}},
+			},
+		},
+	}
+
+	g := NewWithT(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	// The filter is passed to Neutron, and a rule is created for every group found
+	mockClient.EXPECT().ListSecGroup(gomock.Any(), groups.ListOpts{Tags: "monitoring", ProjectID: "project"}).Return([]groups.SecGroup{{ID: "prometheus"}, {ID: "grafana"}}, nil)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard(), ProjectID: "project"},
+	}
+
+	got, err := s.getSGUserRules(specs, infrav1.ManagedSecurityGroupWorker, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]infrav1.SecurityGroupRule{
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", RemoteGroupID: "prometheus"},
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", RemoteGroupID: "grafana"},
+	}))
+
+	// A rule must not be opened to any remote if its remote security groups are not found
+	mockClient.EXPECT().ListSecGroup(gomock.Any(), gomock.Any()).Return(nil, nil)
+	_, err = s.getSGUserRules(specs, infrav1.ManagedSecurityGroupWorker, nil)
+	g.Expect(err).To(HaveOccurred())
+}
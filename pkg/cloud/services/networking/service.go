@@ -24,15 +24,18 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 )
 
 const (
 	networkPrefix string = "k8s-clusterapi"
 	trunkResource string = "trunks"
 	portResource  string = "ports"
+	fipResource   string = "floatingips"
 )
 
 // Service interfaces with the OpenStack Networking API.
@@ -42,6 +45,10 @@ type Service struct {
 	client clients.NetworkClient
 }
 
+// revisionConflictRetries is how often an update which is based on the revision of a Neutron
+// resource is attempted when the resource is modified concurrently.
+const revisionConflictRetries = 3
+
 // NewService returns an instance of the networking service.
 func NewService(scope *scope.Scope) (*Service, error) {
 	networkClient, err := clients.NewNetworkClient(scope)
@@ -95,7 +102,7 @@ func (s *Service) replaceAllAttributesTags(eventObject runtime.Object, resourceT
 	// Sort the tags so that we always get fixed order of tags to make UT easier
 	sort.Strings(uniqueTags)
 
-	_, err := s.client.ReplaceAllAttributesTags(resourceType, resourceID, attributestags.ReplaceAllOpts{
+	_, err := s.client.ReplaceAllAttributesTags(s.scope.Context(), resourceType, resourceID, attributestags.ReplaceAllOpts{
 		Tags: uniqueTags,
 	})
 	if err != nil {
@@ -106,3 +113,26 @@ func (s *Service) replaceAllAttributesTags(eventObject runtime.Object, resourceT
 	record.Eventf(eventObject, "SuccessfulReplaceAllAttributeTags", "Replaced all attributestags for %s with tags %s", resourceID, uniqueTags)
 	return nil
 }
+
+// getResourceNaming returns the resource naming templates of the cluster.
+func getResourceNaming(openStackCluster *infrav1.OpenStackCluster) infrav1.ResourceNaming {
+	if openStackCluster.Spec.ResourceNaming == nil {
+		return infrav1.ResourceNaming{}
+	}
+	return *openStackCluster.Spec.ResourceNaming
+}
+
+// retryOnRevisionConflict calls update, which reads a resource and updates it based on the revision
+// it read, until the update is not rejected because the resource was modified concurrently, e.g. by
+// another controller replica or an external tool, or the attempts are exhausted.
+func (s *Service) retryOnRevisionConflict(resourceType, resourceID string, update func() error) error {
+	var err error
+	for i := 0; i < revisionConflictRetries; i++ {
+		err = update()
+		if !capoerrors.IsPreconditionFailed(err) {
+			return err
+		}
+		s.scope.Logger.Info("Resource was modified concurrently, retrying update", "resourceType", resourceType, "resourceID", resourceID)
+	}
+	return fmt.Errorf("%s %s was modified concurrently: %w", resourceType, resourceID, err)
+}
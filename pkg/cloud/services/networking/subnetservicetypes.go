@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+)
+
+const subnetServiceTypesExtension = "subnet-service-types"
+
+// serviceTypesCreateOpts adds the service types of the subnet-service-types
+// extension, which are not supported by gophercloud, to the subnet create request.
+type serviceTypesCreateOpts struct {
+	subnets.CreateOptsBuilder
+	ServiceTypes []string
+}
+
+func (opts serviceTypesCreateOpts) ToSubnetCreateMap() (map[string]interface{}, error) {
+	b, err := opts.CreateOptsBuilder.ToSubnetCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	subnet := b["subnet"].(map[string]interface{})
+	subnet["service_types"] = opts.ServiceTypes
+	return b, nil
+}
+
+// serviceTypesUpdateOpts adds the service types of the subnet-service-types
+// extension to the subnet update request.
+type serviceTypesUpdateOpts struct {
+	subnets.UpdateOptsBuilder
+	ServiceTypes []string
+}
+
+func (opts serviceTypesUpdateOpts) ToSubnetUpdateMap() (map[string]interface{}, error) {
+	b, err := opts.UpdateOptsBuilder.ToSubnetUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	subnet := b["subnet"].(map[string]interface{})
+	// An empty list removes the service types from the subnet
+	serviceTypes := opts.ServiceTypes
+	if serviceTypes == nil {
+		serviceTypes = []string{}
+	}
+	subnet["service_types"] = serviceTypes
+	return b, nil
+}
+
+// checkSubnetServiceTypesSupport returns an error if Neutron does not support
+// subnet service types, as it would reject the request with an unrecognised attribute.
+func (s *Service) checkSubnetServiceTypesSupport() error {
+	allExts, err := s.client.ListExtensions(s.scope.Context())
+	if err != nil {
+		return err
+	}
+	for _, ext := range allExts {
+		if ext.Alias == subnetServiceTypesExtension {
+			return nil
+		}
+	}
+	return fmt.Errorf("subnet service types require the %s extension, which is not supported by the networking service", subnetServiceTypesExtension)
+}
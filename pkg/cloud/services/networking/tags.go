@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// missingTags returns the desired tags which are not in the current tags.
+func missingTags(current, desired []string) []string {
+	have := make(map[string]struct{}, len(current))
+	for _, tag := range current {
+		have[tag] = struct{}{}
+	}
+	var missing []string
+	for _, tag := range desired {
+		if _, ok := have[tag]; !ok {
+			have[tag] = struct{}{}
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
+
+// reconcileTags adds the desired tags which a Neutron resource lacks, e.g. because they were added to the spec after
+// the resource was created. Tags which are not desired are kept, as they may have been set by other tools. It returns
+// the tags of the resource.
+func (s *Service) reconcileTags(eventObject runtime.Object, resourceType, resourceID string, current, desired []string) ([]string, error) {
+	missing := missingTags(current, desired)
+	if len(missing) == 0 {
+		return current, nil
+	}
+
+	tags := append(append([]string{}, current...), missing...)
+	sort.Strings(tags)
+	s.scope.Logger.Info("Adding missing tags", "resourceType", resourceType, "resourceID", resourceID, "tags", missing)
+	if _, err := s.client.ReplaceAllAttributesTags(s.scope.Context(), resourceType, resourceID, attributestags.ReplaceAllOpts{Tags: tags}); err != nil {
+		record.Warnf(eventObject, "FailedUpdateTags", "Failed to add tags %v to %s %s: %v", missing, resourceType, resourceID, err)
+		return nil, err
+	}
+	record.Eventf(eventObject, "SuccessfulUpdateTags", "Added tags %v to %s %s", missing, resourceType, resourceID)
+	return tags, nil
+}
+
+// ReconcileInstancePortTags adds the given tags to the ports of an instance which lack them and, if trunk is set,
+// to the trunks of the ports.
+func (s *Service) ReconcileInstancePortTags(eventObject runtime.Object, instanceID string, tags []string, trunk bool) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{DeviceID: instanceID})
+	if err != nil {
+		return fmt.Errorf("searching for ports of instance %s: %v", instanceID, err)
+	}
+	for i := range portList {
+		port := &portList[i]
+		if _, err := s.reconcileTags(eventObject, portResource, port.ID, port.Tags, tags); err != nil {
+			return err
+		}
+		if !trunk {
+			continue
+		}
+
+		trunkList, err := s.client.ListTrunk(s.scope.Context(), trunks.ListOpts{PortID: port.ID})
+		if err != nil {
+			return fmt.Errorf("searching for trunk of port %s: %v", port.ID, err)
+		}
+		for j := range trunkList {
+			if _, err := s.reconcileTags(eventObject, trunkResource, trunkList[j].ID, trunkList[j].Tags, tags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -21,12 +21,14 @@ import (
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/cluster-api/util"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/retry"
 )
 
 const (
@@ -35,7 +37,7 @@ const (
 )
 
 func (s *Service) GetTrunkSupport() (bool, error) {
-	allExts, err := s.client.ListExtensions()
+	allExts, err := s.client.ListExtensions(s.scope.Context())
 	if err != nil {
 		return false, err
 	}
@@ -49,7 +51,7 @@ func (s *Service) GetTrunkSupport() (bool, error) {
 }
 
 func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trunkName, portID string) (*trunks.Trunk, error) {
-	trunkList, err := s.client.ListTrunk(trunks.ListOpts{
+	trunkList, err := s.client.ListTrunk(s.scope.Context(), trunks.ListOpts{
 		Name:   trunkName,
 		PortID: portID,
 	})
@@ -67,7 +69,7 @@ func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trun
 		Description: names.GetDescription(clusterName),
 	}
 
-	trunk, err := s.client.CreateTrunk(trunkCreateOpts)
+	trunk, err := s.client.CreateTrunk(s.scope.Context(), trunkCreateOpts)
 	if err != nil {
 		record.Warnf(eventObject, "FailedCreateTrunk", "Failed to create trunk %s: %v", trunkName, err)
 		return nil, err
@@ -77,20 +79,101 @@ func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trun
 	return trunk, nil
 }
 
-func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string) error {
+// getSubportName returns the name of the port CAPO creates for a subport of the given trunk.
+func getSubportName(trunkName, segmentationType string, segmentationID int) string {
+	return fmt.Sprintf("%s-%s-%d", trunkName, segmentationType, segmentationID)
+}
+
+// reconcileSubports creates the ports of the given subports and attaches them to
+// the trunk. Subports which are not in the spec are left alone, as they may have
+// been added by other agents such as Kuryr.
+func (s *Service) reconcileSubports(eventObject runtime.Object, clusterName string, trunk *trunks.Trunk, subports []infrav1.SubportOpts, instanceSecurityGroups *[]string, tags []string) error {
+	attached := make(map[int]trunks.Subport, len(trunk.Subports))
+	for _, subport := range trunk.Subports {
+		attached[subport.SegmentationID] = subport
+	}
+
+	var missing []trunks.Subport
+	for i := range subports {
+		subport := &subports[i]
+		segmentationType := string(subport.SegmentationType)
+		portName := getSubportName(trunk.Name, segmentationType, subport.SegmentationID)
+
+		netID := subport.Network.ID
+		if netID == "" {
+			netIDs, err := s.GetNetworkIDsByFilter(subport.Network.ToListOpt())
+			if err != nil {
+				return err
+			}
+			if len(netIDs) != 1 {
+				return fmt.Errorf("network filter for subport %s returns %d networks, expected 1", portName, len(netIDs))
+			}
+			netID = netIDs[0]
+		}
+
+		portOpts := &infrav1.PortOpts{}
+		if subport.Subnet != nil {
+			portOpts.FixedIPs = []infrav1.FixedIP{{Subnet: subport.Subnet}}
+		}
+		net := infrav1.Network{
+			ID:       netID,
+			Subnet:   &infrav1.Subnet{},
+			PortOpts: portOpts,
+		}
+		port, err := s.GetOrCreatePort(eventObject, clusterName, portName, net, instanceSecurityGroups, tags)
+		if err != nil {
+			return err
+		}
+
+		if existing, ok := attached[subport.SegmentationID]; ok {
+			if existing.PortID != port.ID {
+				return fmt.Errorf("segmentation id %d of trunk %s is already used by port %s", subport.SegmentationID, trunk.Name, existing.PortID)
+			}
+			continue
+		}
+		missing = append(missing, trunks.Subport{
+			PortID:           port.ID,
+			SegmentationType: segmentationType,
+			SegmentationID:   subport.SegmentationID,
+		})
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if _, err := s.client.AddSubports(s.scope.Context(), trunk.ID, trunks.AddSubportsOpts{Subports: missing}); err != nil {
+		record.Warnf(eventObject, "FailedAddSubports", "Failed to add subports to trunk %s with id %s: %v", trunk.Name, trunk.ID, err)
+		return err
+	}
+
+	record.Eventf(eventObject, "SuccessfulAddSubports", "Added %d subports to trunk %s with id %s", len(missing), trunk.Name, trunk.ID)
+	return nil
+}
+
+// DeleteTrunk deletes the trunk of the given port and the ports CAPO created for its subports. subports are the
+// subports declared for the ports of the instance: if the trunk was deleted by a previous attempt which failed to
+// delete the ports of its subports, these ports are looked up by name.
+func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string, subports []infrav1.SubportOpts) error {
 	listOpts := trunks.ListOpts{
 		PortID: portID,
 	}
-	trunkInfo, err := s.client.ListTrunk(listOpts)
+	trunkInfo, err := s.client.ListTrunk(s.scope.Context(), listOpts)
 	if err != nil {
 		return err
 	}
+	if len(trunkInfo) == 0 {
+		if len(subports) == 0 {
+			return nil
+		}
+		return s.deleteDetachedSubportPorts(eventObject, portID, subports)
+	}
 	if len(trunkInfo) != 1 {
 		return nil
 	}
 
-	err = util.PollImmediate(retryIntervalTrunkDelete, timeoutTrunkDelete, func() (bool, error) {
-		if err := s.client.DeleteTrunk(trunkInfo[0].ID); err != nil {
+	err = retry.Wait(s.scope.Context(), retry.Network, retry.Policy{Interval: retryIntervalTrunkDelete, Timeout: timeoutTrunkDelete}, func() (bool, error) {
+		if err := s.client.DeleteTrunk(s.scope.Context(), trunkInfo[0].ID); err != nil {
 			if capoerrors.IsNotFound(err) {
 				record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Trunk %s with id %s did not exist", trunkInfo[0].Name, trunkInfo[0].ID)
 				return true, nil
@@ -111,5 +194,61 @@ func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string) error {
 	}
 
 	record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Deleted trunk %s with id %s", trunkInfo[0].Name, trunkInfo[0].ID)
+	return s.deleteSubportPorts(eventObject, &trunkInfo[0])
+}
+
+// deleteDetachedSubportPorts deletes the ports which CAPO created for the given subports of the deleted trunk of the
+// given parent port, and which are no longer attached to it. They are found by their names, which start with the
+// name of the trunk and thus of the parent port, and by the description of the parent port, which is the description
+// of the cluster the subport ports are created with unless the parent port has a custom description.
+func (s *Service) deleteDetachedSubportPorts(eventObject runtime.Object, parentPortID string, subports []infrav1.SubportOpts) error {
+	parent, err := s.client.GetPort(s.scope.Context(), parentPortID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if parent.Name == "" || parent.Description == "" {
+		return nil
+	}
+
+	for i := range subports {
+		portName := getSubportName(parent.Name, string(subports[i].SegmentationType), subports[i].SegmentationID)
+		portList, err := s.client.ListPort(s.scope.Context(), ports.ListOpts{Name: portName, Description: parent.Description})
+		if err != nil {
+			return err
+		}
+		for _, port := range portList {
+			// Ports attached to another trunk are not left behind by this one
+			if port.DeviceOwner != "" {
+				continue
+			}
+			if err := s.DeletePort(eventObject, port.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteSubportPorts deletes the ports which CAPO created for the subports of
+// the given trunk. Ports of subports added by other agents are left alone.
+func (s *Service) deleteSubportPorts(eventObject runtime.Object, trunk *trunks.Trunk) error {
+	for _, subport := range trunk.Subports {
+		port, err := s.client.GetPort(s.scope.Context(), subport.PortID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if port.Name != getSubportName(trunk.Name, subport.SegmentationType, subport.SegmentationID) {
+			continue
+		}
+		if err := s.DeletePort(eventObject, port.ID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -17,14 +17,18 @@ limitations under the License.
 package networking
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	. "github.com/onsi/gomega"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
 func Test_GetOrCreateTrunk(t *testing.T) {
@@ -47,7 +51,7 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 			"port-1",
 			func(m *mock.MockNetworkClientMockRecorder) {
 				m.
-					ListTrunk(trunks.ListOpts{
+					ListTrunk(gomock.Any(), trunks.ListOpts{
 						Name:   "trunk-1",
 						PortID: "port-1",
 					}).Return([]trunks.Trunk{{
@@ -65,12 +69,12 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 			func(m *mock.MockNetworkClientMockRecorder) {
 				// No ports found
 				m.
-					ListTrunk(trunks.ListOpts{
+					ListTrunk(gomock.Any(), trunks.ListOpts{
 						Name:   "trunk-1",
 						PortID: "port-1",
 					}).Return([]trunks.Trunk{}, nil)
 				m.
-					CreateTrunk(trunks.CreateOpts{
+					CreateTrunk(gomock.Any(), trunks.CreateOpts{
 						Name:        "trunk-1",
 						PortID:      "port-1",
 						Description: "Created by cluster-api-provider-openstack cluster test-cluster",
@@ -90,6 +94,7 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 			tt.expect(mockClient.EXPECT())
 			s := Service{
 				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
 			}
 			got, err := s.getOrCreateTrunk(
 				eventObject,
@@ -106,3 +111,155 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcileSubports(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		trunkID   = "trunk-id"
+		networkID = "network-id"
+		portID    = "subport-id"
+		portName  = "trunk-1-vlan-100"
+	)
+	subports := []infrav1.SubportOpts{{
+		Network:          &infrav1.NetworkFilter{ID: networkID},
+		SegmentationType: infrav1.SubportSegmentationTypeVLAN,
+		SegmentationID:   100,
+	}}
+
+	tests := []struct {
+		name     string
+		attached []trunks.Subport
+		expect   func(m *mock.MockNetworkClientMockRecorder)
+		wantErr  bool
+	}{
+		{
+			name: "creates and adds missing subport",
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Name: portName, NetworkID: networkID}).Return([]ports.Port{}, nil)
+				m.CreatePort(gomock.Any(), gomock.Any()).Return(&ports.Port{ID: portID, Name: portName}, nil)
+				m.AddSubports(gomock.Any(), trunkID, trunks.AddSubportsOpts{
+					Subports: []trunks.Subport{{PortID: portID, SegmentationType: "vlan", SegmentationID: 100}},
+				}).Return(&trunks.Trunk{ID: trunkID}, nil)
+			},
+		},
+		{
+			name:     "does not add subport which is already attached",
+			attached: []trunks.Subport{{PortID: portID, SegmentationType: "vlan", SegmentationID: 100}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Name: portName, NetworkID: networkID}).Return([]ports.Port{{ID: portID, Name: portName}}, nil)
+			},
+		},
+		{
+			name:     "fails if segmentation id is used by another port",
+			attached: []trunks.Subport{{PortID: "other-port", SegmentationType: "vlan", SegmentationID: 100}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListPort(gomock.Any(), ports.ListOpts{Name: portName, NetworkID: networkID}).Return([]ports.Port{{ID: portID, Name: portName}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	eventObject := &infrav1.OpenStackMachine{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := Service{
+				client: mockClient,
+				scope:  &scope.Scope{Logger: logr.Discard()},
+			}
+			trunk := &trunks.Trunk{ID: trunkID, Name: "trunk-1", Subports: tt.attached}
+			err := s.reconcileSubports(eventObject, "test-cluster", trunk, subports, nil, nil)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_deleteSubportPorts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().GetPort(gomock.Any(), "capo-port").Return(&ports.Port{ID: "capo-port", Name: "trunk-1-vlan-100"}, nil)
+	mockClient.EXPECT().DeletePort(gomock.Any(), "capo-port").Return(nil)
+	// Ports of subports which were not created by CAPO must be left alone.
+	mockClient.EXPECT().GetPort(gomock.Any(), "kuryr-port").Return(&ports.Port{ID: "kuryr-port", Name: "kuryr-port"}, nil)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard()},
+	}
+
+	trunk := &trunks.Trunk{
+		ID:   "trunk-id",
+		Name: "trunk-1",
+		Subports: []trunks.Subport{
+			{PortID: "capo-port", SegmentationType: "vlan", SegmentationID: 100},
+			{PortID: "kuryr-port", SegmentationType: "vlan", SegmentationID: 200},
+		},
+	}
+	g.Expect(s.deleteSubportPorts(&infrav1.OpenStackMachine{}, trunk)).To(Succeed())
+}
+
+func Test_DeleteTrunk_RetriesSubportPorts(t *testing.T) {
+	const (
+		parentPortID  = "parent-port"
+		subportPortID = "subport-port"
+		description   = "Created by cluster-api-provider-openstack cluster test-cluster"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	mockClient := mock.NewMockNetworkClient(mockCtrl)
+	s := Service{
+		client: mockClient,
+		scope:  &scope.Scope{Logger: logr.Discard()},
+	}
+	eventObject := &infrav1.OpenStackMachine{}
+	trunk := trunks.Trunk{
+		ID:       "trunk-id",
+		Name:     "machine-0",
+		PortID:   parentPortID,
+		Subports: []trunks.Subport{{PortID: subportPortID, SegmentationType: "vlan", SegmentationID: 100}},
+	}
+	subportPort := ports.Port{ID: subportPortID, Name: "machine-0-vlan-100", Description: description}
+
+	subports := []infrav1.SubportOpts{
+		{SegmentationType: infrav1.SubportSegmentationTypeVLAN, SegmentationID: 100},
+		{SegmentationType: infrav1.SubportSegmentationTypeVLAN, SegmentationID: 200},
+	}
+
+	// The trunk is deleted, but the port of its subport is not
+	gomock.InOrder(
+		mockClient.EXPECT().ListTrunk(gomock.Any(), trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{trunk}, nil),
+		mockClient.EXPECT().DeleteTrunk(gomock.Any(), trunk.ID).Return(nil),
+		mockClient.EXPECT().GetPort(gomock.Any(), subportPortID).Return(&subportPort, nil),
+		mockClient.EXPECT().DeletePort(gomock.Any(), subportPortID).Return(errors.New("boom")),
+	)
+	g.Expect(s.DeleteTrunk(eventObject, parentPortID, subports)).NotTo(Succeed())
+
+	// The retry finds the port of the subport by its name, although the trunk is gone
+	gomock.InOrder(
+		mockClient.EXPECT().ListTrunk(gomock.Any(), trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{}, nil),
+		mockClient.EXPECT().GetPort(gomock.Any(), parentPortID).Return(&ports.Port{ID: parentPortID, Name: "machine-0", Description: description}, nil),
+		mockClient.EXPECT().ListPort(gomock.Any(), ports.ListOpts{Name: "machine-0-vlan-100", Description: description}).Return([]ports.Port{subportPort}, nil),
+		mockClient.EXPECT().DeletePort(gomock.Any(), subportPortID).Return(nil),
+		// A port attached to another trunk is left alone
+		mockClient.EXPECT().ListPort(gomock.Any(), ports.ListOpts{Name: "machine-0-vlan-200", Description: description}).Return([]ports.Port{
+			{ID: "attached-port", Name: "machine-0-vlan-200", Description: description, DeviceOwner: "trunk:subport"},
+		}, nil),
+	)
+	g.Expect(s.DeleteTrunk(eventObject, parentPortID, subports)).To(Succeed())
+
+	// Without declared subports, a port without trunk is not looked up
+	mockClient.EXPECT().ListTrunk(gomock.Any(), trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{}, nil)
+	g.Expect(s.DeleteTrunk(eventObject, parentPortID, nil)).To(Succeed())
+}
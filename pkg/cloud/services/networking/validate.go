@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// ValidateNetworks checks that the networks and subnets referenced by a machine, either by UUID or by
+// filter, exist. References which cannot be found are returned as field errors, while errors of the
+// networking API are returned as error so that the caller can tell both apart.
+func (s *Service) ValidateNetworks(networkParams []infrav1.NetworkParam, ports []infrav1.PortOpts, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	for i, networkParam := range networkParams {
+		networkPath := fldPath.Child("networks").Index(i)
+
+		switch {
+		case networkParam.UUID != "":
+			if _, err := s.client.GetNetwork(s.scope.Context(), networkParam.UUID); err != nil {
+				if !capoerrors.IsNotFound(err) {
+					return nil, err
+				}
+				allErrs = append(allErrs, field.NotFound(networkPath.Child("uuid"), networkParam.UUID))
+				continue
+			}
+		case networkParam.Filter != (infrav1.NetworkFilter{}):
+			found, err := s.networkExists(networkParam.Filter.ToListOpt())
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				allErrs = append(allErrs, field.Invalid(networkPath.Child("filter"), networkParam.Filter, "no network matches the filter"))
+				continue
+			}
+		}
+
+		for j, subnet := range networkParam.Subnets {
+			subnetPath := networkPath.Child("subnets").Index(j)
+
+			// Subnets can only be looked up in the network if it is given by UUID, a network filter
+			// may match several networks.
+			opts := subnet.Filter.ToListOpt()
+			opts.NetworkID = networkParam.UUID
+			if subnet.UUID != "" {
+				opts = subnets.ListOpts{ID: subnet.UUID, NetworkID: networkParam.UUID}
+			}
+
+			found, err := s.subnetExists(opts)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case found:
+			case subnet.UUID != "":
+				allErrs = append(allErrs, field.NotFound(subnetPath.Child("uuid"), subnet.UUID))
+			default:
+				allErrs = append(allErrs, field.Invalid(subnetPath.Child("filter"), subnet.Filter, "no subnet matches the filter"))
+			}
+		}
+	}
+
+	for i, port := range ports {
+		if port.Network == nil || *port.Network == (infrav1.NetworkFilter{}) {
+			continue
+		}
+		found, err := s.networkExists(port.Network.ToListOpt())
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ports").Index(i).Child("network"), *port.Network, "no network matches the filter"))
+		}
+	}
+
+	return allErrs, nil
+}
+
+// networkExists returns whether any network matches opts.
+func (s *Service) networkExists(opts networks.ListOpts) (bool, error) {
+	networkList, err := s.client.ListNetwork(s.scope.Context(), opts)
+	if err != nil {
+		return false, err
+	}
+	return len(networkList) > 0, nil
+}
+
+// subnetExists returns whether any subnet matches opts. Subnets of a network shared by another project
+// which Neutron hides from the project are taken into account, see getHiddenSubnets.
+func (s *Service) subnetExists(opts subnets.ListOpts) (bool, error) {
+	subnetList, err := s.client.ListSubnet(s.scope.Context(), opts)
+	if err != nil {
+		return false, err
+	}
+	if len(subnetList) == 0 && opts.NetworkID != "" {
+		subnetList, err = s.getHiddenSubnets(opts.NetworkID, opts)
+		if err != nil {
+			return false, err
+		}
+	}
+	return len(subnetList) > 0, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+func TestService_ValidateNetworks(t *testing.T) {
+	const (
+		projectID   = "e2a3a5b4-6d4c-4c0c-9b0a-3b9d8a2e6c1f"
+		networkID   = "5f2a2b6e-3a8f-4b43-9d7e-2f3a1c6b0d11"
+		subnetID    = "9d1c7e0a-2b5f-4f61-8c3a-7e4b2d9f1a22"
+		otherProjID = "0b7f3c2d-1e4a-4d5b-8f6c-9a2e3d4c5b33"
+	)
+
+	tests := []struct {
+		name          string
+		networkParams []infrav1.NetworkParam
+		ports         []infrav1.PortOpts
+		expect        func(m *mock.MockNetworkClientMockRecorder)
+		wantFields    []string
+		wantErr       bool
+	}{
+		{
+			name: "Network and subnet by UUID exist",
+			networkParams: []infrav1.NetworkParam{{
+				UUID:    networkID,
+				Subnets: []infrav1.SubnetParam{{UUID: subnetID}},
+			}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetNetwork(gomock.Any(), networkID).Return(&networks.Network{ID: networkID}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{ID: subnetID, NetworkID: networkID}).Return([]subnets.Subnet{{ID: subnetID}}, nil)
+			},
+		},
+		{
+			name: "Network by UUID does not exist",
+			networkParams: []infrav1.NetworkParam{{
+				UUID:    networkID,
+				Subnets: []infrav1.SubnetParam{{UUID: subnetID}},
+			}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetNetwork(gomock.Any(), networkID).Return(nil, gophercloud.ErrDefault404{})
+			},
+			wantFields: []string{"spec.networks[0].uuid"},
+		},
+		{
+			name: "Network and subnet filters without matches",
+			networkParams: []infrav1.NetworkParam{
+				{Filter: infrav1.NetworkFilter{Name: "missing"}},
+				{Subnets: []infrav1.SubnetParam{{Filter: infrav1.SubnetFilter{Name: "missing"}}}},
+			},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{Name: "missing"}).Return([]networks.Network{}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{Name: "missing"}).Return([]subnets.Subnet{}, nil)
+			},
+			wantFields: []string{"spec.networks[0].filter", "spec.networks[1].subnets[0].filter"},
+		},
+		{
+			name: "Subnet hidden in a network of another project",
+			networkParams: []infrav1.NetworkParam{{
+				UUID:    networkID,
+				Subnets: []infrav1.SubnetParam{{UUID: subnetID}},
+			}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				network := &networks.Network{ID: networkID, ProjectID: otherProjID, Subnets: []string{subnetID}}
+				m.GetNetwork(gomock.Any(), networkID).Return(network, nil).Times(2)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{ID: subnetID, NetworkID: networkID}).Return([]subnets.Subnet{}, nil)
+				m.ListSubnet(gomock.Any(), subnets.ListOpts{NetworkID: networkID, Limit: 1}).Return([]subnets.Subnet{}, nil)
+			},
+		},
+		{
+			name:  "Port network filter without matches",
+			ports: []infrav1.PortOpts{{}, {Network: &infrav1.NetworkFilter{ID: networkID}}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any(), networks.ListOpts{ID: networkID}).Return([]networks.Network{}, nil)
+			},
+			wantFields: []string{"spec.ports[1].network"},
+		},
+		{
+			name:          "Networking API returns error",
+			networkParams: []infrav1.NetworkParam{{UUID: networkID}},
+			expect: func(m *mock.MockNetworkClientMockRecorder) {
+				m.GetNetwork(gomock.Any(), networkID).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+
+			s := NewTestService(projectID, mockClient, logr.Discard())
+			allErrs, err := s.ValidateNetworks(tt.networkParams, tt.ports, field.NewPath("spec"))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			fields := []string{}
+			for _, err := range allErrs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tt.wantFields))
+		})
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/external"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/flavoralias"
+)
+
+const (
+	CheckComputeQuota      = "ComputeQuota"
+	CheckNetworkQuota      = "NetworkQuota"
+	CheckNetworkExtensions = "NetworkExtensions"
+	CheckImage             = "Image"
+	CheckFlavor            = "Flavor"
+	CheckExternalNetwork   = "ExternalNetwork"
+	CheckOctavia           = "Octavia"
+)
+
+// Run checks the cloud of the cluster against its spec and returns the report.
+// Failing checks are recorded in the report and never returned as errors, so
+// that every check runs.
+func (s *Service) Run(openStackCluster *infrav1.OpenStackCluster) *infrav1.PreflightReport {
+	checks := []func(*infrav1.OpenStackCluster) infrav1.PreflightCheck{
+		s.checkComputeQuota,
+		s.checkNetworkQuota,
+		s.checkNetworkExtensions,
+		s.checkImage,
+		s.checkFlavor,
+		s.checkExternalNetwork,
+		s.checkOctavia,
+	}
+
+	report := &infrav1.PreflightReport{
+		Time:   metav1.Now(),
+		Passed: true,
+	}
+	for _, check := range checks {
+		result := check(openStackCluster)
+		if result.Result == infrav1.PreflightCheckFailed {
+			report.Passed = false
+		}
+		s.scope.Logger.V(4).Info("Preflight check", "name", result.Name, "result", result.Result, "message", result.Message)
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func passed(name, format string, a ...interface{}) infrav1.PreflightCheck {
+	return infrav1.PreflightCheck{Name: name, Result: infrav1.PreflightCheckPassed, Message: fmt.Sprintf(format, a...)}
+}
+
+func warning(name, format string, a ...interface{}) infrav1.PreflightCheck {
+	return infrav1.PreflightCheck{Name: name, Result: infrav1.PreflightCheckWarning, Message: fmt.Sprintf(format, a...)}
+}
+
+func failed(name, format string, a ...interface{}) infrav1.PreflightCheck {
+	return infrav1.PreflightCheck{Name: name, Result: infrav1.PreflightCheckFailed, Message: fmt.Sprintf(format, a...)}
+}
+
+func skipped(name, format string, a ...interface{}) infrav1.PreflightCheck {
+	return infrav1.PreflightCheck{Name: name, Result: infrav1.PreflightCheckSkipped, Message: fmt.Sprintf(format, a...)}
+}
+
+func bastionEnabled(openStackCluster *infrav1.OpenStackCluster) bool {
+	return openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled
+}
+
+// available returns the remaining amount of a quota. A negative limit means
+// there is no limit.
+func available(limit, used int) (int, bool) {
+	if limit < 0 {
+		return 0, true
+	}
+	return limit - used, false
+}
+
+func (s *Service) checkComputeQuota(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	limits, err := s.computeClient.GetLimits(s.scope.Context())
+	if err != nil {
+		return failed(CheckComputeQuota, "failed to get compute limits: %v", err)
+	}
+	absolute := limits.Absolute
+
+	// At least the first control plane machine, and the bastion if enabled.
+	required := 1
+	if bastionEnabled(openStackCluster) {
+		required++
+	}
+
+	summary := fmt.Sprintf("instances %d/%d, cores %d/%d, RAM %d/%d MB used",
+		absolute.TotalInstancesUsed, absolute.MaxTotalInstances,
+		absolute.TotalCoresUsed, absolute.MaxTotalCores,
+		absolute.TotalRAMUsed, absolute.MaxTotalRAMSize)
+
+	if remaining, unlimited := available(absolute.MaxTotalInstances, absolute.TotalInstancesUsed); !unlimited && remaining < required {
+		return failed(CheckComputeQuota, "%d instances are required but only %d are available: %s", required, remaining, summary)
+	}
+	if remaining, unlimited := available(absolute.MaxTotalCores, absolute.TotalCoresUsed); !unlimited && remaining <= 0 {
+		return failed(CheckComputeQuota, "no cores are available: %s", summary)
+	}
+	if remaining, unlimited := available(absolute.MaxTotalRAMSize, absolute.TotalRAMUsed); !unlimited && remaining <= 0 {
+		return failed(CheckComputeQuota, "no RAM is available: %s", summary)
+	}
+	return passed(CheckComputeQuota, "%s", summary)
+}
+
+func (s *Service) checkNetworkQuota(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	quota, err := s.networkClient.GetQuotaDetail(s.scope.Context(), s.scope.ProjectID)
+	if err != nil {
+		// Quota details are an optional Neutron extension.
+		return warning(CheckNetworkQuota, "failed to get network quota: %v", err)
+	}
+
+	type requirement struct {
+		name     string
+		quota    quotas.QuotaDetail
+		required int
+	}
+	var requirements []requirement
+	if openStackCluster.Spec.NodeCIDR != "" {
+		subnets := 1
+		if openStackCluster.Spec.NodeIPv6Subnet != nil {
+			subnets++
+		}
+		requirements = append(requirements,
+			requirement{"networks", quota.Network, 1},
+			requirement{"subnets", quota.Subnet, subnets},
+			requirement{"routers", quota.Router, 1},
+		)
+	}
+	securityGroups, floatingIPs, ports := 0, 0, 0
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		securityGroups += 2
+		if bastionEnabled(openStackCluster) {
+			securityGroups++
+		}
+	}
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		floatingIPs++
+	}
+	if bastionEnabled(openStackCluster) {
+		floatingIPs++
+		ports++
+	}
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		ports++
+	}
+	// The first control plane machine needs a port.
+	ports++
+	requirements = append(requirements,
+		requirement{"security groups", quota.SecurityGroup, securityGroups},
+		requirement{"floating IPs", quota.FloatingIP, floatingIPs},
+		requirement{"ports", quota.Port, ports},
+	)
+
+	var insufficient []string
+	for _, r := range requirements {
+		if r.required == 0 {
+			continue
+		}
+		remaining, unlimited := available(r.quota.Limit, r.quota.Used+r.quota.Reserved)
+		if !unlimited && remaining < r.required {
+			insufficient = append(insufficient, fmt.Sprintf("%s (%d required, %d available)", r.name, r.required, remaining))
+		}
+	}
+	if len(insufficient) > 0 {
+		return failed(CheckNetworkQuota, "insufficient quota for %s", strings.Join(insufficient, ", "))
+	}
+	return passed(CheckNetworkQuota, "sufficient quota for the cluster network resources")
+}
+
+func (s *Service) checkNetworkExtensions(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	required := map[string]string{}
+	if openStackCluster.Spec.NodeCIDR != "" {
+		required["router"] = "a cluster network is created"
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		required["security-group"] = "managedSecurityGroups is enabled"
+	}
+	if len(openStackCluster.Spec.Tags) > 0 {
+		required["standard-attr-tag"] = "tags are set"
+	}
+	if bastionEnabled(openStackCluster) && openStackCluster.Spec.Bastion.Instance.Trunk {
+		required["trunk"] = "the bastion uses a trunk port"
+	}
+	if len(required) == 0 {
+		return skipped(CheckNetworkExtensions, "no network extensions are required")
+	}
+
+	extensionList, err := s.networkClient.ListExtensions(s.scope.Context())
+	if err != nil {
+		return failed(CheckNetworkExtensions, "failed to list network extensions: %v", err)
+	}
+	aliases := map[string]bool{}
+	for _, extension := range extensionList {
+		aliases[extension.Alias] = true
+	}
+
+	var missing []string
+	for alias, reason := range required {
+		if !aliases[alias] {
+			missing = append(missing, fmt.Sprintf("%q (%s)", alias, reason))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return failed(CheckNetworkExtensions, "missing network extensions: %s", strings.Join(missing, ", "))
+	}
+	return passed(CheckNetworkExtensions, "all required network extensions are available")
+}
+
+func (s *Service) checkImage(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	if !bastionEnabled(openStackCluster) {
+		return skipped(CheckImage, "the bastion is not enabled")
+	}
+
+	instance := openStackCluster.Spec.Bastion.Instance
+	var opts images.ListOpts
+	switch {
+	case instance.ImageFilter != nil:
+		return skipped(CheckImage, "the bastion image is selected by a filter when it is created")
+	case instance.ImageUUID != "":
+		opts.ID = instance.ImageUUID
+	case instance.Image != "":
+		opts.Name = instance.Image
+	default:
+		return failed(CheckImage, "the bastion has no image")
+	}
+
+	imageList, err := s.imageClient.ListImages(s.scope.Context(), opts)
+	if err != nil {
+		return failed(CheckImage, "failed to list images: %v", err)
+	}
+	switch len(imageList) {
+	case 0:
+		return failed(CheckImage, "bastion image %s%s could not be found", opts.ID, opts.Name)
+	case 1:
+		if imageList[0].Status != images.ImageStatusActive {
+			return failed(CheckImage, "bastion image %s is %s", imageList[0].ID, imageList[0].Status)
+		}
+		return passed(CheckImage, "bastion image %s is active", imageList[0].ID)
+	default:
+		return failed(CheckImage, "found %d images with name %s", len(imageList), opts.Name)
+	}
+}
+
+func (s *Service) checkFlavor(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	if !bastionEnabled(openStackCluster) {
+		return skipped(CheckFlavor, "the bastion is not enabled")
+	}
+
+	if flavorID := openStackCluster.Spec.Bastion.Instance.FlavorID; flavorID != "" {
+		return skipped(CheckFlavor, "the bastion flavor is selected by ID %s", flavorID)
+	}
+
+	flavorName := flavoralias.Resolve(s.scope.CloudName(), openStackCluster.Spec.Bastion.Instance.Flavor)
+	flavorID, err := s.computeClient.GetFlavorIDFromName(s.scope.Context(), flavorName)
+	if err != nil {
+		return failed(CheckFlavor, "bastion flavor %s could not be found: %v", flavorName, err)
+	}
+	return passed(CheckFlavor, "bastion flavor %s has ID %s", flavorName, flavorID)
+}
+
+func (s *Service) checkExternalNetwork(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	iTrue := true
+	listOpts := external.ListOptsExt{
+		ListOptsBuilder: networks.ListOpts{ID: openStackCluster.Spec.ExternalNetworkID},
+		External:        &iTrue,
+	}
+	networkList, err := s.networkClient.ListNetwork(s.scope.Context(), listOpts)
+	if err != nil {
+		return failed(CheckExternalNetwork, "failed to list external networks: %v", err)
+	}
+
+	needsExternalNetwork := !openStackCluster.Spec.DisableAPIServerFloatingIP || bastionEnabled(openStackCluster)
+	switch len(networkList) {
+	case 0:
+		if openStackCluster.Spec.ExternalNetworkID != "" {
+			return failed(CheckExternalNetwork, "network %s could not be found or is not external", openStackCluster.Spec.ExternalNetworkID)
+		}
+		if needsExternalNetwork {
+			return failed(CheckExternalNetwork, "no external network was found but floating IPs are required")
+		}
+		return skipped(CheckExternalNetwork, "no external network was found and none is required")
+	case 1:
+	default:
+		return failed(CheckExternalNetwork, "found %d external networks, set externalNetworkId to select one", len(networkList))
+	}
+
+	network := networkList[0]
+	if !network.AdminStateUp || network.Status != "ACTIVE" {
+		return failed(CheckExternalNetwork, "external network %s is not active", network.ID)
+	}
+	if len(network.Subnets) == 0 {
+		return failed(CheckExternalNetwork, "external network %s has no subnets", network.ID)
+	}
+	return passed(CheckExternalNetwork, "external network %s is active", network.ID)
+}
+
+func (s *Service) checkOctavia(openStackCluster *infrav1.OpenStackCluster) infrav1.PreflightCheck {
+	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		return skipped(CheckOctavia, "the API server load balancer is not enabled")
+	}
+	if s.lbClient == nil {
+		return failed(CheckOctavia, "Octavia is not available: %v", s.lbClientErr)
+	}
+
+	versions, err := s.lbClient.ListOctaviaVersions(s.scope.Context())
+	if err != nil {
+		return failed(CheckOctavia, "failed to get Octavia versions: %v", err)
+	}
+	providerList, err := s.lbClient.ListLoadBalancerProviders(s.scope.Context())
+	if err != nil {
+		return failed(CheckOctavia, "failed to list load balancer providers: %v", err)
+	}
+
+	var providerNames []string
+	for _, provider := range providerList {
+		providerNames = append(providerNames, provider.Name)
+	}
+	if provider := openStackCluster.Spec.APIServerLoadBalancer.Provider; provider != "" {
+		found := false
+		for _, name := range providerNames {
+			if name == provider {
+				found = true
+			}
+		}
+		if !found {
+			return failed(CheckOctavia, "load balancer provider %s is not available, available providers: %s", provider, strings.Join(providerNames, ", "))
+		}
+	}
+
+	var latest string
+	if len(versions) > 0 {
+		latest = versions[len(versions)-1].ID
+	}
+	return passed(CheckOctavia, "Octavia %s is available with providers: %s", latest, strings.Join(providerNames, ", "))
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/compute/apiversions"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/limits"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients/mock"
+)
+
+const (
+	projectID         = "project-id"
+	externalNetworkID = "aaaaaaaa-bbbb-cccc-dddd-111111111111"
+	imageID           = "aaaaaaaa-bbbb-cccc-dddd-222222222222"
+)
+
+type recorders struct {
+	compute *mock.MockComputeClientMockRecorder
+	image   *mock.MockImageClientMockRecorder
+	network *mock.MockNetworkClientMockRecorder
+	lb      *mock.MockLbClientMockRecorder
+}
+
+func quotaDetail(limit, used int) quotas.QuotaDetail {
+	return quotas.QuotaDetail{Limit: limit, Used: used}
+}
+
+func Test_Run(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clusterSpec := func() infrav1.OpenStackClusterSpec {
+		return infrav1.OpenStackClusterSpec{
+			NodeCIDR:              "10.6.0.0/24",
+			ManagedSecurityGroups: true,
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+			Bastion: &infrav1.Bastion{
+				Enabled: true,
+				Instance: infrav1.OpenStackMachineSpec{
+					Image:  "ubuntu",
+					Flavor: "m1.small",
+				},
+			},
+		}
+	}
+
+	expectHealthyCloud := func(r recorders) {
+		r.compute.GetLimits(gomock.Any()).Return(&limits.Limits{Absolute: limits.Absolute{
+			MaxTotalInstances: 10, TotalInstancesUsed: 2,
+			MaxTotalCores: 20, TotalCoresUsed: 4,
+			MaxTotalRAMSize: -1, TotalRAMUsed: 8192,
+		}}, nil).AnyTimes()
+		r.network.GetQuotaDetail(gomock.Any(), projectID).Return(&quotas.QuotaDetailSet{
+			Network:       quotaDetail(10, 1),
+			Subnet:        quotaDetail(10, 1),
+			Router:        quotaDetail(10, 1),
+			SecurityGroup: quotaDetail(10, 1),
+			FloatingIP:    quotaDetail(-1, 0),
+			Port:          quotaDetail(50, 3),
+		}, nil).AnyTimes()
+		r.network.ListExtensions(gomock.Any()).Return([]extensions.Extension{
+			{Extension: common.Extension{Alias: "router"}},
+			{Extension: common.Extension{Alias: "security-group"}},
+		}, nil).AnyTimes()
+		r.image.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu"}).Return([]images.Image{
+			{ID: imageID, Status: images.ImageStatusActive},
+		}, nil).AnyTimes()
+		r.compute.GetFlavorIDFromName(gomock.Any(), "m1.small").Return("flavor-id", nil).AnyTimes()
+		r.network.ListNetwork(gomock.Any(), gomock.Any()).Return([]networks.Network{
+			{ID: externalNetworkID, AdminStateUp: true, Status: "ACTIVE", Subnets: []string{"subnet-id"}},
+		}, nil).AnyTimes()
+		r.lb.ListOctaviaVersions(gomock.Any()).Return([]apiversions.APIVersion{{ID: "2.23"}, {ID: "2.24"}}, nil).AnyTimes()
+		r.lb.ListLoadBalancerProviders(gomock.Any()).Return([]providers.Provider{{Name: "amphora"}, {Name: "ovn"}}, nil).AnyTimes()
+	}
+
+	tests := []struct {
+		name       string
+		spec       func() infrav1.OpenStackClusterSpec
+		expect     func(r recorders)
+		wantPassed bool
+		want       map[string]infrav1.PreflightCheckResult
+	}{
+		{
+			name:       "healthy cloud",
+			spec:       clusterSpec,
+			expect:     expectHealthyCloud,
+			wantPassed: true,
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckComputeQuota:      infrav1.PreflightCheckPassed,
+				CheckNetworkQuota:      infrav1.PreflightCheckPassed,
+				CheckNetworkExtensions: infrav1.PreflightCheckPassed,
+				CheckImage:             infrav1.PreflightCheckPassed,
+				CheckFlavor:            infrav1.PreflightCheckPassed,
+				CheckExternalNetwork:   infrav1.PreflightCheckPassed,
+				CheckOctavia:           infrav1.PreflightCheckPassed,
+			},
+		},
+		{
+			name: "bastion and load balancer disabled",
+			spec: func() infrav1.OpenStackClusterSpec {
+				spec := clusterSpec()
+				spec.Bastion = nil
+				spec.APIServerLoadBalancer.Enabled = false
+				return spec
+			},
+			expect:     expectHealthyCloud,
+			wantPassed: true,
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckImage:   infrav1.PreflightCheckSkipped,
+				CheckFlavor:  infrav1.PreflightCheckSkipped,
+				CheckOctavia: infrav1.PreflightCheckSkipped,
+			},
+		},
+		{
+			name: "instance quota exhausted",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.compute.GetLimits(gomock.Any()).Return(&limits.Limits{Absolute: limits.Absolute{
+					MaxTotalInstances: 10, TotalInstancesUsed: 9,
+					MaxTotalCores: -1, MaxTotalRAMSize: -1,
+				}}, nil)
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckComputeQuota: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "router quota exhausted",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.network.GetQuotaDetail(gomock.Any(), projectID).Return(&quotas.QuotaDetailSet{
+					Network:       quotaDetail(-1, 0),
+					Subnet:        quotaDetail(-1, 0),
+					Router:        quotaDetail(1, 1),
+					SecurityGroup: quotaDetail(-1, 0),
+					FloatingIP:    quotaDetail(-1, 0),
+					Port:          quotaDetail(-1, 0),
+				}, nil)
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckNetworkQuota: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "network quota details are not available",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.network.GetQuotaDetail(gomock.Any(), projectID).Return(nil, fmt.Errorf("not found"))
+				expectHealthyCloud(r)
+			},
+			wantPassed: true,
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckNetworkQuota: infrav1.PreflightCheckWarning,
+			},
+		},
+		{
+			name: "trunk extension is missing",
+			spec: func() infrav1.OpenStackClusterSpec {
+				spec := clusterSpec()
+				spec.Bastion.Instance.Trunk = true
+				return spec
+			},
+			expect: expectHealthyCloud,
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckNetworkExtensions: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "bastion image is not found",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.image.ListImages(gomock.Any(), images.ListOpts{Name: "ubuntu"}).Return([]images.Image{}, nil)
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckImage: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "bastion flavor is not found",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.compute.GetFlavorIDFromName(gomock.Any(), "m1.small").Return("", fmt.Errorf("not found"))
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckFlavor: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "external network has no subnets",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.network.ListNetwork(gomock.Any(), gomock.Any()).Return([]networks.Network{
+					{ID: externalNetworkID, AdminStateUp: true, Status: "ACTIVE"},
+				}, nil)
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckExternalNetwork: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "no external network but floating IPs are required",
+			spec: clusterSpec,
+			expect: func(r recorders) {
+				r.network.ListNetwork(gomock.Any(), gomock.Any()).Return([]networks.Network{}, nil)
+				expectHealthyCloud(r)
+			},
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckExternalNetwork: infrav1.PreflightCheckFailed,
+			},
+		},
+		{
+			name: "requested load balancer provider is not available",
+			spec: func() infrav1.OpenStackClusterSpec {
+				spec := clusterSpec()
+				spec.APIServerLoadBalancer.Provider = "f5"
+				return spec
+			},
+			expect: expectHealthyCloud,
+			want: map[string]infrav1.PreflightCheckResult{
+				CheckOctavia: infrav1.PreflightCheckFailed,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			computeClient := mock.NewMockComputeClient(mockCtrl)
+			imageClient := mock.NewMockImageClient(mockCtrl)
+			networkClient := mock.NewMockNetworkClient(mockCtrl)
+			lbClient := mock.NewMockLbClient(mockCtrl)
+			tt.expect(recorders{
+				compute: computeClient.EXPECT(),
+				image:   imageClient.EXPECT(),
+				network: networkClient.EXPECT(),
+				lb:      lbClient.EXPECT(),
+			})
+
+			s := NewTestService(projectID, computeClient, imageClient, networkClient, lbClient, logr.Discard())
+			report := s.Run(&infrav1.OpenStackCluster{Spec: tt.spec()})
+
+			g.Expect(report.Passed).To(Equal(tt.wantPassed))
+			g.Expect(report.Checks).To(HaveLen(7))
+			results := map[string]infrav1.PreflightCheckResult{}
+			for _, check := range report.Checks {
+				results[check.Name] = check.Result
+			}
+			for name, want := range tt.want {
+				g.Expect(results).To(HaveKeyWithValue(name, want), "check %s", name)
+			}
+		})
+	}
+}
+
+func Test_checkOctaviaUnavailable(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &Service{lbClientErr: fmt.Errorf("no suitable endpoint could be found")}
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+		},
+	}
+
+	check := s.checkOctavia(openStackCluster)
+	g.Expect(check.Result).To(Equal(infrav1.PreflightCheckFailed))
+	g.Expect(check.Message).To(ContainSubstring("no suitable endpoint"))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// Service checks whether an OpenStack cloud is able to host a cluster.
+type Service struct {
+	scope         *scope.Scope
+	computeClient clients.ComputeClient
+	imageClient   clients.ImageClient
+	networkClient clients.NetworkClient
+	// lbClient is nil if the load balancer client could not be created, in
+	// which case lbClientErr holds the reason.
+	lbClient    clients.LbClient
+	lbClientErr error
+}
+
+// NewService returns an instance of the preflight service.
+func NewService(scope *scope.Scope) (*Service, error) {
+	if scope.ProviderClientOpts.AuthInfo == nil {
+		return nil, fmt.Errorf("authInfo must be set")
+	}
+
+	computeClient, err := clients.NewComputeClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	imageClient, err := clients.NewImageClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	networkClient, err := clients.NewNetworkClient(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing load balancer endpoint is reported by the checks rather
+	// than failing here.
+	lbClient, lbClientErr := clients.NewLbClient(scope)
+
+	return &Service{
+		scope:         scope,
+		computeClient: computeClient,
+		imageClient:   imageClient,
+		networkClient: networkClient,
+		lbClient:      lbClient,
+		lbClientErr:   lbClientErr,
+	}, nil
+}
+
+// NewTestService returns a Service with no initialisation. It should only be used by tests.
+func NewTestService(projectID string, computeClient clients.ComputeClient, imageClient clients.ImageClient, networkClient clients.NetworkClient, lbClient clients.LbClient, logger logr.Logger) *Service {
+	return &Service{
+		scope: &scope.Scope{
+			ProjectID: projectID,
+			Logger:    logger,
+		},
+		computeClient: computeClient,
+		imageClient:   imageClient,
+		networkClient: networkClient,
+		lbClient:      lbClient,
+	}
+}
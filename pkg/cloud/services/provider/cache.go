@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+)
+
+// tokenExpiryMargin is how long before the expiry of its token a cached client is re-authenticated, so that
+// the token does not expire during a reconcile.
+const tokenExpiryMargin = 5 * time.Minute
+
+// clientCache caches authenticated provider clients by the hash of their credentials. It is shared by all
+// controllers, so that reconciles reuse a Keystone token instead of authenticating again.
+type clientCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedClient
+	// secrets are the keys of the credentials last read from each cloud of an identity secret, so that the clients
+	// of the previous credentials can be evicted when the secret is rotated.
+	secrets map[secretCloud]string
+	// newClient authenticates a new provider client.
+	newClient func(creds credentials) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error)
+	now       func() time.Time
+}
+
+// credentials are the content of an identity secret for one of its clouds, or the credentials of the controller.
+type credentials struct {
+	cloud  clientconfig.Cloud
+	caCert []byte
+	// trustID is the ID of the Keystone trust the tokens are scoped to, if the cloud is a trustee.
+	trustID string
+	// secret is the identity secret and the cloud the credentials were read from, if any.
+	secret secretCloud
+}
+
+type secretCloud struct {
+	secret    types.NamespacedName
+	cloudName string
+}
+
+type cachedClient struct {
+	provider   *gophercloud.ProviderClient
+	clientOpts *clientconfig.ClientOpts
+	projectID  string
+}
+
+var defaultClientCache = newClientCache(newClient)
+
+func newClientCache(newClient func(creds credentials) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error)) *clientCache {
+	return &clientCache{
+		entries:   make(map[string]*cachedClient),
+		secrets:   make(map[secretCloud]string),
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// get returns a provider client for the credentials, authenticating only if no cached client has a valid token.
+func (c *clientCache) get(creds credentials) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	key, err := credentialsHash(creds)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	c.mu.Lock()
+	c.evictExpired()
+	entry := c.entries[key]
+	c.mu.Unlock()
+
+	if entry != nil && c.expiresSoon(entry.provider) {
+		// Re-authenticate unless another reconcile already did
+		err := entry.provider.Reauthenticate(entry.provider.Token())
+		metrics.Reauthenticated(entry.provider.IdentityEndpoint, metrics.ReauthReasonExpiring, err)
+		if err != nil {
+			c.evict(key, entry)
+			entry = nil
+		}
+	}
+
+	if entry == nil {
+		providerClient, clientOpts, projectID, err := c.newClient(creds)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		entry = &cachedClient{provider: providerClient, clientOpts: clientOpts, projectID: projectID}
+
+		c.mu.Lock()
+		c.entries[key] = entry
+		c.mu.Unlock()
+	}
+	if creds.secret != (secretCloud{}) {
+		c.track(creds.secret, key)
+	}
+
+	// Callers may modify the options, e.g. the region
+	clientOpts := *entry.clientOpts
+	evict := func() { c.evict(key, entry) }
+	return entry.session(evict), &clientOpts, entry.projectID, nil
+}
+
+// evict removes the cached client of the credentials, unless it was already replaced by a new one.
+func (c *clientCache) evict(key string, entry *cachedClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+}
+
+// track records the credentials last read from the cloud of an identity secret. The cached client of the
+// credentials previously read from it is evicted, as they were replaced.
+func (c *clientCache) track(source secretCloud, key string) {
+	c.mu.Lock()
+	previous, ok := c.secrets[source]
+	c.secrets[source] = key
+	c.mu.Unlock()
+	if ok && previous != key {
+		c.evictCredentials(previous)
+	}
+}
+
+// rotate authenticates with the credentials read from an identity secret, if they differ from the credentials
+// last read from it, and evicts the cached client of the previous credentials. Reconciles would only switch to
+// the new credentials when they next read the secret, and the previous client would remain cached until then,
+// e.g. after the application credential it authenticates with was deleted. If the new credentials fail to
+// authenticate, the previous client is kept and an error is returned.
+func (c *clientCache) rotate(creds credentials) error {
+	key, err := credentialsHash(creds)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	previous, ok := c.secrets[creds.secret]
+	_, cached := c.entries[previous]
+	c.mu.Unlock()
+	if !ok || previous == key {
+		return nil
+	}
+	if !cached {
+		// The next reconcile authenticates with the new credentials
+		c.forget(creds.secret)
+		return nil
+	}
+
+	_, _, _, err = c.get(creds)
+	return err
+}
+
+// forget evicts the cached client of the credentials last read from the cloud of an identity secret, e.g.
+// because the secret was deleted, and stops tracking the secret.
+func (c *clientCache) forget(source secretCloud) {
+	c.mu.Lock()
+	key, ok := c.secrets[source]
+	delete(c.secrets, source)
+	c.mu.Unlock()
+	if ok {
+		c.evictCredentials(key)
+	}
+}
+
+// evictCredentials removes the cached client of the credentials, unless they are still read from another
+// identity secret.
+func (c *clientCache) evictCredentials(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range c.secrets {
+		if k == key {
+			return
+		}
+	}
+	delete(c.entries, key)
+}
+
+// cloudNames returns the clouds of the identity secret which cached clients were created for.
+func (c *clientCache) cloudNames(secret types.NamespacedName) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var cloudNames []string
+	for source := range c.secrets {
+		if source.secret == secret {
+			cloudNames = append(cloudNames, source.cloudName)
+		}
+	}
+	return cloudNames
+}
+
+// evictExpired removes the clients whose tokens have expired. They are not used any more, e.g. because the
+// credentials were rotated, as the token of a client in use is renewed before it expires.
+func (c *clientCache) evictExpired() {
+	for key, entry := range c.entries {
+		if expiresAt, ok := tokenExpiresAt(entry.provider); ok && !c.now().Before(expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *clientCache) expiresSoon(providerClient *gophercloud.ProviderClient) bool {
+	expiresAt, ok := tokenExpiresAt(providerClient)
+	return ok && !c.now().Add(tokenExpiryMargin).Before(expiresAt)
+}
+
+// session returns a provider client which shares the token, the endpoints and the transport of the cached
+// client. Each reconcile gets its own client, so that its transport can be wrapped, e.g. to count its
+// requests. A 401 response re-authenticates the cached client once for all of its sessions, and the request is
+// retried once with the new token. If the re-authentication fails, e.g. because the credentials were revoked,
+// evict is called, so that later reconciles authenticate again instead of using the cached client.
+func (e *cachedClient) session(evict func()) *gophercloud.ProviderClient {
+	cached := e.provider
+	session := &gophercloud.ProviderClient{
+		IdentityBase:     cached.IdentityBase,
+		IdentityEndpoint: cached.IdentityEndpoint,
+		EndpointLocator:  cached.EndpointLocator,
+		HTTPClient:       http.Client{Transport: cached.HTTPClient.Transport},
+		UserAgent:        cached.UserAgent,
+		RetryFunc:        cached.RetryFunc,
+	}
+	session.UseTokenLock()
+	session.CopyTokenFrom(cached)
+	session.ReauthFunc = func() error {
+		rejected := session.Token()
+		// Only the first session whose token was rejected re-authenticates, the others copy the new token
+		renew := cached.Token() == rejected
+		err := cached.Reauthenticate(rejected)
+		if renew {
+			metrics.Reauthenticated(cached.IdentityEndpoint, metrics.ReauthReasonUnauthorized, err)
+		}
+		if err != nil {
+			evict()
+			return err
+		}
+		session.CopyTokenFrom(cached)
+		return nil
+	}
+	return session
+}
+
+// tokenExpiresAt returns the expiry of the token of the provider client, if it is known.
+func tokenExpiresAt(providerClient *gophercloud.ProviderClient) (time.Time, bool) {
+	authResult, ok := providerClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return time.Time{}, false
+	}
+	token, err := authResult.ExtractToken()
+	if err != nil || token.ExpiresAt.IsZero() {
+		return time.Time{}, false
+	}
+	return token.ExpiresAt, true
+}
+
+// credentialsHash returns the key of the provider clients for the credentials.
+func credentialsHash(creds credentials) (string, error) {
+	data, err := json.Marshal(creds.cloud)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(data)
+	h.Write(creds.caCert)
+	if creds.trustID != "" {
+		h.Write([]byte("trust:" + creds.trustID))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeKeystone issues tokens which expire after tokenLifetime and accepts only the last issued token.
+type fakeKeystone struct {
+	server        *httptest.Server
+	tokenLifetime time.Duration
+	issued        int32
+	// revoked rejects authentication if it is not 0, e.g. because the credentials were revoked.
+	revoked int32
+	// trustID is the trust the last token was scoped to.
+	trustID atomic.Value
+}
+
+func newFakeKeystone(tokenLifetime time.Duration) *fakeKeystone {
+	k := &fakeKeystone{tokenLifetime: tokenLifetime}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&k.revoked) != 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Auth struct {
+				Scope struct {
+					Trust struct {
+						ID string `json:"id"`
+					} `json:"OS-TRUST:trust"`
+				} `json:"scope"`
+			} `json:"auth"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		k.trustID.Store(body.Auth.Scope.Trust.ID)
+		n := atomic.AddInt32(&k.issued, 1)
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", n))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": {"expires_at": %q, "project": {"id": "project-id", "name": "project"}, "catalog": []}}`,
+			time.Now().Add(k.tokenLifetime).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("/v3/resource", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != fmt.Sprintf("token-%d", atomic.LoadInt32(&k.issued)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	k.server = httptest.NewServer(mux)
+	return k
+}
+
+func (k *fakeKeystone) cloud(password string) clientconfig.Cloud {
+	return clientconfig.Cloud{
+		AuthType: clientconfig.AuthPassword,
+		AuthInfo: &clientconfig.AuthInfo{
+			AuthURL:      k.server.URL + "/v3",
+			Username:     "user",
+			Password:     password,
+			ProjectID:    "project-id",
+			UserDomainID: "default",
+		},
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	cache := newClientCache(newClient)
+
+	first, clientOpts, projectID, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(projectID).To(Equal("project-id"))
+	g.Expect(first.Token()).To(Equal("token-1"))
+
+	// Changes to the options of a client do not affect the cache
+	clientOpts.RegionName = "RegionTwo"
+
+	second, clientOpts, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(second).NotTo(BeIdenticalTo(first), "every reconcile gets its own client")
+	g.Expect(second.Token()).To(Equal("token-1"), "the token is reused")
+	g.Expect(clientOpts.RegionName).To(BeEmpty())
+	g.Expect(atomic.LoadInt32(&keystone.issued)).To(Equal(int32(1)))
+
+	other, _, _, err := cache.get(credentials{cloud: keystone.cloud("other-secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(other.Token()).To(Equal("token-2"), "clients are cached per credentials")
+}
+
+func TestClientCacheReauthenticatesOn401(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	cache := newClientCache(newClient)
+
+	first, _, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	second, _, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The token is revoked when a new one is issued
+	atomic.AddInt32(&keystone.issued, 1)
+
+	_, err = first.Request(http.MethodGet, keystone.server.URL+"/v3/resource", &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(first.Token()).To(Equal("token-3"))
+
+	// The other session uses the renewed token of the cached client
+	_, err = second.Request(http.MethodGet, keystone.server.URL+"/v3/resource", &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(second.Token()).To(Equal("token-3"))
+	g.Expect(atomic.LoadInt32(&keystone.issued)).To(Equal(int32(3)))
+}
+
+func TestClientCacheEvictsClientsWhichFailToReauthenticate(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	cache := newClientCache(newClient)
+
+	session, _, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The token and the credentials are revoked
+	atomic.AddInt32(&keystone.issued, 1)
+	atomic.StoreInt32(&keystone.revoked, 1)
+
+	_, err = session.Request(http.MethodGet, keystone.server.URL+"/v3/resource", &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(cache.entries).To(BeEmpty(), "a client which failed to re-authenticate is evicted")
+
+	// Once the credentials are valid again, the next reconcile authenticates
+	atomic.StoreInt32(&keystone.revoked, 0)
+	session, _, _, err = cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = session.Request(http.MethodGet, keystone.server.URL+"/v3/resource", &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK}})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestClientCacheRenewsExpiringTokens(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	clock := time.Now()
+	cache := newClientCache(newClient)
+	cache.now = func() time.Time { return clock }
+
+	_, _, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clock = clock.Add(time.Hour - tokenExpiryMargin)
+	renewed, _, _, err := cache.get(credentials{cloud: keystone.cloud("secret")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(renewed.Token()).To(Equal("token-2"), "a token which expires soon is renewed")
+
+	clock = clock.Add(3 * time.Hour)
+	g.Expect(func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		cache.evictExpired()
+		return len(cache.entries) == 0
+	}()).To(BeTrue(), "clients with expired tokens are evicted")
+}
+
+func TestClientCacheAuthenticatesTrustees(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	cache := newClientCache(newClient)
+
+	trustee := keystone.cloud("secret")
+	trustee.AuthInfo.ProjectID = ""
+	_, _, projectID, err := cache.get(credentials{cloud: trustee, trustID: "trust-id"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(keystone.trustID.Load()).To(Equal("trust-id"), "the token is scoped to the trust")
+	g.Expect(projectID).To(Equal("project-id"))
+
+	_, _, _, err = cache.get(credentials{cloud: trustee, trustID: "other-trust-id"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(atomic.LoadInt32(&keystone.issued)).To(Equal(int32(2)), "clients are cached per trust")
+
+	_, _, _, err = cache.get(credentials{cloud: keystone.cloud("secret"), trustID: "trust-id"})
+	g.Expect(err).To(HaveOccurred(), "the token of a trustee cannot be scoped to a project")
+}
+
+func TestClientCacheRotatesCredentials(t *testing.T) {
+	g := NewWithT(t)
+	keystone := newFakeKeystone(time.Hour)
+	defer keystone.server.Close()
+	cache := newClientCache(newClient)
+	source := secretCloud{secret: types.NamespacedName{Namespace: "default", Name: "cloud-config"}, cloudName: "openstack"}
+
+	_, _, _, err := cache.get(credentials{cloud: keystone.cloud("old"), secret: source})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cache.cloudNames(source.secret)).To(ConsistOf("openstack"))
+
+	// The secret is updated without changing the credentials, e.g. its labels
+	g.Expect(cache.rotate(credentials{cloud: keystone.cloud("old"), secret: source})).To(Succeed())
+	g.Expect(atomic.LoadInt32(&keystone.issued)).To(Equal(int32(1)))
+
+	// The new credentials are rejected, e.g. because they were not created yet
+	atomic.StoreInt32(&keystone.revoked, 1)
+	g.Expect(cache.rotate(credentials{cloud: keystone.cloud("new"), secret: source})).NotTo(Succeed())
+	g.Expect(cache.entries).To(HaveLen(1), "the previous client is kept")
+	atomic.StoreInt32(&keystone.revoked, 0)
+
+	g.Expect(cache.rotate(credentials{cloud: keystone.cloud("new"), secret: source})).To(Succeed())
+	newKey, err := credentialsHash(credentials{cloud: keystone.cloud("new")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cache.entries).To(HaveLen(1), "the client of the previous credentials is evicted")
+	g.Expect(cache.entries).To(HaveKey(newKey))
+
+	// Reconciles use the client authenticated by the rotation
+	session, _, _, err := cache.get(credentials{cloud: keystone.cloud("new"), secret: source})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Token()).To(Equal("token-2"))
+
+	cache.forget(source)
+	g.Expect(cache.entries).To(BeEmpty(), "the clients of a deleted secret are evicted")
+	g.Expect(cache.cloudNames(source.secret)).To(BeEmpty())
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/trusts"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	osclient "github.com/gophercloud/utils/client"
 	"github.com/gophercloud/utils/openstack/clientconfig"
@@ -35,42 +36,122 @@ import (
 	"sigs.k8s.io/yaml"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/clients"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/ratelimit"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/tlsconfig"
 )
 
 const (
 	cloudsSecretKey = "clouds.yaml"
 	caSecretKey     = "cacert"
+	// trustSecretKey is the optional ID of a Keystone trust. The cloud then holds the credentials of the trustee,
+	// whose tokens are scoped to the trust instead of a project.
+	trustSecretKey = "trustid"
 )
 
-func NewClientFromMachine(ctx context.Context, ctrlClient client.Client, openStackMachine *infrav1.OpenStackMachine) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
-	var cloud clientconfig.Cloud
-	var caCert []byte
+// NewClientFromMachine returns a client with the identity of the machine. Machines without an identity use the
+// identity of their cluster. The region of the machine, if set, overrides the region of the cloud.
+func NewClientFromMachine(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	var providerClient *gophercloud.ProviderClient
+	var clientOpts *clientconfig.ClientOpts
+	var projectID string
+	var err error
+	if openStackMachine.Spec.IdentityRef == nil {
+		providerClient, clientOpts, projectID, err = NewClientFromCluster(ctx, ctrlClient, openStackCluster)
+	} else {
+		var creds credentials
+		creds, err = getCredentialsFromSecret(ctx, ctrlClient, openStackMachine.Namespace, openStackMachine.Spec.IdentityRef.Name, openStackMachine.Spec.CloudName)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		providerClient, clientOpts, projectID, err = newClientForCloud(openStackMachine.Spec.CloudName, creds)
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// The service clients are looked up in the catalog by the region of the client options
+	if openStackMachine.Spec.Region != "" {
+		clientOpts.RegionName = openStackMachine.Spec.Region
+	}
+	return providerClient, clientOpts, projectID, nil
+}
+
+// MachineHasClusterIdentity returns true if the machine uses the same identity as its cluster, so that it manages
+// its resources in the project of the cluster.
+func MachineHasClusterIdentity(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) bool {
+	machineRef, clusterRef := openStackMachine.Spec.IdentityRef, openStackCluster.Spec.IdentityRef
+	if machineRef == nil {
+		return true
+	}
+	return clusterRef != nil && machineRef.Name == clusterRef.Name && openStackMachine.Spec.CloudName == openStackCluster.Spec.CloudName
+}
+
+func NewClientFromMachinePool(ctx context.Context, ctrlClient client.Client, openStackMachinePool *infrav1.OpenStackMachinePool) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	var cloudName string
+	var creds credentials
 
-	if openStackMachine.Spec.IdentityRef != nil {
+	if openStackMachinePool.Spec.Template.IdentityRef != nil {
 		var err error
-		cloud, caCert, err = getCloudFromSecret(ctx, ctrlClient, openStackMachine.Namespace, openStackMachine.Spec.IdentityRef.Name, openStackMachine.Spec.CloudName)
+		cloudName = openStackMachinePool.Spec.Template.CloudName
+		creds, err = getCredentialsFromSecret(ctx, ctrlClient, openStackMachinePool.Namespace, openStackMachinePool.Spec.Template.IdentityRef.Name, cloudName)
 		if err != nil {
 			return nil, nil, "", err
 		}
 	}
-	return NewClient(cloud, caCert)
+	return newClientForCloud(cloudName, creds)
+}
+
+func NewClientFromImage(ctx context.Context, ctrlClient client.Client, openStackImage *infrav1.OpenStackImage) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	var cloudName string
+	var creds credentials
+
+	if openStackImage.Spec.IdentityRef != nil {
+		var err error
+		cloudName = openStackImage.Spec.CloudName
+		creds, err = getCredentialsFromSecret(ctx, ctrlClient, openStackImage.Namespace, openStackImage.Spec.IdentityRef.Name, cloudName)
+		if err != nil {
+			return nil, nil, "", err
+		}
+	}
+	return newClientForCloud(cloudName, creds)
 }
 
 func NewClientFromCluster(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
-	var cloud clientconfig.Cloud
-	var caCert []byte
+	var cloudName string
+	var creds credentials
 
 	if openStackCluster.Spec.IdentityRef != nil {
 		var err error
-		cloud, caCert, err = getCloudFromSecret(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef.Name, openStackCluster.Spec.CloudName)
+		cloudName = openStackCluster.Spec.CloudName
+		creds, err = getCredentialsFromSecret(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef.Name, cloudName)
 		if err != nil {
 			return nil, nil, "", err
 		}
 	}
-	return NewClient(cloud, caCert)
+	return newClientForCloud(cloudName, creds)
 }
 
+// NewClient returns a provider client for the credentials of the cloud. Authenticated clients are cached, so
+// that a client with a valid token is reused instead of authenticating again.
 func NewClient(cloud clientconfig.Cloud, caCert []byte) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return defaultClientCache.get(credentials{cloud: cloud, caCert: caCert})
+}
+
+// newClientForCloud returns a provider client for the credentials of the named cloud of an identity secret. The
+// name is set in the client options, e.g. to resolve the flavor aliases of the cloud.
+func newClientForCloud(cloudName string, creds credentials) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	providerClient, clientOpts, projectID, err := defaultClientCache.get(creds)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	clientOpts.Cloud = cloudName
+	return providerClient, clientOpts, projectID, nil
+}
+
+// newClient authenticates a new provider client.
+func newClient(creds credentials) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	cloud := creds.cloud
 	clientOpts := new(clientconfig.ClientOpts)
 	if cloud.AuthInfo != nil {
 		clientOpts.AuthInfo = cloud.AuthInfo
@@ -83,34 +164,43 @@ func NewClient(cloud clientconfig.Cloud, caCert []byte) (*gophercloud.ProviderCl
 		return nil, nil, "", fmt.Errorf("auth option failed for cloud %v: %v", cloud.Cloud, err)
 	}
 	opts.AllowReauth = true
+	if creds.trustID != "" && opts.Scope != nil && *opts.Scope != (gophercloud.AuthScope{}) {
+		return nil, nil, "", fmt.Errorf("cloud %v is the trustee of trust %v, its token cannot also be scoped to a project or domain", cloud.Cloud, creds.trustID)
+	}
 
 	provider, err := openstack.NewClient(opts.IdentityEndpoint)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("create providerClient err: %v", err)
 	}
+	provider.RetryFunc = clients.RetryFunc
 
 	config := &tls.Config{
-		RootCAs:    x509.NewCertPool(),
-		MinVersion: tls.VersionTLS12,
+		RootCAs: x509.NewCertPool(),
 	}
+	tlsconfig.Apply(config)
 	if cloud.Verify != nil {
 		config.InsecureSkipVerify = !*cloud.Verify
 	}
-	if caCert != nil {
-		config.RootCAs.AppendCertsFromPEM(caCert)
+	if creds.caCert != nil {
+		config.RootCAs.AppendCertsFromPEM(creds.caCert)
 	}
 
-	provider.HTTPClient.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
+	provider.HTTPClient.Transport = ratelimit.Transport(&http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config})
 	if klog.V(6).Enabled() {
 		provider.HTTPClient.Transport = &osclient.RoundTripper{
 			Rt:     provider.HTTPClient.Transport,
 			Logger: &defaultLogger{},
 		}
 	}
-	err = openstack.Authenticate(provider, *opts)
+	if creds.trustID != "" {
+		err = openstack.AuthenticateV3(provider, &trusts.AuthOptsExt{AuthOptionsBuilder: opts, TrustID: creds.trustID}, gophercloud.EndpointOpts{})
+	} else {
+		err = openstack.Authenticate(provider, *opts)
+	}
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("providerClient authentication err: %v", err)
 	}
+	provider.EndpointLocator = ratelimit.EndpointLocator(provider.EndpointLocator)
 
 	projectID, err := getProjectIDFromAuthResult(provider.GetAuthResult())
 	if err != nil {
@@ -127,16 +217,14 @@ func (defaultLogger) Printf(format string, args ...interface{}) {
 	klog.V(6).Infof(format, args...)
 }
 
-// getCloudFromSecret extract a Cloud from the given namespace:secretName.
-func getCloudFromSecret(ctx context.Context, ctrlClient client.Client, secretNamespace string, secretName string, cloudName string) (clientconfig.Cloud, []byte, error) {
-	emptyCloud := clientconfig.Cloud{}
-
+// getCredentialsFromSecret extracts the credentials of a cloud from the given namespace:secretName.
+func getCredentialsFromSecret(ctx context.Context, ctrlClient client.Client, secretNamespace string, secretName string, cloudName string) (credentials, error) {
 	if secretName == "" {
-		return emptyCloud, nil, nil
+		return credentials{}, nil
 	}
 
 	if cloudName == "" {
-		return emptyCloud, nil, fmt.Errorf("secret name set to %v but no cloud was specified. Please set cloud_name in your machine spec", secretName)
+		return credentials{}, fmt.Errorf("secret name set to %v but no cloud was specified. Please set cloud_name in your machine spec", secretName)
 	}
 
 	secret := &corev1.Secret{}
@@ -145,26 +233,25 @@ func getCloudFromSecret(ctx context.Context, ctrlClient client.Client, secretNam
 		Name:      secretName,
 	}, secret)
 	if err != nil {
-		return emptyCloud, nil, err
+		return credentials{}, err
 	}
 
 	content, ok := secret.Data[cloudsSecretKey]
 	if !ok {
-		return emptyCloud, nil, fmt.Errorf("OpenStack credentials secret %v did not contain key %v",
+		return credentials{}, fmt.Errorf("OpenStack credentials secret %v did not contain key %v",
 			secretName, cloudsSecretKey)
 	}
 	var clouds clientconfig.Clouds
 	if err = yaml.Unmarshal(content, &clouds); err != nil {
-		return emptyCloud, nil, fmt.Errorf("failed to unmarshal clouds credentials stored in secret %v: %v", secretName, err)
-	}
-
-	// get caCert
-	caCert, ok := secret.Data[caSecretKey]
-	if !ok {
-		return clouds.Clouds[cloudName], nil, nil
+		return credentials{}, fmt.Errorf("failed to unmarshal clouds credentials stored in secret %v: %v", secretName, err)
 	}
 
-	return clouds.Clouds[cloudName], caCert, nil
+	return credentials{
+		cloud:   clouds.Clouds[cloudName],
+		caCert:  secret.Data[caSecretKey],
+		trustID: string(secret.Data[trustSecretKey]),
+		secret:  secretCloud{secret: types.NamespacedName{Namespace: secretNamespace, Name: secretName}, cloudName: cloudName},
+	}, nil
 }
 
 // getProjectIDFromAuthResult handles different auth mechanisms to retrieve the
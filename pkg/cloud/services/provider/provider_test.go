@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func TestMachineHasClusterIdentity(t *testing.T) {
+	identity := func(name string) *infrav1.OpenStackIdentityReference {
+		return &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: name}
+	}
+
+	tests := []struct {
+		name          string
+		clusterRef    *infrav1.OpenStackIdentityReference
+		clusterCloud  string
+		machineRef    *infrav1.OpenStackIdentityReference
+		machineCloud  string
+		wantIdentical bool
+	}{
+		{
+			name:          "Machine without identity",
+			clusterRef:    identity("cluster-cloud-config"),
+			clusterCloud:  "openstack",
+			wantIdentical: true,
+		},
+		{
+			name:          "Machine with the cluster identity",
+			clusterRef:    identity("cluster-cloud-config"),
+			clusterCloud:  "openstack",
+			machineRef:    identity("cluster-cloud-config"),
+			machineCloud:  "openstack",
+			wantIdentical: true,
+		},
+		{
+			name:          "Machine with a different secret",
+			clusterRef:    identity("cluster-cloud-config"),
+			clusterCloud:  "openstack",
+			machineRef:    identity("tenant-cloud-config"),
+			machineCloud:  "openstack",
+			wantIdentical: false,
+		},
+		{
+			name:          "Machine with a different cloud",
+			clusterRef:    identity("cluster-cloud-config"),
+			clusterCloud:  "openstack",
+			machineRef:    identity("cluster-cloud-config"),
+			machineCloud:  "tenant",
+			wantIdentical: false,
+		},
+		{
+			name:          "Cluster without identity",
+			machineRef:    identity("tenant-cloud-config"),
+			machineCloud:  "tenant",
+			wantIdentical: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{IdentityRef: tt.clusterRef, CloudName: tt.clusterCloud},
+			}
+			openStackMachine := &infrav1.OpenStackMachine{
+				Spec: infrav1.OpenStackMachineSpec{IdentityRef: tt.machineRef, CloudName: tt.machineCloud},
+			}
+			g.Expect(MachineHasClusterIdentity(openStackCluster, openStackMachine)).To(Equal(tt.wantIdentical))
+		})
+	}
+}
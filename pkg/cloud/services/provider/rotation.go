@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsIdentitySecret returns true if cached clients were created with the credentials of the secret.
+func IsIdentitySecret(secret types.NamespacedName) bool {
+	return len(defaultClientCache.cloudNames(secret)) > 0
+}
+
+// RotateCredentials re-authenticates with the credentials of an identity secret which was replaced, e.g. with a
+// new application credential or trust, and evicts the cached clients of its previous credentials, so that
+// reconciles switch to the new credentials without a restart of the controller. The clients of a deleted secret
+// are evicted.
+func RotateCredentials(ctx context.Context, ctrlClient client.Client, secret types.NamespacedName) error {
+	for _, cloudName := range defaultClientCache.cloudNames(secret) {
+		source := secretCloud{secret: secret, cloudName: cloudName}
+		creds, err := getCredentialsFromSecret(ctx, ctrlClient, secret.Namespace, secret.Name, cloudName)
+		if apierrors.IsNotFound(err) {
+			defaultClientCache.forget(source)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if creds.cloud.AuthInfo == nil {
+			// The cloud was removed from the secret
+			defaultClientCache.forget(source)
+			continue
+		}
+		if err := defaultClientCache.rotate(creds); err != nil {
+			return fmt.Errorf("authenticate with the new credentials of cloud %s: %w", cloudName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions provides typed access to the state of CAPO objects for controllers which consume it, e.g.
+// autoscalers or fleet managers. It lists the conditions of each kind and the reasons they are reported with, and
+// summarises the conditions of an object, so that consumers do not depend on string literals.
+package conditions
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// MachineConditionTypes are the conditions of an OpenStackMachine.
+var MachineConditionTypes = []clusterv1.ConditionType{
+	clusterv1.ReadyCondition,
+	infrav1.PortsReadyCondition,
+	infrav1.ServerCreatedCondition,
+	infrav1.InstanceReadyCondition,
+	infrav1.FloatingIPReadyCondition,
+	infrav1.APIServerIngressReadyCondition,
+	infrav1.KeyPairReadyCondition,
+	infrav1.ServerGroupReadyCondition,
+	infrav1.ServerCreateOptsUpToDateCondition,
+}
+
+// ClusterConditionTypes are the conditions of an OpenStackCluster.
+var ClusterConditionTypes = []clusterv1.ConditionType{
+	clusterv1.ReadyCondition,
+	infrav1.NetworkReadyCondition,
+	infrav1.SubnetsReadyCondition,
+	infrav1.RouterReadyCondition,
+	infrav1.SecurityGroupsReadyCondition,
+	infrav1.LoadBalancerReadyCondition,
+	infrav1.BastionReadyCondition,
+}
+
+// MachinePoolConditionTypes are the conditions of an OpenStackMachinePool.
+var MachinePoolConditionTypes = []clusterv1.ConditionType{
+	clusterv1.ReadyCondition,
+	infrav1.InstancesReadyCondition,
+}
+
+// ImageConditionTypes are the conditions of an OpenStackImage.
+var ImageConditionTypes = []clusterv1.ConditionType{
+	clusterv1.ReadyCondition,
+	infrav1.ImageReadyCondition,
+}
+
+// openStackErrorReasons are the reasons of the conditions which report on OpenStack resources when OpenStack rejected
+// a request.
+var openStackErrorReasons = []string{
+	infrav1.ResourceNotFoundReason,
+	infrav1.ForbiddenReason,
+	infrav1.InvalidRequestReason,
+	infrav1.QuotaExceededReason,
+	infrav1.ResourceBusyReason,
+	infrav1.OpenStackUnavailableReason,
+}
+
+// withOpenStackErrorReasons returns the reasons followed by openStackErrorReasons.
+func withOpenStackErrorReasons(reasons ...string) []string {
+	return append(reasons, openStackErrorReasons...)
+}
+
+// reasons are the reasons each condition is reported with when it is not true. The Ready summary reports the reason
+// of the condition it summarises.
+var reasons = map[clusterv1.ConditionType][]string{
+	infrav1.PortsReadyCondition: withOpenStackErrorReasons(
+		infrav1.PortCreateFailedReason,
+	),
+	infrav1.ServerCreatedCondition: withOpenStackErrorReasons(
+		infrav1.InstanceCreateFailedReason,
+		infrav1.ImageChecksumMismatchReason,
+		infrav1.VolumeAvailabilityZoneMismatchReason,
+		infrav1.NoValidResourceProviderReason,
+		infrav1.ReservationNotActiveReason,
+	),
+	infrav1.InstanceReadyCondition: {
+		infrav1.WaitingForClusterInfrastructureReason,
+		infrav1.WaitingForBootstrapDataReason,
+		infrav1.WaitingForIPAddressReason,
+		infrav1.WaitingForImageReason,
+		infrav1.InvalidMachineSpecReason,
+		infrav1.InstanceCreateFailedReason,
+		infrav1.ImageChecksumMismatchReason,
+		infrav1.VolumeAvailabilityZoneMismatchReason,
+		infrav1.NoValidResourceProviderReason,
+		infrav1.ReservationNotActiveReason,
+		infrav1.InstanceNotFoundReason,
+		infrav1.InstanceStateErrorReason,
+		infrav1.InstanceDeletedReason,
+		infrav1.InstanceNotReadyReason,
+		infrav1.InstanceDeleteFailedReason,
+		infrav1.InstanceEvacuatingReason,
+	},
+	infrav1.FloatingIPReadyCondition: withOpenStackErrorReasons(
+		infrav1.FloatingIPErrorReason,
+		infrav1.WaitingForLoadBalancerRemovalReason,
+	),
+	infrav1.APIServerIngressReadyCondition: {
+		infrav1.LoadBalancerMemberErrorReason,
+		infrav1.FloatingIPErrorReason,
+		infrav1.WaitingForLoadBalancerRemovalReason,
+	},
+	infrav1.KeyPairReadyCondition: {
+		infrav1.KeyPairNotFoundReason,
+		infrav1.KeyPairImportFailedReason,
+	},
+	infrav1.ServerGroupReadyCondition: {
+		infrav1.NotServerGroupMemberReason,
+		infrav1.ServerGroupPolicyViolatedReason,
+	},
+	infrav1.ServerCreateOptsUpToDateCondition: {
+		infrav1.ServerCreateOptsChangedReason,
+	},
+	infrav1.InstancesReadyCondition: {
+		infrav1.WaitingForClusterInfrastructureReason,
+		infrav1.WaitingForBootstrapDataReason,
+		infrav1.InstancesScalingReason,
+		infrav1.InstanceCreateFailedReason,
+		infrav1.ImageChecksumMismatchReason,
+		infrav1.VolumeAvailabilityZoneMismatchReason,
+		infrav1.NoValidResourceProviderReason,
+		infrav1.ReservationNotActiveReason,
+		infrav1.InstanceDeleteFailedReason,
+	},
+	infrav1.ImageReadyCondition: {
+		infrav1.ImageImportingReason,
+		infrav1.ImageCreateFailedReason,
+		infrav1.ImageDeleteFailedReason,
+	},
+	infrav1.NetworkReadyCondition:        withOpenStackErrorReasons(infrav1.NetworkReconcileFailedReason),
+	infrav1.SubnetsReadyCondition:        withOpenStackErrorReasons(infrav1.SubnetsReconcileFailedReason),
+	infrav1.RouterReadyCondition:         withOpenStackErrorReasons(infrav1.RouterReconcileFailedReason),
+	infrav1.SecurityGroupsReadyCondition: withOpenStackErrorReasons(infrav1.SecurityGroupsReconcileFailedReason),
+	infrav1.LoadBalancerReadyCondition:   withOpenStackErrorReasons(infrav1.LoadBalancerReconcileFailedReason),
+	infrav1.BastionReadyCondition:        withOpenStackErrorReasons(infrav1.BastionReconcileFailedReason),
+}
+
+// waitingReasons are the reasons of conditions which wait for other objects and resolve without intervention.
+var waitingReasons = map[string]bool{
+	infrav1.WaitingForClusterInfrastructureReason: true,
+	infrav1.WaitingForBootstrapDataReason:         true,
+	infrav1.WaitingForIPAddressReason:             true,
+	infrav1.WaitingForImageReason:                 true,
+	infrav1.WaitingForLoadBalancerRemovalReason:   true,
+	infrav1.InstanceNotReadyReason:                true,
+	infrav1.InstancesScalingReason:                true,
+	infrav1.ImageImportingReason:                  true,
+	infrav1.ResourceBusyReason:                    true,
+}
+
+// terminalReasons are the reasons of conditions which do not resolve until the spec of the object or the cloud is
+// changed.
+var terminalReasons = map[string]bool{
+	infrav1.InvalidMachineSpecReason:             true,
+	infrav1.ImageChecksumMismatchReason:          true,
+	infrav1.VolumeAvailabilityZoneMismatchReason: true,
+	infrav1.InstanceStateErrorReason:             true,
+	infrav1.InstanceDeletedReason:                true,
+	infrav1.ResourceNotFoundReason:               true,
+	infrav1.ForbiddenReason:                      true,
+	infrav1.InvalidRequestReason:                 true,
+}
+
+// Reasons returns the reasons the condition is reported with when it is not true.
+func Reasons(conditionType clusterv1.ConditionType) []string {
+	return append([]string{}, reasons[conditionType]...)
+}
+
+// IsWaitingReason returns whether a condition with the reason waits for other objects, and resolves without
+// intervention.
+func IsWaitingReason(reason string) bool {
+	return waitingReasons[reason]
+}
+
+// IsTerminalReason returns whether a condition with the reason does not resolve until the spec of the object or the
+// cloud is changed.
+func IsTerminalReason(reason string) bool {
+	return terminalReasons[reason]
+}
+
+// Summary is the aggregated state of the conditions of an object.
+type Summary struct {
+	// Ready is whether the Ready condition of the object is true.
+	Ready bool
+	// Waiting are the conditions which are not true because they wait for other objects.
+	Waiting []clusterv1.Condition
+	// Terminal are the conditions which are not true and do not resolve until the spec of the object or the cloud
+	// is changed.
+	Terminal []clusterv1.Condition
+	// Failed are the other conditions which are false with severity error.
+	Failed []clusterv1.Condition
+	// Degraded are the other conditions which are not true.
+	Degraded []clusterv1.Condition
+}
+
+// Summarize aggregates the given conditions of the object. The Ready condition only determines Summary.Ready, as it
+// summarises the other conditions.
+func Summarize(obj conditions.Getter, conditionTypes []clusterv1.ConditionType) Summary {
+	summary := Summary{Ready: conditions.IsTrue(obj, clusterv1.ReadyCondition)}
+	for _, conditionType := range conditionTypes {
+		if conditionType == clusterv1.ReadyCondition {
+			continue
+		}
+		condition := conditions.Get(obj, conditionType)
+		if condition == nil || condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		switch {
+		case IsWaitingReason(condition.Reason):
+			summary.Waiting = append(summary.Waiting, *condition)
+		case IsTerminalReason(condition.Reason):
+			summary.Terminal = append(summary.Terminal, *condition)
+		case condition.Status == corev1.ConditionFalse && condition.Severity == clusterv1.ConditionSeverityError:
+			summary.Failed = append(summary.Failed, *condition)
+		default:
+			summary.Degraded = append(summary.Degraded, *condition)
+		}
+	}
+	return summary
+}
+
+// SummarizeMachine aggregates the conditions of an OpenStackMachine.
+func SummarizeMachine(openStackMachine *infrav1.OpenStackMachine) Summary {
+	return Summarize(openStackMachine, MachineConditionTypes)
+}
+
+// SummarizeCluster aggregates the conditions of an OpenStackCluster.
+func SummarizeCluster(openStackCluster *infrav1.OpenStackCluster) Summary {
+	return Summarize(openStackCluster, ClusterConditionTypes)
+}
+
+// SummarizeMachinePool aggregates the conditions of an OpenStackMachinePool.
+func SummarizeMachinePool(openStackMachinePool *infrav1.OpenStackMachinePool) Summary {
+	return Summarize(openStackMachinePool, MachinePoolConditionTypes)
+}
+
+// SummarizeImage aggregates the conditions of an OpenStackImage.
+func SummarizeImage(openStackImage *infrav1.OpenStackImage) Summary {
+	return Summarize(openStackImage, ImageConditionTypes)
+}
+
+// InstanceReady returns the InstanceReady condition of an OpenStackMachine, or nil if it is not set.
+func InstanceReady(openStackMachine *infrav1.OpenStackMachine) *clusterv1.Condition {
+	return conditions.Get(openStackMachine, infrav1.InstanceReadyCondition)
+}
+
+// APIServerIngressReady returns the APIServerIngressReady condition of an OpenStackMachine, or nil if it is not set.
+func APIServerIngressReady(openStackMachine *infrav1.OpenStackMachine) *clusterv1.Condition {
+	return conditions.Get(openStackMachine, infrav1.APIServerIngressReadyCondition)
+}
+
+// InstancesReady returns the InstancesReady condition of an OpenStackMachinePool, or nil if it is not set.
+func InstancesReady(openStackMachinePool *infrav1.OpenStackMachinePool) *clusterv1.Condition {
+	return conditions.Get(openStackMachinePool, infrav1.InstancesReadyCondition)
+}
+
+// ImageReady returns the ImageReady condition of an OpenStackImage, or nil if it is not set.
+func ImageReady(openStackImage *infrav1.OpenStackImage) *clusterv1.Condition {
+	return conditions.Get(openStackImage, infrav1.ImageReadyCondition)
+}
+
+// ClusterFailure returns the reason and message of the terminal failure of an OpenStackCluster, and whether it failed.
+func ClusterFailure(openStackCluster *infrav1.OpenStackCluster) (string, string, bool) {
+	if openStackCluster.Status.FailureReason == nil {
+		return "", "", false
+	}
+	var message string
+	if openStackCluster.Status.FailureMessage != nil {
+		message = *openStackCluster.Status.FailureMessage
+	}
+	return string(*openStackCluster.Status.FailureReason), message, true
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func TestReasons(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Reasons(infrav1.KeyPairReadyCondition)).To(ConsistOf(infrav1.KeyPairNotFoundReason, infrav1.KeyPairImportFailedReason))
+	g.Expect(Reasons(clusterv1.ReadyCondition)).To(BeEmpty())
+
+	// The returned reasons are a copy
+	Reasons(infrav1.KeyPairReadyCondition)[0] = "changed"
+	g.Expect(Reasons(infrav1.KeyPairReadyCondition)).To(ContainElement(infrav1.KeyPairNotFoundReason))
+}
+
+func TestReasonsAreClassified(t *testing.T) {
+	g := NewWithT(t)
+
+	// Waiting reasons resolve without intervention, so they must not be terminal
+	for reason := range waitingReasons {
+		g.Expect(IsTerminalReason(reason)).To(BeFalse(), reason)
+	}
+	// Every classified reason is reported by some condition
+	known := map[string]bool{}
+	for _, conditionReasons := range reasons {
+		for _, reason := range conditionReasons {
+			known[reason] = true
+		}
+	}
+	for reason := range waitingReasons {
+		g.Expect(known).To(HaveKey(reason))
+	}
+	for reason := range terminalReasons {
+		g.Expect(known).To(HaveKey(reason))
+	}
+}
+
+func TestSummarizeMachine(t *testing.T) {
+	tests := []struct {
+		name string
+		mark func(m *infrav1.OpenStackMachine)
+		want func(g *WithT, summary Summary)
+	}{
+		{
+			name: "ready machine",
+			mark: func(m *infrav1.OpenStackMachine) {
+				conditions.MarkTrue(m, infrav1.InstanceReadyCondition)
+				conditions.MarkTrue(m, clusterv1.ReadyCondition)
+			},
+			want: func(g *WithT, summary Summary) {
+				g.Expect(summary).To(Equal(Summary{Ready: true}))
+			},
+		},
+		{
+			name: "machine waiting for bootstrap data",
+			mark: func(m *infrav1.OpenStackMachine) {
+				conditions.MarkFalse(m, infrav1.InstanceReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			want: func(g *WithT, summary Summary) {
+				g.Expect(summary.Ready).To(BeFalse())
+				g.Expect(summary.Waiting).To(HaveLen(1))
+				g.Expect(summary.Waiting[0].Type).To(Equal(infrav1.InstanceReadyCondition))
+				g.Expect(summary.Terminal).To(BeEmpty())
+			},
+		},
+		{
+			name: "machine with invalid spec",
+			mark: func(m *infrav1.OpenStackMachine) {
+				conditions.MarkFalse(m, infrav1.InstanceReadyCondition, infrav1.InvalidMachineSpecReason, clusterv1.ConditionSeverityError, "invalid")
+			},
+			want: func(g *WithT, summary Summary) {
+				g.Expect(summary.Terminal).To(HaveLen(1))
+				g.Expect(summary.Failed).To(BeEmpty())
+			},
+		},
+		{
+			name: "failed and degraded conditions",
+			mark: func(m *infrav1.OpenStackMachine) {
+				conditions.MarkFalse(m, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, "quota exceeded")
+				conditions.MarkFalse(m, infrav1.ServerGroupReadyCondition, infrav1.NotServerGroupMemberReason, clusterv1.ConditionSeverityWarning, "")
+				conditions.MarkTrue(m, infrav1.KeyPairReadyCondition)
+			},
+			want: func(g *WithT, summary Summary) {
+				g.Expect(summary.Failed).To(HaveLen(1))
+				g.Expect(summary.Failed[0].Reason).To(Equal(infrav1.InstanceCreateFailedReason))
+				g.Expect(summary.Degraded).To(HaveLen(1))
+				g.Expect(summary.Degraded[0].Type).To(Equal(infrav1.ServerGroupReadyCondition))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackMachine := &infrav1.OpenStackMachine{}
+			tt.mark(openStackMachine)
+			tt.want(g, SummarizeMachine(openStackMachine))
+		})
+	}
+}
+
+func TestInstanceReady(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackMachine := &infrav1.OpenStackMachine{}
+	g.Expect(InstanceReady(openStackMachine)).To(BeNil())
+
+	conditions.MarkUnknown(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, "")
+	g.Expect(InstanceReady(openStackMachine).Status).To(Equal(corev1.ConditionUnknown))
+	g.Expect(InstanceReady(openStackMachine).Reason).To(Equal(infrav1.InstanceNotReadyReason))
+}
+
+func TestSummarizeCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	conditions.MarkTrue(openStackCluster, infrav1.NetworkReadyCondition)
+	conditions.MarkFalse(openStackCluster, infrav1.SubnetsReadyCondition, infrav1.ResourceNotFoundReason, clusterv1.ConditionSeverityError, "No subnet matches the filter")
+	conditions.MarkFalse(openStackCluster, infrav1.LoadBalancerReadyCondition, infrav1.ResourceBusyReason, clusterv1.ConditionSeverityWarning, "load balancer is immutable")
+	conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.QuotaExceededReason, clusterv1.ConditionSeverityError, "quota exceeded")
+
+	summary := SummarizeCluster(openStackCluster)
+	g.Expect(summary.Ready).To(BeFalse())
+	g.Expect(summary.Terminal).To(HaveLen(1))
+	g.Expect(summary.Terminal[0].Type).To(Equal(infrav1.SubnetsReadyCondition))
+	g.Expect(summary.Waiting).To(HaveLen(1))
+	g.Expect(summary.Waiting[0].Type).To(Equal(infrav1.LoadBalancerReadyCondition))
+	g.Expect(summary.Failed).To(HaveLen(1))
+	g.Expect(summary.Failed[0].Type).To(Equal(infrav1.BastionReadyCondition))
+}
+
+func TestClusterFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	_, _, failed := ClusterFailure(openStackCluster)
+	g.Expect(failed).To(BeFalse())
+
+	reason := capierrors.InvalidConfigurationClusterError
+	openStackCluster.Status.FailureReason = &reason
+	openStackCluster.Status.FailureMessage = pointer.String("invalid external network")
+	gotReason, message, failed := ClusterFailure(openStackCluster)
+	g.Expect(failed).To(BeTrue())
+	g.Expect(gotReason).To(Equal(string(capierrors.InvalidConfigurationClusterError)))
+	g.Expect(message).To(Equal("invalid external network"))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package metrics
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ type OpenstackPrometheusMetrics struct {
 	Duration *prometheus.HistogramVec
 	Total    *prometheus.CounterVec
 	Errors   *prometheus.CounterVec
+	Inflight *prometheus.GaugeVec
 }
 
 // MetricPrometheusContext indicates the context for OpenStack metrics.
@@ -39,11 +41,14 @@ type MetricPrometheusContext struct {
 	Metrics    *OpenstackPrometheusMetrics
 }
 
-// NewMetricPrometheusContext creates a new MetricContext.
+// NewMetricPrometheusContext creates a new MetricContext for a call which is about to be sent.
+// The call is in flight until it is observed.
 func NewMetricPrometheusContext(resource string, request string) *MetricPrometheusContext {
+	apiRequestPrometheusMetrics.Inflight.WithLabelValues(resource).Inc()
 	return &MetricPrometheusContext{
 		Start:      time.Now(),
-		Attributes: []string{resource + "_" + request},
+		Attributes: []string{resource, request},
+		Metrics:    apiRequestPrometheusMetrics,
 	}
 }
 
@@ -74,41 +79,61 @@ func (mc *MetricPrometheusContext) ObserveRequestIgnoreNotFoundorConflict(err er
 	return mc.ObserveRequest(err)
 }
 
-// Observe records the request latency and counts the errors.
+// Observe records the request latency and counts the errors by the class of their status code.
 func (mc *MetricPrometheusContext) Observe(om *OpenstackPrometheusMetrics, err error) error {
 	if om == nil {
 		// mc.RequestMetrics not set, ignore this request
 		return err
 	}
 
+	if om.Inflight != nil && len(mc.Attributes) > 0 {
+		om.Inflight.WithLabelValues(mc.Attributes[0]).Dec()
+	}
 	om.Duration.WithLabelValues(mc.Attributes...).Observe(
 		time.Since(mc.Start).Seconds())
 	om.Total.WithLabelValues(mc.Attributes...).Inc()
 	if err != nil {
-		om.Errors.WithLabelValues(mc.Attributes...).Inc()
+		om.Errors.WithLabelValues(append(mc.Attributes, statusCodeClass(err))...).Inc()
 	}
 	return err
 }
 
+// statusCodeClass returns the class of the status code of the response which caused err, e.g.
+// 4xx, or none if the call failed without a response.
+func statusCodeClass(err error) string {
+	code := capoerrors.StatusCode(err)
+	if code < 100 || code > 599 {
+		return "none"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
 var apiRequestPrometheusMetrics = &OpenstackPrometheusMetrics{
 	Duration: prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "capo",
 			Name:      "openstack_api_request_duration_seconds",
 			Help:      "Latency of an OpenStack API call",
-		}, []string{"request"}),
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"resource", "method"}),
 	Total: prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "capo",
 			Name:      "openstack_api_requests_total",
 			Help:      "Total number of OpenStack API calls",
-		}, []string{"request"}),
+		}, []string{"resource", "method"}),
 	Errors: prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "capo",
 			Name:      "openstack_api_request_errors_total",
-			Help:      "Total number of errors for an OpenStack API call",
-		}, []string{"request"}),
+			Help:      "Total number of errors for an OpenStack API call by the class of the status code",
+		}, []string{"resource", "method", "code_class"}),
+	Inflight: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "openstack_api_requests_inflight",
+			Help:      "Number of OpenStack API calls which are in flight",
+		}, []string{"resource"}),
 }
 
 var registerAPIPrometheusMetrics sync.Once
@@ -118,5 +143,368 @@ func RegisterAPIPrometheusMetrics() {
 		metrics.Registry.MustRegister(apiRequestPrometheusMetrics.Duration)
 		metrics.Registry.MustRegister(apiRequestPrometheusMetrics.Total)
 		metrics.Registry.MustRegister(apiRequestPrometheusMetrics.Errors)
+		metrics.Registry.MustRegister(apiRequestPrometheusMetrics.Inflight)
+	})
+}
+
+var deletionPrometheusMetrics = struct {
+	Pending *prometheus.GaugeVec
+	Deleted *prometheus.CounterVec
+	Errors  *prometheus.CounterVec
+}{
+	Pending: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "batch_deletions_pending",
+			Help:      "Number of OpenStack resources waiting to be deleted in a batch",
+		}, []string{"resource"}),
+	Deleted: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "batch_deletions_total",
+			Help:      "Total number of OpenStack resources deleted in a batch",
+		}, []string{"resource"}),
+	Errors: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "batch_deletion_errors_total",
+			Help:      "Total number of failed deletions of OpenStack resources in a batch",
+		}, []string{"resource"}),
+}
+
+var registerDeletionPrometheusMetrics sync.Once
+
+func RegisterDeletionPrometheusMetrics() {
+	registerDeletionPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(deletionPrometheusMetrics.Pending)
+		metrics.Registry.MustRegister(deletionPrometheusMetrics.Deleted)
+		metrics.Registry.MustRegister(deletionPrometheusMetrics.Errors)
 	})
 }
+
+// DeletionsStarted records that a batch of count resources is about to be deleted.
+func DeletionsStarted(resource string, count int) {
+	deletionPrometheusMetrics.Pending.WithLabelValues(resource).Add(float64(count))
+}
+
+// DeletionFinished records the result of a single deletion of a batch.
+func DeletionFinished(resource string, err error) {
+	deletionPrometheusMetrics.Pending.WithLabelValues(resource).Dec()
+	if err != nil {
+		deletionPrometheusMetrics.Errors.WithLabelValues(resource).Inc()
+		return
+	}
+	deletionPrometheusMetrics.Deleted.WithLabelValues(resource).Inc()
+}
+
+var leftoverPrometheusMetrics = struct {
+	Total *prometheus.CounterVec
+}{
+	Total: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "leftover_resources_total",
+			Help:      "Total number of OpenStack resources found by the verification of a deletion",
+		}, []string{"kind", "resource"}),
+}
+
+var registerLeftoverPrometheusMetrics sync.Once
+
+func RegisterLeftoverPrometheusMetrics() {
+	registerLeftoverPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(leftoverPrometheusMetrics.Total)
+	})
+}
+
+// LeftoverResourcesFound records that count resources of the type remained after the deletion of an object of the kind.
+func LeftoverResourcesFound(kind, resource string, count int) {
+	leftoverPrometheusMetrics.Total.WithLabelValues(kind, resource).Add(float64(count))
+}
+
+var inventoryPrometheusMetrics = struct {
+	Instances       *prometheus.GaugeVec
+	VolumeGigabytes *prometheus.GaugeVec
+	FloatingIPs     *prometheus.GaugeVec
+}{
+	Instances: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "cluster_instances",
+			Help:      "Number of OpenStack instances of a cluster by flavor",
+		}, []string{"namespace", "cluster", "flavor"}),
+	VolumeGigabytes: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "cluster_volume_gigabytes",
+			Help:      "Size in GB of the OpenStack volumes of a cluster by volume type",
+		}, []string{"namespace", "cluster", "volume_type"}),
+	FloatingIPs: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "cluster_floating_ips",
+			Help:      "Number of OpenStack floating IPs of a cluster",
+		}, []string{"namespace", "cluster"}),
+}
+
+var registerInventoryPrometheusMetrics sync.Once
+
+func RegisterInventoryPrometheusMetrics() {
+	registerInventoryPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(inventoryPrometheusMetrics.Instances)
+		metrics.Registry.MustRegister(inventoryPrometheusMetrics.VolumeGigabytes)
+		metrics.Registry.MustRegister(inventoryPrometheusMetrics.FloatingIPs)
+	})
+}
+
+// ClusterInventory counts the OpenStack resources of a cluster which are relevant for cost allocation.
+type ClusterInventory struct {
+	// Instances is the number of instances by flavor.
+	Instances map[string]int
+	// VolumeGigabytes is the size of the volumes in GB by volume type.
+	VolumeGigabytes map[string]int
+	// FloatingIPs is the number of floating IPs.
+	FloatingIPs int
+}
+
+// clusterInventoryLabels tracks the flavors and volume types exported for each cluster,
+// so that series which are no longer part of the inventory can be deleted.
+var clusterInventoryLabels = struct {
+	sync.Mutex
+	flavors     map[string][]string
+	volumeTypes map[string][]string
+}{
+	flavors:     map[string][]string{},
+	volumeTypes: map[string][]string{},
+}
+
+// SetClusterInventory exports the inventory of a cluster, replacing the previous one.
+func SetClusterInventory(namespace, cluster string, inventory ClusterInventory) {
+	clusterInventoryLabels.Lock()
+	defer clusterInventoryLabels.Unlock()
+
+	key := namespace + "/" + cluster
+
+	for _, flavor := range clusterInventoryLabels.flavors[key] {
+		if _, ok := inventory.Instances[flavor]; !ok {
+			inventoryPrometheusMetrics.Instances.DeleteLabelValues(namespace, cluster, flavor)
+		}
+	}
+	clusterInventoryLabels.flavors[key] = nil
+	for flavor, count := range inventory.Instances {
+		inventoryPrometheusMetrics.Instances.WithLabelValues(namespace, cluster, flavor).Set(float64(count))
+		clusterInventoryLabels.flavors[key] = append(clusterInventoryLabels.flavors[key], flavor)
+	}
+
+	for _, volumeType := range clusterInventoryLabels.volumeTypes[key] {
+		if _, ok := inventory.VolumeGigabytes[volumeType]; !ok {
+			inventoryPrometheusMetrics.VolumeGigabytes.DeleteLabelValues(namespace, cluster, volumeType)
+		}
+	}
+	clusterInventoryLabels.volumeTypes[key] = nil
+	for volumeType, size := range inventory.VolumeGigabytes {
+		inventoryPrometheusMetrics.VolumeGigabytes.WithLabelValues(namespace, cluster, volumeType).Set(float64(size))
+		clusterInventoryLabels.volumeTypes[key] = append(clusterInventoryLabels.volumeTypes[key], volumeType)
+	}
+
+	inventoryPrometheusMetrics.FloatingIPs.WithLabelValues(namespace, cluster).Set(float64(inventory.FloatingIPs))
+}
+
+// DeleteClusterInventory stops exporting the inventory of a cluster.
+func DeleteClusterInventory(namespace, cluster string) {
+	clusterInventoryLabels.Lock()
+	defer clusterInventoryLabels.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, flavor := range clusterInventoryLabels.flavors[key] {
+		inventoryPrometheusMetrics.Instances.DeleteLabelValues(namespace, cluster, flavor)
+	}
+	for _, volumeType := range clusterInventoryLabels.volumeTypes[key] {
+		inventoryPrometheusMetrics.VolumeGigabytes.DeleteLabelValues(namespace, cluster, volumeType)
+	}
+	inventoryPrometheusMetrics.FloatingIPs.DeleteLabelValues(namespace, cluster)
+	delete(clusterInventoryLabels.flavors, key)
+	delete(clusterInventoryLabels.volumeTypes, key)
+}
+
+var serverGroupPrometheusMetrics = struct {
+	Violations *prometheus.GaugeVec
+}{
+	Violations: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "capo",
+			Name:      "machine_server_group_violation",
+			Help:      "Whether an OpenStack instance lost the membership or the policy of its server group (1) or not (0)",
+		}, []string{"namespace", "machine"}),
+}
+
+var registerServerGroupPrometheusMetrics sync.Once
+
+func RegisterServerGroupPrometheusMetrics() {
+	registerServerGroupPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(serverGroupPrometheusMetrics.Violations)
+	})
+}
+
+// SetServerGroupViolation records whether the instance of a machine violates its server group.
+func SetServerGroupViolation(namespace, machine string, violated bool) {
+	value := 0.0
+	if violated {
+		value = 1.0
+	}
+	serverGroupPrometheusMetrics.Violations.WithLabelValues(namespace, machine).Set(value)
+}
+
+// DeleteServerGroupViolation removes the series of a machine which is deleted.
+func DeleteServerGroupViolation(namespace, machine string) {
+	serverGroupPrometheusMetrics.Violations.DeleteLabelValues(namespace, machine)
+}
+
+var clusterAPIRequestPrometheusMetrics = struct {
+	Total *prometheus.CounterVec
+}{
+	Total: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "cluster_openstack_api_requests_total",
+			Help:      "Total number of OpenStack API calls made on behalf of a cluster",
+		}, []string{"namespace", "cluster"}),
+}
+
+var registerClusterAPIPrometheusMetrics sync.Once
+
+func RegisterClusterAPIPrometheusMetrics() {
+	registerClusterAPIPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(clusterAPIRequestPrometheusMetrics.Total)
+	})
+}
+
+// ClusterAPIRequest records an OpenStack API call made on behalf of a cluster.
+func ClusterAPIRequest(namespace, cluster string) {
+	clusterAPIRequestPrometheusMetrics.Total.WithLabelValues(namespace, cluster).Inc()
+}
+
+// DeleteClusterAPIRequests removes the series of a cluster which is deleted.
+func DeleteClusterAPIRequests(namespace, cluster string) {
+	clusterAPIRequestPrometheusMetrics.Total.DeleteLabelValues(namespace, cluster)
+}
+
+var apiRetryPrometheusMetrics = struct {
+	Total *prometheus.CounterVec
+}{
+	Total: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "openstack_api_request_retries_total",
+			Help:      "Total number of OpenStack API calls retried after a 429 or 503 response",
+		}, []string{"method", "code"}),
+}
+
+var registerAPIRetryPrometheusMetrics sync.Once
+
+func RegisterAPIRetryPrometheusMetrics() {
+	registerAPIRetryPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(apiRetryPrometheusMetrics.Total)
+	})
+}
+
+// APIRequestRetried records the retry of an OpenStack API call which failed with the status code.
+func APIRequestRetried(method string, code int) {
+	apiRetryPrometheusMetrics.Total.WithLabelValues(method, strconv.Itoa(code)).Inc()
+}
+
+// Reasons for which a provider client re-authenticates with Keystone.
+const (
+	// ReauthReasonExpiring is the renewal of a token which is about to expire.
+	ReauthReasonExpiring = "expiring"
+	// ReauthReasonUnauthorized is the renewal of a token which was rejected with a 401 response.
+	ReauthReasonUnauthorized = "unauthorized"
+)
+
+var reauthPrometheusMetrics = struct {
+	Total *prometheus.CounterVec
+}{
+	Total: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "capo",
+			Name:      "openstack_reauthentications_total",
+			Help:      "Total number of re-authentications with Keystone by the reason and whether they succeeded",
+		}, []string{"auth_url", "reason", "result"}),
+}
+
+var registerReauthPrometheusMetrics sync.Once
+
+func RegisterReauthPrometheusMetrics() {
+	registerReauthPrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(reauthPrometheusMetrics.Total)
+	})
+}
+
+// Reauthenticated records a re-authentication with the Keystone at authURL, which failed if err is set.
+func Reauthenticated(authURL, reason string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	reauthPrometheusMetrics.Total.WithLabelValues(authURL, reason, result).Inc()
+}
+
+// Resources whose reconciliation is timed by the reconcile duration metrics.
+const (
+	ReconcileResourceNetwork       = "network"
+	ReconcileResourceSecurityGroup = "security_group"
+	ReconcileResourceLoadBalancer  = "load_balancer"
+	ReconcileResourceBastion       = "bastion"
+	ReconcileResourceServer        = "server"
+	ReconcileResourcePorts         = "ports"
+	ReconcileResourceVolumes       = "volumes"
+)
+
+var reconcileResources = []string{
+	ReconcileResourceNetwork,
+	ReconcileResourceSecurityGroup,
+	ReconcileResourceLoadBalancer,
+	ReconcileResourceBastion,
+	ReconcileResourceServer,
+	ReconcileResourcePorts,
+	ReconcileResourceVolumes,
+}
+
+var reconcilePrometheusMetrics = struct {
+	Duration *prometheus.HistogramVec
+}{
+	Duration: prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "capo",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Time taken to reconcile the OpenStack resources of a cluster by resource",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"namespace", "cluster", "resource"}),
+}
+
+var registerReconcilePrometheusMetrics sync.Once
+
+func RegisterReconcilePrometheusMetrics() {
+	registerReconcilePrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(reconcilePrometheusMetrics.Duration)
+	})
+}
+
+// ReconcileTimer starts timing the reconciliation of a resource of a cluster. The returned function records
+// the time taken, whether the reconciliation succeeded or not. Only its first call is recorded, so that it can
+// also be deferred to cover early returns.
+func ReconcileTimer(namespace, cluster, resource string) func() {
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			reconcilePrometheusMetrics.Duration.WithLabelValues(namespace, cluster, resource).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// DeleteClusterReconcileDurations removes the series of a cluster which is deleted.
+func DeleteClusterReconcileDurations(namespace, cluster string) {
+	for _, resource := range reconcileResources {
+		reconcilePrometheusMetrics.Duration.DeleteLabelValues(namespace, cluster, resource)
+	}
+}
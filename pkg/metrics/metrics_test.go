@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricPrometheusContext(t *testing.T) {
+	g := NewWithT(t)
+
+	inflight := apiRequestPrometheusMetrics.Inflight.WithLabelValues("test_server")
+	errorCount := func(codeClass string) float64 {
+		return testutil.ToFloat64(apiRequestPrometheusMetrics.Errors.WithLabelValues("test_server", "get", codeClass))
+	}
+
+	mc := NewMetricPrometheusContext("test_server", "get")
+	g.Expect(testutil.ToFloat64(inflight)).To(Equal(1.0))
+	g.Expect(mc.ObserveRequest(nil)).To(Succeed())
+	g.Expect(testutil.ToFloat64(inflight)).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(apiRequestPrometheusMetrics.Total.WithLabelValues("test_server", "get"))).To(Equal(1.0))
+
+	err404 := gophercloud.ErrDefault404{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 404}}
+	_ = NewMetricPrometheusContext("test_server", "get").ObserveRequest(err404)
+	g.Expect(errorCount("4xx")).To(Equal(1.0))
+
+	_ = NewMetricPrometheusContext("test_server", "get").ObserveRequestIgnoreNotFound(err404)
+	g.Expect(errorCount("4xx")).To(Equal(1.0), "not found errors are ignored")
+
+	_ = NewMetricPrometheusContext("test_server", "get").ObserveRequest(gophercloud.ErrDefault503{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 503}})
+	g.Expect(errorCount("5xx")).To(Equal(1.0))
+
+	_ = NewMetricPrometheusContext("test_server", "get").ObserveRequest(errors.New("connection refused"))
+	g.Expect(errorCount("none")).To(Equal(1.0))
+
+	g.Expect(testutil.ToFloat64(inflight)).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(apiRequestPrometheusMetrics.Total.WithLabelValues("test_server", "get"))).To(Equal(5.0))
+}
+
+func TestReconcileTimer(t *testing.T) {
+	g := NewWithT(t)
+
+	done := ReconcileTimer("test", "cluster", ReconcileResourceNetwork)
+	g.Expect(testutil.CollectAndCount(reconcilePrometheusMetrics.Duration)).To(Equal(0))
+	done()
+	done()
+	g.Expect(testutil.CollectAndCount(reconcilePrometheusMetrics.Duration)).To(Equal(1))
+	ReconcileTimer("test", "cluster", ReconcileResourceSecurityGroup)()
+	g.Expect(testutil.CollectAndCount(reconcilePrometheusMetrics.Duration)).To(Equal(2))
+
+	DeleteClusterReconcileDurations("test", "cluster")
+	g.Expect(testutil.CollectAndCount(reconcilePrometheusMetrics.Duration)).To(Equal(0))
+}
+
+func TestReauthenticated(t *testing.T) {
+	g := NewWithT(t)
+
+	Reauthenticated("https://keystone.example.com/v3", ReauthReasonUnauthorized, nil)
+	Reauthenticated("https://keystone.example.com/v3", ReauthReasonUnauthorized, errors.New("test error"))
+	Reauthenticated("https://keystone.example.com/v3", ReauthReasonUnauthorized, nil)
+
+	total := func(result string) float64 {
+		return testutil.ToFloat64(reauthPrometheusMetrics.Total.WithLabelValues("https://keystone.example.com/v3", ReauthReasonUnauthorized, result))
+	}
+	g.Expect(total("success")).To(Equal(2.0))
+	g.Expect(total("failure")).To(Equal(1.0))
+}
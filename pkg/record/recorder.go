@@ -63,3 +63,13 @@ func Warn(object runtime.Object, reason, message string) {
 func Warnf(object runtime.Object, reason, message string, args ...interface{}) {
 	defaultRecorder.Eventf(object, corev1.EventTypeWarning, cases.Title(language.English).String(reason), message, args...)
 }
+
+// AnnotatedEventf is just like Eventf, but attaches the annotations to the event.
+func AnnotatedEventf(object runtime.Object, annotations map[string]string, reason, message string, args ...interface{}) {
+	defaultRecorder.AnnotatedEventf(object, annotations, corev1.EventTypeNormal, cases.Title(language.English).String(reason), message, args...)
+}
+
+// AnnotatedWarnf is just like Warnf, but attaches the annotations to the event.
+func AnnotatedWarnf(object runtime.Object, annotations map[string]string, reason, message string, args ...interface{}) {
+	defaultRecorder.AnnotatedEventf(object, annotations, corev1.EventTypeWarning, cases.Title(language.English).String(reason), message, args...)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package scope
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/utils/openstack/clientconfig"
@@ -29,10 +31,41 @@ import (
 // Gophercloud API Clients (e.g. for Networking/Neutron).
 //
 // The Logger includes context values such as the cluster name.
+//
+// The requests of the API Clients are made with the ReconcileContext, so that
+// they are cancelled with the reconcile.
 type Scope struct {
 	ProviderClient     *gophercloud.ProviderClient
 	ProviderClientOpts *clientconfig.ClientOpts
 	ProjectID          string
 
-	Logger logr.Logger
+	Logger           logr.Logger
+	ReconcileContext context.Context
+}
+
+// Context returns the context the requests of the API Clients are made with.
+// It is the ReconcileContext, or the background context if it is not set.
+func (s *Scope) Context() context.Context {
+	if s.ReconcileContext == nil {
+		return context.Background()
+	}
+	return s.ReconcileContext
+}
+
+// CloudName returns the name of the cloud in the identity secret, or an empty
+// string if the controller credentials are used.
+func (s *Scope) CloudName() string {
+	if s.ProviderClientOpts == nil {
+		return ""
+	}
+	return s.ProviderClientOpts.Cloud
+}
+
+// RegionName returns the region the service clients are looked up in, or an
+// empty string if it is not set.
+func (s *Scope) RegionName() string {
+	if s.ProviderClientOpts == nil {
+		return ""
+	}
+	return s.ProviderClientOpts.RegionName
 }
@@ -0,0 +1,374 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apievents emits a Kubernetes event on the object being reconciled for every call of a provider client
+// which creates, updates or deletes an OpenStack resource, so that the changes CAPO makes to a cloud can be audited
+// with kubectl instead of the logs of the controller. The details of the call are attached to the event as
+// annotations.
+package apievents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// The annotations of the events.
+const (
+	annotationPrefix = "infrastructure.cluster.x-k8s.io/openstack-"
+
+	ResourceTypeAnnotation = annotationPrefix + "resource-type"
+	ResourceNameAnnotation = annotationPrefix + "resource-name"
+	ResourceIDAnnotation   = annotationPrefix + "resource-id"
+	MethodAnnotation       = annotationPrefix + "method"
+	URLAnnotation          = annotationPrefix + "url"
+	StatusCodeAnnotation   = annotationPrefix + "status-code"
+	RequestIDAnnotation    = annotationPrefix + "request-id"
+)
+
+// requestIDHeaders are the response headers carrying the ID of a request, by which it can be found in the logs of
+// the cloud. Older Nova versions only return X-Compute-Request-Id.
+var requestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"}
+
+// maxBodySize bounds the bodies which are read to find the name and ID of a resource.
+const maxBodySize = 1 << 20
+
+// prefixSegments are the segments of paths which group collections, e.g. lbaas in /v2/lbaas/loadbalancers.
+var prefixSegments = map[string]bool{
+	"lbaas": true,
+	"qos":   true,
+	"fwaas": true,
+}
+
+var (
+	versionSegment = regexp.MustCompile(`^v\d+(\.\d+)?$`)
+	projectSegment = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+var (
+	mu      sync.RWMutex
+	enabled bool
+)
+
+// Configure sets whether the events are emitted.
+func Configure(enable bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = enable
+}
+
+// Enabled returns whether the events are emitted.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// Call is a call which creates, updates or deletes an OpenStack resource.
+type Call struct {
+	// Action is Create, Update or Delete.
+	Action string
+	// ResourceType is the type of the resource, e.g. network.
+	ResourceType string
+	// Name and ID identify the resource, as far as they are known from the URL and the bodies of the call.
+	Name string
+	ID   string
+	// SubAction is the action of an update which is made by a sub-resource or an action request, e.g. os-stop for a
+	// server or add_router_interface for a router.
+	SubAction string
+}
+
+// Parse returns the call made by req, or false if req does not change a resource. Calls are identified by the path of
+// their URL, e.g. a POST to /v2.0/networks creates a network and a DELETE of /v2.0/networks/<id> deletes it.
+func Parse(req *http.Request) (Call, bool) {
+	var action string
+	switch req.Method {
+	case http.MethodPost:
+		action = "Create"
+	case http.MethodPut, http.MethodPatch:
+		action = "Update"
+	case http.MethodDelete:
+		action = "Delete"
+	default:
+		return Call{}, false
+	}
+	// Tokens are requested by POST, but do not change the cloud
+	if strings.HasSuffix(req.URL.Path, "/auth/tokens") {
+		return Call{}, false
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		// Drop the API version and prefixes such as the project of Cinder
+		if segment == "" || versionSegment.MatchString(segment) || prefixSegments[segment] || (len(segments) == 0 && projectSegment.MatchString(segment)) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	n := len(segments)
+	if n == 0 {
+		return Call{}, false
+	}
+
+	call := Call{Action: action}
+	switch {
+	case n%2 == 0:
+		// /<collection>/<id>
+		call.ResourceType = singular(segments[n-2])
+		call.ID = segments[n-1]
+	case n == 1 || (action == "Create" && segments[n-1] != "action"):
+		// /<collection>, or a sub-resource such as the volume attachments of a server
+		call.ResourceType = singular(segments[n-1])
+	default:
+		// /<collection>/<id>/<action>
+		call.Action = "Update"
+		call.ResourceType = singular(segments[n-3])
+		call.ID = segments[n-2]
+		call.SubAction = segments[n-1]
+	}
+	return call, true
+}
+
+// singular returns the singular of the name of a collection, e.g. security-group-rule for security-group-rules.
+func singular(collection string) string {
+	switch {
+	case strings.HasSuffix(collection, "ies"):
+		return strings.TrimSuffix(collection, "ies") + "y"
+	case strings.HasSuffix(collection, "s"):
+		return strings.TrimSuffix(collection, "s")
+	}
+	return collection
+}
+
+type resource struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// identify returns the names and IDs of the resources of a JSON body, which holds a single resource or a list of
+// resources under the key of their type, e.g. {"network": {"id": ...}}, or the resource itself like Glance. It also
+// returns the key, which names the action of an action request, e.g. {"os-stop": null}.
+func identify(body []byte) (key, name, id string) {
+	var flat resource
+	if err := json.Unmarshal(body, &flat); err == nil && (flat.Name != "" || flat.ID != "") {
+		return "", flat.Name, flat.ID
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapper); err != nil || len(wrapper) != 1 {
+		return "", "", ""
+	}
+
+	for key, raw := range wrapper {
+		var single resource
+		if err := json.Unmarshal(raw, &single); err == nil {
+			return key, single.Name, single.ID
+		}
+		var list []resource
+		if err := json.Unmarshal(raw, &list); err == nil {
+			names := make([]string, 0, len(list))
+			ids := make([]string, 0, len(list))
+			for _, r := range list {
+				if r.Name != "" {
+					names = append(names, r.Name)
+				}
+				if r.ID != "" {
+					ids = append(ids, r.ID)
+				}
+			}
+			return key, strings.Join(names, ","), strings.Join(ids, ",")
+		}
+		return key, "", ""
+	}
+	return "", "", ""
+}
+
+// isJSON returns whether the header declares a JSON body.
+func isJSON(header http.Header) bool {
+	return strings.Contains(header.Get("Content-Type"), "json")
+}
+
+// requestBody returns a copy of the JSON body of req, without consuming it.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil || !isJSON(req.Header) || req.ContentLength > maxBodySize {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, maxBodySize))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// responseBody returns the JSON body of resp, which is replaced so that it can still be read by the caller.
+func responseBody(resp *http.Response) []byte {
+	if resp.Body == nil || !isJSON(resp.Header) {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// requestID returns the ID of the request from the headers of its response.
+func requestID(resp *http.Response) string {
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// roundTripper emits an event on obj for every call received through it which changes a resource.
+type roundTripper struct {
+	rt  http.RoundTripper
+	obj runtime.Object
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	call, ok := Parse(req)
+	if !ok {
+		return r.rt.RoundTrip(req)
+	}
+
+	key, name, _ := identify(requestBody(req))
+	if call.SubAction == "action" {
+		// The body of an action request names the action, e.g. {"os-stop": null}
+		call.SubAction = key
+	} else {
+		call.Name = name
+	}
+
+	resp, err := r.rt.RoundTrip(req)
+	annotations := map[string]string{
+		MethodAnnotation: req.Method,
+		URLAnnotation:    req.URL.String(),
+	}
+	if resp != nil {
+		annotations[StatusCodeAnnotation] = strconv.Itoa(resp.StatusCode)
+		if id := requestID(resp); id != "" {
+			annotations[RequestIDAnnotation] = id
+		}
+		if call.ID == "" && resp.StatusCode < http.StatusBadRequest {
+			_, responseName, responseID := identify(responseBody(resp))
+			call.ID = responseID
+			if call.Name == "" {
+				call.Name = responseName
+			}
+		}
+	}
+	emit(r.obj, call, annotations, resp, err)
+	return resp, err
+}
+
+// emit emits the event of the call, whose response is resp or which failed with err.
+func emit(obj runtime.Object, call Call, annotations map[string]string, resp *http.Response, err error) {
+	annotations[ResourceTypeAnnotation] = call.ResourceType
+	if call.Name != "" {
+		annotations[ResourceNameAnnotation] = call.Name
+	}
+	if call.ID != "" {
+		annotations[ResourceIDAnnotation] = call.ID
+	}
+
+	var result string
+	if err != nil {
+		result = err.Error()
+	} else {
+		result = resp.Status
+	}
+	if id := annotations[RequestIDAnnotation]; id != "" {
+		result += ", request ID " + id
+	}
+
+	resource := describe(call)
+	switch {
+	case err == nil && resp.StatusCode < http.StatusBadRequest:
+		record.AnnotatedEventf(obj, annotations, "OpenStack"+call.Action, "%s %s: %s", pastTense[call.Action], resource, result)
+	case err == nil && resp.StatusCode == http.StatusNotFound && call.Action == "Delete":
+		// Deletions are retried until the resource is gone
+		record.AnnotatedEventf(obj, annotations, "OpenStack"+call.Action, "Skipped deleting %s, it does not exist: %s", resource, result)
+	default:
+		record.AnnotatedWarnf(obj, annotations, "FailedOpenStack"+call.Action, "Failed to %s %s: %s", strings.ToLower(call.Action), resource, result)
+	}
+}
+
+var pastTense = map[string]string{
+	"Create": "Created",
+	"Update": "Updated",
+	"Delete": "Deleted",
+}
+
+// describe returns the resource of the call as shown in the message of its event, e.g.
+// "server test-0 (id 1234, action os-stop)".
+func describe(call Call) string {
+	var details []string
+	if call.Name != "" && call.ID != "" {
+		details = append(details, "id "+call.ID)
+	}
+	if call.SubAction != "" {
+		details = append(details, "action "+call.SubAction)
+	}
+
+	description := call.ResourceType
+	if id := describeID(call); id != "" {
+		description += " " + id
+	}
+	if len(details) > 0 {
+		description += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+	return description
+}
+
+// describeID returns the name of the resource of the call, or its ID if the name is not known.
+func describeID(call Call) string {
+	if call.Name != "" {
+		return call.Name
+	}
+	return call.ID
+}
+
+// Transport wraps rt so that an event is emitted on obj for every call sent through it which creates, updates or
+// deletes a resource.
+func Transport(rt http.RoundTripper, obj runtime.Object) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &roundTripper{rt: rt, obj: obj}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apievents
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   Call
+		wantOK bool
+	}{
+		{method: http.MethodGet, path: "/v2.0/networks"},
+		{method: http.MethodPost, path: "/v3/auth/tokens"},
+		{method: http.MethodPost, path: "/v2.0/networks", want: Call{Action: "Create", ResourceType: "network"}, wantOK: true},
+		{method: http.MethodPost, path: "/v2.0/security-group-rules", want: Call{Action: "Create", ResourceType: "security-group-rule"}, wantOK: true},
+		{method: http.MethodPut, path: "/v2.0/ports/port-id", want: Call{Action: "Update", ResourceType: "port", ID: "port-id"}, wantOK: true},
+		{method: http.MethodDelete, path: "/v2/lbaas/loadbalancers/lb-id", want: Call{Action: "Delete", ResourceType: "loadbalancer", ID: "lb-id"}, wantOK: true},
+		{
+			method: http.MethodDelete, path: "/v3/0123456789abcdef0123456789abcdef/volumes/volume-id",
+			want: Call{Action: "Delete", ResourceType: "volume", ID: "volume-id"}, wantOK: true,
+		},
+		{method: http.MethodPost, path: "/v2.1/servers/server-id/action", want: Call{Action: "Update", ResourceType: "server", ID: "server-id", SubAction: "action"}, wantOK: true},
+		{
+			method: http.MethodPut, path: "/v2.0/routers/router-id/add_router_interface",
+			want: Call{Action: "Update", ResourceType: "router", ID: "router-id", SubAction: "add_router_interface"}, wantOK: true,
+		},
+		{method: http.MethodPost, path: "/v2.1/servers/server-id/os-volume_attachments", want: Call{Action: "Create", ResourceType: "os-volume_attachment"}, wantOK: true},
+		{method: http.MethodPost, path: "/v2.1/os-keypairs", want: Call{Action: "Create", ResourceType: "os-keypair"}, wantOK: true},
+		{method: http.MethodPost, path: "/v2.0/qos/policies", want: Call{Action: "Create", ResourceType: "policy"}, wantOK: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			g := NewWithT(t)
+			req, err := http.NewRequest(tt.method, "https://openstack.example.com"+tt.path, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			call, ok := Parse(req)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(call).To(Equal(tt.want))
+		})
+	}
+}
+
+// event is an event recorded by recorder.
+type event struct {
+	eventType   string
+	reason      string
+	message     string
+	annotations map[string]string
+}
+
+// recorder records the events emitted through the record package.
+type recorder struct {
+	events []event
+}
+
+func (r *recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *recorder) AnnotatedEventf(_ runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.events = append(r.events, event{eventType: eventtype, reason: reason, message: fmt.Sprintf(messageFmt, args...), annotations: annotations})
+}
+
+func TestTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &recorder{}
+	record.InitFromRecorder(rec)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Openstack-Request-Id", "req-"+r.Method)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2.0/networks":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"network": {"id": "net-id", "name": "cluster"}}`))
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil, &infrav1.OpenStackCluster{})}
+	send := func(method, path, body string) string {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		g.Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	g.Expect(send(http.MethodGet, "/v2.0/networks", "")).To(BeEmpty())
+	g.Expect(rec.events).To(BeEmpty(), "reads are not recorded")
+
+	g.Expect(send(http.MethodPost, "/v2.0/networks", `{"network": {"name": "cluster"}}`)).To(ContainSubstring("net-id"), "response body is still readable")
+	send(http.MethodPost, "/v2.1/servers/server-id/action", `{"os-stop": null}`)
+	send(http.MethodPut, "/v2.0/ports/port-id", `{"port": {"name": "node-0"}}`)
+	send(http.MethodDelete, "/v2.0/ports/port-id", "")
+
+	g.Expect(rec.events).To(HaveLen(4))
+	g.Expect(rec.events[0]).To(Equal(event{
+		eventType: "Normal",
+		reason:    "Openstackcreate",
+		message:   "Created network cluster (id net-id): 201 Created, request ID req-POST",
+		annotations: map[string]string{
+			ResourceTypeAnnotation: "network",
+			ResourceNameAnnotation: "cluster",
+			ResourceIDAnnotation:   "net-id",
+			MethodAnnotation:       http.MethodPost,
+			URLAnnotation:          server.URL + "/v2.0/networks",
+			StatusCodeAnnotation:   "201",
+			RequestIDAnnotation:    "req-POST",
+		},
+	}))
+	g.Expect(rec.events[1].message).To(Equal("Updated server server-id (action os-stop): 202 Accepted, request ID req-POST"))
+	g.Expect(rec.events[2].eventType).To(Equal("Warning"))
+	g.Expect(rec.events[2].reason).To(Equal("Failedopenstackupdate"))
+	g.Expect(rec.events[2].message).To(Equal("Failed to update port node-0 (id port-id): 409 Conflict, request ID req-PUT"))
+	g.Expect(rec.events[3].eventType).To(Equal("Normal"), "deletions of missing resources are not failures")
+	g.Expect(rec.events[3].message).To(Equal("Skipped deleting port port-id, it does not exist: 404 Not Found, request ID req-DELETE"))
+}
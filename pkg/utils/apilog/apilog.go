@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apilog logs the OpenStack API requests and responses of a provider client, like OS_DEBUG does for the
+// OpenStack CLI, through the structured logger of a reconcile. Credentials, tokens and user data are redacted.
+package apilog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	osclient "github.com/gophercloud/utils/client"
+)
+
+// Redacted replaces sensitive values in the logged requests and responses.
+const Redacted = "***"
+
+// sensitiveKeys are the keys of JSON objects whose values are redacted anywhere in a body, in addition to the
+// credentials redacted by gophercloud. user_data holds the bootstrap data of servers, which includes join tokens
+// and certificates, and payload the content of Barbican secrets.
+var sensitiveKeys = map[string]bool{
+	"password":       true,
+	"adminpass":      true,
+	"admin_pass":     true,
+	"secret":         true,
+	"user_data":      true,
+	"payload":        true,
+	"private_key":    true,
+	"security_token": true,
+}
+
+var (
+	mu      sync.RWMutex
+	enabled bool
+)
+
+// Configure sets whether the API requests of all clusters are logged.
+func Configure(enable bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = enable
+}
+
+// Enabled returns whether the API requests of all clusters are logged.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// Transport wraps rt so that every request sent through it and its response are logged to log.
+func Transport(rt http.RoundTripper, log logr.Logger) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &osclient.RoundTripper{
+		Rt:         rt,
+		Logger:     &logger{log: log},
+		FormatJSON: FormatJSON,
+	}
+}
+
+// logger adapts a logr.Logger to the logger of gophercloud.
+type logger struct {
+	log logr.Logger
+}
+
+func (l *logger) Printf(format string, args ...interface{}) {
+	l.log.Info(fmt.Sprintf(format, args...))
+}
+
+// FormatJSON pretty-prints a JSON body with its sensitive values redacted.
+func FormatJSON(raw []byte) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "<body is not valid JSON>", fmt.Errorf("unable to parse OpenStack JSON: %v", err)
+	}
+	redacted, err := json.Marshal(redact(data))
+	if err != nil {
+		return "", err
+	}
+	return osclient.FormatJSON(redacted)
+}
+
+// redact replaces the values of sensitive keys in the decoded JSON value.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveKeys[strings.ToLower(key)] {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(item)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return value
+}
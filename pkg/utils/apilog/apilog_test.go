@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apilog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+)
+
+func TestFormatJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	body := `{
+		"auth": {"identity": {"methods": ["password"], "password": {"user": {"name": "admin", "password": "s3cret"}}}},
+		"server": {"name": "node-0", "adminPass": "hunter2", "user_data": "I2Nsb3VkLWNvbmZpZw==", "networks": [{"uuid": "net"}]}
+	}`
+	formatted, err := FormatJSON([]byte(body))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(formatted).NotTo(ContainSubstring("s3cret"))
+	g.Expect(formatted).NotTo(ContainSubstring("hunter2"))
+	g.Expect(formatted).NotTo(ContainSubstring("I2Nsb3VkLWNvbmZpZw=="))
+	g.Expect(formatted).To(ContainSubstring(`"name": "node-0"`))
+	g.Expect(formatted).To(ContainSubstring(`"uuid": "net"`))
+
+	formatted, err = FormatJSON([]byte("password=s3cret"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(formatted).NotTo(ContainSubstring("s3cret"))
+}
+
+func TestTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "gAAAAAsubject")
+		_, _ = w.Write([]byte(`{"network": {"id": "net", "name": "cluster"}}`))
+	}))
+	defer server.Close()
+
+	var logged []string
+	log := funcr.New(func(_, args string) { logged = append(logged, args) }, funcr.Options{})
+
+	client := &http.Client{Transport: Transport(nil, log)}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v2.0/networks", strings.NewReader(`{"network": {"name": "cluster"}}`))
+	g.Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", "gAAAAAauth")
+	resp, err := client.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+
+	// The body is still passed on after it was logged
+	data, err := io.ReadAll(resp.Body)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"id": "net"`))
+
+	output := strings.Join(logged, "\n")
+	g.Expect(output).To(ContainSubstring("/v2.0/networks"))
+	g.Expect(output).To(ContainSubstring("OpenStack Response Code: 200"))
+	g.Expect(output).NotTo(ContainSubstring("gAAAAAauth"))
+	g.Expect(output).NotTo(ContainSubstring("gAAAAAsubject"))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+)
+
+const (
+	DefaultDeleteConcurrency = 10
+	DefaultDeleteQPS         = 10
+	DefaultDeleteBurst       = 20
+)
+
+var (
+	mu          sync.RWMutex
+	concurrency = DefaultDeleteConcurrency
+	limiter     = flowcontrol.NewTokenBucketRateLimiter(DefaultDeleteQPS, DefaultDeleteBurst)
+)
+
+// Configure sets the number of deletions run in parallel by Delete and the
+// rate limit shared by all deletions of the controller.
+func Configure(deleteConcurrency int, qps float32, burst int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if deleteConcurrency < 1 {
+		deleteConcurrency = 1
+	}
+	concurrency = deleteConcurrency
+	limiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+// Throttle blocks until the shared rate limiter allows another deletion.
+func Throttle() {
+	mu.RLock()
+	l := limiter
+	mu.RUnlock()
+
+	l.Accept()
+}
+
+// Delete calls deleteFn for each of the given IDs of the given resource type.
+// At most the configured number of calls run in parallel, and every call is
+// throttled by the shared rate limiter. All IDs are attempted even if some of
+// the calls fail, and the returned error aggregates the individual errors.
+func Delete(resource string, ids []string, deleteFn func(id string) error) error {
+	mu.RLock()
+	workers := concurrency
+	mu.RUnlock()
+
+	metrics.DeletionsStarted(resource, len(ids))
+
+	var (
+		wg      sync.WaitGroup
+		errsMu  sync.Mutex
+		errs    []error
+		pending = make(chan string)
+	)
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range pending {
+				Throttle()
+				err := deleteFn(id)
+				metrics.DeletionFinished(resource, err)
+				if err != nil {
+					errsMu.Lock()
+					errs = append(errs, err)
+					errsMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		pending <- id
+	}
+	close(pending)
+	wg.Wait()
+
+	return kerrors.NewAggregate(errs)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDelete(t *testing.T) {
+	g := NewWithT(t)
+	Configure(3, 1000, 1000)
+	defer Configure(DefaultDeleteConcurrency, DefaultDeleteQPS, DefaultDeleteBurst)
+
+	var (
+		mu      sync.Mutex
+		deleted []string
+		running int32
+		maxSeen int32
+	)
+	ids := []string{"a", "b", "c", "d", "e", "f", "g"}
+	err := Delete("test", ids, func(id string) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if id == "c" || id == "f" {
+			return fmt.Errorf("failed to delete %s", id)
+		}
+		mu.Lock()
+		deleted = append(deleted, id)
+		mu.Unlock()
+		return nil
+	})
+
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to delete c"))
+	g.Expect(err.Error()).To(ContainSubstring("failed to delete f"))
+	g.Expect(deleted).To(ConsistOf("a", "b", "d", "e", "g"))
+	g.Expect(maxSeen).To(BeNumerically("<=", 3))
+}
+
+func TestDelete_Empty(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(Delete("test", nil, func(string) error {
+		return fmt.Errorf("must not be called")
+	})).To(Succeed())
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package budget limits the number of OpenStack API calls made on behalf of a
+// single cluster, so that one misbehaving cluster spec cannot monopolise a
+// shared cloud.
+package budget
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+)
+
+const DefaultPeriod = time.Hour
+
+// window counts the calls of a cluster since start.
+type window struct {
+	start time.Time
+	calls int
+}
+
+var (
+	mu      sync.Mutex
+	limit   int
+	period  = DefaultPeriod
+	windows = map[string]*window{}
+	now     = time.Now
+)
+
+// Configure sets the maximum number of OpenStack API calls per cluster within
+// each period. A limit of 0 disables the budget; the calls are still counted.
+func Configure(calls int, p time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if calls < 0 {
+		calls = 0
+	}
+	if p <= 0 {
+		p = DefaultPeriod
+	}
+	limit = calls
+	period = p
+	windows = map[string]*window{}
+}
+
+func key(namespace, cluster string) string {
+	return namespace + "/" + cluster
+}
+
+// currentWindow returns the window of the cluster, starting a new one if the
+// period of the previous one has elapsed. It must be called with mu held.
+func currentWindow(namespace, cluster string) *window {
+	w, ok := windows[key(namespace, cluster)]
+	if !ok || now().Sub(w.start) >= period {
+		w = &window{start: now()}
+		windows[key(namespace, cluster)] = w
+	}
+	return w
+}
+
+// Count records an OpenStack API call made on behalf of the cluster.
+func Count(namespace, cluster string) {
+	metrics.ClusterAPIRequest(namespace, cluster)
+
+	mu.Lock()
+	defer mu.Unlock()
+	currentWindow(namespace, cluster).calls++
+}
+
+// Exceeded returns whether the cluster has used up its budget for the current
+// period, and if so how long it is until the period ends.
+func Exceeded(namespace, cluster string) (bool, time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if limit == 0 {
+		return false, 0
+	}
+	w := currentWindow(namespace, cluster)
+	if w.calls < limit {
+		return false, 0
+	}
+	return true, w.start.Add(period).Sub(now())
+}
+
+// Limit returns the configured number of calls per period. It is 0 if the
+// budget is disabled.
+func Limit() (int, time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	return limit, period
+}
+
+// Forget drops the calls counted for a cluster which is deleted.
+func Forget(namespace, cluster string) {
+	metrics.DeleteClusterAPIRequests(namespace, cluster)
+
+	mu.Lock()
+	defer mu.Unlock()
+	delete(windows, key(namespace, cluster))
+}
+
+// roundTripper counts every request sent through it against a cluster.
+type roundTripper struct {
+	rt                 http.RoundTripper
+	namespace, cluster string
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	Count(r.namespace, r.cluster)
+	return r.rt.RoundTrip(req)
+}
+
+// Transport wraps rt so that every request sent through it, including the
+// re-authentication of the client, is counted against the cluster.
+func Transport(rt http.RoundTripper, namespace, cluster string) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &roundTripper{rt: rt, namespace: namespace, cluster: cluster}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budget
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExceeded(t *testing.T) {
+	g := NewWithT(t)
+
+	clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer Configure(0, DefaultPeriod)
+
+	Configure(0, time.Hour)
+	for i := 0; i < 5; i++ {
+		Count("default", "disabled")
+	}
+	exceeded, _ := Exceeded("default", "disabled")
+	g.Expect(exceeded).To(BeFalse(), "budget is disabled")
+
+	Configure(3, time.Hour)
+	Count("default", "a")
+	Count("default", "a")
+	exceeded, _ = Exceeded("default", "a")
+	g.Expect(exceeded).To(BeFalse(), "budget is not used up")
+
+	Count("default", "a")
+	clock = clock.Add(20 * time.Minute)
+	exceeded, wait := Exceeded("default", "a")
+	g.Expect(exceeded).To(BeTrue(), "budget is used up")
+	g.Expect(wait).To(Equal(40 * time.Minute))
+
+	exceeded, _ = Exceeded("default", "b")
+	g.Expect(exceeded).To(BeFalse(), "budgets are per cluster")
+	exceeded, _ = Exceeded("other", "a")
+	g.Expect(exceeded).To(BeFalse(), "budgets are per namespace")
+
+	clock = clock.Add(40 * time.Minute)
+	exceeded, _ = Exceeded("default", "a")
+	g.Expect(exceeded).To(BeFalse(), "budget is renewed after the period")
+
+	Count("default", "a")
+	Count("default", "a")
+	Count("default", "a")
+	Forget("default", "a")
+	exceeded, _ = Exceeded("default", "a")
+	g.Expect(exceeded).To(BeFalse(), "calls of a forgotten cluster are dropped")
+}
+
+func TestTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	defer Configure(0, DefaultPeriod)
+	Configure(2, time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil, "default", "cluster")}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	exceeded, _ := Exceeded("default", "cluster")
+	g.Expect(exceeded).To(BeTrue())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deprecation tracks the deprecation warnings which OpenStack services return in the Warning, Deprecation
+// and Sunset headers of their responses, so that operators learn that a cloud is going to break an API used by CAPO
+// before it does. The warnings are kept per cloud, and each one is only logged the first time it is seen.
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Headers are the response headers carrying deprecation warnings.
+var Headers = []string{"Warning", "Deprecation", "Sunset"}
+
+// maxWarningsPerCloud bounds the warnings kept for a cloud, as the values of the headers are not under our control.
+const maxWarningsPerCloud = 20
+
+// Warning is a deprecation warning returned by an OpenStack service.
+type Warning struct {
+	// Endpoint is the host of the service which returned the warning.
+	Endpoint string
+	// Method and Path are the request which the warning was returned for, when it was first seen.
+	Method string
+	Path   string
+	// Header is the name of the header and Value its value.
+	Header string
+	Value  string
+}
+
+// String returns the warning as shown in logs and conditions.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s returned %s: %s for %s %s", w.Endpoint, w.Header, w.Value, w.Method, w.Path)
+}
+
+// key identifies a warning regardless of the request which returned it.
+func (w Warning) key() string {
+	return w.Endpoint + "\x00" + w.Header + "\x00" + w.Value
+}
+
+var (
+	mu       sync.Mutex
+	warnings = map[string]map[string]Warning{}
+)
+
+// Record records the warning for the cloud. It returns whether the warning is new for the cloud.
+func Record(cloud string, w Warning) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	cloudWarnings, ok := warnings[cloud]
+	if !ok {
+		cloudWarnings = map[string]Warning{}
+		warnings[cloud] = cloudWarnings
+	}
+	if _, ok := cloudWarnings[w.key()]; ok || len(cloudWarnings) >= maxWarningsPerCloud {
+		return false
+	}
+	cloudWarnings[w.key()] = w
+	return true
+}
+
+// Warnings returns the warnings recorded for the cloud, sorted by endpoint, header and value.
+func Warnings(cloud string) []Warning {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var result []Warning
+	for _, w := range warnings[cloud] {
+		result = append(result, w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].key() < result[j].key()
+	})
+	return result
+}
+
+// Reset forgets the warnings of all clouds.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	warnings = map[string]map[string]Warning{}
+}
+
+// roundTripper records the deprecation warnings of the responses received through it.
+type roundTripper struct {
+	rt    http.RoundTripper
+	cloud string
+	log   logr.Logger
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.rt.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, header := range Headers {
+		for _, value := range resp.Header.Values(header) {
+			w := Warning{
+				Endpoint: req.URL.Host,
+				Method:   req.Method,
+				Path:     req.URL.Path,
+				Header:   header,
+				Value:    value,
+			}
+			if Record(r.cloud, w) {
+				r.log.Info("OpenStack service returned a deprecation warning", "cloud", r.cloud, "endpoint", w.Endpoint,
+					"method", w.Method, "path", w.Path, "header", w.Header, "value", w.Value)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Transport wraps rt so that the deprecation warnings of the responses received through it are recorded for the
+// cloud, and logged to log the first time they are seen for the cloud.
+func Transport(rt http.RoundTripper, cloud string, log logr.Logger) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &roundTripper{rt: rt, cloud: cloud, log: log}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+)
+
+func TestRecord(t *testing.T) {
+	g := NewWithT(t)
+	defer Reset()
+
+	w := Warning{Endpoint: "nova.example.com:8774", Method: http.MethodGet, Path: "/v2.1/servers", Header: "Deprecation", Value: "true"}
+	g.Expect(Record("https://keystone.example.com/v3", w)).To(BeTrue(), "warning is new")
+
+	w.Path = "/v2.1/flavors"
+	g.Expect(Record("https://keystone.example.com/v3", w)).To(BeFalse(), "warning is only recorded once per cloud")
+	g.Expect(Record("https://other.example.com/v3", w)).To(BeTrue(), "warnings are per cloud")
+	g.Expect(Warnings("https://keystone.example.com/v3")).To(ConsistOf(Warning{
+		Endpoint: "nova.example.com:8774", Method: http.MethodGet, Path: "/v2.1/servers", Header: "Deprecation", Value: "true",
+	}))
+
+	for i := 0; i < 2*maxWarningsPerCloud; i++ {
+		Record("https://noisy.example.com/v3", Warning{Endpoint: "nova.example.com:8774", Header: "Warning", Value: fmt.Sprintf("299 - %d", i)})
+	}
+	g.Expect(Warnings("https://noisy.example.com/v3")).To(HaveLen(maxWarningsPerCloud), "warnings of a cloud are bounded")
+}
+
+func TestTransport(t *testing.T) {
+	g := NewWithT(t)
+	defer Reset()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2.0/lbaas/loadbalancers" {
+			w.Header().Add("Warning", `299 - "The neutron-lbaas API is deprecated"`)
+			w.Header().Set("Sunset", "Sat, 01 Jul 2023 00:00:00 GMT")
+		}
+	}))
+	defer server.Close()
+
+	var logged int
+	log := funcr.New(func(_, _ string) { logged++ }, funcr.Options{})
+	client := &http.Client{Transport: Transport(nil, "https://keystone.example.com/v3", log)}
+
+	for _, path := range []string{"/v2.0/networks", "/v2.0/lbaas/loadbalancers", "/v2.0/lbaas/loadbalancers"} {
+		resp, err := client.Get(server.URL + path)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	warnings := Warnings("https://keystone.example.com/v3")
+	g.Expect(warnings).To(HaveLen(2))
+	g.Expect(warnings[0].Header).To(Equal("Sunset"))
+	g.Expect(warnings[1].Header).To(Equal("Warning"))
+	g.Expect(warnings[1].Path).To(Equal("/v2.0/lbaas/loadbalancers"))
+	g.Expect(logged).To(Equal(2), "warnings are only logged the first time they are seen")
+}
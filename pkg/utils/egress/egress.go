@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package egress discovers the public IP address the controller uses to reach
+// the OpenStack clouds, so that it can allow itself through the access lists
+// of the resources it manages.
+package egress
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/tlsconfig"
+)
+
+const (
+	DefaultRefreshInterval = 10 * time.Minute
+
+	probeTimeout = 10 * time.Second
+	// maxResponseSize limits the response of the probe, which only contains an address.
+	maxResponseSize = 1024
+)
+
+var (
+	mu              sync.Mutex
+	probeURL        string
+	refreshInterval = DefaultRefreshInterval
+	ip              string
+	lastProbe       time.Time
+	now             = time.Now
+)
+
+// Configure sets the URL of the probe which returns the egress IP of the controller
+// as plain text, e.g. https://api.ipify.org, and how often the address is probed
+// again. An empty URL disables the discovery.
+func Configure(url string, refresh time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	probeURL = url
+	refreshInterval = refresh
+	ip = ""
+	lastProbe = time.Time{}
+}
+
+// IP returns the egress IP of the controller. It returns an empty string if no probe
+// is configured. The address is probed at most once per refresh interval; if the
+// probe fails, the last known address is returned together with the error.
+func IP() (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if probeURL == "" {
+		return "", nil
+	}
+	if !lastProbe.IsZero() && now().Sub(lastProbe) < refreshInterval {
+		return ip, nil
+	}
+
+	lastProbe = now()
+	probed, err := probe(probeURL)
+	if err != nil {
+		return ip, fmt.Errorf("probing egress IP from %s: %v", probeURL, err)
+	}
+	ip = probed
+	return ip, nil
+}
+
+func probe(url string) (string, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{}
+	tlsconfig.Apply(transport.TLSClientConfig)
+	client := &http.Client{Transport: transport}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+
+	address := strings.TrimSpace(string(body))
+	if net.ParseIP(address) == nil {
+		return "", fmt.Errorf("response %q is not an IP address", address)
+	}
+	return address, nil
+}
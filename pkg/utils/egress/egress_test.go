@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIP(t *testing.T) {
+	g := NewWithT(t)
+
+	response := "203.0.113.10\n"
+	status := http.StatusOK
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	clock := time.Now()
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	// Discovery is disabled without a probe
+	Configure("", 0)
+	got, err := IP()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(BeEmpty())
+	g.Expect(probes).To(Equal(0))
+
+	Configure(server.URL, time.Minute)
+	got, err = IP()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal("203.0.113.10"))
+	g.Expect(probes).To(Equal(1))
+
+	// The address is cached until the refresh interval has passed
+	response = "203.0.113.20"
+	got, err = IP()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal("203.0.113.10"))
+	g.Expect(probes).To(Equal(1))
+
+	clock = clock.Add(time.Minute)
+	got, err = IP()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal("203.0.113.20"))
+	g.Expect(probes).To(Equal(2))
+
+	// The last known address is kept if the probe fails
+	clock = clock.Add(time.Minute)
+	status = http.StatusServiceUnavailable
+	got, err = IP()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(got).To(Equal("203.0.113.20"))
+
+	clock = clock.Add(time.Minute)
+	status = http.StatusOK
+	response = "<html>not an address</html>"
+	got, err = IP()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(got).To(Equal("203.0.113.20"))
+}
@@ -17,12 +17,24 @@ limitations under the License.
 package errors
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gophercloud/gophercloud"
 )
 
+// StatusCode returns the HTTP status code of the response which caused err, or 0 if err was
+// not caused by an unexpected response.
+func StatusCode(err error) int {
+	var coder interface{ GetStatusCode() int }
+	if errors.As(err, &coder) {
+		return coder.GetStatusCode()
+	}
+	return 0
+}
+
 func IsRetryable(err error) bool {
 	var errUnexpectedResponseCode gophercloud.ErrUnexpectedResponseCode
 	if errors.As(err, &errUnexpectedResponseCode) {
@@ -84,3 +96,91 @@ func IsConflict(err error) bool {
 
 	return false
 }
+
+// ConflictReason is the reason why OpenStack rejected a request with 409 Conflict.
+type ConflictReason string
+
+const (
+	// ConflictPending means that the resource is in a transitional PENDING_* state, e.g. an Octavia
+	// load balancer which is immutable until the current operation completes.
+	ConflictPending ConflictReason = "Pending"
+	// ConflictInUse means that the resource, or an address requested for it, is in use by another resource.
+	ConflictInUse ConflictReason = "InUse"
+	// ConflictQuota means that the request would exceed the quota of the project.
+	ConflictQuota ConflictReason = "Quota"
+	// ConflictOther is any other conflict.
+	ConflictOther ConflictReason = "Other"
+)
+
+// conflictBody holds the fields of the error bodies of Neutron and Octavia used to classify conflicts.
+type conflictBody struct {
+	NeutronError struct {
+		Type string `json:"type"`
+	} `json:"NeutronError"`
+	FaultString string `json:"faultstring"`
+}
+
+// ClassifyConflict returns the reason of a 409 Conflict returned by Neutron or Octavia, and false if err
+// is not a conflict.
+func ClassifyConflict(err error) (ConflictReason, bool) {
+	var body []byte
+	var errDefault409 gophercloud.ErrDefault409
+	var errUnexpectedResponseCode gophercloud.ErrUnexpectedResponseCode
+	switch {
+	case errors.As(err, &errDefault409):
+		body = errDefault409.Body
+	case errors.As(err, &errUnexpectedResponseCode) && errUnexpectedResponseCode.Actual == http.StatusConflict:
+		body = errUnexpectedResponseCode.Body
+	default:
+		return "", false
+	}
+
+	var conflict conflictBody
+	if json.Unmarshal(body, &conflict) != nil {
+		return ConflictOther, true
+	}
+
+	neutronType := conflict.NeutronError.Type
+	switch {
+	case neutronType == "OverQuota":
+		return ConflictQuota, true
+	case strings.HasSuffix(neutronType, "InUse"), strings.HasSuffix(neutronType, "AlreadyAllocated"):
+		return ConflictInUse, true
+	// Octavia rejects changes to a load balancer in a PENDING_* state as immutable
+	case strings.Contains(conflict.FaultString, "immutable"), strings.Contains(conflict.FaultString, "PENDING_"):
+		return ConflictPending, true
+	case strings.Contains(strings.ToLower(conflict.FaultString), "quota"):
+		return ConflictQuota, true
+	}
+	return ConflictOther, true
+}
+
+// IsPreconditionFailed returns true if an update was rejected because the resource was modified
+// since the revision the update was based on.
+func IsPreconditionFailed(err error) bool {
+	var errUnexpectedResponseCode gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &errUnexpectedResponseCode) {
+		if errUnexpectedResponseCode.Actual == http.StatusPreconditionFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsForbidden returns true if the request was rejected by the policy of the service.
+func IsForbidden(err error) bool {
+	var errDefault403 gophercloud.ErrDefault403
+	if errors.As(err, &errDefault403) {
+		return true
+	}
+
+	var errUnexpectedResponseCode gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &errUnexpectedResponseCode) {
+		if errUnexpectedResponseCode.Actual == http.StatusForbidden {
+			return true
+		}
+	}
+
+	return false
+}
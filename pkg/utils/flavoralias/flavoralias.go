@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavoralias
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Aliases maps flavor names used in templates to the names of the flavors of a cloud.
+type Aliases map[string]string
+
+// config is the content of the configuration file.
+type config struct {
+	// Default are the aliases of all clouds. The aliases of a cloud take precedence.
+	Default Aliases `json:"default,omitempty"`
+	// Clouds are the aliases of each cloud by the cloud name of the identity secret.
+	Clouds map[string]Aliases `json:"clouds,omitempty"`
+}
+
+var (
+	mu      sync.RWMutex
+	current config
+)
+
+// Configure sets the default aliases and the aliases of each cloud.
+func Configure(defaults Aliases, clouds map[string]Aliases) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current = config{Default: defaults, Clouds: clouds}
+}
+
+// LoadConfig configures the aliases from a YAML file.
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("invalid flavor alias configuration %s: %v", path, err)
+	}
+	Configure(c.Default, c.Clouds)
+	return nil
+}
+
+func parseConfig(data []byte) (config, error) {
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return config{}, err
+	}
+
+	validate := func(aliases Aliases) error {
+		for alias, flavor := range aliases {
+			if alias == "" || flavor == "" {
+				return fmt.Errorf("alias %q of flavor %q: names must not be empty", alias, flavor)
+			}
+		}
+		return nil
+	}
+	if err := validate(c.Default); err != nil {
+		return config{}, fmt.Errorf("default: %v", err)
+	}
+	for cloud, aliases := range c.Clouds {
+		if err := validate(aliases); err != nil {
+			return config{}, fmt.Errorf("cloud %s: %v", cloud, err)
+		}
+	}
+	return c, nil
+}
+
+// Resolve returns the name of the flavor of the cloud for the flavor name, which is returned
+// unchanged if it is not an alias.
+func Resolve(cloud, flavor string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if name, ok := current.Clouds[cloud][flavor]; ok {
+		return name
+	}
+	if name, ok := current.Default[flavor]; ok {
+		return name
+	}
+	return flavor
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavoralias
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "default and cloud aliases",
+			data: `
+default:
+  m1.large: general.large
+clouds:
+  cloud-a:
+    m1.large: c4-m8
+`,
+			want: config{
+				Default: Aliases{"m1.large": "general.large"},
+				Clouds:  map[string]Aliases{"cloud-a": {"m1.large": "c4-m8"}},
+			},
+		},
+		{
+			name:    "unknown field",
+			data:    "aliases: {}",
+			wantErr: true,
+		},
+		{
+			name: "empty flavor",
+			data: `
+clouds:
+  cloud-a:
+    m1.large: ""
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := parseConfig([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	defer Configure(nil, nil)
+
+	Configure(
+		Aliases{"m1.large": "general.large", "m1.small": "general.small"},
+		map[string]Aliases{"cloud-a": {"m1.large": "c4-m8"}},
+	)
+
+	tests := []struct {
+		name   string
+		cloud  string
+		flavor string
+		want   string
+	}{
+		{name: "cloud alias", cloud: "cloud-a", flavor: "m1.large", want: "c4-m8"},
+		{name: "default alias of cloud", cloud: "cloud-a", flavor: "m1.small", want: "general.small"},
+		{name: "default alias of other cloud", cloud: "cloud-b", flavor: "m1.large", want: "general.large"},
+		{name: "default alias without cloud", flavor: "m1.large", want: "general.large"},
+		{name: "not an alias", cloud: "cloud-a", flavor: "m1.xlarge", want: "m1.xlarge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Resolve(tt.cloud, tt.flavor)).To(Equal(tt.want))
+		})
+	}
+}
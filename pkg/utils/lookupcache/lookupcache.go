@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lookupcache caches the resolution of networks, subnets, security groups and images
+// by name or filter across reconciles, as the same resources are resolved again for every
+// machine of a cluster.
+package lookupcache
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// Kind is the kind of the resources of a lookup.
+type Kind string
+
+const (
+	Network       Kind = "network"
+	Subnet        Kind = "subnet"
+	SecurityGroup Kind = "security_group"
+	Image         Kind = "image"
+)
+
+// size is the maximum number of lookups kept in memory.
+const size = 4096
+
+type key struct {
+	kind    Kind
+	project string
+	region  string
+	query   string
+}
+
+var (
+	mu      sync.RWMutex
+	ttl     time.Duration
+	lookups = cache.NewLRUExpireCache(size)
+)
+
+// Configure sets how long the result of a lookup is reused. A ttl of 0 disables the cache.
+func Configure(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	ttl = d
+	lookups = cache.NewLRUExpireCache(size)
+}
+
+// Get returns the cached result of the lookup of resources of kind in project and region by
+// query, which is the list query string of the lookup. Lookups are cached per region, as the
+// machines of a project can be in different regions, where a name resolves to other resources.
+func Get(kind Kind, project, region, query string) (interface{}, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if ttl == 0 {
+		return nil, false
+	}
+	return lookups.Get(key{kind, project, region, query})
+}
+
+// Add caches the result of a lookup. Only lookups which found resources should be cached, so
+// that resources which are created later are found.
+func Add(kind Kind, project, region, query string, result interface{}) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if ttl == 0 {
+		return
+	}
+	lookups.Add(key{kind, project, region, query}, result, ttl)
+}
+
+// Invalidate removes the cached lookups of the kinds, e.g. because a resource which was
+// resolved from the cache was not found.
+func Invalidate(kinds ...Kind) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, k := range lookups.Keys() {
+		for _, kind := range kinds {
+			if k.(key).kind == kind {
+				lookups.Remove(k)
+			}
+		}
+	}
+}
+
+// InvalidateOnNotFound invalidates the cached lookups of the kinds if err is caused by a
+// resource which was not found, and returns err.
+func InvalidateOnNotFound(err error, kinds ...Kind) error {
+	if capoerrors.IsNotFound(err) {
+		Invalidate(kinds...)
+	}
+	return err
+}
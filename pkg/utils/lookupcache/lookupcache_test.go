@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lookupcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestLookupCache(t *testing.T) {
+	g := NewWithT(t)
+
+	defer Configure(0)
+
+	Configure(0)
+	Add(Network, "project", "RegionOne", "name=nodes", []string{"net-id"})
+	_, ok := Get(Network, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeFalse(), "the cache is disabled")
+
+	Configure(time.Minute)
+	Add(Network, "project", "RegionOne", "name=nodes", []string{"net-id"})
+	Add(Image, "project", "RegionOne", "name=ubuntu", "image-id")
+	ids, ok := Get(Network, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(ids).To(Equal([]string{"net-id"}))
+	_, ok = Get(Network, "other-project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeFalse(), "lookups are cached per project")
+	_, ok = Get(Network, "project", "RegionTwo", "name=nodes")
+	g.Expect(ok).To(BeFalse(), "lookups are cached per region")
+	_, ok = Get(Subnet, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeFalse(), "lookups are cached per kind")
+
+	err := InvalidateOnNotFound(errors.New("boom"), Network)
+	g.Expect(err).To(MatchError("boom"))
+	_, ok = Get(Network, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeTrue(), "other errors do not invalidate the cache")
+
+	err = InvalidateOnNotFound(gophercloud.ErrDefault404{}, Network)
+	g.Expect(err).To(HaveOccurred())
+	_, ok = Get(Network, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeFalse(), "not found errors invalidate the kinds")
+	_, ok = Get(Image, "project", "RegionOne", "name=ubuntu")
+	g.Expect(ok).To(BeTrue(), "other kinds are kept")
+
+	Invalidate(Image)
+	_, ok = Get(Image, "project", "RegionOne", "name=ubuntu")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestLookupCache_Regions(t *testing.T) {
+	g := NewWithT(t)
+
+	defer Configure(0)
+	Configure(time.Minute)
+
+	// The same name resolves to different networks in each region
+	Add(Network, "project", "RegionOne", "name=nodes", []string{"net-one"})
+	Add(Network, "project", "RegionTwo", "name=nodes", []string{"net-two"})
+
+	ids, ok := Get(Network, "project", "RegionOne", "name=nodes")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(ids).To(Equal([]string{"net-one"}))
+	ids, ok = Get(Network, "project", "RegionTwo", "name=nodes")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(ids).To(Equal([]string{"net-two"}))
+}
@@ -18,8 +18,65 @@ package names
 
 import (
 	"fmt"
+	"strings"
+	"text/template"
 )
 
 func GetDescription(clusterName string) string {
 	return fmt.Sprintf("Created by cluster-api-provider-openstack cluster %s", clusterName)
 }
+
+// BelongsToInstance returns true if the name of a port or volume starts with the name of one of the instances
+// followed by a dash, as the ports and volumes CAPO creates for an instance are named.
+func BelongsToInstance(name string, instanceNames []string) bool {
+	for _, instanceName := range instanceNames {
+		if strings.HasPrefix(name, instanceName+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateData holds the variables available in resource naming templates.
+type TemplateData struct {
+	// ClusterName is the name of the cluster.
+	ClusterName string
+	// Namespace is the namespace of the cluster.
+	Namespace string
+	// Port is the load balancer port, for listener and pool names only.
+	Port int
+	// MachineName is the name of the machine, for snapshot names only.
+	MachineName string
+}
+
+// NewTemplateData returns the template variables for the cluster in the given
+// namespace. clusterName is the "<namespace>-<name>" identifier which CAPO
+// passes to the services.
+func NewTemplateData(namespace, clusterName string) TemplateData {
+	return TemplateData{
+		ClusterName: strings.TrimPrefix(clusterName, namespace+"-"),
+		Namespace:   namespace,
+	}
+}
+
+// Render renders the resource naming template tmpl with the given data. It
+// returns defaultName if tmpl is empty.
+func Render(tmpl, defaultName string, data TemplateData) (string, error) {
+	if tmpl == "" {
+		return defaultName, nil
+	}
+
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid naming template %q: %v", tmpl, err)
+	}
+
+	var name strings.Builder
+	if err := t.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("rendering naming template %q: %v", tmpl, err)
+	}
+	if name.Len() == 0 {
+		return "", fmt.Errorf("naming template %q rendered an empty name", tmpl)
+	}
+	return name.String(), nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	version "github.com/hashicorp/go-version"
+)
+
+// IsMicroversionSupported returns true if microversion is at most maxMicroversion, the maximum microversion
+// supported by a service. Services which do not support microversions have an empty maximum microversion.
+func IsMicroversionSupported(maxMicroversion, microversion string) bool {
+	maxVer, err := version.NewVersion(maxMicroversion)
+	if err != nil {
+		return false
+	}
+	ver, err := version.NewVersion(microversion)
+	if err != nil {
+		return false
+	}
+	return maxVer.GreaterThanOrEqual(ver)
+}
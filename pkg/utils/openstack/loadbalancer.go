@@ -27,6 +27,7 @@ const (
 	OctaviaFeatureFlavors           = 2
 	OctaviaFeatureTimeout           = 3
 	OctaviaFeatureAvailabilityZones = 4
+	OctaviaFeatureTLSTermination    = 5
 	lbProviderOVN                   = "ovn"
 )
 
@@ -73,6 +74,15 @@ func IsOctaviaFeatureSupported(octaviaVer string, feature int, lbProvider string
 		if currentVer.GreaterThanOrEqual(verAvailabilityZones) {
 			return true
 		}
+	case OctaviaFeatureTLSTermination:
+		if lbProvider == lbProviderOVN {
+			return false
+		}
+		// Re-encrypting the traffic to the members requires tls_enabled pools
+		verTLSTermination, _ := version.NewVersion("v2.8")
+		if currentVer.GreaterThanOrEqual(verTLSTermination) {
+			return true
+		}
 	default:
 		klog.Warningf("Feature %d not recognized", feature)
 	}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits the rate of the OpenStack API calls of the controller, so that
+// scaling up many machines at once does not trigger the API throttling of the cloud.
+package ratelimit
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+var (
+	mu       sync.RWMutex
+	global   flowcontrol.RateLimiter
+	services = map[string]flowcontrol.RateLimiter{}
+	// endpoints maps the endpoint URLs of the cloud to the type of their service, e.g. network.
+	endpoints = map[string]string{}
+)
+
+// Configure sets the maximum rate of OpenStack API calls of the controller, and of the calls
+// to individual services by their type in the service catalog, e.g. network or compute. Each
+// limit allows bursts of up to burst calls. A qps of 0 disables the limit.
+func Configure(qps float32, burst int, serviceQPS map[string]float32) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if burst < 1 {
+		burst = 1
+	}
+	global = nil
+	if qps > 0 {
+		global = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	}
+	services = map[string]flowcontrol.RateLimiter{}
+	for serviceType, qps := range serviceQPS {
+		if qps > 0 {
+			services[serviceType] = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		}
+	}
+}
+
+// registerEndpoint records the service type of an endpoint URL.
+func registerEndpoint(serviceType, url string) {
+	mu.Lock()
+	defer mu.Unlock()
+	endpoints[url] = serviceType
+}
+
+// limiters returns the limiters which apply to a request to url.
+func limiters(url string) []flowcontrol.RateLimiter {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var l []flowcontrol.RateLimiter
+	if global != nil {
+		l = append(l, global)
+	}
+	if len(services) == 0 {
+		return l
+	}
+	var serviceType, endpoint string
+	for e, t := range endpoints {
+		if strings.HasPrefix(url, e) && len(e) > len(endpoint) {
+			serviceType, endpoint = t, e
+		}
+	}
+	if limiter, ok := services[serviceType]; ok {
+		l = append(l, limiter)
+	}
+	return l
+}
+
+// EndpointLocator wraps locator so that the endpoints it returns are recorded with the type of
+// their service, which is used to select the limit of the requests to the endpoint.
+func EndpointLocator(locator gophercloud.EndpointLocator) gophercloud.EndpointLocator {
+	return func(opts gophercloud.EndpointOpts) (string, error) {
+		url, err := locator(opts)
+		if err == nil && opts.Type != "" {
+			registerEndpoint(opts.Type, url)
+		}
+		return url, err
+	}
+}
+
+// roundTripper waits for the rate limiters of the controller and of the service before sending
+// a request.
+type roundTripper struct {
+	rt http.RoundTripper
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, limiter := range limiters(req.URL.String()) {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return r.rt.RoundTrip(req)
+}
+
+// Transport wraps rt so that every request sent through it is rate limited.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &roundTripper{rt: rt}
+}
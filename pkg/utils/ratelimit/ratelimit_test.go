@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestLimiters(t *testing.T) {
+	g := NewWithT(t)
+
+	defer Configure(0, 1, nil)
+
+	locator := EndpointLocator(func(opts gophercloud.EndpointOpts) (string, error) {
+		return map[string]string{
+			"network": "https://cloud.example.com:9696/",
+			"compute": "https://cloud.example.com:8774/v2.1/",
+		}[opts.Type], nil
+	})
+	for _, serviceType := range []string{"network", "compute"} {
+		_, err := locator(gophercloud.EndpointOpts{Type: serviceType})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	Configure(0, 1, nil)
+	g.Expect(limiters("https://cloud.example.com:9696/v2.0/ports")).To(BeEmpty(), "limits are disabled")
+
+	Configure(10, 1, map[string]float32{"network": 5})
+	g.Expect(limiters("https://cloud.example.com:9696/v2.0/ports")).To(HaveLen(2), "network calls are limited globally and per service")
+	g.Expect(limiters("https://cloud.example.com:8774/v2.1/servers")).To(HaveLen(1), "compute calls are only limited globally")
+	g.Expect(limiters("https://cloud.example.com:5000/v3/auth/tokens")).To(HaveLen(1), "calls to unknown endpoints are only limited globally")
+
+	Configure(0, 1, map[string]float32{"compute": 5})
+	g.Expect(limiters("https://cloud.example.com:9696/v2.0/ports")).To(BeEmpty())
+	g.Expect(limiters("https://cloud.example.com:8774/v2.1/servers")).To(HaveLen(1))
+}
+
+func TestTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	defer Configure(0, 1, nil)
+	Configure(0.1, 2, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+	get := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	for i := 0; i < 2; i++ {
+		g.Expect(get()).To(Succeed(), "calls within the burst are sent immediately")
+	}
+	g.Expect(get()).NotTo(Succeed(), "the next call is delayed beyond the timeout of the request")
+}
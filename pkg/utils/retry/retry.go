@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/yaml"
+)
+
+// Service is an OpenStack service whose resources the controller waits for.
+type Service string
+
+const (
+	Compute      Service = "compute"
+	Network      Service = "network"
+	LoadBalancer Service = "loadbalancer"
+	Image        Service = "image"
+	Volume       Service = "volume"
+)
+
+// Policy configures how often and how long a condition is polled.
+type Policy struct {
+	// Interval is the time between the first two polls.
+	Interval time.Duration
+	// Factor multiplies the interval after each poll if it is greater than 1.
+	Factor float64
+	// Jitter adds a random duration of up to Jitter times the interval to each interval.
+	Jitter float64
+	// Retries is the maximum number of polls after the first one, if set.
+	Retries int
+	// Timeout is the maximum time to poll, if set. If neither Retries nor Timeout
+	// is set, the condition is polled once.
+	Timeout time.Duration
+}
+
+// Override is a configured policy of a service. Its fields which are set override
+// the defaults of the caller, including fields which are set to zero.
+type Override struct {
+	Interval *time.Duration
+	Factor   *float64
+	Jitter   *float64
+	Retries  *int
+	Timeout  *time.Duration
+}
+
+// merge returns the policy with the fields which are set in override applied.
+func (p Policy) merge(override Override) Policy {
+	if override.Interval != nil {
+		p.Interval = *override.Interval
+	}
+	if override.Factor != nil {
+		p.Factor = *override.Factor
+	}
+	if override.Jitter != nil {
+		p.Jitter = *override.Jitter
+	}
+	if override.Retries != nil {
+		p.Retries = *override.Retries
+	}
+	if override.Timeout != nil {
+		p.Timeout = *override.Timeout
+	}
+	return p
+}
+
+// policyConfig is a policy in the configuration file.
+type policyConfig struct {
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	Factor   *float64         `json:"factor,omitempty"`
+	Jitter   *float64         `json:"jitter,omitempty"`
+	Retries  *int             `json:"retries,omitempty"`
+	Timeout  *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// config is the content of the configuration file.
+type config struct {
+	Compute      policyConfig `json:"compute,omitempty"`
+	Network      policyConfig `json:"network,omitempty"`
+	LoadBalancer policyConfig `json:"loadbalancer,omitempty"`
+	Image        policyConfig `json:"image,omitempty"`
+	Volume       policyConfig `json:"volume,omitempty"`
+}
+
+var (
+	mu        sync.RWMutex
+	overrides = map[Service]Override{}
+)
+
+// Configure sets the policies of the services, which override the defaults of
+// the individual waits for resources of the service.
+func Configure(serviceOverrides map[Service]Override) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	overrides = make(map[Service]Override, len(serviceOverrides))
+	for service, override := range serviceOverrides {
+		overrides[service] = override
+	}
+}
+
+// LoadConfig configures the policies of the services from a YAML file.
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	serviceOverrides, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("invalid retry configuration %s: %v", path, err)
+	}
+	Configure(serviceOverrides)
+	return nil
+}
+
+func parseConfig(data []byte) (map[Service]Override, error) {
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, err
+	}
+
+	serviceOverrides := map[Service]Override{}
+	for service, pc := range map[Service]policyConfig{
+		Compute:      c.Compute,
+		Network:      c.Network,
+		LoadBalancer: c.LoadBalancer,
+		Image:        c.Image,
+		Volume:       c.Volume,
+	} {
+		if (pc.Interval != nil && pc.Interval.Duration < 0) || (pc.Timeout != nil && pc.Timeout.Duration < 0) ||
+			(pc.Factor != nil && *pc.Factor < 0) || (pc.Jitter != nil && *pc.Jitter < 0) || (pc.Retries != nil && *pc.Retries < 0) {
+			return nil, fmt.Errorf("%s: values must not be negative", service)
+		}
+		override := Override{
+			Factor:  pc.Factor,
+			Jitter:  pc.Jitter,
+			Retries: pc.Retries,
+		}
+		if pc.Interval != nil {
+			override.Interval = &pc.Interval.Duration
+		}
+		if pc.Timeout != nil {
+			override.Timeout = &pc.Timeout.Duration
+		}
+		serviceOverrides[service] = override
+	}
+	return serviceOverrides, nil
+}
+
+// Wait polls condition until it returns true or an error, using the defaults
+// overridden by the configured policy of the service. It returns
+// wait.ErrWaitTimeout if the condition is not met within the policy, and the
+// error of ctx if ctx is done while waiting for the next poll.
+func Wait(ctx context.Context, service Service, defaults Policy, condition wait.ConditionFunc) error {
+	mu.RLock()
+	p := defaults.merge(overrides[service])
+	mu.RUnlock()
+
+	var deadline time.Time
+	if p.Timeout > 0 {
+		deadline = time.Now().Add(p.Timeout)
+	}
+
+	interval := p.Interval
+	for polls := 0; ; polls++ {
+		if done, err := condition(); err != nil || done {
+			return err
+		}
+		if (p.Retries > 0 && polls >= p.Retries) || (p.Retries == 0 && deadline.IsZero()) {
+			return wait.ErrWaitTimeout
+		}
+
+		sleep := interval
+		if p.Jitter > 0 {
+			sleep = wait.Jitter(interval, p.Jitter)
+		}
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return wait.ErrWaitTimeout
+			}
+			if sleep > remaining {
+				sleep = remaining
+			}
+		}
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if p.Factor > 1 {
+			interval = time.Duration(float64(interval) * p.Factor)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+)
+
+func TestWait(t *testing.T) {
+	defer Configure(nil)
+
+	countPolls := func(service Service, defaults Policy, doneAfter int) (int, error) {
+		polls := 0
+		err := Wait(context.Background(), service, defaults, func() (bool, error) {
+			polls++
+			return doneAfter > 0 && polls >= doneAfter, nil
+		})
+		return polls, err
+	}
+
+	t.Run("condition is polled once without limits", func(t *testing.T) {
+		g := NewWithT(t)
+		polls, err := countPolls(Image, Policy{}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(polls).To(Equal(1))
+	})
+
+	t.Run("retries limit the polls", func(t *testing.T) {
+		g := NewWithT(t)
+		polls, err := countPolls(Compute, Policy{Interval: time.Millisecond, Retries: 3}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(polls).To(Equal(4))
+	})
+
+	t.Run("timeout limits the polls", func(t *testing.T) {
+		g := NewWithT(t)
+		start := time.Now()
+		_, err := countPolls(Compute, Policy{Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	t.Run("polling stops when the condition is met", func(t *testing.T) {
+		g := NewWithT(t)
+		polls, err := countPolls(Compute, Policy{Interval: time.Millisecond, Retries: 10}, 2)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(polls).To(Equal(2))
+	})
+
+	t.Run("errors stop polling", func(t *testing.T) {
+		g := NewWithT(t)
+		conditionErr := errors.New("error")
+		err := Wait(context.Background(), Compute, Policy{Interval: time.Millisecond, Retries: 10}, func() (bool, error) {
+			return false, conditionErr
+		})
+		g.Expect(err).To(MatchError(conditionErr))
+	})
+
+	t.Run("cancelling the context stops waiting", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		start := time.Now()
+		err := Wait(ctx, Compute, Policy{Interval: time.Hour, Timeout: 2 * time.Hour}, func() (bool, error) {
+			return false, nil
+		})
+		g.Expect(err).To(MatchError(context.Canceled))
+		g.Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+	})
+
+	t.Run("configured policy overrides the defaults of its service", func(t *testing.T) {
+		g := NewWithT(t)
+		Configure(map[Service]Override{LoadBalancer: {Retries: pointer.Int(5)}})
+		polls, err := countPolls(LoadBalancer, Policy{Interval: time.Millisecond, Retries: 1}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(polls).To(Equal(6))
+
+		polls, err = countPolls(Network, Policy{Interval: time.Millisecond, Retries: 1}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(polls).To(Equal(2))
+	})
+
+	t.Run("configured zero values override the defaults", func(t *testing.T) {
+		g := NewWithT(t)
+		Configure(map[Service]Override{Volume: {Retries: pointer.Int(0), Timeout: pointer.Duration(0)}})
+		polls, err := countPolls(Volume, Policy{Interval: time.Millisecond, Retries: 3}, 0)
+		g.Expect(err).To(MatchError(wait.ErrWaitTimeout))
+		g.Expect(polls).To(Equal(1))
+	})
+}
+
+func TestPolicy_merge(t *testing.T) {
+	g := NewWithT(t)
+	defaults := Policy{Interval: time.Second, Factor: 1.25, Jitter: 0.5, Retries: 19, Timeout: time.Minute}
+
+	g.Expect(defaults.merge(Override{})).To(Equal(defaults))
+	g.Expect(defaults.merge(Override{Factor: pointer.Float64(0), Jitter: pointer.Float64(0)})).To(Equal(
+		Policy{Interval: time.Second, Retries: 19, Timeout: time.Minute}))
+}
+
+func TestParseConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	policies, err := parseConfig([]byte(`
+loadbalancer:
+  interval: 5s
+  factor: 1.5
+  retries: 40
+  timeout: 30m
+image:
+  retries: 3
+  jitter: 0
+`))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(policies[LoadBalancer]).To(Equal(Override{
+		Interval: pointer.Duration(5 * time.Second),
+		Factor:   pointer.Float64(1.5),
+		Retries:  pointer.Int(40),
+		Timeout:  pointer.Duration(30 * time.Minute),
+	}))
+	g.Expect(policies[Image]).To(Equal(Override{Retries: pointer.Int(3), Jitter: pointer.Float64(0)}))
+	g.Expect(policies[Compute]).To(Equal(Override{}))
+
+	_, err = parseConfig([]byte("octavia:\n  retries: 3\n"))
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = parseConfig([]byte("network:\n  timeout: -1m\n"))
+	g.Expect(err).To(HaveOccurred())
+}
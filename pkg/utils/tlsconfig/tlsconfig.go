@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+// DefaultMinVersion is the minimum TLS version used if none is configured.
+const DefaultMinVersion = tls.VersionTLS12
+
+var (
+	mu           sync.RWMutex
+	minVersion   uint16 = DefaultMinVersion
+	cipherSuites []uint16
+)
+
+// Configure sets the minimum TLS version and the cipher suites used by the
+// connections of the controller. If cipherSuites is empty, the Go defaults
+// are used. Cipher suites are not configurable for TLS 1.3.
+func Configure(tlsMinVersion uint16, tlsCipherSuites []uint16) {
+	mu.Lock()
+	defer mu.Unlock()
+	if tlsMinVersion == 0 {
+		tlsMinVersion = DefaultMinVersion
+	}
+	minVersion = tlsMinVersion
+	cipherSuites = append([]uint16(nil), tlsCipherSuites...)
+}
+
+// Apply sets the configured minimum TLS version and cipher suites on config.
+func Apply(config *tls.Config) {
+	mu.RLock()
+	defer mu.RUnlock()
+	config.MinVersion = minVersion
+	if len(cipherSuites) > 0 {
+		config.CipherSuites = append([]uint16(nil), cipherSuites...)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestApply(t *testing.T) {
+	defer Configure(DefaultMinVersion, nil)
+
+	tests := []struct {
+		name             string
+		minVersion       uint16
+		cipherSuites     []uint16
+		wantMinVersion   uint16
+		wantCipherSuites []uint16
+	}{
+		{
+			name:           "defaults",
+			wantMinVersion: tls.VersionTLS12,
+		},
+		{
+			name:           "TLS 1.3",
+			minVersion:     tls.VersionTLS13,
+			wantMinVersion: tls.VersionTLS13,
+		},
+		{
+			name:             "FIPS cipher suites",
+			minVersion:       tls.VersionTLS12,
+			cipherSuites:     []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			wantMinVersion:   tls.VersionTLS12,
+			wantCipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			Configure(tt.minVersion, tt.cipherSuites)
+
+			config := &tls.Config{}
+			Apply(config)
+			g.Expect(config.MinVersion).To(Equal(tt.wantMinVersion))
+			g.Expect(config.CipherSuites).To(Equal(tt.wantCipherSuites))
+		})
+	}
+}
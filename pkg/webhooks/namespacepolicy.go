@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+// NamespacePolicyValidatorPath is the path the NamespacePolicyValidator is served at.
+const NamespacePolicyValidatorPath = "/validate-infrastructure-cluster-x-k8s-io-v1alpha6-namespacepolicy"
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-namespacepolicy,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters;openstackclustertemplates;openstackmachines;openstackmachinetemplates,versions=v1alpha6,name=namespacepolicy.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// NamespacePolicy restricts the OpenStack resources which the objects in some namespaces may reference. The
+// namespaces and the allowed resources are shell patterns as understood by path.Match, e.g. team-* or m1.*.
+type NamespacePolicy struct {
+	// Namespaces are the namespaces the policy applies to.
+	Namespaces []string `json:"namespaces"`
+	// ExternalNetworks are the IDs of the external networks clusters may use. All are allowed if it is empty.
+	ExternalNetworks []string `json:"externalNetworks,omitempty"`
+	// Flavors are the names or IDs of the flavors machines may use. All are allowed if it is empty.
+	Flavors []string `json:"flavors,omitempty"`
+	// Images are the names or IDs of the images machines may use. All are allowed if it is empty.
+	Images []string `json:"images,omitempty"`
+}
+
+// namespacePolicyConfig is the content of the configuration file.
+type namespacePolicyConfig struct {
+	Policies []NamespacePolicy `json:"policies"`
+}
+
+// LoadNamespacePolicies reads the namespace policies from a YAML file.
+func LoadNamespacePolicies(file string) ([]NamespacePolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := parseNamespacePolicies(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace policy configuration %s: %v", file, err)
+	}
+	return policies, nil
+}
+
+func parseNamespacePolicies(data []byte) ([]NamespacePolicy, error) {
+	var c namespacePolicyConfig
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, err
+	}
+
+	for i, policy := range c.Policies {
+		if len(policy.Namespaces) == 0 {
+			return nil, fmt.Errorf("policy %d: namespaces must not be empty", i)
+		}
+		for _, patterns := range [][]string{policy.Namespaces, policy.ExternalNetworks, policy.Flavors, policy.Images} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("policy %d: invalid pattern %q: %v", i, pattern, err)
+				}
+			}
+		}
+	}
+	return c.Policies, nil
+}
+
+// matchesAny returns whether any of the values matches any of the patterns.
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			// The patterns are validated when they are loaded
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NamespacePolicyValidator rejects OpenStackClusters, OpenStackMachines and their templates which reference
+// external networks, flavors or images that the policy of their namespace does not allow. The first policy which
+// applies to the namespace of an object is used, and objects in namespaces without a policy are not restricted.
+//
+// On updates only the references which changed are checked, so that tightening a policy does not prevent changes
+// to existing objects which still reference resources that are not allowed any more.
+type NamespacePolicyValidator struct {
+	Policies []NamespacePolicy
+
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &NamespacePolicyValidator{}
+
+// InjectDecoder injects the decoder of admission requests.
+func (v *NamespacePolicyValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the OpenStack resources referenced by an object against the policy of its namespace.
+func (v *NamespacePolicyValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	policy := v.policyFor(req.Namespace)
+	if policy == nil {
+		return admission.Allowed("")
+	}
+
+	allErrs, err := v.validate(policy, req.Kind.Kind, req.Object)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update && len(allErrs) > 0 {
+		oldErrs, err := v.validate(policy, req.Kind.Kind, req.OldObject)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		allErrs = withoutErrors(allErrs, oldErrs)
+	}
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+
+	status := apierrors.NewInvalid(infrav1.GroupVersion.WithKind(req.Kind.Kind).GroupKind(), req.Name, allErrs).Status()
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		},
+	}
+}
+
+// policyFor returns the first policy which applies to the namespace, or nil.
+func (v *NamespacePolicyValidator) policyFor(namespace string) *NamespacePolicy {
+	for i := range v.Policies {
+		if matchesAny(v.Policies[i].Namespaces, namespace) {
+			return &v.Policies[i]
+		}
+	}
+	return nil
+}
+
+// validate decodes the raw object of the kind and validates it against the policy.
+func (v *NamespacePolicyValidator) validate(policy *NamespacePolicy, kind string, raw runtime.RawExtension) (field.ErrorList, error) {
+	switch kind {
+	case "OpenStackCluster":
+		openStackCluster := &infrav1.OpenStackCluster{}
+		if err := v.decoder.DecodeRaw(raw, openStackCluster); err != nil {
+			return nil, err
+		}
+		allErrs := validateClusterPolicy(policy, &openStackCluster.Spec, field.NewPath("spec"))
+		// The controller would use any external network it finds, while templates may leave it to the topology
+		if len(policy.ExternalNetworks) > 0 && openStackCluster.Spec.ExternalNetworkID == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "externalNetworkId"),
+				"must be set as external networks are restricted in this namespace"))
+		}
+		return allErrs, nil
+	case "OpenStackClusterTemplate":
+		template := &infrav1.OpenStackClusterTemplate{}
+		if err := v.decoder.DecodeRaw(raw, template); err != nil {
+			return nil, err
+		}
+		return validateClusterPolicy(policy, &template.Spec.Template.Spec, field.NewPath("spec", "template", "spec")), nil
+	case "OpenStackMachine":
+		openStackMachine := &infrav1.OpenStackMachine{}
+		if err := v.decoder.DecodeRaw(raw, openStackMachine); err != nil {
+			return nil, err
+		}
+		return validateMachinePolicy(policy, &openStackMachine.Spec, field.NewPath("spec")), nil
+	case "OpenStackMachineTemplate":
+		template := &infrav1.OpenStackMachineTemplate{}
+		if err := v.decoder.DecodeRaw(raw, template); err != nil {
+			return nil, err
+		}
+		return validateMachinePolicy(policy, &template.Spec.Template.Spec, field.NewPath("spec", "template", "spec")), nil
+	}
+	return nil, nil
+}
+
+// validateClusterPolicy checks the external network of the cluster and the machine spec of its bastion.
+func validateClusterPolicy(policy *NamespacePolicy, spec *infrav1.OpenStackClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(policy.ExternalNetworks) > 0 && spec.ExternalNetworkID != "" && !matchesAny(policy.ExternalNetworks, spec.ExternalNetworkID) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalNetworkId"),
+			fmt.Sprintf("external network %s is not allowed in this namespace", spec.ExternalNetworkID)))
+	}
+
+	if spec.Bastion != nil && spec.Bastion.Enabled {
+		allErrs = append(allErrs, validateMachinePolicy(policy, &spec.Bastion.Instance, fldPath.Child("bastion", "instance"))...)
+	}
+
+	return allErrs
+}
+
+// validateMachinePolicy checks the flavor and the image of the machine. An image which is selected by a filter
+// without name or by an OpenStackImage cannot be checked, and is therefore not allowed if images are restricted.
+func validateMachinePolicy(policy *NamespacePolicy, spec *infrav1.OpenStackMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(policy.Flavors) > 0 && (spec.Flavor != "" || spec.FlavorID != "") && !matchesAny(policy.Flavors, spec.Flavor, spec.FlavorID) {
+		fldName, flavor := "flavor", spec.Flavor
+		if flavor == "" {
+			fldName, flavor = "flavorID", spec.FlavorID
+		}
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child(fldName),
+			fmt.Sprintf("flavor %s is not allowed in this namespace", flavor)))
+	}
+
+	if len(policy.Images) > 0 {
+		switch {
+		case spec.ImageRef != nil:
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageRef"),
+				"images managed by an OpenStackImage are not allowed in this namespace"))
+		case spec.ImageFilter != nil:
+			if !matchesAny(policy.Images, spec.ImageFilter.Name) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageFilter", "name"),
+					fmt.Sprintf("image %q is not allowed in this namespace", spec.ImageFilter.Name)))
+			}
+		case spec.Image != "" || spec.ImageUUID != "":
+			if !matchesAny(policy.Images, spec.Image, spec.ImageUUID) {
+				fldName, image := "image", spec.Image
+				if image == "" {
+					fldName, image = "imageUUID", spec.ImageUUID
+				}
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child(fldName),
+					fmt.Sprintf("image %s is not allowed in this namespace", image)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// withoutErrors returns the errors which are not in oldErrs.
+func withoutErrors(allErrs, oldErrs field.ErrorList) field.ErrorList {
+	old := make(map[string]bool, len(oldErrs))
+	for _, err := range oldErrs {
+		old[err.Error()] = true
+	}
+	var errs field.ErrorList
+	for _, err := range allErrs {
+		if !old[err.Error()] {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func Test_parseNamespacePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []NamespacePolicy
+		wantErr bool
+	}{
+		{
+			name: "Valid policies",
+			data: `
+policies:
+- namespaces: [team-*]
+  externalNetworks: [ext-net-id]
+  flavors: [m1.*]
+`,
+			want: []NamespacePolicy{{
+				Namespaces:       []string{"team-*"},
+				ExternalNetworks: []string{"ext-net-id"},
+				Flavors:          []string{"m1.*"},
+			}},
+		},
+		{
+			name:    "Policy without namespaces",
+			data:    "policies:\n- flavors: [m1.small]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid pattern",
+			data:    "policies:\n- namespaces: [team-a]\n  images: ['[ubuntu']\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown field",
+			data:    "policies:\n- namespaces: [team-a]\n  networks: [foo]\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := parseNamespacePolicies([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNamespacePolicyValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+
+	policies := []NamespacePolicy{
+		{
+			Namespaces:       []string{"team-*"},
+			ExternalNetworks: []string{"public"},
+			Flavors:          []string{"m1.*"},
+			Images:           []string{"ubuntu-*"},
+		},
+		{
+			Namespaces: []string{"*"},
+			Flavors:    []string{"m1.small"},
+		},
+	}
+
+	openStackCluster := func(namespace, externalNetworkID, bastionFlavor string) client.Object {
+		cluster := &infrav1.OpenStackCluster{
+			TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "OpenStackCluster"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace},
+			Spec:       infrav1.OpenStackClusterSpec{ExternalNetworkID: externalNetworkID},
+		}
+		if bastionFlavor != "" {
+			cluster.Spec.Bastion = &infrav1.Bastion{
+				Enabled:  true,
+				Instance: infrav1.OpenStackMachineSpec{Flavor: bastionFlavor, Image: "ubuntu-22.04"},
+			}
+		}
+		return cluster
+	}
+	openStackMachineTemplate := func(namespace string, spec infrav1.OpenStackMachineSpec) client.Object {
+		return &infrav1.OpenStackMachineTemplate{
+			TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "OpenStackMachineTemplate"},
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: namespace},
+			Spec: infrav1.OpenStackMachineTemplateSpec{
+				Template: infrav1.OpenStackMachineTemplateResource{Spec: spec},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		operation  admissionv1.Operation
+		obj        client.Object
+		oldObj     client.Object
+		wantFields []string
+	}{
+		{
+			name:      "Allowed external network",
+			operation: admissionv1.Create,
+			obj:       openStackCluster("team-a", "public", ""),
+		},
+		{
+			name:       "External network and bastion flavor not allowed",
+			operation:  admissionv1.Create,
+			obj:        openStackCluster("team-a", "provider", "c1.large"),
+			wantFields: []string{"spec.externalNetworkId", "spec.bastion.instance.flavor"},
+		},
+		{
+			name:       "External network must be set if it is restricted",
+			operation:  admissionv1.Create,
+			obj:        openStackCluster("team-a", "", ""),
+			wantFields: []string{"spec.externalNetworkId"},
+		},
+		{
+			name:      "Other policy applies to other namespaces",
+			operation: admissionv1.Create,
+			obj:       openStackCluster("default", "provider", "m1.small"),
+		},
+		{
+			name:       "Machine template with disallowed flavor and image",
+			operation:  admissionv1.Create,
+			obj:        openStackMachineTemplate("team-a", infrav1.OpenStackMachineSpec{FlavorID: "c1", ImageUUID: "image-id"}),
+			wantFields: []string{"spec.template.spec.flavorID", "spec.template.spec.imageUUID"},
+		},
+		{
+			name:      "Machine template with allowed flavor and image filter",
+			operation: admissionv1.Create,
+			obj: openStackMachineTemplate("team-a", infrav1.OpenStackMachineSpec{
+				Flavor:      "m1.medium",
+				ImageFilter: &infrav1.ImageFilter{Name: "ubuntu-22.04"},
+			}),
+		},
+		{
+			name:       "Images managed by OpenStackImages cannot be checked",
+			operation:  admissionv1.Create,
+			obj:        openStackMachineTemplate("team-a", infrav1.OpenStackMachineSpec{Flavor: "m1.medium", ImageRef: &corev1.LocalObjectReference{Name: "ubuntu"}}),
+			wantFields: []string{"spec.template.spec.imageRef"},
+		},
+		{
+			name:      "Update keeping a reference which is not allowed any more",
+			operation: admissionv1.Update,
+			obj:       openStackCluster("team-a", "provider", "m1.small"),
+			oldObj:    openStackCluster("team-a", "provider", ""),
+		},
+		{
+			name:       "Update changing a reference to one which is not allowed",
+			operation:  admissionv1.Update,
+			obj:        openStackCluster("team-a", "public", "c1.large"),
+			oldObj:     openStackCluster("team-a", "public", "m1.small"),
+			wantFields: []string{"spec.bastion.instance.flavor"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			decoder, err := admission.NewDecoder(scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+			v := &NamespacePolicyValidator{Policies: policies}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			raw, err := json.Marshal(tt.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			gvk := tt.obj.GetObjectKind().GroupVersionKind()
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: tt.operation,
+					Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+					Name:      tt.obj.GetName(),
+					Namespace: tt.obj.GetNamespace(),
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+			if tt.oldObj != nil {
+				oldRaw, err := json.Marshal(tt.oldObj)
+				g.Expect(err).NotTo(HaveOccurred())
+				req.OldObject = runtime.RawExtension{Raw: oldRaw}
+			}
+
+			resp := v.Handle(context.TODO(), req)
+			if len(tt.wantFields) == 0 {
+				g.Expect(resp.Allowed).To(BeTrue())
+				return
+			}
+			g.Expect(resp.Allowed).To(BeFalse())
+			fields := []string{}
+			for _, cause := range resp.Result.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tt.wantFields))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks contains admission webhooks which need access to OpenStack, and which
+// therefore cannot be implemented by the API types.
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// ResourceValidatorPath is the path the ResourceValidator is served at.
+const ResourceValidatorPath = "/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackmachine-resources"
+
+// +kubebuilder:webhook:verbs=create,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackmachine-resources,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines;openstackmachinetemplates,versions=v1alpha6,name=resources.openstackmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// ResourceValidator rejects OpenStackMachines and OpenStackMachineTemplates on creation if the flavor,
+// image, networks, subnets or keypair they reference do not exist in OpenStack. The credentials are
+// taken from the identity of the machine, or from the OpenStackCluster of the cluster the object is
+// labelled with.
+//
+// Only references which are definitely not found are rejected. If the credentials cannot be found or
+// OpenStack cannot be reached the object is admitted with a warning, so that the webhook never blocks
+// the creation of objects which the controller would be able to reconcile later.
+type ResourceValidator struct {
+	Client client.Client
+
+	// Enabled enables the validation, which is opt-in as it requires the webhook to call OpenStack.
+	// If it is disabled all objects are admitted.
+	Enabled bool
+
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ResourceValidator{}
+
+// InjectDecoder injects the decoder of admission requests.
+func (v *ResourceValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the OpenStack resources of an OpenStackMachine or OpenStackMachineTemplate.
+func (v *ResourceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !v.Enabled || req.Operation != admissionv1.Create {
+		return admission.Allowed("")
+	}
+
+	var openStackMachine *infrav1.OpenStackMachine
+	var specPath *field.Path
+	switch req.Kind.Kind {
+	case "OpenStackMachine":
+		openStackMachine = &infrav1.OpenStackMachine{}
+		if err := v.decoder.Decode(req, openStackMachine); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		specPath = field.NewPath("spec")
+	case "OpenStackMachineTemplate":
+		template := &infrav1.OpenStackMachineTemplate{}
+		if err := v.decoder.Decode(req, template); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// The machines of the template are created in its namespace and for the cluster it is labelled with
+		openStackMachine = &infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      template.Name,
+				Namespace: template.Namespace,
+				Labels:    template.Labels,
+			},
+			Spec: template.Spec.Template.Spec,
+		}
+		specPath = field.NewPath("spec", "template", "spec")
+	default:
+		return admission.Allowed("")
+	}
+
+	allErrs, err := v.validate(ctx, openStackMachine, specPath)
+	if err != nil {
+		return admission.Allowed("").WithWarnings(
+			fmt.Sprintf("the OpenStack resources of %s %s could not be validated: %v", req.Kind.Kind, openStackMachine.Name, err))
+	}
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+
+	status := apierrors.NewInvalid(infrav1.GroupVersion.WithKind(req.Kind.Kind).GroupKind(), openStackMachine.Name, allErrs).Status()
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		},
+	}
+}
+
+func (v *ResourceValidator) validate(ctx context.Context, openStackMachine *infrav1.OpenStackMachine, specPath *field.Path) (field.ErrorList, error) {
+	// The cluster is only needed for its identity
+	openStackCluster := &infrav1.OpenStackCluster{}
+	if openStackMachine.Spec.IdentityRef == nil {
+		cluster, err := util.GetClusterFromMetadata(ctx, v.Client, openStackMachine.ObjectMeta)
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Spec.InfrastructureRef == nil {
+			return nil, fmt.Errorf("cluster %s has no infrastructureRef", cluster.Name)
+		}
+		openStackClusterName := client.ObjectKey{
+			Namespace: openStackMachine.Namespace,
+			Name:      cluster.Spec.InfrastructureRef.Name,
+		}
+		if err := v.Client.Get(ctx, openStackClusterName, openStackCluster); err != nil {
+			return nil, err
+		}
+	}
+
+	providerClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, v.Client, openStackCluster, openStackMachine)
+	if err != nil {
+		return nil, err
+	}
+	computeService, err := compute.NewService(&scope.Scope{
+		ProviderClient:     providerClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             ctrl.LoggerFrom(ctx),
+		ReconcileContext:   ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return computeService.ValidateMachineResources(&openStackMachine.Spec, specPath)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+)
+
+func TestResourceValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	openStackMachine := &infrav1.OpenStackMachine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "OpenStackMachine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster"},
+		},
+		Spec: infrav1.OpenStackMachineSpec{
+			Flavor: "m1.small",
+			Image:  "ubuntu",
+		},
+	}
+	openStackMachineTemplate := &infrav1.OpenStackMachineTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "OpenStackMachineTemplate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "template",
+			Namespace: "default",
+		},
+		Spec: infrav1.OpenStackMachineTemplateSpec{
+			Template: infrav1.OpenStackMachineTemplateResource{
+				Spec: openStackMachine.Spec,
+			},
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		operation    admissionv1.Operation
+		obj          client.Object
+		objects      []client.Object
+		wantWarnings bool
+	}{
+		{
+			name:      "Disabled validation admits the machine",
+			operation: admissionv1.Create,
+			obj:       openStackMachine,
+		},
+		{
+			name:      "Updates are admitted",
+			enabled:   true,
+			operation: admissionv1.Update,
+			obj:       openStackMachine,
+		},
+		{
+			name:         "Machine of a missing cluster is admitted with a warning",
+			enabled:      true,
+			operation:    admissionv1.Create,
+			obj:          openStackMachine,
+			wantWarnings: true,
+		},
+		{
+			name:         "Machine of a cluster without infrastructure is admitted with a warning",
+			enabled:      true,
+			operation:    admissionv1.Create,
+			obj:          openStackMachine,
+			objects:      []client.Object{cluster},
+			wantWarnings: true,
+		},
+		{
+			name:         "Template without cluster is admitted with a warning",
+			enabled:      true,
+			operation:    admissionv1.Create,
+			obj:          openStackMachineTemplate,
+			wantWarnings: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			decoder, err := admission.NewDecoder(scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+			v := &ResourceValidator{
+				Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				Enabled: tt.enabled,
+			}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			raw, err := json.Marshal(tt.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			gvk := tt.obj.GetObjectKind().GroupVersionKind()
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: tt.operation,
+					Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+					Name:      tt.obj.GetName(),
+					Namespace: tt.obj.GetNamespace(),
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+
+			resp := v.Handle(context.TODO(), req)
+			g.Expect(resp.Allowed).To(BeTrue())
+			if tt.wantWarnings {
+				g.Expect(resp.Warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(resp.Warnings).To(BeEmpty())
+			}
+		})
+	}
+}